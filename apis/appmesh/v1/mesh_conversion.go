@@ -0,0 +1,218 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &Mesh{}
+
+// ConvertTo converts this Mesh to the hub version(v1beta2).
+func (src *Mesh) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta2.Mesh)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = v1beta2.MeshSpec{
+		AWSName:           spec.AWSName,
+		AWSNameSuffix:     spec.AWSNameSuffix,
+		NamespaceSelector: spec.NamespaceSelector,
+		MeshOwner:         spec.MeshOwner,
+	}
+	if spec.EgressFilter != nil {
+		dst.Spec.EgressFilter = &v1beta2.EgressFilter{
+			Type: v1beta2.EgressFilterType(spec.EgressFilter.Type),
+		}
+	}
+	if spec.ServiceDiscovery != nil {
+		dst.Spec.ServiceDiscovery = &v1beta2.MeshServiceDiscovery{
+			IpPreference: spec.ServiceDiscovery.IPPreference,
+		}
+	}
+	if spec.TLSEnforcementMode != nil {
+		tlsEnforcementMode := v1beta2.TLSEnforcementMode(*spec.TLSEnforcementMode)
+		dst.Spec.TLSEnforcementMode = &tlsEnforcementMode
+	}
+	if spec.Replication != nil {
+		dst.Spec.Replication = &v1beta2.MeshReplication{}
+		for _, region := range spec.Replication.Regions {
+			dst.Spec.Replication.Regions = append(dst.Spec.Replication.Regions, v1beta2.MeshReplicationRegion(region))
+		}
+	}
+	if spec.MaintenanceWindow != nil {
+		dst.Spec.MaintenanceWindow = &v1beta2.MaintenanceWindow{
+			Start:    spec.MaintenanceWindow.Start,
+			Duration: spec.MaintenanceWindow.Duration,
+			TimeZone: spec.MaintenanceWindow.TimeZone,
+		}
+		for _, day := range spec.MaintenanceWindow.Days {
+			dst.Spec.MaintenanceWindow.Days = append(dst.Spec.MaintenanceWindow.Days, v1beta2.MaintenanceWindowDay(day))
+		}
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = v1beta2.MeshStatus{
+		MeshARN:            status.MeshARN,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	if status.ResourceMetadata != nil {
+		resourceMetadata := v1beta2.AWSResourceMetadata(*status.ResourceMetadata)
+		dst.Status.ResourceMetadata = &resourceMetadata
+	}
+	if status.TLSAudit != nil {
+		dst.Status.TLSAudit = &v1beta2.MeshTLSAudit{
+			LastAuditTime:  status.TLSAudit.LastAuditTime,
+			ViolationCount: status.TLSAudit.ViolationCount,
+		}
+		for _, violation := range status.TLSAudit.Violations {
+			dst.Status.TLSAudit.Violations = append(dst.Status.TLSAudit.Violations, v1beta2.MeshTLSAuditViolation{
+				Client:  violation.Client,
+				Backend: violation.Backend,
+				Port:    v1beta2.PortNumber(violation.Port),
+				Reason:  violation.Reason,
+			})
+		}
+	}
+	for _, replica := range status.Replicas {
+		dst.Status.Replicas = append(dst.Status.Replicas, v1beta2.MeshReplicaStatus(replica))
+	}
+	if status.Members != nil {
+		dst.Status.Members = &v1beta2.MeshMembersStatus{
+			VirtualNodes:          v1beta2.MeshMemberCounts(status.Members.VirtualNodes),
+			VirtualRouters:        v1beta2.MeshMemberCounts(status.Members.VirtualRouters),
+			VirtualServices:       v1beta2.MeshMemberCounts(status.Members.VirtualServices),
+			VirtualGateways:       v1beta2.MeshMemberCounts(status.Members.VirtualGateways),
+			Ready:                 status.Members.Ready,
+			Errored:               status.Members.Errored,
+			LastFullReconcileTime: status.Members.LastFullReconcileTime,
+		}
+	}
+	if status.Coverage != nil {
+		dst.Status.Coverage = &v1beta2.MeshCoverage{
+			LastReportTime:    status.Coverage.LastReportTime,
+			Namespaces:        status.Coverage.Namespaces,
+			Deployments:       status.Coverage.Deployments,
+			MeshedDeployments: status.Coverage.MeshedDeployments,
+			Meshed:            status.Coverage.Meshed,
+			GapCount:          status.Coverage.GapCount,
+		}
+		for _, gap := range status.Coverage.Gaps {
+			dst.Status.Coverage.Gaps = append(dst.Status.Coverage.Gaps, v1beta2.MeshCoverageGap(gap))
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version(v1beta2) to this Mesh.
+func (dst *Mesh) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta2.Mesh)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = MeshSpec{
+		AWSName:           spec.AWSName,
+		AWSNameSuffix:     spec.AWSNameSuffix,
+		NamespaceSelector: spec.NamespaceSelector,
+		MeshOwner:         spec.MeshOwner,
+	}
+	if spec.EgressFilter != nil {
+		dst.Spec.EgressFilter = &EgressFilter{
+			Type: EgressFilterType(spec.EgressFilter.Type),
+		}
+	}
+	if spec.ServiceDiscovery != nil {
+		dst.Spec.ServiceDiscovery = &MeshServiceDiscovery{
+			IPPreference: spec.ServiceDiscovery.IpPreference,
+		}
+	}
+	if spec.TLSEnforcementMode != nil {
+		tlsEnforcementMode := TLSEnforcementMode(*spec.TLSEnforcementMode)
+		dst.Spec.TLSEnforcementMode = &tlsEnforcementMode
+	}
+	if spec.Replication != nil {
+		dst.Spec.Replication = &MeshReplication{}
+		for _, region := range spec.Replication.Regions {
+			dst.Spec.Replication.Regions = append(dst.Spec.Replication.Regions, MeshReplicationRegion(region))
+		}
+	}
+	if spec.MaintenanceWindow != nil {
+		dst.Spec.MaintenanceWindow = &MaintenanceWindow{
+			Start:    spec.MaintenanceWindow.Start,
+			Duration: spec.MaintenanceWindow.Duration,
+			TimeZone: spec.MaintenanceWindow.TimeZone,
+		}
+		for _, day := range spec.MaintenanceWindow.Days {
+			dst.Spec.MaintenanceWindow.Days = append(dst.Spec.MaintenanceWindow.Days, MaintenanceWindowDay(day))
+		}
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = MeshStatus{
+		MeshARN:            status.MeshARN,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	if status.ResourceMetadata != nil {
+		resourceMetadata := AWSResourceMetadata(*status.ResourceMetadata)
+		dst.Status.ResourceMetadata = &resourceMetadata
+	}
+	if status.TLSAudit != nil {
+		dst.Status.TLSAudit = &MeshTLSAudit{
+			LastAuditTime:  status.TLSAudit.LastAuditTime,
+			ViolationCount: status.TLSAudit.ViolationCount,
+		}
+		for _, violation := range status.TLSAudit.Violations {
+			dst.Status.TLSAudit.Violations = append(dst.Status.TLSAudit.Violations, MeshTLSAuditViolation{
+				Client:  violation.Client,
+				Backend: violation.Backend,
+				Port:    PortNumber(violation.Port),
+				Reason:  violation.Reason,
+			})
+		}
+	}
+	for _, replica := range status.Replicas {
+		dst.Status.Replicas = append(dst.Status.Replicas, MeshReplicaStatus(replica))
+	}
+	if status.Members != nil {
+		dst.Status.Members = &MeshMembersStatus{
+			VirtualNodes:          MeshMemberCounts(status.Members.VirtualNodes),
+			VirtualRouters:        MeshMemberCounts(status.Members.VirtualRouters),
+			VirtualServices:       MeshMemberCounts(status.Members.VirtualServices),
+			VirtualGateways:       MeshMemberCounts(status.Members.VirtualGateways),
+			Ready:                 status.Members.Ready,
+			Errored:               status.Members.Errored,
+			LastFullReconcileTime: status.Members.LastFullReconcileTime,
+		}
+	}
+	if status.Coverage != nil {
+		dst.Status.Coverage = &MeshCoverage{
+			LastReportTime:    status.Coverage.LastReportTime,
+			Namespaces:        status.Coverage.Namespaces,
+			Deployments:       status.Coverage.Deployments,
+			MeshedDeployments: status.Coverage.MeshedDeployments,
+			Meshed:            status.Coverage.Meshed,
+			GapCount:          status.Coverage.GapCount,
+		}
+		for _, gap := range status.Coverage.Gaps {
+			dst.Status.Coverage.Gaps = append(dst.Status.Coverage.Gaps, MeshCoverageGap(gap))
+		}
+	}
+	return nil
+}
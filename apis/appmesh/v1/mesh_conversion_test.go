@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMesh_ConvertTo_ConvertFrom(t *testing.T) {
+	lastAuditTime := metav1.Unix(1600000000, 0)
+	lastFullReconcileTime := metav1.Unix(1600000300, 0)
+	lastReportTime := metav1.Unix(1600000600, 0)
+	lastSyncTime := metav1.Unix(1600000900, 0)
+	tlsEnforcementModeAudit := TLSEnforcementModeAudit
+	hubTLSEnforcementModeAudit := v1beta2.TLSEnforcementModeAudit
+	tests := []struct {
+		name string
+		mesh *Mesh
+		hub  *v1beta2.Mesh
+	}{
+		{
+			name: "mesh with all fields",
+			mesh: &Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-mesh",
+					Annotations: map[string]string{"k": "v"},
+				},
+				Spec: MeshSpec{
+					AWSName:       aws.String("my-mesh"),
+					AWSNameSuffix: aws.String("-dev"),
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"mesh": "my-mesh"},
+					},
+					EgressFilter: &EgressFilter{
+						Type: EgressFilterTypeAllowAll,
+					},
+					MeshOwner: aws.String("222222222"),
+					ServiceDiscovery: &MeshServiceDiscovery{
+						IPPreference: aws.String("IPv6_ONLY"),
+					},
+					TLSEnforcementMode: &tlsEnforcementModeAudit,
+					Replication: &MeshReplication{
+						Regions: []MeshReplicationRegion{
+							{Region: "us-east-1"},
+							{Region: "eu-west-1", Endpoint: aws.String("https://appmesh.eu-west-1.amazonaws.com")},
+						},
+					},
+					MaintenanceWindow: &MaintenanceWindow{
+						Days:     []MaintenanceWindowDay{"Sat", "Sun"},
+						Start:    "02:00",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+						TimeZone: aws.String("America/Los_Angeles"),
+					},
+				},
+				Status: MeshStatus{
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222:mesh/my-mesh"),
+					ResourceMetadata: &AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222"),
+						ResourceOwner: aws.String("222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: "Reconciled"},
+					},
+					ObservedGeneration: aws.Int64(1),
+					TLSAudit: &MeshTLSAudit{
+						LastAuditTime:  &lastAuditTime,
+						ViolationCount: 1,
+						Violations: []MeshTLSAuditViolation{
+							{Client: "ns/client", Backend: "ns/backend", Port: 8080, Reason: "backend listener doesn't configure TLS"},
+						},
+					},
+					Replicas: []MeshReplicaStatus{
+						{
+							Region:       "us-east-1",
+							MeshARN:      aws.String("arn:aws:appmesh:us-east-1:222222222:mesh/my-mesh"),
+							Synced:       metav1.ConditionTrue,
+							LastSyncTime: &lastSyncTime,
+						},
+						{
+							Region:  "eu-west-1",
+							Synced:  metav1.ConditionFalse,
+							Message: aws.String("unable to reach AppMesh endpoint"),
+						},
+					},
+					Members: &MeshMembersStatus{
+						VirtualNodes:          MeshMemberCounts{Total: 3, Ready: 2, Errored: 1},
+						VirtualRouters:        MeshMemberCounts{Total: 1, Ready: 1},
+						VirtualServices:       MeshMemberCounts{Total: 2, Ready: 2},
+						VirtualGateways:       MeshMemberCounts{Total: 1},
+						Ready:                 "5/7",
+						Errored:               1,
+						LastFullReconcileTime: &lastFullReconcileTime,
+					},
+					Coverage: &MeshCoverage{
+						LastReportTime:    &lastReportTime,
+						Namespaces:        2,
+						Deployments:       3,
+						MeshedDeployments: 2,
+						Meshed:            "2/3",
+						GapCount:          1,
+						Gaps: []MeshCoverageGap{
+							{Kind: "Deployment", Name: "ns/legacy", Reason: "NoVirtualNode"},
+						},
+					},
+				},
+			},
+			hub: &v1beta2.Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-mesh",
+					Annotations: map[string]string{"k": "v"},
+				},
+				Spec: v1beta2.MeshSpec{
+					AWSName:       aws.String("my-mesh"),
+					AWSNameSuffix: aws.String("-dev"),
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"mesh": "my-mesh"},
+					},
+					EgressFilter: &v1beta2.EgressFilter{
+						Type: v1beta2.EgressFilterTypeAllowAll,
+					},
+					MeshOwner: aws.String("222222222"),
+					ServiceDiscovery: &v1beta2.MeshServiceDiscovery{
+						IpPreference: aws.String("IPv6_ONLY"),
+					},
+					TLSEnforcementMode: &hubTLSEnforcementModeAudit,
+					Replication: &v1beta2.MeshReplication{
+						Regions: []v1beta2.MeshReplicationRegion{
+							{Region: "us-east-1"},
+							{Region: "eu-west-1", Endpoint: aws.String("https://appmesh.eu-west-1.amazonaws.com")},
+						},
+					},
+					MaintenanceWindow: &v1beta2.MaintenanceWindow{
+						Days:     []v1beta2.MaintenanceWindowDay{"Sat", "Sun"},
+						Start:    "02:00",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+						TimeZone: aws.String("America/Los_Angeles"),
+					},
+				},
+				Status: v1beta2.MeshStatus{
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222:mesh/my-mesh"),
+					ResourceMetadata: &v1beta2.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222"),
+						ResourceOwner: aws.String("222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: "Reconciled"},
+					},
+					ObservedGeneration: aws.Int64(1),
+					TLSAudit: &v1beta2.MeshTLSAudit{
+						LastAuditTime:  &lastAuditTime,
+						ViolationCount: 1,
+						Violations: []v1beta2.MeshTLSAuditViolation{
+							{Client: "ns/client", Backend: "ns/backend", Port: 8080, Reason: "backend listener doesn't configure TLS"},
+						},
+					},
+					Replicas: []v1beta2.MeshReplicaStatus{
+						{
+							Region:       "us-east-1",
+							MeshARN:      aws.String("arn:aws:appmesh:us-east-1:222222222:mesh/my-mesh"),
+							Synced:       metav1.ConditionTrue,
+							LastSyncTime: &lastSyncTime,
+						},
+						{
+							Region:  "eu-west-1",
+							Synced:  metav1.ConditionFalse,
+							Message: aws.String("unable to reach AppMesh endpoint"),
+						},
+					},
+					Members: &v1beta2.MeshMembersStatus{
+						VirtualNodes:          v1beta2.MeshMemberCounts{Total: 3, Ready: 2, Errored: 1},
+						VirtualRouters:        v1beta2.MeshMemberCounts{Total: 1, Ready: 1},
+						VirtualServices:       v1beta2.MeshMemberCounts{Total: 2, Ready: 2},
+						VirtualGateways:       v1beta2.MeshMemberCounts{Total: 1},
+						Ready:                 "5/7",
+						Errored:               1,
+						LastFullReconcileTime: &lastFullReconcileTime,
+					},
+					Coverage: &v1beta2.MeshCoverage{
+						LastReportTime:    &lastReportTime,
+						Namespaces:        2,
+						Deployments:       3,
+						MeshedDeployments: 2,
+						Meshed:            "2/3",
+						GapCount:          1,
+						Gaps: []v1beta2.MeshCoverageGap{
+							{Kind: "Deployment", Name: "ns/legacy", Reason: "NoVirtualNode"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "mesh with no optional fields",
+			mesh: &Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-mesh",
+				},
+			},
+			hub: &v1beta2.Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-mesh",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHub := &v1beta2.Mesh{}
+			err := tt.mesh.DeepCopy().ConvertTo(gotHub)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.hub, gotHub)
+
+			gotMesh := &Mesh{}
+			err = gotMesh.ConvertFrom(gotHub)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.mesh, gotMesh)
+		})
+	}
+}
+
+func TestMeshSpec_serviceDiscoveryFieldName(t *testing.T) {
+	spec := MeshSpec{
+		ServiceDiscovery: &MeshServiceDiscovery{
+			IPPreference: aws.String("IPv4_ONLY"),
+		},
+	}
+	got, err := json.Marshal(spec)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"serviceDiscovery":{"ipPreference":"IPv4_ONLY"}}`, string(got))
+}
@@ -0,0 +1,320 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=ALLOW_ALL;DROP_ALL
+type EgressFilterType string
+
+const (
+	// EgressFilterTypeAllowAll allows egress to any endpoint inside or outside of the service mesh
+	EgressFilterTypeAllowAll EgressFilterType = "ALLOW_ALL"
+	// EgressFilterTypeDropAll allows egress only from virtual nodes to other defined resources in the service mesh (and any traffic to *.amazonaws.com for AWS API calls)
+	EgressFilterTypeDropAll EgressFilterType = "DROP_ALL"
+)
+
+// EgressFilter refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_EgressFilter.html
+type EgressFilter struct {
+	// The egress filter type.
+	Type EgressFilterType `json:"type"`
+}
+
+// +kubebuilder:validation:Enum=ENFORCE;AUDIT
+type TLSEnforcementMode string
+
+const (
+	// TLSEnforcementModeEnforce applies the enforce setting of client policy TLS as specified
+	TLSEnforcementModeEnforce TLSEnforcementMode = "ENFORCE"
+	// TLSEnforcementModeAudit configures all client policy TLS with enforce disabled,
+	// and reports client connections that would fail once enforced
+	TLSEnforcementModeAudit TLSEnforcementMode = "AUDIT"
+)
+
+// +kubebuilder:validation:Minimum=1
+// +kubebuilder:validation:Maximum=65535
+type PortNumber int64
+
+// MeshSpec defines the desired state of Mesh
+// refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_MeshSpec.html
+type MeshSpec struct {
+	// AWSName is the AppMesh Mesh object's name.
+	// If unspecified or empty, it defaults to be "${name}" of k8s Mesh
+	// +optional
+	AWSName *string `json:"awsName,omitempty"`
+	// AWSNameSuffix is appended to the AWSName generated for this mesh and its members except VirtualServices,
+	// whose AWSName is the hostname clients call.
+	// If unspecified, it defaults to the controller's --resource-name-suffix. This field is immutable.
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]*$`
+	// +optional
+	AWSNameSuffix *string `json:"awsNameSuffix,omitempty"`
+	// NamespaceSelector selects Namespaces using labels to designate mesh membership.
+	// This field follows standard label selector semantics:
+	//	if present but empty, it selects all namespaces.
+	// 	if absent, it selects no namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// The egress filter rules for the service mesh.
+	// If unspecified, default settings from AWS API will be applied. Refer to AWS Docs for default settings.
+	// +optional
+	EgressFilter *EgressFilter `json:"egressFilter,omitempty"`
+	// The AWS IAM account ID of the service mesh owner.
+	// Required if the account ID is not your own.
+	// +optional
+	MeshOwner *string `json:"meshOwner,omitempty"`
+	// The service discovery settings for the service mesh.
+	// +optional
+	ServiceDiscovery *MeshServiceDiscovery `json:"serviceDiscovery,omitempty"`
+	// TLSEnforcementMode controls how client policy TLS of mesh members is enforced.
+	// AUDIT allows staged mTLS rollout by disabling enforcement while reporting would-be failures in status.
+	// +kubebuilder:default=ENFORCE
+	// +optional
+	TLSEnforcementMode *TLSEnforcementMode `json:"tlsEnforcementMode,omitempty"`
+	// Replication mirrors the AppMesh resources of the mesh into secondary regions, e.g. for disaster recovery.
+	// +optional
+	Replication *MeshReplication `json:"replication,omitempty"`
+	// MaintenanceWindow defers disruptive changes to mesh members, such as route deletions and TLS enforcement flips, until the window.
+	// Namespaces override it with annotation appmesh.k8s.aws/maintenanceWindow.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type MaintenanceWindowDay string
+
+// MaintenanceWindow is a recurring window during which disruptive changes are applied.
+type MaintenanceWindow struct {
+	// The days of the week the window starts on.
+	// If unspecified, the window starts every day.
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+	// The time of day the window starts at, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// How long the window lasts, e.g. 2h.
+	Duration metav1.Duration `json:"duration"`
+	// The IANA time zone of start, e.g. America/Los_Angeles.
+	// If unspecified, defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// MeshReplication configures the secondary regions the mesh is mirrored into.
+type MeshReplication struct {
+	// The secondary regions to mirror the mesh into.
+	// +kubebuilder:validation:MinItems=1
+	Regions []MeshReplicationRegion `json:"regions"`
+}
+
+// MeshReplicationRegion is a secondary region the mesh is mirrored into.
+type MeshReplicationRegion struct {
+	// The AWS region, e.g. us-west-2.
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+	// Endpoint overrides the AppMesh API endpoint URL of the region.
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+}
+
+type MeshServiceDiscovery struct {
+	// The IP version preferred by mesh members.
+	// +kubebuilder:validation:Enum=IPv6_ONLY;IPv4_ONLY
+	// +optional
+	IPPreference *string `json:"ipPreference,omitempty"`
+}
+
+// MeshStatus defines the observed state of Mesh
+type MeshStatus struct {
+	// MeshARN is the AppMesh Mesh object's Amazon Resource Name
+	// +optional
+	MeshARN *string `json:"meshARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh Mesh object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
+	// The current Mesh status.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation observed by the Mesh controller.
+	// +optional
+	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+	// TLSAudit reports client connections that would fail once client policy TLS is enforced.
+	// Only populated when tlsEnforcementMode is AUDIT.
+	// +optional
+	TLSAudit *MeshTLSAudit `json:"tlsAudit,omitempty"`
+	// Replicas reports the replication status of the mesh in each secondary region.
+	// Only populated when replication is configured.
+	// +optional
+	Replicas []MeshReplicaStatus `json:"replicas,omitempty"`
+	// Members aggregates the status of VirtualNodes, VirtualRouters, VirtualServices and VirtualGateways in the mesh.
+	// +optional
+	Members *MeshMembersStatus `json:"members,omitempty"`
+	// Coverage reports namespaces and Deployments selected by the mesh that aren't meshed, and VirtualNodes without pods.
+	// Only populated when coverage reporting is enabled.
+	// +optional
+	Coverage *MeshCoverage `json:"coverage,omitempty"`
+}
+
+// MeshMembersStatus aggregates the status of mesh members.
+type MeshMembersStatus struct {
+	// The VirtualNodes in the mesh.
+	VirtualNodes MeshMemberCounts `json:"virtualNodes"`
+	// The VirtualRouters in the mesh.
+	VirtualRouters MeshMemberCounts `json:"virtualRouters"`
+	// The VirtualServices in the mesh.
+	VirtualServices MeshMemberCounts `json:"virtualServices"`
+	// The VirtualGateways in the mesh.
+	VirtualGateways MeshMemberCounts `json:"virtualGateways"`
+	// Ready is the number of ready members out of all members, formatted as ready/total.
+	Ready string `json:"ready"`
+	// Errored is the number of errored members.
+	Errored int32 `json:"errored"`
+	// Last time all members of the mesh were reconciled successfully at their latest generation.
+	// +optional
+	LastFullReconcileTime *metav1.Time `json:"lastFullReconcileTime,omitempty"`
+}
+
+// MeshMemberCounts counts mesh members of a kind by their Ready condition.
+type MeshMemberCounts struct {
+	// The number of members.
+	Total int32 `json:"total"`
+	// The number of members whose Ready condition is True for their latest generation.
+	Ready int32 `json:"ready"`
+	// The number of members whose Ready condition is False for their latest generation.
+	Errored int32 `json:"errored"`
+}
+
+// AWSResourceMetadata refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_ResourceMetadata.html
+type AWSResourceMetadata struct {
+	// UID is the unique identifier of the AppMesh object.
+	// +optional
+	UID *string `json:"uid,omitempty"`
+	// MeshOwner is the AWS IAM account ID of the mesh owner.
+	// +optional
+	MeshOwner *string `json:"meshOwner,omitempty"`
+	// ResourceOwner is the AWS IAM account ID of the resource owner.
+	// +optional
+	ResourceOwner *string `json:"resourceOwner,omitempty"`
+}
+
+// MeshReplicaStatus is the replication status of the mesh in a secondary region.
+type MeshReplicaStatus struct {
+	// The AWS region.
+	Region string `json:"region"`
+	// MeshARN is the mirrored AppMesh Mesh object's Amazon Resource Name in the region.
+	// +optional
+	MeshARN *string `json:"meshARN,omitempty"`
+	// Synced is True when the last replication mirrored all AppMesh resources of the mesh into the region.
+	Synced metav1.ConditionStatus `json:"synced"`
+	// A human readable message indicating why the last replication failed.
+	// +optional
+	Message *string `json:"message,omitempty"`
+	// Last time the region became synced, i.e. all AppMesh resources of the mesh were mirrored into the region after it wasn't synced.
+	// It isn't refreshed by resyncs while the region stays synced.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// MeshTLSAudit is the result of auditing client policy TLS of mesh members.
+type MeshTLSAudit struct {
+	// Last time the audit found different violations, audits finding the same violations don't update the status.
+	// +optional
+	LastAuditTime *metav1.Time `json:"lastAuditTime,omitempty"`
+	// The number of client to backend connections that would fail once TLS is enforced.
+	ViolationCount int32 `json:"violationCount"`
+	// The connections that would fail once TLS is enforced, truncated to the first 50.
+	// +optional
+	Violations []MeshTLSAuditViolation `json:"violations,omitempty"`
+}
+
+// MeshTLSAuditViolation is a client to backend connection that would fail once TLS is enforced.
+type MeshTLSAuditViolation struct {
+	// Client is the namespace/name of VirtualNode initiating connections.
+	Client string `json:"client"`
+	// Backend is the namespace/name of VirtualNode accepting connections.
+	Backend string `json:"backend"`
+	// The backend listener port.
+	Port PortNumber `json:"port"`
+	// A human readable message indicating why the connection would fail.
+	Reason string `json:"reason"`
+}
+
+// MeshCoverage is the result of inventorying the onboarding of workloads selected by the mesh.
+type MeshCoverage struct {
+	// Last time the coverage was reported.
+	// +optional
+	LastReportTime *metav1.Time `json:"lastReportTime,omitempty"`
+	// The number of namespaces selected by namespaceSelector of the mesh.
+	Namespaces int32 `json:"namespaces"`
+	// The number of Deployments in namespaces selected by the mesh.
+	Deployments int32 `json:"deployments"`
+	// The number of Deployments with injection enabled, that are members of a VirtualNode and whose pods are injected.
+	MeshedDeployments int32 `json:"meshedDeployments"`
+	// Meshed is the number of meshed Deployments out of all Deployments, formatted as meshed/total.
+	Meshed string `json:"meshed"`
+	// The number of gaps found.
+	GapCount int32 `json:"gapCount"`
+	// The gaps found, truncated to the first 50.
+	// +optional
+	Gaps []MeshCoverageGap `json:"gaps,omitempty"`
+}
+
+// MeshCoverageGap is a namespace, Deployment or VirtualNode that isn't fully onboarded to the mesh.
+type MeshCoverageGap struct {
+	// Kind of the object, one of Namespace, Deployment or VirtualNode.
+	Kind string `json:"kind"`
+	// Name is the name of the Namespace, or the namespace/name of the Deployment or VirtualNode.
+	Name string `json:"name"`
+	// Reason is a programmatic identifier of the gap, one of InjectionDisabled, NoVirtualNode, NotInjected or NoPods.
+	Reason string `json:"reason"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.meshARN",description="The AppMesh Mesh object's Amazon Resource Name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the AppMesh Mesh object is ready"
+// +kubebuilder:printcolumn:name="MEMBERS READY",type="string",JSONPath=".status.members.ready",description="The number of ready members out of all members"
+// +kubebuilder:printcolumn:name="ERRORED",type="integer",JSONPath=".status.members.errored",description="The number of errored members"
+// +kubebuilder:printcolumn:name="LAST FULL RECONCILE",type="date",JSONPath=".status.members.lastFullReconcileTime",description="Last time all members were reconciled successfully"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// Mesh is the Schema for the meshes API
+type Mesh struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeshSpec   `json:"spec,omitempty"`
+	Status MeshStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MeshList contains a list of Mesh
+type MeshList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Mesh `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Mesh{}, &MeshList{})
+}
@@ -25,6 +25,17 @@ type BackendGroupSpec struct {
 	// VirtualServices defines the set of virtual services in this BackendGroup.
 	VirtualServices []VirtualServiceReference `json:"virtualservices,omitempty"`
 
+	// VirtualServiceSelector selects VirtualServices by labels to be included in this BackendGroup, in addition to VirtualServices.
+	// Selected VirtualServices are kept up to date as they're created, relabeled or deleted.
+	// Only VirtualServices within the same mesh as this BackendGroup are selected.
+	// +optional
+	VirtualServiceSelector *metav1.LabelSelector `json:"virtualServiceSelector,omitempty"`
+
+	// NamespaceSelector selects Namespaces by labels to select VirtualServices from using VirtualServiceSelector.
+	// If present but empty, it selects all namespaces. If absent, VirtualServices are selected from this BackendGroup's namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// A reference to k8s Mesh CR that this BackendGroup belongs to.
 	// The admission controller populates it using Meshes's selector, and prevents users from setting this field.
 	//
@@ -17,7 +17,6 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -249,37 +248,25 @@ type GatewayRouteSpec struct {
 	MeshRef *MeshReference `json:"meshRef,omitempty"`
 }
 
-type GatewayRouteConditionType string
-
 const (
 	// GatewayRouteActive is True when the AppMesh GatewayRoute has been created or found via the API
-	GatewayRouteActive GatewayRouteConditionType = "GatewayRouteActive"
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	GatewayRouteActive = "GatewayRouteActive"
 )
 
-type GatewayRouteCondition struct {
-	// Type of GatewayRoute condition.
-	Type GatewayRouteConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
-
 // GatewayRouteStatus defines the observed state of GatewayRoute
 type GatewayRouteStatus struct {
 	// GatewayRouteARN is the AppMesh GatewayRoute object's Amazon Resource Name
 	// +optional
 	GatewayRouteARN *string `json:"gatewayRouteARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh GatewayRoute object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// The current GatewayRoute status.
 	// +optional
-	Conditions []GatewayRouteCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// The generation observed by the GatewayRoute controller.
 	// +optional
 	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
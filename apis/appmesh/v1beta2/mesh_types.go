@@ -17,7 +17,6 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -37,28 +36,22 @@ type EgressFilter struct {
 	Type EgressFilterType `json:"type"`
 }
 
-type MeshConditionType string
+// +kubebuilder:validation:Enum=ENFORCE;AUDIT
+type TLSEnforcementMode string
 
 const (
-	// MeshActive is True when the AppMesh Mesh has been created or found via the API
-	MeshActive MeshConditionType = "MeshActive"
+	// TLSEnforcementModeEnforce applies the enforce setting of client policy TLS as specified
+	TLSEnforcementModeEnforce TLSEnforcementMode = "ENFORCE"
+	// TLSEnforcementModeAudit configures all client policy TLS with enforce disabled,
+	// and reports client connections that would fail once enforced
+	TLSEnforcementModeAudit TLSEnforcementMode = "AUDIT"
 )
 
-type MeshCondition struct {
-	// Type of mesh condition.
-	Type MeshConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
+const (
+	// MeshActive is True when the AppMesh Mesh has been created or found via the API
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	MeshActive = "MeshActive"
+)
 
 // MeshSpec defines the desired state of Mesh
 // refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_MeshSpec.html
@@ -67,6 +60,14 @@ type MeshSpec struct {
 	// If unspecified or empty, it defaults to be "${name}" of k8s Mesh
 	// +optional
 	AWSName *string `json:"awsName,omitempty"`
+	// AWSNameSuffix is appended to the AWSName generated for this mesh and its members except VirtualServices,
+	// whose AWSName is the hostname clients call.
+	// If unspecified, it defaults to the controller's --resource-name-suffix. This field is immutable.
+	// It's only settable in appmesh.k8s.aws/v1 Mesh.
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]*$`
+	// +optional
+	AWSNameSuffix *string `json:"awsNameSuffix,omitempty"`
 	// NamespaceSelector selects Namespaces using labels to designate mesh membership.
 	// This field follows standard label selector semantics:
 	//	if present but empty, it selects all namespaces.
@@ -83,6 +84,57 @@ type MeshSpec struct {
 	MeshOwner *string `json:"meshOwner,omitempty"`
 	// +optional
 	ServiceDiscovery *MeshServiceDiscovery `json:"meshServiceDiscovery,omitempty"`
+	// TLSEnforcementMode controls how client policy TLS of mesh members is enforced.
+	// AUDIT allows staged mTLS rollout by disabling enforcement while reporting would-be failures in status.
+	// If unspecified, defaults to ENFORCE.
+	// +optional
+	TLSEnforcementMode *TLSEnforcementMode `json:"tlsEnforcementMode,omitempty"`
+	// Replication mirrors the AppMesh resources of the mesh into secondary regions, e.g. for disaster recovery.
+	// It's only settable in appmesh.k8s.aws/v1 Mesh.
+	// +optional
+	Replication *MeshReplication `json:"replication,omitempty"`
+	// MaintenanceWindow defers disruptive changes to mesh members, such as route deletions and TLS enforcement flips, until the window.
+	// Namespaces override it with annotation appmesh.k8s.aws/maintenanceWindow.
+	// It's only settable in appmesh.k8s.aws/v1 Mesh.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type MaintenanceWindowDay string
+
+// MaintenanceWindow is a recurring window during which disruptive changes are applied.
+type MaintenanceWindow struct {
+	// The days of the week the window starts on.
+	// If unspecified, the window starts every day.
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+	// The time of day the window starts at, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// How long the window lasts, e.g. 2h.
+	Duration metav1.Duration `json:"duration"`
+	// The IANA time zone of start, e.g. America/Los_Angeles.
+	// If unspecified, defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// MeshReplication configures the secondary regions the mesh is mirrored into.
+type MeshReplication struct {
+	// The secondary regions to mirror the mesh into.
+	// +kubebuilder:validation:MinItems=1
+	Regions []MeshReplicationRegion `json:"regions"`
+}
+
+// MeshReplicationRegion is a secondary region the mesh is mirrored into.
+type MeshReplicationRegion struct {
+	// The AWS region, e.g. us-west-2.
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+	// Endpoint overrides the AppMesh API endpoint URL of the region.
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
 }
 
 type MeshServiceDiscovery struct {
@@ -97,19 +149,144 @@ type MeshStatus struct {
 	// MeshARN is the AppMesh Mesh object's Amazon Resource Name
 	// +optional
 	MeshARN *string `json:"meshARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh Mesh object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// The current Mesh status.
 	// +optional
-	Conditions []MeshCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// The generation observed by the Mesh controller.
 	// +optional
 	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+	// TLSAudit reports client connections that would fail once client policy TLS is enforced.
+	// Only populated when tlsEnforcementMode is AUDIT.
+	// +optional
+	TLSAudit *MeshTLSAudit `json:"tlsAudit,omitempty"`
+	// Replicas reports the replication status of the mesh in each secondary region.
+	// Only populated when replication is configured.
+	// +optional
+	Replicas []MeshReplicaStatus `json:"replicas,omitempty"`
+	// Members aggregates the status of VirtualNodes, VirtualRouters, VirtualServices and VirtualGateways in the mesh.
+	// +optional
+	Members *MeshMembersStatus `json:"members,omitempty"`
+	// Coverage reports namespaces and Deployments selected by the mesh that aren't meshed, and VirtualNodes without pods.
+	// Only populated when coverage reporting is enabled.
+	// +optional
+	Coverage *MeshCoverage `json:"coverage,omitempty"`
+}
+
+// MeshMembersStatus aggregates the status of mesh members.
+type MeshMembersStatus struct {
+	// The VirtualNodes in the mesh.
+	VirtualNodes MeshMemberCounts `json:"virtualNodes"`
+	// The VirtualRouters in the mesh.
+	VirtualRouters MeshMemberCounts `json:"virtualRouters"`
+	// The VirtualServices in the mesh.
+	VirtualServices MeshMemberCounts `json:"virtualServices"`
+	// The VirtualGateways in the mesh.
+	VirtualGateways MeshMemberCounts `json:"virtualGateways"`
+	// Ready is the number of ready members out of all members, formatted as ready/total.
+	Ready string `json:"ready"`
+	// Errored is the number of errored members.
+	Errored int32 `json:"errored"`
+	// Last time all members of the mesh were reconciled successfully at their latest generation.
+	// +optional
+	LastFullReconcileTime *metav1.Time `json:"lastFullReconcileTime,omitempty"`
+}
+
+// MeshMemberCounts counts mesh members of a kind by their Ready condition.
+type MeshMemberCounts struct {
+	// The number of members.
+	Total int32 `json:"total"`
+	// The number of members whose Ready condition is True for their latest generation.
+	Ready int32 `json:"ready"`
+	// The number of members whose Ready condition is False for their latest generation.
+	Errored int32 `json:"errored"`
+}
+
+// MeshReplicaStatus is the replication status of the mesh in a secondary region.
+type MeshReplicaStatus struct {
+	// The AWS region.
+	Region string `json:"region"`
+	// MeshARN is the mirrored AppMesh Mesh object's Amazon Resource Name in the region.
+	// +optional
+	MeshARN *string `json:"meshARN,omitempty"`
+	// Synced is True when the last replication mirrored all AppMesh resources of the mesh into the region.
+	Synced metav1.ConditionStatus `json:"synced"`
+	// A human readable message indicating why the last replication failed.
+	// +optional
+	Message *string `json:"message,omitempty"`
+	// Last time the region became synced, i.e. all AppMesh resources of the mesh were mirrored into the region after it wasn't synced.
+	// It isn't refreshed by resyncs while the region stays synced.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// MeshTLSAudit is the result of auditing client policy TLS of mesh members.
+type MeshTLSAudit struct {
+	// Last time the audit found different violations, audits finding the same violations don't update the status.
+	// +optional
+	LastAuditTime *metav1.Time `json:"lastAuditTime,omitempty"`
+	// The number of client to backend connections that would fail once TLS is enforced.
+	ViolationCount int32 `json:"violationCount"`
+	// The connections that would fail once TLS is enforced, truncated to the first 50.
+	// +optional
+	Violations []MeshTLSAuditViolation `json:"violations,omitempty"`
+}
+
+// MeshTLSAuditViolation is a client to backend connection that would fail once TLS is enforced.
+type MeshTLSAuditViolation struct {
+	// Client is the namespace/name of VirtualNode initiating connections.
+	Client string `json:"client"`
+	// Backend is the namespace/name of VirtualNode accepting connections.
+	Backend string `json:"backend"`
+	// The backend listener port.
+	Port PortNumber `json:"port"`
+	// A human readable message indicating why the connection would fail.
+	Reason string `json:"reason"`
+}
+
+// MeshCoverage is the result of inventorying the onboarding of workloads selected by the mesh.
+type MeshCoverage struct {
+	// Last time the coverage was reported.
+	// +optional
+	LastReportTime *metav1.Time `json:"lastReportTime,omitempty"`
+	// The number of namespaces selected by namespaceSelector of the mesh.
+	Namespaces int32 `json:"namespaces"`
+	// The number of Deployments in namespaces selected by the mesh.
+	Deployments int32 `json:"deployments"`
+	// The number of Deployments with injection enabled, that are members of a VirtualNode and whose pods are injected.
+	MeshedDeployments int32 `json:"meshedDeployments"`
+	// Meshed is the number of meshed Deployments out of all Deployments, formatted as meshed/total.
+	Meshed string `json:"meshed"`
+	// The number of gaps found.
+	GapCount int32 `json:"gapCount"`
+	// The gaps found, truncated to the first 50.
+	// +optional
+	Gaps []MeshCoverageGap `json:"gaps,omitempty"`
+}
+
+// MeshCoverageGap is a namespace, Deployment or VirtualNode that isn't fully onboarded to the mesh.
+type MeshCoverageGap struct {
+	// Kind of the object, one of Namespace, Deployment or VirtualNode.
+	Kind string `json:"kind"`
+	// Name is the name of the Namespace, or the namespace/name of the Deployment or VirtualNode.
+	Name string `json:"name"`
+	// Reason is a programmatic identifier of the gap, one of InjectionDisabled, NoVirtualNode, NotInjected or NoPods.
+	Reason string `json:"reason"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.meshARN",description="The AppMesh Mesh object's Amazon Resource Name"
+// +kubebuilder:printcolumn:name="MEMBERS READY",type="string",JSONPath=".status.members.ready",description="The number of ready members out of all members"
+// +kubebuilder:printcolumn:name="ERRORED",type="integer",JSONPath=".status.members.errored",description="The number of errored members"
+// +kubebuilder:printcolumn:name="LAST FULL RECONCILE",type="date",JSONPath=".status.members.lastFullReconcileTime",description="Last time all members were reconciled successfully"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:pruning:PreserveUnknownFields
 // Mesh is the Schema for the meshes API
@@ -0,0 +1,95 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MeshPolicyRouteDefaults are defaults applied to routes of VirtualRouters in the mesh.
+type MeshPolicyRouteDefaults struct {
+	// The retry policy of http and http2 routes without one.
+	// +optional
+	HTTPRetryPolicy *HTTPRetryPolicy `json:"httpRetryPolicy,omitempty"`
+	// The retry policy of grpc routes without one.
+	// +optional
+	GRPCRetryPolicy *GRPCRetryPolicy `json:"grpcRetryPolicy,omitempty"`
+	// The timeout of routes without one, by route protocol.
+	// +optional
+	Timeout *ListenerTimeout `json:"timeout,omitempty"`
+}
+
+// MeshPolicyListenerDefaults are defaults applied to listeners of VirtualNodes in the mesh.
+type MeshPolicyListenerDefaults struct {
+	// The outlier detection of listeners without one.
+	// +optional
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	// The connection pool settings of listeners without them, by listener protocol.
+	// +optional
+	ConnectionPool *VirtualNodeConnectionPool `json:"connectionPool,omitempty"`
+	// The timeout of listeners without one, by listener protocol.
+	// +optional
+	Timeout *ListenerTimeout `json:"timeout,omitempty"`
+}
+
+// MeshPolicySpec defines the desired state of MeshPolicy
+type MeshPolicySpec struct {
+	// Defaults applied to every route in the mesh, unless the route specifies the field itself.
+	// +optional
+	RouteDefaults *MeshPolicyRouteDefaults `json:"routeDefaults,omitempty"`
+
+	// Defaults applied to every virtualNode listener in the mesh, unless the listener specifies the field itself.
+	// +optional
+	ListenerDefaults *MeshPolicyListenerDefaults `json:"listenerDefaults,omitempty"`
+
+	// A reference to k8s Mesh CR that this MeshPolicy belongs to.
+	// The admission controller populates it using Meshes's selector, and prevents users from setting this field.
+	//
+	// Populated by the system.
+	// Read-only.
+	// +optional
+	MeshRef *MeshReference `json:"meshRef,omitempty"`
+}
+
+// MeshPolicyStatus defines the observed state of MeshPolicy
+type MeshPolicyStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=all
+// +kubebuilder:subresource:status
+// MeshPolicy is the Schema for the meshpolicies API
+type MeshPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeshPolicySpec   `json:"spec,omitempty"`
+	Status MeshPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MeshPolicyList contains a list of MeshPolicy
+type MeshPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MeshPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MeshPolicy{}, &MeshPolicyList{})
+}
@@ -1,6 +1,9 @@
 package v1beta2
 
-import "k8s.io/apimachinery/pkg/types"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
 
 // +kubebuilder:validation:Enum=s;ms
 type DurationUnit string
@@ -245,7 +248,130 @@ type SubjectAlternativeNames struct {
 	Match *SubjectAlternativeNameMatchers `json:"match"`
 }
 
+// AWSResourceMetadata refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_ResourceMetadata.html
+type AWSResourceMetadata struct {
+	// UID is the unique identifier of the AppMesh object.
+	// +optional
+	UID *string `json:"uid,omitempty"`
+	// MeshOwner is the AWS IAM account ID of the mesh owner.
+	// +optional
+	MeshOwner *string `json:"meshOwner,omitempty"`
+	// ResourceOwner is the AWS IAM account ID of the resource owner.
+	// +optional
+	ResourceOwner *string `json:"resourceOwner,omitempty"`
+}
+
 const (
 	IpPreferenceIPv4 string = "IPv4_ONLY"
 	IpPreferenceIPv6 string = "IPv6_ONLY"
 )
+
+// Condition types shared by all AppMesh resources.
+// They follow https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+const (
+	// ConditionReady is True when the resource is synced to AppMesh, all its references are resolved and the AppMesh resource is active.
+	ConditionReady = "Ready"
+	// ConditionSynced is True when the desired state of the resource has been applied to AppMesh.
+	ConditionSynced = "Synced"
+	// ConditionReferencesResolved is True when all resources referenced by the resource are found and active.
+	ConditionReferencesResolved = "ReferencesResolved"
+	// ConditionDegraded is True when the resource is synced to AppMesh, but the AppMesh resource isn't active.
+	ConditionDegraded = "Degraded"
+)
+
+// Condition reasons shared by all AppMesh resources.
+const (
+	// ReasonReconciled indicates the resource has been reconciled successfully.
+	ReasonReconciled = "Reconciled"
+	// ReasonReferencesUnresolved indicates some referenced resources cannot be found.
+	ReasonReferencesUnresolved = "ReferencesUnresolved"
+	// ReasonReferencesNotReady indicates some referenced resources are found but not active yet.
+	ReasonReferencesNotReady = "ReferencesNotReady"
+	// ReasonSyncFailed indicates the desired state of the resource cannot be applied to AppMesh.
+	ReasonSyncFailed = "SyncFailed"
+	// ReasonAppMeshResourceInactive indicates the AppMesh resource isn't in ACTIVE status.
+	ReasonAppMeshResourceInactive = "AppMeshResourceInactive"
+	// ReasonQuotaExceeded indicates the desired state of the resource would exceed an AppMesh service quota.
+	ReasonQuotaExceeded = "QuotaExceeded"
+	// ReasonAppMeshUnavailable indicates calls to AppMesh are rejected by the circuit breaker after consecutive failures.
+	ReasonAppMeshUnavailable = "AppMeshUnavailable"
+	// ReasonSpecRejected indicates AWS rejected the desired state of the resource as invalid, it's not retried until the resource changes.
+	ReasonSpecRejected = "SpecRejected"
+)
+
+const (
+	// ConditionStuckDeleting is True when the resource is being deleted, but its AWS resources failed to be deleted for longer than the finalizer timeout.
+	ConditionStuckDeleting = "StuckDeleting"
+	// ReasonCleanupTimeout indicates AWS resources failed to be deleted for longer than the finalizer timeout.
+	ReasonCleanupTimeout = "CleanupTimeout"
+)
+
+const (
+	// ConditionConflicted is True when a VirtualNode selects pods also selected by other VirtualNodes,
+	// or a Mesh selects namespaces also selected by other Meshes.
+	ConditionConflicted = "Conflicted"
+	// ReasonSelectorsOverlap indicates the selectors of the resource and other resources select the same objects.
+	ReasonSelectorsOverlap = "SelectorsOverlap"
+	// ReasonNoConflicts indicates no other resource selects the objects selected by the resource.
+	ReasonNoConflicts = "NoConflicts"
+)
+
+const (
+	// ConditionEndpointsHealthy is True when every pod selected by a VirtualNode with listener health checks passes them,
+	// as reported by the pods' Envoys. Its message reports the number of healthy endpoints, e.g. "EndpointsHealthy 2/3".
+	ConditionEndpointsHealthy = "EndpointsHealthy"
+	// ReasonHealthChecksPassing indicates every endpoint passes its health checks.
+	ReasonHealthChecksPassing = "HealthChecksPassing"
+	// ReasonHealthChecksFailing indicates some endpoints fail their health checks, or their health can't be read.
+	ReasonHealthChecksFailing = "HealthChecksFailing"
+	// ReasonNoEndpoints indicates the VirtualNode doesn't select any pod.
+	ReasonNoEndpoints = "NoEndpoints"
+)
+
+const (
+	// ConditionReconciliationPaused is True when reconciliation of the resource is paused by the appmesh.k8s.aws/reconcile annotation.
+	ConditionReconciliationPaused = "ReconciliationPaused"
+	// ReasonPausedByAnnotation indicates reconciliation of the resource is paused by annotation.
+	ReasonPausedByAnnotation = "PausedByAnnotation"
+	// ReasonResumed indicates reconciliation of the resource is resumed once its annotation is removed.
+	ReasonResumed = "Resumed"
+)
+
+const (
+	// ConditionChangesDeferred is True when disruptive changes of the resource are deferred until the maintenance window
+	// of its namespace or mesh. Its message lists the deferred changes.
+	ConditionChangesDeferred = "ChangesDeferred"
+	// ReasonOutsideMaintenanceWindow indicates disruptive changes are deferred since the maintenance window isn't open.
+	ReasonOutsideMaintenanceWindow = "OutsideMaintenanceWindow"
+	// ReasonChangesApplied indicates the deferred changes of the resource have been applied.
+	ReasonChangesApplied = "ChangesApplied"
+)
+
+// Per-kind condition types, kept as aliases so code referring to them keeps compiling.
+// They'll be removed in the next release.
+type (
+	// Deprecated: use string.
+	MeshConditionType = string
+	// Deprecated: use metav1.Condition.
+	MeshCondition = metav1.Condition
+	// Deprecated: use string.
+	VirtualGatewayConditionType = string
+	// Deprecated: use metav1.Condition.
+	VirtualGatewayCondition = metav1.Condition
+	// Deprecated: use string.
+	GatewayRouteConditionType = string
+	// Deprecated: use metav1.Condition.
+	GatewayRouteCondition = metav1.Condition
+	// Deprecated: use string.
+	VirtualNodeConditionType = string
+	// Deprecated: use metav1.Condition.
+	VirtualNodeCondition = metav1.Condition
+	// Deprecated: use string.
+	VirtualServiceConditionType = string
+	// Deprecated: use metav1.Condition.
+	VirtualServiceCondition = metav1.Condition
+	// Deprecated: use string.
+	VirtualRouterConditionType = string
+	// Deprecated: use metav1.Condition.
+	VirtualRouterCondition = metav1.Condition
+)
@@ -17,15 +17,13 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-type VirtualGatewayConditionType string
-
 const (
 	// VirtualGatewayActive is True when the AppMesh VirtualGateway has been created or found via the API
-	VirtualGatewayActive VirtualGatewayConditionType = "VirtualGatewayActive"
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	VirtualGatewayActive = "VirtualGatewayActive"
 )
 
 // +kubebuilder:validation:Enum=grpc;http;http2
@@ -46,22 +44,6 @@ type VirtualGatewayPortMapping struct {
 	Protocol VirtualGatewayPortProtocol `json:"protocol"`
 }
 
-type VirtualGatewayCondition struct {
-	// Type of VirtualGateway condition.
-	Type VirtualGatewayConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
-
 // VirtualGatewayHealthCheckPolicy refers to https://docs.aws.amazon.com/app-mesh/latest/userguide/virtual_gateways.html
 type VirtualGatewayHealthCheckPolicy struct {
 	// The number of consecutive successful health checks that must occur before declaring listener healthy.
@@ -308,6 +290,36 @@ type VirtualGatewayBackendDefaults struct {
 	ClientPolicy *VirtualGatewayClientPolicy `json:"clientPolicy,omitempty"`
 }
 
+// VirtualGatewayLoadBalancerScheme is the scheme of load balancers provisioned for VirtualGateways.
+// +kubebuilder:validation:Enum=internal;internet-facing
+type VirtualGatewayLoadBalancerScheme string
+
+const (
+	VirtualGatewayLoadBalancerSchemeInternal       VirtualGatewayLoadBalancerScheme = "internal"
+	VirtualGatewayLoadBalancerSchemeInternetFacing VirtualGatewayLoadBalancerScheme = "internet-facing"
+)
+
+// VirtualGatewayLoadBalancer defines the load balancer fronting VirtualGateway pods, provisioned via the AWS Load Balancer Controller.
+type VirtualGatewayLoadBalancer struct {
+	// ServiceName is the name of the Service fronting VirtualGateway pods.
+	// If unspecified or empty, it defaults to be the name of VirtualGateway.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ServiceName *string `json:"serviceName,omitempty"`
+	// Scheme of the Network Load Balancer.
+	// If unspecified, it defaults to be internal.
+	// +optional
+	Scheme *VirtualGatewayLoadBalancerScheme `json:"scheme,omitempty"`
+	// TargetGroupARN registers VirtualGateway pods into an existing target group with a TargetGroupBinding,
+	// instead of provisioning a Network Load Balancer.
+	// +optional
+	TargetGroupARN *string `json:"targetGroupARN,omitempty"`
+	// Annotations of the Service, which take precedence over the ones set by the controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // VirtualGatewaySpec defines the desired state of VirtualGateway
 // refers to https://docs.aws.amazon.com/app-mesh/latest/userguide/virtual_gateways.html
 type VirtualGatewaySpec struct {
@@ -338,6 +350,9 @@ type VirtualGatewaySpec struct {
 	// A reference to an object that represents the defaults for backend GatewayRoutes.
 	// +optional
 	BackendDefaults *VirtualGatewayBackendDefaults `json:"backendDefaults,omitempty"`
+	// ProvisionLoadBalancer provisions a Service of type LoadBalancer, or a TargetGroupBinding, fronting pods selected by podSelector on the ports of listeners.
+	// +optional
+	ProvisionLoadBalancer *VirtualGatewayLoadBalancer `json:"provisionLoadBalancer,omitempty"`
 
 	// A reference to k8s Mesh CR that this VirtualGateway belongs to.
 	// The admission controller populates it using Meshes's selector, and prevents users from setting this field.
@@ -353,9 +368,14 @@ type VirtualGatewayStatus struct {
 	// VirtualGatewayARN is the AppMesh VirtualGateway object's Amazon Resource Name
 	// +optional
 	VirtualGatewayARN *string `json:"virtualGatewayARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh VirtualGateway object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// The current VirtualGateway status.
 	// +optional
-	Conditions []VirtualGatewayCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// The generation observed by the VirtualGateway controller.
 	// +optional
@@ -17,7 +17,6 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -286,6 +285,10 @@ type Listener struct {
 	// The health check information for the listener.
 	// +optional
 	HealthCheck *HealthCheckPolicy `json:"healthCheck,omitempty"`
+	// Derive the health check of the listener from the readinessProbe of pods selected by the VirtualNode.
+	// Mutually exclusive with healthCheck.
+	// +optional
+	HealthCheckFromProbe *bool `json:"healthCheckFromProbe,omitempty"`
 	// The outlier detection for the listener
 	// +optional
 	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
@@ -312,6 +315,14 @@ type AWSCloudMapInstanceAttribute struct {
 	Value string `json:"value"`
 }
 
+// AWSCloudMapNamespaceManagement configures the controller to manage the AWS Cloud Map private DNS namespace of a virtual node.
+type AWSCloudMapNamespaceManagement struct {
+	// Delete the namespace when the last VirtualNode using it is deleted.
+	// Only namespaces created by the controller are deleted.
+	// +optional
+	DeleteWhenUnused *bool `json:"deleteWhenUnused,omitempty"`
+}
+
 // AWSCloudMapServiceDiscovery refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_AwsCloudMapServiceDiscovery.html
 type AWSCloudMapServiceDiscovery struct {
 	// The name of the AWS Cloud Map namespace to use.
@@ -325,6 +336,9 @@ type AWSCloudMapServiceDiscovery struct {
 	// A string map that contains attributes with values that you can use to filter instances by any custom attribute that you specified when you registered the instance
 	// +optional
 	Attributes []AWSCloudMapInstanceAttribute `json:"attributes,omitempty"`
+	// Create the AWS Cloud Map private DNS namespace if it doesn't exist, instead of requiring it to be created beforehand.
+	// +optional
+	ManageNamespace *AWSCloudMapNamespaceManagement `json:"manageNamespace,omitempty"`
 }
 
 // DNSServiceDiscovery refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_DnsServiceDiscovery.html
@@ -337,6 +351,22 @@ type DNSServiceDiscovery struct {
 	ResponseType *string `json:"responseType,omitempty"`
 }
 
+// ServiceDiscoveryType is the type of service discovery of a virtual node.
+// +kubebuilder:validation:Enum=AWSCloudMap;DNS
+type ServiceDiscoveryType string
+
+const (
+	ServiceDiscoveryTypeAWSCloudMap ServiceDiscoveryType = "AWSCloudMap"
+	ServiceDiscoveryTypeDNS         ServiceDiscoveryType = "DNS"
+)
+
+// ServiceDiscoveryFailover configures failover between the AWS Cloud Map and DNS service discovery of a virtual node.
+type ServiceDiscoveryFailover struct {
+	// The service discovery configured in AppMesh while it's available, the other one is the fallback.
+	// AWS Cloud Map is unavailable when its namespace isn't found or its service can't be created.
+	Primary ServiceDiscoveryType `json:"primary"`
+}
+
 // ServiceDiscovery refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_ServiceDiscovery.html
 type ServiceDiscovery struct {
 	// Specifies any AWS Cloud Map information for the virtual node.
@@ -345,6 +375,10 @@ type ServiceDiscovery struct {
 	// Specifies the DNS information for the virtual node.
 	// +optional
 	DNS *DNSServiceDiscovery `json:"dns,omitempty"`
+	// Failover between AWS Cloud Map and DNS service discovery, only one of them is configured in AppMesh at a time.
+	// Both awsCloudMap and dns must be specified.
+	// +optional
+	Failover *ServiceDiscoveryFailover `json:"failover,omitempty"`
 }
 
 type JsonFormatRef struct {
@@ -391,29 +425,12 @@ type Logging struct {
 	AccessLog *AccessLog `json:"accessLog,omitempty"`
 }
 
-type VirtualNodeConditionType string
-
 const (
 	// VirtualNodeActive is True when the AppMesh VirtualNode has been created or found via the API
-	VirtualNodeActive VirtualNodeConditionType = "VirtualNodeActive"
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	VirtualNodeActive = "VirtualNodeActive"
 )
 
-type VirtualNodeCondition struct {
-	// Type of VirtualNode condition.
-	Type VirtualNodeConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
-
 // VirtualNodeSpec defines the desired state of VirtualNode
 // refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_VirtualNodeSpec.html
 type VirtualNodeSpec struct {
@@ -427,6 +444,10 @@ type VirtualNodeSpec struct {
 	// 	if absent, it selects no pod.
 	// +optional
 	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// PodSelectorTerms selects additional Pods using labels to designate VirtualNode membership.
+	// Pods matching podSelector or any of the terms are selected, e.g. the Pods of both Deployments of a blue/green pair.
+	// +optional
+	PodSelectorTerms []metav1.LabelSelector `json:"podSelectorTerms,omitempty"`
 	// The listener that the virtual node is expected to receive inbound traffic from
 	// +kubebuilder:validation:MinItems=0
 	// +optional
@@ -462,9 +483,17 @@ type VirtualNodeStatus struct {
 	// VirtualNodeARN is the AppMesh VirtualNode object's Amazon Resource Name
 	// +optional
 	VirtualNodeARN *string `json:"virtualNodeARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh VirtualNode object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// The current VirtualNode status.
 	// +optional
-	Conditions []VirtualNodeCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ActiveServiceDiscovery is the service discovery configured in AppMesh when spec.serviceDiscovery.failover is specified.
+	// +optional
+	ActiveServiceDiscovery *ServiceDiscoveryType `json:"activeServiceDiscovery,omitempty"`
 
 	// The generation observed by the VirtualNode controller.
 	// +optional
@@ -17,7 +17,6 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,12 +28,17 @@ type VirtualRouterListener struct {
 
 // WeightedTarget refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_WeightedTarget.html
 type WeightedTarget struct {
-	// Reference to Kubernetes VirtualNode CR in cluster to associate with the weighted target. Exactly one of 'virtualNodeRef' or 'virtualNodeARN' must be specified.
+	// Reference to Kubernetes VirtualNode CR in cluster to associate with the weighted target. Exactly one of 'virtualNodeRef', 'virtualNodeARN' or 'virtualNodeSelector' must be specified.
 	// +optional
 	VirtualNodeRef *VirtualNodeReference `json:"virtualNodeRef,omitempty"`
-	// Amazon Resource Name to AppMesh VirtualNode object to associate with the weighted target. Exactly one of 'virtualNodeRef' or 'virtualNodeARN' must be specified.
+	// Amazon Resource Name to AppMesh VirtualNode object to associate with the weighted target. Exactly one of 'virtualNodeRef', 'virtualNodeARN' or 'virtualNodeSelector' must be specified.
 	// +optional
 	VirtualNodeARN *string `json:"virtualNodeARN,omitempty"`
+	// VirtualNodeSelector selects Kubernetes VirtualNode CRs in the VirtualRouter's namespace by labels to associate with the weighted target.
+	// The weight is split among selected VirtualNodes evenly, or by their appmesh.k8s.aws/weight annotation.
+	// Exactly one of 'virtualNodeRef', 'virtualNodeARN' or 'virtualNodeSelector' must be specified.
+	// +optional
+	VirtualNodeSelector *metav1.LabelSelector `json:"virtualNodeSelector,omitempty"`
 	// The relative weight of the weighted target.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
@@ -108,6 +112,16 @@ type TCPRetryPolicyEvent string
 // +kubebuilder:validation:Enum=cancelled;deadline-exceeded;internal;resource-exhausted;unavailable
 type GRPCRetryPolicyEvent string
 
+// +kubebuilder:validation:Enum=idempotent-only;all
+type GRPCRetryProfile string
+
+const (
+	// GRPCRetryProfileIdempotentOnly retries only failures of requests that didn't reach the application, so retrying them is safe for any method.
+	GRPCRetryProfileIdempotentOnly GRPCRetryProfile = "idempotent-only"
+	// GRPCRetryProfileAll retries all retryable failures, for routes whose methods are all idempotent.
+	GRPCRetryProfileAll GRPCRetryProfile = "all"
+)
+
 // HTTPRetryPolicy refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_HttpRetryPolicy.html
 type HTTPRetryPolicy struct {
 	// +kubebuilder:validation:MinItems=1
@@ -164,6 +178,28 @@ type TCPRouteMatch struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	Port *int64 `json:"port,omitempty"`
+	// Specifies an inclusive range of ports to match requests with.
+	// The route is created as one AppMesh route per port, named <route name>-<port>.
+	// +optional
+	PortRange *TCPRoutePortRange `json:"portRange,omitempty"`
+	// Specifies the ports to match requests with.
+	// The route is created as one AppMesh route per port, named <route name>-<port>.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Ports []int64 `json:"ports,omitempty"`
+}
+
+// TCPRoutePortRange refers to an inclusive range of ports matched by a TCPRoute.
+type TCPRoutePortRange struct {
+	// The first port of the range.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Start int64 `json:"start"`
+	// The last port of the range.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	End int64 `json:"end"`
 }
 
 // GRPCRouteMetadata refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_GrpcRouteMetadata.html
@@ -211,6 +247,10 @@ type GRPCRouteAction struct {
 
 // GRPCRetryPolicy refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_GrpcRetryPolicy.html
 type GRPCRetryPolicy struct {
+	// The retry profile that expands into grpcRetryEvents, httpRetryEvents and tcpRetryEvents,
+	// mutually exclusive with listing them.
+	// +optional
+	Profile *GRPCRetryProfile `json:"profile,omitempty"`
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=5
 	// +optional
@@ -267,29 +307,18 @@ type Route struct {
 	Priority *int64 `json:"priority,omitempty"`
 }
 
-type VirtualRouterConditionType string
-
 const (
 	// VirtualRouterActive is True when the AppMesh VirtualRouter has been created or found via the API
-	VirtualRouterActive VirtualRouterConditionType = "VirtualRouterActive"
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	VirtualRouterActive = "VirtualRouterActive"
+	// ReasonRoutesPartiallyListed indicates some pages of the AppMesh routes of the VirtualRouter failed to be listed,
+	// AppMesh routes removed from spec may not have been deleted.
+	ReasonRoutesPartiallyListed = "RoutesPartiallyListed"
+	// ReasonRouteChangeRejected indicates a change of an AppMesh route of the VirtualRouter is rejected by a route pre hook,
+	// or a pre hook failed to be invoked. The change is retried until every pre hook accepts it.
+	ReasonRouteChangeRejected = "RouteChangeRejected"
 )
 
-type VirtualRouterCondition struct {
-	// Type of VirtualRouter condition.
-	Type VirtualRouterConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
-
 // VirtualRouterSpec defines the desired state of VirtualRouter
 // refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_VirtualRouterSpec.html
 type VirtualRouterSpec struct {
@@ -305,6 +334,11 @@ type VirtualRouterSpec struct {
 	// +optional
 	Routes []Route `json:"routes,omitempty"`
 
+	// AutoCreateVirtualService creates and owns a VirtualService of the same name, with this VirtualRouter as provider.
+	// The VirtualService is deleted along with this VirtualRouter, or when AutoCreateVirtualService is disabled.
+	// +optional
+	AutoCreateVirtualService *bool `json:"autoCreateVirtualService,omitempty"`
+
 	// A reference to k8s Mesh CR that this VirtualRouter belongs to.
 	// The admission controller populates it using Meshes's selector, and prevents users from setting this field.
 	//
@@ -319,12 +353,17 @@ type VirtualRouterStatus struct {
 	// VirtualRouterARN is the AppMesh VirtualRouter object's Amazon Resource Name.
 	// +optional
 	VirtualRouterARN *string `json:"virtualRouterARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh VirtualRouter object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// RouteARNs is a map of AppMesh Route objects' Amazon Resource Names, indexed by route name.
 	// +optional
 	RouteARNs map[string]string `json:"routeARNs,omitempty"`
 	// The current VirtualRouter status.
 	// +optional
-	Conditions []VirtualRouterCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// The generation observed by the VirtualRouter controller.
 	// +optional
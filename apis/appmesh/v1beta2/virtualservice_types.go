@@ -17,7 +17,6 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,29 +50,12 @@ type VirtualServiceProvider struct {
 	VirtualRouter *VirtualRouterServiceProvider `json:"virtualRouter,omitempty"`
 }
 
-type VirtualServiceConditionType string
-
 const (
 	// VirtualServiceActive is True when the AppMesh VirtualService has been created or found via the API
-	VirtualServiceActive VirtualServiceConditionType = "VirtualServiceActive"
+	// Prefer ConditionReady, which also reflects failures to reconcile the resource.
+	VirtualServiceActive = "VirtualServiceActive"
 )
 
-type VirtualServiceCondition struct {
-	// Type of VirtualService condition.
-	Type VirtualServiceConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status corev1.ConditionStatus `json:"status"`
-	// Last time the condition transitioned from one status to another.
-	// +optional
-	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
-	// The reason for the condition's last transition.
-	// +optional
-	Reason *string `json:"reason,omitempty"`
-	// A human readable message indicating details about the transition.
-	// +optional
-	Message *string `json:"message,omitempty"`
-}
-
 // VirtualServiceSpec defines the desired state of VirtualService
 // refers to https://docs.aws.amazon.com/app-mesh/latest/APIReference/API_VirtualServiceSpec.html
 type VirtualServiceSpec struct {
@@ -100,9 +82,14 @@ type VirtualServiceStatus struct {
 	// VirtualServiceARN is the AppMesh VirtualService object's Amazon Resource Name.
 	// +optional
 	VirtualServiceARN *string `json:"virtualServiceARN,omitempty"`
+	// ResourceMetadata is the metadata of the AppMesh VirtualService object.
+	// +optional
+	ResourceMetadata *AWSResourceMetadata `json:"resourceMetadata,omitempty"`
 	// The current VirtualService status.
 	// +optional
-	Conditions []VirtualServiceCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// The generation observed by the VirtualService controller.
 	// +optional
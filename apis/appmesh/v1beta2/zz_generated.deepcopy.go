@@ -41,6 +41,26 @@ func (in *AWSCloudMapInstanceAttribute) DeepCopy() *AWSCloudMapInstanceAttribute
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSCloudMapNamespaceManagement) DeepCopyInto(out *AWSCloudMapNamespaceManagement) {
+	*out = *in
+	if in.DeleteWhenUnused != nil {
+		in, out := &in.DeleteWhenUnused, &out.DeleteWhenUnused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSCloudMapNamespaceManagement.
+func (in *AWSCloudMapNamespaceManagement) DeepCopy() *AWSCloudMapNamespaceManagement {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSCloudMapNamespaceManagement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSCloudMapServiceDiscovery) DeepCopyInto(out *AWSCloudMapServiceDiscovery) {
 	*out = *in
@@ -49,6 +69,11 @@ func (in *AWSCloudMapServiceDiscovery) DeepCopyInto(out *AWSCloudMapServiceDisco
 		*out = make([]AWSCloudMapInstanceAttribute, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManageNamespace != nil {
+		in, out := &in.ManageNamespace, &out.ManageNamespace
+		*out = new(AWSCloudMapNamespaceManagement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSCloudMapServiceDiscovery.
@@ -61,6 +86,36 @@ func (in *AWSCloudMapServiceDiscovery) DeepCopy() *AWSCloudMapServiceDiscovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSResourceMetadata) DeepCopyInto(out *AWSResourceMetadata) {
+	*out = *in
+	if in.UID != nil {
+		in, out := &in.UID, &out.UID
+		*out = new(string)
+		**out = **in
+	}
+	if in.MeshOwner != nil {
+		in, out := &in.MeshOwner, &out.MeshOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceOwner != nil {
+		in, out := &in.ResourceOwner, &out.ResourceOwner
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSResourceMetadata.
+func (in *AWSResourceMetadata) DeepCopy() *AWSResourceMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSResourceMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessLog) DeepCopyInto(out *AccessLog) {
 	*out = *in
@@ -206,6 +261,16 @@ func (in *BackendGroupSpec) DeepCopyInto(out *BackendGroupSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VirtualServiceSelector != nil {
+		in, out := &in.VirtualServiceSelector, &out.VirtualServiceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MeshRef != nil {
 		in, out := &in.MeshRef, &out.MeshRef
 		*out = new(MeshReference)
@@ -507,6 +572,11 @@ func (in *GRPCGatewayRouteMetadata) DeepCopy() *GRPCGatewayRouteMetadata {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GRPCRetryPolicy) DeepCopyInto(out *GRPCRetryPolicy) {
 	*out = *in
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(GRPCRetryProfile)
+		**out = **in
+	}
 	if in.GRPCRetryEvents != nil {
 		in, out := &in.GRPCRetryEvents, &out.GRPCRetryEvents
 		*out = make([]GRPCRetryPolicyEvent, len(*in))
@@ -738,35 +808,6 @@ func (in *GatewayRoute) DeepCopyObject() runtime.Object {
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayRouteCondition) DeepCopyInto(out *GatewayRouteCondition) {
-	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteCondition.
-func (in *GatewayRouteCondition) DeepCopy() *GatewayRouteCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(GatewayRouteCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayRouteHostnameMatch) DeepCopyInto(out *GatewayRouteHostnameMatch) {
 	*out = *in
@@ -947,9 +988,14 @@ func (in *GatewayRouteStatus) DeepCopyInto(out *GatewayRouteStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]GatewayRouteCondition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1537,6 +1583,11 @@ func (in *Listener) DeepCopyInto(out *Listener) {
 		*out = new(HealthCheckPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HealthCheckFromProbe != nil {
+		in, out := &in.HealthCheckFromProbe, &out.HealthCheckFromProbe
+		*out = new(bool)
+		**out = **in
+	}
 	if in.OutlierDetection != nil {
 		in, out := &in.OutlierDetection, &out.OutlierDetection
 		*out = new(OutlierDetection)
@@ -1802,6 +1853,32 @@ func (in *LoggingFormat) DeepCopy() *LoggingFormat {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchRange) DeepCopyInto(out *MatchRange) {
 	*out = *in
@@ -1845,30 +1922,40 @@ func (in *Mesh) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeshCondition) DeepCopyInto(out *MeshCondition) {
+func (in *MeshCoverage) DeepCopyInto(out *MeshCoverage) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+	if in.LastReportTime != nil {
+		in, out := &in.LastReportTime, &out.LastReportTime
 		*out = (*in).DeepCopy()
 	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
+	if in.Gaps != nil {
+		in, out := &in.Gaps, &out.Gaps
+		*out = make([]MeshCoverageGap, len(*in))
+		copy(*out, *in)
 	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshCoverage.
+func (in *MeshCoverage) DeepCopy() *MeshCoverage {
+	if in == nil {
+		return nil
 	}
+	out := new(MeshCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshCoverageGap) DeepCopyInto(out *MeshCoverageGap) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshCondition.
-func (in *MeshCondition) DeepCopy() *MeshCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshCoverageGap.
+func (in *MeshCoverageGap) DeepCopy() *MeshCoverageGap {
 	if in == nil {
 		return nil
 	}
-	out := new(MeshCondition)
+	out := new(MeshCoverageGap)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1906,181 +1993,540 @@ func (in *MeshList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeshReference) DeepCopyInto(out *MeshReference) {
+func (in *MeshMemberCounts) DeepCopyInto(out *MeshMemberCounts) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshReference.
-func (in *MeshReference) DeepCopy() *MeshReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshMemberCounts.
+func (in *MeshMemberCounts) DeepCopy() *MeshMemberCounts {
 	if in == nil {
 		return nil
 	}
-	out := new(MeshReference)
+	out := new(MeshMemberCounts)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeshServiceDiscovery) DeepCopyInto(out *MeshServiceDiscovery) {
+func (in *MeshMembersStatus) DeepCopyInto(out *MeshMembersStatus) {
 	*out = *in
-	if in.IpPreference != nil {
-		in, out := &in.IpPreference, &out.IpPreference
-		*out = new(string)
-		**out = **in
+	out.VirtualNodes = in.VirtualNodes
+	out.VirtualRouters = in.VirtualRouters
+	out.VirtualServices = in.VirtualServices
+	out.VirtualGateways = in.VirtualGateways
+	if in.LastFullReconcileTime != nil {
+		in, out := &in.LastFullReconcileTime, &out.LastFullReconcileTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshServiceDiscovery.
-func (in *MeshServiceDiscovery) DeepCopy() *MeshServiceDiscovery {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshMembersStatus.
+func (in *MeshMembersStatus) DeepCopy() *MeshMembersStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MeshServiceDiscovery)
+	out := new(MeshMembersStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeshSpec) DeepCopyInto(out *MeshSpec) {
+func (in *MeshPolicy) DeepCopyInto(out *MeshPolicy) {
 	*out = *in
-	if in.AWSName != nil {
-		in, out := &in.AWSName, &out.AWSName
-		*out = new(string)
-		**out = **in
-	}
-	if in.NamespaceSelector != nil {
-		in, out := &in.NamespaceSelector, &out.NamespaceSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EgressFilter != nil {
-		in, out := &in.EgressFilter, &out.EgressFilter
-		*out = new(EgressFilter)
-		**out = **in
-	}
-	if in.MeshOwner != nil {
-		in, out := &in.MeshOwner, &out.MeshOwner
-		*out = new(string)
-		**out = **in
-	}
-	if in.ServiceDiscovery != nil {
-		in, out := &in.ServiceDiscovery, &out.ServiceDiscovery
-		*out = new(MeshServiceDiscovery)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshSpec.
-func (in *MeshSpec) DeepCopy() *MeshSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicy.
+func (in *MeshPolicy) DeepCopy() *MeshPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(MeshSpec)
+	out := new(MeshPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MeshPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeshStatus) DeepCopyInto(out *MeshStatus) {
+func (in *MeshPolicyList) DeepCopyInto(out *MeshPolicyList) {
 	*out = *in
-	if in.MeshARN != nil {
-		in, out := &in.MeshARN, &out.MeshARN
-		*out = new(string)
-		**out = **in
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]MeshCondition, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MeshPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ObservedGeneration != nil {
-		in, out := &in.ObservedGeneration, &out.ObservedGeneration
-		*out = new(int64)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshStatus.
-func (in *MeshStatus) DeepCopy() *MeshStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicyList.
+func (in *MeshPolicyList) DeepCopy() *MeshPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(MeshStatus)
+	out := new(MeshPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MeshPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OutlierDetection) DeepCopyInto(out *OutlierDetection) {
+func (in *MeshPolicyListenerDefaults) DeepCopyInto(out *MeshPolicyListenerDefaults) {
 	*out = *in
-	out.Interval = in.Interval
-	out.BaseEjectionDuration = in.BaseEjectionDuration
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionPool != nil {
+		in, out := &in.ConnectionPool, &out.ConnectionPool
+		*out = new(VirtualNodeConnectionPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(ListenerTimeout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetection.
-func (in *OutlierDetection) DeepCopy() *OutlierDetection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicyListenerDefaults.
+func (in *MeshPolicyListenerDefaults) DeepCopy() *MeshPolicyListenerDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(OutlierDetection)
+	out := new(MeshPolicyListenerDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PortMapping) DeepCopyInto(out *PortMapping) {
+func (in *MeshPolicyRouteDefaults) DeepCopyInto(out *MeshPolicyRouteDefaults) {
 	*out = *in
+	if in.HTTPRetryPolicy != nil {
+		in, out := &in.HTTPRetryPolicy, &out.HTTPRetryPolicy
+		*out = new(HTTPRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCRetryPolicy != nil {
+		in, out := &in.GRPCRetryPolicy, &out.GRPCRetryPolicy
+		*out = new(GRPCRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(ListenerTimeout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortMapping.
-func (in *PortMapping) DeepCopy() *PortMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicyRouteDefaults.
+func (in *MeshPolicyRouteDefaults) DeepCopy() *MeshPolicyRouteDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(PortMapping)
+	out := new(MeshPolicyRouteDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryMatchMethod) DeepCopyInto(out *QueryMatchMethod) {
+func (in *MeshPolicySpec) DeepCopyInto(out *MeshPolicySpec) {
 	*out = *in
-	if in.Exact != nil {
-		in, out := &in.Exact, &out.Exact
-		*out = new(string)
+	if in.RouteDefaults != nil {
+		in, out := &in.RouteDefaults, &out.RouteDefaults
+		*out = new(MeshPolicyRouteDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ListenerDefaults != nil {
+		in, out := &in.ListenerDefaults, &out.ListenerDefaults
+		*out = new(MeshPolicyListenerDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MeshRef != nil {
+		in, out := &in.MeshRef, &out.MeshRef
+		*out = new(MeshReference)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryMatchMethod.
-func (in *QueryMatchMethod) DeepCopy() *QueryMatchMethod {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicySpec.
+func (in *MeshPolicySpec) DeepCopy() *MeshPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryMatchMethod)
+	out := new(MeshPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Route) DeepCopyInto(out *Route) {
+func (in *MeshPolicyStatus) DeepCopyInto(out *MeshPolicyStatus) {
 	*out = *in
-	if in.GRPCRoute != nil {
-		in, out := &in.GRPCRoute, &out.GRPCRoute
-		*out = new(GRPCRoute)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HTTPRoute != nil {
-		in, out := &in.HTTPRoute, &out.HTTPRoute
-		*out = new(HTTPRoute)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HTTP2Route != nil {
-		in, out := &in.HTTP2Route, &out.HTTP2Route
-		*out = new(HTTPRoute)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshPolicyStatus.
+func (in *MeshPolicyStatus) DeepCopy() *MeshPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshReference) DeepCopyInto(out *MeshReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshReference.
+func (in *MeshReference) DeepCopy() *MeshReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshReplicaStatus) DeepCopyInto(out *MeshReplicaStatus) {
+	*out = *in
+	if in.MeshARN != nil {
+		in, out := &in.MeshARN, &out.MeshARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshReplicaStatus.
+func (in *MeshReplicaStatus) DeepCopy() *MeshReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshReplication) DeepCopyInto(out *MeshReplication) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]MeshReplicationRegion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshReplication.
+func (in *MeshReplication) DeepCopy() *MeshReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshReplicationRegion) DeepCopyInto(out *MeshReplicationRegion) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshReplicationRegion.
+func (in *MeshReplicationRegion) DeepCopy() *MeshReplicationRegion {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshReplicationRegion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshServiceDiscovery) DeepCopyInto(out *MeshServiceDiscovery) {
+	*out = *in
+	if in.IpPreference != nil {
+		in, out := &in.IpPreference, &out.IpPreference
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshServiceDiscovery.
+func (in *MeshServiceDiscovery) DeepCopy() *MeshServiceDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshServiceDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshSpec) DeepCopyInto(out *MeshSpec) {
+	*out = *in
+	if in.AWSName != nil {
+		in, out := &in.AWSName, &out.AWSName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AWSNameSuffix != nil {
+		in, out := &in.AWSNameSuffix, &out.AWSNameSuffix
+		*out = new(string)
+		**out = **in
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressFilter != nil {
+		in, out := &in.EgressFilter, &out.EgressFilter
+		*out = new(EgressFilter)
+		**out = **in
+	}
+	if in.MeshOwner != nil {
+		in, out := &in.MeshOwner, &out.MeshOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceDiscovery != nil {
+		in, out := &in.ServiceDiscovery, &out.ServiceDiscovery
+		*out = new(MeshServiceDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSEnforcementMode != nil {
+		in, out := &in.TLSEnforcementMode, &out.TLSEnforcementMode
+		*out = new(TLSEnforcementMode)
+		**out = **in
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(MeshReplication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshSpec.
+func (in *MeshSpec) DeepCopy() *MeshSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshStatus) DeepCopyInto(out *MeshStatus) {
+	*out = *in
+	if in.MeshARN != nil {
+		in, out := &in.MeshARN, &out.MeshARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObservedGeneration != nil {
+		in, out := &in.ObservedGeneration, &out.ObservedGeneration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TLSAudit != nil {
+		in, out := &in.TLSAudit, &out.TLSAudit
+		*out = new(MeshTLSAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]MeshReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = new(MeshMembersStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Coverage != nil {
+		in, out := &in.Coverage, &out.Coverage
+		*out = new(MeshCoverage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshStatus.
+func (in *MeshStatus) DeepCopy() *MeshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshTLSAudit) DeepCopyInto(out *MeshTLSAudit) {
+	*out = *in
+	if in.LastAuditTime != nil {
+		in, out := &in.LastAuditTime, &out.LastAuditTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]MeshTLSAuditViolation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshTLSAudit.
+func (in *MeshTLSAudit) DeepCopy() *MeshTLSAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshTLSAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshTLSAuditViolation) DeepCopyInto(out *MeshTLSAuditViolation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshTLSAuditViolation.
+func (in *MeshTLSAuditViolation) DeepCopy() *MeshTLSAuditViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshTLSAuditViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetection) DeepCopyInto(out *OutlierDetection) {
+	*out = *in
+	out.Interval = in.Interval
+	out.BaseEjectionDuration = in.BaseEjectionDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetection.
+func (in *OutlierDetection) DeepCopy() *OutlierDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortMapping) DeepCopyInto(out *PortMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortMapping.
+func (in *PortMapping) DeepCopy() *PortMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(PortMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryMatchMethod) DeepCopyInto(out *QueryMatchMethod) {
+	*out = *in
+	if in.Exact != nil {
+		in, out := &in.Exact, &out.Exact
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryMatchMethod.
+func (in *QueryMatchMethod) DeepCopy() *QueryMatchMethod {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryMatchMethod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	if in.GRPCRoute != nil {
+		in, out := &in.GRPCRoute, &out.GRPCRoute
+		*out = new(GRPCRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPRoute != nil {
+		in, out := &in.HTTPRoute, &out.HTTPRoute
+		*out = new(HTTPRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP2Route != nil {
+		in, out := &in.HTTP2Route, &out.HTTP2Route
+		*out = new(HTTPRoute)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TCPRoute != nil {
 		in, out := &in.TCPRoute, &out.TCPRoute
@@ -2117,6 +2563,11 @@ func (in *ServiceDiscovery) DeepCopyInto(out *ServiceDiscovery) {
 		*out = new(DNSServiceDiscovery)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(ServiceDiscoveryFailover)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDiscovery.
@@ -2129,6 +2580,21 @@ func (in *ServiceDiscovery) DeepCopy() *ServiceDiscovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDiscoveryFailover) DeepCopyInto(out *ServiceDiscoveryFailover) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDiscoveryFailover.
+func (in *ServiceDiscoveryFailover) DeepCopy() *ServiceDiscoveryFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDiscoveryFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubjectAlternativeNameMatchers) DeepCopyInto(out *SubjectAlternativeNameMatchers) {
 	*out = *in
@@ -2246,6 +2712,16 @@ func (in *TCPRouteMatch) DeepCopyInto(out *TCPRouteMatch) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PortRange != nil {
+		in, out := &in.PortRange, &out.PortRange
+		*out = new(TCPRoutePortRange)
+		**out = **in
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPRouteMatch.
@@ -2258,6 +2734,21 @@ func (in *TCPRouteMatch) DeepCopy() *TCPRouteMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPRoutePortRange) DeepCopyInto(out *TCPRoutePortRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPRoutePortRange.
+func (in *TCPRoutePortRange) DeepCopy() *TCPRoutePortRange {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPRoutePortRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPTimeout) DeepCopyInto(out *TCPTimeout) {
 	*out = *in
@@ -2527,35 +3018,6 @@ func (in *VirtualGatewayClientTLSCertificate) DeepCopy() *VirtualGatewayClientTL
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualGatewayCondition) DeepCopyInto(out *VirtualGatewayCondition) {
-	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualGatewayCondition.
-func (in *VirtualGatewayCondition) DeepCopy() *VirtualGatewayCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(VirtualGatewayCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualGatewayConnectionPool) DeepCopyInto(out *VirtualGatewayConnectionPool) {
 	*out = *in
@@ -2846,6 +3308,43 @@ func (in *VirtualGatewayListenerTLSValidationContextTrust) DeepCopy() *VirtualGa
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualGatewayLoadBalancer) DeepCopyInto(out *VirtualGatewayLoadBalancer) {
+	*out = *in
+	if in.ServiceName != nil {
+		in, out := &in.ServiceName, &out.ServiceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scheme != nil {
+		in, out := &in.Scheme, &out.Scheme
+		*out = new(VirtualGatewayLoadBalancerScheme)
+		**out = **in
+	}
+	if in.TargetGroupARN != nil {
+		in, out := &in.TargetGroupARN, &out.TargetGroupARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualGatewayLoadBalancer.
+func (in *VirtualGatewayLoadBalancer) DeepCopy() *VirtualGatewayLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualGatewayLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualGatewayLogging) DeepCopyInto(out *VirtualGatewayLogging) {
 	*out = *in
@@ -2941,6 +3440,11 @@ func (in *VirtualGatewaySpec) DeepCopyInto(out *VirtualGatewaySpec) {
 		*out = new(VirtualGatewayBackendDefaults)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProvisionLoadBalancer != nil {
+		in, out := &in.ProvisionLoadBalancer, &out.ProvisionLoadBalancer
+		*out = new(VirtualGatewayLoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MeshRef != nil {
 		in, out := &in.MeshRef, &out.MeshRef
 		*out = new(MeshReference)
@@ -2966,9 +3470,14 @@ func (in *VirtualGatewayStatus) DeepCopyInto(out *VirtualGatewayStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VirtualGatewayCondition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3123,35 +3632,6 @@ func (in *VirtualNode) DeepCopyObject() runtime.Object {
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualNodeCondition) DeepCopyInto(out *VirtualNodeCondition) {
-	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNodeCondition.
-func (in *VirtualNodeCondition) DeepCopy() *VirtualNodeCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(VirtualNodeCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNodeConnectionPool) DeepCopyInto(out *VirtualNodeConnectionPool) {
 	*out = *in
@@ -3277,6 +3757,13 @@ func (in *VirtualNodeSpec) DeepCopyInto(out *VirtualNodeSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodSelectorTerms != nil {
+		in, out := &in.PodSelectorTerms, &out.PodSelectorTerms
+		*out = make([]v1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Listeners != nil {
 		in, out := &in.Listeners, &out.Listeners
 		*out = make([]Listener, len(*in))
@@ -3338,13 +3825,23 @@ func (in *VirtualNodeStatus) DeepCopyInto(out *VirtualNodeStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VirtualNodeCondition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ActiveServiceDiscovery != nil {
+		in, out := &in.ActiveServiceDiscovery, &out.ActiveServiceDiscovery
+		*out = new(ServiceDiscoveryType)
+		**out = **in
+	}
 	if in.ObservedGeneration != nil {
 		in, out := &in.ObservedGeneration, &out.ObservedGeneration
 		*out = new(int64)
@@ -3389,35 +3886,6 @@ func (in *VirtualRouter) DeepCopyObject() runtime.Object {
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualRouterCondition) DeepCopyInto(out *VirtualRouterCondition) {
-	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualRouterCondition.
-func (in *VirtualRouterCondition) DeepCopy() *VirtualRouterCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(VirtualRouterCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualRouterList) DeepCopyInto(out *VirtualRouterList) {
 	*out = *in
@@ -3531,6 +3999,11 @@ func (in *VirtualRouterSpec) DeepCopyInto(out *VirtualRouterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AutoCreateVirtualService != nil {
+		in, out := &in.AutoCreateVirtualService, &out.AutoCreateVirtualService
+		*out = new(bool)
+		**out = **in
+	}
 	if in.MeshRef != nil {
 		in, out := &in.MeshRef, &out.MeshRef
 		*out = new(MeshReference)
@@ -3556,6 +4029,11 @@ func (in *VirtualRouterStatus) DeepCopyInto(out *VirtualRouterStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RouteARNs != nil {
 		in, out := &in.RouteARNs, &out.RouteARNs
 		*out = make(map[string]string, len(*in))
@@ -3565,7 +4043,7 @@ func (in *VirtualRouterStatus) DeepCopyInto(out *VirtualRouterStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VirtualRouterCondition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3644,35 +4122,6 @@ func (in *VirtualServiceBackend) DeepCopy() *VirtualServiceBackend {
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualServiceCondition) DeepCopyInto(out *VirtualServiceCondition) {
-	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Reason != nil {
-		in, out := &in.Reason, &out.Reason
-		*out = new(string)
-		**out = **in
-	}
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceCondition.
-func (in *VirtualServiceCondition) DeepCopy() *VirtualServiceCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(VirtualServiceCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServiceList) DeepCopyInto(out *VirtualServiceList) {
 	*out = *in
@@ -3788,9 +4237,14 @@ func (in *VirtualServiceStatus) DeepCopyInto(out *VirtualServiceStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(AWSResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VirtualServiceCondition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -3825,6 +4279,11 @@ func (in *WeightedTarget) DeepCopyInto(out *WeightedTarget) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.VirtualNodeSelector != nil {
+		in, out := &in.VirtualNodeSelector, &out.VirtualNodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Port != nil {
 		in, out := &in.Port, &out.Port
 		*out = new(int64)
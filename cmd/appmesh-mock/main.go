@@ -0,0 +1,56 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// appmesh-mock serves the subset of AppMesh and CloudMap APIs used by the controller from memory,
+// for running the controller with --aws-mock-endpoint without an AWS account. state is lost on restart.
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/mockserver"
+	"github.com/spf13/pflag"
+	zapraw "go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func main() {
+	var listenAddress, accountID, region, logLevel string
+	fs := pflag.NewFlagSet("appmesh-mock", pflag.ExitOnError)
+	fs.StringVar(&listenAddress, "listen-address", ":8080", "The address the server listens on")
+	fs.StringVar(&accountID, "aws-account-id", "000000000000", "AWS account ID of the served resources")
+	fs.StringVar(&region, "aws-region", "us-west-2", "AWS region of requests that aren't signed for a region")
+	fs.StringVar(&logLevel, "log-level", "info", "Set the server log level - info(default), debug")
+	_ = fs.Parse(os.Args[1:])
+
+	lvl := zapraw.NewAtomicLevelAt(0)
+	if logLevel == "debug" {
+		lvl = zapraw.NewAtomicLevelAt(-1)
+	}
+	log := zap.New(zap.UseDevMode(false), zap.Level(&lvl))
+
+	server, err := mockserver.NewServer(accountID, region, log)
+	if err != nil {
+		log.Error(err, "failed to create server")
+		os.Exit(1)
+	}
+	log.Info("serving", "address", listenAddress, "accountID", accountID, "region", region)
+	if err := http.ListenAndServe(listenAddress, server); err != nil {
+		log.Error(err, "failed to serve")
+		os.Exit(1)
+	}
+}
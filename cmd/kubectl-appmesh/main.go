@@ -0,0 +1,29 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-appmesh is a kubectl plugin to introspect App Mesh resources managed by the controller.
+// it's invoked by kubectl as "kubectl appmesh" once the binary is on PATH.
+package main
+
+import (
+	"os"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/kubectlplugin"
+)
+
+func main() {
+	os.Exit(kubectlplugin.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -67,3 +67,27 @@ caCert: {{ $ca.Cert | b64enc }}
 clientCert: {{ $cert.Cert | b64enc }}
 clientKey: {{ $cert.Key | b64enc }}
 {{- end -}}
+
+{{/*
+Leader election IDs of all shards
+*/}}
+{{- define "appmesh-controller.leaderElectionIDs" -}}
+{{- $shardCount := int (default 1 .Values.sharding.shardCount) -}}
+{{- if gt $shardCount 1 -}}
+{{- range $shard := until $shardCount }}{{ if $shard }}, {{ end }}appmesh-controller-leader-election-shard-{{ $shard }}{{ end -}}
+{{- else -}}
+appmesh-controller-leader-election
+{{- end -}}
+{{- end -}}
+
+{{/*
+Webhook namespaceSelector expression matching the watched namespaces
+*/}}
+{{- define "appmesh-controller.watchNamespacesExpression" -}}
+- key: kubernetes.io/metadata.name
+  operator: In
+  values:
+  {{- range .Values.watchNamespaces }}
+  - {{ . | quote }}
+  {{- end }}
+{{- end -}}
@@ -16,11 +16,16 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/cloudmap"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
@@ -33,8 +38,13 @@ import (
 // CloudMapReconciler reconciles a VirtualNode pod instance to CloudMap Service
 type cloudMapReconciler struct {
 	k8sClient                   client.Client
+	namespaceRoleResolver       aws.NamespaceRoleResolver
+	controllerOptions           controller.Options
+	reconcileTimeout            time.Duration
+	sharder                     sharding.Sharder
 	log                         logr.Logger
 	finalizerManager            k8s.FinalizerManager
+	stuckDeletionHandler        k8s.StuckDeletionHandler
 	cloudMapResourceManager     cloudmap.ResourceManager
 	enqueueRequestsForPodEvents handler.EventHandler
 	recorder                    record.EventRecorder
@@ -44,15 +54,25 @@ type cloudMapReconciler struct {
 // NewCloudMapReconciler that can respond to pod events (Create/Update/Delete) via notification channels
 func NewCloudMapReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
 	cloudMapResourceManager cloudmap.ResourceManager,
 	podEventNotificationChan <-chan k8s.GenericEvent,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder) *cloudMapReconciler {
 	return &cloudMapReconciler{
 		k8sClient:                   k8sClient,
+		namespaceRoleResolver:       namespaceRoleResolver,
+		controllerOptions:           controllerOptions,
+		reconcileTimeout:            reconcileTimeout,
+		sharder:                     sharder,
 		log:                         log,
 		finalizerManager:            finalizerManager,
+		stuckDeletionHandler:        stuckDeletionHandler,
 		cloudMapResourceManager:     cloudMapResourceManager,
 		enqueueRequestsForPodEvents: cloudmap.NewEnqueueRequestsForPodEvents(k8sClient, log),
 		recorder:                    recorder,
@@ -60,14 +80,19 @@ func NewCloudMapReconciler(
 	}
 }
 
-// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch
-// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes/status,verbs=get
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes/status,verbs=get;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *cloudMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
@@ -75,9 +100,10 @@ func (r *cloudMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("cloudMap").
 		For(&appmesh.VirtualNode{}).
+		Owns(&appmesh.VirtualNode{}).
 		Watches(&k8s.NotificationChannel{Source: r.podEventNotificationChan}, r.enqueueRequestsForPodEvents).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-		Complete(r)
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("cloudMap", audit.NewReconciler("VirtualNode", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *cloudMapReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -85,6 +111,9 @@ func (r *cloudMapReconciler) reconcile(ctx context.Context, req ctrl.Request) er
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, vNode); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, vNode, &vNode.Status.Conditions); err != nil || paused {
+		return err
+	}
 
 	if !vNode.DeletionTimestamp.IsZero() {
 		return r.cleanupCloudMapResources(ctx, vNode)
@@ -113,7 +142,9 @@ func (r *cloudMapReconciler) cleanupCloudMapResources(ctx context.Context, vNode
 	if k8s.HasFinalizer(vNode, k8s.FinalizerAWSCloudMapResources) {
 		if vNode.Spec.ServiceDiscovery != nil && vNode.Spec.ServiceDiscovery.AWSCloudMap != nil {
 			if err := r.cloudMapResourceManager.Cleanup(ctx, vNode); err != nil {
-				return err
+				if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vNode, &vNode.Status.Conditions, err); err != nil {
+					return err
+				}
 			}
 		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, vNode, k8s.FinalizerAWSCloudMapResources); err != nil {
@@ -0,0 +1,121 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/envoy"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	envoyReadinessEvaluateInterval = 5 * time.Second
+	reasonEnvoyNotReady            = "EnvoyNotReady"
+)
+
+// NewEnvoyReadinessReconciler constructs new envoyReadinessReconciler
+func NewEnvoyReadinessReconciler(
+	k8sClient client.Client,
+	readinessChecker envoy.ReadinessChecker,
+	sharder sharding.Sharder,
+	log logr.Logger) *envoyReadinessReconciler {
+	return &envoyReadinessReconciler{
+		k8sClient:        k8sClient,
+		readinessChecker: readinessChecker,
+		sharder:          sharder,
+		log:              log,
+		evaluateInterval: envoyReadinessEvaluateInterval,
+	}
+}
+
+// envoyReadinessReconciler flips the envoy-ready readiness gate of pods once their Envoy is ready.
+type envoyReadinessReconciler struct {
+	k8sClient        client.Client
+	readinessChecker envoy.ReadinessChecker
+	sharder          sharding.Sharder
+	log              logr.Logger
+
+	evaluateInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
+
+func (r *envoyReadinessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *envoyReadinessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("envoyReadiness").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pod, ok := obj.(*corev1.Pod)
+			return ok && k8s.HasPodReadinessGate(pod, k8s.ConditionEnvoyReady)
+		}))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("envoyReadiness", r)))
+}
+
+func (r *envoyReadinessReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	pod := &corev1.Pod{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, pod); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !pod.DeletionTimestamp.IsZero() || !k8s.HasPodReadinessGate(pod, k8s.ConditionEnvoyReady) {
+		return nil
+	}
+	// once flipped, the gate stays open. Envoy restarts are covered by envoy container's own readiness probe.
+	if condition := k8s.GetPodCondition(pod, k8s.ConditionEnvoyReady); condition != nil && condition.Status == corev1.ConditionTrue {
+		return nil
+	}
+
+	ready, reason, err := r.readinessChecker.Check(ctx, pod)
+	if err != nil {
+		return err
+	}
+	if err := r.updateEnvoyReadyCondition(ctx, pod, ready, reason); err != nil {
+		return err
+	}
+	if !ready {
+		return runtime.NewRequeueAfterError(errors.New(reason), r.evaluateInterval)
+	}
+	r.log.V(1).Info("envoy is ready", "pod", k8s.NamespacedName(pod))
+	return nil
+}
+
+func (r *envoyReadinessReconciler) updateEnvoyReadyCondition(ctx context.Context, pod *corev1.Pod, ready bool, reason string) error {
+	oldPod := pod.DeepCopy()
+	status := corev1.ConditionTrue
+	var conditionReason, conditionMessage *string
+	if !ready {
+		status = corev1.ConditionFalse
+		conditionReason = aws.String(reasonEnvoyNotReady)
+		conditionMessage = aws.String(reason)
+	}
+	k8s.UpdatePodCondition(pod, k8s.ConditionEnvoyReady, status, conditionReason, conditionMessage)
+	return r.k8sClient.Status().Patch(ctx, pod, client.MergeFrom(oldPod))
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeReadinessChecker struct {
+	ready  bool
+	reason string
+	calls  int
+}
+
+func (c *fakeReadinessChecker) Check(_ context.Context, _ *corev1.Pod) (bool, string, error) {
+	c.calls++
+	return c.ready, c.reason, nil
+}
+
+func Test_envoyReadinessReconciler_reconcile(t *testing.T) {
+	podWithCondition := func(gated bool, status corev1.ConditionStatus) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}, {Name: "envoy"}},
+			},
+		}
+		if gated {
+			pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: k8s.ConditionEnvoyReady}}
+		}
+		if status != "" {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: k8s.ConditionEnvoyReady, Status: status}}
+		}
+		return pod
+	}
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		checker       *fakeReadinessChecker
+		wantStatus    corev1.ConditionStatus
+		wantReason    string
+		wantCheckCall bool
+		wantErr       error
+	}{
+		{
+			name:          "pod without readiness gate",
+			pod:           podWithCondition(false, ""),
+			checker:       &fakeReadinessChecker{ready: true},
+			wantStatus:    "",
+			wantCheckCall: false,
+		},
+		{
+			name:          "envoy ready",
+			pod:           podWithCondition(true, ""),
+			checker:       &fakeReadinessChecker{ready: true},
+			wantStatus:    corev1.ConditionTrue,
+			wantCheckCall: true,
+		},
+		{
+			name:          "envoy not ready",
+			pod:           podWithCondition(true, ""),
+			checker:       &fakeReadinessChecker{ready: false, reason: "envoy listeners are not configured"},
+			wantStatus:    corev1.ConditionFalse,
+			wantReason:    reasonEnvoyNotReady,
+			wantCheckCall: true,
+			wantErr:       runtime.NewRequeueAfterError(errors.New("envoy listeners are not configured"), envoyReadinessEvaluateInterval),
+		},
+		{
+			name:          "gate already flipped",
+			pod:           podWithCondition(true, corev1.ConditionTrue),
+			checker:       &fakeReadinessChecker{ready: false},
+			wantStatus:    corev1.ConditionTrue,
+			wantCheckCall: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := k8sruntime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			assert.NoError(t, k8sClient.Create(ctx, tt.pod.DeepCopy()))
+
+			r := NewEnvoyReadinessReconciler(k8sClient, tt.checker, sharding.NewSharder(sharding.Config{ShardCount: 1}, k8sClient), logr.New(&log.NullLogSink{}))
+			err := r.reconcile(ctx, ctrl.Request{NamespacedName: k8s.NamespacedName(tt.pod)})
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCheckCall, tt.checker.calls > 0)
+
+			gotPod := &corev1.Pod{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(tt.pod), gotPod))
+			condition := k8s.GetPodCondition(gotPod, k8s.ConditionEnvoyReady)
+			if tt.wantStatus == "" {
+				assert.Nil(t, condition)
+				return
+			}
+			assert.Equal(t, tt.wantStatus, condition.Status)
+			assert.Equal(t, tt.wantReason, condition.Reason)
+		})
+	}
+}
@@ -18,10 +18,15 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/gatewayroute"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
@@ -32,21 +37,37 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 )
 
 // NewGatewayRouteReconciler constructs new gatewayRouteReconciler
 func NewGatewayRouteReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
+	referencesIndexer references.ObjectReferenceIndexer,
 	grResManager gatewayroute.ResourceManager,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder) *gatewayRouteReconciler {
 	return &gatewayRouteReconciler{
 		k8sClient:                              k8sClient,
+		namespaceRoleResolver:                  namespaceRoleResolver,
 		finalizerManager:                       finalizerManager,
+		stuckDeletionHandler:                   stuckDeletionHandler,
+		referencesIndexer:                      referencesIndexer,
 		grResManager:                           grResManager,
 		enqueueRequestsForMeshEvents:           gatewayroute.NewEnqueueRequestsForMeshEvents(k8sClient, log),
 		enqueueRequestsForVirtualGatewayEvents: gatewayroute.NewEnqueueRequestsForVirtualGatewayEvents(k8sClient, log),
+		enqueueRequestsForVirtualServiceEvents: gatewayroute.NewEnqueueRequestsForVirtualServiceEvents(referencesIndexer, log),
+		externalChangesSource:                  externalChangesSource,
+		controllerOptions:                      controllerOptions,
+		reconcileTimeout:                       reconcileTimeout,
+		sharder:                                sharder,
 		log:                                    log,
 		recorder:                               recorder,
 	}
@@ -54,12 +75,20 @@ func NewGatewayRouteReconciler(
 
 // gatewayRouteReconciler reconciles a GatewayRoute object
 type gatewayRouteReconciler struct {
-	k8sClient        client.Client
-	finalizerManager k8s.FinalizerManager
-	grResManager     gatewayroute.ResourceManager
+	k8sClient             client.Client
+	namespaceRoleResolver aws.NamespaceRoleResolver
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	referencesIndexer     references.ObjectReferenceIndexer
+	grResManager          gatewayroute.ResourceManager
 
 	enqueueRequestsForMeshEvents           handler.EventHandler
 	enqueueRequestsForVirtualGatewayEvents handler.EventHandler
+	enqueueRequestsForVirtualServiceEvents handler.EventHandler
+	externalChangesSource                  source.Source
+	controllerOptions                      controller.Options
+	reconcileTimeout                       time.Duration
+	sharder                                sharding.Sharder
 	log                                    logr.Logger
 	recorder                               record.EventRecorder
 }
@@ -69,16 +98,27 @@ type gatewayRouteReconciler struct {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *gatewayRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
 func (r *gatewayRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.referencesIndexer.Setup(&appmesh.GatewayRoute{}, map[string]references.ObjectReferenceIndexFunc{
+		gatewayroute.ReferenceKindVirtualService: gatewayroute.VirtualServiceReferenceIndexFunc,
+	}); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appmesh.GatewayRoute{}).
 		Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
 		Watches(&source.Kind{Type: &appmesh.VirtualGateway{}}, r.enqueueRequestsForVirtualGatewayEvents).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-		Complete(r)
+		Watches(&source.Kind{Type: &appmesh.VirtualService{}}, r.enqueueRequestsForVirtualServiceEvents).
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("gatewayRoute", audit.NewReconciler("GatewayRoute", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *gatewayRouteReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -86,6 +126,9 @@ func (r *gatewayRouteReconciler) reconcile(ctx context.Context, req ctrl.Request
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, gr); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, gr, &gr.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !gr.DeletionTimestamp.IsZero() {
 		return r.cleanupGatewayRoute(ctx, gr)
 	}
@@ -109,7 +152,9 @@ func (r *gatewayRouteReconciler) reconcileGatewayRoute(ctx context.Context, gr *
 func (r *gatewayRouteReconciler) cleanupGatewayRoute(ctx context.Context, gr *appmesh.GatewayRoute) error {
 	if k8s.HasFinalizer(gr, k8s.FinalizerAWSAppMeshResources) {
 		if err := r.grResManager.Cleanup(ctx, gr); err != nil {
-			return err
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, gr, &gr.Status.Conditions, err); err != nil {
+				return err
+			}
 		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, gr, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
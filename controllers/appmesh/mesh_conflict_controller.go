@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conflicts"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewMeshConflictReconciler constructs new meshConflictReconciler
+func NewMeshConflictReconciler(
+	k8sClient client.Client,
+	detector conflicts.Detector,
+	sharder sharding.Sharder,
+	log logr.Logger,
+	recorder record.EventRecorder) *meshConflictReconciler {
+	return &meshConflictReconciler{
+		k8sClient: k8sClient,
+		detector:  detector,
+		sharder:   sharder,
+		log:       log,
+		recorder:  recorder,
+	}
+}
+
+// meshConflictReconciler reports Meshes selecting the same namespaces with a Conflicted condition.
+type meshConflictReconciler struct {
+	k8sClient client.Client
+	detector  conflicts.Detector
+	sharder   sharding.Sharder
+	log       logr.Logger
+	recorder  record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *meshConflictReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *meshConflictReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueueRequestsForMeshSelectionEvents := conflicts.NewEnqueueRequestsForMeshSelectionEvents(r.k8sClient, r.log)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("meshConflict").
+		For(&appmesh.Mesh{}).
+		Watches(&source.Kind{Type: &appmesh.Mesh{}}, enqueueRequestsForMeshSelectionEvents).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, enqueueRequestsForMeshSelectionEvents,
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(sharding.NewMeshReconciler(r.sharder, tracing.NewReconciler("meshConflict", r)))
+}
+
+func (r *meshConflictReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	ms := &appmesh.Mesh{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, ms); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ms.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	msConflicts, err := r.detector.DetectMeshConflicts(ctx, ms)
+	if err != nil {
+		return err
+	}
+
+	oldMS := ms.DeepCopy()
+	wasConflicted := meta.IsStatusConditionTrue(ms.Status.Conditions, appmesh.ConditionConflicted)
+	if !conflicts.SetConflictedCondition(&ms.Status.Conditions, ms.Generation, msConflicts, "mesh", "namespaces") {
+		return nil
+	}
+	// other Mesh controllers patch conditions concurrently, don't overwrite their changes.
+	if err := r.k8sClient.Status().Patch(ctx, ms, client.MergeFromWithOptions(oldMS, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	if len(msConflicts) != 0 {
+		r.recorder.Event(ms, corev1.EventTypeWarning, appmesh.ConditionConflicted, conflicts.ConflictsMessage(msConflicts, "mesh", "namespaces"))
+	} else if wasConflicted {
+		r.recorder.Event(ms, corev1.EventTypeNormal, reasonConflictResolved, "no longer selects the same namespaces as other meshes")
+	}
+	return nil
+}
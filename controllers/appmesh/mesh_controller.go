@@ -18,44 +18,75 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
+)
+
+const (
+	// meshReplicationResyncInterval is the interval meshes are mirrored into their secondary regions.
+	// changes to resources of a mesh in the primary region aren't watched, they're mirrored on the next resync.
+	meshReplicationResyncInterval = 5 * time.Minute
 )
 
 func NewMeshReconciler(
 	k8sClient client.Client,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
 	meshMembersFinalizer mesh.MembersFinalizer,
 	meshResManager mesh.ResourceManager,
+	meshReplicator mesh.Replicator,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder) *meshReconciler {
 	return &meshReconciler{
-		k8sClient:            k8sClient,
-		finalizerManager:     finalizerManager,
-		meshMembersFinalizer: meshMembersFinalizer,
-		meshResManager:       meshResManager,
-		log:                  log,
-		recorder:             recorder,
+		k8sClient:             k8sClient,
+		finalizerManager:      finalizerManager,
+		stuckDeletionHandler:  stuckDeletionHandler,
+		meshMembersFinalizer:  meshMembersFinalizer,
+		meshResManager:        meshResManager,
+		meshReplicator:        meshReplicator,
+		externalChangesSource: externalChangesSource,
+		controllerOptions:     controllerOptions,
+		reconcileTimeout:      reconcileTimeout,
+		sharder:               sharder,
+		log:                   log,
+		recorder:              recorder,
 	}
 }
 
 // meshReconciler reconciles a Mesh object
 type meshReconciler struct {
-	k8sClient            client.Client
-	finalizerManager     k8s.FinalizerManager
-	meshMembersFinalizer mesh.MembersFinalizer
-	meshResManager       mesh.ResourceManager
-	log                  logr.Logger
-	recorder             record.EventRecorder
+	k8sClient             client.Client
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	meshMembersFinalizer  mesh.MembersFinalizer
+	meshResManager        mesh.ResourceManager
+	meshReplicator        mesh.Replicator
+	externalChangesSource source.Source
+	controllerOptions     controller.Options
+	reconcileTimeout      time.Duration
+	sharder               sharding.Sharder
+	log                   logr.Logger
+	recorder              record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes,verbs=get;list;watch;create;update;patch;delete
@@ -69,7 +100,9 @@ func (r *meshReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 func (r *meshReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appmesh.Mesh{}).
-		Complete(r)
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewMeshReconciler(r.sharder, tracing.NewReconciler("mesh", audit.NewReconciler("Mesh", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *meshReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -77,6 +110,9 @@ func (r *meshReconciler) reconcile(ctx context.Context, req ctrl.Request) error
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, ms); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, ms, &ms.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !ms.DeletionTimestamp.IsZero() {
 		return r.cleanupMesh(ctx, ms)
 	}
@@ -84,6 +120,10 @@ func (r *meshReconciler) reconcile(ctx context.Context, req ctrl.Request) error
 		r.recorder.Event(ms, corev1.EventTypeWarning, "ReconcileError", err.Error())
 		return err
 	}
+	// replicas of regions removed from spec.replication remain in status until they're cleaned up.
+	if ms.Spec.Replication != nil || len(ms.Status.Replicas) != 0 {
+		return runtime.NewRequeueAfterError(nil, meshReplicationResyncInterval)
+	}
 	return nil
 }
 
@@ -94,6 +134,9 @@ func (r *meshReconciler) reconcileMesh(ctx context.Context, ms *appmesh.Mesh) er
 	if err := r.meshResManager.Reconcile(ctx, ms); err != nil {
 		return err
 	}
+	if err := r.meshReplicator.Replicate(ctx, ms); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -109,7 +152,14 @@ func (r *meshReconciler) cleanupMesh(ctx context.Context, ms *appmesh.Mesh) erro
 
 	if k8s.HasFinalizer(ms, k8s.FinalizerAWSAppMeshResources) {
 		if err := r.meshResManager.Cleanup(ctx, ms); err != nil {
-			return err
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, ms, &ms.Status.Conditions, err); err != nil {
+				return err
+			}
+		}
+		// replicas are cleaned up on a best-effort basis, an unreachable secondary region mustn't block deletion of the mesh.
+		if err := r.meshReplicator.Cleanup(ctx, ms); err != nil {
+			r.log.Error(err, "failed to cleanup mesh replicas", "mesh", k8s.NamespacedName(ms))
+			r.recorder.Event(ms, corev1.EventTypeWarning, "ReplicaCleanupError", err.Error())
 		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, ms, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
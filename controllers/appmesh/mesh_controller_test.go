@@ -10,7 +10,6 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -49,10 +48,10 @@ func Test_meshReconciler_reconcile(t *testing.T) {
 					},
 					Status: appmesh.MeshStatus{
 						MeshARN: aws.String("arn-1"),
-						Conditions: []appmesh.MeshCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
@@ -103,3 +102,90 @@ func Test_meshReconciler_reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_meshReconciler_cleanupMesh(t *testing.T) {
+	tests := []struct {
+		name           string
+		primaryErr     error
+		wantReplicas   bool
+		replicaErr     error
+		wantErr        error
+		wantEvents     []string
+		wantFinalizers []string
+	}{
+		{
+			name:           "primary and replicas cleaned up",
+			wantReplicas:   true,
+			wantFinalizers: nil,
+		},
+		{
+			name:           "primary cleanup fails",
+			primaryErr:     errors.New("primary cleanup failed"),
+			wantErr:        errors.New("primary cleanup failed"),
+			wantFinalizers: []string{k8s.FinalizerAWSAppMeshResources},
+		},
+		{
+			name:           "replica cleanup fails",
+			wantReplicas:   true,
+			replicaErr:     errors.New("us-west-2 unreachable"),
+			wantEvents:     []string{"Warning ReplicaCleanupError us-west-2 unreachable"},
+			wantFinalizers: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			meshResManager := mock_mesh.NewMockResourceManager(ctrl)
+			meshReplicator := mock_mesh.NewMockReplicator(ctrl)
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+
+			deletionTimestamp := metav1.Now()
+			ms := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "mesh-1",
+					Finalizers:        []string{k8s.FinalizerAWSAppMeshResources},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			}
+			err := k8sClient.Create(ctx, ms.DeepCopy())
+			assert.NoError(t, err)
+			err = k8sClient.Get(ctx, k8s.NamespacedName(ms), ms)
+			assert.NoError(t, err)
+
+			recorder := record.NewFakeRecorder(3)
+			logger := logr.New(&log.NullLogSink{})
+			r := &meshReconciler{
+				k8sClient:            k8sClient,
+				finalizerManager:     k8s.NewDefaultFinalizerManager(k8sClient, logger),
+				meshResManager:       meshResManager,
+				meshReplicator:       meshReplicator,
+				stuckDeletionHandler: k8s.NewDefaultStuckDeletionHandler(k8sClient, recorder, 0, logger),
+				log:                  logger,
+				recorder:             recorder,
+			}
+
+			meshResManager.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(tt.primaryErr)
+			if tt.wantReplicas {
+				meshReplicator.EXPECT().Cleanup(gomock.Any(), gomock.Any()).Return(tt.replicaErr)
+			}
+
+			err = r.cleanupMesh(ctx, ms)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			var gotEvents []string
+			for len(recorder.Events) > 0 {
+				gotEvents = append(gotEvents, <-recorder.Events)
+			}
+			assert.Equal(t, tt.wantEvents, gotEvents)
+			assert.Equal(t, tt.wantFinalizers, ms.Finalizers)
+		})
+	}
+}
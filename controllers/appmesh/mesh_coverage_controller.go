@@ -0,0 +1,96 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/coverage"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// NewMeshCoverageReconciler constructs new meshCoverageReconciler
+func NewMeshCoverageReconciler(
+	k8sClient client.Client,
+	coverageReporter coverage.Reporter,
+	reportInterval time.Duration,
+	sharder sharding.Sharder,
+	log logr.Logger) *meshCoverageReconciler {
+	return &meshCoverageReconciler{
+		k8sClient:        k8sClient,
+		coverageReporter: coverageReporter,
+		reportInterval:   reportInterval,
+		sharder:          sharder,
+		log:              log,
+	}
+}
+
+// meshCoverageReconciler periodically reports the onboarding coverage of workloads selected by meshes.
+type meshCoverageReconciler struct {
+	k8sClient        client.Client
+	coverageReporter coverage.Reporter
+	reportInterval   time.Duration
+	sharder          sharding.Sharder
+	log              logr.Logger
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+
+func (r *meshCoverageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *meshCoverageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("meshCoverage").
+		// coverage updates by this reconciler shouldn't trigger another report.
+		For(&appmesh.Mesh{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(sharding.NewMeshReconciler(r.sharder, tracing.NewReconciler("meshCoverage", r)))
+}
+
+func (r *meshCoverageReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	ms := &appmesh.Mesh{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, ms); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ms.DeletionTimestamp.IsZero() {
+		r.coverageReporter.Forget(ms)
+		return nil
+	}
+
+	meshCoverage, err := r.coverageReporter.Report(ctx, ms)
+	if err != nil {
+		return err
+	}
+	oldMS := ms.DeepCopy()
+	ms.Status.Coverage = meshCoverage
+	if err := r.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS)); err != nil {
+		return err
+	}
+	return runtime.NewRequeueAfterError(errors.New("re-report mesh coverage"), r.reportInterval)
+}
@@ -0,0 +1,127 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	meshMembersStatusInterval = 5 * time.Minute
+)
+
+// NewMeshMembersStatusReconciler constructs new meshMembersStatusReconciler
+func NewMeshMembersStatusReconciler(
+	k8sClient client.Client,
+	membersStatusAggregator mesh.MembersStatusAggregator,
+	sharder sharding.Sharder,
+	log logr.Logger) *meshMembersStatusReconciler {
+	return &meshMembersStatusReconciler{
+		k8sClient:               k8sClient,
+		membersStatusAggregator: membersStatusAggregator,
+		sharder:                 sharder,
+		log:                     log,
+		aggregateInterval:       meshMembersStatusInterval,
+	}
+}
+
+// meshMembersStatusReconciler aggregates the status of mesh members into Mesh status,
+// upon changes to members and periodically.
+type meshMembersStatusReconciler struct {
+	k8sClient               client.Client
+	membersStatusAggregator mesh.MembersStatusAggregator
+	sharder                 sharding.Sharder
+	log                     logr.Logger
+
+	aggregateInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes/status,verbs=get;update;patch
+
+func (r *meshMembersStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *meshMembersStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueueMeshOfMember := handler.EnqueueRequestsFromMapFunc(r.meshRequestsForMember)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("meshMembersStatus").
+		// Mesh status updates by this reconciler shouldn't trigger another aggregation.
+		For(&appmesh.Mesh{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &appmesh.VirtualNode{}}, enqueueMeshOfMember).
+		Watches(&source.Kind{Type: &appmesh.VirtualRouter{}}, enqueueMeshOfMember).
+		Watches(&source.Kind{Type: &appmesh.VirtualService{}}, enqueueMeshOfMember).
+		Watches(&source.Kind{Type: &appmesh.VirtualGateway{}}, enqueueMeshOfMember).
+		Complete(sharding.NewMeshReconciler(r.sharder, tracing.NewReconciler("meshMembersStatus", r)))
+}
+
+func (r *meshMembersStatusReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	ms := &appmesh.Mesh{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, ms); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ms.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	members, err := r.membersStatusAggregator.Aggregate(ctx, ms)
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(ms.Status.Members, members) {
+		oldMS := ms.DeepCopy()
+		ms.Status.Members = members
+		if err := r.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS)); err != nil {
+			return err
+		}
+	}
+	return runtime.NewRequeueAfterError(errors.New("re-aggregate mesh members status"), r.aggregateInterval)
+}
+
+// meshRequestsForMember maps a VirtualNode, VirtualRouter, VirtualService or VirtualGateway to the mesh it belongs to.
+func (r *meshMembersStatusReconciler) meshRequestsForMember(obj client.Object) []reconcile.Request {
+	var msRef *appmesh.MeshReference
+	switch member := obj.(type) {
+	case *appmesh.VirtualNode:
+		msRef = member.Spec.MeshRef
+	case *appmesh.VirtualRouter:
+		msRef = member.Spec.MeshRef
+	case *appmesh.VirtualService:
+		msRef = member.Spec.MeshRef
+	case *appmesh.VirtualGateway:
+		msRef = member.Spec.MeshRef
+	}
+	if msRef == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: msRef.Name}}}
+}
@@ -0,0 +1,116 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	meshTLSAuditInterval = 5 * time.Minute
+)
+
+// NewMeshTLSAuditReconciler constructs new meshTLSAuditReconciler
+func NewMeshTLSAuditReconciler(
+	k8sClient client.Client,
+	tlsAuditor mesh.TLSAuditor,
+	sharder sharding.Sharder,
+	log logr.Logger) *meshTLSAuditReconciler {
+	return &meshTLSAuditReconciler{
+		k8sClient:     k8sClient,
+		tlsAuditor:    tlsAuditor,
+		sharder:       sharder,
+		log:           log,
+		auditInterval: meshTLSAuditInterval,
+	}
+}
+
+// meshTLSAuditReconciler periodically audits client policy TLS of meshes in AUDIT tlsEnforcementMode.
+type meshTLSAuditReconciler struct {
+	k8sClient  client.Client
+	tlsAuditor mesh.TLSAuditor
+	sharder    sharding.Sharder
+	log        logr.Logger
+
+	auditInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshes/status,verbs=get;update;patch
+
+func (r *meshTLSAuditReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *meshTLSAuditReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("meshTLSAudit").
+		// audit updates by this reconciler shouldn't trigger another audit, meshes are re-audited periodically instead.
+		For(&appmesh.Mesh{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(sharding.NewMeshReconciler(r.sharder, tracing.NewReconciler("meshTLSAudit", r)))
+}
+
+func (r *meshTLSAuditReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	ms := &appmesh.Mesh{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, ms); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ms.DeletionTimestamp.IsZero() || !mesh.IsTLSEnforcementAudit(ms) {
+		r.tlsAuditor.Forget(ms)
+		if ms.Status.TLSAudit == nil || !ms.DeletionTimestamp.IsZero() {
+			return nil
+		}
+		return r.updateMeshTLSAudit(ctx, ms, nil)
+	}
+
+	audit, err := r.tlsAuditor.Audit(ctx, ms)
+	if err != nil {
+		return err
+	}
+	if !isTLSAuditViolationsEqual(ms.Status.TLSAudit, audit) {
+		if err := r.updateMeshTLSAudit(ctx, ms, audit); err != nil {
+			return err
+		}
+	}
+	return runtime.NewRequeueAfterError(errors.New("re-audit mesh TLS"), r.auditInterval)
+}
+
+func (r *meshTLSAuditReconciler) updateMeshTLSAudit(ctx context.Context, ms *appmesh.Mesh, audit *appmesh.MeshTLSAudit) error {
+	oldMS := ms.DeepCopy()
+	ms.Status.TLSAudit = audit
+	return r.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS))
+}
+
+// isTLSAuditViolationsEqual checks whether audits found the same violations, regardless of when they're performed.
+func isTLSAuditViolationsEqual(audit *appmesh.MeshTLSAudit, otherAudit *appmesh.MeshTLSAudit) bool {
+	if audit == nil || otherAudit == nil {
+		return audit == otherAudit
+	}
+	return audit.ViolationCount == otherAudit.ViolationCount &&
+		cmp.Equal(audit.Violations, otherAudit.Violations, cmpopts.EquateEmpty())
+}
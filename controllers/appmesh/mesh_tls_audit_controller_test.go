@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	mock_mesh "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	pkgruntime "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_meshTLSAuditReconciler_reconcile(t *testing.T) {
+	lastAuditTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	auditTime := metav1.NewTime(time.Now().Truncate(time.Second))
+	violation := appmesh.MeshTLSAuditViolation{
+		Client:  "ns-1/frontend",
+		Backend: "ns-1/backend",
+		Port:    8080,
+		Reason:  "backend listener doesn't accept TLS on port 8080",
+	}
+	otherViolation := appmesh.MeshTLSAuditViolation{
+		Client:  "ns-1/frontend",
+		Backend: "ns-1/backend-v2",
+		Port:    8080,
+		Reason:  "backend listener doesn't accept TLS on port 8080",
+	}
+	tests := []struct {
+		name         string
+		oldTLSAudit  *appmesh.MeshTLSAudit
+		audit        *appmesh.MeshTLSAudit
+		wantTLSAudit *appmesh.MeshTLSAudit
+		wantPatched  bool
+	}{
+		{
+			name:         "first audit is recorded",
+			audit:        &appmesh.MeshTLSAudit{LastAuditTime: &auditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			wantTLSAudit: &appmesh.MeshTLSAudit{LastAuditTime: &auditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			wantPatched:  true,
+		},
+		{
+			name:         "audit with the same violations isn't recorded",
+			oldTLSAudit:  &appmesh.MeshTLSAudit{LastAuditTime: &lastAuditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			audit:        &appmesh.MeshTLSAudit{LastAuditTime: &auditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			wantTLSAudit: &appmesh.MeshTLSAudit{LastAuditTime: &lastAuditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			wantPatched:  false,
+		},
+		{
+			name:         "audit with different violations is recorded",
+			oldTLSAudit:  &appmesh.MeshTLSAudit{LastAuditTime: &lastAuditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{violation}},
+			audit:        &appmesh.MeshTLSAudit{LastAuditTime: &auditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{otherViolation}},
+			wantTLSAudit: &appmesh.MeshTLSAudit{LastAuditTime: &auditTime, ViolationCount: 1, Violations: []appmesh.MeshTLSAuditViolation{otherViolation}},
+			wantPatched:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			auditMode := appmesh.TLSEnforcementModeAudit
+			ms := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{TLSEnforcementMode: &auditMode},
+				Status:     appmesh.MeshStatus{TLSAudit: tt.oldTLSAudit},
+			}
+			assert.NoError(t, k8sClient.Create(ctx, ms.DeepCopy()))
+			oldMS := &appmesh.Mesh{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(ms), oldMS))
+
+			tlsAuditor := mock_mesh.NewMockTLSAuditor(ctrl)
+			tlsAuditor.EXPECT().Audit(gomock.Any(), gomock.Any()).Return(tt.audit, nil)
+			r := NewMeshTLSAuditReconciler(k8sClient, tlsAuditor, nil, logr.New(&log.NullLogSink{}))
+
+			err := r.reconcile(ctx, reconcile.Request{NamespacedName: k8s.NamespacedName(ms)})
+			var requeueAfterErr *pkgruntime.RequeueAfterError
+			assert.True(t, errors.As(err, &requeueAfterErr))
+			assert.Equal(t, meshTLSAuditInterval, requeueAfterErr.Duration())
+			gotMS := &appmesh.Mesh{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(ms), gotMS))
+			assert.True(t, tt.wantTLSAudit.LastAuditTime.Equal(gotMS.Status.TLSAudit.LastAuditTime))
+			assert.Equal(t, tt.wantTLSAudit.Violations, gotMS.Status.TLSAudit.Violations)
+			assert.Equal(t, tt.wantPatched, gotMS.ResourceVersion != oldMS.ResourceVersion)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/smi"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewTrafficSplitReconciler constructs new trafficSplitReconciler
+func NewTrafficSplitReconciler(
+	k8sClient client.Client,
+	synchronizer smi.Synchronizer,
+	trafficSplitGVK schema.GroupVersionKind,
+	controllerOptions controller.Options,
+	sharder sharding.Sharder,
+	log logr.Logger,
+	recorder record.EventRecorder) *trafficSplitReconciler {
+	return &trafficSplitReconciler{
+		k8sClient:                              k8sClient,
+		synchronizer:                           synchronizer,
+		trafficSplitGVK:                        trafficSplitGVK,
+		enqueueRequestsForVirtualRouterEvents:  smi.NewEnqueueRequestsForVirtualRouterEvents(k8sClient, trafficSplitGVK, log),
+		enqueueRequestsForVirtualServiceEvents: smi.NewEnqueueRequestsForVirtualServiceEvents(k8sClient, trafficSplitGVK, log),
+		controllerOptions:                      controllerOptions,
+		sharder:                                sharder,
+		log:                                    log,
+		recorder:                               recorder,
+	}
+}
+
+// trafficSplitReconciler synchronizes SMI TrafficSplit objects with VirtualRouter routes.
+type trafficSplitReconciler struct {
+	k8sClient       client.Client
+	synchronizer    smi.Synchronizer
+	trafficSplitGVK schema.GroupVersionKind
+
+	enqueueRequestsForVirtualRouterEvents  handler.EventHandler
+	enqueueRequestsForVirtualServiceEvents handler.EventHandler
+	controllerOptions                      controller.Options
+	sharder                                sharding.Sharder
+	log                                    logr.Logger
+	recorder                               record.EventRecorder
+}
+
+func (r *trafficSplitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *trafficSplitReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("trafficSplit").
+		For(r.newTrafficSplit()).
+		Watches(&source.Kind{Type: &appmesh.VirtualRouter{}}, r.enqueueRequestsForVirtualRouterEvents).
+		Watches(&source.Kind{Type: &appmesh.VirtualService{}}, r.enqueueRequestsForVirtualServiceEvents).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("trafficSplit", r)))
+}
+
+func (r *trafficSplitReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	ts := r.newTrafficSplit()
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, ts); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ts.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	if err := r.synchronizer.Sync(ctx, ts); err != nil {
+		r.recorder.Event(ts, corev1.EventTypeWarning, "SyncError", err.Error())
+		// invalid trafficSplits are retried upon changes of them or their mesh resources, rather than with backoff.
+		if errors.Is(err, smi.ErrInvalidTrafficSplit) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *trafficSplitReconciler) newTrafficSplit() *unstructured.Unstructured {
+	ts := &unstructured.Unstructured{}
+	ts.SetGroupVersionKind(r.trafficSplitGVK)
+	return ts
+}
@@ -18,12 +18,18 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/podmonitor"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,22 +38,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 )
 
 // NewVirtualGatewayReconciler constructs new virtualGatewayReconciler
 func NewVirtualGatewayReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
 	vgMembersFinalizer virtualgateway.MembersFinalizer,
 	vgResManager virtualgateway.ResourceManager,
+	vgLBManager virtualgateway.LoadBalancerManager,
+	podMonitorManager podmonitor.Manager,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder) *virtualGatewayReconciler {
 	return &virtualGatewayReconciler{
 		k8sClient:                    k8sClient,
+		namespaceRoleResolver:        namespaceRoleResolver,
 		finalizerManager:             finalizerManager,
+		stuckDeletionHandler:         stuckDeletionHandler,
 		vgMembersFinalizer:           vgMembersFinalizer,
 		vgResManager:                 vgResManager,
+		vgLBManager:                  vgLBManager,
+		podMonitorManager:            podMonitorManager,
 		enqueueRequestsForMeshEvents: virtualgateway.NewEnqueueRequestsForMeshEvents(k8sClient, log),
+		externalChangesSource:        externalChangesSource,
+		controllerOptions:            controllerOptions,
+		reconcileTimeout:             reconcileTimeout,
+		sharder:                      sharder,
 		log:                          log,
 		recorder:                     recorder,
 	}
@@ -55,12 +78,21 @@ func NewVirtualGatewayReconciler(
 
 // virtualGatewayReconciler reconciles a VirtualGateway object
 type virtualGatewayReconciler struct {
-	k8sClient          client.Client
-	finalizerManager   k8s.FinalizerManager
-	vgMembersFinalizer virtualgateway.MembersFinalizer
-	vgResManager       virtualgateway.ResourceManager
+	k8sClient             client.Client
+	namespaceRoleResolver aws.NamespaceRoleResolver
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	vgMembersFinalizer    virtualgateway.MembersFinalizer
+	vgResManager          virtualgateway.ResourceManager
+	vgLBManager           virtualgateway.LoadBalancerManager
+	// podMonitorManager creates PodMonitors scraping Envoy stats of VirtualGateway pods
+	podMonitorManager podmonitor.Manager
 
 	enqueueRequestsForMeshEvents handler.EventHandler
+	externalChangesSource        source.Source
+	controllerOptions            controller.Options
+	reconcileTimeout             time.Duration
+	sharder                      sharding.Sharder
 	log                          logr.Logger
 	recorder                     record.EventRecorder
 }
@@ -70,15 +102,21 @@ type virtualGatewayReconciler struct {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *virtualGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
 func (r *virtualGatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appmesh.VirtualGateway{}).
+		Owns(&corev1.Service{}).
 		Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-		Complete(r)
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualGateway", audit.NewReconciler("VirtualGateway", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *virtualGatewayReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -86,6 +124,9 @@ func (r *virtualGatewayReconciler) reconcile(ctx context.Context, req ctrl.Reque
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, vg); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, vg, &vg.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !vg.DeletionTimestamp.IsZero() {
 		return r.cleanupVirtualGateway(ctx, vg)
 	}
@@ -103,6 +144,16 @@ func (r *virtualGatewayReconciler) reconcileVirtualGateway(ctx context.Context,
 	if err := r.vgResManager.Reconcile(ctx, vg); err != nil {
 		return err
 	}
+	if err := r.vgLBManager.Reconcile(ctx, vg); err != nil {
+		return err
+	}
+	var podSelectors []*metav1.LabelSelector
+	if vg.Spec.PodSelector != nil {
+		podSelectors = append(podSelectors, vg.Spec.PodSelector)
+	}
+	if err := r.podMonitorManager.Reconcile(ctx, vg, podSelectors); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -118,7 +169,9 @@ func (r *virtualGatewayReconciler) cleanupVirtualGateway(ctx context.Context, vg
 
 	if k8s.HasFinalizer(vg, k8s.FinalizerAWSAppMeshResources) {
 		if err := r.vgResManager.Cleanup(ctx, vg); err != nil {
-			return err
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vg, &vg.Status.Conditions, err); err != nil {
+				return err
+			}
 		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, vg, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
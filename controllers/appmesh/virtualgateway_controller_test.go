@@ -5,11 +5,13 @@ import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	mock_virtualgateway "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/podmonitor"
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
@@ -93,3 +95,74 @@ func Test_virtualGatewayReconciler_reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_virtualGatewayReconciler_reconcileVirtualGateway_podMonitor(t *testing.T) {
+	tests := []struct {
+		name        string
+		podSelector *metav1.LabelSelector
+		// wantMatchLabels are the matchLabels of PodMonitors by name.
+		wantMatchLabels map[string]map[string]interface{}
+	}{
+		{
+			name: "virtualGateway with podSelector",
+			podSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "my-gateway"},
+			},
+			wantMatchLabels: map[string]map[string]interface{}{
+				"vg-1": {"app": "my-gateway"},
+			},
+		},
+		{
+			name:            "virtualGateway without podSelector",
+			wantMatchLabels: map[string]map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			vgResManager := mock_virtualgateway.NewMockResourceManager(ctrl)
+			vgLBManager := mock_virtualgateway.NewMockLoadBalancerManager(ctrl)
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+
+			vg := &appmesh.VirtualGateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "my-ns",
+					Name:      "vg-1",
+				},
+				Spec: appmesh.VirtualGatewaySpec{
+					PodSelector: tt.podSelector,
+				},
+			}
+			assert.NoError(t, k8sClient.Create(ctx, vg))
+
+			r := &virtualGatewayReconciler{
+				k8sClient:         k8sClient,
+				finalizerManager:  k8s.NewDefaultFinalizerManager(k8sClient, logr.New(&log.NullLogSink{})),
+				vgResManager:      vgResManager,
+				vgLBManager:       vgLBManager,
+				podMonitorManager: podmonitor.NewDefaultManager(k8sClient, k8sSchema, true, logr.New(&log.NullLogSink{})),
+				log:               logr.New(&log.NullLogSink{}),
+			}
+			vgResManager.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Return(nil)
+			vgLBManager.EXPECT().Reconcile(gomock.Any(), gomock.Any()).Return(nil)
+			assert.NoError(t, r.reconcileVirtualGateway(ctx, vg))
+
+			podMonitorList := &unstructured.UnstructuredList{}
+			podMonitorList.SetGroupVersionKind(podmonitor.PodMonitorGVK.GroupVersion().WithKind("PodMonitorList"))
+			assert.NoError(t, k8sClient.List(ctx, podMonitorList))
+			gotMatchLabels := make(map[string]map[string]interface{})
+			for _, got := range podMonitorList.Items {
+				matchLabels, _, _ := unstructured.NestedMap(got.Object, "spec", "selector", "matchLabels")
+				gotMatchLabels[got.GetName()] = matchLabels
+				assert.Len(t, got.GetOwnerReferences(), 1)
+				assert.Equal(t, "VirtualGateway", got.GetOwnerReferences()[0].Kind)
+			}
+			assert.Equal(t, tt.wantMatchLabels, gotMatchLabels)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conflicts"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	reasonConflictResolved = "ConflictResolved"
+)
+
+// NewVirtualNodeConflictReconciler constructs new virtualNodeConflictReconciler
+func NewVirtualNodeConflictReconciler(
+	k8sClient client.Client,
+	detector conflicts.Detector,
+	sharder sharding.Sharder,
+	log logr.Logger,
+	recorder record.EventRecorder,
+	watchPods bool) *virtualNodeConflictReconciler {
+	return &virtualNodeConflictReconciler{
+		k8sClient: k8sClient,
+		detector:  detector,
+		sharder:   sharder,
+		log:       log,
+		recorder:  recorder,
+		watchPods: watchPods,
+	}
+}
+
+// virtualNodeConflictReconciler reports VirtualNodes selecting the same pods with a Conflicted condition.
+type virtualNodeConflictReconciler struct {
+	k8sClient client.Client
+	detector  conflicts.Detector
+	sharder   sharding.Sharder
+	log       logr.Logger
+	recorder  record.EventRecorder
+	// watchPods detects conflicts upon changes to pod labels, instead of upon virtualNode changes only.
+	watchPods bool
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *virtualNodeConflictReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *virtualNodeConflictReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("virtualNodeConflict").
+		For(&appmesh.VirtualNode{}).
+		Watches(&source.Kind{Type: &appmesh.VirtualNode{}}, conflicts.NewEnqueueRequestsForVirtualNodeEvents(r.k8sClient, r.log))
+	if r.watchPods {
+		b = b.Watches(&source.Kind{Type: &corev1.Pod{}}, conflicts.NewEnqueueRequestsForPodEvents(r.k8sClient, r.log),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	}
+	return b.Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualNodeConflict", r)))
+}
+
+func (r *virtualNodeConflictReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	vn := &appmesh.VirtualNode{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, vn); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !vn.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	vnConflicts, err := r.detector.DetectVirtualNodeConflicts(ctx, vn)
+	if err != nil {
+		return err
+	}
+
+	oldVN := vn.DeepCopy()
+	wasConflicted := meta.IsStatusConditionTrue(vn.Status.Conditions, appmesh.ConditionConflicted)
+	if !conflicts.SetConflictedCondition(&vn.Status.Conditions, vn.Generation, vnConflicts, "virtualNode", "pods") {
+		return nil
+	}
+	// other VirtualNode controllers patch conditions concurrently, don't overwrite their changes.
+	if err := r.k8sClient.Status().Patch(ctx, vn, client.MergeFromWithOptions(oldVN, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	if len(vnConflicts) != 0 {
+		r.recorder.Event(vn, corev1.EventTypeWarning, appmesh.ConditionConflicted, conflicts.ConflictsMessage(vnConflicts, "virtualNode", "pods"))
+	} else if wasConflicted {
+		r.recorder.Event(vn, corev1.EventTypeNormal, reasonConflictResolved, "no longer selects the same pods as other virtualNodes")
+	}
+	return nil
+}
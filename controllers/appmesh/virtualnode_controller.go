@@ -14,9 +14,17 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/deletion"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/podmonitor"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -28,42 +36,87 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 )
 
 // NewVirtualNodeReconciler constructs new virtualNodeReconciler
 func NewVirtualNodeReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
+	referencesIndexer references.ObjectReferenceIndexer,
 	vnResManager virtualnode.ResourceManager,
+	rolloutOrchestrator virtualnode.RolloutOrchestrator,
+	podMonitorManager podmonitor.Manager,
+	deletionOrchestrator deletion.Orchestrator,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder,
-	enableBackendGroups bool) *virtualNodeReconciler {
+	enableBackendGroups bool,
+	enableMeshPolicies bool,
+	watchPods bool) *virtualNodeReconciler {
 	return &virtualNodeReconciler{
 		k8sClient:                              k8sClient,
+		namespaceRoleResolver:                  namespaceRoleResolver,
 		finalizerManager:                       finalizerManager,
+		stuckDeletionHandler:                   stuckDeletionHandler,
+		referencesIndexer:                      referencesIndexer,
 		vnResManager:                           vnResManager,
+		rolloutOrchestrator:                    rolloutOrchestrator,
+		podMonitorManager:                      podMonitorManager,
+		deletionOrchestrator:                   deletionOrchestrator,
 		enqueueRequestsForMeshEvents:           virtualnode.NewEnqueueRequestsForMeshEvents(k8sClient, log),
 		enqueueRequestsForBackendGroupEvents:   virtualnode.NewEnqueueRequestsForBackendGroupEvents(k8sClient, log),
 		enqueueRequestsForVirtualServiceEvents: virtualnode.NewEnqueueRequestsForVirtualServiceEvents(k8sClient, log),
+		enqueueRequestsForPodEvents:            virtualnode.NewEnqueueRequestsForPodEvents(k8sClient, log),
+		enqueueRequestsForMeshPolicyEvents:     virtualnode.NewEnqueueRequestsForMeshPolicyEvents(k8sClient, log),
+		externalChangesSource:                  externalChangesSource,
+		controllerOptions:                      controllerOptions,
+		reconcileTimeout:                       reconcileTimeout,
+		sharder:                                sharder,
 		log:                                    log,
 		recorder:                               recorder,
 		enableBackendGroups:                    enableBackendGroups,
+		enableMeshPolicies:                     enableMeshPolicies,
+		watchPods:                              watchPods,
 	}
 }
 
 // virtualNodeReconciler reconciles a VirtualNode object
 type virtualNodeReconciler struct {
-	k8sClient        client.Client
-	finalizerManager k8s.FinalizerManager
-	vnResManager     virtualnode.ResourceManager
+	k8sClient             client.Client
+	namespaceRoleResolver aws.NamespaceRoleResolver
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	referencesIndexer     references.ObjectReferenceIndexer
+	vnResManager          virtualnode.ResourceManager
+	// rolloutOrchestrator restarts VirtualNode workloads when spec changes require Envoy restart
+	rolloutOrchestrator virtualnode.RolloutOrchestrator
+	// podMonitorManager creates PodMonitors scraping Envoy stats of VirtualNode pods
+	podMonitorManager podmonitor.Manager
+	// deletionOrchestrator defers deletion until virtualServices and virtualRouters being deleted no longer reference virtualNodes
+	deletionOrchestrator deletion.Orchestrator
 
 	enqueueRequestsForMeshEvents           handler.EventHandler
 	enqueueRequestsForBackendGroupEvents   handler.EventHandler
 	enqueueRequestsForVirtualServiceEvents handler.EventHandler
+	enqueueRequestsForPodEvents            handler.EventHandler
+	enqueueRequestsForMeshPolicyEvents     handler.EventHandler
+	externalChangesSource                  source.Source
+	controllerOptions                      controller.Options
+	reconcileTimeout                       time.Duration
+	sharder                                sharding.Sharder
 	log                                    logr.Logger
 	recorder                               record.EventRecorder
 
 	enableBackendGroups bool
+	enableMeshPolicies  bool
+	// watchPods reconciles virtualNodes deriving health checks from probes upon changes to their pods, instead of upon resync only.
+	watchPods bool
 }
 
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch;create;update;patch;delete
@@ -71,27 +124,40 @@ type virtualNodeReconciler struct {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=backendgroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=backendgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshpolicies,verbs=get;list;watch
 
 func (r *virtualNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
 func (r *virtualNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.referencesIndexer.Setup(&appmesh.VirtualNode{}, map[string]references.ObjectReferenceIndexFunc{
+		virtualnode.ReferenceKindVirtualService: virtualnode.VirtualServiceReferenceIndexFunc,
+	}); err != nil {
+		return err
+	}
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&appmesh.VirtualNode{}).
+		Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents)
 	if r.enableBackendGroups {
-		return ctrl.NewControllerManagedBy(mgr).
-			For(&appmesh.VirtualNode{}).
-			Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
+		builder = builder.
 			Watches(&source.Kind{Type: &appmesh.BackendGroup{}}, r.enqueueRequestsForBackendGroupEvents).
-			Watches(&source.Kind{Type: &appmesh.VirtualService{}}, r.enqueueRequestsForVirtualServiceEvents).
-			WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-			Complete(r)
-	} else {
-		return ctrl.NewControllerManagedBy(mgr).
-			For(&appmesh.VirtualNode{}).
-			Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
-			WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-			Complete(r)
+			Watches(&source.Kind{Type: &appmesh.VirtualService{}}, r.enqueueRequestsForVirtualServiceEvents)
+	}
+	if r.enableMeshPolicies {
+		builder = builder.Watches(&source.Kind{Type: &appmesh.MeshPolicy{}}, r.enqueueRequestsForMeshPolicyEvents)
 	}
+	if r.watchPods {
+		builder = builder.Watches(&source.Kind{Type: &corev1.Pod{}}, r.enqueueRequestsForPodEvents)
+	}
+	return builder.
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualNode", audit.NewReconciler("VirtualNode", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *virtualNodeReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -99,10 +165,17 @@ func (r *virtualNodeReconciler) reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, vn); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, vn, &vn.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !vn.DeletionTimestamp.IsZero() {
 		return r.cleanupVirtualNode(ctx, vn)
 	}
 	if err := r.reconcileVirtualNode(ctx, vn); err != nil {
+		if maintenance.IsChangesDeferredError(err) {
+			r.recorder.Event(vn, corev1.EventTypeNormal, "ChangesDeferred", err.Error())
+			return err
+		}
 		r.recorder.Event(vn, corev1.EventTypeWarning, "ReconcileError", err.Error())
 		return err
 	}
@@ -116,14 +189,25 @@ func (r *virtualNodeReconciler) reconcileVirtualNode(ctx context.Context, vn *ap
 	if err := r.vnResManager.Reconcile(ctx, vn); err != nil {
 		return err
 	}
+	if err := r.podMonitorManager.Reconcile(ctx, vn, virtualnode.PodSelectorTerms(vn)); err != nil {
+		return err
+	}
+	if err := r.rolloutOrchestrator.Rollout(ctx, vn); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (r *virtualNodeReconciler) cleanupVirtualNode(ctx context.Context, vn *appmesh.VirtualNode) error {
 	if k8s.HasFinalizer(vn, k8s.FinalizerAWSAppMeshResources) {
-		if err := r.vnResManager.Cleanup(ctx, vn); err != nil {
+		if err := r.deletionOrchestrator.WaitForVirtualNodeDependents(ctx, vn); err != nil {
 			return err
 		}
+		if err := r.vnResManager.Cleanup(ctx, vn); err != nil {
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vn, &vn.Status.Conditions, err); err != nil {
+				return err
+			}
+		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, vn, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
 		}
@@ -0,0 +1,95 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// NewVirtualNodeHealthReconciler constructs new virtualNodeHealthReconciler
+func NewVirtualNodeHealthReconciler(
+	k8sClient client.Client,
+	endpointsHealthReporter virtualnode.EndpointsHealthReporter,
+	reportInterval time.Duration,
+	sharder sharding.Sharder,
+	log logr.Logger) *virtualNodeHealthReconciler {
+	return &virtualNodeHealthReconciler{
+		k8sClient:               k8sClient,
+		endpointsHealthReporter: endpointsHealthReporter,
+		reportInterval:          reportInterval,
+		sharder:                 sharder,
+		log:                     log,
+	}
+}
+
+// virtualNodeHealthReconciler reports the health of pods selected by VirtualNodes with listener health checks
+// with an EndpointsHealthy condition, upon VirtualNode changes and periodically.
+type virtualNodeHealthReconciler struct {
+	k8sClient               client.Client
+	endpointsHealthReporter virtualnode.EndpointsHealthReporter
+	reportInterval          time.Duration
+	sharder                 sharding.Sharder
+	log                     logr.Logger
+}
+
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualnodes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+func (r *virtualNodeHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
+}
+
+func (r *virtualNodeHealthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("virtualNodeHealth").
+		// condition updates by this reconciler shouldn't trigger another report.
+		For(&appmesh.VirtualNode{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualNodeHealth", r)))
+}
+
+func (r *virtualNodeHealthReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
+	vn := &appmesh.VirtualNode{}
+	if err := r.k8sClient.Get(ctx, req.NamespacedName, vn); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !vn.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	oldVN := vn.DeepCopy()
+	updated, err := r.endpointsHealthReporter.Report(ctx, vn)
+	if err != nil {
+		return err
+	}
+	if updated {
+		// other VirtualNode controllers patch conditions concurrently, don't overwrite their changes.
+		if err := r.k8sClient.Status().Patch(ctx, vn, client.MergeFromWithOptions(oldVN, client.MergeFromWithOptimisticLock{})); err != nil {
+			return err
+		}
+	}
+	return runtime.NewRequeueAfterError(errors.New("re-report virtualNode endpoints health"), r.reportInterval)
+}
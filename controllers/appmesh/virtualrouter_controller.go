@@ -18,10 +18,16 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/deletion"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
@@ -34,46 +40,84 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 )
 
 // NewVirtualRouterReconciler constructs new virtualRouterReconciler
 func NewVirtualRouterReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
 	referencesIndexer references.ObjectReferenceIndexer,
 	vrResManager virtualrouter.ResourceManager,
+	vsManager virtualrouter.VirtualServiceManager,
+	deletionOrchestrator deletion.Orchestrator,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
-	recorder record.EventRecorder) *virtualRouterReconciler {
+	recorder record.EventRecorder,
+	enableMeshPolicies bool) *virtualRouterReconciler {
 	return &virtualRouterReconciler{
 		k8sClient:                           k8sClient,
+		namespaceRoleResolver:               namespaceRoleResolver,
 		finalizerManager:                    finalizerManager,
+		stuckDeletionHandler:                stuckDeletionHandler,
 		referencesIndexer:                   referencesIndexer,
 		vrResManager:                        vrResManager,
+		vsManager:                           vsManager,
+		deletionOrchestrator:                deletionOrchestrator,
 		enqueueRequestsForMeshEvents:        virtualrouter.NewEnqueueRequestsForMeshEvents(k8sClient, log),
-		enqueueRequestsForVirtualNodeEvents: virtualrouter.NewEnqueueRequestsForVirtualNodeEvents(referencesIndexer, log),
+		enqueueRequestsForVirtualNodeEvents: virtualrouter.NewEnqueueRequestsForVirtualNodeEvents(k8sClient, referencesIndexer, log),
+		enqueueRequestsForMeshPolicyEvents:  virtualrouter.NewEnqueueRequestsForMeshPolicyEvents(k8sClient, log),
+		externalChangesSource:               externalChangesSource,
+		controllerOptions:                   controllerOptions,
+		reconcileTimeout:                    reconcileTimeout,
+		sharder:                             sharder,
 		log:                                 log,
 		recorder:                            recorder,
+		enableMeshPolicies:                  enableMeshPolicies,
 	}
 }
 
 // virtualRouterReconciler reconciles a VirtualRouter object
 type virtualRouterReconciler struct {
-	k8sClient         client.Client
-	finalizerManager  k8s.FinalizerManager
-	referencesIndexer references.ObjectReferenceIndexer
-	vrResManager      virtualrouter.ResourceManager
+	k8sClient             client.Client
+	namespaceRoleResolver aws.NamespaceRoleResolver
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	referencesIndexer     references.ObjectReferenceIndexer
+	vrResManager          virtualrouter.ResourceManager
+	// vsManager manages virtualServices auto-created for virtualRouters
+	vsManager virtualrouter.VirtualServiceManager
+	// deletionOrchestrator defers deletion until virtualServices being deleted no longer reference virtualRouters
+	deletionOrchestrator deletion.Orchestrator
 
 	enqueueRequestsForMeshEvents        handler.EventHandler
 	enqueueRequestsForVirtualNodeEvents handler.EventHandler
+	enqueueRequestsForMeshPolicyEvents  handler.EventHandler
+	externalChangesSource               source.Source
+	controllerOptions                   controller.Options
+	reconcileTimeout                    time.Duration
+	sharder                             sharding.Sharder
 	log                                 logr.Logger
 	recorder                            record.EventRecorder
+
+	enableMeshPolicies bool
 }
 
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualrouters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=virtualrouters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=appmesh.k8s.aws,resources=meshpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *virtualRouterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
@@ -83,12 +127,18 @@ func (r *virtualRouterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}); err != nil {
 		return err
 	}
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&appmesh.VirtualRouter{}).
 		Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
 		Watches(&source.Kind{Type: &appmesh.VirtualNode{}}, r.enqueueRequestsForVirtualNodeEvents).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-		Complete(r)
+		Owns(&appmesh.VirtualService{})
+	if r.enableMeshPolicies {
+		builder = builder.Watches(&source.Kind{Type: &appmesh.MeshPolicy{}}, r.enqueueRequestsForMeshPolicyEvents)
+	}
+	return builder.
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualRouter", audit.NewReconciler("VirtualRouter", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *virtualRouterReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -96,10 +146,21 @@ func (r *virtualRouterReconciler) reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, vr); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, vr, &vr.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !vr.DeletionTimestamp.IsZero() {
 		return r.cleanupVirtualRouter(ctx, vr)
 	}
 	if err := r.reconcileVirtualRouter(ctx, vr); err != nil {
+		if runtime.IsDependencyNotReadyError(err) {
+			r.recorder.Event(vr, corev1.EventTypeNormal, "DependencyNotReady", err.Error())
+			return err
+		}
+		if maintenance.IsChangesDeferredError(err) {
+			r.recorder.Event(vr, corev1.EventTypeNormal, "ChangesDeferred", err.Error())
+			return err
+		}
 		r.recorder.Event(vr, corev1.EventTypeWarning, "ReconcileError", err.Error())
 		return err
 	}
@@ -113,14 +174,22 @@ func (r *virtualRouterReconciler) reconcileVirtualRouter(ctx context.Context, vr
 	if err := r.vrResManager.Reconcile(ctx, vr); err != nil {
 		return err
 	}
+	if err := r.vsManager.Reconcile(ctx, vr); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (r *virtualRouterReconciler) cleanupVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter) error {
 	if k8s.HasFinalizer(vr, k8s.FinalizerAWSAppMeshResources) {
-		if err := r.vrResManager.Cleanup(ctx, vr); err != nil {
+		if err := r.deletionOrchestrator.WaitForVirtualRouterDependents(ctx, vr); err != nil {
 			return err
 		}
+		if err := r.vrResManager.Cleanup(ctx, vr); err != nil {
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vr, &vr.Status.Conditions, err); err != nil {
+				return err
+			}
+		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, vr, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
 		}
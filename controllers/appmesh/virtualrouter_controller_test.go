@@ -51,6 +51,18 @@ func Test_virtualRouterReconciler_reconcile(t *testing.T) {
 			want:    "",
 			wantErr: errors.New("Test Exception"),
 		},
+		{
+			name: "virtualRouter with paused reconciliation",
+			args: args{
+				vr: &appmesh.VirtualRouter{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "vr-1",
+						Annotations: map[string]string{k8s.ReconcileAnnotation: k8s.ReconcilePaused},
+					},
+					Status: appmesh.VirtualRouterStatus{},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
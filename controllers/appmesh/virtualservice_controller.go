@@ -18,10 +18,14 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -33,24 +37,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 )
 
 // NewVirtualServiceReconciler constructs new virtualServiceReconciler
 func NewVirtualServiceReconciler(
 	k8sClient client.Client,
+	namespaceRoleResolver aws.NamespaceRoleResolver,
 	finalizerManager k8s.FinalizerManager,
+	stuckDeletionHandler k8s.StuckDeletionHandler,
 	referencesIndexer references.ObjectReferenceIndexer,
 	vsResManager virtualservice.ResourceManager,
+	dnsManager virtualservice.DNSManager,
+	externalChangesSource source.Source,
+	controllerOptions controller.Options,
+	reconcileTimeout time.Duration,
+	sharder sharding.Sharder,
 	log logr.Logger,
 	recorder record.EventRecorder) *virtualServiceReconciler {
 	return &virtualServiceReconciler{
 		k8sClient:                             k8sClient,
+		namespaceRoleResolver:                 namespaceRoleResolver,
 		finalizerManager:                      finalizerManager,
+		stuckDeletionHandler:                  stuckDeletionHandler,
 		referencesIndexer:                     referencesIndexer,
 		vsResManager:                          vsResManager,
+		dnsManager:                            dnsManager,
 		enqueueRequestsForMeshEvents:          virtualservice.NewEnqueueRequestsForMeshEvents(k8sClient, log),
 		enqueueRequestsForVirtualNodeEvents:   virtualservice.NewEnqueueRequestsForVirtualNodeEvents(referencesIndexer, log),
 		enqueueRequestsForVirtualRouterEvents: virtualservice.NewEnqueueRequestsForVirtualRouterEvents(referencesIndexer, log),
+		externalChangesSource:                 externalChangesSource,
+		controllerOptions:                     controllerOptions,
+		reconcileTimeout:                      reconcileTimeout,
+		sharder:                               sharder,
 		log:                                   log,
 		recorder:                              recorder,
 	}
@@ -58,14 +77,21 @@ func NewVirtualServiceReconciler(
 
 // virtualServiceReconciler reconciles a VirtualService object
 type virtualServiceReconciler struct {
-	k8sClient         client.Client
-	finalizerManager  k8s.FinalizerManager
-	referencesIndexer references.ObjectReferenceIndexer
-	vsResManager      virtualservice.ResourceManager
+	k8sClient             client.Client
+	namespaceRoleResolver aws.NamespaceRoleResolver
+	finalizerManager      k8s.FinalizerManager
+	stuckDeletionHandler  k8s.StuckDeletionHandler
+	referencesIndexer     references.ObjectReferenceIndexer
+	vsResManager          virtualservice.ResourceManager
+	dnsManager            virtualservice.DNSManager
 
 	enqueueRequestsForMeshEvents          handler.EventHandler
 	enqueueRequestsForVirtualNodeEvents   handler.EventHandler
 	enqueueRequestsForVirtualRouterEvents handler.EventHandler
+	externalChangesSource                 source.Source
+	controllerOptions                     controller.Options
+	reconcileTimeout                      time.Duration
+	sharder                               sharding.Sharder
 	log                                   logr.Logger
 	recorder                              record.EventRecorder
 }
@@ -75,6 +101,10 @@ type virtualServiceReconciler struct {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *virtualServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, err := r.namespaceRoleResolver.WithNamespaceRole(ctx, req.Namespace)
+	if err != nil {
+		return runtime.HandleReconcileError(err, r.log)
+	}
 	return runtime.HandleReconcileError(r.reconcile(ctx, req), r.log)
 }
 
@@ -90,8 +120,9 @@ func (r *virtualServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &appmesh.Mesh{}}, r.enqueueRequestsForMeshEvents).
 		Watches(&source.Kind{Type: &appmesh.VirtualNode{}}, r.enqueueRequestsForVirtualNodeEvents).
 		Watches(&source.Kind{Type: &appmesh.VirtualRouter{}}, r.enqueueRequestsForVirtualRouterEvents).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
-		Complete(r)
+		Watches(r.externalChangesSource, &handler.EnqueueRequestForObject{}).
+		WithOptions(r.controllerOptions).
+		Complete(sharding.NewReconciler(r.sharder, tracing.NewReconciler("virtualService", audit.NewReconciler("VirtualService", runtime.NewTimeoutReconciler(r.reconcileTimeout, r)))))
 }
 
 func (r *virtualServiceReconciler) reconcile(ctx context.Context, req ctrl.Request) error {
@@ -99,10 +130,17 @@ func (r *virtualServiceReconciler) reconcile(ctx context.Context, req ctrl.Reque
 	if err := r.k8sClient.Get(ctx, req.NamespacedName, vs); err != nil {
 		return client.IgnoreNotFound(err)
 	}
+	if paused, err := k8s.UpdateReconciliationPausedCondition(ctx, r.k8sClient, vs, &vs.Status.Conditions); err != nil || paused {
+		return err
+	}
 	if !vs.DeletionTimestamp.IsZero() {
 		return r.cleanupVirtualService(ctx, vs)
 	}
 	if err := r.reconcileVirtualService(ctx, vs); err != nil {
+		if runtime.IsDependencyNotReadyError(err) {
+			r.recorder.Event(vs, corev1.EventTypeNormal, "DependencyNotReady", err.Error())
+			return err
+		}
 		r.recorder.Event(vs, corev1.EventTypeWarning, "ReconcileError", err.Error())
 		return err
 	}
@@ -116,13 +154,23 @@ func (r *virtualServiceReconciler) reconcileVirtualService(ctx context.Context,
 	if err := r.vsResManager.Reconcile(ctx, vs); err != nil {
 		return err
 	}
+	if err := r.dnsManager.Reconcile(ctx, vs); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (r *virtualServiceReconciler) cleanupVirtualService(ctx context.Context, vs *appmesh.VirtualService) error {
 	if k8s.HasFinalizer(vs, k8s.FinalizerAWSAppMeshResources) {
 		if err := r.vsResManager.Cleanup(ctx, vs); err != nil {
-			return err
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vs, &vs.Status.Conditions, err); err != nil {
+				return err
+			}
+		}
+		if err := r.dnsManager.Cleanup(ctx, vs); err != nil {
+			if err := r.stuckDeletionHandler.HandleCleanupError(ctx, vs, &vs.Status.Conditions, err); err != nil {
+				return err
+			}
 		}
 		if err := r.finalizerManager.RemoveFinalizers(ctx, vs, k8s.FinalizerAWSAppMeshResources); err != nil {
 			return err
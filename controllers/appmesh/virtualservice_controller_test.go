@@ -21,7 +21,8 @@ import (
 
 func Test_virtualServiceReconciler_reconcile(t *testing.T) {
 	type fields struct {
-		Reconcile func(ctx context.Context, vs *appmesh.VirtualService) error
+		Reconcile    func(ctx context.Context, vs *appmesh.VirtualService) error
+		DNSReconcile func(ctx context.Context, vs *appmesh.VirtualService) error
 	}
 	type args struct {
 		vs *appmesh.VirtualService
@@ -51,6 +52,27 @@ func Test_virtualServiceReconciler_reconcile(t *testing.T) {
 			want:    "",
 			wantErr: errors.New("Test Exception"),
 		},
+		{
+			name: "virtualService with dns reconcile error",
+			fields: fields{
+				Reconcile: func(ctx context.Context, ref *appmesh.VirtualService) error {
+					return nil
+				},
+				DNSReconcile: func(ctx context.Context, ref *appmesh.VirtualService) error {
+					return errors.New("failed to create placeholder service")
+				},
+			},
+			args: args{
+				vs: &appmesh.VirtualService{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "vs-1",
+					},
+					Status: appmesh.VirtualServiceStatus{},
+				},
+			},
+			want:    "",
+			wantErr: errors.New("failed to create placeholder service"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,6 +80,7 @@ func Test_virtualServiceReconciler_reconcile(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			vsResManager := mock_virtualservice.NewMockResourceManager(ctrl)
+			dnsManager := mock_virtualservice.NewMockDNSManager(ctrl)
 			k8sSchema := runtime.NewScheme()
 			clientgoscheme.AddToScheme(k8sSchema)
 			appmesh.AddToScheme(k8sSchema)
@@ -74,6 +97,7 @@ func Test_virtualServiceReconciler_reconcile(t *testing.T) {
 				k8sClient:        k8sClient,
 				finalizerManager: finalizerManager,
 				vsResManager:     vsResManager,
+				dnsManager:       dnsManager,
 				log:              logr.New(&log.NullLogSink{}),
 				recorder:         recorder,
 			}
@@ -81,6 +105,9 @@ func Test_virtualServiceReconciler_reconcile(t *testing.T) {
 			if tt.fields.Reconcile != nil {
 				vsResManager.EXPECT().Reconcile(gomock.Any(), gomock.Any()).DoAndReturn(tt.fields.Reconcile)
 			}
+			if tt.fields.DNSReconcile != nil {
+				dnsManager.EXPECT().Reconcile(gomock.Any(), gomock.Any()).DoAndReturn(tt.fields.DNSReconcile)
+			}
 
 			err = r.reconcile(ctx, reconcile.Request{
 				NamespacedName: k8s.NamespacedName(tt.args.vs),
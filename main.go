@@ -20,24 +20,51 @@ import (
 	"context"
 	"crypto/tls"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/timeout"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/backup"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/cloudmap"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/componentconfig"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/deletion"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/envoy"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/envoyadmin"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/externalchanges"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/features"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/logging"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
+	appmeshmetrics "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/metrics"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/podmonitor"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	appmeshruntime "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/smi"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/topology"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/version"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	sdkgoaws "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conflicts"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/coverage"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/arnindex"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
-	zapraw "go.uber.org/zap"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -49,15 +76,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/gatewayroute"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/inject"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/lint"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	appmeshv1 "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1"
 	appmeshv1beta2 "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	appmeshcontroller "github.com/aws/aws-app-mesh-controller-for-k8s/controllers/appmesh"
 	appmeshwebhook "github.com/aws/aws-app-mesh-controller-for-k8s/webhooks/appmesh"
@@ -68,6 +98,8 @@ import (
 const (
 	flagHealthProbePort    = "health-probe-port"
 	defaultHealthProbePort = 61779
+	// how long to wait for pending spans to be exported upon shutdown.
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 var (
@@ -82,29 +114,65 @@ type tlsConfig struct {
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 
 	_ = appmeshv1beta2.AddToScheme(scheme)
+	_ = appmeshv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == lint.CommandName {
+		os.Exit(lint.Run(os.Args[2:], os.Stdout, os.Stderr))
+	}
 	var syncPeriod time.Duration
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionLeaseDuration time.Duration
+	var leaderElectionRenewDeadline time.Duration
+	var leaderElectionRetryPeriod time.Duration
 	var enableCustomHealthCheck bool
 	var logLevel string
 	var listPageLimit int64
 	var healthProbePort int
 	var ipFamily string
-	awsCloudConfig := aws.CloudConfig{ThrottleConfig: throttle.NewDefaultServiceOperationsThrottleConfig()}
+	var finalizerTimeout time.Duration
+	awsCloudConfig := aws.CloudConfig{ThrottleConfig: throttle.NewDefaultServiceOperationsThrottleConfig(), TimeoutConfig: &timeout.ServiceOperationsTimeoutConfig{}}
 	injectConfig := inject.Config{}
 	cloudMapConfig := cloudmap.Config{}
+	virtualNodeConfig := virtualnode.Config{}
+	virtualRouterConfig := virtualrouter.Config{}
+	externalChangesConfig := externalchanges.Config{}
+	conversionConfig := webhook.ConversionConfig{}
+	webhookCertConfig := webhook.CertConfig{}
+	awsNameConfig := awsname.Config{}
+	taggingConfig := tagging.Config{}
+	quotaConfig := quota.Config{}
+	tracingConfig := tracing.Config{}
+	featureGates := features.NewFeatureGates()
+	controllerConfig := appmeshruntime.ControllerConfig{}
+	componentConfig := componentconfig.Config{}
+	shardingConfig := sharding.Config{}
+	virtualServiceDNSConfig := virtualservice.DNSConfig{}
+	smiConfig := smi.Config{}
+	topologyConfig := topology.Config{}
+	backupConfig := backup.Config{}
+	envoyAdminConfig := envoyadmin.Config{}
+	cacheConfig := k8s.CacheConfig{}
+	coverageConfig := coverage.Config{}
+	loggingConfig := logging.Config{}
 	fs := pflag.NewFlagSet("", pflag.ExitOnError)
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Hour, "SyncPeriod determines the minimum frequency at which watched resources are reconciled.")
 	fs.StringVar(&metricsAddr, "metrics-addr", "0.0.0.0:8080", "The address the metric endpoint binds to.")
 	fs.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller. "+
 			"Enabling this will ensure there is only one active controller.")
+	fs.DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"How long non-leader replicas wait before taking over leadership from a leader that stopped renewing it. Lower values fail over faster")
+	fs.DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"How long the leader retries renewing leadership before giving it up, must be less than leader-election-lease-duration")
+	fs.DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second,
+		"How often replicas try to acquire or renew leadership")
 	fs.BoolVar(&enableCustomHealthCheck, "enable-custom-health-check", false,
 		"Enable custom healthCheck when using cloudMap serviceDiscovery")
 	fs.StringVar(&logLevel, "log-level", "info", "Set the controller log level - info(default), debug")
@@ -112,30 +180,146 @@ func main() {
 		"The page size limiting the number of response for list operation to API Server")
 	fs.IntVar(&healthProbePort, flagHealthProbePort, defaultHealthProbePort,
 		"The port the health probes binds to.")
+	fs.DurationVar(&finalizerTimeout, "finalizer-timeout", 30*time.Minute,
+		"How long AWS resources of a deleting resource can fail to be deleted before it's reported as stuck, and can be force deleted with annotation appmesh.k8s.aws/force-delete. Set to 0 to disable")
 
 	awsCloudConfig.BindFlags(fs)
 	injectConfig.BindFlags(fs)
 	cloudMapConfig.BindFlags(fs)
+	virtualNodeConfig.BindFlags(fs)
+	virtualRouterConfig.BindFlags(fs)
+	externalChangesConfig.BindFlags(fs)
+	conversionConfig.BindFlags(fs)
+	webhookCertConfig.BindFlags(fs)
+	awsNameConfig.BindFlags(fs)
+	taggingConfig.BindFlags(fs)
+	quotaConfig.BindFlags(fs)
+	tracingConfig.BindFlags(fs)
+	featureGates.AddFlag(fs)
+	controllerConfig.BindFlags(fs)
+	shardingConfig.BindFlags(fs)
+	virtualServiceDNSConfig.BindFlags(fs)
+	smiConfig.BindFlags(fs)
+	topologyConfig.BindFlags(fs)
+	backupConfig.BindFlags(fs)
+	envoyAdminConfig.BindFlags(fs)
+	cacheConfig.BindFlags(fs)
+	coverageConfig.BindFlags(fs)
+	loggingConfig.BindFlags(fs)
+	componentConfig.BindFlags(fs)
 	if err := fs.Parse(os.Args); err != nil {
 		setupLog.Error(err, "invalid flags")
 		os.Exit(1)
 	}
+	var componentConfigValues map[string]string
+	var componentConfigOverridden map[string]bool
+	if componentConfig.ConfigFile != "" {
+		cc, err := componentconfig.Load(componentConfig.ConfigFile)
+		if err != nil {
+			setupLog.Error(err, "invalid controller configuration")
+			os.Exit(1)
+		}
+		componentConfigValues = cc.FlagValues()
+		if componentConfigOverridden, err = componentconfig.Apply(fs, componentConfigValues); err != nil {
+			setupLog.Error(err, "invalid controller configuration")
+			os.Exit(1)
+		}
+	}
 
+	if err := awsCloudConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
 	if err := injectConfig.Validate(); err != nil {
 		setupLog.Error(err, "invalid flags")
 		os.Exit(1)
 	}
-
-	lvl := zapraw.NewAtomicLevelAt(0)
-	if logLevel == "debug" {
-		lvl = zapraw.NewAtomicLevelAt(-1)
+	if err := virtualNodeConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := virtualRouterConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
 	}
-	ctrl.SetLogger(zap.New(zap.UseDevMode(false), zap.Level(&lvl)))
+	if err := awsNameConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := taggingConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := quotaConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := tracingConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := controllerConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := shardingConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := virtualServiceDNSConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := smiConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := topologyConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := backupConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := coverageConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := cloudMapConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := cacheConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := webhookCertConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	if err := loggingConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+	// the readiness gate of pods is flipped by a controller watching pods.
+	if injectConfig.EnableEnvoyReadinessGate && cacheConfig.IsCacheDisabled(k8s.CacheKindPod) {
+		setupLog.Error(errors.New("envoy readiness gate requires caching pods"), "invalid flags")
+		os.Exit(1)
+	}
+
+	loggerVerbosity, _ := logging.ParseLoggerVerbosity(loggingConfig.LoggerVerbosity)
+	logLevels := logging.NewLevels(logLevel == logging.LevelDebug, loggerVerbosity)
+	zapLogger := zap.New(zap.UseDevMode(false), zap.Level(logLevels.ZapLevel()))
+	ctrl.SetLogger(logr.New(logLevels.NewLogSink(zapLogger.GetSink())))
 	setupLog.Info("version",
 		"GitVersion", version.GitVersion,
 		"GitCommit", version.GitCommit,
 		"BuildDate", version.BuildDate,
 	)
+	for _, feature := range features.KnownFeatures(featureGates) {
+		setupLog.Info("feature gate", "feature", feature, "enabled", featureGates.Enabled(feature))
+	}
 
 	awsCloudConfig.HandleAccountID(setupLog)
 	parsedPort := strconv.Itoa(healthProbePort)
@@ -180,23 +364,42 @@ func main() {
 	}
 
 	optionsTlSOptsFuncs = append(optionsTlSOptsFuncs, tlsOption)
+	webhookCertDir := filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
 
+	newCache, err := k8s.NewInstrumentedCacheFunc(shardingConfig.NewCacheFunc(), metrics.Registry)
+	if err != nil {
+		setupLog.Error(err, "unable to instrument cache")
+		os.Exit(1)
+	}
 	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
 		Scheme:                     scheme,
+		NewCache:                   newCache,
+		ClientDisableCacheFor:      cacheConfig.UncachedObjects(),
 		SyncPeriod:                 &syncPeriod,
 		MetricsBindAddress:         metricsAddr,
 		Port:                       9443,
+		CertDir:                    webhookCertDir,
 		LeaderElection:             enableLeaderElection,
-		LeaderElectionID:           "appmesh-controller-leader-election",
+		LeaderElectionID:           shardingConfig.LeaderElectionID("appmesh-controller-leader-election"),
 		LeaderElectionResourceLock: resourcelock.ConfigMapsLeasesResourceLock,
-		HealthProbeBindAddress:     healthProbeBindAddress,
-		TLSOpts:                    optionsTlSOptsFuncs,
+		LeaseDuration:              &leaderElectionLeaseDuration,
+		RenewDeadline:              &leaderElectionRenewDeadline,
+		RetryPeriod:                &leaderElectionRetryPeriod,
+		// releasing leadership when stopped lets another replica take over without waiting for the lease to expire.
+		LeaderElectionReleaseOnCancel: true,
+		HealthProbeBindAddress:        healthProbeBindAddress,
+		TLSOpts:                       optionsTlSOptsFuncs,
 	})
 
+	// the custom controller watches pods of a single namespace, or of every namespace.
+	podsNamespace := metav1.NamespaceAll
+	if len(shardingConfig.WatchNamespaces) == 1 {
+		podsNamespace = shardingConfig.WatchNamespaces[0]
+	}
 	customController := k8s.NewCustomController(
 		clientSet,
 		listPageLimit,
-		metav1.NamespaceAll,
+		podsNamespace,
 		conversions.NewPodConverter(),
 		syncPeriod,
 		false,
@@ -207,6 +410,11 @@ func main() {
 		setupLog.Error(err, "unable to start app mesh controller")
 		os.Exit(1)
 	}
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig, version.GitVersion)
+	if err != nil {
+		setupLog.Error(err, "unable to setup tracing")
+		os.Exit(1)
+	}
 	cloud, err := aws.NewCloud(awsCloudConfig, metrics.Registry)
 	if err != nil {
 		setupLog.Error(err, "unable to initialize AWS cloud")
@@ -234,34 +442,66 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 	referencesIndexer := references.NewDefaultObjectReferenceIndexer(mgr.GetCache(), mgr.GetFieldIndexer())
+	arnIndexer := arnindex.NewDefaultARNIndexer(mgr.GetCache(), mgr.GetFieldIndexer())
+	if err := arnIndexer.Setup(ctx); err != nil {
+		setupLog.Error(err, "unable to setup ARN index")
+		os.Exit(1)
+	}
 	finalizerManager := k8s.NewDefaultFinalizerManager(mgr.GetClient(), ctrl.Log)
+	stuckDeletionHandler := k8s.NewDefaultStuckDeletionHandler(mgr.GetClient(), mgr.GetEventRecorderFor("finalizer"), finalizerTimeout, ctrl.Log.WithName("stuck-deletion"))
 	meshMembersFinalizer := mesh.NewPendingMembersFinalizer(mgr.GetClient(), mgr.GetEventRecorderFor("mesh-members"), ctrl.Log)
 	vgMembersFinalizer := virtualgateway.NewPendingMembersFinalizer(mgr.GetClient(), mgr.GetEventRecorderFor("virtualgateway-members"), ctrl.Log)
 	referencesResolver := references.NewDefaultResolver(mgr.GetClient(), ctrl.Log)
+	namespaceRoleResolver := aws.NewNamespaceRoleResolver(mgr.GetClient(), awsCloudConfig.EnableNamespaceIAMRoles)
+	externalChangesWatcher := externalchanges.NewWatcher(externalChangesConfig, cloud.SQS(), cloud.AppMeshCache(), mgr.GetClient(), ctrl.Log.WithName("external-changes"))
 	virtualNodeEndpointResolver := cloudmap.NewDefaultVirtualNodeEndpointResolver(podsRepository, ctrl.Log)
-	cloudMapInstancesReconciler := cloudmap.NewDefaultInstancesReconciler(mgr.GetClient(), cloud.CloudMap(), ctrl.Log, ctx.Done(), ipFamily)
-	meshResManager := mesh.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), cloud.AccountID(), ctrl.Log)
-	vgResManager := virtualgateway.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), ctrl.Log)
-	grResManager := gatewayroute.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), ctrl.Log)
-	vnResManager := virtualnode.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), ctrl.Log, injectConfig.EnableBackendGroups)
-	vsResManager := virtualservice.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), ctrl.Log)
-	vrResManager := virtualrouter.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), ctrl.Log)
-	cloudMapResManager := cloudmap.NewDefaultResourceManager(mgr.GetClient(), cloud.CloudMap(), referencesResolver, virtualNodeEndpointResolver, cloudMapInstancesReconciler, enableCustomHealthCheck, ctrl.Log, cloudMapConfig, ipFamily)
-	msReconciler := appmeshcontroller.NewMeshReconciler(mgr.GetClient(), finalizerManager, meshMembersFinalizer, meshResManager, ctrl.Log.WithName("controllers").WithName("Mesh"), mgr.GetEventRecorderFor("Mesh"))
-	vgReconciler := appmeshcontroller.NewVirtualGatewayReconciler(mgr.GetClient(), finalizerManager, vgMembersFinalizer, vgResManager, ctrl.Log.WithName("controllers").WithName("VirtualGateway"), mgr.GetEventRecorderFor("VirtualGateway"))
-	grReconciler := appmeshcontroller.NewGatewayRouteReconciler(mgr.GetClient(), finalizerManager, grResManager, ctrl.Log.WithName("controllers").WithName("GatewayRoute"), mgr.GetEventRecorderFor("GatewayRoute"))
-	vnReconciler := appmeshcontroller.NewVirtualNodeReconciler(mgr.GetClient(), finalizerManager, vnResManager, ctrl.Log.WithName("controllers").WithName("VirtualNode"), mgr.GetEventRecorderFor("VirtualNode"), injectConfig.EnableBackendGroups)
+	cloudMapInstancesReconciler, err := cloudmap.NewDefaultInstancesReconciler(mgr.GetClient(), cloud.CloudMap(), cloudMapConfig, metrics.Registry, ctrl.Log, ctx.Done(), ipFamily)
+	if err != nil {
+		setupLog.Error(err, "unable to create cloudMap instances reconciler")
+		os.Exit(1)
+	}
+	tagsManager := tagging.NewDefaultManager(taggingConfig, cloud.AppMesh(), injectConfig.ClusterName, ctrl.Log.WithName("tagging"))
+	var quotaChecker quota.Checker = quota.NewNoopChecker()
+	if quotaConfig.Enabled {
+		quotaChecker = quota.NewDefaultChecker(quotaConfig, cloud.ServiceQuotas(), mgr.GetClient(), ctrl.Log.WithName("quota"))
+	}
+	maintenanceScheduler := maintenance.NewDefaultScheduler(mgr.GetClient())
+	meshResManager := mesh.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), cloud.AccountID(), tagsManager, ctrl.Log.WithName("mesh"))
+	meshReplicator := mesh.NewDefaultReplicator(mgr.GetClient(), cloud.AppMesh(), cloud.AppMeshForRegion, cloud.Region(), cloud.AccountID(), ctrl.Log.WithName("mesh-replication"))
+	vgResManager := virtualgateway.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), tagsManager, quotaChecker, ctrl.Log.WithName("virtualgateway"))
+	vgLBManager := virtualgateway.NewDefaultLoadBalancerManager(mgr.GetClient(), mgr.GetScheme(), ctrl.Log.WithName("virtualgateway-loadbalancer"))
+	grResManager := gatewayroute.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), tagsManager, quotaChecker, ctrl.Log.WithName("gatewayroute"))
+	vnResManager := virtualnode.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), tagsManager, quotaChecker, maintenanceScheduler, ctrl.Log.WithName("virtualnode"), injectConfig.EnableBackendGroups, featureGates.Enabled(features.MeshPolicies))
+	podMonitorManager := podmonitor.NewDefaultManager(mgr.GetClient(), mgr.GetScheme(), injectConfig.PrometheusScrapeMode == inject.PrometheusScrapeModePodMonitor, ctrl.Log.WithName("podmonitor"))
+	vnRolloutOrchestrator := virtualnode.NewDefaultRolloutOrchestrator(mgr.GetClient(), virtualNodeConfig, ctrl.Log.WithName("virtualnode-rollout"))
+	deletionOrchestrator := deletion.NewDefaultOrchestrator(referencesIndexer, ctrl.Log.WithName("deletion"))
+	vsResManager := virtualservice.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), tagsManager, quotaChecker, ctrl.Log.WithName("virtualservice"))
+	vsDNSManager := virtualservice.NewDefaultDNSManager(virtualServiceDNSConfig, mgr.GetClient(), mgr.GetScheme(), cloud.Route53(), ctrl.Log.WithName("virtualservice-dns"))
+	vrResManager := virtualrouter.NewDefaultResourceManager(mgr.GetClient(), cloud.AppMesh(), referencesResolver, cloud.AccountID(), tagsManager, quotaChecker, maintenanceScheduler, virtualRouterConfig, ctrl.Log.WithName("virtualrouter"), featureGates.Enabled(features.MeshPolicies))
+	perPodVirtualNodesManager := cloudmap.NewDefaultPerPodVirtualNodesManager(mgr.GetClient(), mgr.GetScheme(), ctrl.Log.WithName("cloudmap-per-pod-virtualnodes"))
+	cloudMapResManager := cloudmap.NewDefaultResourceManager(mgr.GetClient(), cloud.CloudMap(), referencesResolver, virtualNodeEndpointResolver, cloudMapInstancesReconciler, perPodVirtualNodesManager, enableCustomHealthCheck, ctrl.Log.WithName("cloudmap"), cloudMapConfig, ipFamily)
+	sharder := sharding.NewSharder(shardingConfig, mgr.GetClient())
+	msReconciler := appmeshcontroller.NewMeshReconciler(mgr.GetClient(), finalizerManager, stuckDeletionHandler, meshMembersFinalizer, meshResManager, meshReplicator, externalChangesWatcher.Source(externalchanges.KindMesh), controllerConfig.Options(appmeshruntime.ControllerMesh), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("Mesh"), mgr.GetEventRecorderFor("Mesh"))
+	vgReconciler := appmeshcontroller.NewVirtualGatewayReconciler(mgr.GetClient(), namespaceRoleResolver, finalizerManager, stuckDeletionHandler, vgMembersFinalizer, vgResManager, vgLBManager, podMonitorManager, externalChangesWatcher.Source(externalchanges.KindVirtualGateway), controllerConfig.Options(appmeshruntime.ControllerVirtualGateway), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("VirtualGateway"), mgr.GetEventRecorderFor("VirtualGateway"))
+	grReconciler := appmeshcontroller.NewGatewayRouteReconciler(mgr.GetClient(), namespaceRoleResolver, finalizerManager, stuckDeletionHandler, referencesIndexer, grResManager, externalChangesWatcher.Source(externalchanges.KindGatewayRoute), controllerConfig.Options(appmeshruntime.ControllerGatewayRoute), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("GatewayRoute"), mgr.GetEventRecorderFor("GatewayRoute"))
+	vnReconciler := appmeshcontroller.NewVirtualNodeReconciler(mgr.GetClient(), namespaceRoleResolver, finalizerManager, stuckDeletionHandler, referencesIndexer, vnResManager, vnRolloutOrchestrator, podMonitorManager, deletionOrchestrator, externalChangesWatcher.Source(externalchanges.KindVirtualNode), controllerConfig.Options(appmeshruntime.ControllerVirtualNode), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("VirtualNode"), mgr.GetEventRecorderFor("VirtualNode"), injectConfig.EnableBackendGroups, featureGates.Enabled(features.MeshPolicies), !cacheConfig.IsCacheDisabled(k8s.CacheKindPod))
 
 	cloudMapReconciler := appmeshcontroller.NewCloudMapReconciler(
 		mgr.GetClient(),
+		namespaceRoleResolver,
 		finalizerManager,
+		stuckDeletionHandler,
 		cloudMapResManager,
 		eventNotificationChan,
+		controllerConfig.Options(appmeshruntime.ControllerCloudMap),
+		controllerConfig.ReconcileTimeout,
+		sharder,
 		ctrl.Log.WithName("controllers").WithName("CloudMap"),
 		mgr.GetEventRecorderFor("CloudMap"))
 
-	vsReconciler := appmeshcontroller.NewVirtualServiceReconciler(mgr.GetClient(), finalizerManager, referencesIndexer, vsResManager, ctrl.Log.WithName("controllers").WithName("VirtualService"), mgr.GetEventRecorderFor("VirtualService"))
-	vrReconciler := appmeshcontroller.NewVirtualRouterReconciler(mgr.GetClient(), finalizerManager, referencesIndexer, vrResManager, ctrl.Log.WithName("controllers").WithName("VirtualRouter"), mgr.GetEventRecorderFor("VirtualRouter"))
+	vsReconciler := appmeshcontroller.NewVirtualServiceReconciler(mgr.GetClient(), namespaceRoleResolver, finalizerManager, stuckDeletionHandler, referencesIndexer, vsResManager, vsDNSManager, externalChangesWatcher.Source(externalchanges.KindVirtualService), controllerConfig.Options(appmeshruntime.ControllerVirtualService), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("VirtualService"), mgr.GetEventRecorderFor("VirtualService"))
+	vrVSManager := virtualrouter.NewDefaultVirtualServiceManager(mgr.GetClient(), mgr.GetScheme(), ctrl.Log.WithName("virtualrouter-virtualservice"))
+	vrReconciler := appmeshcontroller.NewVirtualRouterReconciler(mgr.GetClient(), namespaceRoleResolver, finalizerManager, stuckDeletionHandler, referencesIndexer, vrResManager, vrVSManager, deletionOrchestrator, externalChangesWatcher.Source(externalchanges.KindVirtualRouter), controllerConfig.Options(appmeshruntime.ControllerVirtualRouter), controllerConfig.ReconcileTimeout, sharder, ctrl.Log.WithName("controllers").WithName("VirtualRouter"), mgr.GetEventRecorderFor("VirtualRouter"), featureGates.Enabled(features.MeshPolicies))
 	if err = msReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Mesh")
 		os.Exit(1)
@@ -292,26 +532,208 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "CloudMap")
 		os.Exit(1)
 	}
+	if err = metrics.Registry.Register(appmeshmetrics.NewManagedResourcesCollector(mgr.GetClient(), ctrl.Log.WithName("metrics"))); err != nil {
+		setupLog.Error(err, "unable to register managed resources metrics")
+		os.Exit(1)
+	}
+	if err = metrics.Registry.Register(features.NewCollector(featureGates)); err != nil {
+		setupLog.Error(err, "unable to register feature gates metrics")
+		os.Exit(1)
+	}
+	meshTLSAuditor, err := mesh.NewDefaultTLSAuditor(mgr.GetClient(), referencesResolver, metrics.Registry, ctrl.Log.WithName("mesh-tls-auditor"))
+	if err != nil {
+		setupLog.Error(err, "unable to create mesh TLS auditor")
+		os.Exit(1)
+	}
+	meshTLSAuditReconciler := appmeshcontroller.NewMeshTLSAuditReconciler(mgr.GetClient(), meshTLSAuditor, sharder, ctrl.Log.WithName("controllers").WithName("MeshTLSAudit"))
+	if err = meshTLSAuditReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MeshTLSAudit")
+		os.Exit(1)
+	}
+	meshMembersStatusReconciler := appmeshcontroller.NewMeshMembersStatusReconciler(mgr.GetClient(), mesh.NewDefaultMembersStatusAggregator(mgr.GetClient()), sharder, ctrl.Log.WithName("controllers").WithName("MeshMembersStatus"))
+	if err = meshMembersStatusReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MeshMembersStatus")
+		os.Exit(1)
+	}
+	if coverageConfig.ReportInterval > 0 {
+		coverageReporter, err := coverage.NewDefaultReporter(mgr.GetClient(), metrics.Registry, ctrl.Log.WithName("mesh-coverage"))
+		if err != nil {
+			setupLog.Error(err, "unable to create mesh coverage reporter")
+			os.Exit(1)
+		}
+		meshCoverageReconciler := appmeshcontroller.NewMeshCoverageReconciler(mgr.GetClient(), coverageReporter, coverageConfig.ReportInterval, sharder, ctrl.Log.WithName("controllers").WithName("MeshCoverage"))
+		if err = meshCoverageReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MeshCoverage")
+			os.Exit(1)
+		}
+	}
+	conflictDetector := conflicts.NewDefaultDetector(mgr.GetClient())
+	vnConflictReconciler := appmeshcontroller.NewVirtualNodeConflictReconciler(mgr.GetClient(), conflictDetector, sharder, ctrl.Log.WithName("controllers").WithName("VirtualNodeConflict"), mgr.GetEventRecorderFor("VirtualNodeConflict"), !cacheConfig.IsCacheDisabled(k8s.CacheKindPod))
+	if err = vnConflictReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VirtualNodeConflict")
+		os.Exit(1)
+	}
+	if virtualNodeConfig.EndpointHealthReportInterval > 0 {
+		endpointsHealthReporter := virtualnode.NewDefaultEndpointsHealthReporter(mgr.GetClient(), envoy.NewDefaultHealthChecker())
+		vnHealthReconciler := appmeshcontroller.NewVirtualNodeHealthReconciler(mgr.GetClient(), endpointsHealthReporter, virtualNodeConfig.EndpointHealthReportInterval, sharder, ctrl.Log.WithName("controllers").WithName("VirtualNodeHealth"))
+		if err = vnHealthReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "VirtualNodeHealth")
+			os.Exit(1)
+		}
+	}
+	meshConflictReconciler := appmeshcontroller.NewMeshConflictReconciler(mgr.GetClient(), conflictDetector, sharder, ctrl.Log.WithName("controllers").WithName("MeshConflict"), mgr.GetEventRecorderFor("MeshConflict"))
+	if err = meshConflictReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MeshConflict")
+		os.Exit(1)
+	}
+	if injectConfig.EnableEnvoyReadinessGate {
+		envoyReadinessReconciler := appmeshcontroller.NewEnvoyReadinessReconciler(mgr.GetClient(), envoy.NewDefaultReadinessChecker(), sharder, ctrl.Log.WithName("controllers").WithName("EnvoyReadiness"))
+		if err = envoyReadinessReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EnvoyReadiness")
+			os.Exit(1)
+		}
+	}
+	if smiConfig.EnableTrafficSplit {
+		trafficSplitReconciler := appmeshcontroller.NewTrafficSplitReconciler(mgr.GetClient(), smi.NewDefaultSynchronizer(mgr.GetClient(), ctrl.Log.WithName("smi")), smiConfig.TrafficSplitGVK(), controllerConfig.Options(appmeshruntime.ControllerTrafficSplit), sharder, ctrl.Log.WithName("controllers").WithName("TrafficSplit"), mgr.GetEventRecorderFor("TrafficSplit"))
+		if err = trafficSplitReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TrafficSplit")
+			os.Exit(1)
+		}
+	}
+
+	if externalChangesConfig.Enabled() {
+		if err := mgr.Add(externalChangesWatcher); err != nil {
+			setupLog.Error(err, "unable to watch external changes")
+			os.Exit(1)
+		}
+	}
+
+	if componentConfig.ConfigFile != "" {
+		componentConfigReloader := componentconfig.NewReloader(componentConfig.ConfigFile, componentConfigValues, componentConfigOverridden,
+			map[string]componentconfig.ReloadFunc{
+				"log-level": componentconfig.NewLogLevelReloadFunc(logLevels.SetDebug),
+				logging.FlagLoggerVerbosity: func(value string) error {
+					loggerVerbosity, err := logging.ParseLoggerVerbosity(value)
+					if err != nil {
+						return errors.Wrap(err, logging.FlagLoggerVerbosity)
+					}
+					logLevels.SetLoggerVerbosity(loggerVerbosity)
+					return nil
+				},
+			}, ctrl.Log.WithName("componentconfig"))
+		if err := mgr.Add(componentConfigReloader); err != nil {
+			setupLog.Error(err, "unable to reload controller configuration")
+			os.Exit(1)
+		}
+	}
+
+	if taggingConfig.BackfillTags {
+		tagsBackfiller := tagging.NewBackfiller(mgr.GetClient(), tagsManager, namespaceRoleResolver, sharder, cloud.AccountID(), ctrl.Log.WithName("tagging"))
+		if err := mgr.Add(tagsBackfiller); err != nil {
+			setupLog.Error(err, "unable to backfill tags")
+			os.Exit(1)
+		}
+	}
+
+	if topologyConfig.EnableEndpoint {
+		if err := mgr.AddMetricsExtraHandler(topology.EndpointPath, topology.NewHandler(clientSet, mgr.GetClient(), ctrl.Log.WithName("topology"))); err != nil {
+			setupLog.Error(err, "unable to serve mesh topology")
+			os.Exit(1)
+		}
+	}
+	if len(topologyConfig.ConfigMap) != 0 {
+		configMapKey, _ := topologyConfig.ConfigMapKey()
+		topologyExporter := topology.NewConfigMapExporter(configMapKey, topologyConfig.ConfigMapInterval, mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("topology"))
+		if err := mgr.Add(topologyExporter); err != nil {
+			setupLog.Error(err, "unable to export mesh topology")
+			os.Exit(1)
+		}
+	}
+	if backupConfig.Enabled() {
+		var backupStore backup.Store
+		if len(backupConfig.ConfigMap) != 0 {
+			configMapKey, _ := backupConfig.ConfigMapKey()
+			backupStore = backup.NewConfigMapStore(configMapKey, mgr.GetClient(), mgr.GetAPIReader())
+		} else {
+			backupStore = backup.NewS3Store(cloud.S3(), backupConfig.S3Bucket, backupConfig.S3Prefix)
+		}
+		snapshotter := backup.NewDefaultSnapshotter(mgr.GetClient(), cloud.AppMesh(), namespaceRoleResolver, mgr.GetScheme(), featureGates.Enabled(features.MeshPolicies))
+		backuper := backup.NewBackuper(snapshotter, backupStore, backupConfig.Interval, backupConfig.Retention, ctrl.Log.WithName("backup"))
+		if err := mgr.Add(backuper); err != nil {
+			setupLog.Error(err, "unable to snapshot mesh configuration")
+			os.Exit(1)
+		}
+	}
+	if loggingConfig.EnableEndpoint {
+		if err := mgr.AddMetricsExtraHandler(logging.EndpointPath, logging.NewHandler(clientSet, logLevels, ctrl.Log.WithName("logging"))); err != nil {
+			setupLog.Error(err, "unable to serve log levels")
+			os.Exit(1)
+		}
+	}
+	if envoyAdminConfig.EnableProxy {
+		if err := mgr.AddMetricsExtraHandler(envoyadmin.ProxyPath, envoyadmin.NewProxyHandler(clientSet, injectConfig.EnvoyAdminAcessPort, ctrl.Log.WithName("envoyadmin"))); err != nil {
+			setupLog.Error(err, "unable to proxy Envoy admin queries")
+			os.Exit(1)
+		}
+	}
 
 	meshMembershipDesignator := mesh.NewMembershipDesignator(mgr.GetClient())
 	vgMembershipDesignator := virtualgateway.NewMembershipDesignator(mgr.GetClient())
 	vnMembershipDesignator := virtualnode.NewMembershipDesignator(mgr.GetClient())
-	sidecarInjector := inject.NewSidecarInjector(injectConfig, cloud.AccountID(), cloud.Region(), version.GitVersion, k8sVersion, mgr.GetClient(), referencesResolver, vnMembershipDesignator, vgMembershipDesignator)
-	appmeshwebhook.NewMeshMutator(ipFamily).SetupWithManager(mgr)
+	sidecarInjector := inject.NewSidecarInjector(injectConfig, cloud.AccountID(), cloud.Region(), version.GitVersion, k8sVersion, mgr.GetClient(), cloud.SSM(), referencesResolver, vnMembershipDesignator, vgMembershipDesignator)
+	awsNameGenerator := awsname.NewGenerator(awsNameConfig, injectConfig.ClusterName)
+	var namespaceQuotaChecker quota.NamespaceChecker = quota.NewNoopNamespaceChecker()
+	if len(quotaConfig.NamespaceQuotasConfigMap) != 0 {
+		configMapKey, _ := quotaConfig.NamespaceQuotasConfigMapKey()
+		namespaceQuotaChecker = quota.NewNamespaceChecker(configMapKey, mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("namespace-quota"))
+	}
+	appmeshwebhook.NewMeshMutator(ipFamily, awsNameGenerator).SetupWithManager(mgr)
 	appmeshwebhook.NewMeshValidator(ipFamily).SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualGatewayMutator(meshMembershipDesignator).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualGatewayMutator(meshMembershipDesignator, awsNameGenerator).SetupWithManager(mgr)
 	appmeshwebhook.NewVirtualGatewayValidator().SetupWithManager(mgr)
-	appmeshwebhook.NewGatewayRouteMutator(meshMembershipDesignator, vgMembershipDesignator).SetupWithManager(mgr)
+	appmeshwebhook.NewGatewayRouteMutator(meshMembershipDesignator, vgMembershipDesignator, awsNameGenerator).SetupWithManager(mgr)
 	appmeshwebhook.NewGatewayRouteValidator().SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualNodeMutator(meshMembershipDesignator).SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualNodeValidator().SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualServiceMutator(meshMembershipDesignator).SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualServiceValidator().SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualRouterMutator(meshMembershipDesignator).SetupWithManager(mgr)
-	appmeshwebhook.NewVirtualRouterValidator().SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualNodeMutator(meshMembershipDesignator, awsNameGenerator).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualNodeValidator(mgr.GetClient(), referencesIndexer, namespaceQuotaChecker).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualServiceMutator(meshMembershipDesignator, awsNameGenerator).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualServiceValidator(mgr.GetClient(), referencesIndexer).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualRouterMutator(meshMembershipDesignator, awsNameGenerator).SetupWithManager(mgr)
+	appmeshwebhook.NewVirtualRouterValidator(mgr.GetClient(), namespaceQuotaChecker).SetupWithManager(mgr)
 	appmeshwebhook.NewBackendGroupMutator(meshMembershipDesignator).SetupWithManager(mgr)
 	appmeshwebhook.NewBackendGroupValidator().SetupWithManager(mgr)
+	appmeshwebhook.NewMeshPolicyMutator(meshMembershipDesignator).SetupWithManager(mgr)
+	appmeshwebhook.NewMeshPolicyValidator().SetupWithManager(mgr)
 	corewebhook.NewPodMutator(sidecarInjector).SetupWithManager(mgr)
+	mgr.GetWebhookServer().Register(webhook.ConversionWebhookPath, &conversion.Webhook{})
+	if webhookCertConfig.Enabled() {
+		certRotator, err := webhook.NewCertRotator(webhookCertConfig, mgr.GetClient(), mgr.GetAPIReader(), webhookCertDir,
+			ctrl.Log.WithName("webhook-cert-rotator"))
+		if err != nil {
+			setupLog.Error(err, "invalid flags")
+			os.Exit(1)
+		}
+		// the webhook server fails to start without a certificate, so it's in place before the manager is started.
+		if err := certRotator.Rotate(ctx); err != nil {
+			setupLog.Error(err, "unable to provision webhook serving certificate")
+			os.Exit(1)
+		}
+		if err := mgr.Add(certRotator); err != nil {
+			setupLog.Error(err, "unable to rotate webhook serving certificate")
+			os.Exit(1)
+		}
+	}
+	if conversionConfig.Enabled() {
+		conversionConfigurer, err := webhook.NewConversionConfigurer(conversionConfig, mgr.GetClient(),
+			filepath.Join(webhookCertDir, "ca.crt"), ctrl.Log.WithName("conversion-configurer"))
+		if err != nil {
+			setupLog.Error(err, "invalid flags")
+			os.Exit(1)
+		}
+		if err := mgr.Add(conversionConfigurer); err != nil {
+			setupLog.Error(err, "unable to configure CRD conversion webhook")
+			os.Exit(1)
+		}
+	}
 
 	// Add liveness probe
 	err = mgr.AddHealthzCheck("health-ping", healthz.Ping)
@@ -342,4 +764,9 @@ func main() {
 		setupLog.Error(err, "problem running controller")
 		os.Exit(1)
 	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "failed to flush traces")
+	}
 }
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	v1beta2 "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	equality "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -49,6 +50,21 @@ func (mr *MockResourceManagerMockRecorder) Cleanup(ctx, vn interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockResourceManager)(nil).Cleanup), ctx, vn)
 }
 
+// Diff mocks base method.
+func (m *MockResourceManager) Diff(ctx context.Context, vn *v1beta2.VirtualNode) ([]equality.SpecDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, vn)
+	ret0, _ := ret[0].([]equality.SpecDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockResourceManagerMockRecorder) Diff(ctx, vn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockResourceManager)(nil).Diff), ctx, vn)
+}
+
 // Reconcile mocks base method.
 func (m *MockResourceManager) Reconcile(ctx context.Context, vn *v1beta2.VirtualNode) error {
 	m.ctrl.T.Helper()
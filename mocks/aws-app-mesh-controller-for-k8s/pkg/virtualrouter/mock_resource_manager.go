@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	v1beta2 "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	equality "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -49,6 +50,21 @@ func (mr *MockResourceManagerMockRecorder) Cleanup(ctx, vr interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockResourceManager)(nil).Cleanup), ctx, vr)
 }
 
+// Diff mocks base method.
+func (m *MockResourceManager) Diff(ctx context.Context, vr *v1beta2.VirtualRouter) ([]equality.SpecDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, vr)
+	ret0, _ := ret[0].([]equality.SpecDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockResourceManagerMockRecorder) Diff(ctx, vr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockResourceManager)(nil).Diff), ctx, vr)
+}
+
 // Reconcile mocks base method.
 func (m *MockResourceManager) Reconcile(ctx context.Context, vr *v1beta2.VirtualRouter) error {
 	m.ctrl.T.Helper()
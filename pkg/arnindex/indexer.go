@@ -0,0 +1,221 @@
+package arnindex
+
+import (
+	"context"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IndexKeyARN is the field index key under which AppMesh objects are indexed by the ARNs recorded in their status.
+const IndexKeyARN = "status.arn"
+
+// ARNIndexer is responsible for indexing AppMesh objects by their AppMesh ARN,
+// and fetch objects by ARN using that index.
+type ARNIndexer interface {
+	// Setup registers the ARN index for all AppMesh kinds.
+	Setup(ctx context.Context) error
+	// Lookup returns the object whose status records given ARN.
+	// A NotFound error is returned if no such object exists.
+	Lookup(ctx context.Context, resourceARN string) (client.Object, error)
+}
+
+func NewDefaultARNIndexer(k8sCache cache.Cache, k8sFieldIndexer client.FieldIndexer) *defaultARNIndexer {
+	return &defaultARNIndexer{
+		k8sCache:        k8sCache,
+		k8sFieldIndexer: k8sFieldIndexer,
+	}
+}
+
+var _ ARNIndexer = &defaultARNIndexer{}
+
+type defaultARNIndexer struct {
+	k8sCache        cache.Cache
+	k8sFieldIndexer client.FieldIndexer
+}
+
+func (i *defaultARNIndexer) Setup(ctx context.Context) error {
+	for _, kind := range indexedKinds {
+		newObject := kind.newObject
+		if err := i.k8sFieldIndexer.IndexField(ctx, newObject(), IndexKeyARN, ARNsOf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *defaultARNIndexer) Lookup(ctx context.Context, resourceARN string) (client.Object, error) {
+	return lookup(ctx, i.k8sCache, resourceARN, client.MatchingFields{IndexKeyARN: resourceARN})
+}
+
+// FindByARN returns the object whose status records given ARN by listing objects of the kind inferred from the ARN.
+// Unlike ARNIndexer.Lookup, it doesn't require the index and works against uncached readers.
+// A NotFound error is returned if no such object exists.
+func FindByARN(ctx context.Context, reader client.Reader, resourceARN string) (client.Object, error) {
+	return lookup(ctx, reader, resourceARN)
+}
+
+// ARNsOf returns the AppMesh ARNs recorded in the status of obj.
+// VirtualRouters are indexed by their Route ARNs as well, since Routes are not modeled as a separate kind.
+func ARNsOf(obj client.Object) []string {
+	var arns []string
+	switch o := obj.(type) {
+	case *appmesh.Mesh:
+		arns = appendARN(arns, o.Status.MeshARN)
+	case *appmesh.VirtualGateway:
+		arns = appendARN(arns, o.Status.VirtualGatewayARN)
+	case *appmesh.GatewayRoute:
+		arns = appendARN(arns, o.Status.GatewayRouteARN)
+	case *appmesh.VirtualNode:
+		arns = appendARN(arns, o.Status.VirtualNodeARN)
+	case *appmesh.VirtualService:
+		arns = appendARN(arns, o.Status.VirtualServiceARN)
+	case *appmesh.VirtualRouter:
+		arns = appendARN(arns, o.Status.VirtualRouterARN)
+		for _, routeARN := range o.Status.RouteARNs {
+			arns = appendARN(arns, aws.String(routeARN))
+		}
+	}
+	return arns
+}
+
+func appendARN(arns []string, resourceARN *string) []string {
+	if aws.StringValue(resourceARN) == "" {
+		return arns
+	}
+	return append(arns, aws.StringValue(resourceARN))
+}
+
+type indexedKind struct {
+	kind          string
+	newObject     func() client.Object
+	newObjectList func() client.ObjectList
+	items         func(client.ObjectList) []client.Object
+}
+
+// indexedKinds is keyed by the trailing resource type in an AppMesh ARN's resource path.
+var indexedKinds = map[string]indexedKind{
+	"mesh": {
+		kind:          "Mesh",
+		newObject:     func() client.Object { return &appmesh.Mesh{} },
+		newObjectList: func() client.ObjectList { return &appmesh.MeshList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.MeshList).Items {
+				objs = append(objs, &list.(*appmesh.MeshList).Items[i])
+			}
+			return objs
+		},
+	},
+	"virtualGateway": {
+		kind:          "VirtualGateway",
+		newObject:     func() client.Object { return &appmesh.VirtualGateway{} },
+		newObjectList: func() client.ObjectList { return &appmesh.VirtualGatewayList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.VirtualGatewayList).Items {
+				objs = append(objs, &list.(*appmesh.VirtualGatewayList).Items[i])
+			}
+			return objs
+		},
+	},
+	"gatewayRoute": {
+		kind:          "GatewayRoute",
+		newObject:     func() client.Object { return &appmesh.GatewayRoute{} },
+		newObjectList: func() client.ObjectList { return &appmesh.GatewayRouteList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.GatewayRouteList).Items {
+				objs = append(objs, &list.(*appmesh.GatewayRouteList).Items[i])
+			}
+			return objs
+		},
+	},
+	"virtualNode": {
+		kind:          "VirtualNode",
+		newObject:     func() client.Object { return &appmesh.VirtualNode{} },
+		newObjectList: func() client.ObjectList { return &appmesh.VirtualNodeList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.VirtualNodeList).Items {
+				objs = append(objs, &list.(*appmesh.VirtualNodeList).Items[i])
+			}
+			return objs
+		},
+	},
+	"virtualService": {
+		kind:          "VirtualService",
+		newObject:     func() client.Object { return &appmesh.VirtualService{} },
+		newObjectList: func() client.ObjectList { return &appmesh.VirtualServiceList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.VirtualServiceList).Items {
+				objs = append(objs, &list.(*appmesh.VirtualServiceList).Items[i])
+			}
+			return objs
+		},
+	},
+	"virtualRouter": {
+		kind:          "VirtualRouter",
+		newObject:     func() client.Object { return &appmesh.VirtualRouter{} },
+		newObjectList: func() client.ObjectList { return &appmesh.VirtualRouterList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*appmesh.VirtualRouterList).Items {
+				objs = append(objs, &list.(*appmesh.VirtualRouterList).Items[i])
+			}
+			return objs
+		},
+	},
+}
+
+// kindForARN infers the indexed kind from an AppMesh ARN's resource path,
+// e.g. mesh/my-mesh/virtualRouter/my-router/route/my-route resolves to VirtualRouter.
+func kindForARN(resourceARN string) (indexedKind, error) {
+	parsedARN, err := arn.Parse(resourceARN)
+	if err != nil {
+		return indexedKind{}, err
+	}
+	if parsedARN.Service != "appmesh" {
+		return indexedKind{}, errors.Errorf("%s is not an AppMesh ARN", resourceARN)
+	}
+	segments := strings.Split(parsedARN.Resource, "/")
+	if len(segments) < 2 || len(segments)%2 != 0 || segments[0] != "mesh" {
+		return indexedKind{}, errors.Errorf("unexpected AppMesh resource path: %s", parsedARN.Resource)
+	}
+	resourceType := segments[len(segments)-2]
+	if resourceType == "route" {
+		resourceType = "virtualRouter"
+	}
+	kind, ok := indexedKinds[resourceType]
+	if !ok {
+		return indexedKind{}, errors.Errorf("unsupported AppMesh resource type: %s", resourceType)
+	}
+	return kind, nil
+}
+
+func lookup(ctx context.Context, reader client.Reader, resourceARN string, opts ...client.ListOption) (client.Object, error) {
+	kind, err := kindForARN(resourceARN)
+	if err != nil {
+		return nil, err
+	}
+	list := kind.newObjectList()
+	if err := reader.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	for _, obj := range kind.items(list) {
+		for _, objARN := range ARNsOf(obj) {
+			if objARN == resourceARN {
+				return obj, nil
+			}
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: appmesh.GroupVersion.Group, Resource: kind.kind}, resourceARN)
+}
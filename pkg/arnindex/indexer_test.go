@@ -0,0 +1,176 @@
+package arnindex
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ARNsOf(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []string
+	}{
+		{
+			name: "mesh with ARN",
+			obj: &appmesh.Mesh{
+				Status: appmesh.MeshStatus{
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+				},
+			},
+			want: []string{"arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"},
+		},
+		{
+			name: "virtualNode without ARN",
+			obj:  &appmesh.VirtualNode{},
+			want: nil,
+		},
+		{
+			name: "virtualRouter with route ARNs",
+			obj: &appmesh.VirtualRouter{
+				Status: appmesh.VirtualRouterStatus{
+					VirtualRouterARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr"),
+					RouteARNs: map[string]string{
+						"route-1": "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr/route/route-1",
+					},
+				},
+			},
+			want: []string{
+				"arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr",
+				"arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr/route/route-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ARNsOf(tt.obj)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_kindForARN(t *testing.T) {
+	tests := []struct {
+		name     string
+		arn      string
+		wantKind string
+		wantErr  bool
+	}{
+		{
+			name:     "mesh",
+			arn:      "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh",
+			wantKind: "Mesh",
+		},
+		{
+			name:     "gatewayRoute",
+			arn:      "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualGateway/my-vg/gatewayRoute/my-gr",
+			wantKind: "GatewayRoute",
+		},
+		{
+			name:     "route resolves to virtualRouter",
+			arn:      "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr/route/my-route",
+			wantKind: "VirtualRouter",
+		},
+		{
+			name:    "non-AppMesh ARN",
+			arn:     "arn:aws:s3:::my-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected resource path",
+			arn:     "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualNode",
+			wantErr: true,
+		},
+		{
+			name:    "malformed ARN",
+			arn:     "my-mesh",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kindForARN(tt.arn)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantKind, got.kind)
+			}
+		})
+	}
+}
+
+func Test_FindByARN(t *testing.T) {
+	vs := &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "my-vs",
+		},
+		Status: appmesh.VirtualServiceStatus{
+			VirtualServiceARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualService/my-vs.my-ns"),
+		},
+	}
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "my-vr",
+		},
+		Status: appmesh.VirtualRouterStatus{
+			VirtualRouterARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr_my-ns"),
+			RouteARNs: map[string]string{
+				"route-1": "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr_my-ns/route/route-1",
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		arn      string
+		wantName string
+		wantNF   bool
+	}{
+		{
+			name:     "virtualService found",
+			arn:      "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualService/my-vs.my-ns",
+			wantName: "my-vs",
+		},
+		{
+			name:     "route ARN resolves to virtualRouter",
+			arn:      "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualRouter/my-vr_my-ns/route/route-1",
+			wantName: "my-vr",
+		},
+		{
+			name:   "virtualNode not found",
+			arn:    "arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh/virtualNode/my-vn_my-ns",
+			wantNF: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			assert.NoError(t, k8sClient.Create(ctx, vs.DeepCopy()))
+			assert.NoError(t, k8sClient.Create(ctx, vr.DeepCopy()))
+
+			got, err := FindByARN(ctx, k8sClient, tt.arn)
+			if tt.wantNF {
+				assert.True(t, apierrors.IsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantName, got.GetName())
+			}
+		})
+	}
+}
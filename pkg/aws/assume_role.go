@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NamespaceIAMRoleAnnotation specifies the IAM role ARN assumed for AWS calls made on behalf of resources within a namespace.
+	NamespaceIAMRoleAnnotation = "appmesh.k8s.aws/iamRoleArn"
+
+	assumeRoleSessionName = "appmesh-controller"
+)
+
+type roleARNContextKey struct{}
+
+// WithRoleARN returns a copy of ctx, where AWS calls made with it assume roleARN.
+func WithRoleARN(ctx context.Context, roleARN string) context.Context {
+	return context.WithValue(ctx, roleARNContextKey{}, roleARN)
+}
+
+// RoleARNFromContext returns the IAM role ARN assumed for AWS calls made with ctx, if any.
+func RoleARNFromContext(ctx context.Context) (string, bool) {
+	roleARN, ok := ctx.Value(roleARNContextKey{}).(string)
+	return roleARN, ok && len(roleARN) != 0
+}
+
+// assumeRoleCredentialsInjector swaps credentials of AWS requests whose context carries an IAM role ARN
+// with credentials of that role, obtained via STS AssumeRole.
+type assumeRoleCredentialsInjector struct {
+	// stsConfigProvider must not have this injector's handlers, otherwise STS calls to assume role would recurse.
+	stsConfigProvider client.ConfigProvider
+
+	credentialsByRoleARN map[string]*credentials.Credentials
+	mutex                sync.Mutex
+}
+
+func newAssumeRoleCredentialsInjector(stsConfigProvider client.ConfigProvider) *assumeRoleCredentialsInjector {
+	return &assumeRoleCredentialsInjector{
+		stsConfigProvider:    stsConfigProvider,
+		credentialsByRoleARN: make(map[string]*credentials.Credentials),
+	}
+}
+
+// InjectHandlers will inject handlers into AWS SDK handlers.
+func (i *assumeRoleCredentialsInjector) InjectHandlers(handlers *request.Handlers) {
+	handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: fmt.Sprintf("%s/assume-role", appName),
+		Fn:   i.beforeSign,
+	})
+}
+
+func (i *assumeRoleCredentialsInjector) beforeSign(r *request.Request) {
+	roleARN, ok := RoleARNFromContext(r.Context())
+	if !ok {
+		return
+	}
+	r.Config.Credentials = i.credentialsForRole(roleARN)
+}
+
+// credentialsForRole returns cached credentials for roleARN, which are refreshed by the SDK before expiration.
+func (i *assumeRoleCredentialsInjector) credentialsForRole(roleARN string) *credentials.Credentials {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if creds, ok := i.credentialsByRoleARN[roleARN]; ok {
+		return creds
+	}
+	creds := stscreds.NewCredentials(i.stsConfigProvider, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = assumeRoleSessionName
+	})
+	i.credentialsByRoleARN[roleARN] = creds
+	return creds
+}
+
+// NamespaceRoleResolver resolves the IAM role assumed for AWS calls made on behalf of resources within a namespace.
+type NamespaceRoleResolver interface {
+	// WithNamespaceRole returns a copy of ctx, where AWS calls made with it assume the IAM role of namespace.
+	// ctx is returned as is if namespace doesn't specify an IAM role.
+	WithNamespaceRole(ctx context.Context, namespace string) (context.Context, error)
+}
+
+// NewNamespaceRoleResolver constructs new NamespaceRoleResolver. It's a no-op if not enabled.
+func NewNamespaceRoleResolver(k8sClient k8sclient.Client, enabled bool) NamespaceRoleResolver {
+	return &namespaceRoleResolver{
+		k8sClient: k8sClient,
+		enabled:   enabled,
+	}
+}
+
+var _ NamespaceRoleResolver = &namespaceRoleResolver{}
+
+// namespaceRoleResolver resolves IAM role of namespace with NamespaceIAMRoleAnnotation.
+type namespaceRoleResolver struct {
+	k8sClient k8sclient.Client
+	enabled   bool
+}
+
+func (r *namespaceRoleResolver) WithNamespaceRole(ctx context.Context, namespace string) (context.Context, error) {
+	if !r.enabled || len(namespace) == 0 {
+		return ctx, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := r.k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctx, nil
+		}
+		return nil, errors.Wrapf(err, "failed to resolve IAM role of namespace %s", namespace)
+	}
+	roleARN, ok := ns.Annotations[NamespaceIAMRoleAnnotation]
+	if !ok || len(roleARN) == 0 {
+		return ctx, nil
+	}
+	return WithRoleARN(ctx, roleARN), nil
+}
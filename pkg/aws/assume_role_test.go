@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_namespaceRoleResolver_WithNamespaceRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		namespaces  []*corev1.Namespace
+		namespace   string
+		wantRoleARN string
+	}{
+		{
+			name:    "namespace with IAM role annotation",
+			enabled: true,
+			namespaces: []*corev1.Namespace{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "tenant-a",
+						Annotations: map[string]string{
+							NamespaceIAMRoleAnnotation: "arn:aws:iam::123456789012:role/tenant-a",
+						},
+					},
+				},
+			},
+			namespace:   "tenant-a",
+			wantRoleARN: "arn:aws:iam::123456789012:role/tenant-a",
+		},
+		{
+			name:    "namespace without IAM role annotation",
+			enabled: true,
+			namespaces: []*corev1.Namespace{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"},
+				},
+			},
+			namespace:   "tenant-b",
+			wantRoleARN: "",
+		},
+		{
+			name:        "namespace not found",
+			enabled:     true,
+			namespace:   "tenant-c",
+			wantRoleARN: "",
+		},
+		{
+			name:        "cluster scoped resource",
+			enabled:     true,
+			namespace:   "",
+			wantRoleARN: "",
+		},
+		{
+			name:    "namespace IAM roles disabled",
+			enabled: false,
+			namespaces: []*corev1.Namespace{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "tenant-a",
+						Annotations: map[string]string{
+							NamespaceIAMRoleAnnotation: "arn:aws:iam::123456789012:role/tenant-a",
+						},
+					},
+				},
+			},
+			namespace:   "tenant-a",
+			wantRoleARN: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, ns := range tt.namespaces {
+				assert.NoError(t, k8sClient.Create(ctx, ns.DeepCopy()))
+			}
+
+			r := NewNamespaceRoleResolver(k8sClient, tt.enabled)
+			gotCtx, err := r.WithNamespaceRole(ctx, tt.namespace)
+			assert.NoError(t, err)
+			gotRoleARN, _ := RoleARNFromContext(gotCtx)
+			assert.Equal(t, tt.wantRoleARN, gotRoleARN)
+		})
+	}
+}
+
+func Test_assumeRoleCredentialsInjector_beforeSign(t *testing.T) {
+	defaultCreds := credentials.NewStaticCredentials("AKID", "SECRET", "")
+	sess := session.Must(session.NewSession(aws.NewConfig().WithRegion("us-west-2").WithCredentials(defaultCreds)))
+	injector := newAssumeRoleCredentialsInjector(sess)
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		wantDefaultCred bool
+	}{
+		{
+			name:            "request without IAM role keeps default credentials",
+			ctx:             context.Background(),
+			wantDefaultCred: true,
+		},
+		{
+			name:            "request with IAM role uses assumed role credentials",
+			ctx:             WithRoleARN(context.Background(), "arn:aws:iam::123456789012:role/tenant-a"),
+			wantDefaultCred: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request.New(*sess.Config, metadata.ClientInfo{ServiceName: "appmesh"}, sess.Handlers, nil, &request.Operation{Name: "DescribeMesh"}, nil, nil)
+			req.SetContext(tt.ctx)
+			injector.beforeSign(req)
+			if tt.wantDefaultCred {
+				assert.Same(t, defaultCreds, req.Config.Credentials)
+			} else {
+				assert.NotSame(t, defaultCreds, req.Config.Credentials)
+				assert.Same(t, injector.credentialsForRole("arn:aws:iam::123456789012:role/tenant-a"), req.Config.Credentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package audit
+
+import "context"
+
+type requesterContextKey struct{}
+
+type diffContextKey struct{}
+
+// Requester is the CRD on behalf of which AWS calls are made.
+type Requester struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// WithRequester returns a copy of ctx, where AWS calls made with it are audited as made on behalf of requester.
+func WithRequester(ctx context.Context, requester Requester) context.Context {
+	return context.WithValue(ctx, requesterContextKey{}, requester)
+}
+
+// RequesterFromContext returns the CRD on behalf of which AWS calls made with ctx are made, if any.
+func RequesterFromContext(ctx context.Context) (Requester, bool) {
+	requester, ok := ctx.Value(requesterContextKey{}).(Requester)
+	return requester, ok
+}
+
+// WithDiff returns a copy of ctx, where AWS calls made with it are audited with diff between the desired and actual spec.
+func WithDiff(ctx context.Context, diff string) context.Context {
+	return context.WithValue(ctx, diffContextKey{}, diff)
+}
+
+// DiffFromContext returns the diff AWS calls made with ctx are audited with, if any.
+func DiffFromContext(ctx context.Context) string {
+	diff, _ := ctx.Value(diffContextKey{}).(string)
+	return diff
+}
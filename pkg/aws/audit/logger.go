@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LogFileStdout is the audit log file writing entries to stdout, e.g. to be shipped to CloudWatch Logs along with controller logs.
+	LogFileStdout = "stdout"
+
+	sdkHandlerLogAPICall = "auditLogAPICall"
+
+	resultSucceeded = "Succeeded"
+	resultFailed    = "Failed"
+)
+
+// auditedServiceIDs are services whose mutating calls change mesh config.
+var auditedServiceIDs = map[string]bool{
+	appmesh.ServiceID:          true,
+	servicediscovery.ServiceID: true,
+	route53.ServiceID:          true,
+}
+
+// mutatingOperationPrefixes are prefixes of operations that create, update or delete AWS resources.
+var mutatingOperationPrefixes = []string{"Create", "Update", "Delete", "Register", "Deregister", "Change", "Tag", "Untag"}
+
+// Entry is an audit log entry of a mutating AWS call.
+type Entry struct {
+	Time      time.Time   `json:"time"`
+	Service   string      `json:"service"`
+	Operation string      `json:"operation"`
+	Requester *Requester  `json:"requester,omitempty"`
+	Params    interface{} `json:"params,omitempty"`
+	// Diff is the diff between the desired and actual spec of Update calls.
+	Diff         string `json:"diff,omitempty"`
+	Result       string `json:"result"`
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	RequestID    string `json:"requestID,omitempty"`
+	RetryCount   int    `json:"retryCount"`
+}
+
+// Logger writes an audit log entry for every mutating AWS call, as a line of JSON.
+type Logger struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+// NewLogger constructs new Logger writing entries to writer.
+func NewLogger(writer io.Writer) *Logger {
+	return &Logger{writer: writer}
+}
+
+// NewFileLogger constructs new Logger appending entries to file at path, or writing them to stdout if path is LogFileStdout.
+func NewFileLogger(path string) (*Logger, error) {
+	if path == LogFileStdout {
+		return NewLogger(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log file %s", path)
+	}
+	return NewLogger(file), nil
+}
+
+// InjectHandlers audits AWS calls made with handlers once they complete, after all retries.
+func (l *Logger) InjectHandlers(handlers *request.Handlers) {
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: sdkHandlerLogAPICall,
+		Fn:   l.logAPICall,
+	})
+}
+
+func (l *Logger) logAPICall(r *request.Request) {
+	if r.Operation == nil || !auditedServiceIDs[r.ClientInfo.ServiceID] || !isMutatingOperation(r.Operation.Name) {
+		return
+	}
+	entry := Entry{
+		Time:       time.Now().UTC(),
+		Service:    r.ClientInfo.ServiceID,
+		Operation:  r.Operation.Name,
+		Params:     paramsForRequest(r),
+		Diff:       DiffFromContext(r.Context()),
+		Result:     resultSucceeded,
+		RequestID:  r.RequestID,
+		RetryCount: r.RetryCount,
+	}
+	if requester, ok := RequesterFromContext(r.Context()); ok {
+		entry.Requester = &requester
+	}
+	if r.Error != nil {
+		entry.Result = resultFailed
+		entry.ErrorCode = "internal"
+		if awsErr, ok := r.Error.(awserr.Error); ok {
+			entry.ErrorCode = awsErr.Code()
+		}
+		entry.ErrorMessage = r.Error.Error()
+	}
+	l.write(entry)
+}
+
+func (l *Logger) write(entry Entry) {
+	line, _ := json.Marshal(entry)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.writer.Write(append(line, '\n'))
+}
+
+func isMutatingOperation(operation string) bool {
+	for _, prefix := range mutatingOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// paramsForRequest returns the input of request without unset fields, which SDK inputs marshal as nulls.
+// it returns nil if the input cannot be marshaled, so the entry is written without it.
+func paramsForRequest(r *request.Request) interface{} {
+	rawParams, err := json.Marshal(r.Params)
+	if err != nil {
+		return nil
+	}
+	var params interface{}
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return nil
+	}
+	return withoutNulls(params)
+}
+
+func withoutNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			if fieldValue == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = withoutNulls(fieldValue)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = withoutNulls(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRequest(ctx context.Context, serviceID string, operation string, params interface{}, err error) *request.Request {
+	httpReq, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	r := &request.Request{
+		ClientInfo:  metadata.ClientInfo{ServiceID: serviceID},
+		Operation:   &request.Operation{Name: operation},
+		Params:      params,
+		HTTPRequest: httpReq,
+		Error:       err,
+		RequestID:   "request-id",
+	}
+	r.SetContext(ctx)
+	return r
+}
+
+func Test_Logger_logAPICall(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   *request.Request
+		wantEntry *Entry
+	}{
+		{
+			name: "succeeded update",
+			request: newTestRequest(
+				WithDiff(WithRequester(context.Background(), Requester{Kind: "VirtualNode", Namespace: "ns-1", Name: "vn-1"}), "-desired +actual"),
+				appmesh.ServiceID, "UpdateVirtualNode", &appmesh.UpdateVirtualNodeInput{VirtualNodeName: aws.String("vn-1_ns-1")}, nil),
+			wantEntry: &Entry{
+				Service:    appmesh.ServiceID,
+				Operation:  "UpdateVirtualNode",
+				Requester:  &Requester{Kind: "VirtualNode", Namespace: "ns-1", Name: "vn-1"},
+				Params:     map[string]interface{}{"VirtualNodeName": "vn-1_ns-1"},
+				Diff:       "-desired +actual",
+				Result:     resultSucceeded,
+				RequestID:  "request-id",
+				RetryCount: 0,
+			},
+		},
+		{
+			name: "failed delete",
+			request: newTestRequest(context.Background(), appmesh.ServiceID, "DeleteMesh", &appmesh.DeleteMeshInput{MeshName: aws.String("mesh-1")},
+				awserr.New(appmesh.ErrCodeResourceInUseException, "mesh in use", nil)),
+			wantEntry: &Entry{
+				Service:      appmesh.ServiceID,
+				Operation:    "DeleteMesh",
+				Params:       map[string]interface{}{"MeshName": "mesh-1"},
+				Result:       resultFailed,
+				ErrorCode:    appmesh.ErrCodeResourceInUseException,
+				ErrorMessage: "ResourceInUseException: mesh in use",
+				RequestID:    "request-id",
+			},
+		},
+		{
+			name: "failed without aws error",
+			request: newTestRequest(context.Background(), appmesh.ServiceID, "CreateMesh", &appmesh.CreateMeshInput{MeshName: aws.String("mesh-1")},
+				errors.New("connection refused")),
+			wantEntry: &Entry{
+				Service:      appmesh.ServiceID,
+				Operation:    "CreateMesh",
+				Params:       map[string]interface{}{"MeshName": "mesh-1"},
+				Result:       resultFailed,
+				ErrorCode:    "internal",
+				ErrorMessage: "connection refused",
+				RequestID:    "request-id",
+			},
+		},
+		{
+			name:      "read operation isn't audited",
+			request:   newTestRequest(context.Background(), appmesh.ServiceID, "DescribeMesh", &appmesh.DescribeMeshInput{}, nil),
+			wantEntry: nil,
+		},
+		{
+			name:      "unaudited service",
+			request:   newTestRequest(context.Background(), sqs.ServiceID, "DeleteMessage", &sqs.DeleteMessageInput{}, nil),
+			wantEntry: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			NewLogger(buf).logAPICall(tt.request)
+			if tt.wantEntry == nil {
+				assert.Empty(t, buf.String())
+				return
+			}
+			gotEntry := Entry{}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &gotEntry))
+			assert.False(t, gotEntry.Time.IsZero())
+			gotEntry.Time = tt.wantEntry.Time
+			assert.Equal(t, *tt.wantEntry, gotEntry)
+		})
+	}
+}
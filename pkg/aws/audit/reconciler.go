@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler wraps reconciler of CRDs of kind, so AWS calls made during each reconcile are audited as made on behalf of the reconciled CRD.
+func NewReconciler(kind string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		ctx = WithRequester(ctx, Requester{Kind: kind, Namespace: req.Namespace, Name: req.Name})
+		return reconciler.Reconcile(ctx, req)
+	})
+}
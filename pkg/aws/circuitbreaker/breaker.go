@@ -0,0 +1,226 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	sdkHandlerCircuitBreakerAllow  = "circuitBreakerAllow"
+	sdkHandlerCircuitBreakerRecord = "circuitBreakerRecord"
+
+	// ErrCodeOpen is the error code of calls rejected while the circuit breaker is open.
+	ErrCodeOpen = "CircuitBreakerOpen"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets calls through.
+	StateClosed State = iota
+	// StateOpen rejects calls until the cool-down elapses.
+	StateOpen
+	// StateHalfOpen lets a single probing call through, which closes the breaker if it succeeds, or opens it again otherwise.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// NewBreaker constructs new circuit breaker of calls to serviceID,
+// which opens after threshold consecutive calls failed with 5xx status codes or timed out, and rejects calls for cooldown.
+// metrics of the breaker are registered to registerer if it's not nil.
+func NewBreaker(serviceID string, threshold int, cooldown time.Duration, registerer prometheus.Registerer) (*breaker, error) {
+	b := &breaker{
+		serviceID: serviceID,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+	if registerer != nil {
+		instruments, err := newInstruments(registerer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize circuit breaker metrics")
+		}
+		b.instruments = instruments
+		b.instruments.state.WithLabelValues(serviceID).Set(float64(StateClosed))
+	}
+	return b, nil
+}
+
+type breaker struct {
+	serviceID   string
+	threshold   int
+	cooldown    time.Duration
+	now         func() time.Time
+	instruments *instruments
+
+	mutex               sync.Mutex
+	state               State
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+}
+
+func (b *breaker) InjectHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerCircuitBreakerAllow,
+		Fn:   b.beforeValidate,
+	})
+	handlers.Complete.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerCircuitBreakerRecord,
+		Fn:   b.afterComplete,
+	})
+}
+
+// beforeValidate is added to the Validate chain; rejects calls while the breaker is open.
+func (b *breaker) beforeValidate(r *request.Request) {
+	if r.ClientInfo.ServiceID != b.serviceID {
+		return
+	}
+	if retryAfter, ok := b.allow(); !ok {
+		r.Error = NewOpenError(b.serviceID, b.threshold, b.cooldown, retryAfter)
+		if b.instruments != nil {
+			b.instruments.rejectedCallsTotal.WithLabelValues(b.serviceID).Inc()
+		}
+	}
+}
+
+// afterComplete is added to the Complete chain; records the outcome of calls let through.
+func (b *breaker) afterComplete(r *request.Request) {
+	if r.ClientInfo.ServiceID != b.serviceID || IsOpenError(r.Error) {
+		return
+	}
+	// calls canceled by their callers tell nothing about the service.
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		return
+	}
+	b.record(isFailure(r))
+}
+
+// allow returns whether a call is let through, or how long until the breaker lets calls through again.
+func (b *breaker) allow() (time.Duration, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case StateOpen:
+		if elapsed := b.now().Sub(b.openedAt); elapsed < b.cooldown {
+			return b.cooldown - elapsed, false
+		}
+		b.setState(StateHalfOpen)
+		b.probing = true
+		return 0, true
+	case StateHalfOpen:
+		if b.probing {
+			return b.cooldown, false
+		}
+		b.probing = true
+		return 0, true
+	default:
+		return 0, true
+	}
+}
+
+func (b *breaker) record(failure bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !failure {
+		b.consecutiveFailures = 0
+		b.probing = false
+		b.setState(StateClosed)
+		return
+	}
+	b.consecutiveFailures++
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.consecutiveFailures >= b.threshold) {
+		b.probing = false
+		b.openedAt = b.now()
+		b.setState(StateOpen)
+		if b.instruments != nil {
+			b.instruments.opensTotal.WithLabelValues(b.serviceID).Inc()
+		}
+	}
+}
+
+func (b *breaker) setState(state State) {
+	b.state = state
+	if b.instruments != nil {
+		b.instruments.state.WithLabelValues(b.serviceID).Set(float64(state))
+	}
+}
+
+// isFailure tests whether call r failed with a 5xx status code or timed out.
+func isFailure(r *request.Request) bool {
+	if r.Error == nil {
+		return false
+	}
+	if r.HTTPResponse != nil && r.HTTPResponse.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	return errors.Is(r.Context().Err(), context.DeadlineExceeded)
+}
+
+// NewOpenError constructs new OpenError of a call to serviceID rejected by a circuit breaker,
+// which opened after threshold consecutive failures for cooldown, and lets calls through again after retryAfter.
+func NewOpenError(serviceID string, threshold int, cooldown time.Duration, retryAfter time.Duration) *OpenError {
+	return &OpenError{
+		serviceID:  serviceID,
+		threshold:  threshold,
+		cooldown:   cooldown,
+		retryAfter: retryAfter,
+	}
+}
+
+var _ awserr.Error = &OpenError{}
+
+// OpenError is the error of calls rejected while the circuit breaker is open.
+type OpenError struct {
+	serviceID  string
+	threshold  int
+	cooldown   time.Duration
+	retryAfter time.Duration
+}
+
+func (e *OpenError) Code() string {
+	return ErrCodeOpen
+}
+
+// Message is stable across rejected calls, so it can be recorded in status conditions.
+func (e *OpenError) Message() string {
+	return fmt.Sprintf("%s calls are rejected for %v after %d consecutive failures", e.serviceID, e.cooldown, e.threshold)
+}
+
+func (e *OpenError) OrigErr() error {
+	return nil
+}
+
+func (e *OpenError) Error() string {
+	return awserr.SprintError(e.Code(), e.Message(), "", nil)
+}
+
+// RetryAfter is how long until the circuit breaker lets calls through again.
+func (e *OpenError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// IsOpenError tests whether err is, or wraps, an error of a call rejected while the circuit breaker is open.
+func IsOpenError(err error) bool {
+	var openErr *OpenError
+	return errors.As(err, &openErr)
+}
@@ -0,0 +1,174 @@
+package circuitbreaker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRequest(serviceID string, statusCode int, err error) *request.Request {
+	r := &request.Request{
+		ClientInfo:   metadata.ClientInfo{ServiceID: serviceID},
+		HTTPRequest:  &http.Request{},
+		HTTPResponse: &http.Response{StatusCode: statusCode},
+		Error:        err,
+	}
+	r.SetContext(context.Background())
+	return r
+}
+
+// call makes a call through breaker b, which completes with statusCode and err if it's let through.
+// returns the error of the call.
+func call(b *breaker, statusCode int, err error) error {
+	r := newTestRequest(appmesh.ServiceID, 0, nil)
+	b.beforeValidate(r)
+	if r.Error == nil {
+		r.HTTPResponse.StatusCode = statusCode
+		r.Error = err
+	}
+	b.afterComplete(r)
+	return r.Error
+}
+
+func Test_breaker(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	b, err := NewBreaker(appmesh.ServiceID, 3, 30*time.Second, registry)
+	assert.NoError(t, err)
+	now := time.Unix(1600000000, 0)
+	b.now = func() time.Time { return now }
+	unavailableErr := awserr.New("ServiceUnavailableException", "unavailable", nil)
+	notFoundErr := awserr.New(appmesh.ErrCodeNotFoundException, "not found", nil)
+
+	// failures that aren't consecutive don't open the breaker.
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, notFoundErr, call(b, http.StatusNotFound, notFoundErr))
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, StateClosed, b.state)
+
+	// threshold consecutive failures open the breaker.
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, StateOpen, b.state)
+	now = now.Add(10 * time.Second)
+	err = call(b, http.StatusOK, nil)
+	assert.True(t, IsOpenError(err))
+	assert.EqualError(t, err, "CircuitBreakerOpen: App Mesh calls are rejected for 30s after 3 consecutive failures")
+	assert.Equal(t, 20*time.Second, err.(*OpenError).RetryAfter())
+
+	// a failed probe opens the breaker again.
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, unavailableErr, call(b, http.StatusServiceUnavailable, unavailableErr))
+	assert.Equal(t, StateOpen, b.state)
+	assert.True(t, IsOpenError(call(b, http.StatusOK, nil)))
+
+	// a successful probe closes the breaker.
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, call(b, http.StatusOK, nil))
+	assert.Equal(t, StateClosed, b.state)
+	assert.NoError(t, call(b, http.StatusOK, nil))
+
+	assert.Equal(t, float64(StateClosed), testutil.ToFloat64(b.instruments.state.WithLabelValues(appmesh.ServiceID)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(b.instruments.opensTotal.WithLabelValues(appmesh.ServiceID)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(b.instruments.rejectedCallsTotal.WithLabelValues(appmesh.ServiceID)))
+}
+
+func Test_breaker_halfOpen(t *testing.T) {
+	b, err := NewBreaker(appmesh.ServiceID, 1, 30*time.Second, nil)
+	assert.NoError(t, err)
+	now := time.Unix(1600000000, 0)
+	b.now = func() time.Time { return now }
+	b.record(true)
+	now = now.Add(30 * time.Second)
+
+	probe := newTestRequest(appmesh.ServiceID, 0, nil)
+	b.beforeValidate(probe)
+	assert.NoError(t, probe.Error)
+	assert.Equal(t, StateHalfOpen, b.state)
+
+	// calls are rejected while the probe is in flight.
+	other := newTestRequest(appmesh.ServiceID, 0, nil)
+	b.beforeValidate(other)
+	assert.True(t, IsOpenError(other.Error))
+	b.afterComplete(other)
+	assert.Equal(t, StateHalfOpen, b.state)
+
+	b.afterComplete(probe)
+	assert.Equal(t, StateClosed, b.state)
+}
+
+func Test_breaker_afterComplete(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	timedOutCtx, cancelTimedOut := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelTimedOut()
+	requestErr := awserr.New(request.CanceledErrorCode, "request context canceled", nil)
+
+	tests := []struct {
+		name                    string
+		serviceID               string
+		statusCode              int
+		err                     error
+		ctx                     context.Context
+		wantConsecutiveFailures int
+	}{
+		{
+			name:                    "call failed with 5xx status code",
+			serviceID:               appmesh.ServiceID,
+			statusCode:              http.StatusInternalServerError,
+			err:                     awserr.New("InternalServerErrorException", "oops", nil),
+			wantConsecutiveFailures: 2,
+		},
+		{
+			name:                    "call timed out",
+			serviceID:               appmesh.ServiceID,
+			err:                     requestErr,
+			ctx:                     timedOutCtx,
+			wantConsecutiveFailures: 2,
+		},
+		{
+			name:                    "call canceled by caller",
+			serviceID:               appmesh.ServiceID,
+			err:                     requestErr,
+			ctx:                     canceledCtx,
+			wantConsecutiveFailures: 1,
+		},
+		{
+			name:                    "call failed with 4xx status code",
+			serviceID:               appmesh.ServiceID,
+			statusCode:              http.StatusBadRequest,
+			err:                     errors.New("bad request"),
+			wantConsecutiveFailures: 0,
+		},
+		{
+			name:                    "call to other service",
+			serviceID:               "ServiceDiscovery",
+			statusCode:              http.StatusInternalServerError,
+			err:                     awserr.New("InternalServerErrorException", "oops", nil),
+			wantConsecutiveFailures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBreaker(appmesh.ServiceID, 5, 30*time.Second, nil)
+			assert.NoError(t, err)
+			b.record(true)
+			r := newTestRequest(tt.serviceID, tt.statusCode, tt.err)
+			if tt.ctx != nil {
+				r.SetContext(tt.ctx)
+			}
+			b.afterComplete(r)
+			assert.Equal(t, tt.wantConsecutiveFailures, b.consecutiveFailures)
+		})
+	}
+}
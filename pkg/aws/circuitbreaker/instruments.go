@@ -0,0 +1,57 @@
+package circuitbreaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricSubsystemAWS = "aws"
+
+	metricCircuitBreakerState         = "circuit_breaker_state"
+	metricCircuitBreakerOpensTotal    = "circuit_breaker_opens_total"
+	metricCircuitBreakerRejectedTotal = "circuit_breaker_rejected_calls_total"
+)
+
+const (
+	labelService = "service"
+)
+
+type instruments struct {
+	state              *prometheus.GaugeVec
+	opensTotal         *prometheus.CounterVec
+	rejectedCallsTotal *prometheus.CounterVec
+}
+
+// newInstruments allocates and register new metrics to registerer
+func newInstruments(registerer prometheus.Registerer) (*instruments, error) {
+	state := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricSubsystemAWS,
+		Name:      metricCircuitBreakerState,
+		Help:      "State of the circuit breaker of calls to AWS services, 0 is closed, 1 is open and 2 is half-open",
+	}, []string{labelService})
+	opensTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricSubsystemAWS,
+		Name:      metricCircuitBreakerOpensTotal,
+		Help:      "Total number of times the circuit breaker of calls to AWS services opened",
+	}, []string{labelService})
+	rejectedCallsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricSubsystemAWS,
+		Name:      metricCircuitBreakerRejectedTotal,
+		Help:      "Total number of calls to AWS services rejected while the circuit breaker is open",
+	}, []string{labelService})
+
+	if err := registerer.Register(state); err != nil {
+		return nil, err
+	}
+	if err := registerer.Register(opensTotal); err != nil {
+		return nil, err
+	}
+	if err := registerer.Register(rejectedCallsTotal); err != nil {
+		return nil, err
+	}
+	return &instruments{
+		state:              state,
+		opensTotal:         opensTotal,
+		rejectedCallsTotal: rejectedCallsTotal,
+	}, nil
+}
@@ -1,24 +1,40 @@
 package aws
 
 import (
+	"bytes"
 	"context"
-	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/metrics"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
-	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/throttle"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"os"
 )
 
 type Cloud interface {
 	// AppMesh provides API to AWS AppMesh
 	AppMesh() services.AppMesh
+	// AppMeshCache provides the cache of AppMesh API responses
+	AppMeshCache() services.AppMeshCache
+	// AppMeshForRegion provides API to AWS AppMesh in region, at endpoint if it's not empty
+	AppMeshForRegion(region string, endpoint string) services.AppMesh
 	// CloudMap provides API to AWS CloudMap
 	CloudMap() services.CloudMap
 	//EKS provides API to AWS EKS
 	EKS() services.EKS
+	// SSM provides API to AWS Systems Manager
+	SSM() services.SSM
+	// SQS provides API to AWS SQS
+	SQS() services.SQS
+	// Route53 provides API to AWS Route53
+	Route53() services.Route53
+	// ServiceQuotas provides API to AWS Service Quotas
+	ServiceQuotas() services.ServiceQuotas
+	// S3 provides API to AWS S3
+	S3() services.S3
 
 	// AccountID provides AccountID for the kubernetes cluster
 	AccountID() string
@@ -28,22 +44,34 @@ type Cloud interface {
 }
 
 // NewCloud constructs new Cloud implementation.
-func NewCloud(cfg CloudConfig, metricsRegisterer prometheus.Registerer) (Cloud, error) {
-	sess := session.Must(session.NewSession(aws.NewConfig()))
-	// creating separate config for AppMesh because it has both DualStack and FIPS endpoint, But for other AWS APIs services EKS and CloudMap DualStack endpoints(DNS ending in api.aws) are unavailable.
-	sessAppMesh := session.Must(session.NewSession(aws.NewConfig()))
-	injectUserAgent(&sess.Handlers)
-	if cfg.ThrottleConfig != nil {
-		throttler := throttle.NewThrottler(cfg.ThrottleConfig)
-		throttler.InjectHandlers(&sess.Handlers)
-	}
-	if metricsRegisterer != nil {
-		metricsCollector, err := metrics.NewCollector(metricsRegisterer)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to initialize sdk metrics collector")
-		}
-		metricsCollector.InjectHandlers(&sess.Handlers)
+// customMiddlewares are injected after the middlewares configured by cfg, so they observe calls throttled and bounded by timeouts.
+// In local mode, AppMesh is served by in-memory fakes instead, and no AWS credentials are needed.
+// In mock mode, AppMesh and CloudMap calls are sent to the appmesh-mock server instead, and no AWS credentials are needed either.
+func NewCloud(cfg CloudConfig, metricsRegisterer prometheus.Registerer, customMiddlewares ...Middleware) (Cloud, error) {
+	if cfg.LocalMode {
+		return newLocalCloud(cfg)
+	}
+	if len(cfg.MockEndpoint) != 0 {
+		return newMockCloud(cfg, metricsRegisterer, customMiddlewares...)
+	}
+	endpointResolver, err := newCustomEndpointResolver(cfg.APIEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := newSession(cfg, endpointResolver)
+	if err != nil {
+		return nil, err
 	}
+	middlewares, err := defaultMiddlewares(cfg, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	for _, middleware := range append(middlewares, customMiddlewares...) {
+		middleware.InjectHandlers(&sess.Handlers)
+	}
+	// creating separate config for AppMesh because it has both DualStack and FIPS endpoint, But for other AWS APIs services EKS and CloudMap DualStack endpoints(DNS ending in api.aws) are unavailable.
+	// it's copied after middlewares are injected, so AppMesh calls are throttled, instrumented and audited as well.
+	sessAppMesh := sess.Copy()
 
 	if len(cfg.Region) == 0 {
 		metadata := services.NewEC2Metadata(sess)
@@ -54,49 +82,135 @@ func NewCloud(cfg CloudConfig, metricsRegisterer prometheus.Registerer) (Cloud,
 		cfg.Region = region
 	}
 
+	stsCfg := cfg.stsConfig()
+	webIdentityCreds, err := newWebIdentityCredentials(sess, stsCfg, cfg.WebIdentityTokenFile)
+	if err != nil {
+		return nil, err
+	}
 	awsCfgAppMesh := &aws.Config{
 		Region:               aws.String(cfg.Region),
+		Credentials:          webIdentityCreds,
 		UseDualStackEndpoint: endpoints.DualStackEndpointState(cfg.GetAwsDualStackEndpoint()),
 		UseFIPSEndpoint:      endpoints.FIPSEndpointState(cfg.GetAwsFIPSEndpoint()),
-		STSRegionalEndpoint:  1,
+		STSRegionalEndpoint:  stsCfg.STSRegionalEndpoint,
 	}
 	awsCfg := &aws.Config{
 		Region:              aws.String(cfg.Region),
+		Credentials:         webIdentityCreds,
 		UseFIPSEndpoint:     endpoints.FIPSEndpointState(cfg.GetAwsFIPSEndpoint()),
-		STSRegionalEndpoint: 1,
+		STSRegionalEndpoint: stsCfg.STSRegionalEndpoint,
 	}
 	sess = sess.Copy(awsCfg)
 	sessAppMesh = sessAppMesh.Copy(awsCfgAppMesh)
+	if cfg.EnableNamespaceIAMRoles {
+		assumeRoleInjector := newAssumeRoleCredentialsInjector(sess.Copy(stsCfg))
+		assumeRoleInjector.InjectHandlers(&sess.Handlers)
+		assumeRoleInjector.InjectHandlers(&sessAppMesh.Handlers)
+	}
 	if len(cfg.AccountID) == 0 {
-		sts := services.NewSTS(sess)
+		sts := services.NewSTS(sess.Copy(stsCfg))
 		accountID, err := sts.AccountID(context.Background())
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to introspect accountID from STS, specify --aws-account-id instead if STS is unavailable")
 		}
 		cfg.AccountID = accountID
 	}
+	appMesh, appMeshCache, err := newRegionalAppMesh(cfg, sessAppMesh, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
 	return &defaultCloud{
-		cfg:      cfg,
-		appMesh:  services.NewAppMesh(sessAppMesh),
-		cloudMap: services.NewCloudMap(sess),
-		eks:      services.NewEKS(sess),
+		cfg:           cfg,
+		sessAppMesh:   sessAppMesh,
+		appMesh:       appMesh,
+		appMeshCache:  appMeshCache,
+		cloudMap:      services.NewCloudMap(sess),
+		eks:           services.NewEKS(sess),
+		ssm:           services.NewSSM(sess),
+		sqs:           services.NewSQS(sess),
+		route53:       services.NewRoute53(sess),
+		serviceQuotas: services.NewServiceQuotas(sess),
+		s3:            services.NewS3(sess),
 	}, nil
 }
 
+// newRegionalAppMesh constructs the AppMesh client of cfg.Region from sessAppMesh, along with its cache.
+func newRegionalAppMesh(cfg CloudConfig, sessAppMesh *session.Session, metricsRegisterer prometheus.Registerer) (services.AppMesh, services.AppMeshCache, error) {
+	// the circuit breaker is injected into the AppMesh client of cfg.Region only, AppMesh clients of other regions fail independently.
+	sessRegionalAppMesh := sessAppMesh
+	if cfg.AppMeshCircuitBreakerThreshold > 0 {
+		breaker, err := circuitbreaker.NewBreaker(appmesh.ServiceID, cfg.AppMeshCircuitBreakerThreshold, cfg.AppMeshCircuitBreakerCooldown, metricsRegisterer)
+		if err != nil {
+			return nil, nil, err
+		}
+		sessRegionalAppMesh = sessAppMesh.Copy()
+		breaker.InjectHandlers(&sessRegionalAppMesh.Handlers)
+	}
+	var appMesh services.AppMesh = services.NewAppMesh(sessRegionalAppMesh)
+	if cfg.AppMeshWaitForActiveTimeout > 0 {
+		appMesh = services.NewWaitForActiveAppMesh(appMesh, cfg.AppMeshWaitForActiveTimeout)
+	}
+	appMeshCache := services.NewNoopAppMeshCache()
+	if cfg.AppMeshAPICacheTTL > 0 {
+		cachedAppMesh := services.NewCachedAppMesh(appMesh, cfg.AppMeshAPICacheTTL)
+		appMesh, appMeshCache = cachedAppMesh, cachedAppMesh
+	}
+	return appMesh, appMeshCache, nil
+}
+
+// newSession constructs new session that resolves endpoints with endpointResolver and trusts the custom CA bundle if specified.
+func newSession(cfg CloudConfig, endpointResolver endpoints.Resolver) (*session.Session, error) {
+	opts := session.Options{
+		Config: *aws.NewConfig().WithEndpointResolver(endpointResolver),
+	}
+	if len(cfg.CABundle) != 0 {
+		caBundle, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA bundle %s", cfg.CABundle)
+		}
+		opts.CustomCABundle = bytes.NewReader(caBundle)
+	}
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	return sess, nil
+}
+
 var _ Cloud = &defaultCloud{}
 
 type defaultCloud struct {
 	cfg CloudConfig
+	// sessAppMesh is the session AppMesh clients of other regions are derived from.
+	sessAppMesh *session.Session
 
-	appMesh  services.AppMesh
-	cloudMap services.CloudMap
-	eks      services.EKS
+	appMesh       services.AppMesh
+	appMeshCache  services.AppMeshCache
+	cloudMap      services.CloudMap
+	eks           services.EKS
+	ssm           services.SSM
+	sqs           services.SQS
+	route53       services.Route53
+	serviceQuotas services.ServiceQuotas
+	s3            services.S3
 }
 
 func (c *defaultCloud) AppMesh() services.AppMesh {
 	return c.appMesh
 }
 
+func (c *defaultCloud) AppMeshCache() services.AppMeshCache {
+	return c.appMeshCache
+}
+
+func (c *defaultCloud) AppMeshForRegion(region string, endpoint string) services.AppMesh {
+	awsCfg := &aws.Config{Region: aws.String(region)}
+	if len(endpoint) != 0 {
+		awsCfg.Endpoint = aws.String(endpoint)
+	}
+	return services.NewAppMesh(c.sessAppMesh.Copy(awsCfg))
+}
+
 func (c *defaultCloud) CloudMap() services.CloudMap {
 	return c.cloudMap
 }
@@ -105,6 +219,26 @@ func (c *defaultCloud) EKS() services.EKS {
 	return c.eks
 }
 
+func (c *defaultCloud) SSM() services.SSM {
+	return c.ssm
+}
+
+func (c *defaultCloud) SQS() services.SQS {
+	return c.sqs
+}
+
+func (c *defaultCloud) Route53() services.Route53 {
+	return c.route53
+}
+
+func (c *defaultCloud) ServiceQuotas() services.ServiceQuotas {
+	return c.serviceQuotas
+}
+
+func (c *defaultCloud) S3() services.S3 {
+	return c.s3
+}
+
 func (c *defaultCloud) AccountID() string {
 	return c.cfg.AccountID
 }
@@ -2,11 +2,17 @@ package aws
 
 import (
 	"fmt"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/timeout"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,6 +21,20 @@ const (
 	flagAWSAPIThrottle          = "aws-api-throttle"
 	flagUseAwsFipsEndpoint      = "use-aws-fips-endpoint"
 	flagUseAwsDualStackEndpoint = "use-aws-dual-stack-endpoint"
+	flagAWSAPIEndpoints         = "aws-api-endpoints"
+	flagAWSCABundle             = "aws-ca-bundle"
+	flagEnableNamespaceIAMRoles = "enable-namespace-iam-roles"
+	flagAppMeshAPICacheTTL      = "appmesh-api-cache-ttl"
+	flagAppMeshWaitForActive    = "appmesh-wait-for-active-timeout"
+	flagAWSLocalMode            = "aws-local-mode"
+	flagAWSMockEndpoint         = "aws-mock-endpoint"
+	flagAWSAuditLogFile         = "aws-audit-log-file"
+	flagAWSSTSRegionalEndpoints = "aws-sts-regional-endpoints"
+	flagUseAwsDualStackSTS      = "use-aws-dual-stack-sts-endpoint"
+	flagAWSWebIdentityTokenFile = "aws-web-identity-token-file"
+	flagAWSAPITimeouts          = "aws-api-timeouts"
+	flagAppMeshCircuitThreshold = "appmesh-circuit-breaker-threshold"
+	flagAppMeshCircuitCooldown  = "appmesh-circuit-breaker-cooldown"
 )
 
 type CloudConfig struct {
@@ -24,18 +44,87 @@ type CloudConfig struct {
 	AccountID string
 	// Throttle settings for aws APIs
 	ThrottleConfig *throttle.ServiceOperationsThrottleConfig
+	// Timeout settings for aws API calls, including their retries
+	TimeoutConfig *timeout.ServiceOperationsTimeoutConfig
 	// DualStackEndpoint flag for aws APIs
 	UseAwsDualStackEndpoint bool
 	// FipsEndpoint flag for aws APIs
 	UseAwsFIPSEndpoint bool
+	// Custom endpoint URLs for aws APIs, keyed by service endpointsID
+	APIEndpoints map[string]string
+	// Path to custom CA bundle used to verify aws API endpoints
+	CABundle string
+	// Whether to assume IAM roles specified by namespaces for aws APIs
+	EnableNamespaceIAMRoles bool
+	// How long responses of AppMesh Describe and List calls are cached, caching is disabled if it's 0
+	AppMeshAPICacheTTL time.Duration
+	// How long created AppMesh resources are polled until they're ACTIVE, polling is disabled if it's 0
+	AppMeshWaitForActiveTimeout time.Duration
+	// Whether AppMesh is served by in-memory fakes instead of AWS, for testing without AWS credentials
+	LocalMode bool
+	// URL of the appmesh-mock server AppMesh and CloudMap calls are sent to instead of AWS, for testing without AWS credentials
+	MockEndpoint string
+	// Path of file audit log entries of mutating aws calls are appended to, or stdout. auditing is disabled if it's empty
+	AuditLogFile string
+	// Whether STS calls are made to the regional endpoint of Region ("regional") or the global endpoint ("legacy")
+	STSRegionalEndpoints string
+	// DualStackEndpoint flag for STS, which assumes the IAM role for service accounts (IRSA) and namespace IAM roles
+	UseAwsDualStackSTSEndpoint bool
+	// Path of the web identity token of IRSA, overrides AWS_WEB_IDENTITY_TOKEN_FILE if it's not empty
+	WebIdentityTokenFile string
+	// Number of consecutive AppMesh calls failed with 5xx status codes or timed out that open the circuit breaker, it's disabled if it's 0
+	AppMeshCircuitBreakerThreshold int
+	// How long AppMesh calls are rejected once the circuit breaker opens
+	AppMeshCircuitBreakerCooldown time.Duration
 }
 
 func (cfg *CloudConfig) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&cfg.Region, flagAWSRegion, "", "AWS Region for the kubernetes cluster")
 	fs.StringVar(&cfg.AccountID, flagAWSAccountID, "", "AWS AccountID for the kubernetes cluster")
 	fs.Var(cfg.ThrottleConfig, flagAWSAPIThrottle, "throttle settings for AWS APIs, format: serviceID1:operationRegex1=rate:burst,serviceID2:operationRegex2=rate:burst")
+	fs.Var(cfg.TimeoutConfig, flagAWSAPITimeouts, "timeouts of AWS API calls including their retries, format: serviceID1:operationRegex1=timeout,serviceID2:operationRegex2=timeout, e.g. App Mesh:^Describe=10s")
 	fs.BoolVar(&cfg.UseAwsFIPSEndpoint, flagUseAwsFipsEndpoint, false, "To use FIPS Endpoint for AWS Services")
 	fs.BoolVar(&cfg.UseAwsDualStackEndpoint, flagUseAwsDualStackEndpoint, false, "To use Dual Stack Endpoint for AWS Services")
+	fs.StringToStringVar(&cfg.APIEndpoints, flagAWSAPIEndpoints, nil, "custom endpoint URLs for AWS APIs, format: serviceID1=URL1,serviceID2=URL2, e.g. appmesh=https://appmesh.vpce.example.com,servicediscovery=https://servicediscovery.vpce.example.com,sts=https://sts.vpce.example.com")
+	fs.StringVar(&cfg.CABundle, flagAWSCABundle, "", "Path to PEM encoded CA bundle used to verify AWS API endpoints")
+	fs.BoolVar(&cfg.EnableNamespaceIAMRoles, flagEnableNamespaceIAMRoles, false, "If enabled, AWS calls for resources within a namespace assume the IAM role specified by namespace annotation "+NamespaceIAMRoleAnnotation)
+	fs.DurationVar(&cfg.AppMeshAPICacheTTL, flagAppMeshAPICacheTTL, 0, "How long responses of AppMesh Describe and List calls are cached, invalidated upon mutating calls. Set to 0 to disable")
+	fs.DurationVar(&cfg.AppMeshWaitForActiveTimeout, flagAppMeshWaitForActive, 0, "How long created AppMesh resources are polled until they're ACTIVE before their CRDs are marked Ready. Set to 0 to disable")
+	fs.BoolVar(&cfg.LocalMode, flagAWSLocalMode, false, "If enabled, AppMesh is served by in-memory fakes instead of AWS and other AWS APIs are unavailable, for testing without AWS credentials")
+	fs.StringVar(&cfg.MockEndpoint, flagAWSMockEndpoint, "", "URL of the appmesh-mock server AppMesh and CloudMap calls are sent to instead of AWS, other AWS APIs are unavailable, for testing without AWS credentials")
+	fs.StringVar(&cfg.STSRegionalEndpoints, flagAWSSTSRegionalEndpoints, "legacy", "Whether STS calls, e.g. to assume the IAM role for service accounts, are made to the regional endpoint of the AWS Region (regional) or the global endpoint (legacy)")
+	fs.BoolVar(&cfg.UseAwsDualStackSTSEndpoint, flagUseAwsDualStackSTS, false, "To use Dual Stack Endpoint for AWS STS")
+	fs.StringVar(&cfg.WebIdentityTokenFile, flagAWSWebIdentityTokenFile, "", "Path of the web identity token the IAM role for service accounts is assumed with, overrides AWS_WEB_IDENTITY_TOKEN_FILE")
+	fs.IntVar(&cfg.AppMeshCircuitBreakerThreshold, flagAppMeshCircuitThreshold, 0, "Number of consecutive AppMesh calls failed with 5xx status codes or timed out after which AppMesh calls are rejected for the cool-down. Set to 0 to disable")
+	fs.DurationVar(&cfg.AppMeshCircuitBreakerCooldown, flagAppMeshCircuitCooldown, 30*time.Second, "How long AppMesh calls are rejected once the circuit breaker opens, before a single call probes whether AppMesh recovered")
+	fs.StringVar(&cfg.AuditLogFile, flagAWSAuditLogFile, "", "Path of file audit log entries of mutating AppMesh, CloudMap and Route53 calls are appended to, or "+audit.LogFileStdout+". Set to empty to disable")
+}
+
+func (cfg *CloudConfig) Validate() error {
+	if _, err := endpoints.GetSTSRegionalEndpoint(cfg.STSRegionalEndpoints); err != nil {
+		return errors.Errorf("%s must be regional or legacy: %s", flagAWSSTSRegionalEndpoints, cfg.STSRegionalEndpoints)
+	}
+	if cfg.LocalMode && len(cfg.MockEndpoint) != 0 {
+		return errors.Errorf("%s and %s are mutually exclusive", flagAWSLocalMode, flagAWSMockEndpoint)
+	}
+	if cfg.AppMeshCircuitBreakerThreshold < 0 {
+		return errors.Errorf("%s must not be negative: %d", flagAppMeshCircuitThreshold, cfg.AppMeshCircuitBreakerThreshold)
+	}
+	if cfg.AppMeshCircuitBreakerThreshold > 0 && cfg.AppMeshCircuitBreakerCooldown <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagAppMeshCircuitCooldown, cfg.AppMeshCircuitBreakerCooldown)
+	}
+	return nil
+}
+
+// stsConfig returns the config of STS clients, which assume the IAM role for service accounts and namespace IAM roles,
+// and introspect AccountID.
+func (cfg *CloudConfig) stsConfig() *aws.Config {
+	stsRegionalEndpoint, _ := endpoints.GetSTSRegionalEndpoint(cfg.STSRegionalEndpoints)
+	return &aws.Config{
+		Region:               aws.String(cfg.Region),
+		STSRegionalEndpoint:  stsRegionalEndpoint,
+		UseDualStackEndpoint: endpoints.DualStackEndpointState(cfg.getAwsDualStackSTSEndpoint()),
+	}
 }
 
 // function to check if aws accountId got converted to scientific notation, and convert back
@@ -65,6 +154,15 @@ func (cfg *CloudConfig) GetAwsDualStackEndpoint() int {
 	}
 }
 
+// converts boolean values of Dual Stack STS Endpoint into Integer which are used for AWS STS
+func (cfg *CloudConfig) getAwsDualStackSTSEndpoint() int {
+	if cfg.UseAwsDualStackSTSEndpoint {
+		return 1
+	} else {
+		return 0
+	}
+}
+
 // converts boolean values of Fips Endpoint into Integer which are used for AWS Services
 func (cfg *CloudConfig) GetAwsFIPSEndpoint() int {
 	if cfg.UseAwsFIPSEndpoint {
@@ -1,9 +1,12 @@
 package aws
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/stretchr/testify/assert"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"testing"
+	"time"
 )
 
 func TestCloudConfig(t *testing.T) {
@@ -130,3 +133,84 @@ func TestGetAwsFIPSEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name                 string
+		stsRegionalEndpoints string
+		breakerThreshold     int
+		breakerCooldown      time.Duration
+		localMode            bool
+		mockEndpoint         string
+		wantErr              string
+	}{
+		{
+			name:                 "regional STS endpoints",
+			stsRegionalEndpoints: "regional",
+		},
+		{
+			name:                 "legacy STS endpoints",
+			stsRegionalEndpoints: "Legacy",
+		},
+		{
+			name:                 "unknown STS endpoints",
+			stsRegionalEndpoints: "global",
+			wantErr:              "aws-sts-regional-endpoints must be regional or legacy: global",
+		},
+		{
+			name:                 "circuit breaker enabled",
+			stsRegionalEndpoints: "legacy",
+			breakerThreshold:     5,
+			breakerCooldown:      30 * time.Second,
+		},
+		{
+			name:                 "negative circuit breaker threshold",
+			stsRegionalEndpoints: "legacy",
+			breakerThreshold:     -1,
+			wantErr:              "appmesh-circuit-breaker-threshold must not be negative: -1",
+		},
+		{
+			name:                 "circuit breaker enabled without cool-down",
+			stsRegionalEndpoints: "legacy",
+			breakerThreshold:     5,
+			wantErr:              "appmesh-circuit-breaker-cooldown must be positive: 0s",
+		},
+		{
+			name:                 "mock endpoint",
+			stsRegionalEndpoints: "legacy",
+			mockEndpoint:         "http://appmesh-mock.appmesh-system:8080",
+		},
+		{
+			name:                 "local mode with mock endpoint",
+			stsRegionalEndpoints: "legacy",
+			localMode:            true,
+			mockEndpoint:         "http://appmesh-mock.appmesh-system:8080",
+			wantErr:              "aws-local-mode and aws-mock-endpoint are mutually exclusive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CloudConfig{
+				STSRegionalEndpoints:           tt.stsRegionalEndpoints,
+				AppMeshCircuitBreakerThreshold: tt.breakerThreshold,
+				AppMeshCircuitBreakerCooldown:  tt.breakerCooldown,
+				LocalMode:                      tt.localMode,
+				MockEndpoint:                   tt.mockEndpoint,
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCloudConfig_stsConfig(t *testing.T) {
+	cfg := CloudConfig{Region: "cn-north-1", STSRegionalEndpoints: "regional", UseAwsDualStackSTSEndpoint: true}
+	stsCfg := cfg.stsConfig()
+	assert.Equal(t, "cn-north-1", aws.StringValue(stsCfg.Region))
+	assert.Equal(t, endpoints.RegionalSTSEndpoint, stsCfg.STSRegionalEndpoint)
+	assert.Equal(t, endpoints.DualStackEndpointStateEnabled, stsCfg.UseDualStackEndpoint)
+}
@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+)
+
+// newCustomEndpointResolver constructs an endpoints.Resolver that resolves to custom endpoint URLs by service endpointsID,
+// e.g. "appmesh", "servicediscovery" or "sts". Other services are resolved by the SDK default resolver.
+func newCustomEndpointResolver(customEndpoints map[string]string) (endpoints.Resolver, error) {
+	defaultResolver := endpoints.DefaultResolver()
+	if len(customEndpoints) == 0 {
+		return defaultResolver, nil
+	}
+	for serviceID, endpointURL := range customEndpoints {
+		parsedURL, err := url.Parse(endpointURL)
+		if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+			return nil, errors.Errorf("invalid endpoint URL for service %s: %s", serviceID, endpointURL)
+		}
+	}
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if endpointURL, ok := customEndpoints[service]; ok {
+			return endpoints.ResolvedEndpoint{
+				URL:           endpointURL,
+				SigningRegion: region,
+			}, nil
+		}
+		return defaultResolver.EndpointFor(service, region, opts...)
+	}), nil
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newCustomEndpointResolver(t *testing.T) {
+	tests := []struct {
+		name            string
+		customEndpoints map[string]string
+		service         string
+		region          string
+		opts            []func(*endpoints.Options)
+		wantURL         string
+		wantErr         error
+	}{
+		{
+			name:            "custom endpoint for service",
+			customEndpoints: map[string]string{"appmesh": "https://appmesh.vpce.example.com"},
+			service:         "appmesh",
+			region:          "us-west-2",
+			wantURL:         "https://appmesh.vpce.example.com",
+		},
+		{
+			name:            "service without custom endpoint resolves to default endpoint",
+			customEndpoints: map[string]string{"appmesh": "https://appmesh.vpce.example.com"},
+			service:         "servicediscovery",
+			region:          "us-west-2",
+			wantURL:         "https://servicediscovery.us-west-2.amazonaws.com",
+		},
+		{
+			name:            "no custom endpoints resolves to default FIPS endpoint",
+			customEndpoints: nil,
+			service:         "servicediscovery",
+			region:          "us-gov-west-1",
+			opts:            []func(*endpoints.Options){endpoints.UseFIPSEndpointOption},
+			wantURL:         "https://servicediscovery-fips.us-gov-west-1.amazonaws.com",
+		},
+		{
+			name:            "no custom endpoints resolves to default China endpoint",
+			customEndpoints: nil,
+			service:         "sts",
+			region:          "cn-north-1",
+			opts:            []func(*endpoints.Options){endpoints.STSRegionalEndpointOption},
+			wantURL:         "https://sts.cn-north-1.amazonaws.com.cn",
+		},
+		{
+			name:            "invalid custom endpoint",
+			customEndpoints: map[string]string{"appmesh": "appmesh.vpce.example.com"},
+			wantErr:         errors.New("invalid endpoint URL for service appmesh: appmesh.vpce.example.com"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := newCustomEndpointResolver(tt.customEndpoints)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			got, err := resolver.EndpointFor(tt.service, tt.region, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantURL, got.URL)
+		})
+	}
+}
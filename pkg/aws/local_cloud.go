@@ -0,0 +1,128 @@
+package aws
+
+import (
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+const (
+	// ErrCodeLocalMode is the error code of calls to AWS APIs that are unavailable in local mode.
+	ErrCodeLocalMode = "LocalMode"
+
+	defaultLocalModeAccountID = "000000000000"
+	defaultLocalModeRegion    = "us-west-2"
+)
+
+// newLocalCloud constructs new Cloud implementation for local mode, where AppMesh is served by in-memory fakes
+// and calls to other AWS APIs fail without reaching AWS.
+func newLocalCloud(cfg CloudConfig) (Cloud, error) {
+	if len(cfg.AccountID) == 0 {
+		cfg.AccountID = defaultLocalModeAccountID
+	}
+	if len(cfg.Region) == 0 {
+		cfg.Region = defaultLocalModeRegion
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(cfg.Region),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	sess.Handlers.Validate.PushFront(func(r *request.Request) {
+		r.Error = awserr.New(ErrCodeLocalMode, r.ClientInfo.ServiceID+" "+r.Operation.Name+" is unavailable in local mode", nil)
+	})
+	c := &localCloud{
+		cfg:           cfg,
+		appMeshes:     make(map[string]services.AppMesh),
+		cloudMap:      services.NewCloudMap(sess),
+		eks:           services.NewEKS(sess),
+		ssm:           services.NewSSM(sess),
+		sqs:           services.NewSQS(sess),
+		route53:       services.NewRoute53(sess),
+		serviceQuotas: services.NewServiceQuotas(sess),
+		s3:            services.NewS3(sess),
+	}
+	c.appMesh = c.AppMeshForRegion(cfg.Region, "")
+	return c, nil
+}
+
+var _ Cloud = &localCloud{}
+
+type localCloud struct {
+	cfg CloudConfig
+
+	appMesh       services.AppMesh
+	cloudMap      services.CloudMap
+	eks           services.EKS
+	ssm           services.SSM
+	sqs           services.SQS
+	route53       services.Route53
+	serviceQuotas services.ServiceQuotas
+	s3            services.S3
+
+	appMeshesMutex sync.Mutex
+	// appMeshes are the fake AppMesh of each region.
+	appMeshes map[string]services.AppMesh
+}
+
+func (c *localCloud) AppMesh() services.AppMesh {
+	return c.appMesh
+}
+
+func (c *localCloud) AppMeshCache() services.AppMeshCache {
+	return services.NewNoopAppMeshCache()
+}
+
+func (c *localCloud) AppMeshForRegion(region string, endpoint string) services.AppMesh {
+	c.appMeshesMutex.Lock()
+	defer c.appMeshesMutex.Unlock()
+	appMesh, ok := c.appMeshes[region]
+	if !ok {
+		appMesh = services.NewFakeAppMesh(c.cfg.AccountID, region)
+		c.appMeshes[region] = appMesh
+	}
+	return appMesh
+}
+
+func (c *localCloud) CloudMap() services.CloudMap {
+	return c.cloudMap
+}
+
+func (c *localCloud) EKS() services.EKS {
+	return c.eks
+}
+
+func (c *localCloud) SSM() services.SSM {
+	return c.ssm
+}
+
+func (c *localCloud) SQS() services.SQS {
+	return c.sqs
+}
+
+func (c *localCloud) Route53() services.Route53 {
+	return c.route53
+}
+
+func (c *localCloud) ServiceQuotas() services.ServiceQuotas {
+	return c.serviceQuotas
+}
+
+func (c *localCloud) S3() services.S3 {
+	return c.s3
+}
+
+func (c *localCloud) AccountID() string {
+	return c.cfg.AccountID
+}
+
+func (c *localCloud) Region() string {
+	return c.cfg.Region
+}
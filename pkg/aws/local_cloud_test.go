@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newLocalCloud(t *testing.T) {
+	cloud, err := NewCloud(CloudConfig{LocalMode: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultLocalModeAccountID, cloud.AccountID())
+	assert.Equal(t, defaultLocalModeRegion, cloud.Region())
+
+	createResp, err := cloud.AppMesh().CreateMeshWithContext(context.Background(), &appmesh.CreateMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh", aws.StringValue(createResp.Mesh.Metadata.Arn))
+
+	assert.Same(t, cloud.AppMesh(), cloud.AppMeshForRegion(defaultLocalModeRegion, ""))
+	_, err = cloud.AppMeshForRegion("us-east-1", "").DescribeMeshWithContext(context.Background(), &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.Error(t, err)
+
+	_, err = cloud.SQS().ReceiveMessageWithContext(context.Background(), &sqs.ReceiveMessageInput{QueueUrl: aws.String("https://sqs.us-west-2.amazonaws.com/000000000000/queue")})
+	awsErr, ok := err.(awserr.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, ErrCodeLocalMode, awsErr.Code())
+	}
+}
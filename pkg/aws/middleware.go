@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/metrics"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/timeout"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Middleware instruments or shapes AWS API calls made by the controller, e.g. to collect metrics, write audit logs or throttle calls,
+// by injecting handlers into the request handler chains of the session all AWS clients are derived from.
+type Middleware interface {
+	InjectHandlers(handlers *request.Handlers)
+}
+
+// MiddlewareFunc adapts a function to Middleware.
+type MiddlewareFunc func(handlers *request.Handlers)
+
+func (f MiddlewareFunc) InjectHandlers(handlers *request.Handlers) {
+	f(handlers)
+}
+
+// defaultMiddlewares returns the middlewares configured by cfg, in the order they're injected.
+func defaultMiddlewares(cfg CloudConfig, metricsRegisterer prometheus.Registerer) ([]Middleware, error) {
+	middlewares := []Middleware{
+		MiddlewareFunc(injectUserAgent),
+		MiddlewareFunc(tracing.InjectHandlers),
+	}
+	if cfg.ThrottleConfig != nil {
+		middlewares = append(middlewares, throttle.NewThrottler(cfg.ThrottleConfig))
+	}
+	if cfg.TimeoutConfig != nil {
+		middlewares = append(middlewares, timeout.NewTimeouter(cfg.TimeoutConfig))
+	}
+	if metricsRegisterer != nil {
+		metricsCollector, err := metrics.NewCollector(metricsRegisterer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to initialize sdk metrics collector")
+		}
+		middlewares = append(middlewares, metricsCollector)
+	}
+	if len(cfg.AuditLogFile) != 0 {
+		auditLogger, err := audit.NewFileLogger(cfg.AuditLogFile)
+		if err != nil {
+			return nil, err
+		}
+		middlewares = append(middlewares, auditLogger)
+	}
+	return middlewares, nil
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/throttle"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/timeout"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_defaultMiddlewares(t *testing.T) {
+	tests := []struct {
+		name              string
+		cfg               CloudConfig
+		metricsRegisterer prometheus.Registerer
+		wantHandlerNames  []string
+	}{
+		{
+			name:             "user agent and tracing only",
+			cfg:              CloudConfig{},
+			wantHandlerNames: []string{"appmesh.k8s.aws/user-agent", "startAPICallSpan"},
+		},
+		{
+			name: "all middlewares configured",
+			cfg: CloudConfig{
+				ThrottleConfig: &throttle.ServiceOperationsThrottleConfig{},
+				TimeoutConfig:  &timeout.ServiceOperationsTimeoutConfig{},
+				AuditLogFile:   audit.LogFileStdout,
+			},
+			metricsRegisterer: prometheus.NewRegistry(),
+			wantHandlerNames:  []string{"appmesh.k8s.aws/user-agent", "startAPICallSpan", "requestThrottle", "requestTimeout", "collectAPICallMetric", "auditLogAPICall"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middlewares, err := defaultMiddlewares(tt.cfg, tt.metricsRegisterer)
+			assert.NoError(t, err)
+			assert.Len(t, middlewares, len(tt.wantHandlerNames))
+			for i, middleware := range middlewares {
+				handlers := request.Handlers{}
+				middleware.InjectHandlers(&handlers)
+				assert.True(t, hasNamedHandler(handlers, tt.wantHandlerNames[i]), "middleware %d should inject %s", i, tt.wantHandlerNames[i])
+			}
+		})
+	}
+}
+
+func TestMiddlewareFunc_InjectHandlers(t *testing.T) {
+	var sentOperations []string
+	middleware := MiddlewareFunc(func(handlers *request.Handlers) {
+		handlers.Send.PushBack(func(r *request.Request) {
+			sentOperations = append(sentOperations, r.Operation.Name)
+		})
+	})
+	handlers := request.Handlers{}
+	middleware.InjectHandlers(&handlers)
+	handlers.Send.Run(&request.Request{Operation: &request.Operation{Name: "DescribeMesh"}})
+	assert.Equal(t, []string{"DescribeMesh"}, sentOperations)
+}
+
+// hasNamedHandler tests whether any handler chain of handlers contains a handler named name.
+func hasNamedHandler(handlers request.Handlers, name string) bool {
+	for _, list := range []request.HandlerList{handlers.Validate, handlers.Build, handlers.Sign, handlers.Send,
+		handlers.ValidateResponse, handlers.Unmarshal, handlers.Retry, handlers.AfterRetry, handlers.CompleteAttempt, handlers.Complete} {
+		if list.Swap(name, request.NamedHandler{Name: name, Fn: func(*request.Request) {}}) {
+			return true
+		}
+	}
+	return false
+}
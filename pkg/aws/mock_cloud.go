@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ErrCodeMockMode is the error code of calls to AWS APIs that aren't served by the appmesh-mock server in mock mode.
+	ErrCodeMockMode = "MockMode"
+
+	// mockModeAccessKeyID is the access key calls to the appmesh-mock server are signed with, which doesn't verify signatures.
+	mockModeAccessKeyID = "MOCKACCESSKEYID"
+)
+
+// newMockCloud constructs new Cloud implementation for mock mode, where AppMesh and CloudMap calls are sent to the appmesh-mock server
+// at cfg.MockEndpoint with fake credentials, and calls to other AWS APIs fail without reaching AWS.
+// calls go through the same middlewares and AppMesh wrappers as with AWS, so they're throttled, instrumented, audited and cached alike.
+func newMockCloud(cfg CloudConfig, metricsRegisterer prometheus.Registerer, customMiddlewares ...Middleware) (Cloud, error) {
+	if len(cfg.AccountID) == 0 {
+		cfg.AccountID = defaultLocalModeAccountID
+	}
+	if len(cfg.Region) == 0 {
+		cfg.Region = defaultLocalModeRegion
+	}
+	endpointResolver, err := newCustomEndpointResolver(map[string]string{
+		appmesh.EndpointsID:          cfg.MockEndpoint,
+		servicediscovery.EndpointsID: cfg.MockEndpoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(cfg.Region),
+		Credentials:      credentials.NewStaticCredentials(mockModeAccessKeyID, "mock", ""),
+		EndpointResolver: endpointResolver,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	sess.Handlers.Validate.PushFront(func(r *request.Request) {
+		if r.ClientInfo.ServiceID != appmesh.ServiceID && r.ClientInfo.ServiceID != servicediscovery.ServiceID {
+			r.Error = awserr.New(ErrCodeMockMode, r.ClientInfo.ServiceID+" "+r.Operation.Name+" is unavailable in mock mode", nil)
+		}
+	})
+	middlewares, err := defaultMiddlewares(cfg, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	for _, middleware := range append(middlewares, customMiddlewares...) {
+		middleware.InjectHandlers(&sess.Handlers)
+	}
+	appMesh, appMeshCache, err := newRegionalAppMesh(cfg, sess, metricsRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	return &mockCloud{
+		defaultCloud: defaultCloud{
+			cfg:           cfg,
+			sessAppMesh:   sess,
+			appMesh:       appMesh,
+			appMeshCache:  appMeshCache,
+			cloudMap:      services.NewCloudMap(sess),
+			eks:           services.NewEKS(sess),
+			ssm:           services.NewSSM(sess),
+			sqs:           services.NewSQS(sess),
+			route53:       services.NewRoute53(sess),
+			serviceQuotas: services.NewServiceQuotas(sess),
+			s3:            services.NewS3(sess),
+		},
+	}, nil
+}
+
+var _ Cloud = &mockCloud{}
+
+type mockCloud struct {
+	defaultCloud
+}
+
+// AppMeshForRegion provides API to the appmesh-mock server in region, custom endpoints of regions are ignored.
+func (c *mockCloud) AppMeshForRegion(region string, endpoint string) services.AppMesh {
+	return c.defaultCloud.AppMeshForRegion(region, "")
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/mockserver"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newMockCloud(t *testing.T) {
+	ctx := context.Background()
+	server, err := mockserver.NewServer(defaultLocalModeAccountID, defaultLocalModeRegion, logr.Discard())
+	assert.NoError(t, err)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	cloud, err := NewCloud(CloudConfig{MockEndpoint: httpServer.URL}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultLocalModeAccountID, cloud.AccountID())
+	assert.Equal(t, defaultLocalModeRegion, cloud.Region())
+
+	createResp, err := cloud.AppMesh().CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh", aws.StringValue(createResp.Mesh.Metadata.Arn))
+	_, err = server.AppMesh(defaultLocalModeRegion).DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+
+	// meshes of other regions are served by separate fakes, and custom endpoints are ignored.
+	_, err = cloud.AppMeshForRegion("us-east-1", "https://appmesh.example.com").DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assertMockCloudErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+
+	createNSResp, err := cloud.CloudMap().CreateHttpNamespaceWithContext(ctx, &servicediscovery.CreateHttpNamespaceInput{Name: aws.String("my-ns")})
+	assert.NoError(t, err)
+	opResp, err := cloud.CloudMap().GetOperationWithContext(ctx, &servicediscovery.GetOperationInput{OperationId: createNSResp.OperationId})
+	assert.NoError(t, err)
+	assert.Equal(t, servicediscovery.OperationStatusSuccess, aws.StringValue(opResp.Operation.Status))
+
+	_, err = cloud.SQS().ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{QueueUrl: aws.String("https://sqs.us-west-2.amazonaws.com/000000000000/queue")})
+	assertMockCloudErrorCode(t, ErrCodeMockMode, err)
+}
+
+func assertMockCloudErrorCode(t *testing.T, wantCode string, err error) {
+	awsErr, ok := err.(awserr.Error)
+	if assert.True(t, ok, "expected awserr.Error, got %v", err) {
+		assert.Equal(t, wantCode, awsErr.Code())
+	}
+}
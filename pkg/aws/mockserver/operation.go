@@ -0,0 +1,127 @@
+package mockserver
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+)
+
+// operation is an AWS API operation served by calling the method of the same name on a fake.
+type operation struct {
+	name string
+	// httpMethod and httpPath route REST-JSON requests of AppMesh, e.g. PUT /v20190125/meshes/{meshName}.
+	httpMethod string
+	httpPath   string
+	inputType  reflect.Type
+	// paginated operations are served by the Pages method of the fake, which returns all items in a single page.
+	paginated bool
+}
+
+// newOperations describes the operations of client by name, from the requests its "${name}Request" methods construct.
+func newOperations(client interface{}, names []string) ([]*operation, error) {
+	clientValue := reflect.ValueOf(client)
+	operations := make([]*operation, 0, len(names))
+	for _, name := range names {
+		requestMethod := clientValue.MethodByName(name + "Request")
+		if !requestMethod.IsValid() {
+			return nil, errors.Errorf("unknown operation %s", name)
+		}
+		inputType := requestMethod.Type().In(0)
+		results := requestMethod.Call([]reflect.Value{reflect.New(inputType.Elem())})
+		req := results[0].Interface().(*request.Request)
+		operations = append(operations, &operation{
+			name:       name,
+			httpMethod: req.Operation.HTTPMethod,
+			httpPath:   req.Operation.HTTPPath,
+			inputType:  inputType,
+			paginated:  req.Operation.Paginator != nil,
+		})
+	}
+	return operations, nil
+}
+
+// matchPath matches path against httpPath, returning the values of its URI parameters.
+func (o *operation) matchPath(path string) (map[string]string, bool) {
+	templateSegments := strings.Split(o.httpPath, "/")
+	segments := strings.Split(path, "/")
+	if len(segments) != len(templateSegments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, templateSegment := range templateSegments {
+		if strings.HasPrefix(templateSegment, "{") && strings.HasSuffix(templateSegment, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || len(value) == 0 {
+				return nil, false
+			}
+			params[strings.Trim(templateSegment, "{}")] = value
+			continue
+		}
+		if segments[i] != templateSegment {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// setLocationFields sets the fields of input located in the URI or query string of the request.
+func setLocationFields(input reflect.Value, uriParams map[string]string, query url.Values) error {
+	inputValue := input.Elem()
+	inputType := inputValue.Type()
+	for i := 0; i < inputType.NumField(); i++ {
+		field := inputType.Field(i)
+		locationName := field.Tag.Get("locationName")
+		var value string
+		var ok bool
+		switch field.Tag.Get("location") {
+		case "uri":
+			value, ok = uriParams[locationName]
+		case "querystring":
+			value, ok = query.Get(locationName), query.Has(locationName)
+		}
+		if !ok {
+			continue
+		}
+		switch field.Type {
+		case reflect.TypeOf((*string)(nil)):
+			inputValue.Field(i).Set(reflect.ValueOf(aws.String(value)))
+		case reflect.TypeOf((*int64)(nil)):
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.Errorf("%s must be an integer: %s", locationName, value)
+			}
+			inputValue.Field(i).Set(reflect.ValueOf(aws.Int64(number)))
+		default:
+			return errors.Errorf("unsupported type of %s: %v", locationName, field.Type)
+		}
+	}
+	return nil
+}
+
+// invoke calls the method of fake implementing the operation with input.
+func (o *operation) invoke(ctx context.Context, fake interface{}, input reflect.Value) (interface{}, error) {
+	if !o.paginated {
+		results := reflect.ValueOf(fake).MethodByName(o.name + "WithContext").Call([]reflect.Value{reflect.ValueOf(ctx), input})
+		if err, _ := results[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		return results[0].Interface(), nil
+	}
+	pagesMethod := reflect.ValueOf(fake).MethodByName(o.name + "PagesWithContext")
+	var output interface{}
+	fn := reflect.MakeFunc(pagesMethod.Type().In(2), func(args []reflect.Value) []reflect.Value {
+		output = args[0].Interface()
+		return []reflect.Value{reflect.ValueOf(false)}
+	})
+	results := pagesMethod.Call([]reflect.Value{reflect.ValueOf(ctx), input, fn})
+	if err, _ := results[0].Interface().(error); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,256 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ErrCodeUnknownOperation is the error code of requests for operations that aren't served.
+	ErrCodeUnknownOperation = "UnknownOperationException"
+	// ErrCodeInternalFailure is the error code of requests the fakes failed to serve.
+	ErrCodeInternalFailure = "InternalFailure"
+
+	headerErrorType = "X-Amzn-Errortype"
+	headerTarget    = "X-Amz-Target"
+)
+
+// appMeshOperations are the AppMesh operations served, which are the ones implemented by services.NewFakeAppMesh.
+var appMeshOperations = []string{
+	"CreateMesh", "DescribeMesh", "UpdateMesh", "DeleteMesh",
+	"CreateVirtualGateway", "DescribeVirtualGateway", "UpdateVirtualGateway", "DeleteVirtualGateway", "ListVirtualGateways",
+	"CreateGatewayRoute", "DescribeGatewayRoute", "UpdateGatewayRoute", "DeleteGatewayRoute", "ListGatewayRoutes",
+	"CreateVirtualNode", "DescribeVirtualNode", "UpdateVirtualNode", "DeleteVirtualNode", "ListVirtualNodes",
+	"CreateVirtualService", "DescribeVirtualService", "UpdateVirtualService", "DeleteVirtualService", "ListVirtualServices",
+	"CreateVirtualRouter", "DescribeVirtualRouter", "UpdateVirtualRouter", "DeleteVirtualRouter", "ListVirtualRouters",
+	"CreateRoute", "DescribeRoute", "UpdateRoute", "DeleteRoute", "ListRoutes",
+	"TagResource", "UntagResource", "ListTagsForResource",
+}
+
+// cloudMapOperations are the CloudMap operations served, which are the ones implemented by services.NewFakeCloudMap.
+var cloudMapOperations = []string{
+	"CreatePrivateDnsNamespace", "CreateHttpNamespace", "GetNamespace", "DeleteNamespace", "ListNamespaces",
+	"CreateService", "GetService", "DeleteService", "ListServices",
+	"RegisterInstance", "DeregisterInstance", "GetInstance", "ListInstances",
+	"GetInstancesHealthStatus", "UpdateInstanceCustomHealthStatus",
+	"GetOperation", "ListTagsForResource",
+}
+
+// appMeshErrorStatusCodes are the HTTP status codes of AppMesh errors, other errors are returned with 400 like CloudMap errors.
+var appMeshErrorStatusCodes = map[string]int{
+	appmesh.ErrCodeConflictException:            http.StatusConflict,
+	appmesh.ErrCodeForbiddenException:           http.StatusForbidden,
+	appmesh.ErrCodeInternalServerErrorException: http.StatusInternalServerError,
+	appmesh.ErrCodeNotFoundException:            http.StatusNotFound,
+	appmesh.ErrCodeResourceInUseException:       http.StatusConflict,
+	appmesh.ErrCodeServiceUnavailableException:  http.StatusServiceUnavailable,
+	appmesh.ErrCodeTooManyRequestsException:     http.StatusTooManyRequests,
+	ErrCodeInternalFailure:                      http.StatusInternalServerError,
+}
+
+// NewServer constructs new Server of AWS resources in accountID.
+// requests are served in the region they're signed for, or defaultRegion if they aren't signed.
+func NewServer(accountID string, defaultRegion string, log logr.Logger) (*Server, error) {
+	// the session only describes operations, it never sends requests.
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(defaultRegion),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	appMeshOps, err := newOperations(appmesh.New(sess), appMeshOperations)
+	if err != nil {
+		return nil, err
+	}
+	cloudMapClient := servicediscovery.New(sess)
+	cloudMapOps, err := newOperations(cloudMapClient, cloudMapOperations)
+	if err != nil {
+		return nil, err
+	}
+	cloudMapOpsByName := make(map[string]*operation, len(cloudMapOps))
+	for _, op := range cloudMapOps {
+		cloudMapOpsByName[op.name] = op
+	}
+	return &Server{
+		accountID:            accountID,
+		defaultRegion:        defaultRegion,
+		log:                  log,
+		appMeshOperations:    appMeshOps,
+		cloudMapOperations:   cloudMapOpsByName,
+		cloudMapTargetPrefix: cloudMapClient.ClientInfo.TargetPrefix + ".",
+		appMeshes:            make(map[string]services.AppMesh),
+		cloudMaps:            make(map[string]services.CloudMap),
+	}, nil
+}
+
+var _ http.Handler = &Server{}
+
+// Server serves the subset of AppMesh and CloudMap APIs used by the controller over HTTP, from in-memory fakes of each region.
+// AppMesh requests are routed by their method and path, and CloudMap requests by their X-Amz-Target header. Request signatures aren't verified.
+type Server struct {
+	accountID     string
+	defaultRegion string
+	log           logr.Logger
+
+	appMeshOperations []*operation
+	// cloudMapOperations by name.
+	cloudMapOperations   map[string]*operation
+	cloudMapTargetPrefix string
+
+	mutex sync.Mutex
+	// appMeshes and cloudMaps are the fakes of each region.
+	appMeshes map[string]services.AppMesh
+	cloudMaps map[string]services.CloudMap
+}
+
+// AppMesh returns the fake AppMesh of region, e.g. to seed or inspect resources.
+func (s *Server) AppMesh(region string) services.AppMesh {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	appMesh, ok := s.appMeshes[region]
+	if !ok {
+		appMesh = services.NewFakeAppMesh(s.accountID, region)
+		s.appMeshes[region] = appMesh
+	}
+	return appMesh
+}
+
+// CloudMap returns the fake CloudMap of region, e.g. to seed or inspect resources.
+func (s *Server) CloudMap(region string) services.CloudMap {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cloudMap, ok := s.cloudMaps[region]
+	if !ok {
+		cloudMap = services.NewFakeCloudMap(s.accountID, region)
+		s.cloudMaps[region] = cloudMap
+	}
+	return cloudMap
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	region := s.signingRegion(r)
+	if target := r.Header.Get(headerTarget); len(target) != 0 {
+		s.serveCloudMap(w, r, region, target)
+		return
+	}
+	s.serveAppMesh(w, r, region)
+}
+
+func (s *Server) serveAppMesh(w http.ResponseWriter, r *http.Request, region string) {
+	for _, op := range s.appMeshOperations {
+		if op.httpMethod != r.Method {
+			continue
+		}
+		uriParams, ok := op.matchPath(r.URL.EscapedPath())
+		if !ok {
+			continue
+		}
+		input := reflect.New(op.inputType.Elem())
+		if err := jsonutil.UnmarshalJSON(input.Interface(), r.Body); err != nil {
+			s.writeError(w, op.name, region, awserr.New(appmesh.ErrCodeBadRequestException, "invalid request body", err), appMeshErrorStatusCodes)
+			return
+		}
+		if err := setLocationFields(input, uriParams, r.URL.Query()); err != nil {
+			s.writeError(w, op.name, region, awserr.New(appmesh.ErrCodeBadRequestException, err.Error(), nil), appMeshErrorStatusCodes)
+			return
+		}
+		s.serve(w, r, op, region, s.AppMesh(region), input, "application/json", appMeshErrorStatusCodes)
+		return
+	}
+	s.writeError(w, r.Method+" "+r.URL.Path, region, awserr.New(ErrCodeUnknownOperation, fmt.Sprintf("%s %s isn't served", r.Method, r.URL.Path), nil), nil)
+}
+
+func (s *Server) serveCloudMap(w http.ResponseWriter, r *http.Request, region string, target string) {
+	op, ok := s.cloudMapOperations[strings.TrimPrefix(target, s.cloudMapTargetPrefix)]
+	if !ok || !strings.HasPrefix(target, s.cloudMapTargetPrefix) {
+		s.writeError(w, target, region, awserr.New(ErrCodeUnknownOperation, fmt.Sprintf("%s isn't served", target), nil), nil)
+		return
+	}
+	input := reflect.New(op.inputType.Elem())
+	if err := jsonutil.UnmarshalJSON(input.Interface(), r.Body); err != nil {
+		s.writeError(w, op.name, region, awserr.New(servicediscovery.ErrCodeInvalidInput, "invalid request body", err), nil)
+		return
+	}
+	s.serve(w, r, op, region, s.CloudMap(region), input, "application/x-amz-json-1.1", nil)
+}
+
+// serve invokes op on fake with input, and writes its output or error.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, op *operation, region string, fake interface{}, input reflect.Value,
+	contentType string, errorStatusCodes map[string]int) {
+	output, err := s.invoke(r, op, fake, input)
+	if err != nil {
+		s.writeError(w, op.name, region, err, errorStatusCodes)
+		return
+	}
+	body, err := jsonutil.BuildJSON(output)
+	if err != nil {
+		s.writeError(w, op.name, region, awserr.New(ErrCodeInternalFailure, "failed to marshal response", err), errorStatusCodes)
+		return
+	}
+	s.log.V(1).Info("served request", "operation", op.name, "region", region)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// invoke invokes op on fake, recovering panics of the fake as errors, so a single request can't break the server.
+func (s *Server) invoke(r *http.Request, op *operation, fake interface{}, input reflect.Value) (output interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = awserr.New(ErrCodeInternalFailure, fmt.Sprintf("%s failed: %v", op.name, recovered), nil)
+		}
+	}()
+	return op.invoke(r.Context(), fake, input)
+}
+
+// writeError writes err as an AWS JSON error, which the SDK parses from the X-Amzn-Errortype header or the __type field.
+func (s *Server) writeError(w http.ResponseWriter, operationName string, region string, err error, errorStatusCodes map[string]int) {
+	code, message := ErrCodeInternalFailure, err.Error()
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		code, message = awsErr.Code(), awsErr.Message()
+	}
+	statusCode := http.StatusBadRequest
+	if errorStatusCode, ok := errorStatusCodes[code]; ok {
+		statusCode = errorStatusCode
+	} else if code == ErrCodeInternalFailure {
+		statusCode = http.StatusInternalServerError
+	}
+	s.log.V(1).Info("failed request", "operation", operationName, "region", region, "code", code, "message", message)
+	w.Header().Set(headerErrorType, code)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"__type": code, "message": message})
+}
+
+// signingRegion returns the region of the SigV4 credential scope of r, e.g. "Credential=AKID/20240101/us-west-2/appmesh/aws4_request",
+// or the default region if r isn't signed.
+func (s *Server) signingRegion(r *http.Request) string {
+	authorization := r.Header.Get("Authorization")
+	index := strings.Index(authorization, "Credential=")
+	if index < 0 {
+		return s.defaultRegion
+	}
+	scope := strings.SplitN(authorization[index+len("Credential="):], ",", 2)[0]
+	parts := strings.Split(scope, "/")
+	if len(parts) != 5 || len(parts[2]) == 0 {
+		return s.defaultRegion
+	}
+	return parts[2]
+}
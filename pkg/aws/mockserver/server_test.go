@@ -0,0 +1,216 @@
+package mockserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSession(t *testing.T, endpoint string, region string) *session.Session {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	assert.NoError(t, err)
+	return sess
+}
+
+func assertAWSErrorCode(t *testing.T, wantCode string, err error) {
+	awsErr, ok := err.(awserr.Error)
+	if assert.True(t, ok, "expected awserr.Error, got %v", err) {
+		assert.Equal(t, wantCode, awsErr.Code())
+	}
+}
+
+func TestServer_AppMesh(t *testing.T) {
+	ctx := context.Background()
+	server, err := NewServer("222233334444", "us-west-2", logr.Discard())
+	assert.NoError(t, err)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	sdk := appmesh.New(newTestSession(t, httpServer.URL, "us-west-2"))
+
+	_, err = sdk.CreateVirtualRouterWithContext(ctx, &appmesh.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		Spec:              &appmesh.VirtualRouterSpec{},
+	})
+	assertAWSErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+
+	createMeshResp, err := sdk.CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Tags:     []*appmesh.TagRef{{Key: aws.String("k"), Value: aws.String("v")}},
+	})
+	assert.NoError(t, err)
+	meshARN := createMeshResp.Mesh.Metadata.Arn
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh", aws.StringValue(meshARN))
+	assert.NotNil(t, createMeshResp.Mesh.Metadata.CreatedAt)
+	_, err = sdk.CreateVirtualRouterWithContext(ctx, &appmesh.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		Spec: &appmesh.VirtualRouterSpec{
+			Listeners: []*appmesh.VirtualRouterListener{{PortMapping: &appmesh.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")}}},
+		},
+	})
+	assert.NoError(t, err)
+
+	routeSpec := &appmesh.RouteSpec{
+		HttpRoute: &appmesh.HttpRoute{
+			Match: &appmesh.HttpRouteMatch{Prefix: aws.String("/")},
+			Action: &appmesh.HttpRouteAction{
+				WeightedTargets: []*appmesh.WeightedTarget{{VirtualNode: aws.String("my-vn"), Weight: aws.Int64(100)}},
+			},
+		},
+	}
+	_, err = sdk.CreateRouteWithContext(ctx, &appmesh.CreateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		RouteName:         aws.String("my-route"),
+		Spec:              routeSpec,
+	})
+	assert.NoError(t, err)
+	_, err = sdk.CreateRouteWithContext(ctx, &appmesh.CreateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		RouteName:         aws.String("my-route"),
+		Spec:              routeSpec,
+	})
+	assertAWSErrorCode(t, appmesh.ErrCodeConflictException, err)
+
+	routeSpec.HttpRoute.Action.WeightedTargets[0].Weight = aws.Int64(50)
+	updateResp, err := sdk.UpdateRouteWithContext(ctx, &appmesh.UpdateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		RouteName:         aws.String("my-route"),
+		Spec:              routeSpec,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), aws.Int64Value(updateResp.Route.Metadata.Version))
+	describeResp, err := sdk.DescribeRouteWithContext(ctx, &appmesh.DescribeRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		RouteName:         aws.String("my-route"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, routeSpec, describeResp.Route.Spec)
+
+	var routeNames []string
+	assert.NoError(t, sdk.ListRoutesPagesWithContext(ctx, &appmesh.ListRoutesInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		Limit:             aws.Int64(10),
+	}, func(output *appmesh.ListRoutesOutput, lastPage bool) bool {
+		for _, route := range output.Routes {
+			routeNames = append(routeNames, aws.StringValue(route.RouteName))
+		}
+		return true
+	}))
+	assert.Equal(t, []string{"my-route"}, routeNames)
+
+	_, err = sdk.UntagResourceWithContext(ctx, &appmesh.UntagResourceInput{ResourceArn: meshARN, TagKeys: aws.StringSlice([]string{"k"})})
+	assert.NoError(t, err)
+	tagsResp, err := sdk.ListTagsForResourceWithContext(ctx, &appmesh.ListTagsForResourceInput{ResourceArn: meshARN})
+	assert.NoError(t, err)
+	assert.Empty(t, tagsResp.Tags)
+
+	_, err = sdk.DeleteMeshWithContext(ctx, &appmesh.DeleteMeshInput{MeshName: aws.String("my-mesh")})
+	assertAWSErrorCode(t, appmesh.ErrCodeResourceInUseException, err)
+
+	// requests are served by the fake of the region they're signed for.
+	otherRegionSDK := appmesh.New(newTestSession(t, httpServer.URL, "eu-west-1"))
+	_, err = otherRegionSDK.DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assertAWSErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+	_, err = server.AppMesh("us-west-2").DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+
+	_, err = sdk.ListMeshesWithContext(ctx, &appmesh.ListMeshesInput{})
+	assertAWSErrorCode(t, ErrCodeUnknownOperation, err)
+}
+
+func TestServer_CloudMap(t *testing.T) {
+	ctx := context.Background()
+	server, err := NewServer("222233334444", "us-west-2", logr.Discard())
+	assert.NoError(t, err)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	sdk := servicediscovery.New(newTestSession(t, httpServer.URL, "us-west-2"))
+
+	createNSResp, err := sdk.CreatePrivateDnsNamespaceWithContext(ctx, &servicediscovery.CreatePrivateDnsNamespaceInput{
+		Name: aws.String("my-ns.local"),
+		Vpc:  aws.String("vpc-1"),
+	})
+	assert.NoError(t, err)
+	opResp, err := sdk.GetOperationWithContext(ctx, &servicediscovery.GetOperationInput{OperationId: createNSResp.OperationId})
+	assert.NoError(t, err)
+	nsID := opResp.Operation.Targets[servicediscovery.OperationTargetTypeNamespace]
+
+	createSvcResp, err := sdk.CreateServiceWithContext(ctx, &servicediscovery.CreateServiceInput{NamespaceId: nsID, Name: aws.String("my-svc")})
+	assert.NoError(t, err)
+	_, err = sdk.RegisterInstanceWithContext(ctx, &servicediscovery.RegisterInstanceInput{
+		ServiceId:  createSvcResp.Service.Id,
+		InstanceId: aws.String("192.168.0.1"),
+		Attributes: aws.StringMap(map[string]string{"AWS_INSTANCE_IPV4": "192.168.0.1"}),
+	})
+	assert.NoError(t, err)
+	var instanceIDs []string
+	assert.NoError(t, sdk.ListInstancesPagesWithContext(ctx, &servicediscovery.ListInstancesInput{ServiceId: createSvcResp.Service.Id}, func(output *servicediscovery.ListInstancesOutput, lastPage bool) bool {
+		for _, instance := range output.Instances {
+			instanceIDs = append(instanceIDs, aws.StringValue(instance.Id))
+		}
+		return true
+	}))
+	assert.Equal(t, []string{"192.168.0.1"}, instanceIDs)
+
+	_, err = sdk.DeleteServiceWithContext(ctx, &servicediscovery.DeleteServiceInput{Id: createSvcResp.Service.Id})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeResourceInUse, err)
+	_, err = sdk.GetServiceWithContext(ctx, &servicediscovery.GetServiceInput{Id: aws.String("srv-unknown")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeServiceNotFound, err)
+
+	_, err = sdk.ListOperationsWithContext(ctx, &servicediscovery.ListOperationsInput{})
+	assertAWSErrorCode(t, ErrCodeUnknownOperation, err)
+}
+
+func TestServer_signingRegion(t *testing.T) {
+	server := &Server{defaultRegion: "us-west-2"}
+	tests := []struct {
+		name          string
+		authorization string
+		want          string
+	}{
+		{
+			name:          "signed request",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKID/20240101/eu-west-1/appmesh/aws4_request, SignedHeaders=host;x-amz-date, Signature=abc",
+			want:          "eu-west-1",
+		},
+		{
+			name: "unsigned request",
+			want: "us-west-2",
+		},
+		{
+			name:          "malformed credential scope",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKID, Signature=abc",
+			want:          "us-west-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			assert.Equal(t, tt.want, server.signingRegion(r))
+		})
+	}
+}
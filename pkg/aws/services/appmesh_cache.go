@@ -0,0 +1,359 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+)
+
+// AppMeshCache caches responses of AppMesh Describe and List calls.
+type AppMeshCache interface {
+	// InvalidateResourcePath drops cached responses of the AppMesh resource with resourcePath, as well as of resources within it.
+	// resourcePath is the resource part of the ARN, e.g. "mesh/my-mesh/virtualNode/my-node".
+	InvalidateResourcePath(resourcePath string)
+}
+
+// NewCachedAppMesh constructs new AppMesh implementation, which caches responses of Describe and ListRoutes calls for ttl.
+// cached responses of a resource are invalidated upon every Create, Update or Delete call of it.
+func NewCachedAppMesh(appMesh AppMesh, ttl time.Duration) *cachedAppMesh {
+	return &cachedAppMesh{
+		AppMesh:   appMesh,
+		ttl:       ttl,
+		entries:   make(map[string]cacheEntry),
+		lastPurge: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// NewNoopAppMeshCache constructs new AppMeshCache that caches nothing.
+func NewNoopAppMeshCache() AppMeshCache {
+	return noopAppMeshCache{}
+}
+
+type noopAppMeshCache struct{}
+
+func (noopAppMeshCache) InvalidateResourcePath(resourcePath string) {}
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+var _ AppMesh = &cachedAppMesh{}
+var _ AppMeshCache = &cachedAppMesh{}
+
+type cachedAppMesh struct {
+	AppMesh
+	ttl time.Duration
+
+	mutex sync.Mutex
+	// entries by cache key, which starts with the resource path followed by "|".
+	entries map[string]cacheEntry
+	// invalidations counts invalidations, responses received across an invalidation are not cached since they may be stale.
+	invalidations uint64
+	lastPurge     time.Time
+	now           func() time.Time
+}
+
+func (c *cachedAppMesh) InvalidateResourcePath(resourcePath string) {
+	c.invalidate(resourcePath+"|", resourcePath+"/")
+}
+
+// invalidate drops cached responses whose key has any of keyPrefixes.
+func (c *cachedAppMesh) invalidate(keyPrefixes ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.invalidations++
+	for key := range c.entries {
+		for _, keyPrefix := range keyPrefixes {
+			if strings.HasPrefix(key, keyPrefix) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// get returns a copy of the cached response with key.
+func (c *cachedAppMesh) get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return awsutil.CopyOf(entry.value), true
+}
+
+// put caches a copy of value with key, which must be a pointer, unless any invalidation happened since invalidations was observed.
+func (c *cachedAppMesh) put(key string, value interface{}, invalidations uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.invalidations != invalidations {
+		return
+	}
+	now := c.now()
+	if now.Sub(c.lastPurge) >= c.ttl {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastPurge = now
+	}
+	c.entries[key] = cacheEntry{value: awsutil.CopyOf(value), expiresAt: now.Add(c.ttl)}
+}
+
+func (c *cachedAppMesh) currentInvalidations() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.invalidations
+}
+
+// cachedDescribe returns the cached response with key, or calls describe and caches its response.
+func cachedDescribe[T any](c *cachedAppMesh, key string, describe func() (T, error)) (T, error) {
+	if value, ok := c.get(key); ok {
+		return value.(T), nil
+	}
+	invalidations := c.currentInvalidations()
+	resp, err := describe()
+	if err != nil {
+		return resp, err
+	}
+	c.put(key, resp, invalidations)
+	return resp, nil
+}
+
+// cacheKey returns the key of responses of op on the resource with resourcePath within mesh owned by meshOwner.
+func cacheKey(resourcePath string, meshOwner *string, op string) string {
+	return fmt.Sprintf("%s|%s|%s", resourcePath, aws.StringValue(meshOwner), op)
+}
+
+func meshPath(meshName *string) string {
+	return fmt.Sprintf("mesh/%s", aws.StringValue(meshName))
+}
+
+func virtualGatewayPath(meshName *string, virtualGatewayName *string) string {
+	return fmt.Sprintf("%s/virtualGateway/%s", meshPath(meshName), aws.StringValue(virtualGatewayName))
+}
+
+func gatewayRoutePath(meshName *string, virtualGatewayName *string, gatewayRouteName *string) string {
+	return fmt.Sprintf("%s/gatewayRoute/%s", virtualGatewayPath(meshName, virtualGatewayName), aws.StringValue(gatewayRouteName))
+}
+
+func virtualNodePath(meshName *string, virtualNodeName *string) string {
+	return fmt.Sprintf("%s/virtualNode/%s", meshPath(meshName), aws.StringValue(virtualNodeName))
+}
+
+func virtualServicePath(meshName *string, virtualServiceName *string) string {
+	return fmt.Sprintf("%s/virtualService/%s", meshPath(meshName), aws.StringValue(virtualServiceName))
+}
+
+func virtualRouterPath(meshName *string, virtualRouterName *string) string {
+	return fmt.Sprintf("%s/virtualRouter/%s", meshPath(meshName), aws.StringValue(virtualRouterName))
+}
+
+// routesPath is the path of routes of a virtualRouter, which ListRoutes responses are cached with.
+func routesPath(meshName *string, virtualRouterName *string) string {
+	return fmt.Sprintf("%s/route", virtualRouterPath(meshName, virtualRouterName))
+}
+
+func routePath(meshName *string, virtualRouterName *string, routeName *string) string {
+	return fmt.Sprintf("%s/%s", routesPath(meshName, virtualRouterName), aws.StringValue(routeName))
+}
+
+func (c *cachedAppMesh) DescribeMeshWithContext(ctx aws.Context, input *appmesh.DescribeMeshInput, opts ...request.Option) (*appmesh.DescribeMeshOutput, error) {
+	return cachedDescribe(c, cacheKey(meshPath(input.MeshName), input.MeshOwner, "DescribeMesh"), func() (*appmesh.DescribeMeshOutput, error) {
+		return c.AppMesh.DescribeMeshWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateMeshWithContext(ctx aws.Context, input *appmesh.CreateMeshInput, opts ...request.Option) (*appmesh.CreateMeshOutput, error) {
+	defer c.InvalidateResourcePath(meshPath(input.MeshName))
+	return c.AppMesh.CreateMeshWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateMeshWithContext(ctx aws.Context, input *appmesh.UpdateMeshInput, opts ...request.Option) (*appmesh.UpdateMeshOutput, error) {
+	defer c.InvalidateResourcePath(meshPath(input.MeshName))
+	return c.AppMesh.UpdateMeshWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteMeshWithContext(ctx aws.Context, input *appmesh.DeleteMeshInput, opts ...request.Option) (*appmesh.DeleteMeshOutput, error) {
+	defer c.InvalidateResourcePath(meshPath(input.MeshName))
+	return c.AppMesh.DeleteMeshWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeVirtualGatewayWithContext(ctx aws.Context, input *appmesh.DescribeVirtualGatewayInput, opts ...request.Option) (*appmesh.DescribeVirtualGatewayOutput, error) {
+	return cachedDescribe(c, cacheKey(virtualGatewayPath(input.MeshName, input.VirtualGatewayName), input.MeshOwner, "DescribeVirtualGateway"), func() (*appmesh.DescribeVirtualGatewayOutput, error) {
+		return c.AppMesh.DescribeVirtualGatewayWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateVirtualGatewayWithContext(ctx aws.Context, input *appmesh.CreateVirtualGatewayInput, opts ...request.Option) (*appmesh.CreateVirtualGatewayOutput, error) {
+	defer c.InvalidateResourcePath(virtualGatewayPath(input.MeshName, input.VirtualGatewayName))
+	return c.AppMesh.CreateVirtualGatewayWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateVirtualGatewayWithContext(ctx aws.Context, input *appmesh.UpdateVirtualGatewayInput, opts ...request.Option) (*appmesh.UpdateVirtualGatewayOutput, error) {
+	defer c.InvalidateResourcePath(virtualGatewayPath(input.MeshName, input.VirtualGatewayName))
+	return c.AppMesh.UpdateVirtualGatewayWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteVirtualGatewayWithContext(ctx aws.Context, input *appmesh.DeleteVirtualGatewayInput, opts ...request.Option) (*appmesh.DeleteVirtualGatewayOutput, error) {
+	defer c.InvalidateResourcePath(virtualGatewayPath(input.MeshName, input.VirtualGatewayName))
+	return c.AppMesh.DeleteVirtualGatewayWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeGatewayRouteWithContext(ctx aws.Context, input *appmesh.DescribeGatewayRouteInput, opts ...request.Option) (*appmesh.DescribeGatewayRouteOutput, error) {
+	return cachedDescribe(c, cacheKey(gatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName), input.MeshOwner, "DescribeGatewayRoute"), func() (*appmesh.DescribeGatewayRouteOutput, error) {
+		return c.AppMesh.DescribeGatewayRouteWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateGatewayRouteWithContext(ctx aws.Context, input *appmesh.CreateGatewayRouteInput, opts ...request.Option) (*appmesh.CreateGatewayRouteOutput, error) {
+	defer c.InvalidateResourcePath(gatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName))
+	return c.AppMesh.CreateGatewayRouteWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateGatewayRouteWithContext(ctx aws.Context, input *appmesh.UpdateGatewayRouteInput, opts ...request.Option) (*appmesh.UpdateGatewayRouteOutput, error) {
+	defer c.InvalidateResourcePath(gatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName))
+	return c.AppMesh.UpdateGatewayRouteWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteGatewayRouteWithContext(ctx aws.Context, input *appmesh.DeleteGatewayRouteInput, opts ...request.Option) (*appmesh.DeleteGatewayRouteOutput, error) {
+	defer c.InvalidateResourcePath(gatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName))
+	return c.AppMesh.DeleteGatewayRouteWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeVirtualNodeWithContext(ctx aws.Context, input *appmesh.DescribeVirtualNodeInput, opts ...request.Option) (*appmesh.DescribeVirtualNodeOutput, error) {
+	return cachedDescribe(c, cacheKey(virtualNodePath(input.MeshName, input.VirtualNodeName), input.MeshOwner, "DescribeVirtualNode"), func() (*appmesh.DescribeVirtualNodeOutput, error) {
+		return c.AppMesh.DescribeVirtualNodeWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateVirtualNodeWithContext(ctx aws.Context, input *appmesh.CreateVirtualNodeInput, opts ...request.Option) (*appmesh.CreateVirtualNodeOutput, error) {
+	defer c.InvalidateResourcePath(virtualNodePath(input.MeshName, input.VirtualNodeName))
+	return c.AppMesh.CreateVirtualNodeWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateVirtualNodeWithContext(ctx aws.Context, input *appmesh.UpdateVirtualNodeInput, opts ...request.Option) (*appmesh.UpdateVirtualNodeOutput, error) {
+	defer c.InvalidateResourcePath(virtualNodePath(input.MeshName, input.VirtualNodeName))
+	return c.AppMesh.UpdateVirtualNodeWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteVirtualNodeWithContext(ctx aws.Context, input *appmesh.DeleteVirtualNodeInput, opts ...request.Option) (*appmesh.DeleteVirtualNodeOutput, error) {
+	defer c.InvalidateResourcePath(virtualNodePath(input.MeshName, input.VirtualNodeName))
+	return c.AppMesh.DeleteVirtualNodeWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeVirtualServiceWithContext(ctx aws.Context, input *appmesh.DescribeVirtualServiceInput, opts ...request.Option) (*appmesh.DescribeVirtualServiceOutput, error) {
+	return cachedDescribe(c, cacheKey(virtualServicePath(input.MeshName, input.VirtualServiceName), input.MeshOwner, "DescribeVirtualService"), func() (*appmesh.DescribeVirtualServiceOutput, error) {
+		return c.AppMesh.DescribeVirtualServiceWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateVirtualServiceWithContext(ctx aws.Context, input *appmesh.CreateVirtualServiceInput, opts ...request.Option) (*appmesh.CreateVirtualServiceOutput, error) {
+	defer c.InvalidateResourcePath(virtualServicePath(input.MeshName, input.VirtualServiceName))
+	return c.AppMesh.CreateVirtualServiceWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateVirtualServiceWithContext(ctx aws.Context, input *appmesh.UpdateVirtualServiceInput, opts ...request.Option) (*appmesh.UpdateVirtualServiceOutput, error) {
+	defer c.InvalidateResourcePath(virtualServicePath(input.MeshName, input.VirtualServiceName))
+	return c.AppMesh.UpdateVirtualServiceWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteVirtualServiceWithContext(ctx aws.Context, input *appmesh.DeleteVirtualServiceInput, opts ...request.Option) (*appmesh.DeleteVirtualServiceOutput, error) {
+	defer c.InvalidateResourcePath(virtualServicePath(input.MeshName, input.VirtualServiceName))
+	return c.AppMesh.DeleteVirtualServiceWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeVirtualRouterWithContext(ctx aws.Context, input *appmesh.DescribeVirtualRouterInput, opts ...request.Option) (*appmesh.DescribeVirtualRouterOutput, error) {
+	return cachedDescribe(c, cacheKey(virtualRouterPath(input.MeshName, input.VirtualRouterName), input.MeshOwner, "DescribeVirtualRouter"), func() (*appmesh.DescribeVirtualRouterOutput, error) {
+		return c.AppMesh.DescribeVirtualRouterWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *cachedAppMesh) CreateVirtualRouterWithContext(ctx aws.Context, input *appmesh.CreateVirtualRouterInput, opts ...request.Option) (*appmesh.CreateVirtualRouterOutput, error) {
+	defer c.InvalidateResourcePath(virtualRouterPath(input.MeshName, input.VirtualRouterName))
+	return c.AppMesh.CreateVirtualRouterWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateVirtualRouterWithContext(ctx aws.Context, input *appmesh.UpdateVirtualRouterInput, opts ...request.Option) (*appmesh.UpdateVirtualRouterOutput, error) {
+	defer c.InvalidateResourcePath(virtualRouterPath(input.MeshName, input.VirtualRouterName))
+	return c.AppMesh.UpdateVirtualRouterWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteVirtualRouterWithContext(ctx aws.Context, input *appmesh.DeleteVirtualRouterInput, opts ...request.Option) (*appmesh.DeleteVirtualRouterOutput, error) {
+	defer c.InvalidateResourcePath(virtualRouterPath(input.MeshName, input.VirtualRouterName))
+	return c.AppMesh.DeleteVirtualRouterWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DescribeRouteWithContext(ctx aws.Context, input *appmesh.DescribeRouteInput, opts ...request.Option) (*appmesh.DescribeRouteOutput, error) {
+	return cachedDescribe(c, cacheKey(routePath(input.MeshName, input.VirtualRouterName, input.RouteName), input.MeshOwner, "DescribeRoute"), func() (*appmesh.DescribeRouteOutput, error) {
+		return c.AppMesh.DescribeRouteWithContext(ctx, input, opts...)
+	})
+}
+
+// ListRoutesPagesWithContext replays cached pages to fn. pages are only cached once fn iterated all of them.
+func (c *cachedAppMesh) ListRoutesPagesWithContext(ctx aws.Context, input *appmesh.ListRoutesInput, fn func(*appmesh.ListRoutesOutput, bool) bool, opts ...request.Option) error {
+	key := cacheKey(routesPath(input.MeshName, input.VirtualRouterName), input.MeshOwner, "ListRoutes")
+	if len(aws.StringValue(input.NextToken)) == 0 {
+		if value, ok := c.get(key); ok {
+			pages := *value.(*[]*appmesh.ListRoutesOutput)
+			for i, page := range pages {
+				if !fn(page, i == len(pages)-1) {
+					break
+				}
+			}
+			return nil
+		}
+	}
+	invalidations := c.currentInvalidations()
+	var pages []*appmesh.ListRoutesOutput
+	iteratedAll := true
+	if err := c.AppMesh.ListRoutesPagesWithContext(ctx, input, func(page *appmesh.ListRoutesOutput, lastPage bool) bool {
+		pages = append(pages, awsutil.CopyOf(page).(*appmesh.ListRoutesOutput))
+		if !fn(page, lastPage) {
+			iteratedAll = lastPage
+			return false
+		}
+		return true
+	}, opts...); err != nil {
+		return err
+	}
+	if iteratedAll && len(aws.StringValue(input.NextToken)) == 0 {
+		c.put(key, &pages, invalidations)
+	}
+	return nil
+}
+
+func (c *cachedAppMesh) CreateRouteWithContext(ctx aws.Context, input *appmesh.CreateRouteInput, opts ...request.Option) (*appmesh.CreateRouteOutput, error) {
+	defer c.invalidateRoute(input.MeshName, input.VirtualRouterName, input.RouteName)
+	return c.AppMesh.CreateRouteWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) UpdateRouteWithContext(ctx aws.Context, input *appmesh.UpdateRouteInput, opts ...request.Option) (*appmesh.UpdateRouteOutput, error) {
+	defer c.invalidateRoute(input.MeshName, input.VirtualRouterName, input.RouteName)
+	return c.AppMesh.UpdateRouteWithContext(ctx, input, opts...)
+}
+
+func (c *cachedAppMesh) DeleteRouteWithContext(ctx aws.Context, input *appmesh.DeleteRouteInput, opts ...request.Option) (*appmesh.DeleteRouteOutput, error) {
+	defer c.invalidateRoute(input.MeshName, input.VirtualRouterName, input.RouteName)
+	return c.AppMesh.DeleteRouteWithContext(ctx, input, opts...)
+}
+
+// invalidateRoute invalidates the route, as well as the routes listed of its virtualRouter.
+func (c *cachedAppMesh) invalidateRoute(meshName *string, virtualRouterName *string, routeName *string) {
+	path := routePath(meshName, virtualRouterName, routeName)
+	c.invalidate(path+"|", path+"/", routesPath(meshName, virtualRouterName)+"|")
+}
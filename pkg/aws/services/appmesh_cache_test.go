@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAppMesh counts calls, and serves a virtualNode and the routes of a virtualRouter.
+type fakeAppMesh struct {
+	AppMesh
+
+	describeVirtualNodeCalls int
+	listRoutesCalls          int
+	describeErr              error
+	// onDescribe is invoked during DescribeVirtualNode calls if set.
+	onDescribe func()
+	routePages [][]string
+}
+
+func (f *fakeAppMesh) DescribeVirtualNodeWithContext(ctx aws.Context, input *appmesh.DescribeVirtualNodeInput, opts ...request.Option) (*appmesh.DescribeVirtualNodeOutput, error) {
+	f.describeVirtualNodeCalls++
+	if f.onDescribe != nil {
+		f.onDescribe()
+	}
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+	return &appmesh.DescribeVirtualNodeOutput{
+		VirtualNode: &appmesh.VirtualNodeData{
+			MeshName:        input.MeshName,
+			VirtualNodeName: input.VirtualNodeName,
+			Metadata:        &appmesh.ResourceMetadata{Version: aws.Int64(1)},
+		},
+	}, nil
+}
+
+func (f *fakeAppMesh) UpdateVirtualNodeWithContext(ctx aws.Context, input *appmesh.UpdateVirtualNodeInput, opts ...request.Option) (*appmesh.UpdateVirtualNodeOutput, error) {
+	return &appmesh.UpdateVirtualNodeOutput{}, nil
+}
+
+func (f *fakeAppMesh) ListRoutesPagesWithContext(ctx aws.Context, input *appmesh.ListRoutesInput, fn func(*appmesh.ListRoutesOutput, bool) bool, opts ...request.Option) error {
+	f.listRoutesCalls++
+	for i, routeNames := range f.routePages {
+		page := &appmesh.ListRoutesOutput{}
+		for _, routeName := range routeNames {
+			page.Routes = append(page.Routes, &appmesh.RouteRef{RouteName: aws.String(routeName)})
+		}
+		if !fn(page, i == len(f.routePages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeAppMesh) DeleteRouteWithContext(ctx aws.Context, input *appmesh.DeleteRouteInput, opts ...request.Option) (*appmesh.DeleteRouteOutput, error) {
+	return &appmesh.DeleteRouteOutput{}, nil
+}
+
+func describeVirtualNode(t *testing.T, c *cachedAppMesh, meshOwner *string) *appmesh.DescribeVirtualNodeOutput {
+	resp, err := c.DescribeVirtualNodeWithContext(context.Background(), &appmesh.DescribeVirtualNodeInput{
+		MeshName:        aws.String("my-mesh"),
+		MeshOwner:       meshOwner,
+		VirtualNodeName: aws.String("my-node"),
+	})
+	assert.NoError(t, err)
+	return resp
+}
+
+func listRoutes(t *testing.T, c *cachedAppMesh, maxPages int) []string {
+	var routeNames []string
+	pages := 0
+	err := c.ListRoutesPagesWithContext(context.Background(), &appmesh.ListRoutesInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-router"),
+	}, func(page *appmesh.ListRoutesOutput, lastPage bool) bool {
+		for _, route := range page.Routes {
+			routeNames = append(routeNames, aws.StringValue(route.RouteName))
+		}
+		pages++
+		return pages < maxPages
+	})
+	assert.NoError(t, err)
+	return routeNames
+}
+
+func Test_cachedAppMesh_DescribeVirtualNode(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	sdk := &fakeAppMesh{}
+	c := NewCachedAppMesh(sdk, time.Minute)
+	c.now = func() time.Time { return now }
+
+	resp := describeVirtualNode(t, c, nil)
+	assert.Equal(t, "my-node", aws.StringValue(resp.VirtualNode.VirtualNodeName))
+	resp.VirtualNode.VirtualNodeName = aws.String("mutated-by-caller")
+
+	resp = describeVirtualNode(t, c, nil)
+	assert.Equal(t, "my-node", aws.StringValue(resp.VirtualNode.VirtualNodeName))
+	assert.Equal(t, 1, sdk.describeVirtualNodeCalls)
+
+	describeVirtualNode(t, c, aws.String("222222222222"))
+	assert.Equal(t, 2, sdk.describeVirtualNodeCalls, "responses of shared meshes are cached separately")
+
+	now = now.Add(time.Minute)
+	describeVirtualNode(t, c, nil)
+	assert.Equal(t, 3, sdk.describeVirtualNodeCalls, "expired responses are described again")
+
+	_, err := c.UpdateVirtualNodeWithContext(context.Background(), &appmesh.UpdateVirtualNodeInput{
+		MeshName:        aws.String("my-mesh"),
+		VirtualNodeName: aws.String("my-node"),
+	})
+	assert.NoError(t, err)
+	describeVirtualNode(t, c, nil)
+	assert.Equal(t, 4, sdk.describeVirtualNodeCalls, "responses are invalidated upon updates")
+	describeVirtualNode(t, c, aws.String("222222222222"))
+	assert.Equal(t, 5, sdk.describeVirtualNodeCalls, "responses of all mesh owners are invalidated upon updates")
+
+	c.InvalidateResourcePath("mesh/my-mesh")
+	describeVirtualNode(t, c, nil)
+	assert.Equal(t, 6, sdk.describeVirtualNodeCalls, "responses are invalidated with their mesh")
+
+	c.InvalidateResourcePath("mesh/my-mesh/virtualNode/my-node-2")
+	describeVirtualNode(t, c, nil)
+	assert.Equal(t, 6, sdk.describeVirtualNodeCalls, "responses of other resources are kept")
+}
+
+func Test_cachedAppMesh_DescribeVirtualNode_notCached(t *testing.T) {
+	t.Run("errors aren't cached", func(t *testing.T) {
+		sdk := &fakeAppMesh{describeErr: awserr.New("NotFoundException", "virtualNode not found", nil)}
+		c := NewCachedAppMesh(sdk, time.Minute)
+		for i := 0; i < 2; i++ {
+			_, err := c.DescribeVirtualNodeWithContext(context.Background(), &appmesh.DescribeVirtualNodeInput{
+				MeshName:        aws.String("my-mesh"),
+				VirtualNodeName: aws.String("my-node"),
+			})
+			assert.Error(t, err)
+		}
+		assert.Equal(t, 2, sdk.describeVirtualNodeCalls)
+	})
+	t.Run("responses received across invalidations aren't cached", func(t *testing.T) {
+		sdk := &fakeAppMesh{}
+		c := NewCachedAppMesh(sdk, time.Minute)
+		sdk.onDescribe = func() {
+			c.InvalidateResourcePath("mesh/my-mesh/virtualNode/my-node")
+		}
+		describeVirtualNode(t, c, nil)
+		sdk.onDescribe = nil
+		describeVirtualNode(t, c, nil)
+		describeVirtualNode(t, c, nil)
+		assert.Equal(t, 2, sdk.describeVirtualNodeCalls)
+	})
+}
+
+func Test_cachedAppMesh_ListRoutesPages(t *testing.T) {
+	sdk := &fakeAppMesh{routePages: [][]string{{"route-1", "route-2"}, {"route-3"}}}
+	c := NewCachedAppMesh(sdk, time.Minute)
+
+	assert.Equal(t, []string{"route-1", "route-2"}, listRoutes(t, c, 1))
+	assert.Equal(t, []string{"route-1", "route-2", "route-3"}, listRoutes(t, c, 10))
+	assert.Equal(t, 2, sdk.listRoutesCalls, "partially iterated pages aren't cached")
+
+	assert.Equal(t, []string{"route-1", "route-2", "route-3"}, listRoutes(t, c, 10))
+	assert.Equal(t, []string{"route-1", "route-2"}, listRoutes(t, c, 1))
+	assert.Equal(t, 2, sdk.listRoutesCalls, "fully iterated pages are replayed")
+
+	_, err := c.DeleteRouteWithContext(context.Background(), &appmesh.DeleteRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-router"),
+		RouteName:         aws.String("route-3"),
+	})
+	assert.NoError(t, err)
+	sdk.routePages = [][]string{{"route-1", "route-2"}}
+	assert.Equal(t, []string{"route-1", "route-2"}, listRoutes(t, c, 10))
+	assert.Equal(t, 3, sdk.listRoutesCalls, "pages are invalidated upon route mutations")
+
+	c.InvalidateResourcePath("mesh/my-mesh/virtualRouter/my-router")
+	listRoutes(t, c, 10)
+	assert.Equal(t, 4, sdk.listRoutesCalls, "pages are invalidated with their virtualRouter")
+}
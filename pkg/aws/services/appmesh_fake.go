@@ -0,0 +1,708 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/aws/aws-sdk-go/service/appmesh/appmeshiface"
+)
+
+// NewFakeAppMesh constructs new in-memory AppMesh implementation, which behaves like AppMesh in accountID and region
+// for the operations used by the controller: Create, Describe, Update, Delete and List of all resource kinds, as well as tagging.
+// Resources become ACTIVE upon creation, and deleting a resource that still contains other resources fails with ResourceInUseException.
+// Other operations aren't implemented and panic.
+func NewFakeAppMesh(accountID string, region string) AppMesh {
+	return &inMemoryAppMesh{
+		accountID: accountID,
+		region:    region,
+		resources: make(map[string]*fakeResource),
+		now:       time.Now,
+	}
+}
+
+var _ AppMesh = &inMemoryAppMesh{}
+
+type inMemoryAppMesh struct {
+	// AppMeshAPI is left nil, so operations not implemented by the fake panic.
+	appmeshiface.AppMeshAPI
+
+	accountID string
+	region    string
+
+	mutex sync.Mutex
+	// resources by resource path, e.g. "mesh/my-mesh/virtualNode/my-node".
+	resources map[string]*fakeResource
+	// uidSequence generates resource UIDs.
+	uidSequence int64
+	now         func() time.Time
+}
+
+// fakeResource is an AppMesh resource stored by inMemoryAppMesh.
+type fakeResource struct {
+	metadata *appmesh.ResourceMetadata
+	// spec is a copy of the SDK spec, e.g. *appmesh.VirtualNodeSpec.
+	spec interface{}
+	tags []*appmesh.TagRef
+}
+
+func fakeMeshPath(meshName *string) string {
+	return "mesh/" + aws.StringValue(meshName)
+}
+
+func fakeMeshChildPath(meshName *string, resourceType string, name *string) string {
+	return fmt.Sprintf("%s/%s/%s", fakeMeshPath(meshName), resourceType, aws.StringValue(name))
+}
+
+func fakeRoutePath(meshName *string, virtualRouterName *string, routeName *string) string {
+	return fmt.Sprintf("%s/route/%s", fakeMeshChildPath(meshName, "virtualRouter", virtualRouterName), aws.StringValue(routeName))
+}
+
+func fakeGatewayRoutePath(meshName *string, virtualGatewayName *string, gatewayRouteName *string) string {
+	return fmt.Sprintf("%s/gatewayRoute/%s", fakeMeshChildPath(meshName, "virtualGateway", virtualGatewayName), aws.StringValue(gatewayRouteName))
+}
+
+// parentPath returns the path of the resource containing the resource at path, or "" for meshes.
+func parentPath(path string) string {
+	segments := strings.Split(path, "/")
+	if len(segments) <= 2 {
+		return ""
+	}
+	return strings.Join(segments[:len(segments)-2], "/")
+}
+
+func fakeNotFoundError(path string) error {
+	return awserr.New(appmesh.ErrCodeNotFoundException, fmt.Sprintf("%s not found", path), nil)
+}
+
+// create stores a new resource at path with a copy of spec. the resource containing it must exist.
+func (m *inMemoryAppMesh) create(path string, spec interface{}, tags []*appmesh.TagRef) (*fakeResource, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.resources[path]; ok {
+		return nil, awserr.New(appmesh.ErrCodeConflictException, fmt.Sprintf("%s already exists", path), nil)
+	}
+	if parent := parentPath(path); parent != "" {
+		if _, ok := m.resources[parent]; !ok {
+			return nil, fakeNotFoundError(parent)
+		}
+	}
+	m.uidSequence++
+	now := m.now()
+	resource := &fakeResource{
+		metadata: &appmesh.ResourceMetadata{
+			Arn:           aws.String(fmt.Sprintf("arn:aws:appmesh:%s:%s:%s", m.region, m.accountID, path)),
+			Uid:           aws.String(fmt.Sprintf("fake-uid-%d", m.uidSequence)),
+			Version:       aws.Int64(1),
+			CreatedAt:     aws.Time(now),
+			LastUpdatedAt: aws.Time(now),
+			MeshOwner:     aws.String(m.accountID),
+			ResourceOwner: aws.String(m.accountID),
+		},
+		spec: awsutil.CopyOf(spec),
+	}
+	for _, tag := range tags {
+		resource.tags = append(resource.tags, awsutil.CopyOf(tag).(*appmesh.TagRef))
+	}
+	m.resources[path] = resource
+	return resource.copy(), nil
+}
+
+func (m *inMemoryAppMesh) describe(path string) (*fakeResource, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resource, ok := m.resources[path]
+	if !ok {
+		return nil, fakeNotFoundError(path)
+	}
+	return resource.copy(), nil
+}
+
+// update replaces the spec of the resource at path with a copy of spec.
+func (m *inMemoryAppMesh) update(path string, spec interface{}) (*fakeResource, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resource, ok := m.resources[path]
+	if !ok {
+		return nil, fakeNotFoundError(path)
+	}
+	resource.spec = awsutil.CopyOf(spec)
+	resource.metadata.Version = aws.Int64(aws.Int64Value(resource.metadata.Version) + 1)
+	resource.metadata.LastUpdatedAt = aws.Time(m.now())
+	return resource.copy(), nil
+}
+
+// delete removes the resource at path, which must not contain other resources.
+func (m *inMemoryAppMesh) delete(path string) (*fakeResource, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resource, ok := m.resources[path]
+	if !ok {
+		return nil, fakeNotFoundError(path)
+	}
+	for otherPath := range m.resources {
+		if strings.HasPrefix(otherPath, path+"/") {
+			return nil, awserr.New(appmesh.ErrCodeResourceInUseException, fmt.Sprintf("%s contains %s", path, otherPath), nil)
+		}
+	}
+	delete(m.resources, path)
+	return resource, nil
+}
+
+// list returns the resources of resourceType directly contained in the resource at parent, sorted by name.
+func (m *inMemoryAppMesh) list(parent string, resourceType string) ([]*fakeResource, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.resources[parent]; !ok {
+		return nil, fakeNotFoundError(parent)
+	}
+	prefix := parent + "/" + resourceType + "/"
+	var paths []string
+	for path := range m.resources {
+		if strings.HasPrefix(path, prefix) && !strings.Contains(strings.TrimPrefix(path, prefix), "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	resources := make([]*fakeResource, 0, len(paths))
+	for _, path := range paths {
+		resources = append(resources, m.resources[path].copy())
+	}
+	return resources, nil
+}
+
+func (r *fakeResource) copy() *fakeResource {
+	return &fakeResource{
+		metadata: awsutil.CopyOf(r.metadata).(*appmesh.ResourceMetadata),
+		spec:     awsutil.CopyOf(r.spec),
+		tags:     r.tags,
+	}
+}
+
+// name returns the name of the resource, which is the last segment of its ARN.
+func (r *fakeResource) name() *string {
+	arn := aws.StringValue(r.metadata.Arn)
+	return aws.String(arn[strings.LastIndex(arn, "/")+1:])
+}
+
+func (m *inMemoryAppMesh) CreateMeshWithContext(ctx aws.Context, input *appmesh.CreateMeshInput, opts ...request.Option) (*appmesh.CreateMeshOutput, error) {
+	spec := input.Spec
+	if spec == nil {
+		spec = &appmesh.MeshSpec{}
+	}
+	resource, err := m.create(fakeMeshPath(input.MeshName), spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateMeshOutput{Mesh: resource.meshData(appmesh.MeshStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeMeshWithContext(ctx aws.Context, input *appmesh.DescribeMeshInput, opts ...request.Option) (*appmesh.DescribeMeshOutput, error) {
+	resource, err := m.describe(fakeMeshPath(input.MeshName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeMeshOutput{Mesh: resource.meshData(appmesh.MeshStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateMeshWithContext(ctx aws.Context, input *appmesh.UpdateMeshInput, opts ...request.Option) (*appmesh.UpdateMeshOutput, error) {
+	spec := input.Spec
+	if spec == nil {
+		spec = &appmesh.MeshSpec{}
+	}
+	resource, err := m.update(fakeMeshPath(input.MeshName), spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateMeshOutput{Mesh: resource.meshData(appmesh.MeshStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteMeshWithContext(ctx aws.Context, input *appmesh.DeleteMeshInput, opts ...request.Option) (*appmesh.DeleteMeshOutput, error) {
+	resource, err := m.delete(fakeMeshPath(input.MeshName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteMeshOutput{Mesh: resource.meshData(appmesh.MeshStatusCodeDeleted)}, nil
+}
+
+func (r *fakeResource) meshData(status string) *appmesh.MeshData {
+	return &appmesh.MeshData{
+		MeshName: r.name(),
+		Metadata: r.metadata,
+		Spec:     r.spec.(*appmesh.MeshSpec),
+		Status:   &appmesh.MeshStatus{Status: aws.String(status)},
+	}
+}
+
+func (m *inMemoryAppMesh) CreateVirtualGatewayWithContext(ctx aws.Context, input *appmesh.CreateVirtualGatewayInput, opts ...request.Option) (*appmesh.CreateVirtualGatewayOutput, error) {
+	resource, err := m.create(fakeMeshChildPath(input.MeshName, "virtualGateway", input.VirtualGatewayName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateVirtualGatewayOutput{VirtualGateway: resource.virtualGatewayData(input.MeshName, appmesh.VirtualGatewayStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeVirtualGatewayWithContext(ctx aws.Context, input *appmesh.DescribeVirtualGatewayInput, opts ...request.Option) (*appmesh.DescribeVirtualGatewayOutput, error) {
+	resource, err := m.describe(fakeMeshChildPath(input.MeshName, "virtualGateway", input.VirtualGatewayName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeVirtualGatewayOutput{VirtualGateway: resource.virtualGatewayData(input.MeshName, appmesh.VirtualGatewayStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateVirtualGatewayWithContext(ctx aws.Context, input *appmesh.UpdateVirtualGatewayInput, opts ...request.Option) (*appmesh.UpdateVirtualGatewayOutput, error) {
+	resource, err := m.update(fakeMeshChildPath(input.MeshName, "virtualGateway", input.VirtualGatewayName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateVirtualGatewayOutput{VirtualGateway: resource.virtualGatewayData(input.MeshName, appmesh.VirtualGatewayStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteVirtualGatewayWithContext(ctx aws.Context, input *appmesh.DeleteVirtualGatewayInput, opts ...request.Option) (*appmesh.DeleteVirtualGatewayOutput, error) {
+	resource, err := m.delete(fakeMeshChildPath(input.MeshName, "virtualGateway", input.VirtualGatewayName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteVirtualGatewayOutput{VirtualGateway: resource.virtualGatewayData(input.MeshName, appmesh.VirtualGatewayStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListVirtualGatewaysPagesWithContext(ctx aws.Context, input *appmesh.ListVirtualGatewaysInput, fn func(*appmesh.ListVirtualGatewaysOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshPath(input.MeshName), "virtualGateway")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListVirtualGatewaysOutput{}
+	for _, resource := range resources {
+		output.VirtualGateways = append(output.VirtualGateways, &appmesh.VirtualGatewayRef{
+			Arn:                resource.metadata.Arn,
+			CreatedAt:          resource.metadata.CreatedAt,
+			LastUpdatedAt:      resource.metadata.LastUpdatedAt,
+			MeshName:           input.MeshName,
+			MeshOwner:          resource.metadata.MeshOwner,
+			ResourceOwner:      resource.metadata.ResourceOwner,
+			Version:            resource.metadata.Version,
+			VirtualGatewayName: resource.name(),
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) virtualGatewayData(meshName *string, status string) *appmesh.VirtualGatewayData {
+	return &appmesh.VirtualGatewayData{
+		MeshName:           meshName,
+		Metadata:           r.metadata,
+		Spec:               r.spec.(*appmesh.VirtualGatewaySpec),
+		Status:             &appmesh.VirtualGatewayStatus{Status: aws.String(status)},
+		VirtualGatewayName: r.name(),
+	}
+}
+
+func (m *inMemoryAppMesh) CreateGatewayRouteWithContext(ctx aws.Context, input *appmesh.CreateGatewayRouteInput, opts ...request.Option) (*appmesh.CreateGatewayRouteOutput, error) {
+	resource, err := m.create(fakeGatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateGatewayRouteOutput{GatewayRoute: resource.gatewayRouteData(input.MeshName, input.VirtualGatewayName, appmesh.GatewayRouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeGatewayRouteWithContext(ctx aws.Context, input *appmesh.DescribeGatewayRouteInput, opts ...request.Option) (*appmesh.DescribeGatewayRouteOutput, error) {
+	resource, err := m.describe(fakeGatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeGatewayRouteOutput{GatewayRoute: resource.gatewayRouteData(input.MeshName, input.VirtualGatewayName, appmesh.GatewayRouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateGatewayRouteWithContext(ctx aws.Context, input *appmesh.UpdateGatewayRouteInput, opts ...request.Option) (*appmesh.UpdateGatewayRouteOutput, error) {
+	resource, err := m.update(fakeGatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateGatewayRouteOutput{GatewayRoute: resource.gatewayRouteData(input.MeshName, input.VirtualGatewayName, appmesh.GatewayRouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteGatewayRouteWithContext(ctx aws.Context, input *appmesh.DeleteGatewayRouteInput, opts ...request.Option) (*appmesh.DeleteGatewayRouteOutput, error) {
+	resource, err := m.delete(fakeGatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteGatewayRouteOutput{GatewayRoute: resource.gatewayRouteData(input.MeshName, input.VirtualGatewayName, appmesh.GatewayRouteStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListGatewayRoutesPagesWithContext(ctx aws.Context, input *appmesh.ListGatewayRoutesInput, fn func(*appmesh.ListGatewayRoutesOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshChildPath(input.MeshName, "virtualGateway", input.VirtualGatewayName), "gatewayRoute")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListGatewayRoutesOutput{}
+	for _, resource := range resources {
+		output.GatewayRoutes = append(output.GatewayRoutes, &appmesh.GatewayRouteRef{
+			Arn:                resource.metadata.Arn,
+			CreatedAt:          resource.metadata.CreatedAt,
+			GatewayRouteName:   resource.name(),
+			LastUpdatedAt:      resource.metadata.LastUpdatedAt,
+			MeshName:           input.MeshName,
+			MeshOwner:          resource.metadata.MeshOwner,
+			ResourceOwner:      resource.metadata.ResourceOwner,
+			Version:            resource.metadata.Version,
+			VirtualGatewayName: input.VirtualGatewayName,
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) gatewayRouteData(meshName *string, virtualGatewayName *string, status string) *appmesh.GatewayRouteData {
+	return &appmesh.GatewayRouteData{
+		GatewayRouteName:   r.name(),
+		MeshName:           meshName,
+		Metadata:           r.metadata,
+		Spec:               r.spec.(*appmesh.GatewayRouteSpec),
+		Status:             &appmesh.GatewayRouteStatus{Status: aws.String(status)},
+		VirtualGatewayName: virtualGatewayName,
+	}
+}
+
+func (m *inMemoryAppMesh) CreateVirtualNodeWithContext(ctx aws.Context, input *appmesh.CreateVirtualNodeInput, opts ...request.Option) (*appmesh.CreateVirtualNodeOutput, error) {
+	resource, err := m.create(fakeMeshChildPath(input.MeshName, "virtualNode", input.VirtualNodeName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateVirtualNodeOutput{VirtualNode: resource.virtualNodeData(input.MeshName, appmesh.VirtualNodeStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeVirtualNodeWithContext(ctx aws.Context, input *appmesh.DescribeVirtualNodeInput, opts ...request.Option) (*appmesh.DescribeVirtualNodeOutput, error) {
+	resource, err := m.describe(fakeMeshChildPath(input.MeshName, "virtualNode", input.VirtualNodeName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeVirtualNodeOutput{VirtualNode: resource.virtualNodeData(input.MeshName, appmesh.VirtualNodeStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateVirtualNodeWithContext(ctx aws.Context, input *appmesh.UpdateVirtualNodeInput, opts ...request.Option) (*appmesh.UpdateVirtualNodeOutput, error) {
+	resource, err := m.update(fakeMeshChildPath(input.MeshName, "virtualNode", input.VirtualNodeName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateVirtualNodeOutput{VirtualNode: resource.virtualNodeData(input.MeshName, appmesh.VirtualNodeStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteVirtualNodeWithContext(ctx aws.Context, input *appmesh.DeleteVirtualNodeInput, opts ...request.Option) (*appmesh.DeleteVirtualNodeOutput, error) {
+	resource, err := m.delete(fakeMeshChildPath(input.MeshName, "virtualNode", input.VirtualNodeName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteVirtualNodeOutput{VirtualNode: resource.virtualNodeData(input.MeshName, appmesh.VirtualNodeStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListVirtualNodesPagesWithContext(ctx aws.Context, input *appmesh.ListVirtualNodesInput, fn func(*appmesh.ListVirtualNodesOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshPath(input.MeshName), "virtualNode")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListVirtualNodesOutput{}
+	for _, resource := range resources {
+		output.VirtualNodes = append(output.VirtualNodes, &appmesh.VirtualNodeRef{
+			Arn:             resource.metadata.Arn,
+			CreatedAt:       resource.metadata.CreatedAt,
+			LastUpdatedAt:   resource.metadata.LastUpdatedAt,
+			MeshName:        input.MeshName,
+			MeshOwner:       resource.metadata.MeshOwner,
+			ResourceOwner:   resource.metadata.ResourceOwner,
+			Version:         resource.metadata.Version,
+			VirtualNodeName: resource.name(),
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) virtualNodeData(meshName *string, status string) *appmesh.VirtualNodeData {
+	return &appmesh.VirtualNodeData{
+		MeshName:        meshName,
+		Metadata:        r.metadata,
+		Spec:            r.spec.(*appmesh.VirtualNodeSpec),
+		Status:          &appmesh.VirtualNodeStatus{Status: aws.String(status)},
+		VirtualNodeName: r.name(),
+	}
+}
+
+func (m *inMemoryAppMesh) CreateVirtualServiceWithContext(ctx aws.Context, input *appmesh.CreateVirtualServiceInput, opts ...request.Option) (*appmesh.CreateVirtualServiceOutput, error) {
+	resource, err := m.create(fakeMeshChildPath(input.MeshName, "virtualService", input.VirtualServiceName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateVirtualServiceOutput{VirtualService: resource.virtualServiceData(input.MeshName, appmesh.VirtualServiceStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeVirtualServiceWithContext(ctx aws.Context, input *appmesh.DescribeVirtualServiceInput, opts ...request.Option) (*appmesh.DescribeVirtualServiceOutput, error) {
+	resource, err := m.describe(fakeMeshChildPath(input.MeshName, "virtualService", input.VirtualServiceName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeVirtualServiceOutput{VirtualService: resource.virtualServiceData(input.MeshName, appmesh.VirtualServiceStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateVirtualServiceWithContext(ctx aws.Context, input *appmesh.UpdateVirtualServiceInput, opts ...request.Option) (*appmesh.UpdateVirtualServiceOutput, error) {
+	resource, err := m.update(fakeMeshChildPath(input.MeshName, "virtualService", input.VirtualServiceName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateVirtualServiceOutput{VirtualService: resource.virtualServiceData(input.MeshName, appmesh.VirtualServiceStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteVirtualServiceWithContext(ctx aws.Context, input *appmesh.DeleteVirtualServiceInput, opts ...request.Option) (*appmesh.DeleteVirtualServiceOutput, error) {
+	resource, err := m.delete(fakeMeshChildPath(input.MeshName, "virtualService", input.VirtualServiceName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteVirtualServiceOutput{VirtualService: resource.virtualServiceData(input.MeshName, appmesh.VirtualServiceStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListVirtualServicesPagesWithContext(ctx aws.Context, input *appmesh.ListVirtualServicesInput, fn func(*appmesh.ListVirtualServicesOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshPath(input.MeshName), "virtualService")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListVirtualServicesOutput{}
+	for _, resource := range resources {
+		output.VirtualServices = append(output.VirtualServices, &appmesh.VirtualServiceRef{
+			Arn:                resource.metadata.Arn,
+			CreatedAt:          resource.metadata.CreatedAt,
+			LastUpdatedAt:      resource.metadata.LastUpdatedAt,
+			MeshName:           input.MeshName,
+			MeshOwner:          resource.metadata.MeshOwner,
+			ResourceOwner:      resource.metadata.ResourceOwner,
+			Version:            resource.metadata.Version,
+			VirtualServiceName: resource.name(),
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) virtualServiceData(meshName *string, status string) *appmesh.VirtualServiceData {
+	return &appmesh.VirtualServiceData{
+		MeshName:           meshName,
+		Metadata:           r.metadata,
+		Spec:               r.spec.(*appmesh.VirtualServiceSpec),
+		Status:             &appmesh.VirtualServiceStatus{Status: aws.String(status)},
+		VirtualServiceName: r.name(),
+	}
+}
+
+func (m *inMemoryAppMesh) CreateVirtualRouterWithContext(ctx aws.Context, input *appmesh.CreateVirtualRouterInput, opts ...request.Option) (*appmesh.CreateVirtualRouterOutput, error) {
+	resource, err := m.create(fakeMeshChildPath(input.MeshName, "virtualRouter", input.VirtualRouterName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateVirtualRouterOutput{VirtualRouter: resource.virtualRouterData(input.MeshName, appmesh.VirtualRouterStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeVirtualRouterWithContext(ctx aws.Context, input *appmesh.DescribeVirtualRouterInput, opts ...request.Option) (*appmesh.DescribeVirtualRouterOutput, error) {
+	resource, err := m.describe(fakeMeshChildPath(input.MeshName, "virtualRouter", input.VirtualRouterName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeVirtualRouterOutput{VirtualRouter: resource.virtualRouterData(input.MeshName, appmesh.VirtualRouterStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateVirtualRouterWithContext(ctx aws.Context, input *appmesh.UpdateVirtualRouterInput, opts ...request.Option) (*appmesh.UpdateVirtualRouterOutput, error) {
+	resource, err := m.update(fakeMeshChildPath(input.MeshName, "virtualRouter", input.VirtualRouterName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateVirtualRouterOutput{VirtualRouter: resource.virtualRouterData(input.MeshName, appmesh.VirtualRouterStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteVirtualRouterWithContext(ctx aws.Context, input *appmesh.DeleteVirtualRouterInput, opts ...request.Option) (*appmesh.DeleteVirtualRouterOutput, error) {
+	resource, err := m.delete(fakeMeshChildPath(input.MeshName, "virtualRouter", input.VirtualRouterName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteVirtualRouterOutput{VirtualRouter: resource.virtualRouterData(input.MeshName, appmesh.VirtualRouterStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListVirtualRoutersPagesWithContext(ctx aws.Context, input *appmesh.ListVirtualRoutersInput, fn func(*appmesh.ListVirtualRoutersOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshPath(input.MeshName), "virtualRouter")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListVirtualRoutersOutput{}
+	for _, resource := range resources {
+		output.VirtualRouters = append(output.VirtualRouters, &appmesh.VirtualRouterRef{
+			Arn:               resource.metadata.Arn,
+			CreatedAt:         resource.metadata.CreatedAt,
+			LastUpdatedAt:     resource.metadata.LastUpdatedAt,
+			MeshName:          input.MeshName,
+			MeshOwner:         resource.metadata.MeshOwner,
+			ResourceOwner:     resource.metadata.ResourceOwner,
+			Version:           resource.metadata.Version,
+			VirtualRouterName: resource.name(),
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) virtualRouterData(meshName *string, status string) *appmesh.VirtualRouterData {
+	return &appmesh.VirtualRouterData{
+		MeshName:          meshName,
+		Metadata:          r.metadata,
+		Spec:              r.spec.(*appmesh.VirtualRouterSpec),
+		Status:            &appmesh.VirtualRouterStatus{Status: aws.String(status)},
+		VirtualRouterName: r.name(),
+	}
+}
+
+func (m *inMemoryAppMesh) CreateRouteWithContext(ctx aws.Context, input *appmesh.CreateRouteInput, opts ...request.Option) (*appmesh.CreateRouteOutput, error) {
+	resource, err := m.create(fakeRoutePath(input.MeshName, input.VirtualRouterName, input.RouteName), input.Spec, input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.CreateRouteOutput{Route: resource.routeData(input.MeshName, input.VirtualRouterName, appmesh.RouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DescribeRouteWithContext(ctx aws.Context, input *appmesh.DescribeRouteInput, opts ...request.Option) (*appmesh.DescribeRouteOutput, error) {
+	resource, err := m.describe(fakeRoutePath(input.MeshName, input.VirtualRouterName, input.RouteName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DescribeRouteOutput{Route: resource.routeData(input.MeshName, input.VirtualRouterName, appmesh.RouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) UpdateRouteWithContext(ctx aws.Context, input *appmesh.UpdateRouteInput, opts ...request.Option) (*appmesh.UpdateRouteOutput, error) {
+	resource, err := m.update(fakeRoutePath(input.MeshName, input.VirtualRouterName, input.RouteName), input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.UpdateRouteOutput{Route: resource.routeData(input.MeshName, input.VirtualRouterName, appmesh.RouteStatusCodeActive)}, nil
+}
+
+func (m *inMemoryAppMesh) DeleteRouteWithContext(ctx aws.Context, input *appmesh.DeleteRouteInput, opts ...request.Option) (*appmesh.DeleteRouteOutput, error) {
+	resource, err := m.delete(fakeRoutePath(input.MeshName, input.VirtualRouterName, input.RouteName))
+	if err != nil {
+		return nil, err
+	}
+	return &appmesh.DeleteRouteOutput{Route: resource.routeData(input.MeshName, input.VirtualRouterName, appmesh.RouteStatusCodeDeleted)}, nil
+}
+
+func (m *inMemoryAppMesh) ListRoutesPagesWithContext(ctx aws.Context, input *appmesh.ListRoutesInput, fn func(*appmesh.ListRoutesOutput, bool) bool, opts ...request.Option) error {
+	resources, err := m.list(fakeMeshChildPath(input.MeshName, "virtualRouter", input.VirtualRouterName), "route")
+	if err != nil {
+		return err
+	}
+	output := &appmesh.ListRoutesOutput{}
+	for _, resource := range resources {
+		output.Routes = append(output.Routes, &appmesh.RouteRef{
+			Arn:               resource.metadata.Arn,
+			CreatedAt:         resource.metadata.CreatedAt,
+			LastUpdatedAt:     resource.metadata.LastUpdatedAt,
+			MeshName:          input.MeshName,
+			MeshOwner:         resource.metadata.MeshOwner,
+			ResourceOwner:     resource.metadata.ResourceOwner,
+			RouteName:         resource.name(),
+			Version:           resource.metadata.Version,
+			VirtualRouterName: input.VirtualRouterName,
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (r *fakeResource) routeData(meshName *string, virtualRouterName *string, status string) *appmesh.RouteData {
+	return &appmesh.RouteData{
+		MeshName:          meshName,
+		Metadata:          r.metadata,
+		RouteName:         r.name(),
+		Spec:              r.spec.(*appmesh.RouteSpec),
+		Status:            &appmesh.RouteStatus{Status: aws.String(status)},
+		VirtualRouterName: virtualRouterName,
+	}
+}
+
+// resourceByARN returns the resource with arn, callers must hold the mutex.
+func (m *inMemoryAppMesh) resourceByARN(arn *string) (*fakeResource, error) {
+	for _, resource := range m.resources {
+		if aws.StringValue(resource.metadata.Arn) == aws.StringValue(arn) {
+			return resource, nil
+		}
+	}
+	return nil, fakeNotFoundError(aws.StringValue(arn))
+}
+
+func (m *inMemoryAppMesh) TagResourceWithContext(ctx aws.Context, input *appmesh.TagResourceInput, opts ...request.Option) (*appmesh.TagResourceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resource, err := m.resourceByARN(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	var tags []*appmesh.TagRef
+	for _, tag := range resource.tags {
+		overridden := false
+		for _, newTag := range input.Tags {
+			if aws.StringValue(newTag.Key) == aws.StringValue(tag.Key) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			tags = append(tags, tag)
+		}
+	}
+	for _, newTag := range input.Tags {
+		tags = append(tags, awsutil.CopyOf(newTag).(*appmesh.TagRef))
+	}
+	resource.tags = tags
+	return &appmesh.TagResourceOutput{}, nil
+}
+
+func (m *inMemoryAppMesh) UntagResourceWithContext(ctx aws.Context, input *appmesh.UntagResourceInput, opts ...request.Option) (*appmesh.UntagResourceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resource, err := m.resourceByARN(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	var tags []*appmesh.TagRef
+	for _, tag := range resource.tags {
+		removed := false
+		for _, key := range input.TagKeys {
+			if aws.StringValue(key) == aws.StringValue(tag.Key) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			tags = append(tags, tag)
+		}
+	}
+	resource.tags = tags
+	return &appmesh.UntagResourceOutput{}, nil
+}
+
+func (m *inMemoryAppMesh) ListTagsForResourcePagesWithContext(ctx aws.Context, input *appmesh.ListTagsForResourceInput, fn func(*appmesh.ListTagsForResourceOutput, bool) bool, opts ...request.Option) error {
+	m.mutex.Lock()
+	resource, err := m.resourceByARN(input.ResourceArn)
+	var tags []*appmesh.TagRef
+	if err == nil {
+		for _, tag := range resource.tags {
+			tags = append(tags, awsutil.CopyOf(tag).(*appmesh.TagRef))
+		}
+	}
+	m.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	fn(&appmesh.ListTagsForResourceOutput{Tags: tags}, true)
+	return nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertAWSErrorCode(t *testing.T, wantCode string, err error) {
+	awsErr, ok := err.(awserr.Error)
+	if assert.True(t, ok, "expected awserr.Error, got %v", err) {
+		assert.Equal(t, wantCode, awsErr.Code())
+	}
+}
+
+func Test_inMemoryAppMesh_Mesh(t *testing.T) {
+	ctx := context.Background()
+	sdk := NewFakeAppMesh("222233334444", "us-west-2")
+
+	createResp, err := sdk.CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Spec:     &appmesh.MeshSpec{},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh", aws.StringValue(createResp.Mesh.Metadata.Arn))
+	assert.Equal(t, int64(1), aws.Int64Value(createResp.Mesh.Metadata.Version))
+	assert.Equal(t, "222233334444", aws.StringValue(createResp.Mesh.Metadata.MeshOwner))
+	assert.Equal(t, appmesh.MeshStatusCodeActive, aws.StringValue(createResp.Mesh.Status.Status))
+
+	_, err = sdk.CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{MeshName: aws.String("my-mesh")})
+	assertAWSErrorCode(t, appmesh.ErrCodeConflictException, err)
+
+	updateResp, err := sdk.UpdateMeshWithContext(ctx, &appmesh.UpdateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Spec: &appmesh.MeshSpec{
+			EgressFilter: &appmesh.EgressFilter{Type: aws.String(appmesh.EgressFilterTypeAllowAll)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), aws.Int64Value(updateResp.Mesh.Metadata.Version))
+
+	// mutating returned objects must not affect stored resources.
+	updateResp.Mesh.Spec.EgressFilter.Type = aws.String(appmesh.EgressFilterTypeDropAll)
+	describeResp, err := sdk.DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, appmesh.EgressFilterTypeAllowAll, aws.StringValue(describeResp.Mesh.Spec.EgressFilter.Type))
+	assert.Equal(t, createResp.Mesh.Metadata.Uid, describeResp.Mesh.Metadata.Uid)
+
+	deleteResp, err := sdk.DeleteMeshWithContext(ctx, &appmesh.DeleteMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, appmesh.MeshStatusCodeDeleted, aws.StringValue(deleteResp.Mesh.Status.Status))
+
+	_, err = sdk.DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assertAWSErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+}
+
+func Test_inMemoryAppMesh_RoutesOfVirtualRouter(t *testing.T) {
+	ctx := context.Background()
+	sdk := NewFakeAppMesh("222233334444", "us-west-2")
+
+	_, err := sdk.CreateVirtualRouterWithContext(ctx, &appmesh.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		Spec:              &appmesh.VirtualRouterSpec{},
+	})
+	assertAWSErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+
+	_, err = sdk.CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	_, err = sdk.CreateVirtualRouterWithContext(ctx, &appmesh.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+		Spec:              &appmesh.VirtualRouterSpec{},
+	})
+	assert.NoError(t, err)
+	for _, routeName := range []string{"route-b", "route-a"} {
+		createResp, err := sdk.CreateRouteWithContext(ctx, &appmesh.CreateRouteInput{
+			MeshName:          aws.String("my-mesh"),
+			VirtualRouterName: aws.String("my-vr"),
+			RouteName:         aws.String(routeName),
+			Spec:              &appmesh.RouteSpec{},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh/virtualRouter/my-vr/route/"+routeName, aws.StringValue(createResp.Route.Metadata.Arn))
+	}
+
+	var routeNames []string
+	err = sdk.ListRoutesPagesWithContext(ctx, &appmesh.ListRoutesInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+	}, func(output *appmesh.ListRoutesOutput, lastPage bool) bool {
+		for _, route := range output.Routes {
+			routeNames = append(routeNames, aws.StringValue(route.RouteName))
+		}
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"route-a", "route-b"}, routeNames)
+
+	err = sdk.ListVirtualRoutersPagesWithContext(ctx, &appmesh.ListVirtualRoutersInput{MeshName: aws.String("my-mesh")},
+		func(output *appmesh.ListVirtualRoutersOutput, lastPage bool) bool {
+			assert.Len(t, output.VirtualRouters, 1)
+			return true
+		})
+	assert.NoError(t, err)
+
+	_, err = sdk.DeleteVirtualRouterWithContext(ctx, &appmesh.DeleteVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-vr"),
+	})
+	assertAWSErrorCode(t, appmesh.ErrCodeResourceInUseException, err)
+	_, err = sdk.DeleteMeshWithContext(ctx, &appmesh.DeleteMeshInput{MeshName: aws.String("my-mesh")})
+	assertAWSErrorCode(t, appmesh.ErrCodeResourceInUseException, err)
+}
+
+func Test_inMemoryAppMesh_Tags(t *testing.T) {
+	ctx := context.Background()
+	sdk := NewFakeAppMesh("222233334444", "us-west-2")
+
+	createResp, err := sdk.CreateMeshWithContext(ctx, &appmesh.CreateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Tags: []*appmesh.TagRef{
+			{Key: aws.String("team"), Value: aws.String("a")},
+			{Key: aws.String("env"), Value: aws.String("dev")},
+		},
+	})
+	assert.NoError(t, err)
+	arn := createResp.Mesh.Metadata.Arn
+
+	_, err = sdk.TagResourceWithContext(ctx, &appmesh.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        []*appmesh.TagRef{{Key: aws.String("team"), Value: aws.String("b")}},
+	})
+	assert.NoError(t, err)
+	_, err = sdk.UntagResourceWithContext(ctx, &appmesh.UntagResourceInput{
+		ResourceArn: arn,
+		TagKeys:     []*string{aws.String("env")},
+	})
+	assert.NoError(t, err)
+
+	var tags []*appmesh.TagRef
+	err = sdk.ListTagsForResourcePagesWithContext(ctx, &appmesh.ListTagsForResourceInput{ResourceArn: arn},
+		func(output *appmesh.ListTagsForResourceOutput, lastPage bool) bool {
+			tags = append(tags, output.Tags...)
+			return true
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []*appmesh.TagRef{{Key: aws.String("team"), Value: aws.String("b")}}, tags)
+
+	_, err = sdk.TagResourceWithContext(ctx, &appmesh.TagResourceInput{
+		ResourceArn: aws.String("arn:aws:appmesh:us-west-2:222233334444:mesh/other-mesh"),
+	})
+	assertAWSErrorCode(t, appmesh.ErrCodeNotFoundException, err)
+}
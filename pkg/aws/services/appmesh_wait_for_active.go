@@ -0,0 +1,212 @@
+package services
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+)
+
+const (
+	// statusActive is the ACTIVE status code shared by all AppMesh resources.
+	statusActive = "ACTIVE"
+
+	waitForActiveInitialInterval = 500 * time.Millisecond
+	waitForActiveMaxInterval     = 5 * time.Second
+)
+
+// NewWaitForActiveAppMesh constructs new AppMesh implementation, whose Create calls poll the created resource until it's ACTIVE,
+// with exponential backoff for up to timeout, so resources referencing it aren't reconciled against a resource that isn't usable yet.
+// Create calls fail if the resource isn't ACTIVE within timeout, so they're retried.
+func NewWaitForActiveAppMesh(appMesh AppMesh, timeout time.Duration) *waitForActiveAppMesh {
+	return &waitForActiveAppMesh{
+		AppMesh:         appMesh,
+		timeout:         timeout,
+		initialInterval: waitForActiveInitialInterval,
+		maxInterval:     waitForActiveMaxInterval,
+	}
+}
+
+var _ AppMesh = &waitForActiveAppMesh{}
+
+type waitForActiveAppMesh struct {
+	AppMesh
+	timeout         time.Duration
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+// waitForActive describes resource until its status is ACTIVE, starting with the one returned by its Create call.
+func waitForActive[T any](w *waitForActiveAppMesh, ctx aws.Context, resourcePath string, created T, status func(T) string, describe func() (T, error)) (T, error) {
+	resource := created
+	deadline := time.Now().Add(w.timeout)
+	interval := w.initialInterval
+	for status(resource) != statusActive {
+		if !time.Now().Add(interval).Before(deadline) {
+			return resource, errors.Errorf("%s is not ACTIVE after %v, status: %s", resourcePath, w.timeout, status(resource))
+		}
+		select {
+		case <-ctx.Done():
+			return resource, errors.Wrapf(ctx.Err(), "failed to wait for %s to be ACTIVE", resourcePath)
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > w.maxInterval {
+			interval = w.maxInterval
+		}
+		described, err := describe()
+		if err != nil {
+			return resource, errors.Wrapf(err, "failed to wait for %s to be ACTIVE", resourcePath)
+		}
+		resource = described
+	}
+	return resource, nil
+}
+
+func (w *waitForActiveAppMesh) CreateMeshWithContext(ctx aws.Context, input *appmesh.CreateMeshInput, opts ...request.Option) (*appmesh.CreateMeshOutput, error) {
+	resp, err := w.AppMesh.CreateMeshWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.Mesh, err = waitForActive(w, ctx, meshPath(input.MeshName), resp.Mesh, func(ms *appmesh.MeshData) string {
+		return aws.StringValue(ms.Status.Status)
+	}, func() (*appmesh.MeshData, error) {
+		// the created mesh is owned by the caller, MeshOwner isn't specified upon creation.
+		resp, err := w.AppMesh.DescribeMeshWithContext(ctx, &appmesh.DescribeMeshInput{MeshName: input.MeshName})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Mesh, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateVirtualGatewayWithContext(ctx aws.Context, input *appmesh.CreateVirtualGatewayInput, opts ...request.Option) (*appmesh.CreateVirtualGatewayOutput, error) {
+	resp, err := w.AppMesh.CreateVirtualGatewayWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.VirtualGateway, err = waitForActive(w, ctx, virtualGatewayPath(input.MeshName, input.VirtualGatewayName), resp.VirtualGateway, func(vg *appmesh.VirtualGatewayData) string {
+		return aws.StringValue(vg.Status.Status)
+	}, func() (*appmesh.VirtualGatewayData, error) {
+		resp, err := w.AppMesh.DescribeVirtualGatewayWithContext(ctx, &appmesh.DescribeVirtualGatewayInput{
+			MeshName:           input.MeshName,
+			MeshOwner:          input.MeshOwner,
+			VirtualGatewayName: input.VirtualGatewayName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.VirtualGateway, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateGatewayRouteWithContext(ctx aws.Context, input *appmesh.CreateGatewayRouteInput, opts ...request.Option) (*appmesh.CreateGatewayRouteOutput, error) {
+	resp, err := w.AppMesh.CreateGatewayRouteWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.GatewayRoute, err = waitForActive(w, ctx, gatewayRoutePath(input.MeshName, input.VirtualGatewayName, input.GatewayRouteName), resp.GatewayRoute, func(gr *appmesh.GatewayRouteData) string {
+		return aws.StringValue(gr.Status.Status)
+	}, func() (*appmesh.GatewayRouteData, error) {
+		resp, err := w.AppMesh.DescribeGatewayRouteWithContext(ctx, &appmesh.DescribeGatewayRouteInput{
+			MeshName:           input.MeshName,
+			MeshOwner:          input.MeshOwner,
+			VirtualGatewayName: input.VirtualGatewayName,
+			GatewayRouteName:   input.GatewayRouteName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GatewayRoute, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateVirtualNodeWithContext(ctx aws.Context, input *appmesh.CreateVirtualNodeInput, opts ...request.Option) (*appmesh.CreateVirtualNodeOutput, error) {
+	resp, err := w.AppMesh.CreateVirtualNodeWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.VirtualNode, err = waitForActive(w, ctx, virtualNodePath(input.MeshName, input.VirtualNodeName), resp.VirtualNode, func(vn *appmesh.VirtualNodeData) string {
+		return aws.StringValue(vn.Status.Status)
+	}, func() (*appmesh.VirtualNodeData, error) {
+		resp, err := w.AppMesh.DescribeVirtualNodeWithContext(ctx, &appmesh.DescribeVirtualNodeInput{
+			MeshName:        input.MeshName,
+			MeshOwner:       input.MeshOwner,
+			VirtualNodeName: input.VirtualNodeName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.VirtualNode, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateVirtualServiceWithContext(ctx aws.Context, input *appmesh.CreateVirtualServiceInput, opts ...request.Option) (*appmesh.CreateVirtualServiceOutput, error) {
+	resp, err := w.AppMesh.CreateVirtualServiceWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.VirtualService, err = waitForActive(w, ctx, virtualServicePath(input.MeshName, input.VirtualServiceName), resp.VirtualService, func(vs *appmesh.VirtualServiceData) string {
+		return aws.StringValue(vs.Status.Status)
+	}, func() (*appmesh.VirtualServiceData, error) {
+		resp, err := w.AppMesh.DescribeVirtualServiceWithContext(ctx, &appmesh.DescribeVirtualServiceInput{
+			MeshName:           input.MeshName,
+			MeshOwner:          input.MeshOwner,
+			VirtualServiceName: input.VirtualServiceName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.VirtualService, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateVirtualRouterWithContext(ctx aws.Context, input *appmesh.CreateVirtualRouterInput, opts ...request.Option) (*appmesh.CreateVirtualRouterOutput, error) {
+	resp, err := w.AppMesh.CreateVirtualRouterWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.VirtualRouter, err = waitForActive(w, ctx, virtualRouterPath(input.MeshName, input.VirtualRouterName), resp.VirtualRouter, func(vr *appmesh.VirtualRouterData) string {
+		return aws.StringValue(vr.Status.Status)
+	}, func() (*appmesh.VirtualRouterData, error) {
+		resp, err := w.AppMesh.DescribeVirtualRouterWithContext(ctx, &appmesh.DescribeVirtualRouterInput{
+			MeshName:          input.MeshName,
+			MeshOwner:         input.MeshOwner,
+			VirtualRouterName: input.VirtualRouterName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.VirtualRouter, nil
+	})
+	return resp, err
+}
+
+func (w *waitForActiveAppMesh) CreateRouteWithContext(ctx aws.Context, input *appmesh.CreateRouteInput, opts ...request.Option) (*appmesh.CreateRouteOutput, error) {
+	resp, err := w.AppMesh.CreateRouteWithContext(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.Route, err = waitForActive(w, ctx, routePath(input.MeshName, input.VirtualRouterName, input.RouteName), resp.Route, func(route *appmesh.RouteData) string {
+		return aws.StringValue(route.Status.Status)
+	}, func() (*appmesh.RouteData, error) {
+		resp, err := w.AppMesh.DescribeRouteWithContext(ctx, &appmesh.DescribeRouteInput{
+			MeshName:          input.MeshName,
+			MeshOwner:         input.MeshOwner,
+			VirtualRouterName: input.VirtualRouterName,
+			RouteName:         input.RouteName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Route, nil
+	})
+	return resp, err
+}
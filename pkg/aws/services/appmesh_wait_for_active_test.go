@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeInactiveAppMesh creates a route with the first of statuses, and describes it with the following ones, repeating the last one.
+type fakeInactiveAppMesh struct {
+	AppMesh
+
+	statuses           []string
+	describeErr        error
+	describeRouteCalls int
+}
+
+func (f *fakeInactiveAppMesh) route(input *appmesh.DescribeRouteInput, status string) *appmesh.RouteData {
+	return &appmesh.RouteData{
+		MeshName:          input.MeshName,
+		VirtualRouterName: input.VirtualRouterName,
+		RouteName:         input.RouteName,
+		Status:            &appmesh.RouteStatus{Status: aws.String(status)},
+	}
+}
+
+func (f *fakeInactiveAppMesh) CreateRouteWithContext(ctx aws.Context, input *appmesh.CreateRouteInput, opts ...request.Option) (*appmesh.CreateRouteOutput, error) {
+	return &appmesh.CreateRouteOutput{
+		Route: f.route(&appmesh.DescribeRouteInput{MeshName: input.MeshName, VirtualRouterName: input.VirtualRouterName, RouteName: input.RouteName}, f.statuses[0]),
+	}, nil
+}
+
+func (f *fakeInactiveAppMesh) DescribeRouteWithContext(ctx aws.Context, input *appmesh.DescribeRouteInput, opts ...request.Option) (*appmesh.DescribeRouteOutput, error) {
+	f.describeRouteCalls++
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+	status := f.statuses[len(f.statuses)-1]
+	if f.describeRouteCalls < len(f.statuses) {
+		status = f.statuses[f.describeRouteCalls]
+	}
+	return &appmesh.DescribeRouteOutput{Route: f.route(input, status)}, nil
+}
+
+func Test_waitForActiveAppMesh_CreateRouteWithContext(t *testing.T) {
+	tests := []struct {
+		name                   string
+		statuses               []string
+		describeErr            error
+		wantStatus             string
+		wantDescribeRouteCalls int
+		wantErr                string
+	}{
+		{
+			name:                   "route is created ACTIVE",
+			statuses:               []string{"ACTIVE"},
+			wantStatus:             "ACTIVE",
+			wantDescribeRouteCalls: 0,
+		},
+		{
+			name:                   "route becomes ACTIVE",
+			statuses:               []string{"INACTIVE", "INACTIVE", "ACTIVE"},
+			wantStatus:             "ACTIVE",
+			wantDescribeRouteCalls: 2,
+		},
+		{
+			name:     "route doesn't become ACTIVE",
+			statuses: []string{"INACTIVE"},
+			wantErr:  "mesh/my-mesh/virtualRouter/my-router/route/my-route is not ACTIVE after 50ms, status: INACTIVE",
+		},
+		{
+			name:        "describe route fails",
+			statuses:    []string{"INACTIVE"},
+			describeErr: errors.New("oops"),
+			wantErr:     "failed to wait for mesh/my-mesh/virtualRouter/my-router/route/my-route to be ACTIVE: oops",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeInactiveAppMesh{statuses: tt.statuses, describeErr: tt.describeErr}
+			w := NewWaitForActiveAppMesh(fake, 50*time.Millisecond)
+			w.initialInterval = time.Millisecond
+			w.maxInterval = 4 * time.Millisecond
+
+			resp, err := w.CreateRouteWithContext(context.Background(), &appmesh.CreateRouteInput{
+				MeshName:          aws.String("my-mesh"),
+				VirtualRouterName: aws.String("my-router"),
+				RouteName:         aws.String("my-route"),
+			})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, aws.StringValue(resp.Route.Status.Status))
+			assert.Equal(t, tt.wantDescribeRouteCalls, fake.describeRouteCalls)
+		})
+	}
+}
+
+func Test_waitForActiveAppMesh_CreateRouteWithContext_canceled(t *testing.T) {
+	fake := &fakeInactiveAppMesh{statuses: []string{"INACTIVE"}}
+	w := NewWaitForActiveAppMesh(fake, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := w.CreateRouteWithContext(ctx, &appmesh.CreateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("my-router"),
+		RouteName:         aws.String("my-route"),
+	})
+	assert.EqualError(t, err, "failed to wait for mesh/my-mesh/virtualRouter/my-router/route/my-route to be ACTIVE: context canceled")
+	assert.Equal(t, 0, fake.describeRouteCalls)
+}
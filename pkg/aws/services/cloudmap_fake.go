@@ -0,0 +1,497 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/servicediscovery/servicediscoveryiface"
+)
+
+// NewFakeCloudMap constructs new in-memory CloudMap implementation, which behaves like CloudMap in accountID and region
+// for the operations used by the controller: namespaces, services and instances, their health status and operations, as well as tags.
+// Operations succeed upon being requested, and deleting a namespace or service that still contains services or instances fails with ResourceInUse.
+// Other operations aren't implemented and panic.
+func NewFakeCloudMap(accountID string, region string) CloudMap {
+	return &inMemoryCloudMap{
+		accountID:  accountID,
+		region:     region,
+		namespaces: make(map[string]*servicediscovery.NamespaceSummary),
+		services:   make(map[string]*fakeCloudMapService),
+		operations: make(map[string]*servicediscovery.Operation),
+		tags:       make(map[string][]*servicediscovery.Tag),
+		now:        time.Now,
+	}
+}
+
+var _ CloudMap = &inMemoryCloudMap{}
+
+type inMemoryCloudMap struct {
+	// ServiceDiscoveryAPI is left nil, so operations not implemented by the fake panic.
+	servicediscoveryiface.ServiceDiscoveryAPI
+
+	accountID string
+	region    string
+
+	mutex sync.Mutex
+	// namespaces by namespace ID.
+	namespaces map[string]*servicediscovery.NamespaceSummary
+	// services by service ID.
+	services map[string]*fakeCloudMapService
+	// operations by operation ID.
+	operations map[string]*servicediscovery.Operation
+	// tags by resource ARN.
+	tags map[string][]*servicediscovery.Tag
+	// idSequence generates resource and operation IDs.
+	idSequence int64
+	now        func() time.Time
+}
+
+// fakeCloudMapService is a CloudMap service stored by inMemoryCloudMap.
+type fakeCloudMapService struct {
+	service *servicediscovery.Service
+	// instances by instance ID.
+	instances map[string]*servicediscovery.Instance
+	// healthStatus by instance ID.
+	healthStatus map[string]string
+}
+
+// nextID generates the next ID with prefix, callers must hold the mutex.
+func (m *inMemoryCloudMap) nextID(prefix string) string {
+	m.idSequence++
+	return fmt.Sprintf("%s-fake%012d", prefix, m.idSequence)
+}
+
+func (m *inMemoryCloudMap) arn(resourceType string, id string) string {
+	return fmt.Sprintf("arn:aws:servicediscovery:%s:%s:%s/%s", m.region, m.accountID, resourceType, id)
+}
+
+// succeedOperation records an operation of operationType on targets that succeeded, callers must hold the mutex.
+func (m *inMemoryCloudMap) succeedOperation(operationType string, targets map[string]string) *string {
+	now := m.now()
+	operation := &servicediscovery.Operation{
+		Id:         aws.String(m.nextID("op")),
+		Type:       aws.String(operationType),
+		Status:     aws.String(servicediscovery.OperationStatusSuccess),
+		Targets:    aws.StringMap(targets),
+		CreateDate: aws.Time(now),
+		UpdateDate: aws.Time(now),
+	}
+	m.operations[aws.StringValue(operation.Id)] = operation
+	return operation.Id
+}
+
+// tagResource replaces the tags of the resource at arn with a copy of tags, callers must hold the mutex.
+func (m *inMemoryCloudMap) tagResource(arn string, tags []*servicediscovery.Tag) {
+	var copiedTags []*servicediscovery.Tag
+	for _, tag := range tags {
+		copiedTags = append(copiedTags, awsutil.CopyOf(tag).(*servicediscovery.Tag))
+	}
+	m.tags[arn] = copiedTags
+}
+
+// createNamespace stores a new namespace of namespaceType, whose name must be unique.
+func (m *inMemoryCloudMap) createNamespace(name *string, namespaceType string, description *string, properties *servicediscovery.NamespaceProperties, tags []*servicediscovery.Tag) *string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, namespace := range m.namespaces {
+		if aws.StringValue(namespace.Name) == aws.StringValue(name) {
+			return nil
+		}
+	}
+	id := m.nextID("ns")
+	namespace := &servicediscovery.NamespaceSummary{
+		Id:           aws.String(id),
+		Arn:          aws.String(m.arn("namespace", id)),
+		Name:         aws.String(aws.StringValue(name)),
+		Type:         aws.String(namespaceType),
+		Description:  awsutil.CopyOf(description).(*string),
+		Properties:   properties,
+		ServiceCount: aws.Int64(0),
+		CreateDate:   aws.Time(m.now()),
+	}
+	m.namespaces[id] = namespace
+	m.tagResource(aws.StringValue(namespace.Arn), tags)
+	return m.succeedOperation(servicediscovery.OperationTypeCreateNamespace, map[string]string{
+		servicediscovery.OperationTargetTypeNamespace: id,
+	})
+}
+
+func fakeNamespaceAlreadyExistsError(name *string) error {
+	return awserr.New(servicediscovery.ErrCodeNamespaceAlreadyExists, fmt.Sprintf("namespace %s already exists", aws.StringValue(name)), nil)
+}
+
+func (m *inMemoryCloudMap) CreatePrivateDnsNamespaceWithContext(ctx aws.Context, input *servicediscovery.CreatePrivateDnsNamespaceInput, opts ...request.Option) (*servicediscovery.CreatePrivateDnsNamespaceOutput, error) {
+	properties := &servicediscovery.NamespaceProperties{
+		DnsProperties: &servicediscovery.DnsProperties{HostedZoneId: aws.String("Z" + aws.StringValue(input.Name))},
+	}
+	operationID := m.createNamespace(input.Name, servicediscovery.NamespaceTypeDnsPrivate, input.Description, properties, input.Tags)
+	if operationID == nil {
+		return nil, fakeNamespaceAlreadyExistsError(input.Name)
+	}
+	return &servicediscovery.CreatePrivateDnsNamespaceOutput{OperationId: operationID}, nil
+}
+
+func (m *inMemoryCloudMap) CreateHttpNamespaceWithContext(ctx aws.Context, input *servicediscovery.CreateHttpNamespaceInput, opts ...request.Option) (*servicediscovery.CreateHttpNamespaceOutput, error) {
+	properties := &servicediscovery.NamespaceProperties{
+		HttpProperties: &servicediscovery.HttpProperties{HttpName: aws.String(aws.StringValue(input.Name))},
+	}
+	operationID := m.createNamespace(input.Name, servicediscovery.NamespaceTypeHttp, input.Description, properties, input.Tags)
+	if operationID == nil {
+		return nil, fakeNamespaceAlreadyExistsError(input.Name)
+	}
+	return &servicediscovery.CreateHttpNamespaceOutput{OperationId: operationID}, nil
+}
+
+func (m *inMemoryCloudMap) GetNamespaceWithContext(ctx aws.Context, input *servicediscovery.GetNamespaceInput, opts ...request.Option) (*servicediscovery.GetNamespaceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	namespace, ok := m.namespaces[aws.StringValue(input.Id)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeNamespaceNotFound, "namespace", input.Id)
+	}
+	return &servicediscovery.GetNamespaceOutput{
+		Namespace: &servicediscovery.Namespace{
+			Id:           namespace.Id,
+			Arn:          namespace.Arn,
+			Name:         namespace.Name,
+			Type:         namespace.Type,
+			Description:  namespace.Description,
+			Properties:   namespace.Properties,
+			ServiceCount: aws.Int64(m.serviceCount(namespace.Id)),
+			CreateDate:   namespace.CreateDate,
+		},
+	}, nil
+}
+
+func (m *inMemoryCloudMap) DeleteNamespaceWithContext(ctx aws.Context, input *servicediscovery.DeleteNamespaceInput, opts ...request.Option) (*servicediscovery.DeleteNamespaceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	namespace, ok := m.namespaces[aws.StringValue(input.Id)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeNamespaceNotFound, "namespace", input.Id)
+	}
+	if m.serviceCount(namespace.Id) != 0 {
+		return nil, awserr.New(servicediscovery.ErrCodeResourceInUse, fmt.Sprintf("namespace %s contains services", aws.StringValue(input.Id)), nil)
+	}
+	delete(m.namespaces, aws.StringValue(input.Id))
+	delete(m.tags, aws.StringValue(namespace.Arn))
+	return &servicediscovery.DeleteNamespaceOutput{
+		OperationId: m.succeedOperation(servicediscovery.OperationTypeDeleteNamespace, map[string]string{
+			servicediscovery.OperationTargetTypeNamespace: aws.StringValue(input.Id),
+		}),
+	}, nil
+}
+
+func (m *inMemoryCloudMap) ListNamespacesPagesWithContext(ctx aws.Context, input *servicediscovery.ListNamespacesInput, fn func(*servicediscovery.ListNamespacesOutput, bool) bool, opts ...request.Option) error {
+	m.mutex.Lock()
+	output := &servicediscovery.ListNamespacesOutput{}
+	for _, id := range sortedKeys(m.namespaces) {
+		namespace := awsutil.CopyOf(m.namespaces[id]).(*servicediscovery.NamespaceSummary)
+		namespace.ServiceCount = aws.Int64(m.serviceCount(namespace.Id))
+		output.Namespaces = append(output.Namespaces, namespace)
+	}
+	m.mutex.Unlock()
+	fn(output, true)
+	return nil
+}
+
+// serviceCount returns the number of services in the namespace, callers must hold the mutex.
+func (m *inMemoryCloudMap) serviceCount(namespaceID *string) int64 {
+	var count int64
+	for _, service := range m.services {
+		if aws.StringValue(service.service.NamespaceId) == aws.StringValue(namespaceID) {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *inMemoryCloudMap) CreateServiceWithContext(ctx aws.Context, input *servicediscovery.CreateServiceInput, opts ...request.Option) (*servicediscovery.CreateServiceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.namespaces[aws.StringValue(input.NamespaceId)]; !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeNamespaceNotFound, "namespace", input.NamespaceId)
+	}
+	for _, service := range m.services {
+		if aws.StringValue(service.service.NamespaceId) == aws.StringValue(input.NamespaceId) && aws.StringValue(service.service.Name) == aws.StringValue(input.Name) {
+			return nil, awserr.New(servicediscovery.ErrCodeServiceAlreadyExists, fmt.Sprintf("service %s already exists", aws.StringValue(input.Name)), nil)
+		}
+	}
+	id := m.nextID("srv")
+	serviceType := servicediscovery.ServiceTypeHttp
+	if input.DnsConfig != nil {
+		serviceType = servicediscovery.ServiceTypeDnsHttp
+	}
+	service := &servicediscovery.Service{
+		Id:                      aws.String(id),
+		Arn:                     aws.String(m.arn("service", id)),
+		Name:                    aws.String(aws.StringValue(input.Name)),
+		NamespaceId:             aws.String(aws.StringValue(input.NamespaceId)),
+		Type:                    aws.String(serviceType),
+		Description:             awsutil.CopyOf(input.Description).(*string),
+		CreatorRequestId:        awsutil.CopyOf(input.CreatorRequestId).(*string),
+		DnsConfig:               awsutil.CopyOf(input.DnsConfig).(*servicediscovery.DnsConfig),
+		HealthCheckConfig:       awsutil.CopyOf(input.HealthCheckConfig).(*servicediscovery.HealthCheckConfig),
+		HealthCheckCustomConfig: awsutil.CopyOf(input.HealthCheckCustomConfig).(*servicediscovery.HealthCheckCustomConfig),
+		InstanceCount:           aws.Int64(0),
+		CreateDate:              aws.Time(m.now()),
+	}
+	m.services[id] = &fakeCloudMapService{
+		service:      service,
+		instances:    make(map[string]*servicediscovery.Instance),
+		healthStatus: make(map[string]string),
+	}
+	m.tagResource(aws.StringValue(service.Arn), input.Tags)
+	return &servicediscovery.CreateServiceOutput{Service: m.services[id].copy()}, nil
+}
+
+func (m *inMemoryCloudMap) GetServiceWithContext(ctx aws.Context, input *servicediscovery.GetServiceInput, opts ...request.Option) (*servicediscovery.GetServiceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &servicediscovery.GetServiceOutput{Service: service.copy()}, nil
+}
+
+func (m *inMemoryCloudMap) DeleteServiceWithContext(ctx aws.Context, input *servicediscovery.DeleteServiceInput, opts ...request.Option) (*servicediscovery.DeleteServiceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(service.instances) != 0 {
+		return nil, awserr.New(servicediscovery.ErrCodeResourceInUse, fmt.Sprintf("service %s contains instances", aws.StringValue(input.Id)), nil)
+	}
+	delete(m.services, aws.StringValue(input.Id))
+	delete(m.tags, aws.StringValue(service.service.Arn))
+	return &servicediscovery.DeleteServiceOutput{}, nil
+}
+
+func (m *inMemoryCloudMap) ListServicesPagesWithContext(ctx aws.Context, input *servicediscovery.ListServicesInput, fn func(*servicediscovery.ListServicesOutput, bool) bool, opts ...request.Option) error {
+	m.mutex.Lock()
+	output := &servicediscovery.ListServicesOutput{}
+	for _, id := range sortedKeys(m.services) {
+		service := m.services[id].copy()
+		if !matchesServiceFilters(service, input.Filters) {
+			continue
+		}
+		output.Services = append(output.Services, &servicediscovery.ServiceSummary{
+			Id:                      service.Id,
+			Arn:                     service.Arn,
+			Name:                    service.Name,
+			Type:                    service.Type,
+			Description:             service.Description,
+			DnsConfig:               service.DnsConfig,
+			HealthCheckConfig:       service.HealthCheckConfig,
+			HealthCheckCustomConfig: service.HealthCheckCustomConfig,
+			InstanceCount:           service.InstanceCount,
+			CreateDate:              service.CreateDate,
+		})
+	}
+	m.mutex.Unlock()
+	fn(output, true)
+	return nil
+}
+
+// matchesServiceFilters checks whether service matches all filters, of which only NAMESPACE_ID is supported.
+func matchesServiceFilters(service *servicediscovery.Service, filters []*servicediscovery.ServiceFilter) bool {
+	for _, filter := range filters {
+		if aws.StringValue(filter.Name) != servicediscovery.ServiceFilterNameNamespaceId {
+			continue
+		}
+		matched := false
+		for _, value := range filter.Values {
+			if aws.StringValue(value) == aws.StringValue(service.NamespaceId) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// service returns the service with id, callers must hold the mutex.
+func (m *inMemoryCloudMap) service(id *string) (*fakeCloudMapService, error) {
+	service, ok := m.services[aws.StringValue(id)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeServiceNotFound, "service", id)
+	}
+	return service, nil
+}
+
+func (s *fakeCloudMapService) copy() *servicediscovery.Service {
+	service := awsutil.CopyOf(s.service).(*servicediscovery.Service)
+	service.InstanceCount = aws.Int64(int64(len(s.instances)))
+	return service
+}
+
+func (m *inMemoryCloudMap) RegisterInstanceWithContext(ctx aws.Context, input *servicediscovery.RegisterInstanceInput, opts ...request.Option) (*servicediscovery.RegisterInstanceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	instanceID := aws.StringValue(input.InstanceId)
+	service.instances[instanceID] = &servicediscovery.Instance{
+		Id:               aws.String(instanceID),
+		CreatorRequestId: awsutil.CopyOf(input.CreatorRequestId).(*string),
+		Attributes:       aws.StringMap(aws.StringValueMap(input.Attributes)),
+	}
+	if _, ok := service.healthStatus[instanceID]; !ok {
+		service.healthStatus[instanceID] = servicediscovery.HealthStatusHealthy
+	}
+	return &servicediscovery.RegisterInstanceOutput{
+		OperationId: m.succeedOperation(servicediscovery.OperationTypeRegisterInstance, map[string]string{
+			servicediscovery.OperationTargetTypeService:  aws.StringValue(input.ServiceId),
+			servicediscovery.OperationTargetTypeInstance: instanceID,
+		}),
+	}, nil
+}
+
+func (m *inMemoryCloudMap) DeregisterInstanceWithContext(ctx aws.Context, input *servicediscovery.DeregisterInstanceInput, opts ...request.Option) (*servicediscovery.DeregisterInstanceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	instanceID := aws.StringValue(input.InstanceId)
+	if _, ok := service.instances[instanceID]; !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeInstanceNotFound, "instance", input.InstanceId)
+	}
+	delete(service.instances, instanceID)
+	delete(service.healthStatus, instanceID)
+	return &servicediscovery.DeregisterInstanceOutput{
+		OperationId: m.succeedOperation(servicediscovery.OperationTypeDeregisterInstance, map[string]string{
+			servicediscovery.OperationTargetTypeService:  aws.StringValue(input.ServiceId),
+			servicediscovery.OperationTargetTypeInstance: instanceID,
+		}),
+	}, nil
+}
+
+func (m *inMemoryCloudMap) GetInstanceWithContext(ctx aws.Context, input *servicediscovery.GetInstanceInput, opts ...request.Option) (*servicediscovery.GetInstanceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	instance, ok := service.instances[aws.StringValue(input.InstanceId)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeInstanceNotFound, "instance", input.InstanceId)
+	}
+	return &servicediscovery.GetInstanceOutput{Instance: awsutil.CopyOf(instance).(*servicediscovery.Instance)}, nil
+}
+
+func (m *inMemoryCloudMap) ListInstancesPagesWithContext(ctx aws.Context, input *servicediscovery.ListInstancesInput, fn func(*servicediscovery.ListInstancesOutput, bool) bool, opts ...request.Option) error {
+	m.mutex.Lock()
+	service, err := m.service(input.ServiceId)
+	output := &servicediscovery.ListInstancesOutput{}
+	if err == nil {
+		for _, id := range sortedKeys(service.instances) {
+			instance := awsutil.CopyOf(service.instances[id]).(*servicediscovery.Instance)
+			output.Instances = append(output.Instances, &servicediscovery.InstanceSummary{
+				Id:         instance.Id,
+				Attributes: instance.Attributes,
+			})
+		}
+	}
+	m.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+func (m *inMemoryCloudMap) GetInstancesHealthStatusPagesWithContext(ctx aws.Context, input *servicediscovery.GetInstancesHealthStatusInput, fn func(*servicediscovery.GetInstancesHealthStatusOutput, bool) bool, opts ...request.Option) error {
+	m.mutex.Lock()
+	service, err := m.service(input.ServiceId)
+	output := &servicediscovery.GetInstancesHealthStatusOutput{Status: make(map[string]*string)}
+	if err == nil {
+		instanceIDs := aws.StringValueSlice(input.Instances)
+		if len(instanceIDs) == 0 {
+			instanceIDs = sortedKeys(service.instances)
+		}
+		for _, instanceID := range instanceIDs {
+			if status, ok := service.healthStatus[instanceID]; ok {
+				output.Status[instanceID] = aws.String(status)
+			}
+		}
+	}
+	m.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
+
+func (m *inMemoryCloudMap) UpdateInstanceCustomHealthStatusWithContext(ctx aws.Context, input *servicediscovery.UpdateInstanceCustomHealthStatusInput, opts ...request.Option) (*servicediscovery.UpdateInstanceCustomHealthStatusOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	service, err := m.service(input.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	if service.service.HealthCheckCustomConfig == nil {
+		return nil, awserr.New(servicediscovery.ErrCodeCustomHealthNotFound, fmt.Sprintf("service %s doesn't configure custom health checks", aws.StringValue(input.ServiceId)), nil)
+	}
+	instanceID := aws.StringValue(input.InstanceId)
+	if _, ok := service.instances[instanceID]; !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeInstanceNotFound, "instance", input.InstanceId)
+	}
+	service.healthStatus[instanceID] = aws.StringValue(input.Status)
+	return &servicediscovery.UpdateInstanceCustomHealthStatusOutput{}, nil
+}
+
+func (m *inMemoryCloudMap) GetOperationWithContext(ctx aws.Context, input *servicediscovery.GetOperationInput, opts ...request.Option) (*servicediscovery.GetOperationOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	operation, ok := m.operations[aws.StringValue(input.OperationId)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeOperationNotFound, "operation", input.OperationId)
+	}
+	return &servicediscovery.GetOperationOutput{Operation: awsutil.CopyOf(operation).(*servicediscovery.Operation)}, nil
+}
+
+func (m *inMemoryCloudMap) ListTagsForResourceWithContext(ctx aws.Context, input *servicediscovery.ListTagsForResourceInput, opts ...request.Option) (*servicediscovery.ListTagsForResourceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	tags, ok := m.tags[aws.StringValue(input.ResourceARN)]
+	if !ok {
+		return nil, fakeCloudMapNotFoundError(servicediscovery.ErrCodeResourceNotFoundException, "resource", input.ResourceARN)
+	}
+	output := &servicediscovery.ListTagsForResourceOutput{Tags: []*servicediscovery.Tag{}}
+	for _, tag := range tags {
+		output.Tags = append(output.Tags, awsutil.CopyOf(tag).(*servicediscovery.Tag))
+	}
+	return output, nil
+}
+
+func fakeCloudMapNotFoundError(code string, resourceType string, id *string) error {
+	return awserr.New(code, fmt.Sprintf("%s %s not found", resourceType, aws.StringValue(id)), nil)
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
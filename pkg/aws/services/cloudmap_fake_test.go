@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inMemoryCloudMap_NamespacesAndServices(t *testing.T) {
+	ctx := context.Background()
+	sdk := NewFakeCloudMap("222233334444", "us-west-2")
+
+	createNSResp, err := sdk.CreatePrivateDnsNamespaceWithContext(ctx, &servicediscovery.CreatePrivateDnsNamespaceInput{
+		Name: aws.String("my-ns.local"),
+		Vpc:  aws.String("vpc-1"),
+		Tags: []*servicediscovery.Tag{{Key: aws.String("k"), Value: aws.String("v")}},
+	})
+	assert.NoError(t, err)
+	opResp, err := sdk.GetOperationWithContext(ctx, &servicediscovery.GetOperationInput{OperationId: createNSResp.OperationId})
+	assert.NoError(t, err)
+	assert.Equal(t, servicediscovery.OperationStatusSuccess, aws.StringValue(opResp.Operation.Status))
+	nsID := opResp.Operation.Targets[servicediscovery.OperationTargetTypeNamespace]
+
+	_, err = sdk.CreatePrivateDnsNamespaceWithContext(ctx, &servicediscovery.CreatePrivateDnsNamespaceInput{Name: aws.String("my-ns.local")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeNamespaceAlreadyExists, err)
+
+	var namespaces []*servicediscovery.NamespaceSummary
+	assert.NoError(t, sdk.ListNamespacesPagesWithContext(ctx, &servicediscovery.ListNamespacesInput{}, func(output *servicediscovery.ListNamespacesOutput, lastPage bool) bool {
+		namespaces = append(namespaces, output.Namespaces...)
+		return true
+	}))
+	if assert.Len(t, namespaces, 1) {
+		assert.Equal(t, aws.StringValue(nsID), aws.StringValue(namespaces[0].Id))
+		assert.Equal(t, servicediscovery.NamespaceTypeDnsPrivate, aws.StringValue(namespaces[0].Type))
+		assert.Equal(t, "arn:aws:servicediscovery:us-west-2:222233334444:namespace/"+aws.StringValue(nsID), aws.StringValue(namespaces[0].Arn))
+	}
+	tagsResp, err := sdk.ListTagsForResourceWithContext(ctx, &servicediscovery.ListTagsForResourceInput{ResourceARN: namespaces[0].Arn})
+	assert.NoError(t, err)
+	assert.Equal(t, []*servicediscovery.Tag{{Key: aws.String("k"), Value: aws.String("v")}}, tagsResp.Tags)
+
+	_, err = sdk.CreateServiceWithContext(ctx, &servicediscovery.CreateServiceInput{NamespaceId: aws.String("ns-unknown"), Name: aws.String("my-svc")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeNamespaceNotFound, err)
+	createSvcResp, err := sdk.CreateServiceWithContext(ctx, &servicediscovery.CreateServiceInput{
+		NamespaceId: nsID,
+		Name:        aws.String("my-svc"),
+		DnsConfig: &servicediscovery.DnsConfig{
+			DnsRecords: []*servicediscovery.DnsRecord{{Type: aws.String(servicediscovery.RecordTypeA), TTL: aws.Int64(300)}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, servicediscovery.ServiceTypeDnsHttp, aws.StringValue(createSvcResp.Service.Type))
+	_, err = sdk.CreateServiceWithContext(ctx, &servicediscovery.CreateServiceInput{NamespaceId: nsID, Name: aws.String("my-svc")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeServiceAlreadyExists, err)
+
+	var services []*servicediscovery.ServiceSummary
+	assert.NoError(t, sdk.ListServicesPagesWithContext(ctx, &servicediscovery.ListServicesInput{
+		Filters: []*servicediscovery.ServiceFilter{{Name: aws.String(servicediscovery.ServiceFilterNameNamespaceId), Values: []*string{nsID}}},
+	}, func(output *servicediscovery.ListServicesOutput, lastPage bool) bool {
+		services = append(services, output.Services...)
+		return true
+	}))
+	if assert.Len(t, services, 1) {
+		assert.Equal(t, "my-svc", aws.StringValue(services[0].Name))
+	}
+
+	_, err = sdk.DeleteNamespaceWithContext(ctx, &servicediscovery.DeleteNamespaceInput{Id: nsID})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeResourceInUse, err)
+	_, err = sdk.DeleteServiceWithContext(ctx, &servicediscovery.DeleteServiceInput{Id: createSvcResp.Service.Id})
+	assert.NoError(t, err)
+	_, err = sdk.GetServiceWithContext(ctx, &servicediscovery.GetServiceInput{Id: createSvcResp.Service.Id})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeServiceNotFound, err)
+	_, err = sdk.DeleteNamespaceWithContext(ctx, &servicediscovery.DeleteNamespaceInput{Id: nsID})
+	assert.NoError(t, err)
+	_, err = sdk.DeleteNamespaceWithContext(ctx, &servicediscovery.DeleteNamespaceInput{Id: nsID})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeNamespaceNotFound, err)
+}
+
+func Test_inMemoryCloudMap_Instances(t *testing.T) {
+	ctx := context.Background()
+	sdk := NewFakeCloudMap("222233334444", "us-west-2")
+	createNSResp, err := sdk.CreateHttpNamespaceWithContext(ctx, &servicediscovery.CreateHttpNamespaceInput{Name: aws.String("my-ns")})
+	assert.NoError(t, err)
+	opResp, err := sdk.GetOperationWithContext(ctx, &servicediscovery.GetOperationInput{OperationId: createNSResp.OperationId})
+	assert.NoError(t, err)
+	createSvcResp, err := sdk.CreateServiceWithContext(ctx, &servicediscovery.CreateServiceInput{
+		NamespaceId:             opResp.Operation.Targets[servicediscovery.OperationTargetTypeNamespace],
+		Name:                    aws.String("my-svc"),
+		HealthCheckCustomConfig: &servicediscovery.HealthCheckCustomConfig{FailureThreshold: aws.Int64(1)},
+	})
+	assert.NoError(t, err)
+	serviceID := createSvcResp.Service.Id
+
+	registerResp, err := sdk.RegisterInstanceWithContext(ctx, &servicediscovery.RegisterInstanceInput{
+		ServiceId:  serviceID,
+		InstanceId: aws.String("192.168.0.1"),
+		Attributes: aws.StringMap(map[string]string{"AWS_INSTANCE_IPV4": "192.168.0.1"}),
+	})
+	assert.NoError(t, err)
+	opResp, err = sdk.GetOperationWithContext(ctx, &servicediscovery.GetOperationInput{OperationId: registerResp.OperationId})
+	assert.NoError(t, err)
+	assert.Equal(t, servicediscovery.OperationStatusSuccess, aws.StringValue(opResp.Operation.Status))
+
+	var instances []*servicediscovery.InstanceSummary
+	assert.NoError(t, sdk.ListInstancesPagesWithContext(ctx, &servicediscovery.ListInstancesInput{ServiceId: serviceID}, func(output *servicediscovery.ListInstancesOutput, lastPage bool) bool {
+		instances = append(instances, output.Instances...)
+		return true
+	}))
+	assert.Equal(t, []*servicediscovery.InstanceSummary{
+		{Id: aws.String("192.168.0.1"), Attributes: aws.StringMap(map[string]string{"AWS_INSTANCE_IPV4": "192.168.0.1"})},
+	}, instances)
+
+	_, err = sdk.UpdateInstanceCustomHealthStatusWithContext(ctx, &servicediscovery.UpdateInstanceCustomHealthStatusInput{
+		ServiceId:  serviceID,
+		InstanceId: aws.String("192.168.0.1"),
+		Status:     aws.String(servicediscovery.CustomHealthStatusUnhealthy),
+	})
+	assert.NoError(t, err)
+	status := make(map[string]string)
+	assert.NoError(t, sdk.GetInstancesHealthStatusPagesWithContext(ctx, &servicediscovery.GetInstancesHealthStatusInput{
+		ServiceId: serviceID,
+		Instances: aws.StringSlice([]string{"192.168.0.1", "192.168.0.2"}),
+	}, func(output *servicediscovery.GetInstancesHealthStatusOutput, lastPage bool) bool {
+		for instanceID, instanceStatus := range output.Status {
+			status[instanceID] = aws.StringValue(instanceStatus)
+		}
+		return true
+	}))
+	assert.Equal(t, map[string]string{"192.168.0.1": servicediscovery.HealthStatusUnhealthy}, status)
+
+	_, err = sdk.DeleteServiceWithContext(ctx, &servicediscovery.DeleteServiceInput{Id: serviceID})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeResourceInUse, err)
+	_, err = sdk.DeregisterInstanceWithContext(ctx, &servicediscovery.DeregisterInstanceInput{ServiceId: serviceID, InstanceId: aws.String("192.168.0.1")})
+	assert.NoError(t, err)
+	_, err = sdk.GetInstanceWithContext(ctx, &servicediscovery.GetInstanceInput{ServiceId: serviceID, InstanceId: aws.String("192.168.0.1")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeInstanceNotFound, err)
+	_, err = sdk.DeregisterInstanceWithContext(ctx, &servicediscovery.DeregisterInstanceInput{ServiceId: serviceID, InstanceId: aws.String("192.168.0.1")})
+	assertAWSErrorCode(t, servicediscovery.ErrCodeInstanceNotFound, err)
+	_, err = sdk.DeleteServiceWithContext(ctx, &servicediscovery.DeleteServiceInput{Id: serviceID})
+	assert.NoError(t, err)
+}
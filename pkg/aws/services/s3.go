@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type S3 interface {
+	s3iface.S3API
+}
+
+// NewS3 constructs new S3 implementation.
+func NewS3(session *session.Session) S3 {
+	return &defaultS3{
+		S3API: s3.New(session),
+	}
+}
+
+type defaultS3 struct {
+	s3iface.S3API
+}
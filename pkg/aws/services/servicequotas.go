@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+)
+
+type ServiceQuotas interface {
+	servicequotasiface.ServiceQuotasAPI
+}
+
+// NewServiceQuotas constructs new ServiceQuotas implementation.
+func NewServiceQuotas(session *session.Session) ServiceQuotas {
+	return &defaultServiceQuotas{
+		ServiceQuotasAPI: servicequotas.New(session),
+	}
+}
+
+type defaultServiceQuotas struct {
+	servicequotasiface.ServiceQuotasAPI
+}
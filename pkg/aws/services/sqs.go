@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+type SQS interface {
+	sqsiface.SQSAPI
+}
+
+// NewSQS constructs new SQS implementation.
+func NewSQS(session *session.Session) SQS {
+	return &defaultSQS{
+		SQSAPI: sqs.New(session),
+	}
+}
+
+type defaultSQS struct {
+	sqsiface.SQSAPI
+}
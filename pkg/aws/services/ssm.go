@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+)
+
+type SSM interface {
+	ssmiface.SSMAPI
+}
+
+// NewSSM constructs new SSM implementation.
+func NewSSM(session *session.Session) SSM {
+	return &defaultSSM{
+		SSMAPI: ssm.New(session),
+	}
+}
+
+type defaultSSM struct {
+	ssmiface.SSMAPI
+}
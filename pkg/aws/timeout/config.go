@@ -0,0 +1,95 @@
+package timeout
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type timeoutConfig struct {
+	operationPtn *regexp.Regexp
+	timeout      time.Duration
+}
+
+var _ pflag.Value = &ServiceOperationsTimeoutConfig{}
+
+// ServiceOperationsTimeoutConfig is timeoutConfig for each service's operations.
+// It supports to be configured using flags with format like "${serviceID}:${operationRegex}=${timeout}"
+// e.g. "App Mesh:^Describe=10s,App Mesh:^List=30s"
+// The timeout of a call includes its retries, the first matching timeout of a call's service applies.
+type ServiceOperationsTimeoutConfig struct {
+	// service:operationRegex:config
+	value map[string][]timeoutConfig
+}
+
+func (c *ServiceOperationsTimeoutConfig) String() string {
+	if c == nil {
+		return ""
+	}
+
+	var configs []string
+	var serviceIDs []string
+	for serviceID := range c.value {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Strings(serviceIDs)
+	for _, serviceID := range serviceIDs {
+		for _, operationsTimeoutConfig := range c.value[serviceID] {
+			configs = append(configs, fmt.Sprintf("%s:%s=%v",
+				serviceID,
+				operationsTimeoutConfig.operationPtn.String(),
+				operationsTimeoutConfig.timeout,
+			))
+		}
+	}
+	return strings.Join(configs, ",")
+}
+
+func (c *ServiceOperationsTimeoutConfig) Set(val string) error {
+	value := make(map[string][]timeoutConfig)
+	configPairs := strings.Split(val, ",")
+	for _, pair := range configPairs {
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 {
+			return errors.Errorf("%s must be formatted as serviceID:operationRegex=timeout", pair)
+		}
+		serviceIDOperationRegexPair := strings.Split(kv[0], ":")
+		if len(serviceIDOperationRegexPair) != 2 {
+			return errors.Errorf("%s must be formatted as serviceID:operationRegex", kv[0])
+		}
+		serviceID := serviceIDOperationRegexPair[0]
+		operationPtn, err := regexp.Compile(serviceIDOperationRegexPair[1])
+		if err != nil {
+			return errors.Errorf("%s must be valid regex expression for operation", serviceIDOperationRegexPair[1])
+		}
+		timeout, err := time.ParseDuration(kv[1])
+		if err != nil || timeout <= 0 {
+			return errors.Errorf("%s must be valid positive duration as timeout for operations", kv[1])
+		}
+		value[serviceID] = append(value[serviceID], timeoutConfig{
+			operationPtn: operationPtn,
+			timeout:      timeout,
+		})
+	}
+	c.value = value
+	return nil
+}
+
+func (c *ServiceOperationsTimeoutConfig) Type() string {
+	return "serviceOperationsTimeoutConfig"
+}
+
+// timeoutFor returns the timeout of operation of serviceID, if any.
+func (c *ServiceOperationsTimeoutConfig) timeoutFor(serviceID string, operation string) (time.Duration, bool) {
+	for _, operationsTimeoutConfig := range c.value[serviceID] {
+		if operationsTimeoutConfig.operationPtn.MatchString(operation) {
+			return operationsTimeoutConfig.timeout, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,75 @@
+package timeout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceOperationsTimeoutConfig_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		val        string
+		wantString string
+		wantErr    string
+	}{
+		{
+			name:       "timeouts of operations",
+			val:        "App Mesh:^Describe=10s,App Mesh:^List=30s,ServiceDiscovery:.*=1m",
+			wantString: "App Mesh:^Describe=10s,App Mesh:^List=30s,ServiceDiscovery:.*=1m0s",
+		},
+		{
+			name:    "missing timeout",
+			val:     "App Mesh:^Describe",
+			wantErr: "App Mesh:^Describe must be formatted as serviceID:operationRegex=timeout",
+		},
+		{
+			name:    "missing operation",
+			val:     "App Mesh=10s",
+			wantErr: "App Mesh must be formatted as serviceID:operationRegex",
+		},
+		{
+			name:    "invalid operation regex",
+			val:     "App Mesh:(=10s",
+			wantErr: "( must be valid regex expression for operation",
+		},
+		{
+			name:    "invalid timeout",
+			val:     "App Mesh:^Describe=10",
+			wantErr: "10 must be valid positive duration as timeout for operations",
+		},
+		{
+			name:    "non-positive timeout",
+			val:     "App Mesh:^Describe=0s",
+			wantErr: "0s must be valid positive duration as timeout for operations",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ServiceOperationsTimeoutConfig{}
+			err := c.Set(tt.val)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantString, c.String())
+		})
+	}
+}
+
+func TestServiceOperationsTimeoutConfig_timeoutFor(t *testing.T) {
+	c := &ServiceOperationsTimeoutConfig{}
+	assert.NoError(t, c.Set("App Mesh:^Describe=10s,App Mesh:.*=30s"))
+
+	timeout, ok := c.timeoutFor(appmesh.ServiceID, "DescribeVirtualNode")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, timeout)
+	timeout, ok = c.timeoutFor(appmesh.ServiceID, "ListRoutes")
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, timeout)
+	_, ok = c.timeoutFor("ServiceDiscovery", "ListServices")
+	assert.False(t, ok)
+}
@@ -0,0 +1,44 @@
+package timeout
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const sdkHandlerRequestTimeout = "requestTimeout"
+
+type timeouter struct {
+	config *ServiceOperationsTimeoutConfig
+}
+
+// NewTimeouter constructs new request timeouter instance, which cancels calls that take longer than their configured timeout.
+func NewTimeouter(config *ServiceOperationsTimeoutConfig) *timeouter {
+	return &timeouter{
+		config: config,
+	}
+}
+
+func (t *timeouter) InjectHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerRequestTimeout,
+		Fn:   t.beforeValidate,
+	})
+}
+
+// beforeValidate is added to the Validate chain; called once before each call, ahead of its attempts.
+func (t *timeouter) beforeValidate(r *request.Request) {
+	if r.Operation == nil {
+		return
+	}
+	timeout, ok := t.config.timeoutFor(r.ClientInfo.ServiceID, r.Operation.Name)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	r.SetContext(ctx)
+	// r.Handlers is a copy of the handlers of this request only.
+	r.Handlers.Complete.PushBack(func(*request.Request) {
+		cancel()
+	})
+}
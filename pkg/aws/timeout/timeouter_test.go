@@ -0,0 +1,55 @@
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_timeouter_beforeValidate(t *testing.T) {
+	config := &ServiceOperationsTimeoutConfig{}
+	assert.NoError(t, config.Set("App Mesh:^Describe=10s"))
+	tests := []struct {
+		name         string
+		operation    string
+		wantDeadline bool
+	}{
+		{
+			name:         "operation with timeout",
+			operation:    "DescribeVirtualNode",
+			wantDeadline: true,
+		},
+		{
+			name:         "operation without timeout",
+			operation:    "ListRoutes",
+			wantDeadline: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &request.Request{
+				ClientInfo:  metadata.ClientInfo{ServiceID: appmesh.ServiceID},
+				Operation:   &request.Operation{Name: tt.operation},
+				HTTPRequest: &http.Request{},
+			}
+			r.SetContext(context.Background())
+			timeouter := NewTimeouter(config)
+			timeouter.beforeValidate(r)
+
+			deadline, ok := r.Context().Deadline()
+			assert.Equal(t, tt.wantDeadline, ok)
+			if !tt.wantDeadline {
+				return
+			}
+			assert.WithinDuration(t, time.Now().Add(10*time.Second), deadline, time.Second)
+			r.Handlers.Complete.Run(r)
+			assert.Equal(t, context.Canceled, r.Context().Err())
+		})
+	}
+}
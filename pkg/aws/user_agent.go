@@ -2,6 +2,8 @@ package aws
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/version"
 	"github.com/aws/aws-sdk-go/aws/request"
 )
@@ -15,3 +17,8 @@ func injectUserAgent(handlers *request.Handlers) {
 		Fn:   request.MakeAddToUserAgentHandler(appName, version.GitVersion),
 	})
 }
+
+// IsControllerUserAgent checks whether userAgent is of AWS calls made by this controller.
+func IsControllerUserAgent(userAgent string) bool {
+	return strings.Contains(userAgent, appName+"/")
+}
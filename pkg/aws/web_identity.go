@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+)
+
+const (
+	envRoleARN              = "AWS_ROLE_ARN"
+	envRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+	envWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+)
+
+// newWebIdentityCredentials constructs credentials of the IAM role for service accounts (IRSA), assumed via STS configured by stsCfg.
+// The default credential chain assumes it while creating the session, before the region is known, i.e. via the global STS endpoint,
+// which isn't reachable in some regions and partitions.
+// tokenFile overrides AWS_WEB_IDENTITY_TOKEN_FILE if it's not empty. returns nil if IRSA isn't configured.
+func newWebIdentityCredentials(sess *session.Session, stsCfg *aws.Config, tokenFile string) (*credentials.Credentials, error) {
+	roleARN := os.Getenv(envRoleARN)
+	if len(tokenFile) == 0 {
+		tokenFile = os.Getenv(envWebIdentityTokenFile)
+	} else if len(roleARN) == 0 {
+		return nil, errors.Errorf("%s requires %s to be set", flagAWSWebIdentityTokenFile, envRoleARN)
+	}
+	if len(roleARN) == 0 || len(tokenFile) == 0 {
+		return nil, nil
+	}
+	provider := stscreds.NewWebIdentityRoleProviderWithOptions(sts.New(sess, stsCfg), roleARN, os.Getenv(envRoleSessionName), stscreds.FetchTokenPath(tokenFile))
+	return credentials.NewCredentials(provider), nil
+}
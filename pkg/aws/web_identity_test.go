@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newWebIdentityCredentials(t *testing.T) {
+	tests := []struct {
+		name                    string
+		envRoleARN              string
+		envWebIdentityTokenFile string
+		tokenFile               string
+		wantCredentials         bool
+		wantErr                 string
+	}{
+		{
+			name:                    "IRSA configured by env",
+			envRoleARN:              "arn:aws:iam::123456789012:role/appmesh-controller",
+			envWebIdentityTokenFile: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token",
+			wantCredentials:         true,
+		},
+		{
+			name:            "IRSA with overridden token file",
+			envRoleARN:      "arn:aws:iam::123456789012:role/appmesh-controller",
+			tokenFile:       "/var/run/secrets/custom/token",
+			wantCredentials: true,
+		},
+		{
+			name: "IRSA not configured",
+		},
+		{
+			name:                    "token file without role",
+			envWebIdentityTokenFile: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token",
+		},
+		{
+			name:      "overridden token file without role",
+			tokenFile: "/var/run/secrets/custom/token",
+			wantErr:   "aws-web-identity-token-file requires AWS_ROLE_ARN to be set",
+		},
+	}
+	sess := session.Must(session.NewSession())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envRoleARN, tt.envRoleARN)
+			t.Setenv(envWebIdentityTokenFile, tt.envWebIdentityTokenFile)
+			creds, err := newWebIdentityCredentials(sess, &aws.Config{Region: aws.String("us-west-2")}, tt.tokenFile)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCredentials, creds != nil)
+		})
+	}
+}
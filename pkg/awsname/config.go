@@ -0,0 +1,58 @@
+package awsname
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagAWSNameStrategy    = "aws-name-strategy"
+	flagResourceNameSuffix = "resource-name-suffix"
+
+	// maximum length of suffixes of generated AWSName.
+	maxSuffixLength = 64
+)
+
+// suffixPattern matches the characters suffixes of generated AWSName may consist of.
+var suffixPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]*$`)
+
+type Config struct {
+	// Strategy generates AWSName of resources that don't specify one.
+	Strategy Strategy
+	// Suffix is appended to the AWSName generated for meshes and their members, except VirtualServices,
+	// unless the Mesh specifies its own awsNameSuffix.
+	Suffix string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar((*string)(&cfg.Strategy), flagAWSNameStrategy, string(StrategyNameNamespace),
+		"The strategy generating AWSName of resources that don't specify one: name-namespace, namespace-name or name-namespace-hash")
+	fs.StringVar(&cfg.Suffix, flagResourceNameSuffix, "",
+		"The suffix appended to AWSName generated for meshes and their members except VirtualServices, e.g. -dev, unless the Mesh specifies awsNameSuffix")
+}
+
+func (cfg *Config) Validate() error {
+	switch cfg.Strategy {
+	case StrategyNameNamespace, StrategyNamespaceName, StrategyNameNamespaceHash:
+	default:
+		return errors.Errorf("%s must be one of %s, %s, %s: %s", flagAWSNameStrategy,
+			StrategyNameNamespace, StrategyNamespaceName, StrategyNameNamespaceHash, cfg.Strategy)
+	}
+	if err := ValidateSuffix(cfg.Suffix); err != nil {
+		return errors.Wrap(err, flagResourceNameSuffix)
+	}
+	return nil
+}
+
+// ValidateSuffix validates suffix of generated AWSName.
+func ValidateSuffix(suffix string) error {
+	if len(suffix) > maxSuffixLength {
+		return errors.Errorf("must be at most %d characters: %s", maxSuffixLength, suffix)
+	}
+	if !suffixPattern.MatchString(suffix) {
+		return errors.Errorf("must only consist of alphanumeric characters, '.', '_' or '-': %s", suffix)
+	}
+	return nil
+}
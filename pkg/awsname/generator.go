@@ -0,0 +1,99 @@
+package awsname
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Strategy of generating AWSName.
+type Strategy string
+
+const (
+	// StrategyNameNamespace generates "${name}_${namespace}".
+	StrategyNameNamespace Strategy = "name-namespace"
+	// StrategyNamespaceName generates "${namespace}_${name}", so AppMesh resources of a namespace are listed together.
+	StrategyNamespaceName Strategy = "namespace-name"
+	// StrategyNameNamespaceHash generates "${name}_${namespace}_${hash}", where hash is derived from the cluster name,
+	// so same named resources of clusters sharing a mesh don't collide.
+	StrategyNameNamespaceHash Strategy = "name-namespace-hash"
+)
+
+const (
+	// maximum length of AppMesh resource names.
+	maxAWSNameLength = 255
+	// length of hex encoded hash within generated AWSName.
+	hashLength = 8
+)
+
+// Generator generates AWSName for resources that don't specify one.
+type Generator interface {
+	// Generate generates AWSName for resource with name within namespace.
+	// namespace is empty for cluster scoped resources, whose AWSName only consists of name.
+	// separator joins the parts of AWSName, e.g. "_", or "." for VirtualService, whose AWSName usually resembles a DNS name.
+	Generate(name string, namespace string, separator string) string
+
+	// GenerateWithSuffix generates AWSName like Generate, with suffix appended as is.
+	// the suffix configured for the generator is appended instead if suffix is nil, e.g. the Mesh doesn't specify awsNameSuffix.
+	// the suffix isn't truncated, so that AWSName of different environments don't collide.
+	GenerateWithSuffix(name string, namespace string, separator string, suffix *string) string
+}
+
+// NewGenerator constructs new Generator.
+func NewGenerator(cfg Config, clusterName string) Generator {
+	return &defaultGenerator{
+		strategy:    cfg.Strategy,
+		suffix:      cfg.Suffix,
+		clusterName: clusterName,
+	}
+}
+
+var _ Generator = &defaultGenerator{}
+
+type defaultGenerator struct {
+	strategy    Strategy
+	suffix      string
+	clusterName string
+}
+
+func (g *defaultGenerator) Generate(name string, namespace string, separator string) string {
+	return truncate(g.join(name, namespace, separator), maxAWSNameLength)
+}
+
+func (g *defaultGenerator) GenerateWithSuffix(name string, namespace string, separator string, suffix *string) string {
+	awsNameSuffix := g.suffix
+	if suffix != nil {
+		awsNameSuffix = *suffix
+	}
+	return truncate(g.join(name, namespace, separator), maxAWSNameLength-len(awsNameSuffix)) + awsNameSuffix
+}
+
+// join joins the parts of AWSName by strategy.
+func (g *defaultGenerator) join(name string, namespace string, separator string) string {
+	var parts []string
+	switch {
+	case len(namespace) == 0:
+		parts = []string{name}
+	case g.strategy == StrategyNamespaceName:
+		parts = []string{namespace, name}
+	case g.strategy == StrategyNameNamespaceHash:
+		parts = []string{name, namespace, hash(strings.Join([]string{g.clusterName, namespace, name}, "/"))}
+	default:
+		parts = []string{name, namespace}
+	}
+	return strings.Join(parts, separator)
+}
+
+// truncate truncates awsName to maxLength, the maximum length of AppMesh resource names less the length of their suffix.
+// truncated names are suffixed with the hash of awsName, so names sharing the same prefix don't collide.
+func truncate(awsName string, maxLength int) string {
+	if len(awsName) <= maxLength {
+		return awsName
+	}
+	return awsName[:maxLength-hashLength-1] + "-" + hash(awsName)
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:hashLength]
+}
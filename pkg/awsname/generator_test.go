@@ -0,0 +1,201 @@
+package awsname
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_defaultGenerator_Generate(t *testing.T) {
+	longName := strings.Repeat("a", 250)
+	tests := []struct {
+		name        string
+		strategy    Strategy
+		clusterName string
+		objName     string
+		namespace   string
+		separator   string
+		want        string
+	}{
+		{
+			name:      "name-namespace strategy",
+			strategy:  StrategyNameNamespace,
+			objName:   "my-vn",
+			namespace: "my-ns",
+			separator: "_",
+			want:      "my-vn_my-ns",
+		},
+		{
+			name:      "name-namespace strategy with dot separator",
+			strategy:  StrategyNameNamespace,
+			objName:   "my-vs",
+			namespace: "my-ns",
+			separator: ".",
+			want:      "my-vs.my-ns",
+		},
+		{
+			name:      "namespace-name strategy",
+			strategy:  StrategyNamespaceName,
+			objName:   "my-vn",
+			namespace: "my-ns",
+			separator: "_",
+			want:      "my-ns_my-vn",
+		},
+		{
+			name:        "name-namespace-hash strategy",
+			strategy:    StrategyNameNamespaceHash,
+			clusterName: "my-cluster",
+			objName:     "my-vn",
+			namespace:   "my-ns",
+			separator:   "_",
+			want:        "my-vn_my-ns_" + hash("my-cluster/my-ns/my-vn"),
+		},
+		{
+			name:        "name-namespace-hash strategy differs by cluster",
+			strategy:    StrategyNameNamespaceHash,
+			clusterName: "another-cluster",
+			objName:     "my-vn",
+			namespace:   "my-ns",
+			separator:   "_",
+			want:        "my-vn_my-ns_" + hash("another-cluster/my-ns/my-vn"),
+		},
+		{
+			name:      "cluster scoped resource",
+			strategy:  StrategyNameNamespaceHash,
+			objName:   "my-mesh",
+			namespace: "",
+			separator: "",
+			want:      "my-mesh",
+		},
+		{
+			name:      "truncated to 255 characters",
+			strategy:  StrategyNameNamespace,
+			objName:   longName,
+			namespace: "my-ns",
+			separator: "_",
+			want:      longName[:246] + "-" + hash(longName+"_my-ns"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Config{Strategy: tt.strategy}, tt.clusterName)
+			got := g.Generate(tt.objName, tt.namespace, tt.separator)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len(got), maxAWSNameLength)
+		})
+	}
+}
+
+func Test_defaultGenerator_GenerateWithSuffix(t *testing.T) {
+	longName := strings.Repeat("a", 250)
+	tests := []struct {
+		name      string
+		cfgSuffix string
+		objName   string
+		namespace string
+		suffix    *string
+		want      string
+	}{
+		{
+			name:      "without suffix",
+			objName:   "my-vn",
+			namespace: "my-ns",
+			want:      "my-vn_my-ns",
+		},
+		{
+			name:      "configured suffix",
+			cfgSuffix: "-dev",
+			objName:   "my-vn",
+			namespace: "my-ns",
+			want:      "my-vn_my-ns-dev",
+		},
+		{
+			name:      "suffix overrides configured suffix",
+			cfgSuffix: "-dev",
+			objName:   "my-vn",
+			namespace: "my-ns",
+			suffix:    aws.String("-staging"),
+			want:      "my-vn_my-ns-staging",
+		},
+		{
+			name:      "empty suffix overrides configured suffix",
+			cfgSuffix: "-dev",
+			objName:   "my-vn",
+			namespace: "my-ns",
+			suffix:    aws.String(""),
+			want:      "my-vn_my-ns",
+		},
+		{
+			name:      "truncated to 255 characters keeping suffix",
+			cfgSuffix: "-dev",
+			objName:   longName,
+			namespace: "my-ns",
+			want:      longName[:242] + "-" + hash(longName+"_my-ns") + "-dev",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Config{Strategy: StrategyNameNamespace, Suffix: tt.cfgSuffix}, "")
+			got := g.GenerateWithSuffix(tt.objName, tt.namespace, "_", tt.suffix)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len(got), maxAWSNameLength)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		suffix   string
+		wantErr  string
+	}{
+		{
+			name:     "name-namespace",
+			strategy: StrategyNameNamespace,
+		},
+		{
+			name:     "namespace-name",
+			strategy: StrategyNamespaceName,
+		},
+		{
+			name:     "name-namespace-hash",
+			strategy: StrategyNameNamespaceHash,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "random",
+			wantErr:  "aws-name-strategy must be one of name-namespace, namespace-name, name-namespace-hash: random",
+		},
+		{
+			name:     "suffix",
+			strategy: StrategyNameNamespace,
+			suffix:   "-dev.1_a",
+		},
+		{
+			name:     "suffix too long",
+			strategy: StrategyNameNamespace,
+			suffix:   strings.Repeat("a", 65),
+			wantErr:  "resource-name-suffix: must be at most 64 characters: " + strings.Repeat("a", 65),
+		},
+		{
+			name:     "suffix with invalid characters",
+			strategy: StrategyNameNamespace,
+			suffix:   "-dev/1",
+			wantErr:  "resource-name-suffix: must only consist of alphanumeric characters, '.', '_' or '-': -dev/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Strategy: tt.strategy, Suffix: tt.suffix}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// Backuper periodically snapshots the mesh configuration into a Store, keeping the latest snapshots.
+type Backuper interface {
+	manager.Runnable
+	manager.LeaderElectionRunnable
+}
+
+// NewBackuper constructs new Backuper, which saves snapshots taken by snapshotter into store every interval,
+// and deletes snapshots but the latest retention ones.
+func NewBackuper(snapshotter Snapshotter, store Store, interval time.Duration, retention int, log logr.Logger) Backuper {
+	return &backuper{
+		snapshotter: snapshotter,
+		store:       store,
+		interval:    interval,
+		retention:   retention,
+		nowFunc:     time.Now,
+		log:         log,
+	}
+}
+
+var _ Backuper = &backuper{}
+
+type backuper struct {
+	snapshotter Snapshotter
+	store       Store
+	interval    time.Duration
+	retention   int
+	nowFunc     func() time.Time
+	log         logr.Logger
+}
+
+func (b *backuper) Start(ctx context.Context) error {
+	b.backupAndLog(ctx)
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			b.backupAndLog(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns true, only the leader takes snapshots.
+func (b *backuper) NeedLeaderElection() bool {
+	return true
+}
+
+func (b *backuper) backupAndLog(ctx context.Context) {
+	snapshot, err := b.backup(ctx)
+	if err != nil {
+		b.log.Error(err, "failed to snapshot mesh configuration")
+		return
+	}
+	b.log.Info("snapshotted mesh configuration",
+		"snapshot", snapshot.Name,
+		"objects", len(snapshot.Objects),
+		"virtualNodes", len(snapshot.AWS.VirtualNodes),
+		"virtualRouters", len(snapshot.AWS.VirtualRouters),
+	)
+}
+
+func (b *backuper) backup(ctx context.Context) (*Snapshot, error) {
+	snapshot, err := b.snapshotter.Take(ctx, b.nowFunc())
+	if err != nil {
+		return nil, err
+	}
+	// expired snapshots are deleted first, so they don't count towards the size limit of a ConfigMap store.
+	names, err := b.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if expired := len(names) - (b.retention - 1); expired > 0 {
+		if err := b.store.Delete(ctx, names[:expired]); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.store.Save(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
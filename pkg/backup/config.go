@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	flagInterval  = "backup-interval"
+	flagConfigMap = "backup-configmap"
+	flagS3Bucket  = "backup-s3-bucket"
+	flagS3Prefix  = "backup-s3-prefix"
+	flagRetention = "backup-retention"
+
+	defaultInterval  = time.Hour
+	defaultS3Prefix  = "appmesh-controller/"
+	defaultRetention = 24
+)
+
+type Config struct {
+	// Interval is the interval between snapshots.
+	Interval time.Duration
+	// ConfigMap is the namespaced name of the ConfigMap snapshots are stored in, as namespace/name.
+	ConfigMap string
+	// S3Bucket is the S3 bucket snapshots are stored in.
+	S3Bucket string
+	// S3Prefix is the prefix of keys of snapshots in S3Bucket.
+	S3Prefix string
+	// Retention is the number of latest snapshots kept, older snapshots are deleted.
+	Retention int
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&cfg.Interval, flagInterval, defaultInterval,
+		"The interval between snapshots of mesh configuration")
+	fs.StringVar(&cfg.ConfigMap, flagConfigMap, "",
+		"The ConfigMap to store snapshots of mesh configuration in, in the form of namespace/name. Mutually exclusive with "+flagS3Bucket)
+	fs.StringVar(&cfg.S3Bucket, flagS3Bucket, "",
+		"The S3 bucket to store snapshots of mesh configuration in. Mutually exclusive with "+flagConfigMap)
+	fs.StringVar(&cfg.S3Prefix, flagS3Prefix, defaultS3Prefix,
+		"The prefix of keys of snapshots in the S3 bucket")
+	fs.IntVar(&cfg.Retention, flagRetention, defaultRetention,
+		"The number of latest snapshots to keep, older snapshots are deleted")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if len(cfg.ConfigMap) != 0 && len(cfg.S3Bucket) != 0 {
+		return errors.Errorf("%s and %s are mutually exclusive", flagConfigMap, flagS3Bucket)
+	}
+	if len(cfg.ConfigMap) != 0 {
+		if _, err := cfg.ConfigMapKey(); err != nil {
+			return err
+		}
+	}
+	if cfg.Interval <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagInterval, cfg.Interval)
+	}
+	if cfg.Retention <= 0 {
+		return errors.Errorf("%s must be positive: %d", flagRetention, cfg.Retention)
+	}
+	return nil
+}
+
+// Enabled returns whether snapshots are taken, which requires either a ConfigMap or an S3 bucket to store them.
+func (cfg *Config) Enabled() bool {
+	return len(cfg.ConfigMap) != 0 || len(cfg.S3Bucket) != 0
+}
+
+// ConfigMapKey returns the namespaced name of the ConfigMap to store snapshots in.
+func (cfg *Config) ConfigMapKey() (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(cfg.ConfigMap, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return types.NamespacedName{}, errors.Errorf("%s must be in the form of namespace/name: %q", flagConfigMap, cfg.ConfigMap)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "snapshots disabled",
+			cfg:  Config{},
+		},
+		{
+			name: "snapshots to ConfigMap",
+			cfg:  Config{ConfigMap: "appmesh-system/snapshots", Interval: time.Hour, Retention: 24},
+		},
+		{
+			name: "snapshots to S3",
+			cfg:  Config{S3Bucket: "my-bucket", S3Prefix: "appmesh-controller/", Interval: time.Hour, Retention: 24},
+		},
+		{
+			name:    "both ConfigMap and S3",
+			cfg:     Config{ConfigMap: "appmesh-system/snapshots", S3Bucket: "my-bucket", Interval: time.Hour, Retention: 24},
+			wantErr: "backup-configmap and backup-s3-bucket are mutually exclusive",
+		},
+		{
+			name:    "ConfigMap without namespace",
+			cfg:     Config{ConfigMap: "snapshots", Interval: time.Hour, Retention: 24},
+			wantErr: `backup-configmap must be in the form of namespace/name: "snapshots"`,
+		},
+		{
+			name:    "non-positive interval",
+			cfg:     Config{S3Bucket: "my-bucket", Retention: 24},
+			wantErr: "backup-interval must be positive: 0s",
+		},
+		{
+			name:    "non-positive retention",
+			cfg:     Config{S3Bucket: "my-bucket", Interval: time.Hour},
+			wantErr: "backup-retention must be positive: 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
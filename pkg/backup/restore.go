@@ -0,0 +1,127 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// designatedRefFields are spec fields referencing the mesh or virtualGateway by UID, which the mutating webhooks designate.
+// they're not restored, as the UIDs change once the referenced objects are recreated.
+var designatedRefFields = []string{"meshRef", "virtualGatewayRef"}
+
+// RestoreOptions controls which objects of a snapshot are restored and how.
+type RestoreOptions struct {
+	// Namespace restricts restored objects to those in Namespace, every object including meshes is restored if it's empty.
+	Namespace string
+	// DryRun only reports what would be restored, objects are sent to the API server in dry run mode.
+	DryRun bool
+}
+
+// Restore re-applies the desired specs of objects in snapshot, reporting the outcome per object into w.
+// missing objects are created, the spec of existing objects is replaced, while their labels and annotations are merged with the snapshot's.
+// objects created after the snapshot are left as is. the controller then reconciles AppMesh resources to the restored specs.
+func Restore(ctx context.Context, k8sClient client.Client, snapshot *Snapshot, opts RestoreOptions, w io.Writer) error {
+	var createOpts []client.CreateOption
+	var updateOpts []client.UpdateOption
+	suffix := ""
+	if opts.DryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+		suffix = " (dry run)"
+	}
+	for i := range snapshot.Objects {
+		desired := snapshot.Objects[i].DeepCopy()
+		if len(opts.Namespace) != 0 && desired.GetNamespace() != opts.Namespace {
+			continue
+		}
+		resource := describeObject(desired)
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(desired.GroupVersionKind())
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get %s", resource)
+			}
+			for _, field := range designatedRefFields {
+				unstructured.RemoveNestedField(desired.Object, "spec", field)
+			}
+			if err := k8sClient.Create(ctx, desired, createOpts...); err != nil {
+				return errors.Wrapf(err, "failed to create %s", resource)
+			}
+			fmt.Fprintf(w, "%s created%s\n", resource, suffix)
+			continue
+		}
+		if !restoreInto(existing, desired) {
+			fmt.Fprintf(w, "%s unchanged\n", resource)
+			continue
+		}
+		if err := k8sClient.Update(ctx, existing, updateOpts...); err != nil {
+			return errors.Wrapf(err, "failed to restore %s", resource)
+		}
+		fmt.Fprintf(w, "%s restored%s\n", resource, suffix)
+	}
+	return nil
+}
+
+// restoreInto sets the spec of desired into existing and merges its labels and annotations, returns whether existing is changed.
+// designatedRefFields of existing are kept.
+func restoreInto(existing *unstructured.Unstructured, desired *unstructured.Unstructured) bool {
+	changed := false
+	for _, field := range designatedRefFields {
+		unstructured.RemoveNestedField(desired.Object, "spec", field)
+		if ref, ok, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", field); ok {
+			_ = unstructured.SetNestedField(desired.Object, ref, "spec", field)
+		}
+	}
+	if !equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		existing.Object["spec"] = desired.Object["spec"]
+		changed = true
+	}
+	if labels, ok := mergeStringMap(existing.GetLabels(), desired.GetLabels()); ok {
+		existing.SetLabels(labels)
+		changed = true
+	}
+	if annotations, ok := mergeStringMap(existing.GetAnnotations(), desired.GetAnnotations()); ok {
+		existing.SetAnnotations(annotations)
+		changed = true
+	}
+	return changed
+}
+
+// mergeStringMap returns a copy of existing with entries of desired, returns false if existing already has them.
+func mergeStringMap(existing map[string]string, desired map[string]string) (map[string]string, bool) {
+	changed := false
+	for key, value := range desired {
+		if existingValue, ok := existing[key]; !ok || existingValue != value {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return existing, false
+	}
+	merged := make(map[string]string, len(existing)+len(desired))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range desired {
+		merged[key] = value
+	}
+	return merged, true
+}
+
+// describeObject describes obj like kubectl, as kind/name, followed by its namespace if it's namespaced.
+func describeObject(obj client.Object) string {
+	resource := fmt.Sprintf("%s/%s", strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), obj.GetName())
+	if len(obj.GetNamespace()) == 0 {
+		return resource
+	}
+	return fmt.Sprintf("%s in namespace %s", resource, obj.GetNamespace())
+}
@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestore(t *testing.T) {
+	toUnstructured := func(obj runtime.Object, kind string) unstructured.Unstructured {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		require.NoError(t, err)
+		u := unstructured.Unstructured{Object: content}
+		u.SetAPIVersion(appmesh.GroupVersion.String())
+		u.SetKind(kind)
+		delete(u.Object, "status")
+		delete(u.Object["metadata"].(map[string]interface{}), "creationTimestamp")
+		return u
+	}
+	oldMeshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "old-mesh-uid"}
+	newMeshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "new-mesh-uid"}
+	snapshot := &Snapshot{
+		Name: "20230603T120000Z",
+		Objects: []unstructured.Unstructured{
+			toUnstructured(&appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{AWSName: aws.String("my-mesh")},
+			}, "Mesh"),
+			toUnstructured(&appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-a", Labels: map[string]string{"app": "node-a"}},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-a_my-ns"), MeshRef: oldMeshRef},
+			}, "VirtualNode"),
+			toUnstructured(&appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-b"},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-b_my-ns"), MeshRef: oldMeshRef},
+			}, "VirtualNode"),
+			toUnstructured(&appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-c"},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-c_my-ns"), MeshRef: oldMeshRef},
+			}, "VirtualNode"),
+			toUnstructured(&appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "node-a"},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-a_other-ns"), MeshRef: oldMeshRef},
+			}, "VirtualNode"),
+		},
+	}
+	existing := []runtime.Object{
+		&appmesh.Mesh{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "new-mesh-uid"},
+			Spec:       appmesh.MeshSpec{AWSName: aws.String("my-mesh")},
+		},
+		// node-a has been changed since the snapshot.
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-a", Labels: map[string]string{"version": "v2"}},
+			Spec: appmesh.VirtualNodeSpec{
+				AWSName:   aws.String("node-a_my-ns"),
+				MeshRef:   newMeshRef,
+				Listeners: []appmesh.Listener{{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}}},
+			},
+		},
+		// node-c is unchanged, besides its mesh being recreated.
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-c"},
+			Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-c_my-ns"), MeshRef: newMeshRef},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		opts       RestoreOptions
+		wantOutput string
+		wantNodeA  *appmesh.VirtualNode
+		wantNodeB  bool
+	}{
+		{
+			name: "restore namespace",
+			opts: RestoreOptions{Namespace: "my-ns"},
+			wantOutput: "virtualnode/node-a in namespace my-ns restored\n" +
+				"virtualnode/node-b in namespace my-ns created\n" +
+				"virtualnode/node-c in namespace my-ns unchanged\n",
+			wantNodeA: &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-a", Labels: map[string]string{"app": "node-a", "version": "v2"}},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-a_my-ns"), MeshRef: newMeshRef},
+			},
+			wantNodeB: true,
+		},
+		{
+			name: "restore all namespaces",
+			opts: RestoreOptions{},
+			wantOutput: "mesh/my-mesh unchanged\n" +
+				"virtualnode/node-a in namespace my-ns restored\n" +
+				"virtualnode/node-b in namespace my-ns created\n" +
+				"virtualnode/node-c in namespace my-ns unchanged\n" +
+				"virtualnode/node-a in namespace other-ns created\n",
+			wantNodeA: &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-a", Labels: map[string]string{"app": "node-a", "version": "v2"}},
+				Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("node-a_my-ns"), MeshRef: newMeshRef},
+			},
+			wantNodeB: true,
+		},
+		{
+			name: "dry run",
+			opts: RestoreOptions{Namespace: "my-ns", DryRun: true},
+			wantOutput: "virtualnode/node-a in namespace my-ns restored (dry run)\n" +
+				"virtualnode/node-b in namespace my-ns created (dry run)\n" +
+				"virtualnode/node-c in namespace my-ns unchanged\n",
+			wantNodeA: existing[1].(*appmesh.VirtualNode),
+			wantNodeB: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var objs []runtime.Object
+			for _, obj := range existing {
+				objs = append(objs, obj.DeepCopyObject())
+			}
+			k8sClient := testclient.NewClientBuilder().WithScheme(newTestScheme()).WithRuntimeObjects(objs...).Build()
+			output := &bytes.Buffer{}
+			err := Restore(ctx, k8sClient, snapshot, tt.opts, output)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutput, output.String())
+
+			nodeA := &appmesh.VirtualNode{}
+			require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "my-ns", Name: "node-a"}, nodeA))
+			assert.Equal(t, tt.wantNodeA.Labels, nodeA.Labels)
+			assert.Equal(t, tt.wantNodeA.Spec, nodeA.Spec)
+
+			nodeB := &appmesh.VirtualNode{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "my-ns", Name: "node-b"}, nodeB)
+			if tt.wantNodeB {
+				require.NoError(t, err)
+				// meshRef is designated again by the mutating webhook.
+				assert.Nil(t, nodeB.Spec.MeshRef)
+				assert.Equal(t, aws.String("node-b_my-ns"), nodeB.Spec.AWSName)
+			} else {
+				assert.True(t, apierrors.IsNotFound(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package backup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	appmeshaws "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// snapshotNameLayout is the layout of snapshot names, which sort in the order snapshots are taken.
+	snapshotNameLayout = "20060102T150405Z"
+	// lastAppliedConfigurationAnnotation is the annotation kubectl apply records the last applied manifest in.
+	lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// Snapshot is the mesh configuration at a point in time.
+type Snapshot struct {
+	// Name identifies the snapshot, it's derived from Time.
+	Name string `json:"name"`
+	// Time is when the snapshot is taken.
+	Time metav1.Time `json:"time"`
+	// Objects are the CRDs with their desired specs, without status and fields set by the API server.
+	// Meshes come first, then the other kinds in the order they're restored.
+	Objects []unstructured.Unstructured `json:"objects"`
+	// AWS is the actual state of AppMesh resources of VirtualNodes and VirtualRouters.
+	AWS AWSState `json:"aws"`
+}
+
+// AWSState is the actual state of AppMesh resources, with specs in AppMesh API format.
+type AWSState struct {
+	VirtualNodes   []AWSVirtualNode   `json:"virtualNodes,omitempty"`
+	VirtualRouters []AWSVirtualRouter `json:"virtualRouters,omitempty"`
+}
+
+// AWSVirtualNode is an AppMesh VirtualNode.
+type AWSVirtualNode struct {
+	// Object is the namespace/name of the VirtualNode CRD.
+	Object          string                      `json:"object"`
+	MeshName        string                      `json:"meshName"`
+	VirtualNodeName string                      `json:"virtualNodeName"`
+	Spec            *appmeshsdk.VirtualNodeSpec `json:"spec"`
+}
+
+// AWSVirtualRouter is an AppMesh VirtualRouter with its routes.
+type AWSVirtualRouter struct {
+	// Object is the namespace/name of the VirtualRouter CRD.
+	Object            string                        `json:"object"`
+	MeshName          string                        `json:"meshName"`
+	VirtualRouterName string                        `json:"virtualRouterName"`
+	Spec              *appmeshsdk.VirtualRouterSpec `json:"spec"`
+	Routes            []AWSRoute                    `json:"routes,omitempty"`
+}
+
+// AWSRoute is an AppMesh Route.
+type AWSRoute struct {
+	RouteName string                `json:"routeName"`
+	Spec      *appmeshsdk.RouteSpec `json:"spec"`
+}
+
+// Snapshotter takes snapshots of mesh configuration.
+type Snapshotter interface {
+	// Take takes a snapshot at now.
+	Take(ctx context.Context, now time.Time) (*Snapshot, error)
+}
+
+// NewDefaultSnapshotter constructs new Snapshotter, which takes CRDs from k8sClient and their AppMesh resources from appMeshSDK.
+// MeshPolicies are only included if enableMeshPolicies is set, as their CRD may not be installed otherwise.
+func NewDefaultSnapshotter(k8sClient client.Reader, appMeshSDK services.AppMesh, namespaceRoleResolver appmeshaws.NamespaceRoleResolver, scheme *runtime.Scheme, enableMeshPolicies bool) *defaultSnapshotter {
+	return &defaultSnapshotter{
+		k8sClient:             k8sClient,
+		appMeshSDK:            appMeshSDK,
+		namespaceRoleResolver: namespaceRoleResolver,
+		scheme:                scheme,
+		enableMeshPolicies:    enableMeshPolicies,
+	}
+}
+
+var _ Snapshotter = &defaultSnapshotter{}
+
+type defaultSnapshotter struct {
+	k8sClient             client.Reader
+	appMeshSDK            services.AppMesh
+	namespaceRoleResolver appmeshaws.NamespaceRoleResolver
+	scheme                *runtime.Scheme
+	enableMeshPolicies    bool
+}
+
+func (s *defaultSnapshotter) Take(ctx context.Context, now time.Time) (*Snapshot, error) {
+	msList := &appmesh.MeshList{}
+	vnList := &appmesh.VirtualNodeList{}
+	vrList := &appmesh.VirtualRouterList{}
+	lists := []client.ObjectList{
+		msList,
+		&appmesh.VirtualGatewayList{},
+		&appmesh.BackendGroupList{},
+		vnList,
+		vrList,
+		&appmesh.VirtualServiceList{},
+		&appmesh.GatewayRouteList{},
+	}
+	if s.enableMeshPolicies {
+		lists = append(lists, &appmesh.MeshPolicyList{})
+	}
+	snapshot := &Snapshot{
+		Name:    now.UTC().Format(snapshotNameLayout),
+		Time:    metav1.NewTime(now),
+		Objects: []unstructured.Unstructured{},
+	}
+	for _, list := range lists {
+		if err := s.k8sClient.List(ctx, list); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %T", list)
+		}
+		objs, err := s.desiredObjects(list)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Objects = append(snapshot.Objects, objs...)
+	}
+
+	msByName := make(map[string]*appmesh.Mesh, len(msList.Items))
+	for i := range msList.Items {
+		msByName[msList.Items[i].Name] = &msList.Items[i]
+	}
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		ms, ok := findMesh(msByName, vn.Spec.MeshRef, vn.Status.VirtualNodeARN)
+		if !ok {
+			continue
+		}
+		awsVN, err := s.takeVirtualNode(ctx, ms, vn)
+		if err != nil {
+			return nil, err
+		}
+		if awsVN != nil {
+			snapshot.AWS.VirtualNodes = append(snapshot.AWS.VirtualNodes, *awsVN)
+		}
+	}
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		ms, ok := findMesh(msByName, vr.Spec.MeshRef, vr.Status.VirtualRouterARN)
+		if !ok {
+			continue
+		}
+		awsVR, err := s.takeVirtualRouter(ctx, ms, vr)
+		if err != nil {
+			return nil, err
+		}
+		if awsVR != nil {
+			snapshot.AWS.VirtualRouters = append(snapshot.AWS.VirtualRouters, *awsVR)
+		}
+	}
+	return snapshot, nil
+}
+
+// desiredObjects converts items of list into unstructured objects, sorted by namespace and name,
+// keeping only their names, labels, annotations and specs.
+func (s *defaultSnapshotter) desiredObjects(list client.ObjectList) ([]unstructured.Unstructured, error) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]unstructured.Unstructured, 0, len(items))
+	for _, listItem := range items {
+		item, ok := listItem.(client.Object)
+		if !ok {
+			return nil, errors.Errorf("unexpected item %T of %T", listItem, list)
+		}
+		gvk, err := apiutil.GVKForObject(item, s.scheme)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return nil, err
+		}
+		obj := unstructured.Unstructured{Object: map[string]interface{}{"spec": content["spec"]}}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace(item.GetNamespace())
+		obj.SetName(item.GetName())
+		obj.SetLabels(item.GetLabels())
+		obj.SetAnnotations(withoutLastAppliedConfiguration(item.GetAnnotations()))
+		objs = append(objs, obj)
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+	return objs, nil
+}
+
+func (s *defaultSnapshotter) takeVirtualNode(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode) (*AWSVirtualNode, error) {
+	ctx, err := s.namespaceRoleResolver.WithNamespaceRole(ctx, vn.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.appMeshSDK.DescribeVirtualNodeWithContext(ctx, &appmeshsdk.DescribeVirtualNodeInput{
+		MeshName:        ms.Spec.AWSName,
+		MeshOwner:       ms.Spec.MeshOwner,
+		VirtualNodeName: vn.Spec.AWSName,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to describe virtualNode %s", aws.StringValue(vn.Spec.AWSName))
+	}
+	return &AWSVirtualNode{
+		Object:          vn.Namespace + "/" + vn.Name,
+		MeshName:        aws.StringValue(ms.Spec.AWSName),
+		VirtualNodeName: aws.StringValue(vn.Spec.AWSName),
+		Spec:            resp.VirtualNode.Spec,
+	}, nil
+}
+
+func (s *defaultSnapshotter) takeVirtualRouter(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) (*AWSVirtualRouter, error) {
+	ctx, err := s.namespaceRoleResolver.WithNamespaceRole(ctx, vr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.appMeshSDK.DescribeVirtualRouterWithContext(ctx, &appmeshsdk.DescribeVirtualRouterInput{
+		MeshName:          ms.Spec.AWSName,
+		MeshOwner:         ms.Spec.MeshOwner,
+		VirtualRouterName: vr.Spec.AWSName,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to describe virtualRouter %s", aws.StringValue(vr.Spec.AWSName))
+	}
+	awsVR := &AWSVirtualRouter{
+		Object:            vr.Namespace + "/" + vr.Name,
+		MeshName:          aws.StringValue(ms.Spec.AWSName),
+		VirtualRouterName: aws.StringValue(vr.Spec.AWSName),
+		Spec:              resp.VirtualRouter.Spec,
+	}
+
+	var routeNames []string
+	if err := s.appMeshSDK.ListRoutesPagesWithContext(ctx, &appmeshsdk.ListRoutesInput{
+		MeshName:          ms.Spec.AWSName,
+		MeshOwner:         ms.Spec.MeshOwner,
+		VirtualRouterName: vr.Spec.AWSName,
+	}, func(output *appmeshsdk.ListRoutesOutput, lastPage bool) bool {
+		for _, ref := range output.Routes {
+			routeNames = append(routeNames, aws.StringValue(ref.RouteName))
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list routes of virtualRouter %s", awsVR.VirtualRouterName)
+	}
+	sort.Strings(routeNames)
+	for _, routeName := range routeNames {
+		resp, err := s.appMeshSDK.DescribeRouteWithContext(ctx, &appmeshsdk.DescribeRouteInput{
+			MeshName:          ms.Spec.AWSName,
+			MeshOwner:         ms.Spec.MeshOwner,
+			VirtualRouterName: vr.Spec.AWSName,
+			RouteName:         aws.String(routeName),
+		})
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to describe route %s of virtualRouter %s", routeName, awsVR.VirtualRouterName)
+		}
+		awsVR.Routes = append(awsVR.Routes, AWSRoute{RouteName: routeName, Spec: resp.Route.Spec})
+	}
+	return awsVR, nil
+}
+
+// findMesh finds the mesh of a mesh member with meshRef, which is only found once its AppMesh resource is created, as told by resourceARN.
+func findMesh(msByName map[string]*appmesh.Mesh, meshRef *appmesh.MeshReference, resourceARN *string) (*appmesh.Mesh, bool) {
+	if meshRef == nil || len(aws.StringValue(resourceARN)) == 0 {
+		return nil, false
+	}
+	ms, ok := msByName[meshRef.Name]
+	if !ok || ms.UID != meshRef.UID {
+		return nil, false
+	}
+	return ms, true
+}
+
+// withoutLastAppliedConfiguration returns annotations without lastAppliedConfigurationAnnotation, which duplicates the spec.
+func withoutLastAppliedConfiguration(annotations map[string]string) map[string]string {
+	if _, ok := annotations[lastAppliedConfigurationAnnotation]; !ok {
+		return annotations
+	}
+	result := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		if key != lastAppliedConfigurationAnnotation {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func isNotFound(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == appmeshsdk.ErrCodeNotFoundException
+}
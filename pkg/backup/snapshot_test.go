@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	appmeshaws "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestScheme() *runtime.Scheme {
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	return k8sSchema
+}
+
+func Test_defaultSnapshotter_Take(t *testing.T) {
+	ctx := context.Background()
+	appMeshSDK := services.NewFakeAppMesh("123456789012", "us-west-2")
+	_, err := appMeshSDK.CreateMeshWithContext(ctx, &appmeshsdk.CreateMeshInput{MeshName: aws.String("my-mesh")})
+	require.NoError(t, err)
+	vnSpec := &appmeshsdk.VirtualNodeSpec{
+		Listeners: []*appmeshsdk.Listener{
+			{PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")}},
+		},
+	}
+	_, err = appMeshSDK.CreateVirtualNodeWithContext(ctx, &appmeshsdk.CreateVirtualNodeInput{
+		MeshName:        aws.String("my-mesh"),
+		VirtualNodeName: aws.String("node-a_my-ns"),
+		Spec:            vnSpec,
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualRouterWithContext(ctx, &appmeshsdk.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("router-a_my-ns"),
+		Spec: &appmeshsdk.VirtualRouterSpec{
+			Listeners: []*appmeshsdk.VirtualRouterListener{
+				{PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	routeSpec := &appmeshsdk.RouteSpec{
+		HttpRoute: &appmeshsdk.HttpRoute{
+			Match: &appmeshsdk.HttpRouteMatch{Prefix: aws.String("/")},
+			Action: &appmeshsdk.HttpRouteAction{
+				WeightedTargets: []*appmeshsdk.WeightedTarget{{VirtualNode: aws.String("node-a_my-ns"), Weight: aws.Int64(100)}},
+			},
+		},
+	}
+	_, err = appMeshSDK.CreateRouteWithContext(ctx, &appmeshsdk.CreateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("router-a_my-ns"),
+		RouteName:         aws.String("route-a"),
+		Spec:              routeSpec,
+	})
+	require.NoError(t, err)
+
+	meshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "mesh-uid"}
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "mesh-uid", ResourceVersion: "7"},
+		Spec:       appmesh.MeshSpec{AWSName: aws.String("my-mesh")},
+		Status:     appmesh.MeshStatus{MeshARN: aws.String("arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh")},
+	}
+	vn := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "node-a",
+			Labels:    map[string]string{"app": "node-a"},
+			Annotations: map[string]string{
+				"team": "a",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			AWSName: aws.String("node-a_my-ns"),
+			MeshRef: meshRef,
+		},
+		Status: appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualNode/node-a_my-ns")},
+	}
+	vnNotCreated := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "node-b"},
+		Spec: appmesh.VirtualNodeSpec{
+			AWSName: aws.String("node-b_my-ns"),
+			MeshRef: meshRef,
+		},
+	}
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "router-a"},
+		Spec: appmesh.VirtualRouterSpec{
+			AWSName: aws.String("router-a_my-ns"),
+			MeshRef: meshRef,
+		},
+		Status: appmesh.VirtualRouterStatus{VirtualRouterARN: aws.String("arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualRouter/router-a_my-ns")},
+	}
+	k8sSchema := newTestScheme()
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithRuntimeObjects(ms, vn, vnNotCreated, vr).Build()
+	s := NewDefaultSnapshotter(k8sClient, appMeshSDK, appmeshaws.NewNamespaceRoleResolver(k8sClient, false), k8sSchema, false)
+
+	now := time.Date(2023, 6, 3, 12, 30, 0, 0, time.UTC)
+	snapshot, err := s.Take(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, "20230603T123000Z", snapshot.Name)
+
+	var objects []string
+	for _, obj := range snapshot.Objects {
+		objects = append(objects, obj.GetKind()+"/"+obj.GetNamespace()+"/"+obj.GetName())
+		assert.Nil(t, obj.Object["status"])
+		assert.Empty(t, obj.GetResourceVersion())
+	}
+	assert.Equal(t, []string{"Mesh//my-mesh", "VirtualNode/my-ns/node-a", "VirtualNode/my-ns/node-b", "VirtualRouter/my-ns/router-a"}, objects)
+	assert.Equal(t, map[string]string{"app": "node-a"}, snapshot.Objects[1].GetLabels())
+	assert.Equal(t, map[string]string{"team": "a"}, snapshot.Objects[1].GetAnnotations())
+	assert.Equal(t, "node-a_my-ns", snapshot.Objects[1].Object["spec"].(map[string]interface{})["awsName"])
+
+	assert.Equal(t, []AWSVirtualNode{
+		{Object: "my-ns/node-a", MeshName: "my-mesh", VirtualNodeName: "node-a_my-ns", Spec: vnSpec},
+	}, snapshot.AWS.VirtualNodes)
+	require.Len(t, snapshot.AWS.VirtualRouters, 1)
+	assert.Equal(t, "router-a_my-ns", snapshot.AWS.VirtualRouters[0].VirtualRouterName)
+	assert.Equal(t, []AWSRoute{{RouteName: "route-a", Spec: routeSpec}}, snapshot.AWS.VirtualRouters[0].Routes)
+}
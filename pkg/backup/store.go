@@ -0,0 +1,278 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// snapshotKeySuffix is the suffix of ConfigMap keys and S3 keys of snapshots, which are stored as gzipped JSON.
+	snapshotKeySuffix = ".json.gz"
+
+	// ConfigMaps are limited to 1MiB by the API server, including metadata.
+	maxConfigMapDataSize = 1024 * 1024
+)
+
+// Store stores snapshots.
+type Store interface {
+	// Save saves snapshot.
+	Save(ctx context.Context, snapshot *Snapshot) error
+	// List lists the names of stored snapshots, oldest first.
+	List(ctx context.Context) ([]string, error)
+	// Load loads the snapshot with name.
+	Load(ctx context.Context, name string) (*Snapshot, error)
+	// Delete deletes the snapshots with names.
+	Delete(ctx context.Context, names []string) error
+}
+
+// NewConfigMapStore constructs new Store of snapshots in the binaryData of the ConfigMap with key.
+// the ConfigMap is read with apiReader rather than the cached client, so that ConfigMaps aren't cached cluster-wide.
+func NewConfigMapStore(key types.NamespacedName, k8sClient client.Client, apiReader client.Reader) Store {
+	return &configMapStore{
+		key:       key,
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+	}
+}
+
+var _ Store = &configMapStore{}
+
+type configMapStore struct {
+	key       types.NamespacedName
+	k8sClient client.Client
+	apiReader client.Reader
+}
+
+func (s *configMapStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	payload, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := s.apiReader.Get(ctx, s.key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.key.Namespace,
+				Name:      s.key.Name,
+			},
+			BinaryData: map[string][]byte{snapshot.Name + snapshotKeySuffix: payload},
+		}
+		if err := checkConfigMapSize(cm); err != nil {
+			return err
+		}
+		return s.k8sClient.Create(ctx, cm)
+	}
+	if cm.BinaryData == nil {
+		cm.BinaryData = make(map[string][]byte)
+	}
+	cm.BinaryData[snapshot.Name+snapshotKeySuffix] = payload
+	if err := checkConfigMapSize(cm); err != nil {
+		return err
+	}
+	return s.k8sClient.Update(ctx, cm)
+}
+
+func (s *configMapStore) List(ctx context.Context) ([]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.apiReader.Get(ctx, s.key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	keys := make([]string, 0, len(cm.BinaryData))
+	for key := range cm.BinaryData {
+		keys = append(keys, key)
+	}
+	return snapshotNames(keys, ""), nil
+}
+
+func (s *configMapStore) Load(ctx context.Context, name string) (*Snapshot, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.apiReader.Get(ctx, s.key, cm); err != nil {
+		return nil, err
+	}
+	payload, ok := cm.BinaryData[name+snapshotKeySuffix]
+	if !ok {
+		return nil, errors.Errorf("snapshot %s not found in ConfigMap %s", name, s.key)
+	}
+	return decodeSnapshot(bytes.NewReader(payload))
+}
+
+func (s *configMapStore) Delete(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := s.apiReader.Get(ctx, s.key, cm); err != nil {
+		return err
+	}
+	for _, name := range names {
+		delete(cm.BinaryData, name+snapshotKeySuffix)
+	}
+	return s.k8sClient.Update(ctx, cm)
+}
+
+// checkConfigMapSize checks cm fits into the ConfigMap size limit, leaving headroom for its metadata.
+func checkConfigMapSize(cm *corev1.ConfigMap) error {
+	size := 0
+	for key, value := range cm.BinaryData {
+		size += len(key) + len(value)
+	}
+	for key, value := range cm.Data {
+		size += len(key) + len(value)
+	}
+	if size > maxConfigMapDataSize*9/10 {
+		return errors.Errorf("snapshots of %d bytes exceed the ConfigMap size limit, lower the retention or store snapshots in S3", size)
+	}
+	return nil
+}
+
+// NewS3Store constructs new Store of snapshots in bucket, under keys starting with prefix.
+func NewS3Store(s3SDK services.S3, bucket string, prefix string) Store {
+	return &s3Store{
+		s3SDK:  s3SDK,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+var _ Store = &s3Store{}
+
+type s3Store struct {
+	s3SDK  services.S3
+	bucket string
+	prefix string
+}
+
+func (s *s3Store) Save(ctx context.Context, snapshot *Snapshot) error {
+	payload, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	if _, err := s.s3SDK.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key(snapshot.Name)),
+		Body:        bytes.NewReader(payload),
+		ContentType: aws.String("application/gzip"),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to put snapshot %s into S3 bucket %s", snapshot.Name, s.bucket)
+	}
+	return nil
+}
+
+func (s *s3Store) List(ctx context.Context) ([]string, error) {
+	var keys []string
+	if err := s.s3SDK.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	}, func(output *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range output.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list snapshots in S3 bucket %s", s.bucket)
+	}
+	return snapshotNames(keys, s.prefix), nil
+}
+
+func (s *s3Store) Load(ctx context.Context, name string) (*Snapshot, error) {
+	resp, err := s.s3SDK.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errors.Errorf("snapshot %s not found in S3 bucket %s", name, s.bucket)
+		}
+		return nil, errors.Wrapf(err, "failed to get snapshot %s from S3 bucket %s", name, s.bucket)
+	}
+	defer resp.Body.Close()
+	return decodeSnapshot(resp.Body)
+}
+
+func (s *s3Store) Delete(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	objs := make([]*s3.ObjectIdentifier, 0, len(names))
+	for _, name := range names {
+		objs = append(objs, &s3.ObjectIdentifier{Key: aws.String(s.key(name))})
+	}
+	resp, err := s.s3SDK.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(s.bucket),
+		Delete: &s3.Delete{Objects: objs, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete snapshots from S3 bucket %s", s.bucket)
+	}
+	if len(resp.Errors) != 0 {
+		return errors.Errorf("failed to delete snapshot %s from S3 bucket %s: %s",
+			aws.StringValue(resp.Errors[0].Key), s.bucket, aws.StringValue(resp.Errors[0].Message))
+	}
+	return nil
+}
+
+func (s *s3Store) key(name string) string {
+	return s.prefix + name + snapshotKeySuffix
+}
+
+// snapshotNames returns the names of snapshots stored under keys, which start with prefix. other keys are ignored.
+func snapshotNames(keys []string, prefix string) []string {
+	var names []string
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, prefix)
+		if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(name, snapshotKeySuffix) || strings.Contains(name, "/") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, snapshotKeySuffix))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func encodeSnapshot(snapshot *Snapshot) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(r io.Reader) (*Snapshot, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress snapshot")
+	}
+	defer zr.Close()
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(zr).Decode(snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to decode snapshot")
+	}
+	return snapshot, nil
+}
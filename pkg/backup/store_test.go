@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeS3 serves objects of a single bucket from memory.
+type fakeS3 struct {
+	services.S3
+	objects map[string][]byte
+}
+
+func (s *fakeS3) PutObjectWithContext(_ aws.Context, input *s3.PutObjectInput, _ ...request.Option) (*s3.PutObjectOutput, error) {
+	payload, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.objects[aws.StringValue(input.Key)] = payload
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (s *fakeS3) ListObjectsV2PagesWithContext(_ aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, _ ...request.Option) error {
+	output := &s3.ListObjectsV2Output{}
+	for key := range s.objects {
+		if strings.HasPrefix(key, aws.StringValue(input.Prefix)) {
+			output.Contents = append(output.Contents, &s3.Object{Key: aws.String(key)})
+		}
+	}
+	fn(output, true)
+	return nil
+}
+
+func (s *fakeS3) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	payload, ok := s.objects[aws.StringValue(input.Key)]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(payload))}, nil
+}
+
+func (s *fakeS3) DeleteObjectsWithContext(_ aws.Context, input *s3.DeleteObjectsInput, _ ...request.Option) (*s3.DeleteObjectsOutput, error) {
+	for _, obj := range input.Delete.Objects {
+		delete(s.objects, aws.StringValue(obj.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+// fakeSnapshotter takes snapshots with a single mesh.
+type fakeSnapshotter struct{}
+
+func (s *fakeSnapshotter) Take(_ context.Context, now time.Time) (*Snapshot, error) {
+	ms := unstructured.Unstructured{}
+	ms.SetAPIVersion("appmesh.k8s.aws/v1beta2")
+	ms.SetKind("Mesh")
+	ms.SetName("my-mesh")
+	return &Snapshot{
+		Name:    now.UTC().Format(snapshotNameLayout),
+		Time:    metav1.NewTime(now),
+		Objects: []unstructured.Unstructured{ms},
+	}, nil
+}
+
+func Test_backuper_backup(t *testing.T) {
+	stores := map[string]func() Store{
+		"ConfigMap": func() Store {
+			k8sClient := testclient.NewClientBuilder().WithScheme(newTestScheme()).Build()
+			return NewConfigMapStore(types.NamespacedName{Namespace: "appmesh-system", Name: "snapshots"}, k8sClient, k8sClient)
+		},
+		"S3": func() Store {
+			return NewS3Store(&fakeS3{objects: map[string][]byte{"other/key.json.gz": nil}}, "my-bucket", "appmesh-controller/")
+		},
+	}
+	for storeName, newStore := range stores {
+		t.Run(storeName, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore()
+			b := NewBackuper(&fakeSnapshotter{}, store, time.Hour, 2, logr.Discard()).(*backuper)
+			now := time.Date(2023, 6, 3, 10, 0, 0, 0, time.UTC)
+			b.nowFunc = func() time.Time { return now }
+
+			names, err := store.List(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, names)
+			for i := 0; i < 3; i++ {
+				_, err := b.backup(ctx)
+				require.NoError(t, err)
+				now = now.Add(time.Hour)
+			}
+
+			names, err = store.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"20230603T110000Z", "20230603T120000Z"}, names)
+			snapshot, err := store.Load(ctx, "20230603T120000Z")
+			require.NoError(t, err)
+			assert.Equal(t, "20230603T120000Z", snapshot.Name)
+			assert.True(t, time.Date(2023, 6, 3, 12, 0, 0, 0, time.UTC).Equal(snapshot.Time.Time))
+			require.Len(t, snapshot.Objects, 1)
+			assert.Equal(t, "my-mesh", snapshot.Objects[0].GetName())
+
+			_, err = store.Load(ctx, "20230603T100000Z")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_snapshotNames(t *testing.T) {
+	keys := []string{
+		"prefix/20230603T120000Z.json.gz",
+		"prefix/20230603T110000Z.json.gz",
+		"prefix/nested/20230603T100000Z.json.gz",
+		"prefix/notes.txt",
+		"other/20230603T090000Z.json.gz",
+	}
+	assert.Equal(t, []string{"20230603T110000Z", "20230603T120000Z"}, snapshotNames(keys, "prefix/"))
+}
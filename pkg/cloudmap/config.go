@@ -1,21 +1,43 @@
 package cloudmap
 
 import (
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 )
 
 const (
-	flagSetCloudMapTTL = "cloudmap-dns-ttl"
+	flagSetCloudMapTTL         = "cloudmap-dns-ttl"
+	flagCloudMapNamespaceVPCID = "cloudmap-namespace-vpc-id"
+
+	flagCloudMapInstanceOperationsQPS         = "cloudmap-instance-operations-qps"
+	flagCloudMapInstanceOperationsBurst       = "cloudmap-instance-operations-burst"
+	flagCloudMapInstanceOperationsConcurrency = "cloudmap-instance-operations-concurrency"
 )
 
 type Config struct {
 	//Specifies the DNS TTL value to be used while creating CloudMap services.
 	CloudMapServiceTTL int64
+	// NamespaceVPCID is the VPC associated with CloudMap private DNS namespaces created for VirtualNodes that manage their namespace.
+	NamespaceVPCID string
+	// InstanceOperationsQPS is the rate at which CloudMap instance register and deregister operations are started.
+	InstanceOperationsQPS float64
+	// InstanceOperationsBurst is the burst of CloudMap instance register and deregister operations started at once.
+	InstanceOperationsBurst int
+	// InstanceOperationsConcurrency is the maximum number of CloudMap instance register and deregister operations in progress.
+	InstanceOperationsConcurrency int
 }
 
 func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
 	fs.Int64Var(&cfg.CloudMapServiceTTL, flagSetCloudMapTTL, defaultServiceDNSConfigTTL,
 		`CloudMap Service DNS TTL value`)
+	fs.StringVar(&cfg.NamespaceVPCID, flagCloudMapNamespaceVPCID, "",
+		`VPC ID of CloudMap private DNS namespaces created for VirtualNodes with spec.serviceDiscovery.awsCloudMap.manageNamespace`)
+	fs.Float64Var(&cfg.InstanceOperationsQPS, flagCloudMapInstanceOperationsQPS, defaultInstanceOperationsQPS,
+		`Rate per second of CloudMap instance register and deregister operations`)
+	fs.IntVar(&cfg.InstanceOperationsBurst, flagCloudMapInstanceOperationsBurst, defaultInstanceOperationsBurst,
+		`Burst of CloudMap instance register and deregister operations`)
+	fs.IntVar(&cfg.InstanceOperationsConcurrency, flagCloudMapInstanceOperationsConcurrency, defaultInstanceOperationsConcurrency,
+		`Maximum number of CloudMap instance register and deregister operations in progress`)
 }
 
 func (cfg *Config) BindEnv() error {
@@ -23,5 +45,14 @@ func (cfg *Config) BindEnv() error {
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.InstanceOperationsQPS <= 0 {
+		return errors.Errorf("--%v must be positive", flagCloudMapInstanceOperationsQPS)
+	}
+	if cfg.InstanceOperationsBurst < 1 {
+		return errors.Errorf("--%v must be at least 1", flagCloudMapInstanceOperationsBurst)
+	}
+	if cfg.InstanceOperationsConcurrency < 1 {
+		return errors.Errorf("--%v must be at least 1", flagCloudMapInstanceOperationsConcurrency)
+	}
 	return nil
 }
@@ -4,10 +4,9 @@ import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/workqueue"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -83,11 +82,11 @@ func (h *enqueueRequestsForPodEvents) enqueueVirtualNodesForPods(ctx context.Con
 		if vn.Spec.ServiceDiscovery == nil || vn.Spec.ServiceDiscovery.AWSCloudMap == nil {
 			continue
 		}
-		selector, err := metav1.LabelSelectorAsSelector(vn.Spec.PodSelector)
+		matches, err := virtualnode.MatchesPodLabels(&vn, pod.Labels)
 		if err != nil {
 			continue
 		}
-		if selector.Matches(labels.Set(pod.Labels)) {
+		if matches {
 			queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vn)})
 		}
 	}
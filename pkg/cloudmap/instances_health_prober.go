@@ -2,6 +2,7 @@ package cloudmap
 
 import (
 	"context"
+	appmeshaws "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-sdk-go/aws"
@@ -86,6 +87,13 @@ func (p *defaultInstancesHealthProber) Submit(ctx context.Context, service servi
 	if service.healthCheckCustomConfig != nil {
 		probeFunc = p.probeInstanceHealthyStatusWithCustomHC
 	}
+	// probes run asynchronously on prober's own context, so the IAM role to assume must be carried over.
+	if roleARN, ok := appmeshaws.RoleARNFromContext(ctx); ok {
+		roleAgnosticProbeFunc := probeFunc
+		probeFunc = func(ctx context.Context, serviceID string, instanceIDs []string) (map[string]bool, error) {
+			return roleAgnosticProbeFunc(appmeshaws.WithRoleARN(ctx, roleARN), serviceID, instanceIDs)
+		}
+	}
 
 	instanceInfoByID, err := p.filterInstancesBlockedByCMHealthyReadinessGate(ctx, instanceInfoByID)
 	if err != nil {
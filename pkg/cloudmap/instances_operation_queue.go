@@ -0,0 +1,177 @@
+package cloudmap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	defaultInstanceOperationsQPS         = 4
+	defaultInstanceOperationsBurst       = 10
+	defaultInstanceOperationsConcurrency = 10
+)
+
+// instanceOperationQueue queues register and deregister operations of cloudMap instances.
+type instanceOperationQueue interface {
+	// RegisterInstance enqueues registration of instance with attrs, the returned channel receives the operation result.
+	RegisterInstance(ctx context.Context, serviceID string, instanceID string, attrs instanceAttributes) <-chan error
+	// DeregisterInstance enqueues deregistration of instance, the returned channel receives the operation result.
+	DeregisterInstance(ctx context.Context, serviceID string, instanceID string) <-chan error
+}
+
+// newDefaultInstanceOperationQueue constructs new defaultInstanceOperationQueue, its workers run until ctx is done.
+func newDefaultInstanceOperationQueue(ctx context.Context, instancesCache instancesCache, config Config, metricsRegisterer prometheus.Registerer, log logr.Logger) (*defaultInstanceOperationQueue, error) {
+	depthGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "appmesh",
+		Name:      "cloudmap_instance_operations_queue_depth",
+		Help:      "Number of cloudMap instance register and deregister operations waiting to be executed",
+	}, []string{"service_id"})
+	if metricsRegisterer != nil {
+		if err := metricsRegisterer.Register(depthGauge); err != nil {
+			return nil, errors.Wrap(err, "failed to register cloudMap instance operations metrics")
+		}
+	}
+	q := &defaultInstanceOperationQueue{
+		instancesCache:               instancesCache,
+		limiter:                      rate.NewLimiter(rate.Limit(config.InstanceOperationsQPS), config.InstanceOperationsBurst),
+		queue:                        workqueue.New(),
+		pendingOperationByInstance:   make(map[instanceOperationKey]*instanceOperation),
+		pendingOperationsByServiceID: make(map[string]int),
+		depthGauge:                   depthGauge,
+		log:                          log,
+	}
+	for i := 0; i < config.InstanceOperationsConcurrency; i++ {
+		go q.worker()
+	}
+	go func() {
+		<-ctx.Done()
+		q.queue.ShutDown()
+	}()
+	return q, nil
+}
+
+var _ instanceOperationQueue = &defaultInstanceOperationQueue{}
+
+// defaultInstanceOperationQueue executes operations with limited concurrency and rate, so pod churn of large
+// Deployments doesn't exceed cloudMap rate limits.
+// a pending operation of an instance is coalesced with later operations of the same instance into the latest one,
+// and all callers receive its result.
+type defaultInstanceOperationQueue struct {
+	instancesCache instancesCache
+	limiter        *rate.Limiter
+	queue          workqueue.Interface
+
+	// operations not yet executed, by instance.
+	pendingOperationByInstance map[instanceOperationKey]*instanceOperation
+	// number of operations not yet executed, by serviceID.
+	pendingOperationsByServiceID map[string]int
+	// protects pendingOperationByInstance and pendingOperationsByServiceID
+	mutex sync.Mutex
+
+	depthGauge *prometheus.GaugeVec
+	log        logr.Logger
+}
+
+type instanceOperationKey struct {
+	serviceID  string
+	instanceID string
+}
+
+type instanceOperation struct {
+	ctx         context.Context
+	deregister  bool
+	attrs       instanceAttributes
+	resultChans []chan<- error
+}
+
+func (q *defaultInstanceOperationQueue) RegisterInstance(ctx context.Context, serviceID string, instanceID string, attrs instanceAttributes) <-chan error {
+	return q.enqueue(ctx, instanceOperationKey{serviceID: serviceID, instanceID: instanceID}, false, attrs)
+}
+
+func (q *defaultInstanceOperationQueue) DeregisterInstance(ctx context.Context, serviceID string, instanceID string) <-chan error {
+	return q.enqueue(ctx, instanceOperationKey{serviceID: serviceID, instanceID: instanceID}, true, nil)
+}
+
+func (q *defaultInstanceOperationQueue) enqueue(ctx context.Context, key instanceOperationKey, deregister bool, attrs instanceAttributes) <-chan error {
+	resultChan := make(chan error, 1)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	operation, ok := q.pendingOperationByInstance[key]
+	if !ok {
+		operation = &instanceOperation{}
+		q.pendingOperationByInstance[key] = operation
+		q.pendingOperationsByServiceID[key.serviceID]++
+		q.depthGauge.WithLabelValues(key.serviceID).Set(float64(q.pendingOperationsByServiceID[key.serviceID]))
+	} else {
+		q.log.V(1).Info("coalescing cloudMap instance operation",
+			"serviceID", key.serviceID,
+			"instanceID", key.instanceID,
+		)
+	}
+	operation.ctx = ctx
+	operation.deregister = deregister
+	operation.attrs = attrs
+	operation.resultChans = append(operation.resultChans, resultChan)
+	q.queue.Add(key)
+	return resultChan
+}
+
+func (q *defaultInstanceOperationQueue) worker() {
+	for q.processNextOperation() {
+	}
+}
+
+func (q *defaultInstanceOperationQueue) processNextOperation() bool {
+	item, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(item)
+
+	key := item.(instanceOperationKey)
+	operation := q.dequeue(key)
+	// already executed, when the instance was enqueued again while its previous operation was being executed.
+	if operation == nil {
+		return true
+	}
+	err := q.limiter.Wait(operation.ctx)
+	if err == nil {
+		if operation.deregister {
+			err = q.instancesCache.DeregisterInstance(operation.ctx, key.serviceID, key.instanceID)
+		} else {
+			err = q.instancesCache.RegisterInstance(operation.ctx, key.serviceID, key.instanceID, operation.attrs)
+		}
+	}
+	for _, resultChan := range operation.resultChans {
+		resultChan <- err
+		close(resultChan)
+	}
+	return true
+}
+
+// dequeue removes the pending operation of instance, it returns nil if there is none.
+func (q *defaultInstanceOperationQueue) dequeue(key instanceOperationKey) *instanceOperation {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	operation, ok := q.pendingOperationByInstance[key]
+	if !ok {
+		return nil
+	}
+	delete(q.pendingOperationByInstance, key)
+	q.pendingOperationsByServiceID[key.serviceID]--
+	if q.pendingOperationsByServiceID[key.serviceID] == 0 {
+		delete(q.pendingOperationsByServiceID, key.serviceID)
+		q.depthGauge.DeleteLabelValues(key.serviceID)
+	} else {
+		q.depthGauge.WithLabelValues(key.serviceID).Set(float64(q.pendingOperationsByServiceID[key.serviceID]))
+	}
+	return operation
+}
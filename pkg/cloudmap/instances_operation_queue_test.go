@@ -0,0 +1,130 @@
+package cloudmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type instanceOperationCall struct {
+	serviceID  string
+	instanceID string
+	deregister bool
+	attrs      instanceAttributes
+}
+
+// recordingInstancesCache records register and deregister operations it executed.
+type recordingInstancesCache struct {
+	calls []instanceOperationCall
+	err   error
+}
+
+func (c *recordingInstancesCache) ListInstances(ctx context.Context, serviceID string) (map[string]instanceAttributes, error) {
+	return nil, nil
+}
+
+func (c *recordingInstancesCache) RegisterInstance(ctx context.Context, serviceID string, instanceID string, attrs instanceAttributes) error {
+	c.calls = append(c.calls, instanceOperationCall{serviceID: serviceID, instanceID: instanceID, attrs: attrs})
+	return c.err
+}
+
+func (c *recordingInstancesCache) DeregisterInstance(ctx context.Context, serviceID string, instanceID string) error {
+	c.calls = append(c.calls, instanceOperationCall{serviceID: serviceID, instanceID: instanceID, deregister: true})
+	return c.err
+}
+
+func newTestInstanceOperationQueue(instancesCache instancesCache) *defaultInstanceOperationQueue {
+	return &defaultInstanceOperationQueue{
+		instancesCache:               instancesCache,
+		limiter:                      rate.NewLimiter(rate.Inf, 1),
+		queue:                        workqueue.New(),
+		pendingOperationByInstance:   make(map[instanceOperationKey]*instanceOperation),
+		pendingOperationsByServiceID: make(map[string]int),
+		depthGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cloudmap_instance_operations_queue_depth",
+		}, []string{"service_id"}),
+		log: logr.New(&log.NullLogSink{}),
+	}
+}
+
+func Test_defaultInstanceOperationQueue_coalescing(t *testing.T) {
+	ctx := context.Background()
+	instancesCache := &recordingInstancesCache{}
+	q := newTestInstanceOperationQueue(instancesCache)
+
+	registerResultChan := q.RegisterInstance(ctx, "svc-1", "192.168.1.1", instanceAttributes{"k": "v1"})
+	updateResultChan := q.RegisterInstance(ctx, "svc-1", "192.168.1.1", instanceAttributes{"k": "v2"})
+	deregisterResultChan := q.DeregisterInstance(ctx, "svc-1", "192.168.1.2")
+	otherServiceResultChan := q.RegisterInstance(ctx, "svc-2", "192.168.2.1", instanceAttributes{"k": "v1"})
+
+	assert.Equal(t, 3, q.queue.Len())
+	assert.Equal(t, float64(2), testutil.ToFloat64(q.depthGauge.WithLabelValues("svc-1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(q.depthGauge.WithLabelValues("svc-2")))
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, q.processNextOperation())
+	}
+	assert.ElementsMatch(t, []instanceOperationCall{
+		{serviceID: "svc-1", instanceID: "192.168.1.1", attrs: instanceAttributes{"k": "v2"}},
+		{serviceID: "svc-1", instanceID: "192.168.1.2", deregister: true},
+		{serviceID: "svc-2", instanceID: "192.168.2.1", attrs: instanceAttributes{"k": "v1"}},
+	}, instancesCache.calls)
+	for _, resultChan := range []<-chan error{registerResultChan, updateResultChan, deregisterResultChan, otherServiceResultChan} {
+		assert.NoError(t, <-resultChan)
+	}
+	assert.Empty(t, q.pendingOperationByInstance)
+	assert.Empty(t, q.pendingOperationsByServiceID)
+	assert.Equal(t, 0, testutil.CollectAndCount(q.depthGauge))
+}
+
+func Test_defaultInstanceOperationQueue_enqueueWhileExecuting(t *testing.T) {
+	ctx := context.Background()
+	instancesCache := &recordingInstancesCache{}
+	q := newTestInstanceOperationQueue(instancesCache)
+
+	q.RegisterInstance(ctx, "svc-1", "192.168.1.1", instanceAttributes{"k": "v1"})
+	item, _ := q.queue.Get()
+	operation := q.dequeue(item.(instanceOperationKey))
+	// the instance is enqueued again while its register operation is being executed.
+	deregisterResultChan := q.DeregisterInstance(ctx, "svc-1", "192.168.1.1")
+	assert.False(t, operation.deregister)
+	assert.Len(t, operation.resultChans, 1)
+	q.queue.Done(item)
+
+	assert.True(t, q.processNextOperation())
+	assert.Equal(t, []instanceOperationCall{
+		{serviceID: "svc-1", instanceID: "192.168.1.1", deregister: true},
+	}, instancesCache.calls)
+	assert.NoError(t, <-deregisterResultChan)
+}
+
+func Test_defaultInstanceOperationQueue_operationFailed(t *testing.T) {
+	ctx := context.Background()
+	instancesCache := &recordingInstancesCache{err: assert.AnError}
+	q := newTestInstanceOperationQueue(instancesCache)
+
+	resultChan := q.RegisterInstance(ctx, "svc-1", "192.168.1.1", instanceAttributes{"k": "v1"})
+	assert.True(t, q.processNextOperation())
+	assert.Equal(t, assert.AnError, <-resultChan)
+}
+
+func Test_defaultInstanceOperationQueue_contextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	instancesCache := &recordingInstancesCache{}
+	q := newTestInstanceOperationQueue(instancesCache)
+	q.limiter = rate.NewLimiter(rate.Limit(1), 1)
+	q.limiter.Allow()
+
+	resultChan := q.RegisterInstance(ctx, "svc-1", "192.168.1.1", instanceAttributes{"k": "v1"})
+	assert.True(t, q.processNextOperation())
+	assert.Error(t, <-resultChan)
+	assert.Empty(t, instancesCache.calls)
+}
@@ -2,8 +2,10 @@ package cloudmap
 
 import (
 	"context"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sync"
 )
@@ -19,11 +21,16 @@ type instancesReconcileReactor interface {
 }
 
 // newDefaultInstancesReconcileReactor constructs new defaultInstancesReconcileReactor
-func newDefaultInstancesReconcileReactor(ctx context.Context, k8sClient client.Client, cloudMapSDK services.CloudMap, log logr.Logger) *defaultInstancesReconcileReactor {
+func newDefaultInstancesReconcileReactor(ctx context.Context, k8sClient client.Client, cloudMapSDK services.CloudMap, config Config, metricsRegisterer prometheus.Registerer, log logr.Logger) (*defaultInstancesReconcileReactor, error) {
 	instancesCache := newDefaultInstancesCache(cloudMapSDK)
+	instancesOperationQueue, err := newDefaultInstanceOperationQueue(ctx, instancesCache, config, metricsRegisterer, log)
+	if err != nil {
+		return nil, err
+	}
 	reactor := &defaultInstancesReconcileReactor{
 		cloudMapSDK:                       cloudMapSDK,
 		instancesCache:                    instancesCache,
+		instancesOperationQueue:           instancesOperationQueue,
 		reconcileRequestChan:              make(chan instancesReconcileRequest, defaultInstancesReconcileReactorRequestChanBuffer),
 		reconcileTaskByServiceSubset:      make(map[serviceSubsetID]*instancesReconcileTask),
 		reconcileTaskByServiceSubsetMutex: sync.RWMutex{},
@@ -31,14 +38,15 @@ func newDefaultInstancesReconcileReactor(ctx context.Context, k8sClient client.C
 	}
 
 	go reactor.reactorLoop(ctx)
-	return reactor
+	return reactor, nil
 }
 
 var _ instancesReconcileReactor = &defaultInstancesReconcileReactor{}
 
 type defaultInstancesReconcileReactor struct {
-	cloudMapSDK    services.CloudMap
-	instancesCache instancesCache
+	cloudMapSDK             services.CloudMap
+	instancesCache          instancesCache
+	instancesOperationQueue instanceOperationQueue
 
 	// channel to receive reconcile requests
 	reconcileRequestChan chan instancesReconcileRequest
@@ -57,6 +65,8 @@ type instancesReconcileRequest struct {
 	readyInstanceInfoByID   map[string]instanceInfo
 	unreadyInstanceInfoByID map[string]instanceInfo
 	resultChan              chan<- error
+	// IAM role assumed for cloudMap calls, if any.
+	roleARN string
 }
 
 func (r *defaultInstancesReconcileReactor) Submit(ctx context.Context, service serviceSummary, subset serviceSubset, readyInstanceInfoByID map[string]instanceInfo, unreadyInstanceInfoByID map[string]instanceInfo) <-chan error {
@@ -68,6 +78,9 @@ func (r *defaultInstancesReconcileReactor) Submit(ctx context.Context, service s
 		unreadyInstanceInfoByID: unreadyInstanceInfoByID,
 		resultChan:              resultChan,
 	}
+	if roleARN, ok := aws.RoleARNFromContext(ctx); ok {
+		reconcileRequest.roleARN = roleARN
+	}
 
 	select {
 	case <-ctx.Done():
@@ -111,13 +124,17 @@ func (r *defaultInstancesReconcileReactor) dispatch(ctx context.Context, request
 
 // dispatch new cloudMap service subset reconcile task to run on a new task.
 func (r *defaultInstancesReconcileReactor) dispatchToNewTask(ctx context.Context, serviceSubsetID serviceSubsetID, request instancesReconcileRequest) {
-	reconcileTask := newInstancesReconcileTask(r.cloudMapSDK, r.instancesCache, r.log, make(chan struct{}))
+	reconcileTask := newInstancesReconcileTask(r.cloudMapSDK, r.instancesCache, r.instancesOperationQueue, r.log, make(chan struct{}))
 	r.reconcileTaskByServiceSubsetMutex.Lock()
 	r.reconcileTaskByServiceSubset[serviceSubsetID] = reconcileTask
 	r.reconcileTaskByServiceSubsetMutex.Unlock()
 
+	taskCtx := ctx
+	if len(request.roleARN) != 0 {
+		taskCtx = aws.WithRoleARN(ctx, request.roleARN)
+	}
 	go func() {
-		reconcileTask.Run(ctx)
+		reconcileTask.Run(taskCtx)
 		r.reconcileTaskByServiceSubsetMutex.Lock()
 		delete(r.reconcileTaskByServiceSubset, serviceSubsetID)
 		r.reconcileTaskByServiceSubsetMutex.Unlock()
@@ -16,14 +16,15 @@ const (
 )
 
 // newInstancesReconcileTask constructs new instancesReconcileTask for specific subset of cloudMap service.
-func newInstancesReconcileTask(cloudMapSDK services.CloudMap, instancesCache instancesCache, log logr.Logger, done chan struct{}) *instancesReconcileTask {
+func newInstancesReconcileTask(cloudMapSDK services.CloudMap, instancesCache instancesCache, instancesOperationQueue instanceOperationQueue, log logr.Logger, done chan struct{}) *instancesReconcileTask {
 	return &instancesReconcileTask{
-		cloudMapSDK:    cloudMapSDK,
-		instancesCache: instancesCache,
-		done:           done,
+		cloudMapSDK:             cloudMapSDK,
+		instancesCache:          instancesCache,
+		instancesOperationQueue: instancesOperationQueue,
+		done:                    done,
 
 		instancesReconcileRequestChan:   make(chan instancesReconcileRequest),
-		instancesWithOngoingOperation:   sets.String{},
+		instancesWithOngoingOperation:   make(map[string]*ongoingInstanceOperation),
 		instanceOperationCompletionChan: make(chan instanceOperationResult),
 		log:                             log,
 	}
@@ -32,13 +33,14 @@ func newInstancesReconcileTask(cloudMapSDK services.CloudMap, instancesCache ins
 // instancesReconcileTask representing the work to reconcile instances for specific subset of cloudMap service.
 // each instancesReconcileTask should be limited to only work for a single service & subset.
 type instancesReconcileTask struct {
-	cloudMapSDK    services.CloudMap
-	instancesCache instancesCache
-	done           chan struct{}
+	cloudMapSDK             services.CloudMap
+	instancesCache          instancesCache
+	instancesOperationQueue instanceOperationQueue
+	done                    chan struct{}
 
 	instancesReconcileRequestChan chan instancesReconcileRequest
-	// instances that have on-going operation, we'll skip these instances.
-	instancesWithOngoingOperation sets.String
+	// instances that have on-going operation, we'll skip these instances unless their desired operation changed.
+	instancesWithOngoingOperation map[string]*ongoingInstanceOperation
 	// chan of instances that completed operation
 	instanceOperationCompletionChan chan instanceOperationResult
 
@@ -50,6 +52,14 @@ type instanceOperationResult struct {
 	err        error
 }
 
+type ongoingInstanceOperation struct {
+	// the latest operation enqueued for instance.
+	deregister bool
+	attrs      instanceAttributes
+	// number of enqueued operations without result yet.
+	pendingResults int
+}
+
 // run starts the instancesReconcileTask
 // It terminates when instances have been successfully reconciled according to desired state.
 // i.e. no new desiredState and all async operations are completed.
@@ -76,7 +86,7 @@ func (t *instancesReconcileTask) Run(ctx context.Context) {
 			close(request.resultChan)
 			request = newRequest
 		case operationResult := <-t.instanceOperationCompletionChan:
-			t.instancesWithOngoingOperation.Delete(operationResult.instanceID)
+			t.completeInstanceOperation(operationResult.instanceID)
 			if operationResult.err != nil {
 				request.resultChan <- operationResult.err
 				close(request.resultChan)
@@ -101,37 +111,68 @@ func (t *instancesReconcileTask) reconcile(ctx context.Context, service serviceS
 	t.log.V(1).Info("CloudMap: Register Instances", "InstanceToCreateOrUpdate", instancesToCreateOrUpdate)
 
 	for instanceID, info := range instancesToCreateOrUpdate {
-		if t.instancesWithOngoingOperation.Has(instanceID) {
+		if !t.shouldEnqueueInstanceOperation(instanceID, false, info.attrs) {
 			continue
 		}
-		t.instancesWithOngoingOperation.Insert(instanceID)
-		go func(instanceID string, info instanceInfo) {
-			err := t.instancesCache.RegisterInstance(ctx, service.serviceID, instanceID, info.attrs)
-			select {
-			case t.instanceOperationCompletionChan <- instanceOperationResult{instanceID: instanceID, err: err}:
-			case <-t.done:
-			}
-		}(instanceID, info)
+		resultChan := t.instancesOperationQueue.RegisterInstance(ctx, service.serviceID, instanceID, info.attrs)
+		go t.awaitInstanceOperation(ctx, instanceID, resultChan)
 	}
 
 	t.log.V(1).Info("CloudMap: Deregister Instances", "instancesToDelete", instancesToDelete)
 
 	for _, instanceID := range instancesToDelete {
-		if t.instancesWithOngoingOperation.Has(instanceID) {
+		if !t.shouldEnqueueInstanceOperation(instanceID, true, nil) {
 			continue
 		}
-		t.instancesWithOngoingOperation.Insert(instanceID)
-		go func(instanceID string) {
-			err := t.instancesCache.DeregisterInstance(ctx, service.serviceID, instanceID)
-			select {
-			case t.instanceOperationCompletionChan <- instanceOperationResult{instanceID: instanceID, err: err}:
-			case <-t.done:
-			}
-		}(instanceID)
+		resultChan := t.instancesOperationQueue.DeregisterInstance(ctx, service.serviceID, instanceID)
+		go t.awaitInstanceOperation(ctx, instanceID, resultChan)
 	}
 	return nil
 }
 
+// shouldEnqueueInstanceOperation checks whether operation should be enqueued for instance, and records it as ongoing if so.
+// an instance with ongoing operation is only enqueued again if its desired operation changed,
+// which the queue coalesces with the ongoing operation unless it's already executing.
+func (t *instancesReconcileTask) shouldEnqueueInstanceOperation(instanceID string, deregister bool, attrs instanceAttributes) bool {
+	ongoingOperation, ok := t.instancesWithOngoingOperation[instanceID]
+	if !ok {
+		ongoingOperation = &ongoingInstanceOperation{}
+		t.instancesWithOngoingOperation[instanceID] = ongoingOperation
+	} else if ongoingOperation.deregister == deregister && cmp.Equal(ongoingOperation.attrs, attrs) {
+		return false
+	}
+	ongoingOperation.deregister = deregister
+	ongoingOperation.attrs = attrs
+	ongoingOperation.pendingResults++
+	return true
+}
+
+// completeInstanceOperation records a result of instance's enqueued operations.
+func (t *instancesReconcileTask) completeInstanceOperation(instanceID string) {
+	ongoingOperation, ok := t.instancesWithOngoingOperation[instanceID]
+	if !ok {
+		return
+	}
+	ongoingOperation.pendingResults--
+	if ongoingOperation.pendingResults == 0 {
+		delete(t.instancesWithOngoingOperation, instanceID)
+	}
+}
+
+// awaitInstanceOperation waits for result of instance's enqueued operation, and sends it to instanceOperationCompletionChan.
+func (t *instancesReconcileTask) awaitInstanceOperation(ctx context.Context, instanceID string, resultChan <-chan error) {
+	var err error
+	select {
+	case err = <-resultChan:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	select {
+	case t.instanceOperationCompletionChan <- instanceOperationResult{instanceID: instanceID, err: err}:
+	case <-t.done:
+	}
+}
+
 func (t *instancesReconcileTask) matchDesiredInstancesAgainstExistingInstances(
 	desiredReadyInstanceInfoByID map[string]instanceInfo,
 	desiredNotReadyInstanceInfoByID map[string]instanceInfo,
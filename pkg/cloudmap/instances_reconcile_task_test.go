@@ -1,8 +1,10 @@
 package cloudmap
 
 import (
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"testing"
 )
 
@@ -521,3 +523,22 @@ func Test_instancesReconcileTask_matchDesiredInstancesAgainstExistingInstances(t
 		})
 	}
 }
+
+func Test_instancesReconcileTask_shouldEnqueueInstanceOperation(t *testing.T) {
+	task := newInstancesReconcileTask(nil, nil, nil, logr.New(&log.NullLogSink{}), make(chan struct{}))
+
+	assert.True(t, task.shouldEnqueueInstanceOperation("192.168.1.1", false, instanceAttributes{"k": "v1"}))
+	// same operation is already ongoing.
+	assert.False(t, task.shouldEnqueueInstanceOperation("192.168.1.1", false, instanceAttributes{"k": "v1"}))
+	// desired attributes changed while registering.
+	assert.True(t, task.shouldEnqueueInstanceOperation("192.168.1.1", false, instanceAttributes{"k": "v2"}))
+	assert.Equal(t, 2, task.instancesWithOngoingOperation["192.168.1.1"].pendingResults)
+
+	task.completeInstanceOperation("192.168.1.1")
+	assert.Contains(t, task.instancesWithOngoingOperation, "192.168.1.1")
+	task.completeInstanceOperation("192.168.1.1")
+	assert.NotContains(t, task.instancesWithOngoingOperation, "192.168.1.1")
+
+	assert.True(t, task.shouldEnqueueInstanceOperation("192.168.1.1", true, nil))
+	assert.False(t, task.shouldEnqueueInstanceOperation("192.168.1.1", true, nil))
+}
@@ -2,15 +2,18 @@ package cloudmap
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/servicediscovery"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -32,10 +35,17 @@ const (
 	AttrK8sPod = "k8s.io/pod"
 	// AttrK8sNamespace is a custom attribute injected by app-mesh controller
 	AttrK8sNamespace = "k8s.io/namespace"
+	// AttrK8sOwnerKind is a custom attribute injected by app-mesh controller
+	AttrK8sOwnerKind = "k8s.io/owner-kind"
+	// AttrK8sOwnerName is a custom attribute injected by app-mesh controller
+	AttrK8sOwnerName = "k8s.io/owner-name"
 	// AttrK8sPodRegion is a custom attribute injected by app-mesh controller
 	AttrK8sPodRegion = "REGION"
 	// AttrK8sPodAZ is a custom attribute injected by app-mesh controller
 	AttrK8sPodAZ = "AVAILABILITY_ZONE"
+	// AttrK8sPodHostname is a custom attribute injected by app-mesh controller for pods with a hostname and subdomain,
+	// i.e. StatefulSet pods behind their headless service. It's the pod's stable DNS name, which is kept across pod restarts.
+	AttrK8sPodHostname = "k8s.io/pod-hostname"
 
 	AttrAppMeshMesh        = "appmesh.k8s.aws/mesh"
 	AttrAppMeshVirtualNode = "appmesh.k8s.aws/virtualNode"
@@ -55,7 +65,7 @@ type InstancesReconciler interface {
 		readyPods []*corev1.Pod, notReadyPods []*corev1.Pod, nodeInfoByName map[string]nodeAttributes) error
 }
 
-func NewDefaultInstancesReconciler(k8sClient client.Client, cloudMapSDK services.CloudMap, log logr.Logger, stopChan <-chan struct{}, ipFamily string) *defaultInstancesReconciler {
+func NewDefaultInstancesReconciler(k8sClient client.Client, cloudMapSDK services.CloudMap, config Config, metricsRegisterer prometheus.Registerer, log logr.Logger, stopChan <-chan struct{}, ipFamily string) (*defaultInstancesReconciler, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		select {
@@ -64,7 +74,11 @@ func NewDefaultInstancesReconciler(k8sClient client.Client, cloudMapSDK services
 		}
 	}()
 
-	instancesReconcileReactor := newDefaultInstancesReconcileReactor(ctx, k8sClient, cloudMapSDK, log)
+	instancesReconcileReactor, err := newDefaultInstancesReconcileReactor(ctx, k8sClient, cloudMapSDK, config, metricsRegisterer, log)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 	instancesHealthProber := newDefaultInstancesHealthProber(ctx, k8sClient, cloudMapSDK, log)
 	return &defaultInstancesReconciler{
 		cloudMapSDK:               cloudMapSDK,
@@ -72,7 +86,7 @@ func NewDefaultInstancesReconciler(k8sClient client.Client, cloudMapSDK services
 		instancesHealthProber:     instancesHealthProber,
 		log:                       log,
 		ipFamily:                  ipFamily,
-	}
+	}, nil
 }
 
 var _ InstancesReconciler = &defaultInstancesReconciler{}
@@ -171,14 +185,18 @@ func (r *defaultInstancesReconciler) buildInstanceAttributes(ms *appmesh.Mesh, v
 		attr[AttrAWSInstanceIPV4] = pod.Status.PodIP
 	}
 
-	/* VirtualNode currently supports only one listener. In future even if support for multiple listeners is introduced,
-	we will always derive the port value from the first listener config. */
+	/* Cloud Map instances carry a single port, so it's always derived from the first listener config
+	even if VirtualNode has multiple listeners. */
 
 	attr[AttrAWSInstancePort] = strconv.Itoa(int(vn.Spec.Listeners[0].PortMapping.Port))
 	attr[AttrK8sPod] = pod.Name
 	attr[AttrK8sNamespace] = pod.Namespace
+	attr[AttrK8sOwnerKind], attr[AttrK8sOwnerName] = k8s.ResolvePodOwner(pod)
 	attr[AttrAppMeshMesh] = aws.StringValue(ms.Spec.AWSName)
 	attr[AttrAppMeshVirtualNode] = aws.StringValue(vn.Spec.AWSName)
+	if pod.Spec.Hostname != "" && pod.Spec.Subdomain != "" {
+		attr[AttrK8sPodHostname] = fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, pod.Spec.Subdomain, pod.Namespace)
+	}
 	if nodeInfo, ok := nodeInfoByName[podsNodeName]; ok {
 		if nodeInfo.region != "" {
 			attr[AttrK8sPodRegion] = nodeInfo.region
@@ -63,6 +63,58 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes(t *testing.T) {
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
+				"appmesh.k8s.aws/mesh":        "my-mesh",
+				"appmesh.k8s.aws/virtualNode": "my-vn",
+			},
+		},
+		{
+			name: "attributes should have pod hostname of StatefulSet pods",
+			args: args{
+				ms: &appmesh.Mesh{
+					Spec: appmesh.MeshSpec{
+						AWSName: aws.String("my-mesh"),
+					},
+				},
+				vn: &appmesh.VirtualNode{
+					Spec: appmesh.VirtualNodeSpec{
+						AWSName: aws.String("my-vn"),
+						ServiceDiscovery: &appmesh.ServiceDiscovery{
+							AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{},
+						},
+						Listeners: []appmesh.Listener{{
+							PortMapping: appmesh.PortMapping{
+								Port: appmesh.PortNumber(5432),
+							}},
+						},
+					},
+				},
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "pod-ns",
+						Name:      "db-0",
+						OwnerReferences: []metav1.OwnerReference{
+							{Kind: "StatefulSet", Name: "db", Controller: aws.Bool(true)},
+						},
+					},
+					Spec: corev1.PodSpec{
+						Hostname:  "db-0",
+						Subdomain: "db-headless",
+					},
+					Status: corev1.PodStatus{
+						PodIP: "192.168.1.42",
+					},
+				},
+			},
+			want: instanceAttributes{
+				"AWS_INSTANCE_IPV4":           "192.168.1.42",
+				"AWS_INSTANCE_PORT":           "5432",
+				"k8s.io/pod":                  "db-0",
+				"k8s.io/pod-hostname":         "db-0.db-headless.pod-ns.svc",
+				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "StatefulSet",
+				"k8s.io/owner-name":           "db",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -119,6 +171,8 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes(t *testing.T) {
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -181,6 +235,8 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes(t *testing.T) {
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -240,6 +296,59 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes(t *testing.T) {
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
+				"appmesh.k8s.aws/mesh":        "my-mesh",
+				"appmesh.k8s.aws/virtualNode": "my-vn",
+			},
+		},
+		{
+			name: "attributes should have controller of pod created by operator",
+			args: args{
+				ms: &appmesh.Mesh{
+					Spec: appmesh.MeshSpec{
+						AWSName: aws.String("my-mesh"),
+					},
+				},
+				vn: &appmesh.VirtualNode{
+					Spec: appmesh.VirtualNodeSpec{
+						AWSName: aws.String("my-vn"),
+						ServiceDiscovery: &appmesh.ServiceDiscovery{
+							AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{},
+						},
+						Listeners: []appmesh.Listener{{
+							PortMapping: appmesh.PortMapping{
+								Port: appmesh.PortNumber(8080),
+							}},
+						},
+					},
+				},
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "pod-ns",
+						Name:      "pod-name",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion: "example.com/v1",
+								Kind:       "Workload",
+								Name:       "my-workload",
+								Controller: aws.Bool(true),
+							},
+						},
+					},
+					Spec: corev1.PodSpec{},
+					Status: corev1.PodStatus{
+						PodIP: "192.168.1.42",
+					},
+				},
+			},
+			want: instanceAttributes{
+				"AWS_INSTANCE_IPV4":           "192.168.1.42",
+				"AWS_INSTANCE_PORT":           "8080",
+				"k8s.io/pod":                  "pod-name",
+				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Workload",
+				"k8s.io/owner-name":           "my-workload",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -358,6 +467,8 @@ func Test_defaultInstancesReconciler_buildInstanceInfoByID(t *testing.T) {
 						"AWS_INSTANCE_PORT":           "8080",
 						"k8s.io/pod":                  "pod-name-1",
 						"k8s.io/namespace":            "pod-ns",
+						"k8s.io/owner-kind":           "Pod",
+						"k8s.io/owner-name":           "pod-name-1",
 						"appmesh.k8s.aws/mesh":        "my-mesh",
 						"appmesh.k8s.aws/virtualNode": "my-vn",
 					},
@@ -384,6 +495,8 @@ func Test_defaultInstancesReconciler_buildInstanceInfoByID(t *testing.T) {
 						"AWS_INSTANCE_PORT":           "8080",
 						"k8s.io/pod":                  "pod-name-2",
 						"k8s.io/namespace":            "pod-ns",
+						"k8s.io/owner-kind":           "Pod",
+						"k8s.io/owner-name":           "pod-name-2",
 						"appmesh.k8s.aws/mesh":        "my-mesh",
 						"appmesh.k8s.aws/virtualNode": "my-vn",
 					},
@@ -518,6 +631,8 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes_IPv6(t *testing.T)
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -588,6 +703,8 @@ func Test_defaultInstancesReconciler_buildInstanceAttributes_IPv4(t *testing.T)
 				"AWS_INSTANCE_PORT":           "8080",
 				"k8s.io/pod":                  "pod-name",
 				"k8s.io/namespace":            "pod-ns",
+				"k8s.io/owner-kind":           "Pod",
+				"k8s.io/owner-name":           "pod-name",
 				"appmesh.k8s.aws/mesh":        "my-mesh",
 				"appmesh.k8s.aws/virtualNode": "my-vn",
 			},
@@ -0,0 +1,150 @@
+package cloudmap
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// namespace creation is asynchronous, reconciles are requeued at this interval until the namespace is found.
+	defaultNamespaceCreationRequeueDuration = 10 * time.Second
+)
+
+// createCloudMapNamespace creates the private DNS namespace for VirtualNode that manages its namespace.
+// it returns a RequeueAfterError until the namespace is found, since namespace creation completes asynchronously.
+func (m *defaultResourceManager) createCloudMapNamespace(ctx context.Context, vn *appmesh.VirtualNode, namespaceName string) error {
+	if len(m.config.NamespaceVPCID) == 0 {
+		return errors.Errorf("cloudMap namespace not found: %v, --%v must be specified to create it", namespaceName, flagCloudMapNamespaceVPCID)
+	}
+	createNamespaceInput := &servicediscovery.CreatePrivateDnsNamespaceInput{
+		CreatorRequestId: awssdk.String(string(vn.UID)),
+		Name:             awssdk.String(namespaceName),
+		Vpc:              awssdk.String(m.config.NamespaceVPCID),
+		Tags: []*servicediscovery.Tag{
+			{
+				Key:   awssdk.String(tagging.TagKeyManagedBy),
+				Value: awssdk.String(tagging.TagValueManagedBy),
+			},
+		},
+	}
+	resp, err := m.cloudMapSDK.CreatePrivateDnsNamespaceWithContext(ctx, createNamespaceInput)
+	if err != nil {
+		awsErr, ok := err.(awserr.Error)
+		// namespace creation is already in flight, or completed but the namespace isn't listed yet.
+		inProgress := ok && (awsErr.Code() == servicediscovery.ErrCodeDuplicateRequest || awsErr.Code() == servicediscovery.ErrCodeNamespaceAlreadyExists)
+		if !inProgress {
+			return errors.Wrapf(err, "failed to create cloudMap namespace")
+		}
+	} else {
+		m.log.Info("creating cloudMap namespace",
+			"namespaceName", namespaceName,
+			"operationID", awssdk.StringValue(resp.OperationId),
+		)
+	}
+	return runtime.NewRequeueAfterError(errors.Errorf("cloudMap namespace creation in progress: %v", namespaceName), defaultNamespaceCreationRequeueDuration)
+}
+
+// deleteCloudMapNamespaceIfUnused deletes the namespace of VirtualNode that manages its namespace with deleteWhenUnused,
+// once no other VirtualNode uses it. only namespaces created by the controller are deleted.
+func (m *defaultResourceManager) deleteCloudMapNamespaceIfUnused(ctx context.Context, vn *appmesh.VirtualNode, nsSummary *servicediscovery.NamespaceSummary) error {
+	manageNamespace := vn.Spec.ServiceDiscovery.AWSCloudMap.ManageNamespace
+	if manageNamespace == nil || !awssdk.BoolValue(manageNamespace.DeleteWhenUnused) {
+		return nil
+	}
+	namespaceName := awssdk.StringValue(nsSummary.Name)
+	used, err := m.isCloudMapNamespaceUsedByOtherVirtualNodes(ctx, vn, namespaceName)
+	if err != nil {
+		return err
+	}
+	if used {
+		return nil
+	}
+	owned, err := m.isCloudMapNamespaceManagedByController(ctx, nsSummary)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		m.log.V(1).Info("skip cloudMap namespace deletion since it's not created by controller",
+			"namespaceName", namespaceName,
+			"namespaceID", awssdk.StringValue(nsSummary.Id),
+		)
+		return nil
+	}
+
+	if _, err := m.cloudMapSDK.DeleteNamespaceWithContext(ctx, &servicediscovery.DeleteNamespaceInput{Id: nsSummary.Id}); err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			switch awsErr.Code() {
+			case servicediscovery.ErrCodeNamespaceNotFound:
+				m.namespaceSummaryCache.Remove(namespaceName)
+				return nil
+			case servicediscovery.ErrCodeResourceInUse:
+				// services not created for VirtualNodes of this cluster remain in the namespace.
+				m.log.Info("skip cloudMap namespace deletion since it still contains services",
+					"namespaceName", namespaceName,
+					"namespaceID", awssdk.StringValue(nsSummary.Id),
+				)
+				return nil
+			}
+		}
+		return errors.Wrapf(err, "failed to delete cloudMap namespace")
+	}
+	m.log.Info("deleted cloudMap namespace",
+		"namespaceName", namespaceName,
+		"namespaceID", awssdk.StringValue(nsSummary.Id),
+	)
+	m.namespaceSummaryCache.Remove(namespaceName)
+	return nil
+}
+
+// isCloudMapNamespaceUsedByOtherVirtualNodes checks whether VirtualNodes other than vn use the namespace.
+// VirtualNodes being deleted don't count, so the last of VirtualNodes deleted together deletes the namespace.
+func (m *defaultResourceManager) isCloudMapNamespaceUsedByOtherVirtualNodes(ctx context.Context, vn *appmesh.VirtualNode, namespaceName string) (bool, error) {
+	vnList := &appmesh.VirtualNodeList{}
+	if err := m.k8sClient.List(ctx, vnList); err != nil {
+		return false, errors.Wrapf(err, "failed to list virtualNodes")
+	}
+	for i := range vnList.Items {
+		otherVN := &vnList.Items[i]
+		// per-pod virtualNodes of vn are garbage collected along with vn.
+		if otherVN.UID == vn.UID || !otherVN.DeletionTimestamp.IsZero() || metav1.IsControlledBy(otherVN, vn) {
+			continue
+		}
+		if otherVN.Spec.ServiceDiscovery == nil || otherVN.Spec.ServiceDiscovery.AWSCloudMap == nil {
+			continue
+		}
+		if otherVN.Spec.ServiceDiscovery.AWSCloudMap.NamespaceName == namespaceName {
+			m.log.V(1).Info("skip cloudMap namespace deletion since it's used by other virtualNode",
+				"namespaceName", namespaceName,
+				"virtualNode", k8s.NamespacedName(otherVN),
+			)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isCloudMapNamespaceManagedByController checks whether the namespace is tagged as created by the controller.
+func (m *defaultResourceManager) isCloudMapNamespaceManagedByController(ctx context.Context, nsSummary *servicediscovery.NamespaceSummary) (bool, error) {
+	resp, err := m.cloudMapSDK.ListTagsForResourceWithContext(ctx, &servicediscovery.ListTagsForResourceInput{
+		ResourceARN: nsSummary.Arn,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list cloudMap namespace tags")
+	}
+	for _, tag := range resp.Tags {
+		if awssdk.StringValue(tag.Key) == tagging.TagKeyManagedBy && awssdk.StringValue(tag.Value) == tagging.TagValueManagedBy {
+			return true, nil
+		}
+	}
+	return false, nil
+}
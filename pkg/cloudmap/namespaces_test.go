@@ -0,0 +1,219 @@
+package cloudmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultResourceManager_createCloudMapNamespace(t *testing.T) {
+	vn := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "vn-1",
+			UID:  "vn-uid",
+		},
+	}
+	tests := []struct {
+		name        string
+		vpcID       string
+		createErr   error
+		wantCreate  bool
+		wantRequeue bool
+		wantErr     error
+	}{
+		{
+			name:    "vpc ID isn't specified",
+			vpcID:   "",
+			wantErr: errors.New("cloudMap namespace not found: my-ns, --cloudmap-namespace-vpc-id must be specified to create it"),
+		},
+		{
+			name:        "namespace creation started",
+			vpcID:       "vpc-1",
+			wantCreate:  true,
+			wantRequeue: true,
+			wantErr:     errors.New("cloudMap namespace creation in progress: my-ns"),
+		},
+		{
+			name:        "namespace creation already in progress",
+			vpcID:       "vpc-1",
+			createErr:   awserr.New(servicediscovery.ErrCodeDuplicateRequest, "duplicate", nil),
+			wantCreate:  true,
+			wantRequeue: true,
+			wantErr:     errors.New("cloudMap namespace creation in progress: my-ns"),
+		},
+		{
+			name:       "namespace creation failed",
+			vpcID:      "vpc-1",
+			createErr:  awserr.New(servicediscovery.ErrCodeInvalidInput, "invalid vpc", nil),
+			wantCreate: true,
+			wantErr:    errors.New("failed to create cloudMap namespace: InvalidInput: invalid vpc"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cloudMapSDK := services.NewMockCloudMap(ctrl)
+			if tt.wantCreate {
+				cloudMapSDK.EXPECT().CreatePrivateDnsNamespaceWithContext(ctx, &servicediscovery.CreatePrivateDnsNamespaceInput{
+					CreatorRequestId: awssdk.String("vn-uid"),
+					Name:             awssdk.String("my-ns"),
+					Vpc:              awssdk.String(tt.vpcID),
+					Tags: []*servicediscovery.Tag{
+						{Key: awssdk.String("appmesh.k8s.aws/managed-by"), Value: awssdk.String("aws-app-mesh-controller-for-k8s")},
+					},
+				}).Return(&servicediscovery.CreatePrivateDnsNamespaceOutput{OperationId: awssdk.String("op-1")}, tt.createErr)
+			}
+			m := &defaultResourceManager{
+				config:      Config{NamespaceVPCID: tt.vpcID},
+				cloudMapSDK: cloudMapSDK,
+				log:         logr.New(&log.NullLogSink{}),
+			}
+			err := m.createCloudMapNamespace(ctx, vn, "my-ns")
+			assert.EqualError(t, err, tt.wantErr.Error())
+			var requeueAfterErr *runtime.RequeueAfterError
+			assert.Equal(t, tt.wantRequeue, errors.As(err, &requeueAfterErr))
+		})
+	}
+}
+
+func Test_defaultResourceManager_deleteCloudMapNamespaceIfUnused(t *testing.T) {
+	nsSummary := &servicediscovery.NamespaceSummary{
+		Id:   awssdk.String("ns-id"),
+		Arn:  awssdk.String("ns-arn"),
+		Name: awssdk.String("my-ns"),
+	}
+	managedTags := []*servicediscovery.Tag{
+		{Key: awssdk.String("appmesh.k8s.aws/managed-by"), Value: awssdk.String("aws-app-mesh-controller-for-k8s")},
+	}
+	newVN := func(name string, manageNamespace *appmesh.AWSCloudMapNamespaceManagement) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "awesome-ns",
+				Name:      name,
+				UID:       types.UID(name + "-uid"),
+			},
+			Spec: appmesh.VirtualNodeSpec{
+				ServiceDiscovery: &appmesh.ServiceDiscovery{
+					AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+						NamespaceName:   "my-ns",
+						ServiceName:     name,
+						ManageNamespace: manageNamespace,
+					},
+				},
+			},
+		}
+	}
+	deleteWhenUnused := &appmesh.AWSCloudMapNamespaceManagement{DeleteWhenUnused: awssdk.Bool(true)}
+	tests := []struct {
+		name       string
+		vn         *appmesh.VirtualNode
+		otherVNs   []*appmesh.VirtualNode
+		tags       []*servicediscovery.Tag
+		wantDelete bool
+		deleteErr  error
+		wantErr    error
+	}{
+		{
+			name: "namespace isn't managed",
+			vn:   newVN("vn-1", nil),
+		},
+		{
+			name: "namespace is kept when unused",
+			vn:   newVN("vn-1", &appmesh.AWSCloudMapNamespaceManagement{}),
+		},
+		{
+			name:     "namespace is used by other virtualNode",
+			vn:       newVN("vn-1", deleteWhenUnused),
+			otherVNs: []*appmesh.VirtualNode{newVN("vn-2", nil)},
+		},
+		{
+			name: "namespace isn't created by controller",
+			vn:   newVN("vn-1", deleteWhenUnused),
+			tags: nil,
+		},
+		{
+			name:       "namespace is deleted",
+			vn:         newVN("vn-1", deleteWhenUnused),
+			tags:       managedTags,
+			wantDelete: true,
+		},
+		{
+			name:       "namespace still contains services",
+			vn:         newVN("vn-1", deleteWhenUnused),
+			tags:       managedTags,
+			wantDelete: true,
+			deleteErr:  awserr.New(servicediscovery.ErrCodeResourceInUse, "in use", nil),
+		},
+		{
+			name:       "namespace deletion failed",
+			vn:         newVN("vn-1", deleteWhenUnused),
+			tags:       managedTags,
+			wantDelete: true,
+			deleteErr:  awserr.New("ThrottlingException", "slow down", nil),
+			wantErr:    errors.New("failed to delete cloudMap namespace: ThrottlingException: slow down"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			k8sSchema := k8sruntime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range append([]*appmesh.VirtualNode{tt.vn}, tt.otherVNs...) {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+
+			cloudMapSDK := services.NewMockCloudMap(ctrl)
+			manageNamespace := tt.vn.Spec.ServiceDiscovery.AWSCloudMap.ManageNamespace
+			if manageNamespace != nil && awssdk.BoolValue(manageNamespace.DeleteWhenUnused) && len(tt.otherVNs) == 0 {
+				cloudMapSDK.EXPECT().ListTagsForResourceWithContext(ctx, &servicediscovery.ListTagsForResourceInput{ResourceARN: awssdk.String("ns-arn")}).
+					Return(&servicediscovery.ListTagsForResourceOutput{Tags: tt.tags}, nil)
+			}
+			if tt.wantDelete {
+				cloudMapSDK.EXPECT().DeleteNamespaceWithContext(ctx, &servicediscovery.DeleteNamespaceInput{Id: awssdk.String("ns-id")}).
+					Return(&servicediscovery.DeleteNamespaceOutput{}, tt.deleteErr)
+			}
+			m := &defaultResourceManager{
+				k8sClient:             k8sClient,
+				cloudMapSDK:           cloudMapSDK,
+				namespaceSummaryCache: cache.NewLRUExpireCache(1),
+				log:                   logr.New(&log.NullLogSink{}),
+			}
+			m.namespaceSummaryCache.Add("my-ns", nsSummary, time.Minute)
+
+			err := m.deleteCloudMapNamespaceIfUnused(ctx, tt.vn, nsSummary)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			_, cached := m.namespaceSummaryCache.Get("my-ns")
+			assert.Equal(t, !(tt.wantDelete && tt.deleteErr == nil), cached)
+		})
+	}
+}
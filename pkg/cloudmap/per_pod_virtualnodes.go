@@ -0,0 +1,193 @@
+package cloudmap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// PerPodVirtualNodesAnnotation enables per-pod VirtualNodes for the StatefulSet pods selected by a VirtualNode with awsCloudMap serviceDiscovery, when it's "true".
+	PerPodVirtualNodesAnnotation = "appmesh.k8s.aws/perPodVirtualNodes"
+	// PerPodVirtualNodePodAnnotation annotates per-pod VirtualNodes with the name of their pod.
+	PerPodVirtualNodePodAnnotation = "appmesh.k8s.aws/pod"
+	// LabelVirtualNode labels per-pod VirtualNodes with the name of the VirtualNode they're created for.
+	LabelVirtualNode = "appmesh.k8s.aws/virtualNode"
+
+	ownerKindStatefulSet = "StatefulSet"
+)
+
+// PerPodVirtualNodesManager manages per-pod VirtualNodes, which discover a single StatefulSet pod selected by a VirtualNode,
+// so that routes can target specific replicas, e.g. the primary of a database.
+type PerPodVirtualNodesManager interface {
+	// Reconcile ensures a per-pod VirtualNode exists for each StatefulSet pod of pods if vn enables per-pod VirtualNodes,
+	// and deletes the ones created for vn that are no longer desired.
+	// they're owned by vn, so they're garbage collected upon deletion of vn.
+	Reconcile(ctx context.Context, vn *appmesh.VirtualNode, pods []*corev1.Pod) error
+}
+
+// NewDefaultPerPodVirtualNodesManager constructs new PerPodVirtualNodesManager
+func NewDefaultPerPodVirtualNodesManager(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger) PerPodVirtualNodesManager {
+	return &defaultPerPodVirtualNodesManager{
+		k8sClient: k8sClient,
+		scheme:    scheme,
+		log:       log,
+	}
+}
+
+var _ PerPodVirtualNodesManager = &defaultPerPodVirtualNodesManager{}
+
+// defaultPerPodVirtualNodesManager implements PerPodVirtualNodesManager.
+// per-pod VirtualNodes have no podSelector, so pods are still injected and registered into CloudMap by the VirtualNode selecting them.
+// instead, their awsCloudMap serviceDiscovery only discovers the instance whose k8s.io/pod attribute is their pod.
+type defaultPerPodVirtualNodesManager struct {
+	k8sClient client.Client
+	scheme    *runtime.Scheme
+	log       logr.Logger
+}
+
+func (m *defaultPerPodVirtualNodesManager) Reconcile(ctx context.Context, vn *appmesh.VirtualNode, pods []*corev1.Pod) error {
+	desiredVNByName := make(map[string]*appmesh.VirtualNode)
+	if IsPerPodVirtualNodesEnabled(vn) {
+		for _, pod := range pods {
+			if kind, _ := k8s.ResolvePodOwner(pod); kind != ownerKindStatefulSet {
+				continue
+			}
+			desiredVN := buildPerPodVirtualNode(vn, pod)
+			desiredVNByName[desiredVN.Name] = desiredVN
+		}
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := m.k8sClient.List(ctx, vnList, client.InNamespace(vn.Namespace), client.MatchingLabels{LabelVirtualNode: vn.Name}); err != nil {
+		return errors.Wrap(err, "failed to list virtualNodes")
+	}
+	existingVNByName := make(map[string]*appmesh.VirtualNode)
+	for i := range vnList.Items {
+		perPodVN := &vnList.Items[i]
+		if !metav1.IsControlledBy(perPodVN, vn) {
+			continue
+		}
+		if _, ok := desiredVNByName[perPodVN.Name]; ok {
+			existingVNByName[perPodVN.Name] = perPodVN
+			continue
+		}
+		if IsPerPodVirtualNodesEnabled(vn) {
+			retained, err := m.isRetainedForStatefulSet(ctx, perPodVN)
+			if err != nil {
+				return err
+			}
+			if retained {
+				continue
+			}
+		}
+		if err := m.k8sClient.Delete(ctx, perPodVN); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete per-pod virtualNode %v", k8s.NamespacedName(perPodVN))
+		}
+		m.log.V(1).Info("deleted per-pod virtualNode",
+			"virtualNode", k8s.NamespacedName(vn),
+			"perPodVirtualNode", k8s.NamespacedName(perPodVN),
+		)
+	}
+
+	for name, desiredVN := range desiredVNByName {
+		existingVN, ok := existingVNByName[name]
+		if !ok {
+			if err := controllerutil.SetControllerReference(vn, desiredVN, m.scheme); err != nil {
+				return err
+			}
+			// creation fails if the name is taken by a VirtualNode not owned by vn, which is left untouched.
+			if err := m.k8sClient.Create(ctx, desiredVN); err != nil {
+				return errors.Wrapf(err, "failed to create per-pod virtualNode %v", k8s.NamespacedName(desiredVN))
+			}
+			m.log.V(1).Info("created per-pod virtualNode",
+				"virtualNode", k8s.NamespacedName(vn),
+				"perPodVirtualNode", k8s.NamespacedName(desiredVN),
+			)
+			continue
+		}
+
+		// awsName and meshRef are defaulted by the admission webhook upon creation, so they're preserved.
+		oldVN := existingVN.DeepCopy()
+		desiredVN.Spec.AWSName = existingVN.Spec.AWSName
+		desiredVN.Spec.MeshRef = existingVN.Spec.MeshRef
+		existingVN.Spec = desiredVN.Spec
+		if equality.Semantic.DeepEqual(oldVN.Spec, existingVN.Spec) {
+			continue
+		}
+		if err := m.k8sClient.Patch(ctx, existingVN, client.MergeFrom(oldVN)); err != nil {
+			return errors.Wrapf(err, "failed to update per-pod virtualNode %v", k8s.NamespacedName(existingVN))
+		}
+		m.log.V(1).Info("updated per-pod virtualNode",
+			"virtualNode", k8s.NamespacedName(vn),
+			"perPodVirtualNode", k8s.NamespacedName(existingVN),
+		)
+	}
+	return nil
+}
+
+// isRetainedForStatefulSet tests whether perPodVN is kept although its pod doesn't exist, since its StatefulSet is going to recreate the pod,
+// e.g. while the pod is restarted. StatefulSet pods are named <statefulSet>-<ordinal>, and exist for each ordinal below the replicas of the StatefulSet.
+func (m *defaultPerPodVirtualNodesManager) isRetainedForStatefulSet(ctx context.Context, perPodVN *appmesh.VirtualNode) (bool, error) {
+	podName := perPodVN.Annotations[PerPodVirtualNodePodAnnotation]
+	idx := strings.LastIndex(podName, "-")
+	if idx <= 0 {
+		return false, nil
+	}
+	ordinal, err := strconv.ParseInt(podName[idx+1:], 10, 32)
+	if err != nil {
+		return false, nil
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := m.k8sClient.Get(ctx, types.NamespacedName{Namespace: perPodVN.Namespace, Name: podName[:idx]}, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get statefulSet of per-pod virtualNode %v", k8s.NamespacedName(perPodVN))
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.DeletionTimestamp.IsZero() && int32(ordinal) < replicas, nil
+}
+
+// IsPerPodVirtualNodesEnabled tests whether vn enables per-pod VirtualNodes.
+func IsPerPodVirtualNodesEnabled(vn *appmesh.VirtualNode) bool {
+	return vn.Annotations[PerPodVirtualNodesAnnotation] == "true"
+}
+
+// buildPerPodVirtualNode builds the per-pod VirtualNode of vn for pod, named <vn>-<pod>.
+// it's a copy of vn, that discovers the CloudMap instance of pod instead of selecting pods.
+// DNS serviceDiscovery failover is dropped, since the DNS hostname of vn resolves to every pod.
+func buildPerPodVirtualNode(vn *appmesh.VirtualNode, pod *corev1.Pod) *appmesh.VirtualNode {
+	perPodVN := &appmesh.VirtualNode{}
+	perPodVN.Namespace = vn.Namespace
+	perPodVN.Name = fmt.Sprintf("%s-%s", vn.Name, pod.Name)
+	perPodVN.Labels = map[string]string{LabelVirtualNode: vn.Name}
+	perPodVN.Annotations = map[string]string{PerPodVirtualNodePodAnnotation: pod.Name}
+	perPodVN.Spec = *vn.Spec.DeepCopy()
+	perPodVN.Spec.AWSName = nil
+	perPodVN.Spec.PodSelector = nil
+	perPodVN.Spec.PodSelectorTerms = nil
+	perPodVN.Spec.ServiceDiscovery.DNS = nil
+	perPodVN.Spec.ServiceDiscovery.Failover = nil
+	cloudMap := perPodVN.Spec.ServiceDiscovery.AWSCloudMap
+	cloudMap.ManageNamespace = nil
+	cloudMap.Attributes = append(cloudMap.Attributes, appmesh.AWSCloudMapInstanceAttribute{Key: AttrK8sPod, Value: pod.Name})
+	return perPodVN
+}
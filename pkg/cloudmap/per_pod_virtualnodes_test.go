@@ -0,0 +1,199 @@
+package cloudmap
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultPerPodVirtualNodesManager_Reconcile(t *testing.T) {
+	ownerRef := metav1.OwnerReference{
+		APIVersion:         "appmesh.k8s.aws/v1beta2",
+		Kind:               "VirtualNode",
+		Name:               "db",
+		UID:                "vn-uid",
+		Controller:         aws.Bool(true),
+		BlockOwnerDeletion: aws.Bool(true),
+	}
+	statefulSetPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "my-ns",
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: aws.Bool(true)}},
+			},
+		}
+	}
+	deploymentPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "my-ns",
+			Name:            "proxy-6d4cf56db6-8xk2p",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "proxy-6d4cf56db6", Controller: aws.Bool(true)}},
+		},
+	}
+	perPodSpec := func(podName string) appmesh.VirtualNodeSpec {
+		return appmesh.VirtualNodeSpec{
+			Listeners: []appmesh.Listener{{PortMapping: appmesh.PortMapping{Port: 5432, Protocol: appmesh.PortProtocolTCP}}},
+			ServiceDiscovery: &appmesh.ServiceDiscovery{
+				AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+					NamespaceName: "my-cm-ns",
+					ServiceName:   "db",
+					Attributes: []appmesh.AWSCloudMapInstanceAttribute{
+						{Key: "tier", Value: "data"},
+						{Key: "k8s.io/pod", Value: podName},
+					},
+				},
+			},
+			MeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "mesh-uid"},
+		}
+	}
+	perPodVN := func(podName string, spec appmesh.VirtualNodeSpec) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "my-ns",
+				Name:            "db-" + podName,
+				Labels:          map[string]string{"appmesh.k8s.aws/virtualNode": "db"},
+				Annotations:     map[string]string{"appmesh.k8s.aws/pod": podName},
+				OwnerReferences: []metav1.OwnerReference{ownerRef},
+			},
+			Spec: spec,
+		}
+	}
+	staleSpec := perPodSpec("db-1")
+	staleSpec.AWSName = aws.String("db-db-1_my-ns")
+	staleSpec.Listeners[0].PortMapping.Port = 5433
+	wantUpdatedSpec := perPodSpec("db-1")
+	wantUpdatedSpec.AWSName = aws.String("db-db-1_my-ns")
+	unownedVN := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "db-db-3",
+			Labels:    map[string]string{"appmesh.k8s.aws/virtualNode": "db"},
+		},
+	}
+	dbStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: aws.Int32(3)},
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		pods         []*corev1.Pod
+		existingVNs  []*appmesh.VirtualNode
+		statefulSets []*appsv1.StatefulSet
+		wantVNs      map[string]appmesh.VirtualNodeSpec
+	}{
+		{
+			name:        "per-pod virtualNodes created for StatefulSet pods only",
+			annotations: map[string]string{"appmesh.k8s.aws/perPodVirtualNodes": "true"},
+			pods:        []*corev1.Pod{statefulSetPod("db-0"), statefulSetPod("db-1"), deploymentPod},
+			wantVNs: map[string]appmesh.VirtualNodeSpec{
+				"db-db-0": perPodSpec("db-0"),
+				"db-db-1": perPodSpec("db-1"),
+			},
+		},
+		{
+			name:        "stale per-pod virtualNode updated with its awsName preserved",
+			annotations: map[string]string{"appmesh.k8s.aws/perPodVirtualNodes": "true"},
+			pods:        []*corev1.Pod{statefulSetPod("db-1")},
+			existingVNs: []*appmesh.VirtualNode{perPodVN("db-1", staleSpec)},
+			wantVNs: map[string]appmesh.VirtualNodeSpec{
+				"db-db-1": wantUpdatedSpec,
+			},
+		},
+		{
+			name:         "per-pod virtualNodes of missing pods retained while the StatefulSet recreates them",
+			annotations:  map[string]string{"appmesh.k8s.aws/perPodVirtualNodes": "true"},
+			pods:         []*corev1.Pod{statefulSetPod("db-0")},
+			existingVNs:  []*appmesh.VirtualNode{perPodVN("db-0", perPodSpec("db-0")), perPodVN("db-2", perPodSpec("db-2")), perPodVN("db-3", perPodSpec("db-3"))},
+			statefulSets: []*appsv1.StatefulSet{dbStatefulSet},
+			wantVNs: map[string]appmesh.VirtualNodeSpec{
+				"db-db-0": perPodSpec("db-0"),
+				"db-db-2": perPodSpec("db-2"),
+			},
+		},
+		{
+			name:        "per-pod virtualNodes of missing pods deleted without StatefulSet",
+			annotations: map[string]string{"appmesh.k8s.aws/perPodVirtualNodes": "true"},
+			existingVNs: []*appmesh.VirtualNode{perPodVN("db-0", perPodSpec("db-0"))},
+		},
+		{
+			name:         "per-pod virtualNodes deleted once disabled",
+			pods:         []*corev1.Pod{statefulSetPod("db-0")},
+			existingVNs:  []*appmesh.VirtualNode{perPodVN("db-0", perPodSpec("db-0")), unownedVN},
+			statefulSets: []*appsv1.StatefulSet{dbStatefulSet},
+			wantVNs: map[string]appmesh.VirtualNodeSpec{
+				"db-db-3": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).Build()
+			for _, vn := range tt.existingVNs {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+			for _, sts := range tt.statefulSets {
+				assert.NoError(t, k8sClient.Create(ctx, sts.DeepCopy()))
+			}
+			vn := &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "db",
+					UID:         "vn-uid",
+					Annotations: tt.annotations,
+				},
+				Spec: appmesh.VirtualNodeSpec{
+					AWSName:     aws.String("db_my-ns"),
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					Listeners:   []appmesh.Listener{{PortMapping: appmesh.PortMapping{Port: 5432, Protocol: appmesh.PortProtocolTCP}}},
+					ServiceDiscovery: &appmesh.ServiceDiscovery{
+						AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+							NamespaceName:   "my-cm-ns",
+							ServiceName:     "db",
+							Attributes:      []appmesh.AWSCloudMapInstanceAttribute{{Key: "tier", Value: "data"}},
+							ManageNamespace: &appmesh.AWSCloudMapNamespaceManagement{},
+						},
+						DNS:      &appmesh.DNSServiceDiscovery{Hostname: "db.my-ns.svc.cluster.local"},
+						Failover: &appmesh.ServiceDiscoveryFailover{},
+					},
+					MeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "mesh-uid"},
+				},
+			}
+			m := NewDefaultPerPodVirtualNodesManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+			err := m.Reconcile(ctx, vn, tt.pods)
+			assert.NoError(t, err)
+
+			vnList := &appmesh.VirtualNodeList{}
+			assert.NoError(t, k8sClient.List(ctx, vnList))
+			gotVNs := make(map[string]appmesh.VirtualNodeSpec)
+			for _, gotVN := range vnList.Items {
+				gotVNs[gotVN.Name] = gotVN.Spec
+				if gotVN.Name != unownedVN.Name {
+					assert.Equal(t, []metav1.OwnerReference{ownerRef}, gotVN.OwnerReferences)
+					assert.Equal(t, "db", gotVN.Labels["appmesh.k8s.aws/virtualNode"])
+				}
+			}
+			if tt.wantVNs == nil {
+				tt.wantVNs = map[string]appmesh.VirtualNodeSpec{}
+			}
+			assert.Equal(t, tt.wantVNs, gotVNs)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package cloudmap
 import (
 	"context"
 	"fmt"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/servicediscovery"
@@ -50,6 +52,7 @@ func NewDefaultResourceManager(
 	referencesResolver references.Resolver,
 	virtualNodeEndpointResolver VirtualNodeEndpointResolver,
 	instancesReconciler InstancesReconciler,
+	perPodVirtualNodesManager PerPodVirtualNodesManager,
 	enableCustomHealthCheck bool,
 	log logr.Logger,
 	cfg Config,
@@ -62,6 +65,7 @@ func NewDefaultResourceManager(
 		referencesResolver:          referencesResolver,
 		virtualNodeEndpointResolver: virtualNodeEndpointResolver,
 		instancesReconciler:         instancesReconciler,
+		perPodVirtualNodesManager:   perPodVirtualNodesManager,
 		enableCustomHealthCheck:     enableCustomHealthCheck,
 		namespaceSummaryCache:       cache.NewLRUExpireCache(defaultNamespaceCacheMaxSize),
 		serviceSummaryCache:         cache.NewLRUExpireCache(defaultServiceCacheMaxSize),
@@ -78,6 +82,7 @@ type defaultResourceManager struct {
 	referencesResolver          references.Resolver
 	virtualNodeEndpointResolver VirtualNodeEndpointResolver
 	instancesReconciler         InstancesReconciler
+	perPodVirtualNodesManager   PerPodVirtualNodesManager
 	enableCustomHealthCheck     bool
 
 	namespaceSummaryCache *cache.LRUExpireCache
@@ -97,7 +102,10 @@ func (m *defaultResourceManager) Reconcile(ctx context.Context, vn *appmesh.Virt
 		return err
 	}
 	if nsSummary == nil {
-		return fmt.Errorf("cloudMap namespace not found: %v", cloudMapConfig.NamespaceName)
+		if cloudMapConfig.ManageNamespace != nil {
+			return m.updateCRDVirtualNodeForCloudMapFailure(ctx, vn, m.createCloudMapNamespace(ctx, vn, cloudMapConfig.NamespaceName))
+		}
+		return m.updateCRDVirtualNodeForCloudMapFailure(ctx, vn, fmt.Errorf("cloudMap namespace not found: %v", cloudMapConfig.NamespaceName))
 	}
 	svcSummary, err := m.findCloudMapService(ctx, nsSummary, cloudMapConfig.ServiceName)
 	if err != nil {
@@ -106,18 +114,22 @@ func (m *defaultResourceManager) Reconcile(ctx context.Context, vn *appmesh.Virt
 	if svcSummary == nil {
 		svcSummary, err = m.createCloudMapService(ctx, vn, nsSummary, cloudMapConfig.ServiceName, m.config.CloudMapServiceTTL)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualNodeForCloudMapFailure(ctx, vn, err)
 		}
 	}
 
+	if err := m.updateCRDVirtualNodeActiveServiceDiscovery(ctx, vn, true); err != nil {
+		return err
+	}
 	if err := m.updateCRDVirtualNode(ctx, vn, svcSummary); err != nil {
 		return err
 	}
 
 	var readyPods []*corev1.Pod
 	var notReadyPods []*corev1.Pod
-	if vn.Spec.PodSelector != nil {
-		readyPods, notReadyPods, _, err = m.virtualNodeEndpointResolver.Resolve(ctx, vn)
+	var ignoredPods []*corev1.Pod
+	if virtualnode.HasPodSelector(vn) {
+		readyPods, notReadyPods, ignoredPods, err = m.virtualNodeEndpointResolver.Resolve(ctx, vn)
 		if err != nil {
 			return err
 		}
@@ -129,6 +141,15 @@ func (m *defaultResourceManager) Reconcile(ctx context.Context, vn *appmesh.Virt
 		m.log.V(1).Info("VirtualNode does not have a pod selector, no endpoints")
 	}
 
+	// per-pod virtualNodes are kept for pods that aren't endpoints, e.g. while they're starting.
+	var selectedPods []*corev1.Pod
+	selectedPods = append(selectedPods, readyPods...)
+	selectedPods = append(selectedPods, notReadyPods...)
+	selectedPods = append(selectedPods, ignoredPods...)
+	if err := m.perPodVirtualNodesManager.Reconcile(ctx, vn, selectedPods); err != nil {
+		return err
+	}
+
 	nodeInfoByName := m.getClusterNodeInfo(ctx)
 	if err := m.instancesReconciler.Reconcile(ctx, ms, vn, *svcSummary, readyPods, notReadyPods, nodeInfoByName); err != nil {
 		return err
@@ -157,18 +178,16 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, vn *appmesh.Virtua
 	if err != nil {
 		return err
 	}
-	if svcSummary == nil {
-		return nil
-	}
-
-	if err := m.instancesReconciler.Reconcile(ctx, ms, vn, *svcSummary, nil, nil, nil); err != nil {
-		return err
+	if svcSummary != nil {
+		if err := m.instancesReconciler.Reconcile(ctx, ms, vn, *svcSummary, nil, nil, nil); err != nil {
+			return err
+		}
+		if err := m.deleteCloudMapService(ctx, vn, nsSummary, svcSummary); err != nil {
+			return err
+		}
 	}
 
-	if err := m.deleteCloudMapService(ctx, vn, nsSummary, svcSummary); err != nil {
-		return err
-	}
-	return nil
+	return m.deleteCloudMapNamespaceIfUnused(ctx, vn, nsSummary)
 }
 
 // findMeshDependency find the Mesh dependency for this virtualNode.
@@ -479,3 +498,36 @@ func (m *defaultResourceManager) updateCRDVirtualNode(ctx context.Context, vn *a
 	vn.Annotations[cloudMapServiceAnnotation] = *svcSummary.serviceARN
 	return m.k8sClient.Patch(ctx, vn, client.MergeFrom(oldVN))
 }
+
+// updateCRDVirtualNodeForCloudMapFailure fails over VirtualNode to DNS service discovery if it's configured to, and returns err.
+// the error is still returned so that AWS Cloud Map is retried until it's available again.
+func (m *defaultResourceManager) updateCRDVirtualNodeForCloudMapFailure(ctx context.Context, vn *appmesh.VirtualNode, err error) error {
+	if updateErr := m.updateCRDVirtualNodeActiveServiceDiscovery(ctx, vn, false); updateErr != nil {
+		m.log.Error(updateErr, "failed to update active service discovery", "virtualNode", k8s.NamespacedName(vn))
+	}
+	return err
+}
+
+// updateCRDVirtualNodeActiveServiceDiscovery records the service discovery of VirtualNode with failover that should be configured in AppMesh,
+// the virtualNode controller configures it once the status is updated.
+func (m *defaultResourceManager) updateCRDVirtualNodeActiveServiceDiscovery(ctx context.Context, vn *appmesh.VirtualNode, cloudMapAvailable bool) error {
+	failover := vn.Spec.ServiceDiscovery.Failover
+	if failover == nil {
+		return nil
+	}
+	activeServiceDiscovery := appmesh.ServiceDiscoveryTypeDNS
+	if failover.Primary == appmesh.ServiceDiscoveryTypeAWSCloudMap && cloudMapAvailable {
+		activeServiceDiscovery = appmesh.ServiceDiscoveryTypeAWSCloudMap
+	}
+	if vn.Status.ActiveServiceDiscovery != nil && *vn.Status.ActiveServiceDiscovery == activeServiceDiscovery {
+		return nil
+	}
+
+	oldVN := vn.DeepCopy()
+	vn.Status.ActiveServiceDiscovery = &activeServiceDiscovery
+	m.log.Info("updating VirtualNode active service discovery",
+		"virtualNode", k8s.NamespacedName(vn),
+		"serviceDiscovery", activeServiceDiscovery,
+	)
+	return m.k8sClient.Status().Patch(ctx, vn, client.MergeFrom(oldVN))
+}
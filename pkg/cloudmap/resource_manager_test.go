@@ -13,6 +13,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -264,6 +265,7 @@ func Test_defaultResourceManager_reconcile_arePodsReconciled(t *testing.T) {
 				cloudMapSDK:                 cloudMapSDK,
 				virtualNodeEndpointResolver: virtualNodeEndpointResolver,
 				instancesReconciler:         instancesReconciler,
+				perPodVirtualNodesManager:   NewDefaultPerPodVirtualNodesManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{})),
 			}
 
 			m.namespaceSummaryCache.Add(tt.args.vn.Spec.ServiceDiscovery.AWSCloudMap.NamespaceName, &cloudMapNamespace, 1*time.Minute)
@@ -293,3 +295,121 @@ func Test_defaultResourceManager_reconcile_arePodsReconciled(t *testing.T) {
 		})
 	}
 }
+
+func Test_defaultResourceManager_reconcile_serviceDiscoveryFailover(t *testing.T) {
+	activeCloudMap := appmesh.ServiceDiscoveryTypeAWSCloudMap
+	activeDNS := appmesh.ServiceDiscoveryTypeDNS
+	tests := []struct {
+		name                       string
+		failover                   *appmesh.ServiceDiscoveryFailover
+		activeServiceDiscovery     *appmesh.ServiceDiscoveryType
+		namespaceFound             bool
+		wantActiveServiceDiscovery *appmesh.ServiceDiscoveryType
+		wantErr                    error
+	}{
+		{
+			name:                       "without failover, namespace not found",
+			failover:                   nil,
+			namespaceFound:             false,
+			wantActiveServiceDiscovery: nil,
+			wantErr:                    errors.New("cloudMap namespace not found: cmnamespace"),
+		},
+		{
+			name:                       "AWSCloudMap primary, namespace not found",
+			failover:                   &appmesh.ServiceDiscoveryFailover{Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap},
+			namespaceFound:             false,
+			wantActiveServiceDiscovery: &activeDNS,
+			wantErr:                    errors.New("cloudMap namespace not found: cmnamespace"),
+		},
+		{
+			name:                       "AWSCloudMap primary, namespace found after failover",
+			failover:                   &appmesh.ServiceDiscoveryFailover{Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap},
+			activeServiceDiscovery:     &activeDNS,
+			namespaceFound:             true,
+			wantActiveServiceDiscovery: &activeCloudMap,
+		},
+		{
+			name:                       "DNS primary, namespace found",
+			failover:                   &appmesh.ServiceDiscoveryFailover{Primary: appmesh.ServiceDiscoveryTypeDNS},
+			namespaceFound:             true,
+			wantActiveServiceDiscovery: &activeDNS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			referencesResolver := mock_references.NewMockResolver(ctrl)
+			cloudMapSDK := services.NewMockCloudMap(ctrl)
+			instancesReconciler := NewMockInstancesReconciler(ctrl)
+
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+
+			vn := &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "vn-1",
+					Annotations: map[string]string{
+						"cloudMapServiceARN": "cloudMapARN",
+					},
+				},
+				Spec: appmesh.VirtualNodeSpec{
+					MeshRef: &appmesh.MeshReference{},
+					ServiceDiscovery: &appmesh.ServiceDiscovery{
+						AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+							NamespaceName: "cmnamespace",
+							ServiceName:   "cmservice",
+						},
+						DNS: &appmesh.DNSServiceDiscovery{
+							Hostname: "www.example.com",
+						},
+						Failover: tt.failover,
+					},
+				},
+				Status: appmesh.VirtualNodeStatus{
+					ActiveServiceDiscovery: tt.activeServiceDiscovery,
+				},
+			}
+			err := k8sClient.Create(ctx, vn.DeepCopy())
+			assert.NoError(t, err)
+
+			m := &defaultResourceManager{
+				k8sClient:                 k8sClient,
+				log:                       logr.New(&log.NullLogSink{}),
+				referencesResolver:        referencesResolver,
+				namespaceSummaryCache:     cache.NewLRUExpireCache(1),
+				serviceSummaryCache:       cache.NewLRUExpireCache(1),
+				cloudMapSDK:               cloudMapSDK,
+				instancesReconciler:       instancesReconciler,
+				perPodVirtualNodesManager: NewDefaultPerPodVirtualNodesManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{})),
+			}
+
+			mesh := &appmesh.Mesh{}
+			referencesResolver.EXPECT().ResolveMeshReference(ctx, *vn.Spec.MeshRef).Return(mesh, nil)
+			if tt.namespaceFound {
+				svcSummary := serviceSummary{}
+				m.namespaceSummaryCache.Add("cmnamespace", &servicediscovery.NamespaceSummary{Id: awssdk.String("namespace")}, 1*time.Minute)
+				m.serviceSummaryCache.Add("namespace/cmservice", &svcSummary, 1*time.Minute)
+				instancesReconciler.EXPECT().Reconcile(ctx, mesh, gomock.Any(), svcSummary, nil, nil, map[string]nodeAttributes{}).Return(nil)
+			} else {
+				cloudMapSDK.EXPECT().ListNamespacesPagesWithContext(ctx, gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			err = m.Reconcile(ctx, vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			gotVN := &appmesh.VirtualNode{}
+			err = k8sClient.Get(ctx, k8s.NamespacedName(vn), gotVN)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantActiveServiceDiscovery, gotVN.Status.ActiveServiceDiscovery)
+		})
+	}
+}
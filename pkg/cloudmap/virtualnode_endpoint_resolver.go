@@ -5,9 +5,10 @@ import (
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,21 +31,15 @@ type defaultVirtualNodeEndpointResolver struct {
 }
 
 func (e *defaultVirtualNodeEndpointResolver) Resolve(ctx context.Context, vNode *appmesh.VirtualNode) ([]*corev1.Pod, []*corev1.Pod, []*corev1.Pod, error) {
-	var podsList *corev1.PodList
-	var err error
-	var listOptions client.ListOptions
-	listOptions.LabelSelector, _ = metav1.LabelSelectorAsSelector(vNode.Spec.PodSelector)
-	listOptions.Namespace = vNode.Namespace
-
-	if podsList, err = e.podsRepository.ListPodsWithMatchingLabels(listOptions); err != nil {
+	pods, err := e.listSelectedPods(vNode)
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
 	var readyPods []*corev1.Pod
 	var notReadyPods []*corev1.Pod
 	var ignoredPods []*corev1.Pod
-	for i := range podsList.Items {
-		pod := &podsList.Items[i]
+	for _, pod := range pods {
 		if !pod.DeletionTimestamp.IsZero() {
 			ignoredPods = append(ignoredPods, pod)
 			continue
@@ -65,3 +60,31 @@ func (e *defaultVirtualNodeEndpointResolver) Resolve(ctx context.Context, vNode
 	}
 	return readyPods, notReadyPods, ignoredPods, nil
 }
+
+// listSelectedPods lists pods selected by podSelector or podSelectorTerms of vNode.
+func (e *defaultVirtualNodeEndpointResolver) listSelectedPods(vNode *appmesh.VirtualNode) ([]*corev1.Pod, error) {
+	podSelectors, err := virtualnode.PodSelectors(vNode)
+	if err != nil {
+		return nil, err
+	}
+	var pods []*corev1.Pod
+	listedPodNames := sets.NewString()
+	for _, podSelector := range podSelectors {
+		var listOptions client.ListOptions
+		listOptions.LabelSelector = podSelector
+		listOptions.Namespace = vNode.Namespace
+		podsList, err := e.podsRepository.ListPodsWithMatchingLabels(listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for i := range podsList.Items {
+			pod := &podsList.Items[i]
+			if listedPodNames.Has(pod.Name) {
+				continue
+			}
+			listedPodNames.Insert(pod.Name)
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
@@ -0,0 +1,120 @@
+package componentconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/logging"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagConfigFile = "config-file"
+
+	flagLogLevel = "log-level"
+)
+
+type Config struct {
+	// ConfigFile is the path of the ControllerConfiguration file, usually mounted from a ConfigMap.
+	// the controller is configured by flags only if it's empty.
+	ConfigFile string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.ConfigFile, flagConfigFile, "",
+		"The path of the ControllerConfiguration file, flags specified on the command line take precedence over it")
+}
+
+// Load loads the ControllerConfiguration from file.
+func Load(file string) (*ControllerConfiguration, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read controller configuration")
+	}
+	cc := &ControllerConfiguration{}
+	if err := yaml.UnmarshalStrict(content, cc); err != nil {
+		return nil, errors.Wrap(err, "failed to parse controller configuration")
+	}
+	if cc.APIVersion != APIVersion || cc.Kind != Kind {
+		return nil, errors.Errorf("unsupported controller configuration %s/%s, must be %s/%s", cc.APIVersion, cc.Kind, APIVersion, Kind)
+	}
+	return cc, nil
+}
+
+// FlagValues returns the values of flags set by cc, by flag name.
+func (cc *ControllerConfiguration) FlagValues() map[string]string {
+	values := make(map[string]string)
+	for name, value := range cc.Flags {
+		values[name] = value
+	}
+	setString := func(name string, value string) {
+		if len(value) != 0 {
+			values[name] = value
+		}
+	}
+	setString(flagLogLevel, cc.LogLevel)
+	setString(logging.FlagLoggerVerbosity, logging.FormatLoggerVerbosity(cc.LogVerbosity))
+	if cc.SyncPeriod != nil {
+		values["sync-period"] = cc.SyncPeriod.Duration.String()
+	}
+	if inj := cc.Injector; inj != nil {
+		setString("sidecar-image-repository", inj.SidecarImageRepository)
+		setString("sidecar-image-tag", inj.SidecarImageTag)
+		setString("sidecar-cpu-requests", inj.SidecarCPURequests)
+		setString("sidecar-memory-requests", inj.SidecarMemoryRequests)
+		setString("sidecar-cpu-limits", inj.SidecarCPULimits)
+		setString("sidecar-memory-limits", inj.SidecarMemoryLimits)
+		setString("sidecar-log-level", inj.SidecarLogLevel)
+	}
+	if rc := cc.Reconcile; rc != nil {
+		for controller, value := range rc.MaxConcurrentReconciles {
+			values[fmt.Sprintf("%s-max-concurrent-reconciles", controller)] = strconv.Itoa(value)
+		}
+		if rc.RateLimiterBaseDelay != nil {
+			values["reconcile-rate-limiter-base-delay"] = rc.RateLimiterBaseDelay.Duration.String()
+		}
+		if rc.RateLimiterMaxDelay != nil {
+			values["reconcile-rate-limiter-max-delay"] = rc.RateLimiterMaxDelay.Duration.String()
+		}
+		if rc.RateLimiterQPS != nil {
+			values["reconcile-rate-limiter-qps"] = strconv.FormatFloat(*rc.RateLimiterQPS, 'f', -1, 64)
+		}
+		if rc.RateLimiterBurst != nil {
+			values["reconcile-rate-limiter-burst"] = strconv.Itoa(*rc.RateLimiterBurst)
+		}
+	}
+	return values
+}
+
+// Apply sets flags of fs from values, except flags already specified on the command line.
+// returns the names of flags specified on the command line, which values never override.
+func Apply(fs *pflag.FlagSet, values map[string]string) (map[string]bool, error) {
+	overridden := make(map[string]bool)
+	for _, name := range sortedFlagNames(values) {
+		flag := fs.Lookup(name)
+		if flag == nil || name == flagConfigFile {
+			return nil, errors.Errorf("unknown flag in controller configuration: %s", name)
+		}
+		if flag.Changed {
+			overridden[name] = true
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return nil, errors.Wrapf(err, "invalid value of flag %s in controller configuration", name)
+		}
+	}
+	return overridden, nil
+}
+
+func sortedFlagNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
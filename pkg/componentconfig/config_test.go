@@ -0,0 +1,157 @@
+package componentconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, dir string, content string) string {
+	file := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantFlagValues map[string]string
+		wantErr        string
+	}{
+		{
+			name: "all settings",
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+logLevel: debug
+logVerbosity:
+  virtualrouter: 2
+  cloudmap: 1
+syncPeriod: 1h
+injector:
+  sidecarImageTag: v1.27.3.0-prod
+  sidecarCpuRequests: 20m
+reconcile:
+  maxConcurrentReconciles:
+    virtualnode: 10
+  rateLimiterMaxDelay: 5m
+  rateLimiterQPS: 20.5
+  rateLimiterBurst: 200
+flags:
+  enable-sds: "true"
+`,
+			wantFlagValues: map[string]string{
+				"log-level":                             "debug",
+				"log-verbosity":                         "cloudmap=1,virtualrouter=2",
+				"sync-period":                           "1h0m0s",
+				"sidecar-image-tag":                     "v1.27.3.0-prod",
+				"sidecar-cpu-requests":                  "20m",
+				"virtualnode-max-concurrent-reconciles": "10",
+				"reconcile-rate-limiter-max-delay":      "5m0s",
+				"reconcile-rate-limiter-qps":            "20.5",
+				"reconcile-rate-limiter-burst":          "200",
+				"enable-sds":                            "true",
+			},
+		},
+		{
+			name: "empty configuration",
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+`,
+			wantFlagValues: map[string]string{},
+		},
+		{
+			name: "unsupported apiVersion",
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1
+kind: ControllerConfiguration
+`,
+			wantErr: "unsupported controller configuration config.appmesh.k8s.aws/v1/ControllerConfiguration, must be config.appmesh.k8s.aws/v1alpha1/ControllerConfiguration",
+		},
+		{
+			name: "unknown field",
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+logLevl: debug
+`,
+			wantErr: `failed to parse controller configuration: error unmarshaling JSON: while decoding JSON: json: unknown field "logLevl"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := writeConfigFile(t, t.TempDir(), tt.content)
+			cc, err := Load(file)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFlagValues, cc.FlagValues())
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		values         map[string]string
+		wantLogLevel   string
+		wantSyncPeriod time.Duration
+		wantOverridden map[string]bool
+		wantErr        string
+	}{
+		{
+			name:           "values applied",
+			values:         map[string]string{"log-level": "debug", "sync-period": "1h"},
+			wantLogLevel:   "debug",
+			wantSyncPeriod: time.Hour,
+			wantOverridden: map[string]bool{},
+		},
+		{
+			name:           "command line takes precedence",
+			args:           []string{"--log-level=info"},
+			values:         map[string]string{"log-level": "debug", "sync-period": "1h"},
+			wantLogLevel:   "info",
+			wantSyncPeriod: time.Hour,
+			wantOverridden: map[string]bool{"log-level": true},
+		},
+		{
+			name:    "unknown flag",
+			values:  map[string]string{"log-levl": "debug"},
+			wantErr: "unknown flag in controller configuration: log-levl",
+		},
+		{
+			name:    "invalid value",
+			values:  map[string]string{"sync-period": "1 hour"},
+			wantErr: `invalid value of flag sync-period in controller configuration: invalid argument "1 hour" for "--sync-period" flag: time: unknown unit " hour" in duration "1 hour"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logLevel string
+			var syncPeriod time.Duration
+			fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+			fs.StringVar(&logLevel, "log-level", "info", "")
+			fs.DurationVar(&syncPeriod, "sync-period", 10*time.Hour, "")
+			assert.NoError(t, fs.Parse(tt.args))
+
+			overridden, err := Apply(fs, tt.values)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantOverridden, overridden)
+				assert.Equal(t, tt.wantLogLevel, logLevel)
+				assert.Equal(t, tt.wantSyncPeriod, syncPeriod)
+			}
+		})
+	}
+}
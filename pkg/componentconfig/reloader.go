@@ -0,0 +1,129 @@
+package componentconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// how often to check the ControllerConfiguration file for changes.
+	// ConfigMaps mounted as volumes are updated by kubelet within about a minute anyway.
+	reloadInterval = 10 * time.Second
+)
+
+// ReloadFunc applies the new value of a flag without restarting the controller.
+type ReloadFunc func(value string) error
+
+// Reloader reloads the ControllerConfiguration file upon changes.
+// it applies changed flags that can be safely changed at runtime, and logs the ones that require a restart.
+type Reloader interface {
+	manager.Runnable
+	manager.LeaderElectionRunnable
+}
+
+// NewReloader constructs new Reloader.
+// values are the currently applied flag values from file, and overridden are the flags specified on the command line, which are never reloaded.
+func NewReloader(file string, values map[string]string, overridden map[string]bool, reloadFuncs map[string]ReloadFunc, log logr.Logger) Reloader {
+	return &reloader{
+		file:        file,
+		values:      values,
+		overridden:  overridden,
+		reloadFuncs: reloadFuncs,
+		interval:    reloadInterval,
+		log:         log,
+	}
+}
+
+// NewLogLevelReloadFunc returns the ReloadFunc of the log-level flag, which sets the level with setLevel.
+func NewLogLevelReloadFunc(setLevel func(debug bool)) ReloadFunc {
+	return func(value string) error {
+		switch value {
+		case "info", "":
+			setLevel(false)
+		case "debug":
+			setLevel(true)
+		default:
+			return errors.Errorf("%s must be one of info, debug: %s", flagLogLevel, value)
+		}
+		return nil
+	}
+}
+
+var _ Reloader = &reloader{}
+
+type reloader struct {
+	file        string
+	values      map[string]string
+	overridden  map[string]bool
+	reloadFuncs map[string]ReloadFunc
+	interval    time.Duration
+	log         logr.Logger
+}
+
+func (r *reloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// NeedLeaderElection returns false, every replica reloads its own configuration.
+func (r *reloader) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *reloader) reload() {
+	cc, err := Load(r.file)
+	if err != nil {
+		r.log.Error(err, "failed to reload controller configuration, keeping current one", "file", r.file)
+		return
+	}
+	values := cc.FlagValues()
+	for _, name := range sortedFlagNames(changedFlags(r.values, values)) {
+		value := values[name]
+		switch {
+		case r.overridden[name]:
+			r.log.Info("setting changed but specified on the command line, ignoring it", "setting", name)
+		case r.reloadFuncs[name] != nil:
+			if err := r.reloadFuncs[name](value); err != nil {
+				r.log.Error(err, "failed to reload setting, keeping current value", "setting", name, "value", value)
+				if oldValue, ok := r.values[name]; ok {
+					values[name] = oldValue
+				} else {
+					delete(values, name)
+				}
+				continue
+			}
+			r.log.Info("reloaded setting", "setting", name, "value", value)
+		default:
+			r.log.Info("setting changed, restart the controller to apply it", "setting", name, "value", value)
+		}
+	}
+	r.values = values
+}
+
+// changedFlags returns the flags whose values differ between oldValues and newValues, including added and removed ones.
+func changedFlags(oldValues map[string]string, newValues map[string]string) map[string]string {
+	changed := make(map[string]string)
+	for name, value := range newValues {
+		if oldValue, ok := oldValues[name]; !ok || oldValue != value {
+			changed[name] = value
+		}
+	}
+	for name := range oldValues {
+		if _, ok := newValues[name]; !ok {
+			changed[name] = ""
+		}
+	}
+	return changed
+}
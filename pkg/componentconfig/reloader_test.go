@@ -0,0 +1,91 @@
+package componentconfig
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_reloader_reload(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]string
+		overridden map[string]bool
+		content    string
+		wantDebug  []bool
+		wantValues map[string]string
+	}{
+		{
+			name:   "log level reloaded",
+			values: map[string]string{"log-level": "info", "sync-period": "1h0m0s"},
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+logLevel: debug
+syncPeriod: 2h
+`,
+			wantDebug:  []bool{true},
+			wantValues: map[string]string{"log-level": "debug", "sync-period": "2h0m0s"},
+		},
+		{
+			name:   "removed log level reloaded as info",
+			values: map[string]string{"log-level": "debug"},
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+`,
+			wantDebug:  []bool{false},
+			wantValues: map[string]string{},
+		},
+		{
+			name:       "log level specified on command line isn't reloaded",
+			values:     map[string]string{"log-level": "info"},
+			overridden: map[string]bool{"log-level": true},
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+logLevel: debug
+`,
+			wantDebug:  nil,
+			wantValues: map[string]string{"log-level": "debug"},
+		},
+		{
+			name:   "invalid log level keeps current value",
+			values: map[string]string{"log-level": "info"},
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfiguration
+logLevel: trace
+`,
+			wantDebug:  nil,
+			wantValues: map[string]string{"log-level": "info"},
+		},
+		{
+			name:   "invalid configuration keeps current values",
+			values: map[string]string{"log-level": "info"},
+			content: `
+apiVersion: config.appmesh.k8s.aws/v1alpha1
+kind: ControllerConfig
+logLevel: debug
+`,
+			wantDebug:  nil,
+			wantValues: map[string]string{"log-level": "info"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDebug []bool
+			file := writeConfigFile(t, t.TempDir(), tt.content)
+			r := NewReloader(file, tt.values, tt.overridden, map[string]ReloadFunc{
+				"log-level": NewLogLevelReloadFunc(func(debug bool) {
+					gotDebug = append(gotDebug, debug)
+				}),
+			}, logr.New(&log.NullLogSink{})).(*reloader)
+			r.reload()
+			assert.Equal(t, tt.wantDebug, gotDebug)
+			assert.Equal(t, tt.wantValues, r.values)
+		})
+	}
+}
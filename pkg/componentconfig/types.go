@@ -0,0 +1,71 @@
+package componentconfig
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// APIVersion is the only supported apiVersion of ControllerConfiguration.
+	APIVersion = "config.appmesh.k8s.aws/v1alpha1"
+	// Kind is the kind of ControllerConfiguration.
+	Kind = "ControllerConfiguration"
+)
+
+// ControllerConfiguration configures the controller, as an alternative to command line flags.
+// Every setting corresponds to a flag, flags specified on the command line take precedence over it.
+type ControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// LogLevel is the log level of the controller: info or debug.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// LogVerbosity is the verbosity of loggers by name overriding LogLevel, e.g. virtualrouter: 2.
+	// +optional
+	LogVerbosity map[string]int `json:"logVerbosity,omitempty"`
+	// SyncPeriod is the minimum frequency at which watched resources are reconciled.
+	// +optional
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	// Injector configures the defaults of injected sidecars.
+	// +optional
+	Injector *InjectorConfiguration `json:"injector,omitempty"`
+	// Reconcile configures the concurrency and rate limiting of reconciles.
+	// +optional
+	Reconcile *ReconcileConfiguration `json:"reconcile,omitempty"`
+	// Flags sets any other flag of the controller by name, e.g. enable-sds: "true".
+	// +optional
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// InjectorConfiguration configures the defaults of injected sidecars.
+type InjectorConfiguration struct {
+	// +optional
+	SidecarImageRepository string `json:"sidecarImageRepository,omitempty"`
+	// +optional
+	SidecarImageTag string `json:"sidecarImageTag,omitempty"`
+	// +optional
+	SidecarCPURequests string `json:"sidecarCpuRequests,omitempty"`
+	// +optional
+	SidecarMemoryRequests string `json:"sidecarMemoryRequests,omitempty"`
+	// +optional
+	SidecarCPULimits string `json:"sidecarCpuLimits,omitempty"`
+	// +optional
+	SidecarMemoryLimits string `json:"sidecarMemoryLimits,omitempty"`
+	// SidecarLogLevel is the log level of injected Envoy sidecars.
+	// +optional
+	SidecarLogLevel string `json:"sidecarLogLevel,omitempty"`
+}
+
+// ReconcileConfiguration configures the concurrency and rate limiting of reconciles.
+type ReconcileConfiguration struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles by controller, e.g. virtualnode: 10.
+	// +optional
+	MaxConcurrentReconciles map[string]int `json:"maxConcurrentReconciles,omitempty"`
+	// +optional
+	RateLimiterBaseDelay *metav1.Duration `json:"rateLimiterBaseDelay,omitempty"`
+	// +optional
+	RateLimiterMaxDelay *metav1.Duration `json:"rateLimiterMaxDelay,omitempty"`
+	// +optional
+	RateLimiterQPS *float64 `json:"rateLimiterQPS,omitempty"`
+	// +optional
+	RateLimiterBurst *int `json:"rateLimiterBurst,omitempty"`
+}
@@ -0,0 +1,48 @@
+package conflicts
+
+import (
+	"fmt"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetConflictedCondition sets the Conflicted condition of a resource from its conflicts with other resources of kind, e.g. virtualNode,
+// selecting the same objects of objectKind, e.g. pods.
+// without conflicts, the condition is only set to False if the resource was conflicted before. returns whether conditions is changed.
+func SetConflictedCondition(conditions *[]metav1.Condition, generation int64, conflicts []Conflict, kind string, objectKind string) bool {
+	if len(conflicts) == 0 {
+		if meta.FindStatusCondition(*conditions, appmesh.ConditionConflicted) == nil {
+			return false
+		}
+		return k8s.SetStatusCondition(conditions, metav1.Condition{
+			Type:               appmesh.ConditionConflicted,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             appmesh.ReasonNoConflicts,
+		})
+	}
+	return k8s.SetStatusCondition(conditions, metav1.Condition{
+		Type:               appmesh.ConditionConflicted,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             appmesh.ReasonSelectorsOverlap,
+		Message:            ConflictsMessage(conflicts, kind, objectKind),
+	})
+}
+
+// ConflictsMessage describes conflicts with other resources of kind selecting the same objects of objectKind.
+func ConflictsMessage(conflicts []Conflict, kind string, objectKind string) string {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		objects := strings.Join(conflict.Objects, ", ")
+		if omitted := conflict.ObjectCount - len(conflict.Objects); omitted > 0 {
+			objects = fmt.Sprintf("%s and %d more", objects, omitted)
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s (%s)", kind, conflict.Name, objects))
+	}
+	return fmt.Sprintf("selects the same %s as %s", objectKind, strings.Join(descriptions, ", "))
+}
@@ -0,0 +1,38 @@
+package conflicts
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_SetConflictedCondition(t *testing.T) {
+	var conditions []metav1.Condition
+	assert.False(t, SetConflictedCondition(&conditions, 1, nil, "virtualNode", "pods"))
+	assert.Empty(t, conditions)
+
+	vnConflicts := []Conflict{{Name: "vn-b", Objects: []string{"pod-1"}, ObjectCount: 1}}
+	assert.True(t, SetConflictedCondition(&conditions, 1, vnConflicts, "virtualNode", "pods"))
+	assert.False(t, SetConflictedCondition(&conditions, 1, vnConflicts, "virtualNode", "pods"))
+	condition := meta.FindStatusCondition(conditions, appmesh.ConditionConflicted)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, appmesh.ReasonSelectorsOverlap, condition.Reason)
+	assert.Equal(t, "selects the same pods as virtualNode vn-b (pod-1)", condition.Message)
+
+	assert.True(t, SetConflictedCondition(&conditions, 2, nil, "virtualNode", "pods"))
+	condition = meta.FindStatusCondition(conditions, appmesh.ConditionConflicted)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, appmesh.ReasonNoConflicts, condition.Reason)
+	assert.Equal(t, int64(2), condition.ObservedGeneration)
+}
+
+func Test_ConflictsMessage(t *testing.T) {
+	got := ConflictsMessage([]Conflict{
+		{Name: "vn-b", Objects: []string{"pod-1", "pod-2"}, ObjectCount: 5},
+		{Name: "vn-c", Objects: []string{"pod-3"}, ObjectCount: 1},
+	}, "virtualNode", "pods")
+	assert.Equal(t, "selects the same pods as virtualNode vn-b (pod-1, pod-2 and 3 more), virtualNode vn-c (pod-3)", got)
+}
@@ -0,0 +1,168 @@
+package conflicts
+
+import (
+	"context"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxConflictObjects is the maximum number of objects selected by both resources reported per conflict.
+	maxConflictObjects = 5
+)
+
+// Conflict is another resource whose selector selects the same objects.
+type Conflict struct {
+	// Name is the name of the other resource.
+	Name string
+	// Objects are names of objects selected by both resources, truncated to maxConflictObjects.
+	Objects []string
+	// ObjectCount is the number of objects selected by both resources.
+	ObjectCount int
+}
+
+// Detector detects resources whose selectors select the same objects.
+type Detector interface {
+	// DetectVirtualNodeConflicts returns VirtualNodes of vn's namespace selecting the same pods as vn, ordered by name.
+	DetectVirtualNodeConflicts(ctx context.Context, vn *appmesh.VirtualNode) ([]Conflict, error)
+	// DetectMeshConflicts returns Meshes selecting the same namespaces as ms, ordered by name.
+	DetectMeshConflicts(ctx context.Context, ms *appmesh.Mesh) ([]Conflict, error)
+}
+
+// NewDefaultDetector constructs new Detector
+func NewDefaultDetector(k8sClient client.Client) Detector {
+	return &defaultDetector{k8sClient: k8sClient}
+}
+
+var _ Detector = &defaultDetector{}
+
+// defaultDetector detects conflicts by matching selectors against existing pods and namespaces,
+// so selectors that could overlap, but don't select the same objects, don't conflict.
+type defaultDetector struct {
+	k8sClient client.Client
+}
+
+func (d *defaultDetector) DetectVirtualNodeConflicts(ctx context.Context, vn *appmesh.VirtualNode) ([]Conflict, error) {
+	if !vn.DeletionTimestamp.IsZero() || !virtualnode.HasPodSelector(vn) {
+		return nil, nil
+	}
+	podList := &corev1.PodList{}
+	if err := d.k8sClient.List(ctx, podList, client.InNamespace(vn.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+	var selectedPods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		matches, err := virtualnode.MatchesPodLabels(vn, pod.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			selectedPods = append(selectedPods, pod)
+		}
+	}
+	if len(selectedPods) == 0 {
+		return nil, nil
+	}
+
+	vnList := &appmesh.VirtualNodeList{}
+	if err := d.k8sClient.List(ctx, vnList, client.InNamespace(vn.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualNodes")
+	}
+	var conflicts []Conflict
+	for i := range vnList.Items {
+		otherVN := &vnList.Items[i]
+		if otherVN.UID == vn.UID || !otherVN.DeletionTimestamp.IsZero() {
+			continue
+		}
+		var sharedPodNames []string
+		for _, pod := range selectedPods {
+			// invalid selectors of other VirtualNodes are reported by their own reconciles.
+			if matches, _ := virtualnode.MatchesPodLabels(otherVN, pod.Labels); matches {
+				sharedPodNames = append(sharedPodNames, pod.Name)
+			}
+		}
+		if len(sharedPodNames) != 0 {
+			conflicts = append(conflicts, newConflict(otherVN.Name, sharedPodNames))
+		}
+	}
+	sortConflicts(conflicts)
+	return conflicts, nil
+}
+
+func (d *defaultDetector) DetectMeshConflicts(ctx context.Context, ms *appmesh.Mesh) ([]Conflict, error) {
+	if !ms.DeletionTimestamp.IsZero() || ms.Spec.NamespaceSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ms.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid namespaceSelector")
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := d.k8sClient.List(ctx, nsList); err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	var selectedNamespaces []*corev1.Namespace
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		if selector.Matches(labels.Set(ns.Labels)) {
+			selectedNamespaces = append(selectedNamespaces, ns)
+		}
+	}
+	if len(selectedNamespaces) == 0 {
+		return nil, nil
+	}
+
+	meshList := &appmesh.MeshList{}
+	if err := d.k8sClient.List(ctx, meshList); err != nil {
+		return nil, errors.Wrap(err, "failed to list meshes")
+	}
+	var conflicts []Conflict
+	for i := range meshList.Items {
+		otherMS := &meshList.Items[i]
+		if otherMS.UID == ms.UID || !otherMS.DeletionTimestamp.IsZero() || otherMS.Spec.NamespaceSelector == nil {
+			continue
+		}
+		otherSelector, err := metav1.LabelSelectorAsSelector(otherMS.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		var sharedNamespaceNames []string
+		for _, ns := range selectedNamespaces {
+			if otherSelector.Matches(labels.Set(ns.Labels)) {
+				sharedNamespaceNames = append(sharedNamespaceNames, ns.Name)
+			}
+		}
+		if len(sharedNamespaceNames) != 0 {
+			conflicts = append(conflicts, newConflict(otherMS.Name, sharedNamespaceNames))
+		}
+	}
+	sortConflicts(conflicts)
+	return conflicts, nil
+}
+
+func newConflict(name string, objectNames []string) Conflict {
+	sort.Strings(objectNames)
+	conflict := Conflict{Name: name, ObjectCount: len(objectNames)}
+	if len(objectNames) > maxConflictObjects {
+		objectNames = objectNames[:maxConflictObjects]
+	}
+	conflict.Objects = objectNames
+	return conflict
+}
+
+func sortConflicts(conflicts []Conflict) {
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Name < conflicts[j].Name
+	})
+}
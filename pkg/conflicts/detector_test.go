@@ -0,0 +1,99 @@
+package conflicts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	for _, obj := range objs {
+		assert.NoError(t, k8sClient.Create(context.Background(), obj))
+	}
+	return k8sClient
+}
+
+func Test_defaultDetector_DetectVirtualNodeConflicts(t *testing.T) {
+	newVN := func(name string, podSelector map[string]string) *appmesh.VirtualNode {
+		vn := &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name, UID: types.UID(name)},
+		}
+		if podSelector != nil {
+			vn.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: podSelector}
+		}
+		return vn
+	}
+	newPod := func(namespace string, name string, podLabels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels}}
+	}
+
+	vn := newVN("vn-a", map[string]string{"app": "my-app"})
+	objs := []client.Object{
+		vn,
+		newVN("vn-c", map[string]string{"version": "v1"}),
+		newVN("vn-b", map[string]string{"app": "my-app", "version": "v2"}),
+		newVN("vn-d", map[string]string{"app": "other-app"}),
+		newVN("vn-e", nil),
+		newPod("ns-1", "pod-1", map[string]string{"app": "my-app", "version": "v1"}),
+		newPod("ns-1", "pod-2", map[string]string{"app": "my-app", "version": "v2"}),
+		newPod("ns-1", "pod-3", map[string]string{"app": "other-app", "version": "v1"}),
+		newPod("ns-2", "pod-4", map[string]string{"app": "my-app", "version": "v2"}),
+	}
+	for i := 5; i < 12; i++ {
+		objs = append(objs, newPod("ns-1", fmt.Sprintf("pod-%d", i), map[string]string{"app": "my-app", "version": "v1"}))
+	}
+	detector := NewDefaultDetector(newTestClient(t, objs...))
+
+	got, err := detector.DetectVirtualNodeConflicts(context.Background(), vn)
+	assert.NoError(t, err)
+	assert.Equal(t, []Conflict{
+		{Name: "vn-b", Objects: []string{"pod-2"}, ObjectCount: 1},
+		{Name: "vn-c", Objects: []string{"pod-1", "pod-10", "pod-11", "pod-5", "pod-6"}, ObjectCount: 8},
+	}, got)
+
+	got, err = detector.DetectVirtualNodeConflicts(context.Background(), newVN("vn-e", nil))
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_defaultDetector_DetectMeshConflicts(t *testing.T) {
+	newMesh := func(name string, namespaceSelector *metav1.LabelSelector) *appmesh.Mesh {
+		return &appmesh.Mesh{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+			Spec:       appmesh.MeshSpec{NamespaceSelector: namespaceSelector},
+		}
+	}
+	newNamespace := func(name string, nsLabels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nsLabels}}
+	}
+
+	ms := newMesh("mesh-a", &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "mesh-a"}})
+	detector := NewDefaultDetector(newTestClient(t,
+		ms,
+		newMesh("mesh-b", &metav1.LabelSelector{}),
+		newMesh("mesh-c", &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "mesh-c"}}),
+		newMesh("mesh-d", nil),
+		newNamespace("ns-1", map[string]string{"mesh": "mesh-a"}),
+		newNamespace("ns-2", map[string]string{"mesh": "mesh-c"}),
+	))
+
+	got, err := detector.DetectMeshConflicts(context.Background(), ms)
+	assert.NoError(t, err)
+	assert.Equal(t, []Conflict{
+		{Name: "mesh-b", Objects: []string{"ns-1"}, ObjectCount: 1},
+	}, got)
+}
@@ -0,0 +1,70 @@
+package conflicts
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewEnqueueRequestsForVirtualNodeEvents constructs new handler that enqueues all VirtualNodes of the VirtualNode's namespace,
+// since a change to one VirtualNode's selector can introduce or resolve conflicts of the others.
+func NewEnqueueRequestsForVirtualNodeEvents(k8sClient client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(vn client.Object) []reconcile.Request {
+		return listVirtualNodeRequests(k8sClient, log, vn.GetNamespace(), func(*appmesh.VirtualNode) bool {
+			return true
+		})
+	})
+}
+
+// NewEnqueueRequestsForPodEvents constructs new handler that enqueues VirtualNodes selecting the pod.
+func NewEnqueueRequestsForPodEvents(k8sClient client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(pod client.Object) []reconcile.Request {
+		return listVirtualNodeRequests(k8sClient, log, pod.GetNamespace(), func(vn *appmesh.VirtualNode) bool {
+			matches, _ := virtualnode.MatchesPodLabels(vn, pod.GetLabels())
+			return matches
+		})
+	})
+}
+
+// NewEnqueueRequestsForMeshSelectionEvents constructs new handler that enqueues all Meshes,
+// used for events of Meshes and Namespaces that can introduce or resolve conflicts of any Mesh.
+func NewEnqueueRequestsForMeshSelectionEvents(k8sClient client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		meshList := &appmesh.MeshList{}
+		if err := k8sClient.List(context.Background(), meshList); err != nil {
+			log.Error(err, "failed to enqueue meshes")
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range meshList.Items {
+			if meshList.Items[i].Spec.NamespaceSelector == nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.NamespacedName(&meshList.Items[i])})
+		}
+		return requests
+	})
+}
+
+func listVirtualNodeRequests(k8sClient client.Client, log logr.Logger, namespace string, matches func(vn *appmesh.VirtualNode) bool) []reconcile.Request {
+	vnList := &appmesh.VirtualNodeList{}
+	if err := k8sClient.List(context.Background(), vnList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "failed to enqueue virtualNodes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		if !virtualnode.HasPodSelector(vn) || !matches(vn) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: k8s.NamespacedName(vn)})
+	}
+	return requests
+}
@@ -0,0 +1,134 @@
+package conversions
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+// RoundTripCase describes a conversion from CRD type to SDK type to be verified for losslessness.
+type RoundTripCase struct {
+	// NewCRDObj constructs the CRD object to fuzz, e.g. &appmesh.Route{}.
+	NewCRDObj func() interface{}
+	// NewSDKObj constructs the SDK object to convert into, e.g. &appmeshsdk.RouteSpec{}.
+	NewSDKObj func() interface{}
+	// Converter converts CRD object into SDK object, with reference conversions registered.
+	Converter *conversion.Converter
+	// FuzzFuncs are custom fuzz functions, for fields that must hold well-formed values to be convertible, e.g. ARNs.
+	FuzzFuncs []interface{}
+	// IgnoredPaths are JSON paths of CRD fields that are not expected to survive the round trip, with list indices omitted.
+	// e.g. "httpRoute.action.weightedTargets.virtualNodeRef" is resolved into an AppMesh name during conversion.
+	// A path ignores all fields beneath it.
+	IgnoredPaths []string
+}
+
+// VerifyRoundTrip fuzzes iterations CRD objects with every field populated, converts each into SDK object and back,
+// and returns the JSON paths of CRD fields whose values were lost, sorted and with list indices omitted.
+// The SDK object is converted back by decoding its JSON form into the CRD type, which relies on CRD and SDK types sharing JSON field names,
+// so a field the conversion silently drops surfaces as a lost path.
+func VerifyRoundTrip(c RoundTripCase, iterations int, seed int64) ([]string, error) {
+	fuzzer := fuzz.NewWithSeed(seed).NilChance(0).NumElements(1, 2).Funcs(c.FuzzFuncs...)
+	lostPathSet := make(map[string]struct{})
+	for i := 0; i < iterations; i++ {
+		crdObj := c.NewCRDObj()
+		fuzzer.Fuzz(crdObj)
+		sdkObj := c.NewSDKObj()
+		if err := c.Converter.Convert(crdObj, sdkObj, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert fuzzed %T", crdObj)
+		}
+		roundTrippedCRDObj := c.NewCRDObj()
+		if err := convertViaJSON(sdkObj, roundTrippedCRDObj); err != nil {
+			return nil, err
+		}
+		want, err := flattenJSON(crdObj)
+		if err != nil {
+			return nil, err
+		}
+		got, err := flattenJSON(roundTrippedCRDObj)
+		if err != nil {
+			return nil, err
+		}
+		for path, value := range want {
+			normalizedPath := listIndexPattern.ReplaceAllString(path, "")
+			if isIgnoredPath(normalizedPath, c.IgnoredPaths) {
+				continue
+			}
+			if gotValue, ok := got[path]; !ok || !reflect.DeepEqual(value, gotValue) {
+				lostPathSet[normalizedPath] = struct{}{}
+			}
+		}
+	}
+	lostPaths := make([]string, 0, len(lostPathSet))
+	for path := range lostPathSet {
+		lostPaths = append(lostPaths, path)
+	}
+	sort.Strings(lostPaths)
+	return lostPaths, nil
+}
+
+var listIndexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// convertViaJSON decodes the JSON form of src into dst, skipping fields whose JSON values don't fit dst's types,
+// which are reported as lost by the comparison instead.
+func convertViaJSON(src interface{}, dst interface{}) error {
+	payload, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, dst); err != nil {
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenJSON returns the leaf values of obj's JSON form, keyed by JSON path, e.g. "httpRoute.match.headers[0].name".
+func flattenJSON(obj interface{}) (map[string]interface{}, error) {
+	payload, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return nil, err
+	}
+	leaves := make(map[string]interface{})
+	flattenJSONValue("", value, leaves)
+	return leaves, nil
+}
+
+func flattenJSONValue(path string, value interface{}, leaves map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenJSONValue(childPath, child, leaves)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenJSONValue(fmt.Sprintf("%s[%d]", path, i), child, leaves)
+		}
+	default:
+		leaves[path] = v
+	}
+}
+
+func isIgnoredPath(path string, ignoredPaths []string) bool {
+	for _, ignoredPath := range ignoredPaths {
+		if path == ignoredPath || strings.HasPrefix(path, ignoredPath+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package conversions
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	fuzz "github.com/google/gofuzz"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/conversion"
+)
+
+const roundTripIterations = 50
+
+func Test_VerifyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		convertFunc  func(crdObj *appmesh.Duration, sdkObj *appmeshsdk.Duration) error
+		ignoredPaths []string
+		want         []string
+	}{
+		{
+			name: "lossless conversion",
+			convertFunc: func(crdObj *appmesh.Duration, sdkObj *appmeshsdk.Duration) error {
+				return Convert_CRD_Duration_To_SDK_Duration(crdObj, sdkObj, nil)
+			},
+			want: []string{},
+		},
+		{
+			name: "conversion drops unit",
+			convertFunc: func(crdObj *appmesh.Duration, sdkObj *appmeshsdk.Duration) error {
+				sdkObj.Value = aws.Int64(crdObj.Value)
+				return nil
+			},
+			want: []string{"unit"},
+		},
+		{
+			name: "conversion drops ignored unit",
+			convertFunc: func(crdObj *appmesh.Duration, sdkObj *appmeshsdk.Duration) error {
+				sdkObj.Value = aws.Int64(crdObj.Value)
+				return nil
+			},
+			ignoredPaths: []string{"unit"},
+			want:         []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := conversion.NewConverter(conversion.DefaultNameFunc)
+			converter.RegisterUntypedConversionFunc((*appmesh.Duration)(nil), (*appmeshsdk.Duration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+				return tt.convertFunc(a.(*appmesh.Duration), b.(*appmeshsdk.Duration))
+			})
+			got, err := VerifyRoundTrip(RoundTripCase{
+				NewCRDObj:    func() interface{} { return &appmesh.Duration{} },
+				NewSDKObj:    func() interface{} { return &appmeshsdk.Duration{} },
+				Converter:    converter,
+				IgnoredPaths: tt.ignoredPaths,
+			}, roundTripIterations, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_RoundTrip_Route(t *testing.T) {
+	converter := conversion.NewConverter(conversion.DefaultNameFunc)
+	converter.RegisterUntypedConversionFunc((*appmesh.Route)(nil), (*appmeshsdk.RouteSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_CRD_Route_To_SDK_RouteSpec(a.(*appmesh.Route), b.(*appmeshsdk.RouteSpec), scope)
+	})
+	converter.RegisterUntypedConversionFunc((*appmesh.VirtualNodeReference)(nil), (*string)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		*b.(*string) = "vn-name"
+		return nil
+	})
+	got, err := VerifyRoundTrip(RoundTripCase{
+		NewCRDObj: func() interface{} { return &appmesh.Route{} },
+		NewSDKObj: func() interface{} { return &appmeshsdk.RouteSpec{} },
+		Converter: converter,
+		FuzzFuncs: []interface{}{
+			// virtualNodeSelector expands into virtualNodeRef targets before conversion.
+			func(crdObj *appmesh.WeightedTarget, c fuzz.Continue) {
+				c.FuzzNoCustom(crdObj)
+				crdObj.VirtualNodeARN = aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/mesh-name/virtualNode/vn-name")
+				crdObj.VirtualNodeSelector = nil
+			},
+			// profile expands into retry events instead of listed ones, which are verified instead.
+			func(crdObj *appmesh.GRPCRetryPolicy, c fuzz.Continue) {
+				c.FuzzNoCustom(crdObj)
+				crdObj.Profile = nil
+			},
+			// portRange and ports expand into a route per port before conversion.
+			func(crdObj *appmesh.TCPRouteMatch, c fuzz.Continue) {
+				c.FuzzNoCustom(crdObj)
+				crdObj.PortRange = nil
+				crdObj.Ports = nil
+			},
+		},
+		IgnoredPaths: []string{
+			// name is the AppMesh route name rather than part of its spec.
+			"name",
+			// weightedTargets reference virtualNodes, which are resolved into AppMesh names.
+			"grpcRoute.action.weightedTargets.virtualNodeARN",
+			"grpcRoute.action.weightedTargets.virtualNodeRef",
+			"httpRoute.action.weightedTargets.virtualNodeARN",
+			"httpRoute.action.weightedTargets.virtualNodeRef",
+			"http2Route.action.weightedTargets.virtualNodeARN",
+			"http2Route.action.weightedTargets.virtualNodeRef",
+			"tcpRoute.action.weightedTargets.virtualNodeARN",
+			"tcpRoute.action.weightedTargets.virtualNodeRef",
+		},
+	}, roundTripIterations, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_RoundTrip_VirtualNodeSpec(t *testing.T) {
+	converter := conversion.NewConverter(conversion.DefaultNameFunc)
+	converter.RegisterUntypedConversionFunc((*appmesh.VirtualNodeSpec)(nil), (*appmeshsdk.VirtualNodeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_CRD_VirtualNodeSpec_To_SDK_VirtualNodeSpec(a.(*appmesh.VirtualNodeSpec), b.(*appmeshsdk.VirtualNodeSpec), scope)
+	})
+	converter.RegisterUntypedConversionFunc((*appmesh.VirtualServiceReference)(nil), (*string)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		*b.(*string) = "vs-name"
+		return nil
+	})
+	got, err := VerifyRoundTrip(RoundTripCase{
+		NewCRDObj: func() interface{} { return &appmesh.VirtualNodeSpec{} },
+		NewSDKObj: func() interface{} { return &appmeshsdk.VirtualNodeSpec{} },
+		Converter: converter,
+		FuzzFuncs: []interface{}{
+			func(crdObj *appmesh.VirtualServiceBackend, c fuzz.Continue) {
+				c.FuzzNoCustom(crdObj)
+				crdObj.VirtualServiceARN = aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/mesh-name/virtualService/vs-name")
+			},
+		},
+		IgnoredPaths: []string{
+			// awsName and meshRef identify the AppMesh virtualNode rather than being part of its spec.
+			"awsName",
+			"meshRef",
+			// podSelector, podSelectorTerms and backendGroups are only consumed by the controller.
+			"podSelector",
+			"podSelectorTerms",
+			"backendGroups",
+			// healthCheckFromProbe is resolved into healthCheck by the controller.
+			"listeners.healthCheckFromProbe",
+			// failover selects the serviceDiscovery configured in AppMesh by the controller.
+			"serviceDiscovery.failover",
+			// manageNamespace is only consumed by the controller.
+			"serviceDiscovery.awsCloudMap.manageNamespace",
+			// backends reference virtualServices, which are resolved into AppMesh names.
+			"backends.virtualService.virtualServiceARN",
+			"backends.virtualService.virtualServiceRef",
+		},
+	}, roundTripIterations, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_RoundTrip_VirtualGatewaySpec(t *testing.T) {
+	converter := conversion.NewConverter(conversion.DefaultNameFunc)
+	converter.RegisterUntypedConversionFunc((*appmesh.VirtualGatewaySpec)(nil), (*appmeshsdk.VirtualGatewaySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_CRD_VirtualGatewaySpec_To_SDK_VirtualGatewaySpec(a.(*appmesh.VirtualGatewaySpec), b.(*appmeshsdk.VirtualGatewaySpec), scope)
+	})
+	got, err := VerifyRoundTrip(RoundTripCase{
+		NewCRDObj: func() interface{} { return &appmesh.VirtualGatewaySpec{} },
+		NewSDKObj: func() interface{} { return &appmeshsdk.VirtualGatewaySpec{} },
+		Converter: converter,
+		IgnoredPaths: []string{
+			// awsName and meshRef identify the AppMesh virtualGateway rather than being part of its spec.
+			"awsName",
+			"meshRef",
+			// selectors and provisionLoadBalancer are only consumed by the controller.
+			"namespaceSelector",
+			"podSelector",
+			"gatewayRouteSelector",
+			"provisionLoadBalancer",
+			// AppMesh doesn't support ACM trust for virtualGateway listener validation.
+			"listeners.tls.validation.trust.acm",
+		},
+	}, roundTripIterations, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
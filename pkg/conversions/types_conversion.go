@@ -58,3 +58,10 @@ func Convert_CRD_MatchRange_To_SDK_MatchRange(crdObj *appmesh.MatchRange, sdkObj
 	sdkObj.End = aws.Int64(crdObj.End)
 	return nil
 }
+
+func Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkObj *appmeshsdk.ResourceMetadata, crdObj *appmesh.AWSResourceMetadata) error {
+	crdObj.UID = sdkObj.Uid
+	crdObj.MeshOwner = sdkObj.MeshOwner
+	crdObj.ResourceOwner = sdkObj.ResourceOwner
+	return nil
+}
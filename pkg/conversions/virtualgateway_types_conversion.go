@@ -211,6 +211,7 @@ func Convert_CRD_VirtualGatewayListenerTLSValidationContextTrust_To_SDK_VirtualG
 
 func Convert_CRD_VirtualGatewayListenerTLSValidationContextSubjectAlternativeNames_To_SDK_VirtualGatewayListenerTLSValidationContextSubjectAlternativeNames(crdObj *appmesh.SubjectAlternativeNames, sdkObj *appmeshsdk.SubjectAlternativeNames, scope conversion.Scope) error {
 	if crdObj.Match != nil {
+		sdkObj.Match = &appmeshsdk.SubjectAlternativeNameMatchers{}
 		sdkObj.Match.Exact = crdObj.Match.Exact
 	}
 	return nil
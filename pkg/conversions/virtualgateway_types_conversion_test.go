@@ -1018,6 +1018,37 @@ func TestConvert_CRD_VirtualGatewayListenerTLSValidationContext_To_SDK_VirtualGa
 				},
 			},
 		},
+		{
+			name: "sds based validation with subjectAlternativeNames",
+			args: args{
+				crdObj: &appmesh.VirtualGatewayListenerTLSValidationContext{
+					Trust: appmesh.VirtualGatewayListenerTLSValidationContextTrust{
+						SDS: &appmesh.VirtualGatewayTLSValidationContextSDSTrust{
+							SecretName: &validationContext,
+						},
+					},
+					SubjectAlternativeNames: &appmesh.SubjectAlternativeNames{
+						Match: &appmesh.SubjectAlternativeNameMatchers{
+							Exact: []*string{aws.String("client.ns-1.svc.cluster.local")},
+						},
+					},
+				},
+				sdkObj: &appmeshsdk.VirtualGatewayListenerTlsValidationContext{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.VirtualGatewayListenerTlsValidationContext{
+				Trust: &appmeshsdk.VirtualGatewayListenerTlsValidationContextTrust{
+					Sds: &appmeshsdk.VirtualGatewayTlsValidationContextSdsTrust{
+						SecretName: &validationContext,
+					},
+				},
+				SubjectAlternativeNames: &appmeshsdk.SubjectAlternativeNames{
+					Match: &appmeshsdk.SubjectAlternativeNameMatchers{
+						Exact: []*string{aws.String("client.ns-1.svc.cluster.local")},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
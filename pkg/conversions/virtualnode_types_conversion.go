@@ -514,7 +514,7 @@ func Convert_CRD_Listener_To_SDK_Listener(crdObj *appmesh.Listener, sdkObj *appm
 			return err
 		}
 	} else {
-		crdObj.ConnectionPool = nil
+		sdkObj.ConnectionPool = nil
 	}
 
 	if crdObj.TLS != nil {
@@ -4,6 +4,7 @@ import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-sdk-go/aws"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/conversion"
 )
 
@@ -358,31 +359,53 @@ func Convert_CRD_GRPCRouteAction_To_SDK_GrpcRouteAction(crdObj *appmesh.GRPCRout
 	return nil
 }
 
+// grpcRetryProfileEvents are the retry events each gRPC retry profile expands into.
+var grpcRetryProfileEvents = map[appmesh.GRPCRetryProfile]appmesh.GRPCRetryPolicy{
+	appmesh.GRPCRetryProfileIdempotentOnly: {
+		GRPCRetryEvents: []appmesh.GRPCRetryPolicyEvent{"unavailable"},
+		TCPRetryEvents:  []appmesh.TCPRetryPolicyEvent{"connection-error"},
+	},
+	appmesh.GRPCRetryProfileAll: {
+		GRPCRetryEvents: []appmesh.GRPCRetryPolicyEvent{"cancelled", "deadline-exceeded", "internal", "resource-exhausted", "unavailable"},
+		HTTPRetryEvents: []appmesh.HTTPRetryPolicyEvent{"server-error", "gateway-error", "client-error", "stream-error"},
+		TCPRetryEvents:  []appmesh.TCPRetryPolicyEvent{"connection-error"},
+	},
+}
+
 func Convert_CRD_GRPCRetryPolicy_To_SDK_GrpcRetryPolicy(crdObj *appmesh.GRPCRetryPolicy,
 	sdkObj *appmeshsdk.GrpcRetryPolicy, scope conversion.Scope) error {
 
+	grpcRetryEvents, httpRetryEvents, tcpRetryEvents := crdObj.GRPCRetryEvents, crdObj.HTTPRetryEvents, crdObj.TCPRetryEvents
+	if crdObj.Profile != nil {
+		profileEvents, ok := grpcRetryProfileEvents[*crdObj.Profile]
+		if !ok {
+			return errors.Errorf("unknown grpc retry profile: %s", *crdObj.Profile)
+		}
+		grpcRetryEvents, httpRetryEvents, tcpRetryEvents = profileEvents.GRPCRetryEvents, profileEvents.HTTPRetryEvents, profileEvents.TCPRetryEvents
+	}
+
 	var sdkGrpcRetryEvents []*string
-	if len(crdObj.GRPCRetryEvents) != 0 {
-		sdkGrpcRetryEvents = make([]*string, 0, len(crdObj.GRPCRetryEvents))
-		for _, crdGRPCRetryEvent := range crdObj.GRPCRetryEvents {
+	if len(grpcRetryEvents) != 0 {
+		sdkGrpcRetryEvents = make([]*string, 0, len(grpcRetryEvents))
+		for _, crdGRPCRetryEvent := range grpcRetryEvents {
 			sdkGrpcRetryEvents = append(sdkGrpcRetryEvents, aws.String((string)(crdGRPCRetryEvent)))
 		}
 	}
 	sdkObj.GrpcRetryEvents = sdkGrpcRetryEvents
 
 	var sdkHttpRetryEvents []*string
-	if len(crdObj.HTTPRetryEvents) != 0 {
-		sdkHttpRetryEvents = make([]*string, 0, len(crdObj.HTTPRetryEvents))
-		for _, crdHTTPRetryEvent := range crdObj.HTTPRetryEvents {
+	if len(httpRetryEvents) != 0 {
+		sdkHttpRetryEvents = make([]*string, 0, len(httpRetryEvents))
+		for _, crdHTTPRetryEvent := range httpRetryEvents {
 			sdkHttpRetryEvents = append(sdkHttpRetryEvents, aws.String((string)(crdHTTPRetryEvent)))
 		}
 	}
 	sdkObj.HttpRetryEvents = sdkHttpRetryEvents
 
 	var sdkTcpRetryEvents []*string
-	if len(crdObj.TCPRetryEvents) != 0 {
-		sdkTcpRetryEvents = make([]*string, 0, len(crdObj.TCPRetryEvents))
-		for _, crdTCPRetryEvent := range crdObj.TCPRetryEvents {
+	if len(tcpRetryEvents) != 0 {
+		sdkTcpRetryEvents = make([]*string, 0, len(tcpRetryEvents))
+		for _, crdTCPRetryEvent := range tcpRetryEvents {
 			sdkTcpRetryEvents = append(sdkTcpRetryEvents, aws.String((string)(crdTCPRetryEvent)))
 		}
 	}
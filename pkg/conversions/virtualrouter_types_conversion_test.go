@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/conversion"
 )
@@ -427,6 +428,42 @@ func TestConvert_CRD_HTTPRouteMatch_To_SDK_HttpRouteMatch(t *testing.T) {
 				Scheme: nil,
 			},
 		},
+		{
+			name: "normal case + query parameters",
+			args: args{
+				crdObj: &appmesh.HTTPRouteMatch{
+					Prefix: aws.String("/appmesh"),
+					QueryParameters: []appmesh.HTTPQueryParameters{
+						{
+							Name: aws.String("version"),
+							Match: &appmesh.QueryMatchMethod{
+								Exact: aws.String("v2"),
+							},
+						},
+						{
+							Name: aws.String("debug"),
+						},
+					},
+				},
+
+				sdkObj: &appmeshsdk.HttpRouteMatch{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.HttpRouteMatch{
+				Prefix: aws.String("/appmesh"),
+				QueryParameters: []*appmeshsdk.HttpQueryParameter{
+					{
+						Name: aws.String("version"),
+						Match: &appmeshsdk.QueryParameterMatch{
+							Exact: aws.String("v2"),
+						},
+					},
+					{
+						Name: aws.String("debug"),
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -648,6 +685,57 @@ func TestConvert_CRD_HTTPTimeout_To_SDK_HttpTimeout(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "idle timeout only",
+			args: args{
+				crdObj: &appmesh.HTTPTimeout{
+					Idle: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(600),
+					},
+				},
+				sdkObj: &appmeshsdk.HttpTimeout{
+					PerRequest: &appmeshsdk.Duration{
+						Unit:  aws.String("ms"),
+						Value: aws.Int64(200),
+					},
+				},
+				scope: nil,
+			},
+			wantSDKObj: &appmeshsdk.HttpTimeout{
+				Idle: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(600),
+				},
+			},
+		},
+		{
+			name: "perRequest and idle timeout",
+			args: args{
+				crdObj: &appmesh.HTTPTimeout{
+					PerRequest: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(30),
+					},
+					Idle: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(600),
+					},
+				},
+				sdkObj: &appmeshsdk.HttpTimeout{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.HttpTimeout{
+				PerRequest: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(30),
+				},
+				Idle: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(600),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1696,6 +1784,9 @@ func TestConvert_CRD_GRPCRouteAction_To_SDK_GrpcRouteAction(t *testing.T) {
 }
 
 func TestConvert_CRD_GRPCRetryPolicy_To_SDK_GrpcRetryPolicy(t *testing.T) {
+	grpcRetryProfileIdempotentOnly := appmesh.GRPCRetryProfileIdempotentOnly
+	grpcRetryProfileAll := appmesh.GRPCRetryProfileAll
+	grpcRetryProfileUnknown := appmesh.GRPCRetryProfile("non-idempotent")
 	type args struct {
 		crdObj *appmesh.GRPCRetryPolicy
 		sdkObj *appmeshsdk.GrpcRetryPolicy
@@ -1897,6 +1988,68 @@ func TestConvert_CRD_GRPCRetryPolicy_To_SDK_GrpcRetryPolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "idempotent-only retry profile",
+			args: args{
+				crdObj: &appmesh.GRPCRetryPolicy{
+					Profile:    &grpcRetryProfileIdempotentOnly,
+					MaxRetries: int64(3),
+					PerRetryTimeout: appmesh.Duration{
+						Unit:  "ms",
+						Value: int64(200),
+					},
+				},
+				sdkObj: &appmeshsdk.GrpcRetryPolicy{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.GrpcRetryPolicy{
+				GrpcRetryEvents: []*string{aws.String("unavailable")},
+				HttpRetryEvents: nil,
+				TcpRetryEvents:  []*string{aws.String("connection-error")},
+				MaxRetries:      aws.Int64(3),
+				PerRetryTimeout: &appmeshsdk.Duration{
+					Unit:  aws.String("ms"),
+					Value: aws.Int64(200),
+				},
+			},
+		},
+		{
+			name: "all retry profile",
+			args: args{
+				crdObj: &appmesh.GRPCRetryPolicy{
+					Profile:    &grpcRetryProfileAll,
+					MaxRetries: int64(3),
+					PerRetryTimeout: appmesh.Duration{
+						Unit:  "ms",
+						Value: int64(200),
+					},
+				},
+				sdkObj: &appmeshsdk.GrpcRetryPolicy{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.GrpcRetryPolicy{
+				GrpcRetryEvents: []*string{aws.String("cancelled"), aws.String("deadline-exceeded"), aws.String("internal"), aws.String("resource-exhausted"), aws.String("unavailable")},
+				HttpRetryEvents: []*string{aws.String("server-error"), aws.String("gateway-error"), aws.String("client-error"), aws.String("stream-error")},
+				TcpRetryEvents:  []*string{aws.String("connection-error")},
+				MaxRetries:      aws.Int64(3),
+				PerRetryTimeout: &appmeshsdk.Duration{
+					Unit:  aws.String("ms"),
+					Value: aws.Int64(200),
+				},
+			},
+		},
+		{
+			name: "unknown retry profile",
+			args: args{
+				crdObj: &appmesh.GRPCRetryPolicy{
+					Profile:    &grpcRetryProfileUnknown,
+					MaxRetries: int64(3),
+				},
+				sdkObj: &appmeshsdk.GrpcRetryPolicy{},
+				scope:  nil,
+			},
+			wantErr: errors.New("unknown grpc retry profile: non-idempotent"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1942,6 +2095,57 @@ func TestConvert_CRD_GRPCTimeout_To_SDK_GrpcTimeout(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "idle timeout only",
+			args: args{
+				crdObj: &appmesh.GRPCTimeout{
+					Idle: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(600),
+					},
+				},
+				sdkObj: &appmeshsdk.GrpcTimeout{
+					PerRequest: &appmeshsdk.Duration{
+						Unit:  aws.String("ms"),
+						Value: aws.Int64(200),
+					},
+				},
+				scope: nil,
+			},
+			wantSDKObj: &appmeshsdk.GrpcTimeout{
+				Idle: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(600),
+				},
+			},
+		},
+		{
+			name: "perRequest and idle timeout",
+			args: args{
+				crdObj: &appmesh.GRPCTimeout{
+					PerRequest: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(30),
+					},
+					Idle: &appmesh.Duration{
+						Unit:  "s",
+						Value: int64(600),
+					},
+				},
+				sdkObj: &appmeshsdk.GrpcTimeout{},
+				scope:  nil,
+			},
+			wantSDKObj: &appmeshsdk.GrpcTimeout{
+				PerRequest: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(30),
+				},
+				Idle: &appmeshsdk.Duration{
+					Unit:  aws.String("s"),
+					Value: aws.Int64(600),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
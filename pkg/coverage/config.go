@@ -0,0 +1,30 @@
+package coverage
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagReportInterval = "mesh-coverage-report-interval"
+)
+
+type Config struct {
+	// How often the onboarding of namespaces, Deployments and VirtualNodes selected by each mesh is inventoried,
+	// and reported by the coverage of Mesh status. reporting is disabled if it's 0.
+	ReportInterval time.Duration
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&cfg.ReportInterval, flagReportInterval, 0,
+		"How often namespaces and Deployments selected by meshes that aren't meshed, and VirtualNodes without pods, are reported by the coverage of Mesh status, 0s disables")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.ReportInterval < 0 {
+		return errors.Errorf("%s must not be negative: %v", flagReportInterval, cfg.ReportInterval)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package coverage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "reporting disabled",
+			cfg:  Config{},
+		},
+		{
+			name: "reporting enabled",
+			cfg:  Config{ReportInterval: 5 * time.Minute},
+		},
+		{
+			name:    "negative interval",
+			cfg:     Config{ReportInterval: -time.Minute},
+			wantErr: "mesh-coverage-report-interval must not be negative: -1m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/inject"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxCoverageGaps is the maximum number of gaps reported in Mesh status.
+	maxCoverageGaps = 50
+	// envoyContainerName mirrors the name of the Envoy container injected by inject.SidecarInjector.
+	envoyContainerName = "envoy"
+
+	GapKindNamespace   = "Namespace"
+	GapKindDeployment  = "Deployment"
+	GapKindVirtualNode = "VirtualNode"
+
+	// GapReasonInjectionDisabled is reported for namespaces that aren't labeled for sidecar injection,
+	// and Deployments whose pods won't be injected due to namespace label or pod template annotation.
+	GapReasonInjectionDisabled = "InjectionDisabled"
+	// GapReasonNoVirtualNode is reported for Deployments whose pods aren't selected by any VirtualNode of the mesh.
+	GapReasonNoVirtualNode = "NoVirtualNode"
+	// GapReasonNotInjected is reported for Deployments with pods running without Envoy, e.g. created before injection was enabled.
+	GapReasonNotInjected = "NotInjected"
+	// GapReasonNoPods is reported for VirtualNodes with pod selectors that select no pods.
+	GapReasonNoPods = "NoPods"
+)
+
+// Reporter inventories namespaces, Deployments and VirtualNodes selected by meshes, reporting which of them aren't meshed.
+type Reporter interface {
+	// Report inventories the coverage of mesh and records the result as metrics.
+	Report(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshCoverage, error)
+	// Forget removes recorded metrics for mesh.
+	Forget(ms *appmesh.Mesh)
+}
+
+// NewDefaultReporter constructs new Reporter
+func NewDefaultReporter(k8sClient client.Client, metricsRegisterer prometheus.Registerer, log logr.Logger) (Reporter, error) {
+	deploymentsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "appmesh",
+		Name:      "mesh_coverage_deployments",
+		Help:      "Number of Deployments in namespaces selected by the mesh, by whether they are meshed",
+	}, []string{"mesh", "state"})
+	gapsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "appmesh",
+		Name:      "mesh_coverage_gaps",
+		Help:      "Number of namespaces, Deployments and VirtualNodes selected by the mesh that aren't fully onboarded, by reason",
+	}, []string{"mesh", "reason"})
+	if metricsRegisterer != nil {
+		for _, collector := range []prometheus.Collector{deploymentsGauge, gapsGauge} {
+			if err := metricsRegisterer.Register(collector); err != nil {
+				return nil, errors.Wrap(err, "failed to register coverage metrics")
+			}
+		}
+	}
+	return &defaultReporter{
+		k8sClient:        k8sClient,
+		deploymentsGauge: deploymentsGauge,
+		gapsGauge:        gapsGauge,
+		log:              log,
+	}, nil
+}
+
+var _ Reporter = &defaultReporter{}
+
+// defaultReporter implements Reporter by inspecting namespace labels, Deployment pod templates and pods.
+// A Deployment is meshed when its pods are injected by default, are selected by a VirtualNode of the mesh,
+// and none of its running pods lack the Envoy container.
+type defaultReporter struct {
+	k8sClient        client.Client
+	deploymentsGauge *prometheus.GaugeVec
+	gapsGauge        *prometheus.GaugeVec
+	log              logr.Logger
+}
+
+func (r *defaultReporter) Report(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshCoverage, error) {
+	namespaces, err := r.listSelectedNamespaces(ctx, ms)
+	if err != nil {
+		return nil, err
+	}
+	vnsByNamespace, err := r.listMeshVirtualNodes(ctx, ms)
+	if err != nil {
+		return nil, err
+	}
+	podsByNamespace := make(map[string][]corev1.Pod)
+	listPods := func(namespace string) ([]corev1.Pod, error) {
+		if pods, ok := podsByNamespace[namespace]; ok {
+			return pods, nil
+		}
+		podList := &corev1.PodList{}
+		if err := r.k8sClient.List(ctx, podList, client.InNamespace(namespace)); err != nil {
+			return nil, errors.Wrap(err, "failed to list pods")
+		}
+		podsByNamespace[namespace] = podList.Items
+		return podList.Items, nil
+	}
+
+	var gaps []appmesh.MeshCoverageGap
+	var deployments, meshedDeployments int32
+	for i := range namespaces {
+		ns := &namespaces[i]
+		if !isNamespaceInjectionConfigured(ns) {
+			// the injector webhook doesn't intercept pods of this namespace at all, which makes its Deployments unmeshed.
+			gaps = append(gaps, appmesh.MeshCoverageGap{Kind: GapKindNamespace, Name: ns.Name, Reason: GapReasonInjectionDisabled})
+		}
+		deploymentList := &appsv1.DeploymentList{}
+		if err := r.k8sClient.List(ctx, deploymentList, client.InNamespace(ns.Name)); err != nil {
+			return nil, errors.Wrap(err, "failed to list Deployments")
+		}
+		pods, err := listPods(ns.Name)
+		if err != nil {
+			return nil, err
+		}
+		for j := range deploymentList.Items {
+			deploy := &deploymentList.Items[j]
+			if !deploy.DeletionTimestamp.IsZero() {
+				continue
+			}
+			deployments++
+			reason, err := r.deploymentGapReason(ns, deploy, vnsByNamespace[ns.Name], pods)
+			if err != nil {
+				return nil, err
+			}
+			if len(reason) == 0 {
+				meshedDeployments++
+				continue
+			}
+			if reason == GapReasonInjectionDisabled && !isNamespaceInjectionConfigured(ns) {
+				// already reported for the namespace.
+				continue
+			}
+			gaps = append(gaps, appmesh.MeshCoverageGap{Kind: GapKindDeployment, Name: k8s.NamespacedName(deploy).String(), Reason: reason})
+		}
+	}
+
+	vnNamespaces := make([]string, 0, len(vnsByNamespace))
+	for namespace := range vnsByNamespace {
+		vnNamespaces = append(vnNamespaces, namespace)
+	}
+	sort.Strings(vnNamespaces)
+	for _, namespace := range vnNamespaces {
+		pods, err := listPods(namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, vn := range vnsByNamespace[namespace] {
+			selectsPods, err := selectsAnyPod(vn, pods)
+			if err != nil {
+				return nil, err
+			}
+			if !selectsPods {
+				gaps = append(gaps, appmesh.MeshCoverageGap{Kind: GapKindVirtualNode, Name: k8s.NamespacedName(vn).String(), Reason: GapReasonNoPods})
+			}
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if gaps[i].Kind != gaps[j].Kind {
+			return gapKindOrder(gaps[i].Kind) < gapKindOrder(gaps[j].Kind)
+		}
+		return gaps[i].Name < gaps[j].Name
+	})
+
+	r.recordMetrics(ms, deployments, meshedDeployments, gaps)
+	now := metav1.Now()
+	coverage := &appmesh.MeshCoverage{
+		LastReportTime:    &now,
+		Namespaces:        int32(len(namespaces)),
+		Deployments:       deployments,
+		MeshedDeployments: meshedDeployments,
+		Meshed:            fmt.Sprintf("%d/%d", meshedDeployments, deployments),
+		GapCount:          int32(len(gaps)),
+	}
+	if len(gaps) > maxCoverageGaps {
+		gaps = gaps[:maxCoverageGaps]
+	}
+	coverage.Gaps = gaps
+	return coverage, nil
+}
+
+func (r *defaultReporter) Forget(ms *appmesh.Mesh) {
+	r.deploymentsGauge.DeletePartialMatch(prometheus.Labels{"mesh": ms.Name})
+	r.gapsGauge.DeletePartialMatch(prometheus.Labels{"mesh": ms.Name})
+}
+
+// listSelectedNamespaces lists namespaces selected by namespaceSelector of ms, ordered by name.
+func (r *defaultReporter) listSelectedNamespaces(ctx context.Context, ms *appmesh.Mesh) ([]corev1.Namespace, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ms.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid namespaceSelector")
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := r.k8sClient.List(ctx, nsList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	namespaces := nsList.Items
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, nil
+}
+
+// listMeshVirtualNodes lists VirtualNodes of ms with pod selectors, grouped by namespace and ordered by name.
+// VirtualNodes without pod selectors don't have pods in the cluster, so they are out of coverage.
+func (r *defaultReporter) listMeshVirtualNodes(ctx context.Context, ms *appmesh.Mesh) (map[string][]*appmesh.VirtualNode, error) {
+	vnList := &appmesh.VirtualNodeList{}
+	if err := r.k8sClient.List(ctx, vnList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualNodes")
+	}
+	sort.Slice(vnList.Items, func(i, j int) bool {
+		return vnList.Items[i].Name < vnList.Items[j].Name
+	})
+	vnsByNamespace := make(map[string][]*appmesh.VirtualNode)
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		if vn.Spec.MeshRef == nil || !mesh.IsMeshReferenced(ms, *vn.Spec.MeshRef) {
+			continue
+		}
+		if !vn.DeletionTimestamp.IsZero() || !virtualnode.HasPodSelector(vn) {
+			continue
+		}
+		vnsByNamespace[vn.Namespace] = append(vnsByNamespace[vn.Namespace], vn)
+	}
+	return vnsByNamespace, nil
+}
+
+// deploymentGapReason returns why deploy in ns isn't meshed, or empty if it's meshed.
+func (r *defaultReporter) deploymentGapReason(ns *corev1.Namespace, deploy *appsv1.Deployment, vns []*appmesh.VirtualNode, pods []corev1.Pod) (string, error) {
+	if !isInjectionEnabled(ns, deploy.Spec.Template.Annotations) {
+		return GapReasonInjectionDisabled, nil
+	}
+	hasVirtualNode := false
+	for _, vn := range vns {
+		matches, err := virtualnode.MatchesPodLabels(vn, deploy.Spec.Template.Labels)
+		if err != nil {
+			return "", err
+		}
+		if matches {
+			hasVirtualNode = true
+			break
+		}
+	}
+	if !hasVirtualNode {
+		return GapReasonNoVirtualNode, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid selector of Deployment %s", k8s.NamespacedName(deploy))
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) && !hasEnvoyContainer(pod) {
+			return GapReasonNotInjected, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *defaultReporter) recordMetrics(ms *appmesh.Mesh, deployments int32, meshedDeployments int32, gaps []appmesh.MeshCoverageGap) {
+	// reasons without gaps anymore shouldn't be reported with their last count.
+	r.gapsGauge.DeletePartialMatch(prometheus.Labels{"mesh": ms.Name})
+	gapsByReason := make(map[string]int)
+	for _, gap := range gaps {
+		gapsByReason[gap.Reason]++
+	}
+	for reason, count := range gapsByReason {
+		r.gapsGauge.WithLabelValues(ms.Name, reason).Set(float64(count))
+	}
+	r.deploymentsGauge.WithLabelValues(ms.Name, "meshed").Set(float64(meshedDeployments))
+	r.deploymentsGauge.WithLabelValues(ms.Name, "unmeshed").Set(float64(deployments - meshedDeployments))
+}
+
+// isNamespaceInjectionConfigured checks whether the injector webhook intercepts pods of ns.
+func isNamespaceInjectionConfigured(ns *corev1.Namespace) bool {
+	_, ok := ns.Labels[inject.AppMeshSidecarInjectAnnotation]
+	return ok
+}
+
+// isInjectionEnabled checks whether pods with podAnnotations in ns would be injected, following inject.SidecarInjector:
+// the pod annotation takes precedence over the namespace label, and pods are injected unless either is disabled.
+func isInjectionEnabled(ns *corev1.Namespace, podAnnotations map[string]string) bool {
+	if !isNamespaceInjectionConfigured(ns) {
+		return false
+	}
+	mode := ns.Labels[inject.AppMeshSidecarInjectAnnotation]
+	if v, ok := podAnnotations[inject.AppMeshSidecarInjectAnnotation]; ok {
+		mode = v
+	}
+	return !strings.EqualFold(mode, "disabled")
+}
+
+// selectsAnyPod checks whether vn selects any of pods that isn't being deleted.
+func selectsAnyPod(vn *appmesh.VirtualNode, pods []corev1.Pod) (bool, error) {
+	for i := range pods {
+		if !pods[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		matches, err := virtualnode.MatchesPodLabels(vn, pods[i].Labels)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hasEnvoyContainer(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == envoyContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+func gapKindOrder(kind string) int {
+	switch kind {
+	case GapKindNamespace:
+		return 0
+	case GapKindDeployment:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,135 @@
+package coverage
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/inject"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultReporter_Report(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "my-mesh-uid"},
+		Spec: appmesh.MeshSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "my-mesh"}},
+		},
+	}
+	meshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "my-mesh-uid"}
+	namespace := func(name string, injection string) *corev1.Namespace {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"mesh": "my-mesh"}},
+		}
+		if len(injection) != 0 {
+			ns.Labels[inject.AppMeshSidecarInjectAnnotation] = injection
+		}
+		return ns
+	}
+	deployment := func(namespace string, app string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: app},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": app}, Annotations: annotations},
+				},
+			},
+		}
+	}
+	pod := func(namespace string, app string, containers ...string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: app + "-pod", Labels: map[string]string{"app": app}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		for _, container := range containers {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: container, Image: container})
+		}
+		return p
+	}
+	virtualNode := func(namespace string, app string) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: app},
+			Spec: appmesh.VirtualNodeSpec{
+				MeshRef:     meshRef,
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			},
+		}
+	}
+
+	objects := []client.Object{
+		namespace("meshed-ns", "enabled"),
+		namespace("opt-in-ns", "disabled"),
+		namespace("unlabeled-ns", ""),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-ns"}},
+
+		deployment("meshed-ns", "payments", nil),
+		pod("meshed-ns", "payments", "app", "envoy"),
+		virtualNode("meshed-ns", "payments"),
+		deployment("meshed-ns", "legacy", nil),
+		deployment("meshed-ns", "batch", map[string]string{inject.AppMeshSidecarInjectAnnotation: "disabled"}),
+		deployment("meshed-ns", "orders", nil),
+		pod("meshed-ns", "orders", "app"),
+		virtualNode("meshed-ns", "orders"),
+		virtualNode("meshed-ns", "retired"),
+
+		deployment("opt-in-ns", "catalog", map[string]string{inject.AppMeshSidecarInjectAnnotation: "enabled"}),
+		pod("opt-in-ns", "catalog", "app", "envoy"),
+		virtualNode("opt-in-ns", "catalog"),
+		deployment("opt-in-ns", "reports", nil),
+
+		deployment("unlabeled-ns", "frontend", nil),
+		deployment("other-ns", "unrelated", nil),
+	}
+
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	for _, obj := range objects {
+		assert.NoError(t, k8sClient.Create(ctx, obj))
+	}
+
+	r, err := NewDefaultReporter(k8sClient, nil, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, err)
+	coverage, err := r.Report(ctx, ms)
+	assert.NoError(t, err)
+	assert.NotNil(t, coverage.LastReportTime)
+	coverage.LastReportTime = nil
+	assert.Equal(t, &appmesh.MeshCoverage{
+		Namespaces:        3,
+		Deployments:       7,
+		MeshedDeployments: 2,
+		Meshed:            "2/7",
+		GapCount:          6,
+		Gaps: []appmesh.MeshCoverageGap{
+			{Kind: GapKindNamespace, Name: "unlabeled-ns", Reason: GapReasonInjectionDisabled},
+			{Kind: GapKindDeployment, Name: "meshed-ns/batch", Reason: GapReasonInjectionDisabled},
+			{Kind: GapKindDeployment, Name: "meshed-ns/legacy", Reason: GapReasonNoVirtualNode},
+			{Kind: GapKindDeployment, Name: "meshed-ns/orders", Reason: GapReasonNotInjected},
+			{Kind: GapKindDeployment, Name: "opt-in-ns/reports", Reason: GapReasonInjectionDisabled},
+			{Kind: GapKindVirtualNode, Name: "meshed-ns/retired", Reason: GapReasonNoPods},
+		},
+	}, coverage)
+
+	reporter := r.(*defaultReporter)
+	assert.Equal(t, float64(2), testutil.ToFloat64(reporter.deploymentsGauge.WithLabelValues("my-mesh", "meshed")))
+	assert.Equal(t, float64(5), testutil.ToFloat64(reporter.deploymentsGauge.WithLabelValues("my-mesh", "unmeshed")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(reporter.gapsGauge.WithLabelValues("my-mesh", GapReasonInjectionDisabled)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reporter.gapsGauge.WithLabelValues("my-mesh", GapReasonNoPods)))
+
+	r.Forget(ms)
+	assert.Equal(t, 0, testutil.CollectAndCount(reporter.deploymentsGauge))
+	assert.Equal(t, 0, testutil.CollectAndCount(reporter.gapsGauge))
+}
@@ -0,0 +1,103 @@
+package deletion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// dependentsRecheckInterval is how long deletion of an object waiting for its dependents is requeued after.
+	dependentsRecheckInterval = 5 * time.Second
+)
+
+// Orchestrator sequences deletion of AWS resources across objects being deleted together, e.g. upon namespace teardown.
+// AppMesh refuses to delete resources referenced by other resources with ResourceInUseException,
+// so the AWS resources of an object are deleted only once the objects referencing it being deleted are gone:
+//   - virtualServices are deleted first, which removes them as providers of virtualRouters and virtualNodes.
+//   - virtualRouters are deleted along with their routes once virtualServices provided by them are deleted.
+//   - virtualNodes are deleted last, once virtualServices provided by them and virtualRouters routing to them are deleted.
+//
+// Objects referenced by objects that aren't being deleted are deleted as before, and fail until they're no longer referenced.
+type Orchestrator interface {
+	// WaitForVirtualRouterDependents returns RequeueAfterError while virtualServices provided by vr are being deleted.
+	WaitForVirtualRouterDependents(ctx context.Context, vr *appmesh.VirtualRouter) error
+	// WaitForVirtualNodeDependents returns RequeueAfterError while virtualServices provided by vn or virtualRouters routing to vn are being deleted.
+	WaitForVirtualNodeDependents(ctx context.Context, vn *appmesh.VirtualNode) error
+}
+
+// NewDefaultOrchestrator constructs new Orchestrator
+func NewDefaultOrchestrator(referencesIndexer references.ObjectReferenceIndexer, log logr.Logger) Orchestrator {
+	return &defaultOrchestrator{
+		referencesIndexer: referencesIndexer,
+		log:               log,
+	}
+}
+
+var _ Orchestrator = &defaultOrchestrator{}
+
+type defaultOrchestrator struct {
+	referencesIndexer references.ObjectReferenceIndexer
+	log               logr.Logger
+}
+
+func (o *defaultOrchestrator) WaitForVirtualRouterDependents(ctx context.Context, vr *appmesh.VirtualRouter) error {
+	vsList := &appmesh.VirtualServiceList{}
+	if err := o.referencesIndexer.Fetch(ctx, vsList, virtualservice.ReferenceKindVirtualRouter, k8s.NamespacedName(vr)); err != nil {
+		return errors.Wrap(err, "failed to fetch virtualServices referencing virtualRouter")
+	}
+	var dependents []string
+	for i := range vsList.Items {
+		dependents = appendDeletingDependent(dependents, "virtualService", &vsList.Items[i])
+	}
+	return o.waitForDependents("virtualRouter", vr, dependents)
+}
+
+func (o *defaultOrchestrator) WaitForVirtualNodeDependents(ctx context.Context, vn *appmesh.VirtualNode) error {
+	vsList := &appmesh.VirtualServiceList{}
+	if err := o.referencesIndexer.Fetch(ctx, vsList, virtualservice.ReferenceKindVirtualNode, k8s.NamespacedName(vn)); err != nil {
+		return errors.Wrap(err, "failed to fetch virtualServices referencing virtualNode")
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := o.referencesIndexer.Fetch(ctx, vrList, virtualrouter.ReferenceKindVirtualNode, k8s.NamespacedName(vn)); err != nil {
+		return errors.Wrap(err, "failed to fetch virtualRouters referencing virtualNode")
+	}
+	var dependents []string
+	for i := range vsList.Items {
+		dependents = appendDeletingDependent(dependents, "virtualService", &vsList.Items[i])
+	}
+	for i := range vrList.Items {
+		dependents = appendDeletingDependent(dependents, "virtualRouter", &vrList.Items[i])
+	}
+	return o.waitForDependents("virtualNode", vn, dependents)
+}
+
+func (o *defaultOrchestrator) waitForDependents(kind string, obj client.Object, dependents []string) error {
+	if len(dependents) == 0 {
+		return nil
+	}
+	o.log.V(1).Info("defer deletion until dependents are deleted",
+		kind, k8s.NamespacedName(obj),
+		"dependents", dependents,
+	)
+	return runtime.NewRequeueAfterError(errors.Errorf("%s deletion deferred until dependents are deleted: %s",
+		kind, strings.Join(dependents, ", ")), dependentsRecheckInterval)
+}
+
+// appendDeletingDependent appends dependent to dependents if it's being deleted and its AWS resources aren't deleted yet.
+func appendDeletingDependent(dependents []string, kind string, dependent client.Object) []string {
+	if dependent.GetDeletionTimestamp().IsZero() || !k8s.HasFinalizer(dependent, k8s.FinalizerAWSAppMeshResources) {
+		return dependents
+	}
+	return append(dependents, kind+"/"+k8s.NamespacedName(dependent).String())
+}
@@ -0,0 +1,206 @@
+package deletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeReferencesIndexer fetches virtualServices and virtualRouters by the index funcs of their controllers.
+type fakeReferencesIndexer struct {
+	references.ObjectReferenceIndexer
+
+	virtualServices []*appmesh.VirtualService
+	virtualRouters  []*appmesh.VirtualRouter
+}
+
+func (i *fakeReferencesIndexer) Fetch(_ context.Context, objList client.ObjectList, referentKind string, referentKey types.NamespacedName, _ ...client.ListOption) error {
+	switch list := objList.(type) {
+	case *appmesh.VirtualServiceList:
+		indexFunc := virtualservice.VirtualNodeReferenceIndexFunc
+		if referentKind == virtualservice.ReferenceKindVirtualRouter {
+			indexFunc = virtualservice.VirtualRouterReferenceIndexFunc
+		}
+		for _, vs := range i.virtualServices {
+			if containsKey(indexFunc(vs), referentKey) {
+				list.Items = append(list.Items, *vs)
+			}
+		}
+	case *appmesh.VirtualRouterList:
+		for _, vr := range i.virtualRouters {
+			if containsKey(virtualrouter.VirtualNodeReferenceIndexFunc(vr), referentKey) {
+				list.Items = append(list.Items, *vr)
+			}
+		}
+	}
+	return nil
+}
+
+func containsKey(keys []types.NamespacedName, key types.NamespacedName) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func deletingObjectMeta(name string, finalizers ...string) metav1.ObjectMeta {
+	deletionTimestamp := metav1.Now()
+	return metav1.ObjectMeta{Namespace: "my-ns", Name: name, DeletionTimestamp: &deletionTimestamp, Finalizers: finalizers}
+}
+
+func vsWithVirtualRouterProvider(objectMeta metav1.ObjectMeta, vrName string) *appmesh.VirtualService {
+	return &appmesh.VirtualService{
+		ObjectMeta: objectMeta,
+		Spec: appmesh.VirtualServiceSpec{
+			Provider: &appmesh.VirtualServiceProvider{
+				VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+					VirtualRouterRef: &appmesh.VirtualRouterReference{Name: vrName},
+				},
+			},
+		},
+	}
+}
+
+func vsWithVirtualNodeProvider(objectMeta metav1.ObjectMeta, vnName string) *appmesh.VirtualService {
+	return &appmesh.VirtualService{
+		ObjectMeta: objectMeta,
+		Spec: appmesh.VirtualServiceSpec{
+			Provider: &appmesh.VirtualServiceProvider{
+				VirtualNode: &appmesh.VirtualNodeServiceProvider{
+					VirtualNodeRef: &appmesh.VirtualNodeReference{Name: vnName},
+				},
+			},
+		},
+	}
+}
+
+func vrWithTarget(objectMeta metav1.ObjectMeta, vnName string) *appmesh.VirtualRouter {
+	return &appmesh.VirtualRouter{
+		ObjectMeta: objectMeta,
+		Spec: appmesh.VirtualRouterSpec{
+			Routes: []appmesh.Route{
+				{
+					Name: "route-1",
+					TCPRoute: &appmesh.TCPRoute{
+						Action: appmesh.TCPRouteAction{
+							WeightedTargets: []appmesh.WeightedTarget{
+								{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: vnName}, Weight: 100},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_defaultOrchestrator_WaitForVirtualRouterDependents(t *testing.T) {
+	vr := &appmesh.VirtualRouter{ObjectMeta: deletingObjectMeta("vr-1", k8s.FinalizerAWSAppMeshResources)}
+	tests := []struct {
+		name            string
+		virtualServices []*appmesh.VirtualService
+		wantErr         string
+	}{
+		{
+			name: "virtualServices provided by virtualRouter are being deleted",
+			virtualServices: []*appmesh.VirtualService{
+				vsWithVirtualRouterProvider(deletingObjectMeta("vs-1", k8s.FinalizerAWSAppMeshResources), "vr-1"),
+				vsWithVirtualRouterProvider(deletingObjectMeta("vs-2", k8s.FinalizerAWSAppMeshResources), "vr-2"),
+			},
+			wantErr: "virtualRouter deletion deferred until dependents are deleted: virtualService/my-ns/vs-1",
+		},
+		{
+			name: "virtualServices provided by virtualRouter aren't being deleted",
+			virtualServices: []*appmesh.VirtualService{
+				vsWithVirtualRouterProvider(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-1", Finalizers: []string{k8s.FinalizerAWSAppMeshResources}}, "vr-1"),
+			},
+		},
+		{
+			name: "AWS resources of virtualServices provided by virtualRouter are deleted",
+			virtualServices: []*appmesh.VirtualService{
+				vsWithVirtualRouterProvider(deletingObjectMeta("vs-1", "other-finalizer"), "vr-1"),
+			},
+		},
+		{
+			name: "no virtualServices provided by virtualRouter",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewDefaultOrchestrator(&fakeReferencesIndexer{virtualServices: tt.virtualServices}, logr.Discard())
+			err := o.WaitForVirtualRouterDependents(context.Background(), vr)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			var requeueAfterErr *runtime.RequeueAfterError
+			if assert.ErrorAs(t, err, &requeueAfterErr) {
+				assert.Equal(t, 5*time.Second, requeueAfterErr.Duration())
+			}
+		})
+	}
+}
+
+func Test_defaultOrchestrator_WaitForVirtualNodeDependents(t *testing.T) {
+	vn := &appmesh.VirtualNode{ObjectMeta: deletingObjectMeta("vn-1", k8s.FinalizerAWSAppMeshResources)}
+	tests := []struct {
+		name            string
+		virtualServices []*appmesh.VirtualService
+		virtualRouters  []*appmesh.VirtualRouter
+		wantErr         string
+	}{
+		{
+			name: "virtualServices and virtualRouters referencing virtualNode are being deleted",
+			virtualServices: []*appmesh.VirtualService{
+				vsWithVirtualNodeProvider(deletingObjectMeta("vs-1", k8s.FinalizerAWSAppMeshResources), "vn-1"),
+			},
+			virtualRouters: []*appmesh.VirtualRouter{
+				vrWithTarget(deletingObjectMeta("vr-1", k8s.FinalizerAWSAppMeshResources), "vn-1"),
+				vrWithTarget(deletingObjectMeta("vr-2", k8s.FinalizerAWSAppMeshResources), "vn-2"),
+			},
+			wantErr: "virtualNode deletion deferred until dependents are deleted: virtualService/my-ns/vs-1, virtualRouter/my-ns/vr-1",
+		},
+		{
+			name: "virtualRouters routing to virtualNode are being deleted",
+			virtualRouters: []*appmesh.VirtualRouter{
+				vrWithTarget(deletingObjectMeta("vr-1", k8s.FinalizerAWSAppMeshResources), "vn-1"),
+			},
+			wantErr: "virtualNode deletion deferred until dependents are deleted: virtualRouter/my-ns/vr-1",
+		},
+		{
+			name: "virtualServices and virtualRouters referencing virtualNode aren't being deleted",
+			virtualServices: []*appmesh.VirtualService{
+				vsWithVirtualNodeProvider(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-1"}, "vn-1"),
+			},
+			virtualRouters: []*appmesh.VirtualRouter{
+				vrWithTarget(metav1.ObjectMeta{Namespace: "my-ns", Name: "vr-1"}, "vn-1"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewDefaultOrchestrator(&fakeReferencesIndexer{virtualServices: tt.virtualServices, virtualRouters: tt.virtualRouters}, logr.Discard())
+			err := o.WaitForVirtualNodeDependents(context.Background(), vn)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ingressClusterPrefix is the name prefix of Envoy clusters of the local application, which are health checked by listener health checks.
+	ingressClusterPrefix = "cds_ingress_"
+)
+
+// HealthChecker checks whether the application of a pod passes the health checks of its Envoy sidecar.
+type HealthChecker interface {
+	// Check returns whether the local application passes the active health checks of every listener of pod's Envoy.
+	// When it doesn't, a human readable reason is returned as well.
+	Check(ctx context.Context, pod *corev1.Pod) (bool, string, error)
+}
+
+// NewDefaultHealthChecker constructs new HealthChecker that queries Envoy admin interface over pod IP.
+func NewDefaultHealthChecker() HealthChecker {
+	return &defaultHealthChecker{
+		httpClient: &http.Client{Timeout: defaultRequestTimeout},
+	}
+}
+
+var _ HealthChecker = &defaultHealthChecker{}
+
+type defaultHealthChecker struct {
+	httpClient *http.Client
+}
+
+// clustersResponse is the subset of Envoy admin `/clusters?format=json` response we care about.
+type clustersResponse struct {
+	ClusterStatuses []struct {
+		Name         string `json:"name"`
+		HostStatuses []struct {
+			HealthStatus struct {
+				FailedActiveHealthCheck bool `json:"failed_active_health_check"`
+			} `json:"health_status"`
+		} `json:"host_statuses"`
+	} `json:"cluster_statuses"`
+}
+
+func (c *defaultHealthChecker) Check(ctx context.Context, pod *corev1.Pod) (bool, string, error) {
+	envoy := findEnvoyContainer(pod)
+	if envoy == nil {
+		return false, "envoy container not found", nil
+	}
+	adminAddr, reason := resolveAdminAddress(pod, envoy)
+	if len(adminAddr) == 0 {
+		return false, reason, nil
+	}
+
+	payload, err := get(ctx, c.httpClient, fmt.Sprintf("http://%s/clusters?format=json", adminAddr))
+	if err != nil {
+		return false, fmt.Sprintf("failed to query envoy clusters: %v", err), nil
+	}
+	clusters := clustersResponse{}
+	if err := json.Unmarshal([]byte(payload), &clusters); err != nil {
+		return false, "", errors.Wrap(err, "failed to decode envoy clusters")
+	}
+	var failingClusters []string
+	for _, cluster := range clusters.ClusterStatuses {
+		if !strings.HasPrefix(cluster.Name, ingressClusterPrefix) {
+			continue
+		}
+		for _, host := range cluster.HostStatuses {
+			if host.HealthStatus.FailedActiveHealthCheck {
+				failingClusters = append(failingClusters, cluster.Name)
+				break
+			}
+		}
+	}
+	if len(failingClusters) != 0 {
+		sort.Strings(failingClusters)
+		return false, fmt.Sprintf("health checks of %s are failing", strings.Join(failingClusters, ", ")), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,88 @@
+package envoy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_defaultHealthChecker_Check(t *testing.T) {
+	newAdminServer := func(clusters string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/clusters", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(clusters))
+		})
+		return httptest.NewServer(mux)
+	}
+	podForServer := func(server *httptest.Server) *corev1.Pod {
+		host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app"},
+					{Name: "envoy", Env: []corev1.EnvVar{{Name: adminAccessPortEnv, Value: port}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: host,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "envoy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		clusters    string
+		wantHealthy bool
+		wantReason  string
+		wantErrText string
+	}{
+		{
+			name: "local app passes health checks",
+			clusters: `{"cluster_statuses":[
+				{"name":"cds_ingress_my-mesh_my-vn_http_8080","host_statuses":[{"health_status":{"eds_health_status":"HEALTHY"}}]},
+				{"name":"cds_egress_my-mesh_other-vn_http_8080","host_statuses":[{"health_status":{"failed_active_health_check":true}}]}
+			]}`,
+			wantHealthy: true,
+		},
+		{
+			name: "local app fails health checks",
+			clusters: `{"cluster_statuses":[
+				{"name":"cds_ingress_my-mesh_my-vn_http_8080","host_statuses":[{"health_status":{"failed_active_health_check":true}}]},
+				{"name":"cds_ingress_my-mesh_my-vn_grpc_9090","host_statuses":[{"health_status":{"eds_health_status":"HEALTHY"}}]}
+			]}`,
+			wantHealthy: false,
+			wantReason:  "health checks of cds_ingress_my-mesh_my-vn_http_8080 are failing",
+		},
+		{
+			name:        "malformed clusters response",
+			clusters:    `not-json`,
+			wantHealthy: false,
+			wantErrText: "failed to decode envoy clusters: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAdminServer(tt.clusters)
+			defer server.Close()
+			c := NewDefaultHealthChecker()
+			healthy, reason, err := c.Check(context.Background(), podForServer(server))
+			if tt.wantErrText != "" {
+				assert.EqualError(t, err, tt.wantErrText)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantHealthy, healthy)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
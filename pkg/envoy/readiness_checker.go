@@ -0,0 +1,147 @@
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// containerName is the name of injected envoy container.
+	containerName = "envoy"
+	// adminAccessPortEnv is the env variable on envoy container that carries admin port.
+	adminAccessPortEnv     = "ENVOY_ADMIN_ACCESS_PORT"
+	defaultAdminAccessPort = 9901
+
+	serverStateLive       = "LIVE"
+	defaultRequestTimeout = 2 * time.Second
+)
+
+// ReadinessChecker checks whether the Envoy sidecar of a pod is ready to serve mesh traffic.
+type ReadinessChecker interface {
+	// Check returns whether pod's Envoy is LIVE and has listeners configured.
+	// When it's not ready, a human readable reason is returned as well.
+	Check(ctx context.Context, pod *corev1.Pod) (bool, string, error)
+}
+
+// NewDefaultReadinessChecker constructs new ReadinessChecker that queries Envoy admin interface over pod IP.
+func NewDefaultReadinessChecker() ReadinessChecker {
+	return &defaultReadinessChecker{
+		httpClient: &http.Client{Timeout: defaultRequestTimeout},
+	}
+}
+
+var _ ReadinessChecker = &defaultReadinessChecker{}
+
+type defaultReadinessChecker struct {
+	httpClient *http.Client
+}
+
+// listenersResponse is the subset of Envoy admin `/listeners?format=json` response we care about.
+type listenersResponse struct {
+	ListenerStatuses []struct {
+		Name string `json:"name"`
+	} `json:"listener_statuses"`
+}
+
+func (c *defaultReadinessChecker) Check(ctx context.Context, pod *corev1.Pod) (bool, string, error) {
+	envoy := findEnvoyContainer(pod)
+	if envoy == nil {
+		return false, "", errors.Errorf("envoy container not found in pod %s/%s", pod.Namespace, pod.Name)
+	}
+	adminAddr, reason := resolveAdminAddress(pod, envoy)
+	if len(adminAddr) == 0 {
+		return false, reason, nil
+	}
+
+	// failures to reach admin interface are expected while Envoy is starting, and reported as not ready.
+	state, err := get(ctx, c.httpClient, fmt.Sprintf("http://%s/ready", adminAddr))
+	if err != nil {
+		return false, fmt.Sprintf("failed to query envoy server state: %v", err), nil
+	}
+	if strings.TrimSpace(state) != serverStateLive {
+		return false, fmt.Sprintf("envoy server state is %s", strings.TrimSpace(state)), nil
+	}
+	payload, err := get(ctx, c.httpClient, fmt.Sprintf("http://%s/listeners?format=json", adminAddr))
+	if err != nil {
+		return false, fmt.Sprintf("failed to query envoy listeners: %v", err), nil
+	}
+	listeners := listenersResponse{}
+	if err := json.Unmarshal([]byte(payload), &listeners); err != nil {
+		return false, "", errors.Wrap(err, "failed to decode envoy listeners")
+	}
+	if len(listeners.ListenerStatuses) == 0 {
+		return false, "envoy listeners are not configured", nil
+	}
+	return true, "", nil
+}
+
+// resolveAdminAddress returns the address of the admin interface of envoy container of pod.
+// When it can't be reached yet, an empty address is returned along with a human readable reason.
+func resolveAdminAddress(pod *corev1.Pod, envoy *corev1.Container) (string, string) {
+	if !isContainerRunning(pod, containerName) {
+		return "", "envoy container is not running"
+	}
+	if pod.Status.PodIP == "" {
+		return "", "pod IP is not assigned"
+	}
+	return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(adminAccessPort(envoy)))), ""
+}
+
+// get issues a GET request and returns response body. Envoy responds the /ready endpoint with 503 when it's not LIVE,
+// so body is returned regardless of status code.
+func get(ctx context.Context, httpClient *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func findEnvoyContainer(pod *corev1.Pod) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+func isContainerRunning(pod *corev1.Pod, name string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == name {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+func adminAccessPort(envoy *corev1.Container) int32 {
+	for _, env := range envoy.Env {
+		if env.Name != adminAccessPortEnv {
+			continue
+		}
+		if port, err := strconv.ParseInt(env.Value, 10, 32); err == nil {
+			return int32(port)
+		}
+	}
+	return defaultAdminAccessPort
+}
@@ -0,0 +1,144 @@
+package envoy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_defaultReadinessChecker_Check(t *testing.T) {
+	newAdminServer := func(state string, listeners string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+			if state != serverStateLive {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			w.Write([]byte(state + "\n"))
+		})
+		mux.HandleFunc("/listeners", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(listeners))
+		})
+		return httptest.NewServer(mux)
+	}
+	podForServer := func(server *httptest.Server, running bool) *corev1.Pod {
+		host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app"},
+					{Name: "envoy", Env: []corev1.EnvVar{{Name: adminAccessPortEnv, Value: port}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				PodIP: host,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "envoy", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+				},
+			},
+		}
+		if running {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name        string
+		state       string
+		listeners   string
+		running     bool
+		wantReady   bool
+		wantReason  string
+		wantErrText string
+	}{
+		{
+			name:      "envoy live with listeners",
+			state:     "LIVE",
+			listeners: `{"listener_statuses":[{"name":"lds_ingress_0.0.0.0_15000"}]}`,
+			running:   true,
+			wantReady: true,
+		},
+		{
+			name:       "envoy container not running",
+			state:      "LIVE",
+			listeners:  `{"listener_statuses":[{"name":"lds_ingress_0.0.0.0_15000"}]}`,
+			running:    false,
+			wantReady:  false,
+			wantReason: "envoy container is not running",
+		},
+		{
+			name:       "envoy still initializing",
+			state:      "PRE_INITIALIZING",
+			running:    true,
+			wantReady:  false,
+			wantReason: "envoy server state is PRE_INITIALIZING",
+		},
+		{
+			name:       "envoy live without listeners",
+			state:      "LIVE",
+			listeners:  `{"listener_statuses":[]}`,
+			running:    true,
+			wantReady:  false,
+			wantReason: "envoy listeners are not configured",
+		},
+		{
+			name:        "malformed listeners response",
+			state:       "LIVE",
+			listeners:   `not-json`,
+			running:     true,
+			wantReady:   false,
+			wantErrText: "failed to decode envoy listeners: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAdminServer(tt.state, tt.listeners)
+			defer server.Close()
+			c := NewDefaultReadinessChecker()
+			ready, reason, err := c.Check(context.Background(), podForServer(server, tt.running))
+			if tt.wantErrText != "" {
+				assert.EqualError(t, err, tt.wantErrText)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantReady, ready)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func Test_adminAccessPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		envoy *corev1.Container
+		want  int32
+	}{
+		{
+			name:  "port from env",
+			envoy: &corev1.Container{Env: []corev1.EnvVar{{Name: adminAccessPortEnv, Value: "9902"}}},
+			want:  9902,
+		},
+		{
+			name:  "default port when env is absent",
+			envoy: &corev1.Container{},
+			want:  defaultAdminAccessPort,
+		},
+		{
+			name:  "default port when env is malformed",
+			envoy: &corev1.Container{Env: []corev1.EnvVar{{Name: adminAccessPortEnv, Value: "admin"}}},
+			want:  defaultAdminAccessPort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, adminAccessPort(tt.envoy))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package envoyadmin
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagEnableProxy = "enable-envoy-admin-proxy"
+)
+
+type Config struct {
+	// EnableProxy controls whether read-only queries to the Envoy admin interface of pods are proxied at ProxyPath of the metrics server.
+	EnableProxy bool
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.EnableProxy, flagEnableProxy, false,
+		"If enabled, read-only queries to the Envoy admin interface of pods are proxied at "+ProxyPath+"<namespace>/<pod>/<endpoint> of the metrics server, "+
+			"for callers allowed to get pods/proxy of the pod")
+}
@@ -0,0 +1,134 @@
+package envoyadmin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ProxyPath is the path prefix of the Envoy admin proxy on the metrics server.
+	// queries are proxied from ProxyPath<namespace>/<pod>/<endpoint>.
+	ProxyPath = "/debug/envoy/"
+)
+
+// allowedQueryParams are the read-only Envoy admin endpoints proxied, and the query parameters passed on to each of them.
+var allowedQueryParams = map[string][]string{
+	"clusters":    {"format"},
+	"config_dump": {"resource", "mask", "name_regex", "include_eds"},
+	"listeners":   {"format"},
+	"ready":       nil,
+	"server_info": nil,
+	"stats":       {"filter", "format", "usedonly"},
+}
+
+// +kubebuilder:rbac:groups="",resources=pods/proxy,verbs=get
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// NewProxyHandler constructs new http.Handler proxying read-only queries to the Envoy admin interface at adminPort of pods via the API server.
+// callers must present a bearer token of an identity that is allowed to get pods/proxy of the pod.
+func NewProxyHandler(clientSet kubernetes.Interface, adminPort int32, log logr.Logger) http.Handler {
+	return &proxyHandler{
+		clientSet:  clientSet,
+		authorizer: k8s.NewDefaultRequestAuthorizer(clientSet),
+		adminPort:  adminPort,
+		log:        log,
+	}
+}
+
+type proxyHandler struct {
+	clientSet  kubernetes.Interface
+	authorizer k8s.RequestAuthorizer
+	adminPort  int32
+	log        logr.Logger
+}
+
+func (h *proxyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	namespace, podName, endpoint, err := parseProxyPath(req.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	params, ok := allowedQueryParams[endpoint]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported Envoy admin endpoint %q, must be one of %s", endpoint, strings.Join(allowedEndpoints(), ", ")), http.StatusNotFound)
+		return
+	}
+	if _, code, err := h.authorizer.Authorize(req.Context(), req, k8s.AccessAttributes{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        "get",
+			Resource:    "pods",
+			Subresource: "proxy",
+			Name:        podName,
+		},
+		Description: fmt.Sprintf("get pods/proxy of pod %s/%s", namespace, podName),
+	}); err != nil {
+		if code == http.StatusInternalServerError {
+			h.log.Error(err, "failed to authorize Envoy admin query", "pod", namespace+"/"+podName)
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	query := make(map[string]string)
+	for _, param := range params {
+		if value := req.URL.Query().Get(param); len(value) != 0 {
+			query[param] = value
+		}
+	}
+	stream, err := h.clientSet.CoreV1().Pods(namespace).ProxyGet("http", podName, strconv.Itoa(int(h.adminPort)), endpoint, query).Stream(req.Context())
+	if err != nil {
+		code := http.StatusBadGateway
+		if status, ok := err.(apierrors.APIStatus); ok && status.Status().Code != 0 {
+			code = int(status.Status().Code)
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	defer stream.Close()
+	w.Header().Set("Content-Type", contentType(endpoint, query))
+	if _, err := io.Copy(w, stream); err != nil {
+		h.log.Error(err, "failed to write Envoy admin response", "pod", namespace+"/"+podName, "endpoint", endpoint)
+	}
+}
+
+// parseProxyPath parses the namespace, pod name and Envoy admin endpoint from path of the form ProxyPath<namespace>/<pod>/<endpoint>.
+func parseProxyPath(path string) (string, string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(path, ProxyPath), "/")
+	if !strings.HasPrefix(path, ProxyPath) || len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return "", "", "", errors.Errorf("path must be in the form of %s<namespace>/<pod>/<endpoint>: %s", ProxyPath, path)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func contentType(endpoint string, query map[string]string) string {
+	if endpoint == "config_dump" || endpoint == "server_info" || query["format"] == "json" {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func allowedEndpoints() []string {
+	endpoints := make([]string, 0, len(allowedQueryParams))
+	for endpoint := range allowedQueryParams {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
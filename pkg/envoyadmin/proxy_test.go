@@ -0,0 +1,166 @@
+package envoyadmin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type proxyGet struct {
+	namespace string
+	scheme    string
+	name      string
+	port      string
+	path      string
+	params    map[string]string
+}
+
+type fakeResponseWrapper struct {
+	body string
+}
+
+func (r *fakeResponseWrapper) DoRaw(ctx context.Context) ([]byte, error) {
+	return []byte(r.body), nil
+}
+
+func (r *fakeResponseWrapper) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(r.body)), nil
+}
+
+func Test_proxyHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		target          string
+		token           string
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+		wantProxyGet    *proxyGet
+	}{
+		{
+			name:            "proxies stats subset",
+			method:          http.MethodGet,
+			target:          "/debug/envoy/app-ns/app-pod/stats?filter=cluster.cds_&format=json&unsupported=value",
+			token:           "operator-token",
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"stats":[]}`,
+			wantContentType: "application/json",
+			wantProxyGet: &proxyGet{
+				namespace: "app-ns",
+				scheme:    "http",
+				name:      "app-pod",
+				port:      "9901",
+				path:      "stats",
+				params:    map[string]string{"filter": "cluster.cds_", "format": "json"},
+			},
+		},
+		{
+			name:       "token without access to pods/proxy",
+			method:     http.MethodGet,
+			target:     "/debug/envoy/other-ns/app-pod/config_dump",
+			token:      "operator-token",
+			wantStatus: http.StatusForbidden,
+			wantBody:   "operator is not allowed to get pods/proxy of pod other-ns/app-pod\n",
+		},
+		{
+			name:       "invalid token",
+			method:     http.MethodGet,
+			target:     "/debug/envoy/app-ns/app-pod/config_dump",
+			token:      "other-token",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "invalid bearer token\n",
+		},
+		{
+			name:       "missing token",
+			method:     http.MethodGet,
+			target:     "/debug/envoy/app-ns/app-pod/config_dump",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "bearer token is required\n",
+		},
+		{
+			name:       "unsupported endpoint",
+			method:     http.MethodGet,
+			target:     "/debug/envoy/app-ns/app-pod/quitquitquit",
+			token:      "operator-token",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "unsupported Envoy admin endpoint \"quitquitquit\", must be one of clusters, config_dump, listeners, ready, server_info, stats\n",
+		},
+		{
+			name:       "invalid path",
+			method:     http.MethodGet,
+			target:     "/debug/envoy/app-ns/stats",
+			token:      "operator-token",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "path must be in the form of /debug/envoy/<namespace>/<pod>/<endpoint>: /debug/envoy/app-ns/stats\n",
+		},
+		{
+			name:       "unsupported method",
+			method:     http.MethodPost,
+			target:     "/debug/envoy/app-ns/app-pod/stats",
+			token:      "operator-token",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "only GET is supported\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := fake.NewSimpleClientset()
+			clientSet.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				tokenReview := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				if tokenReview.Spec.Token == "operator-token" {
+					tokenReview.Status.Authenticated = true
+					tokenReview.Status.User = authenticationv1.UserInfo{Username: "operator", Groups: []string{"system:authenticated"}}
+				}
+				return true, tokenReview, nil
+			})
+			clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				accessReview := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				attrs := accessReview.Spec.ResourceAttributes
+				accessReview.Status.Allowed = accessReview.Spec.User == "operator" && attrs.Namespace == "app-ns" &&
+					attrs.Verb == "get" && attrs.Resource == "pods" && attrs.Subresource == "proxy"
+				return true, accessReview, nil
+			})
+			var gotProxyGet *proxyGet
+			clientSet.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+				proxyAction := action.(k8stesting.ProxyGetAction)
+				gotProxyGet = &proxyGet{
+					namespace: proxyAction.GetNamespace(),
+					scheme:    proxyAction.GetScheme(),
+					name:      proxyAction.GetName(),
+					port:      proxyAction.GetPort(),
+					path:      proxyAction.GetPath(),
+					params:    proxyAction.GetParams(),
+				}
+				return true, &fakeResponseWrapper{body: `{"stats":[]}`}, nil
+			})
+
+			h := NewProxyHandler(clientSet, 9901, logr.New(&log.NullLogSink{}))
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if len(tt.token) != 0 {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantBody, recorder.Body.String())
+			if len(tt.wantContentType) != 0 {
+				assert.Equal(t, tt.wantContentType, recorder.Header().Get("Content-Type"))
+			}
+			assert.Equal(t, tt.wantProxyGet, gotProxyGet)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package equality
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	routeProtocolHTTP  = "http"
+	routeProtocolHTTP2 = "http2"
+	routeProtocolGRPC  = "grpc"
+	routeProtocolTCP   = "tcp"
+)
+
+// routeTimeoutDefault is the timeout AppMesh applies to routes without one.
+type routeTimeoutDefault struct {
+	perRequest *appmeshsdk.Duration
+	idle       *appmeshsdk.Duration
+}
+
+// routeTimeoutDefaults are AppMesh default route timeouts by route protocol, tcp routes don't have a per request timeout.
+var routeTimeoutDefaults = map[string]routeTimeoutDefault{
+	routeProtocolHTTP:  {perRequest: durationSeconds(15), idle: durationSeconds(300)},
+	routeProtocolHTTP2: {perRequest: durationSeconds(15), idle: durationSeconds(300)},
+	routeProtocolGRPC:  {perRequest: durationSeconds(15), idle: durationSeconds(300)},
+	routeProtocolTCP:   {idle: durationSeconds(3600)},
+}
+
+// CompareOptionForRouteSpecDefaults compares route specs with AppMesh defaults applied to fields unset on either side,
+// so defaults AppMesh returns for fields absent from the desired spec aren't considered as differences.
+func CompareOptionForRouteSpecDefaults() cmp.Option {
+	return cmp.Transformer("WithRouteSpecDefaults", withRouteSpecDefaults)
+}
+
+// withRouteSpecDefaults returns a copy of spec with AppMesh defaults applied to unset fields.
+func withRouteSpecDefaults(spec *appmeshsdk.RouteSpec) *appmeshsdk.RouteSpec {
+	if spec == nil {
+		return nil
+	}
+	spec = awsutil.CopyOf(spec).(*appmeshsdk.RouteSpec)
+	if spec.HttpRoute != nil {
+		applyHTTPRouteDefaults(spec.HttpRoute, routeTimeoutDefaults[routeProtocolHTTP])
+	}
+	if spec.Http2Route != nil {
+		applyHTTPRouteDefaults(spec.Http2Route, routeTimeoutDefaults[routeProtocolHTTP2])
+	}
+	if spec.GrpcRoute != nil {
+		applyGRPCRouteDefaults(spec.GrpcRoute, routeTimeoutDefaults[routeProtocolGRPC])
+	}
+	if spec.TcpRoute != nil {
+		applyTCPRouteDefaults(spec.TcpRoute, routeTimeoutDefaults[routeProtocolTCP])
+	}
+	return spec
+}
+
+func applyHTTPRouteDefaults(route *appmeshsdk.HttpRoute, timeoutDefault routeTimeoutDefault) {
+	if route.Timeout == nil {
+		route.Timeout = &appmeshsdk.HttpTimeout{}
+	}
+	if route.Timeout.PerRequest == nil {
+		route.Timeout.PerRequest = timeoutDefault.perRequest
+	}
+	if route.Timeout.Idle == nil {
+		route.Timeout.Idle = timeoutDefault.idle
+	}
+	if route.Match != nil {
+		for _, header := range route.Match.Headers {
+			if header != nil && header.Invert == nil {
+				header.Invert = aws.Bool(false)
+			}
+		}
+	}
+}
+
+func applyGRPCRouteDefaults(route *appmeshsdk.GrpcRoute, timeoutDefault routeTimeoutDefault) {
+	if route.Timeout == nil {
+		route.Timeout = &appmeshsdk.GrpcTimeout{}
+	}
+	if route.Timeout.PerRequest == nil {
+		route.Timeout.PerRequest = timeoutDefault.perRequest
+	}
+	if route.Timeout.Idle == nil {
+		route.Timeout.Idle = timeoutDefault.idle
+	}
+	if route.Match != nil {
+		for _, metadata := range route.Match.Metadata {
+			if metadata != nil && metadata.Invert == nil {
+				metadata.Invert = aws.Bool(false)
+			}
+		}
+	}
+}
+
+func applyTCPRouteDefaults(route *appmeshsdk.TcpRoute, timeoutDefault routeTimeoutDefault) {
+	if route.Timeout == nil {
+		route.Timeout = &appmeshsdk.TcpTimeout{}
+	}
+	if route.Timeout.Idle == nil {
+		route.Timeout.Idle = timeoutDefault.idle
+	}
+}
+
+func durationSeconds(value int64) *appmeshsdk.Duration {
+	return &appmeshsdk.Duration{Unit: aws.String(appmeshsdk.DurationUnitS), Value: aws.Int64(value)}
+}
@@ -0,0 +1,160 @@
+package equality
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_withRouteSpecDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *appmeshsdk.RouteSpec
+		want *appmeshsdk.RouteSpec
+	}{
+		{
+			name: "nil spec",
+			spec: nil,
+			want: nil,
+		},
+		{
+			name: "http route without timeout",
+			spec: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{
+					Match: &appmeshsdk.HttpRouteMatch{
+						Prefix:  aws.String("/"),
+						Headers: []*appmeshsdk.HttpRouteHeader{{Name: aws.String("x-canary")}},
+					},
+				},
+			},
+			want: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{
+					Match: &appmeshsdk.HttpRouteMatch{
+						Prefix:  aws.String("/"),
+						Headers: []*appmeshsdk.HttpRouteHeader{{Name: aws.String("x-canary"), Invert: aws.Bool(false)}},
+					},
+					Timeout: &appmeshsdk.HttpTimeout{PerRequest: durationSeconds(15), Idle: durationSeconds(300)},
+				},
+			},
+		},
+		{
+			name: "http2 route with per request timeout",
+			spec: &appmeshsdk.RouteSpec{
+				Http2Route: &appmeshsdk.HttpRoute{
+					Timeout: &appmeshsdk.HttpTimeout{
+						PerRequest: &appmeshsdk.Duration{Unit: aws.String("ms"), Value: aws.Int64(500)},
+					},
+				},
+			},
+			want: &appmeshsdk.RouteSpec{
+				Http2Route: &appmeshsdk.HttpRoute{
+					Timeout: &appmeshsdk.HttpTimeout{
+						PerRequest: &appmeshsdk.Duration{Unit: aws.String("ms"), Value: aws.Int64(500)},
+						Idle:       durationSeconds(300),
+					},
+				},
+			},
+		},
+		{
+			name: "grpc route without timeout",
+			spec: &appmeshsdk.RouteSpec{
+				GrpcRoute: &appmeshsdk.GrpcRoute{
+					Match: &appmeshsdk.GrpcRouteMatch{
+						Metadata: []*appmeshsdk.GrpcRouteMetadata{{Name: aws.String("x-canary"), Invert: aws.Bool(true)}},
+					},
+				},
+			},
+			want: &appmeshsdk.RouteSpec{
+				GrpcRoute: &appmeshsdk.GrpcRoute{
+					Match: &appmeshsdk.GrpcRouteMatch{
+						Metadata: []*appmeshsdk.GrpcRouteMetadata{{Name: aws.String("x-canary"), Invert: aws.Bool(true)}},
+					},
+					Timeout: &appmeshsdk.GrpcTimeout{PerRequest: durationSeconds(15), Idle: durationSeconds(300)},
+				},
+			},
+		},
+		{
+			name: "tcp route without timeout",
+			spec: &appmeshsdk.RouteSpec{
+				TcpRoute: &appmeshsdk.TcpRoute{},
+			},
+			want: &appmeshsdk.RouteSpec{
+				TcpRoute: &appmeshsdk.TcpRoute{
+					Timeout: &appmeshsdk.TcpTimeout{Idle: durationSeconds(3600)},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withRouteSpecDefaults(tt.spec)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareOptionForRouteSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		argLeft    *appmeshsdk.RouteSpec
+		argRight   *appmeshsdk.RouteSpec
+		wantEquals bool
+	}{
+		{
+			name: "when actual has default timeout absent from desired",
+			argLeft: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{},
+			},
+			argRight: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{
+					Timeout: &appmeshsdk.HttpTimeout{PerRequest: durationSeconds(15), Idle: durationSeconds(300)},
+				},
+			},
+			wantEquals: true,
+		},
+		{
+			name: "when actual has timeout other than default",
+			argLeft: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{},
+			},
+			argRight: &appmeshsdk.RouteSpec{
+				HttpRoute: &appmeshsdk.HttpRoute{
+					Timeout: &appmeshsdk.HttpTimeout{PerRequest: durationSeconds(30)},
+				},
+			},
+			wantEquals: false,
+		},
+		{
+			name: "when desired has default timeout absent from actual",
+			argLeft: &appmeshsdk.RouteSpec{
+				TcpRoute: &appmeshsdk.TcpRoute{
+					Timeout: &appmeshsdk.TcpTimeout{Idle: durationSeconds(3600)},
+				},
+			},
+			argRight: &appmeshsdk.RouteSpec{
+				TcpRoute: &appmeshsdk.TcpRoute{},
+			},
+			wantEquals: true,
+		},
+		{
+			name: "when neither has timeout",
+			argLeft: &appmeshsdk.RouteSpec{
+				GrpcRoute: &appmeshsdk.GrpcRoute{},
+			},
+			argRight: &appmeshsdk.RouteSpec{
+				GrpcRoute: &appmeshsdk.GrpcRoute{},
+			},
+			wantEquals: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CompareOptionForRouteSpec()
+			gotEquals := cmp.Equal(tt.argLeft, tt.argRight, opts)
+			assert.Equal(t, tt.wantEquals, gotEquals)
+		})
+	}
+}
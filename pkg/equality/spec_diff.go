@@ -0,0 +1,25 @@
+package equality
+
+import (
+	"github.com/google/go-cmp/cmp"
+)
+
+// SpecDiff is the difference between the desired and actual spec of an AppMesh resource.
+type SpecDiff struct {
+	// Resource identifies the AppMesh resource, e.g. virtualRouter/my-router or route/my-route.
+	Resource string
+	// Exists tells whether the AppMesh resource exists.
+	Exists bool
+	// Diff is the diff from desired to actual spec, empty if they're equal.
+	Diff string
+}
+
+// DiffSpec compares the desired and actual spec of resource with opts, the same way reconciles decide whether to update it.
+// actual is nil if the resource doesn't exist.
+func DiffSpec(resource string, desired interface{}, actual interface{}, exists bool, opts cmp.Option) SpecDiff {
+	specDiff := SpecDiff{Resource: resource, Exists: exists}
+	if !exists || !cmp.Equal(desired, actual, opts) {
+		specDiff.Diff = cmp.Diff(desired, actual, opts)
+	}
+	return specDiff
+}
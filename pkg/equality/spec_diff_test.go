@@ -0,0 +1,33 @@
+package equality
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSpec(t *testing.T) {
+	desired := &appmeshsdk.VirtualServiceSpec{
+		Provider: &appmeshsdk.VirtualServiceProvider{
+			VirtualRouter: &appmeshsdk.VirtualRouterServiceProvider{VirtualRouterName: aws.String("my-router")},
+		},
+	}
+
+	specDiff := DiffSpec("virtualService/my-svc", desired, desired, true, CompareOptionForVirtualServiceSpec())
+	assert.Equal(t, SpecDiff{Resource: "virtualService/my-svc", Exists: true}, specDiff)
+
+	actual := &appmeshsdk.VirtualServiceSpec{
+		Provider: &appmeshsdk.VirtualServiceProvider{
+			VirtualRouter: &appmeshsdk.VirtualRouterServiceProvider{VirtualRouterName: aws.String("other-router")},
+		},
+	}
+	specDiff = DiffSpec("virtualService/my-svc", desired, actual, true, CompareOptionForVirtualServiceSpec())
+	assert.Contains(t, specDiff.Diff, "my-router")
+	assert.Contains(t, specDiff.Diff, "other-router")
+
+	specDiff = DiffSpec("virtualService/my-svc", desired, (*appmeshsdk.VirtualServiceSpec)(nil), false, CompareOptionForVirtualServiceSpec())
+	assert.False(t, specDiff.Exists)
+	assert.NotEmpty(t, specDiff.Diff)
+}
@@ -1,6 +1,7 @@
 package equality
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -10,9 +11,24 @@ func CompareOptionForHealthCheckPolicy() cmp.Option {
 	return IgnoreLeftHandUnset(appmeshsdk.HealthCheckPolicy{}, "Port")
 }
 
+// CompareOptionForListeners compares listeners regardless of their order, as each listener is identified by its port.
+func CompareOptionForListeners() cmp.Option {
+	return cmpopts.SortSlices(func(lhs *appmeshsdk.Listener, rhs *appmeshsdk.Listener) bool {
+		return listenerPort(lhs) < listenerPort(rhs)
+	})
+}
+
 func CompareOptionForVirtualNodeSpec() cmp.Option {
 	return cmp.Options{
 		cmpopts.EquateEmpty(),
 		CompareOptionForHealthCheckPolicy(),
+		CompareOptionForListeners(),
+	}
+}
+
+func listenerPort(listener *appmeshsdk.Listener) int64 {
+	if listener == nil || listener.PortMapping == nil {
+		return 0
 	}
+	return aws.Int64Value(listener.PortMapping.Port)
 }
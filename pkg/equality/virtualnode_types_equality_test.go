@@ -140,6 +140,62 @@ func TestCompareOptionForVirtualNodeSpec(t *testing.T) {
 			},
 			wantEquals: false,
 		},
+		{
+			name: "when listeners are in different order",
+			argLeft: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("STRICT")},
+					},
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(9090), Protocol: aws.String("tcp")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("PERMISSIVE")},
+					},
+				},
+			},
+			argRight: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(9090), Protocol: aws.String("tcp")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("PERMISSIVE")},
+					},
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("STRICT")},
+					},
+				},
+			},
+			wantEquals: true,
+		},
+		{
+			name: "when listeners are in different order, but TLS mode of a listener differs",
+			argLeft: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("STRICT")},
+					},
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(9090), Protocol: aws.String("tcp")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("PERMISSIVE")},
+					},
+				},
+			},
+			argRight: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(9090), Protocol: aws.String("tcp")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("STRICT")},
+					},
+					{
+						PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")},
+						Tls:         &appmeshsdk.ListenerTls{Mode: aws.String("STRICT")},
+					},
+				},
+			},
+			wantEquals: false,
+		},
 		{
 			name:       "nil left hand arg",
 			argLeft:    nil,
@@ -0,0 +1,17 @@
+package equality
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func CompareOptionForVirtualRouterSpec() cmp.Option {
+	return cmpopts.EquateEmpty()
+}
+
+func CompareOptionForRouteSpec() cmp.Option {
+	return cmp.Options{
+		cmpopts.EquateEmpty(),
+		CompareOptionForRouteSpecDefaults(),
+	}
+}
@@ -0,0 +1,10 @@
+package equality
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func CompareOptionForVirtualServiceSpec() cmp.Option {
+	return cmpopts.EquateEmpty()
+}
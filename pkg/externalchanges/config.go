@@ -0,0 +1,25 @@
+package externalchanges
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagQueueURL = "external-changes-queue-url"
+)
+
+type Config struct {
+	// QueueURL is the URL of the SQS queue that receives EventBridge events of AppMesh API calls.
+	// watching external changes is disabled if it's empty.
+	QueueURL string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.QueueURL, flagQueueURL, "",
+		"The URL of the SQS queue that receives EventBridge events of AppMesh API calls, used to immediately reconcile resources changed outside of the controller")
+}
+
+// Enabled returns whether watching external changes is enabled.
+func (cfg *Config) Enabled() bool {
+	return len(cfg.QueueURL) != 0
+}
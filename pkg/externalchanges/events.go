@@ -0,0 +1,96 @@
+package externalchanges
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/pkg/errors"
+)
+
+const (
+	cloudTrailEventDetailType = "AWS API Call via CloudTrail"
+	appMeshEventSource        = "appmesh.amazonaws.com"
+)
+
+// kinds of AppMesh CRDs that are enqueued upon external changes.
+const (
+	KindMesh           = "Mesh"
+	KindVirtualGateway = "VirtualGateway"
+	KindGatewayRoute   = "GatewayRoute"
+	KindVirtualNode    = "VirtualNode"
+	KindVirtualService = "VirtualService"
+	KindVirtualRouter  = "VirtualRouter"
+)
+
+// cloudTrailEvent is an EventBridge event of an AWS API call recorded by CloudTrail.
+// see https://docs.aws.amazon.com/eventbridge/latest/userguide/eb-service-event.html#eb-service-event-cloudtrail
+type cloudTrailEvent struct {
+	DetailType string                `json:"detail-type"`
+	Detail     cloudTrailEventDetail `json:"detail"`
+}
+
+type cloudTrailEventDetail struct {
+	EventSource       string                   `json:"eventSource"`
+	EventName         string                   `json:"eventName"`
+	UserAgent         string                   `json:"userAgent"`
+	ErrorCode         string                   `json:"errorCode"`
+	RequestParameters appMeshRequestParameters `json:"requestParameters"`
+}
+
+// appMeshRequestParameters are the parameters identifying the AppMesh resource of an AppMesh API call.
+type appMeshRequestParameters struct {
+	MeshName           string `json:"meshName"`
+	VirtualGatewayName string `json:"virtualGatewayName"`
+	GatewayRouteName   string `json:"gatewayRouteName"`
+	VirtualNodeName    string `json:"virtualNodeName"`
+	VirtualServiceName string `json:"virtualServiceName"`
+	VirtualRouterName  string `json:"virtualRouterName"`
+	RouteName          string `json:"routeName"`
+}
+
+// changedResource identifies the AppMesh resource that is managed by a CRD, and was changed by an AppMesh API call.
+type changedResource struct {
+	// Kind of the CRD managing the resource.
+	Kind string
+	// ResourcePath is the resource part of the ARN, e.g. "mesh/my-mesh/virtualNode/my-node".
+	ResourcePath string
+}
+
+// parseChangedResource parses the resource changed by an AppMesh API call from EventBridge event body.
+// returns nil if the event isn't of a successful mutating AppMesh API call, or the call is made by this controller itself.
+func parseChangedResource(body string) (*changedResource, error) {
+	event := cloudTrailEvent{}
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		return nil, errors.Wrap(err, "failed to decode EventBridge event")
+	}
+	if event.DetailType != cloudTrailEventDetailType || event.Detail.EventSource != appMeshEventSource || len(event.Detail.ErrorCode) != 0 {
+		return nil, nil
+	}
+	if aws.IsControllerUserAgent(event.Detail.UserAgent) {
+		return nil, nil
+	}
+	params := event.Detail.RequestParameters
+	if len(params.MeshName) == 0 {
+		return nil, nil
+	}
+	meshPath := fmt.Sprintf("mesh/%s", params.MeshName)
+	switch event.Detail.EventName {
+	case "CreateMesh", "UpdateMesh", "DeleteMesh":
+		return &changedResource{Kind: KindMesh, ResourcePath: meshPath}, nil
+	case "CreateVirtualGateway", "UpdateVirtualGateway", "DeleteVirtualGateway":
+		return &changedResource{Kind: KindVirtualGateway, ResourcePath: fmt.Sprintf("%s/virtualGateway/%s", meshPath, params.VirtualGatewayName)}, nil
+	case "CreateGatewayRoute", "UpdateGatewayRoute", "DeleteGatewayRoute":
+		return &changedResource{Kind: KindGatewayRoute, ResourcePath: fmt.Sprintf("%s/virtualGateway/%s/gatewayRoute/%s", meshPath, params.VirtualGatewayName, params.GatewayRouteName)}, nil
+	case "CreateVirtualNode", "UpdateVirtualNode", "DeleteVirtualNode":
+		return &changedResource{Kind: KindVirtualNode, ResourcePath: fmt.Sprintf("%s/virtualNode/%s", meshPath, params.VirtualNodeName)}, nil
+	case "CreateVirtualService", "UpdateVirtualService", "DeleteVirtualService":
+		return &changedResource{Kind: KindVirtualService, ResourcePath: fmt.Sprintf("%s/virtualService/%s", meshPath, params.VirtualServiceName)}, nil
+	case "CreateVirtualRouter", "UpdateVirtualRouter", "DeleteVirtualRouter":
+		return &changedResource{Kind: KindVirtualRouter, ResourcePath: fmt.Sprintf("%s/virtualRouter/%s", meshPath, params.VirtualRouterName)}, nil
+	case "CreateRoute", "UpdateRoute", "DeleteRoute":
+		// routes are managed by the VirtualRouter CRD.
+		return &changedResource{Kind: KindVirtualRouter, ResourcePath: fmt.Sprintf("%s/virtualRouter/%s", meshPath, params.VirtualRouterName)}, nil
+	}
+	return nil, nil
+}
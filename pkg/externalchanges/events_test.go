@@ -0,0 +1,73 @@
+package externalchanges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseChangedResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *changedResource
+		wantErr bool
+	}{
+		{
+			name: "UpdateVirtualNode",
+			body: `{"detail-type":"AWS API Call via CloudTrail","source":"aws.appmesh","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateVirtualNode","userAgent":"aws-cli/2.13.0","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			want: &changedResource{Kind: KindVirtualNode, ResourcePath: "mesh/my-mesh/virtualNode/my-node_my-ns"},
+		},
+		{
+			name: "DeleteMesh",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"DeleteMesh","requestParameters":{"meshName":"my-mesh"}}}`,
+			want: &changedResource{Kind: KindMesh, ResourcePath: "mesh/my-mesh"},
+		},
+		{
+			name: "UpdateRoute enqueues VirtualRouter",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateRoute","requestParameters":{"meshName":"my-mesh","virtualRouterName":"my-router_my-ns","routeName":"route-1"}}}`,
+			want: &changedResource{Kind: KindVirtualRouter, ResourcePath: "mesh/my-mesh/virtualRouter/my-router_my-ns"},
+		},
+		{
+			name: "CreateGatewayRoute",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"CreateGatewayRoute","requestParameters":{"meshName":"my-mesh","virtualGatewayName":"my-gw_my-ns","gatewayRouteName":"my-route_my-ns"}}}`,
+			want: &changedResource{Kind: KindGatewayRoute, ResourcePath: "mesh/my-mesh/virtualGateway/my-gw_my-ns/gatewayRoute/my-route_my-ns"},
+		},
+		{
+			name: "call made by controller is ignored",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateVirtualNode","userAgent":"aws-sdk-go/1.44.252 (go1.20; linux; amd64) appmesh.k8s.aws/v1.13.0","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			want: nil,
+		},
+		{
+			name: "failed call is ignored",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateVirtualNode","errorCode":"BadRequestException","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			want: nil,
+		},
+		{
+			name: "read-only call is ignored",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"DescribeVirtualNode","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			want: nil,
+		},
+		{
+			name: "call of other service is ignored",
+			body: `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"servicediscovery.amazonaws.com","eventName":"RegisterInstance","requestParameters":{}}}`,
+			want: nil,
+		},
+		{
+			name:    "malformed event",
+			body:    `not json`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChangedResource(tt.body)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
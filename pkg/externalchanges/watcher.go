@@ -0,0 +1,213 @@
+package externalchanges
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// maximum number of messages to receive per SQS call, capped by SQS at 10.
+	receiveMaxNumberOfMessages = 10
+	// long polling wait time per SQS call, capped by SQS at 20 seconds.
+	receiveWaitTimeSeconds = 20
+	// how long to wait before retrying after failing to receive messages.
+	receiveRetryInterval = 10 * time.Second
+)
+
+// Watcher enqueues AppMesh CRDs upon changes made to their AppMesh resources outside of the controller.
+// Changes are received as EventBridge events of AppMesh API calls recorded by CloudTrail, delivered to a SQS queue.
+type Watcher interface {
+	manager.Runnable
+
+	// Source returns the source of events for CRDs of kind, to be watched by the controller of kind.
+	// the source never emits events if watching external changes is disabled.
+	Source(kind string) source.Source
+}
+
+// NewWatcher constructs new Watcher.
+// appMeshCache is invalidated for changed AppMesh resources, so reconciles don't observe stale responses.
+func NewWatcher(cfg Config, sqsSDK services.SQS, appMeshCache services.AppMeshCache, k8sClient client.Client, log logr.Logger) Watcher {
+	eventChansByKind := make(map[string]chan event.GenericEvent)
+	for _, kind := range []string{KindMesh, KindVirtualGateway, KindGatewayRoute, KindVirtualNode, KindVirtualService, KindVirtualRouter} {
+		eventChansByKind[kind] = make(chan event.GenericEvent)
+	}
+	return &watcher{
+		queueURL:         cfg.QueueURL,
+		sqsSDK:           sqsSDK,
+		appMeshCache:     appMeshCache,
+		k8sClient:        k8sClient,
+		eventChansByKind: eventChansByKind,
+		log:              log,
+	}
+}
+
+var _ Watcher = &watcher{}
+
+type watcher struct {
+	queueURL         string
+	sqsSDK           services.SQS
+	appMeshCache     services.AppMeshCache
+	k8sClient        client.Client
+	eventChansByKind map[string]chan event.GenericEvent
+	log              logr.Logger
+}
+
+func (w *watcher) Source(kind string) source.Source {
+	return &source.Channel{Source: w.eventChansByKind[kind]}
+}
+
+func (w *watcher) Start(ctx context.Context) error {
+	w.log.Info("watching external changes", "queueURL", w.queueURL)
+	for ctx.Err() == nil {
+		resp, err := w.sqsSDK.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(w.queueURL),
+			MaxNumberOfMessages: aws.Int64(receiveMaxNumberOfMessages),
+			WaitTimeSeconds:     aws.Int64(receiveWaitTimeSeconds),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			w.log.Error(err, "failed to receive external change events")
+			select {
+			case <-ctx.Done():
+			case <-time.After(receiveRetryInterval):
+			}
+			continue
+		}
+		for _, msg := range resp.Messages {
+			w.handleMessage(ctx, msg)
+		}
+	}
+	return nil
+}
+
+// handleMessage enqueues the CRD changed by msg, and deletes msg from queue.
+// msg is deleted even if it cannot be handled, changes it carries will be corrected by periodic resync anyway.
+func (w *watcher) handleMessage(ctx context.Context, msg *sqs.Message) {
+	if err := w.enqueueChangedResource(ctx, aws.StringValue(msg.Body)); err != nil {
+		w.log.Error(err, "failed to handle external change event", "messageID", aws.StringValue(msg.MessageId))
+	}
+	if _, err := w.sqsSDK.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(w.queueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	}); err != nil {
+		w.log.Error(err, "failed to delete external change event", "messageID", aws.StringValue(msg.MessageId))
+	}
+}
+
+func (w *watcher) enqueueChangedResource(ctx context.Context, body string) error {
+	changed, err := parseChangedResource(body)
+	if err != nil {
+		return err
+	}
+	if changed == nil {
+		return nil
+	}
+	w.appMeshCache.InvalidateResourcePath(changed.ResourcePath)
+	objs, err := w.findObjectsByResourcePath(ctx, changed.Kind, changed.ResourcePath)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		w.log.V(1).Info("enqueue for external change", "kind", changed.Kind, "object", k8s.NamespacedName(obj))
+		select {
+		case w.eventChansByKind[changed.Kind] <- event.GenericEvent{Object: obj}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// findObjectsByResourcePath finds CRDs of kind, whose AppMesh resource's ARN has resourcePath as resource.
+func (w *watcher) findObjectsByResourcePath(ctx context.Context, kind string, resourcePath string) ([]client.Object, error) {
+	var candidates []client.Object
+	var arns []*string
+	switch kind {
+	case KindMesh:
+		list := &appmesh.MeshList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.MeshARN)
+		}
+	case KindVirtualGateway:
+		list := &appmesh.VirtualGatewayList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.VirtualGatewayARN)
+		}
+	case KindGatewayRoute:
+		list := &appmesh.GatewayRouteList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.GatewayRouteARN)
+		}
+	case KindVirtualNode:
+		list := &appmesh.VirtualNodeList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.VirtualNodeARN)
+		}
+	case KindVirtualService:
+		list := &appmesh.VirtualServiceList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.VirtualServiceARN)
+		}
+	case KindVirtualRouter:
+		list := &appmesh.VirtualRouterList{}
+		if err := w.k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, &list.Items[i])
+			arns = append(arns, list.Items[i].Status.VirtualRouterARN)
+		}
+	default:
+		return nil, errors.Errorf("unsupported kind: %s", kind)
+	}
+
+	var objs []client.Object
+	for i, obj := range candidates {
+		if arns[i] == nil {
+			continue
+		}
+		parsedARN, err := arn.Parse(aws.StringValue(arns[i]))
+		if err != nil {
+			continue
+		}
+		if parsedARN.Resource == resourcePath {
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
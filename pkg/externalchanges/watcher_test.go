@@ -0,0 +1,105 @@
+package externalchanges
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeSQS records deleted messages.
+type fakeSQS struct {
+	services.SQS
+	deletedReceiptHandles []string
+}
+
+func (s *fakeSQS) DeleteMessageWithContext(_ aws.Context, input *sqs.DeleteMessageInput, _ ...request.Option) (*sqs.DeleteMessageOutput, error) {
+	s.deletedReceiptHandles = append(s.deletedReceiptHandles, aws.StringValue(input.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func Test_watcher_handleMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		vns         []*appmesh.VirtualNode
+		body        string
+		wantEnqueue []string
+	}{
+		{
+			name: "enqueue virtualNode changed externally",
+			vns: []*appmesh.VirtualNode{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-node"},
+					Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/my-node_my-ns")},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "other-node"},
+					Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/other-node_my-ns")},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pending-node"},
+				},
+			},
+			body:        `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateVirtualNode","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			wantEnqueue: []string{"my-ns/my-node"},
+		},
+		{
+			name: "resource not managed by any virtualNode",
+			vns: []*appmesh.VirtualNode{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "other-node"},
+					Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/other-node_my-ns")},
+				},
+			},
+			body:        `{"detail-type":"AWS API Call via CloudTrail","detail":{"eventSource":"appmesh.amazonaws.com","eventName":"UpdateVirtualNode","requestParameters":{"meshName":"my-mesh","virtualNodeName":"my-node_my-ns"}}}`,
+			wantEnqueue: nil,
+		},
+		{
+			name:        "malformed event is deleted",
+			body:        `not json`,
+			wantEnqueue: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range tt.vns {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+			sqsSDK := &fakeSQS{}
+			w := NewWatcher(Config{QueueURL: "https://sqs.us-west-2.amazonaws.com/000000000000/appmesh-changes"}, sqsSDK, services.NewNoopAppMeshCache(), k8sClient, logr.New(&log.NullLogSink{})).(*watcher)
+			// buffer events so handleMessage doesn't block without a controller consuming them.
+			w.eventChansByKind[KindVirtualNode] = make(chan event.GenericEvent, 10)
+
+			w.handleMessage(ctx, &sqs.Message{
+				MessageId:     aws.String("msg-1"),
+				ReceiptHandle: aws.String("receipt-1"),
+				Body:          aws.String(tt.body),
+			})
+			close(w.eventChansByKind[KindVirtualNode])
+			var gotEnqueue []string
+			for e := range w.eventChansByKind[KindVirtualNode] {
+				gotEnqueue = append(gotEnqueue, client.ObjectKeyFromObject(e.Object).String())
+			}
+			assert.Equal(t, tt.wantEnqueue, gotEnqueue)
+			assert.Equal(t, []string{"receipt-1"}, sqsSDK.deletedReceiptHandles)
+		})
+	}
+}
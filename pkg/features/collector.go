@@ -0,0 +1,79 @@
+package features
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	labelName  = "name"
+	labelStage = "stage"
+)
+
+// pseudoFeatures are registered by featuregate to toggle all Alpha or Beta features at once, they aren't features of their own.
+var pseudoFeatures = map[featuregate.Feature]bool{
+	"AllAlpha": true,
+	"AllBeta":  true,
+}
+
+// NewCollector constructs new prometheus.Collector that reports whether each feature of gates is enabled.
+func NewCollector(gates featuregate.MutableFeatureGate) prometheus.Collector {
+	return &collector{
+		gates: gates,
+		featureEnabledDesc: prometheus.NewDesc(
+			"appmesh_feature_enabled",
+			"Whether a feature of the controller is enabled by feature gates",
+			[]string{labelName, labelStage}, nil,
+		),
+	}
+}
+
+var _ prometheus.Collector = &collector{}
+
+type collector struct {
+	gates              featuregate.MutableFeatureGate
+	featureEnabledDesc *prometheus.Desc
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.featureEnabledDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for _, feature := range KnownFeatures(c.gates) {
+		spec := c.gates.GetAll()[feature]
+		value := 0.0
+		if c.gates.Enabled(feature) {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.featureEnabledDesc, prometheus.GaugeValue, value, string(feature), stageOf(spec))
+	}
+}
+
+// KnownFeatures returns the features of gates sorted by name.
+func KnownFeatures(gates featuregate.MutableFeatureGate) []featuregate.Feature {
+	var features []featuregate.Feature
+	for feature := range gates.GetAll() {
+		if !pseudoFeatures[feature] {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// stageOf returns the lowercase stage of feature, e.g. alpha.
+func stageOf(spec featuregate.FeatureSpec) string {
+	switch spec.PreRelease {
+	case featuregate.Alpha:
+		return "alpha"
+	case featuregate.Beta:
+		return "beta"
+	case featuregate.Deprecated:
+		return "deprecated"
+	default:
+		return "ga"
+	}
+}
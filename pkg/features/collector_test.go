@@ -0,0 +1,36 @@
+package features
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/featuregate"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	gates := NewFeatureGates()
+	assert.NoError(t, gates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
+		"PreviewFeature": {Default: false, PreRelease: featuregate.Alpha},
+		"StableFeature":  {Default: true, PreRelease: featuregate.GA},
+	}))
+	assert.NoError(t, gates.Set("PreviewFeature=true"))
+
+	err := testutil.CollectAndCompare(NewCollector(gates), strings.NewReader(`
+# HELP appmesh_feature_enabled Whether a feature of the controller is enabled by feature gates
+# TYPE appmesh_feature_enabled gauge
+appmesh_feature_enabled{name="MeshPolicies",stage="alpha"} 0
+appmesh_feature_enabled{name="PreviewFeature",stage="alpha"} 1
+appmesh_feature_enabled{name="StableFeature",stage="ga"} 1
+`))
+	assert.NoError(t, err)
+}
+
+func TestNewFeatureGates(t *testing.T) {
+	gates := NewFeatureGates()
+	for _, feature := range KnownFeatures(gates) {
+		assert.Equal(t, defaultFeatureGates[feature].Default, gates.Enabled(feature), feature)
+	}
+	assert.Error(t, gates.Set("UnknownFeature=true"))
+}
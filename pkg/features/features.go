@@ -0,0 +1,30 @@
+package features
+
+import (
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+// defaultFeatureGates are specs of all features of the controller toggled by --feature-gates.
+// Preview AppMesh capabilities are added as Alpha features, which are disabled by default,
+// and promoted to Beta and GA along with their AppMesh APIs. Each feature is declared as a constant of this package, e.g.
+//
+//	// MyFeature enables ...
+//	MyFeature featuregate.Feature = "MyFeature"
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	MeshPolicies: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+const (
+	// MeshPolicies enables MeshPolicy CRDs, which default retry policies, timeouts, outlier detection and connection pools
+	// of routes and virtualNode listeners in their mesh.
+	MeshPolicies featuregate.Feature = "MeshPolicies"
+)
+
+// NewFeatureGates constructs new feature gates of the controller's features, set to their defaults.
+// Controllers and webhooks check whether features are enabled via the returned gates once flags are parsed.
+func NewFeatureGates() featuregate.MutableFeatureGate {
+	gates := featuregate.NewFeatureGate()
+	utilruntime.Must(gates.Add(defaultFeatureGates))
+	return gates
+}
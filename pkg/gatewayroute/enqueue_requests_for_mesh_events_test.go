@@ -8,7 +8,6 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -77,10 +76,10 @@ func Test_enqueueRequestsForMeshEvents_Update(t *testing.T) {
 							UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
 						},
 						Status: appmesh.MeshStatus{
-							Conditions: []appmesh.MeshCondition{
+							Conditions: []metav1.Condition{
 								{
 									Type:   appmesh.MeshActive,
-									Status: corev1.ConditionFalse,
+									Status: metav1.ConditionFalse,
 								},
 							},
 						},
@@ -108,10 +107,10 @@ func Test_enqueueRequestsForMeshEvents_Update(t *testing.T) {
 							UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
 						},
 						Status: appmesh.MeshStatus{
-							Conditions: []appmesh.MeshCondition{
+							Conditions: []metav1.Condition{
 								{
 									Type:   appmesh.MeshActive,
-									Status: corev1.ConditionTrue,
+									Status: metav1.ConditionTrue,
 								},
 							},
 						},
@@ -168,10 +167,10 @@ func Test_enqueueRequestsForMeshEvents_enqueueGatewayRoutesForMesh(t *testing.T)
 			UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
 		},
 		Status: appmesh.MeshStatus{
-			Conditions: []appmesh.MeshCondition{
+			Conditions: []metav1.Condition{
 				{
 					Type:   appmesh.MeshActive,
-					Status: corev1.ConditionTrue,
+					Status: metav1.ConditionTrue,
 				},
 			},
 		},
@@ -9,7 +9,6 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -82,10 +81,10 @@ func Test_enqueueRequestsForVirtualGatewayEvents_Update(t *testing.T) {
 							Namespace: "vg-ns",
 						},
 						Status: appmesh.VirtualGatewayStatus{
-							Conditions: []appmesh.VirtualGatewayCondition{
+							Conditions: []metav1.Condition{
 								{
 									Type:   appmesh.VirtualGatewayActive,
-									Status: corev1.ConditionFalse,
+									Status: metav1.ConditionFalse,
 								},
 							},
 						},
@@ -115,10 +114,10 @@ func Test_enqueueRequestsForVirtualGatewayEvents_Update(t *testing.T) {
 							Namespace: "vg-ns",
 						},
 						Status: appmesh.VirtualGatewayStatus{
-							Conditions: []appmesh.VirtualGatewayCondition{
+							Conditions: []metav1.Condition{
 								{
 									Type:   appmesh.VirtualGatewayActive,
-									Status: corev1.ConditionTrue,
+									Status: metav1.ConditionTrue,
 								},
 							},
 						},
@@ -176,10 +175,10 @@ func Test_enqueueRequestsForVirtualGatewayEvents_enqueueGatewayRoutesForVirtualG
 			Namespace: "vg-ns",
 		},
 		Status: appmesh.VirtualGatewayStatus{
-			Conditions: []appmesh.VirtualGatewayCondition{
+			Conditions: []metav1.Condition{
 				{
 					Type:   appmesh.VirtualGatewayActive,
-					Status: corev1.ConditionTrue,
+					Status: metav1.ConditionTrue,
 				},
 			},
 		},
@@ -0,0 +1,73 @@
+package gatewayroute
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func NewEnqueueRequestsForVirtualServiceEvents(referencesIndexer references.ObjectReferenceIndexer, log logr.Logger) *enqueueRequestsForVirtualServiceEvents {
+	return &enqueueRequestsForVirtualServiceEvents{
+		referencesIndexer: referencesIndexer,
+		log:               log,
+	}
+}
+
+var _ handler.EventHandler = (*enqueueRequestsForVirtualServiceEvents)(nil)
+
+type enqueueRequestsForVirtualServiceEvents struct {
+	referencesIndexer references.ObjectReferenceIndexer
+	log               logr.Logger
+}
+
+// Create is called in response to an create event
+func (h *enqueueRequestsForVirtualServiceEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	// gatewayRoutes targeting a virtualService that's not found yet wait for its creation.
+	vs := e.Object.(*appmesh.VirtualService)
+	h.enqueueGatewayRoutesForVirtualService(context.Background(), queue, vs)
+}
+
+// Update is called in response to an update event
+func (h *enqueueRequestsForVirtualServiceEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	// gatewayRoute reconcile depends on virtualService is active or not.
+	// so we only need to trigger gatewayRoute reconcile if virtualService's active status changed.
+	vsOld := e.ObjectOld.(*appmesh.VirtualService)
+	vsNew := e.ObjectNew.(*appmesh.VirtualService)
+
+	if virtualservice.IsVirtualServiceActive(vsOld) != virtualservice.IsVirtualServiceActive(vsNew) {
+		h.enqueueGatewayRoutesForVirtualService(context.Background(), queue, vsNew)
+	}
+}
+
+// Delete is called in response to a delete event
+func (h *enqueueRequestsForVirtualServiceEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	// gatewayRoutes targeting a deleted virtualService report it as unresolved.
+	vs := e.Object.(*appmesh.VirtualService)
+	h.enqueueGatewayRoutesForVirtualService(context.Background(), queue, vs)
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request
+func (h *enqueueRequestsForVirtualServiceEvents) Generic(e event.GenericEvent, queue workqueue.RateLimitingInterface) {
+	// no-op
+}
+
+func (h *enqueueRequestsForVirtualServiceEvents) enqueueGatewayRoutesForVirtualService(ctx context.Context, queue workqueue.RateLimitingInterface, vs *appmesh.VirtualService) {
+	grList := &appmesh.GatewayRouteList{}
+	if err := h.referencesIndexer.Fetch(ctx, grList, ReferenceKindVirtualService, k8s.NamespacedName(vs)); err != nil {
+		h.log.Error(err, "failed to enqueue gatewayRoutes for virtualService events",
+			"virtualService", k8s.NamespacedName(vs))
+		return
+	}
+	for _, gr := range grList.Items {
+		queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&gr)})
+	}
+}
@@ -0,0 +1,112 @@
+package gatewayroute
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_enqueueRequestsForVirtualServiceEvents(t *testing.T) {
+	grWithTarget := func(name string, vsName string) *appmesh.GatewayRoute {
+		return &appmesh.GatewayRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name},
+			Spec: appmesh.GatewayRouteSpec{
+				HTTPRoute: &appmesh.HTTPGatewayRoute{
+					Action: appmesh.HTTPGatewayRouteAction{
+						Target: appmesh.GatewayRouteTarget{
+							VirtualService: appmesh.GatewayRouteVirtualService{
+								VirtualServiceRef: &appmesh.VirtualServiceReference{
+									Namespace: aws.String("vs-ns"),
+									Name:      vsName,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	vsWithActive := func(status metav1.ConditionStatus) *appmesh.VirtualService {
+		return &appmesh.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "vs-ns", Name: "vs-1"},
+			Status: appmesh.VirtualServiceStatus{
+				Conditions: []metav1.Condition{{Type: appmesh.VirtualServiceActive, Status: status}},
+			},
+		}
+	}
+	gatewayRoutes := []*appmesh.GatewayRoute{grWithTarget("gr-1", "vs-1"), grWithTarget("gr-2", "vs-2")}
+	wantRequests := []reconcile.Request{{NamespacedName: k8s.NamespacedName(gatewayRoutes[0])}}
+
+	tests := []struct {
+		name         string
+		fire         func(h *enqueueRequestsForVirtualServiceEvents, queue workqueue.RateLimitingInterface)
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "virtualService created",
+			fire: func(h *enqueueRequestsForVirtualServiceEvents, queue workqueue.RateLimitingInterface) {
+				h.Create(event.CreateEvent{Object: vsWithActive(metav1.ConditionUnknown)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+		{
+			name: "virtualService becomes active",
+			fire: func(h *enqueueRequestsForVirtualServiceEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vsWithActive(metav1.ConditionUnknown), ObjectNew: vsWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+		{
+			name: "virtualService updated without active status change",
+			fire: func(h *enqueueRequestsForVirtualServiceEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vsWithActive(metav1.ConditionTrue), ObjectNew: vsWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "virtualService deleted",
+			fire: func(h *enqueueRequestsForVirtualServiceEvents, queue workqueue.RateLimitingInterface) {
+				h.Delete(event.DeleteEvent{Object: vsWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			field, indexFunc := references.BuildIndexField(ReferenceKindVirtualService, VirtualServiceReferenceIndexFunc)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).
+				WithIndex(&appmesh.GatewayRoute{}, field, indexFunc).
+				Build()
+			for _, gr := range gatewayRoutes {
+				assert.NoError(t, k8sClient.Create(context.Background(), gr.DeepCopy()))
+			}
+			h := NewEnqueueRequestsForVirtualServiceEvents(references.NewDefaultObjectReferenceIndexer(k8sClient, nil), logr.Discard())
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			tt.fire(h, queue)
+
+			var gotRequests []reconcile.Request
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				queue.Done(item)
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+			assert.Equal(t, tt.wantRequests, gotRequests)
+		})
+	}
+}
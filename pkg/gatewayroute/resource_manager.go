@@ -4,12 +4,15 @@ import (
 	"context"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
 	"github.com/aws/aws-sdk-go/aws"
@@ -19,7 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,6 +42,8 @@ func NewDefaultResourceManager(
 	appMeshSDK services.AppMesh,
 	referencesResolver references.Resolver,
 	accountID string,
+	tagsManager tagging.Manager,
+	quotaChecker quota.Checker,
 	log logr.Logger) ResourceManager {
 
 	return &defaultResourceManager{
@@ -46,6 +51,8 @@ func NewDefaultResourceManager(
 		appMeshSDK:         appMeshSDK,
 		referencesResolver: referencesResolver,
 		accountID:          accountID,
+		tagsManager:        tagsManager,
+		quotaChecker:       quotaChecker,
 		log:                log,
 	}
 }
@@ -56,45 +63,56 @@ type defaultResourceManager struct {
 	appMeshSDK         services.AppMesh
 	referencesResolver references.Resolver
 	accountID          string
+	tagsManager        tagging.Manager
+	quotaChecker       quota.Checker
 	log                logr.Logger
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, gr *appmesh.GatewayRoute) error {
 	ms, err := m.findMeshDependency(ctx, gr)
 	if err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateMeshDependency(ctx, ms); err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 	vg, err := m.findVirtualGatewayDependency(ctx, gr)
 	if err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualGatewayDependency(ctx, ms, vg); err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 	vsByKey, err := m.findVirtualServiceDependencies(ctx, gr)
 	if err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualServiceDependencies(ctx, ms, vsByKey); err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 
 	sdkGR, err := m.findSDKGatewayRoute(ctx, ms, vg, gr)
 	if err != nil {
-		return err
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	if err := m.quotaChecker.CheckGatewayRoute(ctx, vg, gr, sdkGR == nil); err != nil {
+		return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonQuotaExceeded, err)
 	}
 	if sdkGR == nil {
 		sdkGR, err = m.createSDKGatewayRoute(ctx, ms, vg, gr, vsByKey)
 		if err != nil {
-			return err
+			return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
+		if err := m.checkSDKGatewayRouteOwnership(ctx, sdkGR, gr); err != nil {
+			return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
 		sdkGR, err = m.updateSDKGatewayRoute(ctx, sdkGR, ms, vg, gr, vsByKey)
 		if err != nil {
-			return err
+			return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.reconcileSDKGatewayRouteTags(ctx, sdkGR, gr); err != nil {
+			return m.updateCRDGatewayRouteForFailure(ctx, gr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	}
 
@@ -121,6 +139,16 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, gr *appmesh.Gatewa
 		return nil
 	}
 
+	if err := m.checkSDKGatewayRouteOwnership(ctx, sdkGR, gr); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip gatewayRoute deletion since it's owned by another controller",
+			"gatewayRoute", k8s.NamespacedName(gr),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	return m.deleteSDKGatewayRoute(ctx, sdkGR, ms, vg, gr)
 }
 
@@ -178,6 +206,10 @@ func (m *defaultResourceManager) findVirtualServiceDependencies(ctx context.Cont
 		}
 		vs, err := m.referencesResolver.ResolveVirtualServiceReference(ctx, gr, vsRef)
 		if err != nil {
+			// virtualService events enqueue the referencing gatewayRoutes once it's created.
+			if apierrors.IsNotFound(err) {
+				return nil, runtime.NewDependencyNotReadyError(errors.Wrapf(err, "failed to resolve virtualServiceRef"))
+			}
 			return nil, errors.Wrapf(err, "failed to resolve virtualServiceRef")
 		}
 		vsByKey[vsKey] = vs
@@ -192,7 +224,7 @@ func (m *defaultResourceManager) validateVirtualServiceDependencies(ctx context.
 			return errors.Errorf("virtualService %v didn't belong to mesh %v", k8s.NamespacedName(vs), k8s.NamespacedName(ms))
 		}
 		if !virtualservice.IsVirtualServiceActive(vs) {
-			return runtime.NewRequeueError(errors.New("virtualService is not active yet"))
+			return runtime.NewDependencyNotReadyError(errors.Errorf("virtualService %v is not active yet", k8s.NamespacedName(vs)))
 		}
 	}
 	return nil
@@ -226,6 +258,7 @@ func (m *defaultResourceManager) createSDKGatewayRoute(ctx context.Context, ms *
 		Spec:               sdkGRSpec,
 		VirtualGatewayName: vg.Spec.AWSName,
 		GatewayRouteName:   gr.Spec.AWSName,
+		Tags:               m.buildSDKGatewayRouteTags(ctx, gr),
 	})
 	if err != nil {
 		return nil, err
@@ -259,7 +292,7 @@ func (m *defaultResourceManager) updateSDKGatewayRoute(ctx context.Context, sdkG
 		"desiredSDKGRSpec", desiredSDKGRSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateGatewayRouteWithContext(ctx, &appmeshsdk.UpdateGatewayRouteInput{
+	resp, err := m.appMeshSDK.UpdateGatewayRouteWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateGatewayRouteInput{
 		MeshName:           ms.Spec.AWSName,
 		MeshOwner:          ms.Spec.MeshOwner,
 		Spec:               desiredSDKGRSpec,
@@ -300,17 +333,22 @@ func (m *defaultResourceManager) updateCRDGatewayRoute(ctx context.Context, gr *
 		gr.Status.GatewayRouteARN = sdkGR.Metadata.Arn
 		needsUpdate = true
 	}
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkGR.Metadata, resourceMetadata); err != nil {
+		return err
+	}
+	if !cmp.Equal(gr.Status.ResourceMetadata, resourceMetadata) {
+		gr.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
 
 	if aws.Int64Value(gr.Status.ObservedGeneration) != gr.Generation {
 		gr.Status.ObservedGeneration = aws.Int64(gr.Generation)
 		needsUpdate = true
 	}
 
-	grActiveConditionStatus := corev1.ConditionFalse
-	if sdkGR.Status != nil && aws.StringValue(sdkGR.Status.Status) == appmeshsdk.GatewayRouteStatusCodeActive {
-		grActiveConditionStatus = corev1.ConditionTrue
-	}
-	if updateCondition(gr, appmesh.GatewayRouteActive, grActiveConditionStatus, nil, nil) {
+	grActive := sdkGR.Status != nil && aws.StringValue(sdkGR.Status.Status) == appmeshsdk.GatewayRouteStatusCodeActive
+	if updateConditionsForReconciled(gr, grActive) {
 		needsUpdate = true
 	}
 
@@ -320,11 +358,42 @@ func (m *defaultResourceManager) updateCRDGatewayRoute(ctx context.Context, gr *
 	return m.k8sClient.Status().Patch(ctx, gr, client.MergeFrom(oldGR))
 }
 
+// updateCRDGatewayRouteForFailure will record reconcile failure err into gatewayRoute's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDGatewayRouteForFailure(ctx context.Context, gr *appmesh.GatewayRoute, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	oldGR := gr.DeepCopy()
+	if !updateConditionsForFailure(gr, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, gr, client.MergeFrom(oldGR)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "gatewayRoute", k8s.NamespacedName(gr))
+	}
+	return err
+}
+
 func (m *defaultResourceManager) buildSDKGatewayRouteTags(ctx context.Context, gr *appmesh.GatewayRoute) []*appmeshsdk.TagRef {
-	// TODO, support tags
+	return m.tagsManager.BuildTags(gr)
+}
+
+// reconcileSDKGatewayRouteTags corrects tags of AppMesh gatewayRoute if it's controlled by CRD gatewayRoute.
+func (m *defaultResourceManager) reconcileSDKGatewayRouteTags(ctx context.Context, sdkGR *appmeshsdk.GatewayRouteData, gr *appmesh.GatewayRoute) error {
+	if !m.isSDKGatewayRouteControlledByCRDGatewayRoute(ctx, sdkGR, gr) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkGR.Metadata.Arn), gr); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
 	return nil
 }
 
+// checkSDKGatewayRouteOwnership checks AppMesh gatewayRoute isn't owned by another controller if it's controlled by CRD gatewayRoute.
+func (m *defaultResourceManager) checkSDKGatewayRouteOwnership(ctx context.Context, sdkGR *appmeshsdk.GatewayRouteData, gr *appmesh.GatewayRoute) error {
+	if !m.isSDKGatewayRouteControlledByCRDGatewayRoute(ctx, sdkGR, gr) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkGR.Metadata.Arn), gr)
+}
+
 // isSDKGatewayRouteControlledByCRDGatewayRoute checks whether an AppMesh gatewayRoute is controlled by CRD gatewayRoute
 // if it's controlled, CRD gatewayRoute update is responsible for update AppMesh gatewayRoute.
 func (m *defaultResourceManager) isSDKGatewayRouteControlledByCRDGatewayRoute(ctx context.Context, sdkGR *appmeshsdk.GatewayRouteData, gr *appmesh.GatewayRoute) bool {
@@ -14,7 +14,6 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -46,7 +45,10 @@ func Test_defaultResourceManager_updateCRDGatewayRoute(t *testing.T) {
 				},
 				sdkGR: &appmeshsdk.GatewayRouteData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.GatewayRouteStatus{
 						Status: aws.String(appmeshsdk.GatewayRouteStatusCodeActive),
@@ -59,10 +61,36 @@ func Test_defaultResourceManager_updateCRDGatewayRoute(t *testing.T) {
 				},
 				Status: appmesh.GatewayRouteStatus{
 					GatewayRouteARN: aws.String("arn-1"),
-					Conditions: []appmesh.GatewayRouteCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.GatewayRouteActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -77,17 +105,25 @@ func Test_defaultResourceManager_updateCRDGatewayRoute(t *testing.T) {
 					},
 					Status: appmesh.GatewayRouteStatus{
 						GatewayRouteARN: aws.String("arn-1"),
-						Conditions: []appmesh.GatewayRouteCondition{
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.GatewayRouteActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
 				},
 				sdkGR: &appmeshsdk.GatewayRouteData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.GatewayRouteStatus{
 						Status: aws.String(appmeshsdk.GatewayRouteStatusCodeInactive),
@@ -100,10 +136,36 @@ func Test_defaultResourceManager_updateCRDGatewayRoute(t *testing.T) {
 				},
 				Status: appmesh.GatewayRouteStatus{
 					GatewayRouteARN: aws.String("arn-1"),
-					Conditions: []appmesh.GatewayRouteCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.GatewayRouteActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
 						},
 					},
 				},
@@ -134,7 +196,7 @@ func Test_defaultResourceManager_updateCRDGatewayRoute(t *testing.T) {
 				assert.NoError(t, err)
 				opts := cmp.Options{
 					equality.IgnoreFakeClientPopulatedFields(),
-					cmpopts.IgnoreTypes((*metav1.Time)(nil)),
+					cmpopts.IgnoreTypes(metav1.Time{}),
 				}
 				assert.True(t, cmp.Equal(tt.wantGR, gotGR, opts), "diff", cmp.Diff(tt.wantGR, gotGR, opts))
 			}
@@ -401,10 +463,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
 						},
 					},
 				},
@@ -422,10 +484,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
 						},
 					},
 				},
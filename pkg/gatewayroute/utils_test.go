@@ -3,7 +3,7 @@ package gatewayroute
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
 
@@ -21,10 +21,10 @@ func TestIsGatewayRouteActive(t *testing.T) {
 			args: args{
 				gr: &appmesh.GatewayRoute{
 					Status: appmesh.GatewayRouteStatus{
-						Conditions: []appmesh.GatewayRouteCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.GatewayRouteActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
@@ -37,10 +37,10 @@ func TestIsGatewayRouteActive(t *testing.T) {
 			args: args{
 				gr: &appmesh.GatewayRoute{
 					Status: appmesh.GatewayRouteStatus{
-						Conditions: []appmesh.GatewayRouteCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.GatewayRouteActive,
-								Status: corev1.ConditionFalse,
+								Status: metav1.ConditionFalse,
 							},
 						},
 					},
@@ -53,10 +53,10 @@ func TestIsGatewayRouteActive(t *testing.T) {
 			args: args{
 				gr: &appmesh.GatewayRoute{
 					Status: appmesh.GatewayRouteStatus{
-						Conditions: []appmesh.GatewayRouteCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.GatewayRouteActive,
-								Status: corev1.ConditionUnknown,
+								Status: metav1.ConditionUnknown,
 							},
 						},
 					},
@@ -69,7 +69,7 @@ func TestIsGatewayRouteActive(t *testing.T) {
 			args: args{
 				gr: &appmesh.GatewayRoute{
 					Status: appmesh.GatewayRouteStatus{
-						Conditions: []appmesh.GatewayRouteCondition{},
+						Conditions: []metav1.Condition{},
 					},
 				},
 			},
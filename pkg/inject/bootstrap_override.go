@@ -0,0 +1,150 @@
+package inject
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// envoyBootstrapOverrideEnvName is passed by the Envoy image entrypoint as --config-yaml,
+	// which Envoy merges on top of the bootstrap generated by the App Mesh agent.
+	envoyBootstrapOverrideEnvName = "ENVOY_BOOTSTRAP_OVERRIDE_YAML"
+
+	bootstrapOverrideSourceConfigMap = "configmap"
+	bootstrapOverrideSourceSecret    = "secret"
+	bootstrapOverrideSourceSSM       = "ssm"
+)
+
+// resolveEnvoyBootstrapOverride resolves the envoy bootstrap override referenced by pod into an env variable for envoy.
+// SSM parameters are read with the controller's IAM role, so they're only resolved under one of ssmParameterPrefixes,
+// and SecureString parameters are refused unless allowSecureString.
+// it returns nil if pod doesn't reference any bootstrap override.
+func resolveEnvoyBootstrapOverride(ctx context.Context, ssmSDK services.SSM, ssmParameterPrefixes []string, allowSecureString bool, pod *corev1.Pod) (*corev1.EnvVar, error) {
+	v, ok := pod.ObjectMeta.Annotations[AppMeshEnvoyBootstrapOverrideAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	source, ref, err := parseEnvoyBootstrapOverride(v)
+	if err != nil {
+		return nil, err
+	}
+	switch source {
+	case bootstrapOverrideSourceConfigMap:
+		configMapName, configMapKey := ref[0], ref[1]
+		return &corev1.EnvVar{
+			Name: envoyBootstrapOverrideEnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+					Key:                  configMapKey,
+				},
+			},
+		}, nil
+	case bootstrapOverrideSourceSecret:
+		secretName, secretKey := ref[0], ref[1]
+		return &corev1.EnvVar{
+			Name: envoyBootstrapOverrideEnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  secretKey,
+				},
+			},
+		}, nil
+	default:
+		if !hasAnyPrefix(ref[0], ssmParameterPrefixes) {
+			if len(ssmParameterPrefixes) == 0 {
+				return nil, errors.Errorf("unable to resolve SSM parameter %s, no SSM parameter prefixes are allowed for bootstrap overrides, use a ConfigMap or Secret instead", ref[0])
+			}
+			return nil, errors.Errorf("unable to resolve SSM parameter %s, it must have one of prefixes: %s", ref[0], strings.Join(ssmParameterPrefixes, ", "))
+		}
+		if ssmSDK == nil {
+			return nil, errors.Errorf("unable to resolve SSM parameter %s, SSM client is not configured", ref[0])
+		}
+		resp, err := ssmSDK.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(ref[0]),
+			WithDecryption: aws.Bool(allowSecureString),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get SSM parameter %s", ref[0])
+		}
+		if aws.StringValue(resp.Parameter.Type) == ssm.ParameterTypeSecureString && !allowSecureString {
+			return nil, errors.Errorf("unable to resolve SSM parameter %s, SecureString parameters are not allowed for bootstrap overrides, use a Secret instead", ref[0])
+		}
+		return &corev1.EnvVar{
+			Name:  envoyBootstrapOverrideEnvName,
+			Value: aws.StringValue(resp.Parameter.Value),
+		}, nil
+	}
+}
+
+// parseEnvoyBootstrapOverride parses the envoy bootstrap override annotation value.
+// for configmap and secret sources, ref is the object name and key. For ssm source, ref is the parameter name.
+func parseEnvoyBootstrapOverride(value string) (string, []string, error) {
+	pair := strings.SplitN(strings.TrimSpace(value), ":", 2)
+	if len(pair) == 2 {
+		source := strings.TrimSpace(pair[0])
+		ref := strings.TrimSpace(pair[1])
+		switch source {
+		case bootstrapOverrideSourceConfigMap, bootstrapOverrideSourceSecret:
+			objectRef := strings.Split(ref, "/")
+			if len(objectRef) == 2 && objectRef[0] != "" && objectRef[1] != "" {
+				return source, objectRef, nil
+			}
+		case bootstrapOverrideSourceSSM:
+			if ref != "" {
+				return source, []string{ref}, nil
+			}
+		}
+	}
+	return "", nil, errors.Errorf("malformed annotation %s, expected format: %s, %s or %s",
+		AppMeshEnvoyBootstrapOverrideAnnotation, "configmap:configMapName/key", "secret:secretName/key", "ssm:parameterName")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) != 0 && strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newEnvoyBootstrapOverrideMutator constructs new envoyBootstrapOverrideMutator
+func newEnvoyBootstrapOverrideMutator(bootstrapOverride *corev1.EnvVar) *envoyBootstrapOverrideMutator {
+	return &envoyBootstrapOverrideMutator{
+		bootstrapOverride: bootstrapOverride,
+	}
+}
+
+var _ PodMutator = &envoyBootstrapOverrideMutator{}
+
+// envoyBootstrapOverrideMutator adds the resolved bootstrap override to envoy container.
+type envoyBootstrapOverrideMutator struct {
+	bootstrapOverride *corev1.EnvVar
+}
+
+func (m *envoyBootstrapOverrideMutator) mutate(pod *corev1.Pod) error {
+	if m.bootstrapOverride == nil {
+		return nil
+	}
+	for idx := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[idx]
+		if container.Name != envoyContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == envoyBootstrapOverrideEnvName {
+				return nil
+			}
+		}
+		container.Env = append(container.Env, *m.bootstrapOverride)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package inject
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeSSM struct {
+	ssmiface.SSMAPI
+	parameters       map[string]string
+	secureParameters map[string]string
+}
+
+func (f *fakeSSM) GetParameterWithContext(_ aws.Context, input *ssm.GetParameterInput, _ ...request.Option) (*ssm.GetParameterOutput, error) {
+	if v, ok := f.parameters[aws.StringValue(input.Name)]; ok {
+		return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Type: aws.String(ssm.ParameterTypeString), Value: aws.String(v)}}, nil
+	}
+	if v, ok := f.secureParameters[aws.StringValue(input.Name)]; ok {
+		if !aws.BoolValue(input.WithDecryption) {
+			v = "AQICAHh-ciphertext"
+		}
+		return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Type: aws.String(ssm.ParameterTypeSecureString), Value: aws.String(v)}}, nil
+	}
+	return nil, errors.New("ParameterNotFound")
+}
+
+func Test_resolveEnvoyBootstrapOverride(t *testing.T) {
+	ssmSDK := &fakeSSM{
+		parameters: map[string]string{
+			"/appmesh/envoy/overload-manager": "overload_manager: {}",
+			"/database/connection":            "postgres://db",
+		},
+		secureParameters: map[string]string{
+			"/appmesh/envoy/stats-sinks": "stats_sinks: []",
+		},
+	}
+	tests := []struct {
+		name                 string
+		annotations          map[string]string
+		ssmParameterPrefixes []string
+		allowSecureString    bool
+		want                 *corev1.EnvVar
+		wantErr              error
+	}{
+		{
+			name:        "no annotation",
+			annotations: nil,
+			want:        nil,
+		},
+		{
+			name: "secret reference",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "secret:envoy-overrides/bootstrap.yaml",
+			},
+			want: &corev1.EnvVar{
+				Name: envoyBootstrapOverrideEnvName,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "envoy-overrides"},
+						Key:                  "bootstrap.yaml",
+					},
+				},
+			},
+		},
+		{
+			name: "configmap reference",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "configmap:envoy-overrides/bootstrap.yaml",
+			},
+			want: &corev1.EnvVar{
+				Name: envoyBootstrapOverrideEnvName,
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "envoy-overrides"},
+						Key:                  "bootstrap.yaml",
+					},
+				},
+			},
+		},
+		{
+			name: "ssm reference",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/appmesh/envoy/overload-manager",
+			},
+			ssmParameterPrefixes: []string{"/appmesh/envoy/"},
+			want: &corev1.EnvVar{
+				Name:  envoyBootstrapOverrideEnvName,
+				Value: "overload_manager: {}",
+			},
+		},
+		{
+			name: "ssm parameter not found",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/appmesh/envoy/missing",
+			},
+			ssmParameterPrefixes: []string{"/appmesh/envoy/"},
+			wantErr:              errors.New("failed to get SSM parameter /appmesh/envoy/missing: ParameterNotFound"),
+		},
+		{
+			name: "ssm reference without allowed prefixes",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/appmesh/envoy/overload-manager",
+			},
+			wantErr: errors.New("unable to resolve SSM parameter /appmesh/envoy/overload-manager, no SSM parameter prefixes are allowed for bootstrap overrides, use a ConfigMap or Secret instead"),
+		},
+		{
+			name: "ssm reference outside allowed prefixes",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/database/connection",
+			},
+			ssmParameterPrefixes: []string{"/appmesh/envoy/", "/appmesh/gateway/"},
+			wantErr:              errors.New("unable to resolve SSM parameter /database/connection, it must have one of prefixes: /appmesh/envoy/, /appmesh/gateway/"),
+		},
+		{
+			name: "ssm SecureString reference",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/appmesh/envoy/stats-sinks",
+			},
+			ssmParameterPrefixes: []string{"/appmesh/envoy/"},
+			wantErr:              errors.New("unable to resolve SSM parameter /appmesh/envoy/stats-sinks, SecureString parameters are not allowed for bootstrap overrides, use a Secret instead"),
+		},
+		{
+			name: "ssm SecureString reference allowed",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "ssm:/appmesh/envoy/stats-sinks",
+			},
+			ssmParameterPrefixes: []string{"/appmesh/envoy/"},
+			allowSecureString:    true,
+			want: &corev1.EnvVar{
+				Name:  envoyBootstrapOverrideEnvName,
+				Value: "stats_sinks: []",
+			},
+		},
+		{
+			name: "secret reference without key",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "secret:envoy-overrides",
+			},
+			wantErr: errors.New("malformed annotation appmesh.k8s.aws/envoyBootstrapOverride, expected format: configmap:configMapName/key, secret:secretName/key or ssm:parameterName"),
+		},
+		{
+			name: "unknown source",
+			annotations: map[string]string{
+				AppMeshEnvoyBootstrapOverrideAnnotation: "vault:envoy-overrides/bootstrap.yaml",
+			},
+			wantErr: errors.New("malformed annotation appmesh.k8s.aws/envoyBootstrapOverride, expected format: configmap:configMapName/key, secret:secretName/key or ssm:parameterName"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+			got, err := resolveEnvoyBootstrapOverride(context.Background(), ssmSDK, tt.ssmParameterPrefixes, tt.allowSecureString, pod)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_envoyBootstrapOverrideMutator_mutate(t *testing.T) {
+	override := &corev1.EnvVar{Name: envoyBootstrapOverrideEnvName, Value: "overload_manager: {}"}
+	tests := []struct {
+		name              string
+		bootstrapOverride *corev1.EnvVar
+		envoyEnv          []corev1.EnvVar
+		wantEnvoyEnv      []corev1.EnvVar
+	}{
+		{
+			name:              "no override",
+			bootstrapOverride: nil,
+			envoyEnv:          []corev1.EnvVar{{Name: "APPMESH_RESOURCE_ARN", Value: "arn"}},
+			wantEnvoyEnv:      []corev1.EnvVar{{Name: "APPMESH_RESOURCE_ARN", Value: "arn"}},
+		},
+		{
+			name:              "appends override",
+			bootstrapOverride: override,
+			envoyEnv:          []corev1.EnvVar{{Name: "APPMESH_RESOURCE_ARN", Value: "arn"}},
+			wantEnvoyEnv:      []corev1.EnvVar{{Name: "APPMESH_RESOURCE_ARN", Value: "arn"}, *override},
+		},
+		{
+			name:              "env from sidecarEnv annotation takes precedence",
+			bootstrapOverride: override,
+			envoyEnv:          []corev1.EnvVar{{Name: envoyBootstrapOverrideEnvName, Value: "custom"}},
+			wantEnvoyEnv:      []corev1.EnvVar{{Name: envoyBootstrapOverrideEnvName, Value: "custom"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "app"},
+						{Name: envoyContainerName, Env: tt.envoyEnv},
+					},
+				},
+			}
+			m := newEnvoyBootstrapOverrideMutator(tt.bootstrapOverride)
+			assert.NoError(t, m.mutate(pod))
+			assert.Nil(t, pod.Spec.Containers[0].Env)
+			assert.Equal(t, tt.wantEnvoyEnv, pod.Spec.Containers[1].Env)
+		})
+	}
+}
@@ -24,6 +24,10 @@ func (m *cloudMapHealthyReadinessGate) mutate(pod *corev1.Pod) error {
 	if m.vn.Spec.ServiceDiscovery == nil || m.vn.Spec.ServiceDiscovery.AWSCloudMap == nil {
 		return nil
 	}
+	// pods must become ready without AWS Cloud Map registration once VirtualNode fails over to DNS.
+	if m.vn.Spec.ServiceDiscovery.Failover != nil {
+		return nil
+	}
 	containsAWSCloudMapHealthyReadinessGate := false
 	for _, item := range pod.Spec.ReadinessGates {
 		if item.ConditionType == k8s.ConditionAWSCloudMapHealthy {
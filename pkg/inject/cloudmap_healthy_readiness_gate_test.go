@@ -34,6 +34,25 @@ func Test_cloudMapHealthyReadinessGate_mutate(t *testing.T) {
 			},
 		},
 	}
+	vnWithServiceDiscoveryFailover := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "vn-4",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			ServiceDiscovery: &appmesh.ServiceDiscovery{
+				AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+					NamespaceName: "cm-ns",
+					ServiceName:   "cm-svc",
+				},
+				DNS: &appmesh.DNSServiceDiscovery{
+					Hostname: "www.example.com",
+				},
+				Failover: &appmesh.ServiceDiscoveryFailover{
+					Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+				},
+			},
+		},
+	}
 	vnWithNoServiceDiscovery := &appmesh.VirtualNode{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "vn-3",
@@ -141,6 +160,32 @@ func Test_cloudMapHealthyReadinessGate_mutate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "shouldn't add readinessGate if vn fails over between serviceDiscovery",
+			fields: fields{
+				vn: vnWithServiceDiscoveryFailover,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					Spec: corev1.PodSpec{
+						ReadinessGates: []corev1.PodReadinessGate{
+							{
+								ConditionType: "condition-A",
+							},
+						},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					ReadinessGates: []corev1.PodReadinessGate{
+						{
+							ConditionType: "condition-A",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "shouldn't add readinessGate if vn is using None serviceDiscovery",
 			fields: fields{
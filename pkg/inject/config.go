@@ -1,20 +1,34 @@
 package inject
 
 import (
-	"errors"
-
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 )
 
+const (
+	// PrometheusScrapeModeAnnotations exposes Envoy stats via prometheus.io pod annotations
+	PrometheusScrapeModeAnnotations = "annotations"
+	// PrometheusScrapeModePodMonitor exposes Envoy stats via Prometheus Operator PodMonitor objects
+	PrometheusScrapeModePodMonitor = "podmonitor"
+)
+
 const (
 	flagEnableIAMForServiceAccounts = "enable-iam-for-service-accounts"
 	flagEnableECRSecret             = "enable-ecr-secret"
 	flagEnableSDS                   = "enable-sds"
 	flagSdsUdsPath                  = "sds-uds-path"
 	flagEnableBackendGroups         = "enable-backend-groups"
+	flagEnableBootstrapOverrides    = "enable-envoy-bootstrap-overrides"
+	flagEnableEnvoyReadinessGate    = "enable-envoy-readiness-gate"
+	flagPrometheusScrapeMode        = "prometheus-scrape-mode"
+
+	flagBootstrapOverrideSSMPrefixes       = "envoy-bootstrap-override-ssm-parameter-prefixes"
+	flagBootstrapOverrideAllowSecureString = "envoy-bootstrap-override-allow-secure-string"
 
 	flagSidecarImageRepository     = "sidecar-image-repository"
 	flagSidecarImageTag            = "sidecar-image-tag"
+	flagSidecarWindowsImageTag     = "sidecar-windows-image-tag"
+	flagWindowsRoutingAgent        = "windows-routing-agent"
 	flagSidecarCpuRequests         = "sidecar-cpu-requests"
 	flagSidecarMemoryRequests      = "sidecar-memory-requests"
 	flagSidecarCpuLimits           = "sidecar-cpu-limits"
@@ -22,6 +36,8 @@ const (
 	flagPreview                    = "preview"
 	flagLogLevel                   = "sidecar-log-level"
 	flagPreStopDelay               = "prestop-delay"
+	flagEnvoyDrainDuration         = "envoy-drain-duration"
+	flagAlignTerminationGrace      = "align-termination-grace-period"
 	flagPostStartTimeout           = "poststart-timeout"
 	flagPostStartInterval          = "poststart-interval"
 	flagReadinessProbeInitialDelay = "readiness-probe-initial-delay"
@@ -40,6 +56,13 @@ const (
 	flagInitImage  = "init-image"
 	flagIgnoredIPs = "ignored-ips"
 
+	flagImageRegistry             = "image-registry"
+	flagSidecarImageDigest        = "sidecar-image-digest"
+	flagSidecarWindowsImageDigest = "sidecar-windows-image-digest"
+	flagInitImageDigest           = "init-image-digest"
+	flagXRayImageDigest           = "xray-image-digest"
+	flagVerifyImageDigests        = "verify-image-digests"
+
 	flagEnableJaegerTracing  = "enable-jaeger-tracing"
 	flagJaegerAddress        = "jaeger-address"
 	flagJaegerPort           = "jaeger-port"
@@ -58,6 +81,11 @@ const (
 	flagStatsDSocketPath     = "statsd-socket-path"
 	flagXRayImage            = "xray-image"
 
+	flagEnableOtelCollector   = "enable-otel-collector"
+	flagOtelCollectorImage    = "otel-collector-image"
+	flagOtelCollectorEndpoint = "otel-collector-endpoint"
+	flagOtelSamplingRate      = "otel-sampling-rate"
+
 	flagClusterName = "cluster-name"
 
 	flagTlsMinVersion  = "tls-min-version"
@@ -80,6 +108,8 @@ type Config struct {
 	// Sidecar settings
 	SidecarImageRepository     string
 	SidecarImageTag            string
+	SidecarWindowsImageTag     string
+	WindowsRoutingAgent        bool
 	SidecarCpuRequests         string
 	SidecarMemoryRequests      string
 	SidecarCpuLimits           string
@@ -87,6 +117,8 @@ type Config struct {
 	Preview                    bool
 	LogLevel                   string
 	PreStopDelay               string
+	EnvoyDrainDuration         int32
+	AlignTerminationGrace      bool
 	PostStartTimeout           int32
 	PostStartInterval          int32
 	ReadinessProbeInitialDelay int32
@@ -106,6 +138,17 @@ type Config struct {
 	InitImage  string
 	IgnoredIPs string
 
+	// Image mirroring settings, namespaces can override them with the image annotations.
+	// ImageRegistry replaces the registry of the Envoy, init and X-Ray images if set.
+	ImageRegistry string
+	// Image digests pin the images instead of their tags if set.
+	SidecarImageDigest        string
+	SidecarWindowsImageDigest string
+	InitImageDigest           string
+	XRayImageDigest           string
+	// Whether pinned digests are verified to resolve in their registry before pods are injected
+	VerifyImageDigests bool
+
 	// Observability settings
 	EnableJaegerTracing  bool
 	JaegerAddress        string
@@ -125,6 +168,23 @@ type Config struct {
 	StatsDSocketPath     string
 	XRayImage            string
 
+	EnableOtelCollector   bool
+	OtelCollectorImage    string
+	OtelCollectorEndpoint string
+	OtelSamplingRate      string
+
+	// How Envoy stats are exposed to Prometheus, one of PrometheusScrapeMode* or empty to disable
+	PrometheusScrapeMode string
+
+	// Whether pods can reference extra Envoy bootstrap config from ConfigMap, Secret or SSM parameter
+	EnableBootstrapOverrides bool
+	// Prefixes of SSM parameters pods can reference as bootstrap override, SSM parameters are refused if empty
+	BootstrapOverrideSSMParameterPrefixes []string
+	// Whether SecureString SSM parameters can be referenced as bootstrap override, and decrypted by the controller
+	BootstrapOverrideAllowSecureString bool
+	// Whether pod readiness is gated on injected Envoy being ready
+	EnableEnvoyReadinessGate bool
+
 	ClusterName string
 
 	// TLS settings
@@ -137,8 +197,15 @@ func multipleTracer(config *Config) bool {
 	j := config.EnableJaegerTracing
 	d := config.EnableDatadogTracing
 	x := config.EnableXrayTracing
+	o := config.EnableOtelCollector
 
-	return (j && d) || (d && x) || (j && x)
+	enabled := 0
+	for _, tracer := range []bool{j, d, x, o} {
+		if tracer {
+			enabled++
+		}
+	}
+	return enabled > 1
 }
 
 func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
@@ -155,6 +222,10 @@ func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&cfg.SidecarImageRepository, flagSidecarImageRepository, "public.ecr.aws/appmesh/aws-appmesh-envoy",
 		"Envoy sidecar container image repository.")
 	fs.StringVar(&cfg.SidecarImageTag, flagSidecarImageTag, "v1.27.3.0-prod", "Envoy sidecar container image tag.")
+	fs.StringVar(&cfg.SidecarWindowsImageTag, flagSidecarWindowsImageTag, "v1.27.3.0-prod-windows",
+		"Envoy sidecar container image tag for pods scheduled to Windows nodes.")
+	fs.BoolVar(&cfg.WindowsRoutingAgent, flagWindowsRoutingAgent, false,
+		"Windows nodes run a routing agent intercepting traffic of pods into Envoy from their AppMesh annotations. VirtualNode pods scheduled to Windows nodes are rejected otherwise, as their traffic would bypass Envoy.")
 	fs.StringVar(&cfg.SidecarCpuRequests, flagSidecarCpuRequests, "10m",
 		"Sidecar CPU resources requests.")
 	fs.StringVar(&cfg.SidecarMemoryRequests, flagSidecarMemoryRequests, "32Mi",
@@ -173,6 +244,10 @@ func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
 		"AWS App Mesh envoy access log path")
 	fs.StringVar(&cfg.PreStopDelay, flagPreStopDelay, "20",
 		"AWS App Mesh envoy preStop hook sleep duration")
+	fs.Int32Var(&cfg.EnvoyDrainDuration, flagEnvoyDrainDuration, 0,
+		"Number of seconds the envoy preStop hook drains listeners gracefully after the preStop delay, 0 disables draining")
+	fs.BoolVar(&cfg.AlignTerminationGrace, flagAlignTerminationGrace, false,
+		"If enabled, pod terminationGracePeriodSeconds is raised to cover the envoy preStop delay and drain duration")
 	fs.Int32Var(&cfg.PostStartTimeout, flagPostStartTimeout, 180,
 		"AWS App Mesh envoy postStart hook timeout duration")
 	fs.Int32Var(&cfg.PostStartInterval, flagPostStartInterval, 5,
@@ -185,6 +260,18 @@ func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
 		"Init container image.")
 	fs.StringVar(&cfg.IgnoredIPs, flagIgnoredIPs, "169.254.169.254",
 		"Init container ignored IPs.")
+	fs.StringVar(&cfg.ImageRegistry, flagImageRegistry, "",
+		"Registry, optionally with a path, replacing the registry of the Envoy, init and X-Ray container images, e.g. to pull them from a mirror")
+	fs.StringVar(&cfg.SidecarImageDigest, flagSidecarImageDigest, "",
+		"Digest pinning the Envoy sidecar container image instead of its tag, e.g. sha256:<64 hex digits>")
+	fs.StringVar(&cfg.SidecarWindowsImageDigest, flagSidecarWindowsImageDigest, "",
+		"Digest pinning the Envoy sidecar container image of pods scheduled to Windows nodes instead of its tag")
+	fs.StringVar(&cfg.InitImageDigest, flagInitImageDigest, "",
+		"Digest pinning the init container image instead of its tag")
+	fs.StringVar(&cfg.XRayImageDigest, flagXRayImageDigest, "",
+		"Digest pinning the X-Ray daemon container image instead of its tag")
+	fs.BoolVar(&cfg.VerifyImageDigests, flagVerifyImageDigests, false,
+		"If enabled, pods are only injected once the pinned image digests are found in their registry")
 	fs.BoolVar(&cfg.EnableJaegerTracing, flagEnableJaegerTracing, false,
 		"Enable Envoy Jaeger tracing")
 	fs.StringVar(&cfg.JaegerAddress, flagJaegerAddress, "appmesh-jaeger.appmesh-system",
@@ -209,6 +296,24 @@ func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
 		"X-Ray Agent IAM role to upload segments to a different account")
 	fs.StringVar(&cfg.XRayImage, flagXRayImage, "public.ecr.aws/xray/aws-xray-daemon",
 		"X-Ray daemon container image")
+	fs.BoolVar(&cfg.EnableOtelCollector, flagEnableOtelCollector, false,
+		"Enable Envoy OpenTelemetry (OTLP) tracing and inject the AWS Distro for OpenTelemetry collector as sidecar")
+	fs.StringVar(&cfg.OtelCollectorImage, flagOtelCollectorImage, "public.ecr.aws/aws-observability/aws-otel-collector:v0.40.0",
+		"OpenTelemetry collector container image")
+	fs.StringVar(&cfg.OtelCollectorEndpoint, flagOtelCollectorEndpoint, "127.0.0.1:4317",
+		"OTLP gRPC endpoint Envoy exports spans to")
+	fs.StringVar(&cfg.OtelSamplingRate, flagOtelSamplingRate, "0.05",
+		"Fraction of traces the injected OpenTelemetry collector exports, between 0 and 1.00")
+	fs.BoolVar(&cfg.EnableBootstrapOverrides, flagEnableBootstrapOverrides, false,
+		"If enabled, pods can reference extra Envoy bootstrap config from a ConfigMap, Secret or SSM parameter via the appmesh.k8s.aws/envoyBootstrapOverride annotation")
+	fs.StringSliceVar(&cfg.BootstrapOverrideSSMParameterPrefixes, flagBootstrapOverrideSSMPrefixes, nil,
+		"Prefixes of SSM parameters pods can reference as Envoy bootstrap override, e.g. /appmesh/envoy/. SSM parameters are read with the controller's IAM role, and refused if empty")
+	fs.BoolVar(&cfg.BootstrapOverrideAllowSecureString, flagBootstrapOverrideAllowSecureString, false,
+		"If enabled, SecureString SSM parameters referenced as Envoy bootstrap override are decrypted by the controller and inlined into the pod spec")
+	fs.BoolVar(&cfg.EnableEnvoyReadinessGate, flagEnableEnvoyReadinessGate, false,
+		"If enabled, an appmesh.k8s.aws/envoy-ready readiness gate is injected and only flipped once Envoy is LIVE with listeners configured")
+	fs.StringVar(&cfg.PrometheusScrapeMode, flagPrometheusScrapeMode, "",
+		`Configure Prometheus scraping of Envoy stats, "annotations" to inject prometheus.io annotations or "podmonitor" to create Prometheus Operator PodMonitors. Disabled if empty`)
 	fs.BoolVar(&cfg.EnableStatsTags, flagEnableStatsTags, false,
 		"Enable Envoy to tag stats")
 	fs.BoolVar(&cfg.EnableStatsD, flagEnableStatsD, false,
@@ -244,7 +349,31 @@ func (cfg *Config) BindEnv() error {
 
 func (cfg *Config) Validate() error {
 	if multipleTracer(cfg) {
-		return errors.New("Envoy only supports a single tracer instance. Please choose between Jaeger, Datadog or X-Ray.")
+		return errors.New("Envoy only supports a single tracer instance. Please choose between Jaeger, Datadog, X-Ray or OpenTelemetry.")
+	}
+	switch cfg.PrometheusScrapeMode {
+	case "", PrometheusScrapeModeAnnotations, PrometheusScrapeModePodMonitor:
+	default:
+		return errors.New("prometheus-scrape-mode must be one of annotations or podmonitor")
+	}
+	if cfg.EnvoyDrainDuration < 0 {
+		return errors.New("envoy-drain-duration must not be negative")
+	}
+	if _, err := parseSeconds(cfg.PreStopDelay); err != nil && (cfg.AlignTerminationGrace || cfg.EnvoyDrainDuration > 0) {
+		return errors.Errorf("prestop-delay must be a number of seconds with envoy-drain-duration or align-termination-grace-period: %s", cfg.PreStopDelay)
+	}
+	if err := validateImageRegistry(cfg.ImageRegistry); err != nil {
+		return errors.Wrap(err, flagImageRegistry)
+	}
+	for _, digest := range []struct{ flag, value string }{
+		{flagSidecarImageDigest, cfg.SidecarImageDigest},
+		{flagSidecarWindowsImageDigest, cfg.SidecarWindowsImageDigest},
+		{flagInitImageDigest, cfg.InitImageDigest},
+		{flagXRayImageDigest, cfg.XRayImageDigest},
+	} {
+		if err := validateImageDigest(digest.value); err != nil {
+			return errors.Wrap(err, digest.flag)
+		}
 	}
 	return nil
 }
@@ -75,6 +75,99 @@ const (
 	//
 	AppMeshXrayAgentConfigAnnotation = "appmesh.k8s.aws/xrayAgentConfigMount"
 
+	// === begin opentelemetry collector annotations ===
+
+	// AppMeshOtelCollectorAnnotation overrides the controller level OpenTelemetry collector setting for a pod.
+	// When enabled, Envoy exports traces over OTLP and the ADOT collector is injected as sidecar.
+	//
+	//        e.g. appmesh.k8s.aws/otelCollector: enabled
+	//
+	AppMeshOtelCollectorAnnotation = "appmesh.k8s.aws/otelCollector"
+
+	// AppMeshEnvoyOtelTracingConfigAnnotation holds the Envoy tracing config exporting traces to the OpenTelemetry collector.
+	// It's set by the injector, and projected into the Envoy container as the file of ENVOY_TRACING_CFG_FILE.
+	AppMeshEnvoyOtelTracingConfigAnnotation = "appmesh.k8s.aws/envoyOtelTracingConfig"
+
+	// AppMeshEnvoyBootstrapOverrideAnnotation specifies an Envoy bootstrap fragment in YAML that will be merged
+	// into the generated bootstrap, from either a ConfigMap or Secret key in pod's namespace or an SSM parameter.
+	// SSM parameters are resolved by the controller when pod is created, and must have one of the configured prefixes.
+	//
+	//        e.g. appmesh.k8s.aws/envoyBootstrapOverride: configmap:envoy-overrides/bootstrap.yaml
+	//        e.g. appmesh.k8s.aws/envoyBootstrapOverride: secret:envoy-overrides/bootstrap.yaml
+	//        e.g. appmesh.k8s.aws/envoyBootstrapOverride: ssm:/appmesh/envoy/overload-manager
+	//
+	AppMeshEnvoyBootstrapOverrideAnnotation = "appmesh.k8s.aws/envoyBootstrapOverride"
+
+	// === begin envoy termination annotations ===
+
+	// AppMeshPreStopDelayAnnotation overrides the number of seconds the envoy preStop hook sleeps before draining.
+	//
+	//        e.g. appmesh.k8s.aws/preStopDelay: "30"
+	//
+	AppMeshPreStopDelayAnnotation = "appmesh.k8s.aws/preStopDelay"
+
+	// AppMeshEnvoyDrainDurationAnnotation overrides the number of seconds the envoy preStop hook drains listeners gracefully
+	// after the preStop delay, "0" disables draining.
+	//
+	//        e.g. appmesh.k8s.aws/envoyDrainDuration: "60"
+	//
+	AppMeshEnvoyDrainDurationAnnotation = "appmesh.k8s.aws/envoyDrainDuration"
+
+	// AppMeshAlignTerminationGraceAnnotation overrides the controller level setting raising the pod's terminationGracePeriodSeconds
+	// to cover the envoy preStop delay and drain duration.
+	//
+	//        e.g. appmesh.k8s.aws/alignTerminationGracePeriod: enabled
+	//
+	AppMeshAlignTerminationGraceAnnotation = "appmesh.k8s.aws/alignTerminationGracePeriod"
+
+	// === begin envoy dns annotations ===
+
+	// AppMeshEnvoyDNSPolicyAnnotation specifies how Envoy resolves DNS hostnames, either
+	// `cluster` (default) through the pod's resolv.conf, `host` through the node's DNS by setting the pod's dnsPolicy to Default,
+	// or `custom` through the resolvers of AppMeshEnvoyDNSResolversAnnotation only.
+	//
+	//        e.g. appmesh.k8s.aws/envoyDNSPolicy: custom
+	//
+	AppMeshEnvoyDNSPolicyAnnotation = "appmesh.k8s.aws/envoyDNSPolicy"
+
+	// AppMeshEnvoyDNSResolversAnnotation specifies the list of resolvers Envoy uses with the `custom` DNS policy.
+	// The port defaults to 53.
+	//
+	//        e.g. appmesh.k8s.aws/envoyDNSResolvers: "10.0.0.2, 10.0.0.3:5353, [fd00::2]:53"
+	//
+	AppMeshEnvoyDNSResolversAnnotation = "appmesh.k8s.aws/envoyDNSResolvers"
+
+	// AppMeshEnvoyDNSNdotsAnnotation specifies the ndots option of the pod's resolv.conf.
+	//
+	//        e.g. appmesh.k8s.aws/envoyDNSNdots: "2"
+	//
+	AppMeshEnvoyDNSNdotsAnnotation = "appmesh.k8s.aws/envoyDNSNdots"
+
+	// === begin image annotations ===
+	// these annotations are only read from the pod's namespace, and override the controller level settings of its sidecars.
+
+	// AppMeshImageRegistryAnnotation specifies the registry the Envoy, proxy-init and X-Ray images are pulled from, replacing the registry
+	// of their repository, e.g. public.ecr.aws/appmesh/aws-appmesh-envoy is pulled from registry.example.com/mirror/appmesh/aws-appmesh-envoy.
+	//
+	//        e.g. appmesh.k8s.aws/imageRegistry: registry.example.com/mirror
+	//
+	AppMeshImageRegistryAnnotation = "appmesh.k8s.aws/imageRegistry"
+
+	// AppMeshSidecarImageDigestAnnotation pins the Envoy image to a digest instead of its tag.
+	//
+	//        e.g. appmesh.k8s.aws/sidecarImageDigest: sha256:<64 hex digits>
+	//
+	AppMeshSidecarImageDigestAnnotation = "appmesh.k8s.aws/sidecarImageDigest"
+
+	// AppMeshSidecarWindowsImageDigestAnnotation pins the Envoy image of pods scheduled to Windows nodes to a digest instead of its tag.
+	AppMeshSidecarWindowsImageDigestAnnotation = "appmesh.k8s.aws/sidecarWindowsImageDigest"
+
+	// AppMeshInitImageDigestAnnotation pins the proxy-init image to a digest instead of its tag.
+	AppMeshInitImageDigestAnnotation = "appmesh.k8s.aws/initImageDigest"
+
+	// AppMeshXRayImageDigestAnnotation pins the X-Ray daemon image to a digest instead of its tag.
+	AppMeshXRayImageDigestAnnotation = "appmesh.k8s.aws/xrayImageDigest"
+
 	//Pod Labels
 
 	//FargateProfileLabel is added by fargate-scheduler when pod is running on AWS Fargate
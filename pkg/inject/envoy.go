@@ -30,6 +30,7 @@ type envoyMutatorConfig struct {
 	readinessProbePeriod       int32
 	sidecarImageRepository     string
 	sidecarImageTag            string
+	sidecarImageDigest         string
 	sidecarCPURequests         string
 	sidecarMemoryRequests      string
 	sidecarCPULimits           string
@@ -43,7 +44,10 @@ type envoyMutatorConfig struct {
 	enableDatadogTracing       bool
 	datadogTracerPort          int32
 	datadogTracerAddress       string
+	enableOtelCollector        bool
+	otelCollectorEndpoint      string
 	enableStatsTags            bool
+	enableMetricExtension      bool
 	enableStatsD               bool
 	statsDPort                 int32
 	statsDAddress              string
@@ -122,6 +126,9 @@ func (m *envoyMutator) mutate(pod *corev1.Pod) error {
 
 	m.mutateSecretMounts(pod, &container, secretMounts)
 	m.mutateVolumeMounts(pod, &container, volumeMounts)
+	if variables.EnableOtelTracing {
+		mutateEnvoyOtelTracingConfig(pod, &container, variables.MeshName+"/"+variables.VirtualGatewayOrNodeName, m.mutatorConfig.otelCollectorEndpoint)
+	}
 	if m.mutatorConfig.enableSDS && !isSDSDisabled(pod) {
 		mutateSDSMounts(pod, &container, m.mutatorConfig.sdsUdsPath)
 	}
@@ -162,6 +169,7 @@ func (m *envoyMutator) buildTemplateVariables(pod *corev1.Pod) EnvoyTemplateVari
 		PostStartInterval:        m.mutatorConfig.postStartInterval,
 		SidecarImageRepository:   m.mutatorConfig.sidecarImageRepository,
 		SidecarImageTag:          m.mutatorConfig.sidecarImageTag,
+		SidecarImageDigest:       m.mutatorConfig.sidecarImageDigest,
 		EnableXrayTracing:        m.mutatorConfig.enableXrayTracing,
 		XrayDaemonPort:           m.mutatorConfig.xrayDaemonPort,
 		XraySamplingRate:         m.mutatorConfig.xraySamplingRate,
@@ -171,7 +179,10 @@ func (m *envoyMutator) buildTemplateVariables(pod *corev1.Pod) EnvoyTemplateVari
 		EnableDatadogTracing:     m.mutatorConfig.enableDatadogTracing,
 		DatadogTracerPort:        m.mutatorConfig.datadogTracerPort,
 		DatadogTracerAddress:     m.mutatorConfig.datadogTracerAddress,
+		EnableOtelTracing:        isOtelCollectorEnabled(m.mutatorConfig.enableOtelCollector, pod),
+		OtelCollectorEndpoint:    m.mutatorConfig.otelCollectorEndpoint,
 		EnableStatsTags:          m.mutatorConfig.enableStatsTags,
+		EnableMetricExtension:    m.mutatorConfig.enableMetricExtension,
 		EnableStatsD:             m.mutatorConfig.enableStatsD,
 		StatsDPort:               m.mutatorConfig.statsDPort,
 		StatsDAddress:            m.mutatorConfig.statsDAddress,
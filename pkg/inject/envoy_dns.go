@@ -0,0 +1,167 @@
+package inject
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	envoyDNSPolicyCluster = "cluster"
+	envoyDNSPolicyHost    = "host"
+	envoyDNSPolicyCustom  = "custom"
+
+	defaultDNSResolverPort = 53
+	// maxDNSNdots is the maximum ndots option honored by resolv.conf.
+	maxDNSNdots    = 15
+	dnsOptionNdots = "ndots"
+)
+
+// newEnvoyDNSMutator constructs new envoyDNSMutator
+func newEnvoyDNSMutator() *envoyDNSMutator {
+	return &envoyDNSMutator{}
+}
+
+var _ PodMutator = &envoyDNSMutator{}
+
+// envoyDNSMutator configures how Envoy resolves DNS hostnames per the envoy DNS annotations of pod.
+// custom resolvers are configured through the bootstrap fragment passed to Envoy.
+type envoyDNSMutator struct {
+}
+
+func (m *envoyDNSMutator) mutate(pod *corev1.Pod) error {
+	policy, resolvers, err := parseEnvoyDNSPolicy(pod)
+	if err != nil {
+		return err
+	}
+	switch policy {
+	case envoyDNSPolicyHost:
+		pod.Spec.DNSPolicy = corev1.DNSDefault
+	case envoyDNSPolicyCustom:
+		if err := m.mutateEnvoyResolvers(pod, resolvers); err != nil {
+			return err
+		}
+	}
+	if v, ok := pod.ObjectMeta.Annotations[AppMeshEnvoyDNSNdotsAnnotation]; ok {
+		ndots, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || ndots < 0 || ndots > maxDNSNdots {
+			return errors.Errorf("malformed annotation %s, expected an integer between 0 and %d: %s", AppMeshEnvoyDNSNdotsAnnotation, maxDNSNdots, v)
+		}
+		setDNSNdots(pod, ndots)
+	}
+	return nil
+}
+
+// mutateEnvoyResolvers passes the bootstrap fragment configuring resolvers to envoy container.
+func (m *envoyDNSMutator) mutateEnvoyResolvers(pod *corev1.Pod, resolvers []string) error {
+	for idx := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[idx]
+		if container.Name != envoyContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == envoyBootstrapOverrideEnvName {
+				return errors.Errorf("annotation %s: %s can't be combined with other Envoy bootstrap overrides, configure typed_dns_resolver_config in %s instead",
+					AppMeshEnvoyDNSPolicyAnnotation, envoyDNSPolicyCustom, envoyBootstrapOverrideEnvName)
+			}
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envoyBootstrapOverrideEnvName,
+			Value: buildEnvoyDNSResolversBootstrap(resolvers),
+		})
+	}
+	return nil
+}
+
+// parseEnvoyDNSPolicy parses the envoy DNS policy of pod and the resolvers of the custom policy.
+func parseEnvoyDNSPolicy(pod *corev1.Pod) (string, []string, error) {
+	policy := envoyDNSPolicyCluster
+	if v, ok := pod.ObjectMeta.Annotations[AppMeshEnvoyDNSPolicyAnnotation]; ok {
+		policy = strings.ToLower(strings.TrimSpace(v))
+	}
+	switch policy {
+	case envoyDNSPolicyCluster, envoyDNSPolicyHost, envoyDNSPolicyCustom:
+	default:
+		return "", nil, errors.Errorf("malformed annotation %s, expected one of %s, %s or %s: %s",
+			AppMeshEnvoyDNSPolicyAnnotation, envoyDNSPolicyCluster, envoyDNSPolicyHost, envoyDNSPolicyCustom, policy)
+	}
+
+	v, ok := pod.ObjectMeta.Annotations[AppMeshEnvoyDNSResolversAnnotation]
+	if !ok {
+		if policy == envoyDNSPolicyCustom {
+			return "", nil, errors.Errorf("annotation %s: %s requires annotation %s", AppMeshEnvoyDNSPolicyAnnotation, envoyDNSPolicyCustom, AppMeshEnvoyDNSResolversAnnotation)
+		}
+		return policy, nil, nil
+	}
+	if policy != envoyDNSPolicyCustom {
+		return "", nil, errors.Errorf("annotation %s requires annotation %s: %s", AppMeshEnvoyDNSResolversAnnotation, AppMeshEnvoyDNSPolicyAnnotation, envoyDNSPolicyCustom)
+	}
+	var resolvers []string
+	for _, resolver := range strings.Split(v, ",") {
+		resolver = strings.TrimSpace(resolver)
+		if resolver == "" {
+			continue
+		}
+		address, err := parseDNSResolverAddress(resolver)
+		if err != nil {
+			return "", nil, err
+		}
+		resolvers = append(resolvers, address)
+	}
+	if len(resolvers) == 0 {
+		return "", nil, errors.Errorf("annotation %s must specify at least one resolver", AppMeshEnvoyDNSResolversAnnotation)
+	}
+	return policy, resolvers, nil
+}
+
+// parseDNSResolverAddress parses resolver of form ip or ip:port into host:port.
+func parseDNSResolverAddress(resolver string) (string, error) {
+	host, port := resolver, strconv.Itoa(defaultDNSResolverPort)
+	if h, p, err := net.SplitHostPort(resolver); err == nil {
+		host, port = h, p
+	}
+	if net.ParseIP(host) == nil {
+		return "", errors.Errorf("malformed annotation %s, expected IP addresses with optional port: %s", AppMeshEnvoyDNSResolversAnnotation, resolver)
+	}
+	if portNum, err := strconv.Atoi(port); err != nil || portNum < 1 || portNum > 65535 {
+		return "", errors.Errorf("malformed annotation %s, invalid port of resolver: %s", AppMeshEnvoyDNSResolversAnnotation, resolver)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// buildEnvoyDNSResolversBootstrap builds the bootstrap fragment making Envoy use resolvers only.
+func buildEnvoyDNSResolversBootstrap(resolvers []string) string {
+	var sb strings.Builder
+	sb.WriteString("typed_dns_resolver_config:\n")
+	sb.WriteString("  name: envoy.network.dns_resolver.cares\n")
+	sb.WriteString("  typed_config:\n")
+	sb.WriteString("    \"@type\": type.googleapis.com/envoy.extensions.network.dns_resolver.cares.v3.CaresDnsResolverConfig\n")
+	sb.WriteString("    resolvers:\n")
+	for _, resolver := range resolvers {
+		host, port, _ := net.SplitHostPort(resolver)
+		sb.WriteString(fmt.Sprintf("    - socket_address:\n        address: %q\n        port_value: %s\n", host, port))
+	}
+	return sb.String()
+}
+
+// setDNSNdots sets the ndots option of pod's resolv.conf, replacing any ndots option of pod.
+func setDNSNdots(pod *corev1.Pod, ndots int) {
+	if pod.Spec.DNSConfig == nil {
+		pod.Spec.DNSConfig = &corev1.PodDNSConfig{}
+	}
+	value := strconv.Itoa(ndots)
+	for idx := range pod.Spec.DNSConfig.Options {
+		if pod.Spec.DNSConfig.Options[idx].Name == dnsOptionNdots {
+			pod.Spec.DNSConfig.Options[idx].Value = &value
+			return
+		}
+	}
+	pod.Spec.DNSConfig.Options = append(pod.Spec.DNSConfig.Options, corev1.PodDNSConfigOption{
+		Name:  dnsOptionNdots,
+		Value: &value,
+	})
+}
@@ -0,0 +1,132 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_envoyDNSMutator_mutate(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		envoyEnv      []corev1.EnvVar
+		dnsConfig     *corev1.PodDNSConfig
+		wantDNSPolicy corev1.DNSPolicy
+		wantEnvoyEnv  []corev1.EnvVar
+		wantDNSConfig *corev1.PodDNSConfig
+		wantErr       error
+	}{
+		{
+			name:          "no annotations",
+			wantDNSPolicy: corev1.DNSClusterFirst,
+		},
+		{
+			name:          "host policy",
+			annotations:   map[string]string{AppMeshEnvoyDNSPolicyAnnotation: "host"},
+			wantDNSPolicy: corev1.DNSDefault,
+		},
+		{
+			name: "custom policy with resolvers",
+			annotations: map[string]string{
+				AppMeshEnvoyDNSPolicyAnnotation:    "Custom",
+				AppMeshEnvoyDNSResolversAnnotation: "10.0.0.2, 10.0.0.3:5353,[fd00::2]:53",
+			},
+			wantDNSPolicy: corev1.DNSClusterFirst,
+			wantEnvoyEnv: []corev1.EnvVar{{Name: envoyBootstrapOverrideEnvName, Value: `typed_dns_resolver_config:
+  name: envoy.network.dns_resolver.cares
+  typed_config:
+    "@type": type.googleapis.com/envoy.extensions.network.dns_resolver.cares.v3.CaresDnsResolverConfig
+    resolvers:
+    - socket_address:
+        address: "10.0.0.2"
+        port_value: 53
+    - socket_address:
+        address: "10.0.0.3"
+        port_value: 5353
+    - socket_address:
+        address: "fd00::2"
+        port_value: 53
+`}},
+		},
+		{
+			name: "custom policy with bootstrap override",
+			annotations: map[string]string{
+				AppMeshEnvoyDNSPolicyAnnotation:    "custom",
+				AppMeshEnvoyDNSResolversAnnotation: "10.0.0.2",
+			},
+			envoyEnv: []corev1.EnvVar{{Name: envoyBootstrapOverrideEnvName, Value: "overload_manager: {}"}},
+			wantErr:  errors.New("annotation appmesh.k8s.aws/envoyDNSPolicy: custom can't be combined with other Envoy bootstrap overrides, configure typed_dns_resolver_config in ENVOY_BOOTSTRAP_OVERRIDE_YAML instead"),
+		},
+		{
+			name:        "custom policy without resolvers",
+			annotations: map[string]string{AppMeshEnvoyDNSPolicyAnnotation: "custom"},
+			wantErr:     errors.New("annotation appmesh.k8s.aws/envoyDNSPolicy: custom requires annotation appmesh.k8s.aws/envoyDNSResolvers"),
+		},
+		{
+			name:        "resolvers without custom policy",
+			annotations: map[string]string{AppMeshEnvoyDNSResolversAnnotation: "10.0.0.2"},
+			wantErr:     errors.New("annotation appmesh.k8s.aws/envoyDNSResolvers requires annotation appmesh.k8s.aws/envoyDNSPolicy: custom"),
+		},
+		{
+			name: "malformed resolver",
+			annotations: map[string]string{
+				AppMeshEnvoyDNSPolicyAnnotation:    "custom",
+				AppMeshEnvoyDNSResolversAnnotation: "dns.example.com",
+			},
+			wantErr: errors.New("malformed annotation appmesh.k8s.aws/envoyDNSResolvers, expected IP addresses with optional port: dns.example.com"),
+		},
+		{
+			name:        "unknown policy",
+			annotations: map[string]string{AppMeshEnvoyDNSPolicyAnnotation: "split"},
+			wantErr:     errors.New("malformed annotation appmesh.k8s.aws/envoyDNSPolicy, expected one of cluster, host or custom: split"),
+		},
+		{
+			name:          "ndots replaces pod's ndots option",
+			annotations:   map[string]string{AppMeshEnvoyDNSNdotsAnnotation: "2"},
+			dnsConfig:     &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: aws.String("5")}, {Name: "edns0"}}},
+			wantDNSPolicy: corev1.DNSClusterFirst,
+			wantDNSConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: aws.String("2")}, {Name: "edns0"}}},
+		},
+		{
+			name:          "ndots without pod's dnsConfig",
+			annotations:   map[string]string{AppMeshEnvoyDNSNdotsAnnotation: "1"},
+			wantDNSPolicy: corev1.DNSClusterFirst,
+			wantDNSConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: aws.String("1")}}},
+		},
+		{
+			name:        "malformed ndots",
+			annotations: map[string]string{AppMeshEnvoyDNSNdotsAnnotation: "16"},
+			wantErr:     errors.New("malformed annotation appmesh.k8s.aws/envoyDNSNdots, expected an integer between 0 and 15: 16"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec: corev1.PodSpec{
+					DNSPolicy: corev1.DNSClusterFirst,
+					DNSConfig: tt.dnsConfig,
+					Containers: []corev1.Container{
+						{Name: "app"},
+						{Name: envoyContainerName, Env: tt.envoyEnv},
+					},
+				},
+			}
+			err := newEnvoyDNSMutator().mutate(pod)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantDNSPolicy, pod.Spec.DNSPolicy)
+				assert.Nil(t, pod.Spec.Containers[0].Env)
+				assert.Equal(t, tt.wantEnvoyEnv, pod.Spec.Containers[1].Env)
+				assert.Equal(t, tt.wantDNSConfig, pod.Spec.DNSConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package inject
+
+import (
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newEnvoyReadyReadinessGate constructs new envoyReadyReadinessGate
+func newEnvoyReadyReadinessGate(enabled bool) *envoyReadyReadinessGate {
+	return &envoyReadyReadinessGate{
+		enabled: enabled,
+	}
+}
+
+var _ PodMutator = &envoyReadyReadinessGate{}
+
+// mutator adding a readiness gate that blocks pod readiness until injected Envoy is ready to serve mesh traffic.
+type envoyReadyReadinessGate struct {
+	enabled bool
+}
+
+func (m *envoyReadyReadinessGate) mutate(pod *corev1.Pod) error {
+	if !m.enabled {
+		return nil
+	}
+	if ok, _ := containsEnvoyContainer(pod); !ok {
+		return nil
+	}
+	if !k8s.HasPodReadinessGate(pod, k8s.ConditionEnvoyReady) {
+		pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{
+			ConditionType: k8s.ConditionEnvoyReady,
+		})
+	}
+	return nil
+}
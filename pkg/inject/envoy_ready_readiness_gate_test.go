@@ -0,0 +1,63 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_envoyReadyReadinessGate_mutate(t *testing.T) {
+	envoyReadyGate := corev1.PodReadinessGate{ConditionType: k8s.ConditionEnvoyReady}
+	tests := []struct {
+		name    string
+		enabled bool
+		pod     *corev1.Pod
+		want    []corev1.PodReadinessGate
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "envoy"}}},
+			},
+			want: nil,
+		},
+		{
+			name:    "enabled for pod with envoy",
+			enabled: true,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "envoy"}}},
+			},
+			want: []corev1.PodReadinessGate{envoyReadyGate},
+		},
+		{
+			name:    "enabled for pod without envoy",
+			enabled: true,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			},
+			want: nil,
+		},
+		{
+			name:    "enabled for pod with existing gate",
+			enabled: true,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers:     []corev1.Container{{Name: "app"}, {Name: "envoy"}},
+					ReadinessGates: []corev1.PodReadinessGate{envoyReadyGate},
+				},
+			},
+			want: []corev1.PodReadinessGate{envoyReadyGate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newEnvoyReadyReadinessGate(tt.enabled)
+			pod := tt.pod.DeepCopy()
+			assert.NoError(t, m.mutate(pod))
+			assert.Equal(t, tt.want, pod.Spec.ReadinessGates)
+		})
+	}
+}
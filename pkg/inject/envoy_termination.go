@@ -0,0 +1,146 @@
+package inject
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// terminationGracePeriodMarginSeconds is reserved on top of the envoy preStop hook for containers to exit after SIGTERM.
+const terminationGracePeriodMarginSeconds = 5
+
+type envoyTerminationMutatorConfig struct {
+	preStopDelay          string
+	drainDuration         int32
+	adminAccessPort       int32
+	alignTerminationGrace bool
+}
+
+// newEnvoyTerminationMutator constructs new envoyTerminationMutator
+func newEnvoyTerminationMutator(mutatorConfig envoyTerminationMutatorConfig) *envoyTerminationMutator {
+	return &envoyTerminationMutator{
+		mutatorConfig: mutatorConfig,
+	}
+}
+
+var _ PodMutator = &envoyTerminationMutator{}
+
+// envoyTerminationMutator configures the drain sequence of the envoy preStop hook,
+// and aligns pod's terminationGracePeriodSeconds with it.
+// The preStop hook injected by envoyMutator is kept as is unless draining or a preStop delay annotation is configured.
+type envoyTerminationMutator struct {
+	mutatorConfig envoyTerminationMutatorConfig
+}
+
+func (m *envoyTerminationMutator) mutate(pod *corev1.Pod) error {
+	var envoy *corev1.Container
+	for idx := range pod.Spec.Containers {
+		if pod.Spec.Containers[idx].Name == envoyContainerName {
+			envoy = &pod.Spec.Containers[idx]
+		}
+	}
+	if envoy == nil || envoy.Lifecycle == nil || envoy.Lifecycle.PreStop == nil {
+		return nil
+	}
+
+	preStopDelay, preStopDelayOverridden, err := m.getPreStopDelay(pod)
+	if err != nil {
+		return err
+	}
+	drainDuration, err := m.getDrainDuration(pod)
+	if err != nil {
+		return err
+	}
+	if preStopDelayOverridden || drainDuration > 0 {
+		var command []string
+		if isWindowsPod(pod) {
+			command = []string{"powershell", "-Command", envoyWindowsPreStopCommand(preStopDelay, drainDuration, m.mutatorConfig.adminAccessPort)}
+		} else {
+			command = []string{"sh", "-c", envoyPreStopCommand(preStopDelay, drainDuration, m.mutatorConfig.adminAccessPort)}
+		}
+		envoy.Lifecycle.PreStop = &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: command},
+		}
+	}
+
+	if !m.isTerminationGraceAlignmentEnabled(pod) {
+		return nil
+	}
+	preStopDelaySeconds, err := parseSeconds(preStopDelay)
+	if err != nil {
+		return errors.Errorf("unable to align terminationGracePeriodSeconds, preStop delay must be a number of seconds: %s", preStopDelay)
+	}
+	required := preStopDelaySeconds + int64(drainDuration) + terminationGracePeriodMarginSeconds
+	if pod.Spec.TerminationGracePeriodSeconds == nil || *pod.Spec.TerminationGracePeriodSeconds < required {
+		pod.Spec.TerminationGracePeriodSeconds = &required
+	}
+	return nil
+}
+
+// getPreStopDelay returns the preStop delay of pod, and whether it's overridden by pod annotation.
+func (m *envoyTerminationMutator) getPreStopDelay(pod *corev1.Pod) (string, bool, error) {
+	v, ok := pod.ObjectMeta.Annotations[AppMeshPreStopDelayAnnotation]
+	if !ok {
+		return m.mutatorConfig.preStopDelay, false, nil
+	}
+	seconds, err := parseSeconds(v)
+	if err != nil {
+		return "", false, errors.Errorf("malformed annotation %s, expected a non-negative number of seconds: %s", AppMeshPreStopDelayAnnotation, v)
+	}
+	return strconv.FormatInt(seconds, 10), true, nil
+}
+
+// getDrainDuration returns the drain duration of pod in seconds.
+func (m *envoyTerminationMutator) getDrainDuration(pod *corev1.Pod) (int32, error) {
+	v, ok := pod.ObjectMeta.Annotations[AppMeshEnvoyDrainDurationAnnotation]
+	if !ok {
+		return m.mutatorConfig.drainDuration, nil
+	}
+	seconds, err := parseSeconds(v)
+	if err != nil || seconds > math.MaxInt32 {
+		return 0, errors.Errorf("malformed annotation %s, expected a non-negative number of seconds: %s", AppMeshEnvoyDrainDurationAnnotation, v)
+	}
+	return int32(seconds), nil
+}
+
+func (m *envoyTerminationMutator) isTerminationGraceAlignmentEnabled(pod *corev1.Pod) bool {
+	if v, ok := pod.ObjectMeta.Annotations[AppMeshAlignTerminationGraceAnnotation]; ok {
+		return strings.ToLower(v) == "enabled"
+	}
+	return m.mutatorConfig.alignTerminationGrace
+}
+
+// envoyPreStopCommand returns the preStop command sleeping preStopDelay, then draining envoy listeners gracefully for drainDuration.
+func envoyPreStopCommand(preStopDelay string, drainDuration int32, adminAccessPort int32) string {
+	command := fmt.Sprintf("sleep %s", preStopDelay)
+	if drainDuration > 0 {
+		command += fmt.Sprintf("; curl -s -X POST 'http://localhost:%d/drain_listeners?graceful'; sleep %d", adminAccessPort, drainDuration)
+	}
+	return command
+}
+
+// envoyWindowsPreStopCommand is the powershell variant of envoyPreStopCommand.
+func envoyWindowsPreStopCommand(preStopDelay string, drainDuration int32, adminAccessPort int32) string {
+	command := fmt.Sprintf("Start-Sleep -Seconds %s", preStopDelay)
+	if drainDuration > 0 {
+		command += fmt.Sprintf("; Invoke-WebRequest -UseBasicParsing -Method POST 'http://localhost:%d/drain_listeners?graceful' | Out-Null; Start-Sleep -Seconds %d",
+			adminAccessPort, drainDuration)
+	}
+	return command
+}
+
+// parseSeconds parses a non-negative number of seconds, optionally suffixed by "s".
+func parseSeconds(value string) (int64, error) {
+	seconds, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), "s"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if seconds < 0 {
+		return 0, errors.Errorf("negative seconds: %s", value)
+	}
+	return seconds, nil
+}
@@ -0,0 +1,124 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_envoyTerminationMutator_mutate(t *testing.T) {
+	defaultPreStop := &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "sleep 20"}},
+	}
+	tests := []struct {
+		name                 string
+		mutatorConfig        envoyTerminationMutatorConfig
+		annotations          map[string]string
+		nodeSelector         map[string]string
+		terminationGrace     *int64
+		wantPreStop          *corev1.LifecycleHandler
+		wantTerminationGrace *int64
+		wantErr              error
+	}{
+		{
+			name:                 "no drain configured",
+			mutatorConfig:        envoyTerminationMutatorConfig{preStopDelay: "20", adminAccessPort: 9901},
+			terminationGrace:     aws.Int64(30),
+			wantPreStop:          defaultPreStop,
+			wantTerminationGrace: aws.Int64(30),
+		},
+		{
+			name:          "drain configured by controller",
+			mutatorConfig: envoyTerminationMutatorConfig{preStopDelay: "20", drainDuration: 30, adminAccessPort: 9901},
+			wantPreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{
+					"sh", "-c", "sleep 20; curl -s -X POST 'http://localhost:9901/drain_listeners?graceful'; sleep 30",
+				}},
+			},
+		},
+		{
+			name:          "preStop delay and drain configured by annotations",
+			mutatorConfig: envoyTerminationMutatorConfig{preStopDelay: "20", drainDuration: 30, adminAccessPort: 9901},
+			annotations: map[string]string{
+				AppMeshPreStopDelayAnnotation:       "5",
+				AppMeshEnvoyDrainDurationAnnotation: "0",
+			},
+			wantPreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "sleep 5"}},
+			},
+		},
+		{
+			name:          "drain on windows pod",
+			mutatorConfig: envoyTerminationMutatorConfig{preStopDelay: "20", drainDuration: 30, adminAccessPort: 9901},
+			nodeSelector:  map[string]string{nodeOSLabel: nodeOSWindows},
+			wantPreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{
+					"powershell", "-Command", "Start-Sleep -Seconds 20; Invoke-WebRequest -UseBasicParsing -Method POST 'http://localhost:9901/drain_listeners?graceful' | Out-Null; Start-Sleep -Seconds 30",
+				}},
+			},
+		},
+		{
+			name:                 "terminationGracePeriod raised to cover preStop",
+			mutatorConfig:        envoyTerminationMutatorConfig{preStopDelay: "20", drainDuration: 30, adminAccessPort: 9901, alignTerminationGrace: true},
+			terminationGrace:     aws.Int64(30),
+			wantPreStop:          &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "sleep 20; curl -s -X POST 'http://localhost:9901/drain_listeners?graceful'; sleep 30"}}},
+			wantTerminationGrace: aws.Int64(55),
+		},
+		{
+			name:                 "longer terminationGracePeriod is kept",
+			mutatorConfig:        envoyTerminationMutatorConfig{preStopDelay: "20", adminAccessPort: 9901},
+			annotations:          map[string]string{AppMeshAlignTerminationGraceAnnotation: "enabled"},
+			terminationGrace:     aws.Int64(120),
+			wantPreStop:          defaultPreStop,
+			wantTerminationGrace: aws.Int64(120),
+		},
+		{
+			name:                 "alignment disabled by annotation",
+			mutatorConfig:        envoyTerminationMutatorConfig{preStopDelay: "20", adminAccessPort: 9901, alignTerminationGrace: true},
+			annotations:          map[string]string{AppMeshAlignTerminationGraceAnnotation: "disabled"},
+			terminationGrace:     aws.Int64(10),
+			wantPreStop:          defaultPreStop,
+			wantTerminationGrace: aws.Int64(10),
+		},
+		{
+			name:          "malformed drain duration annotation",
+			mutatorConfig: envoyTerminationMutatorConfig{preStopDelay: "20", adminAccessPort: 9901},
+			annotations:   map[string]string{AppMeshEnvoyDrainDurationAnnotation: "-1"},
+			wantErr:       errors.New("malformed annotation appmesh.k8s.aws/envoyDrainDuration, expected a non-negative number of seconds: -1"),
+		},
+		{
+			name:          "malformed preStop delay annotation",
+			mutatorConfig: envoyTerminationMutatorConfig{preStopDelay: "20", adminAccessPort: 9901},
+			annotations:   map[string]string{AppMeshPreStopDelayAnnotation: "1m"},
+			wantErr:       errors.New("malformed annotation appmesh.k8s.aws/preStopDelay, expected a non-negative number of seconds: 1m"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec: corev1.PodSpec{
+					NodeSelector:                  tt.nodeSelector,
+					TerminationGracePeriodSeconds: tt.terminationGrace,
+					Containers: []corev1.Container{
+						{Name: "app"},
+						{Name: envoyContainerName, Lifecycle: &corev1.Lifecycle{PreStop: defaultPreStop.DeepCopy()}},
+					},
+				},
+			}
+			err := newEnvoyTerminationMutator(tt.mutatorConfig).mutate(pod)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, pod.Spec.Containers[0].Lifecycle)
+				assert.Equal(t, tt.wantPreStop, pod.Spec.Containers[1].Lifecycle.PreStop)
+				assert.Equal(t, tt.wantTerminationGrace, pod.Spec.TerminationGracePeriodSeconds)
+			}
+		})
+	}
+}
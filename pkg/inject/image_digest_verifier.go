@@ -0,0 +1,160 @@
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// dockerHubRegistryHost serves the registry API of dockerHubRegistry.
+	dockerHubRegistryHost = "registry-1.docker.io"
+	// imageDigestVerifyTimeout bounds each request to registries, which happen during pod admission.
+	imageDigestVerifyTimeout = 5 * time.Second
+)
+
+// manifestMediaTypes are the media types of image manifests and indexes, which digests of multi-arch images refer to.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+var bearerChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// imageDigestVerifier verifies images pinned to a digest exist.
+type imageDigestVerifier interface {
+	// verify checks the manifest of image, of the form repository@digest, exists in its registry.
+	verify(ctx context.Context, image string) error
+}
+
+// newRegistryImageDigestVerifier constructs new registryImageDigestVerifier
+func newRegistryImageDigestVerifier(httpClient *http.Client) *registryImageDigestVerifier {
+	return &registryImageDigestVerifier{
+		httpClient: httpClient,
+	}
+}
+
+var _ imageDigestVerifier = &registryImageDigestVerifier{}
+
+// registryImageDigestVerifier verifies digests with the registry HTTP API, anonymously or with an anonymous bearer token.
+// digests are immutable, so images found are cached for the lifetime of the controller.
+type registryImageDigestVerifier struct {
+	httpClient     *http.Client
+	verifiedImages sync.Map
+}
+
+func (v *registryImageDigestVerifier) verify(ctx context.Context, image string) error {
+	if _, ok := v.verifiedImages.Load(image); ok {
+		return nil
+	}
+	repository, _, digest := splitImageReference(image)
+	if len(digest) == 0 {
+		return errors.Errorf("image %s isn't pinned to a digest", image)
+	}
+	registry, path := splitImageRegistry(repository)
+	if registry == dockerHubRegistry {
+		registry = dockerHubRegistryHost
+		if !strings.Contains(path, "/") {
+			path = "library/" + path
+		}
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, path, digest)
+	resp, err := v.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify image %s", image)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := v.fetchAnonymousToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return errors.Wrapf(err, "failed to authenticate to the registry of image %s", image)
+		}
+		if resp, err = v.headManifest(ctx, manifestURL, token); err != nil {
+			return errors.Wrapf(err, "failed to verify image %s", image)
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errors.Errorf("image %s not found in its registry", image)
+	default:
+		return errors.Errorf("failed to verify image %s, registry responded %s", image, resp.Status)
+	}
+	if contentDigest := resp.Header.Get("Docker-Content-Digest"); len(contentDigest) != 0 && contentDigest != digest {
+		return errors.Errorf("image %s resolved to digest %s", image, contentDigest)
+	}
+	v.verifiedImages.Store(image, struct{}{})
+	return nil
+}
+
+func (v *registryImageDigestVerifier) headManifest(ctx context.Context, manifestURL string, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if len(token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchAnonymousToken fetches a token from the realm of the Bearer challenge of a registry, as registries like public ECR or docker hub require for anonymous pulls.
+func (v *registryImageDigestVerifier) fetchAnonymousToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", errors.Errorf("unsupported authentication challenge %q, only anonymous access is supported", challenge)
+	}
+	params := make(map[string]string)
+	for _, match := range bearerChallengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(realm.Host) == 0 {
+		return "", errors.Errorf("invalid realm of authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, param := range []string{"service", "scope"} {
+		if value, ok := params[param]; ok {
+			query.Set(param, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("token endpoint responded %s", resp.Status)
+	}
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", errors.Wrap(err, "failed to decode token")
+	}
+	if len(tokenResp.Token) != 0 {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
@@ -0,0 +1,84 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_registryImageDigestVerifier_verify(t *testing.T) {
+	missingDigest := "sha256:" + strings.Repeat("d", 64)
+	tests := []struct {
+		name         string
+		requireToken bool
+		image        func(registry string) string
+		wantErr      func(registry string) string
+	}{
+		{
+			name:  "digest found",
+			image: func(registry string) string { return registry + "/appmesh/aws-appmesh-envoy@" + envoyDigest },
+		},
+		{
+			name:         "digest found with anonymous token",
+			requireToken: true,
+			image:        func(registry string) string { return registry + "/appmesh/aws-appmesh-envoy@" + envoyDigest },
+		},
+		{
+			name:  "digest not found",
+			image: func(registry string) string { return registry + "/appmesh/aws-appmesh-envoy@" + missingDigest },
+			wantErr: func(registry string) string {
+				return fmt.Sprintf("image %s/appmesh/aws-appmesh-envoy@%s not found in its registry", registry, missingDigest)
+			},
+		},
+		{
+			name:  "image not pinned",
+			image: func(registry string) string { return registry + "/appmesh/aws-appmesh-envoy:v1.27.3.0-prod" },
+			wantErr: func(registry string) string {
+				return fmt.Sprintf("image %s/appmesh/aws-appmesh-envoy:v1.27.3.0-prod isn't pinned to a digest", registry)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			var server *httptest.Server
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					assert.Equal(t, "repository:appmesh/aws-appmesh-envoy:pull", r.URL.Query().Get("scope"))
+					fmt.Fprint(w, `{"token": "anonymous"}`)
+					return
+				}
+				requests++
+				if tt.requireToken && r.Header.Get("Authorization") != "Bearer anonymous" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:appmesh/aws-appmesh-envoy:pull"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if r.Method != http.MethodHead || r.URL.Path != "/v2/appmesh/aws-appmesh-envoy/manifests/"+envoyDigest {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Docker-Content-Digest", envoyDigest)
+			}))
+			defer server.Close()
+			registry := strings.TrimPrefix(server.URL, "https://")
+
+			v := newRegistryImageDigestVerifier(server.Client())
+			err := v.verify(context.Background(), tt.image(registry))
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr(registry))
+				return
+			}
+			assert.NoError(t, err)
+			// found images are cached.
+			requestsBefore := requests
+			assert.NoError(t, v.verify(context.Background(), tt.image(registry)))
+			assert.Equal(t, requestsBefore, requests)
+		})
+	}
+}
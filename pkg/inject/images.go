@@ -0,0 +1,189 @@
+package inject
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// dockerHubRegistry is the registry of images whose repository doesn't start with a registry host.
+	dockerHubRegistry = "docker.io"
+)
+
+var (
+	imageDigestRegexp = regexp.MustCompile(`^(sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+	// imageRegistryRegexp matches a registry host with an optional port, followed by an optional repository path.
+	imageRegistryRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*)(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+)
+
+// sidecarImages are the images of the sidecars injected into a pod, after applying the image registry and digests
+// of the controller and of the pod's namespace.
+type sidecarImages struct {
+	// sidecarImageRepository is the Envoy image repository, referenced by digest if set, otherwise by the configured tags.
+	sidecarImageRepository    string
+	sidecarImageDigest        string
+	sidecarWindowsImageDigest string
+	initImage                 string
+	xRayImage                 string
+	// pinnedImages are the images referenced by a configured digest.
+	pinnedImages []string
+}
+
+// resolveSidecarImages resolves the sidecar images of pod, and verifies their digests resolve if enabled.
+func (m *SidecarInjector) resolveSidecarImages(ctx context.Context, pod *corev1.Pod) (sidecarImages, error) {
+	req := webhook.ContextGetAdmissionRequest(ctx)
+	objectNS := &corev1.Namespace{}
+	if err := m.k8sClient.Get(ctx, types.NamespacedName{Name: req.Namespace}, objectNS); err != nil {
+		return sidecarImages{}, err
+	}
+	images, err := buildSidecarImages(m.config, objectNS.Annotations)
+	if err != nil {
+		return sidecarImages{}, errors.Wrapf(err, "invalid image annotation of namespace %s", req.Namespace)
+	}
+	if m.config.VerifyImageDigests {
+		for _, image := range images.pinnedImages {
+			if err := m.imageDigestVerifier.verify(ctx, image); err != nil {
+				return sidecarImages{}, errors.Wrapf(err, "failed to verify image of pod %s", k8s.PodIdentifier(req.Namespace, pod))
+			}
+		}
+	}
+	return images, nil
+}
+
+// buildSidecarImages builds the sidecar images from cfg, overridden by the image annotations of a namespace.
+func buildSidecarImages(cfg Config, namespaceAnnotations map[string]string) (sidecarImages, error) {
+	registry := cfg.ImageRegistry
+	if v, ok := namespaceAnnotations[AppMeshImageRegistryAnnotation]; ok {
+		if err := validateImageRegistry(v); err != nil {
+			return sidecarImages{}, errors.Wrap(err, AppMeshImageRegistryAnnotation)
+		}
+		registry = v
+	}
+	digests := map[string]string{
+		AppMeshSidecarImageDigestAnnotation:        cfg.SidecarImageDigest,
+		AppMeshSidecarWindowsImageDigestAnnotation: cfg.SidecarWindowsImageDigest,
+		AppMeshInitImageDigestAnnotation:           cfg.InitImageDigest,
+		AppMeshXRayImageDigestAnnotation:           cfg.XRayImageDigest,
+	}
+	for annotation := range digests {
+		if v, ok := namespaceAnnotations[annotation]; ok {
+			if err := validateImageDigest(v); err != nil {
+				return sidecarImages{}, errors.Wrap(err, annotation)
+			}
+			digests[annotation] = v
+		}
+	}
+
+	images := sidecarImages{
+		sidecarImageRepository:    imageWithRegistry(cfg.SidecarImageRepository, registry),
+		sidecarImageDigest:        digests[AppMeshSidecarImageDigestAnnotation],
+		sidecarWindowsImageDigest: digests[AppMeshSidecarWindowsImageDigestAnnotation],
+		initImage:                 pinImage(cfg.InitImage, registry, digests[AppMeshInitImageDigestAnnotation]),
+		xRayImage:                 pinImage(cfg.XRayImage, registry, digests[AppMeshXRayImageDigestAnnotation]),
+	}
+	if len(images.sidecarImageDigest) != 0 {
+		images.pinnedImages = append(images.pinnedImages, imageReference(images.sidecarImageRepository, "", images.sidecarImageDigest))
+	}
+	if len(images.sidecarWindowsImageDigest) != 0 {
+		images.pinnedImages = append(images.pinnedImages, imageReference(images.sidecarImageRepository, "", images.sidecarWindowsImageDigest))
+	}
+	if len(digests[AppMeshInitImageDigestAnnotation]) != 0 {
+		images.pinnedImages = append(images.pinnedImages, images.initImage)
+	}
+	if len(digests[AppMeshXRayImageDigestAnnotation]) != 0 && cfg.EnableXrayTracing {
+		images.pinnedImages = append(images.pinnedImages, images.xRayImage)
+	}
+	return images, nil
+}
+
+// imageReference references the image of repository by digest if set, otherwise by tag.
+func imageReference(repository string, tag string, digest string) string {
+	if len(digest) != 0 {
+		return repository + "@" + digest
+	}
+	return repository + ":" + tag
+}
+
+// pinImage replaces the registry of image with registry and its tag with digest, if they're set.
+func pinImage(image string, registry string, digest string) string {
+	if len(image) == 0 {
+		return image
+	}
+	repository, tag, imageDigest := splitImageReference(image)
+	repository = imageWithRegistry(repository, registry)
+	if len(digest) == 0 {
+		digest = imageDigest
+	}
+	switch {
+	case len(digest) != 0:
+		return imageReference(repository, "", digest)
+	case len(tag) != 0:
+		return imageReference(repository, tag, "")
+	}
+	return repository
+}
+
+// imageWithRegistry replaces the registry of the repository with registry if set, keeping its path,
+// e.g. public.ecr.aws/appmesh/aws-appmesh-envoy becomes registry.example.com/mirror/appmesh/aws-appmesh-envoy with registry.example.com/mirror.
+func imageWithRegistry(repository string, registry string) string {
+	if len(registry) == 0 {
+		return repository
+	}
+	_, path := splitImageRegistry(repository)
+	return registry + "/" + path
+}
+
+// splitImageReference splits image of the form repository[:tag][@digest].
+func splitImageReference(image string) (repository string, tag string, digest string) {
+	repository = image
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		repository, digest = repository[:idx], repository[idx+1:]
+	}
+	// a colon before the last slash separates the port of the registry instead of a tag.
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:idx], repository[idx+1:]
+	}
+	return repository, tag, digest
+}
+
+// splitImageRegistry splits repository into its registry and path.
+// Like docker, the first component of repository is a registry if it contains a dot or port, or is localhost.
+func splitImageRegistry(repository string) (registry string, path string) {
+	if idx := strings.Index(repository, "/"); idx >= 0 {
+		host := repository[:idx]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			return host, repository[idx+1:]
+		}
+	}
+	return dockerHubRegistry, repository
+}
+
+// validateImageRegistry validates registry is empty or a registry host with an optional port and path, e.g. registry.example.com:5000/mirror.
+func validateImageRegistry(registry string) error {
+	if len(registry) == 0 {
+		return nil
+	}
+	if !imageRegistryRegexp.MatchString(registry) {
+		return errors.Errorf("must be a registry host with an optional port and path: %q", registry)
+	}
+	host := strings.SplitN(registry, "/", 2)[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return errors.Errorf("must start with a registry host containing a dot or port, or localhost: %q", registry)
+	}
+	return nil
+}
+
+// validateImageDigest validates digest is empty or a sha256 or sha512 digest.
+func validateImageDigest(digest string) error {
+	if len(digest) == 0 || imageDigestRegexp.MatchString(digest) {
+		return nil
+	}
+	return errors.Errorf("must be a digest of the form sha256:<64 hex digits> or sha512:<128 hex digits>: %q", digest)
+}
@@ -0,0 +1,341 @@
+package inject
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	envoyDigest = "sha256:" + strings.Repeat("a", 64)
+	initDigest  = "sha256:" + strings.Repeat("b", 64)
+	xrayDigest  = "sha256:" + strings.Repeat("c", 64)
+)
+
+func imagesConfig() Config {
+	return Config{
+		SidecarImageRepository: "public.ecr.aws/appmesh/aws-appmesh-envoy",
+		SidecarImageTag:        "v1.27.3.0-prod",
+		SidecarWindowsImageTag: "v1.27.3.0-prod-windows",
+		InitImage:              "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-proxy-route-manager:v7-prod",
+		XRayImage:              "public.ecr.aws/xray/aws-xray-daemon",
+		EnableXrayTracing:      true,
+	}
+}
+
+func Test_buildSidecarImages(t *testing.T) {
+	tests := []struct {
+		name                 string
+		conf                 func(Config) Config
+		namespaceAnnotations map[string]string
+		want                 sidecarImages
+		wantErr              error
+	}{
+		{
+			name: "no overrides",
+			want: sidecarImages{
+				sidecarImageRepository: "public.ecr.aws/appmesh/aws-appmesh-envoy",
+				initImage:              "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-proxy-route-manager:v7-prod",
+				xRayImage:              "public.ecr.aws/xray/aws-xray-daemon",
+			},
+		},
+		{
+			name: "controller registry and digests",
+			conf: func(cfg Config) Config {
+				cfg.ImageRegistry = "registry.example.com:5000/mirror"
+				cfg.SidecarImageDigest = envoyDigest
+				cfg.InitImageDigest = initDigest
+				return cfg
+			},
+			want: sidecarImages{
+				sidecarImageRepository: "registry.example.com:5000/mirror/appmesh/aws-appmesh-envoy",
+				sidecarImageDigest:     envoyDigest,
+				initImage:              "registry.example.com:5000/mirror/aws-appmesh-proxy-route-manager@" + initDigest,
+				xRayImage:              "registry.example.com:5000/mirror/xray/aws-xray-daemon",
+				pinnedImages: []string{
+					"registry.example.com:5000/mirror/appmesh/aws-appmesh-envoy@" + envoyDigest,
+					"registry.example.com:5000/mirror/aws-appmesh-proxy-route-manager@" + initDigest,
+				},
+			},
+		},
+		{
+			name: "namespace annotations override controller settings",
+			conf: func(cfg Config) Config {
+				cfg.ImageRegistry = "registry.example.com"
+				cfg.SidecarImageDigest = envoyDigest
+				return cfg
+			},
+			namespaceAnnotations: map[string]string{
+				AppMeshImageRegistryAnnotation:      "localhost:5000",
+				AppMeshSidecarImageDigestAnnotation: "",
+				AppMeshXRayImageDigestAnnotation:    xrayDigest,
+			},
+			want: sidecarImages{
+				sidecarImageRepository: "localhost:5000/appmesh/aws-appmesh-envoy",
+				initImage:              "localhost:5000/aws-appmesh-proxy-route-manager:v7-prod",
+				xRayImage:              "localhost:5000/xray/aws-xray-daemon@" + xrayDigest,
+				pinnedImages:           []string{"localhost:5000/xray/aws-xray-daemon@" + xrayDigest},
+			},
+		},
+		{
+			name: "namespace annotation disables controller registry",
+			conf: func(cfg Config) Config {
+				cfg.ImageRegistry = "registry.example.com"
+				return cfg
+			},
+			namespaceAnnotations: map[string]string{AppMeshImageRegistryAnnotation: ""},
+			want: sidecarImages{
+				sidecarImageRepository: "public.ecr.aws/appmesh/aws-appmesh-envoy",
+				initImage:              "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-proxy-route-manager:v7-prod",
+				xRayImage:              "public.ecr.aws/xray/aws-xray-daemon",
+			},
+		},
+		{
+			name: "pinned X-Ray image not verified without X-Ray tracing",
+			conf: func(cfg Config) Config {
+				cfg.EnableXrayTracing = false
+				return cfg
+			},
+			namespaceAnnotations: map[string]string{AppMeshXRayImageDigestAnnotation: xrayDigest},
+			want: sidecarImages{
+				sidecarImageRepository: "public.ecr.aws/appmesh/aws-appmesh-envoy",
+				initImage:              "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-proxy-route-manager:v7-prod",
+				xRayImage:              "public.ecr.aws/xray/aws-xray-daemon@" + xrayDigest,
+			},
+		},
+		{
+			name:                 "invalid registry annotation",
+			namespaceAnnotations: map[string]string{AppMeshImageRegistryAnnotation: "https://registry.example.com"},
+			wantErr:              errors.New(`appmesh.k8s.aws/imageRegistry: must be a registry host with an optional port and path: "https://registry.example.com"`),
+		},
+		{
+			name:                 "invalid digest annotation",
+			namespaceAnnotations: map[string]string{AppMeshInitImageDigestAnnotation: "sha256:abc"},
+			wantErr:              errors.New(`appmesh.k8s.aws/initImageDigest: must be a digest of the form sha256:<64 hex digits> or sha512:<128 hex digits>: "sha256:abc"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := imagesConfig()
+			if tt.conf != nil {
+				cfg = tt.conf(cfg)
+			}
+			got, err := buildSidecarImages(cfg, tt.namespaceAnnotations)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_pinImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		registry string
+		digest   string
+		want     string
+	}{
+		{
+			name:  "image kept as is",
+			image: "public.ecr.aws/xray/aws-xray-daemon:latest",
+			want:  "public.ecr.aws/xray/aws-xray-daemon:latest",
+		},
+		{
+			name:   "tag replaced by digest",
+			image:  "public.ecr.aws/xray/aws-xray-daemon:latest",
+			digest: xrayDigest,
+			want:   "public.ecr.aws/xray/aws-xray-daemon@" + xrayDigest,
+		},
+		{
+			name:     "registry with port replaced",
+			image:    "localhost:5000/xray/aws-xray-daemon:latest",
+			registry: "registry.example.com",
+			want:     "registry.example.com/xray/aws-xray-daemon:latest",
+		},
+		{
+			name:     "registry of docker hub image replaced",
+			image:    "amazon/aws-xray-daemon",
+			registry: "registry.example.com",
+			want:     "registry.example.com/amazon/aws-xray-daemon",
+		},
+		{
+			name:     "pinned digest kept",
+			image:    "public.ecr.aws/xray/aws-xray-daemon:latest@" + xrayDigest,
+			registry: "registry.example.com",
+			want:     "registry.example.com/xray/aws-xray-daemon@" + xrayDigest,
+		},
+		{
+			name:     "empty image",
+			registry: "registry.example.com",
+			digest:   xrayDigest,
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pinImage(tt.image, tt.registry, tt.digest)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validateImageRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		wantErr  error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "host",
+			registry: "123456789012.dkr.ecr.us-west-2.amazonaws.com",
+		},
+		{
+			name:     "host with port and path",
+			registry: "registry.example.com:5000/mirror/appmesh",
+		},
+		{
+			name:     "localhost",
+			registry: "localhost/mirror",
+		},
+		{
+			name:     "path without host",
+			registry: "mirror/appmesh",
+			wantErr:  errors.New(`must start with a registry host containing a dot or port, or localhost: "mirror/appmesh"`),
+		},
+		{
+			name:     "trailing slash",
+			registry: "registry.example.com/",
+			wantErr:  errors.New(`must be a registry host with an optional port and path: "registry.example.com/"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageRegistry(tt.registry)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateImageDigest(t *testing.T) {
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "sha256", digest: envoyDigest},
+		{name: "sha512", digest: "sha512:" + strings.Repeat("0", 128)},
+		{name: "tag", digest: "v1.27.3.0-prod", wantErr: true},
+		{name: "uppercase hex", digest: "sha256:" + strings.Repeat("A", 64), wantErr: true},
+		{name: "truncated", digest: envoyDigest[:70], wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageDigest(tt.digest)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+type fakeImageDigestVerifier struct {
+	missingImages map[string]bool
+	verified      []string
+}
+
+func (v *fakeImageDigestVerifier) verify(_ context.Context, image string) error {
+	v.verified = append(v.verified, image)
+	if v.missingImages[image] {
+		return errors.Errorf("image %s not found in its registry", image)
+	}
+	return nil
+}
+
+func TestSidecarInjector_resolveSidecarImages(t *testing.T) {
+	pinnedEnvoy := "registry.example.com/appmesh/aws-appmesh-envoy@" + envoyDigest
+	tests := []struct {
+		name               string
+		verify             bool
+		missingImages      map[string]bool
+		wantVerifiedImages []string
+		wantErr            error
+	}{
+		{
+			name: "digests not verified",
+		},
+		{
+			name:               "digests verified",
+			verify:             true,
+			wantVerifiedImages: []string{pinnedEnvoy},
+		},
+		{
+			name:               "digest not found",
+			verify:             true,
+			missingImages:      map[string]bool{pinnedEnvoy: true},
+			wantVerifiedImages: []string{pinnedEnvoy},
+			wantErr:            errors.New("failed to verify image of pod awesome-ns/my-pod: image " + pinnedEnvoy + " not found in its registry"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).Build()
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "awesome-ns",
+					Annotations: map[string]string{
+						AppMeshImageRegistryAnnotation:      "registry.example.com",
+						AppMeshSidecarImageDigestAnnotation: envoyDigest,
+					},
+				},
+			}
+			assert.NoError(t, k8sClient.Create(ctx, ns))
+			verifier := &fakeImageDigestVerifier{missingImages: tt.missingImages}
+			cfg := imagesConfig()
+			cfg.VerifyImageDigests = tt.verify
+			m := &SidecarInjector{
+				config:              cfg,
+				k8sClient:           k8sClient,
+				imageDigestVerifier: verifier,
+			}
+			ctx = webhook.ContextWithAdmissionRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Namespace: "awesome-ns"},
+			})
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod"}}
+			got, err := m.resolveSidecarImages(ctx, pod)
+			assert.Equal(t, tt.wantVerifiedImages, verifier.verified)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "registry.example.com/appmesh/aws-appmesh-envoy", got.sidecarImageRepository)
+				assert.Equal(t, envoyDigest, got.sidecarImageDigest)
+			}
+		})
+	}
+}
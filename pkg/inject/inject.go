@@ -2,9 +2,12 @@ package inject
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
@@ -29,13 +32,16 @@ type SidecarInjector struct {
 	controllerVersion      string
 	k8sVersion             string
 	k8sClient              client.Client
+	ssmSDK                 services.SSM
 	referenceResolver      references.Resolver
 	vgMembershipDesignator virtualgateway.MembershipDesignator
 	vnMembershipDesignator virtualnode.MembershipDesignator
+	imageDigestVerifier    imageDigestVerifier
 }
 
 func NewSidecarInjector(cfg Config, accountID string, awsRegion string, controllerVersion string, k8sVersion string,
 	k8sClient client.Client,
+	ssmSDK services.SSM,
 	referenceResolver references.Resolver,
 	vnMembershipDesignator virtualnode.MembershipDesignator,
 	vgMembershipDesignator virtualgateway.MembershipDesignator) *SidecarInjector {
@@ -46,9 +52,11 @@ func NewSidecarInjector(cfg Config, accountID string, awsRegion string, controll
 		controllerVersion:      controllerVersion,
 		k8sVersion:             k8sVersion,
 		k8sClient:              k8sClient,
+		ssmSDK:                 ssmSDK,
 		referenceResolver:      referenceResolver,
 		vgMembershipDesignator: vgMembershipDesignator,
 		vnMembershipDesignator: vnMembershipDesignator,
+		imageDigestVerifier:    newRegistryImageDigestVerifier(&http.Client{Timeout: imageDigestVerifyTimeout}),
 	}
 }
 
@@ -71,7 +79,8 @@ func (m *SidecarInjector) Inject(ctx context.Context, pod *corev1.Pod) error {
 	}
 
 	if vn != nil && vg != nil {
-		return errors.Errorf("sidecarInject enabled for both virtualNode %s and virtualGateway %s on pod %s. Please use podSelector on one", vn.Name, vg.Name, pod.Name)
+		return errors.Errorf("sidecarInject enabled for both virtualNode %s and virtualGateway %s on pod %s. Please use podSelector on one", vn.Name, vg.Name,
+			k8s.PodIdentifier(webhook.ContextGetAdmissionRequest(ctx).Namespace, pod))
 	}
 
 	if (vn == nil || vn.Spec.MeshRef == nil) && (vg == nil || vg.Spec.MeshRef == nil) {
@@ -94,19 +103,37 @@ func (m *SidecarInjector) Inject(ctx context.Context, pod *corev1.Pod) error {
 	if err != nil {
 		return err
 	}
-	return m.injectAppMeshPatches(ms, vn, vg, pod)
+
+	images, err := m.resolveSidecarImages(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	var bootstrapOverride *corev1.EnvVar
+	if m.config.EnableBootstrapOverrides {
+		bootstrapOverride, err = resolveEnvoyBootstrapOverride(ctx, m.ssmSDK, m.config.BootstrapOverrideSSMParameterPrefixes, m.config.BootstrapOverrideAllowSecureString, pod)
+		if err != nil {
+			return err
+		}
+	}
+	return m.injectAppMeshPatches(ms, vn, vg, pod, bootstrapOverride, images)
 }
 
-func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.VirtualNode, vg *appmesh.VirtualGateway, pod *corev1.Pod) error {
+func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.VirtualNode, vg *appmesh.VirtualGateway, pod *corev1.Pod, bootstrapOverride *corev1.EnvVar, images sidecarImages) error {
 	// List out all the mutators in sequence
 	var mutators []PodMutator
 
 	if vn != nil {
+		// iptables isn't available on Windows nodes, so traffic of VirtualNode pods only reaches Envoy through a routing agent on the node.
+		// VirtualGateway pods receive traffic on Envoy directly, so they don't need one.
+		if isWindowsPod(pod) && !m.config.WindowsRoutingAgent {
+			return errors.Errorf("pod is scheduled to Windows nodes, whose traffic can't be intercepted into Envoy without a routing agent, see --%s", flagWindowsRoutingAgent)
+		}
 		mutators = []PodMutator{
 			newProxyMutator(proxyMutatorConfig{
 				egressIgnoredIPs: m.config.IgnoredIPs,
 				initProxyMutatorConfig: initProxyMutatorConfig{
-					containerImage: m.config.InitImage,
+					containerImage: images.initImage,
 					cpuRequests:    m.config.SidecarCpuRequests,
 					memoryRequests: m.config.SidecarMemoryRequests,
 					cpuLimits:      m.config.SidecarCpuLimits,
@@ -125,8 +152,9 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 				preStopDelay:               m.config.PreStopDelay,
 				readinessProbeInitialDelay: m.config.ReadinessProbeInitialDelay,
 				readinessProbePeriod:       m.config.ReadinessProbePeriod,
-				sidecarImageRepository:     m.config.SidecarImageRepository,
+				sidecarImageRepository:     images.sidecarImageRepository,
 				sidecarImageTag:            m.config.SidecarImageTag,
+				sidecarImageDigest:         images.sidecarImageDigest,
 				sidecarCPURequests:         m.config.SidecarCpuRequests,
 				sidecarMemoryRequests:      m.config.SidecarMemoryRequests,
 				sidecarCPULimits:           m.config.SidecarCpuLimits,
@@ -140,7 +168,10 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 				enableDatadogTracing:       m.config.EnableDatadogTracing,
 				datadogTracerPort:          m.config.DatadogPort,
 				datadogTracerAddress:       m.config.DatadogAddress,
+				enableOtelCollector:        m.config.EnableOtelCollector,
+				otelCollectorEndpoint:      m.config.OtelCollectorEndpoint,
 				enableStatsTags:            m.config.EnableStatsTags,
+				enableMetricExtension:      m.config.PrometheusScrapeMode != "",
 				enableStatsD:               m.config.EnableStatsD,
 				statsDPort:                 m.config.StatsDPort,
 				statsDAddress:              m.config.StatsDAddress,
@@ -157,22 +188,42 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 				awsSecretAccessKey:         m.config.EnvoyAwsSecretAccessKey,
 				awsSessionToken:            m.config.EnvoyAwsSessionToken,
 			}, ms, vn),
+			newEnvoyBootstrapOverrideMutator(bootstrapOverride),
+			newEnvoyDNSMutator(),
 			newXrayMutator(xrayMutatorConfig{
 				awsRegion:             m.awsRegion,
 				sidecarCPURequests:    m.config.SidecarCpuRequests,
 				sidecarMemoryRequests: m.config.SidecarMemoryRequests,
 				sidecarCPULimits:      m.config.SidecarCpuLimits,
 				sidecarMemoryLimits:   m.config.SidecarMemoryLimits,
-				xRayImage:             m.config.XRayImage,
+				xRayImage:             images.xRayImage,
 				xRayDaemonPort:        m.config.XrayDaemonPort,
 				xRayLogLevel:          m.config.XrayLogLevel,
 				xRayConfigRoleArn:     m.config.XrayConfigRoleArn,
 			}, m.config.EnableXrayTracing),
+			newOtelCollectorMutator(m.newOtelCollectorMutatorConfig(), m.config.EnableOtelCollector),
 			newCloudMapHealthyReadinessGate(vn),
+			newEnvoyReadyReadinessGate(m.config.EnableEnvoyReadinessGate),
+			newPrometheusScrapeMutator(m.config.EnvoyAdminAcessPort, m.config.PrometheusScrapeMode == PrometheusScrapeModeAnnotations),
 			newIAMForServiceAccountsMutator(m.config.EnableIAMForServiceAccounts),
 			newECRSecretMutator(m.config.EnableECRSecret),
+			newWindowsMutator(windowsMutatorConfig{
+				sidecarImageRepository:    images.sidecarImageRepository,
+				sidecarWindowsImageTag:    m.config.SidecarWindowsImageTag,
+				sidecarWindowsImageDigest: images.sidecarWindowsImageDigest,
+				adminAccessPort:           m.config.EnvoyAdminAcessPort,
+				preStopDelay:              m.config.PreStopDelay,
+			}),
+			newEnvoyTerminationMutator(envoyTerminationMutatorConfig{
+				preStopDelay:          m.config.PreStopDelay,
+				drainDuration:         m.config.EnvoyDrainDuration,
+				adminAccessPort:       m.config.EnvoyAdminAcessPort,
+				alignTerminationGrace: m.config.AlignTerminationGrace,
+			}),
 		}
 	} else if vg != nil {
+		// there's no windowsMutator for VirtualGateways, since their Envoy container, including its image, probes and security context,
+		// is defined by the pod itself, and gateways receive traffic directly, so Windows VirtualGateway pods need no Windows variants.
 		mutators = []PodMutator{newVirtualGatewayEnvoyConfig(virtualGatwayEnvoyConfig{
 			accountID:                  m.accountID,
 			awsRegion:                  m.awsRegion,
@@ -182,8 +233,9 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 			logLevel:                   m.config.LogLevel,
 			adminAccessPort:            m.config.EnvoyAdminAcessPort,
 			adminAccessLogFile:         m.config.EnvoyAdminAccessLogFile,
-			sidecarImageRepository:     m.config.SidecarImageRepository,
+			sidecarImageRepository:     images.sidecarImageRepository,
 			sidecarImageTag:            m.config.SidecarImageTag,
+			sidecarImageDigest:         images.sidecarImageDigest,
 			readinessProbeInitialDelay: m.config.ReadinessProbeInitialDelay,
 			readinessProbePeriod:       m.config.ReadinessProbePeriod,
 			enableXrayTracing:          m.config.EnableXrayTracing,
@@ -195,7 +247,10 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 			enableDatadogTracing:       m.config.EnableDatadogTracing,
 			datadogTracerPort:          m.config.DatadogPort,
 			datadogTracerAddress:       m.config.DatadogAddress,
+			enableOtelCollector:        m.config.EnableOtelCollector,
+			otelCollectorEndpoint:      m.config.OtelCollectorEndpoint,
 			enableStatsTags:            m.config.EnableStatsTags,
+			enableMetricExtension:      m.config.PrometheusScrapeMode != "",
 			enableStatsD:               m.config.EnableStatsD,
 			statsDPort:                 m.config.StatsDPort,
 			statsDAddress:              m.config.StatsDAddress,
@@ -208,18 +263,24 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 			awsAccessKeyId:             m.config.EnvoyAwsAccessKeyId,
 			awsSecretAccessKey:         m.config.EnvoyAwsSecretAccessKey,
 			awsSessionToken:            m.config.EnvoyAwsSessionToken,
+			enableStatsPort:            m.config.PrometheusScrapeMode == PrometheusScrapeModePodMonitor,
 		}, ms, vg),
+			newEnvoyBootstrapOverrideMutator(bootstrapOverride),
+			newEnvoyDNSMutator(),
 			newXrayMutator(xrayMutatorConfig{
 				awsRegion:             m.awsRegion,
 				sidecarCPURequests:    m.config.SidecarCpuRequests,
 				sidecarMemoryRequests: m.config.SidecarMemoryRequests,
 				sidecarCPULimits:      m.config.SidecarCpuLimits,
 				sidecarMemoryLimits:   m.config.SidecarMemoryLimits,
-				xRayImage:             m.config.XRayImage,
+				xRayImage:             images.xRayImage,
 				xRayDaemonPort:        m.config.XrayDaemonPort,
 				xRayLogLevel:          m.config.XrayLogLevel,
 				xRayConfigRoleArn:     m.config.XrayConfigRoleArn,
 			}, m.config.EnableXrayTracing),
+			newOtelCollectorMutator(m.newOtelCollectorMutatorConfig(), m.config.EnableOtelCollector),
+			newEnvoyReadyReadinessGate(m.config.EnableEnvoyReadinessGate),
+			newPrometheusScrapeMutator(m.config.EnvoyAdminAcessPort, m.config.PrometheusScrapeMode == PrometheusScrapeModeAnnotations),
 		}
 	}
 
@@ -232,6 +293,18 @@ func (m *SidecarInjector) injectAppMeshPatches(ms *appmesh.Mesh, vn *appmesh.Vir
 	return nil
 }
 
+func (m *SidecarInjector) newOtelCollectorMutatorConfig() otelCollectorMutatorConfig {
+	return otelCollectorMutatorConfig{
+		awsRegion:             m.awsRegion,
+		sidecarCPURequests:    m.config.SidecarCpuRequests,
+		sidecarMemoryRequests: m.config.SidecarMemoryRequests,
+		sidecarCPULimits:      m.config.SidecarCpuLimits,
+		sidecarMemoryLimits:   m.config.SidecarMemoryLimits,
+		otelCollectorImage:    m.config.OtelCollectorImage,
+		otelSamplingRate:      m.config.OtelSamplingRate,
+	}
+}
+
 type sidecarInjectMode string
 
 const (
@@ -255,9 +255,11 @@ func Test_InjectEnvoyContainerVN(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			inj := NewSidecarInjector(tt.conf, "000000000000", "us-west-2", "v1.4.1", "v1.4.1", nil, nil, nil, nil)
+			inj := NewSidecarInjector(tt.conf, "000000000000", "us-west-2", "v1.4.1", "v1.4.1", nil, nil, nil, nil, nil)
 			pod := tt.args.pod
-			inj.injectAppMeshPatches(tt.args.ms, tt.args.vn, nil, pod)
+			images, err := buildSidecarImages(tt.conf, nil)
+			assert.NoError(t, err)
+			inj.injectAppMeshPatches(tt.args.ms, tt.args.vn, nil, pod, nil, images)
 			assert.Equal(t, tt.want.init, len(pod.Spec.InitContainers), "Numbers of init containers mismatch")
 			assert.Equal(t, tt.want.containers, len(pod.Spec.Containers), "Numbers of containers mismatch")
 			if tt.want.xray {
@@ -282,6 +284,47 @@ func Test_InjectEnvoyContainerVN(t *testing.T) {
 	}
 }
 
+func Test_InjectEnvoyContainerVN_windows(t *testing.T) {
+	tests := []struct {
+		name                string
+		windowsRoutingAgent bool
+		wantErr             error
+	}{
+		{
+			name:    "Windows pod without routing agent",
+			wantErr: errors.New("pod is scheduled to Windows nodes, whose traffic can't be intercepted into Envoy without a routing agent, see --windows-routing-agent"),
+		},
+		{
+			name:                "Windows pod with routing agent",
+			windowsRoutingAgent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := getConfig(func(cnf Config) Config {
+				cnf.SidecarWindowsImageTag = "v1.27.3.0-prod-windows"
+				cnf.WindowsRoutingAgent = tt.windowsRoutingAgent
+				return cnf
+			})
+			inj := NewSidecarInjector(conf, "000000000000", "us-west-2", "v1.4.1", "v1.4.1", nil, nil, nil, nil, nil)
+			pod := getPod(nil)
+			pod.Spec.NodeSelector = map[string]string{nodeOSLabel: nodeOSWindows}
+			images, err := buildSidecarImages(conf, nil)
+			assert.NoError(t, err)
+			err = inj.injectAppMeshPatches(getMesh(), getVn([]int{8080}), nil, pod, nil, images)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				assert.Len(t, pod.Spec.Containers, 1)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Empty(t, pod.Spec.InitContainers)
+			assert.Len(t, pod.Spec.Containers, 2)
+			assert.Equal(t, "8080", pod.Annotations[AppMeshPortsAnnotation])
+		})
+	}
+}
+
 func Test_InjectEnvoyContainerVG(t *testing.T) {
 	type args struct {
 		ms  *appmesh.Mesh
@@ -359,9 +402,11 @@ func Test_InjectEnvoyContainerVG(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			inj := NewSidecarInjector(tt.conf, "000000000000", "us-west-2", "v1.4.1", "v1.4.1", nil, nil, nil, nil)
+			inj := NewSidecarInjector(tt.conf, "000000000000", "us-west-2", "v1.4.1", "v1.4.1", nil, nil, nil, nil, nil)
 			pod := tt.args.pod
-			err := inj.injectAppMeshPatches(tt.args.ms, nil, tt.args.vg, pod)
+			images, err := buildSidecarImages(tt.conf, nil)
+			assert.NoError(t, err)
+			err = inj.injectAppMeshPatches(tt.args.ms, nil, tt.args.vg, pod, nil, images)
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
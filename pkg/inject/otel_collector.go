@@ -0,0 +1,215 @@
+package inject
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	otelCollectorContainerName = "aws-otel-collector"
+	otelCollectorOTLPPort      = 4317
+	// otelCollectorConfigEnvName passes the collector config as content instead of a file,
+	// see https://aws-otel.github.io/docs/setup/ecs/config-through-ssm
+	otelCollectorConfigEnvName = "AOT_CONFIG_CONTENT"
+	// otelCollectorConfigTemplate receives OTLP from Envoy, samples traces at the given percentage and exports them to AWS X-Ray.
+	otelCollectorConfigTemplate = `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:%d
+processors:
+  probabilistic_sampler:
+    sampling_percentage: %s
+  batch/traces:
+    timeout: 1s
+    send_batch_size: 50
+exporters:
+  awsxray:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [probabilistic_sampler, batch/traces]
+      exporters: [awsxray]
+`
+
+	envoyOtelTracingConfigVolumeName = "envoy-otel-tracing-config"
+	envoyOtelTracingConfigMountPath  = "/etc/envoy-otel-tracing"
+	envoyOtelTracingConfigPath       = envoyOtelTracingConfigMountPath + "/tracing.yaml"
+	// envoyOtelTracingConfigTemplate is the Envoy tracing config read from ENVOY_TRACING_CFG_FILE.
+	// google_grpc connects to the collector without a static cluster, which the image's bootstrap doesn't define.
+	envoyOtelTracingConfigTemplate = `http:
+  name: envoy.tracers.opentelemetry
+  typed_config:
+    "@type": type.googleapis.com/envoy.config.trace.v3.OpenTelemetryConfig
+    grpc_service:
+      google_grpc:
+        target_uri: %s
+        stat_prefix: otel_collector
+    service_name: %s
+`
+)
+
+type otelCollectorMutatorConfig struct {
+	awsRegion             string
+	sidecarCPURequests    string
+	sidecarMemoryRequests string
+	sidecarCPULimits      string
+	sidecarMemoryLimits   string
+	otelCollectorImage    string
+	otelSamplingRate      string
+}
+
+func newOtelCollectorMutator(mutatorConfig otelCollectorMutatorConfig, enabled bool) *otelCollectorMutator {
+	return &otelCollectorMutator{
+		mutatorConfig: mutatorConfig,
+		enabled:       enabled,
+	}
+}
+
+var _ PodMutator = &otelCollectorMutator{}
+
+// otelCollectorMutator injects the AWS Distro for OpenTelemetry collector as sidecar
+type otelCollectorMutator struct {
+	mutatorConfig otelCollectorMutatorConfig
+	enabled       bool
+}
+
+func (m *otelCollectorMutator) mutate(pod *corev1.Pod) error {
+	if !isOtelCollectorEnabled(m.enabled, pod) {
+		return nil
+	}
+	if containsOtelCollectorContainer(pod) {
+		return nil
+	}
+	if err := m.checkConfig(); err != nil {
+		return err
+	}
+	samplingRate, err := roundSamplingRate(m.mutatorConfig.otelSamplingRate)
+	if err != nil {
+		return err
+	}
+	samplingPercentage, _ := strconv.ParseFloat(samplingRate, 64)
+
+	container := corev1.Container{
+		Name:  otelCollectorContainerName,
+		Image: m.mutatorConfig.otelCollectorImage,
+		// run as the proxy UID so the collector's egress bypasses Envoy
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser: aws.Int64(1337),
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "otlp-grpc",
+				ContainerPort: otelCollectorOTLPPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Env: []corev1.EnvVar{
+			envVar("AWS_REGION", m.mutatorConfig.awsRegion),
+			envVar(otelCollectorConfigEnvName, fmt.Sprintf(otelCollectorConfigTemplate, otelCollectorOTLPPort,
+				strconv.FormatFloat(samplingPercentage*100, 'f', -1, 64))),
+		},
+	}
+
+	container.Resources, err = sidecarResources(getSidecarCPURequest(m.mutatorConfig.sidecarCPURequests, pod),
+		getSidecarMemoryRequest(m.mutatorConfig.sidecarMemoryRequests, pod),
+		getSidecarCPULimit(m.mutatorConfig.sidecarCPULimits, pod),
+		getSidecarMemoryLimit(m.mutatorConfig.sidecarMemoryLimits, pod))
+	if err != nil {
+		return err
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, container)
+	return nil
+}
+
+func (m *otelCollectorMutator) checkConfig() error {
+	var missingConfig []string
+
+	if m.mutatorConfig.awsRegion == "" {
+		missingConfig = append(missingConfig, "AWSRegion")
+	}
+	if m.mutatorConfig.otelCollectorImage == "" {
+		missingConfig = append(missingConfig, "otelCollectorImage")
+	}
+
+	if len(missingConfig) > 0 {
+		return errors.Errorf("Missing configuration parameters: %s", strings.Join(missingConfig, ","))
+	}
+	return nil
+}
+
+// isOtelCollectorEnabled returns whether OpenTelemetry tracing is enabled for pod.
+// The pod annotation overrides the controller level setting.
+func isOtelCollectorEnabled(defaultEnabled bool, pod *corev1.Pod) bool {
+	if v, ok := pod.ObjectMeta.Annotations[AppMeshOtelCollectorAnnotation]; ok {
+		switch strings.ToLower(v) {
+		case "enabled":
+			return true
+		case "disabled":
+			return false
+		}
+		envoyUtilsLogger.Info("Unsupported Value. Annotation only accepts `enabled` or `disabled` in the value field.",
+			"Annotation", AppMeshOtelCollectorAnnotation, "Value Provided: ", v)
+	}
+	return defaultEnabled
+}
+
+func containsOtelCollectorContainer(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == otelCollectorContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// mutateEnvoyOtelTracingConfig configures envoy to export traces of serviceName to the collector at endpoint.
+// the tracing config is set as pod annotation and projected into envoy with the downward API, so no object has to be created for it.
+func mutateEnvoyOtelTracingConfig(pod *corev1.Pod, envoy *corev1.Container, serviceName string, endpoint string) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[AppMeshEnvoyOtelTracingConfigAnnotation] = fmt.Sprintf(envoyOtelTracingConfigTemplate, endpoint, serviceName)
+
+	containsVolume := false
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == envoyOtelTracingConfigVolumeName {
+			containsVolume = true
+			break
+		}
+	}
+	if !containsVolume {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: envoyOtelTracingConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{
+					Items: []corev1.DownwardAPIVolumeFile{
+						{
+							Path: "tracing.yaml",
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: fmt.Sprintf("metadata.annotations['%s']", AppMeshEnvoyOtelTracingConfigAnnotation),
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	for _, volumeMount := range envoy.VolumeMounts {
+		if volumeMount.Name == envoyOtelTracingConfigVolumeName {
+			return
+		}
+	}
+	envoy.VolumeMounts = append(envoy.VolumeMounts, corev1.VolumeMount{
+		Name:      envoyOtelTracingConfigVolumeName,
+		MountPath: envoyOtelTracingConfigMountPath,
+		ReadOnly:  true,
+	})
+}
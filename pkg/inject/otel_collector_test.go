@@ -0,0 +1,420 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_otelCollectorMutator_mutate(t *testing.T) {
+	cpuRequests, _ := resource.ParseQuantity("10m")
+	memoryRequests, _ := resource.ParseQuantity("32Mi")
+
+	mutatorConfig := otelCollectorMutatorConfig{
+		awsRegion:             "us-west-2",
+		sidecarCPURequests:    cpuRequests.String(),
+		sidecarMemoryRequests: memoryRequests.String(),
+		otelCollectorImage:    "public.ecr.aws/aws-observability/aws-otel-collector:v0.40.0",
+		otelSamplingRate:      "0.05",
+	}
+	wantCollector := corev1.Container{
+		Name:  "aws-otel-collector",
+		Image: "public.ecr.aws/aws-observability/aws-otel-collector:v0.40.0",
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser: aws.Int64(1337),
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "otlp-grpc",
+				ContainerPort: 4317,
+				Protocol:      "TCP",
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "AWS_REGION",
+				Value: "us-west-2",
+			},
+			{
+				Name: "AOT_CONFIG_CONTENT",
+				Value: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+processors:
+  probabilistic_sampler:
+    sampling_percentage: 5
+  batch/traces:
+    timeout: 1s
+    send_batch_size: 50
+exporters:
+  awsxray:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [probabilistic_sampler, batch/traces]
+      exporters: [awsxray]
+`,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				"cpu":    cpuRequests,
+				"memory": memoryRequests,
+			},
+		},
+	}
+
+	type fields struct {
+		enabled       bool
+		mutatorConfig otelCollectorMutatorConfig
+	}
+	type args struct {
+		pod *corev1.Pod
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantPod *corev1.Pod
+		wantErr error
+	}{
+		{
+			name: "no-op when disabled",
+			fields: fields{
+				enabled:       false,
+				mutatorConfig: mutatorConfig,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+				},
+			},
+		},
+		{
+			name: "inject collector when enabled",
+			fields: fields{
+				enabled:       true,
+				mutatorConfig: mutatorConfig,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}, wantCollector},
+				},
+			},
+		},
+		{
+			name: "inject collector when enabled by pod annotation",
+			fields: fields{
+				enabled:       false,
+				mutatorConfig: mutatorConfig,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "my-ns",
+						Name:        "my-pod",
+						Annotations: map[string]string{AppMeshOtelCollectorAnnotation: "enabled"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-pod",
+					Annotations: map[string]string{AppMeshOtelCollectorAnnotation: "enabled"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}, wantCollector},
+				},
+			},
+		},
+		{
+			name: "no-op when disabled by pod annotation",
+			fields: fields{
+				enabled:       true,
+				mutatorConfig: mutatorConfig,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "my-ns",
+						Name:        "my-pod",
+						Annotations: map[string]string{AppMeshOtelCollectorAnnotation: "disabled"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-pod",
+					Annotations: map[string]string{AppMeshOtelCollectorAnnotation: "disabled"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+				},
+			},
+		},
+		{
+			name: "no-op when already contains collector container",
+			fields: fields{
+				enabled:       true,
+				mutatorConfig: mutatorConfig,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}, {Name: "aws-otel-collector"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}, {Name: "aws-otel-collector"}},
+				},
+			},
+		},
+		{
+			name: "error when image is missing",
+			fields: fields{
+				enabled: true,
+				mutatorConfig: otelCollectorMutatorConfig{
+					awsRegion: "us-west-2",
+				},
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+				},
+			},
+			wantErr: errors.New("Missing configuration parameters: otelCollectorImage"),
+		},
+		{
+			name: "error when sampling rate out of range",
+			fields: fields{
+				enabled: true,
+				mutatorConfig: otelCollectorMutatorConfig{
+					awsRegion:          "us-west-2",
+					otelCollectorImage: "public.ecr.aws/aws-observability/aws-otel-collector:v0.40.0",
+					otelSamplingRate:   "1.5",
+				},
+			},
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app/v1"}},
+				},
+			},
+			wantErr: errors.New("tracing.samplingRate should be a decimal between 0 & 1.00, but instead got 1.5 <nil>"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newOtelCollectorMutator(tt.fields.mutatorConfig, tt.fields.enabled)
+			pod := tt.args.pod.DeepCopy()
+			err := m.mutate(pod)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, cmp.Equal(tt.wantPod, pod), "diff", cmp.Diff(tt.wantPod, pod))
+			}
+		})
+	}
+}
+
+func Test_updateEnvMapForEnvoy_otelTracing(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    EnvoyTemplateVariables
+		env     map[string]string
+		wantEnv map[string]string
+		wantErr error
+	}{
+		{
+			name: "otel tracing enabled",
+			vars: EnvoyTemplateVariables{
+				EnableOtelTracing:     true,
+				OtelCollectorEndpoint: "127.0.0.1:4317",
+			},
+			env: map[string]string{},
+			wantEnv: map[string]string{
+				"ENVOY_TRACING_CFG_FILE": "/etc/envoy-otel-tracing/tracing.yaml",
+			},
+		},
+		{
+			name: "otel tracing conflicts with x-ray",
+			vars: EnvoyTemplateVariables{
+				EnableOtelTracing: true,
+				EnableXrayTracing: true,
+				XraySamplingRate:  "0.05",
+			},
+			env:     map[string]string{},
+			wantErr: errors.New("Envoy only supports a single tracer instance. Disable X-Ray, Jaeger and Datadog tracing before enabling OpenTelemetry on a pod"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := updateEnvMapForEnvoy(tt.vars, tt.env, "mesh/my-mesh/virtualNode/my-vn")
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				for k, v := range tt.wantEnv {
+					assert.Equal(t, v, tt.env[k], k)
+				}
+			}
+		})
+	}
+}
+
+func Test_mutateEnvoyOtelTracingConfig(t *testing.T) {
+	wantTracingConfig := `http:
+  name: envoy.tracers.opentelemetry
+  typed_config:
+    "@type": type.googleapis.com/envoy.config.trace.v3.OpenTelemetryConfig
+    grpc_service:
+      google_grpc:
+        target_uri: 127.0.0.1:4317
+        stat_prefix: otel_collector
+    service_name: my-mesh/my-vn
+`
+	wantVolume := corev1.Volume{
+		Name: "envoy-otel-tracing-config",
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: "tracing.yaml",
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "metadata.annotations['appmesh.k8s.aws/envoyOtelTracingConfig']",
+						},
+					},
+				},
+			},
+		},
+	}
+	wantVolumeMount := corev1.VolumeMount{
+		Name:      "envoy-otel-tracing-config",
+		MountPath: "/etc/envoy-otel-tracing",
+		ReadOnly:  true,
+	}
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		envoy     *corev1.Container
+		wantPod   *corev1.Pod
+		wantEnvoy *corev1.Container
+	}{
+		{
+			name: "projects tracing config into envoy",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+			},
+			envoy: &corev1.Container{Name: "envoy"},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-pod",
+					Annotations: map[string]string{AppMeshEnvoyOtelTracingConfigAnnotation: wantTracingConfig},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{wantVolume},
+				},
+			},
+			wantEnvoy: &corev1.Container{
+				Name:         "envoy",
+				VolumeMounts: []corev1.VolumeMount{wantVolumeMount},
+			},
+		},
+		{
+			name: "replaces tracing config of mutated pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-pod",
+					Annotations: map[string]string{AppMeshEnvoyOtelTracingConfigAnnotation: "http: {}"},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{wantVolume},
+				},
+			},
+			envoy: &corev1.Container{
+				Name:         "envoy",
+				VolumeMounts: []corev1.VolumeMount{wantVolumeMount},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-pod",
+					Annotations: map[string]string{AppMeshEnvoyOtelTracingConfigAnnotation: wantTracingConfig},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{wantVolume},
+				},
+			},
+			wantEnvoy: &corev1.Container{
+				Name:         "envoy",
+				VolumeMounts: []corev1.VolumeMount{wantVolumeMount},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutateEnvoyOtelTracingConfig(tt.pod, tt.envoy, "my-mesh/my-vn", "127.0.0.1:4317")
+			assert.True(t, cmp.Equal(tt.wantPod, tt.pod), "diff", cmp.Diff(tt.wantPod, tt.pod))
+			assert.True(t, cmp.Equal(tt.wantEnvoy, tt.envoy), "diff", cmp.Diff(tt.wantEnvoy, tt.envoy))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package inject
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	prometheusScrapeAnnotation = "prometheus.io/scrape"
+	prometheusPortAnnotation   = "prometheus.io/port"
+	prometheusPathAnnotation   = "prometheus.io/path"
+
+	// EnvoyPrometheusStatsPath is the Envoy admin endpoint serving stats in Prometheus exposition format
+	EnvoyPrometheusStatsPath = "/stats/prometheus"
+)
+
+// newPrometheusScrapeMutator constructs new prometheusScrapeMutator
+func newPrometheusScrapeMutator(adminAccessPort int32, enabled bool) *prometheusScrapeMutator {
+	return &prometheusScrapeMutator{
+		adminAccessPort: adminAccessPort,
+		enabled:         enabled,
+	}
+}
+
+var _ PodMutator = &prometheusScrapeMutator{}
+
+// prometheusScrapeMutator adds the prometheus.io annotations pointing at Envoy stats endpoint.
+// Annotations already present on pod are not overridden, so application metrics scraping can be kept as is.
+type prometheusScrapeMutator struct {
+	adminAccessPort int32
+	enabled         bool
+}
+
+func (m *prometheusScrapeMutator) mutate(pod *corev1.Pod) error {
+	if !m.enabled {
+		return nil
+	}
+	if ok, _ := containsEnvoyContainer(pod); !ok {
+		return nil
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	if _, ok := pod.Annotations[prometheusScrapeAnnotation]; ok {
+		return nil
+	}
+	pod.Annotations[prometheusScrapeAnnotation] = "true"
+	pod.Annotations[prometheusPortAnnotation] = strconv.Itoa(int(m.adminAccessPort))
+	pod.Annotations[prometheusPathAnnotation] = EnvoyPrometheusStatsPath
+	return nil
+}
@@ -0,0 +1,73 @@
+package inject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_prometheusScrapeMutator_mutate(t *testing.T) {
+	containers := []corev1.Container{{Name: "app"}, {Name: "envoy"}}
+	tests := []struct {
+		name            string
+		enabled         bool
+		pod             *corev1.Pod
+		wantAnnotations map[string]string
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: containers},
+			},
+			wantAnnotations: nil,
+		},
+		{
+			name:    "enabled for pod with envoy",
+			enabled: true,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: containers},
+			},
+			wantAnnotations: map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "9901",
+				"prometheus.io/path":   "/stats/prometheus",
+			},
+		},
+		{
+			name:    "enabled for pod without envoy",
+			enabled: true,
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			},
+			wantAnnotations: nil,
+		},
+		{
+			name:    "enabled for pod with existing scrape annotation",
+			enabled: true,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "8080",
+					},
+				},
+				Spec: corev1.PodSpec{Containers: containers},
+			},
+			wantAnnotations: map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "8080",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newPrometheusScrapeMutator(9901, tt.enabled)
+			pod := tt.pod.DeepCopy()
+			assert.NoError(t, m.mutate(pod))
+			assert.Equal(t, tt.wantAnnotations, pod.Annotations)
+		})
+	}
+}
@@ -35,7 +35,10 @@ type proxyMutator struct {
 func (m *proxyMutator) mutate(pod *corev1.Pod) error {
 	proxyConfig := m.buildProxyConfig(pod)
 	var mutator PodMutator
-	if m.isAppMeshCNIEnabled(pod) {
+	// iptables is not available on Windows nodes, traffic interception is delegated to the
+	// routing agent on the node, which is configured through the same annotations as AppMeshCNI.
+	// Windows pods are rejected before mutation unless the routing agent is enabled.
+	if m.isAppMeshCNIEnabled(pod) || isWindowsPod(pod) {
 		mutator = newCNIProxyMutator(proxyConfig)
 	} else {
 		mutator = newInitProxyMutator(m.mutatorConfig.initProxyMutatorConfig, proxyConfig)
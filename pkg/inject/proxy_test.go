@@ -299,6 +299,36 @@ func Test_proxyMutator_mutate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "mutate using routing agent annotations for windows pod",
+			fields: fields{
+				mutatorConfig: mutatorConfig,
+				vn:            vn,
+			},
+			args: args{
+				pod: &corev1.Pod{
+					Spec: corev1.PodSpec{
+						NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"appmesh.k8s.aws/ports":                  "80,443",
+						"appmesh.k8s.aws/egressIgnoredIPs":       "192.168.0.1",
+						"appmesh.k8s.aws/egressIgnoredPorts":     "22",
+						"appmesh.k8s.aws/proxyEgressPort":        "15001",
+						"appmesh.k8s.aws/proxyIngressPort":       "15000",
+						"appmesh.k8s.aws/ignoredUID":             "1337",
+						"appmesh.k8s.aws/sidecarInjectorWebhook": "enabled",
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+				},
+			},
+		},
 		{
 			name: "mutate using appMesh CNI with no listeners",
 			fields: fields{
@@ -31,6 +31,7 @@ type EnvoyTemplateVariables struct {
 	PostStartInterval        int32
 	SidecarImageRepository   string
 	SidecarImageTag          string
+	SidecarImageDigest       string
 	EnableXrayTracing        bool
 	XrayDaemonPort           int32
 	XraySamplingRate         string
@@ -40,7 +41,10 @@ type EnvoyTemplateVariables struct {
 	EnableDatadogTracing     bool
 	DatadogTracerPort        int32
 	DatadogTracerAddress     string
+	EnableOtelTracing        bool
+	OtelCollectorEndpoint    string
 	EnableStatsTags          bool
+	EnableMetricExtension    bool
 	EnableStatsD             bool
 	StatsDPort               int32
 	StatsDAddress            string
@@ -126,15 +130,23 @@ func updateEnvMapForEnvoy(vars EnvoyTemplateVariables, env map[string]string, vn
 			samplingRate = vars.XraySamplingRate
 		}
 
-		fixedRate, err := strconv.ParseFloat(samplingRate, 32)
-		if err != nil || float64(0) > fixedRate || float64(1) < fixedRate {
-			// The value is not a decimal between 0 and 1.00
-			return errors.Errorf("tracing.samplingRate should be a decimal between 0 & 1.00, "+
-				"but instead got %s %v", samplingRate, err)
-		} else {
-			fixedRate = math.Round(fixedRate*100) / 100
-			env["XRAY_SAMPLING_RATE"] = strconv.FormatFloat(fixedRate, 'f', -1, 32)
+		fixedRate, err := roundSamplingRate(samplingRate)
+		if err != nil {
+			return err
 		}
+		env["XRAY_SAMPLING_RATE"] = fixedRate
+	}
+
+	if vars.EnableOtelTracing {
+		if vars.EnableXrayTracing || vars.EnableJaegerTracing || vars.EnableDatadogTracing {
+			return errors.New("Envoy only supports a single tracer instance. " +
+				"Disable X-Ray, Jaeger and Datadog tracing before enabling OpenTelemetry on a pod")
+		}
+
+		// Specify a custom tracing config file, which replaces the tracer of the Envoy image.
+		// The file is projected from the pod annotation set by mutateEnvoyOtelTracingConfig.
+		// See https://docs.aws.amazon.com/app-mesh/latest/userguide/envoy-config.html
+		env["ENVOY_TRACING_CFG_FILE"] = envoyOtelTracingConfigPath
 	}
 
 	if vars.EnableDatadogTracing {
@@ -154,6 +166,12 @@ func updateEnvMapForEnvoy(vars EnvoyTemplateVariables, env map[string]string, vn
 		env["ENABLE_ENVOY_STATS_TAGS"] = "1"
 	}
 
+	if vars.EnableMetricExtension {
+		// Enables App Mesh metrics extension, which emits mesh level stats such as envoy_appmesh_RequestCount
+		// annotated with virtual node and mesh.
+		env["APPMESH_METRIC_EXTENSION_VERSION"] = "1"
+	}
+
 	if vars.EnableStatsD {
 		// Enables DogStatsD stats using 127.0.0.1:8125
 		// as the default daemon endpoint. To enable, set the value to 1
@@ -189,11 +207,24 @@ func updateEnvMapForEnvoy(vars EnvoyTemplateVariables, env map[string]string, vn
 	return nil
 }
 
+// roundSamplingRate validates a tracer sampling rate is a decimal between 0 and 1.00 (100%)
+// and rounds it to two decimal places
+func roundSamplingRate(samplingRate string) (string, error) {
+	fixedRate, err := strconv.ParseFloat(samplingRate, 32)
+	if err != nil || float64(0) > fixedRate || float64(1) < fixedRate {
+		// The value is not a decimal between 0 and 1.00
+		return "", errors.Errorf("tracing.samplingRate should be a decimal between 0 & 1.00, "+
+			"but instead got %s %v", samplingRate, err)
+	}
+	fixedRate = math.Round(fixedRate*100) / 100
+	return strconv.FormatFloat(fixedRate, 'f', -1, 32), nil
+}
+
 func buildEnvoySidecar(vars EnvoyTemplateVariables, env map[string]string) (corev1.Container, error) {
 
 	envoy := corev1.Container{
 		Name:  "envoy",
-		Image: fmt.Sprintf("%s:%s", vars.SidecarImageRepository, vars.SidecarImageTag),
+		Image: imageReference(vars.SidecarImageRepository, vars.SidecarImageTag, vars.SidecarImageDigest),
 		SecurityContext: &corev1.SecurityContext{
 			RunAsUser: aws.Int64(1337),
 		},
@@ -21,6 +21,7 @@ type virtualGatwayEnvoyConfig struct {
 	adminAccessLogFile         string
 	sidecarImageRepository     string
 	sidecarImageTag            string
+	sidecarImageDigest         string
 	readinessProbeInitialDelay int32
 	readinessProbePeriod       int32
 	enableXrayTracing          bool
@@ -32,7 +33,10 @@ type virtualGatwayEnvoyConfig struct {
 	enableDatadogTracing       bool
 	datadogTracerPort          int32
 	datadogTracerAddress       string
+	enableOtelCollector        bool
+	otelCollectorEndpoint      string
 	enableStatsTags            bool
+	enableMetricExtension      bool
 	enableStatsD               bool
 	statsDPort                 int32
 	statsDAddress              string
@@ -45,6 +49,8 @@ type virtualGatwayEnvoyConfig struct {
 	awsAccessKeyId             string
 	awsSecretAccessKey         string
 	awsSessionToken            string
+	// enableStatsPort adds the "stats" port scraped by PodMonitors to Envoy container
+	enableStatsPort bool
 }
 
 // newVirtualGatewayEnvoyConfig constructs new newVirtualGatewayEnvoyConfig
@@ -84,7 +90,7 @@ func (m *virtualGatewayEnvoyConfig) mutate(pod *corev1.Pod) error {
 	//we override the image to latest Envoy so customers do not have to manually manage
 	// envoy versions and let controller handle consistency versions across the mesh
 	if m.virtualGatewayImageOverride(pod) {
-		envoy.Image = fmt.Sprintf("%s:%s", m.mutatorConfig.sidecarImageRepository, m.mutatorConfig.sidecarImageTag)
+		envoy.Image = imageReference(m.mutatorConfig.sidecarImageRepository, m.mutatorConfig.sidecarImageTag, m.mutatorConfig.sidecarImageDigest)
 	}
 
 	for idx, env := range pod.Spec.Containers[envoyIdx].Env {
@@ -108,6 +114,12 @@ func (m *virtualGatewayEnvoyConfig) mutate(pod *corev1.Pod) error {
 	if m.mutatorConfig.enableSDS && !isSDSDisabled(pod) {
 		mutateSDSMounts(pod, &envoy, m.mutatorConfig.sdsUdsPath)
 	}
+	if variables.EnableOtelTracing {
+		mutateEnvoyOtelTracingConfig(pod, &envoy, variables.MeshName+"/"+variables.VirtualGatewayOrNodeName, m.mutatorConfig.otelCollectorEndpoint)
+	}
+	if m.mutatorConfig.enableStatsPort {
+		mutateEnvoyStatsPort(&envoy, m.mutatorConfig.adminAccessPort)
+	}
 	pod.Spec.Containers[envoyIdx] = envoy
 	return nil
 }
@@ -142,7 +154,10 @@ func (m *virtualGatewayEnvoyConfig) buildTemplateVariables(pod *corev1.Pod) Envo
 		EnableDatadogTracing:     m.mutatorConfig.enableDatadogTracing,
 		DatadogTracerPort:        m.mutatorConfig.datadogTracerPort,
 		DatadogTracerAddress:     m.mutatorConfig.datadogTracerAddress,
+		EnableOtelTracing:        isOtelCollectorEnabled(m.mutatorConfig.enableOtelCollector, pod),
+		OtelCollectorEndpoint:    m.mutatorConfig.otelCollectorEndpoint,
 		EnableStatsTags:          m.mutatorConfig.enableStatsTags,
+		EnableMetricExtension:    m.mutatorConfig.enableMetricExtension,
 		EnableStatsD:             m.mutatorConfig.enableStatsD,
 		StatsDPort:               m.mutatorConfig.statsDPort,
 		StatsDAddress:            m.mutatorConfig.statsDAddress,
@@ -216,3 +231,17 @@ func (m *virtualGatewayEnvoyConfig) getUseFipsEndpoint(useFipsEndpoint bool) str
 		return "0"
 	}
 }
+
+// mutateEnvoyStatsPort adds the "stats" port of Envoy admin interface to envoy container, unless the pod already defines a port by that name or number.
+func mutateEnvoyStatsPort(envoy *corev1.Container, adminAccessPort int32) {
+	for _, port := range envoy.Ports {
+		if port.Name == "stats" || port.ContainerPort == adminAccessPort {
+			return
+		}
+	}
+	envoy.Ports = append(envoy.Ports, corev1.ContainerPort{
+		Name:          "stats",
+		ContainerPort: adminAccessPort,
+		Protocol:      "TCP",
+	})
+}
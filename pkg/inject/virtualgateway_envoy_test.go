@@ -2125,3 +2125,56 @@ func Test_virtualGatewayEnvoyMutator_mutate(t *testing.T) {
 		})
 	}
 }
+
+func Test_mutateEnvoyStatsPort(t *testing.T) {
+	statsPort := corev1.ContainerPort{
+		Name:          "stats",
+		ContainerPort: 9901,
+		Protocol:      "TCP",
+	}
+	tests := []struct {
+		name      string
+		ports     []corev1.ContainerPort
+		wantPorts []corev1.ContainerPort
+	}{
+		{
+			name:      "no ports",
+			wantPorts: []corev1.ContainerPort{statsPort},
+		},
+		{
+			name: "other ports",
+			ports: []corev1.ContainerPort{
+				{Name: "http", ContainerPort: 8088, Protocol: "TCP"},
+			},
+			wantPorts: []corev1.ContainerPort{
+				{Name: "http", ContainerPort: 8088, Protocol: "TCP"},
+				statsPort,
+			},
+		},
+		{
+			name: "port by the same name",
+			ports: []corev1.ContainerPort{
+				{Name: "stats", ContainerPort: 9902, Protocol: "TCP"},
+			},
+			wantPorts: []corev1.ContainerPort{
+				{Name: "stats", ContainerPort: 9902, Protocol: "TCP"},
+			},
+		},
+		{
+			name: "port by the same number",
+			ports: []corev1.ContainerPort{
+				{Name: "admin", ContainerPort: 9901, Protocol: "TCP"},
+			},
+			wantPorts: []corev1.ContainerPort{
+				{Name: "admin", ContainerPort: 9901, Protocol: "TCP"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envoy := &corev1.Container{Name: "envoy", Ports: tt.ports}
+			mutateEnvoyStatsPort(envoy, 9901)
+			assert.Equal(t, tt.wantPorts, envoy.Ports)
+		})
+	}
+}
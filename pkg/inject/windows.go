@@ -0,0 +1,133 @@
+package inject
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// nodeOSLabel is the well-known node label carrying node operating system
+	nodeOSLabel   = "kubernetes.io/os"
+	nodeOSWindows = "windows"
+	// windowsProxyUserName is the Windows account sidecars run as, the counterpart of defaultProxyUID on Linux
+	windowsProxyUserName = "ContainerUser"
+)
+
+type windowsMutatorConfig struct {
+	sidecarImageRepository    string
+	sidecarWindowsImageTag    string
+	sidecarWindowsImageDigest string
+	adminAccessPort           int32
+	preStopDelay              string
+}
+
+// newWindowsMutator constructs new windowsMutator
+func newWindowsMutator(mutatorConfig windowsMutatorConfig) *windowsMutator {
+	return &windowsMutator{
+		mutatorConfig: mutatorConfig,
+	}
+}
+
+var _ PodMutator = &windowsMutator{}
+
+// windowsMutator converts sidecars injected by preceding mutators into their Windows variants for pods
+// scheduled to Windows nodes. It must run after all other container injecting mutators.
+type windowsMutator struct {
+	mutatorConfig windowsMutatorConfig
+}
+
+func (m *windowsMutator) mutate(pod *corev1.Pod) error {
+	if !isWindowsPod(pod) {
+		return nil
+	}
+	for idx := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[idx]
+		switch container.Name {
+		case envoyContainerName:
+			m.mutateEnvoyContainer(container)
+		case xrayDaemonContainerName, otelCollectorContainerName:
+			container.SecurityContext = windowsSidecarSecurityContext()
+		}
+	}
+	return nil
+}
+
+func (m *windowsMutator) mutateEnvoyContainer(envoy *corev1.Container) {
+	if m.mutatorConfig.sidecarWindowsImageTag != "" || m.mutatorConfig.sidecarWindowsImageDigest != "" {
+		envoy.Image = imageReference(m.mutatorConfig.sidecarImageRepository, m.mutatorConfig.sidecarWindowsImageTag, m.mutatorConfig.sidecarWindowsImageDigest)
+	}
+	envoy.SecurityContext = windowsSidecarSecurityContext()
+	// Windows images don't ship sh/curl, so hooks and probes are re-written with powershell.
+	// waitUntilProxyReady relies on the Linux agent binary, so postStart is dropped.
+	envoy.Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{
+				"powershell", "-Command", fmt.Sprintf("Start-Sleep -Seconds %s", m.mutatorConfig.preStopDelay),
+			}},
+		},
+	}
+	if envoy.ReadinessProbe != nil {
+		envoy.ReadinessProbe.ProbeHandler = corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: []string{
+				"powershell", "-Command", envoyWindowsReadinessCommand(strconv.Itoa(int(m.mutatorConfig.adminAccessPort))),
+			}},
+		}
+	}
+}
+
+func envoyWindowsReadinessCommand(adminAccessPort string) string {
+	return "if ((Invoke-WebRequest -UseBasicParsing http://localhost:" + adminAccessPort + "/server_info).Content -match 'LIVE') { exit 0 } else { exit 1 }"
+}
+
+func windowsSidecarSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		WindowsOptions: &corev1.WindowsSecurityContextOptions{
+			RunAsUserName: aws.String(windowsProxyUserName),
+		},
+	}
+}
+
+// isWindowsPod detects whether pod will be scheduled onto Windows nodes.
+// Pods are not yet bound to a node during admission, so detection relies on pod OS,
+// nodeSelector or required nodeAffinity on the well-known OS label.
+func isWindowsPod(pod *corev1.Pod) bool {
+	if pod.Spec.OS != nil {
+		return pod.Spec.OS.Name == corev1.Windows
+	}
+	if v, ok := pod.Spec.NodeSelector[nodeOSLabel]; ok {
+		return v == nodeOSWindows
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return false
+	}
+	// nodeSelectorTerms are ORed, pod is Windows only if every term pins it to Windows.
+	for _, term := range terms {
+		if !isWindowsNodeSelectorTerm(term) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWindowsNodeSelectorTerm(term corev1.NodeSelectorTerm) bool {
+	for _, expr := range term.MatchExpressions {
+		if expr.Key != nodeOSLabel || expr.Operator != corev1.NodeSelectorOpIn {
+			continue
+		}
+		for _, value := range expr.Values {
+			if value != nodeOSWindows {
+				return false
+			}
+		}
+		return len(expr.Values) > 0
+	}
+	return false
+}
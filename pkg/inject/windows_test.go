@@ -0,0 +1,201 @@
+package inject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_windowsMutator_mutate(t *testing.T) {
+	mutatorConfig := windowsMutatorConfig{
+		sidecarImageRepository: "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-envoy",
+		sidecarWindowsImageTag: "v1.27.3.0-prod-windows",
+		adminAccessPort:        9901,
+		preStopDelay:           "20",
+	}
+	linuxEnvoy := corev1.Container{
+		Name:  "envoy",
+		Image: "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-envoy:v1.27.3.0-prod",
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser: aws.Int64(1337),
+		},
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "sleep 20"}},
+			},
+		},
+		ReadinessProbe: envoyReadinessProbe(1, 10, "9901"),
+	}
+	windowsReadinessProbe := envoyReadinessProbe(1, 10, "9901")
+	windowsReadinessProbe.ProbeHandler = corev1.ProbeHandler{
+		Exec: &corev1.ExecAction{Command: []string{
+			"powershell", "-Command",
+			"if ((Invoke-WebRequest -UseBasicParsing http://localhost:9901/server_info).Content -match 'LIVE') { exit 0 } else { exit 1 }",
+		}},
+	}
+	windowsEnvoy := corev1.Container{
+		Name:  "envoy",
+		Image: "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-envoy:v1.27.3.0-prod-windows",
+		SecurityContext: &corev1.SecurityContext{
+			WindowsOptions: &corev1.WindowsSecurityContextOptions{
+				RunAsUserName: aws.String("ContainerUser"),
+			},
+		},
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"powershell", "-Command", "Start-Sleep -Seconds 20"}},
+			},
+		},
+		ReadinessProbe: windowsReadinessProbe,
+	}
+	windowsImageDigest := "sha256:" + strings.Repeat("e", 64)
+	pinnedWindowsEnvoy := windowsEnvoy
+	pinnedWindowsEnvoy.Image = "840364872350.dkr.ecr.us-west-2.amazonaws.com/aws-appmesh-envoy@" + windowsImageDigest
+
+	tests := []struct {
+		name        string
+		imageDigest string
+		pod         *corev1.Pod
+		wantPod     *corev1.Pod
+	}{
+		{
+			name: "no-op for linux pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}, linuxEnvoy},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}, linuxEnvoy},
+				},
+			},
+		},
+		{
+			name: "converts sidecars for windows pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					Containers: []corev1.Container{
+						{Name: "app"},
+						linuxEnvoy,
+						{Name: "xray-daemon", SecurityContext: &corev1.SecurityContext{RunAsUser: aws.Int64(1337)}},
+					},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					Containers: []corev1.Container{
+						{Name: "app"},
+						windowsEnvoy,
+						{Name: "xray-daemon", SecurityContext: windowsSidecarSecurityContext()},
+					},
+				},
+			},
+		},
+		{
+			name:        "pins envoy image of windows pod to digest",
+			imageDigest: windowsImageDigest,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					OS:         &corev1.PodOS{Name: corev1.Windows},
+					Containers: []corev1.Container{{Name: "app"}, linuxEnvoy},
+				},
+			},
+			wantPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pod"},
+				Spec: corev1.PodSpec{
+					OS:         &corev1.PodOS{Name: corev1.Windows},
+					Containers: []corev1.Container{{Name: "app"}, pinnedWindowsEnvoy},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutatorConfig := mutatorConfig
+			mutatorConfig.sidecarWindowsImageDigest = tt.imageDigest
+			m := newWindowsMutator(mutatorConfig)
+			pod := tt.pod.DeepCopy()
+			err := m.mutate(pod)
+			assert.NoError(t, err)
+			assert.True(t, cmp.Equal(tt.wantPod, pod), "diff", cmp.Diff(tt.wantPod, pod))
+		})
+	}
+}
+
+func Test_isWindowsPod(t *testing.T) {
+	windowsAffinity := func(values ...string) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: values},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want bool
+	}{
+		{
+			name: "pod without os constraints",
+			spec: corev1.PodSpec{},
+			want: false,
+		},
+		{
+			name: "pod with windows os",
+			spec: corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Windows}},
+			want: true,
+		},
+		{
+			name: "pod with linux os",
+			spec: corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Linux}},
+			want: false,
+		},
+		{
+			name: "pod with windows nodeSelector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "windows"}},
+			want: true,
+		},
+		{
+			name: "pod with linux nodeSelector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}},
+			want: false,
+		},
+		{
+			name: "pod with windows nodeAffinity",
+			spec: corev1.PodSpec{Affinity: windowsAffinity("windows")},
+			want: true,
+		},
+		{
+			name: "pod with mixed os nodeAffinity",
+			spec: corev1.PodSpec{Affinity: windowsAffinity("windows", "linux")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isWindowsPod(&corev1.Pod{Spec: tt.spec})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	flagDisableCacheFor = "disable-cache-for"
+
+	// CacheKindPod is the kind of pods in CacheConfig.
+	CacheKindPod = "Pod"
+)
+
+// uncachedObjectsByKind are the objects of kinds that can be read from the API server instead of the cache.
+var uncachedObjectsByKind = map[string]client.Object{
+	CacheKindPod: &corev1.Pod{},
+}
+
+// CacheConfig configures the informer cache of the controller.
+type CacheConfig struct {
+	// Kinds of objects read from the API server on demand instead of being watched and cached.
+	DisableCacheFor []string
+}
+
+func (cfg *CacheConfig) BindFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&cfg.DisableCacheFor, flagDisableCacheFor, nil,
+		"Kinds of objects read from the API server on demand instead of being watched and cached, bounding the controller's memory on clusters with many of them. Supported kinds: Pod")
+}
+
+func (cfg *CacheConfig) Validate() error {
+	for _, kind := range cfg.DisableCacheFor {
+		if _, ok := uncachedObjectsByKind[kind]; !ok {
+			return errors.Errorf("%s must only contain supported kinds [%s]: %s", flagDisableCacheFor, CacheKindPod, kind)
+		}
+	}
+	return nil
+}
+
+// IsCacheDisabled tests whether objects of kind are read from the API server instead of the cache.
+func (cfg *CacheConfig) IsCacheDisabled(kind string) bool {
+	for _, disabledKind := range cfg.DisableCacheFor {
+		if disabledKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// UncachedObjects returns the objects of kinds read from the API server instead of the cache.
+func (cfg *CacheConfig) UncachedObjects() []client.Object {
+	var objs []client.Object
+	for _, kind := range cfg.DisableCacheFor {
+		objs = append(objs, uncachedObjectsByKind[kind])
+	}
+	return objs
+}
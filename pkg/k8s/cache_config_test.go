@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCacheConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CacheConfig
+		wantErr error
+	}{
+		{
+			name: "all kinds cached",
+			cfg:  CacheConfig{},
+		},
+		{
+			name: "cache disabled for pods",
+			cfg:  CacheConfig{DisableCacheFor: []string{"Pod"}},
+		},
+		{
+			name:    "cache disabled for unsupported kind",
+			cfg:     CacheConfig{DisableCacheFor: []string{"Pod", "VirtualNode"}},
+			wantErr: errors.New("disable-cache-for must only contain supported kinds [Pod]: VirtualNode"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCacheConfig_UncachedObjects(t *testing.T) {
+	cfg := CacheConfig{DisableCacheFor: []string{"Pod"}}
+	assert.True(t, cfg.IsCacheDisabled(CacheKindPod))
+	assert.Equal(t, []client.Object{&corev1.Pod{}}, cfg.UncachedObjects())
+
+	cfg = CacheConfig{}
+	assert.False(t, cfg.IsCacheDisabled(CacheKindPod))
+	assert.Empty(t, cfg.UncachedObjects())
+}
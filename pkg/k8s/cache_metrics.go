@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewInstrumentedCacheFunc wraps newCache to record the number of objects cached per kind into metric appmesh_controller_cache_objects.
+// kinds are recorded as their informers are started, i.e. upon first watch or read.
+func NewInstrumentedCacheFunc(newCache cache.NewCacheFunc, registerer prometheus.Registerer) (cache.NewCacheFunc, error) {
+	objectsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "appmesh",
+		Name:      "controller_cache_objects",
+		Help:      "Number of objects in the informer cache of the controller",
+	}, []string{"kind"})
+	if err := registerer.Register(objectsGauge); err != nil {
+		return nil, errors.Wrap(err, "failed to register cache metrics")
+	}
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		c, err := newCache(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return newInstrumentedCache(c, opts.Scheme, objectsGauge), nil
+	}, nil
+}
+
+func newInstrumentedCache(c cache.Cache, scheme *runtime.Scheme, objectsGauge *prometheus.GaugeVec) *instrumentedCache {
+	return &instrumentedCache{
+		Cache:             c,
+		scheme:            scheme,
+		objectsGauge:      objectsGauge,
+		instrumentedKinds: sets.NewString(),
+	}
+}
+
+var _ cache.Cache = &instrumentedCache{}
+
+// instrumentedCache counts objects of each informer of the cache with an event handler,
+// which is notified of the objects already cached when it's added to a started informer.
+type instrumentedCache struct {
+	cache.Cache
+	scheme       *runtime.Scheme
+	objectsGauge *prometheus.GaugeVec
+
+	mutex             sync.Mutex
+	instrumentedKinds sets.String
+}
+
+func (c *instrumentedCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.instrument(ctx, obj); err != nil {
+		return err
+	}
+	return c.Cache.Get(ctx, key, obj, opts...)
+}
+
+func (c *instrumentedCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	obj, err := c.listItemPrototype(list)
+	if err != nil {
+		return err
+	}
+	if err := c.instrument(ctx, obj); err != nil {
+		return err
+	}
+	return c.Cache.List(ctx, list, opts...)
+}
+
+func (c *instrumentedCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	if err := c.instrument(ctx, obj); err != nil {
+		return nil, err
+	}
+	return c.Cache.GetInformer(ctx, obj)
+}
+
+// instrument adds the event handler counting objects to the informer of obj's kind, unless it's added already.
+func (c *instrumentedCache) instrument(ctx context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	_, isUnstructured := obj.(*unstructured.Unstructured)
+	// structured and unstructured objects of the same kind are cached by separate informers.
+	instrumentedKind := fmt.Sprintf("%s/unstructured=%v", gvk.String(), isUnstructured)
+	if c.isInstrumented(instrumentedKind) {
+		return nil
+	}
+	// getting the informer of a started cache waits for it to sync, so it's done without holding the lock.
+	informer, err := c.Cache.GetInformer(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.instrumentedKinds.Has(instrumentedKind) {
+		return nil
+	}
+	kindGauge := c.objectsGauge.WithLabelValues(gvk.Kind)
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { kindGauge.Inc() },
+		DeleteFunc: func(obj interface{}) { kindGauge.Dec() },
+	}); err != nil {
+		return errors.Wrapf(err, "failed to instrument cache of %s", gvk.Kind)
+	}
+	c.instrumentedKinds.Insert(instrumentedKind)
+	return nil
+}
+
+func (c *instrumentedCache) isInstrumented(instrumentedKind string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.instrumentedKinds.Has(instrumentedKind)
+}
+
+// listItemPrototype returns an empty object of the kind of items of list.
+func (c *instrumentedCache) listItemPrototype(list client.ObjectList) (client.Object, error) {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if _, isUnstructured := list.(*unstructured.UnstructuredList); isUnstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj, nil
+	}
+	obj, err := c.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, errors.Errorf("%s isn't a client.Object", gvk.Kind)
+	}
+	return clientObj, nil
+}
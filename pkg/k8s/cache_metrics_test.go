@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_instrumentedCache(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	fakeInformers := &informertest.FakeInformers{Scheme: k8sSchema}
+	registry := prometheus.NewRegistry()
+	newCache, err := NewInstrumentedCacheFunc(func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		return fakeInformers, nil
+	}, registry)
+	assert.NoError(t, err)
+	c, err := newCache(nil, cache.Options{Scheme: k8sSchema})
+	assert.NoError(t, err)
+
+	// pods are instrumented upon watch, virtualNodes upon read.
+	_, err = c.GetInformer(ctx, &corev1.Pod{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.List(ctx, &appmesh.VirtualNodeList{}))
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "my-ns", Name: "my-vn"}, &appmesh.VirtualNode{}))
+
+	podInformer, err := fakeInformers.FakeInformerFor(&corev1.Pod{})
+	assert.NoError(t, err)
+	vnInformer, err := fakeInformers.FakeInformerFor(&appmesh.VirtualNode{})
+	assert.NoError(t, err)
+	podInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-1"}})
+	podInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-2"}})
+	podInformer.Update(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-2"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-2", Labels: map[string]string{"app": "my-app"}}})
+	podInformer.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-1"}})
+	vnInformer.Add(&appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vn"}})
+
+	assert.NoError(t, testutil.CollectAndCompare(registry, strings.NewReader(`
+# HELP appmesh_controller_cache_objects Number of objects in the informer cache of the controller
+# TYPE appmesh_controller_cache_objects gauge
+appmesh_controller_cache_objects{kind="Pod"} 1
+appmesh_controller_cache_objects{kind="VirtualNode"} 1
+`), "appmesh_controller_cache_objects"))
+}
+
+func Test_instrumentedCache_instrumentedOnce(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	fakeInformers := &informertest.FakeInformers{Scheme: k8sSchema}
+	objectsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "cache_objects"}, []string{"kind"})
+	c := newInstrumentedCache(fakeInformers, k8sSchema, objectsGauge)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, c.List(ctx, &corev1.PodList{}))
+	}
+	podInformer, err := fakeInformers.FakeInformerFor(&corev1.Pod{})
+	assert.NoError(t, err)
+	podInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "pod-1"}})
+	assert.Equal(t, float64(1), testutil.ToFloat64(objectsGauge.WithLabelValues("Pod")))
+}
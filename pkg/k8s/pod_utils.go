@@ -1,13 +1,18 @@
 package k8s
 
 import (
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
 	ConditionAWSCloudMapHealthy = "conditions.appmesh.k8s.aws/aws-cloudmap-healthy"
+	// ConditionEnvoyReady is set once injected Envoy is LIVE and has its listeners configured.
+	ConditionEnvoyReady = "appmesh.k8s.aws/envoy-ready"
 )
 
 // GetPodCondition will get pointer to Pod's existing condition.
@@ -49,3 +54,50 @@ func UpdatePodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType,
 	existingCondition.LastProbeTime = metav1.Now()
 	return true
 }
+
+// HasPodReadinessGate checks whether pod has readinessGate for conditionType.
+func HasPodReadinessGate(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, item := range pod.Spec.ReadinessGates {
+		if item.ConditionType == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// PodOwnerKindPod is the owner kind of bare pods, which aren't controlled by any workload.
+	PodOwnerKindPod = "Pod"
+
+	ownerKindReplicaSet  = "ReplicaSet"
+	ownerKindDeployment  = "Deployment"
+	labelPodTemplateHash = "pod-template-hash"
+)
+
+// ResolvePodOwner resolves the kind and name of the workload owning pod from its controller ownerReference.
+// ReplicaSets created by Deployments are resolved to their Deployment, pods controlled by other controllers,
+// e.g. custom resources of third-party operators, are resolved to their controller.
+// Bare pods without controller are resolved to themselves.
+func ResolvePodOwner(pod *corev1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return PodOwnerKindPod, pod.Name
+	}
+	if owner.Kind == ownerKindReplicaSet {
+		// ReplicaSets created by Deployments are named <deployment>-<pod-template-hash>.
+		if hash := pod.Labels[labelPodTemplateHash]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return ownerKindDeployment, strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+// PodIdentifier identifies pod in namespace for messages.
+// Pods created with generateName aren't named yet upon admission, they're identified by their generateName instead.
+func PodIdentifier(namespace string, pod *corev1.Pod) string {
+	name := pod.Name
+	if name == "" && pod.GenerateName != "" {
+		name = pod.GenerateName + "*"
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}.String()
+}
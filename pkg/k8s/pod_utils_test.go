@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolvePodOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		wantKind string
+		wantName string
+	}{
+		{
+			name: "bare pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-pod",
+				},
+			},
+			wantKind: "Pod",
+			wantName: "my-pod",
+		},
+		{
+			name: "pod owned by non-controller owner",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-pod",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind: "ConfigMap",
+							Name: "my-cm",
+						},
+					},
+				},
+			},
+			wantKind: "Pod",
+			wantName: "my-pod",
+		},
+		{
+			name: "pod controlled by custom resource of operator",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-pod",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "Workload",
+							Name:       "my-workload",
+							Controller: aws.Bool(true),
+						},
+					},
+				},
+			},
+			wantKind: "Workload",
+			wantName: "my-workload",
+		},
+		{
+			name: "pod controlled by ReplicaSet of Deployment",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-app-5d8f7c9b4-x2x7k",
+					Labels: map[string]string{
+						"pod-template-hash": "5d8f7c9b4",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "ReplicaSet",
+							Name:       "my-app-5d8f7c9b4",
+							Controller: aws.Bool(true),
+						},
+					},
+				},
+			},
+			wantKind: "Deployment",
+			wantName: "my-app",
+		},
+		{
+			name: "pod controlled by standalone ReplicaSet",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-rs-x2x7k",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "ReplicaSet",
+							Name:       "my-rs",
+							Controller: aws.Bool(true),
+						},
+					},
+				},
+			},
+			wantKind: "ReplicaSet",
+			wantName: "my-rs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKind, gotName := ResolvePodOwner(tt.pod)
+			assert.Equal(t, tt.wantKind, gotKind)
+			assert.Equal(t, tt.wantName, gotName)
+		})
+	}
+}
+
+func TestPodIdentifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		pod       *corev1.Pod
+		want      string
+	}{
+		{
+			name:      "named pod",
+			namespace: "my-ns",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-pod",
+				},
+			},
+			want: "my-ns/my-pod",
+		},
+		{
+			name:      "pod with generateName",
+			namespace: "my-ns",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "my-workload-",
+				},
+			},
+			want: "my-ns/my-workload-*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PodIdentifier(tt.namespace, tt.pod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
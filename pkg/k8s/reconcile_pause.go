@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReconcileAnnotation pauses reconciliation of an object when it's set to ReconcilePaused,
+	// so its AWS resources can be changed manually, e.g. during incident response, without the controller reverting them.
+	ReconcileAnnotation = "appmesh.k8s.aws/reconcile"
+	// ReconcilePaused is the value of ReconcileAnnotation pausing reconciliation.
+	ReconcilePaused = "paused"
+)
+
+// IsReconcilePaused checks whether reconciliation of obj is paused by ReconcileAnnotation.
+func IsReconcilePaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[ReconcileAnnotation] == ReconcilePaused
+}
+
+// UpdateReconciliationPausedCondition updates the ReconciliationPaused condition of obj, where conditions are obj's status conditions.
+// the condition is only set to False if obj was paused before. returns whether reconciliation of obj is paused.
+func UpdateReconciliationPausedCondition(ctx context.Context, k8sClient client.Client, obj client.Object, conditions *[]metav1.Condition) (bool, error) {
+	paused := IsReconcilePaused(obj)
+	if !paused && meta.FindStatusCondition(*conditions, appmesh.ConditionReconciliationPaused) == nil {
+		return false, nil
+	}
+	newCondition := metav1.Condition{
+		Type:               appmesh.ConditionReconciliationPaused,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             appmesh.ReasonResumed,
+	}
+	if paused {
+		newCondition.Status = metav1.ConditionTrue
+		newCondition.Reason = appmesh.ReasonPausedByAnnotation
+		newCondition.Message = fmt.Sprintf("reconciliation is paused by annotation %s=%s, remove it to resume", ReconcileAnnotation, ReconcilePaused)
+	}
+
+	oldObj := obj.DeepCopyObject().(client.Object)
+	if SetStatusCondition(conditions, newCondition) {
+		if err := k8sClient.Status().Patch(ctx, obj, client.MergeFrom(oldObj)); err != nil {
+			return paused, err
+		}
+	}
+	return paused, nil
+}
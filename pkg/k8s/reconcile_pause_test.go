@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_UpdateReconciliationPausedCondition(t *testing.T) {
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	ctx := context.Background()
+
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vr-1"},
+	}
+	assert.NoError(t, k8sClient.Create(ctx, vr))
+
+	paused, err := UpdateReconciliationPausedCondition(ctx, k8sClient, vr, &vr.Status.Conditions)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+	assert.Empty(t, vr.Status.Conditions)
+
+	vr.Annotations = map[string]string{ReconcileAnnotation: ReconcilePaused}
+	paused, err = UpdateReconciliationPausedCondition(ctx, k8sClient, vr, &vr.Status.Conditions)
+	assert.NoError(t, err)
+	assert.True(t, paused)
+	gotVR := &appmesh.VirtualRouter{}
+	assert.NoError(t, k8sClient.Get(ctx, NamespacedName(vr), gotVR))
+	condition := meta.FindStatusCondition(gotVR.Status.Conditions, appmesh.ConditionReconciliationPaused)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, appmesh.ReasonPausedByAnnotation, condition.Reason)
+
+	vr.Annotations = nil
+	paused, err = UpdateReconciliationPausedCondition(ctx, k8sClient, vr, &vr.Status.Conditions)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+	assert.NoError(t, k8sClient.Get(ctx, NamespacedName(vr), gotVR))
+	condition = meta.FindStatusCondition(gotVR.Status.Conditions, appmesh.ConditionReconciliationPaused)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, appmesh.ReasonResumed, condition.Reason)
+}
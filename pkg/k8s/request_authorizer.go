@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AccessAttributes are the attributes of the access reviewed by RequestAuthorizer.
+// exactly one of ResourceAttributes and NonResourceAttributes must be set.
+type AccessAttributes struct {
+	ResourceAttributes    *authorizationv1.ResourceAttributes
+	NonResourceAttributes *authorizationv1.NonResourceAttributes
+	// Description describes the access in errors, e.g. "get pods/proxy of pod my-ns/my-pod".
+	Description string
+}
+
+// RequestAuthorizer authorizes HTTP requests by the bearer token of their caller.
+type RequestAuthorizer interface {
+	// Authorize authenticates the bearer token of req with a TokenReview, and checks whether its identity is allowed the access of attrs with a SubjectAccessReview.
+	// returns the username, or the HTTP status code along with the error if not allowed.
+	Authorize(ctx context.Context, req *http.Request, attrs AccessAttributes) (string, int, error)
+}
+
+// NewDefaultRequestAuthorizer constructs new RequestAuthorizer.
+func NewDefaultRequestAuthorizer(clientSet kubernetes.Interface) RequestAuthorizer {
+	return &defaultRequestAuthorizer{
+		clientSet: clientSet,
+	}
+}
+
+var _ RequestAuthorizer = &defaultRequestAuthorizer{}
+
+type defaultRequestAuthorizer struct {
+	clientSet kubernetes.Interface
+}
+
+func (a *defaultRequestAuthorizer) Authorize(ctx context.Context, req *http.Request, attrs AccessAttributes) (string, int, error) {
+	token, ok := bearerToken(req)
+	if !ok {
+		return "", http.StatusUnauthorized, errors.New("bearer token is required")
+	}
+	tokenReview, err := a.clientSet.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", http.StatusInternalServerError, errors.Wrap(err, "failed to review bearer token")
+	}
+	if !tokenReview.Status.Authenticated {
+		return "", http.StatusUnauthorized, errors.New("invalid bearer token")
+	}
+
+	user := tokenReview.Status.User
+	var extra map[string]authorizationv1.ExtraValue
+	if len(user.Extra) != 0 {
+		extra = make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+	}
+	accessReview, err := a.clientSet.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes:    attrs.ResourceAttributes,
+			NonResourceAttributes: attrs.NonResourceAttributes,
+			User:                  user.Username,
+			Groups:                user.Groups,
+			UID:                   user.UID,
+			Extra:                 extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", http.StatusInternalServerError, errors.Wrapf(err, "failed to review access to %s", attrs.Description)
+	}
+	if !accessReview.Status.Allowed {
+		return "", http.StatusForbidden, errors.Errorf("%s is not allowed to %s", user.Username, attrs.Description)
+	}
+	return user.Username, http.StatusOK, nil
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && len(token) != 0
+}
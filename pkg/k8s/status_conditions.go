@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetStatusCondition sets newCondition into conditions. returns whether conditions is changed.
+// the LastTransitionTime of condition is only updated when its status changes.
+func SetStatusCondition(conditions *[]metav1.Condition, newCondition metav1.Condition) bool {
+	existingCondition := meta.FindStatusCondition(*conditions, newCondition.Type)
+	if existingCondition != nil &&
+		existingCondition.Status == newCondition.Status &&
+		existingCondition.Reason == newCondition.Reason &&
+		existingCondition.Message == newCondition.Message &&
+		existingCondition.ObservedGeneration == newCondition.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(conditions, newCondition)
+	return true
+}
+
+// SetReconciledConditions sets the standard conditions of a resource that has been synced to AppMesh.
+// active indicates whether the AppMesh resource is in ACTIVE status.
+// activeConditionType is the per-kind active condition type, e.g. appmesh.VirtualNodeActive.
+// returns whether conditions is changed.
+func SetReconciledConditions(conditions *[]metav1.Condition, generation int64, active bool, activeConditionType string) bool {
+	activeStatus, degradedStatus, reason := metav1.ConditionTrue, metav1.ConditionFalse, appmesh.ReasonReconciled
+	if !active {
+		activeStatus, degradedStatus, reason = metav1.ConditionFalse, metav1.ConditionTrue, appmesh.ReasonAppMeshResourceInactive
+	}
+	newConditions := []metav1.Condition{
+		{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionDegraded, Status: degradedStatus, Reason: reason},
+		{Type: appmesh.ConditionReady, Status: activeStatus, Reason: reason},
+		{Type: activeConditionType, Status: activeStatus, Reason: reason},
+	}
+	return setStatusConditions(conditions, generation, newConditions)
+}
+
+// SetDegradedConditions sets the standard conditions of a resource that has been synced to AppMesh,
+// but whose AppMesh state could only be partially observed, e.g. some of its child resources failed to be listed.
+// Degraded is set to True with degradedReason and err, other conditions are set as by SetReconciledConditions.
+// returns whether conditions is changed.
+func SetDegradedConditions(conditions *[]metav1.Condition, generation int64, active bool, activeConditionType string, degradedReason string, err error) bool {
+	activeStatus, reason := metav1.ConditionTrue, appmesh.ReasonReconciled
+	if !active {
+		activeStatus, reason = metav1.ConditionFalse, appmesh.ReasonAppMeshResourceInactive
+	}
+	newConditions := []metav1.Condition{
+		{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, Reason: degradedReason, Message: conditionMessage(err)},
+		{Type: appmesh.ConditionReady, Status: activeStatus, Reason: reason},
+		{Type: activeConditionType, Status: activeStatus, Reason: reason},
+	}
+	return setStatusConditions(conditions, generation, newConditions)
+}
+
+// SetFailedConditions sets the standard conditions of a resource that failed to reconcile.
+// failedConditionType is either appmesh.ConditionReferencesResolved or appmesh.ConditionSynced.
+// the per-kind active condition is left as is, since the AppMesh resource's status is unknown upon failures.
+// Degraded is set to True as well if err is rejected by the AppMesh circuit breaker, with reason appmesh.ReasonAppMeshUnavailable.
+// reason is appmesh.ReasonSpecRejected instead if err is terminal, i.e. retries can't resolve it.
+// returns whether conditions is changed.
+func SetFailedConditions(conditions *[]metav1.Condition, generation int64, failedConditionType string, reason string, err error) bool {
+	message := conditionMessage(err)
+	unavailable := circuitbreaker.IsOpenError(err)
+	if unavailable {
+		reason = appmesh.ReasonAppMeshUnavailable
+	} else if runtime.IsTerminalError(err) {
+		reason = appmesh.ReasonSpecRejected
+	}
+	var newConditions []metav1.Condition
+	if failedConditionType == appmesh.ConditionSynced {
+		newConditions = append(newConditions, metav1.Condition{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, Reason: appmesh.ReasonReconciled})
+	}
+	newConditions = append(newConditions,
+		metav1.Condition{Type: failedConditionType, Status: metav1.ConditionFalse, Reason: reason, Message: message},
+		metav1.Condition{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, Reason: reason, Message: message},
+	)
+	if unavailable {
+		newConditions = append(newConditions, metav1.Condition{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, Reason: reason, Message: message})
+	}
+	return setStatusConditions(conditions, generation, newConditions)
+}
+
+func setStatusConditions(conditions *[]metav1.Condition, generation int64, newConditions []metav1.Condition) bool {
+	hasChanged := false
+	for _, newCondition := range newConditions {
+		newCondition.ObservedGeneration = generation
+		if SetStatusCondition(conditions, newCondition) {
+			hasChanged = true
+		}
+	}
+	return hasChanged
+}
+
+// conditionMessage returns a message for err that's stable across retries.
+// AWS errors embed a per-request ID, which would otherwise cause a status update upon every failed retry.
+func conditionMessage(err error) string {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return fmt.Sprintf("%s: %s", awsErr.Code(), awsErr.Message())
+	}
+	return err.Error()
+}
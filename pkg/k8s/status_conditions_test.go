@@ -0,0 +1,189 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusCondition(t *testing.T) {
+	lastTransitionTime := metav1.Unix(1600000000, 0)
+	tests := []struct {
+		name                   string
+		conditions             []metav1.Condition
+		newCondition           metav1.Condition
+		wantConditions         []metav1.Condition
+		wantChanged            bool
+		wantTransitionTimeKept bool
+	}{
+		{
+			name:         "condition added",
+			conditions:   nil,
+			newCondition: metav1.Condition{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "condition unchanged",
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled, LastTransitionTime: lastTransitionTime},
+			},
+			newCondition: metav1.Condition{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled, LastTransitionTime: lastTransitionTime},
+			},
+			wantChanged:            false,
+			wantTransitionTimeKept: true,
+		},
+		{
+			name: "condition updated by observedGeneration without status change",
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled, LastTransitionTime: lastTransitionTime},
+			},
+			newCondition: metav1.Condition{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled, LastTransitionTime: lastTransitionTime},
+			},
+			wantChanged:            true,
+			wantTransitionTimeKept: true,
+		},
+		{
+			name: "condition updated by status change",
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled, LastTransitionTime: lastTransitionTime},
+			},
+			newCondition: metav1.Condition{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 1, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 1, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+			},
+			wantChanged:            true,
+			wantTransitionTimeKept: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := tt.conditions
+			gotChanged := SetStatusCondition(&conditions, tt.newCondition)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, conditions, opts), "diff", cmp.Diff(tt.wantConditions, conditions, opts))
+			assert.Equal(t, tt.wantChanged, gotChanged)
+			assert.Equal(t, tt.wantTransitionTimeKept, conditions[0].LastTransitionTime.Equal(&lastTransitionTime))
+		})
+	}
+}
+
+func TestSetFailedConditions(t *testing.T) {
+	syncedCondition := metav1.Condition{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: appmesh.ReasonReconciled}
+	tests := []struct {
+		name                string
+		conditions          []metav1.Condition
+		failedConditionType string
+		reason              string
+		err                 error
+		wantConditions      []metav1.Condition
+	}{
+		{
+			name:                "references failure keeps synced condition",
+			conditions:          []metav1.Condition{syncedCondition},
+			failedConditionType: appmesh.ConditionReferencesResolved,
+			reason:              appmesh.ReasonReferencesNotReady,
+			err:                 errors.New("mesh is not active yet"),
+			wantConditions: []metav1.Condition{
+				syncedCondition,
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonReferencesNotReady, Message: "mesh is not active yet"},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonReferencesNotReady, Message: "mesh is not active yet"},
+			},
+		},
+		{
+			name:                "sync failure with AWS error",
+			conditions:          nil,
+			failedConditionType: appmesh.ConditionSynced,
+			reason:              appmesh.ReasonSyncFailed,
+			err: errors.Wrap(awserr.NewRequestFailure(awserr.New("BadRequestException", "invalid listener", nil), 400, "8f3ac0cc-0dbb-4a8a-8c7e-f24a5c2b9e33"),
+				"failed to update virtualNode"),
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonSyncFailed, Message: "BadRequestException: invalid listener"},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonSyncFailed, Message: "BadRequestException: invalid listener"},
+			},
+		},
+		{
+			name:                "sync failure with terminal AWS error",
+			conditions:          nil,
+			failedConditionType: appmesh.ConditionSynced,
+			reason:              appmesh.ReasonSyncFailed,
+			err: runtime.NewTerminalError(errors.Wrap(awserr.NewRequestFailure(awserr.New("BadRequestException", "invalid listener", nil), 400, "8f3ac0cc-0dbb-4a8a-8c7e-f24a5c2b9e33"),
+				"failed to update virtualNode")),
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonSpecRejected, Message: "BadRequestException: invalid listener"},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonSpecRejected, Message: "BadRequestException: invalid listener"},
+			},
+		},
+		{
+			name:                "sync failure rejected by circuit breaker",
+			conditions:          nil,
+			failedConditionType: appmesh.ConditionSynced,
+			reason:              appmesh.ReasonSyncFailed,
+			err:                 errors.Wrap(circuitbreaker.NewOpenError("App Mesh", 5, 30*time.Second, 12*time.Second), "failed to describe virtualNode"),
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshUnavailable, Message: "CircuitBreakerOpen: App Mesh calls are rejected for 30s after 5 consecutive failures"},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshUnavailable, Message: "CircuitBreakerOpen: App Mesh calls are rejected for 30s after 5 consecutive failures"},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshUnavailable, Message: "CircuitBreakerOpen: App Mesh calls are rejected for 30s after 5 consecutive failures"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := tt.conditions
+			gotChanged := SetFailedConditions(&conditions, 2, tt.failedConditionType, tt.reason, tt.err)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, conditions, opts), "diff", cmp.Diff(tt.wantConditions, conditions, opts))
+			assert.True(t, gotChanged)
+
+			gotChanged = SetFailedConditions(&conditions, 2, tt.failedConditionType, tt.reason, tt.err)
+			assert.False(t, gotChanged)
+		})
+	}
+}
+
+func TestSetDegradedConditions(t *testing.T) {
+	reconciled := func(conditionType string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled}
+	}
+	err := errors.Wrap(awserr.NewRequestFailure(awserr.New("ServiceUnavailableException", "service unavailable", nil), 503, "8f3ac0cc-0dbb-4a8a-8c7e-f24a5c2b9e33"),
+		"failed to list routes")
+	var conditions []metav1.Condition
+	gotChanged := SetDegradedConditions(&conditions, 2, true, appmesh.VirtualRouterActive, appmesh.ReasonRoutesPartiallyListed, err)
+	wantConditions := []metav1.Condition{
+		reconciled(appmesh.ConditionReferencesResolved),
+		reconciled(appmesh.ConditionSynced),
+		{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonRoutesPartiallyListed, Message: "ServiceUnavailableException: service unavailable"},
+		reconciled(appmesh.ConditionReady),
+		reconciled(appmesh.VirtualRouterActive),
+	}
+	opts := cmpopts.IgnoreTypes(metav1.Time{})
+	assert.True(t, cmp.Equal(wantConditions, conditions, opts), "diff", cmp.Diff(wantConditions, conditions, opts))
+	assert.True(t, gotChanged)
+
+	gotChanged = SetDegradedConditions(&conditions, 2, true, appmesh.VirtualRouterActive, appmesh.ReasonRoutesPartiallyListed, err)
+	assert.False(t, gotChanged)
+
+	gotChanged = SetReconciledConditions(&conditions, 2, true, appmesh.VirtualRouterActive)
+	assert.True(t, gotChanged)
+	degraded := meta.FindStatusCondition(conditions, appmesh.ConditionDegraded)
+	assert.Equal(t, metav1.ConditionFalse, degraded.Status)
+}
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ForceDeleteAnnotation allows removing the finalizer of an object stuck deleting, which leaves its AWS resources behind.
+	// it only takes effect once AWS resources failed to be deleted for longer than the finalizer timeout.
+	ForceDeleteAnnotation = "appmesh.k8s.aws/force-delete"
+)
+
+// StuckDeletionHandler handles objects whose AWS resources repeatedly fail to be deleted during finalization.
+type StuckDeletionHandler interface {
+	// HandleCleanupError handles cleanupErr of deleting AWS resources of obj, where conditions are obj's status conditions.
+	// returns nil if obj is force deleted, so its finalizer should be removed without deleting AWS resources.
+	// returns cleanupErr otherwise.
+	HandleCleanupError(ctx context.Context, obj client.Object, conditions *[]metav1.Condition, cleanupErr error) error
+}
+
+// NewDefaultStuckDeletionHandler constructs new StuckDeletionHandler.
+// objects are considered stuck once deleting for longer than finalizerTimeout, a non-positive finalizerTimeout disables it.
+func NewDefaultStuckDeletionHandler(k8sClient client.Client, recorder record.EventRecorder, finalizerTimeout time.Duration, log logr.Logger) StuckDeletionHandler {
+	return &defaultStuckDeletionHandler{
+		k8sClient:        k8sClient,
+		recorder:         recorder,
+		finalizerTimeout: finalizerTimeout,
+		log:              log,
+	}
+}
+
+var _ StuckDeletionHandler = &defaultStuckDeletionHandler{}
+
+type defaultStuckDeletionHandler struct {
+	k8sClient        client.Client
+	recorder         record.EventRecorder
+	finalizerTimeout time.Duration
+	log              logr.Logger
+}
+
+func (h *defaultStuckDeletionHandler) HandleCleanupError(ctx context.Context, obj client.Object, conditions *[]metav1.Condition, cleanupErr error) error {
+	if h.finalizerTimeout <= 0 || obj.GetDeletionTimestamp() == nil {
+		return cleanupErr
+	}
+	deletingFor := time.Since(obj.GetDeletionTimestamp().Time).Round(time.Second)
+	if deletingFor < h.finalizerTimeout {
+		return cleanupErr
+	}
+	if obj.GetAnnotations()[ForceDeleteAnnotation] == "true" {
+		h.log.Info("force deleting object, AWS resources are left behind", "object", NamespacedName(obj), "error", cleanupErr.Error())
+		h.recorder.Eventf(obj, corev1.EventTypeWarning, "ForceDeleted",
+			"removing finalizer without deleting AWS resources, which failed to be deleted for %v: %v", deletingFor, cleanupErr)
+		return nil
+	}
+	h.recorder.Eventf(obj, corev1.EventTypeWarning, appmesh.ConditionStuckDeleting,
+		"AWS resources failed to be deleted for %v, annotate with %s=true to remove finalizer without deleting them: %v", deletingFor, ForceDeleteAnnotation, cleanupErr)
+
+	oldObj := obj.DeepCopyObject().(client.Object)
+	if SetStatusCondition(conditions, metav1.Condition{
+		Type:               appmesh.ConditionStuckDeleting,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             appmesh.ReasonCleanupTimeout,
+		Message:            conditionMessage(cleanupErr),
+	}) {
+		if err := h.k8sClient.Status().Patch(ctx, obj, client.MergeFrom(oldObj)); err != nil {
+			h.log.Error(err, "failed to update StuckDeleting condition", "object", NamespacedName(obj))
+		}
+	}
+	return cleanupErr
+}
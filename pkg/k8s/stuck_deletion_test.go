@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultStuckDeletionHandler_HandleCleanupError(t *testing.T) {
+	cleanupErr := errors.New("ResourceInUseException: mesh has virtualNodes")
+	tests := []struct {
+		name             string
+		finalizerTimeout time.Duration
+		deletingFor      time.Duration
+		annotations      map[string]string
+		wantErr          error
+		wantConditions   []metav1.Condition
+		wantEvents       int
+	}{
+		{
+			name:             "timeout disabled",
+			finalizerTimeout: 0,
+			deletingFor:      time.Hour,
+			wantErr:          cleanupErr,
+		},
+		{
+			name:             "deleting within timeout",
+			finalizerTimeout: 30 * time.Minute,
+			deletingFor:      time.Minute,
+			annotations:      map[string]string{ForceDeleteAnnotation: "true"},
+			wantErr:          cleanupErr,
+		},
+		{
+			name:             "deleting beyond timeout",
+			finalizerTimeout: 30 * time.Minute,
+			deletingFor:      time.Hour,
+			wantErr:          cleanupErr,
+			wantConditions: []metav1.Condition{
+				{
+					Type:               appmesh.ConditionStuckDeleting,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+					Reason:             appmesh.ReasonCleanupTimeout,
+					Message:            "ResourceInUseException: mesh has virtualNodes",
+				},
+			},
+			wantEvents: 1,
+		},
+		{
+			name:             "deleting beyond timeout with force-delete annotation",
+			finalizerTimeout: 30 * time.Minute,
+			deletingFor:      time.Hour,
+			annotations:      map[string]string{ForceDeleteAnnotation: "true"},
+			wantErr:          nil,
+			wantEvents:       1,
+		},
+		{
+			name:             "deleting beyond timeout with force-delete annotation not true",
+			finalizerTimeout: 30 * time.Minute,
+			deletingFor:      time.Hour,
+			annotations:      map[string]string{ForceDeleteAnnotation: "false"},
+			wantErr:          cleanupErr,
+			wantConditions: []metav1.Condition{
+				{
+					Type:               appmesh.ConditionStuckDeleting,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+					Reason:             appmesh.ReasonCleanupTimeout,
+					Message:            "ResourceInUseException: mesh has virtualNodes",
+				},
+			},
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			recorder := record.NewFakeRecorder(10)
+			h := NewDefaultStuckDeletionHandler(k8sClient, recorder, tt.finalizerTimeout, logr.New(&log.NullLogSink{}))
+
+			mesh := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-mesh",
+					Generation:  1,
+					Annotations: tt.annotations,
+					Finalizers:  []string{"finalizers.appmesh.k8s.aws/aws-resources"},
+				},
+			}
+			err := k8sClient.Create(ctx, mesh.DeepCopy())
+			assert.NoError(t, err)
+			deletionTimestamp := metav1.NewTime(time.Now().Add(-tt.deletingFor))
+			mesh.DeletionTimestamp = &deletionTimestamp
+
+			gotErr := h.HandleCleanupError(ctx, mesh, &mesh.Status.Conditions, cleanupErr)
+			assert.Equal(t, tt.wantErr, gotErr)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, mesh.Status.Conditions, opts), "diff", cmp.Diff(tt.wantConditions, mesh.Status.Conditions, opts))
+			assert.Len(t, recorder.Events, tt.wantEvents)
+		})
+	}
+}
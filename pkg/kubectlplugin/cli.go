@@ -0,0 +1,182 @@
+package kubectlplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PluginName is the name of the kubectl plugin, invoked as "kubectl appmesh".
+	PluginName = "kubectl-appmesh"
+
+	exitCodeOK          = 0
+	exitCodeDifferences = 1
+	exitCodeFailure     = 2
+)
+
+// command is a subcommand of the plugin.
+type command struct {
+	name     string
+	synopsis string
+	short    string
+	// run runs the command with its parsed flags and remaining args, returning the exit code.
+	run func(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int
+	// bindFlags binds command specific flags into o, optional.
+	bindFlags func(fs *pflag.FlagSet, o *options)
+}
+
+// options are the flags of subcommands, the first group is shared by all of them.
+type options struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	region     string
+
+	enableBackendGroups bool
+	enableMeshPolicies  bool
+	allNamespaces       bool
+	mesh                string
+	output              string
+	apply               bool
+	backupConfigMap     string
+	backupS3Bucket      string
+	backupS3Prefix      string
+}
+
+func (o *options) bindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to the kubectl loading rules.")
+	fs.StringVar(&o.context, "context", "", "The name of the kubeconfig context to use.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the resources, defaults to the namespace of the kubeconfig context.")
+	fs.StringVar(&o.region, "region", "", "The AWS region of the mesh, defaults to the AWS shared config.")
+}
+
+// Run runs the plugin with args, which starts with the subcommand.
+// returns the exit code, which is 1 if diff finds differences, or 2 upon failures.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	commands := []command{describeCommand(), diffCommand(), graphCommand(), importCommand(), lookupCommand(), restoreCommand(), snapshotsCommand()}
+	usage := func() {
+		fmt.Fprintf(stderr, "Usage: kubectl appmesh <command> [flags]\n\nCommands:\n")
+		tw := tabwriter.NewWriter(stderr, 0, 8, 2, ' ', 0)
+		for _, cmd := range commands {
+			fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.short)
+		}
+		tw.Flush()
+	}
+	if len(args) == 0 {
+		usage()
+		return exitCodeFailure
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		o := &options{}
+		fs := pflag.NewFlagSet(cmd.name, pflag.ContinueOnError)
+		fs.SetOutput(stderr)
+		o.bindFlags(fs)
+		if cmd.bindFlags != nil {
+			cmd.bindFlags(fs, o)
+		}
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "%s\n\nUsage: kubectl appmesh %s [flags]\n", cmd.short, cmd.synopsis)
+			fs.PrintDefaults()
+		}
+		if err := fs.Parse(args[1:]); err != nil {
+			if err == pflag.ErrHelp {
+				return exitCodeOK
+			}
+			return exitCodeFailure
+		}
+		return cmd.run(context.Background(), o, fs.Args(), stdout, stderr)
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage()
+		return exitCodeOK
+	}
+	fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+	usage()
+	return exitCodeFailure
+}
+
+// clients are the clients to k8s and AppMesh used by subcommands.
+type clients struct {
+	k8sClient          client.Client
+	appMeshSDK         services.AppMesh
+	referencesResolver references.Resolver
+	// namespace is the namespace of resources, resolved from flags or kubeconfig.
+	namespace string
+}
+
+// newK8sClient constructs the k8s client and resolves the namespace from o.
+func newK8sClient(o *options) (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to load kubeconfig")
+	}
+	namespace := o.namespace
+	if len(namespace) == 0 {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", errors.Wrap(err, "failed to resolve namespace")
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appmesh.AddToScheme(scheme)
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to construct k8s client")
+	}
+	return k8sClient, namespace, nil
+}
+
+// newClients constructs clients to k8s and AppMesh for o.
+func newClients(o *options) (*clients, error) {
+	k8sClient, namespace, err := newK8sClient(o)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := newAWSSession(o)
+	if err != nil {
+		return nil, err
+	}
+	return &clients{
+		k8sClient:          k8sClient,
+		appMeshSDK:         services.NewAppMesh(sess),
+		referencesResolver: references.NewDefaultResolver(k8sClient, logr.Discard()),
+		namespace:          namespace,
+	}, nil
+}
+
+// newAWSSession constructs the AWS session of o, from the AWS shared config.
+func newAWSSession(o *options) (*session.Session, error) {
+	awsConfig := aws.Config{}
+	if len(o.region) != 0 {
+		awsConfig.Region = aws.String(o.region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            awsConfig,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to construct AWS session")
+	}
+	return sess, nil
+}
@@ -0,0 +1,300 @@
+package kubectlplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func describeCommand() command {
+	return command{
+		name:     "describe",
+		synopsis: "describe <virtualnode|virtualrouter|virtualservice> <name>",
+		short:    "Show the merged k8s and AppMesh state of a resource.",
+		run:      runDescribe,
+	}
+}
+
+func runDescribe(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintf(stderr, "describe requires a kind and a name\n")
+		return exitCodeFailure
+	}
+	kind, err := parseKind(args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	c, err := newClients(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	state, err := describeResource(ctx, c, kind, types.NamespacedName{Namespace: c.namespace, Name: args[1]})
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	if err := state.write(stdout); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	return exitCodeOK
+}
+
+// resourceState is the merged k8s and AppMesh state of a resource.
+type resourceState struct {
+	kind       string
+	key        types.NamespacedName
+	meshName   string
+	awsName    string
+	arn        string
+	conditions []metav1.Condition
+
+	// sdk is the state of the AppMesh resource, nil if it doesn't exist.
+	sdk *sdkResourceState
+	// routes are the states of AppMesh routes of a virtualRouter.
+	routes []sdkRouteState
+}
+
+// sdkResourceState is the state of an AppMesh resource.
+type sdkResourceState struct {
+	status   string
+	metadata *appmeshsdk.ResourceMetadata
+	spec     interface{}
+}
+
+// sdkRouteState is the state of an AppMesh route.
+type sdkRouteState struct {
+	name     string
+	status   string
+	metadata *appmeshsdk.ResourceMetadata
+}
+
+func describeResource(ctx context.Context, c *clients, kind string, key types.NamespacedName) (*resourceState, error) {
+	switch kind {
+	case kindVirtualNode:
+		vn := &appmesh.VirtualNode{}
+		if err := c.k8sClient.Get(ctx, key, vn); err != nil {
+			return nil, err
+		}
+		ms, err := c.resolveMesh(ctx, vn.Spec.MeshRef)
+		if err != nil {
+			return nil, err
+		}
+		state := newResourceState(kind, key, ms, vn.Spec.AWSName, vn.Status.VirtualNodeARN, vn.Status.Conditions)
+		resp, err := c.appMeshSDK.DescribeVirtualNodeWithContext(ctx, &appmeshsdk.DescribeVirtualNodeInput{
+			MeshName:        ms.Spec.AWSName,
+			MeshOwner:       ms.Spec.MeshOwner,
+			VirtualNodeName: vn.Spec.AWSName,
+		})
+		if err := ignoreNotFound(err); err != nil {
+			return nil, errors.Wrap(err, "failed to describe AppMesh virtualNode")
+		}
+		if resp != nil && resp.VirtualNode != nil {
+			state.sdk = &sdkResourceState{
+				status:   aws.StringValue(resp.VirtualNode.Status.Status),
+				metadata: resp.VirtualNode.Metadata,
+				spec:     resp.VirtualNode.Spec,
+			}
+		}
+		return state, nil
+	case kindVirtualRouter:
+		vr := &appmesh.VirtualRouter{}
+		if err := c.k8sClient.Get(ctx, key, vr); err != nil {
+			return nil, err
+		}
+		ms, err := c.resolveMesh(ctx, vr.Spec.MeshRef)
+		if err != nil {
+			return nil, err
+		}
+		state := newResourceState(kind, key, ms, vr.Spec.AWSName, vr.Status.VirtualRouterARN, vr.Status.Conditions)
+		resp, err := c.appMeshSDK.DescribeVirtualRouterWithContext(ctx, &appmeshsdk.DescribeVirtualRouterInput{
+			MeshName:          ms.Spec.AWSName,
+			MeshOwner:         ms.Spec.MeshOwner,
+			VirtualRouterName: vr.Spec.AWSName,
+		})
+		if err := ignoreNotFound(err); err != nil {
+			return nil, errors.Wrap(err, "failed to describe AppMesh virtualRouter")
+		}
+		if resp == nil || resp.VirtualRouter == nil {
+			return state, nil
+		}
+		state.sdk = &sdkResourceState{
+			status:   aws.StringValue(resp.VirtualRouter.Status.Status),
+			metadata: resp.VirtualRouter.Metadata,
+			spec:     resp.VirtualRouter.Spec,
+		}
+		if state.routes, err = c.describeRoutes(ctx, ms, vr); err != nil {
+			return nil, err
+		}
+		return state, nil
+	case kindVirtualService:
+		vs := &appmesh.VirtualService{}
+		if err := c.k8sClient.Get(ctx, key, vs); err != nil {
+			return nil, err
+		}
+		ms, err := c.resolveMesh(ctx, vs.Spec.MeshRef)
+		if err != nil {
+			return nil, err
+		}
+		state := newResourceState(kind, key, ms, vs.Spec.AWSName, vs.Status.VirtualServiceARN, vs.Status.Conditions)
+		resp, err := c.appMeshSDK.DescribeVirtualServiceWithContext(ctx, &appmeshsdk.DescribeVirtualServiceInput{
+			MeshName:           ms.Spec.AWSName,
+			MeshOwner:          ms.Spec.MeshOwner,
+			VirtualServiceName: vs.Spec.AWSName,
+		})
+		if err := ignoreNotFound(err); err != nil {
+			return nil, errors.Wrap(err, "failed to describe AppMesh virtualService")
+		}
+		if resp != nil && resp.VirtualService != nil {
+			state.sdk = &sdkResourceState{
+				status:   aws.StringValue(resp.VirtualService.Status.Status),
+				metadata: resp.VirtualService.Metadata,
+				spec:     resp.VirtualService.Spec,
+			}
+		}
+		return state, nil
+	default:
+		return nil, errors.Errorf("unsupported kind %s", kind)
+	}
+}
+
+func (c *clients) resolveMesh(ctx context.Context, meshRef *appmesh.MeshReference) (*appmesh.Mesh, error) {
+	if meshRef == nil {
+		return nil, errors.New("meshRef isn't populated, please check webhook setup")
+	}
+	ms, err := c.referencesResolver.ResolveMeshReference(ctx, *meshRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve meshRef")
+	}
+	return ms, nil
+}
+
+func (c *clients) describeRoutes(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) ([]sdkRouteState, error) {
+	var sdkRouteRefs []*appmeshsdk.RouteRef
+	if err := c.appMeshSDK.ListRoutesPagesWithContext(ctx, &appmeshsdk.ListRoutesInput{
+		MeshName:          ms.Spec.AWSName,
+		MeshOwner:         ms.Spec.MeshOwner,
+		VirtualRouterName: vr.Spec.AWSName,
+	}, func(output *appmeshsdk.ListRoutesOutput, _ bool) bool {
+		sdkRouteRefs = append(sdkRouteRefs, output.Routes...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to list AppMesh routes")
+	}
+	routes := make([]sdkRouteState, 0, len(sdkRouteRefs))
+	for _, sdkRouteRef := range sdkRouteRefs {
+		resp, err := c.appMeshSDK.DescribeRouteWithContext(ctx, &appmeshsdk.DescribeRouteInput{
+			MeshName:          sdkRouteRef.MeshName,
+			MeshOwner:         sdkRouteRef.MeshOwner,
+			VirtualRouterName: sdkRouteRef.VirtualRouterName,
+			RouteName:         sdkRouteRef.RouteName,
+		})
+		if err := ignoreNotFound(err); err != nil {
+			return nil, errors.Wrapf(err, "failed to describe AppMesh route %s", aws.StringValue(sdkRouteRef.RouteName))
+		}
+		if resp == nil || resp.Route == nil {
+			continue
+		}
+		routes = append(routes, sdkRouteState{
+			name:     aws.StringValue(resp.Route.RouteName),
+			status:   aws.StringValue(resp.Route.Status.Status),
+			metadata: resp.Route.Metadata,
+		})
+	}
+	return routes, nil
+}
+
+func newResourceState(kind string, key types.NamespacedName, ms *appmesh.Mesh, awsName *string, arn *string, conditions []metav1.Condition) *resourceState {
+	return &resourceState{
+		kind:       kind,
+		key:        key,
+		meshName:   aws.StringValue(ms.Spec.AWSName),
+		awsName:    aws.StringValue(awsName),
+		arn:        aws.StringValue(arn),
+		conditions: conditions,
+	}
+}
+
+// write writes state in the layout of kubectl describe.
+func (s *resourceState) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", s.key.Name)
+	fmt.Fprintf(tw, "Namespace:\t%s\n", s.key.Namespace)
+	fmt.Fprintf(tw, "Kind:\t%s\n", s.kind)
+	fmt.Fprintf(tw, "Mesh:\t%s\n", s.meshName)
+	fmt.Fprintf(tw, "AWS Name:\t%s\n", s.awsName)
+	fmt.Fprintf(tw, "ARN:\t%s\n", valueOrNone(s.arn))
+	fmt.Fprintf(tw, "Conditions:\n")
+	if len(s.conditions) == 0 {
+		fmt.Fprintf(tw, "  <none>\n")
+	} else {
+		fmt.Fprintf(tw, "  Type\tStatus\tReason\tMessage\n")
+		for _, condition := range s.conditions {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, valueOrNone(condition.Reason), valueOrNone(condition.Message))
+		}
+	}
+	fmt.Fprintf(tw, "AppMesh:\n")
+	if s.sdk == nil {
+		fmt.Fprintf(tw, "  <not found>\n")
+		return tw.Flush()
+	}
+	fmt.Fprintf(tw, "  Status:\t%s\n", s.sdk.status)
+	fmt.Fprintf(tw, "  Version:\t%d\n", aws.Int64Value(s.sdk.metadata.Version))
+	fmt.Fprintf(tw, "  Created:\t%s\n", formatTime(s.sdk.metadata.CreatedAt))
+	fmt.Fprintf(tw, "  Last Updated:\t%s\n", formatTime(s.sdk.metadata.LastUpdatedAt))
+	if s.kind == kindVirtualRouter {
+		fmt.Fprintf(tw, "  Routes:\n")
+		if len(s.routes) == 0 {
+			fmt.Fprintf(tw, "    <none>\n")
+		} else {
+			fmt.Fprintf(tw, "    Name\tStatus\tVersion\tLast Updated\n")
+			for _, route := range s.routes {
+				fmt.Fprintf(tw, "    %s\t%s\t%d\t%s\n", route.name, route.status, aws.Int64Value(route.metadata.Version), formatTime(route.metadata.LastUpdatedAt))
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	spec, err := json.MarshalIndent(s.sdk.spec, "    ", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "  Spec:\n    %s\n", spec)
+	return err
+}
+
+func ignoreNotFound(err error) error {
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFoundException" {
+		return nil
+	}
+	return err
+}
+
+func valueOrNone(value string) string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return "<none>"
+	}
+	return value
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "<unknown>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,94 @@
+package kubectlplugin
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_resourceState_write(t *testing.T) {
+	updatedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		state *resourceState
+		want  string
+	}{
+		{
+			name: "virtualRouter with routes",
+			state: &resourceState{
+				kind:     kindVirtualRouter,
+				key:      types.NamespacedName{Namespace: "app-ns", Name: "router"},
+				meshName: "my-mesh",
+				awsName:  "router_app-ns",
+				arn:      "arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualRouter/router_app-ns",
+				conditions: []metav1.Condition{
+					{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue},
+				},
+				sdk: &sdkResourceState{
+					status:   appmeshsdk.VirtualRouterStatusCodeActive,
+					metadata: &appmeshsdk.ResourceMetadata{Version: aws.Int64(2), CreatedAt: &updatedAt, LastUpdatedAt: &updatedAt},
+					spec:     &appmeshsdk.VirtualRouterSpec{},
+				},
+				routes: []sdkRouteState{
+					{name: "route", status: appmeshsdk.RouteStatusCodeActive, metadata: &appmeshsdk.ResourceMetadata{Version: aws.Int64(3), LastUpdatedAt: &updatedAt}},
+				},
+			},
+			want: `Name:       router
+Namespace:  app-ns
+Kind:       VirtualRouter
+Mesh:       my-mesh
+AWS Name:   router_app-ns
+ARN:        arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualRouter/router_app-ns
+Conditions:
+  Type    Status  Reason  Message
+  Synced  True    <none>  <none>
+AppMesh:
+  Status:        ACTIVE
+  Version:       2
+  Created:       2020-01-02T03:04:05Z
+  Last Updated:  2020-01-02T03:04:05Z
+  Routes:
+    Name   Status  Version  Last Updated
+    route  ACTIVE  3        2020-01-02T03:04:05Z
+  Spec:
+    {
+      "Listeners": null
+    }
+`,
+		},
+		{
+			name: "virtualNode not found in AppMesh",
+			state: &resourceState{
+				kind:     kindVirtualNode,
+				key:      types.NamespacedName{Namespace: "app-ns", Name: "node"},
+				meshName: "my-mesh",
+				awsName:  "node_app-ns",
+			},
+			want: `Name:       node
+Namespace:  app-ns
+Kind:       VirtualNode
+Mesh:       my-mesh
+AWS Name:   node_app-ns
+ARN:        <none>
+Conditions:
+  <none>
+AppMesh:
+  <not found>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.NoError(t, tt.state.write(buf))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package kubectlplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func diffCommand() command {
+	return command{
+		name:     "diff",
+		synopsis: "diff <virtualnode|virtualrouter|virtualservice> <name>",
+		short:    "Show differences between the desired and actual AppMesh specs of a resource, exits with 1 if there are any.",
+		run:      runDiff,
+		bindFlags: func(fs *pflag.FlagSet, o *options) {
+			fs.BoolVar(&o.enableBackendGroups, "enable-backend-groups", false, "Whether the controller has backend groups enabled, which affects the desired specs of virtualNodes.")
+			fs.BoolVar(&o.enableMeshPolicies, "enable-mesh-policies", false, "Whether the controller has the MeshPolicies feature enabled, which affects the desired specs of virtualNodes and routes.")
+		},
+	}
+}
+
+func runDiff(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintf(stderr, "diff requires a kind and a name\n")
+		return exitCodeFailure
+	}
+	kind, err := parseKind(args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	c, err := newClients(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	specDiffs, err := diffResource(ctx, c, kind, types.NamespacedName{Namespace: c.namespace, Name: args[1]}, o.enableBackendGroups, o.enableMeshPolicies)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	if writeSpecDiffs(stdout, specDiffs) {
+		return exitCodeDifferences
+	}
+	return exitCodeOK
+}
+
+// diffResource diffs the AppMesh resources of the k8s resource of kind with key, using the resource managers of the controller.
+// resource managers are constructed with tagging, quota checks and maintenance windows disabled, which Diff doesn't depend on.
+func diffResource(ctx context.Context, c *clients, kind string, key types.NamespacedName, enableBackendGroups bool, enableMeshPolicies bool) ([]equality.SpecDiff, error) {
+	tagsManager := tagging.NewDefaultManager(tagging.Config{}, c.appMeshSDK, "", logr.Discard())
+	quotaChecker := quota.NewNoopChecker()
+	maintenanceScheduler := maintenance.NewNoopScheduler()
+	switch kind {
+	case kindVirtualNode:
+		vn := &appmesh.VirtualNode{}
+		if err := c.k8sClient.Get(ctx, key, vn); err != nil {
+			return nil, err
+		}
+		resManager := virtualnode.NewDefaultResourceManager(c.k8sClient, c.appMeshSDK, c.referencesResolver, "", tagsManager, quotaChecker, maintenanceScheduler, logr.Discard(), enableBackendGroups, enableMeshPolicies)
+		return resManager.Diff(ctx, vn)
+	case kindVirtualRouter:
+		vr := &appmesh.VirtualRouter{}
+		if err := c.k8sClient.Get(ctx, key, vr); err != nil {
+			return nil, err
+		}
+		resManager := virtualrouter.NewDefaultResourceManager(c.k8sClient, c.appMeshSDK, c.referencesResolver, "", tagsManager, quotaChecker, maintenanceScheduler, virtualrouter.Config{}, logr.Discard(), enableMeshPolicies)
+		return resManager.Diff(ctx, vr)
+	case kindVirtualService:
+		vs := &appmesh.VirtualService{}
+		if err := c.k8sClient.Get(ctx, key, vs); err != nil {
+			return nil, err
+		}
+		resManager := virtualservice.NewDefaultResourceManager(c.k8sClient, c.appMeshSDK, c.referencesResolver, "", tagsManager, quotaChecker, logr.Discard())
+		return resManager.Diff(ctx, vs)
+	default:
+		return nil, errors.Errorf("unsupported kind %s", kind)
+	}
+}
+
+// writeSpecDiffs writes specDiffs to w, returns whether any of them has differences.
+func writeSpecDiffs(w io.Writer, specDiffs []equality.SpecDiff) bool {
+	hasDifferences := false
+	for _, specDiff := range specDiffs {
+		switch {
+		case len(specDiff.Diff) == 0:
+			fmt.Fprintf(w, "%s: in sync\n", specDiff.Resource)
+			continue
+		case !specDiff.Exists:
+			fmt.Fprintf(w, "%s: not found in AppMesh (-desired +actual):\n", specDiff.Resource)
+		default:
+			fmt.Fprintf(w, "%s: out of sync (-desired +actual):\n", specDiff.Resource)
+		}
+		fmt.Fprintf(w, "%s\n", specDiff.Diff)
+		hasDifferences = true
+	}
+	return hasDifferences
+}
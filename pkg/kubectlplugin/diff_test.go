@@ -0,0 +1,60 @@
+package kubectlplugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_writeSpecDiffs(t *testing.T) {
+	tests := []struct {
+		name               string
+		specDiffs          []equality.SpecDiff
+		wantOutput         string
+		wantHasDifferences bool
+	}{
+		{
+			name: "in sync",
+			specDiffs: []equality.SpecDiff{
+				{Resource: "virtualRouter/router", Exists: true},
+				{Resource: "route/route", Exists: true},
+			},
+			wantOutput:         "virtualRouter/router: in sync\nroute/route: in sync\n",
+			wantHasDifferences: false,
+		},
+		{
+			name: "out of sync and not found",
+			specDiffs: []equality.SpecDiff{
+				{Resource: "virtualRouter/router", Exists: true, Diff: "-a\n+b"},
+				{Resource: "route/route", Exists: false, Diff: "-a"},
+			},
+			wantOutput:         "virtualRouter/router: out of sync (-desired +actual):\n-a\n+b\nroute/route: not found in AppMesh (-desired +actual):\n-a\n",
+			wantHasDifferences: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			gotHasDifferences := writeSpecDiffs(buf, tt.specDiffs)
+			assert.Equal(t, tt.wantHasDifferences, gotHasDifferences)
+			assert.Equal(t, tt.wantOutput, buf.String())
+		})
+	}
+}
+
+func Test_parseKind(t *testing.T) {
+	for arg, want := range map[string]string{
+		"vn":             kindVirtualNode,
+		"VirtualNode":    kindVirtualNode,
+		"virtualrouters": kindVirtualRouter,
+		"vs":             kindVirtualService,
+	} {
+		got, err := parseKind(arg)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, arg)
+	}
+	_, err := parseKind("mesh")
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package kubectlplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/topology"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	graphOutputDOT  = "dot"
+	graphOutputJSON = "json"
+)
+
+func graphCommand() command {
+	return command{
+		name:     "graph",
+		synopsis: "graph",
+		short:    "Show the mesh topology as DOT or JSON.",
+		run:      runGraph,
+		bindFlags: func(fs *pflag.FlagSet, o *options) {
+			fs.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Graph resources across all namespaces.")
+			fs.StringVar(&o.mesh, "mesh", "", "Only graph resources of the mesh with this name.")
+			fs.StringVarP(&o.output, "output", "o", graphOutputDOT, "Output format, one of dot or json.")
+		},
+	}
+}
+
+func runGraph(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintf(stderr, "graph accepts no arguments\n")
+		return exitCodeFailure
+	}
+	if o.output != graphOutputDOT && o.output != graphOutputJSON {
+		fmt.Fprintf(stderr, "unsupported output %q, must be one of dot or json\n", o.output)
+		return exitCodeFailure
+	}
+	k8sClient, namespace, err := newK8sClient(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	var listOpts []client.ListOption
+	if !o.allNamespaces {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+	}
+	objs, err := topology.ListObjects(ctx, k8sClient, listOpts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	g := topology.Build(objs, o.mesh)
+	if o.output == graphOutputJSON {
+		err = g.WriteJSON(stdout)
+	} else {
+		err = g.WriteDOT(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	return exitCodeOK
+}
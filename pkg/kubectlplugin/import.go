@@ -0,0 +1,515 @@
+package kubectlplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// importMeshLabel is the label of namespaces selected by the imported mesh and its virtualGateways.
+	importMeshLabel = "mesh"
+	// importGatewayLabel is the label of gatewayRoutes selected by their imported virtualGateway.
+	importGatewayLabel = "gateway"
+)
+
+func importCommand() command {
+	return command{
+		name:     "import",
+		synopsis: "import <mesh-name>",
+		short:    "Generate manifests of the CRDs of an existing AppMesh mesh, and optionally apply them so the controller adopts its resources.",
+		run:      runImport,
+		bindFlags: func(fs *pflag.FlagSet, o *options) {
+			fs.BoolVar(&o.apply, "apply", false, "Apply the generated CRDs into the namespace, and label the namespace to be selected by the mesh, instead of printing them.")
+		},
+	}
+}
+
+func runImport(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintf(stderr, "import requires a mesh name\n")
+		return exitCodeFailure
+	}
+	c, err := newClients(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	objs, err := importMesh(ctx, c.appMeshSDK, args[0], c.namespace)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	if o.apply {
+		err = applyImportedObjects(ctx, c.k8sClient, c.namespace, objs, stdout)
+	} else {
+		err = writeManifests(stdout, objs)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	return exitCodeOK
+}
+
+// importMesh generates the CRDs of AppMesh mesh meshName and all its resources into namespace,
+// ordered by kind so applying them in order creates referenced CRDs first where possible.
+// every CRD has awsName of its AppMesh resource, so the controller adopts the resource instead of creating a new one.
+func importMesh(ctx context.Context, appMeshSDK services.AppMesh, meshName string, namespace string) ([]client.Object, error) {
+	importer := &meshImporter{
+		appMeshSDK: appMeshSDK,
+		meshName:   meshName,
+		namespace:  namespace,
+		names:      make(map[string]map[string]string),
+	}
+	return importer.importMesh(ctx)
+}
+
+// meshImporter generates CRDs of an AppMesh mesh.
+type meshImporter struct {
+	appMeshSDK services.AppMesh
+	meshName   string
+	namespace  string
+	// names are names of CRDs keyed by kind and AppMesh name, so references between AppMesh resources are converted into references between CRDs.
+	names map[string]map[string]string
+}
+
+func (i *meshImporter) importMesh(ctx context.Context) ([]client.Object, error) {
+	resp, err := i.appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String(i.meshName)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe mesh %s", i.meshName)
+	}
+	ms, err := i.importSDKMesh(resp.Mesh)
+	if err != nil {
+		return nil, err
+	}
+
+	var vgNames, vnNames, vrNames, vsNames []string
+	if err := i.appMeshSDK.ListVirtualGatewaysPagesWithContext(ctx, &appmeshsdk.ListVirtualGatewaysInput{MeshName: aws.String(i.meshName)},
+		func(output *appmeshsdk.ListVirtualGatewaysOutput, lastPage bool) bool {
+			for _, ref := range output.VirtualGateways {
+				vgNames = append(vgNames, aws.StringValue(ref.VirtualGatewayName))
+			}
+			return true
+		}); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualGateways")
+	}
+	if err := i.appMeshSDK.ListVirtualNodesPagesWithContext(ctx, &appmeshsdk.ListVirtualNodesInput{MeshName: aws.String(i.meshName)},
+		func(output *appmeshsdk.ListVirtualNodesOutput, lastPage bool) bool {
+			for _, ref := range output.VirtualNodes {
+				vnNames = append(vnNames, aws.StringValue(ref.VirtualNodeName))
+			}
+			return true
+		}); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualNodes")
+	}
+	if err := i.appMeshSDK.ListVirtualRoutersPagesWithContext(ctx, &appmeshsdk.ListVirtualRoutersInput{MeshName: aws.String(i.meshName)},
+		func(output *appmeshsdk.ListVirtualRoutersOutput, lastPage bool) bool {
+			for _, ref := range output.VirtualRouters {
+				vrNames = append(vrNames, aws.StringValue(ref.VirtualRouterName))
+			}
+			return true
+		}); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualRouters")
+	}
+	if err := i.appMeshSDK.ListVirtualServicesPagesWithContext(ctx, &appmeshsdk.ListVirtualServicesInput{MeshName: aws.String(i.meshName)},
+		func(output *appmeshsdk.ListVirtualServicesOutput, lastPage bool) bool {
+			for _, ref := range output.VirtualServices {
+				vsNames = append(vsNames, aws.StringValue(ref.VirtualServiceName))
+			}
+			return true
+		}); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualServices")
+	}
+	for kind, awsNames := range map[string][]string{kindVirtualGateway: vgNames, kindVirtualNode: vnNames, kindVirtualRouter: vrNames, kindVirtualService: vsNames} {
+		if err := i.assignNames(kind, awsNames); err != nil {
+			return nil, err
+		}
+	}
+
+	objs := []client.Object{ms}
+	var grs []client.Object
+	for _, vgName := range vgNames {
+		vg, vgGRs, err := i.importVirtualGateway(ctx, vgName)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, vg)
+		grs = append(grs, vgGRs...)
+	}
+	for _, vnName := range vnNames {
+		vn, err := i.importVirtualNode(ctx, vnName)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, vn)
+	}
+	for _, vrName := range vrNames {
+		vr, err := i.importVirtualRouter(ctx, vrName)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, vr)
+	}
+	for _, vsName := range vsNames {
+		vs, err := i.importVirtualService(ctx, vsName)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, vs)
+	}
+	return append(objs, grs...), nil
+}
+
+func (i *meshImporter) importSDKMesh(sdkMS *appmeshsdk.MeshData) (*appmesh.Mesh, error) {
+	name := i.crdName(i.meshName)
+	ms := &appmesh.Mesh{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindMesh},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appmesh.MeshSpec{
+			AWSName:           aws.String(i.meshName),
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{importMeshLabel: name}},
+		},
+	}
+	if sdkMS.Spec != nil && sdkMS.Spec.EgressFilter != nil {
+		ms.Spec.EgressFilter = &appmesh.EgressFilter{Type: appmesh.EgressFilterType(aws.StringValue(sdkMS.Spec.EgressFilter.Type))}
+	}
+	if sdkMS.Spec != nil && sdkMS.Spec.ServiceDiscovery != nil {
+		ms.Spec.ServiceDiscovery = &appmesh.MeshServiceDiscovery{IpPreference: sdkMS.Spec.ServiceDiscovery.IpPreference}
+	}
+	return ms, nil
+}
+
+func (i *meshImporter) importVirtualGateway(ctx context.Context, vgName string) (*appmesh.VirtualGateway, []client.Object, error) {
+	resp, err := i.appMeshSDK.DescribeVirtualGatewayWithContext(ctx, &appmeshsdk.DescribeVirtualGatewayInput{
+		MeshName:           aws.String(i.meshName),
+		VirtualGatewayName: aws.String(vgName),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to describe virtualGateway %s", vgName)
+	}
+	name := i.names[kindVirtualGateway][vgName]
+	vg := &appmesh.VirtualGateway{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindVirtualGateway},
+		ObjectMeta: metav1.ObjectMeta{Namespace: i.namespace, Name: name},
+	}
+	if err := convertSDKSpec(resp.VirtualGateway.Spec, &vg.Spec); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to convert spec of virtualGateway %s", vgName)
+	}
+	vg.Spec.AWSName = aws.String(vgName)
+	vg.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{importMeshLabel: i.crdName(i.meshName)}}
+	vg.Spec.GatewayRouteSelector = &metav1.LabelSelector{MatchLabels: map[string]string{importGatewayLabel: name}}
+
+	var grNames []string
+	if err := i.appMeshSDK.ListGatewayRoutesPagesWithContext(ctx, &appmeshsdk.ListGatewayRoutesInput{
+		MeshName:           aws.String(i.meshName),
+		VirtualGatewayName: aws.String(vgName),
+	}, func(output *appmeshsdk.ListGatewayRoutesOutput, lastPage bool) bool {
+		for _, ref := range output.GatewayRoutes {
+			grNames = append(grNames, aws.StringValue(ref.GatewayRouteName))
+		}
+		return true
+	}); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to list gatewayRoutes of virtualGateway %s", vgName)
+	}
+	// gatewayRoute names are only unique within their virtualGateway.
+	grKind := kindGatewayRoute + "/" + vgName
+	if err := i.assignNames(grKind, grNames); err != nil {
+		return nil, nil, err
+	}
+	var grs []client.Object
+	for _, grName := range grNames {
+		gr, err := i.importGatewayRoute(ctx, vgName, name, grName, i.names[grKind][grName])
+		if err != nil {
+			return nil, nil, err
+		}
+		grs = append(grs, gr)
+	}
+	return vg, grs, nil
+}
+
+func (i *meshImporter) importGatewayRoute(ctx context.Context, vgName string, vgCRDName string, grName string, name string) (*appmesh.GatewayRoute, error) {
+	resp, err := i.appMeshSDK.DescribeGatewayRouteWithContext(ctx, &appmeshsdk.DescribeGatewayRouteInput{
+		MeshName:           aws.String(i.meshName),
+		VirtualGatewayName: aws.String(vgName),
+		GatewayRouteName:   aws.String(grName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe gatewayRoute %s of virtualGateway %s", grName, vgName)
+	}
+	gr := &appmesh.GatewayRoute{
+		TypeMeta: metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindGatewayRoute},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: i.namespace,
+			Name:      name,
+			Labels:    map[string]string{importGatewayLabel: vgCRDName},
+		},
+	}
+	sdkSpec := resp.GatewayRoute.Spec
+	if err := convertSDKSpec(sdkSpec, &gr.Spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert spec of gatewayRoute %s of virtualGateway %s", grName, vgName)
+	}
+	gr.Spec.AWSName = aws.String(grName)
+	if sdkSpec.GrpcRoute != nil && sdkSpec.GrpcRoute.Action != nil && gr.Spec.GRPCRoute != nil {
+		gr.Spec.GRPCRoute.Action.Target.VirtualService = i.gatewayRouteVirtualService(sdkSpec.GrpcRoute.Action.Target)
+	}
+	if sdkSpec.HttpRoute != nil && sdkSpec.HttpRoute.Action != nil && gr.Spec.HTTPRoute != nil {
+		gr.Spec.HTTPRoute.Action.Target.VirtualService = i.gatewayRouteVirtualService(sdkSpec.HttpRoute.Action.Target)
+	}
+	if sdkSpec.Http2Route != nil && sdkSpec.Http2Route.Action != nil && gr.Spec.HTTP2Route != nil {
+		gr.Spec.HTTP2Route.Action.Target.VirtualService = i.gatewayRouteVirtualService(sdkSpec.Http2Route.Action.Target)
+	}
+	return gr, nil
+}
+
+func (i *meshImporter) gatewayRouteVirtualService(sdkTarget *appmeshsdk.GatewayRouteTarget) appmesh.GatewayRouteVirtualService {
+	if sdkTarget == nil || sdkTarget.VirtualService == nil {
+		return appmesh.GatewayRouteVirtualService{}
+	}
+	return appmesh.GatewayRouteVirtualService{
+		VirtualServiceRef: &appmesh.VirtualServiceReference{Name: i.names[kindVirtualService][aws.StringValue(sdkTarget.VirtualService.VirtualServiceName)]},
+	}
+}
+
+func (i *meshImporter) importVirtualNode(ctx context.Context, vnName string) (*appmesh.VirtualNode, error) {
+	resp, err := i.appMeshSDK.DescribeVirtualNodeWithContext(ctx, &appmeshsdk.DescribeVirtualNodeInput{
+		MeshName:        aws.String(i.meshName),
+		VirtualNodeName: aws.String(vnName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe virtualNode %s", vnName)
+	}
+	vn := &appmesh.VirtualNode{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindVirtualNode},
+		ObjectMeta: metav1.ObjectMeta{Namespace: i.namespace, Name: i.names[kindVirtualNode][vnName]},
+	}
+	sdkSpec := resp.VirtualNode.Spec
+	if err := convertSDKSpec(sdkSpec, &vn.Spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert spec of virtualNode %s", vnName)
+	}
+	vn.Spec.AWSName = aws.String(vnName)
+	for idx, sdkBackend := range sdkSpec.Backends {
+		if sdkBackend.VirtualService == nil || idx >= len(vn.Spec.Backends) {
+			continue
+		}
+		vsName := aws.StringValue(sdkBackend.VirtualService.VirtualServiceName)
+		vn.Spec.Backends[idx].VirtualService.VirtualServiceRef = &appmesh.VirtualServiceReference{Name: i.names[kindVirtualService][vsName]}
+	}
+	return vn, nil
+}
+
+func (i *meshImporter) importVirtualRouter(ctx context.Context, vrName string) (*appmesh.VirtualRouter, error) {
+	resp, err := i.appMeshSDK.DescribeVirtualRouterWithContext(ctx, &appmeshsdk.DescribeVirtualRouterInput{
+		MeshName:          aws.String(i.meshName),
+		VirtualRouterName: aws.String(vrName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe virtualRouter %s", vrName)
+	}
+	vr := &appmesh.VirtualRouter{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindVirtualRouter},
+		ObjectMeta: metav1.ObjectMeta{Namespace: i.namespace, Name: i.names[kindVirtualRouter][vrName]},
+	}
+	if err := convertSDKSpec(resp.VirtualRouter.Spec, &vr.Spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert spec of virtualRouter %s", vrName)
+	}
+	vr.Spec.AWSName = aws.String(vrName)
+
+	var routeNames []string
+	if err := i.appMeshSDK.ListRoutesPagesWithContext(ctx, &appmeshsdk.ListRoutesInput{
+		MeshName:          aws.String(i.meshName),
+		VirtualRouterName: aws.String(vrName),
+	}, func(output *appmeshsdk.ListRoutesOutput, lastPage bool) bool {
+		for _, ref := range output.Routes {
+			routeNames = append(routeNames, aws.StringValue(ref.RouteName))
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list routes of virtualRouter %s", vrName)
+	}
+	for _, routeName := range routeNames {
+		route, err := i.importRoute(ctx, vrName, routeName)
+		if err != nil {
+			return nil, err
+		}
+		vr.Spec.Routes = append(vr.Spec.Routes, route)
+	}
+	return vr, nil
+}
+
+func (i *meshImporter) importRoute(ctx context.Context, vrName string, routeName string) (appmesh.Route, error) {
+	resp, err := i.appMeshSDK.DescribeRouteWithContext(ctx, &appmeshsdk.DescribeRouteInput{
+		MeshName:          aws.String(i.meshName),
+		VirtualRouterName: aws.String(vrName),
+		RouteName:         aws.String(routeName),
+	})
+	if err != nil {
+		return appmesh.Route{}, errors.Wrapf(err, "failed to describe route %s of virtualRouter %s", routeName, vrName)
+	}
+	route := appmesh.Route{}
+	sdkSpec := resp.Route.Spec
+	if err := convertSDKSpec(sdkSpec, &route); err != nil {
+		return appmesh.Route{}, errors.Wrapf(err, "failed to convert spec of route %s of virtualRouter %s", routeName, vrName)
+	}
+	route.Name = routeName
+	if sdkSpec.GrpcRoute != nil && sdkSpec.GrpcRoute.Action != nil && route.GRPCRoute != nil {
+		i.setWeightedTargetRefs(sdkSpec.GrpcRoute.Action.WeightedTargets, route.GRPCRoute.Action.WeightedTargets)
+	}
+	if sdkSpec.HttpRoute != nil && sdkSpec.HttpRoute.Action != nil && route.HTTPRoute != nil {
+		i.setWeightedTargetRefs(sdkSpec.HttpRoute.Action.WeightedTargets, route.HTTPRoute.Action.WeightedTargets)
+	}
+	if sdkSpec.Http2Route != nil && sdkSpec.Http2Route.Action != nil && route.HTTP2Route != nil {
+		i.setWeightedTargetRefs(sdkSpec.Http2Route.Action.WeightedTargets, route.HTTP2Route.Action.WeightedTargets)
+	}
+	if sdkSpec.TcpRoute != nil && sdkSpec.TcpRoute.Action != nil && route.TCPRoute != nil {
+		i.setWeightedTargetRefs(sdkSpec.TcpRoute.Action.WeightedTargets, route.TCPRoute.Action.WeightedTargets)
+	}
+	return route, nil
+}
+
+// setWeightedTargetRefs sets virtualNodeRef of targets to the CRDs of virtualNodes of sdkTargets, which are converted into targets in order.
+func (i *meshImporter) setWeightedTargetRefs(sdkTargets []*appmeshsdk.WeightedTarget, targets []appmesh.WeightedTarget) {
+	for idx, sdkTarget := range sdkTargets {
+		if idx >= len(targets) {
+			return
+		}
+		targets[idx].VirtualNodeRef = &appmesh.VirtualNodeReference{Name: i.names[kindVirtualNode][aws.StringValue(sdkTarget.VirtualNode)]}
+	}
+}
+
+func (i *meshImporter) importVirtualService(ctx context.Context, vsName string) (*appmesh.VirtualService, error) {
+	resp, err := i.appMeshSDK.DescribeVirtualServiceWithContext(ctx, &appmeshsdk.DescribeVirtualServiceInput{
+		MeshName:           aws.String(i.meshName),
+		VirtualServiceName: aws.String(vsName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe virtualService %s", vsName)
+	}
+	vs := &appmesh.VirtualService{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindVirtualService},
+		ObjectMeta: metav1.ObjectMeta{Namespace: i.namespace, Name: i.names[kindVirtualService][vsName]},
+		Spec:       appmesh.VirtualServiceSpec{AWSName: aws.String(vsName)},
+	}
+	if sdkSpec := resp.VirtualService.Spec; sdkSpec != nil && sdkSpec.Provider != nil {
+		switch {
+		case sdkSpec.Provider.VirtualNode != nil:
+			vnName := aws.StringValue(sdkSpec.Provider.VirtualNode.VirtualNodeName)
+			vs.Spec.Provider = &appmesh.VirtualServiceProvider{VirtualNode: &appmesh.VirtualNodeServiceProvider{
+				VirtualNodeRef: &appmesh.VirtualNodeReference{Name: i.names[kindVirtualNode][vnName]},
+			}}
+		case sdkSpec.Provider.VirtualRouter != nil:
+			vrName := aws.StringValue(sdkSpec.Provider.VirtualRouter.VirtualRouterName)
+			vs.Spec.Provider = &appmesh.VirtualServiceProvider{VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+				VirtualRouterRef: &appmesh.VirtualRouterReference{Name: i.names[kindVirtualRouter][vrName]},
+			}}
+		}
+	}
+	return vs, nil
+}
+
+// assignNames assigns CRD names to AppMesh resources of kind with awsNames, failing if two of them would share a name.
+func (i *meshImporter) assignNames(kind string, awsNames []string) error {
+	names := make(map[string]string, len(awsNames))
+	awsNameByName := make(map[string]string, len(awsNames))
+	for _, awsName := range awsNames {
+		name := i.crdName(awsName)
+		if otherAWSName, ok := awsNameByName[name]; ok {
+			return errors.Errorf("%s %s and %s would both be imported as %s, rename one of them", strings.Split(kind, "/")[0], otherAWSName, awsName, name)
+		}
+		names[awsName] = name
+		awsNameByName[name] = awsName
+	}
+	i.names[kind] = names
+	return nil
+}
+
+var invalidNameCharsPattern = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// crdName returns the CRD name of AppMesh resource with awsName.
+// the "_<namespace>" suffix the controller appends to AppMesh names is dropped, other characters invalid in object names are replaced with "-".
+func (i *meshImporter) crdName(awsName string) string {
+	name := strings.TrimSuffix(awsName, "_"+i.namespace)
+	name = invalidNameCharsPattern.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-.")
+}
+
+// convertSDKSpec converts AppMesh spec into CRD spec via JSON, which relies on CRD and SDK types sharing JSON field names.
+// references to other AppMesh resources, which CRDs represent differently, are left unset.
+func convertSDKSpec(sdkSpec interface{}, crdSpec interface{}) error {
+	payload, err := json.Marshal(sdkSpec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, crdSpec)
+}
+
+// writeManifests writes objs to w as a YAML stream, without status and other fields set by the API server.
+func writeManifests(w io.Writer, objs []client.Object) error {
+	for _, obj := range objs {
+		manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		delete(manifest, "status")
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		payload, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "---\n%s", payload)
+	}
+	return nil
+}
+
+// applyImportedObjects labels namespace to be selected by the imported mesh and creates objs in it, objects that already exist are left unchanged.
+func applyImportedObjects(ctx context.Context, k8sClient client.Client, namespace string, objs []client.Object, w io.Writer) error {
+	for _, obj := range objs {
+		ms, ok := obj.(*appmesh.Mesh)
+		if !ok {
+			continue
+		}
+		ns := &corev1.Namespace{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			return errors.Wrapf(err, "failed to get namespace %s", namespace)
+		}
+		oldNS := ns.DeepCopy()
+		if ns.Labels == nil {
+			ns.Labels = make(map[string]string)
+		}
+		ns.Labels[importMeshLabel] = ms.Name
+		if err := k8sClient.Patch(ctx, ns, client.MergeFrom(oldNS)); err != nil {
+			return errors.Wrapf(err, "failed to label namespace %s", namespace)
+		}
+		fmt.Fprintf(w, "namespace/%s labeled %s=%s\n", namespace, importMeshLabel, ms.Name)
+	}
+	for _, obj := range objs {
+		resource := fmt.Sprintf("%s/%s", strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), obj.GetName())
+		if err := k8sClient.Create(ctx, obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				fmt.Fprintf(w, "%s unchanged, already exists\n", resource)
+				continue
+			}
+			return errors.Wrapf(err, "failed to create %s", resource)
+		}
+		fmt.Fprintf(w, "%s created\n", resource)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package kubectlplugin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_importMesh(t *testing.T) {
+	ctx := context.Background()
+	appMeshSDK := services.NewFakeAppMesh("222222222", "us-west-2")
+	_, err := appMeshSDK.CreateMeshWithContext(ctx, &appmeshsdk.CreateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Spec: &appmeshsdk.MeshSpec{
+			EgressFilter: &appmeshsdk.EgressFilter{Type: aws.String("ALLOW_ALL")},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualNodeWithContext(ctx, &appmeshsdk.CreateVirtualNodeInput{
+		MeshName:        aws.String("my-mesh"),
+		VirtualNodeName: aws.String("Frontend_my-ns"),
+		Spec: &appmeshsdk.VirtualNodeSpec{
+			Backends: []*appmeshsdk.Backend{
+				{VirtualService: &appmeshsdk.VirtualServiceBackend{VirtualServiceName: aws.String("backend.my-ns.svc.cluster.local")}},
+			},
+			Listeners: []*appmeshsdk.Listener{
+				{PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualNodeWithContext(ctx, &appmeshsdk.CreateVirtualNodeInput{
+		MeshName:        aws.String("my-mesh"),
+		VirtualNodeName: aws.String("backend-v1"),
+		Spec:            &appmeshsdk.VirtualNodeSpec{},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualRouterWithContext(ctx, &appmeshsdk.CreateVirtualRouterInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("backend-router"),
+		Spec: &appmeshsdk.VirtualRouterSpec{
+			Listeners: []*appmeshsdk.VirtualRouterListener{
+				{PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(8080), Protocol: aws.String("http")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateRouteWithContext(ctx, &appmeshsdk.CreateRouteInput{
+		MeshName:          aws.String("my-mesh"),
+		VirtualRouterName: aws.String("backend-router"),
+		RouteName:         aws.String("default"),
+		Spec: &appmeshsdk.RouteSpec{
+			HttpRoute: &appmeshsdk.HttpRoute{
+				Match: &appmeshsdk.HttpRouteMatch{Prefix: aws.String("/")},
+				Action: &appmeshsdk.HttpRouteAction{
+					WeightedTargets: []*appmeshsdk.WeightedTarget{
+						{VirtualNode: aws.String("backend-v1"), Weight: aws.Int64(100)},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualServiceWithContext(ctx, &appmeshsdk.CreateVirtualServiceInput{
+		MeshName:           aws.String("my-mesh"),
+		VirtualServiceName: aws.String("backend.my-ns.svc.cluster.local"),
+		Spec: &appmeshsdk.VirtualServiceSpec{
+			Provider: &appmeshsdk.VirtualServiceProvider{
+				VirtualRouter: &appmeshsdk.VirtualRouterServiceProvider{VirtualRouterName: aws.String("backend-router")},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateVirtualGatewayWithContext(ctx, &appmeshsdk.CreateVirtualGatewayInput{
+		MeshName:           aws.String("my-mesh"),
+		VirtualGatewayName: aws.String("ingress"),
+		Spec: &appmeshsdk.VirtualGatewaySpec{
+			Listeners: []*appmeshsdk.VirtualGatewayListener{
+				{PortMapping: &appmeshsdk.VirtualGatewayPortMapping{Port: aws.Int64(80), Protocol: aws.String("http")}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = appMeshSDK.CreateGatewayRouteWithContext(ctx, &appmeshsdk.CreateGatewayRouteInput{
+		MeshName:           aws.String("my-mesh"),
+		VirtualGatewayName: aws.String("ingress"),
+		GatewayRouteName:   aws.String("backend"),
+		Spec: &appmeshsdk.GatewayRouteSpec{
+			HttpRoute: &appmeshsdk.HttpGatewayRoute{
+				Match: &appmeshsdk.HttpGatewayRouteMatch{Prefix: aws.String("/")},
+				Action: &appmeshsdk.HttpGatewayRouteAction{
+					Target: &appmeshsdk.GatewayRouteTarget{
+						VirtualService: &appmeshsdk.GatewayRouteVirtualService{VirtualServiceName: aws.String("backend.my-ns.svc.cluster.local")},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	objs, err := importMesh(ctx, appMeshSDK, "my-mesh", "my-ns")
+	require.NoError(t, err)
+	require.Len(t, objs, 7)
+
+	ms := objs[0].(*appmesh.Mesh)
+	assert.Equal(t, "my-mesh", ms.Name)
+	assert.Equal(t, appmesh.MeshSpec{
+		AWSName:           aws.String("my-mesh"),
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "my-mesh"}},
+		EgressFilter:      &appmesh.EgressFilter{Type: appmesh.EgressFilterTypeAllowAll},
+	}, ms.Spec)
+
+	vg := objs[1].(*appmesh.VirtualGateway)
+	assert.Equal(t, "ingress", vg.Name)
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: map[string]string{"gateway": "ingress"}}, vg.Spec.GatewayRouteSelector)
+	assert.Equal(t, appmesh.PortNumber(80), vg.Spec.Listeners[0].PortMapping.Port)
+
+	vn := objs[2].(*appmesh.VirtualNode)
+	assert.Equal(t, "frontend", vn.Name)
+	assert.Equal(t, aws.String("Frontend_my-ns"), vn.Spec.AWSName)
+	assert.Equal(t, &appmesh.VirtualServiceReference{Name: "backend.my-ns.svc.cluster.local"}, vn.Spec.Backends[0].VirtualService.VirtualServiceRef)
+	assert.Equal(t, appmesh.PortProtocolHTTP, vn.Spec.Listeners[0].PortMapping.Protocol)
+	assert.Equal(t, "backend-v1", objs[3].(*appmesh.VirtualNode).Name)
+
+	vr := objs[4].(*appmesh.VirtualRouter)
+	require.Len(t, vr.Spec.Routes, 1)
+	assert.Equal(t, "default", vr.Spec.Routes[0].Name)
+	assert.Equal(t, []appmesh.WeightedTarget{
+		{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "backend-v1"}, Weight: 100},
+	}, vr.Spec.Routes[0].HTTPRoute.Action.WeightedTargets)
+
+	vs := objs[5].(*appmesh.VirtualService)
+	assert.Equal(t, &appmesh.VirtualServiceProvider{VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+		VirtualRouterRef: &appmesh.VirtualRouterReference{Name: "backend-router"},
+	}}, vs.Spec.Provider)
+
+	gr := objs[6].(*appmesh.GatewayRoute)
+	assert.Equal(t, map[string]string{"gateway": "ingress"}, gr.Labels)
+	assert.Equal(t, &appmesh.VirtualServiceReference{Name: "backend.my-ns.svc.cluster.local"}, gr.Spec.HTTPRoute.Action.Target.VirtualService.VirtualServiceRef)
+}
+
+func Test_meshImporter_assignNames(t *testing.T) {
+	importer := &meshImporter{namespace: "my-ns", names: make(map[string]map[string]string)}
+	assert.NoError(t, importer.assignNames(kindVirtualNode, []string{"Backend_V1", "frontend_my-ns"}))
+	assert.Equal(t, map[string]string{"Backend_V1": "backend-v1", "frontend_my-ns": "frontend"}, importer.names[kindVirtualNode])
+	assert.EqualError(t, importer.assignNames(kindVirtualNode, []string{"backend_v1", "backend-v1"}),
+		"VirtualNode backend_v1 and backend-v1 would both be imported as backend-v1, rename one of them")
+}
+
+func Test_writeManifests(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := writeManifests(buf, []client.Object{
+		&appmesh.VirtualService{
+			TypeMeta:   metav1.TypeMeta{APIVersion: appmesh.GroupVersion.String(), Kind: kindVirtualService},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vs"},
+			Spec:       appmesh.VirtualServiceSpec{AWSName: aws.String("my-vs")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `---
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualService
+metadata:
+  name: my-vs
+  namespace: my-ns
+spec:
+  awsName: my-vs
+`, buf.String())
+}
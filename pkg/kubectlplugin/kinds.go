@@ -0,0 +1,30 @@
+package kubectlplugin
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	kindMesh           = "Mesh"
+	kindVirtualGateway = "VirtualGateway"
+	kindGatewayRoute   = "GatewayRoute"
+	kindVirtualNode    = "VirtualNode"
+	kindVirtualRouter  = "VirtualRouter"
+	kindVirtualService = "VirtualService"
+)
+
+// parseKind parses the kind argument of describe and diff, which accepts kubectl style singular, plural and short names.
+func parseKind(arg string) (string, error) {
+	switch strings.ToLower(arg) {
+	case "virtualnode", "virtualnodes", "vn":
+		return kindVirtualNode, nil
+	case "virtualrouter", "virtualrouters", "vr":
+		return kindVirtualRouter, nil
+	case "virtualservice", "virtualservices", "vs":
+		return kindVirtualService, nil
+	default:
+		return "", errors.Errorf("unsupported kind %q, must be one of virtualnode(vn), virtualrouter(vr) or virtualservice(vs)", arg)
+	}
+}
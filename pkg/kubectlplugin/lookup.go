@@ -0,0 +1,92 @@
+package kubectlplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/arnindex"
+	"github.com/aws/aws-sdk-go/aws"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func lookupCommand() command {
+	return command{
+		name:     "lookup",
+		synopsis: "lookup <arn>",
+		short:    "Find the k8s resource that owns an AppMesh ARN.",
+		run:      runLookup,
+	}
+}
+
+func runLookup(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintf(stderr, "lookup requires an ARN\n")
+		return exitCodeFailure
+	}
+	k8sClient, _, err := newK8sClient(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	obj, err := arnindex.FindByARN(ctx, k8sClient, args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	if err := newLookupResult(obj).write(stdout); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	return exitCodeOK
+}
+
+// lookupResult is the k8s resource owning an AppMesh ARN, along with the AppMesh metadata recorded in its status.
+type lookupResult struct {
+	kind      string
+	namespace string
+	name      string
+	arn       string
+	metadata  *appmesh.AWSResourceMetadata
+}
+
+func newLookupResult(obj client.Object) *lookupResult {
+	result := &lookupResult{
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+	switch o := obj.(type) {
+	case *appmesh.Mesh:
+		result.kind, result.arn, result.metadata = "Mesh", aws.StringValue(o.Status.MeshARN), o.Status.ResourceMetadata
+	case *appmesh.VirtualGateway:
+		result.kind, result.arn, result.metadata = "VirtualGateway", aws.StringValue(o.Status.VirtualGatewayARN), o.Status.ResourceMetadata
+	case *appmesh.GatewayRoute:
+		result.kind, result.arn, result.metadata = "GatewayRoute", aws.StringValue(o.Status.GatewayRouteARN), o.Status.ResourceMetadata
+	case *appmesh.VirtualNode:
+		result.kind, result.arn, result.metadata = kindVirtualNode, aws.StringValue(o.Status.VirtualNodeARN), o.Status.ResourceMetadata
+	case *appmesh.VirtualService:
+		result.kind, result.arn, result.metadata = kindVirtualService, aws.StringValue(o.Status.VirtualServiceARN), o.Status.ResourceMetadata
+	case *appmesh.VirtualRouter:
+		result.kind, result.arn, result.metadata = kindVirtualRouter, aws.StringValue(o.Status.VirtualRouterARN), o.Status.ResourceMetadata
+	}
+	return result
+}
+
+// write writes result in the layout of kubectl describe.
+func (r *lookupResult) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", r.name)
+	fmt.Fprintf(tw, "Namespace:\t%s\n", valueOrNone(r.namespace))
+	fmt.Fprintf(tw, "Kind:\t%s\n", r.kind)
+	fmt.Fprintf(tw, "ARN:\t%s\n", valueOrNone(r.arn))
+	metadata := r.metadata
+	if metadata == nil {
+		metadata = &appmesh.AWSResourceMetadata{}
+	}
+	fmt.Fprintf(tw, "UID:\t%s\n", valueOrNone(aws.StringValue(metadata.UID)))
+	fmt.Fprintf(tw, "Mesh Owner:\t%s\n", valueOrNone(aws.StringValue(metadata.MeshOwner)))
+	fmt.Fprintf(tw, "Resource Owner:\t%s\n", valueOrNone(aws.StringValue(metadata.ResourceOwner)))
+	return tw.Flush()
+}
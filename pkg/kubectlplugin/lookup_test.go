@@ -0,0 +1,68 @@
+package kubectlplugin
+
+import (
+	"bytes"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_lookupResult_write(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want string
+	}{
+		{
+			name: "virtualRouter with resource metadata",
+			obj: &appmesh.VirtualRouter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "router"},
+				Status: appmesh.VirtualRouterStatus{
+					VirtualRouterARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualRouter/router_app-ns"),
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("000000000000"),
+						ResourceOwner: aws.String("000000000000"),
+					},
+				},
+			},
+			want: `Name:            router
+Namespace:       app-ns
+Kind:            VirtualRouter
+ARN:             arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualRouter/router_app-ns
+UID:             uid-1
+Mesh Owner:      000000000000
+Resource Owner:  000000000000
+`,
+		},
+		{
+			name: "mesh without resource metadata",
+			obj: &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Status: appmesh.MeshStatus{
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh"),
+				},
+			},
+			want: `Name:            my-mesh
+Namespace:       <none>
+Kind:            Mesh
+ARN:             arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh
+UID:             <none>
+Mesh Owner:      <none>
+Resource Owner:  <none>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := newLookupResult(tt.obj).write(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
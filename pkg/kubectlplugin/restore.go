@@ -0,0 +1,117 @@
+package kubectlplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/backup"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bindSnapshotStoreFlags binds the flags locating the snapshot store, which mirror the backup flags of the controller.
+func bindSnapshotStoreFlags(fs *pflag.FlagSet, o *options) {
+	fs.StringVar(&o.backupConfigMap, "configmap", "", "The ConfigMap the controller stores snapshots in, in the form of namespace/name.")
+	fs.StringVar(&o.backupS3Bucket, "s3-bucket", "", "The S3 bucket the controller stores snapshots in.")
+	fs.StringVar(&o.backupS3Prefix, "s3-prefix", "appmesh-controller/", "The prefix of keys of snapshots in the S3 bucket.")
+}
+
+func snapshotsCommand() command {
+	return command{
+		name:      "snapshots",
+		synopsis:  "snapshots (--configmap <namespace/name> | --s3-bucket <bucket>)",
+		short:     "List the snapshots of mesh configuration taken by the controller, oldest first.",
+		run:       runSnapshots,
+		bindFlags: bindSnapshotStoreFlags,
+	}
+}
+
+func restoreCommand() command {
+	return command{
+		name:     "restore",
+		synopsis: "restore <snapshot> (--configmap <namespace/name> | --s3-bucket <bucket>)",
+		short:    "Re-apply the desired specs of resources in a snapshot, the controller then reconciles AppMesh to them.",
+		run:      runRestore,
+		bindFlags: func(fs *pflag.FlagSet, o *options) {
+			bindSnapshotStoreFlags(fs, o)
+			fs.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Restore resources of all namespaces in the snapshot, including meshes.")
+			fs.BoolVar(&o.apply, "apply", false, "Apply the restored resources, instead of only showing what would be restored with a server side dry run.")
+		},
+	}
+}
+
+func runSnapshots(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintf(stderr, "snapshots accepts no arguments\n")
+		return exitCodeFailure
+	}
+	store, _, _, err := newSnapshotStore(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	names, err := store.List(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	for _, name := range names {
+		fmt.Fprintln(stdout, name)
+	}
+	return exitCodeOK
+}
+
+func runRestore(ctx context.Context, o *options, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintf(stderr, "restore requires a snapshot name\n")
+		return exitCodeFailure
+	}
+	store, k8sClient, namespace, err := newSnapshotStore(o)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	snapshot, err := store.Load(ctx, args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	opts := backup.RestoreOptions{Namespace: namespace, DryRun: !o.apply}
+	if o.allNamespaces {
+		opts.Namespace = ""
+	}
+	if err := backup.Restore(ctx, k8sClient, snapshot, opts, stdout); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	return exitCodeOK
+}
+
+// newSnapshotStore constructs the snapshot store located by o, along with the k8s client and the namespace resolved from o.
+func newSnapshotStore(o *options) (backup.Store, client.Client, string, error) {
+	if (len(o.backupConfigMap) == 0) == (len(o.backupS3Bucket) == 0) {
+		return nil, nil, "", errors.New("exactly one of --configmap or --s3-bucket is required")
+	}
+	k8sClient, namespace, err := newK8sClient(o)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(o.backupConfigMap) != 0 {
+		cmNamespace, cmName, ok := strings.Cut(o.backupConfigMap, "/")
+		if !ok || len(cmNamespace) == 0 || len(cmName) == 0 {
+			return nil, nil, "", errors.Errorf("--configmap must be in the form of namespace/name: %q", o.backupConfigMap)
+		}
+		key := types.NamespacedName{Namespace: cmNamespace, Name: cmName}
+		return backup.NewConfigMapStore(key, k8sClient, k8sClient), k8sClient, namespace, nil
+	}
+	sess, err := newAWSSession(o)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return backup.NewS3Store(services.NewS3(sess), o.backupS3Bucket, o.backupS3Prefix), k8sClient, namespace, nil
+}
@@ -0,0 +1,118 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CommandName is the name of the controller's subcommand running Run.
+	CommandName = "lint"
+
+	exitCodeOK       = 0
+	exitCodeFindings = 1
+	exitCodeFailure  = 2
+)
+
+// Run runs the lint subcommand with args, which lints manifests within files or directories given as args, or stdin with "-".
+// returns the exit code, which is 1 if errors are found, or 2 if manifests cannot be loaded.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	var failOnWarnings bool
+	fs := pflag.NewFlagSet(CommandName, pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&failOnWarnings, "fail-on-warnings", false, "Exit with non-zero code if warnings are found.")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: controller %s [flags] <file|directory|->...\n", CommandName)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == pflag.ErrHelp {
+			return exitCodeOK
+		}
+		return exitCodeFailure
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitCodeFailure
+	}
+
+	objs, findings, err := loadPaths(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitCodeFailure
+	}
+	findings = append(findings, Lint(objs)...)
+
+	errorCount, warningCount := 0, 0
+	for _, finding := range findings {
+		fmt.Fprintln(stdout, finding.String())
+		if finding.Severity == SeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+	fmt.Fprintf(stdout, "linted %d objects: %d errors, %d warnings\n", len(objs), errorCount, warningCount)
+	if errorCount > 0 || (failOnWarnings && warningCount > 0) {
+		return exitCodeFindings
+	}
+	return exitCodeOK
+}
+
+// loadPaths loads manifests within paths, directories are walked for .yaml, .yml and .json files.
+func loadPaths(paths []string) ([]client.Object, []Finding, error) {
+	var objs []client.Object
+	var findings []Finding
+	load := func(name string, r io.Reader) error {
+		fileObjs, fileFindings, err := LoadManifests(r)
+		if err != nil {
+			return errors.Wrap(err, name)
+		}
+		objs = append(objs, fileObjs...)
+		findings = append(findings, fileFindings...)
+		return nil
+	}
+	for _, path := range paths {
+		if path == "-" {
+			if err := load("stdin", os.Stdin); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			if filePath != path && !isManifestFile(filePath) {
+				return nil
+			}
+			f, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return load(filePath, f)
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return objs, findings, nil
+}
+
+func isManifestFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	const validManifests = `
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualNode
+metadata:
+  name: node-a
+  namespace: ns
+`
+	const invalidManifests = `
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualRouter
+metadata:
+  name: router
+  namespace: ns
+spec:
+  listeners:
+    - portMapping:
+        port: 8080
+        protocol: http
+  routes:
+    - name: r1
+`
+	tests := []struct {
+		name         string
+		files        map[string]string
+		args         []string
+		wantExitCode int
+		wantStdout   string
+	}{
+		{
+			name:         "valid manifests within directory",
+			files:        map[string]string{"node.yaml": validManifests, "README.md": "not a manifest"},
+			args:         []string{"."},
+			wantExitCode: 0,
+			wantStdout:   "linted 1 objects: 0 errors, 0 warnings\n",
+		},
+		{
+			name:         "invalid manifests",
+			files:        map[string]string{"node.yaml": validManifests, "router.yml": invalidManifests},
+			args:         []string{"node.yaml", "router.yml"},
+			wantExitCode: 1,
+			wantStdout: "error: VirtualRouter ns/router: route r1 must specify exactly one of grpcRoute, httpRoute, http2Route or tcpRoute\n" +
+				"linted 2 objects: 1 errors, 0 warnings\n",
+		},
+		{
+			name:         "missing file",
+			args:         []string{"missing.yaml"},
+			wantExitCode: 2,
+		},
+		{
+			name:         "no paths",
+			args:         nil,
+			wantExitCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+			var args []string
+			for _, arg := range tt.args {
+				args = append(args, filepath.Join(dir, arg))
+			}
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			gotExitCode := Run(args, stdout, stderr)
+			assert.Equal(t, tt.wantExitCode, gotExitCode, "stderr", stderr.String())
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/gatewayroute"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	appmeshwebhook "github.com/aws/aws-app-mesh-controller-for-k8s/webhooks/appmesh"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// namespace of namespaced objects that don't specify one, same as kubectl.
+	defaultNamespace = "default"
+	// maximum number of weighted targets per route.
+	maxWeightedTargets = 10
+	// maximum weight of a weighted target.
+	maxWeight = 100
+)
+
+type Severity string
+
+const (
+	// SeverityError is for issues that fail to reconcile the object.
+	SeverityError Severity = "error"
+	// SeverityWarning is for issues that may not behave as intended.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is an issue found in an object.
+type Finding struct {
+	Severity Severity
+	Kind     string
+	Object   types.NamespacedName
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s %s: %s", f.Severity, f.Kind, objectKeyString(f.Object), f.Message)
+}
+
+// Lint lints AppMesh objs offline, without accessing a cluster or AppMesh.
+// objs are converted into AppMesh specs the same way the controller does, with awsName defaulted like the webhooks do.
+// references to objects that are not within objs are reported as warnings.
+func Lint(objs []client.Object) []Finding {
+	l := newLinter(objs)
+	var findings []Finding
+	for _, obj := range l.objs {
+		findings = append(findings, l.lintObject(obj)...)
+	}
+	return findings
+}
+
+type linter struct {
+	objs             []client.Object
+	awsNameGenerator awsname.Generator
+	vsByKey          map[types.NamespacedName]*appmesh.VirtualService
+	vnByKey          map[types.NamespacedName]*appmesh.VirtualNode
+	vrByKey          map[types.NamespacedName]*appmesh.VirtualRouter
+}
+
+func newLinter(objs []client.Object) *linter {
+	l := &linter{
+		awsNameGenerator: awsname.NewGenerator(awsname.Config{Strategy: awsname.StrategyNameNamespace}, ""),
+		vsByKey:          make(map[types.NamespacedName]*appmesh.VirtualService),
+		vnByKey:          make(map[types.NamespacedName]*appmesh.VirtualNode),
+		vrByKey:          make(map[types.NamespacedName]*appmesh.VirtualRouter),
+	}
+	for _, obj := range objs {
+		obj = obj.DeepCopyObject().(client.Object)
+		l.defaultObject(obj)
+		switch o := obj.(type) {
+		case *appmesh.VirtualService:
+			l.vsByKey[k8s.NamespacedName(o)] = o
+		case *appmesh.VirtualNode:
+			l.vnByKey[k8s.NamespacedName(o)] = o
+		case *appmesh.VirtualRouter:
+			l.vrByKey[k8s.NamespacedName(o)] = o
+		}
+		l.objs = append(l.objs, obj)
+	}
+	return l
+}
+
+// defaultObject defaults obj the way it's defaulted once applied.
+func (l *linter) defaultObject(obj client.Object) {
+	if _, ok := obj.(*appmesh.Mesh); !ok && len(obj.GetNamespace()) == 0 {
+		obj.SetNamespace(defaultNamespace)
+	}
+	defaultAWSName := func(awsName **string, separator string) {
+		if *awsName == nil || len(**awsName) == 0 {
+			generated := l.awsNameGenerator.Generate(obj.GetName(), obj.GetNamespace(), separator)
+			*awsName = &generated
+		}
+	}
+	switch o := obj.(type) {
+	case *appmesh.Mesh:
+		defaultAWSName(&o.Spec.AWSName, "")
+	case *appmesh.VirtualGateway:
+		o.Spec.MeshRef = nil
+		defaultAWSName(&o.Spec.AWSName, "_")
+	case *appmesh.GatewayRoute:
+		o.Spec.MeshRef = nil
+		o.Spec.VirtualGatewayRef = nil
+		defaultAWSName(&o.Spec.AWSName, "_")
+	case *appmesh.VirtualNode:
+		o.Spec.MeshRef = nil
+		defaultAWSName(&o.Spec.AWSName, "_")
+	case *appmesh.VirtualService:
+		o.Spec.MeshRef = nil
+		defaultAWSName(&o.Spec.AWSName, ".")
+	case *appmesh.VirtualRouter:
+		o.Spec.MeshRef = nil
+		defaultAWSName(&o.Spec.AWSName, "_")
+	}
+}
+
+func (l *linter) lintObject(obj client.Object) []Finding {
+	ctx := context.Background()
+	var validator webhook.Validator
+	var findings []Finding
+	var buildErr error
+	switch o := obj.(type) {
+	case *appmesh.Mesh:
+		validator = appmeshwebhook.NewMeshValidator("")
+		_, buildErr = mesh.BuildSDKMeshSpec(ctx, o)
+	case *appmesh.VirtualGateway:
+		validator = appmeshwebhook.NewVirtualGatewayValidator()
+		_, buildErr = virtualgateway.BuildSDKVirtualGatewaySpec(ctx, o)
+	case *appmesh.GatewayRoute:
+		validator = appmeshwebhook.NewGatewayRouteValidator()
+		vsByKey, missingFindings := l.resolveVirtualServices(o, gatewayroute.ExtractVirtualServiceReferences(o))
+		findings = append(findings, missingFindings...)
+		_, buildErr = gatewayroute.BuildSDKGatewayRouteSpec(ctx, o, vsByKey)
+	case *appmesh.VirtualNode:
+		validator = appmeshwebhook.NewVirtualNodeValidator(&virtualNodesReader{vnByKey: l.vnByKey}, nil, quota.NewNoopNamespaceChecker())
+		vsByKey, missingFindings := l.resolveVirtualServices(o, virtualnode.ExtractVirtualServiceReferences(o))
+		findings = append(findings, missingFindings...)
+		_, buildErr = virtualnode.BuildSDKVirtualNodeSpec(o, vsByKey)
+	case *appmesh.VirtualService:
+		validator = appmeshwebhook.NewVirtualServiceValidator(nil, nil)
+		vnByKey, missingVNFindings := l.resolveVirtualNodes(o, virtualservice.ExtractVirtualNodeReferences(o))
+		vrByKey, missingVRFindings := l.resolveVirtualRouters(o, virtualservice.ExtractVirtualRouterReferences(o))
+		findings = append(findings, missingVNFindings...)
+		findings = append(findings, missingVRFindings...)
+		_, buildErr = virtualservice.BuildSDKVirtualServiceSpec(o, vnByKey, vrByKey)
+	case *appmesh.VirtualRouter:
+		validator = appmeshwebhook.NewVirtualRouterValidator(nil, quota.NewNoopNamespaceChecker())
+		vnByKey, missingFindings := l.resolveVirtualNodes(o, virtualrouter.ExtractVirtualNodeReferences(o))
+		findings = append(findings, missingFindings...)
+		if _, err := virtualrouter.BuildSDKVirtualRouterSpec(o); err != nil {
+			findings = append(findings, newFinding(SeverityError, o, err.Error()))
+		}
+		for _, route := range o.Spec.Routes {
+			if _, err := virtualrouter.BuildSDKRouteSpec(o, route, vnByKey); err != nil {
+				findings = append(findings, newFinding(SeverityError, o, fmt.Sprintf("route %s: %v", route.Name, err)))
+			}
+		}
+		findings = append(findings, lintRoutes(o)...)
+	case *appmesh.BackendGroup:
+		validator = appmeshwebhook.NewBackendGroupValidator()
+	default:
+		return nil
+	}
+	if err := validator.ValidateCreate(ctx, obj); err != nil {
+		findings = append(findings, newFinding(SeverityError, obj, err.Error()))
+	}
+	if buildErr != nil {
+		findings = append(findings, newFinding(SeverityError, obj, buildErr.Error()))
+	}
+	return findings
+}
+
+// lintRoutes lints the routes of vr for issues that are accepted by AppMesh, but don't behave as intended.
+func lintRoutes(vr *appmesh.VirtualRouter) []Finding {
+	var findings []Finding
+	routeNameByPriority := make(map[int64]string)
+	for _, route := range vr.Spec.Routes {
+		if route.Priority != nil {
+			if otherRouteName, ok := routeNameByPriority[*route.Priority]; ok {
+				findings = append(findings, newFinding(SeverityWarning, vr,
+					fmt.Sprintf("routes %s and %s have the same priority %d, the order they're matched in is undefined", otherRouteName, route.Name, *route.Priority)))
+			} else {
+				routeNameByPriority[*route.Priority] = route.Name
+			}
+		}
+
+		var weightedTargets []appmesh.WeightedTarget
+		routeTypeCount := 0
+		if route.GRPCRoute != nil {
+			routeTypeCount++
+			weightedTargets = route.GRPCRoute.Action.WeightedTargets
+		}
+		if route.HTTPRoute != nil {
+			routeTypeCount++
+			weightedTargets = route.HTTPRoute.Action.WeightedTargets
+		}
+		if route.HTTP2Route != nil {
+			routeTypeCount++
+			weightedTargets = route.HTTP2Route.Action.WeightedTargets
+		}
+		if route.TCPRoute != nil {
+			routeTypeCount++
+			weightedTargets = route.TCPRoute.Action.WeightedTargets
+		}
+		if routeTypeCount != 1 {
+			findings = append(findings, newFinding(SeverityError, vr,
+				fmt.Sprintf("route %s must specify exactly one of grpcRoute, httpRoute, http2Route or tcpRoute", route.Name)))
+			continue
+		}
+		for _, message := range lintWeightedTargets(weightedTargets) {
+			findings = append(findings, newFinding(SeverityError, vr, fmt.Sprintf("route %s: %s", route.Name, message)))
+		}
+	}
+	return findings
+}
+
+func lintWeightedTargets(weightedTargets []appmesh.WeightedTarget) []string {
+	if len(weightedTargets) == 0 {
+		return []string{"must specify at least one weightedTarget"}
+	}
+	var messages []string
+	if len(weightedTargets) > maxWeightedTargets {
+		messages = append(messages, fmt.Sprintf("must specify at most %d weightedTargets, got %d", maxWeightedTargets, len(weightedTargets)))
+	}
+	var totalWeight int64
+	for i, target := range weightedTargets {
+		references := 0
+		if target.VirtualNodeRef != nil {
+			references++
+		}
+		if target.VirtualNodeARN != nil {
+			references++
+		}
+		if target.VirtualNodeSelector != nil {
+			references++
+		}
+		if references != 1 {
+			messages = append(messages, fmt.Sprintf("weightedTargets[%d] must specify exactly one of virtualNodeRef, virtualNodeARN or virtualNodeSelector", i))
+		}
+		if target.Weight < 0 || target.Weight > maxWeight {
+			messages = append(messages, fmt.Sprintf("weightedTargets[%d] weight must be between 0 and %d, got %d", i, maxWeight, target.Weight))
+		}
+		totalWeight += target.Weight
+	}
+	if totalWeight == 0 {
+		messages = append(messages, "weights of all weightedTargets are 0, no traffic is routed")
+	}
+	return messages
+}
+
+func (l *linter) resolveVirtualServices(obj client.Object, vsRefs []appmesh.VirtualServiceReference) (map[types.NamespacedName]*appmesh.VirtualService, []Finding) {
+	var findings []Finding
+	vsByKey := make(map[types.NamespacedName]*appmesh.VirtualService)
+	for _, vsRef := range vsRefs {
+		vsKey := references.ObjectKeyForVirtualServiceReference(obj, vsRef)
+		if _, ok := vsByKey[vsKey]; ok {
+			continue
+		}
+		vs, ok := l.vsByKey[vsKey]
+		if !ok {
+			findings = append(findings, newMissingReferenceFinding(obj, "VirtualService", vsKey))
+			vs = &appmesh.VirtualService{ObjectMeta: metav1.ObjectMeta{Namespace: vsKey.Namespace, Name: vsKey.Name}}
+			l.defaultObject(vs)
+		}
+		vsByKey[vsKey] = vs
+	}
+	return vsByKey, findings
+}
+
+func (l *linter) resolveVirtualNodes(obj client.Object, vnRefs []appmesh.VirtualNodeReference) (map[types.NamespacedName]*appmesh.VirtualNode, []Finding) {
+	var findings []Finding
+	vnByKey := make(map[types.NamespacedName]*appmesh.VirtualNode)
+	for _, vnRef := range vnRefs {
+		vnKey := references.ObjectKeyForVirtualNodeReference(obj, vnRef)
+		if _, ok := vnByKey[vnKey]; ok {
+			continue
+		}
+		vn, ok := l.vnByKey[vnKey]
+		if !ok {
+			findings = append(findings, newMissingReferenceFinding(obj, "VirtualNode", vnKey))
+			vn = &appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{Namespace: vnKey.Namespace, Name: vnKey.Name}}
+			l.defaultObject(vn)
+		}
+		vnByKey[vnKey] = vn
+	}
+	return vnByKey, findings
+}
+
+func (l *linter) resolveVirtualRouters(obj client.Object, vrRefs []appmesh.VirtualRouterReference) (map[types.NamespacedName]*appmesh.VirtualRouter, []Finding) {
+	var findings []Finding
+	vrByKey := make(map[types.NamespacedName]*appmesh.VirtualRouter)
+	for _, vrRef := range vrRefs {
+		vrKey := references.ObjectKeyForVirtualRouterReference(obj, vrRef)
+		if _, ok := vrByKey[vrKey]; ok {
+			continue
+		}
+		vr, ok := l.vrByKey[vrKey]
+		if !ok {
+			findings = append(findings, newMissingReferenceFinding(obj, "VirtualRouter", vrKey))
+			vr = &appmesh.VirtualRouter{ObjectMeta: metav1.ObjectMeta{Namespace: vrKey.Namespace, Name: vrKey.Name}}
+			l.defaultObject(vr)
+		}
+		vrByKey[vrKey] = vr
+	}
+	return vrByKey, findings
+}
+
+// virtualNodesReader lists the VirtualNodes within manifests, so validation against other VirtualNodes works offline.
+type virtualNodesReader struct {
+	vnByKey map[types.NamespacedName]*appmesh.VirtualNode
+}
+
+var _ client.Reader = &virtualNodesReader{}
+
+func (r *virtualNodesReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	vn, ok := r.vnByKey[key]
+	vnObj, isVN := obj.(*appmesh.VirtualNode)
+	if !ok || !isVN {
+		return apierrors.NewNotFound(appmesh.GroupVersion.WithResource("virtualnodes").GroupResource(), key.Name)
+	}
+	vn.DeepCopyInto(vnObj)
+	return nil
+}
+
+func (r *virtualNodesReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	vnList, ok := list.(*appmesh.VirtualNodeList)
+	if !ok {
+		return errors.Errorf("unsupported list type %T", list)
+	}
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	vnList.Items = nil
+	for key, vn := range r.vnByKey {
+		if len(listOpts.Namespace) != 0 && key.Namespace != listOpts.Namespace {
+			continue
+		}
+		vnList.Items = append(vnList.Items, *vn.DeepCopy())
+	}
+	return nil
+}
+
+func newFinding(severity Severity, obj client.Object, message string) Finding {
+	return Finding{
+		Severity: severity,
+		Kind:     kindOf(obj),
+		Object:   k8s.NamespacedName(obj),
+		Message:  message,
+	}
+}
+
+func newMissingReferenceFinding(obj client.Object, referentKind string, referentKey types.NamespacedName) Finding {
+	return newFinding(SeverityWarning, obj, fmt.Sprintf("referenced %s %s isn't within manifests", referentKind, objectKeyString(referentKey)))
+}
+
+// kindOf returns the kind of obj, which may not have TypeMeta populated.
+func kindOf(obj client.Object) string {
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+	return gvks[0].Kind
+}
+
+func objectKeyString(key types.NamespacedName) string {
+	if len(key.Namespace) == 0 {
+		return key.Name
+	}
+	return key.String()
+}
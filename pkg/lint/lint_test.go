@@ -0,0 +1,211 @@
+package lint
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLint(t *testing.T) {
+	vnA := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-a"},
+	}
+	httpRoute := func(name string, priority int64, weightedTargets ...appmesh.WeightedTarget) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match:  appmesh.HTTPRouteMatch{Prefix: aws.String("/")},
+				Action: appmesh.HTTPRouteAction{WeightedTargets: weightedTargets},
+			},
+			Priority: aws.Int64(priority),
+		}
+	}
+	vrWithRoutes := func(routes ...appmesh.Route) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "router"},
+			Spec: appmesh.VirtualRouterSpec{
+				Listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}},
+				},
+				Routes: routes,
+			},
+		}
+	}
+	targetNodeA := func(weight int64) appmesh.WeightedTarget {
+		return appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "node-a"}, Weight: weight}
+	}
+	routerKey := types.NamespacedName{Namespace: "ns", Name: "router"}
+
+	tests := []struct {
+		name         string
+		objs         []client.Object
+		wantFindings []Finding
+	}{
+		{
+			name: "valid objects",
+			objs: []client.Object{
+				vnA,
+				vrWithRoutes(httpRoute("r1", 1, targetNodeA(1)), httpRoute("r2", 2, targetNodeA(1))),
+			},
+			wantFindings: nil,
+		},
+		{
+			name: "namespace defaults to default",
+			objs: []client.Object{
+				&appmesh.VirtualService{
+					ObjectMeta: metav1.ObjectMeta{Name: "svc-a"},
+					Spec: appmesh.VirtualServiceSpec{
+						Provider: &appmesh.VirtualServiceProvider{
+							VirtualNode: &appmesh.VirtualNodeServiceProvider{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "node-a"}},
+						},
+					},
+				},
+			},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityWarning,
+					Kind:     "VirtualService",
+					Object:   types.NamespacedName{Namespace: "default", Name: "svc-a"},
+					Message:  "referenced VirtualNode default/node-a isn't within manifests",
+				},
+			},
+		},
+		{
+			name: "duplicate route priorities",
+			objs: []client.Object{
+				vnA,
+				vrWithRoutes(httpRoute("r1", 1, targetNodeA(1)), httpRoute("r2", 1, targetNodeA(1))),
+			},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityWarning,
+					Kind:     "VirtualRouter",
+					Object:   routerKey,
+					Message:  "routes r1 and r2 have the same priority 1, the order they're matched in is undefined",
+				},
+			},
+		},
+		{
+			name: "invalid weighted targets",
+			objs: []client.Object{
+				vnA,
+				vrWithRoutes(
+					httpRoute("r1", 1, targetNodeA(0)),
+					httpRoute("r2", 2, targetNodeA(101), appmesh.WeightedTarget{Weight: 1}),
+				),
+			},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualRouter",
+					Object:   routerKey,
+					Message:  "route r1: weights of all weightedTargets are 0, no traffic is routed",
+				},
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualRouter",
+					Object:   routerKey,
+					Message:  "route r2: weightedTargets[0] weight must be between 0 and 100, got 101",
+				},
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualRouter",
+					Object:   routerKey,
+					Message:  "route r2: weightedTargets[1] must specify exactly one of virtualNodeRef, virtualNodeARN or virtualNodeSelector",
+				},
+			},
+		},
+		{
+			name: "route without route type",
+			objs: []client.Object{
+				vrWithRoutes(appmesh.Route{Name: "r1"}),
+			},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualRouter",
+					Object:   routerKey,
+					Message:  "route r1 must specify exactly one of grpcRoute, httpRoute, http2Route or tcpRoute",
+				},
+			},
+		},
+		{
+			name: "overlapping pod selectors",
+			objs: []client.Object{
+				&appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-blue"},
+					Spec: appmesh.VirtualNodeSpec{
+						PodSelectorTerms: []metav1.LabelSelector{
+							{MatchLabels: map[string]string{"app": "my-app", "version": "blue"}},
+						},
+					},
+				},
+				&appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-all"},
+					Spec: appmesh.VirtualNodeSpec{
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+					},
+				},
+			},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualNode",
+					Object:   types.NamespacedName{Namespace: "ns", Name: "node-blue"},
+					Message:  "pod selectors overlap with virtualNode node-all, pods can only be selected by a single virtualNode",
+				},
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualNode",
+					Object:   types.NamespacedName{Namespace: "ns", Name: "node-all"},
+					Message:  "pod selectors overlap with virtualNode node-blue, pods can only be selected by a single virtualNode",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFindings := Lint(tt.objs)
+			assert.Equal(t, tt.wantFindings, gotFindings)
+		})
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		want    string
+	}{
+		{
+			name: "namespaced object",
+			finding: Finding{
+				Severity: SeverityError,
+				Kind:     "VirtualRouter",
+				Object:   types.NamespacedName{Namespace: "ns", Name: "router"},
+				Message:  "oops",
+			},
+			want: "error: VirtualRouter ns/router: oops",
+		},
+		{
+			name: "cluster scoped object",
+			finding: Finding{
+				Severity: SeverityWarning,
+				Kind:     "Mesh",
+				Object:   types.NamespacedName{Name: "my-mesh"},
+				Message:  "oops",
+			},
+			want: "warning: Mesh my-mesh: oops",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.finding.String())
+		})
+	}
+}
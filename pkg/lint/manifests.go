@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"bytes"
+	"io"
+
+	appmeshv1 "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = appmesh.AddToScheme(scheme)
+	_ = appmeshv1.AddToScheme(scheme)
+}
+
+// LoadManifests decodes AppMesh objects from YAML or JSON manifests in r, objects of other kinds are skipped.
+// manifests with unknown fields are decoded as well, while reported as findings.
+func LoadManifests(r io.Reader) ([]client.Object, []Finding, error) {
+	deserializer := serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDeserializer()
+	decoder := yamlutil.NewYAMLOrJSONDecoder(r, 4096)
+	var objs []client.Object
+	var findings []Finding
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, nil, errors.Wrap(err, "failed to decode manifests")
+		}
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue
+		}
+		obj, gvk, err := deserializer.Decode(raw.Raw, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) || runtime.IsMissingVersion(err) {
+				continue
+			}
+			if !runtime.IsStrictDecodingError(err) {
+				return nil, nil, errors.Wrap(err, "failed to decode manifests")
+			}
+			clientObj := obj.(client.Object)
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Kind:     gvk.Kind,
+				Object:   types.NamespacedName{Namespace: clientObj.GetNamespace(), Name: clientObj.GetName()},
+				Message:  err.Error(),
+			})
+		}
+		if v1Mesh, ok := obj.(*appmeshv1.Mesh); ok {
+			mesh := &appmesh.Mesh{}
+			if err := v1Mesh.ConvertTo(mesh); err != nil {
+				return nil, nil, err
+			}
+			obj = mesh
+		}
+		objs = append(objs, obj.(client.Object))
+	}
+	return objs, findings, nil
+}
@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestLoadManifests(t *testing.T) {
+	tests := []struct {
+		name         string
+		manifests    string
+		wantKinds    []string
+		wantFindings []Finding
+		wantErr      string
+	}{
+		{
+			name: "yaml manifests with objects of other kinds",
+			manifests: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualNode
+metadata:
+  name: node-a
+  namespace: ns
+---
+---
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualService
+metadata:
+  name: svc-a
+  namespace: ns
+`,
+			wantKinds: []string{"VirtualNode", "VirtualService"},
+		},
+		{
+			name:      "json manifest",
+			manifests: `{"apiVersion": "appmesh.k8s.aws/v1beta2", "kind": "Mesh", "metadata": {"name": "my-mesh"}}`,
+			wantKinds: []string{"Mesh"},
+		},
+		{
+			name: "unknown fields are reported",
+			manifests: `
+apiVersion: appmesh.k8s.aws/v1beta2
+kind: VirtualNode
+metadata:
+  name: node-a
+  namespace: ns
+spec:
+  listner: []
+`,
+			wantKinds: []string{"VirtualNode"},
+			wantFindings: []Finding{
+				{
+					Severity: SeverityError,
+					Kind:     "VirtualNode",
+					Object:   types.NamespacedName{Namespace: "ns", Name: "node-a"},
+					Message:  `strict decoding error: unknown field "spec.listner"`,
+				},
+			},
+		},
+		{
+			name:      "malformed manifests",
+			manifests: "kind: [",
+			wantErr:   "failed to decode manifests",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, findings, err := LoadManifests(strings.NewReader(tt.manifests))
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var gotKinds []string
+			for _, obj := range objs {
+				gotKinds = append(gotKinds, kindOf(obj))
+			}
+			assert.Equal(t, tt.wantKinds, gotKinds)
+			assert.Equal(t, tt.wantFindings, findings)
+		})
+	}
+}
+
+func TestLoadManifests_v1MeshConversion(t *testing.T) {
+	objs, _, err := LoadManifests(strings.NewReader(`
+apiVersion: appmesh.k8s.aws/v1
+kind: Mesh
+metadata:
+  name: my-mesh
+spec:
+  serviceDiscovery:
+    ipPreference: IPv6_ONLY
+`))
+	assert.NoError(t, err)
+	assert.Len(t, objs, 1)
+	ms, ok := objs[0].(*appmesh.Mesh)
+	assert.True(t, ok)
+	assert.Equal(t, "IPv6_ONLY", *ms.Spec.ServiceDiscovery.IpPreference)
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// EndpointPath is the path of the log levels endpoint on the metrics server.
+	EndpointPath = "/debug/loglevel"
+
+	// FlagLoggerVerbosity is the flag of the verbosity of loggers, which can be reloaded at runtime.
+	FlagLoggerVerbosity = "log-verbosity"
+
+	flagEnableEndpoint = "enable-log-level-endpoint"
+)
+
+type Config struct {
+	// EnableEndpoint controls whether log levels can be read and changed at EndpointPath of the metrics server.
+	EnableEndpoint bool
+	// LoggerVerbosity is the verbosity of loggers by name, in the form of name=verbosity,name=verbosity, see ParseLoggerVerbosity.
+	LoggerVerbosity string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.EnableEndpoint, flagEnableEndpoint, false,
+		"If enabled, log levels can be read and changed at "+EndpointPath+" of the metrics server, by callers allowed to get and put it")
+	fs.StringVar(&cfg.LoggerVerbosity, FlagLoggerVerbosity, "",
+		"Comma-separated list of name=verbosity overriding the log level of loggers with the name, e.g. virtualrouter=2 logs V(2) messages of the VirtualRouter controller")
+}
+
+func (cfg *Config) Validate() error {
+	if _, err := ParseLoggerVerbosity(cfg.LoggerVerbosity); err != nil {
+		return errors.Wrap(err, FlagLoggerVerbosity)
+	}
+	return nil
+}
+
+// ParseLoggerVerbosity parses the verbosity of loggers in the form of name=verbosity,name=verbosity, e.g. virtualrouter=2,cloudmap=1.
+// names are case-insensitive, and verbosities are the non-negative V levels of logr.
+func ParseLoggerVerbosity(value string) (map[string]int, error) {
+	loggerVerbosity := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		name, verbosityValue, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || len(name) == 0 {
+			return nil, errors.Errorf("must be a list of name=verbosity: %q", entry)
+		}
+		verbosity, err := strconv.Atoi(strings.TrimSpace(verbosityValue))
+		if err != nil || verbosity < 0 {
+			return nil, errors.Errorf("verbosity of %s must be a non-negative integer: %q", name, verbosityValue)
+		}
+		loggerVerbosity[strings.ToLower(name)] = verbosity
+	}
+	return loggerVerbosity, nil
+}
+
+// FormatLoggerVerbosity formats the verbosity of loggers as parsed by ParseLoggerVerbosity, sorted by name.
+func FormatLoggerVerbosity(loggerVerbosity map[string]int) string {
+	entries := make([]string, 0, len(loggerVerbosity))
+	for name, verbosity := range loggerVerbosity {
+		entries = append(entries, fmt.Sprintf("%s=%d", name, verbosity))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoggerVerbosity(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]int
+		wantErr error
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  map[string]int{},
+		},
+		{
+			name:  "loggers",
+			value: "VirtualRouter=2, cloudmap=1,controllers.virtualnode=0,",
+			want:  map[string]int{"virtualrouter": 2, "cloudmap": 1, "controllers.virtualnode": 0},
+		},
+		{
+			name:    "missing verbosity",
+			value:   "virtualrouter",
+			wantErr: errors.New(`must be a list of name=verbosity: "virtualrouter"`),
+		},
+		{
+			name:    "negative verbosity",
+			value:   "virtualrouter=-1",
+			wantErr: errors.New(`verbosity of virtualrouter must be a non-negative integer: "-1"`),
+		},
+		{
+			name:    "non-numeric verbosity",
+			value:   "virtualrouter=debug",
+			wantErr: errors.New(`verbosity of virtualrouter must be a non-negative integer: "debug"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLoggerVerbosity(tt.value)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, got, mustParseLoggerVerbosity(t, FormatLoggerVerbosity(got)))
+			}
+		})
+	}
+}
+
+func mustParseLoggerVerbosity(t *testing.T, value string) map[string]int {
+	loggerVerbosity, err := ParseLoggerVerbosity(value)
+	assert.NoError(t, err)
+	return loggerVerbosity
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Settings are the log levels read and written at EndpointPath.
+type Settings struct {
+	// Level is the log level of the controller, info or debug.
+	Level string `json:"level,omitempty"`
+	// Loggers is the verbosity of loggers by name overriding Level, see Levels.SetLoggerVerbosity.
+	Loggers map[string]int `json:"loggers,omitempty"`
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// NewHandler constructs new http.Handler serving the Settings of levels on GET, and changing them on PUT.
+// callers must present a bearer token of an identity that is allowed to get or put the non-resource URL EndpointPath.
+func NewHandler(clientSet kubernetes.Interface, levels *Levels, log logr.Logger) http.Handler {
+	return &handler{
+		authorizer: k8s.NewDefaultRequestAuthorizer(clientSet),
+		levels:     levels,
+		log:        log,
+	}
+}
+
+type handler struct {
+	authorizer k8s.RequestAuthorizer
+	levels     *Levels
+	log        logr.Logger
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPut {
+		http.Error(w, "only GET and PUT are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	verb := strings.ToLower(req.Method)
+	username, code, err := h.authorizer.Authorize(req.Context(), req, k8s.AccessAttributes{
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: EndpointPath, Verb: verb},
+		Description:           verb + " " + EndpointPath,
+	})
+	if err != nil {
+		if code == http.StatusInternalServerError {
+			h.log.Error(err, "failed to authorize log levels request")
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		var settings Settings
+		decoder := json.NewDecoder(req.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&settings); err != nil {
+			http.Error(w, "invalid log levels: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.apply(settings); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Info("log levels changed", "user", username, "level", settings.Level, "loggers", FormatLoggerVerbosity(settings.Loggers))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.settings()); err != nil {
+		h.log.Error(err, "failed to write log levels")
+	}
+}
+
+// apply changes the level if set, and replaces the verbosity of loggers if set, so that an empty object of loggers resets them.
+func (h *handler) apply(settings Settings) error {
+	switch settings.Level {
+	case "":
+	case LevelInfo:
+		h.levels.SetDebug(false)
+	case LevelDebug:
+		h.levels.SetDebug(true)
+	default:
+		return errors.Errorf("level must be one of info, debug: %q", settings.Level)
+	}
+	for name, verbosity := range settings.Loggers {
+		if verbosity < 0 {
+			return errors.Errorf("verbosity of %s must be non-negative: %d", name, verbosity)
+		}
+	}
+	if settings.Loggers != nil {
+		h.levels.SetLoggerVerbosity(settings.Loggers)
+	}
+	return nil
+}
+
+func (h *handler) settings() Settings {
+	settings := Settings{Level: LevelInfo, Loggers: h.levels.LoggerVerbosity()}
+	if h.levels.Debug() {
+		settings.Level = LevelDebug
+	}
+	return settings
+}
@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_handler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name                string
+		method              string
+		token               string
+		body                string
+		wantStatus          int
+		wantBody            string
+		wantDebug           bool
+		wantLoggerVerbosity map[string]int
+	}{
+		{
+			name:                "gets log levels",
+			method:              http.MethodGet,
+			token:               "viewer-token",
+			wantStatus:          http.StatusOK,
+			wantBody:            `{"level":"info","loggers":{"cloudmap":1}}` + "\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "puts log level and verbosity of loggers",
+			method:              http.MethodPut,
+			token:               "operator-token",
+			body:                `{"level": "debug", "loggers": {"VirtualRouter": 2}}`,
+			wantStatus:          http.StatusOK,
+			wantBody:            `{"level":"debug","loggers":{"virtualrouter":2}}` + "\n",
+			wantDebug:           true,
+			wantLoggerVerbosity: map[string]int{"virtualrouter": 2},
+		},
+		{
+			name:                "puts log level only",
+			method:              http.MethodPut,
+			token:               "operator-token",
+			body:                `{"level": "debug"}`,
+			wantStatus:          http.StatusOK,
+			wantBody:            `{"level":"debug","loggers":{"cloudmap":1}}` + "\n",
+			wantDebug:           true,
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "resets verbosity of loggers",
+			method:              http.MethodPut,
+			token:               "operator-token",
+			body:                `{"loggers": {}}`,
+			wantStatus:          http.StatusOK,
+			wantBody:            `{"level":"info"}` + "\n",
+			wantLoggerVerbosity: map[string]int{},
+		},
+		{
+			name:                "rejects invalid level",
+			method:              http.MethodPut,
+			token:               "operator-token",
+			body:                `{"level": "trace"}`,
+			wantStatus:          http.StatusBadRequest,
+			wantBody:            "level must be one of info, debug: \"trace\"\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "rejects negative verbosity",
+			method:              http.MethodPut,
+			token:               "operator-token",
+			body:                `{"loggers": {"virtualnode": -1}}`,
+			wantStatus:          http.StatusBadRequest,
+			wantBody:            "verbosity of virtualnode must be non-negative: -1\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "forbids put without permission",
+			method:              http.MethodPut,
+			token:               "viewer-token",
+			body:                `{"level": "debug"}`,
+			wantStatus:          http.StatusForbidden,
+			wantBody:            "viewer is not allowed to put /debug/loglevel\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "requires bearer token",
+			method:              http.MethodGet,
+			wantStatus:          http.StatusUnauthorized,
+			wantBody:            "bearer token is required\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "rejects invalid bearer token",
+			method:              http.MethodGet,
+			token:               "unknown-token",
+			wantStatus:          http.StatusUnauthorized,
+			wantBody:            "invalid bearer token\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+		{
+			name:                "unsupported method",
+			method:              http.MethodPost,
+			token:               "operator-token",
+			wantStatus:          http.StatusMethodNotAllowed,
+			wantBody:            "only GET and PUT are supported\n",
+			wantLoggerVerbosity: map[string]int{"cloudmap": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSet := fake.NewSimpleClientset()
+			clientSet.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				tokenReview := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				switch tokenReview.Spec.Token {
+				case "operator-token":
+					tokenReview.Status.Authenticated = true
+					tokenReview.Status.User = authenticationv1.UserInfo{Username: "operator"}
+				case "viewer-token":
+					tokenReview.Status.Authenticated = true
+					tokenReview.Status.User = authenticationv1.UserInfo{Username: "viewer"}
+				}
+				return true, tokenReview, nil
+			})
+			clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				accessReview := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				attrs := accessReview.Spec.NonResourceAttributes
+				accessReview.Status.Allowed = attrs.Path == "/debug/loglevel" &&
+					(accessReview.Spec.User == "operator" || (accessReview.Spec.User == "viewer" && attrs.Verb == "get"))
+				return true, accessReview, nil
+			})
+			levels := NewLevels(false, map[string]int{"cloudmap": 1})
+
+			h := NewHandler(clientSet, levels, logr.New(&log.NullLogSink{}))
+			req := httptest.NewRequest(tt.method, "/debug/loglevel", strings.NewReader(tt.body))
+			if len(tt.token) != 0 {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantBody, recorder.Body.String())
+			assert.Equal(t, tt.wantDebug, levels.Debug())
+			assert.Equal(t, tt.wantLoggerVerbosity, levels.LoggerVerbosity())
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// LevelInfo logs V(0) messages.
+	LevelInfo = "info"
+	// LevelDebug logs messages up to V(1).
+	LevelDebug = "debug"
+)
+
+// Levels holds the log level of the controller and the verbosity of loggers overriding it, which can be changed at runtime.
+// loggers are named with logr's WithName, e.g. controllers.VirtualRouter.
+type Levels struct {
+	zapLevel zap.AtomicLevel
+	current  atomic.Pointer[levelsSnapshot]
+}
+
+// levelsSnapshot is an immutable state of Levels, so that loggers check it without locking.
+type levelsSnapshot struct {
+	debug           bool
+	loggerVerbosity map[string]int
+}
+
+// NewLevels constructs new Levels.
+func NewLevels(debug bool, loggerVerbosity map[string]int) *Levels {
+	l := &Levels{zapLevel: zap.NewAtomicLevel()}
+	l.set(levelsSnapshot{debug: debug, loggerVerbosity: loggerVerbosity})
+	return l
+}
+
+// ZapLevel is the level of the zap logger the LogSink of NewLogSink wraps.
+// it's kept at the highest verbosity of any logger, since the zap logger isn't aware of logger names.
+func (l *Levels) ZapLevel() zapcore.LevelEnabler {
+	return l.zapLevel
+}
+
+// NewLogSink wraps the initialized sink of a zap logger, e.g. of logr.Logger's GetSink, so that loggers log messages up to their verbosity.
+func (l *Levels) NewLogSink(sink logr.LogSink) logr.LogSink {
+	// the methods of levelsLogSink add a frame to the callers of sink.
+	if callDepthSink, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = callDepthSink.WithCallDepth(1)
+	}
+	return &levelsLogSink{sink: sink, levels: l}
+}
+
+// Debug returns whether the controller logs at debug level.
+func (l *Levels) Debug() bool {
+	return l.current.Load().debug
+}
+
+// LoggerVerbosity returns the verbosity of loggers overriding the log level of the controller.
+func (l *Levels) LoggerVerbosity() map[string]int {
+	loggerVerbosity := make(map[string]int)
+	for name, verbosity := range l.current.Load().loggerVerbosity {
+		loggerVerbosity[name] = verbosity
+	}
+	return loggerVerbosity
+}
+
+// SetDebug sets whether the controller logs at debug level, apart from loggers with a verbosity.
+func (l *Levels) SetDebug(debug bool) {
+	current := *l.current.Load()
+	current.debug = debug
+	l.set(current)
+}
+
+// SetLoggerVerbosity sets the verbosity of loggers, replacing the current one.
+// loggers with any of the names log messages up to the highest verbosity of their names regardless of the log level of the controller,
+// so that they can be silenced as well.
+func (l *Levels) SetLoggerVerbosity(loggerVerbosity map[string]int) {
+	current := *l.current.Load()
+	current.loggerVerbosity = make(map[string]int, len(loggerVerbosity))
+	for name, verbosity := range loggerVerbosity {
+		current.loggerVerbosity[strings.ToLower(name)] = verbosity
+	}
+	l.set(current)
+}
+
+func (l *Levels) set(snapshot levelsSnapshot) {
+	maxVerbosity := snapshot.verbosity("", nil)
+	for _, verbosity := range snapshot.loggerVerbosity {
+		if verbosity > maxVerbosity {
+			maxVerbosity = verbosity
+		}
+	}
+	l.current.Store(&snapshot)
+	// logr V(n) messages are logged at zap level -n.
+	l.zapLevel.SetLevel(zapcore.Level(-maxVerbosity))
+}
+
+// verbosity returns the verbosity of the logger with fullName, and names its lowercased components.
+// a logger matches the verbosity of its full dotted name, e.g. controllers.virtualrouter, or of any of its names, e.g. virtualrouter.
+func (s *levelsSnapshot) verbosity(fullName string, names []string) int {
+	verbosity, matched := 0, false
+	match := func(name string) {
+		if v, ok := s.loggerVerbosity[name]; ok && (!matched || v > verbosity) {
+			verbosity, matched = v, true
+		}
+	}
+	if len(names) > 1 {
+		match(fullName)
+	}
+	for _, name := range names {
+		match(name)
+	}
+	if matched {
+		return verbosity
+	}
+	if s.debug {
+		return 1
+	}
+	return 0
+}
+
+var _ logr.LogSink = &levelsLogSink{}
+var _ logr.CallDepthLogSink = &levelsLogSink{}
+
+// levelsLogSink drops messages more verbose than the verbosity of its logger, before the wrapped sink checks its zap level.
+type levelsLogSink struct {
+	sink     logr.LogSink
+	levels   *Levels
+	fullName string
+	names    []string
+}
+
+// Init does nothing, the wrapped sink is already initialized.
+func (s *levelsLogSink) Init(_ logr.RuntimeInfo) {
+}
+
+func (s *levelsLogSink) Enabled(level int) bool {
+	return level <= s.levels.current.Load().verbosity(s.fullName, s.names) && s.sink.Enabled(level)
+}
+
+func (s *levelsLogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *levelsLogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *levelsLogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &levelsLogSink{sink: s.sink.WithValues(keysAndValues...), levels: s.levels, fullName: s.fullName, names: s.names}
+}
+
+func (s *levelsLogSink) WithName(name string) logr.LogSink {
+	names := make([]string, len(s.names), len(s.names)+1)
+	copy(names, s.names)
+	names = append(names, strings.ToLower(name))
+	return &levelsLogSink{sink: s.sink.WithName(name), levels: s.levels, fullName: strings.Join(names, "."), names: names}
+}
+
+func (s *levelsLogSink) WithCallDepth(depth int) logr.LogSink {
+	sink, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &levelsLogSink{sink: sink.WithCallDepth(depth), levels: s.levels, fullName: s.fullName, names: s.names}
+}
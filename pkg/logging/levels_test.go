@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name            string
+		debug           bool
+		loggerVerbosity map[string]int
+		wantLogged      []string
+		wantZapLevel    zapcore.Level
+	}{
+		{
+			name:         "info",
+			wantLogged:   []string{"setup V(0)", "controllers.VirtualRouter V(0)", "virtualrouter V(0)", "controllers.VirtualNode V(0)"},
+			wantZapLevel: zapcore.InfoLevel,
+		},
+		{
+			name:         "debug",
+			debug:        true,
+			wantLogged:   []string{"setup V(0)", "setup V(1)", "controllers.VirtualRouter V(0)", "controllers.VirtualRouter V(1)", "virtualrouter V(0)", "virtualrouter V(1)", "controllers.VirtualNode V(0)", "controllers.VirtualNode V(1)"},
+			wantZapLevel: zapcore.DebugLevel,
+		},
+		{
+			name:            "verbose loggers with matching names",
+			loggerVerbosity: map[string]int{"virtualrouter": 2},
+			wantLogged:      []string{"setup V(0)", "controllers.VirtualRouter V(0)", "controllers.VirtualRouter V(1)", "controllers.VirtualRouter V(2)", "virtualrouter V(0)", "virtualrouter V(1)", "virtualrouter V(2)", "controllers.VirtualNode V(0)"},
+			wantZapLevel:    zapcore.Level(-2),
+		},
+		{
+			name:            "verbose logger with full name",
+			loggerVerbosity: map[string]int{"controllers.virtualrouter": 1},
+			wantLogged:      []string{"setup V(0)", "controllers.VirtualRouter V(0)", "controllers.VirtualRouter V(1)", "virtualrouter V(0)", "controllers.VirtualNode V(0)"},
+			wantZapLevel:    zapcore.DebugLevel,
+		},
+		{
+			name:            "highest verbosity of matching names",
+			loggerVerbosity: map[string]int{"controllers": 1, "virtualnode": 0},
+			wantLogged:      []string{"setup V(0)", "controllers.VirtualRouter V(0)", "controllers.VirtualRouter V(1)", "virtualrouter V(0)", "controllers.VirtualNode V(0)", "controllers.VirtualNode V(1)"},
+			wantZapLevel:    zapcore.DebugLevel,
+		},
+		{
+			name:            "debug silenced for logger",
+			debug:           true,
+			loggerVerbosity: map[string]int{"virtualrouter": 0},
+			wantLogged:      []string{"setup V(0)", "setup V(1)", "controllers.VirtualRouter V(0)", "virtualrouter V(0)", "controllers.VirtualNode V(0)", "controllers.VirtualNode V(1)"},
+			wantZapLevel:    zapcore.DebugLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingLogSink{}
+			// changes at runtime apply to loggers created before.
+			levels := NewLevels(false, nil)
+			logger := logr.New(levels.NewLogSink(sink))
+			loggers := map[string]logr.Logger{
+				"setup":                     logger.WithName("setup"),
+				"controllers.VirtualRouter": logger.WithName("controllers").WithName("VirtualRouter"),
+				"virtualrouter":             logger.WithName("virtualrouter").WithValues("key", "value"),
+				"controllers.VirtualNode":   logger.WithName("controllers").WithName("VirtualNode"),
+			}
+			levels.SetDebug(tt.debug)
+			levels.SetLoggerVerbosity(tt.loggerVerbosity)
+
+			for _, name := range []string{"setup", "controllers.VirtualRouter", "virtualrouter", "controllers.VirtualNode"} {
+				for v := 0; v <= 3; v++ {
+					loggers[name].V(v).Info(fmt.Sprintf("%s V(%d)", name, v))
+				}
+			}
+			assert.Equal(t, tt.wantLogged, sink.messages)
+			assert.True(t, levels.ZapLevel().Enabled(tt.wantZapLevel))
+			assert.False(t, levels.ZapLevel().Enabled(tt.wantZapLevel-1))
+		})
+	}
+}
+
+// recordingLogSink records the messages of all its loggers.
+type recordingLogSink struct {
+	messages []string
+}
+
+func (s *recordingLogSink) Init(_ logr.RuntimeInfo) {}
+
+func (s *recordingLogSink) Enabled(_ int) bool {
+	return true
+}
+
+func (s *recordingLogSink) Info(_ int, msg string, _ ...interface{}) {
+	s.messages = append(s.messages, msg)
+}
+
+func (s *recordingLogSink) Error(_ error, msg string, _ ...interface{}) {
+	s.messages = append(s.messages, msg)
+}
+
+func (s *recordingLogSink) WithValues(_ ...interface{}) logr.LogSink {
+	return s
+}
+
+func (s *recordingLogSink) WithName(_ string) logr.LogSink {
+	return s
+}
@@ -0,0 +1,46 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeferChanges records changes of obj deferred until the maintenance window starting at until, in the ChangesDeferred condition
+// among conditions of obj's status. It returns a ChangesDeferredError requeueing obj once the window starts.
+func DeferChanges(ctx context.Context, k8sClient client.Client, obj client.Object, conditions *[]metav1.Condition, changes []string, until time.Time) error {
+	deferredErr := &ChangesDeferredError{Changes: changes, Until: until}
+	oldObj := obj.DeepCopyObject().(client.Object)
+	if k8s.SetStatusCondition(conditions, metav1.Condition{
+		Type:               appmesh.ConditionChangesDeferred,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             appmesh.ReasonOutsideMaintenanceWindow,
+		Message:            deferredErr.Error(),
+	}) {
+		if err := k8sClient.Status().Patch(ctx, obj, client.MergeFrom(oldObj)); err != nil {
+			return err
+		}
+	}
+	return runtime.NewRequeueAfterError(deferredErr, time.Until(until))
+}
+
+// SetChangesApplied sets the ChangesDeferred condition among conditions to False once the changes of a resource at generation are applied.
+// the condition is only set if changes were deferred before. returns whether conditions are changed.
+func SetChangesApplied(generation int64, conditions *[]metav1.Condition) bool {
+	if meta.FindStatusCondition(*conditions, appmesh.ConditionChangesDeferred) == nil {
+		return false
+	}
+	return k8s.SetStatusCondition(conditions, metav1.Condition{
+		Type:               appmesh.ConditionChangesDeferred,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             appmesh.ReasonChangesApplied,
+	})
+}
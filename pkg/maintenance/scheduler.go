@@ -0,0 +1,117 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WindowAnnotation overrides the maintenance window of the mesh for its members in a namespace,
+	// as JSON of a MaintenanceWindow, e.g. {"days":["Sat"],"start":"02:00","duration":"2h"}.
+	WindowAnnotation = "appmesh.k8s.aws/maintenanceWindow"
+	// WindowNone is the value of WindowAnnotation applying disruptive changes in the namespace immediately.
+	WindowNone = "none"
+)
+
+// Scheduler schedules disruptive changes of mesh members into their maintenance window.
+type Scheduler interface {
+	// DeferUntil returns the time disruptive changes of obj, a member of ms, are deferred until,
+	// or zero time if they can be applied now.
+	DeferUntil(ctx context.Context, ms *appmesh.Mesh, obj client.Object) (time.Time, error)
+}
+
+// NewDefaultScheduler constructs new Scheduler, which takes maintenance windows from namespace annotations, then from meshes.
+func NewDefaultScheduler(k8sClient client.Reader) *defaultScheduler {
+	return &defaultScheduler{
+		k8sClient: k8sClient,
+		nowFunc:   time.Now,
+	}
+}
+
+var _ Scheduler = &defaultScheduler{}
+
+type defaultScheduler struct {
+	k8sClient client.Reader
+	nowFunc   func() time.Time
+}
+
+func (s *defaultScheduler) DeferUntil(ctx context.Context, ms *appmesh.Mesh, obj client.Object) (time.Time, error) {
+	window, err := s.findWindow(ctx, ms, obj.GetNamespace())
+	if err != nil || window == nil {
+		return time.Time{}, err
+	}
+	now := s.nowFunc()
+	if window.Contains(now) {
+		return time.Time{}, nil
+	}
+	return window.NextStart(now), nil
+}
+
+// findWindow finds the maintenance window of members of ms in namespace, returns nil if there's no window.
+func (s *defaultScheduler) findWindow(ctx context.Context, ms *appmesh.Mesh, namespace string) (*Window, error) {
+	ns := &corev1.Namespace{}
+	if err := s.k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	if value, ok := ns.Annotations[WindowAnnotation]; ok {
+		if strings.TrimSpace(value) == WindowNone {
+			return nil, nil
+		}
+		spec := appmesh.MaintenanceWindow{}
+		if err := json.Unmarshal([]byte(value), &spec); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse annotation %s of namespace %s", WindowAnnotation, namespace)
+		}
+		window, err := ParseWindow(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "annotation %s of namespace %s", WindowAnnotation, namespace)
+		}
+		return window, nil
+	}
+	if ms.Spec.MaintenanceWindow == nil {
+		return nil, nil
+	}
+	window, err := ParseWindow(*ms.Spec.MaintenanceWindow)
+	if err != nil {
+		return nil, errors.Wrapf(err, "maintenanceWindow of mesh %s", ms.Name)
+	}
+	return window, nil
+}
+
+// ChangesDeferredError indicates disruptive changes of a resource are deferred until its maintenance window.
+type ChangesDeferredError struct {
+	// Changes describes the deferred changes.
+	Changes []string
+	// Until is the start of the maintenance window the changes are deferred until.
+	Until time.Time
+}
+
+func (e *ChangesDeferredError) Error() string {
+	return fmt.Sprintf("%s deferred until maintenance window at %s", strings.Join(e.Changes, ", "), e.Until.UTC().Format(time.RFC3339))
+}
+
+// IsChangesDeferredError tests whether err is a ChangesDeferredError.
+func IsChangesDeferredError(err error) bool {
+	var deferredErr *ChangesDeferredError
+	return errors.As(err, &deferredErr)
+}
+
+// NewNoopScheduler constructs new Scheduler that never defers changes.
+func NewNoopScheduler() *noopScheduler {
+	return &noopScheduler{}
+}
+
+var _ Scheduler = &noopScheduler{}
+
+type noopScheduler struct{}
+
+func (s *noopScheduler) DeferUntil(ctx context.Context, ms *appmesh.Mesh, obj client.Object) (time.Time, error) {
+	return time.Time{}, nil
+}
@@ -0,0 +1,84 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_defaultScheduler_DeferUntil(t *testing.T) {
+	// 2023-06-03 is a Saturday.
+	now := time.Date(2023, 6, 3, 12, 0, 0, 0, time.UTC)
+	meshWindow := &appmesh.MaintenanceWindow{
+		Days:     []appmesh.MaintenanceWindowDay{"Sun"},
+		Start:    "02:00",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}
+	tests := []struct {
+		name        string
+		meshWindow  *appmesh.MaintenanceWindow
+		annotations map[string]string
+		want        time.Time
+		wantErr     string
+	}{
+		{
+			name: "no window",
+			want: time.Time{},
+		},
+		{
+			name:       "outside mesh window",
+			meshWindow: meshWindow,
+			want:       time.Date(2023, 6, 4, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "inside namespace window overriding mesh window",
+			meshWindow:  meshWindow,
+			annotations: map[string]string{WindowAnnotation: `{"days":["Sat"],"start":"11:00","duration":"2h"}`},
+			want:        time.Time{},
+		},
+		{
+			name:        "namespace opted out of mesh window",
+			meshWindow:  meshWindow,
+			annotations: map[string]string{WindowAnnotation: WindowNone},
+			want:        time.Time{},
+		},
+		{
+			name:        "malformed namespace window",
+			meshWindow:  meshWindow,
+			annotations: map[string]string{WindowAnnotation: `{"start":"11:00"}`},
+			wantErr:     "annotation appmesh.k8s.aws/maintenanceWindow of namespace my-ns: invalid maintenance window duration 0s, must be positive and at most 168h0m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns", Annotations: tt.annotations}}
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithRuntimeObjects(ns).Build()
+			s := NewDefaultScheduler(k8sClient)
+			s.nowFunc = func() time.Time { return now }
+
+			ms := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{MaintenanceWindow: tt.meshWindow},
+			}
+			vn := &appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vn"}}
+			got, err := s.DeferUntil(context.Background(), ms, vn)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tt.want.Equal(got), "got %v", got)
+			}
+		})
+	}
+}
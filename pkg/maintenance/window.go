@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"fmt"
+	"time"
+	// IANA time zones of maintenance windows are resolved without relying on the zoneinfo of the image.
+	_ "time/tzdata"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+)
+
+// maxWindowDuration is the maximum duration of a maintenance window, which recurs at least weekly.
+const maxWindowDuration = 7 * 24 * time.Hour
+
+var weekdayByDay = map[appmesh.MaintenanceWindowDay]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// Window is a parsed maintenance window.
+type Window struct {
+	// days the window starts on, the window starts every day if it's empty.
+	days map[time.Weekday]bool
+	// hour and minute of day the window starts at.
+	hour   int
+	minute int
+	// duration of the window.
+	duration time.Duration
+	// location of the start of the window.
+	location *time.Location
+}
+
+// ParseWindow parses and validates spec.
+func ParseWindow(spec appmesh.MaintenanceWindow) (*Window, error) {
+	w := &Window{
+		days:     make(map[time.Weekday]bool, len(spec.Days)),
+		duration: spec.Duration.Duration,
+		location: time.UTC,
+	}
+	for _, day := range spec.Days {
+		weekday, ok := weekdayByDay[day]
+		if !ok {
+			return nil, errors.Errorf("invalid maintenance window day %q, must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun", day)
+		}
+		w.days[weekday] = true
+	}
+	if _, err := fmt.Sscanf(spec.Start, "%02d:%02d", &w.hour, &w.minute); err != nil || len(spec.Start) != len("HH:MM") ||
+		w.hour < 0 || w.hour > 23 || w.minute < 0 || w.minute > 59 {
+		return nil, errors.Errorf("invalid maintenance window start %q, must be in the form of HH:MM", spec.Start)
+	}
+	if w.duration <= 0 || w.duration > maxWindowDuration {
+		return nil, errors.Errorf("invalid maintenance window duration %v, must be positive and at most %v", w.duration, maxWindowDuration)
+	}
+	if spec.TimeZone != nil {
+		location, err := time.LoadLocation(*spec.TimeZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid maintenance window timeZone %q", *spec.TimeZone)
+		}
+		w.location = location
+	}
+	return w, nil
+}
+
+// Contains tests whether the window is open at t.
+func (w *Window) Contains(t time.Time) bool {
+	// windows started on previous days may still be open.
+	for offset := -int(maxWindowDuration / (24 * time.Hour)); offset <= 0; offset++ {
+		start, ok := w.startOnDay(t, offset)
+		if ok && !t.Before(start) && t.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStart returns the first start of the window after t.
+func (w *Window) NextStart(t time.Time) time.Time {
+	for offset := 0; offset <= 7; offset++ {
+		start, ok := w.startOnDay(t, offset)
+		if ok && start.After(t) {
+			return start
+		}
+	}
+	// unreachable, the window starts at least weekly.
+	return t.Add(maxWindowDuration)
+}
+
+// startOnDay returns the start of the window on the day offset days from t in the window's location,
+// returns false if the window doesn't start on that day.
+func (w *Window) startOnDay(t time.Time, offset int) (time.Time, bool) {
+	local := t.In(w.location)
+	start := time.Date(local.Year(), local.Month(), local.Day()+offset, w.hour, w.minute, 0, 0, w.location)
+	if len(w.days) != 0 && !w.days[start.Weekday()] {
+		return time.Time{}, false
+	}
+	return start, true
+}
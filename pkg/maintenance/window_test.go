@@ -0,0 +1,162 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    appmesh.MaintenanceWindow
+		wantErr string
+	}{
+		{
+			name: "valid window",
+			spec: appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Sat", "Sun"},
+				Start:    "02:30",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+				TimeZone: aws.String("Europe/Berlin"),
+			},
+		},
+		{
+			name: "invalid day",
+			spec: appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Saturday"},
+				Start:    "02:30",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+			},
+			wantErr: `invalid maintenance window day "Saturday", must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun`,
+		},
+		{
+			name: "invalid start",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "24:00",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+			},
+			wantErr: `invalid maintenance window start "24:00", must be in the form of HH:MM`,
+		},
+		{
+			name: "start without leading zero",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "2:30",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+			},
+			wantErr: `invalid maintenance window start "2:30", must be in the form of HH:MM`,
+		},
+		{
+			name: "zero duration",
+			spec: appmesh.MaintenanceWindow{
+				Start: "02:30",
+			},
+			wantErr: "invalid maintenance window duration 0s, must be positive and at most 168h0m0s",
+		},
+		{
+			name: "duration longer than a week",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "02:30",
+				Duration: metav1.Duration{Duration: 8 * 24 * time.Hour},
+			},
+			wantErr: "invalid maintenance window duration 192h0m0s, must be positive and at most 168h0m0s",
+		},
+		{
+			name: "invalid time zone",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "02:30",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+				TimeZone: aws.String("Mars/Olympus"),
+			},
+			wantErr: `invalid maintenance window timeZone "Mars/Olympus": unknown time zone Mars/Olympus`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWindow(tt.spec)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWindow_ContainsAndNextStart(t *testing.T) {
+	// 2023-06-03 is a Saturday.
+	tests := []struct {
+		name          string
+		spec          appmesh.MaintenanceWindow
+		now           time.Time
+		wantContains  bool
+		wantNextStart time.Time
+	}{
+		{
+			name: "daily window open",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "02:00",
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+			now:           time.Date(2023, 6, 3, 2, 30, 0, 0, time.UTC),
+			wantContains:  true,
+			wantNextStart: time.Date(2023, 6, 4, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily window closed at its end",
+			spec: appmesh.MaintenanceWindow{
+				Start:    "02:00",
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+			now:           time.Date(2023, 6, 3, 3, 0, 0, 0, time.UTC),
+			wantContains:  false,
+			wantNextStart: time.Date(2023, 6, 4, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "window across midnight open on next day",
+			spec: appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Fri"},
+				Start:    "23:00",
+				Duration: metav1.Duration{Duration: 3 * time.Hour},
+			},
+			now:           time.Date(2023, 6, 3, 1, 0, 0, 0, time.UTC),
+			wantContains:  true,
+			wantNextStart: time.Date(2023, 6, 9, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekly window closed",
+			spec: appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Mon", "Wed"},
+				Start:    "10:00",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+			},
+			now:           time.Date(2023, 6, 3, 11, 0, 0, 0, time.UTC),
+			wantContains:  false,
+			wantNextStart: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "window in time zone",
+			spec: appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Sat"},
+				Start:    "04:00",
+				Duration: metav1.Duration{Duration: time.Hour},
+				TimeZone: aws.String("Europe/Berlin"),
+			},
+			now:           time.Date(2023, 6, 3, 2, 30, 0, 0, time.UTC),
+			wantContains:  true,
+			wantNextStart: time.Date(2023, 6, 10, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWindow(tt.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContains, w.Contains(tt.now))
+			assert.True(t, tt.wantNextStart.Equal(w.NextStart(tt.now)), "got next start %v", w.NextStart(tt.now))
+		})
+	}
+}
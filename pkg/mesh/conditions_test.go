@@ -1,269 +1,83 @@
 package mesh
 
 import (
+	"testing"
+
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"testing"
 )
 
-func Test_getCondition(t *testing.T) {
-	type args struct {
-		ms            *appmesh.Mesh
-		conditionType appmesh.MeshConditionType
-	}
-	tests := []struct {
-		name string
-		args args
-		want *appmesh.MeshCondition
-	}{
-		{
-			name: "condition found",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-			},
-			want: &appmesh.MeshCondition{
-				Type:   appmesh.MeshActive,
-				Status: corev1.ConditionFalse,
-			},
-		},
-		{
-			name: "condition not found",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-			},
-			want: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := getCondition(tt.args.ms, tt.args.conditionType)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
-func Test_updateCondition(t *testing.T) {
+func Test_updateConditionsForReconciled(t *testing.T) {
 	type args struct {
-		ms            *appmesh.Mesh
-		conditionType appmesh.MeshConditionType
-		status        corev1.ConditionStatus
-		reason        *string
-		message       *string
+		ms     *appmesh.Mesh
+		active bool
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantMS      *appmesh.Mesh
-		wantChanged bool
+		name           string
+		args           args
+		wantConditions []metav1.Condition
+		wantChanged    bool
 	}{
 		{
-			name: "condition updated by modify condition status",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition reason",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
-							Reason: aws.String("reason"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition message",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-				message:       aws.String("message"),
-			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:    appmesh.MeshActive,
-							Status:  corev1.ConditionTrue,
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition status/reason/message",
+			name: "active mesh",
 			args: args{
 				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: true,
 			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:    appmesh.MeshActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.MeshActive, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
 			},
 			wantChanged: true,
 		},
 		{
-			name: "condition updated by new condition",
+			name: "inactive mesh",
 			args: args{
 				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: nil,
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: false,
 			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:    appmesh.MeshActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.MeshActive, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
 			},
 			wantChanged: true,
 		},
-		{
-			name: "condition unmodified",
-			args: args{
-				ms: &appmesh.Mesh{
-					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
-							{
-								Type:    appmesh.MeshActive,
-								Status:  corev1.ConditionTrue,
-								Reason:  aws.String("reason"),
-								Message: aws.String("message"),
-							},
-						},
-					},
-				},
-				conditionType: appmesh.MeshActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
-			},
-			wantMS: &appmesh.Mesh{
-				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
-						{
-							Type:    appmesh.MeshActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: false,
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotChanged := updateCondition(tt.args.ms, tt.args.conditionType, tt.args.status, tt.args.reason, tt.args.message)
-			opts := cmpopts.IgnoreTypes((*metav1.Time)(nil))
-			assert.True(t, cmp.Equal(tt.wantMS, tt.args.ms, opts), "diff", cmp.Diff(tt.wantMS, tt.args.ms, opts))
+			gotChanged := updateConditionsForReconciled(tt.args.ms, tt.args.active)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, tt.args.ms.Status.Conditions, opts), "diff", cmp.Diff(tt.wantConditions, tt.args.ms.Status.Conditions, opts))
 			assert.Equal(t, tt.wantChanged, gotChanged)
 		})
 	}
 }
+
+func Test_updateConditionsForFailure(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+	}
+	gotChanged := updateConditionsForFailure(ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, errors.New("oops"))
+	wantConditions := []metav1.Condition{
+		{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+		{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+	}
+	opts := cmpopts.IgnoreTypes(metav1.Time{})
+	assert.True(t, cmp.Equal(wantConditions, ms.Status.Conditions, opts), "diff", cmp.Diff(wantConditions, ms.Status.Conditions, opts))
+	assert.True(t, gotChanged)
+}
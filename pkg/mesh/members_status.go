@@ -0,0 +1,112 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MembersStatusAggregator aggregates the status of mesh members.
+type MembersStatusAggregator interface {
+	// Aggregate counts VirtualNodes, VirtualRouters, VirtualServices and VirtualGateways of mesh by their Ready condition.
+	Aggregate(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshMembersStatus, error)
+}
+
+// NewDefaultMembersStatusAggregator constructs new MembersStatusAggregator
+func NewDefaultMembersStatusAggregator(k8sClient client.Client) MembersStatusAggregator {
+	return &defaultMembersStatusAggregator{
+		k8sClient: k8sClient,
+	}
+}
+
+var _ MembersStatusAggregator = &defaultMembersStatusAggregator{}
+
+// defaultMembersStatusAggregator implements MembersStatusAggregator by listing members of all namespaces.
+type defaultMembersStatusAggregator struct {
+	k8sClient client.Client
+}
+
+func (a *defaultMembersStatusAggregator) Aggregate(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshMembersStatus, error) {
+	status := &appmesh.MeshMembersStatus{}
+
+	vnList := &appmesh.VirtualNodeList{}
+	if err := a.k8sClient.List(ctx, vnList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualNodes")
+	}
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		if vn.Spec.MeshRef != nil && IsMeshReferenced(ms, *vn.Spec.MeshRef) {
+			countMember(&status.VirtualNodes, vn, vn.Status.Conditions)
+		}
+	}
+
+	vrList := &appmesh.VirtualRouterList{}
+	if err := a.k8sClient.List(ctx, vrList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualRouters")
+	}
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		if vr.Spec.MeshRef != nil && IsMeshReferenced(ms, *vr.Spec.MeshRef) {
+			countMember(&status.VirtualRouters, vr, vr.Status.Conditions)
+		}
+	}
+
+	vsList := &appmesh.VirtualServiceList{}
+	if err := a.k8sClient.List(ctx, vsList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualServices")
+	}
+	for i := range vsList.Items {
+		vs := &vsList.Items[i]
+		if vs.Spec.MeshRef != nil && IsMeshReferenced(ms, *vs.Spec.MeshRef) {
+			countMember(&status.VirtualServices, vs, vs.Status.Conditions)
+		}
+	}
+
+	vgList := &appmesh.VirtualGatewayList{}
+	if err := a.k8sClient.List(ctx, vgList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualGateways")
+	}
+	for i := range vgList.Items {
+		vg := &vgList.Items[i]
+		if vg.Spec.MeshRef != nil && IsMeshReferenced(ms, *vg.Spec.MeshRef) {
+			countMember(&status.VirtualGateways, vg, vg.Status.Conditions)
+		}
+	}
+
+	var total, ready int32
+	for _, counts := range []appmesh.MeshMemberCounts{status.VirtualNodes, status.VirtualRouters, status.VirtualServices, status.VirtualGateways} {
+		total += counts.Total
+		ready += counts.Ready
+		status.Errored += counts.Errored
+	}
+	status.Ready = fmt.Sprintf("%d/%d", ready, total)
+
+	if ready == total {
+		now := metav1.Now()
+		status.LastFullReconcileTime = &now
+	} else if ms.Status.Members != nil {
+		status.LastFullReconcileTime = ms.Status.Members.LastFullReconcileTime
+	}
+	return status, nil
+}
+
+// countMember counts member by its Ready condition.
+// members whose Ready condition isn't reported for their latest generation yet are neither ready nor errored.
+func countMember(counts *appmesh.MeshMemberCounts, member metav1.Object, conditions []metav1.Condition) {
+	counts.Total++
+	condition := meta.FindStatusCondition(conditions, appmesh.ConditionReady)
+	if condition == nil || condition.ObservedGeneration != member.GetGeneration() {
+		return
+	}
+	switch condition.Status {
+	case metav1.ConditionTrue:
+		counts.Ready++
+	case metav1.ConditionFalse:
+		counts.Errored++
+	}
+}
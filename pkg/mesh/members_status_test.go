@@ -0,0 +1,160 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_countMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		conditions []metav1.Condition
+		want       appmesh.MeshMemberCounts
+	}{
+		{
+			name:       "member without Ready condition",
+			generation: 1,
+			want:       appmesh.MeshMemberCounts{Total: 1},
+		},
+		{
+			name:       "member ready for its latest generation",
+			generation: 2,
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			},
+			want: appmesh.MeshMemberCounts{Total: 1, Ready: 1},
+		},
+		{
+			name:       "member errored for its latest generation",
+			generation: 2,
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2},
+			},
+			want: appmesh.MeshMemberCounts{Total: 1, Errored: 1},
+		},
+		{
+			name:       "member ready for a previous generation",
+			generation: 3,
+			conditions: []metav1.Condition{
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			},
+			want: appmesh.MeshMemberCounts{Total: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member := &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+			}
+			got := appmesh.MeshMemberCounts{}
+			countMember(&got, member, tt.conditions)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultMembersStatusAggregator_Aggregate(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "uid-1"},
+	}
+	msRef := &appmesh.MeshReference{Name: "my-mesh", UID: "uid-1"}
+	otherMSRef := &appmesh.MeshReference{Name: "other-mesh", UID: "uid-2"}
+	ready := []metav1.Condition{{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 1}}
+	errored := []metav1.Condition{{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 1}}
+	objectMeta := func(namespace string, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name, Generation: 1}
+	}
+	lastFullReconcileTime := metav1.Unix(1600000000, 0)
+
+	tests := []struct {
+		name                      string
+		previous                  *appmesh.MeshMembersStatus
+		members                   []client.Object
+		want                      *appmesh.MeshMembersStatus
+		wantFullReconcileAdvanced bool
+	}{
+		{
+			name: "mesh with ready, errored and pending members",
+			previous: &appmesh.MeshMembersStatus{
+				LastFullReconcileTime: &lastFullReconcileTime,
+			},
+			members: []client.Object{
+				&appmesh.VirtualNode{ObjectMeta: objectMeta("ns-1", "vn-1"), Spec: appmesh.VirtualNodeSpec{MeshRef: msRef}, Status: appmesh.VirtualNodeStatus{Conditions: ready}},
+				&appmesh.VirtualNode{ObjectMeta: objectMeta("ns-2", "vn-2"), Spec: appmesh.VirtualNodeSpec{MeshRef: msRef}, Status: appmesh.VirtualNodeStatus{Conditions: errored}},
+				&appmesh.VirtualNode{ObjectMeta: objectMeta("ns-2", "vn-3"), Spec: appmesh.VirtualNodeSpec{MeshRef: otherMSRef}, Status: appmesh.VirtualNodeStatus{Conditions: errored}},
+				&appmesh.VirtualRouter{ObjectMeta: objectMeta("ns-1", "vr-1"), Spec: appmesh.VirtualRouterSpec{MeshRef: msRef}},
+				&appmesh.VirtualService{ObjectMeta: objectMeta("ns-1", "vs-1"), Spec: appmesh.VirtualServiceSpec{MeshRef: msRef}, Status: appmesh.VirtualServiceStatus{Conditions: ready}},
+				&appmesh.VirtualGateway{ObjectMeta: objectMeta("ns-1", "vg-1"), Spec: appmesh.VirtualGatewaySpec{MeshRef: msRef}, Status: appmesh.VirtualGatewayStatus{Conditions: ready}},
+			},
+			want: &appmesh.MeshMembersStatus{
+				VirtualNodes:          appmesh.MeshMemberCounts{Total: 2, Ready: 1, Errored: 1},
+				VirtualRouters:        appmesh.MeshMemberCounts{Total: 1},
+				VirtualServices:       appmesh.MeshMemberCounts{Total: 1, Ready: 1},
+				VirtualGateways:       appmesh.MeshMemberCounts{Total: 1, Ready: 1},
+				Ready:                 "3/5",
+				Errored:               1,
+				LastFullReconcileTime: &lastFullReconcileTime,
+			},
+		},
+		{
+			name: "mesh with all members ready",
+			previous: &appmesh.MeshMembersStatus{
+				LastFullReconcileTime: &lastFullReconcileTime,
+			},
+			members: []client.Object{
+				&appmesh.VirtualNode{ObjectMeta: objectMeta("ns-1", "vn-1"), Spec: appmesh.VirtualNodeSpec{MeshRef: msRef}, Status: appmesh.VirtualNodeStatus{Conditions: ready}},
+				&appmesh.VirtualRouter{ObjectMeta: objectMeta("ns-1", "vr-1"), Spec: appmesh.VirtualRouterSpec{MeshRef: msRef}, Status: appmesh.VirtualRouterStatus{Conditions: ready}},
+			},
+			want: &appmesh.MeshMembersStatus{
+				VirtualNodes:   appmesh.MeshMemberCounts{Total: 1, Ready: 1},
+				VirtualRouters: appmesh.MeshMemberCounts{Total: 1, Ready: 1},
+				Ready:          "2/2",
+			},
+			wantFullReconcileAdvanced: true,
+		},
+		{
+			name:     "mesh without previous members status and pending members",
+			previous: nil,
+			members: []client.Object{
+				&appmesh.VirtualNode{ObjectMeta: objectMeta("ns-1", "vn-1"), Spec: appmesh.VirtualNodeSpec{MeshRef: msRef}},
+			},
+			want: &appmesh.MeshMembersStatus{
+				VirtualNodes: appmesh.MeshMemberCounts{Total: 1},
+				Ready:        "0/1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			ctx := context.Background()
+			for _, member := range tt.members {
+				assert.NoError(t, k8sClient.Create(ctx, member))
+			}
+
+			mesh := ms.DeepCopy()
+			mesh.Status.Members = tt.previous
+			aggregator := NewDefaultMembersStatusAggregator(k8sClient)
+			got, err := aggregator.Aggregate(ctx, mesh)
+			assert.NoError(t, err)
+			if tt.wantFullReconcileAdvanced {
+				assert.NotNil(t, got.LastFullReconcileTime)
+				assert.True(t, got.LastFullReconcileTime.After(lastFullReconcileTime.Time))
+				got.LastFullReconcileTime = nil
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package mesh
+
+import (
+	"context"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+)
+
+const (
+	kindVirtualNode    = "VirtualNode"
+	kindVirtualRouter  = "VirtualRouter"
+	kindRoute          = "Route"
+	kindVirtualService = "VirtualService"
+	kindVirtualGateway = "VirtualGateway"
+	kindGatewayRoute   = "GatewayRoute"
+)
+
+// replicatedKind mirrors AppMesh resources of a kind between regions.
+// specs are passed around as the SDK spec type of the kind, e.g. *appmeshsdk.VirtualNodeSpec for VirtualNodes.
+type replicatedKind struct {
+	kind string
+	// parentKind is the kind resources of this kind are scoped to, e.g. Routes are scoped to VirtualRouters.
+	parentKind string
+
+	list     func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string) ([]string, error)
+	describe func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) (interface{}, error)
+	create   func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error
+	update   func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error
+	delete   func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) error
+}
+
+// replicatedKinds are ordered such that resources are created after the resources they reference,
+// e.g. a VirtualService is created after the VirtualRouter providing it.
+var replicatedKinds = []replicatedKind{
+	{
+		kind: kindVirtualNode,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string) ([]string, error) {
+			var names []string
+			err := sdk.ListVirtualNodesPagesWithContext(ctx, &appmeshsdk.ListVirtualNodesInput{MeshName: aws.String(meshName)},
+				func(page *appmeshsdk.ListVirtualNodesOutput, _ bool) bool {
+					for _, ref := range page.VirtualNodes {
+						names = append(names, aws.StringValue(ref.VirtualNodeName))
+					}
+					return true
+				})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeVirtualNodeWithContext(ctx, &appmeshsdk.DescribeVirtualNodeInput{
+				MeshName:        aws.String(meshName),
+				VirtualNodeName: aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.VirtualNode.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.CreateVirtualNodeWithContext(ctx, &appmeshsdk.CreateVirtualNodeInput{
+				MeshName:        aws.String(meshName),
+				VirtualNodeName: aws.String(name),
+				Spec:            spec.(*appmeshsdk.VirtualNodeSpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.UpdateVirtualNodeWithContext(ctx, &appmeshsdk.UpdateVirtualNodeInput{
+				MeshName:        aws.String(meshName),
+				VirtualNodeName: aws.String(name),
+				Spec:            spec.(*appmeshsdk.VirtualNodeSpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) error {
+			_, err := sdk.DeleteVirtualNodeWithContext(ctx, &appmeshsdk.DeleteVirtualNodeInput{
+				MeshName:        aws.String(meshName),
+				VirtualNodeName: aws.String(name),
+			})
+			return err
+		},
+	},
+	{
+		kind: kindVirtualRouter,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string) ([]string, error) {
+			var names []string
+			err := sdk.ListVirtualRoutersPagesWithContext(ctx, &appmeshsdk.ListVirtualRoutersInput{MeshName: aws.String(meshName)},
+				func(page *appmeshsdk.ListVirtualRoutersOutput, _ bool) bool {
+					for _, ref := range page.VirtualRouters {
+						names = append(names, aws.StringValue(ref.VirtualRouterName))
+					}
+					return true
+				})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeVirtualRouterWithContext(ctx, &appmeshsdk.DescribeVirtualRouterInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.VirtualRouter.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.CreateVirtualRouterWithContext(ctx, &appmeshsdk.CreateVirtualRouterInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(name),
+				Spec:              spec.(*appmeshsdk.VirtualRouterSpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.UpdateVirtualRouterWithContext(ctx, &appmeshsdk.UpdateVirtualRouterInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(name),
+				Spec:              spec.(*appmeshsdk.VirtualRouterSpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) error {
+			_, err := sdk.DeleteVirtualRouterWithContext(ctx, &appmeshsdk.DeleteVirtualRouterInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(name),
+			})
+			return err
+		},
+	},
+	{
+		kind:       kindRoute,
+		parentKind: kindVirtualRouter,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string) ([]string, error) {
+			var names []string
+			err := sdk.ListRoutesPagesWithContext(ctx, &appmeshsdk.ListRoutesInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(parentName),
+			}, func(page *appmeshsdk.ListRoutesOutput, _ bool) bool {
+				for _, ref := range page.Routes {
+					names = append(names, aws.StringValue(ref.RouteName))
+				}
+				return true
+			})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeRouteWithContext(ctx, &appmeshsdk.DescribeRouteInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(parentName),
+				RouteName:         aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.Route.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error {
+			_, err := sdk.CreateRouteWithContext(ctx, &appmeshsdk.CreateRouteInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(parentName),
+				RouteName:         aws.String(name),
+				Spec:              spec.(*appmeshsdk.RouteSpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error {
+			_, err := sdk.UpdateRouteWithContext(ctx, &appmeshsdk.UpdateRouteInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(parentName),
+				RouteName:         aws.String(name),
+				Spec:              spec.(*appmeshsdk.RouteSpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) error {
+			_, err := sdk.DeleteRouteWithContext(ctx, &appmeshsdk.DeleteRouteInput{
+				MeshName:          aws.String(meshName),
+				VirtualRouterName: aws.String(parentName),
+				RouteName:         aws.String(name),
+			})
+			return err
+		},
+	},
+	{
+		kind: kindVirtualService,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string) ([]string, error) {
+			var names []string
+			err := sdk.ListVirtualServicesPagesWithContext(ctx, &appmeshsdk.ListVirtualServicesInput{MeshName: aws.String(meshName)},
+				func(page *appmeshsdk.ListVirtualServicesOutput, _ bool) bool {
+					for _, ref := range page.VirtualServices {
+						names = append(names, aws.StringValue(ref.VirtualServiceName))
+					}
+					return true
+				})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeVirtualServiceWithContext(ctx, &appmeshsdk.DescribeVirtualServiceInput{
+				MeshName:           aws.String(meshName),
+				VirtualServiceName: aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.VirtualService.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.CreateVirtualServiceWithContext(ctx, &appmeshsdk.CreateVirtualServiceInput{
+				MeshName:           aws.String(meshName),
+				VirtualServiceName: aws.String(name),
+				Spec:               spec.(*appmeshsdk.VirtualServiceSpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.UpdateVirtualServiceWithContext(ctx, &appmeshsdk.UpdateVirtualServiceInput{
+				MeshName:           aws.String(meshName),
+				VirtualServiceName: aws.String(name),
+				Spec:               spec.(*appmeshsdk.VirtualServiceSpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) error {
+			_, err := sdk.DeleteVirtualServiceWithContext(ctx, &appmeshsdk.DeleteVirtualServiceInput{
+				MeshName:           aws.String(meshName),
+				VirtualServiceName: aws.String(name),
+			})
+			return err
+		},
+	},
+	{
+		kind: kindVirtualGateway,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string) ([]string, error) {
+			var names []string
+			err := sdk.ListVirtualGatewaysPagesWithContext(ctx, &appmeshsdk.ListVirtualGatewaysInput{MeshName: aws.String(meshName)},
+				func(page *appmeshsdk.ListVirtualGatewaysOutput, _ bool) bool {
+					for _, ref := range page.VirtualGateways {
+						names = append(names, aws.StringValue(ref.VirtualGatewayName))
+					}
+					return true
+				})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeVirtualGatewayWithContext(ctx, &appmeshsdk.DescribeVirtualGatewayInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.VirtualGateway.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.CreateVirtualGatewayWithContext(ctx, &appmeshsdk.CreateVirtualGatewayInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(name),
+				Spec:               spec.(*appmeshsdk.VirtualGatewaySpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string, spec interface{}) error {
+			_, err := sdk.UpdateVirtualGatewayWithContext(ctx, &appmeshsdk.UpdateVirtualGatewayInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(name),
+				Spec:               spec.(*appmeshsdk.VirtualGatewaySpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, _ string, name string) error {
+			_, err := sdk.DeleteVirtualGatewayWithContext(ctx, &appmeshsdk.DeleteVirtualGatewayInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(name),
+			})
+			return err
+		},
+	},
+	{
+		kind:       kindGatewayRoute,
+		parentKind: kindVirtualGateway,
+		list: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string) ([]string, error) {
+			var names []string
+			err := sdk.ListGatewayRoutesPagesWithContext(ctx, &appmeshsdk.ListGatewayRoutesInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(parentName),
+			}, func(page *appmeshsdk.ListGatewayRoutesOutput, _ bool) bool {
+				for _, ref := range page.GatewayRoutes {
+					names = append(names, aws.StringValue(ref.GatewayRouteName))
+				}
+				return true
+			})
+			return names, err
+		},
+		describe: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) (interface{}, error) {
+			resp, err := sdk.DescribeGatewayRouteWithContext(ctx, &appmeshsdk.DescribeGatewayRouteInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(parentName),
+				GatewayRouteName:   aws.String(name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return resp.GatewayRoute.Spec, nil
+		},
+		create: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error {
+			_, err := sdk.CreateGatewayRouteWithContext(ctx, &appmeshsdk.CreateGatewayRouteInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(parentName),
+				GatewayRouteName:   aws.String(name),
+				Spec:               spec.(*appmeshsdk.GatewayRouteSpec),
+			})
+			return err
+		},
+		update: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string, spec interface{}) error {
+			_, err := sdk.UpdateGatewayRouteWithContext(ctx, &appmeshsdk.UpdateGatewayRouteInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(parentName),
+				GatewayRouteName:   aws.String(name),
+				Spec:               spec.(*appmeshsdk.GatewayRouteSpec),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, sdk services.AppMesh, meshName string, parentName string, name string) error {
+			_, err := sdk.DeleteGatewayRouteWithContext(ctx, &appmeshsdk.DeleteGatewayRouteInput{
+				MeshName:           aws.String(meshName),
+				VirtualGatewayName: aws.String(parentName),
+				GatewayRouteName:   aws.String(name),
+			})
+			return err
+		},
+	},
+}
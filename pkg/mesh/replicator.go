@@ -0,0 +1,447 @@
+package mesh
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AppMeshForRegion provides API to AWS AppMesh in region, at endpoint if it's not empty.
+type AppMeshForRegion func(region string, endpoint string) services.AppMesh
+
+// Replicator mirrors AppMesh resources of meshes from the primary region into secondary regions.
+type Replicator interface {
+	// Replicate mirrors the AppMesh mesh of ms and all resources in it into each secondary region of ms,
+	// and records the replication status of each secondary region into ms.status.
+	Replicate(ctx context.Context, ms *appmesh.Mesh) error
+
+	// Cleanup deletes the AppMesh mesh of ms and all resources in it from each secondary region of ms,
+	// including regions removed from ms.spec that still have a replica in ms.status.
+	Cleanup(ctx context.Context, ms *appmesh.Mesh) error
+}
+
+// NewDefaultReplicator constructs new Replicator.
+// appMeshSDK is the AppMesh API of the primary region.
+func NewDefaultReplicator(k8sClient client.Client, appMeshSDK services.AppMesh, appMeshForRegion AppMeshForRegion, region string, accountID string, log logr.Logger) Replicator {
+	return &defaultReplicator{
+		k8sClient:        k8sClient,
+		appMeshSDK:       appMeshSDK,
+		appMeshForRegion: appMeshForRegion,
+		region:           region,
+		accountID:        accountID,
+		log:              log,
+		replicaSDKs:      make(map[replicaSDKKey]services.AppMesh),
+	}
+}
+
+var _ Replicator = &defaultReplicator{}
+
+// defaultReplicator implements Replicator.
+// resources are mirrored from their AppMesh spec in the primary region, so the replicas match what the controller reconciled.
+type defaultReplicator struct {
+	k8sClient        client.Client
+	appMeshSDK       services.AppMesh
+	appMeshForRegion AppMeshForRegion
+	region           string
+	accountID        string
+	log              logr.Logger
+
+	// replicaSDKs caches AppMesh API of secondary regions.
+	replicaSDKsMutex sync.Mutex
+	replicaSDKs      map[replicaSDKKey]services.AppMesh
+}
+
+type replicaSDKKey struct {
+	region   string
+	endpoint string
+}
+
+// replicatedResource is an AppMesh resource within a mesh.
+type replicatedResource struct {
+	kind       string
+	parentName string
+	name       string
+	// spec is the SDK spec of resource.
+	spec interface{}
+}
+
+// replicatedResourceKey identifies a replicatedResource within a mesh.
+type replicatedResourceKey struct {
+	kind       string
+	parentName string
+	name       string
+}
+
+func (r replicatedResource) key() replicatedResourceKey {
+	return replicatedResourceKey{kind: r.kind, parentName: r.parentName, name: r.name}
+}
+
+func (r *defaultReplicator) Replicate(ctx context.Context, ms *appmesh.Mesh) error {
+	replicas := r.replicate(ctx, ms)
+	// replicas only change when a region's synced, meshARN or message change, as the Mesh is reconciled again upon status patches.
+	if reflect.DeepEqual(replicas, ms.Status.Replicas) {
+		return nil
+	}
+	oldMS := ms.DeepCopy()
+	ms.Status.Replicas = replicas
+	return r.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS))
+}
+
+// replicate mirrors ms into each secondary region of ms, and returns the replication status of each secondary region.
+// replicas of regions removed from ms.spec are deleted, and kept in the status until they're deleted.
+func (r *defaultReplicator) replicate(ctx context.Context, ms *appmesh.Mesh) []appmesh.MeshReplicaStatus {
+	var replicas []appmesh.MeshReplicaStatus
+	if ms.Spec.Replication != nil {
+		replicas = r.replicateIntoRegions(ctx, ms)
+	}
+	for _, removedReplica := range r.removedReplicas(ms) {
+		if err := r.cleanupRegion(ctx, r.replicaSDK(appmesh.MeshReplicationRegion{Region: removedReplica.Region}), ms); err != nil {
+			r.log.Error(err, "failed to cleanup mesh of removed region",
+				"mesh", k8s.NamespacedName(ms),
+				"region", removedReplica.Region,
+			)
+			removedReplica.Synced = metav1.ConditionFalse
+			removedReplica.Message = aws.String(errors.Wrap(err, "failed to cleanup replica of region removed from spec.replication").Error())
+			replicas = append(replicas, removedReplica)
+		}
+	}
+	return replicas
+}
+
+// replicateIntoRegions mirrors ms into each region of ms.spec.replication, and returns their replication status.
+func (r *defaultReplicator) replicateIntoRegions(ctx context.Context, ms *appmesh.Mesh) []appmesh.MeshReplicaStatus {
+	meshName := aws.StringValue(ms.Spec.AWSName)
+	var sdkMS *appmeshsdk.MeshData
+	var resources []replicatedResource
+	err := r.validateReplication(ms)
+	if err == nil {
+		if sdkMS, err = r.findSDKMesh(ctx, r.appMeshSDK, meshName); err == nil && sdkMS == nil {
+			err = errors.Errorf("mesh %s doesn't exist in primary region %s", meshName, r.region)
+		}
+	}
+	if err == nil {
+		if resources, err = r.listResources(ctx, r.appMeshSDK, meshName); err != nil {
+			err = errors.Wrapf(err, "failed to list resources in primary region %s", r.region)
+		}
+	}
+
+	now := metav1.Now()
+	replicas := make([]appmesh.MeshReplicaStatus, 0, len(ms.Spec.Replication.Regions))
+	for _, replicationRegion := range ms.Spec.Replication.Regions {
+		replica := appmesh.MeshReplicaStatus{Region: replicationRegion.Region}
+		wasSynced := false
+		for _, oldReplica := range ms.Status.Replicas {
+			if oldReplica.Region == replicationRegion.Region {
+				replica.MeshARN = oldReplica.MeshARN
+				replica.LastSyncTime = oldReplica.LastSyncTime
+				wasSynced = oldReplica.Synced == metav1.ConditionTrue
+			}
+		}
+		replicaErr := err
+		if replicaErr == nil {
+			var replicaMeshARN *string
+			replicaMeshARN, replicaErr = r.replicateIntoRegion(ctx, r.replicaSDK(replicationRegion), ms, sdkMS, resources)
+			if replicaMeshARN != nil {
+				replica.MeshARN = replicaMeshARN
+			}
+		}
+		if replicaErr != nil {
+			r.log.Error(replicaErr, "failed to replicate mesh",
+				"mesh", k8s.NamespacedName(ms),
+				"region", replicationRegion.Region,
+			)
+			replica.Synced = metav1.ConditionFalse
+			replica.Message = aws.String(replicaErr.Error())
+		} else {
+			replica.Synced = metav1.ConditionTrue
+			if !wasSynced || replica.LastSyncTime == nil {
+				replica.LastSyncTime = &now
+			}
+		}
+		replicas = append(replicas, replica)
+	}
+	return replicas
+}
+
+func (r *defaultReplicator) Cleanup(ctx context.Context, ms *appmesh.Mesh) error {
+	var replicationRegions []appmesh.MeshReplicationRegion
+	if ms.Spec.Replication != nil {
+		replicationRegions = append(replicationRegions, ms.Spec.Replication.Regions...)
+	}
+	for _, removedReplica := range r.removedReplicas(ms) {
+		replicationRegions = append(replicationRegions, appmesh.MeshReplicationRegion{Region: removedReplica.Region})
+	}
+	meshName := aws.StringValue(ms.Spec.AWSName)
+	for _, replicationRegion := range replicationRegions {
+		if replicationRegion.Region == r.region {
+			continue
+		}
+		if err := r.cleanupRegion(ctx, r.replicaSDK(replicationRegion), ms); err != nil {
+			return errors.Wrapf(err, "failed to cleanup mesh %s in region %s", meshName, replicationRegion.Region)
+		}
+	}
+	return nil
+}
+
+// removedReplicas returns the replicas in ms.status of secondary regions that are no longer in ms.spec.
+// the primary region is never a removed replica, as it's never replicated into.
+func (r *defaultReplicator) removedReplicas(ms *appmesh.Mesh) []appmesh.MeshReplicaStatus {
+	regions := sets.NewString(r.region)
+	if ms.Spec.Replication != nil {
+		for _, replicationRegion := range ms.Spec.Replication.Regions {
+			regions.Insert(replicationRegion.Region)
+		}
+	}
+	var replicas []appmesh.MeshReplicaStatus
+	for _, replica := range ms.Status.Replicas {
+		if !regions.Has(replica.Region) {
+			replicas = append(replicas, *replica.DeepCopy())
+		}
+	}
+	return replicas
+}
+
+func (r *defaultReplicator) validateReplication(ms *appmesh.Mesh) error {
+	if ms.Spec.MeshOwner != nil && aws.StringValue(ms.Spec.MeshOwner) != r.accountID {
+		return errors.New("meshes shared from another account can't be replicated")
+	}
+	for _, replicationRegion := range ms.Spec.Replication.Regions {
+		if replicationRegion.Region == r.region {
+			return errors.Errorf("region %s is the primary region of the mesh", r.region)
+		}
+	}
+	return nil
+}
+
+// replicateIntoRegion mirrors sdkMS of ms and resources in it into the region of replicaSDK, and returns the ARN of the mirrored mesh.
+// resources in the mirrored mesh that don't exist in sdkMS are deleted.
+// existing meshes in the region are only mirrored into if they're tagged as replica of ms.
+func (r *defaultReplicator) replicateIntoRegion(ctx context.Context, replicaSDK services.AppMesh, ms *appmesh.Mesh, sdkMS *appmeshsdk.MeshData, resources []replicatedResource) (*string, error) {
+	meshName := aws.StringValue(sdkMS.MeshName)
+	replicaMS, err := r.findSDKMesh(ctx, replicaSDK, meshName)
+	if err != nil {
+		return nil, err
+	}
+	if replicaMS == nil {
+		resp, err := replicaSDK.CreateMeshWithContext(ctx, &appmeshsdk.CreateMeshInput{
+			MeshName: sdkMS.MeshName,
+			Spec:     sdkMS.Spec,
+			Tags: []*appmeshsdk.TagRef{
+				{Key: aws.String(tagging.TagKeyManagedBy), Value: aws.String(tagging.TagValueManagedBy)},
+				{Key: aws.String(tagging.TagKeyReplicaOf), Value: aws.String(string(ms.UID))},
+			},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create mesh")
+		}
+		replicaMS = resp.Mesh
+	} else {
+		if aws.StringValue(replicaMS.Metadata.ResourceOwner) != r.accountID {
+			return nil, errors.Errorf("mesh %s in region is owned by account %s", meshName, aws.StringValue(replicaMS.Metadata.ResourceOwner))
+		}
+		isReplica, err := r.isReplicaOf(ctx, replicaSDK, replicaMS, ms)
+		if err != nil {
+			return nil, err
+		}
+		if !isReplica {
+			return nil, errors.Errorf("mesh %s in region isn't a replica of this Mesh, it isn't tagged with %s: %s", meshName, tagging.TagKeyReplicaOf, ms.UID)
+		}
+		if !cmp.Equal(sdkMS.Spec, replicaMS.Spec, cmpopts.EquateEmpty()) {
+			if _, err := replicaSDK.UpdateMeshWithContext(ctx, &appmeshsdk.UpdateMeshInput{
+				MeshName: sdkMS.MeshName,
+				Spec:     sdkMS.Spec,
+			}); err != nil {
+				return replicaMS.Metadata.Arn, errors.Wrap(err, "failed to update mesh")
+			}
+		}
+	}
+	meshARN := replicaMS.Metadata.Arn
+
+	replicaResources, err := r.listResources(ctx, replicaSDK, meshName)
+	if err != nil {
+		return meshARN, errors.Wrap(err, "failed to list resources")
+	}
+	desiredResourceByKey := make(map[replicatedResourceKey]replicatedResource, len(resources))
+	for _, res := range resources {
+		desiredResourceByKey[res.key()] = res
+	}
+	replicaResourceByKey := make(map[replicatedResourceKey]replicatedResource, len(replicaResources))
+	for _, res := range replicaResources {
+		replicaResourceByKey[res.key()] = res
+	}
+
+	kindByName := replicatedKindByName()
+	for _, res := range resources {
+		kind := kindByName[res.kind]
+		replicaRes, exists := replicaResourceByKey[res.key()]
+		switch {
+		case !exists:
+			err = kind.create(ctx, replicaSDK, meshName, res.parentName, res.name, res.spec)
+		case !cmp.Equal(res.spec, replicaRes.spec, cmpopts.EquateEmpty()):
+			err = kind.update(ctx, replicaSDK, meshName, res.parentName, res.name, res.spec)
+		default:
+			continue
+		}
+		if err != nil {
+			return meshARN, errors.Wrapf(err, "failed to replicate %s %s", res.kind, res.name)
+		}
+	}
+	for i := len(replicaResources) - 1; i >= 0; i-- {
+		res := replicaResources[i]
+		if _, desired := desiredResourceByKey[res.key()]; desired {
+			continue
+		}
+		if err := kindByName[res.kind].delete(ctx, replicaSDK, meshName, res.parentName, res.name); err != nil && !isAWSNotFoundError(err) {
+			return meshARN, errors.Wrapf(err, "failed to delete %s %s", res.kind, res.name)
+		}
+	}
+	return meshARN, nil
+}
+
+// cleanupRegion deletes the replica mesh of ms and all resources in it from the region of replicaSDK.
+// meshes in the region that aren't tagged as replica of ms are left as is.
+func (r *defaultReplicator) cleanupRegion(ctx context.Context, replicaSDK services.AppMesh, ms *appmesh.Mesh) error {
+	meshName := aws.StringValue(ms.Spec.AWSName)
+	replicaMS, err := r.findSDKMesh(ctx, replicaSDK, meshName)
+	if err != nil {
+		return err
+	}
+	if replicaMS == nil || aws.StringValue(replicaMS.Metadata.ResourceOwner) != r.accountID {
+		return nil
+	}
+	isReplica, err := r.isReplicaOf(ctx, replicaSDK, replicaMS, ms)
+	if err != nil {
+		return err
+	}
+	if !isReplica {
+		r.log.Info("skipping cleanup of mesh that isn't a replica of the Mesh",
+			"mesh", k8s.NamespacedName(ms),
+			"meshARN", aws.StringValue(replicaMS.Metadata.Arn),
+		)
+		return nil
+	}
+	replicaResources, err := r.listResources(ctx, replicaSDK, meshName)
+	if err != nil {
+		return err
+	}
+	kindByName := replicatedKindByName()
+	for i := len(replicaResources) - 1; i >= 0; i-- {
+		res := replicaResources[i]
+		if err := kindByName[res.kind].delete(ctx, replicaSDK, meshName, res.parentName, res.name); err != nil && !isAWSNotFoundError(err) {
+			return errors.Wrapf(err, "failed to delete %s %s", res.kind, res.name)
+		}
+	}
+	if _, err := replicaSDK.DeleteMeshWithContext(ctx, &appmeshsdk.DeleteMeshInput{MeshName: aws.String(meshName)}); err != nil && !isAWSNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// isReplicaOf checks whether replicaMS is tagged as replica of ms upon its creation, so meshes created outside of replication are never mirrored into nor deleted.
+func (r *defaultReplicator) isReplicaOf(ctx context.Context, replicaSDK services.AppMesh, replicaMS *appmeshsdk.MeshData, ms *appmesh.Mesh) (bool, error) {
+	var replicaOf *string
+	if err := replicaSDK.ListTagsForResourcePagesWithContext(ctx, &appmeshsdk.ListTagsForResourceInput{
+		ResourceArn: replicaMS.Metadata.Arn,
+	}, func(output *appmeshsdk.ListTagsForResourceOutput, lastPage bool) bool {
+		for _, tag := range output.Tags {
+			if aws.StringValue(tag.Key) == tagging.TagKeyReplicaOf {
+				replicaOf = tag.Value
+			}
+		}
+		return true
+	}); err != nil {
+		return false, errors.Wrap(err, "failed to list tags of mesh")
+	}
+	return replicaOf != nil && aws.StringValue(replicaOf) == string(ms.UID), nil
+}
+
+// listResources lists all resources within mesh along with their spec, in the order of replicatedKinds.
+func (r *defaultReplicator) listResources(ctx context.Context, sdk services.AppMesh, meshName string) ([]replicatedResource, error) {
+	var resources []replicatedResource
+	namesByKind := make(map[string][]string, len(replicatedKinds))
+	for _, kind := range replicatedKinds {
+		parentNames := []string{""}
+		if kind.parentKind != "" {
+			parentNames = namesByKind[kind.parentKind]
+		}
+		for _, parentName := range parentNames {
+			names, err := kind.list(ctx, sdk, meshName, parentName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list %s", kind.kind)
+			}
+			for _, name := range names {
+				spec, err := kind.describe(ctx, sdk, meshName, parentName, name)
+				if err != nil {
+					// resource is deleted after it's listed.
+					if isAWSNotFoundError(err) {
+						continue
+					}
+					return nil, errors.Wrapf(err, "failed to describe %s %s", kind.kind, name)
+				}
+				resources = append(resources, replicatedResource{
+					kind:       kind.kind,
+					parentName: parentName,
+					name:       name,
+					spec:       spec,
+				})
+				namesByKind[kind.kind] = append(namesByKind[kind.kind], name)
+			}
+		}
+	}
+	return resources, nil
+}
+
+func (r *defaultReplicator) findSDKMesh(ctx context.Context, sdk services.AppMesh, meshName string) (*appmeshsdk.MeshData, error) {
+	resp, err := sdk.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{
+		MeshName: aws.String(meshName),
+	})
+	if err != nil {
+		if isAWSNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Mesh, nil
+}
+
+func (r *defaultReplicator) replicaSDK(replicationRegion appmesh.MeshReplicationRegion) services.AppMesh {
+	key := replicaSDKKey{region: replicationRegion.Region, endpoint: aws.StringValue(replicationRegion.Endpoint)}
+	r.replicaSDKsMutex.Lock()
+	defer r.replicaSDKsMutex.Unlock()
+	if sdk, ok := r.replicaSDKs[key]; ok {
+		return sdk
+	}
+	sdk := r.appMeshForRegion(key.region, key.endpoint)
+	r.replicaSDKs[key] = sdk
+	return sdk
+}
+
+func replicatedKindByName() map[string]replicatedKind {
+	kindByName := make(map[string]replicatedKind, len(replicatedKinds))
+	for _, kind := range replicatedKinds {
+		kindByName[kind.kind] = kind
+	}
+	return kindByName
+}
+
+func isAWSNotFoundError(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFoundException" {
+		return true
+	}
+	return false
+}
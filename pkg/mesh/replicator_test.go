@@ -0,0 +1,494 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeRegionAppMesh serves a single mesh with its tags, VirtualNodes and VirtualServices in a region, and records changes.
+type fakeRegionAppMesh struct {
+	services.AppMesh
+
+	region          string
+	accountID       string
+	meshSpec        *appmeshsdk.MeshSpec
+	meshTags        map[string]string
+	virtualNodes    map[string]*appmeshsdk.VirtualNodeSpec
+	virtualServices map[string]*appmeshsdk.VirtualServiceSpec
+	changes         []string
+}
+
+func (f *fakeRegionAppMesh) notFound() error {
+	return awserr.New("NotFoundException", "not found", nil)
+}
+
+func (f *fakeRegionAppMesh) meshData(meshName *string) *appmeshsdk.MeshData {
+	return &appmeshsdk.MeshData{
+		MeshName: meshName,
+		Spec:     f.meshSpec,
+		Metadata: &appmeshsdk.ResourceMetadata{
+			Arn:           aws.String(fmt.Sprintf("arn:aws:appmesh:%s:%s:mesh/%s", f.region, f.accountID, aws.StringValue(meshName))),
+			ResourceOwner: aws.String(f.accountID),
+		},
+	}
+}
+
+func (f *fakeRegionAppMesh) DescribeMeshWithContext(_ aws.Context, input *appmeshsdk.DescribeMeshInput, _ ...request.Option) (*appmeshsdk.DescribeMeshOutput, error) {
+	if f.meshSpec == nil {
+		return nil, f.notFound()
+	}
+	return &appmeshsdk.DescribeMeshOutput{Mesh: f.meshData(input.MeshName)}, nil
+}
+
+func (f *fakeRegionAppMesh) CreateMeshWithContext(_ aws.Context, input *appmeshsdk.CreateMeshInput, _ ...request.Option) (*appmeshsdk.CreateMeshOutput, error) {
+	f.meshSpec = input.Spec
+	f.meshTags = make(map[string]string, len(input.Tags))
+	for _, tag := range input.Tags {
+		f.meshTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	f.changes = append(f.changes, "CreateMesh "+aws.StringValue(input.MeshName))
+	return &appmeshsdk.CreateMeshOutput{Mesh: f.meshData(input.MeshName)}, nil
+}
+
+func (f *fakeRegionAppMesh) DeleteMeshWithContext(_ aws.Context, input *appmeshsdk.DeleteMeshInput, _ ...request.Option) (*appmeshsdk.DeleteMeshOutput, error) {
+	f.meshSpec = nil
+	f.meshTags = nil
+	f.changes = append(f.changes, "DeleteMesh "+aws.StringValue(input.MeshName))
+	return &appmeshsdk.DeleteMeshOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) ListTagsForResourcePagesWithContext(_ aws.Context, _ *appmeshsdk.ListTagsForResourceInput, fn func(*appmeshsdk.ListTagsForResourceOutput, bool) bool, _ ...request.Option) error {
+	page := &appmeshsdk.ListTagsForResourceOutput{}
+	for _, key := range sortedKeys(f.meshTags) {
+		page.Tags = append(page.Tags, &appmeshsdk.TagRef{Key: aws.String(key), Value: aws.String(f.meshTags[key])})
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *fakeRegionAppMesh) ListVirtualNodesPagesWithContext(_ aws.Context, _ *appmeshsdk.ListVirtualNodesInput, fn func(*appmeshsdk.ListVirtualNodesOutput, bool) bool, _ ...request.Option) error {
+	page := &appmeshsdk.ListVirtualNodesOutput{}
+	for _, name := range sortedKeys(f.virtualNodes) {
+		page.VirtualNodes = append(page.VirtualNodes, &appmeshsdk.VirtualNodeRef{VirtualNodeName: aws.String(name)})
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *fakeRegionAppMesh) DescribeVirtualNodeWithContext(_ aws.Context, input *appmeshsdk.DescribeVirtualNodeInput, _ ...request.Option) (*appmeshsdk.DescribeVirtualNodeOutput, error) {
+	spec, ok := f.virtualNodes[aws.StringValue(input.VirtualNodeName)]
+	if !ok {
+		return nil, f.notFound()
+	}
+	return &appmeshsdk.DescribeVirtualNodeOutput{VirtualNode: &appmeshsdk.VirtualNodeData{Spec: spec}}, nil
+}
+
+func (f *fakeRegionAppMesh) CreateVirtualNodeWithContext(_ aws.Context, input *appmeshsdk.CreateVirtualNodeInput, _ ...request.Option) (*appmeshsdk.CreateVirtualNodeOutput, error) {
+	f.virtualNodes[aws.StringValue(input.VirtualNodeName)] = input.Spec
+	f.changes = append(f.changes, "CreateVirtualNode "+aws.StringValue(input.VirtualNodeName))
+	return &appmeshsdk.CreateVirtualNodeOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) UpdateVirtualNodeWithContext(_ aws.Context, input *appmeshsdk.UpdateVirtualNodeInput, _ ...request.Option) (*appmeshsdk.UpdateVirtualNodeOutput, error) {
+	f.virtualNodes[aws.StringValue(input.VirtualNodeName)] = input.Spec
+	f.changes = append(f.changes, "UpdateVirtualNode "+aws.StringValue(input.VirtualNodeName))
+	return &appmeshsdk.UpdateVirtualNodeOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) DeleteVirtualNodeWithContext(_ aws.Context, input *appmeshsdk.DeleteVirtualNodeInput, _ ...request.Option) (*appmeshsdk.DeleteVirtualNodeOutput, error) {
+	delete(f.virtualNodes, aws.StringValue(input.VirtualNodeName))
+	f.changes = append(f.changes, "DeleteVirtualNode "+aws.StringValue(input.VirtualNodeName))
+	return &appmeshsdk.DeleteVirtualNodeOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) ListVirtualServicesPagesWithContext(_ aws.Context, _ *appmeshsdk.ListVirtualServicesInput, fn func(*appmeshsdk.ListVirtualServicesOutput, bool) bool, _ ...request.Option) error {
+	page := &appmeshsdk.ListVirtualServicesOutput{}
+	for _, name := range sortedKeys(f.virtualServices) {
+		page.VirtualServices = append(page.VirtualServices, &appmeshsdk.VirtualServiceRef{VirtualServiceName: aws.String(name)})
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *fakeRegionAppMesh) DescribeVirtualServiceWithContext(_ aws.Context, input *appmeshsdk.DescribeVirtualServiceInput, _ ...request.Option) (*appmeshsdk.DescribeVirtualServiceOutput, error) {
+	spec, ok := f.virtualServices[aws.StringValue(input.VirtualServiceName)]
+	if !ok {
+		return nil, f.notFound()
+	}
+	return &appmeshsdk.DescribeVirtualServiceOutput{VirtualService: &appmeshsdk.VirtualServiceData{Spec: spec}}, nil
+}
+
+func (f *fakeRegionAppMesh) CreateVirtualServiceWithContext(_ aws.Context, input *appmeshsdk.CreateVirtualServiceInput, _ ...request.Option) (*appmeshsdk.CreateVirtualServiceOutput, error) {
+	f.virtualServices[aws.StringValue(input.VirtualServiceName)] = input.Spec
+	f.changes = append(f.changes, "CreateVirtualService "+aws.StringValue(input.VirtualServiceName))
+	return &appmeshsdk.CreateVirtualServiceOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) DeleteVirtualServiceWithContext(_ aws.Context, input *appmeshsdk.DeleteVirtualServiceInput, _ ...request.Option) (*appmeshsdk.DeleteVirtualServiceOutput, error) {
+	delete(f.virtualServices, aws.StringValue(input.VirtualServiceName))
+	f.changes = append(f.changes, "DeleteVirtualService "+aws.StringValue(input.VirtualServiceName))
+	return &appmeshsdk.DeleteVirtualServiceOutput{}, nil
+}
+
+func (f *fakeRegionAppMesh) ListVirtualRoutersPagesWithContext(_ aws.Context, _ *appmeshsdk.ListVirtualRoutersInput, fn func(*appmeshsdk.ListVirtualRoutersOutput, bool) bool, _ ...request.Option) error {
+	fn(&appmeshsdk.ListVirtualRoutersOutput{}, true)
+	return nil
+}
+
+func (f *fakeRegionAppMesh) ListVirtualGatewaysPagesWithContext(_ aws.Context, _ *appmeshsdk.ListVirtualGatewaysInput, fn func(*appmeshsdk.ListVirtualGatewaysOutput, bool) bool, _ ...request.Option) error {
+	fn(&appmeshsdk.ListVirtualGatewaysOutput{}, true)
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func virtualNodeSpec(hostname string) *appmeshsdk.VirtualNodeSpec {
+	return &appmeshsdk.VirtualNodeSpec{
+		ServiceDiscovery: &appmeshsdk.ServiceDiscovery{Dns: &appmeshsdk.DnsServiceDiscovery{Hostname: aws.String(hostname)}},
+	}
+}
+
+func virtualServiceSpec(virtualNodeName string) *appmeshsdk.VirtualServiceSpec {
+	return &appmeshsdk.VirtualServiceSpec{
+		Provider: &appmeshsdk.VirtualServiceProvider{VirtualNode: &appmeshsdk.VirtualNodeServiceProvider{VirtualNodeName: aws.String(virtualNodeName)}},
+	}
+}
+
+func Test_defaultReplicator_replicate(t *testing.T) {
+	lastSyncTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	tests := []struct {
+		name        string
+		regions     []appmesh.MeshReplicationRegion
+		oldReplicas []appmesh.MeshReplicaStatus
+		replicaSDK  *fakeRegionAppMesh
+		// removedReplicaSDK serves the region of oldReplicas that's removed from regions.
+		removedReplicaSDK  *fakeRegionAppMesh
+		wantChanges        []string
+		wantRemovedChanges []string
+		wantReplicas       []appmesh.MeshReplicaStatus
+	}{
+		{
+			name:    "replica region without mesh",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}},
+			replicaSDK: &fakeRegionAppMesh{
+				region:          "us-west-2",
+				accountID:       "222222222222",
+				virtualNodes:    map[string]*appmeshsdk.VirtualNodeSpec{},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{},
+			},
+			wantChanges: []string{
+				"CreateMesh my-mesh",
+				"CreateVirtualNode node-a_ns",
+				"CreateVirtualNode node-b_ns",
+				"CreateVirtualService svc-a.ns",
+			},
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:  "us-west-2",
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+					Synced:  metav1.ConditionTrue,
+				},
+			},
+		},
+		{
+			name:    "replica region with stale resources",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2", Endpoint: aws.String("https://appmesh.us-west-2.example.com")}},
+			replicaSDK: &fakeRegionAppMesh{
+				region:    "us-west-2",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+					"node-b_ns": virtualNodeSpec("stale.ns.svc.cluster.local"),
+					"node-c_ns": virtualNodeSpec("node-c.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{
+					"svc-a.ns": virtualServiceSpec("node-a_ns"),
+					"svc-c.ns": virtualServiceSpec("node-c_ns"),
+				},
+			},
+			wantChanges: []string{
+				"UpdateVirtualNode node-b_ns",
+				"DeleteVirtualService svc-c.ns",
+				"DeleteVirtualNode node-c_ns",
+			},
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:  "us-west-2",
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+					Synced:  metav1.ConditionTrue,
+				},
+			},
+		},
+		{
+			name:    "replica region already synced",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}},
+			oldReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:       "us-west-2",
+					MeshARN:      aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+					Synced:       metav1.ConditionTrue,
+					LastSyncTime: &lastSyncTime,
+				},
+			},
+			replicaSDK: &fakeRegionAppMesh{
+				region:    "us-west-2",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+					"node-b_ns": virtualNodeSpec("node-b.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{
+					"svc-a.ns": virtualServiceSpec("node-a_ns"),
+				},
+			},
+			wantChanges: nil,
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:       "us-west-2",
+					MeshARN:      aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+					Synced:       metav1.ConditionTrue,
+					LastSyncTime: &lastSyncTime,
+				},
+			},
+		},
+		{
+			name:    "replica region with mesh that isn't a replica",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}},
+			replicaSDK: &fakeRegionAppMesh{
+				region:    "us-west-2",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  map[string]string{tagging.TagKeyReplicaOf: "other-mesh-uid"},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-c_ns": virtualNodeSpec("node-c.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{},
+			},
+			wantChanges: nil,
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:  "us-west-2",
+					Synced:  metav1.ConditionFalse,
+					Message: aws.String("mesh my-mesh in region isn't a replica of this Mesh, it isn't tagged with appmesh.k8s.aws/replica-of: mesh-uid"),
+				},
+			},
+		},
+		{
+			name:    "replica region removed from spec",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}},
+			oldReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:       "eu-west-1",
+					MeshARN:      aws.String("arn:aws:appmesh:eu-west-1:222222222222:mesh/my-mesh"),
+					Synced:       metav1.ConditionTrue,
+					LastSyncTime: &lastSyncTime,
+				},
+			},
+			replicaSDK: &fakeRegionAppMesh{
+				region:    "us-west-2",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+					"node-b_ns": virtualNodeSpec("node-b.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{
+					"svc-a.ns": virtualServiceSpec("node-a_ns"),
+				},
+			},
+			removedReplicaSDK: &fakeRegionAppMesh{
+				region:    "eu-west-1",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{},
+			},
+			wantChanges:        nil,
+			wantRemovedChanges: []string{"DeleteVirtualNode node-a_ns", "DeleteMesh my-mesh"},
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:  "us-west-2",
+					MeshARN: aws.String("arn:aws:appmesh:us-west-2:222222222222:mesh/my-mesh"),
+					Synced:  metav1.ConditionTrue,
+				},
+			},
+		},
+		{
+			name:    "replica region is the primary region",
+			regions: []appmesh.MeshReplicationRegion{{Region: "us-east-1"}},
+			oldReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:       "us-east-1",
+					MeshARN:      aws.String("arn:aws:appmesh:us-east-1:222222222222:mesh/my-mesh"),
+					Synced:       metav1.ConditionTrue,
+					LastSyncTime: &lastSyncTime,
+				},
+			},
+			replicaSDK: &fakeRegionAppMesh{
+				region:          "us-east-1",
+				accountID:       "222222222222",
+				virtualNodes:    map[string]*appmeshsdk.VirtualNodeSpec{},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{},
+			},
+			wantChanges: nil,
+			wantReplicas: []appmesh.MeshReplicaStatus{
+				{
+					Region:       "us-east-1",
+					MeshARN:      aws.String("arn:aws:appmesh:us-east-1:222222222222:mesh/my-mesh"),
+					Synced:       metav1.ConditionFalse,
+					Message:      aws.String("region us-east-1 is the primary region of the mesh"),
+					LastSyncTime: &lastSyncTime,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primarySDK := &fakeRegionAppMesh{
+				region:    "us-east-1",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+					"node-b_ns": virtualNodeSpec("node-b.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{
+					"svc-a.ns": virtualServiceSpec("node-a_ns"),
+				},
+			}
+			var gotEndpoints []string
+			r := NewDefaultReplicator(nil, primarySDK, func(region string, endpoint string) services.AppMesh {
+				if tt.removedReplicaSDK != nil && region == tt.removedReplicaSDK.region {
+					return tt.removedReplicaSDK
+				}
+				gotEndpoints = append(gotEndpoints, endpoint)
+				return tt.replicaSDK
+			}, "us-east-1", "222222222222", logr.New(&log.NullLogSink{})).(*defaultReplicator)
+			ms := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "mesh-uid"},
+				Spec: appmesh.MeshSpec{
+					AWSName:     aws.String("my-mesh"),
+					Replication: &appmesh.MeshReplication{Regions: tt.regions},
+				},
+				Status: appmesh.MeshStatus{Replicas: tt.oldReplicas},
+			}
+
+			gotReplicas := r.replicate(context.Background(), ms)
+			for i := range gotReplicas {
+				// LastSyncTime of newly synced regions is the time of replication.
+				if gotReplicas[i].Synced == metav1.ConditionTrue && !gotReplicas[i].LastSyncTime.Equal(&lastSyncTime) {
+					assert.NotNil(t, gotReplicas[i].LastSyncTime)
+					gotReplicas[i].LastSyncTime = nil
+				}
+			}
+			assert.Equal(t, tt.wantReplicas, gotReplicas)
+			assert.Equal(t, tt.wantChanges, tt.replicaSDK.changes)
+			if tt.removedReplicaSDK != nil {
+				assert.Equal(t, tt.wantRemovedChanges, tt.removedReplicaSDK.changes)
+			}
+			if len(tt.wantChanges) != 0 {
+				assert.Equal(t, primarySDK.virtualNodes, tt.replicaSDK.virtualNodes)
+				assert.Equal(t, primarySDK.virtualServices, tt.replicaSDK.virtualServices)
+				assert.Equal(t, "mesh-uid", tt.replicaSDK.meshTags[tagging.TagKeyReplicaOf])
+				assert.Equal(t, []string{aws.StringValue(tt.regions[0].Endpoint)}, gotEndpoints)
+			}
+		})
+	}
+}
+
+func Test_defaultReplicator_Cleanup(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication *appmesh.MeshReplication
+		replicas    []appmesh.MeshReplicaStatus
+		meshTags    map[string]string
+		wantChanges []string
+	}{
+		{
+			name:        "replica of the mesh",
+			replication: &appmesh.MeshReplication{Regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}}},
+			meshTags:    map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+			wantChanges: []string{
+				"DeleteVirtualService svc-a.ns",
+				"DeleteVirtualNode node-a_ns",
+				"DeleteMesh my-mesh",
+			},
+		},
+		{
+			name:     "replica of region removed from spec",
+			replicas: []appmesh.MeshReplicaStatus{{Region: "us-west-2", Synced: metav1.ConditionTrue}},
+			meshTags: map[string]string{tagging.TagKeyReplicaOf: "mesh-uid"},
+			wantChanges: []string{
+				"DeleteVirtualService svc-a.ns",
+				"DeleteVirtualNode node-a_ns",
+				"DeleteMesh my-mesh",
+			},
+		},
+		{
+			name:        "mesh that isn't a replica",
+			replication: &appmesh.MeshReplication{Regions: []appmesh.MeshReplicationRegion{{Region: "us-west-2"}}},
+			meshTags:    map[string]string{tagging.TagKeyManagedBy: tagging.TagValueManagedBy},
+			wantChanges: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicaSDK := &fakeRegionAppMesh{
+				region:    "us-west-2",
+				accountID: "222222222222",
+				meshSpec:  &appmeshsdk.MeshSpec{},
+				meshTags:  tt.meshTags,
+				virtualNodes: map[string]*appmeshsdk.VirtualNodeSpec{
+					"node-a_ns": virtualNodeSpec("node-a.ns.svc.cluster.local"),
+				},
+				virtualServices: map[string]*appmeshsdk.VirtualServiceSpec{
+					"svc-a.ns": virtualServiceSpec("node-a_ns"),
+				},
+			}
+			r := NewDefaultReplicator(nil, nil, func(region string, endpoint string) services.AppMesh {
+				return replicaSDK
+			}, "us-east-1", "222222222222", logr.New(&log.NullLogSink{}))
+			ms := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "mesh-uid"},
+				Spec: appmesh.MeshSpec{
+					AWSName:     aws.String("my-mesh"),
+					Replication: tt.replication,
+				},
+				Status: appmesh.MeshStatus{Replicas: tt.replicas},
+			}
+
+			assert.NoError(t, r.Cleanup(context.Background(), ms))
+			assert.Equal(t, tt.wantChanges, replicaSDK.changes)
+		})
+	}
+}
@@ -4,16 +4,19 @@ import (
 	"context"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,13 +34,15 @@ func NewDefaultResourceManager(
 	k8sClient client.Client,
 	appMeshSDK services.AppMesh,
 	accountID string,
+	tagsManager tagging.Manager,
 	log logr.Logger) ResourceManager {
 
 	return &defaultResourceManager{
-		k8sClient:  k8sClient,
-		appMeshSDK: appMeshSDK,
-		accountID:  accountID,
-		log:        log,
+		k8sClient:   k8sClient,
+		appMeshSDK:  appMeshSDK,
+		accountID:   accountID,
+		tagsManager: tagsManager,
+		log:         log,
 	}
 }
 
@@ -46,24 +51,40 @@ type defaultResourceManager struct {
 	k8sClient  client.Client
 	appMeshSDK services.AppMesh
 	// current iam identity's aws accountID, used to differentiate mesh ownership.
-	accountID string
-	log       logr.Logger
+	accountID   string
+	tagsManager tagging.Manager
+	log         logr.Logger
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, ms *appmesh.Mesh) error {
 	sdkMS, err := m.findSDKMesh(ctx, ms)
 	if err != nil {
-		return err
+		return m.updateCRDMeshForFailure(ctx, ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 	}
 	if sdkMS == nil {
 		sdkMS, err = m.createSDKMesh(ctx, ms)
 		if err != nil {
-			return err
+			return m.updateCRDMeshForFailure(ctx, ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
-		sdkMS, err = m.updateSDKMesh(ctx, sdkMS, ms)
-		if err != nil {
-			return err
+		err := m.checkSDKMeshOwnership(ctx, sdkMS, ms)
+		switch {
+		case tagging.IsOwnershipConflict(err):
+			// the mesh is shared with the controller owning it, which updates it, while members of this controller still reconcile against it.
+			m.log.V(1).Info("skip mesh update since it's owned by another controller",
+				"mesh", k8s.NamespacedName(ms),
+				"reason", err.Error(),
+			)
+		case err != nil:
+			return m.updateCRDMeshForFailure(ctx, ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		default:
+			sdkMS, err = m.updateSDKMesh(ctx, sdkMS, ms)
+			if err != nil {
+				return m.updateCRDMeshForFailure(ctx, ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+			}
+			if err := m.reconcileSDKMeshTags(ctx, sdkMS, ms); err != nil {
+				return m.updateCRDMeshForFailure(ctx, ms, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+			}
 		}
 	}
 	return m.updateCRDMesh(ctx, ms, sdkMS)
@@ -81,6 +102,16 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, ms *appmesh.Mesh)
 		return nil
 	}
 
+	if err := m.checkSDKMeshOwnership(ctx, sdkMS, ms); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip mesh deletion since it's owned by another controller",
+			"mesh", k8s.NamespacedName(ms),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	return m.deleteSDKMesh(ctx, sdkMS, ms)
 }
 
@@ -107,6 +138,7 @@ func (m *defaultResourceManager) createSDKMesh(ctx context.Context, ms *appmesh.
 	resp, err := m.appMeshSDK.CreateMeshWithContext(ctx, &appmeshsdk.CreateMeshInput{
 		MeshName: ms.Spec.AWSName,
 		Spec:     sdkMSSpec,
+		Tags:     m.buildSDKMeshTags(ctx, ms),
 	})
 	if err != nil {
 		return nil, err
@@ -139,7 +171,7 @@ func (m *defaultResourceManager) updateSDKMesh(ctx context.Context, sdkMS *appme
 		"desiredSDKMSSpec", desiredSDKMSSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateMeshWithContext(ctx, &appmeshsdk.UpdateMeshInput{
+	resp, err := m.appMeshSDK.UpdateMeshWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateMeshInput{
 		MeshName: sdkMS.MeshName,
 		Spec:     desiredSDKMSSpec,
 	})
@@ -175,16 +207,21 @@ func (m *defaultResourceManager) updateCRDMesh(ctx context.Context, ms *appmesh.
 		ms.Status.MeshARN = sdkMS.Metadata.Arn
 		needsUpdate = true
 	}
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkMS.Metadata, resourceMetadata); err != nil {
+		return err
+	}
+	if !cmp.Equal(ms.Status.ResourceMetadata, resourceMetadata) {
+		ms.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
 	if aws.Int64Value(ms.Status.ObservedGeneration) != ms.Generation {
 		ms.Status.ObservedGeneration = aws.Int64(ms.Generation)
 		needsUpdate = true
 	}
 
-	msActiveConditionStatus := corev1.ConditionFalse
-	if sdkMS.Status != nil && aws.StringValue(sdkMS.Status.Status) == appmeshsdk.MeshStatusCodeActive {
-		msActiveConditionStatus = corev1.ConditionTrue
-	}
-	if updateCondition(ms, appmesh.MeshActive, msActiveConditionStatus, nil, nil) {
+	msActive := sdkMS.Status != nil && aws.StringValue(sdkMS.Status.Status) == appmeshsdk.MeshStatusCodeActive
+	if updateConditionsForReconciled(ms, msActive) {
 		needsUpdate = true
 	}
 
@@ -194,6 +231,42 @@ func (m *defaultResourceManager) updateCRDMesh(ctx context.Context, ms *appmesh.
 	return m.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS))
 }
 
+// updateCRDMeshForFailure will record reconcile failure err into mesh's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDMeshForFailure(ctx context.Context, ms *appmesh.Mesh, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	oldMS := ms.DeepCopy()
+	if !updateConditionsForFailure(ms, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, ms, client.MergeFrom(oldMS)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "mesh", k8s.NamespacedName(ms))
+	}
+	return err
+}
+
+func (m *defaultResourceManager) buildSDKMeshTags(ctx context.Context, ms *appmesh.Mesh) []*appmeshsdk.TagRef {
+	return m.tagsManager.BuildTags(ms)
+}
+
+// reconcileSDKMeshTags corrects tags of AppMesh mesh if it's controlled by CRD mesh.
+func (m *defaultResourceManager) reconcileSDKMeshTags(ctx context.Context, sdkMS *appmeshsdk.MeshData, ms *appmesh.Mesh) error {
+	if !m.isSDKMeshControlledByCRDMesh(ctx, sdkMS, ms) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkMS.Metadata.Arn), ms); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
+	return nil
+}
+
+// checkSDKMeshOwnership checks AppMesh mesh isn't owned by another controller if it's controlled by CRD mesh.
+func (m *defaultResourceManager) checkSDKMeshOwnership(ctx context.Context, sdkMS *appmeshsdk.MeshData, ms *appmesh.Mesh) error {
+	if !m.isSDKMeshControlledByCRDMesh(ctx, sdkMS, ms) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkMS.Metadata.Arn), ms)
+}
+
 // isSDKMeshControlledByCRDMesh checks whether an AppMesh mesh is controlled by CRDMesh
 // if it's controlled, CRDMesh update is responsible for update AppMesh mesh.
 func (m *defaultResourceManager) isSDKMeshControlledByCRDMesh(ctx context.Context, sdkMS *appmeshsdk.MeshData, ms *appmesh.Mesh) bool {
@@ -3,15 +3,17 @@ package mesh
 import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-sdk-go/aws"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -42,7 +44,10 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 				},
 				sdkMS: &appmeshsdk.MeshData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.MeshStatus{
 						Status: aws.String(appmeshsdk.MeshStatusCodeActive),
@@ -55,10 +60,36 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 				},
 				Status: appmesh.MeshStatus{
 					MeshARN: aws.String("arn-1"),
-					Conditions: []appmesh.MeshCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -73,17 +104,25 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 					},
 					Status: appmesh.MeshStatus{
 						MeshARN: aws.String("arn-1"),
-						Conditions: []appmesh.MeshCondition{
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
 				},
 				sdkMS: &appmeshsdk.MeshData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.MeshStatus{
 						Status: aws.String(appmeshsdk.MeshStatusCodeInactive),
@@ -96,10 +135,36 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 				},
 				Status: appmesh.MeshStatus{
 					MeshARN: aws.String("arn-1"),
-					Conditions: []appmesh.MeshCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
 						},
 					},
 				},
@@ -131,7 +196,7 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 				assert.NoError(t, err)
 				opts := cmp.Options{
 					equality.IgnoreFakeClientPopulatedFields(),
-					cmpopts.IgnoreTypes((*metav1.Time)(nil)),
+					cmpopts.IgnoreTypes(metav1.Time{}),
 				}
 				assert.True(t, cmp.Equal(tt.wantMS, gotMS, opts), "diff", cmp.Diff(tt.wantMS, gotMS, opts))
 			}
@@ -139,6 +204,146 @@ func Test_defaultResourceManager_updateCRDMesh(t *testing.T) {
 	}
 }
 
+func Test_defaultResourceManager_ReconcileAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	appMeshSDK := services.NewFakeAppMesh("222233334444", "us-west-2")
+	log := logr.New(&log.NullLogSink{})
+	m := NewDefaultResourceManager(k8sClient, appMeshSDK, "222233334444", tagging.NewDefaultManager(tagging.Config{}, appMeshSDK, "", log), log)
+
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+		Spec: appmesh.MeshSpec{
+			AWSName: aws.String("my-mesh"),
+			EgressFilter: &appmesh.EgressFilter{
+				Type: appmesh.EgressFilterTypeAllowAll,
+			},
+		},
+	}
+	assert.NoError(t, k8sClient.Create(ctx, ms))
+
+	assert.NoError(t, m.Reconcile(ctx, ms))
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh", aws.StringValue(ms.Status.MeshARN))
+	assert.Equal(t, "222233334444", aws.StringValue(ms.Status.ResourceMetadata.ResourceOwner))
+
+	ms.Spec.EgressFilter.Type = appmesh.EgressFilterTypeDropAll
+	assert.NoError(t, m.Reconcile(ctx, ms))
+	describeResp, err := appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, appmeshsdk.EgressFilterTypeDropAll, aws.StringValue(describeResp.Mesh.Spec.EgressFilter.Type))
+	assert.Equal(t, int64(2), aws.Int64Value(describeResp.Mesh.Metadata.Version))
+
+	assert.NoError(t, m.Cleanup(ctx, ms))
+	_, err = appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.Error(t, err)
+}
+
+func Test_defaultResourceManager_ReconcileAndCleanup_ownedByOtherController(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	appMeshSDK := services.NewFakeAppMesh("222233334444", "us-west-2")
+	log := logr.New(&log.NullLogSink{})
+	_, err := appMeshSDK.CreateMeshWithContext(ctx, &appmeshsdk.CreateMeshInput{
+		MeshName: aws.String("my-mesh"),
+		Tags:     []*appmeshsdk.TagRef{{Key: aws.String(tagging.TagKeyControllerID), Value: aws.String("cluster-b")}},
+	})
+	assert.NoError(t, err)
+	m := NewDefaultResourceManager(k8sClient, appMeshSDK, "222233334444",
+		tagging.NewDefaultManager(tagging.Config{ControllerID: "cluster-a"}, appMeshSDK, "", log), log)
+
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+		Spec: appmesh.MeshSpec{
+			AWSName: aws.String("my-mesh"),
+			EgressFilter: &appmesh.EgressFilter{
+				Type: appmesh.EgressFilterTypeAllowAll,
+			},
+		},
+	}
+	assert.NoError(t, k8sClient.Create(ctx, ms))
+
+	assert.NoError(t, m.Reconcile(ctx, ms))
+	assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh", aws.StringValue(ms.Status.MeshARN))
+	describeResp, err := appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), aws.Int64Value(describeResp.Mesh.Metadata.Version))
+
+	assert.NoError(t, m.Cleanup(ctx, ms))
+	_, err = appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+
+	ms.Annotations = map[string]string{tagging.AnnotationTakeover: "true"}
+	assert.NoError(t, m.Reconcile(ctx, ms))
+	describeResp, err = appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, appmeshsdk.EgressFilterTypeAllowAll, aws.StringValue(describeResp.Mesh.Spec.EgressFilter.Type))
+	var tags []*appmeshsdk.TagRef
+	err = appMeshSDK.ListTagsForResourcePagesWithContext(ctx, &appmeshsdk.ListTagsForResourceInput{ResourceArn: describeResp.Mesh.Metadata.Arn},
+		func(output *appmeshsdk.ListTagsForResourceOutput, lastPage bool) bool {
+			tags = append(tags, output.Tags...)
+			return true
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []*appmeshsdk.TagRef{{Key: aws.String(tagging.TagKeyControllerID), Value: aws.String("cluster-a")}}, tags)
+}
+
+func Test_defaultResourceManager_Reconcile_sharedByTwoControllers(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	appMeshSDK := services.NewFakeAppMesh("222233334444", "us-west-2")
+	log := logr.New(&log.NullLogSink{})
+	newMesh := func(egressFilterType appmesh.EgressFilterType) *appmesh.Mesh {
+		return &appmesh.Mesh{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+			Spec: appmesh.MeshSpec{
+				AWSName:      aws.String("my-mesh"),
+				EgressFilter: &appmesh.EgressFilter{Type: egressFilterType},
+			},
+		}
+	}
+
+	// the Mesh of each cluster is reconciled by the controller of that cluster.
+	k8sClientA := testclient.NewFakeClientWithScheme(k8sSchema)
+	mA := NewDefaultResourceManager(k8sClientA, appMeshSDK, "222233334444",
+		tagging.NewDefaultManager(tagging.Config{ControllerID: "cluster-a"}, appMeshSDK, "", log), log)
+	msA := newMesh(appmesh.EgressFilterTypeAllowAll)
+	assert.NoError(t, k8sClientA.Create(ctx, msA))
+	k8sClientB := testclient.NewFakeClientWithScheme(k8sSchema)
+	mB := NewDefaultResourceManager(k8sClientB, appMeshSDK, "222233334444",
+		tagging.NewDefaultManager(tagging.Config{ControllerID: "cluster-b"}, appMeshSDK, "", log), log)
+	msB := newMesh(appmesh.EgressFilterTypeDropAll)
+	assert.NoError(t, k8sClientB.Create(ctx, msB))
+
+	assert.NoError(t, mA.Reconcile(ctx, msA))
+	assert.NoError(t, mB.Reconcile(ctx, msB))
+	for _, ms := range []*appmesh.Mesh{msA, msB} {
+		assert.Equal(t, "arn:aws:appmesh:us-west-2:222233334444:mesh/my-mesh", aws.StringValue(ms.Status.MeshARN))
+		meshActive := meta.FindStatusCondition(ms.Status.Conditions, appmesh.MeshActive)
+		if assert.NotNil(t, meshActive) {
+			assert.Equal(t, metav1.ConditionTrue, meshActive.Status)
+		}
+	}
+	describeResp, err := appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.Equal(t, appmeshsdk.EgressFilterTypeAllowAll, aws.StringValue(describeResp.Mesh.Spec.EgressFilter.Type))
+	assert.Equal(t, int64(1), aws.Int64Value(describeResp.Mesh.Metadata.Version))
+
+	assert.NoError(t, mB.Cleanup(ctx, msB))
+	_, err = appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.NoError(t, err)
+	assert.NoError(t, mA.Cleanup(ctx, msA))
+	_, err = appMeshSDK.DescribeMeshWithContext(ctx, &appmeshsdk.DescribeMeshInput{MeshName: aws.String("my-mesh")})
+	assert.Error(t, err)
+}
+
 func Test_defaultResourceManager_isSDKMeshControlledByCRDMesh(t *testing.T) {
 	type fields struct {
 		accountID string
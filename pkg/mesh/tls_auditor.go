@@ -0,0 +1,243 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxTLSAuditViolations is the maximum number of violations reported in Mesh status.
+	maxTLSAuditViolations = 50
+)
+
+// TLSAuditor audits client policy TLS of mesh members, reporting client to backend connections
+// that would fail once TLS is enforced.
+type TLSAuditor interface {
+	// Audit performs TLS audit for mesh and records the result as metric.
+	Audit(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshTLSAudit, error)
+	// Forget removes recorded metrics for mesh.
+	Forget(ms *appmesh.Mesh)
+}
+
+// NewDefaultTLSAuditor constructs new TLSAuditor
+func NewDefaultTLSAuditor(k8sClient client.Client, referencesResolver references.Resolver, metricsRegisterer prometheus.Registerer, log logr.Logger) (TLSAuditor, error) {
+	violationsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "appmesh",
+		Name:      "mesh_tls_audit_violations",
+		Help:      "Number of client to backend connections that would fail once client policy TLS is enforced",
+	}, []string{"mesh"})
+	if metricsRegisterer != nil {
+		if err := metricsRegisterer.Register(violationsGauge); err != nil {
+			return nil, errors.Wrap(err, "failed to register TLS audit metrics")
+		}
+	}
+	return &defaultTLSAuditor{
+		k8sClient:          k8sClient,
+		referencesResolver: referencesResolver,
+		violationsGauge:    violationsGauge,
+		log:                log,
+	}, nil
+}
+
+var _ TLSAuditor = &defaultTLSAuditor{}
+
+// defaultTLSAuditor implements TLSAuditor by inspecting VirtualNode specs.
+// A connection would fail when client policy TLS applies to a backend listener that doesn't accept TLS,
+// or that requires client certificates which the client policy doesn't provide.
+type defaultTLSAuditor struct {
+	k8sClient          client.Client
+	referencesResolver references.Resolver
+	violationsGauge    *prometheus.GaugeVec
+	log                logr.Logger
+}
+
+func (a *defaultTLSAuditor) Audit(ctx context.Context, ms *appmesh.Mesh) (*appmesh.MeshTLSAudit, error) {
+	vnList := &appmesh.VirtualNodeList{}
+	if err := a.k8sClient.List(ctx, vnList); err != nil {
+		return nil, errors.Wrap(err, "failed to list VirtualNodes")
+	}
+	var violations []appmesh.MeshTLSAuditViolation
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		if vn.Spec.MeshRef == nil || !IsMeshReferenced(ms, *vn.Spec.MeshRef) {
+			continue
+		}
+		vnViolations, err := a.auditVirtualNode(ctx, vn)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, vnViolations...)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Client != violations[j].Client {
+			return violations[i].Client < violations[j].Client
+		}
+		if violations[i].Backend != violations[j].Backend {
+			return violations[i].Backend < violations[j].Backend
+		}
+		return violations[i].Port < violations[j].Port
+	})
+
+	a.violationsGauge.WithLabelValues(ms.Name).Set(float64(len(violations)))
+	now := metav1.Now()
+	audit := &appmesh.MeshTLSAudit{
+		LastAuditTime:  &now,
+		ViolationCount: int32(len(violations)),
+	}
+	if len(violations) > maxTLSAuditViolations {
+		violations = violations[:maxTLSAuditViolations]
+	}
+	audit.Violations = violations
+	return audit, nil
+}
+
+func (a *defaultTLSAuditor) Forget(ms *appmesh.Mesh) {
+	a.violationsGauge.DeleteLabelValues(ms.Name)
+}
+
+func (a *defaultTLSAuditor) auditVirtualNode(ctx context.Context, vn *appmesh.VirtualNode) ([]appmesh.MeshTLSAuditViolation, error) {
+	var violations []appmesh.MeshTLSAuditViolation
+	for _, backend := range vn.Spec.Backends {
+		clientPolicyTLS := effectiveClientPolicyTLS(vn, backend)
+		// backends referenced by ARN are out of the cluster and cannot be audited.
+		if clientPolicyTLS == nil || backend.VirtualService.VirtualServiceRef == nil {
+			continue
+		}
+		backendVNs, err := a.resolveVirtualServiceBackendNodes(ctx, vn, *backend.VirtualService.VirtualServiceRef)
+		if err != nil {
+			return nil, err
+		}
+		for _, backendVN := range backendVNs {
+			violations = append(violations, auditConnection(vn, backendVN, clientPolicyTLS)...)
+		}
+	}
+	return violations, nil
+}
+
+// resolveVirtualServiceBackendNodes resolves the VirtualNodes that serve traffic for virtualService.
+// references that cannot be resolved are skipped, since they are reported by the respective resource reconcile.
+func (a *defaultTLSAuditor) resolveVirtualServiceBackendNodes(ctx context.Context, vn *appmesh.VirtualNode, vsRef appmesh.VirtualServiceReference) ([]*appmesh.VirtualNode, error) {
+	vs, err := a.referencesResolver.ResolveVirtualServiceReference(ctx, vn, vsRef)
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	if vs.Spec.Provider == nil {
+		return nil, nil
+	}
+	if vs.Spec.Provider.VirtualNode != nil && vs.Spec.Provider.VirtualNode.VirtualNodeRef != nil {
+		return a.resolveVirtualNodeReferences(ctx, vs, []appmesh.VirtualNodeReference{*vs.Spec.Provider.VirtualNode.VirtualNodeRef})
+	}
+	if vs.Spec.Provider.VirtualRouter != nil && vs.Spec.Provider.VirtualRouter.VirtualRouterRef != nil {
+		vr, err := a.referencesResolver.ResolveVirtualRouterReference(ctx, vs, *vs.Spec.Provider.VirtualRouter.VirtualRouterRef)
+		if err != nil {
+			return nil, ignoreNotFound(err)
+		}
+		var vnRefs []appmesh.VirtualNodeReference
+		for _, target := range virtualRouterWeightedTargets(vr) {
+			if target.VirtualNodeRef != nil {
+				vnRefs = append(vnRefs, *target.VirtualNodeRef)
+			}
+		}
+		return a.resolveVirtualNodeReferences(ctx, vr, vnRefs)
+	}
+	return nil, nil
+}
+
+// resolveVirtualNodeReferences resolves distinct VirtualNodes referenced by obj.
+func (a *defaultTLSAuditor) resolveVirtualNodeReferences(ctx context.Context, obj metav1.Object, vnRefs []appmesh.VirtualNodeReference) ([]*appmesh.VirtualNode, error) {
+	var vns []*appmesh.VirtualNode
+	seen := make(map[string]bool)
+	for _, vnRef := range vnRefs {
+		vn, err := a.referencesResolver.ResolveVirtualNodeReference(ctx, obj, vnRef)
+		if err != nil {
+			if err := ignoreNotFound(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		key := k8s.NamespacedName(vn).String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		vns = append(vns, vn)
+	}
+	return vns, nil
+}
+
+// effectiveClientPolicyTLS returns the client policy TLS applies to backend, backend specific client policy takes precedence.
+func effectiveClientPolicyTLS(vn *appmesh.VirtualNode, backend appmesh.Backend) *appmesh.ClientPolicyTLS {
+	if backend.VirtualService.ClientPolicy != nil {
+		return backend.VirtualService.ClientPolicy.TLS
+	}
+	if vn.Spec.BackendDefaults != nil && vn.Spec.BackendDefaults.ClientPolicy != nil {
+		return vn.Spec.BackendDefaults.ClientPolicy.TLS
+	}
+	return nil
+}
+
+// auditConnection checks whether connections from client to backend listeners covered by clientPolicyTLS would fail if enforced.
+func auditConnection(client *appmesh.VirtualNode, backend *appmesh.VirtualNode, clientPolicyTLS *appmesh.ClientPolicyTLS) []appmesh.MeshTLSAuditViolation {
+	enforcedPorts := make(map[appmesh.PortNumber]bool, len(clientPolicyTLS.Ports))
+	for _, port := range clientPolicyTLS.Ports {
+		enforcedPorts[port] = true
+	}
+	var violations []appmesh.MeshTLSAuditViolation
+	for _, listener := range backend.Spec.Listeners {
+		port := listener.PortMapping.Port
+		if len(enforcedPorts) != 0 && !enforcedPorts[port] {
+			continue
+		}
+		var reason string
+		switch {
+		case listener.TLS == nil || listener.TLS.Mode == appmesh.ListenerTLSModeDisabled:
+			reason = "backend listener doesn't accept TLS"
+		case listener.TLS.Mode == appmesh.ListenerTLSModeStrict && listener.TLS.Validation != nil && clientPolicyTLS.Certificate == nil:
+			reason = "backend listener requires client certificate, but client policy doesn't provide one"
+		default:
+			continue
+		}
+		violations = append(violations, appmesh.MeshTLSAuditViolation{
+			Client:  k8s.NamespacedName(client).String(),
+			Backend: k8s.NamespacedName(backend).String(),
+			Port:    port,
+			Reason:  fmt.Sprintf("%s on port %d", reason, port),
+		})
+	}
+	return violations
+}
+
+func virtualRouterWeightedTargets(vr *appmesh.VirtualRouter) []appmesh.WeightedTarget {
+	var targets []appmesh.WeightedTarget
+	for _, route := range vr.Spec.Routes {
+		switch {
+		case route.HTTPRoute != nil:
+			targets = append(targets, route.HTTPRoute.Action.WeightedTargets...)
+		case route.HTTP2Route != nil:
+			targets = append(targets, route.HTTP2Route.Action.WeightedTargets...)
+		case route.GRPCRoute != nil:
+			targets = append(targets, route.GRPCRoute.Action.WeightedTargets...)
+		case route.TCPRoute != nil:
+			targets = append(targets, route.TCPRoute.Action.WeightedTargets...)
+		}
+	}
+	return targets
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(errors.Cause(err)) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,243 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_auditConnection(t *testing.T) {
+	client := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "client"},
+	}
+	backendWithListenerTLS := func(tls *appmesh.ListenerTLS) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "backend"},
+			Spec: appmesh.VirtualNodeSpec{
+				Listeners: []appmesh.Listener{
+					{
+						PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP},
+						TLS:         tls,
+					},
+					{
+						PortMapping: appmesh.PortMapping{Port: 9090, Protocol: appmesh.PortProtocolTCP},
+					},
+				},
+			},
+		}
+	}
+	strictWithValidation := &appmesh.ListenerTLS{
+		Mode: appmesh.ListenerTLSModeStrict,
+		Validation: &appmesh.ListenerTLSValidationContext{
+			Trust: appmesh.ListenerTLSValidationContextTrust{
+				File: &appmesh.TLSValidationContextFileTrust{CertificateChain: "/certs/ca.pem"},
+			},
+		},
+	}
+	clientCertificate := &appmesh.ClientTLSCertificate{
+		File: &appmesh.ListenerTLSFileCertificate{CertificateChain: "/certs/cert.pem", PrivateKey: "/certs/key.pem"},
+	}
+	tests := []struct {
+		name            string
+		backend         *appmesh.VirtualNode
+		clientPolicyTLS *appmesh.ClientPolicyTLS
+		want            []appmesh.MeshTLSAuditViolation
+	}{
+		{
+			name:            "listener without TLS on enforced port",
+			backend:         backendWithListenerTLS(nil),
+			clientPolicyTLS: &appmesh.ClientPolicyTLS{Ports: []appmesh.PortNumber{8080}},
+			want: []appmesh.MeshTLSAuditViolation{
+				{
+					Client:  "ns-1/client",
+					Backend: "ns-2/backend",
+					Port:    8080,
+					Reason:  "backend listener doesn't accept TLS on port 8080",
+				},
+			},
+		},
+		{
+			name:            "all listeners are covered when ports are not specified",
+			backend:         backendWithListenerTLS(&appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModePermissive}),
+			clientPolicyTLS: &appmesh.ClientPolicyTLS{},
+			want: []appmesh.MeshTLSAuditViolation{
+				{
+					Client:  "ns-1/client",
+					Backend: "ns-2/backend",
+					Port:    9090,
+					Reason:  "backend listener doesn't accept TLS on port 9090",
+				},
+			},
+		},
+		{
+			name:            "listener with disabled TLS",
+			backend:         backendWithListenerTLS(&appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeDisabled}),
+			clientPolicyTLS: &appmesh.ClientPolicyTLS{Ports: []appmesh.PortNumber{8080}},
+			want: []appmesh.MeshTLSAuditViolation{
+				{
+					Client:  "ns-1/client",
+					Backend: "ns-2/backend",
+					Port:    8080,
+					Reason:  "backend listener doesn't accept TLS on port 8080",
+				},
+			},
+		},
+		{
+			name:            "listener requires client certificate that's not provided",
+			backend:         backendWithListenerTLS(strictWithValidation),
+			clientPolicyTLS: &appmesh.ClientPolicyTLS{Ports: []appmesh.PortNumber{8080}},
+			want: []appmesh.MeshTLSAuditViolation{
+				{
+					Client:  "ns-1/client",
+					Backend: "ns-2/backend",
+					Port:    8080,
+					Reason:  "backend listener requires client certificate, but client policy doesn't provide one on port 8080",
+				},
+			},
+		},
+		{
+			name:            "listener requires client certificate that's provided",
+			backend:         backendWithListenerTLS(strictWithValidation),
+			clientPolicyTLS: &appmesh.ClientPolicyTLS{Ports: []appmesh.PortNumber{8080}, Certificate: clientCertificate},
+			want:            nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := auditConnection(client, tt.backend, tt.clientPolicyTLS)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultTLSAuditor_Audit(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "uid-1"},
+	}
+	meshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "uid-1"}
+	clientVN := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "client"},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: meshRef,
+			BackendDefaults: &appmesh.BackendDefaults{
+				ClientPolicy: &appmesh.ClientPolicy{TLS: &appmesh.ClientPolicyTLS{}},
+			},
+			Backends: []appmesh.Backend{
+				{VirtualService: appmesh.VirtualServiceBackend{VirtualServiceRef: &appmesh.VirtualServiceReference{Name: "vs-router"}}},
+				{VirtualService: appmesh.VirtualServiceBackend{VirtualServiceRef: &appmesh.VirtualServiceReference{Name: "vs-node"}}},
+				{VirtualService: appmesh.VirtualServiceBackend{VirtualServiceRef: &appmesh.VirtualServiceReference{Name: "vs-missing"}}},
+			},
+		},
+	}
+	plainVN := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "plain"},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef:   meshRef,
+			Listeners: []appmesh.Listener{{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}}},
+		},
+	}
+	tlsVN := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "tls"},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: meshRef,
+			Listeners: []appmesh.Listener{
+				{
+					PortMapping: appmesh.PortMapping{Port: 8443, Protocol: appmesh.PortProtocolHTTP},
+					TLS:         &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeStrict},
+				},
+			},
+		},
+	}
+	otherMeshVN := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "other"},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef:         &appmesh.MeshReference{Name: "other-mesh", UID: "uid-2"},
+			BackendDefaults: clientVN.Spec.BackendDefaults,
+			Backends:        clientVN.Spec.Backends,
+		},
+	}
+	vsNode := &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vs-node"},
+		Spec: appmesh.VirtualServiceSpec{
+			Provider: &appmesh.VirtualServiceProvider{
+				VirtualNode: &appmesh.VirtualNodeServiceProvider{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "tls"}},
+			},
+		},
+	}
+	vsRouter := &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vs-router"},
+		Spec: appmesh.VirtualServiceSpec{
+			Provider: &appmesh.VirtualServiceProvider{
+				VirtualRouter: &appmesh.VirtualRouterServiceProvider{VirtualRouterRef: &appmesh.VirtualRouterReference{Name: "vr"}},
+			},
+		},
+	}
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vr"},
+		Spec: appmesh.VirtualRouterSpec{
+			Routes: []appmesh.Route{
+				{
+					Name: "http",
+					HTTPRoute: &appmesh.HTTPRoute{
+						Action: appmesh.HTTPRouteAction{
+							WeightedTargets: []appmesh.WeightedTarget{
+								{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "plain"}, Weight: 90},
+								{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "tls"}, Weight: 10},
+							},
+						},
+					},
+				},
+				{
+					Name: "tcp",
+					TCPRoute: &appmesh.TCPRoute{
+						Action: appmesh.TCPRouteAction{
+							WeightedTargets: []appmesh.WeightedTarget{
+								{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "plain"}, Weight: 100},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	ctx := context.Background()
+	for _, obj := range []interface{}{clientVN, plainVN, tlsVN, otherMeshVN, vsNode, vsRouter, vr} {
+		switch o := obj.(type) {
+		case *appmesh.VirtualNode:
+			assert.NoError(t, k8sClient.Create(ctx, o.DeepCopy()))
+		case *appmesh.VirtualService:
+			assert.NoError(t, k8sClient.Create(ctx, o.DeepCopy()))
+		case *appmesh.VirtualRouter:
+			assert.NoError(t, k8sClient.Create(ctx, o.DeepCopy()))
+		}
+	}
+
+	auditor, err := NewDefaultTLSAuditor(k8sClient, references.NewDefaultResolver(k8sClient, logr.New(&log.NullLogSink{})), nil, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, err)
+	got, err := auditor.Audit(ctx, ms)
+	assert.NoError(t, err)
+	assert.NotNil(t, got.LastAuditTime)
+	assert.Equal(t, int32(1), got.ViolationCount)
+	assert.Equal(t, []appmesh.MeshTLSAuditViolation{
+		{
+			Client:  "ns-1/client",
+			Backend: "ns-1/plain",
+			Port:    8080,
+			Reason:  "backend listener doesn't accept TLS on port 8080",
+		},
+	}, got.Violations)
+}
@@ -2,7 +2,7 @@ package mesh
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IsMeshActive tests whether given mesh is active.
@@ -10,12 +10,17 @@ import (
 func IsMeshActive(ms *appmesh.Mesh) bool {
 	for _, condition := range ms.Status.Conditions {
 		if condition.Type == appmesh.MeshActive {
-			return condition.Status == corev1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue
 		}
 	}
 	return false
 }
 
+// IsTLSEnforcementAudit tests whether given mesh audits client policy TLS instead of enforcing it.
+func IsTLSEnforcementAudit(ms *appmesh.Mesh) bool {
+	return ms.Spec.TLSEnforcementMode != nil && *ms.Spec.TLSEnforcementMode == appmesh.TLSEnforcementModeAudit
+}
+
 // IsMeshReferenced tests whether given mesh is referenced by meshReference
 func IsMeshReferenced(ms *appmesh.Mesh, reference appmesh.MeshReference) bool {
 	return ms.Name == reference.Name && ms.UID == reference.UID
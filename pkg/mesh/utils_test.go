@@ -3,7 +3,6 @@ package mesh
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
@@ -22,10 +21,10 @@ func TestIsMeshActive(t *testing.T) {
 			args: args{
 				mesh: &appmesh.Mesh{
 					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
@@ -38,10 +37,10 @@ func TestIsMeshActive(t *testing.T) {
 			args: args{
 				mesh: &appmesh.Mesh{
 					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionFalse,
+								Status: metav1.ConditionFalse,
 							},
 						},
 					},
@@ -54,10 +53,10 @@ func TestIsMeshActive(t *testing.T) {
 			args: args{
 				mesh: &appmesh.Mesh{
 					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.MeshActive,
-								Status: corev1.ConditionUnknown,
+								Status: metav1.ConditionUnknown,
 							},
 						},
 					},
@@ -70,7 +69,7 @@ func TestIsMeshActive(t *testing.T) {
 			args: args{
 				mesh: &appmesh.Mesh{
 					Status: appmesh.MeshStatus{
-						Conditions: []appmesh.MeshCondition{},
+						Conditions: []metav1.Condition{},
 					},
 				},
 			},
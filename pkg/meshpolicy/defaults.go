@@ -0,0 +1,205 @@
+package meshpolicy
+
+import (
+	"context"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FindMeshPolicies returns the meshPolicies of ms, ordered by namespace and name.
+func FindMeshPolicies(ctx context.Context, k8sClient client.Client, ms *appmesh.Mesh) ([]appmesh.MeshPolicy, error) {
+	mpList := &appmesh.MeshPolicyList{}
+	if err := k8sClient.List(ctx, mpList); err != nil {
+		return nil, errors.Wrap(err, "failed to list meshPolicies")
+	}
+	var policies []appmesh.MeshPolicy
+	for _, mp := range mpList.Items {
+		if mp.Spec.MeshRef == nil || !mesh.IsMeshReferenced(ms, *mp.Spec.MeshRef) {
+			continue
+		}
+		policies = append(policies, mp)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return k8s.NamespacedName(&policies[i]).String() < k8s.NamespacedName(&policies[j]).String()
+	})
+	return policies, nil
+}
+
+// ApplyRouteDefaults returns a copy of vr with the route defaults of policies applied to its routes.
+// Fields specified by a route take precedence over policies, and earlier policies take precedence over later ones.
+func ApplyRouteDefaults(policies []appmesh.MeshPolicy, vr *appmesh.VirtualRouter) *appmesh.VirtualRouter {
+	defaults := mergeRouteDefaults(policies)
+	vr = vr.DeepCopy()
+	for i := range vr.Spec.Routes {
+		applyRouteDefaults(&vr.Spec.Routes[i], defaults)
+	}
+	return vr
+}
+
+// ApplyListenerDefaults returns a copy of vn with the listener defaults of policies applied to its listeners.
+// Fields specified by a listener take precedence over policies, and earlier policies take precedence over later ones.
+func ApplyListenerDefaults(policies []appmesh.MeshPolicy, vn *appmesh.VirtualNode) *appmesh.VirtualNode {
+	defaults := mergeListenerDefaults(policies)
+	vn = vn.DeepCopy()
+	for i := range vn.Spec.Listeners {
+		applyListenerDefaults(&vn.Spec.Listeners[i], defaults)
+	}
+	return vn
+}
+
+func mergeRouteDefaults(policies []appmesh.MeshPolicy) appmesh.MeshPolicyRouteDefaults {
+	merged := appmesh.MeshPolicyRouteDefaults{Timeout: &appmesh.ListenerTimeout{}}
+	for _, policy := range policies {
+		defaults := policy.Spec.RouteDefaults
+		if defaults == nil {
+			continue
+		}
+		if merged.HTTPRetryPolicy == nil {
+			merged.HTTPRetryPolicy = defaults.HTTPRetryPolicy
+		}
+		if merged.GRPCRetryPolicy == nil {
+			merged.GRPCRetryPolicy = defaults.GRPCRetryPolicy
+		}
+		mergeTimeout(merged.Timeout, defaults.Timeout)
+	}
+	return merged
+}
+
+func mergeListenerDefaults(policies []appmesh.MeshPolicy) appmesh.MeshPolicyListenerDefaults {
+	merged := appmesh.MeshPolicyListenerDefaults{
+		ConnectionPool: &appmesh.VirtualNodeConnectionPool{},
+		Timeout:        &appmesh.ListenerTimeout{},
+	}
+	for _, policy := range policies {
+		defaults := policy.Spec.ListenerDefaults
+		if defaults == nil {
+			continue
+		}
+		if merged.OutlierDetection == nil {
+			merged.OutlierDetection = defaults.OutlierDetection
+		}
+		if defaults.ConnectionPool != nil {
+			if merged.ConnectionPool.TCP == nil {
+				merged.ConnectionPool.TCP = defaults.ConnectionPool.TCP
+			}
+			if merged.ConnectionPool.HTTP == nil {
+				merged.ConnectionPool.HTTP = defaults.ConnectionPool.HTTP
+			}
+			if merged.ConnectionPool.HTTP2 == nil {
+				merged.ConnectionPool.HTTP2 = defaults.ConnectionPool.HTTP2
+			}
+			if merged.ConnectionPool.GRPC == nil {
+				merged.ConnectionPool.GRPC = defaults.ConnectionPool.GRPC
+			}
+		}
+		mergeTimeout(merged.Timeout, defaults.Timeout)
+	}
+	return merged
+}
+
+// mergeTimeout sets the protocols' timeouts of merged that are unset to those of timeout.
+func mergeTimeout(merged *appmesh.ListenerTimeout, timeout *appmesh.ListenerTimeout) {
+	if timeout == nil {
+		return
+	}
+	if merged.TCP == nil {
+		merged.TCP = timeout.TCP
+	}
+	if merged.HTTP == nil {
+		merged.HTTP = timeout.HTTP
+	}
+	if merged.HTTP2 == nil {
+		merged.HTTP2 = timeout.HTTP2
+	}
+	if merged.GRPC == nil {
+		merged.GRPC = timeout.GRPC
+	}
+}
+
+func applyRouteDefaults(route *appmesh.Route, defaults appmesh.MeshPolicyRouteDefaults) {
+	if route.HTTPRoute != nil {
+		if route.HTTPRoute.RetryPolicy == nil {
+			route.HTTPRoute.RetryPolicy = defaults.HTTPRetryPolicy.DeepCopy()
+		}
+		if route.HTTPRoute.Timeout == nil {
+			route.HTTPRoute.Timeout = defaults.Timeout.HTTP.DeepCopy()
+		}
+	}
+	if route.HTTP2Route != nil {
+		if route.HTTP2Route.RetryPolicy == nil {
+			route.HTTP2Route.RetryPolicy = defaults.HTTPRetryPolicy.DeepCopy()
+		}
+		if route.HTTP2Route.Timeout == nil {
+			route.HTTP2Route.Timeout = defaults.Timeout.HTTP2.DeepCopy()
+		}
+	}
+	if route.GRPCRoute != nil {
+		if route.GRPCRoute.RetryPolicy == nil {
+			route.GRPCRoute.RetryPolicy = defaults.GRPCRetryPolicy.DeepCopy()
+		}
+		if route.GRPCRoute.Timeout == nil {
+			route.GRPCRoute.Timeout = defaults.Timeout.GRPC.DeepCopy()
+		}
+	}
+	if route.TCPRoute != nil {
+		if route.TCPRoute.Timeout == nil {
+			route.TCPRoute.Timeout = defaults.Timeout.TCP.DeepCopy()
+		}
+	}
+}
+
+func applyListenerDefaults(listener *appmesh.Listener, defaults appmesh.MeshPolicyListenerDefaults) {
+	if listener.OutlierDetection == nil {
+		listener.OutlierDetection = defaults.OutlierDetection.DeepCopy()
+	}
+	protocol := listener.PortMapping.Protocol
+	if listener.ConnectionPool == nil {
+		listener.ConnectionPool = connectionPoolForProtocol(defaults.ConnectionPool, protocol)
+	}
+	if listener.Timeout == nil {
+		listener.Timeout = timeoutForProtocol(defaults.Timeout, protocol)
+	}
+}
+
+// connectionPoolForProtocol returns a copy of the connection pool settings of pool for protocol, or nil if there are none.
+func connectionPoolForProtocol(pool *appmesh.VirtualNodeConnectionPool, protocol appmesh.PortProtocol) *appmesh.VirtualNodeConnectionPool {
+	var protocolPool appmesh.VirtualNodeConnectionPool
+	switch protocol {
+	case appmesh.PortProtocolTCP:
+		protocolPool.TCP = pool.TCP.DeepCopy()
+	case appmesh.PortProtocolHTTP:
+		protocolPool.HTTP = pool.HTTP.DeepCopy()
+	case appmesh.PortProtocolHTTP2:
+		protocolPool.HTTP2 = pool.HTTP2.DeepCopy()
+	case appmesh.PortProtocolGRPC:
+		protocolPool.GRPC = pool.GRPC.DeepCopy()
+	}
+	if protocolPool == (appmesh.VirtualNodeConnectionPool{}) {
+		return nil
+	}
+	return &protocolPool
+}
+
+// timeoutForProtocol returns a copy of the timeout of timeout for protocol, or nil if there is none.
+func timeoutForProtocol(timeout *appmesh.ListenerTimeout, protocol appmesh.PortProtocol) *appmesh.ListenerTimeout {
+	var protocolTimeout appmesh.ListenerTimeout
+	switch protocol {
+	case appmesh.PortProtocolTCP:
+		protocolTimeout.TCP = timeout.TCP.DeepCopy()
+	case appmesh.PortProtocolHTTP:
+		protocolTimeout.HTTP = timeout.HTTP.DeepCopy()
+	case appmesh.PortProtocolHTTP2:
+		protocolTimeout.HTTP2 = timeout.HTTP2.DeepCopy()
+	case appmesh.PortProtocolGRPC:
+		protocolTimeout.GRPC = timeout.GRPC.DeepCopy()
+	}
+	if protocolTimeout == (appmesh.ListenerTimeout{}) {
+		return nil
+	}
+	return &protocolTimeout
+}
@@ -0,0 +1,157 @@
+package meshpolicy
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_FindMeshPolicies(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "408d3036-7dec-11ea-b156-0e30aabe1ca8"},
+	}
+	meshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "408d3036-7dec-11ea-b156-0e30aabe1ca8"}
+	anotherMeshRef := &appmesh.MeshReference{Name: "another-mesh", UID: "1d3b3c3c-7dec-11ea-b156-0e30aabe1ca8"}
+	policies := []*appmesh.MeshPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "mp-a"}, Spec: appmesh.MeshPolicySpec{MeshRef: meshRef}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-b"}, Spec: appmesh.MeshPolicySpec{MeshRef: meshRef}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-a"}, Spec: appmesh.MeshPolicySpec{MeshRef: meshRef}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-c"}, Spec: appmesh.MeshPolicySpec{MeshRef: anotherMeshRef}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-d"}},
+	}
+
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	for _, mp := range policies {
+		assert.NoError(t, k8sClient.Create(ctx, mp.DeepCopy()))
+	}
+
+	got, err := FindMeshPolicies(ctx, k8sClient, ms)
+	assert.NoError(t, err)
+	var gotKeys []types.NamespacedName
+	for i := range got {
+		gotKeys = append(gotKeys, k8s.NamespacedName(&got[i]))
+	}
+	assert.Equal(t, []types.NamespacedName{
+		{Namespace: "ns-1", Name: "mp-a"},
+		{Namespace: "ns-1", Name: "mp-b"},
+		{Namespace: "ns-2", Name: "mp-a"},
+	}, gotKeys)
+}
+
+func Test_ApplyRouteDefaults(t *testing.T) {
+	policyRetry := &appmesh.HTTPRetryPolicy{
+		HTTPRetryEvents: []appmesh.HTTPRetryPolicyEvent{"server-error"},
+		MaxRetries:      3,
+		PerRetryTimeout: appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 500},
+	}
+	laterPolicyRetry := &appmesh.HTTPRetryPolicy{
+		HTTPRetryEvents: []appmesh.HTTPRetryPolicyEvent{"gateway-error"},
+		MaxRetries:      1,
+		PerRetryTimeout: appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 1},
+	}
+	routeRetry := &appmesh.HTTPRetryPolicy{
+		TCPRetryEvents:  []appmesh.TCPRetryPolicyEvent{"connection-error"},
+		MaxRetries:      5,
+		PerRetryTimeout: appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 2},
+	}
+	http2Timeout := &appmesh.HTTPTimeout{PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 15}}
+	tcpTimeout := &appmesh.TCPTimeout{Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 600}}
+	policies := []appmesh.MeshPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-a"},
+			Spec: appmesh.MeshPolicySpec{
+				RouteDefaults: &appmesh.MeshPolicyRouteDefaults{
+					HTTPRetryPolicy: policyRetry,
+					Timeout:         &appmesh.ListenerTimeout{HTTP2: http2Timeout},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-b"},
+			Spec: appmesh.MeshPolicySpec{
+				RouteDefaults: &appmesh.MeshPolicyRouteDefaults{
+					HTTPRetryPolicy: laterPolicyRetry,
+					Timeout:         &appmesh.ListenerTimeout{TCP: tcpTimeout},
+				},
+			},
+		},
+	}
+	vr := &appmesh.VirtualRouter{
+		Spec: appmesh.VirtualRouterSpec{
+			Routes: []appmesh.Route{
+				{Name: "http-route", HTTPRoute: &appmesh.HTTPRoute{RetryPolicy: routeRetry}},
+				{Name: "http2-route", HTTP2Route: &appmesh.HTTPRoute{}},
+				{Name: "tcp-route", TCPRoute: &appmesh.TCPRoute{}},
+				{Name: "grpc-route", GRPCRoute: &appmesh.GRPCRoute{}},
+			},
+		},
+	}
+
+	got := ApplyRouteDefaults(policies, vr)
+	assert.Equal(t, routeRetry, got.Spec.Routes[0].HTTPRoute.RetryPolicy)
+	assert.Nil(t, got.Spec.Routes[0].HTTPRoute.Timeout)
+	assert.Equal(t, policyRetry, got.Spec.Routes[1].HTTP2Route.RetryPolicy)
+	assert.Equal(t, http2Timeout, got.Spec.Routes[1].HTTP2Route.Timeout)
+	assert.Equal(t, tcpTimeout, got.Spec.Routes[2].TCPRoute.Timeout)
+	assert.Nil(t, got.Spec.Routes[3].GRPCRoute.RetryPolicy)
+	assert.Nil(t, got.Spec.Routes[3].GRPCRoute.Timeout)
+	// The input virtualRouter must be left untouched.
+	assert.Nil(t, vr.Spec.Routes[1].HTTP2Route.RetryPolicy)
+}
+
+func Test_ApplyListenerDefaults(t *testing.T) {
+	outlierDetection := &appmesh.OutlierDetection{
+		MaxServerErrors:      5,
+		MaxEjectionPercent:   50,
+		Interval:             appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 10},
+		BaseEjectionDuration: appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 30},
+	}
+	tcpPool := &appmesh.TCPConnectionPool{MaxConnections: 100}
+	httpPool := &appmesh.HTTPConnectionPool{MaxConnections: 50}
+	tcpTimeout := &appmesh.TCPTimeout{Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 600}}
+	policies := []appmesh.MeshPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "mp-a"},
+			Spec: appmesh.MeshPolicySpec{
+				ListenerDefaults: &appmesh.MeshPolicyListenerDefaults{
+					OutlierDetection: outlierDetection,
+					ConnectionPool:   &appmesh.VirtualNodeConnectionPool{TCP: tcpPool, HTTP: httpPool},
+					Timeout:          &appmesh.ListenerTimeout{TCP: tcpTimeout},
+				},
+			},
+		},
+	}
+	listenerPool := &appmesh.VirtualNodeConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 10}}
+	vn := &appmesh.VirtualNode{
+		Spec: appmesh.VirtualNodeSpec{
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}, ConnectionPool: listenerPool},
+				{PortMapping: appmesh.PortMapping{Port: 9090, Protocol: appmesh.PortProtocolTCP}},
+				{PortMapping: appmesh.PortMapping{Port: 7070, Protocol: appmesh.PortProtocolGRPC}},
+			},
+		},
+	}
+
+	got := ApplyListenerDefaults(policies, vn)
+	assert.Equal(t, outlierDetection, got.Spec.Listeners[0].OutlierDetection)
+	assert.Equal(t, listenerPool, got.Spec.Listeners[0].ConnectionPool)
+	assert.Nil(t, got.Spec.Listeners[0].Timeout)
+	assert.Equal(t, &appmesh.VirtualNodeConnectionPool{TCP: tcpPool}, got.Spec.Listeners[1].ConnectionPool)
+	assert.Equal(t, &appmesh.ListenerTimeout{TCP: tcpTimeout}, got.Spec.Listeners[1].Timeout)
+	assert.Nil(t, got.Spec.Listeners[2].ConnectionPool)
+	assert.Nil(t, got.Spec.Listeners[2].Timeout)
+	// The input virtualNode must be left untouched.
+	assert.Nil(t, vn.Spec.Listeners[1].ConnectionPool)
+}
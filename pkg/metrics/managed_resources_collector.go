@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	labelKind = "kind"
+
+	// how long to wait for listing resources upon a scrape
+	defaultManagedResourcesListTimeout = 10 * time.Second
+)
+
+// managedResourcesCounter counts resources of a kind, and how many of them have been created in AppMesh.
+type managedResourcesCounter func(ctx context.Context, k8sClient client.Client) (total int, managed int, err error)
+
+// NewManagedResourcesCollector constructs new prometheus.Collector that reports number of AppMesh CRD resources per kind.
+// Resources are listed from k8sClient upon each scrape, k8sClient is expected to be backed by informer cache.
+func NewManagedResourcesCollector(k8sClient client.Client, log logr.Logger) prometheus.Collector {
+	return &managedResourcesCollector{
+		k8sClient: k8sClient,
+		resourcesDesc: prometheus.NewDesc(
+			"appmesh_resources",
+			"Number of AppMesh CRD resources in the cluster",
+			[]string{labelKind}, nil,
+		),
+		managedResourcesDesc: prometheus.NewDesc(
+			"appmesh_managed_resources",
+			"Number of AppMesh CRD resources created in AppMesh",
+			[]string{labelKind}, nil,
+		),
+		countersByKind: map[string]managedResourcesCounter{
+			"Mesh":           countMeshes,
+			"VirtualGateway": countVirtualGateways,
+			"GatewayRoute":   countGatewayRoutes,
+			"VirtualNode":    countVirtualNodes,
+			"VirtualService": countVirtualServices,
+			"VirtualRouter":  countVirtualRouters,
+		},
+		listTimeout: defaultManagedResourcesListTimeout,
+		log:         log,
+	}
+}
+
+var _ prometheus.Collector = &managedResourcesCollector{}
+
+type managedResourcesCollector struct {
+	k8sClient            client.Client
+	resourcesDesc        *prometheus.Desc
+	managedResourcesDesc *prometheus.Desc
+	countersByKind       map[string]managedResourcesCounter
+	listTimeout          time.Duration
+	log                  logr.Logger
+}
+
+func (c *managedResourcesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.resourcesDesc
+	ch <- c.managedResourcesDesc
+}
+
+func (c *managedResourcesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.listTimeout)
+	defer cancel()
+	for kind, counter := range c.countersByKind {
+		total, managed, err := counter(ctx, c.k8sClient)
+		if err != nil {
+			c.log.Error(err, "failed to count resources", "kind", kind)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.resourcesDesc, prometheus.GaugeValue, float64(total), kind)
+		ch <- prometheus.MustNewConstMetric(c.managedResourcesDesc, prometheus.GaugeValue, float64(managed), kind)
+	}
+}
+
+func countMeshes(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.MeshList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.MeshARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
+
+func countVirtualGateways(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.VirtualGatewayList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.VirtualGatewayARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
+
+func countGatewayRoutes(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.GatewayRouteList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.GatewayRouteARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
+
+func countVirtualNodes(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.VirtualNodeList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.VirtualNodeARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
+
+func countVirtualServices(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.VirtualServiceList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.VirtualServiceARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
+
+func countVirtualRouters(ctx context.Context, k8sClient client.Client) (int, int, error) {
+	list := &appmesh.VirtualRouterList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		return 0, 0, err
+	}
+	managed := 0
+	for _, obj := range list.Items {
+		if obj.Status.VirtualRouterARN != nil {
+			managed++
+		}
+	}
+	return len(list.Items), managed, nil
+}
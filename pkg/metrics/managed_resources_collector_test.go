@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_managedResourcesCollector_Collect(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+
+	assert.NoError(t, k8sClient.Create(ctx, &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+		Status:     appmesh.MeshStatus{MeshARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh")},
+	}))
+	assert.NoError(t, k8sClient.Create(ctx, &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vn-1"},
+		Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/vn-1_ns-1")},
+	}))
+	assert.NoError(t, k8sClient.Create(ctx, &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vn-2"},
+	}))
+
+	c := NewManagedResourcesCollector(k8sClient, logr.New(&log.NullLogSink{}))
+	want := `
+# HELP appmesh_managed_resources Number of AppMesh CRD resources created in AppMesh
+# TYPE appmesh_managed_resources gauge
+appmesh_managed_resources{kind="GatewayRoute"} 0
+appmesh_managed_resources{kind="Mesh"} 1
+appmesh_managed_resources{kind="VirtualGateway"} 0
+appmesh_managed_resources{kind="VirtualNode"} 1
+appmesh_managed_resources{kind="VirtualRouter"} 0
+appmesh_managed_resources{kind="VirtualService"} 0
+# HELP appmesh_resources Number of AppMesh CRD resources in the cluster
+# TYPE appmesh_resources gauge
+appmesh_resources{kind="GatewayRoute"} 0
+appmesh_resources{kind="Mesh"} 1
+appmesh_resources{kind="VirtualGateway"} 0
+appmesh_resources{kind="VirtualNode"} 2
+appmesh_resources{kind="VirtualRouter"} 0
+appmesh_resources{kind="VirtualService"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(want)))
+}
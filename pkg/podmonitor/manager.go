@@ -0,0 +1,157 @@
+package podmonitor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// envoyStatsPortName is the name of envoy container port serving admin interface.
+	envoyStatsPortName = "stats"
+	// envoyStatsPath is the Envoy admin endpoint serving stats in Prometheus exposition format.
+	envoyStatsPath = "/stats/prometheus"
+)
+
+// PodMonitorGVK is the GroupVersionKind of Prometheus Operator PodMonitor.
+// It's handled as unstructured to avoid depending on Prometheus Operator API.
+var PodMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PodMonitor",
+}
+
+// Manager manages Prometheus Operator PodMonitors that scrape Envoy stats of pods selected by mesh resources.
+type Manager interface {
+	// Reconcile ensures a PodMonitor owned by owner exists for each of podSelectors and scrapes Envoy of pods selected by it,
+	// and deletes PodMonitors owned by owner for podSelectors that no longer exist.
+	Reconcile(ctx context.Context, owner client.Object, podSelectors []*metav1.LabelSelector) error
+}
+
+// NewDefaultManager constructs new Manager. It's a no-op if not enabled.
+func NewDefaultManager(k8sClient client.Client, scheme *runtime.Scheme, enabled bool, log logr.Logger) Manager {
+	return &defaultManager{
+		k8sClient: k8sClient,
+		scheme:    scheme,
+		enabled:   enabled,
+		log:       log,
+	}
+}
+
+var _ Manager = &defaultManager{}
+
+// defaultManager implements Manager.
+// PodMonitors select pods by a single label selector, so one is created per podSelector, named after their mesh resource followed by the index of podSelector if it's not the first.
+// PodMonitors are owned by their mesh resource, so they are garbage collected by Kubernetes upon deletion.
+type defaultManager struct {
+	k8sClient client.Client
+	scheme    *runtime.Scheme
+	enabled   bool
+	log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
+
+func (m *defaultManager) Reconcile(ctx context.Context, owner client.Object, podSelectors []*metav1.LabelSelector) error {
+	if !m.enabled {
+		return nil
+	}
+	desiredNames := make(map[string]struct{}, len(podSelectors))
+	for idx, podSelector := range podSelectors {
+		name := podMonitorName(owner, idx)
+		if err := m.reconcilePodMonitor(ctx, owner, name, podSelector); err != nil {
+			return err
+		}
+		desiredNames[name] = struct{}{}
+	}
+	return m.deleteStalePodMonitors(ctx, owner, desiredNames)
+}
+
+func (m *defaultManager) reconcilePodMonitor(ctx context.Context, owner client.Object, name string, podSelector *metav1.LabelSelector) error {
+	desiredSpec, err := buildPodMonitorSpec(podSelector)
+	if err != nil {
+		return err
+	}
+
+	podMonitor := &unstructured.Unstructured{}
+	podMonitor.SetGroupVersionKind(PodMonitorGVK)
+	podMonitor.SetNamespace(owner.GetNamespace())
+	podMonitor.SetName(name)
+	result, err := controllerutil.CreateOrUpdate(ctx, m.k8sClient, podMonitor, func() error {
+		podMonitor.Object["spec"] = desiredSpec
+		return controllerutil.SetControllerReference(owner, podMonitor, m.scheme)
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return errors.Wrap(err, "failed to reconcile PodMonitor, make sure Prometheus Operator CRDs are installed")
+		}
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		m.log.V(1).Info("reconciled PodMonitor",
+			"podMonitor", k8s.NamespacedName(podMonitor),
+			"result", result,
+		)
+	}
+	return nil
+}
+
+// deleteStalePodMonitors deletes PodMonitors owned by owner, except for desiredNames.
+func (m *defaultManager) deleteStalePodMonitors(ctx context.Context, owner client.Object, desiredNames map[string]struct{}) error {
+	podMonitorList := &unstructured.UnstructuredList{}
+	podMonitorList.SetGroupVersionKind(PodMonitorGVK.GroupVersion().WithKind(PodMonitorGVK.Kind + "List"))
+	if err := m.k8sClient.List(ctx, podMonitorList, client.InNamespace(owner.GetNamespace())); err != nil {
+		// there's nothing to delete without Prometheus Operator CRDs.
+		if meta.IsNoMatchError(err) && len(desiredNames) == 0 {
+			return nil
+		}
+		return err
+	}
+	for idx := range podMonitorList.Items {
+		podMonitor := &podMonitorList.Items[idx]
+		if _, desired := desiredNames[podMonitor.GetName()]; desired || !metav1.IsControlledBy(podMonitor, owner) {
+			continue
+		}
+		if err := m.k8sClient.Delete(ctx, podMonitor); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		m.log.V(1).Info("deleted stale PodMonitor",
+			"podMonitor", k8s.NamespacedName(podMonitor),
+		)
+	}
+	return nil
+}
+
+// podMonitorName returns the name of PodMonitor for the podSelector at idx of owner.
+func podMonitorName(owner client.Object, idx int) string {
+	if idx == 0 {
+		return owner.GetName()
+	}
+	return fmt.Sprintf("%s-%d", owner.GetName(), idx)
+}
+
+func buildPodMonitorSpec(podSelector *metav1.LabelSelector) (map[string]interface{}, error) {
+	selector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSelector)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"selector": selector,
+		"podMetricsEndpoints": []interface{}{
+			map[string]interface{}{
+				"port": envoyStatsPortName,
+				"path": envoyStatsPath,
+			},
+		},
+	}, nil
+}
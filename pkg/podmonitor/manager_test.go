@@ -0,0 +1,144 @@
+package podmonitor
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultManager_Reconcile(t *testing.T) {
+	vnOwnerReference := metav1.OwnerReference{
+		APIVersion: "appmesh.k8s.aws/v1beta2",
+		Kind:       "VirtualNode",
+		Name:       "my-vn",
+		UID:        "uid-1",
+		Controller: pointer.Bool(true),
+	}
+	tests := []struct {
+		name             string
+		enabled          bool
+		podSelectorTerms []metav1.LabelSelector
+		// existingPodMonitors are the specs of existing PodMonitors by name, owned by the VirtualNode.
+		existingPodMonitors map[string]map[string]interface{}
+		// wantMatchLabels are the matchLabels of PodMonitors by name.
+		wantMatchLabels map[string]map[string]interface{}
+	}{
+		{
+			name:            "no-op when disabled",
+			enabled:         false,
+			wantMatchLabels: map[string]map[string]interface{}{},
+		},
+		{
+			name:    "creates podMonitor",
+			enabled: true,
+			wantMatchLabels: map[string]map[string]interface{}{
+				"my-vn": {"app": "my-app"},
+			},
+		},
+		{
+			name:    "updates podMonitor with stale selector",
+			enabled: true,
+			existingPodMonitors: map[string]map[string]interface{}{
+				"my-vn": {"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "old-app"}}},
+			},
+			wantMatchLabels: map[string]map[string]interface{}{
+				"my-vn": {"app": "my-app"},
+			},
+		},
+		{
+			name:    "creates podMonitor per podSelector term",
+			enabled: true,
+			podSelectorTerms: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"app": "my-app-canary"}},
+				{MatchLabels: map[string]string{"app": "my-app-legacy"}},
+			},
+			wantMatchLabels: map[string]map[string]interface{}{
+				"my-vn":   {"app": "my-app"},
+				"my-vn-1": {"app": "my-app-canary"},
+				"my-vn-2": {"app": "my-app-legacy"},
+			},
+		},
+		{
+			name:    "deletes podMonitor of removed podSelector term",
+			enabled: true,
+			existingPodMonitors: map[string]map[string]interface{}{
+				"my-vn":   {"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "my-app"}}},
+				"my-vn-1": {"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "my-app-canary"}}},
+			},
+			wantMatchLabels: map[string]map[string]interface{}{
+				"my-vn": {"app": "my-app"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).Build()
+			for name, spec := range tt.existingPodMonitors {
+				existing := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+				existing.SetGroupVersionKind(PodMonitorGVK)
+				existing.SetNamespace("awesome-ns")
+				existing.SetName(name)
+				existing.SetOwnerReferences([]metav1.OwnerReference{vnOwnerReference})
+				assert.NoError(t, k8sClient.Create(ctx, existing))
+			}
+			// a PodMonitor that isn't owned by the VirtualNode is never deleted.
+			unowned := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+			unowned.SetGroupVersionKind(PodMonitorGVK)
+			unowned.SetNamespace("awesome-ns")
+			unowned.SetName("my-vn-9")
+			assert.NoError(t, k8sClient.Create(ctx, unowned))
+
+			vn := &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "awesome-ns",
+					Name:      "my-vn",
+					UID:       "uid-1",
+				},
+				Spec: appmesh.VirtualNodeSpec{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "my-app"},
+					},
+					PodSelectorTerms: tt.podSelectorTerms,
+				},
+			}
+			m := NewDefaultManager(k8sClient, k8sSchema, tt.enabled, logr.New(&log.NullLogSink{}))
+			assert.NoError(t, m.Reconcile(ctx, vn, virtualnode.PodSelectorTerms(vn)))
+
+			podMonitorList := &unstructured.UnstructuredList{}
+			podMonitorList.SetGroupVersionKind(PodMonitorGVK.GroupVersion().WithKind("PodMonitorList"))
+			assert.NoError(t, k8sClient.List(ctx, podMonitorList))
+			gotMatchLabels := make(map[string]map[string]interface{})
+			for _, got := range podMonitorList.Items {
+				if got.GetName() == "my-vn-9" {
+					continue
+				}
+				matchLabels, _, _ := unstructured.NestedMap(got.Object, "spec", "selector", "matchLabels")
+				gotMatchLabels[got.GetName()] = matchLabels
+				endpoints, _, _ := unstructured.NestedSlice(got.Object, "spec", "podMetricsEndpoints")
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"port": "stats", "path": "/stats/prometheus"},
+				}, endpoints)
+				assert.Len(t, got.GetOwnerReferences(), 1)
+				assert.Equal(t, "my-vn", got.GetOwnerReferences()[0].Name)
+			}
+			assert.Equal(t, tt.wantMatchLabels, gotMatchLabels)
+			assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "awesome-ns", Name: "my-vn-9"}, unowned))
+		})
+	}
+}
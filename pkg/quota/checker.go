@@ -0,0 +1,274 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceCodeAppMesh is the Service Quotas service code of AppMesh.
+const serviceCodeAppMesh = "appmesh"
+
+// Names of AppMesh service quotas checked by Checker, as reported by the Service Quotas API.
+const (
+	QuotaVirtualNodesPerMesh            = "Virtual nodes per mesh"
+	QuotaVirtualRoutersPerMesh          = "Virtual routers per mesh"
+	QuotaVirtualServicesPerMesh         = "Virtual services per mesh"
+	QuotaVirtualGatewaysPerMesh         = "Virtual gateways per mesh"
+	QuotaBackendsPerVirtualNode         = "Backends per virtual node"
+	QuotaRoutesPerVirtualRouter         = "Routes per virtual router"
+	QuotaGatewayRoutesPerVirtualGateway = "Gateway routes per virtual gateway"
+)
+
+// ExceededError indicates that applying a resource would exceed an AppMesh service quota.
+type ExceededError struct {
+	// QuotaName is the name of the exceeded quota.
+	QuotaName string
+	// Value is the value of the quota.
+	Value int64
+	// Count is the count the resource would result in.
+	Count int64
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("AppMesh service quota %q would be exceeded: %d > %d", e.QuotaName, e.Count, e.Value)
+}
+
+// Checker checks AppMesh resources against AppMesh service quotas before they are applied,
+// so that quota violations surface as status conditions instead of LimitExceededException from AppMesh.
+// Checks only return ExceededError, quotas that cannot be checked are skipped since AppMesh still enforces them.
+// Counts of resources per mesh or parent are taken from the k8s objects already synced to AppMesh,
+// so AppMesh resources created outside of this cluster aren't accounted for.
+type Checker interface {
+	// CheckVirtualNode checks backends of vn, and virtualNodes of ms if vn is to be created.
+	CheckVirtualNode(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode, create bool) error
+	// CheckVirtualRouter checks routes of vr, and virtualRouters of ms if vr is to be created.
+	CheckVirtualRouter(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, create bool) error
+	// CheckVirtualService checks virtualServices of ms if vs is to be created.
+	CheckVirtualService(ctx context.Context, ms *appmesh.Mesh, vs *appmesh.VirtualService, create bool) error
+	// CheckVirtualGateway checks virtualGateways of ms if vg is to be created.
+	CheckVirtualGateway(ctx context.Context, ms *appmesh.Mesh, vg *appmesh.VirtualGateway, create bool) error
+	// CheckGatewayRoute checks gatewayRoutes of vg if gr is to be created.
+	CheckGatewayRoute(ctx context.Context, vg *appmesh.VirtualGateway, gr *appmesh.GatewayRoute, create bool) error
+}
+
+// NewDefaultChecker constructs new Checker that fetches quotas from the Service Quotas API and refreshes them every cfg.RefreshInterval.
+func NewDefaultChecker(cfg Config, serviceQuotasSDK services.ServiceQuotas, k8sClient client.Client, log logr.Logger) *defaultChecker {
+	return &defaultChecker{
+		refreshInterval:  cfg.RefreshInterval,
+		serviceQuotasSDK: serviceQuotasSDK,
+		k8sClient:        k8sClient,
+		log:              log,
+		nowFunc:          time.Now,
+	}
+}
+
+var _ Checker = &defaultChecker{}
+
+type defaultChecker struct {
+	refreshInterval  time.Duration
+	serviceQuotasSDK services.ServiceQuotas
+	k8sClient        client.Client
+	log              logr.Logger
+	nowFunc          func() time.Time
+
+	// mutex protects quotaValueByName and refreshedAt.
+	mutex sync.Mutex
+	// quotaValueByName is the value of AppMesh service quotas, keyed by lower-cased quota name.
+	quotaValueByName map[string]int64
+	refreshedAt      time.Time
+}
+
+func (c *defaultChecker) CheckVirtualNode(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode, create bool) error {
+	if err := c.checkCount(ctx, QuotaBackendsPerVirtualNode, int64(len(vn.Spec.Backends))); err != nil {
+		return err
+	}
+	if !create {
+		return nil
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := c.k8sClient.List(ctx, vnList); err != nil {
+		c.log.Error(err, "failed to count virtualNodes, skipping quota check")
+		return nil
+	}
+	count := int64(1)
+	for i := range vnList.Items {
+		other := &vnList.Items[i]
+		if other.UID != vn.UID && isMemberOfMesh(other.Spec.MeshRef, ms) && other.Status.VirtualNodeARN != nil {
+			count++
+		}
+	}
+	return c.checkCount(ctx, QuotaVirtualNodesPerMesh, count)
+}
+
+func (c *defaultChecker) CheckVirtualRouter(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, create bool) error {
+	if err := c.checkCount(ctx, QuotaRoutesPerVirtualRouter, int64(len(vr.Spec.Routes))); err != nil {
+		return err
+	}
+	if !create {
+		return nil
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := c.k8sClient.List(ctx, vrList); err != nil {
+		c.log.Error(err, "failed to count virtualRouters, skipping quota check")
+		return nil
+	}
+	count := int64(1)
+	for i := range vrList.Items {
+		other := &vrList.Items[i]
+		if other.UID != vr.UID && isMemberOfMesh(other.Spec.MeshRef, ms) && other.Status.VirtualRouterARN != nil {
+			count++
+		}
+	}
+	return c.checkCount(ctx, QuotaVirtualRoutersPerMesh, count)
+}
+
+func (c *defaultChecker) CheckVirtualService(ctx context.Context, ms *appmesh.Mesh, vs *appmesh.VirtualService, create bool) error {
+	if !create {
+		return nil
+	}
+	vsList := &appmesh.VirtualServiceList{}
+	if err := c.k8sClient.List(ctx, vsList); err != nil {
+		c.log.Error(err, "failed to count virtualServices, skipping quota check")
+		return nil
+	}
+	count := int64(1)
+	for i := range vsList.Items {
+		other := &vsList.Items[i]
+		if other.UID != vs.UID && isMemberOfMesh(other.Spec.MeshRef, ms) && other.Status.VirtualServiceARN != nil {
+			count++
+		}
+	}
+	return c.checkCount(ctx, QuotaVirtualServicesPerMesh, count)
+}
+
+func (c *defaultChecker) CheckVirtualGateway(ctx context.Context, ms *appmesh.Mesh, vg *appmesh.VirtualGateway, create bool) error {
+	if !create {
+		return nil
+	}
+	vgList := &appmesh.VirtualGatewayList{}
+	if err := c.k8sClient.List(ctx, vgList); err != nil {
+		c.log.Error(err, "failed to count virtualGateways, skipping quota check")
+		return nil
+	}
+	count := int64(1)
+	for i := range vgList.Items {
+		other := &vgList.Items[i]
+		if other.UID != vg.UID && isMemberOfMesh(other.Spec.MeshRef, ms) && other.Status.VirtualGatewayARN != nil {
+			count++
+		}
+	}
+	return c.checkCount(ctx, QuotaVirtualGatewaysPerMesh, count)
+}
+
+func (c *defaultChecker) CheckGatewayRoute(ctx context.Context, vg *appmesh.VirtualGateway, gr *appmesh.GatewayRoute, create bool) error {
+	if !create {
+		return nil
+	}
+	grList := &appmesh.GatewayRouteList{}
+	if err := c.k8sClient.List(ctx, grList); err != nil {
+		c.log.Error(err, "failed to count gatewayRoutes, skipping quota check")
+		return nil
+	}
+	vgKey := k8s.NamespacedName(vg)
+	count := int64(1)
+	for i := range grList.Items {
+		other := &grList.Items[i]
+		if other.UID == gr.UID || other.Spec.VirtualGatewayRef == nil || other.Status.GatewayRouteARN == nil {
+			continue
+		}
+		if references.ObjectKeyForVirtualGatewayReference(other, *other.Spec.VirtualGatewayRef) == vgKey {
+			count++
+		}
+	}
+	return c.checkCount(ctx, QuotaGatewayRoutesPerVirtualGateway, count)
+}
+
+// checkCount returns an ExceededError if count exceeds the value of quota.
+func (c *defaultChecker) checkCount(ctx context.Context, quotaName string, count int64) error {
+	value, ok := c.quotaValue(ctx, quotaName)
+	if !ok || count <= value {
+		return nil
+	}
+	return &ExceededError{QuotaName: quotaName, Value: value, Count: count}
+}
+
+// quotaValue returns the value of quota, refreshing quotas from the Service Quotas API if they are stale.
+func (c *defaultChecker) quotaValue(ctx context.Context, quotaName string) (int64, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.refreshedAt.IsZero() || c.nowFunc().Sub(c.refreshedAt) >= c.refreshInterval {
+		quotaValueByName, err := c.fetchQuotaValues(ctx)
+		if err != nil {
+			// keep using the quotas fetched previously, and retry upon next refresh.
+			c.log.Error(err, "failed to fetch AppMesh service quotas")
+		} else {
+			c.quotaValueByName = quotaValueByName
+		}
+		c.refreshedAt = c.nowFunc()
+	}
+	value, ok := c.quotaValueByName[strings.ToLower(quotaName)]
+	return value, ok
+}
+
+func (c *defaultChecker) fetchQuotaValues(ctx context.Context) (map[string]int64, error) {
+	quotaValueByName := make(map[string]int64)
+	if err := c.serviceQuotasSDK.ListServiceQuotasPagesWithContext(ctx, &servicequotas.ListServiceQuotasInput{
+		ServiceCode: aws.String(serviceCodeAppMesh),
+	}, func(output *servicequotas.ListServiceQuotasOutput, _ bool) bool {
+		for _, quota := range output.Quotas {
+			if quota.QuotaName == nil || quota.Value == nil {
+				continue
+			}
+			quotaValueByName[strings.ToLower(aws.StringValue(quota.QuotaName))] = int64(aws.Float64Value(quota.Value))
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return quotaValueByName, nil
+}
+
+func isMemberOfMesh(meshRef *appmesh.MeshReference, ms *appmesh.Mesh) bool {
+	return meshRef != nil && meshRef.Name == ms.Name
+}
+
+// NewNoopChecker constructs new Checker that doesn't check any quota.
+func NewNoopChecker() *noopChecker {
+	return &noopChecker{}
+}
+
+var _ Checker = &noopChecker{}
+
+type noopChecker struct{}
+
+func (c *noopChecker) CheckVirtualNode(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode, create bool) error {
+	return nil
+}
+
+func (c *noopChecker) CheckVirtualRouter(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, create bool) error {
+	return nil
+}
+
+func (c *noopChecker) CheckVirtualService(ctx context.Context, ms *appmesh.Mesh, vs *appmesh.VirtualService, create bool) error {
+	return nil
+}
+
+func (c *noopChecker) CheckVirtualGateway(ctx context.Context, ms *appmesh.Mesh, vg *appmesh.VirtualGateway, create bool) error {
+	return nil
+}
+
+func (c *noopChecker) CheckGatewayRoute(ctx context.Context, vg *appmesh.VirtualGateway, gr *appmesh.GatewayRoute, create bool) error {
+	return nil
+}
@@ -0,0 +1,226 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeServiceQuotas struct {
+	services.ServiceQuotas
+	quotas []*servicequotas.ServiceQuota
+	err    error
+	calls  int
+}
+
+func (f *fakeServiceQuotas) ListServiceQuotasPagesWithContext(ctx aws.Context, input *servicequotas.ListServiceQuotasInput, fn func(*servicequotas.ListServiceQuotasOutput, bool) bool, opts ...request.Option) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	fn(&servicequotas.ListServiceQuotasOutput{Quotas: f.quotas}, true)
+	return nil
+}
+
+func newTestChecker(sdk *fakeServiceQuotas) *defaultChecker {
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	return NewDefaultChecker(Config{Enabled: true, RefreshInterval: time.Hour}, sdk, k8sClient, logr.New(&log.NullLogSink{}))
+}
+
+func Test_defaultChecker_CheckVirtualNode(t *testing.T) {
+	ms := &appmesh.Mesh{ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"}}
+	newVN := func(name string, uid types.UID, arn *string, backends int) *appmesh.VirtualNode {
+		vn := &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name, UID: uid},
+			Spec: appmesh.VirtualNodeSpec{
+				MeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "uid-mesh"},
+			},
+			Status: appmesh.VirtualNodeStatus{VirtualNodeARN: arn},
+		}
+		for i := 0; i < backends; i++ {
+			vn.Spec.Backends = append(vn.Spec.Backends, appmesh.Backend{})
+		}
+		return vn
+	}
+	quotas := []*servicequotas.ServiceQuota{
+		{QuotaName: aws.String("Virtual nodes per mesh"), Value: aws.Float64(2)},
+		{QuotaName: aws.String("Backends per virtual node"), Value: aws.Float64(2)},
+	}
+	tests := []struct {
+		name     string
+		existing []*appmesh.VirtualNode
+		vn       *appmesh.VirtualNode
+		create   bool
+		sdkErr   error
+		wantErr  error
+	}{
+		{
+			name:     "create within quotas",
+			existing: []*appmesh.VirtualNode{newVN("vn-1", "uid-1", aws.String("arn-1"), 0)},
+			vn:       newVN("vn-2", "uid-2", nil, 2),
+			create:   true,
+		},
+		{
+			name: "create exceeds virtualNodes per mesh",
+			existing: []*appmesh.VirtualNode{
+				newVN("vn-1", "uid-1", aws.String("arn-1"), 0),
+				newVN("vn-2", "uid-2", aws.String("arn-2"), 0),
+			},
+			vn:      newVN("vn-3", "uid-3", nil, 0),
+			create:  true,
+			wantErr: &ExceededError{QuotaName: QuotaVirtualNodesPerMesh, Value: 2, Count: 3},
+		},
+		{
+			name: "virtualNodes not yet created are not counted",
+			existing: []*appmesh.VirtualNode{
+				newVN("vn-1", "uid-1", aws.String("arn-1"), 0),
+				newVN("vn-2", "uid-2", nil, 0),
+			},
+			vn:     newVN("vn-3", "uid-3", nil, 0),
+			create: true,
+		},
+		{
+			name: "update doesn't check virtualNodes per mesh",
+			existing: []*appmesh.VirtualNode{
+				newVN("vn-1", "uid-1", aws.String("arn-1"), 0),
+				newVN("vn-2", "uid-2", aws.String("arn-2"), 0),
+			},
+			vn:     newVN("vn-3", "uid-3", aws.String("arn-3"), 0),
+			create: false,
+		},
+		{
+			name:    "update exceeds backends per virtualNode",
+			vn:      newVN("vn-1", "uid-1", aws.String("arn-1"), 3),
+			create:  false,
+			wantErr: &ExceededError{QuotaName: QuotaBackendsPerVirtualNode, Value: 2, Count: 3},
+		},
+		{
+			name:   "quotas cannot be fetched",
+			vn:     newVN("vn-1", "uid-1", aws.String("arn-1"), 3),
+			create: false,
+			sdkErr: errors.New("AccessDeniedException"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			checker := newTestChecker(&fakeServiceQuotas{quotas: quotas, err: tt.sdkErr})
+			for _, vn := range tt.existing {
+				assert.NoError(t, checker.k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+			err := checker.CheckVirtualNode(ctx, ms, tt.vn, tt.create)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_defaultChecker_CheckGatewayRoute(t *testing.T) {
+	vg := &appmesh.VirtualGateway{ObjectMeta: metav1.ObjectMeta{Namespace: "gw-ns", Name: "my-vg"}}
+	newGR := func(name string, uid types.UID, vgRef appmesh.VirtualGatewayReference, arn *string) *appmesh.GatewayRoute {
+		return &appmesh.GatewayRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "gw-ns", Name: name, UID: uid},
+			Spec:       appmesh.GatewayRouteSpec{VirtualGatewayRef: &vgRef},
+			Status:     appmesh.GatewayRouteStatus{GatewayRouteARN: arn},
+		}
+	}
+	myVGRef := appmesh.VirtualGatewayReference{Name: "my-vg"}
+	otherVGRef := appmesh.VirtualGatewayReference{Name: "other-vg"}
+	quotas := []*servicequotas.ServiceQuota{
+		{QuotaName: aws.String("Gateway routes per virtual gateway"), Value: aws.Float64(1)},
+	}
+	tests := []struct {
+		name     string
+		existing []*appmesh.GatewayRoute
+		gr       *appmesh.GatewayRoute
+		wantErr  error
+	}{
+		{
+			name:     "gatewayRoutes of other virtualGateways are not counted",
+			existing: []*appmesh.GatewayRoute{newGR("gr-1", "uid-1", otherVGRef, aws.String("arn-1"))},
+			gr:       newGR("gr-2", "uid-2", myVGRef, nil),
+		},
+		{
+			name:     "create exceeds gatewayRoutes per virtualGateway",
+			existing: []*appmesh.GatewayRoute{newGR("gr-1", "uid-1", myVGRef, aws.String("arn-1"))},
+			gr:       newGR("gr-2", "uid-2", myVGRef, nil),
+			wantErr:  &ExceededError{QuotaName: QuotaGatewayRoutesPerVirtualGateway, Value: 1, Count: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			checker := newTestChecker(&fakeServiceQuotas{quotas: quotas})
+			for _, gr := range tt.existing {
+				assert.NoError(t, checker.k8sClient.Create(ctx, gr.DeepCopy()))
+			}
+			err := checker.CheckGatewayRoute(ctx, vg, tt.gr, true)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_defaultChecker_quotaValue(t *testing.T) {
+	sdk := &fakeServiceQuotas{
+		quotas: []*servicequotas.ServiceQuota{
+			{QuotaName: aws.String("Routes per virtual router"), Value: aws.Float64(50)},
+		},
+	}
+	checker := newTestChecker(sdk)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker.nowFunc = func() time.Time { return now }
+	ctx := context.Background()
+
+	value, ok := checker.quotaValue(ctx, QuotaRoutesPerVirtualRouter)
+	assert.True(t, ok)
+	assert.Equal(t, int64(50), value)
+	_, ok = checker.quotaValue(ctx, QuotaVirtualNodesPerMesh)
+	assert.False(t, ok)
+	assert.Equal(t, 1, sdk.calls)
+
+	// quotas fetched previously are kept when refresh fails.
+	now = now.Add(time.Hour)
+	sdk.err = errors.New("ThrottlingException")
+	value, ok = checker.quotaValue(ctx, QuotaRoutesPerVirtualRouter)
+	assert.True(t, ok)
+	assert.Equal(t, int64(50), value)
+	assert.Equal(t, 2, sdk.calls)
+
+	// refreshed quotas take effect.
+	now = now.Add(time.Hour)
+	sdk.err = nil
+	sdk.quotas[0].Value = aws.Float64(100)
+	value, _ = checker.quotaValue(ctx, QuotaRoutesPerVirtualRouter)
+	assert.Equal(t, int64(100), value)
+	assert.Equal(t, 3, sdk.calls)
+}
+
+func TestExceededError_Error(t *testing.T) {
+	err := &ExceededError{QuotaName: QuotaRoutesPerVirtualRouter, Value: 50, Count: 51}
+	assert.Equal(t, `AppMesh service quota "Routes per virtual router" would be exceeded: 51 > 50`, err.Error())
+}
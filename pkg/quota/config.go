@@ -0,0 +1,58 @@
+package quota
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	flagEnableQuotaChecks    = "enable-quota-checks"
+	flagQuotaRefreshInterval = "quota-refresh-interval"
+	flagNamespaceQuotas      = "namespace-quotas-configmap"
+
+	defaultQuotaRefreshInterval = 1 * time.Hour
+)
+
+type Config struct {
+	// Enabled controls whether AppMesh service quotas are checked before creating or updating AppMesh resources.
+	Enabled bool
+	// RefreshInterval is how often AppMesh service quotas are refreshed from the Service Quotas API.
+	RefreshInterval time.Duration
+	// NamespaceQuotasConfigMap is the namespaced name of the ConfigMap with per-namespace quotas, as namespace/name.
+	// per-namespace quotas are disabled if it's empty.
+	NamespaceQuotasConfigMap string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.Enabled, flagEnableQuotaChecks, false,
+		"Enable checking AppMesh service quotas fetched from the Service Quotas API before creating or updating AppMesh resources")
+	fs.DurationVar(&cfg.RefreshInterval, flagQuotaRefreshInterval, defaultQuotaRefreshInterval,
+		"The interval AppMesh service quotas are refreshed from the Service Quotas API")
+	fs.StringVar(&cfg.NamespaceQuotasConfigMap, flagNamespaceQuotas, "",
+		"The ConfigMap with quotas of virtualNodes, virtualRouters and routes per namespace, in the form of namespace/name. Disabled if empty")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Enabled && cfg.RefreshInterval <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagQuotaRefreshInterval, cfg.RefreshInterval)
+	}
+	if len(cfg.NamespaceQuotasConfigMap) != 0 {
+		if _, err := cfg.NamespaceQuotasConfigMapKey(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NamespaceQuotasConfigMapKey returns the namespaced name of the ConfigMap with per-namespace quotas.
+func (cfg *Config) NamespaceQuotasConfigMapKey() (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(cfg.NamespaceQuotasConfigMap, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return types.NamespacedName{}, errors.Errorf("%s must be in the form of namespace/name: %q", flagNamespaceQuotas, cfg.NamespaceQuotasConfigMap)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "enabled with refresh interval",
+			cfg:  Config{Enabled: true, RefreshInterval: time.Hour},
+		},
+		{
+			name: "disabled without refresh interval",
+			cfg:  Config{},
+		},
+		{
+			name:    "enabled without refresh interval",
+			cfg:     Config{Enabled: true},
+			wantErr: "quota-refresh-interval must be positive: 0s",
+		},
+		{
+			name: "namespace quotas configMap",
+			cfg:  Config{NamespaceQuotasConfigMap: "appmesh-system/namespace-quotas"},
+		},
+		{
+			name:    "namespace quotas configMap without namespace",
+			cfg:     Config{NamespaceQuotasConfigMap: "namespace-quotas"},
+			wantErr: `namespace-quotas-configmap must be in the form of namespace/name: "namespace-quotas"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
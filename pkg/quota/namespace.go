@@ -0,0 +1,224 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// NamespaceQuotasDataKey is the data key of the namespace quotas policy in its ConfigMap.
+const NamespaceQuotasDataKey = "quotas.yaml"
+
+// Names of resources capped by NamespaceQuotas.
+const (
+	NamespaceResourceVirtualNodes   = "virtualNodes"
+	NamespaceResourceVirtualRouters = "virtualRouters"
+	NamespaceResourceRoutes         = "routes"
+)
+
+// NamespaceQuotas caps the count of AppMesh resources in a namespace, resources without a cap are unlimited.
+type NamespaceQuotas struct {
+	// VirtualNodes caps the count of virtualNodes in the namespace.
+	VirtualNodes *int64 `json:"virtualNodes,omitempty"`
+	// VirtualRouters caps the count of virtualRouters in the namespace.
+	VirtualRouters *int64 `json:"virtualRouters,omitempty"`
+	// Routes caps the count of routes across virtualRouters in the namespace.
+	Routes *int64 `json:"routes,omitempty"`
+}
+
+// NamespaceQuotasPolicy is the policy stored in the namespace quotas ConfigMap.
+type NamespaceQuotasPolicy struct {
+	// Default are the quotas of namespaces, unless overridden by Namespaces.
+	Default NamespaceQuotas `json:"default,omitempty"`
+	// Namespaces overrides quotas of namespaces by name, per resource.
+	Namespaces map[string]NamespaceQuotas `json:"namespaces,omitempty"`
+}
+
+// quotasForNamespace returns the quotas of namespace, with caps of Namespaces taking precedence over Default.
+func (p *NamespaceQuotasPolicy) quotasForNamespace(namespace string) NamespaceQuotas {
+	quotas := p.Default
+	override, ok := p.Namespaces[namespace]
+	if !ok {
+		return quotas
+	}
+	if override.VirtualNodes != nil {
+		quotas.VirtualNodes = override.VirtualNodes
+	}
+	if override.VirtualRouters != nil {
+		quotas.VirtualRouters = override.VirtualRouters
+	}
+	if override.Routes != nil {
+		quotas.Routes = override.Routes
+	}
+	return quotas
+}
+
+// NamespaceExceededError indicates that applying a resource would exceed the quota of its namespace.
+type NamespaceExceededError struct {
+	// Namespace is the namespace of the resource.
+	Namespace string
+	// Resource is the name of the capped resource, such as virtualNodes.
+	Resource string
+	// Value is the value of the quota.
+	Value int64
+	// Count is the count the resource would result in.
+	Count int64
+	// ConfigMap is the ConfigMap the quota is set by.
+	ConfigMap types.NamespacedName
+}
+
+func (e *NamespaceExceededError) Error() string {
+	return fmt.Sprintf("namespace %q quota of %s would be exceeded: %d > %d, quotas are set by ConfigMap %s",
+		e.Namespace, e.Resource, e.Count, e.Value, e.ConfigMap)
+}
+
+// NamespaceChecker checks AppMesh resources against their namespace's quotas before they are admitted,
+// so that a single namespace can't exhaust the AppMesh service quotas shared by the account.
+// Counts are taken from the k8s objects in the namespace, including those being deleted until they are gone.
+type NamespaceChecker interface {
+	// CheckVirtualNode checks virtualNodes of vn's namespace when vn is to be created.
+	CheckVirtualNode(ctx context.Context, vn *appmesh.VirtualNode) error
+	// CheckVirtualRouter checks virtualRouters and routes of vr's namespace, oldVR is nil when vr is to be created.
+	// Updates are only denied if they add routes to a namespace exceeding its quota of routes.
+	CheckVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter, oldVR *appmesh.VirtualRouter) error
+}
+
+// NewNamespaceChecker constructs new NamespaceChecker with the quotas policy of ConfigMap configMapKey,
+// which is read through apiReader upon every check so that policy changes apply immediately.
+func NewNamespaceChecker(configMapKey types.NamespacedName, k8sClient client.Reader, apiReader client.Reader, log logr.Logger) *namespaceChecker {
+	return &namespaceChecker{
+		configMapKey: configMapKey,
+		k8sClient:    k8sClient,
+		apiReader:    apiReader,
+		log:          log,
+	}
+}
+
+var _ NamespaceChecker = &namespaceChecker{}
+
+type namespaceChecker struct {
+	configMapKey types.NamespacedName
+	k8sClient    client.Reader
+	apiReader    client.Reader
+	log          logr.Logger
+}
+
+func (c *namespaceChecker) CheckVirtualNode(ctx context.Context, vn *appmesh.VirtualNode) error {
+	quotas, err := c.quotasForNamespace(ctx, vn.Namespace)
+	if err != nil {
+		return err
+	}
+	if quotas.VirtualNodes == nil {
+		return nil
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := c.k8sClient.List(ctx, vnList, client.InNamespace(vn.Namespace)); err != nil {
+		return errors.Wrapf(err, "failed to count virtualNodes in namespace %q", vn.Namespace)
+	}
+	count := int64(1)
+	for i := range vnList.Items {
+		if vnList.Items[i].UID != vn.UID {
+			count++
+		}
+	}
+	return c.checkCount(vn.Namespace, NamespaceResourceVirtualNodes, *quotas.VirtualNodes, count)
+}
+
+func (c *namespaceChecker) CheckVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter, oldVR *appmesh.VirtualRouter) error {
+	addedRoutes := int64(len(vr.Spec.Routes))
+	if oldVR != nil {
+		addedRoutes -= int64(len(oldVR.Spec.Routes))
+		if addedRoutes <= 0 {
+			return nil
+		}
+	}
+	quotas, err := c.quotasForNamespace(ctx, vr.Namespace)
+	if err != nil {
+		return err
+	}
+	if quotas.Routes == nil && (oldVR != nil || quotas.VirtualRouters == nil) {
+		return nil
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := c.k8sClient.List(ctx, vrList, client.InNamespace(vr.Namespace)); err != nil {
+		return errors.Wrapf(err, "failed to count virtualRouters in namespace %q", vr.Namespace)
+	}
+	vrCount := int64(1)
+	routeCount := int64(len(vr.Spec.Routes))
+	for i := range vrList.Items {
+		other := &vrList.Items[i]
+		if other.UID == vr.UID {
+			continue
+		}
+		vrCount++
+		routeCount += int64(len(other.Spec.Routes))
+	}
+	if oldVR == nil && quotas.VirtualRouters != nil {
+		if err := c.checkCount(vr.Namespace, NamespaceResourceVirtualRouters, *quotas.VirtualRouters, vrCount); err != nil {
+			return err
+		}
+	}
+	if quotas.Routes != nil {
+		return c.checkCount(vr.Namespace, NamespaceResourceRoutes, *quotas.Routes, routeCount)
+	}
+	return nil
+}
+
+// checkCount returns a NamespaceExceededError if count exceeds value.
+func (c *namespaceChecker) checkCount(namespace string, resource string, value int64, count int64) error {
+	if count <= value {
+		return nil
+	}
+	return &NamespaceExceededError{
+		Namespace: namespace,
+		Resource:  resource,
+		Value:     value,
+		Count:     count,
+		ConfigMap: c.configMapKey,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// quotasForNamespace returns the quotas of namespace, there are no quotas if the ConfigMap doesn't exist.
+// a malformed policy denies admission, so that a typo doesn't silently lift all quotas.
+func (c *namespaceChecker) quotasForNamespace(ctx context.Context, namespace string) (NamespaceQuotas, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.apiReader.Get(ctx, c.configMapKey, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.log.V(1).Info("namespace quotas ConfigMap not found, skipping quota check", "configMap", c.configMapKey)
+			return NamespaceQuotas{}, nil
+		}
+		return NamespaceQuotas{}, errors.Wrapf(err, "failed to get namespace quotas from ConfigMap %s", c.configMapKey)
+	}
+	policy := NamespaceQuotasPolicy{}
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[NamespaceQuotasDataKey]), &policy); err != nil {
+		return NamespaceQuotas{}, errors.Wrapf(err, "failed to parse namespace quotas from key %s of ConfigMap %s", NamespaceQuotasDataKey, c.configMapKey)
+	}
+	return policy.quotasForNamespace(namespace), nil
+}
+
+// NewNoopNamespaceChecker constructs new NamespaceChecker that doesn't check any quota.
+func NewNoopNamespaceChecker() *noopNamespaceChecker {
+	return &noopNamespaceChecker{}
+}
+
+var _ NamespaceChecker = &noopNamespaceChecker{}
+
+type noopNamespaceChecker struct{}
+
+func (c *noopNamespaceChecker) CheckVirtualNode(ctx context.Context, vn *appmesh.VirtualNode) error {
+	return nil
+}
+
+func (c *noopNamespaceChecker) CheckVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter, oldVR *appmesh.VirtualRouter) error {
+	return nil
+}
@@ -0,0 +1,162 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_namespaceChecker(t *testing.T) {
+	configMapKey := types.NamespacedName{Namespace: "appmesh-system", Name: "namespace-quotas"}
+	policy := `
+default:
+  virtualNodes: 2
+  routes: 3
+namespaces:
+  big-ns:
+    virtualNodes: 10
+    virtualRouters: 1
+`
+	vn := func(namespace string, name string) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)}}
+	}
+	vr := func(namespace string, name string, routeNames ...string) *appmesh.VirtualRouter {
+		vr := &appmesh.VirtualRouter{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)}}
+		for _, routeName := range routeNames {
+			vr.Spec.Routes = append(vr.Spec.Routes, appmesh.Route{Name: routeName})
+		}
+		return vr
+	}
+	existing := []runtime.Object{
+		vn("my-ns", "node-a"),
+		vn("my-ns", "node-b"),
+		vn("big-ns", "node-a"),
+		vn("big-ns", "node-b"),
+		vr("my-ns", "router-a", "route-a", "route-b"),
+		vr("big-ns", "router-a", "route-a"),
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]string
+		noPolicy  bool
+		check     func(c *namespaceChecker) error
+		wantErr   string
+		wantQuota bool
+	}{
+		{
+			name: "virtualNode exceeds default quota",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualNode(context.Background(), vn("my-ns", "node-c"))
+			},
+			wantErr:   `namespace "my-ns" quota of virtualNodes would be exceeded: 3 > 2, quotas are set by ConfigMap appmesh-system/namespace-quotas`,
+			wantQuota: true,
+		},
+		{
+			name: "virtualNode within namespace quota",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualNode(context.Background(), vn("big-ns", "node-c"))
+			},
+		},
+		{
+			name: "virtualNode in namespace without objects",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualNode(context.Background(), vn("empty-ns", "node-a"))
+			},
+		},
+		{
+			name: "virtualRouter without quota of virtualRouters",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualRouter(context.Background(), vr("my-ns", "router-b", "route-a"), nil)
+			},
+		},
+		{
+			name: "virtualRouter exceeds namespace quota of virtualRouters",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualRouter(context.Background(), vr("big-ns", "router-b"), nil)
+			},
+			wantErr:   `namespace "big-ns" quota of virtualRouters would be exceeded: 2 > 1, quotas are set by ConfigMap appmesh-system/namespace-quotas`,
+			wantQuota: true,
+		},
+		{
+			name: "virtualRouter exceeds default quota of routes",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualRouter(context.Background(), vr("my-ns", "router-b", "route-a", "route-b"), nil)
+			},
+			wantErr:   `namespace "my-ns" quota of routes would be exceeded: 4 > 3, quotas are set by ConfigMap appmesh-system/namespace-quotas`,
+			wantQuota: true,
+		},
+		{
+			name: "virtualRouter update adds routes beyond quota",
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualRouter(context.Background(),
+					vr("my-ns", "router-a", "route-a", "route-b", "route-c", "route-d"),
+					vr("my-ns", "router-a", "route-a", "route-b"))
+			},
+			wantErr:   `namespace "my-ns" quota of routes would be exceeded: 4 > 3, quotas are set by ConfigMap appmesh-system/namespace-quotas`,
+			wantQuota: true,
+		},
+		{
+			name: "virtualRouter update without adding routes",
+			data: map[string]string{NamespaceQuotasDataKey: "default:\n  routes: 1\n"},
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualRouter(context.Background(),
+					vr("my-ns", "router-a", "route-a", "route-c"),
+					vr("my-ns", "router-a", "route-a", "route-b"))
+			},
+		},
+		{
+			name:     "no policy ConfigMap",
+			noPolicy: true,
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualNode(context.Background(), vn("my-ns", "node-c"))
+			},
+		},
+		{
+			name: "malformed policy",
+			data: map[string]string{NamespaceQuotasDataKey: "default:\n  virtualNode: 1\n"},
+			check: func(c *namespaceChecker) error {
+				return c.CheckVirtualNode(context.Background(), vn("my-ns", "node-c"))
+			},
+			wantErr: `failed to parse namespace quotas from key quotas.yaml of ConfigMap appmesh-system/namespace-quotas: error unmarshaling JSON: while decoding JSON: json: unknown field "virtualNode"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			objs := append([]runtime.Object{}, existing...)
+			if !tt.noPolicy {
+				data := tt.data
+				if data == nil {
+					data = map[string]string{NamespaceQuotasDataKey: policy}
+				}
+				objs = append(objs, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: configMapKey.Namespace, Name: configMapKey.Name},
+					Data:       data,
+				})
+			}
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithRuntimeObjects(objs...).Build()
+			c := NewNamespaceChecker(configMapKey, k8sClient, k8sClient, logr.Discard())
+
+			err := tt.check(c)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				_, isQuotaErr := err.(*NamespaceExceededError)
+				assert.Equal(t, tt.wantQuota, isQuotaErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ObjectReferenceIndexer is responsible for build indexes based on object's reference,
-// and fetch objects based on reference using index.
+// and fetch objects based on reference using index, e.g. the virtualRouters routing to a virtualNode,
+// so that dependents of an object are looked up without listing every object of their kind.
 type ObjectReferenceIndexer interface {
 	Setup(obj client.Object, indexFuncByKind map[string]ObjectReferenceIndexFunc) error
 	Fetch(ctx context.Context, objList client.ObjectList, referentKind string, referentKey types.NamespacedName, opts ...client.ListOption) error
@@ -18,7 +18,9 @@ type ObjectReferenceIndexer interface {
 
 type ObjectReferenceIndexFunc func(obj client.Object) []types.NamespacedName
 
-func NewDefaultObjectReferenceIndexer(k8sCache cache.Cache, k8sFieldIndexer client.FieldIndexer) *defaultObjectReferenceIndexer {
+// NewDefaultObjectReferenceIndexer constructs new ObjectReferenceIndexer, which fetches objects from k8sCache
+// by the indexes set up with k8sFieldIndexer.
+func NewDefaultObjectReferenceIndexer(k8sCache client.Reader, k8sFieldIndexer client.FieldIndexer) *defaultObjectReferenceIndexer {
 	return &defaultObjectReferenceIndexer{
 		k8sCache:        k8sCache,
 		k8sFieldIndexer: k8sFieldIndexer,
@@ -28,21 +30,14 @@ func NewDefaultObjectReferenceIndexer(k8sCache cache.Cache, k8sFieldIndexer clie
 var _ ObjectReferenceIndexer = &defaultObjectReferenceIndexer{}
 
 type defaultObjectReferenceIndexer struct {
-	k8sCache        cache.Cache
+	k8sCache        client.Reader
 	k8sFieldIndexer client.FieldIndexer
 }
 
 func (i *defaultObjectReferenceIndexer) Setup(obj client.Object, indexFuncByKind map[string]ObjectReferenceIndexFunc) error {
-	for kind := range indexFuncByKind {
-		indexFunc := indexFuncByKind[kind]
-		ctrlIndexFunc := func(obj client.Object) []string {
-			var indexValues []string
-			for _, referent := range indexFunc(obj) {
-				indexValues = append(indexValues, buildIndexValue(referent))
-			}
-			return indexValues
-		}
-		if err := i.k8sFieldIndexer.IndexField(context.Background(), obj, buildIndexKey(kind), ctrlIndexFunc); err != nil {
+	for kind, indexFunc := range indexFuncByKind {
+		field, ctrlIndexFunc := BuildIndexField(kind, indexFunc)
+		if err := i.k8sFieldIndexer.IndexField(context.Background(), obj, field, ctrlIndexFunc); err != nil {
 			return err
 		}
 	}
@@ -56,6 +51,18 @@ func (i *defaultObjectReferenceIndexer) Fetch(ctx context.Context, objList clien
 	return i.k8sCache.List(ctx, objList, opts...)
 }
 
+// BuildIndexField builds the field and function indexing objects by their referents of referentKind returned by indexFunc,
+// e.g. to set up the index of a fake client in tests.
+func BuildIndexField(referentKind string, indexFunc ObjectReferenceIndexFunc) (string, client.IndexerFunc) {
+	return buildIndexKey(referentKind), func(obj client.Object) []string {
+		var indexValues []string
+		for _, referent := range indexFunc(obj) {
+			indexValues = append(indexValues, buildIndexValue(referent))
+		}
+		return indexValues
+	}
+}
+
 func buildIndexKey(referentKind string) string {
 	return "objectRefIndex:" + referentKind
 }
@@ -1,9 +1,17 @@
 package references
 
 import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_buildIndexKey(t *testing.T) {
@@ -58,3 +66,44 @@ func Test_buildIndexValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_defaultObjectReferenceIndexer_Fetch(t *testing.T) {
+	// virtualServices are indexed by the virtualNode providing them.
+	indexFunc := func(obj client.Object) []types.NamespacedName {
+		vs := obj.(*appmesh.VirtualService)
+		if vs.Spec.Provider == nil || vs.Spec.Provider.VirtualNode == nil || vs.Spec.Provider.VirtualNode.VirtualNodeRef == nil {
+			return nil
+		}
+		return []types.NamespacedName{ObjectKeyForVirtualNodeReference(vs, *vs.Spec.Provider.VirtualNode.VirtualNodeRef)}
+	}
+	vsProvidedBy := func(namespace string, name string, vnRef *appmesh.VirtualNodeReference) *appmesh.VirtualService {
+		vs := &appmesh.VirtualService{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if vnRef != nil {
+			vs.Spec.Provider = &appmesh.VirtualServiceProvider{VirtualNode: &appmesh.VirtualNodeServiceProvider{VirtualNodeRef: vnRef}}
+		}
+		return vs
+	}
+	k8sSchema := runtime.NewScheme()
+	appmesh.AddToScheme(k8sSchema)
+	field, extractValue := BuildIndexField("VirtualNode", indexFunc)
+	k8sClient := testclient.NewClientBuilder().
+		WithScheme(k8sSchema).
+		WithIndex(&appmesh.VirtualService{}, field, extractValue).
+		WithObjects(
+			vsProvidedBy("my-ns", "vs-1", &appmesh.VirtualNodeReference{Name: "my-vn"}),
+			vsProvidedBy("other-ns", "vs-2", &appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "my-vn"}),
+			vsProvidedBy("other-ns", "vs-3", &appmesh.VirtualNodeReference{Name: "my-vn"}),
+			vsProvidedBy("my-ns", "vs-4", nil),
+		).
+		Build()
+	indexer := NewDefaultObjectReferenceIndexer(k8sClient, nil)
+
+	vsList := &appmesh.VirtualServiceList{}
+	err := indexer.Fetch(context.Background(), vsList, "VirtualNode", types.NamespacedName{Namespace: "my-ns", Name: "my-vn"})
+	assert.NoError(t, err)
+	var got []types.NamespacedName
+	for i := range vsList.Items {
+		got = append(got, types.NamespacedName{Namespace: vsList.Items[i].Namespace, Name: vsList.Items[i].Name})
+	}
+	assert.ElementsMatch(t, []types.NamespacedName{{Namespace: "my-ns", Name: "vs-1"}, {Namespace: "other-ns", Name: "vs-2"}}, got)
+}
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	ControllerMesh           = "mesh"
+	ControllerVirtualGateway = "virtualgateway"
+	ControllerGatewayRoute   = "gatewayroute"
+	ControllerVirtualNode    = "virtualnode"
+	ControllerVirtualService = "virtualservice"
+	ControllerVirtualRouter  = "virtualrouter"
+	ControllerCloudMap       = "cloudmap"
+	ControllerTrafficSplit   = "trafficsplit"
+
+	flagMaxConcurrentReconcilesFmt = "%s-max-concurrent-reconciles"
+	flagRateLimiterBaseDelay       = "reconcile-rate-limiter-base-delay"
+	flagRateLimiterMaxDelay        = "reconcile-rate-limiter-max-delay"
+	flagRateLimiterQPS             = "reconcile-rate-limiter-qps"
+	flagRateLimiterBurst           = "reconcile-rate-limiter-burst"
+	flagReconcileTimeout           = "reconcile-timeout"
+
+	// defaults of rate limiter mirror workqueue.DefaultControllerRateLimiter.
+	defaultRateLimiterBaseDelay = 5 * time.Millisecond
+	defaultRateLimiterMaxDelay  = 1000 * time.Second
+	defaultRateLimiterQPS       = 10
+	defaultRateLimiterBurst     = 100
+
+	defaultReconcileTimeout = 5 * time.Minute
+)
+
+// defaultMaxConcurrentReconciles by controller, in the order flags are bound.
+var defaultMaxConcurrentReconciles = []struct {
+	controller string
+	value      int
+}{
+	{ControllerMesh, 1},
+	{ControllerVirtualGateway, 3},
+	{ControllerGatewayRoute, 3},
+	{ControllerVirtualNode, 3},
+	{ControllerVirtualService, 3},
+	{ControllerVirtualRouter, 3},
+	{ControllerCloudMap, 3},
+	{ControllerTrafficSplit, 3},
+}
+
+type ControllerConfig struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles by controller.
+	MaxConcurrentReconciles map[string]*int
+	// RateLimiterBaseDelay is the delay of the first retry of a failed reconcile, doubled upon every later failure.
+	RateLimiterBaseDelay time.Duration
+	// RateLimiterMaxDelay caps the delay of retrying a failed reconcile.
+	RateLimiterMaxDelay time.Duration
+	// RateLimiterQPS is the overall rate of requeues per second of each controller.
+	RateLimiterQPS float64
+	// RateLimiterBurst is the overall burst of requeues of each controller.
+	RateLimiterBurst int
+	// ReconcileTimeout is how long a reconcile may take before it's canceled and retried, reconciles never time out if it's 0.
+	ReconcileTimeout time.Duration
+}
+
+func (cfg *ControllerConfig) BindFlags(fs *pflag.FlagSet) {
+	cfg.MaxConcurrentReconciles = make(map[string]*int, len(defaultMaxConcurrentReconciles))
+	for _, d := range defaultMaxConcurrentReconciles {
+		cfg.MaxConcurrentReconciles[d.controller] = fs.Int(fmt.Sprintf(flagMaxConcurrentReconcilesFmt, d.controller), d.value,
+			fmt.Sprintf("The maximum number of concurrent reconciles of the %s controller", d.controller))
+	}
+	fs.DurationVar(&cfg.RateLimiterBaseDelay, flagRateLimiterBaseDelay, defaultRateLimiterBaseDelay,
+		"The delay of the first retry of a failed reconcile, doubled upon every later failure of the same object")
+	fs.DurationVar(&cfg.RateLimiterMaxDelay, flagRateLimiterMaxDelay, defaultRateLimiterMaxDelay,
+		"The maximum delay of retrying a failed reconcile")
+	fs.Float64Var(&cfg.RateLimiterQPS, flagRateLimiterQPS, defaultRateLimiterQPS,
+		"The overall number of requeues per second allowed by each controller")
+	fs.IntVar(&cfg.RateLimiterBurst, flagRateLimiterBurst, defaultRateLimiterBurst,
+		"The overall burst of requeues allowed by each controller")
+	fs.DurationVar(&cfg.ReconcileTimeout, flagReconcileTimeout, defaultReconcileTimeout,
+		"How long a reconcile may take before it's canceled, including AWS calls in progress, and retried with backoff. Set to 0 to disable")
+}
+
+func (cfg *ControllerConfig) Validate() error {
+	for _, d := range defaultMaxConcurrentReconciles {
+		if v := cfg.MaxConcurrentReconciles[d.controller]; v != nil && *v < 1 {
+			return errors.Errorf("%s must be positive: %d", fmt.Sprintf(flagMaxConcurrentReconcilesFmt, d.controller), *v)
+		}
+	}
+	if cfg.RateLimiterBaseDelay <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagRateLimiterBaseDelay, cfg.RateLimiterBaseDelay)
+	}
+	if cfg.RateLimiterMaxDelay < cfg.RateLimiterBaseDelay {
+		return errors.Errorf("%s must not be less than %s: %v", flagRateLimiterMaxDelay, flagRateLimiterBaseDelay, cfg.RateLimiterMaxDelay)
+	}
+	if cfg.RateLimiterQPS <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagRateLimiterQPS, cfg.RateLimiterQPS)
+	}
+	if cfg.RateLimiterBurst < 1 {
+		return errors.Errorf("%s must be positive: %d", flagRateLimiterBurst, cfg.RateLimiterBurst)
+	}
+	if cfg.ReconcileTimeout < 0 {
+		return errors.Errorf("%s must not be negative: %v", flagReconcileTimeout, cfg.ReconcileTimeout)
+	}
+	return nil
+}
+
+// Options returns the options of controller, with a rate limiter of its own.
+func (cfg *ControllerConfig) Options(controllerName string) controller.Options {
+	maxConcurrentReconciles := 1
+	if v := cfg.MaxConcurrentReconciles[controllerName]; v != nil {
+		maxConcurrentReconciles = *v
+	}
+	return controller.Options{
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(cfg.RateLimiterBaseDelay, cfg.RateLimiterMaxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(cfg.RateLimiterQPS), cfg.RateLimiterBurst)},
+		),
+	}
+}
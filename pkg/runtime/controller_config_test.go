@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerConfig_BindFlags(t *testing.T) {
+	tests := []struct {
+		name                        string
+		args                        []string
+		wantMaxConcurrentReconciles map[string]int
+		wantRateLimiterQPS          float64
+		wantReconcileTimeout        time.Duration
+	}{
+		{
+			name: "defaults",
+			args: nil,
+			wantMaxConcurrentReconciles: map[string]int{
+				ControllerMesh:           1,
+				ControllerVirtualNode:    3,
+				ControllerVirtualService: 3,
+				ControllerCloudMap:       3,
+			},
+			wantRateLimiterQPS:   10,
+			wantReconcileTimeout: 5 * time.Minute,
+		},
+		{
+			name: "overridden",
+			args: []string{"--mesh-max-concurrent-reconciles=2", "--virtualnode-max-concurrent-reconciles=20", "--reconcile-rate-limiter-qps=50", "--reconcile-timeout=30s"},
+			wantMaxConcurrentReconciles: map[string]int{
+				ControllerMesh:           2,
+				ControllerVirtualNode:    20,
+				ControllerVirtualService: 3,
+				ControllerCloudMap:       3,
+			},
+			wantRateLimiterQPS:   50,
+			wantReconcileTimeout: 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ControllerConfig{}
+			fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+			cfg.BindFlags(fs)
+			assert.NoError(t, fs.Parse(tt.args))
+			assert.NoError(t, cfg.Validate())
+			for controller, want := range tt.wantMaxConcurrentReconciles {
+				assert.Equal(t, want, cfg.Options(controller).MaxConcurrentReconciles, controller)
+			}
+			assert.Equal(t, tt.wantRateLimiterQPS, cfg.RateLimiterQPS)
+			assert.Equal(t, tt.wantReconcileTimeout, cfg.ReconcileTimeout)
+		})
+	}
+}
+
+func TestControllerConfig_Validate(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name    string
+		cfg     ControllerConfig
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: ControllerConfig{
+				RateLimiterBaseDelay: 5 * time.Millisecond,
+				RateLimiterMaxDelay:  time.Minute,
+				RateLimiterQPS:       10,
+				RateLimiterBurst:     100,
+			},
+		},
+		{
+			name: "non-positive max concurrent reconciles",
+			cfg: ControllerConfig{
+				MaxConcurrentReconciles: map[string]*int{ControllerVirtualRouter: &zero},
+				RateLimiterBaseDelay:    5 * time.Millisecond,
+				RateLimiterMaxDelay:     time.Minute,
+				RateLimiterQPS:          10,
+				RateLimiterBurst:        100,
+			},
+			wantErr: "virtualrouter-max-concurrent-reconciles must be positive: 0",
+		},
+		{
+			name: "max delay less than base delay",
+			cfg: ControllerConfig{
+				RateLimiterBaseDelay: time.Second,
+				RateLimiterMaxDelay:  time.Millisecond,
+				RateLimiterQPS:       10,
+				RateLimiterBurst:     100,
+			},
+			wantErr: "reconcile-rate-limiter-max-delay must not be less than reconcile-rate-limiter-base-delay: 1ms",
+		},
+		{
+			name: "non-positive qps",
+			cfg: ControllerConfig{
+				RateLimiterBaseDelay: 5 * time.Millisecond,
+				RateLimiterMaxDelay:  time.Minute,
+				RateLimiterQPS:       0,
+				RateLimiterBurst:     100,
+			},
+			wantErr: "reconcile-rate-limiter-qps must be positive: 0",
+		},
+		{
+			name: "negative reconcile timeout",
+			cfg: ControllerConfig{
+				RateLimiterBaseDelay: 5 * time.Millisecond,
+				RateLimiterMaxDelay:  time.Minute,
+				RateLimiterQPS:       10,
+				RateLimiterBurst:     100,
+				ReconcileTimeout:     -time.Second,
+			},
+			wantErr: "reconcile-timeout must not be negative: -1s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestControllerConfig_Options(t *testing.T) {
+	cfg := ControllerConfig{
+		RateLimiterBaseDelay: 5 * time.Millisecond,
+		RateLimiterMaxDelay:  20 * time.Millisecond,
+		RateLimiterQPS:       1000,
+		RateLimiterBurst:     1000,
+	}
+	limiter := cfg.Options(ControllerVirtualNode).RateLimiter
+	assert.Equal(t, 5*time.Millisecond, limiter.When("vn"))
+	assert.Equal(t, 10*time.Millisecond, limiter.When("vn"))
+	assert.Equal(t, 20*time.Millisecond, limiter.When("vn"))
+	assert.Equal(t, 20*time.Millisecond, limiter.When("vn"))
+
+	anotherLimiter := cfg.Options(ControllerVirtualNode).RateLimiter
+	assert.Equal(t, 5*time.Millisecond, anotherLimiter.When("vn"))
+}
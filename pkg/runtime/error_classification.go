@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	servicediscoverysdk "github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ThrottledRequeueInterval is how long the processing item whose AWS calls are throttled is requeued after,
+// instead of retrying with backoff, which starts at a few milliseconds and adds to the throttled calls.
+const ThrottledRequeueInterval = 30 * time.Second
+
+// terminalAWSErrorCodes are codes of AWS errors rejecting the request itself, e.g. for an invalid spec, which retries can't resolve.
+var terminalAWSErrorCodes = sets.NewString(
+	appmeshsdk.ErrCodeBadRequestException,
+	servicediscoverysdk.ErrCodeInvalidInput,
+	"ValidationException",
+)
+
+// ClassifyAWSError classifies err from AWS calls by how the processing item should be requeued upon it:
+//   - Terminal: AWS rejects the request itself, e.g. for an invalid spec. It's returned as TerminalError, and isn't requeued until the processing item changes.
+//   - ThrottledRequeueAfter: AWS throttles the request. It's returned as RequeueAfterError, and requeued after ThrottledRequeueInterval.
+//   - Retryable: any other error. It's returned as is, and requeued with backoff.
+//
+// err is returned as is if it's already classified.
+func ClassifyAWSError(err error) error {
+	if err == nil || isClassifiedError(err) {
+		return err
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return err
+	}
+	if terminalAWSErrorCodes.Has(awsErr.Code()) {
+		return NewTerminalError(err)
+	}
+	if request.IsErrorThrottle(awsErr) {
+		return NewRequeueAfterError(err, ThrottledRequeueInterval)
+	}
+	return err
+}
+
+// isClassifiedError tests whether err already instructs controller-runtime how to requeue the processing item.
+func isClassifiedError(err error) bool {
+	var requeueErr *RequeueError
+	var requeueAfterErr *RequeueAfterError
+	return errors.As(err, &requeueErr) || errors.As(err, &requeueAfterErr) ||
+		IsDependencyNotReadyError(err) || IsTerminalError(err) || circuitbreaker.IsOpenError(err)
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyAWSError(t *testing.T) {
+	badRequestErr := awserr.NewRequestFailure(awserr.New("BadRequestException", "invalid listener", nil), 400, "request-id")
+	throttlingErr := awserr.NewRequestFailure(awserr.New("TooManyRequestsException", "rate exceeded", nil), 429, "request-id")
+	serviceUnavailableErr := awserr.NewRequestFailure(awserr.New("ServiceUnavailableException", "service unavailable", nil), 503, "request-id")
+	tests := []struct {
+		name             string
+		err              error
+		wantTerminal     bool
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+		},
+		{
+			name: "non AWS error",
+			err:  errors.New("some error"),
+		},
+		{
+			name:         "bad request error",
+			err:          errors.Wrap(badRequestErr, "failed to update virtualNode"),
+			wantTerminal: true,
+		},
+		{
+			name:         "validation error",
+			err:          awserr.New("ValidationException", "invalid tag", nil),
+			wantTerminal: true,
+		},
+		{
+			name:             "throttling error",
+			err:              errors.Wrap(throttlingErr, "failed to update route"),
+			wantRequeueAfter: ThrottledRequeueInterval,
+		},
+		{
+			name: "server error",
+			err:  errors.Wrap(serviceUnavailableErr, "failed to update route"),
+		},
+		{
+			name: "circuit breaker OpenError",
+			err:  circuitbreaker.NewOpenError("App Mesh", 5, 30*time.Second, 12*time.Second),
+		},
+		{
+			name:             "already classified error",
+			err:              NewRequeueAfterError(badRequestErr, 5*time.Second),
+			wantRequeueAfter: 5 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyAWSError(tt.err)
+			if tt.err == nil {
+				assert.NoError(t, got)
+				return
+			}
+			assert.EqualError(t, got, tt.err.Error())
+			assert.Equal(t, tt.wantTerminal, IsTerminalError(got))
+			var requeueAfterErr *RequeueAfterError
+			if tt.wantRequeueAfter != 0 {
+				assert.True(t, errors.As(got, &requeueAfterErr))
+				assert.Equal(t, tt.wantRequeueAfter, requeueAfterErr.Duration())
+			} else {
+				assert.False(t, errors.As(got, &requeueAfterErr))
+			}
+		})
+	}
+}
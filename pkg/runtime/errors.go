@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // NewRequeueError constructs new RequeueError to
@@ -66,3 +68,76 @@ func (e *RequeueAfterError) Duration() time.Duration {
 func (e *RequeueAfterError) Unwrap() error {
 	return e.err
 }
+
+// DependencyRecheckInterval is how long the processing item waiting for a dependency is requeued after,
+// in case the events of the dependency enqueuing it are missed.
+const DependencyRecheckInterval = 10 * time.Minute
+
+// NewDependencyNotReadyError constructs new DependencyNotReadyError to
+// instruct controller-runtime to wait for events of the dependency to requeue the processing item, without been logged as error.
+func NewDependencyNotReadyError(err error) *DependencyNotReadyError {
+	return &DependencyNotReadyError{
+		err: err,
+	}
+}
+
+var _ error = &DependencyNotReadyError{}
+
+// An error to instruct controller-runtime to wait for events of the dependency to requeue the processing item, without been logged as error.
+// This should be used when a dependency isn't found or active yet, and events of the dependency enqueue the processing item, e.g.
+// the VirtualRouter is enqueued once the VirtualNode targeted by its routes is created or becomes active.
+// The processing item is still requeued after DependencyRecheckInterval in case such events are missed.
+type DependencyNotReadyError struct {
+	err error
+}
+
+func (e *DependencyNotReadyError) Error() string {
+	if e.err == nil {
+		return ""
+	}
+	return e.err.Error()
+}
+
+func (e *DependencyNotReadyError) Unwrap() error {
+	return e.err
+}
+
+// IsDependencyNotReadyError tests whether err indicates a dependency isn't found or active yet.
+func IsDependencyNotReadyError(err error) bool {
+	var dependencyErr *DependencyNotReadyError
+	return errors.As(err, &dependencyErr)
+}
+
+// NewTerminalError constructs new TerminalError to
+// instruct controller-runtime not to requeue the processing item until it changes, without been logged as error.
+func NewTerminalError(err error) *TerminalError {
+	return &TerminalError{
+		err: err,
+	}
+}
+
+var _ error = &TerminalError{}
+
+// An error to instruct controller-runtime not to requeue the processing item until it changes, without been logged as error.
+// This should be used when retries can't resolve the "error condition", e.g. AWS rejects the spec of the processing item as invalid,
+// which should be surfaced by its conditions instead. The processing item is enqueued again by its own events once its spec changes.
+type TerminalError struct {
+	err error
+}
+
+func (e *TerminalError) Error() string {
+	if e.err == nil {
+		return ""
+	}
+	return e.err.Error()
+}
+
+func (e *TerminalError) Unwrap() error {
+	return e.err
+}
+
+// IsTerminalError tests whether err indicates retries can't resolve it.
+func IsTerminalError(err error) bool {
+	var terminalErr *TerminalError
+	return errors.As(err, &terminalErr)
+}
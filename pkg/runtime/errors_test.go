@@ -93,3 +93,71 @@ func TestNewRequeueAfterError(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDependencyNotReadyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "DependencyNotReadyError",
+			err:  NewDependencyNotReadyError(errors.New("virtualNode is not active yet")),
+			want: true,
+		},
+		{
+			name: "wrapped DependencyNotReadyError",
+			err:  errors.Wrap(NewDependencyNotReadyError(errors.New("virtualNode is not active yet")), "failed to reconcile"),
+			want: true,
+		},
+		{
+			name: "other error",
+			err:  NewRequeueError(errors.New("mesh is not active yet")),
+			want: false,
+		},
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDependencyNotReadyError(tt.err))
+		})
+	}
+}
+
+func TestIsTerminalError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "TerminalError",
+			err:  NewTerminalError(errors.New("invalid listener")),
+			want: true,
+		},
+		{
+			name: "wrapped TerminalError",
+			err:  errors.Wrap(NewTerminalError(errors.New("invalid listener")), "failed to update virtualNode"),
+			want: true,
+		},
+		{
+			name: "other error",
+			err:  NewRequeueError(errors.New("invalid listener")),
+			want: false,
+		},
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTerminalError(tt.err))
+		})
+	}
+}
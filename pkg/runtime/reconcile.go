@@ -1,9 +1,14 @@
 package runtime
 
 import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // HandleReconcileError will handle errors from reconcile handlers, which respects runtime errors.
@@ -18,6 +23,24 @@ func HandleReconcileError(err error, log logr.Logger) (ctrl.Result, error) {
 		return ctrl.Result{RequeueAfter: requeueAfterErr.Duration()}, nil
 	}
 
+	var dependencyErr *DependencyNotReadyError
+	if errors.As(err, &dependencyErr) {
+		log.V(1).Info("wait for dependency", "error", dependencyErr.Unwrap())
+		return ctrl.Result{RequeueAfter: DependencyRecheckInterval}, nil
+	}
+
+	var terminalErr *TerminalError
+	if errors.As(err, &terminalErr) {
+		log.Info("wait for changes due to terminal error", "error", terminalErr.Unwrap())
+		return ctrl.Result{}, nil
+	}
+
+	var circuitOpenErr *circuitbreaker.OpenError
+	if errors.As(err, &circuitOpenErr) {
+		log.V(1).Info("requeue after circuit breaker is open", "duration", circuitOpenErr.RetryAfter(), "error", err)
+		return ctrl.Result{RequeueAfter: circuitOpenErr.RetryAfter()}, nil
+	}
+
 	var requeueError *RequeueError
 	if errors.As(err, &requeueError) {
 		log.V(1).Info("requeue due to error", "error", requeueError.Unwrap())
@@ -26,3 +49,20 @@ func HandleReconcileError(err error, log logr.Logger) (ctrl.Result, error) {
 
 	return ctrl.Result{}, err
 }
+
+// NewTimeoutReconciler wraps reconciler to cancel reconciles that take longer than timeout, including AWS calls in progress,
+// so they fail and are retried with backoff instead of blocking the worker. reconciles never time out if timeout is 0.
+func NewTimeoutReconciler(timeout time.Duration, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if timeout == 0 {
+		return reconciler
+	}
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		result, err := reconciler.Reconcile(ctx, req)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result, errors.Wrapf(err, "reconcile timed out after %v", timeout)
+		}
+		return result, err
+	})
+}
@@ -1,11 +1,14 @@
 package runtime
 
 import (
+	"context"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/circuitbreaker"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"testing"
 	"time"
 )
@@ -48,6 +51,34 @@ func TestHandleReconcileError(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "input err is DependencyNotReadyError",
+			args: args{
+				err: errors.Wrap(NewDependencyNotReadyError(errors.New("virtualNode is not active yet")), "failed to reconcile"),
+			},
+			want: ctrl.Result{
+				RequeueAfter: DependencyRecheckInterval,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "input err is TerminalError",
+			args: args{
+				err: errors.Wrap(NewTerminalError(errors.New("invalid listener")), "failed to update virtualNode"),
+			},
+			want:    ctrl.Result{},
+			wantErr: nil,
+		},
+		{
+			name: "input err is circuit breaker OpenError",
+			args: args{
+				err: errors.Wrap(circuitbreaker.NewOpenError("App Mesh", 5, 30*time.Second, 12*time.Second), "failed to describe virtualNode"),
+			},
+			want: ctrl.Result{
+				RequeueAfter: 12 * time.Second,
+			},
+			wantErr: nil,
+		},
 		{
 			name: "input err is other error type",
 			args: args{
@@ -69,3 +100,45 @@ func TestHandleReconcileError(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTimeoutReconciler(t *testing.T) {
+	// reconcileUntilCanceled blocks like a hung AWS call, until its context is done or it's waited for long enough.
+	reconcileUntilCanceled := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		select {
+		case <-ctx.Done():
+			return ctrl.Result{}, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+			return ctrl.Result{}, nil
+		}
+	})
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantErr string
+	}{
+		{
+			name:    "reconcile times out",
+			timeout: 10 * time.Millisecond,
+			wantErr: "reconcile timed out after 10ms: context deadline exceeded",
+		},
+		{
+			name:    "reconcile completes within timeout",
+			timeout: time.Minute,
+		},
+		{
+			name:    "timeout disabled",
+			timeout: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTimeoutReconciler(tt.timeout, reconcileUntilCanceled)
+			_, err := r.Reconcile(context.Background(), ctrl.Request{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
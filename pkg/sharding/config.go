@@ -0,0 +1,76 @@
+package sharding
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+const (
+	flagShardCount      = "shard-count"
+	flagShardIndex      = "shard-index"
+	flagWatchNamespaces = "watch-namespaces"
+)
+
+type Config struct {
+	// ShardCount is the number of shards reconciling resources, each shard is a separate set of controller replicas.
+	// sharding is disabled if it's 1.
+	ShardCount int
+	// ShardIndex is the index of the shard of this controller, within [0, ShardCount).
+	ShardIndex int
+	// WatchNamespaces are the namespaces whose resources are watched and reconciled by this controller.
+	// every namespace is watched if it's empty.
+	WatchNamespaces []string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&cfg.ShardCount, flagShardCount, 1,
+		"The number of shards reconciling resources, resources are assigned to shards by the hash of their namespace")
+	fs.IntVar(&cfg.ShardIndex, flagShardIndex, 0,
+		"The index of the shard of this controller, within [0, shard-count)")
+	fs.StringSliceVar(&cfg.WatchNamespaces, flagWatchNamespaces, nil,
+		"Namespaces whose resources are watched and reconciled by this controller, every namespace if empty. Meshes are reconciled if they select any of these namespaces")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.ShardCount < 1 {
+		return errors.Errorf("%s must be positive: %d", flagShardCount, cfg.ShardCount)
+	}
+	if cfg.ShardIndex < 0 || cfg.ShardIndex >= cfg.ShardCount {
+		return errors.Errorf("%s must be within [0, %d): %d", flagShardIndex, cfg.ShardCount, cfg.ShardIndex)
+	}
+	for _, namespace := range cfg.WatchNamespaces {
+		if len(namespace) == 0 {
+			return errors.Errorf("%s must not contain empty namespaces", flagWatchNamespaces)
+		}
+	}
+	return nil
+}
+
+// Enabled returns whether sharding is enabled.
+func (cfg *Config) Enabled() bool {
+	return cfg.ShardCount > 1
+}
+
+// LeaderElectionID returns the leader election ID of the shard, so replicas of every shard elect a leader of their own.
+func (cfg *Config) LeaderElectionID(id string) string {
+	if !cfg.Enabled() {
+		return id
+	}
+	return fmt.Sprintf("%s-shard-%d", id, cfg.ShardIndex)
+}
+
+// Namespaced returns whether this controller only watches resources within WatchNamespaces.
+func (cfg *Config) Namespaced() bool {
+	return len(cfg.WatchNamespaces) > 0
+}
+
+// NewCacheFunc returns the constructor of the informer cache, which only watches namespaced resources within WatchNamespaces if set.
+func (cfg *Config) NewCacheFunc() cache.NewCacheFunc {
+	if !cfg.Namespaced() {
+		return cache.New
+	}
+	return cache.MultiNamespacedCacheBuilder(cfg.WatchNamespaces)
+}
@@ -0,0 +1,77 @@
+package sharding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "sharding disabled",
+			cfg:  Config{ShardCount: 1, ShardIndex: 0},
+		},
+		{
+			name: "sharding enabled",
+			cfg:  Config{ShardCount: 4, ShardIndex: 3},
+		},
+		{
+			name:    "non-positive shard count",
+			cfg:     Config{ShardCount: 0, ShardIndex: 0},
+			wantErr: "shard-count must be positive: 0",
+		},
+		{
+			name:    "shard index out of range",
+			cfg:     Config{ShardCount: 4, ShardIndex: 4},
+			wantErr: "shard-index must be within [0, 4): 4",
+		},
+		{
+			name: "watching namespaces",
+			cfg:  Config{ShardCount: 1, ShardIndex: 0, WatchNamespaces: []string{"ns-a", "ns-b"}},
+		},
+		{
+			name:    "watching empty namespace",
+			cfg:     Config{ShardCount: 1, ShardIndex: 0, WatchNamespaces: []string{"ns-a", ""}},
+			wantErr: "watch-namespaces must not contain empty namespaces",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_LeaderElectionID(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "sharding disabled",
+			cfg:  Config{ShardCount: 1, ShardIndex: 0},
+			want: "appmesh-controller-leader-election",
+		},
+		{
+			name: "sharding enabled",
+			cfg:  Config{ShardCount: 4, ShardIndex: 2},
+			want: "appmesh-controller-leader-election-shard-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.LeaderElectionID("appmesh-controller-leader-election"))
+		})
+	}
+}
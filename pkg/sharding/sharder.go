@@ -0,0 +1,125 @@
+package sharding
+
+import (
+	"context"
+	"hash/fnv"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Sharder decides which resources are reconciled by this controller.
+type Sharder interface {
+	// Owns returns whether resources within namespace are reconciled by this controller.
+	// cluster scoped resources have an empty namespace.
+	Owns(namespace string) bool
+
+	// OwnsMesh returns whether mesh meshName is reconciled by this controller.
+	// meshes are owned by every controller watching a namespace they select.
+	OwnsMesh(ctx context.Context, meshName string) (bool, error)
+}
+
+// NewSharder constructs new Sharder, which owns every resource if sharding is disabled and every namespace is watched.
+// k8sClient is used to match namespaces selected by meshes against watched namespaces.
+func NewSharder(cfg Config, k8sClient client.Reader) Sharder {
+	return &sharder{
+		k8sClient:       k8sClient,
+		shardCount:      cfg.ShardCount,
+		shardIndex:      cfg.ShardIndex,
+		watchNamespaces: sets.NewString(cfg.WatchNamespaces...),
+	}
+}
+
+var _ Sharder = &sharder{}
+
+type sharder struct {
+	k8sClient       client.Reader
+	shardCount      int
+	shardIndex      int
+	watchNamespaces sets.String
+}
+
+func (s *sharder) Owns(namespace string) bool {
+	if len(namespace) != 0 && s.watchNamespaces.Len() != 0 && !s.watchNamespaces.Has(namespace) {
+		return false
+	}
+	return ShardOf(namespace, s.shardCount) == s.shardIndex
+}
+
+func (s *sharder) OwnsMesh(ctx context.Context, meshName string) (bool, error) {
+	if !s.Owns("") {
+		return false, nil
+	}
+	if s.watchNamespaces.Len() == 0 {
+		return true, nil
+	}
+	ms := &appmesh.Mesh{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: meshName}, ms); err != nil {
+		// reconcilers handle meshes that no longer exist on their own.
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get mesh: %s", meshName)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ms.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	for _, namespace := range s.watchNamespaces.List() {
+		ns := &corev1.Namespace{}
+		if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to get namespace: %s", namespace)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ShardOf returns the shard owning resources within namespace, out of shardCount shards.
+// cluster scoped resources are always owned by shard 0.
+func ShardOf(namespace string, shardCount int) int {
+	if shardCount <= 1 || len(namespace) == 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shardCount))
+}
+
+// NewReconciler wraps reconciler to only reconcile requests owned by sharder, requests owned by other shards are ignored.
+func NewReconciler(sharder Sharder, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if !sharder.Owns(req.Namespace) {
+			return ctrl.Result{}, nil
+		}
+		return reconciler.Reconcile(ctx, req)
+	})
+}
+
+// NewMeshReconciler wraps reconciler of meshes to only reconcile meshes owned by sharder, other meshes are ignored.
+func NewMeshReconciler(sharder Sharder, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		owns, err := sharder.OwnsMesh(ctx, req.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !owns {
+			return ctrl.Result{}, nil
+		}
+		return reconciler.Reconcile(ctx, req)
+	})
+}
@@ -0,0 +1,189 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestShardOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespace  string
+		shardCount int
+		want       int
+	}{
+		{
+			name:       "sharding disabled",
+			namespace:  "my-ns",
+			shardCount: 1,
+			want:       0,
+		},
+		{
+			name:       "cluster scoped",
+			namespace:  "",
+			shardCount: 4,
+			want:       0,
+		},
+		{
+			name:       "namespaced",
+			namespace:  "my-ns",
+			shardCount: 4,
+			want:       1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShardOf(tt.namespace, tt.shardCount))
+		})
+	}
+}
+
+func TestShardOf_distribution(t *testing.T) {
+	shardCount := 4
+	countByShard := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		countByShard[ShardOf(fmt.Sprintf("ns-%d", i), shardCount)]++
+	}
+	for shard := 0; shard < shardCount; shard++ {
+		assert.InDelta(t, 250, countByShard[shard], 50, "shard %d", shard)
+	}
+}
+
+func TestNewReconciler(t *testing.T) {
+	sharder := NewSharder(Config{ShardCount: 4, ShardIndex: 1}, nil)
+	var reconciled []string
+	r := NewReconciler(sharder, reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		reconciled = append(reconciled, req.Namespace)
+		return ctrl.Result{}, nil
+	}))
+	for _, ns := range []string{"my-ns", "", "ns-0", "ns-1", "ns-2", "ns-3"} {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: "obj"}})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"my-ns", "ns-2"}, reconciled)
+}
+
+func Test_sharder_Owns(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		namespace string
+		want      bool
+	}{
+		{
+			name:      "every namespace watched",
+			cfg:       Config{ShardCount: 1},
+			namespace: "my-ns",
+			want:      true,
+		},
+		{
+			name:      "namespace watched",
+			cfg:       Config{ShardCount: 1, WatchNamespaces: []string{"my-ns", "other-ns"}},
+			namespace: "my-ns",
+			want:      true,
+		},
+		{
+			name:      "namespace not watched",
+			cfg:       Config{ShardCount: 1, WatchNamespaces: []string{"other-ns"}},
+			namespace: "my-ns",
+			want:      false,
+		},
+		{
+			name:      "namespace watched but owned by other shard",
+			cfg:       Config{ShardCount: 4, ShardIndex: 0, WatchNamespaces: []string{"my-ns"}},
+			namespace: "my-ns",
+			want:      false,
+		},
+		{
+			name:      "cluster scoped",
+			cfg:       Config{ShardCount: 1, WatchNamespaces: []string{"other-ns"}},
+			namespace: "",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewSharder(tt.cfg, nil).Owns(tt.namespace))
+		})
+	}
+}
+
+func Test_sharder_OwnsMesh(t *testing.T) {
+	teamANS := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"mesh": "team-a"}},
+	}
+	teamBNS := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"mesh": "team-b"}},
+	}
+	teamAMesh := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: appmesh.MeshSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "team-a"}},
+		},
+	}
+	tests := []struct {
+		name     string
+		cfg      Config
+		meshName string
+		want     bool
+	}{
+		{
+			name:     "every namespace watched",
+			cfg:      Config{ShardCount: 1},
+			meshName: "team-a",
+			want:     true,
+		},
+		{
+			name:     "mesh owned by other shard",
+			cfg:      Config{ShardCount: 4, ShardIndex: 1},
+			meshName: "team-a",
+			want:     false,
+		},
+		{
+			name:     "mesh selects watched namespace",
+			cfg:      Config{ShardCount: 1, WatchNamespaces: []string{"team-a", "team-c"}},
+			meshName: "team-a",
+			want:     true,
+		},
+		{
+			name:     "mesh selects no watched namespace",
+			cfg:      Config{ShardCount: 1, WatchNamespaces: []string{"team-b"}},
+			meshName: "team-a",
+			want:     false,
+		},
+		{
+			name:     "mesh doesn't exist",
+			cfg:      Config{ShardCount: 1, WatchNamespaces: []string{"team-b"}},
+			meshName: "deleted",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			assert.NoError(t, k8sClient.Create(ctx, teamANS.DeepCopy()))
+			assert.NoError(t, k8sClient.Create(ctx, teamBNS.DeepCopy()))
+			assert.NoError(t, k8sClient.Create(ctx, teamAMesh.DeepCopy()))
+
+			got, err := NewSharder(tt.cfg, k8sClient).OwnsMesh(ctx, tt.meshName)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package smi
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	flagEnableTrafficSplit     = "enable-smi-traffic-split"
+	flagTrafficSplitAPIVersion = "smi-traffic-split-api-version"
+
+	defaultTrafficSplitAPIVersion = "v1alpha2"
+)
+
+// supportedTrafficSplitAPIVersions are versions of TrafficSplit API with integer backend weights.
+var supportedTrafficSplitAPIVersions = []string{"v1alpha2", "v1alpha3", "v1alpha4"}
+
+type Config struct {
+	// EnableTrafficSplit controls whether SMI TrafficSplits are synchronized with weighted targets of VirtualRouter routes.
+	EnableTrafficSplit bool
+	// TrafficSplitAPIVersion is the version of TrafficSplit API served by the cluster.
+	TrafficSplitAPIVersion string
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.EnableTrafficSplit, flagEnableTrafficSplit, false,
+		"If enabled, backend weights of SMI TrafficSplits are kept in sync with weighted targets of the VirtualRouter route of their VirtualService")
+	fs.StringVar(&cfg.TrafficSplitAPIVersion, flagTrafficSplitAPIVersion, defaultTrafficSplitAPIVersion,
+		"The version of SMI TrafficSplit API served by the cluster: v1alpha2, v1alpha3 or v1alpha4")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.EnableTrafficSplit {
+		return nil
+	}
+	for _, version := range supportedTrafficSplitAPIVersions {
+		if cfg.TrafficSplitAPIVersion == version {
+			return nil
+		}
+	}
+	return errors.Errorf("%s must be one of %v: %q", flagTrafficSplitAPIVersion, supportedTrafficSplitAPIVersions, cfg.TrafficSplitAPIVersion)
+}
+
+// TrafficSplitGVK returns the GroupVersionKind of TrafficSplits.
+// It's handled as unstructured to avoid depending on SMI API.
+func (cfg *Config) TrafficSplitGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "split.smi-spec.io",
+		Version: cfg.TrafficSplitAPIVersion,
+		Kind:    "TrafficSplit",
+	}
+}
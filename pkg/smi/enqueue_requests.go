@@ -0,0 +1,51 @@
+package smi
+
+import (
+	"context"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewEnqueueRequestsForVirtualRouterEvents constructs new handler that enqueues TrafficSplits synchronized with the VirtualRouter.
+func NewEnqueueRequestsForVirtualRouterEvents(k8sClient client.Client, trafficSplitGVK schema.GroupVersionKind, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(vr client.Object) []reconcile.Request {
+		vrKey := k8s.NamespacedName(vr).String()
+		return listTrafficSplitRequests(k8sClient, trafficSplitGVK, log, func(ts *unstructured.Unstructured) bool {
+			return ts.GetAnnotations()[AnnotationVirtualRouter] == vrKey
+		})
+	})
+}
+
+// NewEnqueueRequestsForVirtualServiceEvents constructs new handler that enqueues TrafficSplits whose root service is the VirtualService.
+func NewEnqueueRequestsForVirtualServiceEvents(k8sClient client.Client, trafficSplitGVK schema.GroupVersionKind, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(vs client.Object) []reconcile.Request {
+		return listTrafficSplitRequests(k8sClient, trafficSplitGVK, log, func(ts *unstructured.Unstructured) bool {
+			service, _ := trafficSplitService(ts)
+			return ts.GetNamespace() == vs.GetNamespace() && service == vs.GetName()
+		}, client.InNamespace(vs.GetNamespace()))
+	})
+}
+
+func listTrafficSplitRequests(k8sClient client.Client, trafficSplitGVK schema.GroupVersionKind, log logr.Logger,
+	matches func(ts *unstructured.Unstructured) bool, opts ...client.ListOption) []reconcile.Request {
+	tsList := &unstructured.UnstructuredList{}
+	tsList.SetGroupVersionKind(trafficSplitGVK.GroupVersion().WithKind(trafficSplitGVK.Kind + "List"))
+	if err := k8sClient.List(context.Background(), tsList, opts...); err != nil {
+		log.Error(err, "failed to enqueue trafficSplits")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range tsList.Items {
+		ts := &tsList.Items[i]
+		if matches(ts) {
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.NamespacedName(ts)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,258 @@
+package smi
+
+import (
+	"context"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxTargetWeight is the maximum weight of weighted targets accepted by AppMesh.
+	maxTargetWeight = 100
+)
+
+// ErrInvalidTrafficSplit is wrapped by errors of TrafficSplits that can't be synchronized until they or their mesh resources are fixed.
+var ErrInvalidTrafficSplit = errors.New("invalid trafficSplit")
+
+// Synchronizer synchronizes SMI TrafficSplits with weighted targets of VirtualRouter routes.
+type Synchronizer interface {
+	// Sync synchronizes weights of ts and the route of the VirtualRouter providing the VirtualService named by its root service.
+	// weights of ts are applied to the route if ts changed since the last synchronization, otherwise changed weights of the route are applied to ts.
+	Sync(ctx context.Context, ts *unstructured.Unstructured) error
+}
+
+// NewDefaultSynchronizer constructs new Synchronizer.
+func NewDefaultSynchronizer(k8sClient client.Client, log logr.Logger) Synchronizer {
+	return &defaultSynchronizer{
+		k8sClient: k8sClient,
+		log:       log,
+	}
+}
+
+var _ Synchronizer = &defaultSynchronizer{}
+
+// defaultSynchronizer maps TrafficSplit backend services to VirtualNodes of the same name within its namespace.
+type defaultSynchronizer struct {
+	k8sClient client.Client
+	log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups=split.smi-spec.io,resources=trafficsplits,verbs=get;list;watch;update;patch
+
+func (s *defaultSynchronizer) Sync(ctx context.Context, ts *unstructured.Unstructured) error {
+	splitWeights, err := trafficSplitBackendWeights(ts)
+	if err != nil {
+		return errors.Wrap(ErrInvalidTrafficSplit, err.Error())
+	}
+	vr, err := s.findVirtualRouter(ctx, ts)
+	if err != nil {
+		return err
+	}
+	route, err := findRoute(vr, ts.GetAnnotations()[AnnotationRoute])
+	if err != nil {
+		return errors.Wrap(ErrInvalidTrafficSplit, err.Error())
+	}
+	routeWeights := routeBackendWeights(vr, route, ts.GetNamespace(), splitWeights)
+
+	syncedWeights := ts.GetAnnotations()[AnnotationSyncedWeights]
+	vrKey := k8s.NamespacedName(vr).String()
+	switch {
+	case splitWeights.String() != syncedWeights:
+		for service, weight := range splitWeights {
+			if weight < 0 || weight > maxTargetWeight {
+				return errors.Wrapf(ErrInvalidTrafficSplit, "weight of backend %s must be between 0 and %d: %d", service, maxTargetWeight, weight)
+			}
+		}
+		if splitWeights.String() != routeWeights.String() {
+			if err := s.updateRouteWeights(ctx, vr, route.Name, ts.GetNamespace(), splitWeights); err != nil {
+				return err
+			}
+			s.log.V(1).Info("applied trafficSplit weights to route",
+				"trafficSplit", k8s.NamespacedName(ts),
+				"virtualRouter", vrKey,
+				"route", route.Name,
+				"weights", splitWeights.String(),
+			)
+		}
+		return s.updateTrafficSplit(ctx, ts, vrKey, nil, splitWeights)
+	case routeWeights.String() != syncedWeights:
+		if err := s.updateTrafficSplit(ctx, ts, vrKey, routeWeights, routeWeights); err != nil {
+			return err
+		}
+		s.log.V(1).Info("applied route weights to trafficSplit",
+			"trafficSplit", k8s.NamespacedName(ts),
+			"virtualRouter", vrKey,
+			"route", route.Name,
+			"weights", routeWeights.String(),
+		)
+		return nil
+	default:
+		return s.updateTrafficSplit(ctx, ts, vrKey, nil, splitWeights)
+	}
+}
+
+// findVirtualRouter finds the VirtualRouter providing the VirtualService named by the root service of ts within its namespace.
+func (s *defaultSynchronizer) findVirtualRouter(ctx context.Context, ts *unstructured.Unstructured) (*appmesh.VirtualRouter, error) {
+	service, err := trafficSplitService(ts)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTrafficSplit, err.Error())
+	}
+	vs := &appmesh.VirtualService{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: ts.GetNamespace(), Name: service}, vs); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, errors.Wrapf(ErrInvalidTrafficSplit, "virtualService %s not found", service)
+		}
+		return nil, errors.Wrapf(err, "failed to get virtualService %s", service)
+	}
+	if vs.Spec.Provider == nil || vs.Spec.Provider.VirtualRouter == nil || vs.Spec.Provider.VirtualRouter.VirtualRouterRef == nil {
+		return nil, errors.Wrapf(ErrInvalidTrafficSplit, "virtualService %s isn't provided by a virtualRouter reference", service)
+	}
+	vrKey := references.ObjectKeyForVirtualRouterReference(vs, *vs.Spec.Provider.VirtualRouter.VirtualRouterRef)
+	vr := &appmesh.VirtualRouter{}
+	if err := s.k8sClient.Get(ctx, vrKey, vr); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, errors.Wrapf(ErrInvalidTrafficSplit, "virtualRouter %v not found", vrKey)
+		}
+		return nil, errors.Wrapf(err, "failed to get virtualRouter %v", vrKey)
+	}
+	return vr, nil
+}
+
+// updateRouteWeights sets weighted targets of route to VirtualNodes of the services in weights, keeping ports of existing targets.
+func (s *defaultSynchronizer) updateRouteWeights(ctx context.Context, vr *appmesh.VirtualRouter, routeName string, namespace string, weights backendWeights) error {
+	oldVR := vr.DeepCopy()
+	route, err := findRoute(vr, routeName)
+	if err != nil {
+		return err
+	}
+	targets := routeWeightedTargets(route)
+	existingTargets := make(map[string]appmesh.WeightedTarget, len(*targets))
+	for _, target := range *targets {
+		if name, ok := targetVirtualNodeName(vr, target, namespace); ok {
+			existingTargets[name] = target
+		}
+	}
+	newTargets := make([]appmesh.WeightedTarget, 0, len(weights))
+	for _, service := range sortedServices(weights) {
+		target, ok := existingTargets[service]
+		if !ok {
+			target = appmesh.WeightedTarget{
+				VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String(namespace), Name: service},
+			}
+		}
+		target.Weight = weights[service]
+		newTargets = append(newTargets, target)
+	}
+	*targets = newTargets
+	if err := s.k8sClient.Patch(ctx, vr, client.MergeFrom(oldVR)); err != nil {
+		return errors.Wrapf(err, "failed to update weights of route %s of virtualRouter %v", routeName, k8s.NamespacedName(vr))
+	}
+	return nil
+}
+
+// updateTrafficSplit records the synchronization of ts, as well as replacing its backend weights if weights is non-nil.
+func (s *defaultSynchronizer) updateTrafficSplit(ctx context.Context, ts *unstructured.Unstructured, vrKey string, weights backendWeights, syncedWeights backendWeights) error {
+	oldTS := ts.DeepCopy()
+	if weights != nil {
+		if err := setTrafficSplitBackendWeights(ts, weights); err != nil {
+			return err
+		}
+	}
+	annotations := ts.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if weights == nil && annotations[AnnotationVirtualRouter] == vrKey && annotations[AnnotationSyncedWeights] == syncedWeights.String() {
+		return nil
+	}
+	annotations[AnnotationVirtualRouter] = vrKey
+	annotations[AnnotationSyncedWeights] = syncedWeights.String()
+	ts.SetAnnotations(annotations)
+	if err := s.k8sClient.Patch(ctx, ts, client.MergeFrom(oldTS)); err != nil {
+		return errors.Wrapf(err, "failed to update trafficSplit %v", k8s.NamespacedName(ts))
+	}
+	return nil
+}
+
+// findRoute returns the route of vr named routeName, or its only route if routeName is empty.
+func findRoute(vr *appmesh.VirtualRouter, routeName string) (*appmesh.Route, error) {
+	if len(routeName) == 0 {
+		if len(vr.Spec.Routes) != 1 {
+			return nil, errors.Errorf("virtualRouter %v has %d routes, select one with annotation %s", k8s.NamespacedName(vr), len(vr.Spec.Routes), AnnotationRoute)
+		}
+		routeName = vr.Spec.Routes[0].Name
+	}
+	for i := range vr.Spec.Routes {
+		route := &vr.Spec.Routes[i]
+		if route.Name != routeName {
+			continue
+		}
+		if routeWeightedTargets(route) == nil {
+			return nil, errors.Errorf("route %s of virtualRouter %v has no action", routeName, k8s.NamespacedName(vr))
+		}
+		return route, nil
+	}
+	return nil, errors.Errorf("virtualRouter %v has no route %s", k8s.NamespacedName(vr), routeName)
+}
+
+// routeWeightedTargets returns the weighted targets of the action of route, or nil if it has no action.
+func routeWeightedTargets(route *appmesh.Route) *[]appmesh.WeightedTarget {
+	switch {
+	case route.HTTPRoute != nil:
+		return &route.HTTPRoute.Action.WeightedTargets
+	case route.HTTP2Route != nil:
+		return &route.HTTP2Route.Action.WeightedTargets
+	case route.GRPCRoute != nil:
+		return &route.GRPCRoute.Action.WeightedTargets
+	case route.TCPRoute != nil:
+		return &route.TCPRoute.Action.WeightedTargets
+	default:
+		return nil
+	}
+}
+
+// routeBackendWeights returns the weights of route targets of services in splitWeights, services without a target have weight 0.
+func routeBackendWeights(vr *appmesh.VirtualRouter, route *appmesh.Route, namespace string, splitWeights backendWeights) backendWeights {
+	weights := make(backendWeights, len(splitWeights))
+	for service := range splitWeights {
+		weights[service] = 0
+	}
+	for _, target := range *routeWeightedTargets(route) {
+		if name, ok := targetVirtualNodeName(vr, target, namespace); ok {
+			if _, ok := weights[name]; ok {
+				weights[name] = target.Weight
+			}
+		}
+	}
+	return weights
+}
+
+// targetVirtualNodeName returns the name of VirtualNode referenced by target if it's within namespace.
+func targetVirtualNodeName(vr *appmesh.VirtualRouter, target appmesh.WeightedTarget, namespace string) (string, bool) {
+	if target.VirtualNodeRef == nil {
+		return "", false
+	}
+	vnKey := references.ObjectKeyForVirtualNodeReference(vr, *target.VirtualNodeRef)
+	if vnKey.Namespace != namespace {
+		return "", false
+	}
+	return vnKey.Name, true
+}
+
+func sortedServices(weights backendWeights) []string {
+	services := make([]string, 0, len(weights))
+	for service := range weights {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
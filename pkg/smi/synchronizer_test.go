@@ -0,0 +1,208 @@
+package smi
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestTrafficSplit(annotations map[string]string, weights ...interface{}) *unstructured.Unstructured {
+	var backends []interface{}
+	for i := 0; i+1 < len(weights); i += 2 {
+		backends = append(backends, map[string]interface{}{"service": weights[i], "weight": weights[i+1]})
+	}
+	ts := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"service":  "my-svc",
+			"backends": backends,
+		},
+	}}
+	ts.SetGroupVersionKind((&Config{TrafficSplitAPIVersion: "v1alpha2"}).TrafficSplitGVK())
+	ts.SetNamespace("my-ns")
+	ts.SetName("my-split")
+	ts.SetAnnotations(annotations)
+	return ts
+}
+
+func newTestVirtualRouter(targets ...appmesh.WeightedTarget) *appmesh.VirtualRouter {
+	return &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-router"},
+		Spec: appmesh.VirtualRouterSpec{
+			Routes: []appmesh.Route{
+				{
+					Name: "my-route",
+					HTTPRoute: &appmesh.HTTPRoute{
+						Action: appmesh.HTTPRouteAction{WeightedTargets: targets},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_defaultSynchronizer_Sync(t *testing.T) {
+	port := aws.Int64(8080)
+	tests := []struct {
+		name            string
+		ts              *unstructured.Unstructured
+		vr              *appmesh.VirtualRouter
+		wantTargets     []appmesh.WeightedTarget
+		wantTSWeights   backendWeights
+		wantSynced      string
+		wantErrInvalid  bool
+		wantErrContains string
+	}{
+		{
+			name: "applies new trafficSplit to route",
+			ts:   newTestTrafficSplit(nil, "my-svc-primary", int64(90), "my-svc-canary", int64(10)),
+			vr: newTestVirtualRouter(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 100, Port: port},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "other-node"}, Weight: 0},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "my-svc-canary"}, Weight: 10},
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 90, Port: port},
+			},
+			wantTSWeights: backendWeights{"my-svc-primary": 90, "my-svc-canary": 10},
+			wantSynced:    "my-svc-canary=10,my-svc-primary=90",
+		},
+		{
+			name: "applies changed route to trafficSplit",
+			ts: newTestTrafficSplit(map[string]string{AnnotationSyncedWeights: "my-svc-canary=10,my-svc-primary=90"},
+				"my-svc-primary", int64(90), "my-svc-canary", int64(10)),
+			vr: newTestVirtualRouter(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 50},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-canary"}, Weight: 50},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 50},
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-canary"}, Weight: 50},
+			},
+			wantTSWeights: backendWeights{"my-svc-primary": 50, "my-svc-canary": 50},
+			wantSynced:    "my-svc-canary=50,my-svc-primary=50",
+		},
+		{
+			name: "trafficSplit changes win over route changes",
+			ts: newTestTrafficSplit(map[string]string{AnnotationSyncedWeights: "my-svc-canary=10,my-svc-primary=90"},
+				"my-svc-primary", int64(80), "my-svc-canary", int64(20)),
+			vr: newTestVirtualRouter(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 50},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-canary"}, Weight: 50},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-canary"}, Weight: 20},
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 80},
+			},
+			wantTSWeights: backendWeights{"my-svc-primary": 80, "my-svc-canary": 20},
+			wantSynced:    "my-svc-canary=20,my-svc-primary=80",
+		},
+		{
+			name: "rejects weights AppMesh doesn't accept",
+			ts:   newTestTrafficSplit(nil, "my-svc-primary", int64(900), "my-svc-canary", int64(100)),
+			vr: newTestVirtualRouter(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 100},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "my-svc-primary"}, Weight: 100},
+			},
+			wantTSWeights:   backendWeights{"my-svc-primary": 900, "my-svc-canary": 100},
+			wantErrInvalid:  true,
+			wantErrContains: "weight of backend my-svc-primary must be between 0 and 100: 900",
+		},
+		{
+			name: "rejects virtualRouter with multiple routes without route annotation",
+			ts:   newTestTrafficSplit(nil, "my-svc-primary", int64(100)),
+			vr: func() *appmesh.VirtualRouter {
+				vr := newTestVirtualRouter()
+				vr.Spec.Routes = append(vr.Spec.Routes, appmesh.Route{Name: "other-route", TCPRoute: &appmesh.TCPRoute{}})
+				return vr
+			}(),
+			wantTSWeights:   backendWeights{"my-svc-primary": 100},
+			wantErrInvalid:  true,
+			wantErrContains: "virtualRouter my-ns/my-router has 2 routes, select one with annotation appmesh.k8s.aws/route",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			vs := &appmesh.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-svc"},
+				Spec: appmesh.VirtualServiceSpec{
+					Provider: &appmesh.VirtualServiceProvider{
+						VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+							VirtualRouterRef: &appmesh.VirtualRouterReference{Name: "my-router"},
+						},
+					},
+				},
+			}
+			assert.NoError(t, k8sClient.Create(ctx, vs))
+			assert.NoError(t, k8sClient.Create(ctx, tt.vr.DeepCopy()))
+			assert.NoError(t, k8sClient.Create(ctx, tt.ts.DeepCopy()))
+			ts := tt.ts.DeepCopy()
+			assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ts), ts))
+
+			s := NewDefaultSynchronizer(k8sClient, logr.New(&log.NullLogSink{}))
+			err := s.Sync(ctx, ts)
+			if tt.wantErrContains != "" {
+				assert.Contains(t, err.Error(), tt.wantErrContains)
+				assert.Equal(t, tt.wantErrInvalid, errors.Is(err, ErrInvalidTrafficSplit))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			vr := &appmesh.VirtualRouter{}
+			assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "my-ns", Name: "my-router"}, vr))
+			assert.Equal(t, tt.wantTargets, vr.Spec.Routes[0].HTTPRoute.Action.WeightedTargets)
+
+			assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ts), ts))
+			gotTSWeights, err := trafficSplitBackendWeights(ts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTSWeights, gotTSWeights)
+			if tt.wantSynced != "" {
+				assert.Equal(t, tt.wantSynced, ts.GetAnnotations()[AnnotationSyncedWeights])
+				assert.Equal(t, "my-ns/my-router", ts.GetAnnotations()[AnnotationVirtualRouter])
+
+				assert.NoError(t, s.Sync(ctx, ts))
+				syncedTS := ts.DeepCopy()
+				assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ts), syncedTS))
+				assert.Equal(t, ts.GetResourceVersion(), syncedTS.GetResourceVersion(), "synchronized trafficSplits aren't updated again")
+			}
+		})
+	}
+}
+
+func Test_backendWeight(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    int64
+		wantErr bool
+	}{
+		{value: int64(10), want: 10},
+		{value: float64(20), want: 20},
+		{value: "30", want: 30},
+		{value: nil, want: 0},
+		{value: "30%", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := backendWeight(tt.value)
+		assert.Equal(t, tt.wantErr, err != nil, "%v", tt.value)
+		assert.Equal(t, tt.want, got, "%v", tt.value)
+	}
+}
@@ -0,0 +1,101 @@
+package smi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// AnnotationRoute selects the route of VirtualRouter a TrafficSplit is synchronized with, required if it has multiple routes.
+	AnnotationRoute = "appmesh.k8s.aws/route"
+	// AnnotationVirtualRouter records the namespaced name of VirtualRouter a TrafficSplit is synchronized with.
+	AnnotationVirtualRouter = "appmesh.k8s.aws/virtualRouter"
+	// AnnotationSyncedWeights records the backend weights of last synchronization, used to tell which side changed since.
+	AnnotationSyncedWeights = "appmesh.k8s.aws/syncedWeights"
+)
+
+// backendWeights are the weights of TrafficSplit backends by service name.
+type backendWeights map[string]int64
+
+// String encodes weights as comma separated service=weight pairs sorted by service.
+func (w backendWeights) String() string {
+	pairs := make([]string, 0, len(w))
+	for service, weight := range w {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", service, weight))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// trafficSplitService returns the root service of ts.
+func trafficSplitService(ts *unstructured.Unstructured) (string, error) {
+	service, _, err := unstructured.NestedString(ts.Object, "spec", "service")
+	if err != nil {
+		return "", errors.Wrap(err, "invalid spec.service")
+	}
+	if len(service) == 0 {
+		return "", errors.New("spec.service must be specified")
+	}
+	return service, nil
+}
+
+// trafficSplitBackendWeights returns the weights of backends of ts.
+func trafficSplitBackendWeights(ts *unstructured.Unstructured) (backendWeights, error) {
+	backends, _, err := unstructured.NestedSlice(ts.Object, "spec", "backends")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid spec.backends")
+	}
+	weights := make(backendWeights, len(backends))
+	for i, backend := range backends {
+		backendMap, ok := backend.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid spec.backends[%d]", i)
+		}
+		service, _, err := unstructured.NestedString(backendMap, "service")
+		if err != nil || len(service) == 0 {
+			return nil, errors.Errorf("spec.backends[%d].service must be specified", i)
+		}
+		weight, err := backendWeight(backendMap["weight"])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid spec.backends[%d].weight", i)
+		}
+		weights[service] = weight
+	}
+	return weights, nil
+}
+
+// setTrafficSplitBackendWeights sets the weights of existing backends of ts, backends without a weight are set to 0.
+func setTrafficSplitBackendWeights(ts *unstructured.Unstructured, weights backendWeights) error {
+	backends, _, err := unstructured.NestedSlice(ts.Object, "spec", "backends")
+	if err != nil {
+		return errors.Wrap(err, "invalid spec.backends")
+	}
+	for _, backend := range backends {
+		if backendMap, ok := backend.(map[string]interface{}); ok {
+			service, _, _ := unstructured.NestedString(backendMap, "service")
+			backendMap["weight"] = weights[service]
+		}
+	}
+	return unstructured.SetNestedSlice(ts.Object, backends, "spec", "backends")
+}
+
+// backendWeight parses weights, which are integers since v1alpha2 but may be decoded as float or string.
+func backendWeight(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case nil:
+		return 0, nil
+	default:
+		return 0, errors.Errorf("unsupported weight: %v", v)
+	}
+}
@@ -0,0 +1,204 @@
+package tagging
+
+import (
+	"context"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	appmeshaws "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Backfiller tags the AppMesh resources of existing CRDs once upon start, e.g. resources created before tagging was enabled,
+// so that their ownership is tagged without waiting for them to be reconciled.
+type Backfiller interface {
+	manager.Runnable
+	manager.LeaderElectionRunnable
+}
+
+// NewBackfiller constructs new Backfiller, which tags AppMesh resources of CRDs owned by sharder with tagsManager.
+// only resources in accountID are tagged, resources of meshes shared from other accounts are left to reconciles.
+func NewBackfiller(k8sClient client.Reader, tagsManager Manager, namespaceRoleResolver appmeshaws.NamespaceRoleResolver, sharder sharding.Sharder, accountID string, log logr.Logger) Backfiller {
+	return &backfiller{
+		k8sClient:             k8sClient,
+		tagsManager:           tagsManager,
+		namespaceRoleResolver: namespaceRoleResolver,
+		sharder:               sharder,
+		accountID:             accountID,
+		log:                   log,
+	}
+}
+
+var _ Backfiller = &backfiller{}
+
+type backfiller struct {
+	k8sClient             client.Reader
+	tagsManager           Manager
+	namespaceRoleResolver appmeshaws.NamespaceRoleResolver
+	sharder               sharding.Sharder
+	accountID             string
+	log                   logr.Logger
+}
+
+// backfillTarget is a CRD with the ARNs of its AppMesh resources, the first of which is its own.
+type backfillTarget struct {
+	obj          client.Object
+	resourceARNs []string
+}
+
+// backfillResult counts the CRDs by outcome of their backfill.
+type backfillResult struct {
+	tagged  int
+	skipped int
+	failed  int
+}
+
+func (b *backfiller) Start(ctx context.Context) error {
+	targets, err := b.listTargets(ctx)
+	if err != nil {
+		b.log.Error(err, "failed to backfill tags")
+		return nil
+	}
+	result := backfillResult{}
+	for _, target := range targets {
+		b.backfill(ctx, target, &result)
+	}
+	b.log.Info("backfilled tags",
+		"tagged", result.tagged,
+		"skipped", result.skipped,
+		"failed", result.failed,
+	)
+	return nil
+}
+
+// NeedLeaderElection returns true, only the leader backfills tags.
+func (b *backfiller) NeedLeaderElection() bool {
+	return true
+}
+
+func (b *backfiller) backfill(ctx context.Context, target backfillTarget, result *backfillResult) {
+	obj := target.obj
+	if !b.shouldBackfill(ctx, target) {
+		result.skipped++
+		return
+	}
+	ctx, err := b.namespaceRoleResolver.WithNamespaceRole(ctx, obj.GetNamespace())
+	if err != nil {
+		b.log.Error(err, "failed to backfill tags", "object", k8s.NamespacedName(obj))
+		result.failed++
+		return
+	}
+	if err := b.tagsManager.CheckOwnership(ctx, target.resourceARNs[0], obj); err != nil {
+		if IsOwnershipConflict(err) {
+			b.log.V(1).Info("skip backfilling tags of resource owned by another owner", "object", k8s.NamespacedName(obj), "reason", err.Error())
+			result.skipped++
+			return
+		}
+		b.log.Error(err, "failed to backfill tags", "object", k8s.NamespacedName(obj))
+		result.failed++
+		return
+	}
+	for _, resourceARN := range target.resourceARNs {
+		if err := b.tagsManager.ReconcileTags(ctx, resourceARN, obj); err != nil {
+			b.log.Error(err, "failed to backfill tags", "object", k8s.NamespacedName(obj), "resourceARN", resourceARN)
+			result.failed++
+			return
+		}
+	}
+	result.tagged++
+}
+
+// shouldBackfill checks whether tags of target are to be backfilled, which excludes CRDs being deleted, paused or owned by another shard,
+// as well as resources outside of the controller's account.
+func (b *backfiller) shouldBackfill(ctx context.Context, target backfillTarget) bool {
+	obj := target.obj
+	if !obj.GetDeletionTimestamp().IsZero() || k8s.IsReconcilePaused(obj) {
+		return false
+	}
+	if _, ok := obj.(*appmesh.Mesh); ok {
+		owns, err := b.sharder.OwnsMesh(ctx, obj.GetName())
+		if err != nil || !owns {
+			return false
+		}
+	} else if !b.sharder.Owns(obj.GetNamespace()) {
+		return false
+	}
+	resourceARN, err := arn.Parse(target.resourceARNs[0])
+	return err == nil && resourceARN.AccountID == b.accountID
+}
+
+// listTargets lists CRDs of every kind tagged by the controller, which have AppMesh resources.
+func (b *backfiller) listTargets(ctx context.Context) ([]backfillTarget, error) {
+	var targets []backfillTarget
+	addTarget := func(obj client.Object, resourceARN *string, otherARNs ...string) {
+		if len(aws.StringValue(resourceARN)) == 0 {
+			return
+		}
+		targets = append(targets, backfillTarget{obj: obj, resourceARNs: append([]string{aws.StringValue(resourceARN)}, otherARNs...)})
+	}
+
+	msList := &appmesh.MeshList{}
+	if err := b.k8sClient.List(ctx, msList); err != nil {
+		return nil, err
+	}
+	for i := range msList.Items {
+		addTarget(&msList.Items[i], msList.Items[i].Status.MeshARN)
+	}
+	vgList := &appmesh.VirtualGatewayList{}
+	if err := b.k8sClient.List(ctx, vgList); err != nil {
+		return nil, err
+	}
+	for i := range vgList.Items {
+		addTarget(&vgList.Items[i], vgList.Items[i].Status.VirtualGatewayARN)
+	}
+	grList := &appmesh.GatewayRouteList{}
+	if err := b.k8sClient.List(ctx, grList); err != nil {
+		return nil, err
+	}
+	for i := range grList.Items {
+		addTarget(&grList.Items[i], grList.Items[i].Status.GatewayRouteARN)
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := b.k8sClient.List(ctx, vnList); err != nil {
+		return nil, err
+	}
+	for i := range vnList.Items {
+		addTarget(&vnList.Items[i], vnList.Items[i].Status.VirtualNodeARN)
+	}
+	vsList := &appmesh.VirtualServiceList{}
+	if err := b.k8sClient.List(ctx, vsList); err != nil {
+		return nil, err
+	}
+	for i := range vsList.Items {
+		addTarget(&vsList.Items[i], vsList.Items[i].Status.VirtualServiceARN)
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := b.k8sClient.List(ctx, vrList); err != nil {
+		return nil, err
+	}
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		// routes carry the tags of their virtualRouter.
+		var routeARNs []string
+		for _, routeName := range sortedKeys(vr.Status.RouteARNs) {
+			routeARNs = append(routeARNs, vr.Status.RouteARNs[routeName])
+		}
+		addTarget(vr, vr.Status.VirtualRouterARN, routeARNs...)
+	}
+	return targets, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package tagging
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	appmeshaws "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/sharding"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeTagsAppMesh serves and records tags of resources by ARN.
+type fakeTagsAppMesh struct {
+	services.AppMesh
+	tagsByARN map[string]map[string]string
+}
+
+func (s *fakeTagsAppMesh) ListTagsForResourcePagesWithContext(_ aws.Context, input *appmeshsdk.ListTagsForResourceInput, fn func(*appmeshsdk.ListTagsForResourceOutput, bool) bool, _ ...request.Option) error {
+	fn(&appmeshsdk.ListTagsForResourceOutput{Tags: buildSDKTagRefs(s.tagsByARN[aws.StringValue(input.ResourceArn)])}, true)
+	return nil
+}
+
+func (s *fakeTagsAppMesh) TagResourceWithContext(_ aws.Context, input *appmeshsdk.TagResourceInput, _ ...request.Option) (*appmeshsdk.TagResourceOutput, error) {
+	resourceARN := aws.StringValue(input.ResourceArn)
+	if s.tagsByARN[resourceARN] == nil {
+		s.tagsByARN[resourceARN] = make(map[string]string)
+	}
+	for _, tag := range input.Tags {
+		s.tagsByARN[resourceARN][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &appmeshsdk.TagResourceOutput{}, nil
+}
+
+func Test_backfiller_Start(t *testing.T) {
+	const (
+		vnARN           = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualNode/node-a_ns"
+		vrARN           = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualRouter/router-a_ns"
+		routeARN        = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualRouter/router-a_ns/route/route-a"
+		pausedVSARN     = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualService/svc-a.ns"
+		sharedVNARN     = "arn:aws:appmesh:us-west-2:210987654321:mesh/shared-mesh/virtualNode/node-b_ns"
+		otherClusterARN = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualNode/node-c_ns"
+	)
+	wantTags := func(name string, uid string) map[string]string {
+		return map[string]string{
+			TagKeyManagedBy: TagValueManagedBy,
+			TagKeyCluster:   "my-cluster",
+			TagKeyNamespace: "ns",
+			TagKeyName:      name,
+			TagKeyUID:       uid,
+		}
+	}
+	objs := []runtime.Object{
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-a", UID: "uid-vn"},
+			Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String(vnARN)},
+		},
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-b", UID: "uid-shared"},
+			Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String(sharedVNARN)},
+		},
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-c", UID: "uid-other-cluster"},
+			Status:     appmesh.VirtualNodeStatus{VirtualNodeARN: aws.String(otherClusterARN)},
+		},
+		&appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-d", UID: "uid-not-created"},
+		},
+		&appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "router-a", UID: "uid-vr"},
+			Status: appmesh.VirtualRouterStatus{
+				VirtualRouterARN: aws.String(vrARN),
+				RouteARNs:        map[string]string{"route-a": routeARN},
+			},
+		},
+		&appmesh.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc-a", UID: "uid-vs", Annotations: map[string]string{k8s.ReconcileAnnotation: k8s.ReconcilePaused}},
+			Status:     appmesh.VirtualServiceStatus{VirtualServiceARN: aws.String(pausedVSARN)},
+		},
+	}
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithRuntimeObjects(objs...).Build()
+
+	sdk := &fakeTagsAppMesh{tagsByARN: map[string]map[string]string{
+		otherClusterARN: {TagKeyCluster: "other-cluster", TagKeyNamespace: "ns", TagKeyName: "node-c"},
+	}}
+	tagsManager := NewDefaultManager(Config{Enabled: true, TagBasedOwnership: true}, sdk, "my-cluster", logr.Discard())
+	b := NewBackfiller(k8sClient, tagsManager, appmeshaws.NewNamespaceRoleResolver(k8sClient, false), sharding.NewSharder(sharding.Config{ShardCount: 1}, k8sClient), "123456789012", logr.Discard())
+	assert.NoError(t, b.Start(context.Background()))
+
+	assert.Equal(t, map[string]map[string]string{
+		vnARN:    wantTags("node-a", "uid-vn"),
+		vrARN:    wantTags("router-a", "uid-vr"),
+		routeARN: wantTags("router-a", "uid-vr"),
+		// resources owned by other clusters are left as is.
+		otherClusterARN: {TagKeyCluster: "other-cluster", TagKeyNamespace: "ns", TagKeyName: "node-c"},
+	}, sdk.tagsByARN)
+}
@@ -0,0 +1,76 @@
+package tagging
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagEnableResourceTagging = "enable-resource-tagging"
+	flagTagLabelKeys          = "tag-label-keys"
+	flagTagAnnotationKeys     = "tag-annotation-keys"
+	flagControllerID          = "controller-id"
+	flagTagBasedOwnership     = "tag-based-ownership"
+	flagBackfillTags          = "backfill-tags"
+
+	// the prefix of tag keys reserved by AWS.
+	awsReservedTagKeyPrefix = "aws:"
+	// maximum length of tag keys accepted by AppMesh.
+	maxTagKeyLength = 128
+)
+
+type Config struct {
+	// Enabled controls whether AppMesh resources are tagged.
+	Enabled bool
+	// LabelKeys are keys of CRD labels that are propagated as tags.
+	LabelKeys []string
+	// AnnotationKeys are keys of CRD annotations that are propagated as tags.
+	AnnotationKeys []string
+	// ControllerID identifies the controller among controllers sharing meshes, AppMesh resources tagged with another ID aren't updated or deleted.
+	ControllerID string
+	// TagBasedOwnership controls whether AppMesh resources tagged with another cluster, or another CRD's namespace and name, aren't updated or deleted.
+	TagBasedOwnership bool
+	// BackfillTags controls whether AppMesh resources of existing CRDs are tagged once upon start.
+	BackfillTags bool
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.Enabled, flagEnableResourceTagging, false,
+		"Enable tagging AppMesh resources with controller identity tags, as well as labels and annotations selected by tag-label-keys and tag-annotation-keys")
+	fs.StringSliceVar(&cfg.LabelKeys, flagTagLabelKeys, nil,
+		"Comma separated keys of CRD labels to propagate as tags of AppMesh resources")
+	fs.StringSliceVar(&cfg.AnnotationKeys, flagTagAnnotationKeys, nil,
+		"Comma separated keys of CRD annotations to propagate as tags of AppMesh resources")
+	fs.StringVar(&cfg.ControllerID, flagControllerID, "",
+		"ID of the controller tagged on AppMesh resources it creates, resources tagged with another ID are neither updated nor deleted unless taken over by the "+AnnotationTakeover+" annotation. Required to share meshes between clusters")
+	fs.BoolVar(&cfg.TagBasedOwnership, flagTagBasedOwnership, false,
+		"Neither update nor delete AppMesh resources whose identity tags name another cluster, or another CRD, unless taken over by the "+AnnotationTakeover+" annotation. Requires enable-resource-tagging")
+	fs.BoolVar(&cfg.BackfillTags, flagBackfillTags, false,
+		"Tag AppMesh resources of existing CRDs once the controller starts, e.g. resources created before tagging was enabled, instead of upon their next reconcile")
+}
+
+func (cfg *Config) Validate() error {
+	if len(cfg.ControllerID) > maxTagValueLength {
+		return errors.Errorf("controller ID must be at most %d characters: %q", maxTagValueLength, cfg.ControllerID)
+	}
+	if cfg.TagBasedOwnership && !cfg.Enabled {
+		return errors.Errorf("%s requires %s", flagTagBasedOwnership, flagEnableResourceTagging)
+	}
+	if cfg.BackfillTags && !cfg.Enabled && len(cfg.ControllerID) == 0 {
+		return errors.Errorf("%s requires %s or %s", flagBackfillTags, flagEnableResourceTagging, flagControllerID)
+	}
+	for _, key := range append(append([]string{}, cfg.LabelKeys...), cfg.AnnotationKeys...) {
+		if len(key) == 0 || len(key) > maxTagKeyLength {
+			return errors.Errorf("tag keys must be between 1 and %d characters: %q", maxTagKeyLength, key)
+		}
+		if strings.HasPrefix(strings.ToLower(key), awsReservedTagKeyPrefix) {
+			return errors.Errorf("tag keys must not use the reserved prefix %q: %s", awsReservedTagKeyPrefix, key)
+		}
+		if isIdentityTagKey(key) {
+			return errors.Errorf("tag key is reserved for controller identity tags: %s", key)
+		}
+	}
+	return nil
+}
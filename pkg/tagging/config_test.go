@@ -0,0 +1,70 @@
+package tagging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "valid keys",
+			cfg:  Config{Enabled: true, LabelKeys: []string{"team", "app.kubernetes.io/name"}, AnnotationKeys: []string{"cost-center"}},
+		},
+		{
+			name:    "empty key",
+			cfg:     Config{LabelKeys: []string{""}},
+			wantErr: `tag keys must be between 1 and 128 characters: ""`,
+		},
+		{
+			name:    "reserved by AWS",
+			cfg:     Config{AnnotationKeys: []string{"aws:cloudformation:stack-name"}},
+			wantErr: `tag keys must not use the reserved prefix "aws:": aws:cloudformation:stack-name`,
+		},
+		{
+			name:    "reserved by controller",
+			cfg:     Config{LabelKeys: []string{TagKeyCluster}},
+			wantErr: "tag key is reserved for controller identity tags: appmesh.k8s.aws/cluster",
+		},
+		{
+			name:    "controller ID exceeding tag value limit",
+			cfg:     Config{ControllerID: strings.Repeat("a", 257)},
+			wantErr: fmt.Sprintf("controller ID must be at most 256 characters: %q", strings.Repeat("a", 257)),
+		},
+		{
+			name: "tag based ownership with tagging enabled",
+			cfg:  Config{Enabled: true, TagBasedOwnership: true, BackfillTags: true},
+		},
+		{
+			name:    "tag based ownership without tagging enabled",
+			cfg:     Config{ControllerID: "cluster-a", TagBasedOwnership: true},
+			wantErr: "tag-based-ownership requires enable-resource-tagging",
+		},
+		{
+			name: "backfill with controller ID",
+			cfg:  Config{ControllerID: "cluster-a", BackfillTags: true},
+		},
+		{
+			name:    "backfill without tags",
+			cfg:     Config{BackfillTags: true},
+			wantErr: "backfill-tags requires enable-resource-tagging or controller-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
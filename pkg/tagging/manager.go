@@ -0,0 +1,304 @@
+package tagging
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TagKeyManagedBy tags AppMesh resources managed by the controller.
+	TagKeyManagedBy = "appmesh.k8s.aws/managed-by"
+	// TagKeyCluster tags AppMesh resources with the cluster name of the controller.
+	TagKeyCluster = "appmesh.k8s.aws/cluster"
+	// TagKeyNamespace tags AppMesh resources with the namespace of their CRD.
+	TagKeyNamespace = "appmesh.k8s.aws/namespace"
+	// TagKeyName tags AppMesh resources with the name of their CRD.
+	TagKeyName = "appmesh.k8s.aws/name"
+	// TagKeyUID tags AppMesh resources with the UID of their CRD.
+	TagKeyUID = "appmesh.k8s.aws/uid"
+	// TagKeyControllerID tags AppMesh resources with the ID of the controller owning them.
+	TagKeyControllerID = "appmesh.k8s.aws/controller-id"
+	// TagKeyReplicaOf tags replica meshes in secondary regions with the UID of the Mesh they're mirrored from.
+	TagKeyReplicaOf = "appmesh.k8s.aws/replica-of"
+
+	// AnnotationTakeover on a CRD set to "true" takes over its AppMesh resource owned by another controller.
+	AnnotationTakeover = "appmesh.k8s.aws/takeover"
+
+	// TagValueManagedBy is the value of TagKeyManagedBy for AWS resources managed by the controller.
+	TagValueManagedBy = "aws-app-mesh-controller-for-k8s"
+	// maximum length of tag values accepted by AppMesh.
+	maxTagValueLength = 256
+)
+
+var identityTagKeys = []string{TagKeyManagedBy, TagKeyCluster, TagKeyNamespace, TagKeyName, TagKeyUID, TagKeyControllerID, TagKeyReplicaOf}
+
+func isIdentityTagKey(key string) bool {
+	for _, identityTagKey := range identityTagKeys {
+		if key == identityTagKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Manager is dedicated to manage tags of AppMesh resources for CRDs.
+type Manager interface {
+	// BuildTags returns tags of the AppMesh resource for obj, to be specified upon creating it.
+	BuildTags(obj client.Object) []*appmeshsdk.TagRef
+
+	// ReconcileTags corrects tags of the AppMesh resource with resourceARN to match the tags for obj.
+	// only tags with keys managed by controller are changed, other tags are left as is.
+	ReconcileTags(ctx context.Context, resourceARN string, obj client.Object) error
+
+	// CheckOwnership checks the AppMesh resource with resourceARN isn't owned by another controller, i.e. tagged with another controller ID,
+	// nor by another cluster or CRD per its identity tags if tag based ownership is enabled, unless obj takes it over.
+	// returns an OwnershipConflictError if it's owned by another controller, cluster or CRD.
+	CheckOwnership(ctx context.Context, resourceARN string, obj client.Object) error
+}
+
+// OwnershipConflictError is returned when an AppMesh resource is owned by another controller, cluster or CRD.
+type OwnershipConflictError struct {
+	ResourceARN string
+	// Owner describes the owner of the resource, e.g. "controller cluster-b".
+	Owner string
+}
+
+func (e *OwnershipConflictError) Error() string {
+	return fmt.Sprintf("%s is owned by %s, annotate with %s: \"true\" to take it over", e.ResourceARN, e.Owner, AnnotationTakeover)
+}
+
+// IsOwnershipConflict checks whether err is caused by an AppMesh resource owned by another controller, cluster or CRD.
+func IsOwnershipConflict(err error) bool {
+	var conflictErr *OwnershipConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// NewDefaultManager constructs new Manager.
+func NewDefaultManager(cfg Config, appMeshSDK services.AppMesh, clusterName string, log logr.Logger) Manager {
+	return &defaultManager{
+		enabled:           cfg.Enabled,
+		labelKeys:         cfg.LabelKeys,
+		annotationKeys:    cfg.AnnotationKeys,
+		controllerID:      cfg.ControllerID,
+		tagBasedOwnership: cfg.TagBasedOwnership,
+		appMeshSDK:        appMeshSDK,
+		clusterName:       clusterName,
+		log:               log,
+	}
+}
+
+var _ Manager = &defaultManager{}
+
+type defaultManager struct {
+	enabled        bool
+	labelKeys      []string
+	annotationKeys []string
+	controllerID   string
+	// tagBasedOwnership checks ownership of AppMesh resources by their cluster, namespace and name tags as well.
+	tagBasedOwnership bool
+	appMeshSDK        services.AppMesh
+	clusterName       string
+	log               logr.Logger
+}
+
+func (m *defaultManager) BuildTags(obj client.Object) []*appmeshsdk.TagRef {
+	if !m.isTagging() {
+		return nil
+	}
+	return buildSDKTagRefs(m.desiredTags(obj))
+}
+
+func (m *defaultManager) ReconcileTags(ctx context.Context, resourceARN string, obj client.Object) error {
+	if !m.isTagging() || len(resourceARN) == 0 {
+		return nil
+	}
+	actualTags, err := m.listTags(ctx, resourceARN)
+	if err != nil {
+		return err
+	}
+	desiredTags := m.desiredTags(obj)
+
+	tagsToAdd := make(map[string]string)
+	for key, value := range desiredTags {
+		if actualValue, ok := actualTags[key]; !ok || actualValue != value {
+			tagsToAdd[key] = value
+		}
+	}
+	var tagKeysToRemove []string
+	for key := range actualTags {
+		if _, ok := desiredTags[key]; !ok && m.isManagedTagKey(key) {
+			tagKeysToRemove = append(tagKeysToRemove, key)
+		}
+	}
+	sort.Strings(tagKeysToRemove)
+
+	if len(tagsToAdd) > 0 {
+		m.log.V(1).Info("tagging resource", "resourceARN", resourceARN, "tags", tagsToAdd)
+		if _, err := m.appMeshSDK.TagResourceWithContext(ctx, &appmeshsdk.TagResourceInput{
+			ResourceArn: aws.String(resourceARN),
+			Tags:        buildSDKTagRefs(tagsToAdd),
+		}); err != nil {
+			return err
+		}
+	}
+	if len(tagKeysToRemove) > 0 {
+		m.log.V(1).Info("untagging resource", "resourceARN", resourceARN, "tagKeys", tagKeysToRemove)
+		if _, err := m.appMeshSDK.UntagResourceWithContext(ctx, &appmeshsdk.UntagResourceInput{
+			ResourceArn: aws.String(resourceARN),
+			TagKeys:     aws.StringSlice(tagKeysToRemove),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *defaultManager) CheckOwnership(ctx context.Context, resourceARN string, obj client.Object) error {
+	if (len(m.controllerID) == 0 && !m.tagBasedOwnership) || len(resourceARN) == 0 {
+		return nil
+	}
+	actualTags, err := m.listTags(ctx, resourceARN)
+	if err != nil {
+		return err
+	}
+	owner := m.findOtherOwner(resourceARN, actualTags, obj)
+	if len(owner) == 0 {
+		return nil
+	}
+	if obj.GetAnnotations()[AnnotationTakeover] == "true" {
+		m.log.Info("taking over resource owned by another owner", "resourceARN", resourceARN, "owner", owner)
+		return nil
+	}
+	return &OwnershipConflictError{ResourceARN: resourceARN, Owner: owner}
+}
+
+// findOtherOwner returns the owner other than obj the AppMesh resource with resourceARN is tagged with, or "" if there's none.
+// resources without identity tags, e.g. created before tagging was enabled, are adopted by obj,
+// as are resources of a CRD that has been recreated, i.e. tagged with its namespace and name but another UID.
+func (m *defaultManager) findOtherOwner(resourceARN string, actualTags map[string]string, obj client.Object) string {
+	if controllerID, ok := actualTags[TagKeyControllerID]; ok && len(m.controllerID) != 0 && controllerID != m.controllerID {
+		return "controller " + controllerID
+	}
+	if !m.tagBasedOwnership {
+		return ""
+	}
+	if cluster, ok := actualTags[TagKeyCluster]; ok && cluster != m.clusterName {
+		return "cluster " + cluster
+	}
+	name, ok := actualTags[TagKeyName]
+	if !ok {
+		return ""
+	}
+	namespace := actualTags[TagKeyNamespace]
+	if name != obj.GetName() || namespace != obj.GetNamespace() {
+		if len(namespace) == 0 {
+			return "object " + name
+		}
+		return "object " + namespace + "/" + name
+	}
+	if uid, ok := actualTags[TagKeyUID]; ok && uid != string(obj.GetUID()) {
+		m.log.Info("adopting resource of recreated object", "resourceARN", resourceARN, "uid", uid)
+	}
+	return ""
+}
+
+// isTagging checks whether AppMesh resources are tagged, which is the case when tagging is enabled or a controller ID is configured.
+func (m *defaultManager) isTagging() bool {
+	return m.enabled || len(m.controllerID) != 0
+}
+
+// desiredTags computes the tags for obj, which are identity tags and selected labels and annotations.
+// identity tags take precedence over labels, which take precedence over annotations.
+// if tagging isn't enabled, only the controller ID is tagged.
+func (m *defaultManager) desiredTags(obj client.Object) map[string]string {
+	tags := make(map[string]string)
+	if len(m.controllerID) != 0 {
+		tags[TagKeyControllerID] = m.controllerID
+	}
+	if !m.enabled {
+		return tags
+	}
+	m.addTagsFromMap(tags, m.annotationKeys, obj.GetAnnotations())
+	m.addTagsFromMap(tags, m.labelKeys, obj.GetLabels())
+	tags[TagKeyManagedBy] = TagValueManagedBy
+	if len(m.clusterName) != 0 {
+		tags[TagKeyCluster] = m.clusterName
+	}
+	if len(obj.GetNamespace()) != 0 {
+		tags[TagKeyNamespace] = obj.GetNamespace()
+	}
+	tags[TagKeyName] = obj.GetName()
+	if len(obj.GetUID()) != 0 {
+		tags[TagKeyUID] = string(obj.GetUID())
+	}
+	return tags
+}
+
+func (m *defaultManager) addTagsFromMap(tags map[string]string, keys []string, values map[string]string) {
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if len(value) > maxTagValueLength {
+			m.log.V(1).Info("skip tag whose value exceeds length limit", "key", key)
+			continue
+		}
+		tags[key] = value
+	}
+}
+
+func (m *defaultManager) isManagedTagKey(key string) bool {
+	if !m.enabled {
+		return key == TagKeyControllerID
+	}
+	if isIdentityTagKey(key) {
+		return true
+	}
+	for _, managedKey := range append(append([]string{}, m.labelKeys...), m.annotationKeys...) {
+		if key == managedKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *defaultManager) listTags(ctx context.Context, resourceARN string) (map[string]string, error) {
+	tags := make(map[string]string)
+	if err := m.appMeshSDK.ListTagsForResourcePagesWithContext(ctx, &appmeshsdk.ListTagsForResourceInput{
+		ResourceArn: aws.String(resourceARN),
+	}, func(output *appmeshsdk.ListTagsForResourceOutput, lastPage bool) bool {
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// buildSDKTagRefs builds AppMesh tags from tags, sorted by key.
+func buildSDKTagRefs(tags map[string]string) []*appmeshsdk.TagRef {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sdkTags := make([]*appmeshsdk.TagRef, 0, len(keys))
+	for _, key := range keys {
+		sdkTags = append(sdkTags, &appmeshsdk.TagRef{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+	return sdkTags
+}
@@ -0,0 +1,273 @@
+package tagging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeAppMesh serves tags of a single resource, and records tag changes.
+type fakeAppMesh struct {
+	services.AppMesh
+	tags           map[string]string
+	addedTags      map[string]string
+	removedTagKeys []string
+}
+
+func (s *fakeAppMesh) ListTagsForResourcePagesWithContext(_ aws.Context, _ *appmeshsdk.ListTagsForResourceInput, fn func(*appmeshsdk.ListTagsForResourceOutput, bool) bool, _ ...request.Option) error {
+	fn(&appmeshsdk.ListTagsForResourceOutput{Tags: buildSDKTagRefs(s.tags)}, true)
+	return nil
+}
+
+func (s *fakeAppMesh) TagResourceWithContext(_ aws.Context, input *appmeshsdk.TagResourceInput, _ ...request.Option) (*appmeshsdk.TagResourceOutput, error) {
+	s.addedTags = make(map[string]string)
+	for _, tag := range input.Tags {
+		s.addedTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &appmeshsdk.TagResourceOutput{}, nil
+}
+
+func (s *fakeAppMesh) UntagResourceWithContext(_ aws.Context, input *appmeshsdk.UntagResourceInput, _ ...request.Option) (*appmeshsdk.UntagResourceOutput, error) {
+	s.removedTagKeys = aws.StringValueSlice(input.TagKeys)
+	return &appmeshsdk.UntagResourceOutput{}, nil
+}
+
+func Test_defaultManager_BuildTags(t *testing.T) {
+	obj := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "node-a",
+			Labels:      map[string]string{"team": "payments", "app": "node-a", TagKeyName: "spoofed"},
+			Annotations: map[string]string{"cost-center": "1234", "team": "overridden"},
+		},
+	}
+	tests := []struct {
+		name        string
+		cfg         Config
+		clusterName string
+		withUID     bool
+		want        []*appmeshsdk.TagRef
+	}{
+		{
+			name: "tagging disabled",
+			cfg:  Config{Enabled: false, LabelKeys: []string{"team"}},
+			want: nil,
+		},
+		{
+			name:        "tagging disabled with controller ID",
+			cfg:         Config{Enabled: false, LabelKeys: []string{"team"}, ControllerID: "cluster-a"},
+			clusterName: "my-cluster",
+			want: []*appmeshsdk.TagRef{
+				{Key: aws.String(TagKeyControllerID), Value: aws.String("cluster-a")},
+			},
+		},
+		{
+			name:        "identity tags only",
+			cfg:         Config{Enabled: true},
+			clusterName: "my-cluster",
+			want: []*appmeshsdk.TagRef{
+				{Key: aws.String(TagKeyCluster), Value: aws.String("my-cluster")},
+				{Key: aws.String(TagKeyManagedBy), Value: aws.String(TagValueManagedBy)},
+				{Key: aws.String(TagKeyName), Value: aws.String("node-a")},
+				{Key: aws.String(TagKeyNamespace), Value: aws.String("ns")},
+			},
+		},
+		{
+			name:        "identity tags of object with UID",
+			cfg:         Config{Enabled: true},
+			clusterName: "my-cluster",
+			withUID:     true,
+			want: []*appmeshsdk.TagRef{
+				{Key: aws.String(TagKeyCluster), Value: aws.String("my-cluster")},
+				{Key: aws.String(TagKeyManagedBy), Value: aws.String(TagValueManagedBy)},
+				{Key: aws.String(TagKeyName), Value: aws.String("node-a")},
+				{Key: aws.String(TagKeyNamespace), Value: aws.String("ns")},
+				{Key: aws.String(TagKeyUID), Value: aws.String("3a4d0c5e-8f1b-4c3e-9a7d-2b6f1e0c9d8a")},
+			},
+		},
+		{
+			name: "labels and annotations, with labels taking precedence",
+			cfg:  Config{Enabled: true, LabelKeys: []string{"team", "missing", TagKeyName}, AnnotationKeys: []string{"cost-center", "team"}},
+			want: []*appmeshsdk.TagRef{
+				{Key: aws.String(TagKeyManagedBy), Value: aws.String(TagValueManagedBy)},
+				{Key: aws.String(TagKeyName), Value: aws.String("node-a")},
+				{Key: aws.String(TagKeyNamespace), Value: aws.String("ns")},
+				{Key: aws.String("cost-center"), Value: aws.String("1234")},
+				{Key: aws.String("team"), Value: aws.String("payments")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewDefaultManager(tt.cfg, nil, tt.clusterName, logr.New(&log.NullLogSink{}))
+			obj := obj.DeepCopy()
+			if tt.withUID {
+				obj.UID = "3a4d0c5e-8f1b-4c3e-9a7d-2b6f1e0c9d8a"
+			}
+			got := m.BuildTags(obj)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultManager_ReconcileTags(t *testing.T) {
+	obj := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "node-a",
+			Labels:    map[string]string{"team": "payments"},
+		},
+	}
+	tests := []struct {
+		name               string
+		cfg                Config
+		actualTags         map[string]string
+		wantAddedTags      map[string]string
+		wantRemovedTagKeys []string
+	}{
+		{
+			name:       "tagging disabled",
+			cfg:        Config{Enabled: false},
+			actualTags: map[string]string{},
+		},
+		{
+			name:          "untagged resource",
+			cfg:           Config{Enabled: true, LabelKeys: []string{"team"}},
+			actualTags:    map[string]string{"owner": "someone"},
+			wantAddedTags: map[string]string{TagKeyManagedBy: TagValueManagedBy, TagKeyNamespace: "ns", TagKeyName: "node-a", "team": "payments"},
+		},
+		{
+			name:       "tags in sync",
+			cfg:        Config{Enabled: true, LabelKeys: []string{"team"}},
+			actualTags: map[string]string{TagKeyManagedBy: TagValueManagedBy, TagKeyNamespace: "ns", TagKeyName: "node-a", "team": "payments", "owner": "someone"},
+		},
+		{
+			name:               "drifted tags corrected, unmanaged tags kept",
+			cfg:                Config{Enabled: true, LabelKeys: []string{"team", "tier"}},
+			actualTags:         map[string]string{TagKeyManagedBy: TagValueManagedBy, TagKeyNamespace: "ns", TagKeyName: "node-a", TagKeyCluster: "old-cluster", "team": "orders", "tier": "backend", "owner": "someone"},
+			wantAddedTags:      map[string]string{"team": "payments"},
+			wantRemovedTagKeys: []string{TagKeyCluster, "tier"},
+		},
+		{
+			name:          "tagging disabled with controller ID, only controller ID corrected",
+			cfg:           Config{Enabled: false, LabelKeys: []string{"team"}, ControllerID: "cluster-b"},
+			actualTags:    map[string]string{TagKeyControllerID: "cluster-a", TagKeyName: "node-a", "team": "orders"},
+			wantAddedTags: map[string]string{TagKeyControllerID: "cluster-b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := &fakeAppMesh{tags: tt.actualTags}
+			m := NewDefaultManager(tt.cfg, sdk, "", logr.New(&log.NullLogSink{}))
+			err := m.ReconcileTags(context.Background(), "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualNode/node-a_ns", obj)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAddedTags, sdk.addedTags)
+			assert.Equal(t, tt.wantRemovedTagKeys, sdk.removedTagKeys)
+		})
+	}
+}
+
+func Test_defaultManager_CheckOwnership(t *testing.T) {
+	const resourceARN = "arn:aws:appmesh:us-west-2:123456789012:mesh/my-mesh/virtualNode/node-a_ns"
+	tests := []struct {
+		name              string
+		controllerID      string
+		tagBasedOwnership bool
+		annotations       map[string]string
+		actualTags        map[string]string
+		wantErr           string
+	}{
+		{
+			name:       "without controller ID",
+			actualTags: map[string]string{TagKeyControllerID: "cluster-b"},
+		},
+		{
+			name:         "owned by controller",
+			controllerID: "cluster-a",
+			actualTags:   map[string]string{TagKeyControllerID: "cluster-a"},
+		},
+		{
+			name:         "owned by no controller",
+			controllerID: "cluster-a",
+			actualTags:   map[string]string{TagKeyManagedBy: TagValueManagedBy},
+		},
+		{
+			name:         "owned by another controller",
+			controllerID: "cluster-a",
+			actualTags:   map[string]string{TagKeyControllerID: "cluster-b"},
+			wantErr:      resourceARN + ` is owned by controller cluster-b, annotate with appmesh.k8s.aws/takeover: "true" to take it over`,
+		},
+		{
+			name:         "taken over from another controller",
+			controllerID: "cluster-a",
+			annotations:  map[string]string{AnnotationTakeover: "true"},
+			actualTags:   map[string]string{TagKeyControllerID: "cluster-b"},
+		},
+		{
+			name:              "tag based ownership, owned by object",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{TagKeyCluster: "my-cluster", TagKeyNamespace: "ns", TagKeyName: "node-a", TagKeyUID: "uid-a"},
+		},
+		{
+			name:              "tag based ownership, owned by recreated object",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{TagKeyCluster: "my-cluster", TagKeyNamespace: "ns", TagKeyName: "node-a", TagKeyUID: "uid-old"},
+		},
+		{
+			name:              "tag based ownership, without identity tags",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{"team": "payments"},
+		},
+		{
+			name:              "tag based ownership, owned by another cluster",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{TagKeyCluster: "other-cluster", TagKeyNamespace: "ns", TagKeyName: "node-a"},
+			wantErr:           resourceARN + ` is owned by cluster other-cluster, annotate with appmesh.k8s.aws/takeover: "true" to take it over`,
+		},
+		{
+			name:              "tag based ownership, owned by another object",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{TagKeyCluster: "my-cluster", TagKeyNamespace: "other-ns", TagKeyName: "node-a"},
+			wantErr:           resourceARN + ` is owned by object other-ns/node-a, annotate with appmesh.k8s.aws/takeover: "true" to take it over`,
+		},
+		{
+			name:              "tag based ownership, taken over from another object",
+			tagBasedOwnership: true,
+			annotations:       map[string]string{AnnotationTakeover: "true"},
+			actualTags:        map[string]string{TagKeyCluster: "my-cluster", TagKeyNamespace: "other-ns", TagKeyName: "node-a"},
+		},
+		{
+			name:              "tag based ownership, owned by another controller",
+			controllerID:      "cluster-a",
+			tagBasedOwnership: true,
+			actualTags:        map[string]string{TagKeyControllerID: "cluster-b", TagKeyCluster: "my-cluster", TagKeyNamespace: "ns", TagKeyName: "node-a"},
+			wantErr:           resourceARN + ` is owned by controller cluster-b, annotate with appmesh.k8s.aws/takeover: "true" to take it over`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "node-a", UID: "uid-a", Annotations: tt.annotations},
+			}
+			sdk := &fakeAppMesh{tags: tt.actualTags}
+			cfg := Config{Enabled: tt.tagBasedOwnership, ControllerID: tt.controllerID, TagBasedOwnership: tt.tagBasedOwnership}
+			m := NewDefaultManager(cfg, sdk, "my-cluster", logr.New(&log.NullLogSink{}))
+			err := m.CheckOwnership(context.Background(), resourceARN, obj)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.True(t, IsOwnershipConflict(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
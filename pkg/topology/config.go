@@ -0,0 +1,62 @@
+package topology
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// EndpointPath is the path of the topology endpoint on the metrics server.
+	EndpointPath = "/topology"
+
+	flagEnableEndpoint    = "enable-topology-endpoint"
+	flagConfigMap         = "topology-configmap"
+	flagConfigMapInterval = "topology-configmap-interval"
+
+	defaultConfigMapInterval = 5 * time.Minute
+)
+
+type Config struct {
+	// EnableEndpoint controls whether the mesh topology is served as JSON at EndpointPath of the metrics server.
+	EnableEndpoint bool
+	// ConfigMap is the namespaced name of the ConfigMap the mesh topology is periodically exported to, as namespace/name.
+	// exporting to ConfigMap is disabled if it's empty.
+	ConfigMap string
+	// ConfigMapInterval is the interval between exports to ConfigMap.
+	ConfigMapInterval time.Duration
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.EnableEndpoint, flagEnableEndpoint, false,
+		"If enabled, the mesh topology is served as JSON at "+EndpointPath+" of the metrics server, to callers allowed to get the non-resource URL")
+	fs.StringVar(&cfg.ConfigMap, flagConfigMap, "",
+		"The ConfigMap to periodically export the mesh topology to as JSON, in the form of namespace/name. Disabled if empty")
+	fs.DurationVar(&cfg.ConfigMapInterval, flagConfigMapInterval, defaultConfigMapInterval,
+		"The interval between exports of the mesh topology to ConfigMap")
+}
+
+func (cfg *Config) Validate() error {
+	if len(cfg.ConfigMap) == 0 {
+		return nil
+	}
+	if _, err := cfg.ConfigMapKey(); err != nil {
+		return err
+	}
+	if cfg.ConfigMapInterval <= 0 {
+		return errors.Errorf("%s must be positive: %v", flagConfigMapInterval, cfg.ConfigMapInterval)
+	}
+	return nil
+}
+
+// ConfigMapKey returns the namespaced name of the ConfigMap to export to.
+func (cfg *Config) ConfigMapKey() (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(cfg.ConfigMap, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return types.NamespacedName{}, errors.Errorf("%s must be in the form of namespace/name: %q", flagConfigMap, cfg.ConfigMap)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
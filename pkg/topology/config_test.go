@@ -0,0 +1,50 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "ConfigMap export disabled",
+			cfg:  Config{EnableEndpoint: true},
+		},
+		{
+			name: "ConfigMap export enabled",
+			cfg:  Config{ConfigMap: "appmesh-system/topology", ConfigMapInterval: 5 * time.Minute},
+		},
+		{
+			name:    "ConfigMap without namespace",
+			cfg:     Config{ConfigMap: "topology", ConfigMapInterval: 5 * time.Minute},
+			wantErr: `topology-configmap must be in the form of namespace/name: "topology"`,
+		},
+		{
+			name:    "ConfigMap with empty name",
+			cfg:     Config{ConfigMap: "appmesh-system/", ConfigMapInterval: 5 * time.Minute},
+			wantErr: `topology-configmap must be in the form of namespace/name: "appmesh-system/"`,
+		},
+		{
+			name:    "non-positive interval",
+			cfg:     Config{ConfigMap: "appmesh-system/topology"},
+			wantErr: "topology-configmap-interval must be positive: 0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package topology
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// ConfigMapDataKey is the key of the mesh topology JSON in the exported ConfigMap.
+	ConfigMapDataKey = "topology.json"
+
+	// ConfigMaps are limited to 1MiB by the API server, including metadata.
+	maxConfigMapDataSize = 1024 * 1024
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// Exporter periodically exports the mesh topology of all namespaces to a ConfigMap.
+type Exporter interface {
+	manager.Runnable
+	manager.LeaderElectionRunnable
+}
+
+// NewConfigMapExporter constructs new Exporter to the ConfigMap with key.
+// the ConfigMap is read with apiReader rather than the cached client, so that ConfigMaps aren't cached cluster-wide.
+func NewConfigMapExporter(key types.NamespacedName, interval time.Duration, k8sClient client.Client, apiReader client.Reader, log logr.Logger) Exporter {
+	return &configMapExporter{
+		key:       key,
+		interval:  interval,
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+		log:       log,
+	}
+}
+
+var _ Exporter = &configMapExporter{}
+
+type configMapExporter struct {
+	key       types.NamespacedName
+	interval  time.Duration
+	k8sClient client.Client
+	apiReader client.Reader
+	log       logr.Logger
+}
+
+func (e *configMapExporter) Start(ctx context.Context) error {
+	e.exportAndLog(ctx)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.exportAndLog(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns true, only the leader exports the topology.
+func (e *configMapExporter) NeedLeaderElection() bool {
+	return true
+}
+
+func (e *configMapExporter) exportAndLog(ctx context.Context) {
+	if err := e.export(ctx); err != nil {
+		e.log.Error(err, "failed to export mesh topology", "configMap", e.key)
+	}
+}
+
+func (e *configMapExporter) export(ctx context.Context) error {
+	objs, err := ListObjects(ctx, e.k8sClient)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err := Build(objs, "").WriteJSON(buf); err != nil {
+		return err
+	}
+	if buf.Len() > maxConfigMapDataSize {
+		return errors.Errorf("mesh topology of %d bytes exceeds the ConfigMap size limit", buf.Len())
+	}
+	data := buf.String()
+
+	cm := &corev1.ConfigMap{}
+	if err := e.apiReader.Get(ctx, e.key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: e.key.Namespace,
+				Name:      e.key.Name,
+			},
+			Data: map[string]string{ConfigMapDataKey: data},
+		}
+		return e.k8sClient.Create(ctx, cm)
+	}
+	if cm.Data[ConfigMapDataKey] == data {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[ConfigMapDataKey] = data
+	return e.k8sClient.Update(ctx, cm)
+}
@@ -0,0 +1,83 @@
+package topology
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_configMapExporter_export(t *testing.T) {
+	vn := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "node"},
+		Spec:       appmesh.VirtualNodeSpec{MeshRef: &appmesh.MeshReference{Name: "my-mesh"}},
+	}
+	wantData := `{"nodes":[{"id":"VirtualNode/app-ns/node","kind":"VirtualNode","namespace":"app-ns","name":"node","mesh":"my-mesh"}],"edges":[]}`
+	key := types.NamespacedName{Namespace: "appmesh-system", Name: "topology"}
+	tests := []struct {
+		name       string
+		existingCM *corev1.ConfigMap
+		wantData   map[string]string
+	}{
+		{
+			name:     "ConfigMap doesn't exist",
+			wantData: map[string]string{ConfigMapDataKey: wantData},
+		},
+		{
+			name: "ConfigMap exists with stale topology",
+			existingCM: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "appmesh-system", Name: "topology"},
+				Data:       map[string]string{ConfigMapDataKey: `{"nodes":[],"edges":[]}`, "other": "value"},
+			},
+			wantData: map[string]string{ConfigMapDataKey: wantData, "other": "value"},
+		},
+		{
+			name: "ConfigMap exists without data",
+			existingCM: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "appmesh-system", Name: "topology"},
+			},
+			wantData: map[string]string{ConfigMapDataKey: wantData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			if tt.existingCM != nil {
+				assert.NoError(t, k8sClient.Create(ctx, tt.existingCM.DeepCopy()))
+			}
+
+			e := &configMapExporter{
+				key:       key,
+				k8sClient: k8sClient,
+				apiReader: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+			assert.NoError(t, e.export(ctx))
+
+			gotCM := &corev1.ConfigMap{}
+			assert.NoError(t, k8sClient.Get(ctx, key, gotCM))
+			assert.Equal(t, len(tt.wantData), len(gotCM.Data))
+			for k, v := range tt.wantData {
+				if k == ConfigMapDataKey {
+					assert.JSONEq(t, v, gotCM.Data[k])
+				} else {
+					assert.Equal(t, v, gotCM.Data[k])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package topology
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// NewHandler constructs new http.Handler serving the mesh topology as JSON.
+// query parameters namespace and mesh restrict resources to the namespace and the mesh with the name.
+// callers must present a bearer token of an identity that is allowed to get the non-resource URL EndpointPath.
+func NewHandler(clientSet kubernetes.Interface, k8sClient client.Reader, log logr.Logger) http.Handler {
+	return &handler{
+		authorizer: k8s.NewDefaultRequestAuthorizer(clientSet),
+		k8sClient:  k8sClient,
+		log:        log,
+	}
+}
+
+type handler struct {
+	authorizer k8s.RequestAuthorizer
+	k8sClient  client.Reader
+	log        logr.Logger
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, code, err := h.authorizer.Authorize(req.Context(), req, k8s.AccessAttributes{
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: EndpointPath, Verb: "get"},
+		Description:           "get " + EndpointPath,
+	}); err != nil {
+		if code == http.StatusInternalServerError {
+			h.log.Error(err, "failed to authorize mesh topology request")
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	var listOpts []client.ListOption
+	if namespace := req.URL.Query().Get("namespace"); len(namespace) != 0 {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+	}
+	objs, err := ListObjects(req.Context(), h.k8sClient, listOpts...)
+	if err != nil {
+		h.log.Error(err, "failed to list mesh topology")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := Build(objs, req.URL.Query().Get("mesh")).WriteJSON(w); err != nil {
+		h.log.Error(err, "failed to write mesh topology")
+	}
+}
@@ -0,0 +1,135 @@
+package topology
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_handler_ServeHTTP(t *testing.T) {
+	vns := []*appmesh.VirtualNode{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "node"},
+			Spec:       appmesh.VirtualNodeSpec{MeshRef: &appmesh.MeshReference{Name: "my-mesh"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "node"},
+			Spec:       appmesh.VirtualNodeSpec{MeshRef: &appmesh.MeshReference{Name: "other-mesh"}},
+		},
+	}
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "all namespaces",
+			method:     http.MethodGet,
+			target:     EndpointPath,
+			token:      "viewer-token",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"nodes":[{"id":"VirtualNode/app-ns/node","kind":"VirtualNode","namespace":"app-ns","name":"node","mesh":"my-mesh"},{"id":"VirtualNode/other-ns/node","kind":"VirtualNode","namespace":"other-ns","name":"node","mesh":"other-mesh"}],"edges":[]}`,
+		},
+		{
+			name:       "filtered by namespace",
+			method:     http.MethodGet,
+			target:     EndpointPath + "?namespace=other-ns",
+			token:      "viewer-token",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"nodes":[{"id":"VirtualNode/other-ns/node","kind":"VirtualNode","namespace":"other-ns","name":"node","mesh":"other-mesh"}],"edges":[]}`,
+		},
+		{
+			name:       "filtered by mesh",
+			method:     http.MethodGet,
+			target:     EndpointPath + "?mesh=my-mesh",
+			token:      "viewer-token",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"nodes":[{"id":"VirtualNode/app-ns/node","kind":"VirtualNode","namespace":"app-ns","name":"node","mesh":"my-mesh"}],"edges":[]}`,
+		},
+		{
+			name:       "forbids without permission",
+			method:     http.MethodGet,
+			target:     EndpointPath,
+			token:      "other-token",
+			wantStatus: http.StatusForbidden,
+			wantBody:   "other is not allowed to get /topology\n",
+		},
+		{
+			name:       "requires bearer token",
+			method:     http.MethodGet,
+			target:     EndpointPath,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "bearer token is required\n",
+		},
+		{
+			name:       "unsupported method",
+			method:     http.MethodPost,
+			target:     EndpointPath,
+			token:      "viewer-token",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range vns {
+				assert.NoError(t, k8sClient.Create(context.Background(), vn.DeepCopy()))
+			}
+
+			clientSet := fake.NewSimpleClientset()
+			clientSet.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				tokenReview := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				switch tokenReview.Spec.Token {
+				case "viewer-token":
+					tokenReview.Status.Authenticated = true
+					tokenReview.Status.User = authenticationv1.UserInfo{Username: "viewer"}
+				case "other-token":
+					tokenReview.Status.Authenticated = true
+					tokenReview.Status.User = authenticationv1.UserInfo{Username: "other"}
+				}
+				return true, tokenReview, nil
+			})
+			clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				accessReview := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				attrs := accessReview.Spec.NonResourceAttributes
+				accessReview.Status.Allowed = attrs.Path == "/topology" && attrs.Verb == "get" && accessReview.Spec.User == "viewer"
+				return true, accessReview, nil
+			})
+
+			h := NewHandler(clientSet, k8sClient, logr.New(&log.NullLogSink{}))
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if len(tt.token) != 0 {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			} else if len(tt.wantBody) != 0 {
+				assert.Equal(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
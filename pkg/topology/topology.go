@@ -0,0 +1,291 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	KindVirtualGateway = "VirtualGateway"
+	KindGatewayRoute   = "GatewayRoute"
+	KindVirtualService = "VirtualService"
+	KindVirtualRouter  = "VirtualRouter"
+	KindRoute          = "Route"
+	KindVirtualNode    = "VirtualNode"
+	KindBackendGroup   = "BackendGroup"
+)
+
+const (
+	// EdgeTypeGatewayRoute references a GatewayRoute from its VirtualGateway.
+	EdgeTypeGatewayRoute = "gatewayRoute"
+	// EdgeTypeTarget references the target VirtualService of a GatewayRoute, or a weighted target VirtualNode of a Route.
+	EdgeTypeTarget = "target"
+	// EdgeTypeProvider references the provider VirtualRouter or VirtualNode of a VirtualService.
+	EdgeTypeProvider = "provider"
+	// EdgeTypeRoute references a Route from its VirtualRouter.
+	EdgeTypeRoute = "route"
+	// EdgeTypeBackend references a backend VirtualService of a VirtualNode or BackendGroup.
+	EdgeTypeBackend = "backend"
+	// EdgeTypeBackendGroup references a BackendGroup of a VirtualNode.
+	EdgeTypeBackendGroup = "backendGroup"
+)
+
+// Objects are the k8s resources making up the mesh topology.
+type Objects struct {
+	VirtualGateways []appmesh.VirtualGateway
+	GatewayRoutes   []appmesh.GatewayRoute
+	VirtualServices []appmesh.VirtualService
+	VirtualRouters  []appmesh.VirtualRouter
+	VirtualNodes    []appmesh.VirtualNode
+	BackendGroups   []appmesh.BackendGroup
+}
+
+// ListObjects lists the k8s resources making up the mesh topology.
+func ListObjects(ctx context.Context, k8sClient client.Reader, opts ...client.ListOption) (Objects, error) {
+	vgList := &appmesh.VirtualGatewayList{}
+	grList := &appmesh.GatewayRouteList{}
+	vsList := &appmesh.VirtualServiceList{}
+	vrList := &appmesh.VirtualRouterList{}
+	vnList := &appmesh.VirtualNodeList{}
+	bgList := &appmesh.BackendGroupList{}
+	for _, list := range []client.ObjectList{vgList, grList, vsList, vrList, vnList, bgList} {
+		if err := k8sClient.List(ctx, list, opts...); err != nil {
+			return Objects{}, errors.Wrapf(err, "failed to list %T", list)
+		}
+	}
+	return Objects{
+		VirtualGateways: vgList.Items,
+		GatewayRoutes:   grList.Items,
+		VirtualServices: vsList.Items,
+		VirtualRouters:  vrList.Items,
+		VirtualNodes:    vnList.Items,
+		BackendGroups:   bgList.Items,
+	}, nil
+}
+
+// Graph is the mesh topology, with resources as nodes and their references as edges.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node is a resource of the mesh topology.
+type Node struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Mesh      string `json:"mesh,omitempty"`
+	// Parent is the ID of the node owning this node, i.e. the VirtualRouter of a Route.
+	Parent string `json:"parent,omitempty"`
+}
+
+// Edge references the node To from the node From.
+// To may not be a node of the graph if it's filtered out or missing.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+}
+
+// Build builds the graph of objs, only resources of mesh are included if it's non-empty.
+// references by ARN, as well as VirtualServices selected by BackendGroup selectors, aren't included.
+func Build(objs Objects, mesh string) *Graph {
+	g := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	addNode := func(kind string, obj metav1.Object, meshRef *appmesh.MeshReference) (string, bool) {
+		if len(mesh) != 0 && meshName(meshRef) != mesh {
+			return "", false
+		}
+		id := NodeID(kind, k8s.NamespacedName(obj))
+		g.Nodes = append(g.Nodes, Node{ID: id, Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Mesh: meshName(meshRef)})
+		return id, true
+	}
+	addEdge := func(from string, to string, edgeType string, label string) {
+		g.Edges = append(g.Edges, Edge{From: from, To: to, Type: edgeType, Label: label})
+	}
+
+	for i := range objs.VirtualGateways {
+		vg := &objs.VirtualGateways[i]
+		addNode(KindVirtualGateway, vg, vg.Spec.MeshRef)
+	}
+	for i := range objs.GatewayRoutes {
+		gr := &objs.GatewayRoutes[i]
+		id, ok := addNode(KindGatewayRoute, gr, gr.Spec.MeshRef)
+		if !ok {
+			continue
+		}
+		if gr.Spec.VirtualGatewayRef != nil {
+			vgKey := references.ObjectKeyForVirtualGatewayReference(gr, *gr.Spec.VirtualGatewayRef)
+			addEdge(NodeID(KindVirtualGateway, vgKey), id, EdgeTypeGatewayRoute, "")
+		}
+		for _, target := range gatewayRouteTargets(gr) {
+			if target.VirtualService.VirtualServiceRef != nil {
+				vsKey := references.ObjectKeyForVirtualServiceReference(gr, *target.VirtualService.VirtualServiceRef)
+				addEdge(id, NodeID(KindVirtualService, vsKey), EdgeTypeTarget, "")
+			}
+		}
+	}
+	for i := range objs.VirtualServices {
+		vs := &objs.VirtualServices[i]
+		id, ok := addNode(KindVirtualService, vs, vs.Spec.MeshRef)
+		if !ok || vs.Spec.Provider == nil {
+			continue
+		}
+		if vs.Spec.Provider.VirtualNode != nil && vs.Spec.Provider.VirtualNode.VirtualNodeRef != nil {
+			vnKey := references.ObjectKeyForVirtualNodeReference(vs, *vs.Spec.Provider.VirtualNode.VirtualNodeRef)
+			addEdge(id, NodeID(KindVirtualNode, vnKey), EdgeTypeProvider, "")
+		}
+		if vs.Spec.Provider.VirtualRouter != nil && vs.Spec.Provider.VirtualRouter.VirtualRouterRef != nil {
+			vrKey := references.ObjectKeyForVirtualRouterReference(vs, *vs.Spec.Provider.VirtualRouter.VirtualRouterRef)
+			addEdge(id, NodeID(KindVirtualRouter, vrKey), EdgeTypeProvider, "")
+		}
+	}
+	for i := range objs.VirtualRouters {
+		vr := &objs.VirtualRouters[i]
+		id, ok := addNode(KindVirtualRouter, vr, vr.Spec.MeshRef)
+		if !ok {
+			continue
+		}
+		for _, route := range vr.Spec.Routes {
+			routeID := id + "/" + route.Name
+			g.Nodes = append(g.Nodes, Node{ID: routeID, Kind: KindRoute, Namespace: vr.Namespace, Name: route.Name, Mesh: meshName(vr.Spec.MeshRef), Parent: id})
+			addEdge(id, routeID, EdgeTypeRoute, "")
+			for _, target := range routeWeightedTargets(route) {
+				if target.VirtualNodeRef != nil {
+					vnKey := references.ObjectKeyForVirtualNodeReference(vr, *target.VirtualNodeRef)
+					addEdge(routeID, NodeID(KindVirtualNode, vnKey), EdgeTypeTarget, fmt.Sprintf("weight=%d", target.Weight))
+				}
+			}
+		}
+	}
+	for i := range objs.VirtualNodes {
+		vn := &objs.VirtualNodes[i]
+		id, ok := addNode(KindVirtualNode, vn, vn.Spec.MeshRef)
+		if !ok {
+			continue
+		}
+		for _, backend := range vn.Spec.Backends {
+			if backend.VirtualService.VirtualServiceRef != nil {
+				vsKey := references.ObjectKeyForVirtualServiceReference(vn, *backend.VirtualService.VirtualServiceRef)
+				addEdge(id, NodeID(KindVirtualService, vsKey), EdgeTypeBackend, "")
+			}
+		}
+		for _, bgRef := range vn.Spec.BackendGroups {
+			bgKey := references.ObjectKeyForBackendGroupReference(vn, bgRef)
+			addEdge(id, NodeID(KindBackendGroup, bgKey), EdgeTypeBackendGroup, "")
+		}
+	}
+	for i := range objs.BackendGroups {
+		bg := &objs.BackendGroups[i]
+		id, ok := addNode(KindBackendGroup, bg, bg.Spec.MeshRef)
+		if !ok {
+			continue
+		}
+		for _, vsRef := range bg.Spec.VirtualServices {
+			vsKey := references.ObjectKeyForVirtualServiceReference(bg, vsRef)
+			addEdge(id, NodeID(KindVirtualService, vsKey), EdgeTypeBackend, "")
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		if g.Edges[i].To != g.Edges[j].To {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return g.Edges[i].Label < g.Edges[j].Label
+	})
+	return g
+}
+
+// NodeID returns the ID of node for the resource of kind with key.
+func NodeID(kind string, key types.NamespacedName) string {
+	return kind + "/" + key.String()
+}
+
+func meshName(meshRef *appmesh.MeshReference) string {
+	if meshRef == nil {
+		return ""
+	}
+	return meshRef.Name
+}
+
+func gatewayRouteTargets(gr *appmesh.GatewayRoute) []appmesh.GatewayRouteTarget {
+	var targets []appmesh.GatewayRouteTarget
+	if gr.Spec.GRPCRoute != nil {
+		targets = append(targets, gr.Spec.GRPCRoute.Action.Target)
+	}
+	if gr.Spec.HTTPRoute != nil {
+		targets = append(targets, gr.Spec.HTTPRoute.Action.Target)
+	}
+	if gr.Spec.HTTP2Route != nil {
+		targets = append(targets, gr.Spec.HTTP2Route.Action.Target)
+	}
+	return targets
+}
+
+func routeWeightedTargets(route appmesh.Route) []appmesh.WeightedTarget {
+	switch {
+	case route.HTTPRoute != nil:
+		return route.HTTPRoute.Action.WeightedTargets
+	case route.HTTP2Route != nil:
+		return route.HTTP2Route.Action.WeightedTargets
+	case route.GRPCRoute != nil:
+		return route.GRPCRoute.Action.WeightedTargets
+	case route.TCPRoute != nil:
+		return route.TCPRoute.Action.WeightedTargets
+	default:
+		return nil
+	}
+}
+
+var dotShapeByKind = map[string]string{
+	KindVirtualGateway: "house",
+	KindGatewayRoute:   "cds",
+	KindVirtualService: "ellipse",
+	KindVirtualRouter:  "diamond",
+	KindRoute:          "rarrow",
+	KindVirtualNode:    "box",
+	KindBackendGroup:   "folder",
+}
+
+// WriteDOT writes g in the DOT language of Graphviz.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	fmt.Fprintf(w, "digraph mesh {\n")
+	fmt.Fprintf(w, "  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(w, "  %q [label=%q, shape=%s];\n", node.ID, node.Kind+"\n"+node.Namespace+"/"+node.Name, dotShapeByKind[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		if len(edge.Label) != 0 {
+			fmt.Fprintf(w, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Label)
+		} else {
+			fmt.Fprintf(w, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	_, err := fmt.Fprintf(w, "}\n")
+	return err
+}
+
+// WriteJSON writes g as indented JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
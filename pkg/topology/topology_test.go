@@ -0,0 +1,198 @@
+package topology
+
+import (
+	"bytes"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestObjects() Objects {
+	meshRef := &appmesh.MeshReference{Name: "my-mesh"}
+	otherMeshRef := &appmesh.MeshReference{Name: "other-mesh"}
+	return Objects{
+		VirtualGateways: []appmesh.VirtualGateway{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "gw-ns", Name: "gw"},
+				Spec:       appmesh.VirtualGatewaySpec{MeshRef: meshRef},
+			},
+		},
+		GatewayRoutes: []appmesh.GatewayRoute{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "gw-ns", Name: "gr"},
+				Spec: appmesh.GatewayRouteSpec{
+					MeshRef:           meshRef,
+					VirtualGatewayRef: &appmesh.VirtualGatewayReference{Namespace: aws.String("gw-ns"), Name: "gw"},
+					HTTPRoute: &appmesh.HTTPGatewayRoute{
+						Action: appmesh.HTTPGatewayRouteAction{
+							Target: appmesh.GatewayRouteTarget{
+								VirtualService: appmesh.GatewayRouteVirtualService{
+									VirtualServiceRef: &appmesh.VirtualServiceReference{Namespace: aws.String("app-ns"), Name: "svc"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		VirtualServices: []appmesh.VirtualService{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "svc"},
+				Spec: appmesh.VirtualServiceSpec{
+					MeshRef: meshRef,
+					Provider: &appmesh.VirtualServiceProvider{
+						VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+							VirtualRouterRef: &appmesh.VirtualRouterReference{Name: "router"},
+						},
+					},
+				},
+			},
+		},
+		VirtualRouters: []appmesh.VirtualRouter{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "router"},
+				Spec: appmesh.VirtualRouterSpec{
+					MeshRef: meshRef,
+					Routes: []appmesh.Route{
+						{
+							Name: "route",
+							HTTPRoute: &appmesh.HTTPRoute{
+								Action: appmesh.HTTPRouteAction{
+									WeightedTargets: []appmesh.WeightedTarget{
+										{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "node-v1"}, Weight: 90},
+										{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "node-v2"}, Weight: 10},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		VirtualNodes: []appmesh.VirtualNode{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "node-v1"},
+				Spec: appmesh.VirtualNodeSpec{
+					MeshRef:       meshRef,
+					BackendGroups: []appmesh.BackendGroupReference{{Name: "bg"}},
+					Backends: []appmesh.Backend{
+						{
+							VirtualService: appmesh.VirtualServiceBackend{
+								VirtualServiceRef: &appmesh.VirtualServiceReference{Namespace: aws.String("db-ns"), Name: "db"},
+							},
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "node-v2"},
+				Spec:       appmesh.VirtualNodeSpec{MeshRef: meshRef},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "node-other"},
+				Spec:       appmesh.VirtualNodeSpec{MeshRef: otherMeshRef},
+			},
+		},
+		BackendGroups: []appmesh.BackendGroup{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "bg"},
+				Spec: appmesh.BackendGroupSpec{
+					MeshRef:         meshRef,
+					VirtualServices: []appmesh.VirtualServiceReference{{Namespace: aws.String("db-ns"), Name: "cache"}},
+				},
+			},
+		},
+	}
+}
+
+func Test_Build(t *testing.T) {
+	tests := []struct {
+		name      string
+		mesh      string
+		wantNodes []string
+		wantEdges []Edge
+	}{
+		{
+			name: "graphs resources of all meshes",
+			wantNodes: []string{
+				"BackendGroup/app-ns/bg",
+				"GatewayRoute/gw-ns/gr",
+				"VirtualGateway/gw-ns/gw",
+				"VirtualNode/app-ns/node-other",
+				"VirtualNode/app-ns/node-v1",
+				"VirtualNode/app-ns/node-v2",
+				"VirtualRouter/app-ns/router",
+				"VirtualRouter/app-ns/router/route",
+				"VirtualService/app-ns/svc",
+			},
+			wantEdges: []Edge{
+				{From: "BackendGroup/app-ns/bg", To: "VirtualService/db-ns/cache", Type: EdgeTypeBackend},
+				{From: "GatewayRoute/gw-ns/gr", To: "VirtualService/app-ns/svc", Type: EdgeTypeTarget},
+				{From: "VirtualGateway/gw-ns/gw", To: "GatewayRoute/gw-ns/gr", Type: EdgeTypeGatewayRoute},
+				{From: "VirtualNode/app-ns/node-v1", To: "BackendGroup/app-ns/bg", Type: EdgeTypeBackendGroup},
+				{From: "VirtualNode/app-ns/node-v1", To: "VirtualService/db-ns/db", Type: EdgeTypeBackend},
+				{From: "VirtualRouter/app-ns/router", To: "VirtualRouter/app-ns/router/route", Type: EdgeTypeRoute},
+				{From: "VirtualRouter/app-ns/router/route", To: "VirtualNode/app-ns/node-v1", Type: EdgeTypeTarget, Label: "weight=90"},
+				{From: "VirtualRouter/app-ns/router/route", To: "VirtualNode/app-ns/node-v2", Type: EdgeTypeTarget, Label: "weight=10"},
+				{From: "VirtualService/app-ns/svc", To: "VirtualRouter/app-ns/router", Type: EdgeTypeProvider},
+			},
+		},
+		{
+			name: "graphs resources of mesh only",
+			mesh: "other-mesh",
+			wantNodes: []string{
+				"VirtualNode/app-ns/node-other",
+			},
+			wantEdges: []Edge{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := Build(newTestObjects(), tt.mesh)
+			var gotNodes []string
+			for _, node := range g.Nodes {
+				gotNodes = append(gotNodes, node.ID)
+			}
+			assert.Equal(t, tt.wantNodes, gotNodes)
+			assert.Equal(t, tt.wantEdges, g.Edges)
+		})
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	g := &Graph{
+		Nodes: []Node{
+			{ID: "VirtualRouter/app-ns/router", Kind: KindVirtualRouter, Namespace: "app-ns", Name: "router"},
+			{ID: "VirtualService/app-ns/svc", Kind: KindVirtualService, Namespace: "app-ns", Name: "svc"},
+		},
+		Edges: []Edge{
+			{From: "VirtualRouter/app-ns/router", To: "VirtualNode/app-ns/node", Type: EdgeTypeTarget, Label: "weight=100"},
+			{From: "VirtualService/app-ns/svc", To: "VirtualRouter/app-ns/router", Type: EdgeTypeProvider},
+		},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, g.WriteDOT(buf))
+	assert.Equal(t, `digraph mesh {
+  rankdir=LR;
+  "VirtualRouter/app-ns/router" [label="VirtualRouter\napp-ns/router", shape=diamond];
+  "VirtualService/app-ns/svc" [label="VirtualService\napp-ns/svc", shape=ellipse];
+  "VirtualRouter/app-ns/router" -> "VirtualNode/app-ns/node" [label="weight=100"];
+  "VirtualService/app-ns/svc" -> "VirtualRouter/app-ns/router";
+}
+`, buf.String())
+}
+
+func TestGraph_WriteJSON(t *testing.T) {
+	g := &Graph{
+		Nodes: []Node{
+			{ID: "VirtualNode/app-ns/node", Kind: KindVirtualNode, Namespace: "app-ns", Name: "node", Mesh: "my-mesh"},
+		},
+		Edges: []Edge{},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, g.WriteJSON(buf))
+	assert.JSONEq(t, `{"nodes":[{"id":"VirtualNode/app-ns/node","kind":"VirtualNode","namespace":"app-ns","name":"node","mesh":"my-mesh"}],"edges":[]}`, buf.String())
+}
@@ -0,0 +1,22 @@
+package tracing
+
+import "go.opentelemetry.io/otel/attribute"
+
+const (
+	// AttributeController is the name of the controller reconciling the object.
+	AttributeController = attribute.Key("controller.name")
+	// AttributeNamespace is the namespace of the reconciled object.
+	AttributeNamespace = attribute.Key("k8s.namespace.name")
+	// AttributeName is the name of the reconciled object.
+	AttributeName = attribute.Key("k8s.object.name")
+	// AttributeRouteName is the name of the route within a virtualRouter.
+	AttributeRouteName = attribute.Key("appmesh.route.name")
+	// AttributeAWSErrorCode is the error code of failed AWS calls.
+	AttributeAWSErrorCode = attribute.Key("aws.error_code")
+	// AttributeAWSRequestID is the request ID of AWS calls.
+	AttributeAWSRequestID = attribute.Key("aws.request_id")
+	// AttributeRetryCount is the number of times AWS calls were retried.
+	AttributeRetryCount = attribute.Key("aws.retry_count")
+	// AttributeRequeue is whether the reconciled object is requeued.
+	AttributeRequeue = attribute.Key("reconcile.requeue")
+)
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagTracingOTLPEndpoint = "tracing-otlp-endpoint"
+	flagTracingOTLPInsecure = "tracing-otlp-insecure"
+	flagTracingSampleRatio  = "tracing-sample-ratio"
+
+	defaultSampleRatio = 1.0
+)
+
+type Config struct {
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector spans are exported to, tracing is disabled if it's empty.
+	OTLPEndpoint string
+	// OTLPInsecure controls whether spans are exported without TLS.
+	OTLPInsecure bool
+	// SampleRatio is the fraction of reconciles that are traced.
+	SampleRatio float64
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.OTLPEndpoint, flagTracingOTLPEndpoint, "",
+		"host:port of the OTLP gRPC collector to export reconcile traces to, e.g. otel-collector.observability:4317. Set to empty to disable tracing")
+	fs.BoolVar(&cfg.OTLPInsecure, flagTracingOTLPInsecure, false,
+		"Export traces to the OTLP collector without TLS")
+	fs.Float64Var(&cfg.SampleRatio, flagTracingSampleRatio, defaultSampleRatio,
+		"Fraction of reconciles that are traced, between 0 and 1")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return errors.Errorf("%s must be between 0 and 1: %v", flagTracingSampleRatio, cfg.SampleRatio)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "sample all",
+			cfg:  Config{OTLPEndpoint: "otel-collector:4317", SampleRatio: 1},
+		},
+		{
+			name: "sample none",
+			cfg:  Config{SampleRatio: 0},
+		},
+		{
+			name:    "negative sample ratio",
+			cfg:     Config{SampleRatio: -0.5},
+			wantErr: "tracing-sample-ratio must be between 0 and 1: -0.5",
+		},
+		{
+			name:    "sample ratio above 1",
+			cfg:     Config{SampleRatio: 2},
+			wantErr: "tracing-sample-ratio must be between 0 and 1: 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler wraps reconciler of controller to trace each reconcile in a span,
+// which is the parent of spans for AWS calls made during the reconcile.
+func NewReconciler(controller string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		ctx, span := StartSpan(ctx, "Reconcile "+controller,
+			AttributeController.String(controller),
+			AttributeNamespace.String(req.Namespace),
+			AttributeName.String(req.Name),
+		)
+		result, err := reconciler.Reconcile(ctx, req)
+		span.SetAttributes(AttributeRequeue.Bool(result.Requeue || result.RequeueAfter > 0))
+		EndSpan(span, err)
+		return result, err
+	})
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	sdkHandlerStartAPICallSpan = "startAPICallSpan"
+	sdkHandlerEndAPICallSpan   = "endAPICallSpan"
+	sdkHandlerAddAttemptEvent  = "addAPIAttemptEvent"
+)
+
+// apiCallSpanKey is the context key of the span of an AWS API call.
+type apiCallSpanKey struct{}
+
+// InjectHandlers traces AWS API calls made with handlers, as children of the span in the context of calls.
+func InjectHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: sdkHandlerStartAPICallSpan,
+		Fn:   startAPICallSpan,
+	})
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: sdkHandlerAddAttemptEvent,
+		Fn:   addAPIAttemptEvent,
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: sdkHandlerEndAPICallSpan,
+		Fn:   endAPICallSpan,
+	})
+}
+
+func startAPICallSpan(r *request.Request) {
+	service := r.ClientInfo.ServiceID
+	operation := operationForRequest(r)
+	ctx, span := StartSpan(r.Context(), service+"."+operation,
+		semconv.RPCSystemKey.String("aws-api"),
+		semconv.RPCService(service),
+		semconv.RPCMethod(operation),
+	)
+	r.SetContext(context.WithValue(ctx, apiCallSpanKey{}, span))
+}
+
+func addAPIAttemptEvent(r *request.Request) {
+	span, ok := r.Context().Value(apiCallSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	span.AddEvent("attempt", trace.WithAttributes(
+		semconv.HTTPStatusCode(statusCodeForRequest(r)),
+		AttributeRetryCount.Int(r.RetryCount),
+	))
+}
+
+func endAPICallSpan(r *request.Request) {
+	span, ok := r.Context().Value(apiCallSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(AttributeRetryCount.Int(r.RetryCount))
+	if len(r.RequestID) != 0 {
+		span.SetAttributes(AttributeAWSRequestID.String(r.RequestID))
+	}
+	if r.HTTPResponse != nil {
+		span.SetAttributes(semconv.HTTPStatusCode(r.HTTPResponse.StatusCode))
+	}
+	EndSpan(span, r.Error)
+}
+
+// statusCodeForRequest returns the http status code for request, or 0 if there is no http response.
+func statusCodeForRequest(r *request.Request) int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// operationForRequest returns the operation for request.
+func operationForRequest(r *request.Request) string {
+	if r.Operation != nil {
+		return r.Operation.Name
+	}
+	return "?"
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// setupSpanRecorder registers a global tracer provider recording spans for the duration of t.
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(original) })
+	return recorder
+}
+
+func attributesOf(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestNewReconciler(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+	reconciler := NewReconciler("virtualRouter", reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		_, span := StartSpan(ctx, "child")
+		EndSpan(span, nil)
+		return ctrl.Result{}, errors.Wrap(awserr.New("LimitExceededException", "too many routes", nil), "failed to create route")
+	}))
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "my-ns", Name: "my-vr"}})
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		child, parent := spans[0], spans[1]
+		assert.Equal(t, "Reconcile virtualRouter", parent.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+		assert.Equal(t, codes.Error, parent.Status().Code)
+		attrs := attributesOf(parent)
+		assert.Equal(t, "my-ns", attrs[AttributeNamespace].AsString())
+		assert.Equal(t, "my-vr", attrs[AttributeName].AsString())
+		assert.Equal(t, "LimitExceededException", attrs[AttributeAWSErrorCode].AsString())
+		assert.False(t, attrs[AttributeRequeue].AsBool())
+	}
+}
+
+func TestInjectHandlers(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+	ctx, parent := StartSpan(context.Background(), "Reconcile virtualRouter")
+
+	handlers := request.Handlers{}
+	InjectHandlers(&handlers)
+	handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusNotFound}
+		r.RequestID = "request-1"
+		r.Error = awserr.New("NotFoundException", "route not found", nil)
+	})
+	req := request.New(aws.Config{}, metadata.ClientInfo{ServiceID: "App Mesh"}, handlers, nil,
+		&request.Operation{Name: "DescribeRoute"}, nil, nil)
+	req.SetContext(ctx)
+	assert.Error(t, req.Send())
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		apiCall := spans[0]
+		assert.Equal(t, "App Mesh.DescribeRoute", apiCall.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), apiCall.Parent().SpanID())
+		assert.Equal(t, codes.Error, apiCall.Status().Code)
+		attrs := attributesOf(apiCall)
+		assert.Equal(t, "NotFoundException", attrs[AttributeAWSErrorCode].AsString())
+		assert.Equal(t, "request-1", attrs[AttributeAWSRequestID].AsString())
+		assert.Equal(t, int64(http.StatusNotFound), attrs[attribute.Key("http.status_code")].AsInt64())
+		assert.Len(t, apiCall.Events(), 2)
+	}
+}
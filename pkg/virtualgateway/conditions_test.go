@@ -1,270 +1,83 @@
 package virtualgateway
 
 import (
-	"github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"testing"
+
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"testing"
 )
 
-func Test_getCondition(t *testing.T) {
-	type args struct {
-		vg            *appmesh.VirtualGateway
-		conditionType v1beta2.VirtualGatewayConditionType
-	}
-	tests := []struct {
-		name string
-		args args
-		want *appmesh.VirtualGatewayCondition
-	}{
-		{
-			name: "condition found",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-			},
-			want: &appmesh.VirtualGatewayCondition{
-				Type:   appmesh.VirtualGatewayActive,
-				Status: corev1.ConditionFalse,
-			},
-		},
-		{
-			name: "condition not found",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-			},
-			want: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := getCondition(tt.args.vg, tt.args.conditionType)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
-func Test_updateCondition(t *testing.T) {
+func Test_updateConditionsForReconciled(t *testing.T) {
 	type args struct {
-		vg            *appmesh.VirtualGateway
-		conditionType appmesh.VirtualGatewayConditionType
-		status        corev1.ConditionStatus
-		reason        *string
-		message       *string
+		vg     *appmesh.VirtualGateway
+		active bool
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantVG      *appmesh.VirtualGateway
-		wantChanged bool
+		name           string
+		args           args
+		wantConditions []metav1.Condition
+		wantChanged    bool
 	}{
 		{
-			name: "condition updated by modify condition status",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:   appmesh.VirtualGatewayActive,
-							Status: corev1.ConditionTrue,
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition reason",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:   appmesh.VirtualGatewayActive,
-							Status: corev1.ConditionTrue,
-							Reason: aws.String("reason"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition message",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-				message:       aws.String("message"),
-			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:    appmesh.VirtualGatewayActive,
-							Status:  corev1.ConditionTrue,
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition status/reason/message",
+			name: "active virtualGateway",
 			args: args{
 				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: true,
 			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:    appmesh.VirtualGatewayActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.VirtualGatewayActive, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
 			},
 			wantChanged: true,
 		},
 		{
-			name: "condition updated by new condition",
+			name: "inactive virtualGateway",
 			args: args{
 				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: nil,
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: false,
 			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:    appmesh.VirtualGatewayActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.VirtualGatewayActive, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
 			},
 			wantChanged: true,
 		},
-		{
-			name: "condition unmodified",
-			args: args{
-				vg: &appmesh.VirtualGateway{
-					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
-							{
-								Type:    appmesh.VirtualGatewayActive,
-								Status:  corev1.ConditionTrue,
-								Reason:  aws.String("reason"),
-								Message: aws.String("message"),
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualGatewayActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
-			},
-			wantVG: &appmesh.VirtualGateway{
-				Status: appmesh.VirtualGatewayStatus{
-					Conditions: []appmesh.VirtualGatewayCondition{
-						{
-							Type:    appmesh.VirtualGatewayActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: false,
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotChanged := updateCondition(tt.args.vg, tt.args.conditionType, tt.args.status, tt.args.reason, tt.args.message)
-			opts := cmpopts.IgnoreTypes((*metav1.Time)(nil))
-			assert.True(t, cmp.Equal(tt.wantVG, tt.args.vg, opts), "diff", cmp.Diff(tt.wantVG, tt.args.vg, opts))
+			gotChanged := updateConditionsForReconciled(tt.args.vg, tt.args.active)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, tt.args.vg.Status.Conditions, opts), "diff", cmp.Diff(tt.wantConditions, tt.args.vg.Status.Conditions, opts))
 			assert.Equal(t, tt.wantChanged, gotChanged)
 		})
 	}
 }
+
+func Test_updateConditionsForFailure(t *testing.T) {
+	vg := &appmesh.VirtualGateway{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+	}
+	gotChanged := updateConditionsForFailure(vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, errors.New("oops"))
+	wantConditions := []metav1.Condition{
+		{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+		{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+	}
+	opts := cmpopts.IgnoreTypes(metav1.Time{})
+	assert.True(t, cmp.Equal(wantConditions, vg.Status.Conditions, opts), "diff", cmp.Diff(wantConditions, vg.Status.Conditions, opts))
+	assert.True(t, gotChanged)
+}
@@ -34,12 +34,13 @@ func (h *enqueueRequestsForMeshEvents) Create(e event.CreateEvent, queue workque
 
 // Update is called in response to an update event
 func (h *enqueueRequestsForMeshEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
-	// virtualGateway reconcile depends on mesh is active or not.
-	// so we only need to trigger virtualGateway reconcile if mesh's active status changed.
+	// virtualGateway reconcile depends on mesh is active or not, and mesh's TLS enforcement mode.
+	// so we only need to trigger virtualGateway reconcile if either changed.
 	msOld := e.ObjectOld.(*appmesh.Mesh)
 	msNew := e.ObjectNew.(*appmesh.Mesh)
 
-	if mesh.IsMeshActive(msOld) != mesh.IsMeshActive(msNew) {
+	if mesh.IsMeshActive(msOld) != mesh.IsMeshActive(msNew) ||
+		mesh.IsTLSEnforcementAudit(msOld) != mesh.IsTLSEnforcementAudit(msNew) {
 		h.enqueueVirtualGatewaysForMesh(context.Background(), queue, msNew)
 	}
 }
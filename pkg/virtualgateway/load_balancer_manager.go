@@ -0,0 +1,350 @@
+package virtualgateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// LabelVirtualGateway labels Services and TargetGroupBindings provisioned for a VirtualGateway with its name.
+	LabelVirtualGateway = "appmesh.k8s.aws/virtualGateway"
+)
+
+// annotations of Services recognized by the AWS Load Balancer Controller.
+const (
+	annotationLoadBalancerType                          = "service.beta.kubernetes.io/aws-load-balancer-type"
+	annotationLoadBalancerNLBTargetType                 = "service.beta.kubernetes.io/aws-load-balancer-nlb-target-type"
+	annotationLoadBalancerScheme                        = "service.beta.kubernetes.io/aws-load-balancer-scheme"
+	annotationLoadBalancerHealthCheckProtocol           = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-protocol"
+	annotationLoadBalancerHealthCheckPort               = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-port"
+	annotationLoadBalancerHealthCheckPath               = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-path"
+	annotationLoadBalancerHealthCheckInterval           = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-interval"
+	annotationLoadBalancerHealthCheckTimeout            = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-timeout"
+	annotationLoadBalancerHealthCheckHealthyThreshold   = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-healthy-threshold"
+	annotationLoadBalancerHealthCheckUnhealthyThreshold = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-unhealthy-threshold"
+)
+
+// targetGroupBindingGVK is the kind of TargetGroupBindings of the AWS Load Balancer Controller.
+// they're managed as unstructured objects, so the controller doesn't depend on the AWS Load Balancer Controller being installed,
+// unless VirtualGateways request them.
+var targetGroupBindingGVK = schema.GroupVersionKind{Group: "elbv2.k8s.aws", Version: "v1beta1", Kind: "TargetGroupBinding"}
+
+// LoadBalancerManager manages load balancers fronting pods of VirtualGateways.
+type LoadBalancerManager interface {
+	// Reconcile ensures the Service, and TargetGroupBinding if requested, fronting pods of vg match vg.spec.provisionLoadBalancer,
+	// and deletes the ones provisioned for vg that are no longer desired.
+	// they're owned by vg, so they're garbage collected upon deletion of vg.
+	Reconcile(ctx context.Context, vg *appmesh.VirtualGateway) error
+}
+
+// NewDefaultLoadBalancerManager constructs new LoadBalancerManager
+func NewDefaultLoadBalancerManager(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger) LoadBalancerManager {
+	return &defaultLoadBalancerManager{
+		k8sClient: k8sClient,
+		scheme:    scheme,
+		log:       log,
+	}
+}
+
+var _ LoadBalancerManager = &defaultLoadBalancerManager{}
+
+// defaultLoadBalancerManager implements LoadBalancerManager.
+// the load balancer itself is provisioned by the AWS Load Balancer Controller, either for a Service of type LoadBalancer,
+// or into an existing target group for a TargetGroupBinding of a ClusterIP Service.
+type defaultLoadBalancerManager struct {
+	k8sClient client.Client
+	scheme    *runtime.Scheme
+	log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=elbv2.k8s.aws,resources=targetgroupbindings,verbs=get;list;create;update;patch;delete
+
+func (m *defaultLoadBalancerManager) Reconcile(ctx context.Context, vg *appmesh.VirtualGateway) error {
+	var desiredSVC *corev1.Service
+	var desiredTGB *unstructured.Unstructured
+	if vg.Spec.ProvisionLoadBalancer != nil {
+		desiredSVC = buildLoadBalancerService(vg)
+		if vg.Spec.ProvisionLoadBalancer.TargetGroupARN != nil {
+			desiredTGB = buildTargetGroupBinding(vg, desiredSVC)
+		}
+	}
+	if err := m.reconcileService(ctx, vg, desiredSVC); err != nil {
+		return err
+	}
+	return m.reconcileTargetGroupBinding(ctx, vg, desiredTGB)
+}
+
+func (m *defaultLoadBalancerManager) reconcileService(ctx context.Context, vg *appmesh.VirtualGateway, desiredSVC *corev1.Service) error {
+	svcList := &corev1.ServiceList{}
+	if err := m.k8sClient.List(ctx, svcList, client.InNamespace(vg.Namespace), client.MatchingLabels{LabelVirtualGateway: vg.Name}); err != nil {
+		return errors.Wrap(err, "failed to list services")
+	}
+	var existingSVC *corev1.Service
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if !metav1.IsControlledBy(svc, vg) {
+			continue
+		}
+		if desiredSVC != nil && svc.Name == desiredSVC.Name {
+			existingSVC = svc
+			continue
+		}
+		if err := m.k8sClient.Delete(ctx, svc); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete service %v", k8s.NamespacedName(svc))
+		}
+		m.log.V(1).Info("deleted load balancer service",
+			"virtualGateway", k8s.NamespacedName(vg),
+			"service", k8s.NamespacedName(svc),
+		)
+	}
+	if desiredSVC == nil {
+		return nil
+	}
+	if existingSVC == nil {
+		if err := controllerutil.SetControllerReference(vg, desiredSVC, m.scheme); err != nil {
+			return err
+		}
+		// creation fails if the name is taken by a Service not owned by vg, which is left untouched.
+		if err := m.k8sClient.Create(ctx, desiredSVC); err != nil {
+			return errors.Wrapf(err, "failed to create service %v", k8s.NamespacedName(desiredSVC))
+		}
+		m.log.V(1).Info("created load balancer service",
+			"virtualGateway", k8s.NamespacedName(vg),
+			"service", k8s.NamespacedName(desiredSVC),
+		)
+		return nil
+	}
+
+	oldSVC := existingSVC.DeepCopy()
+	existingSVC.Annotations = desiredSVC.Annotations
+	existingSVC.Spec.Type = desiredSVC.Spec.Type
+	existingSVC.Spec.Selector = desiredSVC.Spec.Selector
+	existingSVC.Spec.Ports = withAllocatedNodePorts(desiredSVC.Spec.Ports, oldSVC.Spec.Ports)
+	if equality.Semantic.DeepEqual(oldSVC, existingSVC) {
+		return nil
+	}
+	if err := m.k8sClient.Patch(ctx, existingSVC, client.MergeFrom(oldSVC)); err != nil {
+		return errors.Wrapf(err, "failed to update service %v", k8s.NamespacedName(existingSVC))
+	}
+	m.log.V(1).Info("updated load balancer service",
+		"virtualGateway", k8s.NamespacedName(vg),
+		"service", k8s.NamespacedName(existingSVC),
+	)
+	return nil
+}
+
+func (m *defaultLoadBalancerManager) reconcileTargetGroupBinding(ctx context.Context, vg *appmesh.VirtualGateway, desiredTGB *unstructured.Unstructured) error {
+	tgbList := &unstructured.UnstructuredList{}
+	tgbList.SetGroupVersionKind(targetGroupBindingGVK.GroupVersion().WithKind(targetGroupBindingGVK.Kind + "List"))
+	if err := m.k8sClient.List(ctx, tgbList, client.InNamespace(vg.Namespace), client.MatchingLabels{LabelVirtualGateway: vg.Name}); err != nil {
+		// without the AWS Load Balancer Controller installed, there can't be TargetGroupBindings to delete.
+		if meta.IsNoMatchError(err) && desiredTGB == nil {
+			return nil
+		}
+		return errors.Wrap(err, "failed to list targetGroupBindings")
+	}
+	var existingTGB *unstructured.Unstructured
+	for i := range tgbList.Items {
+		tgb := &tgbList.Items[i]
+		if !metav1.IsControlledBy(tgb, vg) {
+			continue
+		}
+		if desiredTGB != nil && tgb.GetName() == desiredTGB.GetName() {
+			existingTGB = tgb
+			continue
+		}
+		if err := m.k8sClient.Delete(ctx, tgb); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete targetGroupBinding %v", k8s.NamespacedName(tgb))
+		}
+		m.log.V(1).Info("deleted targetGroupBinding",
+			"virtualGateway", k8s.NamespacedName(vg),
+			"targetGroupBinding", k8s.NamespacedName(tgb),
+		)
+	}
+	if desiredTGB == nil {
+		return nil
+	}
+	if existingTGB == nil {
+		if err := controllerutil.SetControllerReference(vg, desiredTGB, m.scheme); err != nil {
+			return err
+		}
+		if err := m.k8sClient.Create(ctx, desiredTGB); err != nil {
+			return errors.Wrapf(err, "failed to create targetGroupBinding %v", k8s.NamespacedName(desiredTGB))
+		}
+		m.log.V(1).Info("created targetGroupBinding",
+			"virtualGateway", k8s.NamespacedName(vg),
+			"targetGroupBinding", k8s.NamespacedName(desiredTGB),
+		)
+		return nil
+	}
+
+	// only fields set by the controller are compared, the AWS Load Balancer Controller defaults the others.
+	oldTGB := existingTGB.DeepCopy()
+	desiredSpec, _, _ := unstructured.NestedMap(desiredTGB.Object, "spec")
+	for field, value := range desiredSpec {
+		if err := unstructured.SetNestedField(existingTGB.Object, value, "spec", field); err != nil {
+			return err
+		}
+	}
+	if equality.Semantic.DeepEqual(oldTGB, existingTGB) {
+		return nil
+	}
+	if err := m.k8sClient.Patch(ctx, existingTGB, client.MergeFrom(oldTGB)); err != nil {
+		return errors.Wrapf(err, "failed to update targetGroupBinding %v", k8s.NamespacedName(existingTGB))
+	}
+	m.log.V(1).Info("updated targetGroupBinding",
+		"virtualGateway", k8s.NamespacedName(vg),
+		"targetGroupBinding", k8s.NamespacedName(existingTGB),
+	)
+	return nil
+}
+
+// buildLoadBalancerService builds the Service fronting pods of vg, with a port per listener of vg.
+// it's of type LoadBalancer, unless pods are registered into an existing target group with a TargetGroupBinding.
+func buildLoadBalancerService(vg *appmesh.VirtualGateway) *corev1.Service {
+	lb := vg.Spec.ProvisionLoadBalancer
+	svcType := corev1.ServiceTypeClusterIP
+	annotations := make(map[string]string)
+	if lb.TargetGroupARN == nil {
+		svcType = corev1.ServiceTypeLoadBalancer
+		scheme := appmesh.VirtualGatewayLoadBalancerSchemeInternal
+		if lb.Scheme != nil {
+			scheme = *lb.Scheme
+		}
+		annotations[annotationLoadBalancerType] = "external"
+		annotations[annotationLoadBalancerNLBTargetType] = "ip"
+		annotations[annotationLoadBalancerScheme] = string(scheme)
+		for key, value := range buildHealthCheckAnnotations(vg) {
+			annotations[key] = value
+		}
+	}
+	for key, value := range lb.Annotations {
+		annotations[key] = value
+	}
+
+	selector := make(map[string]string)
+	if vg.Spec.PodSelector != nil {
+		for key, value := range vg.Spec.PodSelector.MatchLabels {
+			selector[key] = value
+		}
+	}
+	ports := make([]corev1.ServicePort, 0, len(vg.Spec.Listeners))
+	for _, listener := range vg.Spec.Listeners {
+		port := int32(listener.PortMapping.Port)
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("%s-%d", strings.ToLower(string(listener.PortMapping.Protocol)), port),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt(int(port)),
+		})
+	}
+
+	svc := &corev1.Service{}
+	svc.Namespace = vg.Namespace
+	svc.Name = LoadBalancerServiceName(vg)
+	svc.Labels = map[string]string{LabelVirtualGateway: vg.Name}
+	svc.Annotations = annotations
+	svc.Spec = corev1.ServiceSpec{
+		Type:     svcType,
+		Selector: selector,
+		Ports:    ports,
+	}
+	return svc
+}
+
+// buildHealthCheckAnnotations builds annotations aligning health checks of the load balancer with the health check of the first listener of vg that has one.
+// health checks of listeners with protocols other than http are done over tcp, since load balancer health checks are HTTP/1.1 only.
+func buildHealthCheckAnnotations(vg *appmesh.VirtualGateway) map[string]string {
+	for _, listener := range vg.Spec.Listeners {
+		healthCheck := listener.HealthCheck
+		if healthCheck == nil {
+			continue
+		}
+		port := listener.PortMapping.Port
+		if healthCheck.Port != nil {
+			port = *healthCheck.Port
+		}
+		annotations := map[string]string{
+			annotationLoadBalancerHealthCheckProtocol:           "tcp",
+			annotationLoadBalancerHealthCheckPort:               strconv.FormatInt(int64(port), 10),
+			annotationLoadBalancerHealthCheckInterval:           strconv.FormatInt(millisToSeconds(healthCheck.IntervalMillis), 10),
+			annotationLoadBalancerHealthCheckTimeout:            strconv.FormatInt(millisToSeconds(healthCheck.TimeoutMillis), 10),
+			annotationLoadBalancerHealthCheckHealthyThreshold:   strconv.FormatInt(healthCheck.HealthyThreshold, 10),
+			annotationLoadBalancerHealthCheckUnhealthyThreshold: strconv.FormatInt(healthCheck.UnhealthyThreshold, 10),
+		}
+		if healthCheck.Protocol == appmesh.VirtualGatewayPortProtocolHTTP && healthCheck.Path != nil {
+			annotations[annotationLoadBalancerHealthCheckProtocol] = "http"
+			annotations[annotationLoadBalancerHealthCheckPath] = aws.StringValue(healthCheck.Path)
+		}
+		return annotations
+	}
+	return nil
+}
+
+// buildTargetGroupBinding builds the TargetGroupBinding registering pods behind the first port of svc into the target group of vg.
+func buildTargetGroupBinding(vg *appmesh.VirtualGateway, svc *corev1.Service) *unstructured.Unstructured {
+	tgb := &unstructured.Unstructured{}
+	tgb.SetGroupVersionKind(targetGroupBindingGVK)
+	tgb.SetNamespace(svc.Namespace)
+	tgb.SetName(svc.Name)
+	tgb.SetLabels(map[string]string{LabelVirtualGateway: vg.Name})
+	serviceRef := map[string]interface{}{
+		"name": svc.Name,
+	}
+	if len(svc.Spec.Ports) != 0 {
+		serviceRef["port"] = int64(svc.Spec.Ports[0].Port)
+	}
+	tgb.Object["spec"] = map[string]interface{}{
+		"serviceRef":     serviceRef,
+		"targetGroupARN": aws.StringValue(vg.Spec.ProvisionLoadBalancer.TargetGroupARN),
+		"targetType":     "ip",
+	}
+	return tgb
+}
+
+// LoadBalancerServiceName returns the name of the Service fronting pods of vg.
+func LoadBalancerServiceName(vg *appmesh.VirtualGateway) string {
+	if vg.Spec.ProvisionLoadBalancer != nil && len(aws.StringValue(vg.Spec.ProvisionLoadBalancer.ServiceName)) != 0 {
+		return aws.StringValue(vg.Spec.ProvisionLoadBalancer.ServiceName)
+	}
+	return vg.Name
+}
+
+// withAllocatedNodePorts returns ports with the node ports already allocated to the same ports in existingPorts,
+// so that updates don't reallocate them.
+func withAllocatedNodePorts(ports []corev1.ServicePort, existingPorts []corev1.ServicePort) []corev1.ServicePort {
+	nodePortByPort := make(map[int32]int32, len(existingPorts))
+	for _, existingPort := range existingPorts {
+		nodePortByPort[existingPort.Port] = existingPort.NodePort
+	}
+	result := make([]corev1.ServicePort, 0, len(ports))
+	for _, port := range ports {
+		port.NodePort = nodePortByPort[port.Port]
+		result = append(result, port)
+	}
+	return result
+}
+
+func millisToSeconds(millis int64) int64 {
+	return (millis + 999) / 1000
+}
@@ -0,0 +1,336 @@
+package virtualgateway
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestLoadBalancerVirtualGateway(lb *appmesh.VirtualGatewayLoadBalancer) *appmesh.VirtualGateway {
+	return &appmesh.VirtualGateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "my-vg",
+			UID:       "vg-uid",
+		},
+		Spec: appmesh.VirtualGatewaySpec{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "ingress-gw"},
+			},
+			Listeners: []appmesh.VirtualGatewayListener{
+				{
+					PortMapping: appmesh.VirtualGatewayPortMapping{
+						Port:     8088,
+						Protocol: appmesh.VirtualGatewayPortProtocolHTTP,
+					},
+					HealthCheck: &appmesh.VirtualGatewayHealthCheckPolicy{
+						HealthyThreshold:   2,
+						IntervalMillis:     10000,
+						Path:               aws.String("/ready"),
+						Protocol:           appmesh.VirtualGatewayPortProtocolHTTP,
+						TimeoutMillis:      2500,
+						UnhealthyThreshold: 3,
+					},
+				},
+				{
+					PortMapping: appmesh.VirtualGatewayPortMapping{
+						Port:     9090,
+						Protocol: appmesh.VirtualGatewayPortProtocolGRPC,
+					},
+				},
+			},
+			ProvisionLoadBalancer: lb,
+		},
+	}
+}
+
+func Test_buildLoadBalancerService(t *testing.T) {
+	tests := []struct {
+		name    string
+		lb      *appmesh.VirtualGatewayLoadBalancer
+		want    *corev1.Service
+		wantTGB *unstructured.Unstructured
+	}{
+		{
+			name: "network load balancer with health check of listener",
+			lb: &appmesh.VirtualGatewayLoadBalancer{
+				ServiceName: aws.String("ingress"),
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/aws-load-balancer-scheme":           "internet-facing",
+					"service.beta.kubernetes.io/aws-load-balancer-healthcheck-path": "/healthz",
+				},
+			},
+			want: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "my-ns",
+					Name:      "ingress",
+					Labels:    map[string]string{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+					Annotations: map[string]string{
+						"service.beta.kubernetes.io/aws-load-balancer-type":                            "external",
+						"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type":                 "ip",
+						"service.beta.kubernetes.io/aws-load-balancer-scheme":                          "internet-facing",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-protocol":            "http",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-port":                "8088",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-path":                "/healthz",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-interval":            "10",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-timeout":             "3",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-healthy-threshold":   "2",
+						"service.beta.kubernetes.io/aws-load-balancer-healthcheck-unhealthy-threshold": "3",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:     corev1.ServiceTypeLoadBalancer,
+					Selector: map[string]string{"app": "ingress-gw"},
+					Ports: []corev1.ServicePort{
+						{Name: "http-8088", Protocol: corev1.ProtocolTCP, Port: 8088, TargetPort: intstr.FromInt(8088)},
+						{Name: "grpc-9090", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromInt(9090)},
+					},
+				},
+			},
+		},
+		{
+			name: "existing target group",
+			lb: &appmesh.VirtualGatewayLoadBalancer{
+				TargetGroupARN: aws.String("arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/73e2d6bc24d8a067"),
+				Annotations:    map[string]string{"team": "edge"},
+			},
+			want: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-vg",
+					Labels:      map[string]string{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+					Annotations: map[string]string{"team": "edge"},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:     corev1.ServiceTypeClusterIP,
+					Selector: map[string]string{"app": "ingress-gw"},
+					Ports: []corev1.ServicePort{
+						{Name: "http-8088", Protocol: corev1.ProtocolTCP, Port: 8088, TargetPort: intstr.FromInt(8088)},
+						{Name: "grpc-9090", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromInt(9090)},
+					},
+				},
+			},
+			wantTGB: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "elbv2.k8s.aws/v1beta1",
+					"kind":       "TargetGroupBinding",
+					"metadata": map[string]interface{}{
+						"namespace": "my-ns",
+						"name":      "my-vg",
+						"labels":    map[string]interface{}{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+					},
+					"spec": map[string]interface{}{
+						"serviceRef": map[string]interface{}{
+							"name": "my-vg",
+							"port": int64(8088),
+						},
+						"targetGroupARN": "arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/73e2d6bc24d8a067",
+						"targetType":     "ip",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vg := newTestLoadBalancerVirtualGateway(tt.lb)
+			got := buildLoadBalancerService(vg)
+			assert.Equal(t, tt.want, got)
+			if tt.wantTGB != nil {
+				assert.Equal(t, tt.wantTGB, buildTargetGroupBinding(vg, got))
+			}
+		})
+	}
+}
+
+func Test_buildHealthCheckAnnotations(t *testing.T) {
+	vg := newTestLoadBalancerVirtualGateway(&appmesh.VirtualGatewayLoadBalancer{})
+	vg.Spec.Listeners[0].HealthCheck = nil
+	vg.Spec.Listeners[1].HealthCheck = &appmesh.VirtualGatewayHealthCheckPolicy{
+		HealthyThreshold:   3,
+		IntervalMillis:     5000,
+		Port:               func() *appmesh.PortNumber { port := appmesh.PortNumber(9091); return &port }(),
+		Protocol:           appmesh.VirtualGatewayPortProtocolGRPC,
+		TimeoutMillis:      2000,
+		UnhealthyThreshold: 3,
+	}
+	assert.Equal(t, map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-protocol":            "tcp",
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-port":                "9091",
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-interval":            "5",
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-timeout":             "2",
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-healthy-threshold":   "3",
+		"service.beta.kubernetes.io/aws-load-balancer-healthcheck-unhealthy-threshold": "3",
+	}, buildHealthCheckAnnotations(vg))
+
+	vg.Spec.Listeners[1].HealthCheck = nil
+	assert.Nil(t, buildHealthCheckAnnotations(vg))
+}
+
+func Test_defaultLoadBalancerManager_Reconcile(t *testing.T) {
+	ownerRef := metav1.OwnerReference{
+		APIVersion:         "appmesh.k8s.aws/v1beta2",
+		Kind:               "VirtualGateway",
+		Name:               "my-vg",
+		UID:                "vg-uid",
+		Controller:         aws.Bool(true),
+		BlockOwnerDeletion: aws.Bool(true),
+	}
+	tests := []struct {
+		name             string
+		lb               *appmesh.VirtualGatewayLoadBalancer
+		existingServices []*corev1.Service
+		wantServices     []corev1.Service
+	}{
+		{
+			name: "service doesn't exist",
+			lb:   &appmesh.VirtualGatewayLoadBalancer{},
+			wantServices: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-vg", OwnerReferences: []metav1.OwnerReference{ownerRef}},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Ports: []corev1.ServicePort{
+							{Name: "http-8088", Protocol: corev1.ProtocolTCP, Port: 8088, TargetPort: intstr.FromInt(8088)},
+							{Name: "grpc-9090", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromInt(9090)},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "service exists with stale ports",
+			lb:   &appmesh.VirtualGatewayLoadBalancer{},
+			existingServices: []*corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "my-ns",
+						Name:            "my-vg",
+						Labels:          map[string]string{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Ports: []corev1.ServicePort{
+							{Name: "http-8088", Protocol: corev1.ProtocolTCP, Port: 8088, TargetPort: intstr.FromInt(8088), NodePort: 31088},
+							{Name: "http-8089", Protocol: corev1.ProtocolTCP, Port: 8089, TargetPort: intstr.FromInt(8089), NodePort: 31089},
+						},
+					},
+				},
+			},
+			wantServices: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-vg", OwnerReferences: []metav1.OwnerReference{ownerRef}},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Ports: []corev1.ServicePort{
+							{Name: "http-8088", Protocol: corev1.ProtocolTCP, Port: 8088, TargetPort: intstr.FromInt(8088), NodePort: 31088},
+							{Name: "grpc-9090", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromInt(9090)},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "provisionLoadBalancer is removed",
+			existingServices: []*corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "my-ns",
+						Name:            "my-vg",
+						Labels:          map[string]string{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "unmanaged",
+						Labels:    map[string]string{"appmesh.k8s.aws/virtualGateway": "my-vg"},
+					},
+				},
+			},
+			wantServices: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, svc := range tt.existingServices {
+				assert.NoError(t, k8sClient.Create(ctx, svc.DeepCopy()))
+			}
+
+			m := NewDefaultLoadBalancerManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+			vg := newTestLoadBalancerVirtualGateway(tt.lb)
+			assert.NoError(t, m.Reconcile(ctx, vg))
+
+			svcList := &corev1.ServiceList{}
+			assert.NoError(t, k8sClient.List(ctx, svcList))
+			var gotServices []corev1.Service
+			for _, svc := range svcList.Items {
+				gotServices = append(gotServices, corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: svc.Name, OwnerReferences: svc.OwnerReferences},
+					Spec:       corev1.ServiceSpec{Type: svc.Spec.Type, Ports: svc.Spec.Ports},
+				})
+			}
+			assert.Equal(t, tt.wantServices, gotServices)
+		})
+	}
+}
+
+func Test_defaultLoadBalancerManager_Reconcile_targetGroupBinding(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	m := NewDefaultLoadBalancerManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+
+	vg := newTestLoadBalancerVirtualGateway(&appmesh.VirtualGatewayLoadBalancer{
+		TargetGroupARN: aws.String("arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/73e2d6bc24d8a067"),
+	})
+	assert.NoError(t, m.Reconcile(ctx, vg))
+	tgbList := &unstructured.UnstructuredList{}
+	tgbList.SetGroupVersionKind(targetGroupBindingGVK.GroupVersion().WithKind("TargetGroupBindingList"))
+	assert.NoError(t, k8sClient.List(ctx, tgbList))
+	if assert.Len(t, tgbList.Items, 1) {
+		tgb := tgbList.Items[0]
+		assert.Equal(t, "my-vg", tgb.GetName())
+		assert.True(t, metav1.IsControlledBy(&tgb, vg))
+		targetGroupARN, _, _ := unstructured.NestedString(tgb.Object, "spec", "targetGroupARN")
+		assert.Equal(t, "arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/73e2d6bc24d8a067", targetGroupARN)
+	}
+
+	vg.Spec.ProvisionLoadBalancer.TargetGroupARN = aws.String("arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/0a1b2c3d4e5f6a7b")
+	assert.NoError(t, m.Reconcile(ctx, vg))
+	assert.NoError(t, k8sClient.List(ctx, tgbList))
+	if assert.Len(t, tgbList.Items, 1) {
+		targetGroupARN, _, _ := unstructured.NestedString(tgbList.Items[0].Object, "spec", "targetGroupARN")
+		assert.Equal(t, "arn:aws:elasticloadbalancing:us-west-2:111122223333:targetgroup/ingress/0a1b2c3d4e5f6a7b", targetGroupARN)
+	}
+
+	vg.Spec.ProvisionLoadBalancer = nil
+	assert.NoError(t, m.Reconcile(ctx, vg))
+	assert.NoError(t, k8sClient.List(ctx, tgbList))
+	assert.Len(t, tgbList.Items, 0)
+}
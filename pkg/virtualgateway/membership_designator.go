@@ -65,7 +65,7 @@ func (d *membershipDesignator) DesignateForPod(ctx context.Context, pod *corev1.
 			vgCandidatesNames = append(vgCandidatesNames, k8s.NamespacedName(vg).String())
 		}
 		return nil, errors.Errorf("found multiple matching VirtualGateways for pod %s: %s",
-			k8s.NamespacedName(pod).String(), strings.Join(vgCandidatesNames, ","))
+			k8s.PodIdentifier(req.Namespace, pod), strings.Join(vgCandidatesNames, ","))
 	}
 	return vgCandidates[0], nil
 }
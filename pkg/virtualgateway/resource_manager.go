@@ -4,20 +4,22 @@ import (
 	"context"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -36,6 +38,8 @@ func NewDefaultResourceManager(
 	appMeshSDK services.AppMesh,
 	referencesResolver references.Resolver,
 	accountID string,
+	tagsManager tagging.Manager,
+	quotaChecker quota.Checker,
 	log logr.Logger) ResourceManager {
 
 	return &defaultResourceManager{
@@ -43,6 +47,8 @@ func NewDefaultResourceManager(
 		appMeshSDK:         appMeshSDK,
 		referencesResolver: referencesResolver,
 		accountID:          accountID,
+		tagsManager:        tagsManager,
+		quotaChecker:       quotaChecker,
 		log:                log,
 	}
 }
@@ -53,31 +59,42 @@ type defaultResourceManager struct {
 	appMeshSDK         services.AppMesh
 	referencesResolver references.Resolver
 	accountID          string
+	tagsManager        tagging.Manager
+	quotaChecker       quota.Checker
 	log                logr.Logger
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, vg *appmesh.VirtualGateway) error {
 	ms, err := m.findMeshDependency(ctx, vg)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateMeshDependencies(ctx, ms); err != nil {
-		return err
+		return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 
 	sdkVG, err := m.findSDKVirtualGateway(ctx, ms, vg)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	if err := m.quotaChecker.CheckVirtualGateway(ctx, ms, vg, sdkVG == nil); err != nil {
+		return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonQuotaExceeded, err)
 	}
 	if sdkVG == nil {
 		sdkVG, err = m.createSDKVirtualGateway(ctx, ms, vg)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
+		if err := m.checkSDKVirtualGatewayOwnership(ctx, sdkVG, vg); err != nil {
+			return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
 		sdkVG, err = m.updateSDKVirtualGateway(ctx, sdkVG, ms, vg)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.reconcileSDKVirtualGatewayTags(ctx, sdkVG, vg); err != nil {
+			return m.updateCRDVirtualGatewayForFailure(ctx, vg, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	}
 
@@ -100,6 +117,16 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, vg *appmesh.Virtua
 		return nil
 	}
 
+	if err := m.checkSDKVirtualGatewayOwnership(ctx, sdkVG, vg); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip virtualGateway deletion since it's owned by another controller",
+			"virtualGateway", k8s.NamespacedName(vg),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	return m.deleteSDKVirtualGateway(ctx, sdkVG, ms, vg)
 }
 
@@ -144,11 +171,13 @@ func (m *defaultResourceManager) createSDKVirtualGateway(ctx context.Context, ms
 	if err != nil {
 		return nil, err
 	}
+	applyMeshTLSEnforcementMode(ms, sdkVGSpec)
 	resp, err := m.appMeshSDK.CreateVirtualGatewayWithContext(ctx, &appmeshsdk.CreateVirtualGatewayInput{
 		MeshName:           ms.Spec.AWSName,
 		MeshOwner:          ms.Spec.MeshOwner,
 		Spec:               sdkVGSpec,
 		VirtualGatewayName: vg.Spec.AWSName,
+		Tags:               m.buildSDKVirtualGatewayTags(ctx, vg),
 	})
 	if err != nil {
 		return nil, err
@@ -162,6 +191,7 @@ func (m *defaultResourceManager) updateSDKVirtualGateway(ctx context.Context, sd
 	if err != nil {
 		return nil, err
 	}
+	applyMeshTLSEnforcementMode(ms, desiredSDKVGSpec)
 
 	opts := equality.CompareOptionForVirtualGatewaySpec()
 	if cmp.Equal(desiredSDKVGSpec, actualSDKVGSpec, opts) {
@@ -182,7 +212,7 @@ func (m *defaultResourceManager) updateSDKVirtualGateway(ctx context.Context, sd
 		"desiredSDKVGSpec", desiredSDKVGSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateVirtualGatewayWithContext(ctx, &appmeshsdk.UpdateVirtualGatewayInput{
+	resp, err := m.appMeshSDK.UpdateVirtualGatewayWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateVirtualGatewayInput{
 		MeshName:           ms.Spec.AWSName,
 		MeshOwner:          ms.Spec.MeshOwner,
 		Spec:               desiredSDKVGSpec,
@@ -221,12 +251,17 @@ func (m *defaultResourceManager) updateCRDVirtualGateway(ctx context.Context, vg
 		vg.Status.VirtualGatewayARN = sdkVG.Metadata.Arn
 		needsUpdate = true
 	}
-
-	vgActiveConditionStatus := corev1.ConditionFalse
-	if sdkVG.Status != nil && aws.StringValue(sdkVG.Status.Status) == appmeshsdk.VirtualGatewayStatusCodeActive {
-		vgActiveConditionStatus = corev1.ConditionTrue
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkVG.Metadata, resourceMetadata); err != nil {
+		return err
 	}
-	if updateCondition(vg, appmesh.VirtualGatewayActive, vgActiveConditionStatus, nil, nil) {
+	if !cmp.Equal(vg.Status.ResourceMetadata, resourceMetadata) {
+		vg.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
+
+	vgActive := sdkVG.Status != nil && aws.StringValue(sdkVG.Status.Status) == appmeshsdk.VirtualGatewayStatusCodeActive
+	if updateConditionsForReconciled(vg, vgActive) {
 		needsUpdate = true
 	}
 
@@ -236,11 +271,42 @@ func (m *defaultResourceManager) updateCRDVirtualGateway(ctx context.Context, vg
 	return m.k8sClient.Status().Patch(ctx, vg, client.MergeFrom(oldVG))
 }
 
+// updateCRDVirtualGatewayForFailure will record reconcile failure err into virtualGateway's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDVirtualGatewayForFailure(ctx context.Context, vg *appmesh.VirtualGateway, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	oldVG := vg.DeepCopy()
+	if !updateConditionsForFailure(vg, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, vg, client.MergeFrom(oldVG)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "virtualGateway", k8s.NamespacedName(vg))
+	}
+	return err
+}
+
 func (m *defaultResourceManager) buildSDKVirtualGatewayTags(ctx context.Context, vg *appmesh.VirtualGateway) []*appmeshsdk.TagRef {
-	// TODO, support tags
+	return m.tagsManager.BuildTags(vg)
+}
+
+// reconcileSDKVirtualGatewayTags corrects tags of AppMesh virtualGateway if it's controlled by CRD virtualGateway.
+func (m *defaultResourceManager) reconcileSDKVirtualGatewayTags(ctx context.Context, sdkVG *appmeshsdk.VirtualGatewayData, vg *appmesh.VirtualGateway) error {
+	if !m.isSDKVirtualGatewayControlledByCRDVirtualGateway(ctx, sdkVG, vg) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkVG.Metadata.Arn), vg); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
 	return nil
 }
 
+// checkSDKVirtualGatewayOwnership checks AppMesh virtualGateway isn't owned by another controller if it's controlled by CRD virtualGateway.
+func (m *defaultResourceManager) checkSDKVirtualGatewayOwnership(ctx context.Context, sdkVG *appmeshsdk.VirtualGatewayData, vg *appmesh.VirtualGateway) error {
+	if !m.isSDKVirtualGatewayControlledByCRDVirtualGateway(ctx, sdkVG, vg) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkVG.Metadata.Arn), vg)
+}
+
 // isSDKVirtualGatewayControlledByCRDVirtualGateway checks whether an AppMesh virtualGateway is controlled by CRD virtualGateway
 // if it's controlled, CRD virtualGateway update is responsible for update AppMesh virtualGateway.
 func (m *defaultResourceManager) isSDKVirtualGatewayControlledByCRDVirtualGateway(ctx context.Context, sdkVG *appmeshsdk.VirtualGatewayData, vg *appmesh.VirtualGateway) bool {
@@ -274,3 +340,13 @@ func BuildSDKVirtualGatewaySpec(ctx context.Context, vg *appmesh.VirtualGateway)
 	}
 	return sdkVGSpec, nil
 }
+
+// applyMeshTLSEnforcementMode disables enforcement of client policy TLS when mesh is in TLS audit mode.
+func applyMeshTLSEnforcementMode(ms *appmesh.Mesh, sdkVGSpec *appmeshsdk.VirtualGatewaySpec) {
+	if !mesh.IsTLSEnforcementAudit(ms) {
+		return
+	}
+	if sdkVGSpec.BackendDefaults != nil && sdkVGSpec.BackendDefaults.ClientPolicy != nil && sdkVGSpec.BackendDefaults.ClientPolicy.Tls != nil {
+		sdkVGSpec.BackendDefaults.ClientPolicy.Tls.Enforce = aws.Bool(false)
+	}
+}
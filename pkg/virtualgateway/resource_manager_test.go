@@ -14,7 +14,6 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -45,7 +44,10 @@ func Test_defaultResourceManager_updateCRDVirtualGateway(t *testing.T) {
 				},
 				sdkVG: &appmeshsdk.VirtualGatewayData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualGatewayStatus{
 						Status: aws.String(appmeshsdk.VirtualGatewayStatusCodeActive),
@@ -58,10 +60,36 @@ func Test_defaultResourceManager_updateCRDVirtualGateway(t *testing.T) {
 				},
 				Status: appmesh.VirtualGatewayStatus{
 					VirtualGatewayARN: aws.String("arn-1"),
-					Conditions: []appmesh.VirtualGatewayCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.VirtualGatewayActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -76,17 +104,25 @@ func Test_defaultResourceManager_updateCRDVirtualGateway(t *testing.T) {
 					},
 					Status: appmesh.VirtualGatewayStatus{
 						VirtualGatewayARN: aws.String("arn-1"),
-						Conditions: []appmesh.VirtualGatewayCondition{
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
 				},
 				sdkVG: &appmeshsdk.VirtualGatewayData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualGatewayStatus{
 						Status: aws.String(appmeshsdk.VirtualGatewayStatusCodeInactive),
@@ -99,10 +135,36 @@ func Test_defaultResourceManager_updateCRDVirtualGateway(t *testing.T) {
 				},
 				Status: appmesh.VirtualGatewayStatus{
 					VirtualGatewayARN: aws.String("arn-1"),
-					Conditions: []appmesh.VirtualGatewayCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.VirtualGatewayActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
 						},
 					},
 				},
@@ -133,7 +195,7 @@ func Test_defaultResourceManager_updateCRDVirtualGateway(t *testing.T) {
 				assert.NoError(t, err)
 				opts := cmp.Options{
 					equality.IgnoreFakeClientPopulatedFields(),
-					cmpopts.IgnoreTypes((*metav1.Time)(nil)),
+					cmpopts.IgnoreTypes(metav1.Time{}),
 				}
 				assert.True(t, cmp.Equal(tt.wantVG, gotVG, opts), "diff", cmp.Diff(tt.wantVG, gotVG, opts))
 			}
@@ -400,10 +462,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
 						},
 					},
 				},
@@ -421,10 +483,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
 						},
 					},
 				},
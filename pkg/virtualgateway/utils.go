@@ -2,7 +2,7 @@ package virtualgateway
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IsVirtualGatewayActive tests whether given virtualGateway is active.
@@ -10,7 +10,7 @@ import (
 func IsVirtualGatewayActive(vg *appmesh.VirtualGateway) bool {
 	for _, condition := range vg.Status.Conditions {
 		if condition.Type == appmesh.VirtualGatewayActive {
-			return condition.Status == corev1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue
 		}
 	}
 	return false
@@ -4,7 +4,6 @@ import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
@@ -23,10 +22,10 @@ func TestIsVirtualGatewayActive(t *testing.T) {
 			args: args{
 				vg: &appmesh.VirtualGateway{
 					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
@@ -39,10 +38,10 @@ func TestIsVirtualGatewayActive(t *testing.T) {
 			args: args{
 				vg: &appmesh.VirtualGateway{
 					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionFalse,
+								Status: metav1.ConditionFalse,
 							},
 						},
 					},
@@ -55,10 +54,10 @@ func TestIsVirtualGatewayActive(t *testing.T) {
 			args: args{
 				vg: &appmesh.VirtualGateway{
 					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualGatewayActive,
-								Status: corev1.ConditionUnknown,
+								Status: metav1.ConditionUnknown,
 							},
 						},
 					},
@@ -71,7 +70,7 @@ func TestIsVirtualGatewayActive(t *testing.T) {
 			args: args{
 				vg: &appmesh.VirtualGateway{
 					Status: appmesh.VirtualGatewayStatus{
-						Conditions: []appmesh.VirtualGatewayCondition{},
+						Conditions: []metav1.Condition{},
 					},
 				},
 			},
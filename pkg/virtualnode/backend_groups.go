@@ -0,0 +1,98 @@
+package virtualnode
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backendGroupWildcard is the backendGroup name that selects all virtualServices within its namespace.
+const backendGroupWildcard = "*"
+
+// listSelectedVirtualServices lists virtualServices selected by virtualServiceSelector of bg.
+// virtualServices being deleted or within other meshes are excluded.
+func listSelectedVirtualServices(ctx context.Context, k8sClient client.Client, bg *appmesh.BackendGroup) ([]appmesh.VirtualService, error) {
+	if bg.Spec.VirtualServiceSelector == nil {
+		return nil, nil
+	}
+	vsSelector, err := metav1.LabelSelectorAsSelector(bg.Spec.VirtualServiceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid virtualServiceSelector")
+	}
+	listOpts := []client.ListOption{client.MatchingLabelsSelector{Selector: vsSelector}}
+	var namespaces map[string]bool
+	if bg.Spec.NamespaceSelector == nil {
+		listOpts = append(listOpts, client.InNamespace(bg.Namespace))
+	} else {
+		if namespaces, err = listSelectedNamespaces(ctx, k8sClient, bg.Spec.NamespaceSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	vsList := &appmesh.VirtualServiceList{}
+	if err := k8sClient.List(ctx, vsList, listOpts...); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualServices")
+	}
+	var selectedVSs []appmesh.VirtualService
+	for _, vs := range vsList.Items {
+		if namespaces != nil && !namespaces[vs.Namespace] {
+			continue
+		}
+		if !vs.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if bg.Spec.MeshRef == nil || vs.Spec.MeshRef == nil || *bg.Spec.MeshRef != *vs.Spec.MeshRef {
+			continue
+		}
+		selectedVSs = append(selectedVSs, vs)
+	}
+	return selectedVSs, nil
+}
+
+// isVirtualServiceSelectedByBackendGroup checks whether labels and namespace of vs are selected by virtualServiceSelector of bg.
+func isVirtualServiceSelectedByBackendGroup(ctx context.Context, k8sClient client.Client, bg *appmesh.BackendGroup, vs *appmesh.VirtualService) (bool, error) {
+	if bg.Spec.VirtualServiceSelector == nil {
+		return false, nil
+	}
+	vsSelector, err := metav1.LabelSelectorAsSelector(bg.Spec.VirtualServiceSelector)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid virtualServiceSelector")
+	}
+	if !vsSelector.Matches(labels.Set(vs.Labels)) {
+		return false, nil
+	}
+	if bg.Spec.NamespaceSelector == nil {
+		return vs.Namespace == bg.Namespace, nil
+	}
+	nsSelector, err := metav1.LabelSelectorAsSelector(bg.Spec.NamespaceSelector)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid namespaceSelector")
+	}
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: vs.Namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return nsSelector.Matches(labels.Set(ns.Labels)), nil
+}
+
+func listSelectedNamespaces(ctx context.Context, k8sClient client.Client, namespaceSelector *metav1.LabelSelector) (map[string]bool, error) {
+	nsSelector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid namespaceSelector")
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := k8sClient.List(ctx, nsList, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	namespaces := make(map[string]bool, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		namespaces[ns.Name] = true
+	}
+	return namespaces, nil
+}
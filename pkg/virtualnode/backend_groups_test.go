@@ -0,0 +1,117 @@
+package virtualnode
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func Test_listSelectedVirtualServices(t *testing.T) {
+	meshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	anotherMeshRef := &appmesh.MeshReference{
+		Name: "another-mesh",
+		UID:  "0d65db83-1b4c-40aa-90ba-57064dd73c98",
+	}
+	deletionTimestamp := metav1.Now()
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-1", Labels: map[string]string{"env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-2", Labels: map[string]string{"env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-3", Labels: map[string]string{"env": "dev"}}},
+	}
+	newVS := func(namespace string, name string, labels map[string]string, meshRef *appmesh.MeshReference) *appmesh.VirtualService {
+		return &appmesh.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+			Spec:       appmesh.VirtualServiceSpec{MeshRef: meshRef},
+		}
+	}
+	vsDeleting := newVS("ns-1", "vs-deleting", map[string]string{"team": "payments"}, meshRef)
+	vsDeleting.DeletionTimestamp = &deletionTimestamp
+	vsDeleting.Finalizers = []string{"finalizers.appmesh.k8s.aws/aws-resources"}
+	virtualServices := []*appmesh.VirtualService{
+		newVS("ns-1", "vs-payments", map[string]string{"team": "payments"}, meshRef),
+		newVS("ns-1", "vs-orders", map[string]string{"team": "orders"}, meshRef),
+		newVS("ns-1", "vs-payments-another-mesh", map[string]string{"team": "payments"}, anotherMeshRef),
+		newVS("ns-2", "vs-payments", map[string]string{"team": "payments"}, meshRef),
+		newVS("ns-3", "vs-payments", map[string]string{"team": "payments"}, meshRef),
+		vsDeleting,
+	}
+	tests := []struct {
+		name     string
+		bgSpec   appmesh.BackendGroupSpec
+		wantKeys []types.NamespacedName
+	}{
+		{
+			name:     "without virtualServiceSelector",
+			bgSpec:   appmesh.BackendGroupSpec{MeshRef: meshRef},
+			wantKeys: nil,
+		},
+		{
+			name: "select within backendGroup's namespace",
+			bgSpec: appmesh.BackendGroupSpec{
+				VirtualServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+				MeshRef:                meshRef,
+			},
+			wantKeys: []types.NamespacedName{{Namespace: "ns-1", Name: "vs-payments"}},
+		},
+		{
+			name: "select within selected namespaces",
+			bgSpec: appmesh.BackendGroupSpec{
+				VirtualServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+				NamespaceSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				MeshRef:                meshRef,
+			},
+			wantKeys: []types.NamespacedName{{Namespace: "ns-1", Name: "vs-payments"}, {Namespace: "ns-2", Name: "vs-payments"}},
+		},
+		{
+			name: "select within all namespaces",
+			bgSpec: appmesh.BackendGroupSpec{
+				VirtualServiceSelector: &metav1.LabelSelector{},
+				NamespaceSelector:      &metav1.LabelSelector{},
+				MeshRef:                meshRef,
+			},
+			wantKeys: []types.NamespacedName{
+				{Namespace: "ns-1", Name: "vs-orders"},
+				{Namespace: "ns-1", Name: "vs-payments"},
+				{Namespace: "ns-2", Name: "vs-payments"},
+				{Namespace: "ns-3", Name: "vs-payments"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, ns := range namespaces {
+				assert.NoError(t, k8sClient.Create(ctx, ns.DeepCopy()))
+			}
+			for _, vs := range virtualServices {
+				assert.NoError(t, k8sClient.Create(ctx, vs.DeepCopy()))
+			}
+			bg := &appmesh.BackendGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "my-bg"},
+				Spec:       tt.bgSpec,
+			}
+			gotVSs, err := listSelectedVirtualServices(ctx, k8sClient, bg)
+			assert.NoError(t, err)
+			var gotKeys []types.NamespacedName
+			for i := range gotVSs {
+				gotKeys = append(gotKeys, k8s.NamespacedName(&gotVSs[i]))
+			}
+			assert.ElementsMatch(t, tt.wantKeys, gotKeys)
+		})
+	}
+}
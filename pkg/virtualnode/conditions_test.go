@@ -1,270 +1,83 @@
 package virtualnode
 
 import (
-	"github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"testing"
+
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"testing"
 )
 
-func Test_getCondition(t *testing.T) {
-	type args struct {
-		vn            *appmesh.VirtualNode
-		conditionType v1beta2.VirtualNodeConditionType
-	}
-	tests := []struct {
-		name string
-		args args
-		want *appmesh.VirtualNodeCondition
-	}{
-		{
-			name: "condition found",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-			},
-			want: &appmesh.VirtualNodeCondition{
-				Type:   appmesh.VirtualNodeActive,
-				Status: corev1.ConditionFalse,
-			},
-		},
-		{
-			name: "condition not found",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-			},
-			want: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := getCondition(tt.args.vn, tt.args.conditionType)
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
-func Test_updateCondition(t *testing.T) {
+func Test_updateConditionsForReconciled(t *testing.T) {
 	type args struct {
-		vn            *appmesh.VirtualNode
-		conditionType appmesh.VirtualNodeConditionType
-		status        corev1.ConditionStatus
-		reason        *string
-		message       *string
+		vn     *appmesh.VirtualNode
+		active bool
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantVN      *appmesh.VirtualNode
-		wantChanged bool
+		name           string
+		args           args
+		wantConditions []metav1.Condition
+		wantChanged    bool
 	}{
 		{
-			name: "condition updated by modify condition status",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:   appmesh.VirtualNodeActive,
-							Status: corev1.ConditionTrue,
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition reason",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:   appmesh.VirtualNodeActive,
-							Status: corev1.ConditionTrue,
-							Reason: aws.String("reason"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition message",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionTrue,
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-				message:       aws.String("message"),
-			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:    appmesh.VirtualNodeActive,
-							Status:  corev1.ConditionTrue,
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: true,
-		},
-		{
-			name: "condition updated by modify condition status/reason/message",
+			name: "active virtualNode",
 			args: args{
 				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionFalse,
-							},
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: true,
 			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:    appmesh.VirtualNodeActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.VirtualNodeActive, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
 			},
 			wantChanged: true,
 		},
 		{
-			name: "condition updated by new condition",
+			name: "inactive virtualNode",
 			args: args{
 				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: nil,
-					},
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
 				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
+				active: false,
 			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:    appmesh.VirtualNodeActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
+			wantConditions: []metav1.Condition{
+				{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionSynced, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonReconciled},
+				{Type: appmesh.ConditionDegraded, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
+				{Type: appmesh.VirtualNodeActive, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: appmesh.ReasonAppMeshResourceInactive},
 			},
 			wantChanged: true,
 		},
-		{
-			name: "condition unmodified",
-			args: args{
-				vn: &appmesh.VirtualNode{
-					Status: appmesh.VirtualNodeStatus{
-						Conditions: []appmesh.VirtualNodeCondition{
-							{
-								Type:    appmesh.VirtualNodeActive,
-								Status:  corev1.ConditionTrue,
-								Reason:  aws.String("reason"),
-								Message: aws.String("message"),
-							},
-						},
-					},
-				},
-				conditionType: appmesh.VirtualNodeActive,
-				status:        corev1.ConditionTrue,
-				reason:        aws.String("reason"),
-				message:       aws.String("message"),
-			},
-			wantVN: &appmesh.VirtualNode{
-				Status: appmesh.VirtualNodeStatus{
-					Conditions: []appmesh.VirtualNodeCondition{
-						{
-							Type:    appmesh.VirtualNodeActive,
-							Status:  corev1.ConditionTrue,
-							Reason:  aws.String("reason"),
-							Message: aws.String("message"),
-						},
-					},
-				},
-			},
-			wantChanged: false,
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotChanged := updateCondition(tt.args.vn, tt.args.conditionType, tt.args.status, tt.args.reason, tt.args.message)
-			opts := cmpopts.IgnoreTypes((*metav1.Time)(nil))
-			assert.True(t, cmp.Equal(tt.wantVN, tt.args.vn, opts), "diff", cmp.Diff(tt.wantVN, tt.args.vn, opts))
+			gotChanged := updateConditionsForReconciled(tt.args.vn, tt.args.active)
+			opts := cmpopts.IgnoreTypes(metav1.Time{})
+			assert.True(t, cmp.Equal(tt.wantConditions, tt.args.vn.Status.Conditions, opts), "diff", cmp.Diff(tt.wantConditions, tt.args.vn.Status.Conditions, opts))
 			assert.Equal(t, tt.wantChanged, gotChanged)
 		})
 	}
 }
+
+func Test_updateConditionsForFailure(t *testing.T) {
+	vn := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+	}
+	gotChanged := updateConditionsForFailure(vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, errors.New("oops"))
+	wantConditions := []metav1.Condition{
+		{Type: appmesh.ConditionReferencesResolved, Status: metav1.ConditionTrue, ObservedGeneration: 3, Reason: appmesh.ReasonReconciled},
+		{Type: appmesh.ConditionSynced, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+		{Type: appmesh.ConditionReady, Status: metav1.ConditionFalse, ObservedGeneration: 3, Reason: appmesh.ReasonSyncFailed, Message: "oops"},
+	}
+	opts := cmpopts.IgnoreTypes(metav1.Time{})
+	assert.True(t, cmp.Equal(wantConditions, vn.Status.Conditions, opts), "diff", cmp.Diff(wantConditions, vn.Status.Conditions, opts))
+	assert.True(t, gotChanged)
+}
@@ -0,0 +1,48 @@
+package virtualnode
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagEnableSidecarRollout              = "enable-sidecar-rollout"
+	flagSidecarRolloutMaxConcurrentDeploy = "sidecar-rollout-max-concurrent-deployments"
+	flagEndpointHealthReportInterval      = "endpoint-health-report-interval"
+)
+
+type Config struct {
+	// If enabled, Deployments backing a VirtualNode are restarted when the VirtualNode spec changes
+	// in a way that requires Envoy sidecars to be re-injected.
+	EnableSidecarRollout bool
+	// Maximum number of Deployments per VirtualNode that can be restarting at the same time.
+	SidecarRolloutMaxConcurrentDeployments int
+	// How often the health of pods selected by VirtualNodes with listener health checks is read from their Envoys,
+	// and reported by the EndpointsHealthy condition. reporting is disabled if it's 0.
+	EndpointHealthReportInterval time.Duration
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&cfg.EnableSidecarRollout, flagEnableSidecarRollout, false,
+		"If enabled, Deployments of a VirtualNode will be rolling restarted when VirtualNode spec change requires Envoy restart")
+	fs.IntVar(&cfg.SidecarRolloutMaxConcurrentDeployments, flagSidecarRolloutMaxConcurrentDeploy, 1,
+		"Maximum number of Deployments per VirtualNode restarting concurrently during sidecar rollout")
+	fs.DurationVar(&cfg.EndpointHealthReportInterval, flagEndpointHealthReportInterval, 0,
+		"How often the health checks of pods of VirtualNodes with listener health checks are read from their Envoys and reported by the EndpointsHealthy condition, 0s disables")
+}
+
+func (cfg *Config) BindEnv() error {
+	return nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.SidecarRolloutMaxConcurrentDeployments < 1 {
+		return errors.Errorf("%s must be greater than 0", flagSidecarRolloutMaxConcurrentDeploy)
+	}
+	if cfg.EndpointHealthReportInterval < 0 {
+		return errors.Errorf("%s must not be negative: %v", flagEndpointHealthReportInterval, cfg.EndpointHealthReportInterval)
+	}
+	return nil
+}
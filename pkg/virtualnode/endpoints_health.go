@@ -0,0 +1,113 @@
+package virtualnode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/envoy"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxUnhealthyEndpointsInMessage is the maximum number of unhealthy endpoints described in the EndpointsHealthy condition.
+	maxUnhealthyEndpointsInMessage = 5
+)
+
+// EndpointsHealthReporter reports the health of pods selected by VirtualNodes with listener health checks.
+type EndpointsHealthReporter interface {
+	// Report sets the EndpointsHealthy condition of vn from the health checks of the Envoys of pods selected by vn,
+	// or removes it if vn has no listener health checks. returns whether conditions of vn are updated.
+	Report(ctx context.Context, vn *appmesh.VirtualNode) (bool, error)
+}
+
+// NewDefaultEndpointsHealthReporter constructs new EndpointsHealthReporter
+func NewDefaultEndpointsHealthReporter(k8sClient client.Client, healthChecker envoy.HealthChecker) EndpointsHealthReporter {
+	return &defaultEndpointsHealthReporter{
+		k8sClient:     k8sClient,
+		healthChecker: healthChecker,
+	}
+}
+
+var _ EndpointsHealthReporter = &defaultEndpointsHealthReporter{}
+
+type defaultEndpointsHealthReporter struct {
+	k8sClient     client.Client
+	healthChecker envoy.HealthChecker
+}
+
+func (r *defaultEndpointsHealthReporter) Report(ctx context.Context, vn *appmesh.VirtualNode) (bool, error) {
+	if !hasListenerWithHealthCheck(vn) {
+		if meta.FindStatusCondition(vn.Status.Conditions, appmesh.ConditionEndpointsHealthy) == nil {
+			return false, nil
+		}
+		meta.RemoveStatusCondition(&vn.Status.Conditions, appmesh.ConditionEndpointsHealthy)
+		return true, nil
+	}
+	pods, err := listSelectedPods(ctx, r.k8sClient, vn)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) == 0 {
+		return k8s.SetStatusCondition(&vn.Status.Conditions, metav1.Condition{
+			Type:               appmesh.ConditionEndpointsHealthy,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: vn.Generation,
+			Reason:             appmesh.ReasonNoEndpoints,
+			Message:            "EndpointsHealthy 0/0",
+		}), nil
+	}
+
+	var unhealthyEndpoints []string
+	for i := range pods {
+		healthy, reason, err := r.healthChecker.Check(ctx, &pods[i])
+		// endpoints whose health can't be read are reported as unhealthy, so one of them doesn't hide the others.
+		if err != nil {
+			reason = err.Error()
+		}
+		if !healthy {
+			unhealthyEndpoints = append(unhealthyEndpoints, fmt.Sprintf("%s (%s)", pods[i].Name, reason))
+		}
+	}
+	message := fmt.Sprintf("EndpointsHealthy %d/%d", len(pods)-len(unhealthyEndpoints), len(pods))
+	if len(unhealthyEndpoints) == 0 {
+		return k8s.SetStatusCondition(&vn.Status.Conditions, metav1.Condition{
+			Type:               appmesh.ConditionEndpointsHealthy,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: vn.Generation,
+			Reason:             appmesh.ReasonHealthChecksPassing,
+			Message:            message,
+		}), nil
+	}
+	return k8s.SetStatusCondition(&vn.Status.Conditions, metav1.Condition{
+		Type:               appmesh.ConditionEndpointsHealthy,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: vn.Generation,
+		Reason:             appmesh.ReasonHealthChecksFailing,
+		Message:            fmt.Sprintf("%s, unhealthy: %s", message, describeUnhealthyEndpoints(unhealthyEndpoints)),
+	}), nil
+}
+
+// hasListenerWithHealthCheck checks whether any listener of vn has a health check, or derives it from probes.
+func hasListenerWithHealthCheck(vn *appmesh.VirtualNode) bool {
+	for _, listener := range vn.Spec.Listeners {
+		if listener.HealthCheck != nil || aws.BoolValue(listener.HealthCheckFromProbe) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeUnhealthyEndpoints describes up to maxUnhealthyEndpointsInMessage unhealthy endpoints.
+func describeUnhealthyEndpoints(unhealthyEndpoints []string) string {
+	if len(unhealthyEndpoints) <= maxUnhealthyEndpointsInMessage {
+		return strings.Join(unhealthyEndpoints, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(unhealthyEndpoints[:maxUnhealthyEndpointsInMessage], ", "),
+		len(unhealthyEndpoints)-maxUnhealthyEndpointsInMessage)
+}
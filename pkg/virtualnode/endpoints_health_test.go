@@ -0,0 +1,167 @@
+package virtualnode
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeHealthChecker reports the health of pods by name, pods without health are reported with an error.
+type fakeHealthChecker struct {
+	reasonByPod map[string]string
+}
+
+func (c *fakeHealthChecker) Check(_ context.Context, pod *corev1.Pod) (bool, string, error) {
+	reason, ok := c.reasonByPod[pod.Name]
+	if !ok {
+		return false, "", errors.New("failed to decode envoy clusters")
+	}
+	return len(reason) == 0, reason, nil
+}
+
+func Test_defaultEndpointsHealthReporter_Report(t *testing.T) {
+	healthCheck := &appmesh.HealthCheckPolicy{
+		HealthyThreshold:   2,
+		IntervalMillis:     5000,
+		Protocol:           appmesh.PortProtocolHTTP,
+		TimeoutMillis:      2000,
+		UnhealthyThreshold: 2,
+	}
+	vnWithListener := func(healthCheck *appmesh.HealthCheckPolicy, conditions ...metav1.Condition) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: "payments", Generation: 2},
+			Spec: appmesh.VirtualNodeSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}},
+				Listeners: []appmesh.Listener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheck: healthCheck},
+				},
+			},
+			Status: appmesh.VirtualNodeStatus{Conditions: conditions},
+		}
+	}
+	podNamed := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app-ns", Name: name, Labels: map[string]string{"app": "payments"}},
+		}
+	}
+	healthyCondition := metav1.Condition{
+		Type:               appmesh.ConditionEndpointsHealthy,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 2,
+		Reason:             appmesh.ReasonHealthChecksPassing,
+		Message:            "EndpointsHealthy 2/2",
+	}
+	tests := []struct {
+		name          string
+		vn            *appmesh.VirtualNode
+		pods          []*corev1.Pod
+		reasonByPod   map[string]string
+		wantUpdated   bool
+		wantCondition *metav1.Condition
+	}{
+		{
+			name:        "every endpoint healthy",
+			vn:          vnWithListener(healthCheck),
+			pods:        []*corev1.Pod{podNamed("pod-0"), podNamed("pod-1")},
+			reasonByPod: map[string]string{"pod-0": "", "pod-1": ""},
+			wantUpdated: true,
+			wantCondition: &metav1.Condition{
+				Type:               appmesh.ConditionEndpointsHealthy,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 2,
+				Reason:             appmesh.ReasonHealthChecksPassing,
+				Message:            "EndpointsHealthy 2/2",
+			},
+		},
+		{
+			name:        "endpoints unhealthy",
+			vn:          vnWithListener(healthCheck, healthyCondition),
+			pods:        []*corev1.Pod{podNamed("pod-0"), podNamed("pod-1"), podNamed("pod-2")},
+			reasonByPod: map[string]string{"pod-0": "health checks of cds_ingress_my-mesh_payments_http_8080 are failing", "pod-1": ""},
+			wantUpdated: true,
+			wantCondition: &metav1.Condition{
+				Type:               appmesh.ConditionEndpointsHealthy,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 2,
+				Reason:             appmesh.ReasonHealthChecksFailing,
+				Message:            "EndpointsHealthy 1/3, unhealthy: pod-0 (health checks of cds_ingress_my-mesh_payments_http_8080 are failing), pod-2 (failed to decode envoy clusters)",
+			},
+		},
+		{
+			name:          "health unchanged",
+			vn:            vnWithListener(healthCheck, healthyCondition),
+			pods:          []*corev1.Pod{podNamed("pod-0"), podNamed("pod-1")},
+			reasonByPod:   map[string]string{"pod-0": "", "pod-1": ""},
+			wantUpdated:   false,
+			wantCondition: &healthyCondition,
+		},
+		{
+			name:        "no endpoints",
+			vn:          vnWithListener(healthCheck),
+			wantUpdated: true,
+			wantCondition: &metav1.Condition{
+				Type:               appmesh.ConditionEndpointsHealthy,
+				Status:             metav1.ConditionUnknown,
+				ObservedGeneration: 2,
+				Reason:             appmesh.ReasonNoEndpoints,
+				Message:            "EndpointsHealthy 0/0",
+			},
+		},
+		{
+			name:          "health checks removed from listeners",
+			vn:            vnWithListener(nil, healthyCondition),
+			pods:          []*corev1.Pod{podNamed("pod-0")},
+			wantUpdated:   true,
+			wantCondition: nil,
+		},
+		{
+			name:          "listeners without health checks",
+			vn:            vnWithListener(nil),
+			pods:          []*corev1.Pod{podNamed("pod-0")},
+			wantUpdated:   false,
+			wantCondition: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, pod := range tt.pods {
+				assert.NoError(t, k8sClient.Create(ctx, pod.DeepCopy()))
+			}
+
+			r := NewDefaultEndpointsHealthReporter(k8sClient, &fakeHealthChecker{reasonByPod: tt.reasonByPod})
+			vn := tt.vn.DeepCopy()
+			updated, err := r.Report(ctx, vn)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUpdated, updated)
+			condition := meta.FindStatusCondition(vn.Status.Conditions, appmesh.ConditionEndpointsHealthy)
+			if tt.wantCondition == nil {
+				assert.Nil(t, condition)
+				return
+			}
+			if assert.NotNil(t, condition) {
+				condition.LastTransitionTime = metav1.Time{}
+				assert.Equal(t, *tt.wantCondition, *condition)
+			}
+		})
+	}
+}
+
+func Test_describeUnhealthyEndpoints(t *testing.T) {
+	endpoints := []string{"pod-0 (a)", "pod-1 (a)", "pod-2 (a)", "pod-3 (a)", "pod-4 (a)", "pod-5 (a)", "pod-6 (a)"}
+	assert.Equal(t, "pod-0 (a), pod-1 (a)", describeUnhealthyEndpoints(endpoints[:2]))
+	assert.Equal(t, "pod-0 (a), pod-1 (a), pod-2 (a), pod-3 (a), pod-4 (a) and 2 more", describeUnhealthyEndpoints(endpoints))
+}
@@ -37,7 +37,9 @@ func (h *enqueueRequestsForBackendGroupEvents) Create(e event.CreateEvent, queue
 func (h *enqueueRequestsForBackendGroupEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
 	bgOld := e.ObjectOld.(*appmesh.BackendGroup)
 	bgNew := e.ObjectNew.(*appmesh.BackendGroup)
-	if !reflect.DeepEqual(bgOld.Spec.VirtualServices, bgNew.Spec.VirtualServices) {
+	if !reflect.DeepEqual(bgOld.Spec.VirtualServices, bgNew.Spec.VirtualServices) ||
+		!reflect.DeepEqual(bgOld.Spec.VirtualServiceSelector, bgNew.Spec.VirtualServiceSelector) ||
+		!reflect.DeepEqual(bgOld.Spec.NamespaceSelector, bgNew.Spec.NamespaceSelector) {
 		h.enqueueVirtualNodesForMesh(context.Background(), queue, bgNew.Spec.MeshRef, bgNew)
 	}
 }
@@ -0,0 +1,305 @@
+package virtualnode
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+func Test_enqueueRequestsForMeshPolicyEvents_Update(t *testing.T) {
+	myMeshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	vn1 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-1",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vn2 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-2",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	mp := &appmesh.MeshPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "defaults",
+		},
+		Spec: appmesh.MeshPolicySpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	mpWithRouteDefaults := mp.DeepCopy()
+	mpWithRouteDefaults.Spec.RouteDefaults = &appmesh.MeshPolicyRouteDefaults{
+		HTTPRetryPolicy: &appmesh.HTTPRetryPolicy{
+			HTTPRetryEvents: []appmesh.HTTPRetryPolicyEvent{"server-error"},
+			MaxRetries:      2,
+			PerRetryTimeout: appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 2000},
+		},
+	}
+	mpWithListenerDefaults := mp.DeepCopy()
+	mpWithListenerDefaults.Spec.ListenerDefaults = &appmesh.MeshPolicyListenerDefaults{
+		ConnectionPool: &appmesh.VirtualNodeConnectionPool{
+			HTTP: &appmesh.HTTPConnectionPool{
+				MaxConnections:     100,
+				MaxPendingRequests: aws.Int64(1000),
+			},
+		},
+	}
+
+	type env struct {
+		virtualNodes []*appmesh.VirtualNode
+	}
+	type args struct {
+		e event.UpdateEvent
+	}
+	tests := []struct {
+		name         string
+		env          env
+		args         args
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "listenerDefaults un-changed",
+			env: env{
+				virtualNodes: []*appmesh.VirtualNode{vn1, vn2},
+			},
+			args: args{
+				e: event.UpdateEvent{
+					ObjectOld: mp,
+					ObjectNew: mpWithRouteDefaults,
+				},
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "listenerDefaults changed",
+			env: env{
+				virtualNodes: []*appmesh.VirtualNode{vn1, vn2},
+			},
+			args: args{
+				e: event.UpdateEvent{
+					ObjectOld: mp,
+					ObjectNew: mpWithListenerDefaults,
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vn1),
+				},
+				{
+					NamespacedName: k8s.NamespacedName(vn2),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForMeshPolicyEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+
+			for _, vn := range tt.env.virtualNodes {
+				err := k8sClient.Create(ctx, vn.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.Update(tt.args.e, queue)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
+
+func Test_enqueueRequestsForMeshPolicyEvents_enqueueVirtualNodesForMeshPolicy(t *testing.T) {
+	myMeshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	anotherMeshRef := &appmesh.MeshReference{
+		Name: "another-mesh",
+		UID:  "1d3b3c3c-7dec-11ea-b156-0e30aabe1ca8",
+	}
+	vn1 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-1",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vn2 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other-ns",
+			Name:      "vn-2",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vn3 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-3",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: anotherMeshRef,
+		},
+	}
+	vn4 := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-4",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: &appmesh.MeshReference{
+				Name: "my-mesh",
+				UID:  "f7e83b85-3f41-4b27-9e7a-5c8a5b0a3e4d",
+			},
+		},
+	}
+
+	type env struct {
+		virtualNodes []*appmesh.VirtualNode
+	}
+	type args struct {
+		mp *appmesh.MeshPolicy
+	}
+	tests := []struct {
+		name         string
+		env          env
+		args         args
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "meshPolicy without meshRef",
+			env: env{
+				virtualNodes: []*appmesh.VirtualNode{vn1, vn2, vn3, vn4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+				},
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "meshPolicy of my-mesh",
+			env: env{
+				virtualNodes: []*appmesh.VirtualNode{vn1, vn2, vn3, vn4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+					Spec: appmesh.MeshPolicySpec{
+						MeshRef: myMeshRef,
+					},
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vn1),
+				},
+				{
+					NamespacedName: k8s.NamespacedName(vn2),
+				},
+			},
+		},
+		{
+			name: "meshPolicy of another-mesh",
+			env: env{
+				virtualNodes: []*appmesh.VirtualNode{vn1, vn2, vn3, vn4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+					Spec: appmesh.MeshPolicySpec{
+						MeshRef: anotherMeshRef,
+					},
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vn3),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForMeshPolicyEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+
+			for _, vn := range tt.env.virtualNodes {
+				err := k8sClient.Create(ctx, vn.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.enqueueVirtualNodesForMeshPolicy(ctx, queue, tt.args.mp)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package virtualnode
+
+import (
+	"context"
+	"reflect"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func NewEnqueueRequestsForPodEvents(k8sClient client.Client, log logr.Logger) *enqueueRequestsForPodEvents {
+	return &enqueueRequestsForPodEvents{
+		k8sClient: k8sClient,
+		log:       log,
+	}
+}
+
+var _ handler.EventHandler = (*enqueueRequestsForPodEvents)(nil)
+
+type enqueueRequestsForPodEvents struct {
+	k8sClient client.Client
+	log       logr.Logger
+}
+
+// Create is called in response to a create event
+func (h *enqueueRequestsForPodEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueVirtualNodesForPod(context.Background(), queue, e.Object.(*corev1.Pod))
+}
+
+// Update is called in response to an update event
+func (h *enqueueRequestsForPodEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	// health checks are only derived from readinessProbes and ports of pods selected by labels, as well as dropped once being deleted.
+	podOld := e.ObjectOld.(*corev1.Pod)
+	podNew := e.ObjectNew.(*corev1.Pod)
+	if !reflect.DeepEqual(podOld.Labels, podNew.Labels) || podOld.DeletionTimestamp.IsZero() != podNew.DeletionTimestamp.IsZero() ||
+		!reflect.DeepEqual(probedContainers(podOld), probedContainers(podNew)) {
+		h.enqueueVirtualNodesForPod(context.Background(), queue, podOld)
+		h.enqueueVirtualNodesForPod(context.Background(), queue, podNew)
+	}
+}
+
+// Delete is called in response to a delete event
+func (h *enqueueRequestsForPodEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueVirtualNodesForPod(context.Background(), queue, e.Object.(*corev1.Pod))
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request
+func (h *enqueueRequestsForPodEvents) Generic(e event.GenericEvent, queue workqueue.RateLimitingInterface) {
+	// no-op
+}
+
+// enqueueVirtualNodesForPod enqueues virtualNodes selecting pod that derive health checks of listeners from probes.
+func (h *enqueueRequestsForPodEvents) enqueueVirtualNodesForPod(ctx context.Context, queue workqueue.RateLimitingInterface, pod *corev1.Pod) {
+	vnList := &appmesh.VirtualNodeList{}
+	if err := h.k8sClient.List(ctx, vnList, client.InNamespace(pod.Namespace)); err != nil {
+		h.log.Error(err, "failed to enqueue virtualNodes for pod events", "pod", k8s.NamespacedName(pod))
+		return
+	}
+	for _, vn := range vnList.Items {
+		if !HasPodSelector(&vn) || !hasListenerWithHealthCheckFromProbe(&vn) {
+			continue
+		}
+		matches, err := MatchesPodLabels(&vn, pod.Labels)
+		if err != nil {
+			continue
+		}
+		if matches {
+			queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vn)})
+		}
+	}
+}
+
+type probedContainer struct {
+	ports          []corev1.ContainerPort
+	readinessProbe *corev1.Probe
+}
+
+// probedContainers returns ports and readinessProbe of containers of pod, which health checks are derived from.
+func probedContainers(pod *corev1.Pod) []probedContainer {
+	containers := make([]probedContainer, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, probedContainer{ports: container.Ports, readinessProbe: container.ReadinessProbe})
+	}
+	return containers
+}
@@ -0,0 +1,139 @@
+package virtualnode
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+func Test_enqueueRequestsForPodEvents_Update(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "pod-1",
+			Labels:    map[string]string{"app": "payments"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt(8080)}},
+					},
+				},
+			},
+		},
+	}
+	podRelabeled := pod.DeepCopy()
+	podRelabeled.Labels = map[string]string{"app": "orders"}
+	podReprobed := pod.DeepCopy()
+	podReprobed.Spec.Containers[0].ReadinessProbe.HTTPGet.Path = "/healthz"
+	podStatusChanged := pod.DeepCopy()
+	podStatusChanged.Status.Phase = corev1.PodRunning
+
+	vnPaymentsFromProbe := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "vn-payments-from-probe",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}},
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheckFromProbe: aws.Bool(true)},
+			},
+		},
+	}
+	vnOrdersFromProbe := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "vn-orders-from-probe",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}},
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheckFromProbe: aws.Bool(true)},
+			},
+		},
+	}
+	vnPayments := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "vn-payments",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}},
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}},
+			},
+		},
+	}
+	vnPaymentsFromProbeInNS2 := vnPaymentsFromProbe.DeepCopy()
+	vnPaymentsFromProbeInNS2.Namespace = "ns-2"
+
+	tests := []struct {
+		name         string
+		args         event.UpdateEvent
+		wantRequests []reconcile.Request
+	}{
+		{
+			name:         "pod relabeled",
+			args:         event.UpdateEvent{ObjectOld: pod, ObjectNew: podRelabeled},
+			wantRequests: []reconcile.Request{{NamespacedName: k8s.NamespacedName(vnPaymentsFromProbe)}, {NamespacedName: k8s.NamespacedName(vnOrdersFromProbe)}},
+		},
+		{
+			name:         "pod readinessProbe changed",
+			args:         event.UpdateEvent{ObjectOld: pod, ObjectNew: podReprobed},
+			wantRequests: []reconcile.Request{{NamespacedName: k8s.NamespacedName(vnPaymentsFromProbe)}},
+		},
+		{
+			name:         "pod status changed",
+			args:         event.UpdateEvent{ObjectOld: pod, ObjectNew: podStatusChanged},
+			wantRequests: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForPodEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+			for _, vn := range []*appmesh.VirtualNode{vnPaymentsFromProbe, vnOrdersFromProbe, vnPayments, vnPaymentsFromProbeInNS2} {
+				err := k8sClient.Create(ctx, vn.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.Update(tt.args, queue)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -28,17 +31,23 @@ type enqueueRequestsForVirtualServiceEvents struct {
 
 // Create is called in response to a create event
 func (h *enqueueRequestsForVirtualServiceEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueVirtualNodesForMesh(context.Background(), queue, e.Object.(*appmesh.VirtualService).Spec.MeshRef, e.Object.(*appmesh.VirtualService))
+	h.enqueueVirtualNodesForVirtualService(context.Background(), queue, e.Object.(*appmesh.VirtualService))
 }
 
 // Update is called in response to an update event
 func (h *enqueueRequestsForVirtualServiceEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualServices are only discovered by namespace and labels, as well as dropped once being deleted.
+	vsOld := e.ObjectOld.(*appmesh.VirtualService)
+	vsNew := e.ObjectNew.(*appmesh.VirtualService)
+	if !reflect.DeepEqual(vsOld.Labels, vsNew.Labels) || vsOld.DeletionTimestamp.IsZero() != vsNew.DeletionTimestamp.IsZero() {
+		h.enqueueVirtualNodesForVirtualService(context.Background(), queue, vsOld)
+		h.enqueueVirtualNodesForVirtualService(context.Background(), queue, vsNew)
+	}
 }
 
 // Delete is called in response to a delete event
 func (h *enqueueRequestsForVirtualServiceEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	h.enqueueVirtualNodesForVirtualService(context.Background(), queue, e.Object.(*appmesh.VirtualService))
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
@@ -47,28 +56,51 @@ func (h *enqueueRequestsForVirtualServiceEvents) Generic(e event.GenericEvent, q
 	// no-op
 }
 
-func (h *enqueueRequestsForVirtualServiceEvents) enqueueVirtualNodesForMesh(ctx context.Context, queue workqueue.RateLimitingInterface, meshRef *appmesh.MeshReference, vs *appmesh.VirtualService) {
+// enqueueVirtualNodesForVirtualService enqueues virtualNodes whose backendGroups select vs, either by wildcard or virtualServiceSelector.
+func (h *enqueueRequestsForVirtualServiceEvents) enqueueVirtualNodesForVirtualService(ctx context.Context, queue workqueue.RateLimitingInterface, vs *appmesh.VirtualService) {
+	if vs.Spec.MeshRef == nil {
+		return
+	}
 	vnList := &appmesh.VirtualNodeList{}
 	if err := h.k8sClient.List(ctx, vnList); err != nil {
-		h.log.Error(err, "failed to enqueue virtualNodes for virtual service events", "mesh", meshRef.Name)
+		h.log.Error(err, "failed to enqueue virtualNodes for virtual service events", "mesh", vs.Spec.MeshRef.Name)
 		return
 	}
+	selectedByBGKey := make(map[types.NamespacedName]bool)
 	for _, vn := range vnList.Items {
-		if vn.Spec.MeshRef == nil || *meshRef != *vn.Spec.MeshRef {
+		if vn.Spec.MeshRef == nil || *vs.Spec.MeshRef != *vn.Spec.MeshRef {
 			continue
 		}
-		for _, bg := range vn.Spec.BackendGroups {
-			if bg.Name == "*" {
-				if bg.Namespace != nil {
-					if *bg.Namespace == vs.Namespace {
-						queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vn)})
-					}
-				} else {
-					if vs.Namespace == vn.Namespace {
-						queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vn)})
-					}
-				}
+		for _, bgRef := range vn.Spec.BackendGroups {
+			bgKey := references.ObjectKeyForBackendGroupReference(&vn, bgRef)
+			selected, ok := selectedByBGKey[bgKey]
+			if !ok {
+				selected = h.isVirtualServiceSelectedByBackendGroupKey(ctx, bgKey, vs)
+				selectedByBGKey[bgKey] = selected
+			}
+			if selected {
+				queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vn)})
+				break
 			}
 		}
 	}
 }
+
+func (h *enqueueRequestsForVirtualServiceEvents) isVirtualServiceSelectedByBackendGroupKey(ctx context.Context, bgKey types.NamespacedName, vs *appmesh.VirtualService) bool {
+	if bgKey.Name == backendGroupWildcard {
+		return bgKey.Namespace == vs.Namespace
+	}
+	bg := &appmesh.BackendGroup{}
+	if err := h.k8sClient.Get(ctx, bgKey, bg); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			h.log.Error(err, "failed to get backendGroup for virtual service events", "backendGroup", bgKey)
+		}
+		return false
+	}
+	selected, err := isVirtualServiceSelectedByBackendGroup(ctx, h.k8sClient, bg, vs)
+	if err != nil {
+		h.log.Error(err, "failed to match virtualService against backendGroup", "backendGroup", bgKey, "virtualService", k8s.NamespacedName(vs))
+		return false
+	}
+	return selected
+}
@@ -0,0 +1,167 @@
+package virtualnode
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+func Test_enqueueRequestsForVirtualServiceEvents_Update(t *testing.T) {
+	meshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	vs := &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "vs-1",
+			Labels:    map[string]string{"team": "payments"},
+		},
+		Spec: appmesh.VirtualServiceSpec{
+			MeshRef: meshRef,
+		},
+	}
+	vsRelabeled := vs.DeepCopy()
+	vsRelabeled.Labels = map[string]string{"team": "orders"}
+	vsDeleting := vs.DeepCopy()
+	deletionTimestamp := metav1.Now()
+	vsDeleting.DeletionTimestamp = &deletionTimestamp
+	vsNewGeneration := vs.DeepCopy()
+	vsNewGeneration.Generation = 2
+
+	bgPayments := &appmesh.BackendGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-2",
+			Name:      "payments",
+		},
+		Spec: appmesh.BackendGroupSpec{
+			VirtualServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			NamespaceSelector:      &metav1.LabelSelector{},
+			MeshRef:                meshRef,
+		},
+	}
+	bgOrdersInNS2 := &appmesh.BackendGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-2",
+			Name:      "orders",
+		},
+		Spec: appmesh.BackendGroupSpec{
+			VirtualServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "orders"}},
+			MeshRef:                meshRef,
+		},
+	}
+	vnWithPaymentsBG := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-2",
+			Name:      "vn-with-payments-bg",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			BackendGroups: []appmesh.BackendGroupReference{{Name: "payments"}},
+			MeshRef:       meshRef,
+		},
+	}
+	vnWithOrdersBG := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-2",
+			Name:      "vn-with-orders-bg",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			BackendGroups: []appmesh.BackendGroupReference{{Name: "orders"}},
+			MeshRef:       meshRef,
+		},
+	}
+	vnWithWildcardBG := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-2",
+			Name:      "vn-with-wildcard-bg",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			BackendGroups: []appmesh.BackendGroupReference{{Name: "*", Namespace: aws.String("ns-1")}},
+			MeshRef:       meshRef,
+		},
+	}
+	vnWithoutBG := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "vn-without-bg",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: meshRef,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		args         event.UpdateEvent
+		wantRequests []reconcile.Request
+	}{
+		{
+			name:         "virtualService relabeled",
+			args:         event.UpdateEvent{ObjectOld: vs, ObjectNew: vsRelabeled},
+			wantRequests: []reconcile.Request{{NamespacedName: k8s.NamespacedName(vnWithPaymentsBG)}, {NamespacedName: k8s.NamespacedName(vnWithWildcardBG)}},
+		},
+		{
+			name:         "virtualService being deleted",
+			args:         event.UpdateEvent{ObjectOld: vs, ObjectNew: vsDeleting},
+			wantRequests: []reconcile.Request{{NamespacedName: k8s.NamespacedName(vnWithPaymentsBG)}, {NamespacedName: k8s.NamespacedName(vnWithWildcardBG)}},
+		},
+		{
+			name:         "virtualService spec changed",
+			args:         event.UpdateEvent{ObjectOld: vs, ObjectNew: vsNewGeneration},
+			wantRequests: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForVirtualServiceEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+
+			for _, ns := range []string{"ns-1", "ns-2"} {
+				err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+				assert.NoError(t, err)
+			}
+			for _, bg := range []*appmesh.BackendGroup{bgPayments, bgOrdersInNS2} {
+				err := k8sClient.Create(ctx, bg.DeepCopy())
+				assert.NoError(t, err)
+			}
+			for _, vn := range []*appmesh.VirtualNode{vnWithPaymentsBG, vnWithOrdersBG, vnWithWildcardBG, vnWithoutBG} {
+				err := k8sClient.Create(ctx, vn.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.Update(tt.args, queue)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package virtualnode
+
+import (
+	"context"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// limits of listener health checks accepted by App Mesh, probe settings beyond them are clamped.
+const (
+	minHealthCheckThreshold      = 2
+	maxHealthCheckThreshold      = 10
+	minHealthCheckIntervalMillis = 5000
+	maxHealthCheckIntervalMillis = 300000
+	minHealthCheckTimeoutMillis  = 2000
+	maxHealthCheckTimeoutMillis  = 60000
+)
+
+// defaults of probe settings, as set by the API server.
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+)
+
+// resolveHealthChecksFromProbes returns vn with health checks of listeners with healthCheckFromProbe derived from the readinessProbe of pods selected by vn.
+// such listeners are left without health check if no selected pod has a readinessProbe the health check can be derived from.
+func resolveHealthChecksFromProbes(ctx context.Context, k8sClient client.Client, vn *appmesh.VirtualNode) (*appmesh.VirtualNode, error) {
+	if !hasListenerWithHealthCheckFromProbe(vn) {
+		return vn, nil
+	}
+	pods, err := listSelectedPods(ctx, k8sClient, vn)
+	if err != nil {
+		return nil, err
+	}
+	resolvedVN := vn.DeepCopy()
+	for i := range resolvedVN.Spec.Listeners {
+		listener := &resolvedVN.Spec.Listeners[i]
+		if !aws.BoolValue(listener.HealthCheckFromProbe) {
+			continue
+		}
+		for _, pod := range pods {
+			if healthCheck := buildHealthCheckFromProbe(pod, listener.PortMapping.Port); healthCheck != nil {
+				listener.HealthCheck = healthCheck
+				break
+			}
+		}
+	}
+	return resolvedVN, nil
+}
+
+// hasListenerWithHealthCheckFromProbe checks whether any listener of vn derives its health check from probes.
+func hasListenerWithHealthCheckFromProbe(vn *appmesh.VirtualNode) bool {
+	for _, listener := range vn.Spec.Listeners {
+		if aws.BoolValue(listener.HealthCheckFromProbe) {
+			return true
+		}
+	}
+	return false
+}
+
+// listSelectedPods lists pods selected by podSelector or podSelectorTerms of vn, ordered by name.
+// pods being deleted are excluded.
+func listSelectedPods(ctx context.Context, k8sClient client.Client, vn *appmesh.VirtualNode) ([]corev1.Pod, error) {
+	podSelectors, err := PodSelectors(vn)
+	if err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	listedPodNames := sets.NewString()
+	for _, podSelector := range podSelectors {
+		podList := &corev1.PodList{}
+		if err := k8sClient.List(ctx, podList, client.InNamespace(vn.Namespace), client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+			return nil, errors.Wrap(err, "failed to list pods")
+		}
+		for _, pod := range podList.Items {
+			if !pod.DeletionTimestamp.IsZero() || listedPodNames.Has(pod.Name) {
+				continue
+			}
+			listedPodNames.Insert(pod.Name)
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// buildHealthCheckFromProbe builds the health check of the listener on port from the readinessProbe of the container of pod serving it.
+// a container serves the port if it declares it as a container port, or its readinessProbe probes it.
+// it returns nil if there's no such container, or its readinessProbe is neither a httpGet, tcpSocket nor grpc probe.
+func buildHealthCheckFromProbe(pod corev1.Pod, port appmesh.PortNumber) *appmesh.HealthCheckPolicy {
+	for _, container := range pod.Spec.Containers {
+		probe := container.ReadinessProbe
+		if probe == nil {
+			continue
+		}
+		protocol, probePort, path, ok := resolveProbeTarget(container, probe)
+		if !ok {
+			continue
+		}
+		if probePort != port && !declaresContainerPort(container, port) {
+			continue
+		}
+		return &appmesh.HealthCheckPolicy{
+			HealthyThreshold:   clamp(int64(valueOrDefault(probe.SuccessThreshold, defaultProbeSuccessThreshold)), minHealthCheckThreshold, maxHealthCheckThreshold),
+			IntervalMillis:     clamp(int64(valueOrDefault(probe.PeriodSeconds, defaultProbePeriodSeconds))*1000, minHealthCheckIntervalMillis, maxHealthCheckIntervalMillis),
+			Path:               path,
+			Port:               &probePort,
+			Protocol:           protocol,
+			TimeoutMillis:      clamp(int64(valueOrDefault(probe.TimeoutSeconds, defaultProbeTimeoutSeconds))*1000, minHealthCheckTimeoutMillis, maxHealthCheckTimeoutMillis),
+			UnhealthyThreshold: clamp(int64(valueOrDefault(probe.FailureThreshold, defaultProbeFailureThreshold)), minHealthCheckThreshold, maxHealthCheckThreshold),
+		}
+	}
+	return nil
+}
+
+// resolveProbeTarget resolves the health check protocol, port and path probed by probe of container.
+func resolveProbeTarget(container corev1.Container, probe *corev1.Probe) (appmesh.PortProtocol, appmesh.PortNumber, *string, bool) {
+	switch {
+	case probe.HTTPGet != nil:
+		// App Mesh health checks don't support TLS.
+		if probe.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			return "", 0, nil, false
+		}
+		port, ok := resolveContainerPort(container, probe.HTTPGet.Port)
+		path := probe.HTTPGet.Path
+		if len(path) == 0 {
+			path = "/"
+		}
+		return appmesh.PortProtocolHTTP, port, aws.String(path), ok
+	case probe.TCPSocket != nil:
+		port, ok := resolveContainerPort(container, probe.TCPSocket.Port)
+		return appmesh.PortProtocolTCP, port, nil, ok
+	case probe.GRPC != nil:
+		return appmesh.PortProtocolGRPC, appmesh.PortNumber(probe.GRPC.Port), nil, true
+	default:
+		return "", 0, nil, false
+	}
+}
+
+// resolveContainerPort resolves port of a probe, which is either a number or the name of a container port.
+func resolveContainerPort(container corev1.Container, port intstr.IntOrString) (appmesh.PortNumber, bool) {
+	if port.Type == intstr.Int {
+		return appmesh.PortNumber(port.IntVal), true
+	}
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return appmesh.PortNumber(containerPort.ContainerPort), true
+		}
+	}
+	return 0, false
+}
+
+func declaresContainerPort(container corev1.Container, port appmesh.PortNumber) bool {
+	for _, containerPort := range container.Ports {
+		if appmesh.PortNumber(containerPort.ContainerPort) == port {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOrDefault(value int32, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func clamp(value int64, min int64, max int64) int64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,274 @@
+package virtualnode
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_buildHealthCheckFromProbe(t *testing.T) {
+	port8080 := appmesh.PortNumber(8080)
+	port9090 := appmesh.PortNumber(9090)
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		port appmesh.PortNumber
+		want *appmesh.HealthCheckPolicy
+	}{
+		{
+			name: "httpGet probe on named port",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "app",
+							Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("http")}},
+								PeriodSeconds:    15,
+								TimeoutSeconds:   3,
+								SuccessThreshold: 2,
+								FailureThreshold: 5,
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: &appmesh.HealthCheckPolicy{
+				HealthyThreshold:   2,
+				IntervalMillis:     15000,
+				Path:               aws.String("/ready"),
+				Port:               &port8080,
+				Protocol:           appmesh.PortProtocolHTTP,
+				TimeoutMillis:      3000,
+				UnhealthyThreshold: 5,
+			},
+		},
+		{
+			name: "httpGet probe with defaults clamped to App Mesh limits",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(8080)}},
+								PeriodSeconds:    1,
+								FailureThreshold: 30,
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: &appmesh.HealthCheckPolicy{
+				HealthyThreshold:   2,
+				IntervalMillis:     5000,
+				Path:               aws.String("/"),
+				Port:               &port8080,
+				Protocol:           appmesh.PortProtocolHTTP,
+				TimeoutMillis:      2000,
+				UnhealthyThreshold: 10,
+			},
+		},
+		{
+			name: "tcpSocket probe on separate port of container serving the listener",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "app",
+							Ports: []corev1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 9090}},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(9090)}},
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: &appmesh.HealthCheckPolicy{
+				HealthyThreshold:   2,
+				IntervalMillis:     10000,
+				Port:               &port9090,
+				Protocol:           appmesh.PortProtocolTCP,
+				TimeoutMillis:      2000,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			name: "grpc probe",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{GRPC: &corev1.GRPCAction{Port: 8080}},
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: &appmesh.HealthCheckPolicy{
+				HealthyThreshold:   2,
+				IntervalMillis:     10000,
+				Port:               &port8080,
+				Protocol:           appmesh.PortProtocolGRPC,
+				TimeoutMillis:      2000,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			name: "exec probe isn't derivable",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "app",
+							Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"cat", "/tmp/ready"}}},
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: nil,
+		},
+		{
+			name: "HTTPS httpGet probe isn't derivable",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(8080), Scheme: corev1.URISchemeHTTPS}},
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: nil,
+		},
+		{
+			name: "no container serving the listener",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "sidecar",
+							Ports: []corev1.ContainerPort{{ContainerPort: 9090}},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(9090)}},
+							},
+						},
+					},
+				},
+			},
+			port: 8080,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildHealthCheckFromProbe(tt.pod, tt.port)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_resolveHealthChecksFromProbes(t *testing.T) {
+	port8080 := appmesh.PortNumber(8080)
+	readyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "app-ns",
+			Name:      "pod-1",
+			Labels:    map[string]string{"app": "payments"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt(8080)}},
+					},
+				},
+			},
+		},
+	}
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "app-ns",
+			Name:      "pod-0",
+			Labels:    map[string]string{"app": "orders"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)}},
+					},
+				},
+			},
+		},
+	}
+	explicitHealthCheck := &appmesh.HealthCheckPolicy{
+		HealthyThreshold:   3,
+		IntervalMillis:     6000,
+		Protocol:           appmesh.PortProtocolTCP,
+		TimeoutMillis:      3000,
+		UnhealthyThreshold: 3,
+	}
+	vn := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "app-ns",
+			Name:      "payments",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}},
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheckFromProbe: aws.Bool(true)},
+				{PortMapping: appmesh.PortMapping{Port: 9090, Protocol: "tcp"}, HealthCheck: explicitHealthCheck},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	for _, pod := range []*corev1.Pod{readyPod, otherPod} {
+		assert.NoError(t, k8sClient.Create(ctx, pod.DeepCopy()))
+	}
+
+	got, err := resolveHealthChecksFromProbes(ctx, k8sClient, vn)
+	assert.NoError(t, err)
+	assert.Equal(t, &appmesh.HealthCheckPolicy{
+		HealthyThreshold:   2,
+		IntervalMillis:     10000,
+		Path:               aws.String("/ready"),
+		Port:               &port8080,
+		Protocol:           appmesh.PortProtocolHTTP,
+		TimeoutMillis:      2000,
+		UnhealthyThreshold: 3,
+	}, got.Spec.Listeners[0].HealthCheck)
+	assert.Equal(t, explicitHealthCheck, got.Spec.Listeners[1].HealthCheck)
+	assert.Nil(t, vn.Spec.Listeners[0].HealthCheck)
+}
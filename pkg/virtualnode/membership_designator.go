@@ -7,8 +7,6 @@ import (
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
@@ -44,11 +42,11 @@ func (d *membershipDesignator) Designate(ctx context.Context, pod *corev1.Pod) (
 
 	var vnCandidates []*appmesh.VirtualNode
 	for _, vnObj := range vnList.Items {
-		selector, err := metav1.LabelSelectorAsSelector(vnObj.Spec.PodSelector)
+		matches, err := MatchesPodLabels(&vnObj, pod.Labels)
 		if err != nil {
 			return nil, err
 		}
-		if selector.Matches(labels.Set(pod.Labels)) {
+		if matches {
 			vnCandidates = append(vnCandidates, vnObj.DeepCopy())
 		}
 	}
@@ -61,7 +59,7 @@ func (d *membershipDesignator) Designate(ctx context.Context, pod *corev1.Pod) (
 			vnCandidatesNames = append(vnCandidatesNames, k8s.NamespacedName(vn).String())
 		}
 		return nil, errors.Errorf("found multiple matching VirtualNodes for pod %s: %s",
-			k8s.NamespacedName(pod).String(), strings.Join(vnCandidatesNames, ","))
+			k8s.PodIdentifier(req.Namespace, pod), strings.Join(vnCandidatesNames, ","))
 	}
 	return vnCandidates[0], nil
 }
@@ -0,0 +1,118 @@
+package virtualnode
+
+import (
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// HasPodSelector checks whether vn selects pods by either podSelector or podSelectorTerms.
+func HasPodSelector(vn *appmesh.VirtualNode) bool {
+	return vn.Spec.PodSelector != nil || len(vn.Spec.PodSelectorTerms) != 0
+}
+
+// PodSelectorTerms returns the label selectors of vn, i.e. its podSelector followed by its podSelectorTerms.
+func PodSelectorTerms(vn *appmesh.VirtualNode) []*metav1.LabelSelector {
+	var terms []*metav1.LabelSelector
+	if vn.Spec.PodSelector != nil {
+		terms = append(terms, vn.Spec.PodSelector)
+	}
+	for i := range vn.Spec.PodSelectorTerms {
+		terms = append(terms, &vn.Spec.PodSelectorTerms[i])
+	}
+	return terms
+}
+
+// PodSelectors returns selectors for the label selectors of vn, pods matching any of them are members of vn.
+func PodSelectors(vn *appmesh.VirtualNode) ([]labels.Selector, error) {
+	terms := PodSelectorTerms(vn)
+	selectors := make([]labels.Selector, 0, len(terms))
+	for _, term := range terms {
+		selector, err := metav1.LabelSelectorAsSelector(term)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid podSelector")
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// MatchesPodLabels checks whether pods with podLabels are members of vn.
+func MatchesPodLabels(vn *appmesh.VirtualNode, podLabels map[string]string) (bool, error) {
+	selectors, err := PodSelectors(vn)
+	if err != nil {
+		return false, err
+	}
+	for _, selector := range selectors {
+		if selector.Matches(labels.Set(podLabels)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PodSelectorTermsOverlap checks whether some pod labels could match both label selectors.
+// it's conservative: label selectors overlap unless requirements on some label contradict each other.
+func PodSelectorTermsOverlap(term *metav1.LabelSelector, otherTerm *metav1.LabelSelector) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(term)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid podSelector")
+	}
+	otherSelector, err := metav1.LabelSelectorAsSelector(otherTerm)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid podSelector")
+	}
+	requirements, selectable := selector.Requirements()
+	otherRequirements, otherSelectable := otherSelector.Requirements()
+	// nil label selectors select nothing.
+	if !selectable || !otherSelectable {
+		return false, nil
+	}
+	requirementsByKey := make(map[string][]labels.Requirement)
+	for _, requirement := range append(requirements, otherRequirements...) {
+		requirementsByKey[requirement.Key()] = append(requirementsByKey[requirement.Key()], requirement)
+	}
+	for _, keyRequirements := range requirementsByKey {
+		if !labelRequirementsSatisfiable(keyRequirements) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// labelRequirementsSatisfiable checks whether some value, or absence, of a label satisfies all requirements on it.
+func labelRequirementsSatisfiable(requirements []labels.Requirement) bool {
+	mustExist, mustNotExist := false, false
+	var allowedValues sets.String
+	excludedValues := sets.NewString()
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			mustExist = true
+			if allowedValues == nil {
+				allowedValues = requirement.Values()
+			} else {
+				allowedValues = allowedValues.Intersection(requirement.Values())
+			}
+		case selection.NotIn, selection.NotEquals:
+			excludedValues = excludedValues.Union(requirement.Values())
+		case selection.Exists:
+			mustExist = true
+		case selection.DoesNotExist:
+			mustNotExist = true
+		default:
+			// other operators, e.g. Gt and Lt, aren't allowed in label selectors of pods, consider them satisfiable.
+			return true
+		}
+	}
+	if mustExist && mustNotExist {
+		return false
+	}
+	if allowedValues != nil && allowedValues.Difference(excludedValues).Len() == 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package virtualnode
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_MatchesPodLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      appmesh.VirtualNodeSpec
+		podLabels map[string]string
+		want      bool
+	}{
+		{
+			name:      "without pod selector",
+			spec:      appmesh.VirtualNodeSpec{},
+			podLabels: map[string]string{"app": "my-app"},
+			want:      false,
+		},
+		{
+			name: "matches podSelector",
+			spec: appmesh.VirtualNodeSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+			},
+			podLabels: map[string]string{"app": "my-app"},
+			want:      true,
+		},
+		{
+			name: "matches one of podSelectorTerms",
+			spec: appmesh.VirtualNodeSpec{
+				PodSelectorTerms: []metav1.LabelSelector{
+					{MatchLabels: map[string]string{"app": "my-app-blue"}},
+					{MatchLabels: map[string]string{"app": "my-app-green"}},
+				},
+			},
+			podLabels: map[string]string{"app": "my-app-green"},
+			want:      true,
+		},
+		{
+			name: "matches neither podSelector nor podSelectorTerms",
+			spec: appmesh.VirtualNodeSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app-blue"}},
+				PodSelectorTerms: []metav1.LabelSelector{
+					{MatchLabels: map[string]string{"app": "my-app-green"}},
+				},
+			},
+			podLabels: map[string]string{"app": "other-app"},
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchesPodLabels(&appmesh.VirtualNode{Spec: tt.spec}, tt.podLabels)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_PodSelectorTermsOverlap(t *testing.T) {
+	tests := []struct {
+		name      string
+		term      *metav1.LabelSelector
+		otherTerm *metav1.LabelSelector
+		want      bool
+	}{
+		{
+			name:      "nil selector selects nothing",
+			term:      nil,
+			otherTerm: &metav1.LabelSelector{},
+			want:      false,
+		},
+		{
+			name:      "empty selector selects everything",
+			term:      &metav1.LabelSelector{},
+			otherTerm: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+			want:      true,
+		},
+		{
+			name:      "different values of same label",
+			term:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app", "version": "blue"}},
+			otherTerm: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app", "version": "green"}},
+			want:      false,
+		},
+		{
+			name:      "different labels",
+			term:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+			otherTerm: &metav1.LabelSelector{MatchLabels: map[string]string{"version": "blue"}},
+			want:      true,
+		},
+		{
+			name: "In values excluded by NotIn",
+			term: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "version", Operator: metav1.LabelSelectorOpIn, Values: []string{"blue", "green"}},
+			}},
+			otherTerm: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "version", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"blue", "green"}},
+			}},
+			want: false,
+		},
+		{
+			name: "In values partially excluded by NotIn",
+			term: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "version", Operator: metav1.LabelSelectorOpIn, Values: []string{"blue", "green"}},
+			}},
+			otherTerm: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "version", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"blue"}},
+			}},
+			want: true,
+		},
+		{
+			name: "label exists and doesn't exist",
+			term: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+			otherTerm: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+			}},
+			want: false,
+		},
+		{
+			name: "NotIn is satisfied by absent label",
+			term: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "canary", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"}},
+			}},
+			otherTerm: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+			}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PodSelectorTermsOverlap(tt.term, tt.otherTerm)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			got, err = PodSelectorTermsOverlap(tt.otherTerm, tt.term)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,13 @@ package virtualnode
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ReferenceKindVirtualService = "VirtualService"
 )
 
 // ExtractVirtualServiceReferences extracts all virtualServiceReferences for this VirtualNode
@@ -14,3 +21,14 @@ func ExtractVirtualServiceReferences(vn *appmesh.VirtualNode) []appmesh.VirtualS
 	}
 	return vsRefs
 }
+
+// VirtualServiceReferenceIndexFunc indexes virtualNodes by the virtualServices referenced as their backends.
+func VirtualServiceReferenceIndexFunc(obj client.Object) []types.NamespacedName {
+	vn := obj.(*appmesh.VirtualNode)
+	vsRefs := ExtractVirtualServiceReferences(vn)
+	var vsKeys []types.NamespacedName
+	for _, vsRef := range vsRefs {
+		vsKeys = append(vsKeys, references.ObjectKeyForVirtualServiceReference(vn, vsRef))
+	}
+	return vsKeys
+}
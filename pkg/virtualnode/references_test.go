@@ -0,0 +1,85 @@
+package virtualnode
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestVirtualServiceReferenceIndexFunc(t *testing.T) {
+	type args struct {
+		obj client.Object
+	}
+	tests := []struct {
+		name string
+		args args
+		want []types.NamespacedName
+	}{
+		{
+			name: "backends with and without namespace",
+			args: args{
+				obj: &appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+					},
+					Spec: appmesh.VirtualNodeSpec{
+						Backends: []appmesh.Backend{
+							{
+								VirtualService: appmesh.VirtualServiceBackend{
+									VirtualServiceRef: &appmesh.VirtualServiceReference{
+										Namespace: aws.String("other-ns"),
+										Name:      "vs-1",
+									},
+								},
+							},
+							{
+								VirtualService: appmesh.VirtualServiceBackend{
+									VirtualServiceRef: &appmesh.VirtualServiceReference{
+										Name: "vs-2",
+									},
+								},
+							},
+							{
+								VirtualService: appmesh.VirtualServiceBackend{
+									VirtualServiceARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/mesh-name/virtualService/vs-3"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []types.NamespacedName{
+				{
+					Namespace: "other-ns",
+					Name:      "vs-1",
+				},
+				{
+					Namespace: "my-ns",
+					Name:      "vs-2",
+				},
+			},
+		},
+		{
+			name: "no backends",
+			args: args{
+				obj: &appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+					},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VirtualServiceReferenceIndexFunc(tt.args.obj)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
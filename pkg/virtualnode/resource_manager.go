@@ -4,20 +4,24 @@ import (
 	"context"
 	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/meshpolicy"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +34,9 @@ type ResourceManager interface {
 
 	// Cleanup will delete AppMesh VirtualNode created for vn.
 	Cleanup(ctx context.Context, vn *appmesh.VirtualNode) error
+
+	// Diff will compare AppMesh VirtualNode against the spec desired for vn, without changing it.
+	Diff(ctx context.Context, vn *appmesh.VirtualNode) ([]equality.SpecDiff, error)
 }
 
 func NewDefaultResourceManager(
@@ -37,58 +44,95 @@ func NewDefaultResourceManager(
 	appMeshSDK services.AppMesh,
 	referencesResolver references.Resolver,
 	accountID string,
+	tagsManager tagging.Manager,
+	quotaChecker quota.Checker,
+	maintenanceScheduler maintenance.Scheduler,
 	log logr.Logger,
-	enableBackendGroups bool) ResourceManager {
+	enableBackendGroups bool,
+	enableMeshPolicies bool) ResourceManager {
 
 	return &defaultResourceManager{
-		k8sClient:           k8sClient,
-		appMeshSDK:          appMeshSDK,
-		referencesResolver:  referencesResolver,
-		accountID:           accountID,
-		log:                 log,
-		enableBackendGroups: enableBackendGroups,
+		k8sClient:            k8sClient,
+		appMeshSDK:           appMeshSDK,
+		referencesResolver:   referencesResolver,
+		accountID:            accountID,
+		tagsManager:          tagsManager,
+		quotaChecker:         quotaChecker,
+		maintenanceScheduler: maintenanceScheduler,
+		log:                  log,
+		enableBackendGroups:  enableBackendGroups,
+		enableMeshPolicies:   enableMeshPolicies,
 	}
 }
 
 // defaultResourceManager implements ResourceManager
 type defaultResourceManager struct {
-	k8sClient           client.Client
-	appMeshSDK          services.AppMesh
-	referencesResolver  references.Resolver
-	accountID           string
-	log                 logr.Logger
-	enableBackendGroups bool
+	k8sClient            client.Client
+	appMeshSDK           services.AppMesh
+	referencesResolver   references.Resolver
+	accountID            string
+	tagsManager          tagging.Manager
+	quotaChecker         quota.Checker
+	maintenanceScheduler maintenance.Scheduler
+	log                  logr.Logger
+	enableBackendGroups  bool
+	enableMeshPolicies   bool
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, vn *appmesh.VirtualNode) error {
 	ms, err := m.findMeshDependency(ctx, vn)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateMeshDependencies(ctx, ms); err != nil {
-		return err
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 	vsByKey, err := m.findVirtualServiceDependencies(ctx, vn)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualServiceDependencies(ctx, ms, vsByKey); err != nil {
-		return err
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
+	}
+	// desiredVN has health checks derived from probes and listener defaults of meshPolicies applied,
+	// it's only used to build the AppMesh VirtualNode spec.
+	desiredVN, err := resolveHealthChecksFromProbes(ctx, m.k8sClient, vn)
+	if err != nil {
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	desiredVN, err = m.applyMeshPolicies(ctx, ms, desiredVN)
+	if err != nil {
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 	}
 
 	sdkVN, err := m.findSDKVirtualNode(ctx, ms, vn)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	if err := m.quotaChecker.CheckVirtualNode(ctx, ms, vn, sdkVN == nil); err != nil {
+		return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonQuotaExceeded, err)
 	}
 	if sdkVN == nil {
-		sdkVN, err = m.createSDKVirtualNode(ctx, ms, vn, vsByKey)
+		sdkVN, err = m.createSDKVirtualNode(ctx, ms, desiredVN, vsByKey)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
-		sdkVN, err = m.updateSDKVirtualNode(ctx, sdkVN, ms, vn, vsByKey)
+		if err := m.checkSDKVirtualNodeOwnership(ctx, sdkVN, vn); err != nil {
+			return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.deferDisruptiveChanges(ctx, sdkVN, ms, desiredVN, vsByKey, vn); err != nil {
+			if maintenance.IsChangesDeferredError(err) {
+				return err
+			}
+			return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		sdkVN, err = m.updateSDKVirtualNode(ctx, sdkVN, ms, desiredVN, vsByKey)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.reconcileSDKVirtualNodeTags(ctx, sdkVN, vn); err != nil {
+			return m.updateCRDVirtualNodeForFailure(ctx, vn, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	}
 
@@ -111,9 +155,55 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, vn *appmesh.Virtua
 		return nil
 	}
 
+	if err := m.checkSDKVirtualNodeOwnership(ctx, sdkVN, vn); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip virtualNode deletion since it's owned by another controller",
+			"virtualNode", k8s.NamespacedName(vn),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	return m.deleteSDKVirtualNode(ctx, sdkVN, ms, vn)
 }
 
+func (m *defaultResourceManager) Diff(ctx context.Context, vn *appmesh.VirtualNode) ([]equality.SpecDiff, error) {
+	ms, err := m.findMeshDependency(ctx, vn)
+	if err != nil {
+		return nil, err
+	}
+	vsByKey, err := m.findVirtualServiceDependencies(ctx, vn)
+	if err != nil {
+		return nil, err
+	}
+	desiredVN, err := resolveHealthChecksFromProbes(ctx, m.k8sClient, vn)
+	if err != nil {
+		return nil, err
+	}
+	desiredVN, err = m.applyMeshPolicies(ctx, ms, desiredVN)
+	if err != nil {
+		return nil, err
+	}
+	desiredSDKVNSpec, err := BuildSDKVirtualNodeSpec(desiredVN, vsByKey)
+	if err != nil {
+		return nil, err
+	}
+	applyMeshTLSEnforcementMode(ms, desiredSDKVNSpec)
+	sdkVN, err := m.findSDKVirtualNode(ctx, ms, vn)
+	if err != nil {
+		return nil, err
+	}
+	var actualSDKVNSpec *appmeshsdk.VirtualNodeSpec
+	if sdkVN != nil {
+		actualSDKVNSpec = sdkVN.Spec
+	}
+	resource := "virtualNode/" + aws.StringValue(vn.Spec.AWSName)
+	return []equality.SpecDiff{
+		equality.DiffSpec(resource, desiredSDKVNSpec, actualSDKVNSpec, sdkVN != nil, equality.CompareOptionForVirtualNodeSpec()),
+	}, nil
+}
+
 // findMeshDependency find the Mesh dependency for this virtualNode.
 func (m *defaultResourceManager) findMeshDependency(ctx context.Context, vn *appmesh.VirtualNode) (*appmesh.Mesh, error) {
 	if vn.Spec.MeshRef == nil {
@@ -142,7 +232,7 @@ func (m *defaultResourceManager) findVirtualServiceDependencies(ctx context.Cont
 		for _, backendGroupRef := range vn.Spec.BackendGroups {
 			// Wildcard special case
 			bgKey := references.ObjectKeyForBackendGroupReference(vn, backendGroupRef)
-			if bgKey.Name == "*" {
+			if bgKey.Name == backendGroupWildcard {
 				var listOptions client.ListOptions
 				listOptions.Namespace = bgKey.Namespace
 				vsList := &appmesh.VirtualServiceList{}
@@ -150,6 +240,10 @@ func (m *defaultResourceManager) findVirtualServiceDependencies(ctx context.Cont
 					return nil, fmt.Errorf("could not list virtualservices for namespace: %s", bgKey.Namespace)
 				}
 				for _, vs := range vsList.Items {
+					// virtualServices being deleted are dropped from backends, so they don't block the deletion.
+					if !vs.DeletionTimestamp.IsZero() {
+						continue
+					}
 					vsRef := appmesh.VirtualServiceReference{
 						Namespace: aws.String(vs.Namespace),
 						Name:      vs.Name,
@@ -162,6 +256,16 @@ func (m *defaultResourceManager) findVirtualServiceDependencies(ctx context.Cont
 					return nil, errors.Wrapf(err, "failed to resolve backendGroupRef")
 				}
 				vsRefs = append(vsRefs, bg.Spec.VirtualServices...)
+				selectedVSs, err := listSelectedVirtualServices(ctx, m.k8sClient, bg)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to select virtualServices of backendGroup %v", k8s.NamespacedName(bg))
+				}
+				for _, vs := range selectedVSs {
+					vsRefs = append(vsRefs, appmesh.VirtualServiceReference{
+						Namespace: aws.String(vs.Namespace),
+						Name:      vs.Name,
+					})
+				}
 			}
 		}
 	}
@@ -191,6 +295,18 @@ func (m *defaultResourceManager) validateVirtualServiceDependencies(ctx context.
 	return nil
 }
 
+// applyMeshPolicies returns vn with listener defaults of the meshPolicies of ms applied, when meshPolicies are enabled.
+func (m *defaultResourceManager) applyMeshPolicies(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode) (*appmesh.VirtualNode, error) {
+	if !m.enableMeshPolicies {
+		return vn, nil
+	}
+	policies, err := meshpolicy.FindMeshPolicies(ctx, m.k8sClient, ms)
+	if err != nil {
+		return nil, err
+	}
+	return meshpolicy.ApplyListenerDefaults(policies, vn), nil
+}
+
 func (m *defaultResourceManager) findSDKVirtualNode(ctx context.Context, ms *appmesh.Mesh, vn *appmesh.VirtualNode) (*appmeshsdk.VirtualNodeData, error) {
 	resp, err := m.appMeshSDK.DescribeVirtualNodeWithContext(ctx, &appmeshsdk.DescribeVirtualNodeInput{
 		MeshName:        ms.Spec.AWSName,
@@ -212,11 +328,13 @@ func (m *defaultResourceManager) createSDKVirtualNode(ctx context.Context, ms *a
 	if err != nil {
 		return nil, err
 	}
+	applyMeshTLSEnforcementMode(ms, sdkVNSpec)
 	resp, err := m.appMeshSDK.CreateVirtualNodeWithContext(ctx, &appmeshsdk.CreateVirtualNodeInput{
 		MeshName:        ms.Spec.AWSName,
 		MeshOwner:       ms.Spec.MeshOwner,
 		Spec:            sdkVNSpec,
 		VirtualNodeName: vn.Spec.AWSName,
+		Tags:            m.buildSDKVirtualNodeTags(ctx, vn),
 	})
 	if err != nil {
 		return nil, err
@@ -230,6 +348,7 @@ func (m *defaultResourceManager) updateSDKVirtualNode(ctx context.Context, sdkVN
 	if err != nil {
 		return nil, err
 	}
+	applyMeshTLSEnforcementMode(ms, desiredSDKVNSpec)
 
 	opts := equality.CompareOptionForVirtualNodeSpec()
 	if cmp.Equal(desiredSDKVNSpec, actualSDKVNSpec, opts) {
@@ -250,7 +369,7 @@ func (m *defaultResourceManager) updateSDKVirtualNode(ctx context.Context, sdkVN
 		"desiredSDKVNSpec", desiredSDKVNSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateVirtualNodeWithContext(ctx, &appmeshsdk.UpdateVirtualNodeInput{
+	resp, err := m.appMeshSDK.UpdateVirtualNodeWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateVirtualNodeInput{
 		MeshName:        ms.Spec.AWSName,
 		MeshOwner:       ms.Spec.MeshOwner,
 		Spec:            desiredSDKVNSpec,
@@ -262,6 +381,26 @@ func (m *defaultResourceManager) updateSDKVirtualNode(ctx context.Context, sdkVN
 	return resp.VirtualNode, nil
 }
 
+// deferDisruptiveChanges returns a ChangesDeferredError if updating sdkVN to desiredVN flips TLS enforcement
+// while the maintenance window of vn isn't open.
+func (m *defaultResourceManager) deferDisruptiveChanges(ctx context.Context, sdkVN *appmeshsdk.VirtualNodeData, ms *appmesh.Mesh,
+	desiredVN *appmesh.VirtualNode, vsByKey map[types.NamespacedName]*appmesh.VirtualService, vn *appmesh.VirtualNode) error {
+	until, err := m.maintenanceScheduler.DeferUntil(ctx, ms, vn)
+	if err != nil || until.IsZero() {
+		return err
+	}
+	desiredSDKVNSpec, err := BuildSDKVirtualNodeSpec(desiredVN, vsByKey)
+	if err != nil {
+		return err
+	}
+	applyMeshTLSEnforcementMode(ms, desiredSDKVNSpec)
+	changes := tlsEnforcementChanges(desiredSDKVNSpec, sdkVN.Spec)
+	if len(changes) == 0 {
+		return nil
+	}
+	return maintenance.DeferChanges(ctx, m.k8sClient, vn, &vn.Status.Conditions, changes, until)
+}
+
 func (m *defaultResourceManager) deleteSDKVirtualNode(ctx context.Context, sdkVN *appmeshsdk.VirtualNodeData, ms *appmesh.Mesh, vn *appmesh.VirtualNode) error {
 	if !m.isSDKVirtualNodeOwnedByCRDVirtualNode(ctx, sdkVN, vn) {
 		m.log.V(1).Info("skip mesh virtualNode since its not owned",
@@ -289,16 +428,25 @@ func (m *defaultResourceManager) updateCRDVirtualNode(ctx context.Context, vn *a
 		vn.Status.VirtualNodeARN = sdkVN.Metadata.Arn
 		needsUpdate = true
 	}
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkVN.Metadata, resourceMetadata); err != nil {
+		return err
+	}
+	if !cmp.Equal(vn.Status.ResourceMetadata, resourceMetadata) {
+		vn.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
 	if aws.Int64Value(vn.Status.ObservedGeneration) != vn.Generation {
 		vn.Status.ObservedGeneration = aws.Int64(vn.Generation)
 		needsUpdate = true
 	}
 
-	vnActiveConditionStatus := corev1.ConditionFalse
-	if sdkVN.Status != nil && aws.StringValue(sdkVN.Status.Status) == appmeshsdk.VirtualNodeStatusCodeActive {
-		vnActiveConditionStatus = corev1.ConditionTrue
+	if maintenance.SetChangesApplied(vn.Generation, &vn.Status.Conditions) {
+		needsUpdate = true
 	}
-	if updateCondition(vn, appmesh.VirtualNodeActive, vnActiveConditionStatus, nil, nil) {
+
+	vnActive := sdkVN.Status != nil && aws.StringValue(sdkVN.Status.Status) == appmeshsdk.VirtualNodeStatusCodeActive
+	if updateConditionsForReconciled(vn, vnActive) {
 		needsUpdate = true
 	}
 
@@ -308,11 +456,42 @@ func (m *defaultResourceManager) updateCRDVirtualNode(ctx context.Context, vn *a
 	return m.k8sClient.Status().Patch(ctx, vn, client.MergeFrom(oldVN))
 }
 
+// updateCRDVirtualNodeForFailure will record reconcile failure err into virtualNode's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDVirtualNodeForFailure(ctx context.Context, vn *appmesh.VirtualNode, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	oldVN := vn.DeepCopy()
+	if !updateConditionsForFailure(vn, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, vn, client.MergeFrom(oldVN)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "virtualNode", k8s.NamespacedName(vn))
+	}
+	return err
+}
+
 func (m *defaultResourceManager) buildSDKVirtualNodeTags(ctx context.Context, vn *appmesh.VirtualNode) []*appmeshsdk.TagRef {
-	// TODO, support tags
+	return m.tagsManager.BuildTags(vn)
+}
+
+// reconcileSDKVirtualNodeTags corrects tags of AppMesh virtualNode if it's controlled by CRD virtualNode.
+func (m *defaultResourceManager) reconcileSDKVirtualNodeTags(ctx context.Context, sdkVN *appmeshsdk.VirtualNodeData, vn *appmesh.VirtualNode) error {
+	if !m.isSDKVirtualNodeControlledByCRDVirtualNode(ctx, sdkVN, vn) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkVN.Metadata.Arn), vn); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
 	return nil
 }
 
+// checkSDKVirtualNodeOwnership checks AppMesh virtualNode isn't owned by another controller if it's controlled by CRD virtualNode.
+func (m *defaultResourceManager) checkSDKVirtualNodeOwnership(ctx context.Context, sdkVN *appmeshsdk.VirtualNodeData, vn *appmesh.VirtualNode) error {
+	if !m.isSDKVirtualNodeControlledByCRDVirtualNode(ctx, sdkVN, vn) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkVN.Metadata.Arn), vn)
+}
+
 // isSDKVirtualNodeControlledByCRDVirtualNode checks whether an AppMesh virtualNode is controlled by CRD virtualNode
 // if it's controlled, CRD virtualNode update is responsible for updating the AppMesh virtualNode.
 func (m *defaultResourceManager) isSDKVirtualNodeControlledByCRDVirtualNode(ctx context.Context, sdkVN *appmeshsdk.VirtualNodeData, vn *appmesh.VirtualNode) bool {
@@ -342,6 +521,7 @@ func BuildSDKVirtualNodeSpec(vn *appmesh.VirtualNode, vsByKey map[types.Namespac
 	})
 	sdkVNSpec := &appmeshsdk.VirtualNodeSpec{}
 	tempSpec := vn.Spec.DeepCopy()
+	applyServiceDiscoveryFailover(vn, tempSpec)
 	backendMap := make(map[types.NamespacedName]bool)
 
 	for _, backend := range tempSpec.Backends {
@@ -369,3 +549,18 @@ func BuildSDKVirtualNodeSpec(vn *appmesh.VirtualNode, vsByKey map[types.Namespac
 	}
 	return sdkVNSpec, nil
 }
+
+// applyMeshTLSEnforcementMode disables enforcement of client policy TLS when mesh is in TLS audit mode.
+func applyMeshTLSEnforcementMode(ms *appmesh.Mesh, sdkVNSpec *appmeshsdk.VirtualNodeSpec) {
+	if !mesh.IsTLSEnforcementAudit(ms) {
+		return
+	}
+	if sdkVNSpec.BackendDefaults != nil && sdkVNSpec.BackendDefaults.ClientPolicy != nil && sdkVNSpec.BackendDefaults.ClientPolicy.Tls != nil {
+		sdkVNSpec.BackendDefaults.ClientPolicy.Tls.Enforce = aws.Bool(false)
+	}
+	for _, backend := range sdkVNSpec.Backends {
+		if backend.VirtualService != nil && backend.VirtualService.ClientPolicy != nil && backend.VirtualService.ClientPolicy.Tls != nil {
+			backend.VirtualService.ClientPolicy.Tls.Enforce = aws.Bool(false)
+		}
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -46,7 +45,10 @@ func Test_defaultResourceManager_updateCRDVirtualNode(t *testing.T) {
 				},
 				sdkVN: &appmeshsdk.VirtualNodeData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualNodeStatus{
 						Status: aws.String(appmeshsdk.VirtualNodeStatusCodeActive),
@@ -59,10 +61,36 @@ func Test_defaultResourceManager_updateCRDVirtualNode(t *testing.T) {
 				},
 				Status: appmesh.VirtualNodeStatus{
 					VirtualNodeARN: aws.String("arn-1"),
-					Conditions: []appmesh.VirtualNodeCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.VirtualNodeActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -77,17 +105,25 @@ func Test_defaultResourceManager_updateCRDVirtualNode(t *testing.T) {
 					},
 					Status: appmesh.VirtualNodeStatus{
 						VirtualNodeARN: aws.String("arn-1"),
-						Conditions: []appmesh.VirtualNodeCondition{
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualNodeActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
 				},
 				sdkVN: &appmeshsdk.VirtualNodeData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualNodeStatus{
 						Status: aws.String(appmeshsdk.VirtualNodeStatusCodeInactive),
@@ -100,10 +136,36 @@ func Test_defaultResourceManager_updateCRDVirtualNode(t *testing.T) {
 				},
 				Status: appmesh.VirtualNodeStatus{
 					VirtualNodeARN: aws.String("arn-1"),
-					Conditions: []appmesh.VirtualNodeCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.VirtualNodeActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
 						},
 					},
 				},
@@ -134,7 +196,7 @@ func Test_defaultResourceManager_updateCRDVirtualNode(t *testing.T) {
 				assert.NoError(t, err)
 				opts := cmp.Options{
 					equality.IgnoreFakeClientPopulatedFields(),
-					cmpopts.IgnoreTypes((*metav1.Time)(nil)),
+					cmpopts.IgnoreTypes(metav1.Time{}),
 				}
 				assert.True(t, cmp.Equal(tt.wantVN, gotVN, opts), "diff", cmp.Diff(tt.wantVN, gotVN, opts))
 			}
@@ -404,10 +466,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
 						},
 					},
 				},
@@ -425,10 +487,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
 						},
 					},
 				},
@@ -756,3 +818,65 @@ func Test_BuildSDKVirtualNodeSpec(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyMeshTLSEnforcementMode(t *testing.T) {
+	auditMode := appmesh.TLSEnforcementModeAudit
+	enforceMode := appmesh.TLSEnforcementModeEnforce
+	buildSDKVNSpec := func() *appmeshsdk.VirtualNodeSpec {
+		return &appmeshsdk.VirtualNodeSpec{
+			BackendDefaults: &appmeshsdk.BackendDefaults{
+				ClientPolicy: &appmeshsdk.ClientPolicy{
+					Tls: &appmeshsdk.ClientPolicyTls{Enforce: aws.Bool(true)},
+				},
+			},
+			Backends: []*appmeshsdk.Backend{
+				{
+					VirtualService: &appmeshsdk.VirtualServiceBackend{
+						VirtualServiceName: aws.String("vs-1"),
+						ClientPolicy: &appmeshsdk.ClientPolicy{
+							Tls: &appmeshsdk.ClientPolicyTls{Enforce: aws.Bool(true)},
+						},
+					},
+				},
+				{
+					VirtualService: &appmeshsdk.VirtualServiceBackend{
+						VirtualServiceName: aws.String("vs-2"),
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		mode        *appmesh.TLSEnforcementMode
+		wantEnforce bool
+	}{
+		{
+			name:        "tlsEnforcementMode unset",
+			mode:        nil,
+			wantEnforce: true,
+		},
+		{
+			name:        "tlsEnforcementMode ENFORCE",
+			mode:        &enforceMode,
+			wantEnforce: true,
+		},
+		{
+			name:        "tlsEnforcementMode AUDIT",
+			mode:        &auditMode,
+			wantEnforce: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &appmesh.Mesh{
+				Spec: appmesh.MeshSpec{TLSEnforcementMode: tt.mode},
+			}
+			sdkVNSpec := buildSDKVNSpec()
+			applyMeshTLSEnforcementMode(ms, sdkVNSpec)
+			assert.Equal(t, tt.wantEnforce, aws.BoolValue(sdkVNSpec.BackendDefaults.ClientPolicy.Tls.Enforce))
+			assert.Equal(t, tt.wantEnforce, aws.BoolValue(sdkVNSpec.Backends[0].VirtualService.ClientPolicy.Tls.Enforce))
+			assert.Nil(t, sdkVNSpec.Backends[1].VirtualService.ClientPolicy)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package virtualnode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SidecarConfigHashAnnotation records the hash of VirtualNode settings baked into injected Envoy sidecars.
+	// On a VirtualNode it is the hash all its Deployments have been rolled out with,
+	// on a Deployment pod template it is the hash the pods were last restarted for.
+	SidecarConfigHashAnnotation = "appmesh.k8s.aws/sidecarConfigHash"
+
+	// sidecarInjectAnnotation mirrors inject.AppMeshSidecarInjectAnnotation, which cannot be imported due to import cycle.
+	sidecarInjectAnnotation = "appmesh.k8s.aws/sidecarInjectorWebhook"
+
+	sidecarRolloutEvaluateInterval = 15 * time.Second
+)
+
+// RolloutOrchestrator restarts workloads of a VirtualNode when its spec changes in a way that
+// requires Envoy sidecars to be restarted.
+type RolloutOrchestrator interface {
+	// Rollout will rolling restart Deployments selected by vn if sidecar relevant settings changed.
+	Rollout(ctx context.Context, vn *appmesh.VirtualNode) error
+}
+
+// NewDefaultRolloutOrchestrator constructs new RolloutOrchestrator
+func NewDefaultRolloutOrchestrator(k8sClient client.Client, cfg Config, log logr.Logger) RolloutOrchestrator {
+	return &defaultRolloutOrchestrator{
+		k8sClient:        k8sClient,
+		enabled:          cfg.EnableSidecarRollout,
+		maxConcurrent:    cfg.SidecarRolloutMaxConcurrentDeployments,
+		log:              log,
+		evaluateInterval: sidecarRolloutEvaluateInterval,
+	}
+}
+
+var _ RolloutOrchestrator = &defaultRolloutOrchestrator{}
+
+// defaultRolloutOrchestrator implements RolloutOrchestrator.
+// Deployments are restarted by stamping the new hash onto pod templates, which lets Deployment's own
+// rolling update strategy control surge. At most maxConcurrent Deployments are restarting at any time.
+type defaultRolloutOrchestrator struct {
+	k8sClient     client.Client
+	enabled       bool
+	maxConcurrent int
+	log           logr.Logger
+
+	evaluateInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+
+func (o *defaultRolloutOrchestrator) Rollout(ctx context.Context, vn *appmesh.VirtualNode) error {
+	if !o.enabled {
+		return nil
+	}
+	desiredHash, err := ComputeSidecarConfigHash(vn)
+	if err != nil {
+		return err
+	}
+	currentHash, ok := vn.Annotations[SidecarConfigHashAnnotation]
+	if !ok {
+		// first observation of this virtualNode, existing pods are assumed to be up to date.
+		return o.updateVirtualNodeSidecarConfigHash(ctx, vn, desiredHash)
+	}
+	if currentHash == desiredHash {
+		return nil
+	}
+
+	deployments, err := o.findVirtualNodeDeployments(ctx, vn)
+	if err != nil {
+		return err
+	}
+	inProgress := 0
+	var pending []*appsv1.Deployment
+	for _, deploy := range deployments {
+		if deploy.Spec.Template.Annotations[SidecarConfigHashAnnotation] == desiredHash {
+			if !isDeploymentRolloutComplete(deploy) {
+				inProgress++
+			}
+			continue
+		}
+		pending = append(pending, deploy)
+	}
+	for _, deploy := range pending {
+		if inProgress >= o.maxConcurrent {
+			break
+		}
+		if err := o.restartDeployment(ctx, deploy, desiredHash); err != nil {
+			return err
+		}
+		o.log.Info("restarted deployment for virtualNode sidecar rollout",
+			"virtualNode", k8s.NamespacedName(vn),
+			"deployment", k8s.NamespacedName(deploy),
+		)
+		inProgress++
+	}
+	if inProgress > 0 {
+		return runtime.NewRequeueAfterError(errors.New("sidecar rollout in progress"), o.evaluateInterval)
+	}
+	return o.updateVirtualNodeSidecarConfigHash(ctx, vn, desiredHash)
+}
+
+// findVirtualNodeDeployments finds Deployments whose pods will be selected by vn and injected with sidecar.
+func (o *defaultRolloutOrchestrator) findVirtualNodeDeployments(ctx context.Context, vn *appmesh.VirtualNode) ([]*appsv1.Deployment, error) {
+	deployList := &appsv1.DeploymentList{}
+	if err := o.k8sClient.List(ctx, deployList, client.InNamespace(vn.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Deployments")
+	}
+	var deployments []*appsv1.Deployment
+	for i := range deployList.Items {
+		deploy := &deployList.Items[i]
+		matches, err := MatchesPodLabels(vn, deploy.Spec.Template.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+		if strings.ToLower(deploy.Spec.Template.Annotations[sidecarInjectAnnotation]) == "disabled" {
+			continue
+		}
+		deployments = append(deployments, deploy)
+	}
+	return deployments, nil
+}
+
+func (o *defaultRolloutOrchestrator) restartDeployment(ctx context.Context, deploy *appsv1.Deployment, hash string) error {
+	oldDeploy := deploy.DeepCopy()
+	if deploy.Spec.Template.Annotations == nil {
+		deploy.Spec.Template.Annotations = make(map[string]string)
+	}
+	deploy.Spec.Template.Annotations[SidecarConfigHashAnnotation] = hash
+	return o.k8sClient.Patch(ctx, deploy, client.MergeFrom(oldDeploy))
+}
+
+func (o *defaultRolloutOrchestrator) updateVirtualNodeSidecarConfigHash(ctx context.Context, vn *appmesh.VirtualNode, hash string) error {
+	oldVN := vn.DeepCopy()
+	if vn.Annotations == nil {
+		vn.Annotations = make(map[string]string)
+	}
+	vn.Annotations[SidecarConfigHashAnnotation] = hash
+	return o.k8sClient.Patch(ctx, vn, client.MergeFrom(oldVN))
+}
+
+// isDeploymentRolloutComplete checks whether all replicas of deploy are updated and available, same as `kubectl rollout status`.
+func isDeploymentRolloutComplete(deploy *appsv1.Deployment) bool {
+	if deploy.Status.ObservedGeneration < deploy.Generation {
+		return false
+	}
+	replicas := aws.Int32Value(deploy.Spec.Replicas)
+	if deploy.Spec.Replicas == nil {
+		replicas = 1
+	}
+	return deploy.Status.UpdatedReplicas >= replicas &&
+		deploy.Status.Replicas <= deploy.Status.UpdatedReplicas &&
+		deploy.Status.AvailableReplicas >= deploy.Status.UpdatedReplicas
+}
+
+// sidecarConfig contains the VirtualNode settings that are baked into injected pods,
+// and changes to them cannot be picked up by running Envoys.
+type sidecarConfig struct {
+	AWSName   string                 `json:"awsName"`
+	MeshRef   *appmesh.MeshReference `json:"meshRef,omitempty"`
+	Listeners []sidecarListener      `json:"listeners,omitempty"`
+}
+
+type sidecarListener struct {
+	Port     appmesh.PortNumber      `json:"port"`
+	Protocol appmesh.PortProtocol    `json:"protocol"`
+	TLSMode  appmesh.ListenerTLSMode `json:"tlsMode,omitempty"`
+}
+
+// ComputeSidecarConfigHash computes the hash of vn settings that require Envoy restart when changed.
+func ComputeSidecarConfigHash(vn *appmesh.VirtualNode) (string, error) {
+	cfg := sidecarConfig{
+		AWSName: aws.StringValue(vn.Spec.AWSName),
+		MeshRef: vn.Spec.MeshRef,
+	}
+	for _, listener := range vn.Spec.Listeners {
+		listenerCfg := sidecarListener{
+			Port:     listener.PortMapping.Port,
+			Protocol: listener.PortMapping.Protocol,
+		}
+		if listener.TLS != nil {
+			listenerCfg.TLSMode = listener.TLS.Mode
+		}
+		cfg.Listeners = append(cfg.Listeners, listenerCfg)
+	}
+	payload, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:8]), nil
+}
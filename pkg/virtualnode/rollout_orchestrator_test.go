@@ -0,0 +1,213 @@
+package virtualnode
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultRolloutOrchestrator_Rollout(t *testing.T) {
+	vnWithListenerPort := func(port appmesh.PortNumber, hash string) *appmesh.VirtualNode {
+		vn := &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "awesome-ns",
+				Name:      "my-vn",
+			},
+			Spec: appmesh.VirtualNodeSpec{
+				AWSName: aws.String("my-vn_awesome-ns"),
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "my-app"},
+				},
+				Listeners: []appmesh.Listener{
+					{PortMapping: appmesh.PortMapping{Port: port, Protocol: appmesh.PortProtocolHTTP}},
+				},
+			},
+		}
+		if hash != "" {
+			vn.Annotations = map[string]string{SidecarConfigHashAnnotation: hash}
+		}
+		return vn
+	}
+	deployment := func(name string, appLabel string, hash string, complete bool) *appsv1.Deployment {
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "awesome-ns",
+				Name:      name,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: aws.Int32(2),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": appLabel},
+					},
+				},
+			},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          2,
+				UpdatedReplicas:   2,
+				AvailableReplicas: 2,
+			},
+		}
+		if hash != "" {
+			deploy.Spec.Template.Annotations = map[string]string{SidecarConfigHashAnnotation: hash}
+		}
+		if !complete {
+			deploy.Status.UpdatedReplicas = 1
+		}
+		return deploy
+	}
+	oldHash, _ := ComputeSidecarConfigHash(vnWithListenerPort(8080, ""))
+	newHash, _ := ComputeSidecarConfigHash(vnWithListenerPort(9090, ""))
+
+	tests := []struct {
+		name               string
+		enabled            bool
+		vn                 *appmesh.VirtualNode
+		deployments        []*appsv1.Deployment
+		wantVNHash         string
+		wantDeploymentHash map[string]string
+		wantErr            error
+	}{
+		{
+			name:        "no-op when disabled",
+			enabled:     false,
+			vn:          vnWithListenerPort(9090, oldHash),
+			deployments: []*appsv1.Deployment{deployment("deploy-1", "my-app", "", true)},
+			wantVNHash:  oldHash,
+			wantDeploymentHash: map[string]string{
+				"deploy-1": "",
+			},
+		},
+		{
+			name:        "records hash on first observation without restart",
+			enabled:     true,
+			vn:          vnWithListenerPort(8080, ""),
+			deployments: []*appsv1.Deployment{deployment("deploy-1", "my-app", "", true)},
+			wantVNHash:  oldHash,
+			wantDeploymentHash: map[string]string{
+				"deploy-1": "",
+			},
+		},
+		{
+			name:    "restarts one deployment at a time",
+			enabled: true,
+			vn:      vnWithListenerPort(9090, oldHash),
+			deployments: []*appsv1.Deployment{
+				deployment("deploy-1", "my-app", "", true),
+				deployment("deploy-2", "my-app", "", true),
+				deployment("deploy-3", "other-app", "", true),
+			},
+			wantVNHash: oldHash,
+			wantDeploymentHash: map[string]string{
+				"deploy-1": newHash,
+				"deploy-2": "",
+				"deploy-3": "",
+			},
+			wantErr: runtime.NewRequeueAfterError(errors.New("sidecar rollout in progress"), sidecarRolloutEvaluateInterval),
+		},
+		{
+			name:    "waits for in progress deployment",
+			enabled: true,
+			vn:      vnWithListenerPort(9090, oldHash),
+			deployments: []*appsv1.Deployment{
+				deployment("deploy-1", "my-app", newHash, false),
+				deployment("deploy-2", "my-app", "", true),
+			},
+			wantVNHash: oldHash,
+			wantDeploymentHash: map[string]string{
+				"deploy-1": newHash,
+				"deploy-2": "",
+			},
+			wantErr: runtime.NewRequeueAfterError(errors.New("sidecar rollout in progress"), sidecarRolloutEvaluateInterval),
+		},
+		{
+			name:    "records hash once all deployments rolled out",
+			enabled: true,
+			vn:      vnWithListenerPort(9090, oldHash),
+			deployments: []*appsv1.Deployment{
+				deployment("deploy-1", "my-app", newHash, true),
+				deployment("deploy-2", "my-app", newHash, true),
+			},
+			wantVNHash: newHash,
+			wantDeploymentHash: map[string]string{
+				"deploy-1": newHash,
+				"deploy-2": newHash,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := k8sruntime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			assert.NoError(t, k8sClient.Create(ctx, tt.vn.DeepCopy()))
+			for _, deploy := range tt.deployments {
+				assert.NoError(t, k8sClient.Create(ctx, deploy.DeepCopy()))
+			}
+
+			o := NewDefaultRolloutOrchestrator(k8sClient, Config{
+				EnableSidecarRollout:                   tt.enabled,
+				SidecarRolloutMaxConcurrentDeployments: 1,
+			}, logr.New(&log.NullLogSink{}))
+
+			vn := &appmesh.VirtualNode{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(tt.vn), vn))
+			err := o.Rollout(ctx, vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			gotVN := &appmesh.VirtualNode{}
+			assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(tt.vn), gotVN))
+			assert.Equal(t, tt.wantVNHash, gotVN.Annotations[SidecarConfigHashAnnotation])
+			for _, deploy := range tt.deployments {
+				gotDeploy := &appsv1.Deployment{}
+				assert.NoError(t, k8sClient.Get(ctx, k8s.NamespacedName(deploy), gotDeploy))
+				assert.Equal(t, tt.wantDeploymentHash[deploy.Name], gotDeploy.Spec.Template.Annotations[SidecarConfigHashAnnotation], deploy.Name)
+			}
+		})
+	}
+}
+
+func Test_ComputeSidecarConfigHash(t *testing.T) {
+	vn := &appmesh.VirtualNode{
+		Spec: appmesh.VirtualNodeSpec{
+			AWSName: aws.String("my-vn_awesome-ns"),
+			Listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}},
+			},
+		},
+	}
+	vnWithTLS := vn.DeepCopy()
+	vnWithTLS.Spec.Listeners[0].TLS = &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeStrict}
+	vnWithHealthCheck := vn.DeepCopy()
+	vnWithHealthCheck.Spec.Listeners[0].HealthCheck = &appmesh.HealthCheckPolicy{Path: aws.String("/ping")}
+
+	hash, err := ComputeSidecarConfigHash(vn)
+	assert.NoError(t, err)
+	hashWithTLS, err := ComputeSidecarConfigHash(vnWithTLS)
+	assert.NoError(t, err)
+	hashWithHealthCheck, err := ComputeSidecarConfigHash(vnWithHealthCheck)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, hash, hashWithTLS, "listener TLS mode change requires restart")
+	assert.Equal(t, hash, hashWithHealthCheck, "health check change is picked up by Envoy dynamically")
+}
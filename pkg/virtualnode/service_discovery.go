@@ -0,0 +1,28 @@
+package virtualnode
+
+import (
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+)
+
+// activeServiceDiscovery returns the service discovery to configure in AppMesh for vn that fails over between AWS Cloud Map and DNS.
+// the cloudMap controller records it in vn's status, the primary one is used until then.
+func activeServiceDiscovery(vn *appmesh.VirtualNode) appmesh.ServiceDiscoveryType {
+	if vn.Status.ActiveServiceDiscovery != nil {
+		return *vn.Status.ActiveServiceDiscovery
+	}
+	return vn.Spec.ServiceDiscovery.Failover.Primary
+}
+
+// applyServiceDiscoveryFailover keeps only the active service discovery in spec if vn fails over between AWS Cloud Map and DNS,
+// since AppMesh VirtualNode accepts a single one of them.
+func applyServiceDiscoveryFailover(vn *appmesh.VirtualNode, spec *appmesh.VirtualNodeSpec) {
+	if spec.ServiceDiscovery == nil || spec.ServiceDiscovery.Failover == nil {
+		return
+	}
+	switch activeServiceDiscovery(vn) {
+	case appmesh.ServiceDiscoveryTypeAWSCloudMap:
+		spec.ServiceDiscovery.DNS = nil
+	case appmesh.ServiceDiscoveryTypeDNS:
+		spec.ServiceDiscovery.AWSCloudMap = nil
+	}
+}
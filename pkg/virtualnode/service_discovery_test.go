@@ -0,0 +1,90 @@
+package virtualnode
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyServiceDiscoveryFailover(t *testing.T) {
+	cloudMap := &appmesh.AWSCloudMapServiceDiscovery{
+		NamespaceName: "my-ns",
+		ServiceName:   "my-svc",
+	}
+	dns := &appmesh.DNSServiceDiscovery{
+		Hostname: "my-svc.my-ns.svc.cluster.local",
+	}
+	activeDNS := appmesh.ServiceDiscoveryTypeDNS
+	tests := []struct {
+		name                 string
+		failover             *appmesh.ServiceDiscoveryFailover
+		activeDiscovery      *appmesh.ServiceDiscoveryType
+		wantServiceDiscovery *appmesh.ServiceDiscovery
+	}{
+		{
+			name:     "without failover",
+			failover: nil,
+			wantServiceDiscovery: &appmesh.ServiceDiscovery{
+				AWSCloudMap: cloudMap,
+				DNS:         dns,
+			},
+		},
+		{
+			name: "AWSCloudMap primary and no active service discovery recorded",
+			failover: &appmesh.ServiceDiscoveryFailover{
+				Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+			},
+			wantServiceDiscovery: &appmesh.ServiceDiscovery{
+				AWSCloudMap: cloudMap,
+				Failover: &appmesh.ServiceDiscoveryFailover{
+					Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+				},
+			},
+		},
+		{
+			name: "AWSCloudMap primary failed over to DNS",
+			failover: &appmesh.ServiceDiscoveryFailover{
+				Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+			},
+			activeDiscovery: &activeDNS,
+			wantServiceDiscovery: &appmesh.ServiceDiscovery{
+				DNS: dns,
+				Failover: &appmesh.ServiceDiscoveryFailover{
+					Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+				},
+			},
+		},
+		{
+			name: "DNS primary and no active service discovery recorded",
+			failover: &appmesh.ServiceDiscoveryFailover{
+				Primary: appmesh.ServiceDiscoveryTypeDNS,
+			},
+			wantServiceDiscovery: &appmesh.ServiceDiscovery{
+				DNS: dns,
+				Failover: &appmesh.ServiceDiscoveryFailover{
+					Primary: appmesh.ServiceDiscoveryTypeDNS,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vn := &appmesh.VirtualNode{
+				Spec: appmesh.VirtualNodeSpec{
+					ServiceDiscovery: &appmesh.ServiceDiscovery{
+						AWSCloudMap: cloudMap,
+						DNS:         dns,
+						Failover:    tt.failover,
+					},
+				},
+				Status: appmesh.VirtualNodeStatus{
+					ActiveServiceDiscovery: tt.activeDiscovery,
+				},
+			}
+			spec := vn.Spec.DeepCopy()
+			applyServiceDiscoveryFailover(vn, spec)
+			assert.Equal(t, tt.wantServiceDiscovery, spec.ServiceDiscovery)
+		})
+	}
+}
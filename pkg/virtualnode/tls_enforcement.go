@@ -0,0 +1,78 @@
+package virtualnode
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+)
+
+// tlsEnforcementChanges describes the changes of TLS enforcement from actual to desired AppMesh VirtualNode spec,
+// which break connections of clients or backends not ready for the change. Those are listeners changing TLS mode,
+// and client policies of backend defaults or backends starting or stopping to enforce TLS.
+func tlsEnforcementChanges(desired *appmeshsdk.VirtualNodeSpec, actual *appmeshsdk.VirtualNodeSpec) []string {
+	var changes []string
+	actualModeByPort := make(map[int64]string, len(actual.Listeners))
+	for _, listener := range actual.Listeners {
+		if listener.PortMapping != nil {
+			actualModeByPort[aws.Int64Value(listener.PortMapping.Port)] = listenerTLSMode(listener)
+		}
+	}
+	for _, listener := range desired.Listeners {
+		if listener.PortMapping == nil {
+			continue
+		}
+		port := aws.Int64Value(listener.PortMapping.Port)
+		actualMode, ok := actualModeByPort[port]
+		if desiredMode := listenerTLSMode(listener); ok && actualMode != desiredMode {
+			changes = append(changes, fmt.Sprintf("TLS mode of listener %d from %s to %s", port, actualMode, desiredMode))
+		}
+	}
+
+	var desiredDefaults, actualDefaults *appmeshsdk.ClientPolicy
+	if desired.BackendDefaults != nil {
+		desiredDefaults = desired.BackendDefaults.ClientPolicy
+	}
+	if actual.BackendDefaults != nil {
+		actualDefaults = actual.BackendDefaults.ClientPolicy
+	}
+	if desiredEnforced, actualEnforced := isClientPolicyTLSEnforced(desiredDefaults), isClientPolicyTLSEnforced(actualDefaults); desiredEnforced != actualEnforced {
+		changes = append(changes, fmt.Sprintf("TLS enforcement of backend defaults from %t to %t", actualEnforced, desiredEnforced))
+	}
+
+	actualPolicyByVSName := make(map[string]*appmeshsdk.ClientPolicy, len(actual.Backends))
+	for _, backend := range actual.Backends {
+		if backend.VirtualService != nil {
+			actualPolicyByVSName[aws.StringValue(backend.VirtualService.VirtualServiceName)] = backend.VirtualService.ClientPolicy
+		}
+	}
+	for _, backend := range desired.Backends {
+		if backend.VirtualService == nil {
+			continue
+		}
+		vsName := aws.StringValue(backend.VirtualService.VirtualServiceName)
+		actualPolicy, ok := actualPolicyByVSName[vsName]
+		if !ok {
+			continue
+		}
+		if desiredEnforced, actualEnforced := isClientPolicyTLSEnforced(backend.VirtualService.ClientPolicy), isClientPolicyTLSEnforced(actualPolicy); desiredEnforced != actualEnforced {
+			changes = append(changes, fmt.Sprintf("TLS enforcement of backend %s from %t to %t", vsName, actualEnforced, desiredEnforced))
+		}
+	}
+	return changes
+}
+
+func listenerTLSMode(listener *appmeshsdk.Listener) string {
+	if listener.Tls == nil || listener.Tls.Mode == nil {
+		return appmeshsdk.ListenerTlsModeDisabled
+	}
+	return aws.StringValue(listener.Tls.Mode)
+}
+
+// isClientPolicyTLSEnforced tests whether policy enforces TLS, which AppMesh defaults to once TLS is configured.
+func isClientPolicyTLSEnforced(policy *appmeshsdk.ClientPolicy) bool {
+	if policy == nil || policy.Tls == nil {
+		return false
+	}
+	return policy.Tls.Enforce == nil || aws.BoolValue(policy.Tls.Enforce)
+}
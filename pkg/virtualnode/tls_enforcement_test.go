@@ -0,0 +1,96 @@
+package virtualnode
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tlsEnforcementChanges(t *testing.T) {
+	listener := func(port int64, mode *string) *appmeshsdk.Listener {
+		l := &appmeshsdk.Listener{PortMapping: &appmeshsdk.PortMapping{Port: aws.Int64(port), Protocol: aws.String("http")}}
+		if mode != nil {
+			l.Tls = &appmeshsdk.ListenerTls{Mode: mode}
+		}
+		return l
+	}
+	tlsPolicy := func(enforce *bool) *appmeshsdk.ClientPolicy {
+		return &appmeshsdk.ClientPolicy{Tls: &appmeshsdk.ClientPolicyTls{Enforce: enforce}}
+	}
+	backend := func(vsName string, policy *appmeshsdk.ClientPolicy) *appmeshsdk.Backend {
+		return &appmeshsdk.Backend{VirtualService: &appmeshsdk.VirtualServiceBackend{VirtualServiceName: aws.String(vsName), ClientPolicy: policy}}
+	}
+	tests := []struct {
+		name    string
+		desired *appmeshsdk.VirtualNodeSpec
+		actual  *appmeshsdk.VirtualNodeSpec
+		want    []string
+	}{
+		{
+			name: "no changes",
+			desired: &appmeshsdk.VirtualNodeSpec{
+				Listeners:       []*appmeshsdk.Listener{listener(8080, aws.String(appmeshsdk.ListenerTlsModeStrict))},
+				BackendDefaults: &appmeshsdk.BackendDefaults{ClientPolicy: tlsPolicy(nil)},
+				Backends:        []*appmeshsdk.Backend{backend("vs-1", nil)},
+			},
+			actual: &appmeshsdk.VirtualNodeSpec{
+				Listeners:       []*appmeshsdk.Listener{listener(8080, aws.String(appmeshsdk.ListenerTlsModeStrict))},
+				BackendDefaults: &appmeshsdk.BackendDefaults{ClientPolicy: tlsPolicy(aws.Bool(true))},
+				Backends:        []*appmeshsdk.Backend{backend("vs-1", tlsPolicy(aws.Bool(false)))},
+			},
+		},
+		{
+			name: "listener TLS mode changed",
+			desired: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					listener(8080, aws.String(appmeshsdk.ListenerTlsModeStrict)),
+					listener(9090, nil),
+				},
+			},
+			actual: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{
+					listener(8080, aws.String(appmeshsdk.ListenerTlsModePermissive)),
+					listener(9090, nil),
+				},
+			},
+			want: []string{"TLS mode of listener 8080 from PERMISSIVE to STRICT"},
+		},
+		{
+			name: "new listener isn't a change of TLS mode",
+			desired: &appmeshsdk.VirtualNodeSpec{
+				Listeners: []*appmeshsdk.Listener{listener(8080, aws.String(appmeshsdk.ListenerTlsModeStrict))},
+			},
+			actual: &appmeshsdk.VirtualNodeSpec{},
+		},
+		{
+			name: "TLS enforcement of backend defaults and backends changed",
+			desired: &appmeshsdk.VirtualNodeSpec{
+				BackendDefaults: &appmeshsdk.BackendDefaults{ClientPolicy: tlsPolicy(nil)},
+				Backends: []*appmeshsdk.Backend{
+					backend("vs-1", nil),
+					backend("vs-2", tlsPolicy(aws.Bool(true))),
+					backend("vs-3", tlsPolicy(aws.Bool(true))),
+				},
+			},
+			actual: &appmeshsdk.VirtualNodeSpec{
+				Backends: []*appmeshsdk.Backend{
+					backend("vs-1", tlsPolicy(aws.Bool(true))),
+					backend("vs-2", tlsPolicy(aws.Bool(false))),
+				},
+			},
+			want: []string{
+				"TLS enforcement of backend defaults from false to true",
+				"TLS enforcement of backend vs-1 from true to false",
+				"TLS enforcement of backend vs-2 from false to true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tlsEnforcementChanges(tt.desired, tt.actual)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
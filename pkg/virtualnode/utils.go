@@ -2,7 +2,7 @@ package virtualnode
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IsVirtualNodeActive checks whether given virtualNode is active.
@@ -10,7 +10,7 @@ import (
 func IsVirtualNodeActive(vn *appmesh.VirtualNode) bool {
 	for _, condition := range vn.Status.Conditions {
 		if condition.Type == appmesh.VirtualNodeActive {
-			return condition.Status == corev1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue
 		}
 	}
 	return false
@@ -0,0 +1,78 @@
+package virtualrouter
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagListRoutesPageLimit     = "list-routes-page-limit"
+	flagListRoutesPageRetries   = "list-routes-page-retries"
+	flagRoutePreHooks           = "route-pre-hooks"
+	flagRoutePostHooks          = "route-post-hooks"
+	flagRouteHooksTimeout       = "route-hooks-timeout"
+	flagRouteHooksRetryInterval = "route-hooks-retry-interval"
+
+	// maxListRoutesPageLimit is the maximum number of routes AppMesh returns per ListRoutes page.
+	maxListRoutesPageLimit = 100
+)
+
+type Config struct {
+	// Maximum number of routes per ListRoutes page, 0 uses AppMesh's default.
+	ListRoutesPageLimit int64
+	// Number of times a ListRoutes page that failed to be listed is retried.
+	ListRoutesPageRetries int
+	// Hooks invoked before routes are created, updated or deleted, which can reject the change.
+	RoutePreHooks []string
+	// Hooks invoked after routes are created, updated or deleted.
+	RoutePostHooks []string
+	// Timeout of each hook invocation.
+	RouteHooksTimeout time.Duration
+	// How long a VirtualRouter whose route change is rejected by a pre hook is requeued after.
+	RouteHooksRetryInterval time.Duration
+}
+
+func (cfg *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&cfg.ListRoutesPageLimit, flagListRoutesPageLimit, 0,
+		"Maximum number of routes per page when listing routes of a VirtualRouter from AppMesh, up to 100. Set to 0 to use AppMesh's default")
+	fs.IntVar(&cfg.ListRoutesPageRetries, flagListRoutesPageRetries, 3,
+		"Number of times a page of routes of a VirtualRouter is retried before reconciling the VirtualRouter with the routes listed so far")
+	fs.StringSliceVar(&cfg.RoutePreHooks, flagRoutePreHooks, nil,
+		"Hooks invoked with the diff before an AppMesh route is created, updated or deleted, as http(s) URLs the change is POSTed to or exec:///path URLs of executables it's piped to. "+
+			"The change is applied only if every hook accepts it with a 2xx response or zero exit code")
+	fs.StringSliceVar(&cfg.RoutePostHooks, flagRoutePostHooks, nil,
+		"Hooks invoked with the diff and result after an AppMesh route is created, updated or deleted, in the same format as --"+flagRoutePreHooks)
+	fs.DurationVar(&cfg.RouteHooksTimeout, flagRouteHooksTimeout, 10*time.Second,
+		"Timeout of each route hook invocation")
+	fs.DurationVar(&cfg.RouteHooksRetryInterval, flagRouteHooksRetryInterval, time.Minute,
+		"How long a VirtualRouter whose route change is rejected by a pre hook is requeued after")
+}
+
+func (cfg *Config) BindEnv() error {
+	return nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.ListRoutesPageLimit < 0 || cfg.ListRoutesPageLimit > maxListRoutesPageLimit {
+		return errors.Errorf("%s must be within [0, %d]: %d", flagListRoutesPageLimit, maxListRoutesPageLimit, cfg.ListRoutesPageLimit)
+	}
+	if cfg.ListRoutesPageRetries < 0 {
+		return errors.Errorf("%s must not be negative: %d", flagListRoutesPageRetries, cfg.ListRoutesPageRetries)
+	}
+	for _, endpoint := range append(append([]string{}, cfg.RoutePreHooks...), cfg.RoutePostHooks...) {
+		if _, err := newRouteHook(endpoint, cfg.RouteHooksTimeout); err != nil {
+			return err
+		}
+	}
+	if len(cfg.RoutePreHooks) != 0 || len(cfg.RoutePostHooks) != 0 {
+		if cfg.RouteHooksTimeout <= 0 {
+			return errors.Errorf("%s must be positive: %v", flagRouteHooksTimeout, cfg.RouteHooksTimeout)
+		}
+		if cfg.RouteHooksRetryInterval <= 0 {
+			return errors.Errorf("%s must be positive: %v", flagRouteHooksRetryInterval, cfg.RouteHooksRetryInterval)
+		}
+	}
+	return nil
+}
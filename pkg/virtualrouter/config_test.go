@@ -0,0 +1,74 @@
+package virtualrouter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "default page limit",
+			cfg:  Config{ListRoutesPageLimit: 0, ListRoutesPageRetries: 3},
+		},
+		{
+			name: "maximum page limit without retries",
+			cfg:  Config{ListRoutesPageLimit: 100, ListRoutesPageRetries: 0},
+		},
+		{
+			name:    "page limit out of range",
+			cfg:     Config{ListRoutesPageLimit: 101, ListRoutesPageRetries: 3},
+			wantErr: "list-routes-page-limit must be within [0, 100]: 101",
+		},
+		{
+			name:    "negative page retries",
+			cfg:     Config{ListRoutesPageLimit: 50, ListRoutesPageRetries: -1},
+			wantErr: "list-routes-page-retries must not be negative: -1",
+		},
+		{
+			name: "route hooks",
+			cfg: Config{
+				ListRoutesPageRetries:   3,
+				RoutePreHooks:           []string{"https://approvals.example.com/routes"},
+				RoutePostHooks:          []string{"exec:///usr/local/bin/notify-route-change"},
+				RouteHooksTimeout:       10 * time.Second,
+				RouteHooksRetryInterval: time.Minute,
+			},
+		},
+		{
+			name: "invalid route hook",
+			cfg: Config{
+				ListRoutesPageRetries:   3,
+				RoutePostHooks:          []string{"slack://channel"},
+				RouteHooksTimeout:       10 * time.Second,
+				RouteHooksRetryInterval: time.Minute,
+			},
+			wantErr: "route hook slack://channel must be an http, https or exec URL",
+		},
+		{
+			name: "route hooks without timeout",
+			cfg: Config{
+				ListRoutesPageRetries:   3,
+				RoutePreHooks:           []string{"https://approvals.example.com/routes"},
+				RouteHooksRetryInterval: time.Minute,
+			},
+			wantErr: "route-hooks-timeout must be positive: 0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
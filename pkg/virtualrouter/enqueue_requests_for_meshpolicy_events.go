@@ -0,0 +1,75 @@
+package virtualrouter
+
+import (
+	"context"
+	"reflect"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func NewEnqueueRequestsForMeshPolicyEvents(k8sClient client.Client, log logr.Logger) *enqueueRequestsForMeshPolicyEvents {
+	return &enqueueRequestsForMeshPolicyEvents{
+		k8sClient: k8sClient,
+		log:       log,
+	}
+}
+
+var _ handler.EventHandler = (*enqueueRequestsForMeshPolicyEvents)(nil)
+
+type enqueueRequestsForMeshPolicyEvents struct {
+	k8sClient client.Client
+	log       logr.Logger
+}
+
+// Create is called in response to a create event
+func (h *enqueueRequestsForMeshPolicyEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	mp := e.Object.(*appmesh.MeshPolicy)
+	h.enqueueVirtualRoutersForMeshPolicy(context.Background(), queue, mp)
+}
+
+// Update is called in response to an update event
+func (h *enqueueRequestsForMeshPolicyEvents) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	// virtualRouter reconcile only depends on route defaults of meshPolicy.
+	mpOld := e.ObjectOld.(*appmesh.MeshPolicy)
+	mpNew := e.ObjectNew.(*appmesh.MeshPolicy)
+	if !reflect.DeepEqual(mpOld.Spec.RouteDefaults, mpNew.Spec.RouteDefaults) {
+		h.enqueueVirtualRoutersForMeshPolicy(context.Background(), queue, mpNew)
+	}
+}
+
+// Delete is called in response to a delete event
+func (h *enqueueRequestsForMeshPolicyEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	mp := e.Object.(*appmesh.MeshPolicy)
+	h.enqueueVirtualRoutersForMeshPolicy(context.Background(), queue, mp)
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request
+func (h *enqueueRequestsForMeshPolicyEvents) Generic(e event.GenericEvent, queue workqueue.RateLimitingInterface) {
+	// no-op
+}
+
+func (h *enqueueRequestsForMeshPolicyEvents) enqueueVirtualRoutersForMeshPolicy(ctx context.Context, queue workqueue.RateLimitingInterface, mp *appmesh.MeshPolicy) {
+	if mp.Spec.MeshRef == nil {
+		return
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := h.k8sClient.List(ctx, vrList); err != nil {
+		h.log.Error(err, "failed to enqueue virtualRouters for meshPolicy events",
+			"meshPolicy", k8s.NamespacedName(mp))
+		return
+	}
+	for _, vr := range vrList.Items {
+		if vr.Spec.MeshRef == nil || *vr.Spec.MeshRef != *mp.Spec.MeshRef {
+			continue
+		}
+		queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vr)})
+	}
+}
@@ -0,0 +1,305 @@
+package virtualrouter
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+func Test_enqueueRequestsForMeshPolicyEvents_Update(t *testing.T) {
+	myMeshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	vr1 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vr-1",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vr2 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vr-2",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	mp := &appmesh.MeshPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "defaults",
+		},
+		Spec: appmesh.MeshPolicySpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	mpWithRouteDefaults := mp.DeepCopy()
+	mpWithRouteDefaults.Spec.RouteDefaults = &appmesh.MeshPolicyRouteDefaults{
+		HTTPRetryPolicy: &appmesh.HTTPRetryPolicy{
+			HTTPRetryEvents: []appmesh.HTTPRetryPolicyEvent{"server-error"},
+			MaxRetries:      2,
+			PerRetryTimeout: appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 2000},
+		},
+	}
+	mpWithListenerDefaults := mp.DeepCopy()
+	mpWithListenerDefaults.Spec.ListenerDefaults = &appmesh.MeshPolicyListenerDefaults{
+		ConnectionPool: &appmesh.VirtualNodeConnectionPool{
+			HTTP: &appmesh.HTTPConnectionPool{
+				MaxConnections:     100,
+				MaxPendingRequests: aws.Int64(1000),
+			},
+		},
+	}
+
+	type env struct {
+		virtualRouters []*appmesh.VirtualRouter
+	}
+	type args struct {
+		e event.UpdateEvent
+	}
+	tests := []struct {
+		name         string
+		env          env
+		args         args
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "routeDefaults un-changed",
+			env: env{
+				virtualRouters: []*appmesh.VirtualRouter{vr1, vr2},
+			},
+			args: args{
+				e: event.UpdateEvent{
+					ObjectOld: mp,
+					ObjectNew: mpWithListenerDefaults,
+				},
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "routeDefaults changed",
+			env: env{
+				virtualRouters: []*appmesh.VirtualRouter{vr1, vr2},
+			},
+			args: args{
+				e: event.UpdateEvent{
+					ObjectOld: mp,
+					ObjectNew: mpWithRouteDefaults,
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vr1),
+				},
+				{
+					NamespacedName: k8s.NamespacedName(vr2),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForMeshPolicyEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+
+			for _, vr := range tt.env.virtualRouters {
+				err := k8sClient.Create(ctx, vr.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.Update(tt.args.e, queue)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
+
+func Test_enqueueRequestsForMeshPolicyEvents_enqueueVirtualRoutersForMeshPolicy(t *testing.T) {
+	myMeshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "a385048d-aba8-4235-9a11-4173764c8ab7",
+	}
+	anotherMeshRef := &appmesh.MeshReference{
+		Name: "another-mesh",
+		UID:  "1d3b3c3c-7dec-11ea-b156-0e30aabe1ca8",
+	}
+	vr1 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vr-1",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vr2 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other-ns",
+			Name:      "vr-2",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: myMeshRef,
+		},
+	}
+	vr3 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vr-3",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: anotherMeshRef,
+		},
+	}
+	vr4 := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vr-4",
+		},
+		Spec: appmesh.VirtualRouterSpec{
+			MeshRef: &appmesh.MeshReference{
+				Name: "my-mesh",
+				UID:  "f7e83b85-3f41-4b27-9e7a-5c8a5b0a3e4d",
+			},
+		},
+	}
+
+	type env struct {
+		virtualRouters []*appmesh.VirtualRouter
+	}
+	type args struct {
+		mp *appmesh.MeshPolicy
+	}
+	tests := []struct {
+		name         string
+		env          env
+		args         args
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "meshPolicy without meshRef",
+			env: env{
+				virtualRouters: []*appmesh.VirtualRouter{vr1, vr2, vr3, vr4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+				},
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "meshPolicy of my-mesh",
+			env: env{
+				virtualRouters: []*appmesh.VirtualRouter{vr1, vr2, vr3, vr4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+					Spec: appmesh.MeshPolicySpec{
+						MeshRef: myMeshRef,
+					},
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vr1),
+				},
+				{
+					NamespacedName: k8s.NamespacedName(vr2),
+				},
+			},
+		},
+		{
+			name: "meshPolicy of another-mesh",
+			env: env{
+				virtualRouters: []*appmesh.VirtualRouter{vr1, vr2, vr3, vr4},
+			},
+			args: args{
+				mp: &appmesh.MeshPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "defaults",
+					},
+					Spec: appmesh.MeshPolicySpec{
+						MeshRef: anotherMeshRef,
+					},
+				},
+			},
+			wantRequests: []reconcile.Request{
+				{
+					NamespacedName: k8s.NamespacedName(vr3),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			h := &enqueueRequestsForMeshPolicyEvents{
+				k8sClient: k8sClient,
+				log:       logr.New(&log.NullLogSink{}),
+			}
+
+			for _, vr := range tt.env.virtualRouters {
+				err := k8sClient.Create(ctx, vr.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			h.enqueueVirtualRoutersForMeshPolicy(ctx, queue, tt.args.mp)
+			var gotRequests []reconcile.Request
+			queueLen := queue.Len()
+			for i := 0; i < queueLen; i++ {
+				item, _ := queue.Get()
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+
+			opt := cmpopts.SortSlices(compareReconcileRequest)
+			assert.True(t, cmp.Equal(tt.wantRequests, gotRequests, opt), "diff: %v", cmp.Diff(tt.wantRequests, gotRequests, opt))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package virtualrouter
 
 import (
 	"context"
+	"reflect"
+
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
@@ -9,12 +11,14 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
-func NewEnqueueRequestsForVirtualNodeEvents(referencesIndexer references.ObjectReferenceIndexer, log logr.Logger) *enqueueRequestsForVirtualNodeEvents {
+func NewEnqueueRequestsForVirtualNodeEvents(k8sClient client.Client, referencesIndexer references.ObjectReferenceIndexer, log logr.Logger) *enqueueRequestsForVirtualNodeEvents {
 	return &enqueueRequestsForVirtualNodeEvents{
+		k8sClient:         k8sClient,
 		referencesIndexer: referencesIndexer,
 		log:               log,
 	}
@@ -23,13 +27,16 @@ func NewEnqueueRequestsForVirtualNodeEvents(referencesIndexer references.ObjectR
 var _ handler.EventHandler = (*enqueueRequestsForVirtualNodeEvents)(nil)
 
 type enqueueRequestsForVirtualNodeEvents struct {
+	k8sClient         client.Client
 	referencesIndexer references.ObjectReferenceIndexer
 	log               logr.Logger
 }
 
 // Create is called in response to an create event
 func (h *enqueueRequestsForVirtualNodeEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualRouters referencing a virtualNode that's not found yet wait for its creation.
+	vn := e.Object.(*appmesh.VirtualNode)
+	h.enqueueVirtualRoutersForVirtualNode(context.Background(), queue, vn)
 }
 
 // Update is called in response to an update event
@@ -41,12 +48,20 @@ func (h *enqueueRequestsForVirtualNodeEvents) Update(e event.UpdateEvent, queue
 
 	if virtualnode.IsVirtualNodeActive(vnOld) != virtualnode.IsVirtualNodeActive(vnNew) {
 		h.enqueueVirtualRoutersForVirtualNode(context.Background(), queue, vnNew)
+		return
+	}
+	// virtualRouters selecting virtualNodes by labels resolve their targets and weights again.
+	if !reflect.DeepEqual(vnOld.Labels, vnNew.Labels) ||
+		vnOld.Annotations[VirtualNodeWeightAnnotation] != vnNew.Annotations[VirtualNodeWeightAnnotation] {
+		h.enqueueVirtualRoutersSelectingVirtualNode(context.Background(), queue, vnOld, vnNew)
 	}
 }
 
 // Delete is called in response to a delete event
 func (h *enqueueRequestsForVirtualNodeEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualRouters referencing a deleted virtualNode report it as unresolved.
+	vn := e.Object.(*appmesh.VirtualNode)
+	h.enqueueVirtualRoutersForVirtualNode(context.Background(), queue, vn)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
@@ -65,4 +80,25 @@ func (h *enqueueRequestsForVirtualNodeEvents) enqueueVirtualRoutersForVirtualNod
 	for _, vr := range vrList.Items {
 		queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(&vr)})
 	}
+	h.enqueueVirtualRoutersSelectingVirtualNode(ctx, queue, vn)
+}
+
+// enqueueVirtualRoutersSelectingVirtualNode enqueues virtualRouters whose route targets select any of vns by virtualNodeSelector.
+func (h *enqueueRequestsForVirtualNodeEvents) enqueueVirtualRoutersSelectingVirtualNode(ctx context.Context, queue workqueue.RateLimitingInterface, vns ...*appmesh.VirtualNode) {
+	vrList := &appmesh.VirtualRouterList{}
+	if err := h.k8sClient.List(ctx, vrList, client.InNamespace(vns[0].Namespace)); err != nil {
+		h.log.Error(err, "failed to enqueue virtualRouters for virtualNode events",
+			"virtualNode", k8s.NamespacedName(vns[0]))
+		return
+	}
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		for _, vn := range vns {
+			// invalid virtualNodeSelectors are reported when the virtualRouter is reconciled.
+			if selected, _ := IsVirtualNodeSelected(vr, vn); selected {
+				queue.Add(ctrl.Request{NamespacedName: k8s.NamespacedName(vr)})
+				break
+			}
+		}
+	}
 }
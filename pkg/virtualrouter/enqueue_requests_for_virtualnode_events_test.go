@@ -0,0 +1,169 @@
+package virtualrouter
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeReferencesIndexer fetches virtualRouters referencing a virtualNode by VirtualNodeReferenceIndexFunc.
+type fakeReferencesIndexer struct {
+	references.ObjectReferenceIndexer
+
+	virtualRouters []*appmesh.VirtualRouter
+}
+
+func (i *fakeReferencesIndexer) Fetch(_ context.Context, objList client.ObjectList, _ string, referentKey types.NamespacedName, _ ...client.ListOption) error {
+	vrList := objList.(*appmesh.VirtualRouterList)
+	for _, vr := range i.virtualRouters {
+		for _, vnKey := range VirtualNodeReferenceIndexFunc(vr) {
+			if vnKey == referentKey {
+				vrList.Items = append(vrList.Items, *vr)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func Test_enqueueRequestsForVirtualNodeEvents(t *testing.T) {
+	vrWithTarget := func(name string, vnName string) *appmesh.VirtualRouter {
+		route := newTestTCPRoute("route-1", 100)
+		route.TCPRoute.Action.WeightedTargets[0].VirtualNodeRef.Name = vnName
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name},
+			Spec: appmesh.VirtualRouterSpec{
+				Routes: []appmesh.Route{route},
+			},
+		}
+	}
+	vrWithSelector := func(name string, vnLabels map[string]string) *appmesh.VirtualRouter {
+		route := newTestTCPRoute("route-1", 100)
+		route.TCPRoute.Action.WeightedTargets[0].VirtualNodeRef = nil
+		route.TCPRoute.Action.WeightedTargets[0].VirtualNodeSelector = &metav1.LabelSelector{MatchLabels: vnLabels}
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name},
+			Spec: appmesh.VirtualRouterSpec{
+				Routes: []appmesh.Route{route},
+			},
+		}
+	}
+	vnWithActive := func(status metav1.ConditionStatus) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "vn-1"},
+			Status: appmesh.VirtualNodeStatus{
+				Conditions: []metav1.Condition{{Type: appmesh.VirtualNodeActive, Status: status}},
+			},
+		}
+	}
+	vnWithLabels := func(vnLabels map[string]string, weight string) *appmesh.VirtualNode {
+		vn := vnWithActive(metav1.ConditionTrue)
+		vn.Labels = vnLabels
+		if len(weight) != 0 {
+			vn.Annotations = map[string]string{VirtualNodeWeightAnnotation: weight}
+		}
+		return vn
+	}
+	virtualRouters := []*appmesh.VirtualRouter{vrWithTarget("vr-1", "vn-1"), vrWithTarget("vr-2", "vn-2"), vrWithSelector("vr-3", map[string]string{"track": "green"})}
+	wantRequests := []reconcile.Request{{NamespacedName: k8s.NamespacedName(virtualRouters[0])}}
+	wantSelectorRequests := []reconcile.Request{{NamespacedName: k8s.NamespacedName(virtualRouters[2])}}
+
+	tests := []struct {
+		name         string
+		fire         func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface)
+		wantRequests []reconcile.Request
+	}{
+		{
+			name: "virtualNode created",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Create(event.CreateEvent{Object: vnWithActive(metav1.ConditionUnknown)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+		{
+			name: "virtualNode becomes active",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vnWithActive(metav1.ConditionUnknown), ObjectNew: vnWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+		{
+			name: "virtualNode updated without active status change",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vnWithActive(metav1.ConditionTrue), ObjectNew: vnWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "virtualNode deleted",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Delete(event.DeleteEvent{Object: vnWithActive(metav1.ConditionTrue)}, queue)
+			},
+			wantRequests: wantRequests,
+		},
+		{
+			name: "virtualNode selected by labels created",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Create(event.CreateEvent{Object: vnWithLabels(map[string]string{"track": "green"}, "")}, queue)
+			},
+			wantRequests: append(wantRequests, wantSelectorRequests...),
+		},
+		{
+			name: "virtualNode labels no longer selected",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vnWithLabels(map[string]string{"track": "green"}, ""), ObjectNew: vnWithLabels(map[string]string{"track": "blue"}, "")}, queue)
+			},
+			wantRequests: wantSelectorRequests,
+		},
+		{
+			name: "virtualNode selected by labels changes weight",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vnWithLabels(map[string]string{"track": "green"}, "1"), ObjectNew: vnWithLabels(map[string]string{"track": "green"}, "3")}, queue)
+			},
+			wantRequests: wantSelectorRequests,
+		},
+		{
+			name: "virtualNode not selected by labels changes weight",
+			fire: func(h *enqueueRequestsForVirtualNodeEvents, queue workqueue.RateLimitingInterface) {
+				h.Update(event.UpdateEvent{ObjectOld: vnWithLabels(map[string]string{"track": "blue"}, "1"), ObjectNew: vnWithLabels(map[string]string{"track": "blue"}, "3")}, queue)
+			},
+			wantRequests: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vr := range virtualRouters {
+				assert.NoError(t, k8sClient.Create(context.Background(), vr.DeepCopy()))
+			}
+			h := NewEnqueueRequestsForVirtualNodeEvents(k8sClient, &fakeReferencesIndexer{virtualRouters: virtualRouters}, logr.Discard())
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			tt.fire(h, queue)
+
+			var gotRequests []reconcile.Request
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				queue.Done(item)
+				gotRequests = append(gotRequests, item.(reconcile.Request))
+			}
+			assert.Equal(t, tt.wantRequests, gotRequests)
+		})
+	}
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/meshpolicy"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,76 +36,121 @@ type ResourceManager interface {
 
 	// Cleanup will delete AppMesh VirtualRouter created for vr.
 	Cleanup(ctx context.Context, vr *appmesh.VirtualRouter) error
+
+	// Diff will compare AppMesh VirtualRouter and its routes against the specs desired for vr, without changing them.
+	Diff(ctx context.Context, vr *appmesh.VirtualRouter) ([]equality.SpecDiff, error)
 }
 
 func NewDefaultResourceManager(k8sClient client.Client, appMeshSDK services.AppMesh, referencesResolver references.Resolver,
-	accountID string, log logr.Logger) ResourceManager {
-	routesManager := newDefaultRoutesManager(appMeshSDK, log)
+	accountID string, tagsManager tagging.Manager, quotaChecker quota.Checker, maintenanceScheduler maintenance.Scheduler, config Config, log logr.Logger, enableMeshPolicies bool) ResourceManager {
+	routesManager := newDefaultRoutesManager(appMeshSDK, tagsManager, config, log)
 	return &defaultResourceManager{
-		k8sClient:          k8sClient,
-		appMeshSDK:         appMeshSDK,
-		referencesResolver: referencesResolver,
-		routesManager:      routesManager,
-		accountID:          accountID,
-		log:                log,
+		k8sClient:            k8sClient,
+		appMeshSDK:           appMeshSDK,
+		referencesResolver:   referencesResolver,
+		routesManager:        routesManager,
+		accountID:            accountID,
+		tagsManager:          tagsManager,
+		quotaChecker:         quotaChecker,
+		maintenanceScheduler: maintenanceScheduler,
+		log:                  log,
+		enableMeshPolicies:   enableMeshPolicies,
 	}
 }
 
 type defaultResourceManager struct {
-	k8sClient          client.Client
-	appMeshSDK         services.AppMesh
-	referencesResolver references.Resolver
-	routesManager      routesManager
-	accountID          string
-	log                logr.Logger
+	k8sClient            client.Client
+	appMeshSDK           services.AppMesh
+	referencesResolver   references.Resolver
+	routesManager        routesManager
+	accountID            string
+	tagsManager          tagging.Manager
+	quotaChecker         quota.Checker
+	maintenanceScheduler maintenance.Scheduler
+	log                  logr.Logger
+	enableMeshPolicies   bool
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, vr *appmesh.VirtualRouter) error {
 	ms, err := m.findMeshDependency(ctx, vr)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateMeshDependencies(ctx, ms); err != nil {
-		return err
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
-	vnByKey, err := m.findVirtualNodeDependencies(ctx, vr)
+	// expandedVR has route targets selected by virtualNodeSelector resolved into virtualNodeRefs.
+	expandedVR, err := ExpandVirtualNodeSelectors(ctx, m.k8sClient, vr)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
+	}
+	vnByKey, err := m.findVirtualNodeDependencies(ctx, expandedVR)
+	if err != nil {
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualNodeDependencies(ctx, ms, vnByKey); err != nil {
-		return err
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
+	}
+	// desiredVR has route defaults of meshPolicies applied, it's only used to build the AppMesh route specs.
+	desiredVR, err := m.applyMeshPolicies(ctx, ms, expandedVR)
+	if err != nil {
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 	}
 
 	sdkVR, err := m.findSDKVirtualRouter(ctx, ms, vr)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	if err := m.quotaChecker.CheckVirtualRouter(ctx, ms, vr, sdkVR == nil); err != nil {
+		return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonQuotaExceeded, err)
 	}
 	var sdkRouteByName map[string]*appmeshsdk.RouteData
+	// routesListErr is set when routes are partially listed, the virtualRouter is reconciled as Degraded then.
+	var routesListErr error
 	if sdkVR == nil {
 		sdkVR, err = m.createSDKVirtualRouter(ctx, ms, vr)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
-		sdkRouteByName, err = m.routesManager.create(ctx, ms, vr, vnByKey)
+		sdkRouteByName, err = m.routesManager.create(ctx, ms, desiredVR, vnByKey)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
+		if err := m.checkSDKVirtualRouterOwnership(ctx, sdkVR, vr); err != nil {
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.deferDisruptiveChanges(ctx, ms, sdkVR, vr); err != nil {
+			if maintenance.IsChangesDeferredError(err) {
+				return err
+			}
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
 		err = m.routesManager.remove(ctx, ms, sdkVR, vr)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 		sdkVR, err = m.updateSDKVirtualRouter(ctx, sdkVR, vr)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.reconcileSDKVirtualRouterTags(ctx, sdkVR, vr); err != nil {
+			return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
-		sdkRouteByName, err = m.routesManager.update(ctx, ms, vr, vnByKey)
+		sdkRouteByName, err = m.routesManager.update(ctx, ms, desiredVR, vnByKey)
 		if err != nil {
-			return err
+			if !isRoutesPartiallyListedError(err) {
+				return m.updateCRDVirtualRouterForFailure(ctx, vr, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+			}
+			routesListErr = err
 		}
 	}
 
-	return m.updateCRDVirtualRouter(ctx, vr, sdkVR, sdkRouteByName)
+	if err := m.updateCRDVirtualRouter(ctx, vr, sdkVR, sdkRouteByName, routesListErr); err != nil {
+		return err
+	}
+	// routes are listed again upon retry, to delete the routes on pages failed to be listed.
+	return routesListErr
 }
 
 func (m *defaultResourceManager) Cleanup(ctx context.Context, vr *appmesh.VirtualRouter) error {
@@ -118,12 +168,74 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, vr *appmesh.Virtua
 	if sdkVR == nil {
 		return nil
 	}
+	if err := m.checkSDKVirtualRouterOwnership(ctx, sdkVR, vr); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip virtualRouter deletion since it's owned by another controller",
+			"virtualRouter", k8s.NamespacedName(vr),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	if err := m.routesManager.cleanup(ctx, ms, vr); err != nil {
 		return err
 	}
 	return m.deleteSDKVirtualRouter(ctx, sdkVR, vr)
 }
 
+func (m *defaultResourceManager) Diff(ctx context.Context, vr *appmesh.VirtualRouter) ([]equality.SpecDiff, error) {
+	ms, err := m.findMeshDependency(ctx, vr)
+	if err != nil {
+		return nil, err
+	}
+	expandedVR, err := ExpandVirtualNodeSelectors(ctx, m.k8sClient, vr)
+	if err != nil {
+		return nil, err
+	}
+	vnByKey, err := m.findVirtualNodeDependencies(ctx, expandedVR)
+	if err != nil {
+		return nil, err
+	}
+	desiredSDKVRSpec, err := BuildSDKVirtualRouterSpec(vr)
+	if err != nil {
+		return nil, err
+	}
+	sdkVR, err := m.findSDKVirtualRouter(ctx, ms, vr)
+	if err != nil {
+		return nil, err
+	}
+	var actualSDKVRSpec *appmeshsdk.VirtualRouterSpec
+	if sdkVR != nil {
+		actualSDKVRSpec = sdkVR.Spec
+	}
+	resource := "virtualRouter/" + aws.StringValue(vr.Spec.AWSName)
+	vrDiff := equality.DiffSpec(resource, desiredSDKVRSpec, actualSDKVRSpec, sdkVR != nil, equality.CompareOptionForVirtualRouterSpec())
+	desiredVR, err := m.applyMeshPolicies(ctx, ms, expandedVR)
+	if err != nil {
+		return nil, err
+	}
+	routeDiffs, err := m.routesManager.diff(ctx, ms, sdkVR, desiredVR, vnByKey)
+	if err != nil {
+		return nil, err
+	}
+	return append([]equality.SpecDiff{vrDiff}, routeDiffs...), nil
+}
+
+// deferDisruptiveChanges returns a ChangesDeferredError if vr has disruptive changes, such as route deletions,
+// while the maintenance window of vr isn't open. vr is reconciled as a whole once the window opens.
+func (m *defaultResourceManager) deferDisruptiveChanges(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) error {
+	until, err := m.maintenanceScheduler.DeferUntil(ctx, ms, vr)
+	if err != nil || until.IsZero() {
+		return err
+	}
+	changes, err := m.routesManager.disruptiveChanges(ctx, ms, sdkVR, vr)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	return maintenance.DeferChanges(ctx, m.k8sClient, vr, &vr.Status.Conditions, changes, until)
+}
+
 // findMeshDependency find the Mesh dependency for this VirtualRouter.
 func (m *defaultResourceManager) findMeshDependency(ctx context.Context, vr *appmesh.VirtualRouter) (*appmesh.Mesh, error) {
 	if vr.Spec.MeshRef == nil {
@@ -154,6 +266,10 @@ func (m *defaultResourceManager) findVirtualNodeDependencies(ctx context.Context
 		}
 		vn, err := m.referencesResolver.ResolveVirtualNodeReference(ctx, vr, vnRef)
 		if err != nil {
+			// virtualNode events enqueue the referencing objects once it's created.
+			if apierrors.IsNotFound(err) {
+				return nil, runtime.NewDependencyNotReadyError(errors.Wrapf(err, "failed to resolve virtualNodeRef"))
+			}
 			return nil, errors.Wrapf(err, "failed to resolve virtualNodeRef")
 		}
 		vnByKey[vnKey] = vn
@@ -167,12 +283,24 @@ func (m *defaultResourceManager) validateVirtualNodeDependencies(ctx context.Con
 			return errors.Errorf("virtualNode %v didn't belong to mesh %v", k8s.NamespacedName(vn), k8s.NamespacedName(ms))
 		}
 		if !virtualnode.IsVirtualNodeActive(vn) {
-			return runtime.NewRequeueError(errors.New("virtualNode is not active yet"))
+			return runtime.NewDependencyNotReadyError(errors.Errorf("virtualNode %v is not active yet", k8s.NamespacedName(vn)))
 		}
 	}
 	return nil
 }
 
+// applyMeshPolicies returns vr with route defaults of the meshPolicies of ms applied, when meshPolicies are enabled.
+func (m *defaultResourceManager) applyMeshPolicies(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) (*appmesh.VirtualRouter, error) {
+	if !m.enableMeshPolicies {
+		return vr, nil
+	}
+	policies, err := meshpolicy.FindMeshPolicies(ctx, m.k8sClient, ms)
+	if err != nil {
+		return nil, err
+	}
+	return meshpolicy.ApplyRouteDefaults(policies, vr), nil
+}
+
 func (m *defaultResourceManager) findSDKVirtualRouter(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) (*appmeshsdk.VirtualRouterData, error) {
 	resp, err := m.appMeshSDK.DescribeVirtualRouterWithContext(ctx, &appmeshsdk.DescribeVirtualRouterInput{
 		MeshName:          ms.Spec.AWSName,
@@ -199,6 +327,7 @@ func (m *defaultResourceManager) createSDKVirtualRouter(ctx context.Context, ms
 		MeshOwner:         ms.Spec.MeshOwner,
 		VirtualRouterName: vr.Spec.AWSName,
 		Spec:              sdkVRSpec,
+		Tags:              m.buildSDKVirtualRouterTags(ctx, vr),
 	})
 	if err != nil {
 		return nil, err
@@ -213,7 +342,7 @@ func (m *defaultResourceManager) updateSDKVirtualRouter(ctx context.Context, sdk
 		return nil, err
 	}
 
-	opts := cmpopts.EquateEmpty()
+	opts := equality.CompareOptionForVirtualRouterSpec()
 	if cmp.Equal(desiredSDKVRSpec, actualSDKVRSpec, opts) {
 		return sdkVR, nil
 	}
@@ -232,7 +361,7 @@ func (m *defaultResourceManager) updateSDKVirtualRouter(ctx context.Context, sdk
 		"desiredSDKVRSpec", desiredSDKVRSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateVirtualRouterWithContext(ctx, &appmeshsdk.UpdateVirtualRouterInput{
+	resp, err := m.appMeshSDK.UpdateVirtualRouterWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateVirtualRouterInput{
 		MeshName:          sdkVR.MeshName,
 		MeshOwner:         sdkVR.Metadata.MeshOwner,
 		VirtualRouterName: sdkVR.VirtualRouterName,
@@ -263,7 +392,9 @@ func (m *defaultResourceManager) deleteSDKVirtualRouter(ctx context.Context, sdk
 	return nil
 }
 
-func (m *defaultResourceManager) updateCRDVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter, sdkVR *appmeshsdk.VirtualRouterData, sdkRouteByName map[string]*appmeshsdk.RouteData) error {
+// updateCRDVirtualRouter will update virtualRouter's status, routesListErr is non-nil if routes are partially listed.
+func (m *defaultResourceManager) updateCRDVirtualRouter(ctx context.Context, vr *appmesh.VirtualRouter, sdkVR *appmeshsdk.VirtualRouterData,
+	sdkRouteByName map[string]*appmeshsdk.RouteData, routesListErr error) error {
 	oldVR := vr.DeepCopy()
 
 	needsUpdate := false
@@ -271,6 +402,14 @@ func (m *defaultResourceManager) updateCRDVirtualRouter(ctx context.Context, vr
 		vr.Status.VirtualRouterARN = sdkVR.Metadata.Arn
 		needsUpdate = true
 	}
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkVR.Metadata, resourceMetadata); err != nil {
+		return err
+	}
+	if !cmp.Equal(vr.Status.ResourceMetadata, resourceMetadata) {
+		vr.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
 	if aws.Int64Value(vr.Status.ObservedGeneration) != vr.Generation {
 		vr.Status.ObservedGeneration = aws.Int64(vr.Generation)
 		needsUpdate = true
@@ -285,11 +424,16 @@ func (m *defaultResourceManager) updateCRDVirtualRouter(ctx context.Context, vr
 		needsUpdate = true
 	}
 
-	vrActiveConditionStatus := corev1.ConditionFalse
-	if sdkVR.Status != nil && aws.StringValue(sdkVR.Status.Status) == appmeshsdk.VirtualRouterStatusCodeActive {
-		vrActiveConditionStatus = corev1.ConditionTrue
+	if maintenance.SetChangesApplied(vr.Generation, &vr.Status.Conditions) {
+		needsUpdate = true
 	}
-	if updateCondition(vr, appmesh.VirtualRouterActive, vrActiveConditionStatus, nil, nil) {
+
+	vrActive := sdkVR.Status != nil && aws.StringValue(sdkVR.Status.Status) == appmeshsdk.VirtualRouterStatusCodeActive
+	if routesListErr != nil {
+		if updateConditionsForDegraded(vr, vrActive, appmesh.ReasonRoutesPartiallyListed, routesListErr) {
+			needsUpdate = true
+		}
+	} else if updateConditionsForReconciled(vr, vrActive) {
 		needsUpdate = true
 	}
 
@@ -299,6 +443,45 @@ func (m *defaultResourceManager) updateCRDVirtualRouter(ctx context.Context, vr
 	return m.k8sClient.Status().Patch(ctx, vr, client.MergeFrom(oldVR))
 }
 
+// updateCRDVirtualRouterForFailure will record reconcile failure err into virtualRouter's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDVirtualRouterForFailure(ctx context.Context, vr *appmesh.VirtualRouter, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	if isRouteChangeRejectedError(err) {
+		reason = appmesh.ReasonRouteChangeRejected
+	}
+	oldVR := vr.DeepCopy()
+	if !updateConditionsForFailure(vr, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, vr, client.MergeFrom(oldVR)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "virtualRouter", k8s.NamespacedName(vr))
+	}
+	return err
+}
+
+func (m *defaultResourceManager) buildSDKVirtualRouterTags(ctx context.Context, vr *appmesh.VirtualRouter) []*appmeshsdk.TagRef {
+	return m.tagsManager.BuildTags(vr)
+}
+
+// reconcileSDKVirtualRouterTags corrects tags of AppMesh virtualRouter if it's controlled by CRD virtualRouter.
+func (m *defaultResourceManager) reconcileSDKVirtualRouterTags(ctx context.Context, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) error {
+	if !m.isSDKVirtualRouterControlledByCRDVirtualRouter(ctx, sdkVR, vr) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkVR.Metadata.Arn), vr); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
+	return nil
+}
+
+// checkSDKVirtualRouterOwnership checks AppMesh virtualRouter isn't owned by another controller if it's controlled by CRD virtualRouter.
+func (m *defaultResourceManager) checkSDKVirtualRouterOwnership(ctx context.Context, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) error {
+	if !m.isSDKVirtualRouterControlledByCRDVirtualRouter(ctx, sdkVR, vr) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkVR.Metadata.Arn), vr)
+}
+
 // isSDKVirtualRouterControlledByCRDVirtualRouter checks whether an AppMesh virtualRouter is controlled by CRD VirtualRouter.
 // if it's controlled, CRD VirtualRouter update is responsible for updating the AppMesh virtualRouter.
 func (m *defaultResourceManager) isSDKVirtualRouterControlledByCRDVirtualRouter(ctx context.Context, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) bool {
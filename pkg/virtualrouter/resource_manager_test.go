@@ -9,6 +9,7 @@ import (
 	mock_resolver "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	appmeshruntime "github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
 	"github.com/aws/aws-sdk-go/aws"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/golang/mock/gomock"
@@ -16,9 +17,10 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -30,6 +32,7 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 		vr             *appmesh.VirtualRouter
 		sdkVR          *appmeshsdk.VirtualRouterData
 		sdkRouteByName map[string]*appmeshsdk.RouteData
+		routesListErr  error
 	}
 	tests := []struct {
 		name    string
@@ -48,7 +51,10 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualRouterStatus{
 						Status: aws.String(appmeshsdk.VirtualRouterStatusCodeActive),
@@ -73,14 +79,40 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 				},
 				Status: appmesh.VirtualRouterStatus{
 					VirtualRouterARN: aws.String("arn-1"),
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
 					RouteARNs: map[string]string{
 						"route-1": "route-arn-1",
 						"route-2": "route-arn-2",
 					},
-					Conditions: []appmesh.VirtualRouterCondition{
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.VirtualRouterActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -95,17 +127,25 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 					},
 					Status: appmesh.VirtualRouterStatus{
 						VirtualRouterARN: aws.String("arn-1"),
-						Conditions: []appmesh.VirtualRouterCondition{
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
+						Conditions: []metav1.Condition{
 							{
 								Type:   appmesh.VirtualRouterActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
 				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualRouterStatus{
 						Status: aws.String(appmeshsdk.VirtualRouterStatusCodeInactive),
@@ -118,10 +158,36 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 				},
 				Status: appmesh.VirtualRouterStatus{
 					VirtualRouterARN: aws.String("arn-1"),
-					Conditions: []appmesh.VirtualRouterCondition{
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.VirtualRouterActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonAppMeshResourceInactive,
 						},
 					},
 				},
@@ -136,21 +202,50 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 					},
 					Status: appmesh.VirtualRouterStatus{
 						VirtualRouterARN: aws.String("arn-1"),
+						ResourceMetadata: &appmesh.AWSResourceMetadata{
+							UID:           aws.String("uid-1"),
+							MeshOwner:     aws.String("222222222222"),
+							ResourceOwner: aws.String("222222222222"),
+						},
 						RouteARNs: map[string]string{
 							"route-1": "route-arn-1",
 							"route-2": "route-arn-2",
 						},
-						Conditions: []appmesh.VirtualRouterCondition{
+						Conditions: []metav1.Condition{
+							{
+								Type:   appmesh.ConditionReferencesResolved,
+								Status: metav1.ConditionTrue,
+								Reason: appmesh.ReasonReconciled,
+							},
+							{
+								Type:   appmesh.ConditionSynced,
+								Status: metav1.ConditionTrue,
+								Reason: appmesh.ReasonReconciled,
+							},
+							{
+								Type:   appmesh.ConditionDegraded,
+								Status: metav1.ConditionFalse,
+								Reason: appmesh.ReasonReconciled,
+							},
+							{
+								Type:   appmesh.ConditionReady,
+								Status: metav1.ConditionTrue,
+								Reason: appmesh.ReasonReconciled,
+							},
 							{
 								Type:   appmesh.VirtualRouterActive,
-								Status: corev1.ConditionTrue,
+								Status: metav1.ConditionTrue,
+								Reason: appmesh.ReasonReconciled,
 							},
 						},
 					},
 				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Metadata: &appmeshsdk.ResourceMetadata{
-						Arn: aws.String("arn-1"),
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
 					},
 					Status: &appmeshsdk.VirtualRouterStatus{
 						Status: aws.String(appmeshsdk.VirtualRouterStatusCodeActive),
@@ -180,15 +275,115 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 				},
 				Status: appmesh.VirtualRouterStatus{
 					VirtualRouterARN: aws.String("arn-1"),
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
 					RouteARNs: map[string]string{
 						"route-1": "route-arn-1",
 						"route-2": "route-arn-2",
 						"route-3": "route-arn-3",
 					},
-					Conditions: []appmesh.VirtualRouterCondition{
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionDegraded,
+							Status: metav1.ConditionFalse,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.VirtualRouterActive,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "virtualRouter with routes partially listed",
+			args: args{
+				vr: &appmesh.VirtualRouter{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "vr-1",
+					},
+					Status: appmesh.VirtualRouterStatus{},
+				},
+				sdkVR: &appmeshsdk.VirtualRouterData{
+					Metadata: &appmeshsdk.ResourceMetadata{
+						Arn:           aws.String("arn-1"),
+						Uid:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					Status: &appmeshsdk.VirtualRouterStatus{
+						Status: aws.String(appmeshsdk.VirtualRouterStatusCodeActive),
+					},
+				},
+				sdkRouteByName: map[string]*appmeshsdk.RouteData{
+					"route-1": {
+						Metadata: &appmeshsdk.ResourceMetadata{
+							Arn: aws.String("route-arn-1"),
+						},
+					},
+				},
+				routesListErr: &routesPartiallyListedError{err: errors.New("service unavailable")},
+			},
+			wantVR: &appmesh.VirtualRouter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "vr-1",
+				},
+				Status: appmesh.VirtualRouterStatus{
+					VirtualRouterARN: aws.String("arn-1"),
+					ResourceMetadata: &appmesh.AWSResourceMetadata{
+						UID:           aws.String("uid-1"),
+						MeshOwner:     aws.String("222222222222"),
+						ResourceOwner: aws.String("222222222222"),
+					},
+					RouteARNs: map[string]string{
+						"route-1": "route-arn-1",
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   appmesh.ConditionReferencesResolved,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:   appmesh.ConditionSynced,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
+						{
+							Type:    appmesh.ConditionDegraded,
+							Status:  metav1.ConditionTrue,
+							Reason:  appmesh.ReasonRoutesPartiallyListed,
+							Message: "failed to list all routes: service unavailable",
+						},
+						{
+							Type:   appmesh.ConditionReady,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
+						},
 						{
 							Type:   appmesh.VirtualRouterActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
+							Reason: appmesh.ReasonReconciled,
 						},
 					},
 				},
@@ -209,7 +404,7 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 
 			err := k8sClient.Create(ctx, tt.args.vr.DeepCopy())
 			assert.NoError(t, err)
-			err = m.updateCRDVirtualRouter(ctx, tt.args.vr, tt.args.sdkVR, tt.args.sdkRouteByName)
+			err = m.updateCRDVirtualRouter(ctx, tt.args.vr, tt.args.sdkVR, tt.args.sdkRouteByName, tt.args.routesListErr)
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -219,7 +414,7 @@ func Test_defaultResourceManager_updateCRDVirtualRouter(t *testing.T) {
 				assert.NoError(t, err)
 				opts := cmp.Options{
 					equality.IgnoreFakeClientPopulatedFields(),
-					cmpopts.IgnoreTypes((*metav1.Time)(nil)),
+					cmpopts.IgnoreTypes(metav1.Time{}),
 				}
 				assert.True(t, cmp.Equal(tt.wantVR, gotVR, opts), "diff", cmp.Diff(tt.wantVR, gotVR, opts))
 			}
@@ -552,10 +747,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionTrue,
+							Status: metav1.ConditionTrue,
 						},
 					},
 				},
@@ -573,10 +768,10 @@ func Test_defaultResourceManager_validateMeshDependency(t *testing.T) {
 					AWSName: aws.String("my-mesh"),
 				},
 				Status: appmesh.MeshStatus{
-					Conditions: []appmesh.MeshCondition{
+					Conditions: []metav1.Condition{
 						{
 							Type:   appmesh.MeshActive,
-							Status: corev1.ConditionFalse,
+							Status: metav1.ConditionFalse,
 						},
 					},
 				},
@@ -764,3 +959,54 @@ func Test_defaultResourceManager_findVirtualNodeDependencies(t *testing.T) {
 		})
 	}
 }
+
+func Test_defaultResourceManager_virtualNodeDependenciesNotReady(t *testing.T) {
+	ms := &appmesh.Mesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-mesh", UID: "uid-1"},
+	}
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vr-1"},
+		Spec: appmesh.VirtualRouterSpec{
+			Routes: []appmesh.Route{newTestTCPRoute("route-1", 100)},
+		},
+	}
+	vnWithActive := func(status metav1.ConditionStatus) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "vn-1"},
+			Spec: appmesh.VirtualNodeSpec{
+				MeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "uid-1"},
+			},
+			Status: appmesh.VirtualNodeStatus{
+				Conditions: []metav1.Condition{{Type: appmesh.VirtualNodeActive, Status: status}},
+			},
+		}
+	}
+
+	t.Run("virtualNode not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		resolver := mock_resolver.NewMockResolver(ctrl)
+		resolver.EXPECT().ResolveVirtualNodeReference(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, apierrors.NewNotFound(schema.GroupResource{Group: "appmesh.k8s.aws", Resource: "virtualnodes"}, "vn-1"))
+		m := &defaultResourceManager{
+			referencesResolver: resolver,
+			log:                logr.New(&log.NullLogSink{}),
+		}
+		_, err := m.findVirtualNodeDependencies(context.Background(), vr)
+		assert.EqualError(t, err, `failed to resolve virtualNodeRef: virtualnodes.appmesh.k8s.aws "vn-1" not found`)
+		assert.True(t, appmeshruntime.IsDependencyNotReadyError(err))
+	})
+
+	t.Run("virtualNode not active", func(t *testing.T) {
+		m := &defaultResourceManager{
+			log: logr.New(&log.NullLogSink{}),
+		}
+		vnByKey := map[types.NamespacedName]*appmesh.VirtualNode{{Namespace: "ns-1", Name: "vn-1"}: vnWithActive(metav1.ConditionFalse)}
+		err := m.validateVirtualNodeDependencies(context.Background(), ms, vnByKey)
+		assert.EqualError(t, err, "virtualNode ns-1/vn-1 is not active yet")
+		assert.True(t, appmeshruntime.IsDependencyNotReadyError(err))
+
+		vnByKey = map[types.NamespacedName]*appmesh.VirtualNode{{Namespace: "ns-1", Name: "vn-1"}: vnWithActive(metav1.ConditionTrue)}
+		assert.NoError(t, m.validateVirtualNodeDependencies(context.Background(), ms, vnByKey))
+	})
+}
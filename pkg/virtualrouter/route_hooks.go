@@ -0,0 +1,235 @@
+package virtualrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RouteHookPhasePre is the phase of hooks invoked before a route change is applied, which can reject the change.
+	RouteHookPhasePre = "pre"
+	// RouteHookPhasePost is the phase of hooks invoked after a route change is applied or failed to be applied.
+	RouteHookPhasePost = "post"
+
+	// RouteChangeCreate, RouteChangeUpdate and RouteChangeDelete are the operations of route changes.
+	RouteChangeCreate = "create"
+	RouteChangeUpdate = "update"
+	RouteChangeDelete = "delete"
+
+	// routeHookExecScheme is the URL scheme of hooks running a local executable, e.g. "exec:///usr/local/bin/approve-route".
+	routeHookExecScheme = "exec"
+	// maxRouteHookMessageLength is the maximum length of the response of a hook included in errors.
+	maxRouteHookMessageLength = 1024
+)
+
+// RouteChange is the payload hooks are invoked with, as JSON.
+type RouteChange struct {
+	// Phase is either "pre" or "post".
+	Phase string `json:"phase"`
+	// Operation is one of "create", "update" or "delete".
+	Operation string `json:"operation"`
+	// MeshName is the AppMesh name of the mesh of the route.
+	MeshName string `json:"meshName"`
+	// VirtualRouter is the k8s VirtualRouter the route belongs to.
+	VirtualRouter RouteChangeVirtualRouter `json:"virtualRouter"`
+	// RouteName is the AppMesh name of the route.
+	RouteName string `json:"routeName"`
+	// Diff is the difference between the desired and actual spec of the route.
+	Diff string `json:"diff"`
+	// DesiredSpec is the AppMesh spec of the route after the change, nil for deletions.
+	DesiredSpec *appmeshsdk.RouteSpec `json:"desiredSpec,omitempty"`
+	// ActualSpec is the AppMesh spec of the route before the change, nil for creations.
+	ActualSpec *appmeshsdk.RouteSpec `json:"actualSpec,omitempty"`
+	// Error is why the change failed to be applied, only set for post hooks.
+	Error string `json:"error,omitempty"`
+}
+
+// RouteChangeVirtualRouter identifies the VirtualRouter of a RouteChange.
+type RouteChangeVirtualRouter struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	AWSName   string `json:"awsName"`
+}
+
+// routeChangeRejectedError indicates a route change is rejected by a pre hook, or a pre hook failed to be invoked.
+type routeChangeRejectedError struct {
+	routeName string
+	hook      string
+	err       error
+}
+
+func (e *routeChangeRejectedError) Error() string {
+	return fmt.Sprintf("change of route %s rejected by hook %s: %v", e.routeName, e.hook, e.err)
+}
+
+func (e *routeChangeRejectedError) Unwrap() error {
+	return e.err
+}
+
+// isRouteChangeRejectedError tests whether err indicates a route change is rejected by a pre hook.
+func isRouteChangeRejectedError(err error) bool {
+	var rejectedErr *routeChangeRejectedError
+	return errors.As(err, &rejectedErr)
+}
+
+// routeHook is an endpoint invoked with RouteChange payloads.
+type routeHook interface {
+	// invoke invokes the hook with payload, it returns an error if the hook rejects or fails to handle payload.
+	invoke(ctx context.Context, payload []byte) error
+	String() string
+}
+
+// newRouteHook constructs new routeHook invoking endpoint, which is either an http(s) URL the payload is POSTed to,
+// or an exec URL of an executable the payload is piped to.
+func newRouteHook(endpoint string, timeout time.Duration) (routeHook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid route hook %s", endpoint)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &httpRouteHook{url: endpoint, httpClient: &http.Client{Timeout: timeout}}, nil
+	case routeHookExecScheme:
+		if len(u.Host) != 0 || !strings.HasPrefix(u.Path, "/") {
+			return nil, errors.Errorf("route hook %s must specify the absolute path of an executable", endpoint)
+		}
+		return &execRouteHook{path: u.Path, timeout: timeout}, nil
+	}
+	return nil, errors.Errorf("route hook %s must be an http, https or exec URL", endpoint)
+}
+
+// httpRouteHook POSTs payloads to url, which accepts the change with a 2xx response.
+type httpRouteHook struct {
+	url        string
+	httpClient *http.Client
+}
+
+func (h *httpRouteHook) invoke(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRouteHookMessageLength))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (h *httpRouteHook) String() string {
+	return h.url
+}
+
+// execRouteHook runs the executable at path with payloads on stdin, which accepts the change by exiting with 0.
+type execRouteHook struct {
+	path    string
+	timeout time.Duration
+}
+
+func (h *execRouteHook) invoke(ctx context.Context, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, h.path)
+	cmd.Stdin = bytes.NewReader(payload)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if len(message) > maxRouteHookMessageLength {
+			message = message[:maxRouteHookMessageLength]
+		}
+		return errors.Errorf("%v: %s", err, message)
+	}
+	return nil
+}
+
+func (h *execRouteHook) String() string {
+	return routeHookExecScheme + "://" + h.path
+}
+
+// newRouteHooks constructs new routeHooks from config, whose hooks are validated by Config.Validate.
+func newRouteHooks(config Config, log logr.Logger) *routeHooks {
+	hooks := &routeHooks{retryInterval: config.RouteHooksRetryInterval, log: log}
+	for _, endpoint := range config.RoutePreHooks {
+		if hook, err := newRouteHook(endpoint, config.RouteHooksTimeout); err == nil {
+			hooks.preHooks = append(hooks.preHooks, hook)
+		}
+	}
+	for _, endpoint := range config.RoutePostHooks {
+		if hook, err := newRouteHook(endpoint, config.RouteHooksTimeout); err == nil {
+			hooks.postHooks = append(hooks.postHooks, hook)
+		}
+	}
+	return hooks
+}
+
+// routeHooks invokes hooks before and after routes are changed in AppMesh, a nil routeHooks invokes none.
+type routeHooks struct {
+	preHooks  []routeHook
+	postHooks []routeHook
+	// retryInterval is how long the virtualRouter whose route change is rejected is requeued after.
+	retryInterval time.Duration
+	log           logr.Logger
+}
+
+// apply applies change by calling fn, after every pre hook accepts it. post hooks are invoked afterwards with the error of fn, if any.
+// if rejected, fn isn't called, and a routeChangeRejectedError wrapped to requeue the virtualRouter after retryInterval is returned.
+func (h *routeHooks) apply(ctx context.Context, change RouteChange, fn func() error) error {
+	if h == nil || (len(h.preHooks) == 0 && len(h.postHooks) == 0) {
+		return fn()
+	}
+	change.Phase = RouteHookPhasePre
+	for _, hook := range h.preHooks {
+		if err := h.invoke(ctx, hook, change); err != nil {
+			return runtime.NewRequeueAfterError(&routeChangeRejectedError{routeName: change.RouteName, hook: hook.String(), err: err}, h.retryInterval)
+		}
+	}
+	err := fn()
+	change.Phase = RouteHookPhasePost
+	if err != nil {
+		change.Error = err.Error()
+	}
+	// post hooks are notifications, their failures don't fail the change.
+	for _, hook := range h.postHooks {
+		if hookErr := h.invoke(ctx, hook, change); hookErr != nil {
+			h.log.Error(hookErr, "route post hook failed",
+				"hook", hook.String(),
+				"virtualRouter", change.VirtualRouter.Namespace+"/"+change.VirtualRouter.Name,
+				"route", change.RouteName,
+			)
+		}
+	}
+	return err
+}
+
+func (h *routeHooks) invoke(ctx context.Context, hook routeHook, change RouteChange) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	h.log.V(1).Info("invoking route hook",
+		"hook", hook.String(),
+		"phase", change.Phase,
+		"operation", change.Operation,
+		"route", change.RouteName,
+	)
+	return hook.invoke(ctx, payload)
+}
@@ -0,0 +1,213 @@
+package virtualrouter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingRouteHook records the changes it's invoked with, and fails them with err.
+type recordingRouteHook struct {
+	name    string
+	err     error
+	changes []RouteChange
+}
+
+func (h *recordingRouteHook) invoke(ctx context.Context, payload []byte) error {
+	var change RouteChange
+	if err := json.Unmarshal(payload, &change); err != nil {
+		return err
+	}
+	h.changes = append(h.changes, change)
+	return h.err
+}
+
+func (h *recordingRouteHook) String() string {
+	return h.name
+}
+
+func Test_newRouteHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     routeHook
+		wantErr  string
+	}{
+		{
+			name:     "https URL",
+			endpoint: "https://approvals.example.com/routes",
+			want:     &httpRouteHook{url: "https://approvals.example.com/routes", httpClient: &http.Client{Timeout: time.Second}},
+		},
+		{
+			name:     "exec URL",
+			endpoint: "exec:///usr/local/bin/approve-route",
+			want:     &execRouteHook{path: "/usr/local/bin/approve-route", timeout: time.Second},
+		},
+		{
+			name:     "exec URL with relative path",
+			endpoint: "exec:approve-route",
+			wantErr:  "route hook exec:approve-route must specify the absolute path of an executable",
+		},
+		{
+			name:     "unsupported scheme",
+			endpoint: "ftp://example.com/hook",
+			wantErr:  "route hook ftp://example.com/hook must be an http, https or exec URL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newRouteHook(tt.endpoint, time.Second)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_httpRouteHook_invoke(t *testing.T) {
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		if r.URL.Path == "/reject" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("pending approval\n"))
+		}
+	}))
+	defer server.Close()
+
+	hook, err := newRouteHook(server.URL+"/accept", time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, hook.invoke(context.Background(), []byte(`{"phase":"pre"}`)))
+	assert.Equal(t, `{"phase":"pre"}`, string(gotBody))
+
+	hook, err = newRouteHook(server.URL+"/reject", time.Second)
+	assert.NoError(t, err)
+	assert.EqualError(t, hook.invoke(context.Background(), []byte(`{"phase":"pre"}`)), "403 Forbidden: pending approval")
+}
+
+func Test_execRouteHook_invoke(t *testing.T) {
+	dir := t.TempDir()
+	payloadFile := filepath.Join(dir, "payload.json")
+	acceptScript := filepath.Join(dir, "accept.sh")
+	assert.NoError(t, os.WriteFile(acceptScript, []byte("#!/bin/sh\ncat > "+payloadFile+"\n"), 0700))
+	rejectScript := filepath.Join(dir, "reject.sh")
+	assert.NoError(t, os.WriteFile(rejectScript, []byte("#!/bin/sh\necho change freeze in effect\nexit 3\n"), 0700))
+
+	hook := &execRouteHook{path: acceptScript, timeout: 5 * time.Second}
+	assert.NoError(t, hook.invoke(context.Background(), []byte(`{"phase":"pre"}`)))
+	payload, err := os.ReadFile(payloadFile)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"phase":"pre"}`, string(payload))
+
+	hook = &execRouteHook{path: rejectScript, timeout: 5 * time.Second}
+	assert.EqualError(t, hook.invoke(context.Background(), []byte(`{"phase":"pre"}`)), "exit status 3: change freeze in effect")
+}
+
+func Test_routeHooks_apply(t *testing.T) {
+	change := RouteChange{
+		Operation:     RouteChangeUpdate,
+		MeshName:      "my-mesh",
+		VirtualRouter: RouteChangeVirtualRouter{Namespace: "my-ns", Name: "my-vr", AWSName: "my-vr_my-ns"},
+		RouteName:     "my-route",
+		Diff:          "-weight: 100\n+weight: 50",
+	}
+	tests := []struct {
+		name          string
+		preHookErr    error
+		postHookErr   error
+		applyErr      error
+		wantApplied   bool
+		wantPostError string
+		wantErr       string
+		wantRejected  bool
+	}{
+		{
+			name:        "change accepted by pre hooks",
+			wantApplied: true,
+		},
+		{
+			name:         "change rejected by pre hook",
+			preHookErr:   errors.New("403 Forbidden: pending approval"),
+			wantErr:      "change of route my-route rejected by hook pre: 403 Forbidden: pending approval",
+			wantRejected: true,
+		},
+		{
+			name:          "failed change is notified to post hooks",
+			applyErr:      errors.New("ConflictException"),
+			wantApplied:   true,
+			wantPostError: "ConflictException",
+			wantErr:       "ConflictException",
+		},
+		{
+			name:        "post hook failure doesn't fail the change",
+			postHookErr: errors.New("connection refused"),
+			wantApplied: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preHook := &recordingRouteHook{name: "pre", err: tt.preHookErr}
+			postHook := &recordingRouteHook{name: "post", err: tt.postHookErr}
+			hooks := &routeHooks{
+				preHooks:      []routeHook{preHook},
+				postHooks:     []routeHook{postHook},
+				retryInterval: time.Minute,
+				log:           logr.Discard(),
+			}
+			applied := false
+			err := hooks.apply(context.Background(), change, func() error {
+				applied = true
+				return tt.applyErr
+			})
+			assert.Equal(t, tt.wantApplied, applied)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRejected, isRouteChangeRejectedError(err))
+			if tt.wantRejected {
+				var requeueAfterErr *runtime.RequeueAfterError
+				assert.True(t, errors.As(err, &requeueAfterErr))
+				assert.Equal(t, time.Minute, requeueAfterErr.Duration())
+			}
+
+			wantPreChange := change
+			wantPreChange.Phase = RouteHookPhasePre
+			assert.Equal(t, []RouteChange{wantPreChange}, preHook.changes)
+			if !tt.wantApplied {
+				assert.Empty(t, postHook.changes)
+				return
+			}
+			wantPostChange := change
+			wantPostChange.Phase = RouteHookPhasePost
+			wantPostChange.Error = tt.wantPostError
+			assert.Equal(t, []RouteChange{wantPostChange}, postHook.changes)
+		})
+	}
+}
+
+func Test_routeHooks_apply_nil(t *testing.T) {
+	var hooks *routeHooks
+	applied := false
+	assert.NoError(t, hooks.apply(context.Background(), RouteChange{}, func() error {
+		applied = true
+		return nil
+	}))
+	assert.True(t, applied)
+}
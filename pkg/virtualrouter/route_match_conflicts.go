@@ -0,0 +1,147 @@
+package virtualrouter
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+)
+
+// RouteMatchConflict is a route whose requests are matched by another route of the same protocol.
+type RouteMatchConflict struct {
+	// Route is the name of the route whose requests are matched by ConflictingRoute.
+	Route string
+	// ConflictingRoute is the name of the route matching requests of Route.
+	ConflictingRoute string
+	// Shadowed is true when ConflictingRoute has a higher priority and matches every request of Route, so Route never matches.
+	// Otherwise both routes have the same match without distinct priorities, and it's undefined which of them matches requests.
+	Shadowed bool
+}
+
+// DetectRouteMatchConflicts returns the conflicts among httpRoutes and among http2Routes of routes, in the order of routes.
+// Each route is reported in at most one conflict, preferably one shadowing it.
+// Routes matching a superset of the requests of another route, e.g. by prefix or regex, only shadow it if both routes have a priority,
+// since the order of routes without priority is up to AppMesh.
+func DetectRouteMatchConflicts(routes []appmesh.Route) []RouteMatchConflict {
+	var conflicts []RouteMatchConflict
+	for i, route := range routes {
+		match := httpRouteMatch(route)
+		if match == nil {
+			continue
+		}
+		var shadowedBy, ambiguousWith string
+		for j, other := range routes {
+			otherMatch := httpRouteMatch(other)
+			if i == j || otherMatch == nil || (route.HTTPRoute == nil) != (other.HTTPRoute == nil) {
+				continue
+			}
+			if hasHigherPriority(other, route) && httpRouteMatchCovers(*otherMatch, *match) {
+				shadowedBy = other.Name
+				break
+			}
+			if j < i && len(ambiguousWith) == 0 && reflect.DeepEqual(otherMatch, match) && !hasHigherPriority(route, other) {
+				ambiguousWith = other.Name
+			}
+		}
+		switch {
+		case len(shadowedBy) != 0:
+			conflicts = append(conflicts, RouteMatchConflict{Route: route.Name, ConflictingRoute: shadowedBy, Shadowed: true})
+		case len(ambiguousWith) != 0:
+			conflicts = append(conflicts, RouteMatchConflict{Route: route.Name, ConflictingRoute: ambiguousWith})
+		}
+	}
+	return conflicts
+}
+
+// httpRouteMatch returns the match of route if it's an httpRoute or http2Route.
+func httpRouteMatch(route appmesh.Route) *appmesh.HTTPRouteMatch {
+	switch {
+	case route.HTTPRoute != nil:
+		return &route.HTTPRoute.Match
+	case route.HTTP2Route != nil:
+		return &route.HTTP2Route.Match
+	}
+	return nil
+}
+
+// hasHigherPriority tests whether route and other route both have a priority, and route's is higher, i.e. a lower value.
+func hasHigherPriority(route appmesh.Route, other appmesh.Route) bool {
+	return route.Priority != nil && other.Priority != nil && *route.Priority < *other.Priority
+}
+
+// httpRouteMatchCovers tests whether every request matched by other match is matched by match.
+func httpRouteMatchCovers(match appmesh.HTTPRouteMatch, other appmesh.HTTPRouteMatch) bool {
+	if !httpPathCovers(match, other) {
+		return false
+	}
+	if match.Method != nil && !reflect.DeepEqual(match.Method, other.Method) {
+		return false
+	}
+	if match.Scheme != nil && !reflect.DeepEqual(match.Scheme, other.Scheme) {
+		return false
+	}
+	if match.Port != nil && !reflect.DeepEqual(match.Port, other.Port) {
+		return false
+	}
+	for _, header := range match.Headers {
+		if !containsHTTPRouteHeader(other.Headers, header) {
+			return false
+		}
+	}
+	for _, queryParameter := range match.QueryParameters {
+		if !containsHTTPQueryParameter(other.QueryParameters, queryParameter) {
+			return false
+		}
+	}
+	return true
+}
+
+// httpPathCovers tests whether every path matched by the prefix or path of other match is matched by match.
+func httpPathCovers(match appmesh.HTTPRouteMatch, other appmesh.HTTPRouteMatch) bool {
+	switch {
+	case match.Prefix != nil:
+		// every path starts with /, so the prefix / matches any path including regex ones.
+		if *match.Prefix == "/" {
+			return true
+		}
+		if other.Prefix != nil {
+			return strings.HasPrefix(*other.Prefix, *match.Prefix)
+		}
+		return other.Path != nil && other.Path.Exact != nil && strings.HasPrefix(*other.Path.Exact, *match.Prefix)
+	case match.Path != nil && match.Path.Exact != nil:
+		return other.Path != nil && reflect.DeepEqual(match.Path.Exact, other.Path.Exact)
+	case match.Path != nil && match.Path.Regex != nil:
+		if other.Path == nil {
+			return false
+		}
+		if other.Path.Regex != nil {
+			return *match.Path.Regex == *other.Path.Regex
+		}
+		if other.Path.Exact == nil {
+			return false
+		}
+		// Envoy matches regexes against the whole path.
+		re, err := regexp.Compile("^(?:" + *match.Path.Regex + ")$")
+		return err == nil && re.MatchString(*other.Path.Exact)
+	}
+	return false
+}
+
+func containsHTTPRouteHeader(headers []appmesh.HTTPRouteHeader, header appmesh.HTTPRouteHeader) bool {
+	for _, h := range headers {
+		if reflect.DeepEqual(h, header) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHTTPQueryParameter(queryParameters []appmesh.HTTPQueryParameters, queryParameter appmesh.HTTPQueryParameters) bool {
+	for _, q := range queryParameters {
+		if reflect.DeepEqual(q, queryParameter) {
+			return true
+		}
+	}
+	return false
+}
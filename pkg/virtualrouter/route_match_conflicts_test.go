@@ -0,0 +1,178 @@
+package virtualrouter
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectRouteMatchConflicts(t *testing.T) {
+	httpRoute := func(name string, priority *int64, match appmesh.HTTPRouteMatch) appmesh.Route {
+		return appmesh.Route{
+			Name:      name,
+			HTTPRoute: &appmesh.HTTPRoute{Match: match},
+			Priority:  priority,
+		}
+	}
+	http2Route := func(name string, priority *int64, match appmesh.HTTPRouteMatch) appmesh.Route {
+		return appmesh.Route{
+			Name:       name,
+			HTTP2Route: &appmesh.HTTPRoute{Match: match},
+			Priority:   priority,
+		}
+	}
+	prefix := func(prefix string) appmesh.HTTPRouteMatch {
+		return appmesh.HTTPRouteMatch{Prefix: aws.String(prefix)}
+	}
+	exact := func(path string) appmesh.HTTPRouteMatch {
+		return appmesh.HTTPRouteMatch{Path: &appmesh.HTTPPathMatch{Exact: aws.String(path)}}
+	}
+	regex := func(regex string) appmesh.HTTPRouteMatch {
+		return appmesh.HTTPRouteMatch{Path: &appmesh.HTTPPathMatch{Regex: aws.String(regex)}}
+	}
+	blueHeader := appmesh.HTTPRouteHeader{Name: "color", Match: &appmesh.HeaderMatchMethod{Exact: aws.String("blue")}}
+	tests := []struct {
+		name   string
+		routes []appmesh.Route
+		want   []RouteMatchConflict
+	}{
+		{
+			name: "distinct matches",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), prefix("/v1")),
+				httpRoute("route-2", aws.Int64(2), prefix("/v2")),
+				httpRoute("route-3", aws.Int64(3), exact("/v3/status")),
+			},
+		},
+		{
+			name: "identical match with different priorities",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(2), prefix("/v1")),
+				httpRoute("route-2", aws.Int64(1), prefix("/v1")),
+			},
+			want: []RouteMatchConflict{{Route: "route-1", ConflictingRoute: "route-2", Shadowed: true}},
+		},
+		{
+			name: "identical match without priorities",
+			routes: []appmesh.Route{
+				httpRoute("route-1", nil, exact("/v1")),
+				httpRoute("route-2", nil, exact("/v1")),
+			},
+			want: []RouteMatchConflict{{Route: "route-2", ConflictingRoute: "route-1"}},
+		},
+		{
+			name: "identical match with same priority",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), regex("/v1/.*")),
+				httpRoute("route-2", aws.Int64(1), regex("/v1/.*")),
+			},
+			want: []RouteMatchConflict{{Route: "route-2", ConflictingRoute: "route-1"}},
+		},
+		{
+			name: "regex subsumes exact match of lower priority",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), regex("/users/[a-z]+")),
+				httpRoute("route-2", aws.Int64(2), exact("/users/me")),
+			},
+			want: []RouteMatchConflict{{Route: "route-2", ConflictingRoute: "route-1", Shadowed: true}},
+		},
+		{
+			name: "regex matches exact path partially only",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), regex("/users")),
+				httpRoute("route-2", aws.Int64(2), exact("/users/me")),
+			},
+		},
+		{
+			name: "regex subsumes exact match of higher priority",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(2), regex("/users/[a-z]+")),
+				httpRoute("route-2", aws.Int64(1), exact("/users/me")),
+			},
+		},
+		{
+			name: "regex subsumes exact match without priorities",
+			routes: []appmesh.Route{
+				httpRoute("route-1", nil, regex("/users/[a-z]+")),
+				httpRoute("route-2", nil, exact("/users/me")),
+			},
+		},
+		{
+			name: "prefix subsumes prefix and exact match of lower priority",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), prefix("/users")),
+				httpRoute("route-2", aws.Int64(2), prefix("/users/admin")),
+				httpRoute("route-3", aws.Int64(3), exact("/users/me")),
+				httpRoute("route-4", aws.Int64(4), regex("/users/.*")),
+			},
+			want: []RouteMatchConflict{
+				{Route: "route-2", ConflictingRoute: "route-1", Shadowed: true},
+				{Route: "route-3", ConflictingRoute: "route-1", Shadowed: true},
+			},
+		},
+		{
+			name: "prefix / subsumes regex match of lower priority",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), prefix("/")),
+				httpRoute("route-2", aws.Int64(2), regex("/users/.*")),
+			},
+			want: []RouteMatchConflict{{Route: "route-2", ConflictingRoute: "route-1", Shadowed: true}},
+		},
+		{
+			name: "more specific header match isn't shadowed",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Headers: []appmesh.HTTPRouteHeader{blueHeader}}),
+				httpRoute("route-2", aws.Int64(2), prefix("/")),
+			},
+		},
+		{
+			name: "less specific header match shadows",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), prefix("/")),
+				httpRoute("route-2", aws.Int64(2), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Headers: []appmesh.HTTPRouteHeader{blueHeader}}),
+			},
+			want: []RouteMatchConflict{{Route: "route-2", ConflictingRoute: "route-1", Shadowed: true}},
+		},
+		{
+			name: "different methods",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Method: aws.String("GET")}),
+				httpRoute("route-2", aws.Int64(2), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Method: aws.String("POST")}),
+			},
+		},
+		{
+			name: "different ports",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Port: aws.Int64(8080)}),
+				httpRoute("route-2", aws.Int64(2), appmesh.HTTPRouteMatch{Prefix: aws.String("/"), Port: aws.Int64(9090)}),
+			},
+		},
+		{
+			name: "different protocols",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), prefix("/")),
+				http2Route("route-2", aws.Int64(2), prefix("/")),
+			},
+		},
+		{
+			name: "shadowing preferred over identical match",
+			routes: []appmesh.Route{
+				httpRoute("route-1", nil, prefix("/v1")),
+				httpRoute("route-2", aws.Int64(2), prefix("/v1")),
+				http2Route("route-3", aws.Int64(1), prefix("/")),
+				httpRoute("route-4", aws.Int64(1), prefix("/")),
+			},
+			want: []RouteMatchConflict{
+				{Route: "route-2", ConflictingRoute: "route-4", Shadowed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectRouteMatchConflicts(tt.routes)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
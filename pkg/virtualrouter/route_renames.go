@@ -0,0 +1,76 @@
+package virtualrouter
+
+import (
+	"reflect"
+	"sort"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+)
+
+const (
+	// AllowRouteRenameAnnotation allows VirtualRouter updates renaming routes when set to "true".
+	// AppMesh routes can't be renamed in place, renamed routes are created before the old routes are deleted,
+	// with a priority shadowing the old routes until they're deleted.
+	AllowRouteRenameAnnotation = "appmesh.k8s.aws/allow-route-rename"
+)
+
+// RouteRename is a route removed by an update along with the route added in its place with the same match.
+type RouteRename struct {
+	OldName string
+	NewName string
+}
+
+// IsRouteRenameAllowed tests whether vr allows renaming routes.
+func IsRouteRenameAllowed(vr *appmesh.VirtualRouter) bool {
+	return vr.Annotations[AllowRouteRenameAnnotation] == "true"
+}
+
+// DetectRouteRenames returns the routes of oldRoutes that are renamed in routes, sorted by old name.
+// A route is renamed when it's removed and a route of the same protocol and match is added.
+func DetectRouteRenames(routes []appmesh.Route, oldRoutes []appmesh.Route) []RouteRename {
+	routeNames := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		routeNames[route.Name] = true
+	}
+	oldRouteNames := make(map[string]bool, len(oldRoutes))
+	for _, oldRoute := range oldRoutes {
+		oldRouteNames[oldRoute.Name] = true
+	}
+
+	var renames []RouteRename
+	renamedTo := make(map[string]bool)
+	for _, oldRoute := range oldRoutes {
+		if routeNames[oldRoute.Name] {
+			continue
+		}
+		for _, route := range routes {
+			if oldRouteNames[route.Name] || renamedTo[route.Name] {
+				continue
+			}
+			if routeMatchEqual(route, oldRoute) {
+				renames = append(renames, RouteRename{OldName: oldRoute.Name, NewName: route.Name})
+				renamedTo[route.Name] = true
+				break
+			}
+		}
+	}
+	sort.Slice(renames, func(i, j int) bool {
+		return renames[i].OldName < renames[j].OldName
+	})
+	return renames
+}
+
+// routeMatchEqual tests whether route and other route are of the same protocol and match.
+func routeMatchEqual(route appmesh.Route, other appmesh.Route) bool {
+	switch {
+	case route.GRPCRoute != nil:
+		return other.GRPCRoute != nil && reflect.DeepEqual(route.GRPCRoute.Match, other.GRPCRoute.Match)
+	case route.HTTP2Route != nil:
+		return other.HTTP2Route != nil && reflect.DeepEqual(route.HTTP2Route.Match, other.HTTP2Route.Match)
+	case route.HTTPRoute != nil:
+		return other.HTTPRoute != nil && reflect.DeepEqual(route.HTTPRoute.Match, other.HTTPRoute.Match)
+	case route.TCPRoute != nil:
+		return other.TCPRoute != nil && reflect.DeepEqual(route.TCPRoute.Match, other.TCPRoute.Match)
+	}
+	return false
+}
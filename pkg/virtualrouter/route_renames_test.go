@@ -0,0 +1,81 @@
+package virtualrouter
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectRouteRenames(t *testing.T) {
+	httpRoute := func(name string, prefix string) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{Prefix: aws.String(prefix)},
+			},
+		}
+	}
+	http2Route := func(name string, prefix string) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTP2Route: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{Prefix: aws.String(prefix)},
+			},
+		}
+	}
+	tcpRoute := func(name string, port int64) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			TCPRoute: &appmesh.TCPRoute{
+				Match: &appmesh.TCPRouteMatch{Port: aws.Int64(port)},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		routes    []appmesh.Route
+		oldRoutes []appmesh.Route
+		want      []RouteRename
+	}{
+		{
+			name:      "routes unchanged",
+			routes:    []appmesh.Route{httpRoute("route-1", "/")},
+			oldRoutes: []appmesh.Route{httpRoute("route-1", "/")},
+		},
+		{
+			name:      "route renamed",
+			routes:    []appmesh.Route{httpRoute("route-1", "/"), tcpRoute("route-3", 8080)},
+			oldRoutes: []appmesh.Route{httpRoute("route-1", "/"), tcpRoute("route-2", 8080)},
+			want:      []RouteRename{{OldName: "route-2", NewName: "route-3"}},
+		},
+		{
+			name:      "route replaced with different match",
+			routes:    []appmesh.Route{httpRoute("route-2", "/v2")},
+			oldRoutes: []appmesh.Route{httpRoute("route-1", "/")},
+		},
+		{
+			name:      "route replaced with different protocol",
+			routes:    []appmesh.Route{http2Route("route-2", "/")},
+			oldRoutes: []appmesh.Route{httpRoute("route-1", "/")},
+		},
+		{
+			name:      "route match moved to an existing route",
+			routes:    []appmesh.Route{httpRoute("route-2", "/")},
+			oldRoutes: []appmesh.Route{httpRoute("route-1", "/"), httpRoute("route-2", "/v2")},
+		},
+		{
+			name:      "removed routes sharing a match are renamed once",
+			routes:    []appmesh.Route{httpRoute("route-3", "/")},
+			oldRoutes: []appmesh.Route{httpRoute("route-2", "/"), httpRoute("route-1", "/")},
+			want:      []RouteRename{{OldName: "route-2", NewName: "route-3"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectRouteRenames(tt.routes, tt.oldRoutes)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,62 +2,114 @@ package virtualrouter
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tracing"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// maxRoutePriority is the lowest priority of routes, routes without priority are evaluated after it.
+const maxRoutePriority = 1000
+
+// listRoutesPageRetryInterval is the interval between retries of a ListRoutes page, multiplied by the attempt.
+const listRoutesPageRetryInterval = time.Second
+
+// routesPartiallyListedError indicates some pages of routes of a virtualRouter failed to be listed after retries.
+type routesPartiallyListedError struct {
+	err error
+}
+
+func (e *routesPartiallyListedError) Error() string {
+	return fmt.Sprintf("failed to list all routes: %v", e.err)
+}
+
+func (e *routesPartiallyListedError) Unwrap() error {
+	return e.err
+}
+
+// isRoutesPartiallyListedError tests whether err indicates some routes of a virtualRouter failed to be listed.
+func isRoutesPartiallyListedError(err error) bool {
+	var partialErr *routesPartiallyListedError
+	return errors.As(err, &partialErr)
+}
+
 // routesManager is responsible for manage routes for virtualRouter.
 type routesManager interface {
 	// create will create routes on AppMesh virtualRouter to match k8s virtualRouter spec.
 	create(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, vnByRefHash map[types.NamespacedName]*appmesh.VirtualNode) (map[string]*appmeshsdk.RouteData, error)
 	// remove will remove old routes on AppMesh virtualRouter to match k8s virtualRouter spec.
+	// only routes listed are removed if routes are partially listed.
 	remove(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) error
 	// update will update routes on AppMesh virtualRouter to match k8s virtualRouter spec.
+	// if routes are partially listed, routes in spec are still created or updated, while a routesPartiallyListedError is returned along with them.
 	update(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, vnByRefHash map[types.NamespacedName]*appmesh.VirtualNode) (map[string]*appmeshsdk.RouteData, error)
 	// cleanup will cleanup routes on AppMesh virtualRouter
 	cleanup(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) error
+	// disruptiveChanges will describe routes on AppMesh virtualRouter to be deleted or recreated to match k8s virtualRouter spec.
+	disruptiveChanges(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) ([]string, error)
+	// diff will compare routes on AppMesh virtualRouter against k8s virtualRouter spec, sdkVR is nil if it doesn't exist.
+	diff(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter, vnByRefHash map[types.NamespacedName]*appmesh.VirtualNode) ([]equality.SpecDiff, error)
 }
 
 // newDefaultRoutesManager constructs new routesManager
-func newDefaultRoutesManager(appMeshSDK services.AppMesh, log logr.Logger) routesManager {
+func newDefaultRoutesManager(appMeshSDK services.AppMesh, tagsManager tagging.Manager, config Config, log logr.Logger) routesManager {
 	return &defaultRoutesManager{
-		appMeshSDK: appMeshSDK,
-		log:        log,
+		appMeshSDK:        appMeshSDK,
+		tagsManager:       tagsManager,
+		config:            config,
+		hooks:             newRouteHooks(config, log),
+		pageRetryInterval: listRoutesPageRetryInterval,
+		log:               log,
 	}
 }
 
 type defaultRoutesManager struct {
-	appMeshSDK services.AppMesh
-	log        logr.Logger
+	appMeshSDK        services.AppMesh
+	tagsManager       tagging.Manager
+	config            Config
+	hooks             *routeHooks
+	pageRetryInterval time.Duration
+	log               logr.Logger
 }
 
 func (m *defaultRoutesManager) create(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) (map[string]*appmeshsdk.RouteData, error) {
-	return m.reconcile(ctx, ms, vr, vnByKey, vr.Spec.Routes, nil)
+	return m.reconcile(ctx, ms, vr, vnByKey, ExpandTCPRoutePorts(vr.Spec.Routes), nil)
 }
 
-func (m *defaultRoutesManager) remove(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) error {
+func (m *defaultRoutesManager) remove(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) (err error) {
+	ctx, span := startRoutesSpan(ctx, "routesManager.remove", vr)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	sdkRouteRefs, err := m.listSDKRouteRefs(ctx, ms, vr)
-	if err != nil {
+	if err != nil && !isRoutesPartiallyListedError(err) {
 		return err
 	}
-	// Only reconcile routes which need to be removed before we remove the corresponding listener
-	taintedRefs := taintedSDKRouteRefs(vr.Spec.Routes, sdkVR, sdkRouteRefs)
+	// Only reconcile routes which need to be removed before we remove the corresponding listener,
+	// other routes removed from spec are deleted once their replacement routes are created.
+	taintedRefs := taintedSDKRouteRefs(ExpandTCPRoutePorts(vr.Spec.Routes), vr.Spec.Listeners, sdkVR, sdkRouteRefs)
 	for _, sdkRouteRef := range taintedRefs {
-		if err = m.deleteSDKRouteByRef(ctx, sdkRouteRef); err != nil {
+		span.AddEvent("deleteRoute", trace.WithAttributes(tracing.AttributeRouteName.String(aws.StringValue(sdkRouteRef.RouteName))))
+		if err = m.deleteSDKRouteByRef(ctx, vr, sdkRouteRef); err != nil {
 			return err
 		}
 	}
@@ -65,11 +117,24 @@ func (m *defaultRoutesManager) remove(ctx context.Context, ms *appmesh.Mesh, sdk
 }
 
 func (m *defaultRoutesManager) update(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) (map[string]*appmeshsdk.RouteData, error) {
-	sdkRouteRefs, err := m.listSDKRouteRefs(ctx, ms, vr)
+	routes := ExpandTCPRoutePorts(vr.Spec.Routes)
+	sdkRouteRefs, listErr := m.listSDKRouteRefs(ctx, ms, vr)
+	if listErr != nil {
+		if !isRoutesPartiallyListedError(listErr) {
+			return nil, listErr
+		}
+		// routes in spec may be on pages failed to be listed, they're looked up by name instead of being created again.
+		unlistedSDKRouteRefs, err := m.findUnlistedSDKRouteRefs(ctx, ms, vr, routes, sdkRouteRefs)
+		if err != nil {
+			return nil, err
+		}
+		sdkRouteRefs = append(sdkRouteRefs, unlistedSDKRouteRefs...)
+	}
+	sdkRouteByName, err := m.reconcile(ctx, ms, vr, vnByKey, routes, sdkRouteRefs)
 	if err != nil {
 		return nil, err
 	}
-	return m.reconcile(ctx, ms, vr, vnByKey, vr.Spec.Routes, sdkRouteRefs)
+	return sdkRouteByName, listErr
 }
 
 func (m *defaultRoutesManager) cleanup(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) error {
@@ -81,24 +146,123 @@ func (m *defaultRoutesManager) cleanup(ctx context.Context, ms *appmesh.Mesh, vr
 	return err
 }
 
+func (m *defaultRoutesManager) disruptiveChanges(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter) ([]string, error) {
+	sdkRouteRefs, err := m.listSDKRouteRefs(ctx, ms, vr)
+	if err != nil && !isRoutesPartiallyListedError(err) {
+		return nil, err
+	}
+	routes := ExpandTCPRoutePorts(vr.Spec.Routes)
+	_, _, unmatchedSDKRouteRefs := matchRoutesAgainstSDKRouteRefs(routes, sdkRouteRefs)
+	deletedNameSet := sets.NewString()
+	var changes []string
+	for _, sdkRouteRef := range unmatchedSDKRouteRefs {
+		deletedNameSet.Insert(aws.StringValue(sdkRouteRef.RouteName))
+		changes = append(changes, fmt.Sprintf("deletion of route %s", aws.StringValue(sdkRouteRef.RouteName)))
+	}
+	// routes matched by spec are recreated if their listener changes protocol.
+	for _, sdkRouteRef := range taintedSDKRouteRefs(routes, vr.Spec.Listeners, sdkVR, sdkRouteRefs) {
+		if !deletedNameSet.Has(aws.StringValue(sdkRouteRef.RouteName)) {
+			changes = append(changes, fmt.Sprintf("recreation of route %s for listener protocol change", aws.StringValue(sdkRouteRef.RouteName)))
+		}
+	}
+	return changes, nil
+}
+
+func (m *defaultRoutesManager) diff(ctx context.Context, ms *appmesh.Mesh, sdkVR *appmeshsdk.VirtualRouterData, vr *appmesh.VirtualRouter, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) ([]equality.SpecDiff, error) {
+	var sdkRouteRefs []*appmeshsdk.RouteRef
+	if sdkVR != nil {
+		var err error
+		if sdkRouteRefs, err = m.listSDKRouteRefs(ctx, ms, vr); err != nil {
+			return nil, err
+		}
+	}
+	matchedRouteAndSDKRouteRefs, unmatchedRoutes, unmatchedSDKRouteRefs := matchRoutesAgainstSDKRouteRefs(ExpandTCPRoutePorts(vr.Spec.Routes), sdkRouteRefs)
+	opts := equality.CompareOptionForRouteSpec()
+	diffs := make([]equality.SpecDiff, 0, len(matchedRouteAndSDKRouteRefs)+len(unmatchedRoutes)+len(unmatchedSDKRouteRefs))
+
+	for _, routeAndSDKRouteRef := range matchedRouteAndSDKRouteRefs {
+		route := routeAndSDKRouteRef.route
+		desiredSDKRouteSpec, err := BuildSDKRouteSpec(vr, route, vnByKey)
+		if err != nil {
+			return nil, err
+		}
+		sdkRoute, err := m.findSDKRoute(ctx, routeAndSDKRouteRef.sdkRouteRef)
+		if err != nil {
+			return nil, err
+		}
+		var actualSDKRouteSpec *appmeshsdk.RouteSpec
+		if sdkRoute != nil {
+			actualSDKRouteSpec = sdkRoute.Spec
+		}
+		diffs = append(diffs, equality.DiffSpec("route/"+route.Name, desiredSDKRouteSpec, actualSDKRouteSpec, sdkRoute != nil, opts))
+	}
+
+	for _, route := range unmatchedRoutes {
+		desiredSDKRouteSpec, err := BuildSDKRouteSpec(vr, route, vnByKey)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, equality.DiffSpec("route/"+route.Name, desiredSDKRouteSpec, (*appmeshsdk.RouteSpec)(nil), false, opts))
+	}
+
+	// routes not in k8s virtualRouter spec are desired to be deleted.
+	for _, sdkRouteRef := range unmatchedSDKRouteRefs {
+		sdkRoute, err := m.findSDKRoute(ctx, sdkRouteRef)
+		if err != nil {
+			return nil, err
+		}
+		if sdkRoute == nil {
+			continue
+		}
+		diffs = append(diffs, equality.DiffSpec("route/"+aws.StringValue(sdkRouteRef.RouteName), (*appmeshsdk.RouteSpec)(nil), sdkRoute.Spec, true, opts))
+	}
+	return diffs, nil
+}
+
 // reconcile will make AppMesh routes(sdkRouteRefs) matches routes.
 func (m *defaultRoutesManager) reconcile(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, vnByKey map[types.NamespacedName]*appmesh.VirtualNode,
-	routes []appmesh.Route, sdkRouteRefs []*appmeshsdk.RouteRef) (map[string]*appmeshsdk.RouteData, error) {
+	routes []appmesh.Route, sdkRouteRefs []*appmeshsdk.RouteRef) (_ map[string]*appmeshsdk.RouteData, err error) {
+	ctx, span := startRoutesSpan(ctx, "routesManager.reconcile", vr)
+	defer func() { tracing.EndSpan(span, err) }()
 
 	matchedRouteAndSDKRouteRefs, unmatchedRoutes, unmatchedSDKRouteRefs := matchRoutesAgainstSDKRouteRefs(routes, sdkRouteRefs)
+	span.SetAttributes(
+		attribute.Int("routes.created", len(unmatchedRoutes)),
+		attribute.Int("routes.matched", len(matchedRouteAndSDKRouteRefs)),
+		attribute.Int("routes.deleted", len(unmatchedSDKRouteRefs)),
+	)
 	sdkRouteByName := make(map[string]*appmeshsdk.RouteData, len(matchedRouteAndSDKRouteRefs)+len(unmatchedRoutes))
 
+	// routes are deleted only after new routes are created, so traffic keeps matching routes during the change.
+	sdkRoutesToDelete := make([]*appmeshsdk.RouteData, 0, len(unmatchedSDKRouteRefs))
+	for _, sdkRouteRef := range unmatchedSDKRouteRefs {
+		sdkRoute, err := m.findSDKRoute(ctx, sdkRouteRef)
+		if err != nil {
+			return nil, err
+		}
+		if sdkRoute == nil {
+			return nil, errors.Errorf("route not found: %v", aws.StringValue(sdkRouteRef.RouteName))
+		}
+		sdkRoutesToDelete = append(sdkRoutesToDelete, sdkRoute)
+	}
+
+	var shadowingRoutes []appmesh.Route
 	for _, route := range unmatchedRoutes {
-		sdkRoute, err := m.createSDKRoute(ctx, ms, vr, route, vnByKey)
+		span.AddEvent("createRoute", trace.WithAttributes(tracing.AttributeRouteName.String(route.Name)))
+		sdkRoute, shadowing, err := m.createSDKRoute(ctx, ms, vr, route, vnByKey, sdkRoutesToDelete)
 		if err != nil {
 			return nil, err
 		}
+		if shadowing {
+			shadowingRoutes = append(shadowingRoutes, route)
+		}
 		sdkRouteByName[route.Name] = sdkRoute
 	}
 
 	for _, routeAndSDKRouteRef := range matchedRouteAndSDKRouteRefs {
 		route := routeAndSDKRouteRef.route
 		sdkRouteRef := routeAndSDKRouteRef.sdkRouteRef
+		span.AddEvent("updateRoute", trace.WithAttributes(tracing.AttributeRouteName.String(route.Name)))
 		sdkRoute, err := m.findSDKRoute(ctx, sdkRouteRef)
 		if err != nil {
 			return nil, err
@@ -110,37 +274,108 @@ func (m *defaultRoutesManager) reconcile(ctx context.Context, ms *appmesh.Mesh,
 		if err != nil {
 			return nil, err
 		}
+		// routes carry tags of their virtualRouter.
+		if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkRoute.Metadata.Arn), vr); err != nil {
+			return nil, errors.Wrapf(err, "failed to reconcile tags of route %v", route.Name)
+		}
 		sdkRouteByName[route.Name] = sdkRoute
 	}
 
-	for _, sdkRouteRef := range unmatchedSDKRouteRefs {
-		sdkRoute, err := m.findSDKRoute(ctx, sdkRouteRef)
-		if err != nil {
+	for _, sdkRoute := range sdkRoutesToDelete {
+		span.AddEvent("deleteRoute", trace.WithAttributes(tracing.AttributeRouteName.String(aws.StringValue(sdkRoute.RouteName))))
+		if err = m.deleteSDKRoute(ctx, vr, sdkRoute); err != nil {
 			return nil, err
 		}
-		if sdkRoute == nil {
-			return nil, errors.Errorf("route not found: %v", aws.StringValue(sdkRouteRef.RouteName))
-		}
-		if err = m.deleteSDKRoute(ctx, sdkRoute); err != nil {
+	}
+
+	// routes shadowing deleted routes are restored to their desired priority.
+	for _, route := range shadowingRoutes {
+		span.AddEvent("updateRoute", trace.WithAttributes(tracing.AttributeRouteName.String(route.Name)))
+		sdkRoute, err := m.updateSDKRoute(ctx, sdkRouteByName[route.Name], vr, route, vnByKey)
+		if err != nil {
 			return nil, err
 		}
+		sdkRouteByName[route.Name] = sdkRoute
 	}
 	return sdkRouteByName, nil
 }
 
+// startRoutesSpan starts a span named name for managing routes of vr.
+func startRoutesSpan(ctx context.Context, name string, vr *appmesh.VirtualRouter) (context.Context, trace.Span) {
+	return tracing.StartSpan(ctx, name,
+		tracing.AttributeNamespace.String(vr.Namespace),
+		tracing.AttributeName.String(vr.Name),
+	)
+}
+
 func (m *defaultRoutesManager) listSDKRouteRefs(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter) ([]*appmeshsdk.RouteRef, error) {
-	var sdkRouteRefs []*appmeshsdk.RouteRef
-	if err := m.appMeshSDK.ListRoutesPagesWithContext(ctx, &appmeshsdk.ListRoutesInput{
+	input := &appmeshsdk.ListRoutesInput{
 		MeshName:          ms.Spec.AWSName,
 		MeshOwner:         ms.Spec.MeshOwner,
 		VirtualRouterName: vr.Spec.AWSName,
-	}, func(output *appmeshsdk.ListRoutesOutput, b bool) bool {
-		sdkRouteRefs = append(sdkRouteRefs, output.Routes...)
-		return true
-	}); err != nil {
-		return nil, err
 	}
-	return sdkRouteRefs, nil
+	if m.config.ListRoutesPageLimit > 0 {
+		input.Limit = aws.Int64(m.config.ListRoutesPageLimit)
+	}
+	var sdkRouteRefs []*appmeshsdk.RouteRef
+	failures := 0
+	for {
+		// listing resumes from the page that failed to be listed.
+		err := m.appMeshSDK.ListRoutesPagesWithContext(ctx, input, func(output *appmeshsdk.ListRoutesOutput, b bool) bool {
+			sdkRouteRefs = append(sdkRouteRefs, output.Routes...)
+			input.NextToken = output.NextToken
+			failures = 0
+			return true
+		})
+		if err == nil {
+			return sdkRouteRefs, nil
+		}
+		failures++
+		if failures > m.config.ListRoutesPageRetries || ctx.Err() != nil {
+			if len(sdkRouteRefs) == 0 {
+				return nil, err
+			}
+			return sdkRouteRefs, &routesPartiallyListedError{err: err}
+		}
+		m.log.V(1).Info("retrying to list routes",
+			"virtualRouter", k8s.NamespacedName(vr),
+			"attempt", failures,
+			"error", err.Error(),
+		)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(failures) * m.pageRetryInterval):
+		}
+	}
+}
+
+// findUnlistedSDKRouteRefs finds routes that are not in sdkRouteRefs by their names.
+func (m *defaultRoutesManager) findUnlistedSDKRouteRefs(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter,
+	routes []appmesh.Route, sdkRouteRefs []*appmeshsdk.RouteRef) ([]*appmeshsdk.RouteRef, error) {
+	listedRouteNames := sets.NewString()
+	for _, sdkRouteRef := range sdkRouteRefs {
+		listedRouteNames.Insert(aws.StringValue(sdkRouteRef.RouteName))
+	}
+	var unlistedSDKRouteRefs []*appmeshsdk.RouteRef
+	for _, route := range routes {
+		if listedRouteNames.Has(route.Name) {
+			continue
+		}
+		sdkRouteRef := &appmeshsdk.RouteRef{
+			MeshName:          ms.Spec.AWSName,
+			MeshOwner:         ms.Spec.MeshOwner,
+			VirtualRouterName: vr.Spec.AWSName,
+			RouteName:         aws.String(route.Name),
+		}
+		sdkRoute, err := m.findSDKRoute(ctx, sdkRouteRef)
+		if err != nil {
+			return nil, err
+		}
+		if sdkRoute != nil {
+			unlistedSDKRouteRefs = append(unlistedSDKRouteRefs, sdkRouteRef)
+		}
+	}
+	return unlistedSDKRouteRefs, nil
 }
 
 func (m *defaultRoutesManager) findSDKRoute(ctx context.Context, sdkRouteRef *appmeshsdk.RouteRef) (*appmeshsdk.RouteData, error) {
@@ -159,23 +394,80 @@ func (m *defaultRoutesManager) findSDKRoute(ctx context.Context, sdkRouteRef *ap
 	return resp.Route, nil
 }
 
-func (m *defaultRoutesManager) createSDKRoute(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, route appmesh.Route, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) (*appmeshsdk.RouteData, error) {
+// createSDKRoute creates route, shadowing sdkRoutesToDelete with the same match until they're deleted.
+// returns whether the created route shadows any of sdkRoutesToDelete with a priority other than its desired one.
+func (m *defaultRoutesManager) createSDKRoute(ctx context.Context, ms *appmesh.Mesh, vr *appmesh.VirtualRouter, route appmesh.Route,
+	vnByKey map[types.NamespacedName]*appmesh.VirtualNode, sdkRoutesToDelete []*appmeshsdk.RouteData) (*appmeshsdk.RouteData, bool, error) {
 	sdkRouteSpec, err := BuildSDKRouteSpec(vr, route, vnByKey)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-
-	resp, err := m.appMeshSDK.CreateRouteWithContext(ctx, &appmeshsdk.CreateRouteInput{
-		MeshName:          ms.Spec.AWSName,
-		MeshOwner:         ms.Spec.MeshOwner,
-		VirtualRouterName: vr.Spec.AWSName,
-		RouteName:         aws.String(route.Name),
-		Spec:              sdkRouteSpec,
+	shadowing := applyShadowingPriority(sdkRouteSpec, sdkRoutesToDelete)
+
+	opts := equality.CompareOptionForRouteSpec()
+	change := buildRouteChange(RouteChangeCreate, aws.StringValue(ms.Spec.AWSName), vr, route.Name, sdkRouteSpec, nil, cmp.Diff(sdkRouteSpec, (*appmeshsdk.RouteSpec)(nil), opts))
+	var resp *appmeshsdk.CreateRouteOutput
+	err = m.hooks.apply(ctx, change, func() error {
+		resp, err = m.appMeshSDK.CreateRouteWithContext(ctx, &appmeshsdk.CreateRouteInput{
+			MeshName:          ms.Spec.AWSName,
+			MeshOwner:         ms.Spec.MeshOwner,
+			VirtualRouterName: vr.Spec.AWSName,
+			RouteName:         aws.String(route.Name),
+			Spec:              sdkRouteSpec,
+			Tags:              m.tagsManager.BuildTags(vr),
+		})
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, false, runtime.ClassifyAWSError(err)
 	}
-	return resp.Route, nil
+	return resp.Route, shadowing, nil
+}
+
+// applyShadowingPriority raises the priority of sdkRouteSpec above sdkRoutes with the same match,
+// so that traffic matching them is routed per sdkRouteSpec before they're deleted.
+// returns whether the priority of sdkRouteSpec is changed.
+func applyShadowingPriority(sdkRouteSpec *appmeshsdk.RouteSpec, sdkRoutes []*appmeshsdk.RouteData) bool {
+	match := sdkRouteMatch(sdkRouteSpec)
+	if match == "" {
+		return false
+	}
+	shadowing := false
+	for _, sdkRoute := range sdkRoutes {
+		if sdkRoute.Spec == nil || sdkRouteMatch(sdkRoute.Spec) != match {
+			continue
+		}
+		// lower values take precedence, routes without priority are evaluated last.
+		priority := aws.Int64Value(sdkRoute.Spec.Priority) - 1
+		if sdkRoute.Spec.Priority == nil {
+			priority = maxRoutePriority
+		}
+		if priority < 0 {
+			priority = 0
+		}
+		if sdkRouteSpec.Priority == nil || aws.Int64Value(sdkRouteSpec.Priority) > priority {
+			sdkRouteSpec.Priority = aws.Int64(priority)
+			shadowing = true
+		}
+	}
+	return shadowing
+}
+
+// sdkRouteMatch returns the protocol and match of sdkRouteSpec for comparison with other routes.
+func sdkRouteMatch(sdkRouteSpec *appmeshsdk.RouteSpec) string {
+	switch {
+	case sdkRouteSpec.GrpcRoute != nil && sdkRouteSpec.GrpcRoute.Match != nil:
+		return "grpc/" + sdkRouteSpec.GrpcRoute.Match.String()
+	case sdkRouteSpec.Http2Route != nil && sdkRouteSpec.Http2Route.Match != nil:
+		return "http2/" + sdkRouteSpec.Http2Route.Match.String()
+	case sdkRouteSpec.HttpRoute != nil && sdkRouteSpec.HttpRoute.Match != nil:
+		return "http/" + sdkRouteSpec.HttpRoute.Match.String()
+	case sdkRouteSpec.TcpRoute != nil && sdkRouteSpec.TcpRoute.Match != nil:
+		return "tcp/" + sdkRouteSpec.TcpRoute.Match.String()
+	case sdkRouteSpec.TcpRoute != nil:
+		return "tcp/"
+	}
+	return ""
 }
 
 func (m *defaultRoutesManager) updateSDKRoute(ctx context.Context, sdkRoute *appmeshsdk.RouteData, vr *appmesh.VirtualRouter, route appmesh.Route, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) (*appmeshsdk.RouteData, error) {
@@ -185,7 +477,7 @@ func (m *defaultRoutesManager) updateSDKRoute(ctx context.Context, sdkRoute *app
 		return nil, err
 	}
 
-	opts := cmpopts.EquateEmpty()
+	opts := equality.CompareOptionForRouteSpec()
 	if cmp.Equal(desiredSDKRouteSpec, actualSDKRouteSpec, opts) {
 		return sdkRoute, nil
 	}
@@ -197,25 +489,35 @@ func (m *defaultRoutesManager) updateSDKRoute(ctx context.Context, sdkRoute *app
 		"desiredSDKRouteSpec", desiredSDKRouteSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateRouteWithContext(ctx, &appmeshsdk.UpdateRouteInput{
-		MeshName:          sdkRoute.MeshName,
-		MeshOwner:         sdkRoute.Metadata.MeshOwner,
-		VirtualRouterName: sdkRoute.VirtualRouterName,
-		RouteName:         sdkRoute.RouteName,
-		Spec:              desiredSDKRouteSpec,
+	change := buildRouteChange(RouteChangeUpdate, aws.StringValue(sdkRoute.MeshName), vr, route.Name, desiredSDKRouteSpec, actualSDKRouteSpec, diff)
+	var resp *appmeshsdk.UpdateRouteOutput
+	err = m.hooks.apply(ctx, change, func() error {
+		resp, err = m.appMeshSDK.UpdateRouteWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateRouteInput{
+			MeshName:          sdkRoute.MeshName,
+			MeshOwner:         sdkRoute.Metadata.MeshOwner,
+			VirtualRouterName: sdkRoute.VirtualRouterName,
+			RouteName:         sdkRoute.RouteName,
+			Spec:              desiredSDKRouteSpec,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, runtime.ClassifyAWSError(err)
 	}
 	return resp.Route, nil
 }
 
-func (m *defaultRoutesManager) deleteSDKRoute(ctx context.Context, sdkRoute *appmeshsdk.RouteData) error {
-	_, err := m.appMeshSDK.DeleteRouteWithContext(ctx, &appmeshsdk.DeleteRouteInput{
-		MeshName:          sdkRoute.MeshName,
-		MeshOwner:         sdkRoute.Metadata.MeshOwner,
-		VirtualRouterName: sdkRoute.VirtualRouterName,
-		RouteName:         sdkRoute.RouteName,
+func (m *defaultRoutesManager) deleteSDKRoute(ctx context.Context, vr *appmesh.VirtualRouter, sdkRoute *appmeshsdk.RouteData) error {
+	opts := equality.CompareOptionForRouteSpec()
+	change := buildRouteChange(RouteChangeDelete, aws.StringValue(sdkRoute.MeshName), vr, aws.StringValue(sdkRoute.RouteName), nil, sdkRoute.Spec, cmp.Diff((*appmeshsdk.RouteSpec)(nil), sdkRoute.Spec, opts))
+	err := m.hooks.apply(ctx, change, func() error {
+		_, err := m.appMeshSDK.DeleteRouteWithContext(ctx, &appmeshsdk.DeleteRouteInput{
+			MeshName:          sdkRoute.MeshName,
+			MeshOwner:         sdkRoute.Metadata.MeshOwner,
+			VirtualRouterName: sdkRoute.VirtualRouterName,
+			RouteName:         sdkRoute.RouteName,
+		})
+		return err
 	})
 	if err != nil {
 		return err
@@ -223,12 +525,17 @@ func (m *defaultRoutesManager) deleteSDKRoute(ctx context.Context, sdkRoute *app
 	return nil
 }
 
-func (m *defaultRoutesManager) deleteSDKRouteByRef(ctx context.Context, sdkRouteRef *appmeshsdk.RouteRef) error {
-	_, err := m.appMeshSDK.DeleteRouteWithContext(ctx, &appmeshsdk.DeleteRouteInput{
-		MeshName:          sdkRouteRef.MeshName,
-		MeshOwner:         sdkRouteRef.MeshOwner,
-		VirtualRouterName: sdkRouteRef.VirtualRouterName,
-		RouteName:         sdkRouteRef.RouteName,
+// deleteSDKRouteByRef deletes the route of sdkRouteRef, whose spec isn't described, so hooks are invoked without it.
+func (m *defaultRoutesManager) deleteSDKRouteByRef(ctx context.Context, vr *appmesh.VirtualRouter, sdkRouteRef *appmeshsdk.RouteRef) error {
+	change := buildRouteChange(RouteChangeDelete, aws.StringValue(sdkRouteRef.MeshName), vr, aws.StringValue(sdkRouteRef.RouteName), nil, nil, "")
+	err := m.hooks.apply(ctx, change, func() error {
+		_, err := m.appMeshSDK.DeleteRouteWithContext(ctx, &appmeshsdk.DeleteRouteInput{
+			MeshName:          sdkRouteRef.MeshName,
+			MeshOwner:         sdkRouteRef.MeshOwner,
+			VirtualRouterName: sdkRouteRef.VirtualRouterName,
+			RouteName:         sdkRouteRef.RouteName,
+		})
+		return err
 	})
 	if err != nil {
 		var awsErr awserr.Error
@@ -240,6 +547,24 @@ func (m *defaultRoutesManager) deleteSDKRouteByRef(ctx context.Context, sdkRoute
 	return nil
 }
 
+// buildRouteChange builds the RouteChange hooks are invoked with for changing route routeName of vr.
+func buildRouteChange(operation string, meshName string, vr *appmesh.VirtualRouter, routeName string,
+	desiredSDKRouteSpec *appmeshsdk.RouteSpec, actualSDKRouteSpec *appmeshsdk.RouteSpec, diff string) RouteChange {
+	return RouteChange{
+		Operation: operation,
+		MeshName:  meshName,
+		VirtualRouter: RouteChangeVirtualRouter{
+			Namespace: vr.Namespace,
+			Name:      vr.Name,
+			AWSName:   aws.StringValue(vr.Spec.AWSName),
+		},
+		RouteName:   routeName,
+		Diff:        diff,
+		DesiredSpec: desiredSDKRouteSpec,
+		ActualSpec:  actualSDKRouteSpec,
+	}
+}
+
 type routeAndSDKRouteRef struct {
 	route       appmesh.Route
 	sdkRouteRef *appmeshsdk.RouteRef
@@ -284,8 +609,11 @@ func matchRoutesAgainstSDKRouteRefs(routes []appmesh.Route, sdkRouteRefs []*appm
 }
 
 // taintedSDKRouteRefs returns the routes which need to be deleted before the corresponding listener can be updated or deleted.
-// This includes both routes which are no longer defined by the CRD and routes where the protocol has changed but not the port.
-func taintedSDKRouteRefs(routes []appmesh.Route, sdkVR *appmeshsdk.VirtualRouterData, sdkRouteRefs []*appmeshsdk.RouteRef) []*appmeshsdk.RouteRef {
+// This includes routes where the protocol has changed but not the port, and routes which are no longer defined by the CRD
+// when any listener of sdkVR is removed or changes protocol.
+// Routes no longer defined by the CRD are kept as long as the listeners of sdkVR are, so traffic keeps matching them
+// until their replacement routes are created.
+func taintedSDKRouteRefs(routes []appmesh.Route, listeners []appmesh.VirtualRouterListener, sdkVR *appmeshsdk.VirtualRouterData, sdkRouteRefs []*appmeshsdk.RouteRef) []*appmeshsdk.RouteRef {
 	routeByName := make(map[string]appmesh.Route, len(routes))
 	sdkRouteRefByName := make(map[string]*appmeshsdk.RouteRef, len(sdkRouteRefs))
 	sdkListenerByPort := make(map[int64]appmesh.PortProtocol, len(sdkVR.Spec.Listeners))
@@ -301,7 +629,10 @@ func taintedSDKRouteRefs(routes []appmesh.Route, sdkVR *appmeshsdk.VirtualRouter
 	routeNameSet := sets.StringKeySet(routeByName)
 	sdkRouteRefNameSet := sets.StringKeySet(sdkRouteRefByName)
 	matchedNameSet := routeNameSet.Intersection(sdkRouteRefNameSet)
-	unmatchedSDKRouteRefNameSet := sdkRouteRefNameSet.Difference(routeNameSet)
+	unmatchedSDKRouteRefNameSet := sets.NewString()
+	if !sdkListenersRetained(listeners, sdkListenerByPort) {
+		unmatchedSDKRouteRefNameSet = sdkRouteRefNameSet.Difference(routeNameSet)
+	}
 
 	for _, name := range matchedNameSet.List() {
 		route := routeByName[name]
@@ -324,6 +655,20 @@ func taintedSDKRouteRefs(routes []appmesh.Route, sdkVR *appmeshsdk.VirtualRouter
 	return unmatchedSDKRouteRefs
 }
 
+// sdkListenersRetained tests whether listeners keep every listener of sdkListenerByPort with the same protocol.
+func sdkListenersRetained(listeners []appmesh.VirtualRouterListener, sdkListenerByPort map[int64]appmesh.PortProtocol) bool {
+	listenerByPort := make(map[int64]appmesh.PortProtocol, len(listeners))
+	for _, listener := range listeners {
+		listenerByPort[int64(listener.PortMapping.Port)] = listener.PortMapping.Protocol
+	}
+	for port, protocol := range sdkListenerByPort {
+		if listenerByPort[port] != protocol {
+			return false
+		}
+	}
+	return true
+}
+
 func BuildSDKRouteSpec(vr *appmesh.VirtualRouter, route appmesh.Route, vnByKey map[types.NamespacedName]*appmesh.VirtualNode) (*appmeshsdk.RouteSpec, error) {
 	sdkVNRefConvertFunc := references.BuildSDKVirtualNodeReferenceConvertFunc(vr, vnByKey)
 	converter := conversion.NewConverter(conversion.DefaultNameFunc)
@@ -3,14 +3,20 @@ package virtualrouter
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/aws/aws-sdk-go/aws/request"
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -784,6 +790,7 @@ func Test_BuildSDKRouteSpec(t *testing.T) {
 func Test_taintedSDKRouteRefs(t *testing.T) {
 	type args struct {
 		routes       []appmesh.Route
+		listeners    []appmesh.VirtualRouterListener
 		sdkVR        *appmeshsdk.VirtualRouterData
 		sdkRouteRefs []*appmeshsdk.RouteRef
 	}
@@ -834,6 +841,9 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 						Name: "route-5",
 					},
 				},
+				listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}},
+				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Spec: &appmeshsdk.VirtualRouterSpec{
 						Listeners: []*appmeshsdk.VirtualRouterListener{
@@ -864,13 +874,16 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 			wantTaintedRouteRefs: []*appmeshsdk.RouteRef{},
 		},
 		{
-			name: "routes removed from CRD are removed",
+			name: "routes removed from CRD are removed when listeners change",
 			args: args{
 				routes: []appmesh.Route{
 					{
 						Name: "route-1",
 					},
 				},
+				listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 9090, Protocol: appmesh.PortProtocolHTTP}},
+				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Spec: &appmeshsdk.VirtualRouterSpec{
 						Listeners: []*appmeshsdk.VirtualRouterListener{
@@ -898,6 +911,40 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "routes removed from CRD are kept when listeners are retained",
+			args: args{
+				routes: []appmesh.Route{
+					{
+						Name: "route-1",
+					},
+				},
+				listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: appmesh.PortProtocolHTTP}},
+				},
+				sdkVR: &appmeshsdk.VirtualRouterData{
+					Spec: &appmeshsdk.VirtualRouterSpec{
+						Listeners: []*appmeshsdk.VirtualRouterListener{
+							{
+								PortMapping: &appmeshsdk.PortMapping{
+									Port:     aws.Int64(8080),
+									Protocol: aws.String("http"),
+								},
+							},
+						},
+					},
+				},
+				sdkRouteRefs: []*appmeshsdk.RouteRef{
+					{
+						RouteName: aws.String("route-1"),
+					},
+					{
+						RouteName: aws.String("route-2"),
+					},
+				},
+			},
+			wantTaintedRouteRefs: []*appmeshsdk.RouteRef{},
+		},
 		{
 			name: "routes with port change are removed",
 			args: args{
@@ -919,6 +966,10 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 						},
 					},
 				},
+				listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 9000, Protocol: appmesh.PortProtocolHTTP2}},
+					{PortMapping: appmesh.PortMapping{Port: 4000, Protocol: appmesh.PortProtocolHTTP}},
+				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Spec: &appmeshsdk.VirtualRouterSpec{
 						Listeners: []*appmeshsdk.VirtualRouterListener{
@@ -989,6 +1040,12 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 						},
 					},
 				},
+				listeners: []appmesh.VirtualRouterListener{
+					{PortMapping: appmesh.PortMapping{Port: 8001, Protocol: appmesh.PortProtocolHTTP2}},
+					{PortMapping: appmesh.PortMapping{Port: 8002, Protocol: appmesh.PortProtocolGRPC}},
+					{PortMapping: appmesh.PortMapping{Port: 8003, Protocol: appmesh.PortProtocolTCP}},
+					{PortMapping: appmesh.PortMapping{Port: 8004, Protocol: appmesh.PortProtocolHTTP}},
+				},
 				sdkVR: &appmeshsdk.VirtualRouterData{
 					Spec: &appmeshsdk.VirtualRouterSpec{
 						Listeners: []*appmeshsdk.VirtualRouterListener{
@@ -1052,7 +1109,7 @@ func Test_taintedSDKRouteRefs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotTaintedRouteRefs := taintedSDKRouteRefs(tt.args.routes, tt.args.sdkVR, tt.args.sdkRouteRefs)
+			gotTaintedRouteRefs := taintedSDKRouteRefs(tt.args.routes, tt.args.listeners, tt.args.sdkVR, tt.args.sdkRouteRefs)
 			assert.Equal(t, tt.wantTaintedRouteRefs, gotTaintedRouteRefs)
 		})
 	}
@@ -1170,6 +1227,47 @@ func Test_defaultRoutesManager_remove(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "renamed routes are kept until their replacement is created",
+			sdkRouteRefs: []*appmeshsdk.RouteRef{
+				{
+					RouteName: aws.String("route-1"),
+				},
+			},
+			args: args{
+				ms: &appmesh.Mesh{},
+				sdkVR: &appmeshsdk.VirtualRouterData{
+					Spec: &appmeshsdk.VirtualRouterSpec{
+						Listeners: []*appmeshsdk.VirtualRouterListener{
+							{
+								PortMapping: &appmeshsdk.PortMapping{
+									Port:     aws.Int64(8000),
+									Protocol: aws.String("tcp"),
+								},
+							},
+						},
+					},
+				},
+				vr: &appmesh.VirtualRouter{
+					Spec: appmesh.VirtualRouterSpec{
+						Listeners: []appmesh.VirtualRouterListener{
+							{PortMapping: appmesh.PortMapping{Port: 8000, Protocol: appmesh.PortProtocolTCP}},
+						},
+						Routes: []appmesh.Route{
+							{
+								Name: "route-1-renamed",
+								TCPRoute: &appmesh.TCPRoute{
+									Match: &appmesh.TCPRouteMatch{
+										Port: aws.Int64(8000),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantDeleteRoutes: []*appmeshsdk.DeleteRouteInput{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1189,14 +1287,513 @@ func Test_defaultRoutesManager_remove(t *testing.T) {
 	}
 }
 
+func Test_defaultRoutesManager_update(t *testing.T) {
+	httpRoute := func(name string, prefix string, priority *int64) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{
+					Prefix: aws.String(prefix),
+				},
+				Action: appmesh.HTTPRouteAction{
+					WeightedTargets: []appmesh.WeightedTarget{
+						{
+							VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/vn"),
+							Weight:         100,
+						},
+					},
+				},
+			},
+			Priority: priority,
+		}
+	}
+	vrWithRoutes := func(routes ...appmesh.Route) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			Spec: appmesh.VirtualRouterSpec{
+				AWSName: aws.String("vr"),
+				Routes:  routes,
+			},
+		}
+	}
+	sdkRoute := func(route appmesh.Route) *appmeshsdk.RouteData {
+		spec, err := BuildSDKRouteSpec(vrWithRoutes(route), route, nil)
+		assert.NoError(t, err)
+		return &appmeshsdk.RouteData{
+			MeshName:          aws.String("my-mesh"),
+			VirtualRouterName: aws.String("vr"),
+			RouteName:         aws.String(route.Name),
+			Spec:              spec,
+			Metadata:          &appmeshsdk.ResourceMetadata{},
+		}
+	}
+	tests := []struct {
+		name                 string
+		existingRoutes       []appmesh.Route
+		vr                   *appmesh.VirtualRouter
+		wantCalls            []string
+		wantCreatedPriority  map[string]*int64
+		wantRoutePriorityMap map[string]*int64
+	}{
+		{
+			name:                 "renamed route is created before the old route is deleted",
+			existingRoutes:       []appmesh.Route{httpRoute("route-1", "/", nil)},
+			vr:                   vrWithRoutes(httpRoute("route-2", "/", nil)),
+			wantCalls:            []string{"create route-2", "delete route-1", "update route-2"},
+			wantCreatedPriority:  map[string]*int64{"route-2": aws.Int64(1000)},
+			wantRoutePriorityMap: map[string]*int64{"route-2": nil},
+		},
+		{
+			name:                 "renamed route shadows the old route with higher priority",
+			existingRoutes:       []appmesh.Route{httpRoute("route-1", "/", aws.Int64(10))},
+			vr:                   vrWithRoutes(httpRoute("route-2", "/", aws.Int64(10))),
+			wantCalls:            []string{"create route-2", "delete route-1", "update route-2"},
+			wantCreatedPriority:  map[string]*int64{"route-2": aws.Int64(9)},
+			wantRoutePriorityMap: map[string]*int64{"route-2": aws.Int64(10)},
+		},
+		{
+			name:                 "new route already taking precedence isn't updated",
+			existingRoutes:       []appmesh.Route{httpRoute("route-1", "/", aws.Int64(10))},
+			vr:                   vrWithRoutes(httpRoute("route-2", "/", aws.Int64(5))),
+			wantCalls:            []string{"create route-2", "delete route-1"},
+			wantCreatedPriority:  map[string]*int64{"route-2": aws.Int64(5)},
+			wantRoutePriorityMap: map[string]*int64{"route-2": aws.Int64(5)},
+		},
+		{
+			name:                 "new route with different match doesn't shadow",
+			existingRoutes:       []appmesh.Route{httpRoute("route-1", "/", nil)},
+			vr:                   vrWithRoutes(httpRoute("route-2", "/v2", nil)),
+			wantCalls:            []string{"create route-2", "delete route-1"},
+			wantCreatedPriority:  map[string]*int64{"route-2": nil},
+			wantRoutePriorityMap: map[string]*int64{"route-2": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAppMesh{}
+			for _, route := range tt.existingRoutes {
+				f.existingRoutes = append(f.existingRoutes, sdkRoute(route))
+				f.existingRouteRefs = append(f.existingRouteRefs, &appmeshsdk.RouteRef{
+					MeshName:          aws.String("my-mesh"),
+					VirtualRouterName: aws.String("vr"),
+					RouteName:         aws.String(route.Name),
+				})
+			}
+			m := &defaultRoutesManager{
+				appMeshSDK:  f,
+				tagsManager: tagging.NewDefaultManager(tagging.Config{}, f, "", logr.Discard()),
+				log:         logr.Discard(),
+			}
+			ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSName: aws.String("my-mesh")}}
+
+			sdkRouteByName, err := m.update(context.Background(), ms, tt.vr, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, f.calls)
+			for _, input := range f.createdRoutes {
+				assert.Equal(t, tt.wantCreatedPriority[aws.StringValue(input.RouteName)], input.Spec.Priority)
+			}
+			for name, priority := range tt.wantRoutePriorityMap {
+				assert.Equal(t, priority, sdkRouteByName[name].Spec.Priority)
+			}
+		})
+	}
+}
+
+func Test_defaultRoutesManager_update_routeHooks(t *testing.T) {
+	httpRoute := func(name string, prefix string) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{
+					Prefix: aws.String(prefix),
+				},
+				Action: appmesh.HTTPRouteAction{
+					WeightedTargets: []appmesh.WeightedTarget{
+						{
+							VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/vn"),
+							Weight:         100,
+						},
+					},
+				},
+			},
+		}
+	}
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: v1.ObjectMeta{Namespace: "my-ns", Name: "vr"},
+		Spec: appmesh.VirtualRouterSpec{
+			AWSName: aws.String("vr"),
+			Routes:  []appmesh.Route{httpRoute("route-2", "/v2")},
+		},
+	}
+	newFakeAppMesh := func() *fakeAppMesh {
+		spec, err := BuildSDKRouteSpec(vr, httpRoute("route-1", "/v1"), nil)
+		assert.NoError(t, err)
+		return &fakeAppMesh{
+			existingRouteRefs: []*appmeshsdk.RouteRef{{MeshName: aws.String("my-mesh"), VirtualRouterName: aws.String("vr"), RouteName: aws.String("route-1")}},
+			existingRoutes: []*appmeshsdk.RouteData{{
+				MeshName:          aws.String("my-mesh"),
+				VirtualRouterName: aws.String("vr"),
+				RouteName:         aws.String("route-1"),
+				Spec:              spec,
+				Metadata:          &appmeshsdk.ResourceMetadata{},
+			}},
+		}
+	}
+	ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSName: aws.String("my-mesh")}}
+
+	t.Run("changes are notified to post hooks", func(t *testing.T) {
+		f := newFakeAppMesh()
+		postHook := &recordingRouteHook{name: "post"}
+		m := &defaultRoutesManager{
+			appMeshSDK:  f,
+			tagsManager: tagging.NewDefaultManager(tagging.Config{}, f, "", logr.Discard()),
+			hooks:       &routeHooks{postHooks: []routeHook{postHook}, log: logr.Discard()},
+			log:         logr.Discard(),
+		}
+		_, err := m.update(context.Background(), ms, vr, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"create route-2", "delete route-1"}, f.calls)
+		if assert.Len(t, postHook.changes, 2) {
+			created, deleted := postHook.changes[0], postHook.changes[1]
+			assert.Equal(t, RouteChangeCreate, created.Operation)
+			assert.Equal(t, "route-2", created.RouteName)
+			assert.Equal(t, RouteChangeVirtualRouter{Namespace: "my-ns", Name: "vr", AWSName: "vr"}, created.VirtualRouter)
+			assert.Equal(t, "my-mesh", created.MeshName)
+			assert.NotNil(t, created.DesiredSpec)
+			assert.Nil(t, created.ActualSpec)
+			assert.Contains(t, created.Diff, "/v2")
+			assert.Equal(t, RouteChangeDelete, deleted.Operation)
+			assert.Equal(t, "route-1", deleted.RouteName)
+			assert.Nil(t, deleted.DesiredSpec)
+			assert.NotNil(t, deleted.ActualSpec)
+		}
+	})
+
+	t.Run("changes rejected by pre hooks aren't applied", func(t *testing.T) {
+		f := newFakeAppMesh()
+		m := &defaultRoutesManager{
+			appMeshSDK:  f,
+			tagsManager: tagging.NewDefaultManager(tagging.Config{}, f, "", logr.Discard()),
+			hooks: &routeHooks{
+				preHooks:      []routeHook{&recordingRouteHook{name: "approvals", err: errors.New("pending approval")}},
+				retryInterval: time.Minute,
+				log:           logr.Discard(),
+			},
+			log: logr.Discard(),
+		}
+		_, err := m.update(context.Background(), ms, vr, nil)
+		assert.EqualError(t, err, "change of route route-2 rejected by hook approvals: pending approval")
+		assert.True(t, isRouteChangeRejectedError(err))
+		assert.Empty(t, f.calls)
+	})
+}
+
+func Test_defaultRoutesManager_update_routesPartiallyListed(t *testing.T) {
+	httpRoute := func(name string, prefix string) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{
+					Prefix: aws.String(prefix),
+				},
+				Action: appmesh.HTTPRouteAction{
+					WeightedTargets: []appmesh.WeightedTarget{
+						{
+							VirtualNodeARN: aws.String("arn:aws:appmesh:us-west-2:000000000000:mesh/my-mesh/virtualNode/vn"),
+							Weight:         100,
+						},
+					},
+				},
+			},
+		}
+	}
+	routeRef := func(name string) *appmeshsdk.RouteRef {
+		return &appmeshsdk.RouteRef{
+			MeshName:          aws.String("my-mesh"),
+			VirtualRouterName: aws.String("vr"),
+			RouteName:         aws.String(name),
+		}
+	}
+	vr := &appmesh.VirtualRouter{
+		Spec: appmesh.VirtualRouterSpec{
+			AWSName: aws.String("vr"),
+			Routes:  []appmesh.Route{httpRoute("route-1", "/v1"), httpRoute("route-2", "/v2")},
+		},
+	}
+	f := &fakeAppMesh{
+		// route-2 is on the page failed to be listed.
+		routeRefPages:     [][]*appmeshsdk.RouteRef{{routeRef("route-1"), routeRef("route-3")}, {routeRef("route-2")}},
+		pageFailures:      map[int]int{1: 10},
+		existingRouteRefs: []*appmeshsdk.RouteRef{routeRef("route-1"), routeRef("route-2"), routeRef("route-3")},
+	}
+	for _, route := range []appmesh.Route{httpRoute("route-1", "/"), httpRoute("route-2", "/"), httpRoute("route-3", "/v3")} {
+		spec, err := BuildSDKRouteSpec(vr, route, nil)
+		assert.NoError(t, err)
+		f.existingRoutes = append(f.existingRoutes, &appmeshsdk.RouteData{
+			MeshName:          aws.String("my-mesh"),
+			VirtualRouterName: aws.String("vr"),
+			RouteName:         aws.String(route.Name),
+			Spec:              spec,
+			Metadata:          &appmeshsdk.ResourceMetadata{},
+		})
+	}
+	m := &defaultRoutesManager{
+		appMeshSDK:  f,
+		tagsManager: tagging.NewDefaultManager(tagging.Config{}, f, "", logr.Discard()),
+		config:      Config{ListRoutesPageRetries: 1},
+		log:         logr.Discard(),
+	}
+	ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSName: aws.String("my-mesh")}}
+
+	sdkRouteByName, err := m.update(context.Background(), ms, vr, nil)
+	assert.True(t, isRoutesPartiallyListedError(err))
+	assert.Equal(t, []string{"update route-1", "update route-2", "delete route-3"}, f.calls)
+	assert.Len(t, sdkRouteByName, 2)
+	assert.Equal(t, aws.String("/v2"), sdkRouteByName["route-2"].Spec.HttpRoute.Match.Prefix)
+}
+
+func Test_defaultRoutesManager_listSDKRouteRefs(t *testing.T) {
+	routeRef := func(name string) *appmeshsdk.RouteRef {
+		return &appmeshsdk.RouteRef{RouteName: aws.String(name)}
+	}
+	pages := [][]*appmeshsdk.RouteRef{{routeRef("route-1"), routeRef("route-2")}, {routeRef("route-3")}}
+	tests := []struct {
+		name             string
+		config           Config
+		pageFailures     map[int]int
+		wantRouteRefs    []*appmeshsdk.RouteRef
+		wantListCalls    int
+		wantErr          string
+		wantPartialError bool
+	}{
+		{
+			name:          "all pages listed with limit",
+			config:        Config{ListRoutesPageLimit: 2, ListRoutesPageRetries: 3},
+			wantRouteRefs: []*appmeshsdk.RouteRef{routeRef("route-1"), routeRef("route-2"), routeRef("route-3")},
+			wantListCalls: 1,
+		},
+		{
+			name:          "failed page is retried",
+			config:        Config{ListRoutesPageLimit: 2, ListRoutesPageRetries: 3},
+			pageFailures:  map[int]int{1: 2},
+			wantRouteRefs: []*appmeshsdk.RouteRef{routeRef("route-1"), routeRef("route-2"), routeRef("route-3")},
+			wantListCalls: 3,
+		},
+		{
+			name:             "routes listed so far are returned once retries are exhausted",
+			config:           Config{ListRoutesPageLimit: 2, ListRoutesPageRetries: 2},
+			pageFailures:     map[int]int{1: 5},
+			wantRouteRefs:    []*appmeshsdk.RouteRef{routeRef("route-1"), routeRef("route-2")},
+			wantListCalls:    3,
+			wantErr:          "failed to list all routes: ServiceUnavailableException: service unavailable",
+			wantPartialError: true,
+		},
+		{
+			name:          "no routes listed",
+			config:        Config{ListRoutesPageLimit: 2, ListRoutesPageRetries: 1},
+			pageFailures:  map[int]int{0: 5},
+			wantListCalls: 2,
+			wantErr:       "ServiceUnavailableException: service unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAppMesh{
+				routeRefPages: pages,
+				pageFailures:  tt.pageFailures,
+			}
+			m := &defaultRoutesManager{
+				appMeshSDK: f,
+				config:     tt.config,
+				log:        logr.Discard(),
+			}
+			ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSName: aws.String("my-mesh")}}
+			vr := &appmesh.VirtualRouter{Spec: appmesh.VirtualRouterSpec{AWSName: aws.String("vr")}}
+
+			gotRouteRefs, err := m.listSDKRouteRefs(context.Background(), ms, vr)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPartialError, isRoutesPartiallyListedError(err))
+			assert.Equal(t, tt.wantRouteRefs, gotRouteRefs)
+			assert.Len(t, f.listRoutesArgs, tt.wantListCalls)
+			for _, input := range f.listRoutesArgs {
+				assert.Equal(t, aws.Int64(tt.config.ListRoutesPageLimit), input.Limit)
+			}
+		})
+	}
+}
+
+func Test_defaultRoutesManager_diff(t *testing.T) {
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns-1", Name: "vr-1"},
+		Spec: appmesh.VirtualRouterSpec{
+			AWSName: aws.String("vr-1_ns-1"),
+			Routes: []appmesh.Route{
+				newTestTCPRoute("route-1", 100),
+				newTestTCPRoute("route-2", 100),
+				newTestTCPRoute("route-3", 100),
+			},
+		},
+	}
+	vnByKey := map[types.NamespacedName]*appmesh.VirtualNode{
+		types.NamespacedName{Namespace: "ns-1", Name: "vn-1"}: {
+			ObjectMeta: v1.ObjectMeta{Namespace: "ns-1", Name: "vn-1"},
+			Spec:       appmesh.VirtualNodeSpec{AWSName: aws.String("vn-1_ns-1")},
+		},
+	}
+	buildSDKRouteSpec := func(route appmesh.Route) *appmeshsdk.RouteSpec {
+		sdkRouteSpec, err := BuildSDKRouteSpec(vr, route, vnByKey)
+		assert.NoError(t, err)
+		return sdkRouteSpec
+	}
+	sdkRoutes := []*appmeshsdk.RouteData{
+		{RouteName: aws.String("route-1"), Spec: buildSDKRouteSpec(newTestTCPRoute("route-1", 100))},
+		{RouteName: aws.String("route-2"), Spec: buildSDKRouteSpec(newTestTCPRoute("route-2", 50))},
+		{RouteName: aws.String("route-4"), Spec: buildSDKRouteSpec(newTestTCPRoute("route-4", 100))},
+	}
+	type wantDiff struct {
+		resource string
+		exists   bool
+		diff     bool
+	}
+	tests := []struct {
+		name      string
+		sdkVR     *appmeshsdk.VirtualRouterData
+		wantDiffs []wantDiff
+	}{
+		{
+			name:  "diffs matched, missing and extra routes",
+			sdkVR: &appmeshsdk.VirtualRouterData{VirtualRouterName: aws.String("vr-1_ns-1")},
+			wantDiffs: []wantDiff{
+				{resource: "route/route-1", exists: true, diff: false},
+				{resource: "route/route-2", exists: true, diff: true},
+				{resource: "route/route-3", exists: false, diff: true},
+				{resource: "route/route-4", exists: true, diff: true},
+			},
+		},
+		{
+			name:  "diffs routes of missing virtualRouter",
+			sdkVR: nil,
+			wantDiffs: []wantDiff{
+				{resource: "route/route-1", exists: false, diff: true},
+				{resource: "route/route-2", exists: false, diff: true},
+				{resource: "route/route-3", exists: false, diff: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeAppMesh{
+				existingRoutes: sdkRoutes,
+			}
+			for _, sdkRoute := range sdkRoutes {
+				f.existingRouteRefs = append(f.existingRouteRefs, &appmeshsdk.RouteRef{RouteName: sdkRoute.RouteName})
+			}
+			m := &defaultRoutesManager{
+				appMeshSDK: f,
+				log:        logr.Discard(),
+			}
+			ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSName: aws.String("mesh-1")}}
+
+			gotDiffs, err := m.diff(context.Background(), ms, tt.sdkVR, vr, vnByKey)
+
+			assert.NoError(t, err)
+			var got []wantDiff
+			for _, specDiff := range gotDiffs {
+				got = append(got, wantDiff{resource: specDiff.Resource, exists: specDiff.Exists, diff: len(specDiff.Diff) != 0})
+			}
+			assert.Equal(t, tt.wantDiffs, got)
+		})
+	}
+}
+
+func newTestTCPRoute(name string, weight int64) appmesh.Route {
+	return appmesh.Route{
+		Name: name,
+		TCPRoute: &appmesh.TCPRoute{
+			Action: appmesh.TCPRouteAction{
+				WeightedTargets: []appmesh.WeightedTarget{
+					{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn-1"}, Weight: weight},
+				},
+			},
+		},
+	}
+}
+
 type fakeAppMesh struct {
 	services.AppMesh
 
 	existingRouteRefs []*appmeshsdk.RouteRef
-	deletedRoutes     []*appmeshsdk.DeleteRouteInput
+	// routeRefPages are listed instead of existingRouteRefs if set, listing page i fails pageFailures[i] times.
+	routeRefPages  [][]*appmeshsdk.RouteRef
+	pageFailures   map[int]int
+	listRoutesArgs []*appmeshsdk.ListRoutesInput
+	existingRoutes []*appmeshsdk.RouteData
+	createdRoutes  []*appmeshsdk.CreateRouteInput
+	updatedRoutes  []*appmeshsdk.UpdateRouteInput
+	deletedRoutes  []*appmeshsdk.DeleteRouteInput
+	// calls records the route mutations in order, e.g. "create route-1".
+	calls []string
 }
 
-func (f *fakeAppMesh) ListRoutesPagesWithContext(_ aws.Context, _ *appmeshsdk.ListRoutesInput, callback func(*appmeshsdk.ListRoutesOutput, bool) bool, _ ...request.Option) error {
+func (f *fakeAppMesh) CreateRouteWithContext(_ aws.Context, params *appmeshsdk.CreateRouteInput, _ ...request.Option) (*appmeshsdk.CreateRouteOutput, error) {
+	f.createdRoutes = append(f.createdRoutes, params)
+	f.calls = append(f.calls, "create "+aws.StringValue(params.RouteName))
+	return &appmeshsdk.CreateRouteOutput{Route: &appmeshsdk.RouteData{
+		MeshName:          params.MeshName,
+		VirtualRouterName: params.VirtualRouterName,
+		RouteName:         params.RouteName,
+		Spec:              params.Spec,
+		Metadata:          &appmeshsdk.ResourceMetadata{},
+	}}, nil
+}
+
+func (f *fakeAppMesh) UpdateRouteWithContext(_ aws.Context, params *appmeshsdk.UpdateRouteInput, _ ...request.Option) (*appmeshsdk.UpdateRouteOutput, error) {
+	f.updatedRoutes = append(f.updatedRoutes, params)
+	f.calls = append(f.calls, "update "+aws.StringValue(params.RouteName))
+	return &appmeshsdk.UpdateRouteOutput{Route: &appmeshsdk.RouteData{
+		MeshName:          params.MeshName,
+		VirtualRouterName: params.VirtualRouterName,
+		RouteName:         params.RouteName,
+		Spec:              params.Spec,
+		Metadata:          &appmeshsdk.ResourceMetadata{},
+	}}, nil
+}
+
+func (f *fakeAppMesh) DescribeRouteWithContext(_ aws.Context, params *appmeshsdk.DescribeRouteInput, _ ...request.Option) (*appmeshsdk.DescribeRouteOutput, error) {
+	for _, route := range f.existingRoutes {
+		if aws.StringValue(route.RouteName) == aws.StringValue(params.RouteName) {
+			return &appmeshsdk.DescribeRouteOutput{Route: route}, nil
+		}
+	}
+	return nil, awserr.New("NotFoundException", "not found", nil)
+}
+
+func (f *fakeAppMesh) ListRoutesPagesWithContext(_ aws.Context, params *appmeshsdk.ListRoutesInput, callback func(*appmeshsdk.ListRoutesOutput, bool) bool, _ ...request.Option) error {
+	f.listRoutesArgs = append(f.listRoutesArgs, awsutil.CopyOf(params).(*appmeshsdk.ListRoutesInput))
+	if f.routeRefPages != nil {
+		start := 0
+		if params.NextToken != nil {
+			start, _ = strconv.Atoi(aws.StringValue(params.NextToken))
+		}
+		for i := start; i < len(f.routeRefPages); i++ {
+			if f.pageFailures[i] > 0 {
+				f.pageFailures[i]--
+				return awserr.New("ServiceUnavailableException", "service unavailable", nil)
+			}
+			lastPage := i == len(f.routeRefPages)-1
+			output := &appmeshsdk.ListRoutesOutput{Routes: f.routeRefPages[i]}
+			if !lastPage {
+				output.NextToken = aws.String(strconv.Itoa(i + 1))
+			}
+			if !callback(output, lastPage) {
+				return nil
+			}
+		}
+		return nil
+	}
 	if len(f.existingRouteRefs) > 0 {
 		callback(&appmeshsdk.ListRoutesOutput{
 			Routes: f.existingRouteRefs,
@@ -1207,6 +1804,7 @@ func (f *fakeAppMesh) ListRoutesPagesWithContext(_ aws.Context, _ *appmeshsdk.Li
 
 func (f *fakeAppMesh) DeleteRouteWithContext(_ aws.Context, params *appmeshsdk.DeleteRouteInput, _ ...request.Option) (*appmeshsdk.DeleteRouteOutput, error) {
 	f.deletedRoutes = append(f.deletedRoutes, params)
+	f.calls = append(f.calls, "delete "+aws.StringValue(params.RouteName))
 	for _, ref := range f.existingRouteRefs {
 		if aws.StringValue(ref.RouteName) != aws.StringValue(params.RouteName) {
 			continue
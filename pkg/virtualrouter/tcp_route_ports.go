@@ -0,0 +1,50 @@
+package virtualrouter
+
+import (
+	"fmt"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ExpandTCPRoutePorts returns routes with every tcpRoute matching a port range or list of ports
+// expanded into one route per port, named <route name>-<port>, in the order of ports.
+// Other routes are returned unchanged.
+func ExpandTCPRoutePorts(routes []appmesh.Route) []appmesh.Route {
+	expanded := make([]appmesh.Route, 0, len(routes))
+	for _, route := range routes {
+		ports := tcpRouteMatchPorts(route)
+		if len(ports) == 0 {
+			expanded = append(expanded, route)
+			continue
+		}
+		for _, port := range ports {
+			portRoute := *route.DeepCopy()
+			portRoute.Name = TCPRoutePortName(route.Name, port)
+			portRoute.TCPRoute.Match = &appmesh.TCPRouteMatch{Port: aws.Int64(port)}
+			expanded = append(expanded, portRoute)
+		}
+	}
+	return expanded
+}
+
+// TCPRoutePortName returns the name of the route derived from route routeName for port.
+func TCPRoutePortName(routeName string, port int64) string {
+	return fmt.Sprintf("%s-%d", routeName, port)
+}
+
+// tcpRouteMatchPorts returns the ports of route's port range or list of ports, or nil if it has neither.
+func tcpRouteMatchPorts(route appmesh.Route) []int64 {
+	if route.TCPRoute == nil || route.TCPRoute.Match == nil {
+		return nil
+	}
+	match := route.TCPRoute.Match
+	if match.PortRange != nil {
+		var ports []int64
+		for port := match.PortRange.Start; port <= match.PortRange.End; port++ {
+			ports = append(ports, port)
+		}
+		return ports
+	}
+	return match.Ports
+}
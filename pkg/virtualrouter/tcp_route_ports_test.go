@@ -0,0 +1,73 @@
+package virtualrouter
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTCPRoutePorts(t *testing.T) {
+	action := appmesh.TCPRouteAction{
+		WeightedTargets: []appmesh.WeightedTarget{
+			{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn"}, Weight: 1},
+		},
+	}
+	timeout := &appmesh.TCPTimeout{Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 60}}
+	tests := []struct {
+		name   string
+		routes []appmesh.Route
+		want   []appmesh.Route
+	}{
+		{
+			name: "routes without portRange or ports are unchanged",
+			routes: []appmesh.Route{
+				{Name: "http", HTTPRoute: &appmesh.HTTPRoute{}},
+				{Name: "tcp", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9000)}, Action: action}},
+				{Name: "tcp-any", TCPRoute: &appmesh.TCPRoute{Action: action}},
+			},
+			want: []appmesh.Route{
+				{Name: "http", HTTPRoute: &appmesh.HTTPRoute{}},
+				{Name: "tcp", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9000)}, Action: action}},
+				{Name: "tcp-any", TCPRoute: &appmesh.TCPRoute{Action: action}},
+			},
+		},
+		{
+			name: "portRange is expanded into a route per port",
+			routes: []appmesh.Route{
+				{
+					Name: "tcp",
+					TCPRoute: &appmesh.TCPRoute{
+						Match:   &appmesh.TCPRouteMatch{PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9002}},
+						Action:  action,
+						Timeout: timeout,
+					},
+				},
+			},
+			want: []appmesh.Route{
+				{Name: "tcp-9000", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9000)}, Action: action, Timeout: timeout}},
+				{Name: "tcp-9001", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9001)}, Action: action, Timeout: timeout}},
+				{Name: "tcp-9002", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9002)}, Action: action, Timeout: timeout}},
+			},
+		},
+		{
+			name: "ports are expanded into a route per port",
+			routes: []appmesh.Route{
+				{Name: "tcp", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Ports: []int64{5432, 3306}}, Action: action}},
+				{Name: "http", HTTPRoute: &appmesh.HTTPRoute{}},
+			},
+			want: []appmesh.Route{
+				{Name: "tcp-5432", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(5432)}, Action: action}},
+				{Name: "tcp-3306", TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(3306)}, Action: action}},
+				{Name: "http", HTTPRoute: &appmesh.HTTPRoute{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandTCPRoutePorts(tt.routes)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
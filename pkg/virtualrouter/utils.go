@@ -2,7 +2,7 @@ package virtualrouter
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IsVirtualRouterActive checks whether given virtualRouter is active.
@@ -10,7 +10,7 @@ import (
 func IsVirtualRouterActive(vr *appmesh.VirtualRouter) bool {
 	for _, condition := range vr.Status.Conditions {
 		if condition.Type == appmesh.VirtualRouterActive {
-			return condition.Status == corev1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue
 		}
 	}
 	return false
@@ -0,0 +1,203 @@
+package virtualrouter
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// VirtualNodeWeightAnnotation declares the ratio of the weight of a weighted target a VirtualNode selected
+	// by its virtualNodeSelector receives, as a non-negative integer. VirtualNodes without it have a ratio of 1.
+	VirtualNodeWeightAnnotation = "appmesh.k8s.aws/weight"
+)
+
+// HasVirtualNodeSelectors checks whether any route target of vr selects virtualNodes by virtualNodeSelector.
+func HasVirtualNodeSelectors(vr *appmesh.VirtualRouter) bool {
+	for _, targets := range routeWeightedTargets(vr) {
+		for _, target := range *targets {
+			if target.VirtualNodeSelector != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsVirtualNodeSelected checks whether vn's labels match any virtualNodeSelector of route targets of vr.
+func IsVirtualNodeSelected(vr *appmesh.VirtualRouter, vn *appmesh.VirtualNode) (bool, error) {
+	if vn.Namespace != vr.Namespace {
+		return false, nil
+	}
+	for _, targets := range routeWeightedTargets(vr) {
+		for _, target := range *targets {
+			if target.VirtualNodeSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(target.VirtualNodeSelector)
+			if err != nil {
+				return false, errors.Wrap(err, "invalid virtualNodeSelector")
+			}
+			if selector.Matches(labels.Set(vn.Labels)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ExpandVirtualNodeSelectors returns vr with every route target selecting virtualNodes by virtualNodeSelector replaced by
+// virtualNodeRef targets of the virtualNodes it selects in vr's namespace, which split its weight by their weight annotation.
+// vr is returned as is if it doesn't have virtualNodeSelectors.
+func ExpandVirtualNodeSelectors(ctx context.Context, k8sClient client.Client, vr *appmesh.VirtualRouter) (*appmesh.VirtualRouter, error) {
+	if !HasVirtualNodeSelectors(vr) {
+		return vr, nil
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := k8sClient.List(ctx, vnList, client.InNamespace(vr.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list virtualNodes")
+	}
+	vns := make([]*appmesh.VirtualNode, 0, len(vnList.Items))
+	for i := range vnList.Items {
+		vn := &vnList.Items[i]
+		if !vn.DeletionTimestamp.IsZero() || vn.Spec.MeshRef == nil || vr.Spec.MeshRef == nil || *vn.Spec.MeshRef != *vr.Spec.MeshRef {
+			continue
+		}
+		vns = append(vns, vn)
+	}
+	sort.Slice(vns, func(i, j int) bool {
+		return vns[i].Name < vns[j].Name
+	})
+
+	expandedVR := vr.DeepCopy()
+	for _, targets := range routeWeightedTargets(expandedVR) {
+		var expandedTargets []appmesh.WeightedTarget
+		for _, target := range *targets {
+			if target.VirtualNodeSelector == nil {
+				expandedTargets = append(expandedTargets, target)
+				continue
+			}
+			selectedTargets, err := expandWeightedTarget(target, vns)
+			if err != nil {
+				return nil, err
+			}
+			expandedTargets = append(expandedTargets, selectedTargets...)
+		}
+		*targets = expandedTargets
+	}
+	return expandedVR, nil
+}
+
+// expandWeightedTarget returns virtualNodeRef targets of vns selected by target's virtualNodeSelector.
+func expandWeightedTarget(target appmesh.WeightedTarget, vns []*appmesh.VirtualNode) ([]appmesh.WeightedTarget, error) {
+	selector, err := metav1.LabelSelectorAsSelector(target.VirtualNodeSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid virtualNodeSelector")
+	}
+	var selectedVNs []*appmesh.VirtualNode
+	var ratios []int64
+	for _, vn := range vns {
+		if !selector.Matches(labels.Set(vn.Labels)) {
+			continue
+		}
+		ratio, err := virtualNodeWeightRatio(vn)
+		if err != nil {
+			return nil, err
+		}
+		selectedVNs = append(selectedVNs, vn)
+		ratios = append(ratios, ratio)
+	}
+	// virtualNode events enqueue the virtualRouter once a matching virtualNode is created.
+	if len(selectedVNs) == 0 {
+		return nil, runtime.NewDependencyNotReadyError(errors.Errorf("virtualNodeSelector %s selects no virtualNodes", selector.String()))
+	}
+
+	weights := splitWeight(target.Weight, ratios)
+	expandedTargets := make([]appmesh.WeightedTarget, 0, len(selectedVNs))
+	for i, vn := range selectedVNs {
+		expandedTargets = append(expandedTargets, appmesh.WeightedTarget{
+			VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String(vn.Namespace), Name: vn.Name},
+			Weight:         weights[i],
+			Port:           target.Port,
+		})
+	}
+	return expandedTargets, nil
+}
+
+// virtualNodeWeightRatio returns the ratio declared by the weight annotation of vn, defaults to 1.
+func virtualNodeWeightRatio(vn *appmesh.VirtualNode) (int64, error) {
+	value, ok := vn.Annotations[VirtualNodeWeightAnnotation]
+	if !ok {
+		return 1, nil
+	}
+	ratio, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ratio < 0 {
+		return 0, errors.Errorf("annotation %s of virtualNode %v must be a non-negative integer: %q", VirtualNodeWeightAnnotation, k8s.NamespacedName(vn), value)
+	}
+	return ratio, nil
+}
+
+// splitWeight splits weight by ratios, rounding down and handing out the remainder to the largest fractions first,
+// so the split weights always add up to weight. weight is split evenly if all ratios are 0.
+func splitWeight(weight int64, ratios []int64) []int64 {
+	var totalRatio int64
+	for _, ratio := range ratios {
+		totalRatio += ratio
+	}
+	if totalRatio == 0 {
+		ratios = make([]int64, len(ratios))
+		for i := range ratios {
+			ratios[i] = 1
+		}
+		totalRatio = int64(len(ratios))
+	}
+	weights := make([]int64, len(ratios))
+	remainders := make([]int64, len(ratios))
+	var assigned int64
+	for i, ratio := range ratios {
+		weights[i] = weight * ratio / totalRatio
+		remainders[i] = weight * ratio % totalRatio
+		assigned += weights[i]
+	}
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for i := int64(0); i < weight-assigned; i++ {
+		weights[order[i]]++
+	}
+	return weights
+}
+
+// routeWeightedTargets returns the weighted targets of each route of vr, by pointer for them to be replaced.
+func routeWeightedTargets(vr *appmesh.VirtualRouter) []*[]appmesh.WeightedTarget {
+	var targets []*[]appmesh.WeightedTarget
+	for i := range vr.Spec.Routes {
+		route := &vr.Spec.Routes[i]
+		if route.GRPCRoute != nil {
+			targets = append(targets, &route.GRPCRoute.Action.WeightedTargets)
+		}
+		if route.HTTPRoute != nil {
+			targets = append(targets, &route.HTTPRoute.Action.WeightedTargets)
+		}
+		if route.HTTP2Route != nil {
+			targets = append(targets, &route.HTTP2Route.Action.WeightedTargets)
+		}
+		if route.TCPRoute != nil {
+			targets = append(targets, &route.TCPRoute.Action.WeightedTargets)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,159 @@
+package virtualrouter
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_splitWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		weight int64
+		ratios []int64
+		want   []int64
+	}{
+		{
+			name:   "single ratio",
+			weight: 100,
+			ratios: []int64{1},
+			want:   []int64{100},
+		},
+		{
+			name:   "even ratios with remainder",
+			weight: 100,
+			ratios: []int64{1, 1, 1},
+			want:   []int64{34, 33, 33},
+		},
+		{
+			name:   "uneven ratios",
+			weight: 10,
+			ratios: []int64{3, 1},
+			want:   []int64{8, 2},
+		},
+		{
+			name:   "zero ratio",
+			weight: 60,
+			ratios: []int64{0, 2, 1},
+			want:   []int64{0, 40, 20},
+		},
+		{
+			name:   "all zero ratios",
+			weight: 10,
+			ratios: []int64{0, 0},
+			want:   []int64{5, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitWeight(tt.weight, tt.ratios))
+		})
+	}
+}
+
+func Test_ExpandVirtualNodeSelectors(t *testing.T) {
+	meshRef := &appmesh.MeshReference{Name: "my-mesh", UID: "uid-1"}
+	virtualNode := func(namespace string, name string, labels map[string]string, annotations map[string]string) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels, Annotations: annotations},
+			Spec:       appmesh.VirtualNodeSpec{MeshRef: meshRef},
+		}
+	}
+	virtualRouter := func(targets ...appmesh.WeightedTarget) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "vr"},
+			Spec: appmesh.VirtualRouterSpec{
+				MeshRef: meshRef,
+				Routes: []appmesh.Route{
+					{
+						Name: "route",
+						HTTPRoute: &appmesh.HTTPRoute{
+							Match:  appmesh.HTTPRouteMatch{Prefix: aws.String("/")},
+							Action: appmesh.HTTPRouteAction{WeightedTargets: targets},
+						},
+					},
+				},
+			},
+		}
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}}
+	port := aws.Int64(8080)
+	tests := []struct {
+		name        string
+		vns         []*appmesh.VirtualNode
+		vr          *appmesh.VirtualRouter
+		wantTargets []appmesh.WeightedTarget
+		wantErr     string
+	}{
+		{
+			name: "without virtualNodeSelectors",
+			vr: virtualRouter(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "payments"}, Weight: 100},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "payments"}, Weight: 100},
+			},
+		},
+		{
+			name: "virtualNodeSelector split by weight annotation",
+			vns: []*appmesh.VirtualNode{
+				virtualNode("my-ns", "payments-green", map[string]string{"app": "payments"}, map[string]string{VirtualNodeWeightAnnotation: "3"}),
+				virtualNode("my-ns", "payments-blue", map[string]string{"app": "payments"}, nil),
+				virtualNode("my-ns", "orders", map[string]string{"app": "orders"}, nil),
+				virtualNode("other-ns", "payments", map[string]string{"app": "payments"}, nil),
+			},
+			vr: virtualRouter(
+				appmesh.WeightedTarget{VirtualNodeSelector: selector, Weight: 80, Port: port},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "orders"}, Weight: 20},
+			),
+			wantTargets: []appmesh.WeightedTarget{
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "payments-blue"}, Weight: 20, Port: port},
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "payments-green"}, Weight: 60, Port: port},
+				{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "orders"}, Weight: 20},
+			},
+		},
+		{
+			name: "virtualNodeSelector selects no virtualNodes",
+			vns: []*appmesh.VirtualNode{
+				virtualNode("my-ns", "orders", map[string]string{"app": "orders"}, nil),
+			},
+			vr:      virtualRouter(appmesh.WeightedTarget{VirtualNodeSelector: selector, Weight: 100}),
+			wantErr: "virtualNodeSelector app=payments selects no virtualNodes",
+		},
+		{
+			name: "invalid weight annotation",
+			vns: []*appmesh.VirtualNode{
+				virtualNode("my-ns", "payments", map[string]string{"app": "payments"}, map[string]string{VirtualNodeWeightAnnotation: "-1"}),
+			},
+			vr:      virtualRouter(appmesh.WeightedTarget{VirtualNodeSelector: selector, Weight: 100}),
+			wantErr: `annotation appmesh.k8s.aws/weight of virtualNode my-ns/payments must be a non-negative integer: "-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range tt.vns {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+
+			got, err := ExpandVirtualNodeSelectors(ctx, k8sClient, tt.vr)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTargets, got.Spec.Routes[0].HTTPRoute.Action.WeightedTargets)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package virtualrouter
+
+import (
+	"context"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// LabelVirtualRouter labels VirtualServices auto-created for a VirtualRouter with its name.
+	LabelVirtualRouter = "appmesh.k8s.aws/virtualRouter"
+)
+
+// VirtualServiceManager manages VirtualServices auto-created for VirtualRouters.
+type VirtualServiceManager interface {
+	// Reconcile ensures the VirtualService provided by vr exists if vr.spec.autoCreateVirtualService is enabled,
+	// and deletes the ones auto-created for vr that are no longer desired.
+	// they're owned by vr, so they're garbage collected upon deletion of vr.
+	Reconcile(ctx context.Context, vr *appmesh.VirtualRouter) error
+}
+
+// NewDefaultVirtualServiceManager constructs new VirtualServiceManager
+func NewDefaultVirtualServiceManager(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger) VirtualServiceManager {
+	return &defaultVirtualServiceManager{
+		k8sClient: k8sClient,
+		scheme:    scheme,
+		log:       log,
+	}
+}
+
+var _ VirtualServiceManager = &defaultVirtualServiceManager{}
+
+// defaultVirtualServiceManager implements VirtualServiceManager.
+// auto-created VirtualServices are reconciled into AppMesh by the VirtualService controller like any other,
+// their mesh and awsName are defaulted by the admission webhook, so their hostname is "${name}.${namespace}" unless configured otherwise.
+type defaultVirtualServiceManager struct {
+	k8sClient client.Client
+	scheme    *runtime.Scheme
+	log       logr.Logger
+}
+
+func (m *defaultVirtualServiceManager) Reconcile(ctx context.Context, vr *appmesh.VirtualRouter) error {
+	var desiredVS *appmesh.VirtualService
+	if aws.BoolValue(vr.Spec.AutoCreateVirtualService) {
+		desiredVS = buildVirtualService(vr)
+	}
+	vsList := &appmesh.VirtualServiceList{}
+	if err := m.k8sClient.List(ctx, vsList, client.InNamespace(vr.Namespace), client.MatchingLabels{LabelVirtualRouter: vr.Name}); err != nil {
+		return errors.Wrap(err, "failed to list virtualServices")
+	}
+	var existingVS *appmesh.VirtualService
+	for i := range vsList.Items {
+		vs := &vsList.Items[i]
+		if !metav1.IsControlledBy(vs, vr) {
+			continue
+		}
+		if desiredVS != nil && vs.Name == desiredVS.Name {
+			existingVS = vs
+			continue
+		}
+		if err := m.k8sClient.Delete(ctx, vs); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete virtualService %v", k8s.NamespacedName(vs))
+		}
+		m.log.V(1).Info("deleted auto-created virtualService",
+			"virtualRouter", k8s.NamespacedName(vr),
+			"virtualService", k8s.NamespacedName(vs),
+		)
+	}
+	if desiredVS == nil {
+		return nil
+	}
+	if existingVS == nil {
+		if err := controllerutil.SetControllerReference(vr, desiredVS, m.scheme); err != nil {
+			return err
+		}
+		// creation fails if the name is taken by a VirtualService not owned by vr, which is left untouched.
+		if err := m.k8sClient.Create(ctx, desiredVS); err != nil {
+			return errors.Wrapf(err, "failed to create virtualService %v", k8s.NamespacedName(desiredVS))
+		}
+		m.log.V(1).Info("created auto-created virtualService",
+			"virtualRouter", k8s.NamespacedName(vr),
+			"virtualService", k8s.NamespacedName(desiredVS),
+		)
+		return nil
+	}
+
+	// only the provider is kept in sync, so the awsName defaulted upon creation is preserved.
+	oldVS := existingVS.DeepCopy()
+	existingVS.Spec.Provider = desiredVS.Spec.Provider
+	if equality.Semantic.DeepEqual(oldVS, existingVS) {
+		return nil
+	}
+	if err := m.k8sClient.Patch(ctx, existingVS, client.MergeFrom(oldVS)); err != nil {
+		return errors.Wrapf(err, "failed to update virtualService %v", k8s.NamespacedName(existingVS))
+	}
+	m.log.V(1).Info("updated auto-created virtualService",
+		"virtualRouter", k8s.NamespacedName(vr),
+		"virtualService", k8s.NamespacedName(existingVS),
+	)
+	return nil
+}
+
+// buildVirtualService builds the VirtualService of the same name as vr, provided by vr.
+func buildVirtualService(vr *appmesh.VirtualRouter) *appmesh.VirtualService {
+	vs := &appmesh.VirtualService{}
+	vs.Namespace = vr.Namespace
+	vs.Name = vr.Name
+	vs.Labels = map[string]string{LabelVirtualRouter: vr.Name}
+	vs.Spec = appmesh.VirtualServiceSpec{
+		Provider: &appmesh.VirtualServiceProvider{
+			VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+				VirtualRouterRef: &appmesh.VirtualRouterReference{
+					Namespace: aws.String(vr.Namespace),
+					Name:      vr.Name,
+				},
+			},
+		},
+	}
+	return vs
+}
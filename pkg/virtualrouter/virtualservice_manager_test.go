@@ -0,0 +1,152 @@
+package virtualrouter
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_defaultVirtualServiceManager_Reconcile(t *testing.T) {
+	ownerRef := metav1.OwnerReference{
+		APIVersion:         "appmesh.k8s.aws/v1beta2",
+		Kind:               "VirtualRouter",
+		Name:               "my-vr",
+		UID:                "vr-uid",
+		Controller:         aws.Bool(true),
+		BlockOwnerDeletion: aws.Bool(true),
+	}
+	providedByVR := func(vrName string) *appmesh.VirtualServiceProvider {
+		return &appmesh.VirtualServiceProvider{
+			VirtualRouter: &appmesh.VirtualRouterServiceProvider{
+				VirtualRouterRef: &appmesh.VirtualRouterReference{Namespace: aws.String("my-ns"), Name: vrName},
+			},
+		}
+	}
+	tests := []struct {
+		name                     string
+		autoCreateVirtualService *bool
+		existingVSs              []*appmesh.VirtualService
+		wantVSs                  []appmesh.VirtualService
+	}{
+		{
+			name:                     "virtualService doesn't exist",
+			autoCreateVirtualService: aws.Bool(true),
+			wantVSs: []appmesh.VirtualService{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-vr", OwnerReferences: []metav1.OwnerReference{ownerRef}},
+					Spec:       appmesh.VirtualServiceSpec{Provider: providedByVR("my-vr")},
+				},
+			},
+		},
+		{
+			name:                     "virtualService exists with stale provider",
+			autoCreateVirtualService: aws.Bool(true),
+			existingVSs: []*appmesh.VirtualService{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "my-ns",
+						Name:            "my-vr",
+						Labels:          map[string]string{"appmesh.k8s.aws/virtualRouter": "my-vr"},
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+					Spec: appmesh.VirtualServiceSpec{
+						AWSName:  aws.String("my-vr.my-ns"),
+						Provider: providedByVR("other-vr"),
+					},
+				},
+			},
+			wantVSs: []appmesh.VirtualService{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-vr", OwnerReferences: []metav1.OwnerReference{ownerRef}},
+					Spec: appmesh.VirtualServiceSpec{
+						AWSName:  aws.String("my-vr.my-ns"),
+						Provider: providedByVR("my-vr"),
+					},
+				},
+			},
+		},
+		{
+			name: "autoCreateVirtualService is disabled",
+			existingVSs: []*appmesh.VirtualService{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "my-ns",
+						Name:            "my-vr",
+						Labels:          map[string]string{"appmesh.k8s.aws/virtualRouter": "my-vr"},
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "my-ns",
+						Name:      "unmanaged",
+						Labels:    map[string]string{"appmesh.k8s.aws/virtualRouter": "my-vr"},
+					},
+				},
+			},
+			wantVSs: []appmesh.VirtualService{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vs := range tt.existingVSs {
+				assert.NoError(t, k8sClient.Create(ctx, vs.DeepCopy()))
+			}
+
+			m := NewDefaultVirtualServiceManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+			vr := &appmesh.VirtualRouter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vr", UID: "vr-uid"},
+				Spec:       appmesh.VirtualRouterSpec{AutoCreateVirtualService: tt.autoCreateVirtualService},
+			}
+			assert.NoError(t, m.Reconcile(ctx, vr))
+
+			vsList := &appmesh.VirtualServiceList{}
+			assert.NoError(t, k8sClient.List(ctx, vsList))
+			var gotVSs []appmesh.VirtualService
+			for _, vs := range vsList.Items {
+				gotVSs = append(gotVSs, appmesh.VirtualService{
+					ObjectMeta: metav1.ObjectMeta{Name: vs.Name, OwnerReferences: vs.OwnerReferences},
+					Spec:       vs.Spec,
+				})
+			}
+			assert.Equal(t, tt.wantVSs, gotVSs)
+		})
+	}
+}
+
+func Test_defaultVirtualServiceManager_Reconcile_nameTaken(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	assert.NoError(t, k8sClient.Create(ctx, &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vr"},
+	}))
+
+	m := NewDefaultVirtualServiceManager(k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+	vr := &appmesh.VirtualRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vr", UID: "vr-uid"},
+		Spec:       appmesh.VirtualRouterSpec{AutoCreateVirtualService: aws.Bool(true)},
+	}
+	err := m.Reconcile(ctx, vr)
+	assert.EqualError(t, err, `failed to create virtualService my-ns/my-vr: virtualservices.appmesh.k8s.aws "my-vr" already exists`)
+}
@@ -0,0 +1,79 @@
+package virtualservice
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// DNSProviderNone disables managing DNS records of VirtualServices.
+	DNSProviderNone = ""
+	// DNSProviderService resolves awsNames of the form <name>.<namespace>.svc.<cluster-domain> with placeholder Services in the namespace of their VirtualService.
+	DNSProviderService = "service"
+	// DNSProviderRoute53 resolves awsNames within a Route53 private hosted zone with A records.
+	DNSProviderRoute53 = "route53"
+
+	flagDNSProvider            = "virtual-service-dns-provider"
+	flagDNSClusterDomain       = "virtual-service-dns-cluster-domain"
+	flagDNSRoute53HostedZoneID = "virtual-service-dns-route53-hosted-zone-id"
+	flagDNSRecordIP            = "virtual-service-dns-record-ip"
+	flagDNSRecordTTL           = "virtual-service-dns-record-ttl"
+
+	defaultDNSClusterDomain = "cluster.local"
+	defaultDNSRecordIP      = "10.10.10.10"
+	defaultDNSRecordTTL     = 300
+)
+
+// DNSConfig configures DNS records that make awsNames of VirtualServices resolvable by applications.
+// Envoy intercepts traffic to the resolved address, so it only needs to resolve to some IP.
+type DNSConfig struct {
+	// Provider of DNS records, one of DNSProviderNone, DNSProviderService or DNSProviderRoute53.
+	Provider string
+	// ClusterDomain is the domain of Services within the cluster.
+	ClusterDomain string
+	// Route53HostedZoneID is the ID of the private hosted zone records are created in.
+	Route53HostedZoneID string
+	// RecordIP is the IP Route53 records resolve to.
+	RecordIP string
+	// RecordTTL is the TTL of Route53 records in seconds.
+	RecordTTL int64
+}
+
+func (cfg *DNSConfig) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.Provider, flagDNSProvider, DNSProviderNone,
+		"Provider of DNS records resolving awsNames of VirtualServices that aren't backed by a Service: service or route53. Disabled if empty")
+	fs.StringVar(&cfg.ClusterDomain, flagDNSClusterDomain, defaultDNSClusterDomain,
+		"The cluster domain of Services, placeholder Services are created for awsNames of the form <name>.<namespace>.svc.<cluster-domain> in the namespace of their VirtualService")
+	fs.StringVar(&cfg.Route53HostedZoneID, flagDNSRoute53HostedZoneID, "",
+		"The ID of the Route53 private hosted zone to create records in, required by the route53 provider")
+	fs.StringVar(&cfg.RecordIP, flagDNSRecordIP, defaultDNSRecordIP,
+		"The IP that Route53 records of VirtualServices resolve to")
+	fs.Int64Var(&cfg.RecordTTL, flagDNSRecordTTL, defaultDNSRecordTTL,
+		"The TTL in seconds of Route53 records of VirtualServices")
+}
+
+func (cfg *DNSConfig) Validate() error {
+	switch cfg.Provider {
+	case DNSProviderNone:
+		return nil
+	case DNSProviderService:
+		if len(cfg.ClusterDomain) == 0 {
+			return errors.Errorf("%s must be specified with provider %s", flagDNSClusterDomain, DNSProviderService)
+		}
+	case DNSProviderRoute53:
+		if len(cfg.Route53HostedZoneID) == 0 {
+			return errors.Errorf("%s must be specified with provider %s", flagDNSRoute53HostedZoneID, DNSProviderRoute53)
+		}
+		if ip := net.ParseIP(cfg.RecordIP); ip == nil || ip.To4() == nil {
+			return errors.Errorf("%s must be an IPv4 address: %q", flagDNSRecordIP, cfg.RecordIP)
+		}
+		if cfg.RecordTTL < 0 {
+			return errors.Errorf("%s must not be negative: %d", flagDNSRecordTTL, cfg.RecordTTL)
+		}
+	default:
+		return errors.Errorf("%s must be one of %q, %q or %q: %q", flagDNSProvider, DNSProviderNone, DNSProviderService, DNSProviderRoute53, cfg.Provider)
+	}
+	return nil
+}
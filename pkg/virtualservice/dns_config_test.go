@@ -0,0 +1,53 @@
+package virtualservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DNSConfig
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			cfg:  DNSConfig{},
+		},
+		{
+			name: "service provider",
+			cfg:  DNSConfig{Provider: DNSProviderService, ClusterDomain: "cluster.local"},
+		},
+		{
+			name: "route53 provider",
+			cfg:  DNSConfig{Provider: DNSProviderRoute53, Route53HostedZoneID: "Z123", RecordIP: "10.10.10.10", RecordTTL: 300},
+		},
+		{
+			name:    "route53 provider without hosted zone",
+			cfg:     DNSConfig{Provider: DNSProviderRoute53, RecordIP: "10.10.10.10", RecordTTL: 300},
+			wantErr: "virtual-service-dns-route53-hosted-zone-id must be specified with provider route53",
+		},
+		{
+			name:    "route53 provider with IPv6 record IP",
+			cfg:     DNSConfig{Provider: DNSProviderRoute53, Route53HostedZoneID: "Z123", RecordIP: "fd00::1", RecordTTL: 300},
+			wantErr: `virtual-service-dns-record-ip must be an IPv4 address: "fd00::1"`,
+		},
+		{
+			name:    "unknown provider",
+			cfg:     DNSConfig{Provider: "coredns"},
+			wantErr: `virtual-service-dns-provider must be one of "", "service" or "route53": "coredns"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package virtualservice
+
+import (
+	"context"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelManagedBy labels placeholder Services managed by the controller.
+	LabelManagedBy = "appmesh.k8s.aws/managed-by"
+	// AnnotationVirtualService annotates placeholder Services with the namespaced name of the VirtualService owning them.
+	AnnotationVirtualService = "appmesh.k8s.aws/virtualService"
+
+	managedByValue = "appmesh-controller"
+)
+
+// DNSManager manages DNS records that make awsNames of VirtualServices resolvable by applications within the mesh.
+type DNSManager interface {
+	// Reconcile ensures the awsName of vs resolves, unless it's already resolved by a record not managed by the controller.
+	Reconcile(ctx context.Context, vs *appmesh.VirtualService) error
+	// Cleanup deletes DNS records managed for vs.
+	Cleanup(ctx context.Context, vs *appmesh.VirtualService) error
+}
+
+// NewDefaultDNSManager constructs new DNSManager of the provider in cfg. It's a no-op if no provider is configured.
+func NewDefaultDNSManager(cfg DNSConfig, k8sClient client.Client, scheme *runtime.Scheme, route53SDK services.Route53, log logr.Logger) DNSManager {
+	switch cfg.Provider {
+	case DNSProviderService:
+		return newServiceDNSManager(cfg, k8sClient, scheme, log)
+	case DNSProviderRoute53:
+		return newRoute53DNSManager(cfg, route53SDK, log)
+	default:
+		return &noopDNSManager{}
+	}
+}
+
+var _ DNSManager = &noopDNSManager{}
+
+type noopDNSManager struct{}
+
+func (m *noopDNSManager) Reconcile(ctx context.Context, vs *appmesh.VirtualService) error {
+	return nil
+}
+
+func (m *noopDNSManager) Cleanup(ctx context.Context, vs *appmesh.VirtualService) error {
+	return nil
+}
+
+// dnsName returns the awsName of vs as a DNS name without trailing dot, in lower case.
+func dnsName(vs *appmesh.VirtualService) string {
+	return strings.ToLower(strings.TrimSuffix(aws.StringValue(vs.Spec.AWSName), "."))
+}
+
+// ownerValue identifies vs as the owner of DNS records.
+func ownerValue(vs *appmesh.VirtualService) string {
+	return k8s.NamespacedName(vs).String()
+}
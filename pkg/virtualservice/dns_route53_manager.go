@@ -0,0 +1,219 @@
+package virtualservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxRecordSetsPerName bounds record sets listed for a name, it covers every record type.
+	maxRecordSetsPerName = "20"
+)
+
+func newRoute53DNSManager(cfg DNSConfig, route53SDK services.Route53, log logr.Logger) *route53DNSManager {
+	return &route53DNSManager{
+		hostedZoneID: cfg.Route53HostedZoneID,
+		recordIP:     cfg.RecordIP,
+		recordTTL:    cfg.RecordTTL,
+		route53SDK:   route53SDK,
+		log:          log,
+	}
+}
+
+var _ DNSManager = &route53DNSManager{}
+
+// route53DNSManager resolves awsNames within a Route53 hosted zone with A records.
+// each A record is accompanied by a TXT record of the same name identifying its VirtualService,
+// so records not created by the controller are never modified.
+type route53DNSManager struct {
+	hostedZoneID string
+	recordIP     string
+	recordTTL    int64
+	route53SDK   services.Route53
+	log          logr.Logger
+
+	// hostedZoneName is lazily described, it's empty until then.
+	hostedZoneName      string
+	hostedZoneNameMutex sync.Mutex
+}
+
+func (m *route53DNSManager) Reconcile(ctx context.Context, vs *appmesh.VirtualService) error {
+	recordName, ok, err := m.recordName(ctx, vs)
+	if err != nil || !ok {
+		return err
+	}
+	aRecordSet, txtRecordSet, err := m.findRecordSets(ctx, recordName)
+	if err != nil {
+		return err
+	}
+	owned := isOwnerRecordSetOf(txtRecordSet, vs)
+	if !owned && (aRecordSet != nil || txtRecordSet != nil) {
+		m.log.V(1).Info("awsName is resolved by records not managed by virtualService, skipping records",
+			"virtualService", k8s.NamespacedName(vs),
+			"recordName", recordName,
+		)
+		return nil
+	}
+	desiredARecordSet := m.buildARecordSet(recordName)
+	if owned && isARecordSetUpToDate(aRecordSet, desiredARecordSet) {
+		return nil
+	}
+	changes := []*route53.Change{
+		{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: desiredARecordSet},
+		{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: m.buildOwnerRecordSet(recordName, vs)},
+	}
+	if err := m.changeRecordSets(ctx, changes); err != nil {
+		return errors.Wrapf(err, "failed to upsert records of %s", recordName)
+	}
+	m.log.V(1).Info("upserted records",
+		"virtualService", k8s.NamespacedName(vs),
+		"recordName", recordName,
+	)
+	return nil
+}
+
+func (m *route53DNSManager) Cleanup(ctx context.Context, vs *appmesh.VirtualService) error {
+	recordName, ok, err := m.recordName(ctx, vs)
+	if err != nil || !ok {
+		return err
+	}
+	aRecordSet, txtRecordSet, err := m.findRecordSets(ctx, recordName)
+	if err != nil {
+		return err
+	}
+	if !isOwnerRecordSetOf(txtRecordSet, vs) {
+		return nil
+	}
+	var changes []*route53.Change
+	if aRecordSet != nil {
+		changes = append(changes, &route53.Change{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: aRecordSet})
+	}
+	changes = append(changes, &route53.Change{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: txtRecordSet})
+	if err := m.changeRecordSets(ctx, changes); err != nil {
+		return errors.Wrapf(err, "failed to delete records of %s", recordName)
+	}
+	m.log.V(1).Info("deleted records",
+		"virtualService", k8s.NamespacedName(vs),
+		"recordName", recordName,
+	)
+	return nil
+}
+
+// recordName returns the fully qualified name of records resolving the awsName of vs.
+// returns false if the awsName isn't within the hosted zone.
+func (m *route53DNSManager) recordName(ctx context.Context, vs *appmesh.VirtualService) (string, bool, error) {
+	zoneName, err := m.describeHostedZoneName(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	name := dnsName(vs)
+	if name == zoneName || !strings.HasSuffix(name, "."+zoneName) {
+		return "", false, nil
+	}
+	return name + ".", true, nil
+}
+
+func (m *route53DNSManager) describeHostedZoneName(ctx context.Context) (string, error) {
+	m.hostedZoneNameMutex.Lock()
+	defer m.hostedZoneNameMutex.Unlock()
+	if len(m.hostedZoneName) != 0 {
+		return m.hostedZoneName, nil
+	}
+	resp, err := m.route53SDK.GetHostedZoneWithContext(ctx, &route53.GetHostedZoneInput{
+		Id: aws.String(m.hostedZoneID),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe hosted zone %s", m.hostedZoneID)
+	}
+	m.hostedZoneName = strings.ToLower(strings.TrimSuffix(aws.StringValue(resp.HostedZone.Name), "."))
+	return m.hostedZoneName, nil
+}
+
+// findRecordSets returns the A and TXT record sets of recordName, which are nil if absent.
+func (m *route53DNSManager) findRecordSets(ctx context.Context, recordName string) (*route53.ResourceRecordSet, *route53.ResourceRecordSet, error) {
+	resp, err := m.route53SDK.ListResourceRecordSetsWithContext(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(m.hostedZoneID),
+		StartRecordName: aws.String(recordName),
+		MaxItems:        aws.String(maxRecordSetsPerName),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to list records of %s", recordName)
+	}
+	var aRecordSet, txtRecordSet *route53.ResourceRecordSet
+	for _, recordSet := range resp.ResourceRecordSets {
+		if !strings.EqualFold(aws.StringValue(recordSet.Name), recordName) {
+			continue
+		}
+		switch aws.StringValue(recordSet.Type) {
+		case route53.RRTypeA:
+			aRecordSet = recordSet
+		case route53.RRTypeTxt:
+			txtRecordSet = recordSet
+		}
+	}
+	return aRecordSet, txtRecordSet, nil
+}
+
+func (m *route53DNSManager) changeRecordSets(ctx context.Context, changes []*route53.Change) error {
+	_, err := m.route53SDK.ChangeResourceRecordSetsWithContext(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(m.hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Comment: aws.String("managed by appmesh-controller"),
+			Changes: changes,
+		},
+	})
+	return err
+}
+
+func (m *route53DNSManager) buildARecordSet(recordName string) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:            aws.String(recordName),
+		Type:            aws.String(route53.RRTypeA),
+		TTL:             aws.Int64(m.recordTTL),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(m.recordIP)}},
+	}
+}
+
+func (m *route53DNSManager) buildOwnerRecordSet(recordName string, vs *appmesh.VirtualService) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:            aws.String(recordName),
+		Type:            aws.String(route53.RRTypeTxt),
+		TTL:             aws.Int64(m.recordTTL),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(ownerRecordValue(vs))}},
+	}
+}
+
+// ownerRecordValue is the value of TXT record identifying vs as the owner of records, quoted as required by Route53.
+func ownerRecordValue(vs *appmesh.VirtualService) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%s=%s,%s=%s", LabelManagedBy, managedByValue, AnnotationVirtualService, ownerValue(vs)))
+}
+
+// isOwnerRecordSetOf checks whether txtRecordSet identifies vs as the owner of records.
+func isOwnerRecordSetOf(txtRecordSet *route53.ResourceRecordSet, vs *appmesh.VirtualService) bool {
+	if txtRecordSet == nil {
+		return false
+	}
+	for _, record := range txtRecordSet.ResourceRecords {
+		if aws.StringValue(record.Value) == ownerRecordValue(vs) {
+			return true
+		}
+	}
+	return false
+}
+
+func isARecordSetUpToDate(actual *route53.ResourceRecordSet, desired *route53.ResourceRecordSet) bool {
+	if actual == nil || aws.Int64Value(actual.TTL) != aws.Int64Value(desired.TTL) || len(actual.ResourceRecords) != 1 {
+		return false
+	}
+	return aws.StringValue(actual.ResourceRecords[0].Value) == aws.StringValue(desired.ResourceRecords[0].Value)
+}
@@ -0,0 +1,146 @@
+package virtualservice
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeRoute53 serves record sets of a single hosted zone, keyed by name and type.
+type fakeRoute53 struct {
+	services.Route53
+
+	zoneName             string
+	getHostedZoneCalls   int
+	changeRecordSetCalls int
+	recordSets           map[string]*route53.ResourceRecordSet
+}
+
+func (f *fakeRoute53) GetHostedZoneWithContext(ctx aws.Context, input *route53.GetHostedZoneInput, opts ...request.Option) (*route53.GetHostedZoneOutput, error) {
+	f.getHostedZoneCalls++
+	return &route53.GetHostedZoneOutput{
+		HostedZone: &route53.HostedZone{Id: input.Id, Name: aws.String(f.zoneName)},
+	}, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSetsWithContext(ctx aws.Context, input *route53.ListResourceRecordSetsInput, opts ...request.Option) (*route53.ListResourceRecordSetsOutput, error) {
+	resp := &route53.ListResourceRecordSetsOutput{}
+	for _, recordSet := range f.recordSets {
+		if aws.StringValue(recordSet.Name) == aws.StringValue(input.StartRecordName) {
+			resp.ResourceRecordSets = append(resp.ResourceRecordSets, recordSet)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeRoute53) ChangeResourceRecordSetsWithContext(ctx aws.Context, input *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error) {
+	f.changeRecordSetCalls++
+	for _, change := range input.ChangeBatch.Changes {
+		key := aws.StringValue(change.ResourceRecordSet.Name) + "|" + aws.StringValue(change.ResourceRecordSet.Type)
+		switch aws.StringValue(change.Action) {
+		case route53.ChangeActionUpsert:
+			f.recordSets[key] = change.ResourceRecordSet
+		case route53.ChangeActionDelete:
+			delete(f.recordSets, key)
+		}
+	}
+	return &route53.ChangeResourceRecordSetsOutput{}, nil
+}
+
+func (f *fakeRoute53) recordValue(name string, recordType string) string {
+	recordSet, ok := f.recordSets[name+"|"+recordType]
+	if !ok {
+		return ""
+	}
+	var values []string
+	for _, record := range recordSet.ResourceRecords {
+		values = append(values, aws.StringValue(record.Value))
+	}
+	return strings.Join(values, ",")
+}
+
+func Test_route53DNSManager_ReconcileAndCleanup(t *testing.T) {
+	foreignARecordSet := &route53.ResourceRecordSet{
+		Name:            aws.String("my-svc.mesh.local."),
+		Type:            aws.String(route53.RRTypeA),
+		TTL:             aws.Int64(60),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("192.168.0.1")}},
+	}
+	tests := []struct {
+		name              string
+		awsName           string
+		recordSets        map[string]*route53.ResourceRecordSet
+		wantARecordValue  string
+		wantManaged       bool
+		wantChangeCalls   int
+		wantCleanupRecord bool
+	}{
+		{
+			name:             "creates records within hosted zone",
+			awsName:          "my-svc.mesh.local",
+			wantARecordValue: "10.10.10.10",
+			wantManaged:      true,
+			wantChangeCalls:  2,
+		},
+		{
+			name:            "ignores names outside of hosted zone",
+			awsName:         "my-svc.my-ns.svc.cluster.local",
+			wantChangeCalls: 0,
+		},
+		{
+			name:    "leaves records not managed by virtualService alone",
+			awsName: "My-Svc.mesh.local.",
+			recordSets: map[string]*route53.ResourceRecordSet{
+				"my-svc.mesh.local.|A": foreignARecordSet,
+			},
+			wantARecordValue:  "192.168.0.1",
+			wantChangeCalls:   0,
+			wantCleanupRecord: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sdk := &fakeRoute53{zoneName: "Mesh.Local.", recordSets: map[string]*route53.ResourceRecordSet{}}
+			for key, recordSet := range tt.recordSets {
+				sdk.recordSets[key] = recordSet
+			}
+			m := newRoute53DNSManager(DNSConfig{Route53HostedZoneID: "Z123", RecordIP: "10.10.10.10", RecordTTL: 300}, sdk, logr.New(&log.NullLogSink{}))
+			vs := newTestVirtualService(tt.awsName)
+
+			assert.NoError(t, m.Reconcile(ctx, vs))
+			assert.NoError(t, m.Reconcile(ctx, vs))
+			assert.Equal(t, tt.wantARecordValue, sdk.recordValue("my-svc.mesh.local.", route53.RRTypeA))
+			if tt.wantManaged {
+				assert.Equal(t, `"appmesh.k8s.aws/managed-by=appmesh-controller,appmesh.k8s.aws/virtualService=my-ns/my-vs"`,
+					sdk.recordValue("my-svc.mesh.local.", route53.RRTypeTxt))
+			}
+
+			assert.NoError(t, m.Cleanup(ctx, vs))
+			assert.Equal(t, tt.wantCleanupRecord, len(sdk.recordSets) != 0)
+			assert.Equal(t, tt.wantChangeCalls, sdk.changeRecordSetCalls, "unchanged records aren't upserted again")
+			assert.Equal(t, 1, sdk.getHostedZoneCalls, "hosted zone is described once")
+		})
+	}
+}
+
+func Test_route53DNSManager_Reconcile_updatesRecords(t *testing.T) {
+	ctx := context.Background()
+	sdk := &fakeRoute53{zoneName: "mesh.local.", recordSets: map[string]*route53.ResourceRecordSet{}}
+	vs := newTestVirtualService("my-svc.mesh.local")
+	m := newRoute53DNSManager(DNSConfig{Route53HostedZoneID: "Z123", RecordIP: "10.10.10.10", RecordTTL: 300}, sdk, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, m.Reconcile(ctx, vs))
+
+	m = newRoute53DNSManager(DNSConfig{Route53HostedZoneID: "Z123", RecordIP: "10.0.0.1", RecordTTL: 300}, sdk, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, m.Reconcile(ctx, vs))
+	assert.Equal(t, "10.0.0.1", sdk.recordValue("my-svc.mesh.local.", route53.RRTypeA))
+	assert.Equal(t, 2, sdk.changeRecordSetCalls)
+}
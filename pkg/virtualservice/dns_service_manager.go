@@ -0,0 +1,143 @@
+package virtualservice
+
+import (
+	"context"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// placeholderServicePort is the port of placeholder Services, its value doesn't matter since Envoy intercepts traffic to them.
+	placeholderServicePort = 80
+)
+
+func newServiceDNSManager(cfg DNSConfig, k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger) *serviceDNSManager {
+	return &serviceDNSManager{
+		clusterDomain: strings.ToLower(strings.Trim(cfg.ClusterDomain, ".")),
+		k8sClient:     k8sClient,
+		scheme:        scheme,
+		log:           log,
+	}
+}
+
+var _ DNSManager = &serviceDNSManager{}
+
+// serviceDNSManager resolves awsNames of the form <name>.<namespace>.svc.<cluster-domain> with selector-less ClusterIP Services.
+// Services are only managed in the namespace of their VirtualService, so VirtualServices can't create Services in namespaces of others,
+// and they're owned by their VirtualService, so they are garbage collected upon deletion.
+type serviceDNSManager struct {
+	clusterDomain string
+	k8sClient     client.Client
+	scheme        *runtime.Scheme
+	log           logr.Logger
+}
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+
+func (m *serviceDNSManager) Reconcile(ctx context.Context, vs *appmesh.VirtualService) error {
+	svcKey, ok := m.placeholderServiceKey(vs)
+	if !ok {
+		return nil
+	}
+	svc := &corev1.Service{}
+	if err := m.k8sClient.Get(ctx, svcKey, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get placeholder service %v", svcKey)
+		}
+		return m.createPlaceholderService(ctx, vs, svcKey)
+	}
+	if !isPlaceholderServiceOf(svc, vs) {
+		m.log.V(1).Info("awsName is resolved by service not managed by virtualService, skipping placeholder service",
+			"virtualService", k8s.NamespacedName(vs),
+			"service", svcKey,
+		)
+	}
+	return nil
+}
+
+func (m *serviceDNSManager) Cleanup(ctx context.Context, vs *appmesh.VirtualService) error {
+	svcKey, ok := m.placeholderServiceKey(vs)
+	if !ok {
+		return nil
+	}
+	svc := &corev1.Service{}
+	if err := m.k8sClient.Get(ctx, svcKey, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get placeholder service %v", svcKey)
+	}
+	if !isPlaceholderServiceOf(svc, vs) {
+		return nil
+	}
+	if err := m.k8sClient.Delete(ctx, svc); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete placeholder service %v", svcKey)
+	}
+	m.log.V(1).Info("deleted placeholder service",
+		"virtualService", k8s.NamespacedName(vs),
+		"service", svcKey,
+	)
+	return nil
+}
+
+func (m *serviceDNSManager) createPlaceholderService(ctx context.Context, vs *appmesh.VirtualService, svcKey types.NamespacedName) error {
+	svc := &corev1.Service{}
+	svc.Namespace = svcKey.Namespace
+	svc.Name = svcKey.Name
+	svc.Labels = map[string]string{LabelManagedBy: managedByValue}
+	svc.Annotations = map[string]string{AnnotationVirtualService: ownerValue(vs)}
+	svc.Spec = corev1.ServiceSpec{
+		Type: corev1.ServiceTypeClusterIP,
+		Ports: []corev1.ServicePort{
+			{
+				Name:       "http",
+				Protocol:   corev1.ProtocolTCP,
+				Port:       placeholderServicePort,
+				TargetPort: intstr.FromInt(placeholderServicePort),
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(vs, svc, m.scheme); err != nil {
+		return err
+	}
+	if err := m.k8sClient.Create(ctx, svc); err != nil {
+		return errors.Wrapf(err, "failed to create placeholder service %v", svcKey)
+	}
+	m.log.V(1).Info("created placeholder service",
+		"virtualService", k8s.NamespacedName(vs),
+		"service", svcKey,
+	)
+	return nil
+}
+
+// placeholderServiceKey returns the key of Service resolving the awsName of vs.
+// returns false if the awsName isn't of the form <name>.<namespace>.svc.<cluster-domain>, or the namespace isn't the one of vs.
+func (m *serviceDNSManager) placeholderServiceKey(vs *appmesh.VirtualService) (types.NamespacedName, bool) {
+	name := dnsName(vs)
+	suffix := ".svc." + m.clusterDomain
+	if !strings.HasSuffix(name, suffix) {
+		return types.NamespacedName{}, false
+	}
+	labels := strings.Split(strings.TrimSuffix(name, suffix), ".")
+	if len(labels) != 2 || len(validation.IsDNS1035Label(labels[0])) != 0 || labels[1] != vs.Namespace {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: labels[1], Name: labels[0]}, true
+}
+
+// isPlaceholderServiceOf checks whether svc is the placeholder Service managed for vs.
+func isPlaceholderServiceOf(svc *corev1.Service, vs *appmesh.VirtualService) bool {
+	return svc.Labels[LabelManagedBy] == managedByValue && svc.Annotations[AnnotationVirtualService] == ownerValue(vs)
+}
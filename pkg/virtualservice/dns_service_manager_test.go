@@ -0,0 +1,168 @@
+package virtualservice
+
+import (
+	"context"
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestVirtualService(awsName string) *appmesh.VirtualService {
+	return &appmesh.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "my-vs",
+			UID:       "vs-uid",
+		},
+		Spec: appmesh.VirtualServiceSpec{
+			AWSName: aws.String(awsName),
+		},
+	}
+}
+
+func Test_serviceDNSManager_placeholderServiceKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		awsName string
+		want    types.NamespacedName
+		wantOK  bool
+	}{
+		{
+			name:    "service of the same namespace",
+			awsName: "my-svc.my-ns.svc.cluster.local",
+			want:    types.NamespacedName{Namespace: "my-ns", Name: "my-svc"},
+			wantOK:  true,
+		},
+		{
+			name:    "service of the same namespace, with trailing dot and upper case",
+			awsName: "My-Svc.My-Ns.svc.cluster.local.",
+			want:    types.NamespacedName{Namespace: "my-ns", Name: "my-svc"},
+			wantOK:  true,
+		},
+		{
+			name:    "service of other namespace",
+			awsName: "my-svc.other-ns.svc.cluster.local",
+		},
+		{
+			name:    "name outside of cluster domain",
+			awsName: "my-svc.my-ns.svc.example.com",
+		},
+		{
+			name:    "name with extra labels",
+			awsName: "api.my-svc.my-ns.svc.cluster.local",
+		},
+		{
+			name:    "name that isn't a valid service name",
+			awsName: "1-svc.my-ns.svc.cluster.local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServiceDNSManager(DNSConfig{ClusterDomain: "cluster.local."}, nil, nil, logr.New(&log.NullLogSink{}))
+			got, gotOK := m.placeholderServiceKey(newTestVirtualService(tt.awsName))
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_serviceDNSManager_ReconcileAndCleanup(t *testing.T) {
+	tests := []struct {
+		name            string
+		awsName         string
+		existingService *corev1.Service
+		wantCreated     bool
+	}{
+		{
+			name:        "creates owned service in the same namespace",
+			awsName:     "my-svc.my-ns.svc.cluster.local",
+			wantCreated: true,
+		},
+		{
+			name:    "leaves existing service alone",
+			awsName: "my-svc.my-ns.svc.cluster.local",
+			existingService: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-svc", Labels: map[string]string{"app": "my-app"}},
+				Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "my-app"}},
+			},
+		},
+		{
+			name:    "leaves placeholder service of other virtualService alone",
+			awsName: "my-svc.my-ns.svc.cluster.local",
+			existingService: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "my-ns",
+					Name:        "my-svc",
+					Labels:      map[string]string{LabelManagedBy: managedByValue},
+					Annotations: map[string]string{AnnotationVirtualService: "my-ns/other-vs"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			if tt.existingService != nil {
+				assert.NoError(t, k8sClient.Create(ctx, tt.existingService.DeepCopy()))
+			}
+			m := newServiceDNSManager(DNSConfig{ClusterDomain: "cluster.local"}, k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+			vs := newTestVirtualService(tt.awsName)
+			svcKey, _ := m.placeholderServiceKey(vs)
+
+			assert.NoError(t, m.Reconcile(ctx, vs))
+			assert.NoError(t, m.Reconcile(ctx, vs))
+			svc := &corev1.Service{}
+			assert.NoError(t, k8sClient.Get(ctx, svcKey, svc))
+			if tt.wantCreated {
+				assert.Equal(t, managedByValue, svc.Labels[LabelManagedBy])
+				assert.Equal(t, "my-ns/my-vs", svc.Annotations[AnnotationVirtualService])
+				assert.Equal(t, corev1.ServiceTypeClusterIP, svc.Spec.Type)
+				assert.Empty(t, svc.Spec.Selector)
+				assert.Len(t, svc.OwnerReferences, 1)
+			} else {
+				assert.Equal(t, tt.existingService.Labels, svc.Labels)
+				assert.Equal(t, tt.existingService.Annotations, svc.Annotations)
+			}
+
+			assert.NoError(t, m.Cleanup(ctx, vs))
+			err := k8sClient.Get(ctx, svcKey, &corev1.Service{})
+			if tt.wantCreated {
+				assert.True(t, apierrors.IsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, m.Cleanup(ctx, vs))
+		})
+	}
+}
+
+func Test_serviceDNSManager_Reconcile_otherNamespace(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	appmesh.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	m := newServiceDNSManager(DNSConfig{ClusterDomain: "cluster.local"}, k8sClient, k8sSchema, logr.New(&log.NullLogSink{}))
+	vs := newTestVirtualService("my-svc.other-ns.svc.cluster.local")
+
+	assert.NoError(t, m.Reconcile(ctx, vs))
+	svcList := &corev1.ServiceList{}
+	assert.NoError(t, k8sClient.List(ctx, svcList))
+	assert.Empty(t, svcList.Items)
+	assert.NoError(t, m.Cleanup(ctx, vs))
+}
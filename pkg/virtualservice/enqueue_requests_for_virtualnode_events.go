@@ -29,7 +29,9 @@ type enqueueRequestsForVirtualNodeEvents struct {
 
 // Create is called in response to an create event
 func (h *enqueueRequestsForVirtualNodeEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualServices referencing a virtualNode that's not found yet wait for its creation.
+	vn := e.Object.(*appmesh.VirtualNode)
+	h.enqueueVirtualServicesForVirtualNode(context.Background(), queue, vn)
 }
 
 // Update is called in response to an update event
@@ -46,7 +48,9 @@ func (h *enqueueRequestsForVirtualNodeEvents) Update(e event.UpdateEvent, queue
 
 // Delete is called in response to a delete event
 func (h *enqueueRequestsForVirtualNodeEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualServices referencing a deleted virtualNode report it as unresolved.
+	vn := e.Object.(*appmesh.VirtualNode)
+	h.enqueueVirtualServicesForVirtualNode(context.Background(), queue, vn)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
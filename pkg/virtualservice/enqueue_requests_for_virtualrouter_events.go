@@ -29,7 +29,9 @@ type enqueueRequestsForVirtualRouterEvents struct {
 
 // Create is called in response to an create event
 func (h *enqueueRequestsForVirtualRouterEvents) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualServices referencing a virtualRouter that's not found yet wait for its creation.
+	vr := e.Object.(*appmesh.VirtualRouter)
+	h.enqueueVirtualServicesForVirtualRouter(context.Background(), queue, vr)
 }
 
 // Update is called in response to an update event
@@ -46,7 +48,9 @@ func (h *enqueueRequestsForVirtualRouterEvents) Update(e event.UpdateEvent, queu
 
 // Delete is called in response to a delete event
 func (h *enqueueRequestsForVirtualRouterEvents) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	// no-op
+	// virtualServices referencing a deleted virtualRouter report it as unresolved.
+	vr := e.Object.(*appmesh.VirtualRouter)
+	h.enqueueVirtualServicesForVirtualRouter(context.Background(), queue, vr)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
@@ -4,12 +4,16 @@ import (
 	"context"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/audit"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/aws/services"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/conversions"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/equality"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/runtime"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/tagging"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,9 +21,8 @@ import (
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +35,9 @@ type ResourceManager interface {
 
 	// Cleanup will delete AppMesh VirtualService created for vs.
 	Cleanup(ctx context.Context, vs *appmesh.VirtualService) error
+
+	// Diff will compare AppMesh VirtualService against the spec desired for vs, without changing it.
+	Diff(ctx context.Context, vs *appmesh.VirtualService) ([]equality.SpecDiff, error)
 }
 
 func NewDefaultResourceManager(
@@ -39,12 +45,16 @@ func NewDefaultResourceManager(
 	appMeshSDK services.AppMesh,
 	referencesResolver references.Resolver,
 	accountID string,
+	tagsManager tagging.Manager,
+	quotaChecker quota.Checker,
 	log logr.Logger) ResourceManager {
 	return &defaultResourceManager{
 		k8sClient:          k8sClient,
 		appMeshSDK:         appMeshSDK,
 		referencesResolver: referencesResolver,
 		accountID:          accountID,
+		tagsManager:        tagsManager,
+		quotaChecker:       quotaChecker,
 		log:                log,
 	}
 }
@@ -54,45 +64,56 @@ type defaultResourceManager struct {
 	appMeshSDK         services.AppMesh
 	referencesResolver references.Resolver
 	accountID          string
+	tagsManager        tagging.Manager
+	quotaChecker       quota.Checker
 	log                logr.Logger
 }
 
 func (m *defaultResourceManager) Reconcile(ctx context.Context, vs *appmesh.VirtualService) error {
 	ms, err := m.findMeshDependency(ctx, vs)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateMeshDependencies(ctx, ms); err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 	vnByKey, err := m.findVirtualNodeDependencies(ctx, vs)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualNodeDependencies(ctx, ms, vnByKey); err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 	vrByKey, err := m.findVirtualRouterDependencies(ctx, vs)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesUnresolved, err)
 	}
 	if err := m.validateVirtualRouterDependencies(ctx, ms, vrByKey); err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionReferencesResolved, appmesh.ReasonReferencesNotReady, err)
 	}
 
 	sdkVS, err := m.findSDKVirtualService(ctx, ms, vs)
 	if err != nil {
-		return err
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+	}
+	if err := m.quotaChecker.CheckVirtualService(ctx, ms, vs, sdkVS == nil); err != nil {
+		return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonQuotaExceeded, err)
 	}
 	if sdkVS == nil {
 		sdkVS, err = m.createSDKVirtualService(ctx, ms, vs, vnByKey, vrByKey)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	} else {
+		if err := m.checkSDKVirtualServiceOwnership(ctx, sdkVS, vs); err != nil {
+			return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
 		sdkVS, err = m.updateSDKVirtualService(ctx, sdkVS, vs, vnByKey, vrByKey)
 		if err != nil {
-			return err
+			return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
+		}
+		if err := m.reconcileSDKVirtualServiceTags(ctx, sdkVS, vs); err != nil {
+			return m.updateCRDVirtualServiceForFailure(ctx, vs, appmesh.ConditionSynced, appmesh.ReasonSyncFailed, err)
 		}
 	}
 	return m.updateCRDVirtualService(ctx, vs, sdkVS)
@@ -113,9 +134,50 @@ func (m *defaultResourceManager) Cleanup(ctx context.Context, vs *appmesh.Virtua
 	if sdkVS == nil {
 		return nil
 	}
+	if err := m.checkSDKVirtualServiceOwnership(ctx, sdkVS, vs); err != nil {
+		if !tagging.IsOwnershipConflict(err) {
+			return err
+		}
+		m.log.V(1).Info("skip virtualService deletion since it's owned by another controller",
+			"virtualService", k8s.NamespacedName(vs),
+			"reason", err.Error(),
+		)
+		return nil
+	}
 	return m.deleteSDKVirtualService(ctx, sdkVS, vs)
 }
 
+func (m *defaultResourceManager) Diff(ctx context.Context, vs *appmesh.VirtualService) ([]equality.SpecDiff, error) {
+	ms, err := m.findMeshDependency(ctx, vs)
+	if err != nil {
+		return nil, err
+	}
+	vnByKey, err := m.findVirtualNodeDependencies(ctx, vs)
+	if err != nil {
+		return nil, err
+	}
+	vrByKey, err := m.findVirtualRouterDependencies(ctx, vs)
+	if err != nil {
+		return nil, err
+	}
+	desiredSDKVSSpec, err := BuildSDKVirtualServiceSpec(vs, vnByKey, vrByKey)
+	if err != nil {
+		return nil, err
+	}
+	sdkVS, err := m.findSDKVirtualService(ctx, ms, vs)
+	if err != nil {
+		return nil, err
+	}
+	var actualSDKVSSpec *appmeshsdk.VirtualServiceSpec
+	if sdkVS != nil {
+		actualSDKVSSpec = sdkVS.Spec
+	}
+	resource := "virtualService/" + aws.StringValue(vs.Spec.AWSName)
+	return []equality.SpecDiff{
+		equality.DiffSpec(resource, desiredSDKVSSpec, actualSDKVSSpec, sdkVS != nil, equality.CompareOptionForVirtualServiceSpec()),
+	}, nil
+}
+
 // findMeshDependency find the Mesh dependency for this VirtualService.
 func (m *defaultResourceManager) findMeshDependency(ctx context.Context, vs *appmesh.VirtualService) (*appmesh.Mesh, error) {
 	if vs.Spec.MeshRef == nil {
@@ -146,6 +208,10 @@ func (m *defaultResourceManager) findVirtualNodeDependencies(ctx context.Context
 		}
 		vn, err := m.referencesResolver.ResolveVirtualNodeReference(ctx, vs, vnRef)
 		if err != nil {
+			// virtualNode events enqueue the referencing objects once it's created.
+			if apierrors.IsNotFound(err) {
+				return nil, runtime.NewDependencyNotReadyError(errors.Wrapf(err, "failed to resolve virtualNodeRef"))
+			}
 			return nil, errors.Wrapf(err, "failed to resolve virtualNodeRef")
 		}
 		vnByKey[vnKey] = vn
@@ -159,7 +225,7 @@ func (m *defaultResourceManager) validateVirtualNodeDependencies(ctx context.Con
 			return errors.Errorf("virtualNode %v didn't belong to mesh %v", k8s.NamespacedName(vn), k8s.NamespacedName(ms))
 		}
 		if !virtualnode.IsVirtualNodeActive(vn) {
-			return runtime.NewRequeueError(errors.New("virtualNode is not active yet"))
+			return runtime.NewDependencyNotReadyError(errors.Errorf("virtualNode %v is not active yet", k8s.NamespacedName(vn)))
 		}
 	}
 	return nil
@@ -175,6 +241,10 @@ func (m *defaultResourceManager) findVirtualRouterDependencies(ctx context.Conte
 		}
 		vr, err := m.referencesResolver.ResolveVirtualRouterReference(ctx, vs, vrRef)
 		if err != nil {
+			// virtualRouter events enqueue the referencing objects once it's created.
+			if apierrors.IsNotFound(err) {
+				return nil, runtime.NewDependencyNotReadyError(errors.Wrapf(err, "failed to resolve virtualRouterRef"))
+			}
 			return nil, errors.Wrapf(err, "failed to resolve virtualRouterRef")
 		}
 		vrByKey[vrKey] = vr
@@ -188,7 +258,7 @@ func (m *defaultResourceManager) validateVirtualRouterDependencies(ctx context.C
 			return errors.Errorf("virtualRouter %v didn't belong to mesh %v", k8s.NamespacedName(vr), k8s.NamespacedName(ms))
 		}
 		if !virtualrouter.IsVirtualRouterActive(vr) {
-			return runtime.NewRequeueError(errors.New("virtualRouter is not active yet"))
+			return runtime.NewDependencyNotReadyError(errors.Errorf("virtualRouter %v is not active yet", k8s.NamespacedName(vr)))
 		}
 	}
 	return nil
@@ -221,6 +291,7 @@ func (m *defaultResourceManager) createSDKVirtualService(ctx context.Context, ms
 		MeshOwner:          ms.Spec.MeshOwner,
 		VirtualServiceName: vs.Spec.AWSName,
 		Spec:               sdkVSSpec,
+		Tags:               m.buildSDKVirtualServiceTags(ctx, vs),
 	})
 	if err != nil {
 		return nil, err
@@ -236,7 +307,7 @@ func (m *defaultResourceManager) updateSDKVirtualService(ctx context.Context, sd
 		return nil, err
 	}
 
-	opts := cmpopts.EquateEmpty()
+	opts := equality.CompareOptionForVirtualServiceSpec()
 	if cmp.Equal(desiredSDKVSSpec, actualSDKVSSpec, opts) {
 		return sdkVS, nil
 	}
@@ -255,7 +326,7 @@ func (m *defaultResourceManager) updateSDKVirtualService(ctx context.Context, sd
 		"desiredSDKVRSpec", desiredSDKVSSpec,
 		"diff", diff,
 	)
-	resp, err := m.appMeshSDK.UpdateVirtualServiceWithContext(ctx, &appmeshsdk.UpdateVirtualServiceInput{
+	resp, err := m.appMeshSDK.UpdateVirtualServiceWithContext(audit.WithDiff(ctx, diff), &appmeshsdk.UpdateVirtualServiceInput{
 		MeshName:           sdkVS.MeshName,
 		MeshOwner:          sdkVS.Metadata.MeshOwner,
 		VirtualServiceName: sdkVS.VirtualServiceName,
@@ -293,16 +364,21 @@ func (m *defaultResourceManager) updateCRDVirtualService(ctx context.Context, vs
 		vs.Status.VirtualServiceARN = sdkVS.Metadata.Arn
 		needsUpdate = true
 	}
+	resourceMetadata := &appmesh.AWSResourceMetadata{}
+	if err := conversions.Convert_SDK_ResourceMetadata_To_CRD_AWSResourceMetadata(sdkVS.Metadata, resourceMetadata); err != nil {
+		return err
+	}
+	if !cmp.Equal(vs.Status.ResourceMetadata, resourceMetadata) {
+		vs.Status.ResourceMetadata = resourceMetadata
+		needsUpdate = true
+	}
 	if aws.Int64Value(vs.Status.ObservedGeneration) != vs.Generation {
 		vs.Status.ObservedGeneration = aws.Int64(vs.Generation)
 		needsUpdate = true
 	}
 
-	vsActiveConditionStatus := corev1.ConditionFalse
-	if sdkVS.Status != nil && aws.StringValue(sdkVS.Status.Status) == appmeshsdk.VirtualServiceStatusCodeActive {
-		vsActiveConditionStatus = corev1.ConditionTrue
-	}
-	if updateCondition(vs, appmesh.VirtualServiceActive, vsActiveConditionStatus, nil, nil) {
+	vsActive := sdkVS.Status != nil && aws.StringValue(sdkVS.Status.Status) == appmeshsdk.VirtualServiceStatusCodeActive
+	if updateConditionsForReconciled(vs, vsActive) {
 		needsUpdate = true
 	}
 
@@ -312,6 +388,42 @@ func (m *defaultResourceManager) updateCRDVirtualService(ctx context.Context, vs
 	return m.k8sClient.Status().Patch(ctx, vs, client.MergeFrom(oldVS))
 }
 
+// updateCRDVirtualServiceForFailure will record reconcile failure err into virtualService's conditions, and returns err.
+func (m *defaultResourceManager) updateCRDVirtualServiceForFailure(ctx context.Context, vs *appmesh.VirtualService, failedConditionType string, reason string, err error) error {
+	err = runtime.ClassifyAWSError(err)
+	oldVS := vs.DeepCopy()
+	if !updateConditionsForFailure(vs, failedConditionType, reason, err) {
+		return err
+	}
+	if updateErr := m.k8sClient.Status().Patch(ctx, vs, client.MergeFrom(oldVS)); updateErr != nil {
+		m.log.Error(updateErr, "failed to update conditions", "virtualService", k8s.NamespacedName(vs))
+	}
+	return err
+}
+
+func (m *defaultResourceManager) buildSDKVirtualServiceTags(ctx context.Context, vs *appmesh.VirtualService) []*appmeshsdk.TagRef {
+	return m.tagsManager.BuildTags(vs)
+}
+
+// reconcileSDKVirtualServiceTags corrects tags of AppMesh virtualService if it's controlled by CRD virtualService.
+func (m *defaultResourceManager) reconcileSDKVirtualServiceTags(ctx context.Context, sdkVS *appmeshsdk.VirtualServiceData, vs *appmesh.VirtualService) error {
+	if !m.isSDKVirtualServiceControlledByCRDVirtualService(ctx, sdkVS, vs) {
+		return nil
+	}
+	if err := m.tagsManager.ReconcileTags(ctx, aws.StringValue(sdkVS.Metadata.Arn), vs); err != nil {
+		return errors.Wrap(err, "failed to reconcile tags")
+	}
+	return nil
+}
+
+// checkSDKVirtualServiceOwnership checks AppMesh virtualService isn't owned by another controller if it's controlled by CRD virtualService.
+func (m *defaultResourceManager) checkSDKVirtualServiceOwnership(ctx context.Context, sdkVS *appmeshsdk.VirtualServiceData, vs *appmesh.VirtualService) error {
+	if !m.isSDKVirtualServiceControlledByCRDVirtualService(ctx, sdkVS, vs) {
+		return nil
+	}
+	return m.tagsManager.CheckOwnership(ctx, aws.StringValue(sdkVS.Metadata.Arn), vs)
+}
+
 // isSDKVirtualServiceControlledByCRDVirtualService checks whether an AppMesh VirtualService is controlled by CRD VirtualService.
 // if it's controlled, CRD VirtualService update is responsible for updating the AppMesh VirtualService.
 func (m *defaultResourceManager) isSDKVirtualServiceControlledByCRDVirtualService(ctx context.Context, sdkVS *appmeshsdk.VirtualServiceData, vs *appmesh.VirtualService) bool {
@@ -2,7 +2,7 @@ package virtualservice
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IsVirtualServiceActive checks whether given virtualService is active.
@@ -10,7 +10,7 @@ import (
 func IsVirtualServiceActive(vs *appmesh.VirtualService) bool {
 	for _, condition := range vs.Status.Conditions {
 		if condition.Type == appmesh.VirtualServiceActive {
-			return condition.Status == corev1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue
 		}
 	}
 	return false
@@ -0,0 +1,524 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	flagWebhookCertSecret   = "webhook-cert-secret"
+	flagWebhookCertService  = "webhook-cert-service"
+	flagWebhookCertValidity = "webhook-cert-validity"
+
+	defaultWebhookCertValidity = 365 * 24 * time.Hour
+	minWebhookCertValidity     = time.Hour
+
+	// how often the certificate is checked for rotation, and reloaded from the Secret in case another replica rotated it.
+	certCheckPeriod = 10 * time.Minute
+	// the CA is valid for this many times as long as the certificates it signs, so that certificates are rotated without changing CA bundles.
+	caValidityFactor = 10
+	// certificates and CAs are rotated once less than 1/certRotationDivisor of their validity remains.
+	certRotationDivisor = 5
+
+	secretKeyCABundle = "ca.crt"
+	secretKeyCAKey    = "ca.key"
+	secretKeyCert     = corev1.TLSCertKey
+	secretKeyKey      = corev1.TLSPrivateKeyKey
+)
+
+type CertConfig struct {
+	// Secret is the namespace/name of the Secret storing the webhook serving certificate.
+	// if set, the certificate is generated and rotated by the controller, which injects its CA into webhook configurations,
+	// instead of being provided by Helm or cert-manager.
+	Secret string
+	// Service is the namespace/name of the webhook service, whose DNS names the certificate is issued for.
+	Service string
+	// Validity is how long generated certificates are valid.
+	Validity time.Duration
+}
+
+func (cfg *CertConfig) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.Secret, flagWebhookCertSecret, "",
+		"The namespace/name of the Secret storing the webhook serving certificate. If set, the certificate is generated, rotated and injected into webhook configurations by the controller")
+	fs.StringVar(&cfg.Service, flagWebhookCertService, "",
+		"The namespace/name of the webhook service the generated webhook serving certificate is issued for")
+	fs.DurationVar(&cfg.Validity, flagWebhookCertValidity, defaultWebhookCertValidity,
+		"How long generated webhook serving certificates are valid, they're rotated once less than a fifth of it remains")
+}
+
+func (cfg *CertConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if len(cfg.Service) == 0 {
+		return errors.Errorf("%s must be set along with %s", flagWebhookCertService, flagWebhookCertSecret)
+	}
+	if cfg.Validity < minWebhookCertValidity {
+		return errors.Errorf("%s must be at least %v: %v", flagWebhookCertValidity, minWebhookCertValidity, cfg.Validity)
+	}
+	return nil
+}
+
+// Enabled returns whether the webhook serving certificate is managed by the controller.
+func (cfg *CertConfig) Enabled() bool {
+	return len(cfg.Secret) != 0
+}
+
+// NewCertRotator constructs a CertRotator that manages the webhook serving certificate in certDir.
+// apiReader is used to read the Secret and webhook configurations, they're read before the manager's cache is started.
+func NewCertRotator(cfg CertConfig, k8sClient client.Client, apiReader client.Reader, certDir string, log logr.Logger) (*CertRotator, error) {
+	secretKey, err := parseNamespacedName(flagWebhookCertSecret, cfg.Secret)
+	if err != nil {
+		return nil, err
+	}
+	serviceKey, err := parseNamespacedName(flagWebhookCertService, cfg.Service)
+	if err != nil {
+		return nil, err
+	}
+	return &CertRotator{
+		k8sClient:  k8sClient,
+		apiReader:  apiReader,
+		secretKey:  secretKey,
+		serviceKey: serviceKey,
+		validity:   cfg.Validity,
+		certDir:    certDir,
+		now:        time.Now,
+		log:        log,
+	}, nil
+}
+
+var _ manager.Runnable = &CertRotator{}
+var _ manager.LeaderElectionRunnable = &CertRotator{}
+
+// CertRotator generates and rotates the webhook serving certificate, which is stored in a Secret shared by all replicas.
+// Each replica writes the certificate into the certificate directory of its webhook server, which reloads it upon changes,
+// and injects the CA bundle into webhook configurations referring to the webhook service.
+// Certificates are signed by a CA that's valid for much longer, so that rotated certificates are trusted by the CA bundle already.
+// Upon rotation of the CA, the CA bundle also contains the previous CA until the next one, so both certificates are trusted
+// while replicas haven't reloaded the Secret yet.
+type CertRotator struct {
+	k8sClient  client.Client
+	apiReader  client.Reader
+	secretKey  types.NamespacedName
+	serviceKey types.NamespacedName
+	validity   time.Duration
+	certDir    string
+	now        func() time.Time
+	log        logr.Logger
+}
+
+// webhookCerts are the PEM encoded certificates and keys stored in the webhook certificate Secret.
+type webhookCerts struct {
+	// caBundle is the current CA, followed by the previous one if the CA has been rotated.
+	caBundle []byte
+	caKey    []byte
+	cert     []byte
+	key      []byte
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;get;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=list;patch
+
+func (r *CertRotator) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(certCheckPeriod):
+		}
+		if err := r.Rotate(ctx); err != nil {
+			r.log.Error(err, "failed to rotate webhook serving certificate")
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since every replica serves webhooks with the certificate.
+func (r *CertRotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Rotate makes sure the Secret stores a valid certificate, rotating it if it's about to expire,
+// then writes it into the certificate directory and injects its CA bundle into webhook configurations.
+// It must be called before the webhook server is started, which fails without a certificate.
+func (r *CertRotator) Rotate(ctx context.Context) error {
+	certs, err := r.reconcileSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.writeCertFiles(certs); err != nil {
+		return err
+	}
+	return r.injectCABundle(ctx, certs.caBundle)
+}
+
+func (r *CertRotator) reconcileSecret(ctx context.Context) (webhookCerts, error) {
+	secret := &corev1.Secret{}
+	if err := r.apiReader.Get(ctx, r.secretKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return webhookCerts{}, errors.Wrapf(err, "failed to get secret %v", r.secretKey)
+		}
+		secret = nil
+	}
+	var certs webhookCerts
+	if secret != nil {
+		certs = certsFromSecret(secret)
+	}
+	rotatedCerts, rotated, err := r.rotateCerts(certs)
+	if err != nil {
+		return webhookCerts{}, err
+	}
+	if !rotated {
+		return certs, nil
+	}
+
+	data := map[string][]byte{
+		secretKeyCABundle: rotatedCerts.caBundle,
+		secretKeyCAKey:    rotatedCerts.caKey,
+		secretKeyCert:     rotatedCerts.cert,
+		secretKeyKey:      rotatedCerts.key,
+	}
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: r.secretKey.Namespace, Name: r.secretKey.Name},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		err = r.k8sClient.Create(ctx, secret)
+	} else {
+		// the resourceVersion read makes the update fail if another replica rotated the certificate meanwhile.
+		secret.Data = data
+		err = r.k8sClient.Update(ctx, secret)
+	}
+	if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+		r.log.V(1).Info("webhook serving certificate is rotated by another replica", "secret", r.secretKey)
+		return r.readSecretCerts(ctx)
+	}
+	if err != nil {
+		return webhookCerts{}, errors.Wrapf(err, "failed to store webhook serving certificate in secret %v", r.secretKey)
+	}
+	r.log.Info("rotated webhook serving certificate", "secret", r.secretKey)
+	return rotatedCerts, nil
+}
+
+// readSecretCerts reads the certificate stored by another replica, which must be valid.
+func (r *CertRotator) readSecretCerts(ctx context.Context) (webhookCerts, error) {
+	secret := &corev1.Secret{}
+	if err := r.apiReader.Get(ctx, r.secretKey, secret); err != nil {
+		return webhookCerts{}, errors.Wrapf(err, "failed to get secret %v", r.secretKey)
+	}
+	certs := certsFromSecret(secret)
+	if _, rotated, err := r.rotateCerts(certs); err != nil || rotated {
+		return webhookCerts{}, errors.Errorf("secret %v doesn't store a valid webhook serving certificate", r.secretKey)
+	}
+	return certs, nil
+}
+
+func certsFromSecret(secret *corev1.Secret) webhookCerts {
+	return webhookCerts{
+		caBundle: secret.Data[secretKeyCABundle],
+		caKey:    secret.Data[secretKeyCAKey],
+		cert:     secret.Data[secretKeyCert],
+		key:      secret.Data[secretKeyKey],
+	}
+}
+
+// rotateCerts rotates the CA and certificate of certs that are invalid or about to expire. returns whether any is rotated.
+func (r *CertRotator) rotateCerts(certs webhookCerts) (webhookCerts, bool, error) {
+	now := r.now()
+	rotated := false
+	ca, caKey, err := parseCA(certs.caBundle, certs.caKey)
+	if err != nil || needsRotation(ca, now) {
+		caValidity := r.validity * caValidityFactor
+		newCA, newCAKey, caPEM, caKeyPEM, err := generateCA(now, caValidity)
+		if err != nil {
+			return webhookCerts{}, false, err
+		}
+		caBundle := caPEM
+		if ca != nil && now.Before(ca.NotAfter) {
+			caBundle = append(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+		}
+		ca, caKey = newCA, newCAKey
+		certs = webhookCerts{caBundle: caBundle, caKey: caKeyPEM}
+		rotated = true
+	}
+
+	dnsNames := r.dnsNames()
+	cert, err := parseCert(certs.cert, certs.key)
+	if err != nil || needsRotation(cert, now) || !isCertSignedFor(cert, ca, dnsNames, now) {
+		certPEM, keyPEM, err := generateCert(ca, caKey, dnsNames, now, r.validity)
+		if err != nil {
+			return webhookCerts{}, false, err
+		}
+		certs.cert, certs.key = certPEM, keyPEM
+		rotated = true
+	}
+	return certs, rotated, nil
+}
+
+// dnsNames returns the DNS names the webhook service is reached by.
+func (r *CertRotator) dnsNames() []string {
+	name, namespace := r.serviceKey.Name, r.serviceKey.Namespace
+	return []string{
+		name,
+		fmt.Sprintf("%s.%s", name, namespace),
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+	}
+}
+
+// writeCertFiles writes certs into the certificate directory, only files whose content changed are written
+// so that the webhook server doesn't reload them needlessly.
+func (r *CertRotator) writeCertFiles(certs webhookCerts) error {
+	if err := os.MkdirAll(r.certDir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create certificate directory")
+	}
+	// the key is written before the certificate, the webhook server reloads them once both match.
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{name: secretKeyCABundle, data: certs.caBundle},
+		{name: secretKeyKey, data: certs.key},
+		{name: secretKeyCert, data: certs.cert},
+	} {
+		path := filepath.Join(r.certDir, file.name)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, file.data) {
+			continue
+		}
+		if err := os.WriteFile(path, file.data, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write %s", path)
+		}
+	}
+	return nil
+}
+
+// injectCABundle sets caBundle on the client config of webhooks referring to the webhook service.
+func (r *CertRotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	mutatingConfigs := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := r.apiReader.List(ctx, mutatingConfigs); err != nil {
+		return errors.Wrap(err, "failed to list mutatingWebhookConfigurations")
+	}
+	for i := range mutatingConfigs.Items {
+		config := &mutatingConfigs.Items[i]
+		oldConfig := config.DeepCopy()
+		changed := false
+		for j := range config.Webhooks {
+			if r.setCABundle(&config.Webhooks[j].ClientConfig, caBundle) {
+				changed = true
+			}
+		}
+		if err := r.patchWebhookConfiguration(ctx, config, oldConfig, changed); err != nil {
+			return err
+		}
+	}
+
+	validatingConfigs := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := r.apiReader.List(ctx, validatingConfigs); err != nil {
+		return errors.Wrap(err, "failed to list validatingWebhookConfigurations")
+	}
+	for i := range validatingConfigs.Items {
+		config := &validatingConfigs.Items[i]
+		oldConfig := config.DeepCopy()
+		changed := false
+		for j := range config.Webhooks {
+			if r.setCABundle(&config.Webhooks[j].ClientConfig, caBundle) {
+				changed = true
+			}
+		}
+		if err := r.patchWebhookConfiguration(ctx, config, oldConfig, changed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCABundle sets caBundle on clientConfig if it refers to the webhook service. returns whether it's changed.
+func (r *CertRotator) setCABundle(clientConfig *admissionregistrationv1.WebhookClientConfig, caBundle []byte) bool {
+	if clientConfig.Service == nil || clientConfig.Service.Namespace != r.serviceKey.Namespace || clientConfig.Service.Name != r.serviceKey.Name {
+		return false
+	}
+	if bytes.Equal(clientConfig.CABundle, caBundle) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
+
+func (r *CertRotator) patchWebhookConfiguration(ctx context.Context, config client.Object, oldConfig client.Object, changed bool) error {
+	if !changed {
+		return nil
+	}
+	if err := r.k8sClient.Patch(ctx, config, client.MergeFromWithOptions(oldConfig, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to inject CA bundle into webhook configuration %s", config.GetName())
+	}
+	r.log.V(1).Info("injected CA bundle into webhook configuration", "name", config.GetName())
+	return nil
+}
+
+// needsRotation checks whether less than 1/certRotationDivisor of the validity of cert remains.
+func needsRotation(cert *x509.Certificate, now time.Time) bool {
+	validity := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotAfter.Add(-validity / certRotationDivisor))
+}
+
+// isCertSignedFor checks whether cert is signed by ca for all of dnsNames.
+func isCertSignedFor(cert *x509.Certificate, ca *x509.Certificate, dnsNames []string, now time.Time) bool {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, dnsName := range dnsNames {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: dnsName, Roots: roots, CurrentTime: now}); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCA parses the first certificate of caBundle, along with its key caKey.
+func parseCA(caBundle []byte, caKey []byte) (*x509.Certificate, crypto.Signer, error) {
+	ca, err := parseCertPEM(caBundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(caKey)
+	if block == nil {
+		return ca, nil, errors.New("invalid CA key PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return ca, nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return ca, nil, errors.New("CA key cannot sign")
+	}
+	return ca, signer, nil
+}
+
+// parseCert parses certPEM and checks that it matches keyPEM.
+func parseCert(certPEM []byte, keyPEM []byte) (*x509.Certificate, error) {
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, err
+	}
+	return parseCertPEM(certPEM)
+}
+
+func parseCertPEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// generateCA generates a self-signed CA valid for validity.
+func generateCA(now time.Time, validity time.Duration) (*x509.Certificate, crypto.Signer, []byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "failed to generate CA key")
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("appmesh-controller-ca@%d", now.Unix())},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "failed to generate CA")
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	keyPEM, err := encodeKeyPEM(key)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return ca, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// generateCert generates a serving certificate for dnsNames signed by ca, valid for validity.
+func generateCert(ca *x509.Certificate, caKey crypto.Signer, dnsNames []string, now time.Time, validity time.Duration) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate key")
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate certificate")
+	}
+	keyPEM, err := encodeKeyPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func encodeKeyPEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+	return serialNumber, nil
+}
+
+func parseNamespacedName(flag string, value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return types.NamespacedName{}, errors.Errorf("invalid %s %q, must be namespace/name", flag, value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
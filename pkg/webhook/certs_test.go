@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestCertConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CertConfig
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			cfg:  CertConfig{},
+		},
+		{
+			name: "enabled",
+			cfg:  CertConfig{Secret: "appmesh-system/webhook-server-cert", Service: "appmesh-system/webhook-service", Validity: defaultWebhookCertValidity},
+		},
+		{
+			name:    "enabled without service",
+			cfg:     CertConfig{Secret: "appmesh-system/webhook-server-cert", Validity: defaultWebhookCertValidity},
+			wantErr: "webhook-cert-service must be set along with webhook-cert-secret",
+		},
+		{
+			name:    "validity too short",
+			cfg:     CertConfig{Secret: "appmesh-system/webhook-server-cert", Service: "appmesh-system/webhook-service", Validity: time.Minute},
+			wantErr: "webhook-cert-validity must be at least 1h0m0s: 1m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewCertRotator(t *testing.T) {
+	_, err := NewCertRotator(CertConfig{Secret: "webhook-server-cert", Service: "appmesh-system/webhook-service"}, nil, nil, "", logr.Discard())
+	assert.EqualError(t, err, `invalid webhook-cert-secret "webhook-server-cert", must be namespace/name`)
+}
+
+func TestCertRotator_Rotate(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	clientgoscheme.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	webhookClientConfig := func(serviceName string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{
+			Service:  &admissionregistrationv1.ServiceReference{Namespace: "appmesh-system", Name: serviceName},
+			CABundle: []byte("Cg=="),
+		}
+	}
+	assert.NoError(t, k8sClient.Create(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "appmesh-mutating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "mpod.appmesh.k8s.aws", ClientConfig: webhookClientConfig("webhook-service")},
+		},
+	}))
+	assert.NoError(t, k8sClient.Create(ctx, &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "appmesh-validating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "vmesh.appmesh.k8s.aws", ClientConfig: webhookClientConfig("webhook-service")},
+			{Name: "other.example.com", ClientConfig: webhookClientConfig("other-service")},
+		},
+	}))
+
+	certDir := t.TempDir()
+	r, err := NewCertRotator(CertConfig{
+		Secret:   "appmesh-system/webhook-server-cert",
+		Service:  "appmesh-system/webhook-service",
+		Validity: 100 * time.Hour,
+	}, k8sClient, k8sClient, certDir, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	// the certificate is generated, written and injected.
+	assert.NoError(t, r.Rotate(ctx))
+	secret := &corev1.Secret{}
+	assert.NoError(t, k8sClient.Get(ctx, r.secretKey, secret))
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	caBundle := secret.Data[secretKeyCABundle]
+	cert := secret.Data[secretKeyCert]
+	assert.Equal(t, 1, countCerts(caBundle))
+	for name, data := range map[string][]byte{secretKeyCABundle: caBundle, secretKeyCert: cert, secretKeyKey: secret.Data[secretKeyKey]} {
+		fileData, err := os.ReadFile(filepath.Join(certDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, data, fileData)
+	}
+	assertCABundles(t, ctx, k8sClient, caBundle)
+	roots := x509.NewCertPool()
+	assert.True(t, roots.AppendCertsFromPEM(caBundle))
+	leaf, err := parseCertPEM(cert)
+	assert.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "webhook-service.appmesh-system.svc", Roots: roots, CurrentTime: now})
+	assert.NoError(t, err)
+
+	// a valid certificate is kept as is.
+	now = now.Add(70 * time.Hour)
+	assert.NoError(t, r.Rotate(ctx))
+	assert.NoError(t, k8sClient.Get(ctx, r.secretKey, secret))
+	assert.Equal(t, cert, secret.Data[secretKeyCert])
+
+	// the certificate is rotated once less than a fifth of its validity remains, but the CA bundle is kept.
+	now = now.Add(20 * time.Hour)
+	assert.NoError(t, r.Rotate(ctx))
+	assert.NoError(t, k8sClient.Get(ctx, r.secretKey, secret))
+	assert.NotEqual(t, cert, secret.Data[secretKeyCert])
+	assert.Equal(t, caBundle, secret.Data[secretKeyCABundle])
+
+	// the CA is rotated once less than a fifth of its validity remains, the previous CA stays in the bundle.
+	now = now.Add(900 * time.Hour)
+	assert.NoError(t, r.Rotate(ctx))
+	assert.NoError(t, k8sClient.Get(ctx, r.secretKey, secret))
+	rotatedCABundle := secret.Data[secretKeyCABundle]
+	assert.Equal(t, 2, countCerts(rotatedCABundle))
+	assert.Equal(t, caBundle, rotatedCABundle[len(rotatedCABundle)-len(caBundle):])
+	assertCABundles(t, ctx, k8sClient, rotatedCABundle)
+	fileData, err := os.ReadFile(filepath.Join(certDir, secretKeyCert))
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data[secretKeyCert], fileData)
+}
+
+func assertCABundles(t *testing.T, ctx context.Context, k8sClient client.Client, caBundle []byte) {
+	mutatingConfig := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "appmesh-mutating-webhook-configuration"}, mutatingConfig))
+	assert.Equal(t, caBundle, mutatingConfig.Webhooks[0].ClientConfig.CABundle)
+	validatingConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "appmesh-validating-webhook-configuration"}, validatingConfig))
+	assert.Equal(t, caBundle, validatingConfig.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, []byte("Cg=="), validatingConfig.Webhooks[1].ClientConfig.CABundle)
+}
+
+func countCerts(caBundle []byte) int {
+	count := 0
+	for block, rest := pem.Decode(caBundle); block != nil; block, rest = pem.Decode(rest) {
+		count++
+	}
+	return count
+}
@@ -9,6 +9,7 @@ type contextKey string
 
 const (
 	contextKeyAdmissionRequest contextKey = "admissionRequest"
+	contextKeyWarnings         contextKey = "warnings"
 )
 
 func ContextGetAdmissionRequest(ctx context.Context) *admission.Request {
@@ -21,3 +22,30 @@ func ContextGetAdmissionRequest(ctx context.Context) *admission.Request {
 func ContextWithAdmissionRequest(ctx context.Context, req admission.Request) context.Context {
 	return context.WithValue(ctx, contextKeyAdmissionRequest, &req)
 }
+
+// admissionWarnings collects warnings to be returned to the client of an admission request.
+type admissionWarnings struct {
+	warnings []string
+}
+
+// ContextWithWarnings returns a context that collects warnings added by ContextAddWarning.
+func ContextWithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyWarnings, &admissionWarnings{})
+}
+
+// ContextAddWarning adds a warning to be returned to the client of the admission request.
+// it's a no-op if ctx doesn't collect warnings.
+func ContextAddWarning(ctx context.Context, warning string) {
+	if v := ctx.Value(contextKeyWarnings); v != nil {
+		w := v.(*admissionWarnings)
+		w.warnings = append(w.warnings, warning)
+	}
+}
+
+// ContextGetWarnings returns the warnings added to ctx.
+func ContextGetWarnings(ctx context.Context) []string {
+	if v := ctx.Value(contextKeyWarnings); v != nil {
+		return v.(*admissionWarnings).warnings
+	}
+	return nil
+}
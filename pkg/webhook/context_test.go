@@ -52,3 +52,44 @@ func TestContextGetAdmissionRequestAndContextWithAdmissionRequest(t *testing.T)
 		})
 	}
 }
+
+func TestContextAddWarningAndContextGetWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		withWarnings bool
+		warnings     []string
+		want         []string
+	}{
+		{
+			name:         "with warnings",
+			withWarnings: true,
+			warnings:     []string{"warning-1", "warning-2"},
+			want:         []string{"warning-1", "warning-2"},
+		},
+		{
+			name:         "without warnings added",
+			withWarnings: true,
+			warnings:     nil,
+			want:         nil,
+		},
+		{
+			name:         "without warnings collected",
+			withWarnings: false,
+			warnings:     []string{"warning-1"},
+			want:         nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.withWarnings {
+				ctx = ContextWithWarnings(ctx)
+			}
+			for _, warning := range tt.warnings {
+				ContextAddWarning(ctx, warning)
+			}
+			got := ContextGetWarnings(ctx)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	flagConversionWebhookService = "conversion-webhook-service"
+
+	// ConversionWebhookPath is the path of the CRD conversion webhook.
+	ConversionWebhookPath = "/convert"
+	// how often CRDs are configured again, in case they're reapplied without the conversion webhook.
+	conversionConfigResyncPeriod = 5 * time.Minute
+)
+
+// ConvertibleCRDNames are the names of CRDs serving multiple versions, which are converted by the conversion webhook.
+var ConvertibleCRDNames = []string{"meshes.appmesh.k8s.aws"}
+
+type ConversionConfig struct {
+	// Service is the namespace/name of the webhook service.
+	// if set, ConvertibleCRDNames are configured to convert via the webhook service.
+	Service string
+}
+
+func (cfg *ConversionConfig) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&cfg.Service, flagConversionWebhookService, "",
+		"The namespace/name of the webhook service. If set, CRDs serving multiple versions are configured to use the conversion webhook served by it")
+}
+
+// Enabled returns whether configuring CRDs to use the conversion webhook is enabled.
+func (cfg *ConversionConfig) Enabled() bool {
+	return len(cfg.Service) != 0
+}
+
+// NewConversionConfigurer constructs a Runnable that configures CRDs to use the conversion webhook.
+// caBundlePath is the path of the CA bundle that signed the certificate of the webhook server.
+func NewConversionConfigurer(cfg ConversionConfig, k8sClient client.Client, caBundlePath string, log logr.Logger) (manager.Runnable, error) {
+	serviceNamespace, serviceName, ok := strings.Cut(cfg.Service, "/")
+	if !ok || len(serviceNamespace) == 0 || len(serviceName) == 0 {
+		return nil, errors.Errorf("invalid %s %q, must be namespace/name", flagConversionWebhookService, cfg.Service)
+	}
+	return &conversionConfigurer{
+		k8sClient:        k8sClient,
+		crdNames:         ConvertibleCRDNames,
+		serviceNamespace: serviceNamespace,
+		serviceName:      serviceName,
+		caBundlePath:     caBundlePath,
+		log:              log,
+	}, nil
+}
+
+// conversionConfigurer configures the conversion of CRDs with a client config of the conversion webhook.
+// This is needed for installations whose CRDs cannot refer to the webhook service, e.g. CRDs installed by Helm cannot be templated.
+type conversionConfigurer struct {
+	k8sClient        client.Client
+	crdNames         []string
+	serviceNamespace string
+	serviceName      string
+	caBundlePath     string
+	log              logr.Logger
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=patch
+
+func (c *conversionConfigurer) Start(ctx context.Context) error {
+	for {
+		if err := c.configureCRDs(ctx); err != nil {
+			c.log.Error(err, "failed to configure CRD conversion webhook")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(conversionConfigResyncPeriod):
+		}
+	}
+}
+
+func (c *conversionConfigurer) configureCRDs(ctx context.Context) error {
+	// the CA bundle is read every time, since the certificate of webhook server may be rotated.
+	caBundle, err := os.ReadFile(c.caBundlePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read CA bundle")
+	}
+	servicePath := ConversionWebhookPath
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service: &apiextensionsv1.ServiceReference{
+							Namespace: c.serviceNamespace,
+							Name:      c.serviceName,
+							Path:      &servicePath,
+						},
+						CABundle: caBundle,
+					},
+					ConversionReviewVersions: []string{"v1", "v1beta1"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, crdName := range c.crdNames {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: crdName,
+			},
+		}
+		if err := c.k8sClient.Patch(ctx, crd, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return errors.Wrapf(err, "failed to configure conversion webhook of CRD %s", crdName)
+		}
+		c.log.V(1).Info("configured conversion webhook", "crd", crdName)
+	}
+	return nil
+}
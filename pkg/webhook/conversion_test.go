@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestNewConversionConfigurer(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ConversionConfig
+		wantErr error
+	}{
+		{
+			name: "valid service",
+			cfg:  ConversionConfig{Service: "appmesh-system/appmesh-controller-webhook-service"},
+		},
+		{
+			name:    "service without namespace",
+			cfg:     ConversionConfig{Service: "appmesh-controller-webhook-service"},
+			wantErr: errors.New(`invalid conversion-webhook-service "appmesh-controller-webhook-service", must be namespace/name`),
+		},
+		{
+			name:    "service with empty name",
+			cfg:     ConversionConfig{Service: "appmesh-system/"},
+			wantErr: errors.New(`invalid conversion-webhook-service "appmesh-system/", must be namespace/name`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConversionConfigurer(tt.cfg, nil, "", logr.New(&log.NullLogSink{}))
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_conversionConfigurer_configureCRDs(t *testing.T) {
+	ctx := context.Background()
+	k8sSchema := runtime.NewScheme()
+	apiextensionsv1.AddToScheme(k8sSchema)
+	k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+	err := k8sClient.Create(ctx, &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "meshes.appmesh.k8s.aws",
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "appmesh.k8s.aws",
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.NoneConverter,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	caBundlePath := filepath.Join(t.TempDir(), "ca.crt")
+	err = os.WriteFile(caBundlePath, []byte("ca-bundle"), 0600)
+	assert.NoError(t, err)
+
+	configurer, err := NewConversionConfigurer(ConversionConfig{Service: "appmesh-system/appmesh-controller-webhook-service"},
+		k8sClient, caBundlePath, logr.New(&log.NullLogSink{}))
+	assert.NoError(t, err)
+	err = configurer.(*conversionConfigurer).configureCRDs(ctx)
+	assert.NoError(t, err)
+
+	gotCRD := &apiextensionsv1.CustomResourceDefinition{}
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: "meshes.appmesh.k8s.aws"}, gotCRD)
+	assert.NoError(t, err)
+	servicePath := "/convert"
+	assert.Equal(t, "appmesh.k8s.aws", gotCRD.Spec.Group)
+	assert.Equal(t, &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: "appmesh-system",
+					Name:      "appmesh-controller-webhook-service",
+					Path:      &servicePath,
+				},
+				CABundle: []byte("ca-bundle"),
+			},
+			ConversionReviewVersions: []string{"v1", "v1beta1"},
+		},
+	}, gotCRD.Spec.Conversion)
+}
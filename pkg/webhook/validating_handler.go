@@ -52,10 +52,11 @@ func (h *validatingHandler) handleCreate(ctx context.Context, req admission.Requ
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if err := h.validator.ValidateCreate(ContextWithAdmissionRequest(ctx, req), obj); err != nil {
-		return admission.Denied(err.Error())
+	ctx = ContextWithWarnings(ContextWithAdmissionRequest(ctx, req))
+	if err := h.validator.ValidateCreate(ctx, obj); err != nil {
+		return admission.Denied(err.Error()).WithWarnings(ContextGetWarnings(ctx)...)
 	}
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(ContextGetWarnings(ctx)...)
 }
 
 func (h *validatingHandler) handleUpdate(ctx context.Context, req admission.Request) admission.Response {
@@ -72,10 +73,11 @@ func (h *validatingHandler) handleUpdate(ctx context.Context, req admission.Requ
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if err := h.validator.ValidateUpdate(ContextWithAdmissionRequest(ctx, req), obj, oldObj); err != nil {
-		return admission.Denied(err.Error())
+	ctx = ContextWithWarnings(ContextWithAdmissionRequest(ctx, req))
+	if err := h.validator.ValidateUpdate(ctx, obj, oldObj); err != nil {
+		return admission.Denied(err.Error()).WithWarnings(ContextGetWarnings(ctx)...)
 	}
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(ContextGetWarnings(ctx)...)
 }
 
 func (h *validatingHandler) handleDelete(ctx context.Context, req admission.Request) admission.Response {
@@ -88,8 +90,9 @@ func (h *validatingHandler) handleDelete(ctx context.Context, req admission.Requ
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if err := h.validator.ValidateDelete(ContextWithAdmissionRequest(ctx, req), obj); err != nil {
-		return admission.Denied(err.Error())
+	ctx = ContextWithWarnings(ContextWithAdmissionRequest(ctx, req))
+	if err := h.validator.ValidateDelete(ctx, obj); err != nil {
+		return admission.Denied(err.Error()).WithWarnings(ContextGetWarnings(ctx)...)
 	}
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(ContextGetWarnings(ctx)...)
 }
@@ -93,7 +93,7 @@ func (b *VRBuilder) TcpBuildRoutes(tcpRoutesCFgs []TcpRouteToWeightedVirtualNode
 		routes = append(routes, appmesh.Route{
 			Name: fmt.Sprintf("route-%d", index),
 			TCPRoute: &appmesh.TCPRoute{
-				Match: (*appmesh.TCPRouteMatch)(tcpRouteCfg.Match),
+				Match: buildTCPRouteMatch(tcpRouteCfg.Match),
 				Action: appmesh.TCPRouteAction{
 					WeightedTargets: targets,
 				},
@@ -119,6 +119,15 @@ func (b *VRBuilder) BuildTcpRouteMatch(port *int64) *TCPRouteMatch {
 	}
 }
 
+func buildTCPRouteMatch(match *TCPRouteMatch) *appmesh.TCPRouteMatch {
+	if match == nil {
+		return nil
+	}
+	return &appmesh.TCPRouteMatch{
+		Port: match.Port,
+	}
+}
+
 func (b *VRBuilder) buildName(instanceName string) string {
 	return instanceName
 }
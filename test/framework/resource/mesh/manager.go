@@ -11,8 +11,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
@@ -49,7 +49,7 @@ func (m *defaultManager) WaitUntilMeshActive(ctx context.Context, mesh *appmesh.
 		}
 
 		for _, condition := range observedMesh.Status.Conditions {
-			if condition.Type == appmesh.MeshActive && condition.Status == corev1.ConditionTrue {
+			if condition.Type == appmesh.MeshActive && condition.Status == metav1.ConditionTrue {
 				return true, nil
 			}
 		}
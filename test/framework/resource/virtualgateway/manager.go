@@ -12,8 +12,8 @@ import (
 	appmeshsdk "github.com/aws/aws-sdk-go/service/appmesh"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,7 +51,7 @@ func (m *defaultManager) WaitUntilVirtualGatewayActive(ctx context.Context, vg *
 		}
 
 		for _, condition := range observedVG.Status.Conditions {
-			if condition.Type == appmesh.VirtualGatewayActive && condition.Status == corev1.ConditionTrue {
+			if condition.Type == appmesh.VirtualGatewayActive && condition.Status == metav1.ConditionTrue {
 				return true, nil
 			}
 		}
@@ -68,7 +68,7 @@ func (m *defaultManager) WaitUntilVirtualNodeActive(ctx context.Context, vn *app
 		}
 
 		for _, condition := range observedVN.Status.Conditions {
-			if condition.Type == appmesh.VirtualNodeActive && condition.Status == corev1.ConditionTrue {
+			if condition.Type == appmesh.VirtualNodeActive && condition.Status == metav1.ConditionTrue {
 				return true, nil
 			}
 		}
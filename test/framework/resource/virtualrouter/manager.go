@@ -13,8 +13,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,7 +54,7 @@ func (m *defaultManager) WaitUntilVirtualRouterActive(ctx context.Context, vr *a
 		}
 
 		for _, condition := range observedVR.Status.Conditions {
-			if condition.Type == appmesh.VirtualRouterActive && condition.Status == corev1.ConditionTrue {
+			if condition.Type == appmesh.VirtualRouterActive && condition.Status == metav1.ConditionTrue {
 				return true, nil
 			}
 		}
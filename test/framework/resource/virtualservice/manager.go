@@ -13,8 +13,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,7 +53,7 @@ func (m *defaultManager) WaitUntilVirtualServiceActive(ctx context.Context, vs *
 		}
 
 		for _, condition := range observedVS.Status.Conditions {
-			if condition.Type == appmesh.VirtualServiceActive && condition.Status == corev1.ConditionTrue {
+			if condition.Type == appmesh.VirtualServiceActive && condition.Status == metav1.ConditionTrue {
 				return true, nil
 			}
 		}
@@ -5,6 +5,7 @@ import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -29,7 +30,8 @@ func (v *backendGroupValidator) Prototype(req admission.Request) (runtime.Object
 }
 
 func (v *backendGroupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
-	return nil
+	bg := obj.(*appmesh.BackendGroup)
+	return v.checkSelectors(bg)
 }
 
 func (v *backendGroupValidator) ValidateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
@@ -38,7 +40,7 @@ func (v *backendGroupValidator) ValidateUpdate(ctx context.Context, obj runtime.
 	if err := v.enforceFieldsImmutability(bg, oldVS); err != nil {
 		return err
 	}
-	return nil
+	return v.checkSelectors(bg)
 }
 
 func (v *backendGroupValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
@@ -57,6 +59,24 @@ func (v *backendGroupValidator) enforceFieldsImmutability(bg *appmesh.BackendGro
 	return nil
 }
 
+// checkSelectors checks virtualServiceSelector and namespaceSelector are valid label selectors.
+func (v *backendGroupValidator) checkSelectors(bg *appmesh.BackendGroup) error {
+	if bg.Spec.VirtualServiceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(bg.Spec.VirtualServiceSelector); err != nil {
+			return errors.Wrap(err, "invalid virtualServiceSelector")
+		}
+	}
+	if bg.Spec.NamespaceSelector != nil {
+		if bg.Spec.VirtualServiceSelector == nil {
+			return errors.New("namespaceSelector must be specified with virtualServiceSelector")
+		}
+		if _, err := metav1.LabelSelectorAsSelector(bg.Spec.NamespaceSelector); err != nil {
+			return errors.Wrap(err, "invalid namespaceSelector")
+		}
+	}
+	return nil
+}
+
 // +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-backendgroup,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=backendgroups,verbs=create;update,versions=v1beta2,name=vbackendgroup.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
 
 func (v *backendGroupValidator) SetupWithManager(mgr ctrl.Manager) {
@@ -106,3 +106,62 @@ func Test_backendGroupValidator_enforceFieldsImmutability(t *testing.T) {
 		})
 	}
 }
+
+func Test_backendGroupValidator_checkSelectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		bg      *appmesh.BackendGroup
+		wantErr error
+	}{
+		{
+			name: "BackendGroup without selectors",
+			bg: &appmesh.BackendGroup{
+				Spec: appmesh.BackendGroupSpec{
+					VirtualServices: []appmesh.VirtualServiceReference{{Name: "my-vs"}},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "BackendGroup with virtualServiceSelector and namespaceSelector",
+			bg: &appmesh.BackendGroup{
+				Spec: appmesh.BackendGroupSpec{
+					VirtualServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+					NamespaceSelector:      &metav1.LabelSelector{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "BackendGroup with invalid virtualServiceSelector",
+			bg: &appmesh.BackendGroup{
+				Spec: appmesh.BackendGroupSpec{
+					VirtualServiceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Equals", Values: []string{"payments"}}},
+					},
+				},
+			},
+			wantErr: errors.New(`invalid virtualServiceSelector: "Equals" is not a valid label selector operator`),
+		},
+		{
+			name: "BackendGroup with namespaceSelector only",
+			bg: &appmesh.BackendGroup{
+				Spec: appmesh.BackendGroupSpec{
+					NamespaceSelector: &metav1.LabelSelector{},
+				},
+			},
+			wantErr: errors.New("namespaceSelector must be specified with virtualServiceSelector"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &backendGroupValidator{}
+			err := v.checkSelectors(tt.bg)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
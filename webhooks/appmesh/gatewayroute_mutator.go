@@ -2,8 +2,8 @@ package appmesh
 
 import (
 	"context"
-	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
@@ -16,10 +16,11 @@ import (
 const apiPathMutateAppMeshGatewayRoute = "/mutate-appmesh-k8s-aws-v1beta2-gatewayroute"
 
 // NewGatewayRouteMutator returns a mutator for GatewayRoute.
-func NewGatewayRouteMutator(meshMembershipDesignator mesh.MembershipDesignator, virtualGatewayMembershipDesignator virtualgateway.MembershipDesignator) *gatewayRouteMutator {
+func NewGatewayRouteMutator(meshMembershipDesignator mesh.MembershipDesignator, virtualGatewayMembershipDesignator virtualgateway.MembershipDesignator, awsNameGenerator awsname.Generator) *gatewayRouteMutator {
 	return &gatewayRouteMutator{
 		meshMembershipDesignator:           meshMembershipDesignator,
 		virtualGatewayMembershipDesignator: virtualGatewayMembershipDesignator,
+		awsNameGenerator:                   awsNameGenerator,
 	}
 }
 
@@ -28,6 +29,7 @@ var _ webhook.Mutator = &gatewayRouteMutator{}
 type gatewayRouteMutator struct {
 	meshMembershipDesignator           mesh.MembershipDesignator
 	virtualGatewayMembershipDesignator virtualgateway.MembershipDesignator
+	awsNameGenerator                   awsname.Generator
 }
 
 func (m *gatewayRouteMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -48,7 +50,7 @@ func (m *gatewayRouteMutator) MutateCreate(ctx context.Context, obj runtime.Obje
 	if vg.Spec.MeshRef == nil || !mesh.IsMeshReferenced(ms, *vg.Spec.MeshRef) {
 		return nil, errors.Errorf("virtualGateway referenced does not belong to the mesh referenced in GatewayRoute Create")
 	}
-	if err := m.defaultingAWSName(gr); err != nil {
+	if err := m.defaultingAWSName(gr, ms); err != nil {
 		return nil, err
 	}
 
@@ -59,9 +61,9 @@ func (m *gatewayRouteMutator) MutateUpdate(ctx context.Context, obj runtime.Obje
 	return obj, nil
 }
 
-func (m *gatewayRouteMutator) defaultingAWSName(gr *appmesh.GatewayRoute) error {
+func (m *gatewayRouteMutator) defaultingAWSName(gr *appmesh.GatewayRoute, ms *appmesh.Mesh) error {
 	if gr.Spec.AWSName == nil || len(*gr.Spec.AWSName) == 0 {
-		awsName := fmt.Sprintf("%s_%s", gr.Name, gr.Namespace)
+		awsName := m.awsNameGenerator.GenerateWithSuffix(gr.Name, gr.Namespace, "_", ms.Spec.AWSNameSuffix)
 		gr.Spec.AWSName = &awsName
 	}
 	return nil
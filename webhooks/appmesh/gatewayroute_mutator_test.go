@@ -5,6 +5,7 @@ import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	mock_mesh "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	mock_virtualgateway "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
@@ -93,8 +94,10 @@ func Test_gatewayRouteMutator_defaultingAWSName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &gatewayRouteMutator{}
-			err := m.defaultingAWSName(tt.args.gr)
+			m := &gatewayRouteMutator{
+				awsNameGenerator: awsname.NewGenerator(awsname.Config{Strategy: awsname.StrategyNameNamespace}, ""),
+			}
+			err := m.defaultingAWSName(tt.args.gr, &appmesh.Mesh{})
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
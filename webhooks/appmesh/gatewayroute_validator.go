@@ -7,6 +7,7 @@ import (
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -71,6 +72,9 @@ func getNumberOfRouteTypes(spec appmesh.GatewayRouteSpec) int {
 
 func validateQueryParametersIfAny(queryParams []appmesh.HTTPQueryParameters) error {
 	for _, queryParam := range queryParams {
+		if aws.StringValue(queryParam.Name) == "" {
+			return errors.New("Missing name for one or more query parameter match blocks")
+		}
 		if queryParam.Match == nil {
 			continue
 		}
@@ -3,6 +3,7 @@ package appmesh
 import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/aws/aws-sdk-go/aws"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -13,16 +14,18 @@ import (
 const apiPathMutateAppMeshMesh = "/mutate-appmesh-k8s-aws-v1beta2-mesh"
 
 // NewMeshMutator returns a mutator for Mesh.
-func NewMeshMutator(ipFamily string) *meshMutator {
+func NewMeshMutator(ipFamily string, awsNameGenerator awsname.Generator) *meshMutator {
 	return &meshMutator{
-		ipFamily: ipFamily,
+		ipFamily:         ipFamily,
+		awsNameGenerator: awsNameGenerator,
 	}
 }
 
 var _ webhook.Mutator = &meshMutator{}
 
 type meshMutator struct {
-	ipFamily string
+	ipFamily         string
+	awsNameGenerator awsname.Generator
 }
 
 func (m *meshMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -50,7 +53,7 @@ func (m *meshMutator) MutateUpdate(ctx context.Context, obj runtime.Object, oldO
 
 func (m *meshMutator) defaultingAWSName(mesh *appmesh.Mesh) error {
 	if mesh.Spec.AWSName == nil || len(*mesh.Spec.AWSName) == 0 {
-		awsName := mesh.Name
+		awsName := m.awsNameGenerator.GenerateWithSuffix(mesh.Name, "", "", mesh.Spec.AWSNameSuffix)
 		mesh.Spec.AWSName = &awsName
 	}
 	return nil
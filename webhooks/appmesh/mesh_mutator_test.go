@@ -2,6 +2,7 @@ package appmesh
 
 import (
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -79,10 +80,34 @@ func Test_meshMutator_defaultingAWSName(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Mesh didn't specify awsName, specified awsNameSuffix",
+			args: args{
+				mesh: &appmesh.Mesh{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-mesh",
+					},
+					Spec: appmesh.MeshSpec{
+						AWSNameSuffix: aws.String("-dev"),
+					},
+				},
+			},
+			want: &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-mesh",
+				},
+				Spec: appmesh.MeshSpec{
+					AWSName:       aws.String("my-mesh-dev"),
+					AWSNameSuffix: aws.String("-dev"),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &meshMutator{}
+			m := &meshMutator{
+				awsNameGenerator: awsname.NewGenerator(awsname.Config{Strategy: awsname.StrategyNameNamespace}, ""),
+			}
 			err := m.defaultingAWSName(tt.args.mesh)
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
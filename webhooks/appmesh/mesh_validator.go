@@ -3,6 +3,8 @@ package appmesh
 import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/maintenance"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,9 +35,23 @@ func (v *meshValidator) Prototype(req admission.Request) (runtime.Object, error)
 
 func (v *meshValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
 	mesh := obj.(*appmesh.Mesh)
+	if err := v.checkV1OnlyFields(ctx, mesh, nil); err != nil {
+		return err
+	}
 	if err := v.checkIpPreference(mesh); err != nil {
 		return err
 	}
+	if err := v.checkReplicationRegions(mesh); err != nil {
+		return err
+	}
+	if err := v.checkAWSNameSuffix(mesh); err != nil {
+		return err
+	}
+	if err := v.checkMaintenanceWindow(mesh); err != nil {
+		return err
+	}
+	v.warnDeprecatedFields(ctx, mesh)
+	v.warnRiskyConfigs(ctx, mesh)
 	return nil
 }
 
@@ -45,9 +61,23 @@ func (v *meshValidator) ValidateUpdate(ctx context.Context, obj runtime.Object,
 	if err := v.enforceFieldsImmutability(mesh, oldMesh); err != nil {
 		return err
 	}
+	if err := v.checkV1OnlyFields(ctx, mesh, oldMesh); err != nil {
+		return err
+	}
 	if err := v.checkIpPreference(mesh); err != nil {
 		return err
 	}
+	if err := v.checkReplicationRegions(mesh); err != nil {
+		return err
+	}
+	if err := v.checkAWSNameSuffix(mesh); err != nil {
+		return err
+	}
+	if err := v.checkMaintenanceWindow(mesh); err != nil {
+		return err
+	}
+	v.warnDeprecatedFields(ctx, mesh)
+	v.warnRiskyConfigs(ctx, mesh)
 	return nil
 }
 
@@ -61,12 +91,41 @@ func (v *meshValidator) enforceFieldsImmutability(mesh *appmesh.Mesh, oldMesh *a
 	if !reflect.DeepEqual(mesh.Spec.AWSName, oldMesh.Spec.AWSName) {
 		changedImmutableFields = append(changedImmutableFields, "spec.awsName")
 	}
+	if !reflect.DeepEqual(mesh.Spec.AWSNameSuffix, oldMesh.Spec.AWSNameSuffix) {
+		changedImmutableFields = append(changedImmutableFields, "spec.awsNameSuffix")
+	}
 	if len(changedImmutableFields) != 0 {
 		return errors.Errorf("%s update may not change these fields: %s", "Mesh", strings.Join(changedImmutableFields, ","))
 	}
 	return nil
 }
 
+// checkV1OnlyFields checks v1beta2 requests don't set fields that are only served in v1, unless they're unchanged from oldMesh.
+// such fields are still part of v1beta2 mesh, as it's the storage version, so objects configured in v1 can be updated in v1beta2.
+func (v *meshValidator) checkV1OnlyFields(ctx context.Context, mesh *appmesh.Mesh, oldMesh *appmesh.Mesh) error {
+	if !isV1beta2Request(ctx) {
+		return nil
+	}
+	var oldSpec appmesh.MeshSpec
+	if oldMesh != nil {
+		oldSpec = oldMesh.Spec
+	}
+	var changedV1OnlyFields []string
+	if !reflect.DeepEqual(mesh.Spec.AWSNameSuffix, oldSpec.AWSNameSuffix) {
+		changedV1OnlyFields = append(changedV1OnlyFields, "spec.awsNameSuffix")
+	}
+	if !reflect.DeepEqual(mesh.Spec.Replication, oldSpec.Replication) {
+		changedV1OnlyFields = append(changedV1OnlyFields, "spec.replication")
+	}
+	if !reflect.DeepEqual(mesh.Spec.MaintenanceWindow, oldSpec.MaintenanceWindow) {
+		changedV1OnlyFields = append(changedV1OnlyFields, "spec.maintenanceWindow")
+	}
+	if len(changedV1OnlyFields) != 0 {
+		return errors.Errorf("%s fields are only available in appmesh.k8s.aws/v1 Mesh: %s", "Mesh", strings.Join(changedV1OnlyFields, ","))
+	}
+	return nil
+}
+
 func (v *meshValidator) checkIpPreference(mesh *appmesh.Mesh) error {
 	if mesh.Spec.ServiceDiscovery == nil {
 		if v.ipFamily == IPv4 {
@@ -83,6 +142,67 @@ func (v *meshValidator) checkIpPreference(mesh *appmesh.Mesh) error {
 	return nil
 }
 
+// checkReplicationRegions checks each secondary region of mesh is listed once.
+func (v *meshValidator) checkReplicationRegions(mesh *appmesh.Mesh) error {
+	if mesh.Spec.Replication == nil {
+		return nil
+	}
+	regions := make(map[string]bool, len(mesh.Spec.Replication.Regions))
+	for _, replicationRegion := range mesh.Spec.Replication.Regions {
+		if regions[replicationRegion.Region] {
+			return errors.Errorf("%s-%s has duplicate replication region %s", "Mesh", mesh.Name, replicationRegion.Region)
+		}
+		regions[replicationRegion.Region] = true
+	}
+	return nil
+}
+
+// checkAWSNameSuffix checks the awsNameSuffix of mesh can be appended to AWSName of its members.
+func (v *meshValidator) checkAWSNameSuffix(mesh *appmesh.Mesh) error {
+	if mesh.Spec.AWSNameSuffix == nil {
+		return nil
+	}
+	if err := awsname.ValidateSuffix(*mesh.Spec.AWSNameSuffix); err != nil {
+		return errors.Wrapf(err, "%s-%s has invalid awsNameSuffix", "Mesh", mesh.Name)
+	}
+	return nil
+}
+
+// checkMaintenanceWindow checks the maintenanceWindow of mesh can be parsed.
+func (v *meshValidator) checkMaintenanceWindow(mesh *appmesh.Mesh) error {
+	if mesh.Spec.MaintenanceWindow == nil {
+		return nil
+	}
+	if _, err := maintenance.ParseWindow(*mesh.Spec.MaintenanceWindow); err != nil {
+		return errors.Wrapf(err, "%s-%s has invalid maintenanceWindow", "Mesh", mesh.Name)
+	}
+	return nil
+}
+
+// warnDeprecatedFields warns about fields of v1beta2 mesh that are renamed in v1.
+func (v *meshValidator) warnDeprecatedFields(ctx context.Context, mesh *appmesh.Mesh) {
+	if !isV1beta2Request(ctx) {
+		return
+	}
+	if mesh.Spec.ServiceDiscovery != nil {
+		webhook.ContextAddWarning(ctx, "spec.meshServiceDiscovery is deprecated, it's renamed spec.serviceDiscovery in appmesh.k8s.aws/v1 Mesh")
+	}
+}
+
+// warnRiskyConfigs warns about configurations of mesh that are valid, but weaken the security of mesh members.
+func (v *meshValidator) warnRiskyConfigs(ctx context.Context, mesh *appmesh.Mesh) {
+	if mesh.Spec.TLSEnforcementMode != nil && *mesh.Spec.TLSEnforcementMode == appmesh.TLSEnforcementModeAudit {
+		webhook.ContextAddWarning(ctx, "spec.tlsEnforcementMode is AUDIT, client policy TLS of mesh members isn't enforced")
+	}
+}
+
+// isV1beta2Request returns whether the admission request of ctx is made in v1beta2.
+// requests in v1 are converted to v1beta2 before they're validated, so it's told by the kind of original request.
+func isV1beta2Request(ctx context.Context) bool {
+	req := webhook.ContextGetAdmissionRequest(ctx)
+	return req == nil || req.RequestKind == nil || req.RequestKind.Version == appmesh.GroupVersion.Version
+}
+
 // +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-mesh,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=meshes,verbs=create;update,versions=v1beta2,name=vmesh.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
 
 func (v *meshValidator) SetupWithManager(mgr ctrl.Manager) {
@@ -1,12 +1,17 @@
 package appmesh
 
 import (
+	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"testing"
+	"time"
 )
 
 func Test_meshValidator_enforceFieldsImmutability(t *testing.T) {
@@ -63,6 +68,30 @@ func Test_meshValidator_enforceFieldsImmutability(t *testing.T) {
 			},
 			wantErr: errors.New("Mesh update may not change these fields: spec.awsName"),
 		},
+		{
+			name: "Mesh field awsNameSuffix changed",
+			args: args{
+				mesh: &appmesh.Mesh{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-mesh",
+					},
+					Spec: appmesh.MeshSpec{
+						AWSName:       aws.String("my-mesh"),
+						AWSNameSuffix: aws.String("-staging"),
+					},
+				},
+				oldMesh: &appmesh.Mesh{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-mesh",
+					},
+					Spec: appmesh.MeshSpec{
+						AWSName:       aws.String("my-mesh"),
+						AWSNameSuffix: aws.String("-dev"),
+					},
+				},
+			},
+			wantErr: errors.New("Mesh update may not change these fields: spec.awsNameSuffix"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -256,3 +285,258 @@ func Test_meshValidator_checkIpPreferenceValues_IPv4(t *testing.T) {
 		})
 	}
 }
+
+func Test_meshValidator_checkReplicationRegions(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication *appmesh.MeshReplication
+		wantErr     error
+	}{
+		{
+			name:        "mesh without replication",
+			replication: nil,
+			wantErr:     nil,
+		},
+		{
+			name: "mesh replicated into distinct regions",
+			replication: &appmesh.MeshReplication{
+				Regions: []appmesh.MeshReplicationRegion{
+					{Region: "us-west-2"},
+					{Region: "eu-west-1", Endpoint: aws.String("https://appmesh.eu-west-1.amazonaws.com")},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "mesh replicated into the same region twice",
+			replication: &appmesh.MeshReplication{
+				Regions: []appmesh.MeshReplicationRegion{
+					{Region: "us-west-2"},
+					{Region: "us-west-2", Endpoint: aws.String("https://appmesh-fips.us-west-2.amazonaws.com")},
+				},
+			},
+			wantErr: errors.New("Mesh-my-mesh has duplicate replication region us-west-2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &meshValidator{}
+			mesh := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{Replication: tt.replication},
+			}
+			err := v.checkReplicationRegions(mesh)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_meshValidator_checkAWSNameSuffix(t *testing.T) {
+	tests := []struct {
+		name          string
+		awsNameSuffix *string
+		wantErr       error
+	}{
+		{
+			name:          "mesh without awsNameSuffix",
+			awsNameSuffix: nil,
+			wantErr:       nil,
+		},
+		{
+			name:          "mesh with valid awsNameSuffix",
+			awsNameSuffix: aws.String("-dev"),
+			wantErr:       nil,
+		},
+		{
+			name:          "mesh with invalid awsNameSuffix",
+			awsNameSuffix: aws.String("/dev"),
+			wantErr:       errors.New("Mesh-my-mesh has invalid awsNameSuffix: must only consist of alphanumeric characters, '.', '_' or '-': /dev"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &meshValidator{}
+			mesh := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{AWSNameSuffix: tt.awsNameSuffix},
+			}
+			err := v.checkAWSNameSuffix(mesh)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_meshValidator_checkMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name              string
+		maintenanceWindow *appmesh.MaintenanceWindow
+		wantErr           error
+	}{
+		{
+			name:              "mesh without maintenanceWindow",
+			maintenanceWindow: nil,
+			wantErr:           nil,
+		},
+		{
+			name: "mesh with valid maintenanceWindow",
+			maintenanceWindow: &appmesh.MaintenanceWindow{
+				Days:     []appmesh.MaintenanceWindowDay{"Sat"},
+				Start:    "02:00",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+				TimeZone: aws.String("America/New_York"),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "mesh with invalid maintenanceWindow",
+			maintenanceWindow: &appmesh.MaintenanceWindow{
+				Start:    "02:00",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+				TimeZone: aws.String("America/Nowhere"),
+			},
+			wantErr: errors.New(`Mesh-my-mesh has invalid maintenanceWindow: invalid maintenance window timeZone "America/Nowhere": unknown time zone America/Nowhere`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &meshValidator{}
+			mesh := &appmesh.Mesh{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"},
+				Spec:       appmesh.MeshSpec{MaintenanceWindow: tt.maintenanceWindow},
+			}
+			err := v.checkMaintenanceWindow(mesh)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_meshValidator_checkV1OnlyFields(t *testing.T) {
+	maintenanceWindow := &appmesh.MaintenanceWindow{
+		Start:    "02:00",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}
+	tests := []struct {
+		name           string
+		requestVersion string
+		spec           appmesh.MeshSpec
+		oldSpec        *appmesh.MeshSpec
+		wantErr        error
+	}{
+		{
+			name:           "v1beta2 mesh created without v1 only fields",
+			requestVersion: "v1beta2",
+			spec:           appmesh.MeshSpec{AWSName: aws.String("my-mesh")},
+		},
+		{
+			name:           "v1beta2 mesh created with v1 only fields",
+			requestVersion: "v1beta2",
+			spec: appmesh.MeshSpec{
+				AWSNameSuffix:     aws.String("-dev"),
+				Replication:       &appmesh.MeshReplication{Regions: []appmesh.MeshReplicationRegion{{Region: "us-east-1"}}},
+				MaintenanceWindow: maintenanceWindow,
+			},
+			wantErr: errors.New("Mesh fields are only available in appmesh.k8s.aws/v1 Mesh: spec.awsNameSuffix,spec.replication,spec.maintenanceWindow"),
+		},
+		{
+			name:           "v1 mesh created with v1 only fields",
+			requestVersion: "v1",
+			spec: appmesh.MeshSpec{
+				AWSNameSuffix:     aws.String("-dev"),
+				Replication:       &appmesh.MeshReplication{Regions: []appmesh.MeshReplicationRegion{{Region: "us-east-1"}}},
+				MaintenanceWindow: maintenanceWindow,
+			},
+		},
+		{
+			name:           "v1beta2 mesh updated without changing v1 only fields",
+			requestVersion: "v1beta2",
+			spec:           appmesh.MeshSpec{AWSName: aws.String("my-mesh"), MaintenanceWindow: maintenanceWindow},
+			oldSpec:        &appmesh.MeshSpec{MaintenanceWindow: maintenanceWindow},
+		},
+		{
+			name:           "v1beta2 mesh updated with changed v1 only fields",
+			requestVersion: "v1beta2",
+			spec:           appmesh.MeshSpec{},
+			oldSpec:        &appmesh.MeshSpec{MaintenanceWindow: maintenanceWindow},
+			wantErr:        errors.New("Mesh fields are only available in appmesh.k8s.aws/v1 Mesh: spec.maintenanceWindow"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				RequestKind: &metav1.GroupVersionKind{Group: "appmesh.k8s.aws", Version: tt.requestVersion, Kind: "Mesh"},
+			}}
+			ctx := webhook.ContextWithAdmissionRequest(context.Background(), req)
+			v := &meshValidator{}
+			mesh := &appmesh.Mesh{ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"}, Spec: tt.spec}
+			var oldMesh *appmesh.Mesh
+			if tt.oldSpec != nil {
+				oldMesh = &appmesh.Mesh{ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"}, Spec: *tt.oldSpec}
+			}
+			err := v.checkV1OnlyFields(ctx, mesh, oldMesh)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_meshValidator_warnings(t *testing.T) {
+	tlsEnforcementModeAudit := appmesh.TLSEnforcementModeAudit
+	tlsEnforcementModeEnforce := appmesh.TLSEnforcementModeEnforce
+	tests := []struct {
+		name           string
+		requestVersion string
+		spec           appmesh.MeshSpec
+		wantWarnings   []string
+	}{
+		{
+			name:           "v1beta2 mesh without deprecated fields or risky configs",
+			requestVersion: "v1beta2",
+			spec:           appmesh.MeshSpec{TLSEnforcementMode: &tlsEnforcementModeEnforce},
+		},
+		{
+			name:           "v1beta2 mesh with meshServiceDiscovery",
+			requestVersion: "v1beta2",
+			spec:           appmesh.MeshSpec{ServiceDiscovery: &appmesh.MeshServiceDiscovery{IpPreference: aws.String(appmesh.IpPreferenceIPv4)}},
+			wantWarnings:   []string{"spec.meshServiceDiscovery is deprecated, it's renamed spec.serviceDiscovery in appmesh.k8s.aws/v1 Mesh"},
+		},
+		{
+			name:           "v1 mesh with serviceDiscovery",
+			requestVersion: "v1",
+			spec:           appmesh.MeshSpec{ServiceDiscovery: &appmesh.MeshServiceDiscovery{IpPreference: aws.String(appmesh.IpPreferenceIPv4)}},
+		},
+		{
+			name:           "mesh with TLS enforcement audited",
+			requestVersion: "v1",
+			spec:           appmesh.MeshSpec{TLSEnforcementMode: &tlsEnforcementModeAudit},
+			wantWarnings:   []string{"spec.tlsEnforcementMode is AUDIT, client policy TLS of mesh members isn't enforced"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				RequestKind: &metav1.GroupVersionKind{Group: "appmesh.k8s.aws", Version: tt.requestVersion, Kind: "Mesh"},
+			}}
+			ctx := webhook.ContextWithWarnings(webhook.ContextWithAdmissionRequest(context.Background(), req))
+			v := &meshValidator{}
+			mesh := &appmesh.Mesh{ObjectMeta: metav1.ObjectMeta{Name: "my-mesh"}, Spec: tt.spec}
+			v.warnDeprecatedFields(ctx, mesh)
+			v.warnRiskyConfigs(ctx, mesh)
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
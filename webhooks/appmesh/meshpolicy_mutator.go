@@ -0,0 +1,64 @@
+package appmesh
+
+import (
+	"context"
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const apiPathMutateAppMeshMeshPolicy = "/mutate-appmesh-k8s-aws-v1beta2-meshpolicy"
+
+// NewMeshPolicyMutator returns a mutator for MeshPolicy.
+func NewMeshPolicyMutator(meshMembershipDesignator mesh.MembershipDesignator) *meshPolicyMutator {
+	return &meshPolicyMutator{
+		meshMembershipDesignator: meshMembershipDesignator,
+	}
+}
+
+var _ webhook.Mutator = &meshPolicyMutator{}
+
+type meshPolicyMutator struct {
+	meshMembershipDesignator mesh.MembershipDesignator
+}
+
+func (m *meshPolicyMutator) Prototype(req admission.Request) (runtime.Object, error) {
+	return &appmesh.MeshPolicy{}, nil
+}
+
+func (m *meshPolicyMutator) MutateCreate(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
+	mp := obj.(*appmesh.MeshPolicy)
+	if err := m.designateMeshMembership(ctx, mp); err != nil {
+		return nil, err
+	}
+	return mp, nil
+}
+
+func (m *meshPolicyMutator) MutateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) (runtime.Object, error) {
+	return obj, nil
+}
+
+func (m *meshPolicyMutator) designateMeshMembership(ctx context.Context, mp *appmesh.MeshPolicy) error {
+	if mp.Spec.MeshRef != nil {
+		return errors.Errorf("%s create may not specify read-only field: %s", "MeshPolicy", "spec.meshRef")
+	}
+	mesh, err := m.meshMembershipDesignator.Designate(ctx, mp)
+	if err != nil {
+		return err
+	}
+	mp.Spec.MeshRef = &appmesh.MeshReference{
+		Name: mesh.Name,
+		UID:  mesh.UID,
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-appmesh-k8s-aws-v1beta2-meshpolicy,mutating=true,failurePolicy=fail,groups=appmesh.k8s.aws,resources=meshpolicies,verbs=create;update,versions=v1beta2,name=mmeshpolicy.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
+
+func (m *meshPolicyMutator) SetupWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(apiPathMutateAppMeshMeshPolicy, webhook.MutatingWebhookForMutator(m))
+}
@@ -0,0 +1,122 @@
+package appmesh
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const apiPathValidateAppMeshMeshPolicy = "/validate-appmesh-k8s-aws-v1beta2-meshpolicy"
+
+// NewMeshPolicyValidator returns a validator for MeshPolicy.
+func NewMeshPolicyValidator() *meshPolicyValidator {
+	return &meshPolicyValidator{}
+}
+
+var _ webhook.Validator = &meshPolicyValidator{}
+
+type meshPolicyValidator struct {
+}
+
+func (v *meshPolicyValidator) Prototype(req admission.Request) (runtime.Object, error) {
+	return &appmesh.MeshPolicy{}, nil
+}
+
+func (v *meshPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	mp := obj.(*appmesh.MeshPolicy)
+	return v.checkDefaults(mp)
+}
+
+func (v *meshPolicyValidator) ValidateUpdate(ctx context.Context, obj runtime.Object, oldObj runtime.Object) error {
+	mp := obj.(*appmesh.MeshPolicy)
+	oldMP := oldObj.(*appmesh.MeshPolicy)
+	if err := v.enforceFieldsImmutability(mp, oldMP); err != nil {
+		return err
+	}
+	return v.checkDefaults(mp)
+}
+
+func (v *meshPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// enforceFieldsImmutability will enforce immutable fields are not changed.
+func (v *meshPolicyValidator) enforceFieldsImmutability(mp *appmesh.MeshPolicy, oldMP *appmesh.MeshPolicy) error {
+	var changedImmutableFields []string
+	if !reflect.DeepEqual(mp.Spec.MeshRef, oldMP.Spec.MeshRef) {
+		changedImmutableFields = append(changedImmutableFields, "spec.meshRef")
+	}
+	if len(changedImmutableFields) != 0 {
+		return errors.Errorf("%s update may not change these fields: %s", "MeshPolicy", strings.Join(changedImmutableFields, ","))
+	}
+	return nil
+}
+
+// checkDefaults checks defaults are valid for the routes and listeners they're applied to.
+func (v *meshPolicyValidator) checkDefaults(mp *appmesh.MeshPolicy) error {
+	if routeDefaults := mp.Spec.RouteDefaults; routeDefaults != nil {
+		if routeDefaults.GRPCRetryPolicy != nil {
+			if err := validateGRPCRetryPolicy("routeDefaults.grpcRetryPolicy", routeDefaults.GRPCRetryPolicy); err != nil {
+				return err
+			}
+		}
+		if err := validateMeshPolicyTimeout("routeDefaults.timeout", routeDefaults.Timeout); err != nil {
+			return err
+		}
+	}
+	if listenerDefaults := mp.Spec.ListenerDefaults; listenerDefaults != nil {
+		if err := validateMeshPolicyTimeout("listenerDefaults.timeout", listenerDefaults.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMeshPolicyTimeout(field string, timeout *appmesh.ListenerTimeout) error {
+	if timeout == nil {
+		return nil
+	}
+	if err := validateMeshPolicyHTTPTimeout(field+".http", timeout.HTTP); err != nil {
+		return err
+	}
+	if err := validateMeshPolicyHTTPTimeout(field+".http2", timeout.HTTP2); err != nil {
+		return err
+	}
+	if timeout.GRPC != nil {
+		if err := validateTimeoutDuration(field+".grpc.perRequest", timeout.GRPC.PerRequest); err != nil {
+			return err
+		}
+		if err := validateTimeoutDuration(field+".grpc.idle", timeout.GRPC.Idle); err != nil {
+			return err
+		}
+	}
+	if timeout.TCP != nil {
+		if err := validateTimeoutDuration(field+".tcp.idle", timeout.TCP.Idle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMeshPolicyHTTPTimeout(field string, timeout *appmesh.HTTPTimeout) error {
+	if timeout == nil {
+		return nil
+	}
+	if err := validateTimeoutDuration(field+".perRequest", timeout.PerRequest); err != nil {
+		return err
+	}
+	return validateTimeoutDuration(field+".idle", timeout.Idle)
+}
+
+// +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-meshpolicy,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=meshpolicies,verbs=create;update,versions=v1beta2,name=vmeshpolicy.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
+
+func (v *meshPolicyValidator) SetupWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(apiPathValidateAppMeshMeshPolicy, webhook.ValidatingWebhookForValidator(v))
+}
@@ -0,0 +1,124 @@
+package appmesh
+
+import (
+	"testing"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_meshPolicyValidator_enforceFieldsImmutability(t *testing.T) {
+	tests := []struct {
+		name       string
+		meshRef    *appmesh.MeshReference
+		oldMeshRef *appmesh.MeshReference
+		wantErr    error
+	}{
+		{
+			name:       "MeshPolicy immutable fields didn't change",
+			meshRef:    &appmesh.MeshReference{Name: "my-mesh", UID: "408d3036-7dec-11ea-b156-0e30aabe1ca8"},
+			oldMeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "408d3036-7dec-11ea-b156-0e30aabe1ca8"},
+			wantErr:    nil,
+		},
+		{
+			name:       "MeshPolicy field meshRef changed",
+			meshRef:    &appmesh.MeshReference{Name: "another-mesh", UID: "1d3b3c3c-7dec-11ea-b156-0e30aabe1ca8"},
+			oldMeshRef: &appmesh.MeshReference{Name: "my-mesh", UID: "408d3036-7dec-11ea-b156-0e30aabe1ca8"},
+			wantErr:    errors.New("MeshPolicy update may not change these fields: spec.meshRef"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &meshPolicyValidator{}
+			mp := &appmesh.MeshPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "awesome-ns", Name: "my-mp"},
+				Spec:       appmesh.MeshPolicySpec{MeshRef: tt.meshRef},
+			}
+			oldMP := &appmesh.MeshPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "awesome-ns", Name: "my-mp"},
+				Spec:       appmesh.MeshPolicySpec{MeshRef: tt.oldMeshRef},
+			}
+			err := v.enforceFieldsImmutability(mp, oldMP)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_meshPolicyValidator_checkDefaults(t *testing.T) {
+	grpcRetryProfileAll := appmesh.GRPCRetryProfileAll
+	tests := []struct {
+		name    string
+		spec    appmesh.MeshPolicySpec
+		wantErr error
+	}{
+		{
+			name: "valid defaults",
+			spec: appmesh.MeshPolicySpec{
+				RouteDefaults: &appmesh.MeshPolicyRouteDefaults{
+					GRPCRetryPolicy: &appmesh.GRPCRetryPolicy{Profile: &grpcRetryProfileAll, MaxRetries: 2},
+					Timeout: &appmesh.ListenerTimeout{
+						HTTP: &appmesh.HTTPTimeout{PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 30}},
+					},
+				},
+				ListenerDefaults: &appmesh.MeshPolicyListenerDefaults{
+					Timeout: &appmesh.ListenerTimeout{
+						TCP: &appmesh.TCPTimeout{Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 3600}},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "grpc retry profile with retry events",
+			spec: appmesh.MeshPolicySpec{
+				RouteDefaults: &appmesh.MeshPolicyRouteDefaults{
+					GRPCRetryPolicy: &appmesh.GRPCRetryPolicy{
+						Profile:        &grpcRetryProfileAll,
+						TCPRetryEvents: []appmesh.TCPRetryPolicyEvent{"connection-error"},
+						MaxRetries:     2,
+					},
+				},
+			},
+			wantErr: errors.New("routeDefaults.grpcRetryPolicy.profile is mutually exclusive with grpcRetryEvents, httpRetryEvents and tcpRetryEvents"),
+		},
+		{
+			name: "route timeout beyond maximum",
+			spec: appmesh.MeshPolicySpec{
+				RouteDefaults: &appmesh.MeshPolicyRouteDefaults{
+					Timeout: &appmesh.ListenerTimeout{
+						HTTP2: &appmesh.HTTPTimeout{Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 315576000001}},
+					},
+				},
+			},
+			wantErr: errors.New("routeDefaults.timeout.http2.idle must not exceed 315576000000 seconds: 315576000001s"),
+		},
+		{
+			name: "listener timeout beyond maximum",
+			spec: appmesh.MeshPolicySpec{
+				ListenerDefaults: &appmesh.MeshPolicyListenerDefaults{
+					Timeout: &appmesh.ListenerTimeout{
+						GRPC: &appmesh.GRPCTimeout{PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 315576000001}},
+					},
+				},
+			},
+			wantErr: errors.New("listenerDefaults.timeout.grpc.perRequest must not exceed 315576000000 seconds: 315576000001s"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &meshPolicyValidator{}
+			err := v.checkDefaults(&appmesh.MeshPolicy{Spec: tt.spec})
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
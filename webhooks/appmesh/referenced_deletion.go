@@ -0,0 +1,86 @@
+package appmesh
+
+import (
+	"context"
+	"strings"
+
+	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/gatewayroute"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/k8s"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AllowReferencedDeletionAnnotation allows deleting VirtualNodes and VirtualServices still referenced by other objects when set to "true".
+	AllowReferencedDeletionAnnotation = "appmesh.k8s.aws/allow-referenced-deletion"
+)
+
+// isReferencedDeletionAllowed tests whether obj can be deleted while it's still referenced.
+func isReferencedDeletionAllowed(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AllowReferencedDeletionAnnotation] == "true"
+}
+
+// findVirtualNodeReferrers returns the virtualServices provided by vn and the virtualRouters routing to vn, which aren't being deleted.
+// they're fetched by the reference indexes set up by the virtualService and virtualRouter controllers.
+func findVirtualNodeReferrers(ctx context.Context, referencesIndexer references.ObjectReferenceIndexer, vn *appmesh.VirtualNode) ([]string, error) {
+	vnKey := k8s.NamespacedName(vn)
+	vsList := &appmesh.VirtualServiceList{}
+	if err := referencesIndexer.Fetch(ctx, vsList, virtualservice.ReferenceKindVirtualNode, vnKey); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch virtualServices")
+	}
+	vrList := &appmesh.VirtualRouterList{}
+	if err := referencesIndexer.Fetch(ctx, vrList, virtualrouter.ReferenceKindVirtualNode, vnKey); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch virtualRouters")
+	}
+	var referrers []string
+	for i := range vsList.Items {
+		referrers = appendReferrer(referrers, "virtualService", &vsList.Items[i])
+	}
+	for i := range vrList.Items {
+		referrers = appendReferrer(referrers, "virtualRouter", &vrList.Items[i])
+	}
+	return referrers, nil
+}
+
+// findVirtualServiceReferrers returns the virtualNodes with vs as backend and the gatewayRoutes targeting vs, which aren't being deleted.
+// they're fetched by the reference indexes set up by the virtualNode and gatewayRoute controllers.
+func findVirtualServiceReferrers(ctx context.Context, referencesIndexer references.ObjectReferenceIndexer, vs *appmesh.VirtualService) ([]string, error) {
+	vsKey := k8s.NamespacedName(vs)
+	vnList := &appmesh.VirtualNodeList{}
+	if err := referencesIndexer.Fetch(ctx, vnList, virtualnode.ReferenceKindVirtualService, vsKey); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch virtualNodes")
+	}
+	grList := &appmesh.GatewayRouteList{}
+	if err := referencesIndexer.Fetch(ctx, grList, gatewayroute.ReferenceKindVirtualService, vsKey); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch gatewayRoutes")
+	}
+	var referrers []string
+	for i := range vnList.Items {
+		referrers = appendReferrer(referrers, "virtualNode", &vnList.Items[i])
+	}
+	for i := range grList.Items {
+		referrers = appendReferrer(referrers, "gatewayRoute", &grList.Items[i])
+	}
+	return referrers, nil
+}
+
+// appendReferrer appends referrer of kind to referrers.
+// referrers being deleted are skipped, so objects referencing each other can be deleted together, e.g. upon namespace teardown.
+func appendReferrer(referrers []string, kind string, referrer client.Object) []string {
+	if !referrer.GetDeletionTimestamp().IsZero() {
+		return referrers
+	}
+	return append(referrers, kind+"/"+k8s.NamespacedName(referrer).String())
+}
+
+// referencedDeletionError returns the error denying deletion of obj of kind, which is still referenced by referrers.
+func referencedDeletionError(kind string, obj metav1.Object, referrers []string) error {
+	return errors.Errorf("%s-%s is referenced by %s, deleting it breaks traffic through them: remove the references first, or set annotation %s: \"true\" to delete it anyway",
+		kind, obj.GetName(), strings.Join(referrers, ", "), AllowReferencedDeletionAnnotation)
+}
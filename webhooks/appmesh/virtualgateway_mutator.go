@@ -2,8 +2,8 @@ package appmesh
 
 import (
 	"context"
-	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
@@ -15,9 +15,10 @@ import (
 const apiPathMutateAppMeshVirtualGateway = "/mutate-appmesh-k8s-aws-v1beta2-virtualgateway"
 
 // NewVirtualGatewayMutator returns a mutator for VirtualGateway.
-func NewVirtualGatewayMutator(meshMembershipDesignator mesh.MembershipDesignator) *virtualGatewayMutator {
+func NewVirtualGatewayMutator(meshMembershipDesignator mesh.MembershipDesignator, awsNameGenerator awsname.Generator) *virtualGatewayMutator {
 	return &virtualGatewayMutator{
 		meshMembershipDesignator: meshMembershipDesignator,
+		awsNameGenerator:         awsNameGenerator,
 	}
 }
 
@@ -25,6 +26,7 @@ var _ webhook.Mutator = &virtualGatewayMutator{}
 
 type virtualGatewayMutator struct {
 	meshMembershipDesignator mesh.MembershipDesignator
+	awsNameGenerator         awsname.Generator
 }
 
 func (m *virtualGatewayMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -33,10 +35,11 @@ func (m *virtualGatewayMutator) Prototype(req admission.Request) (runtime.Object
 
 func (m *virtualGatewayMutator) MutateCreate(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
 	vg := obj.(*appmesh.VirtualGateway)
-	if err := m.designateMeshMembership(ctx, vg); err != nil {
+	ms, err := m.designateMeshMembership(ctx, vg)
+	if err != nil {
 		return nil, err
 	}
-	if err := m.defaultingAWSName(vg); err != nil {
+	if err := m.defaultingAWSName(vg, ms); err != nil {
 		return nil, err
 	}
 
@@ -47,27 +50,27 @@ func (m *virtualGatewayMutator) MutateUpdate(ctx context.Context, obj runtime.Ob
 	return obj, nil
 }
 
-func (m *virtualGatewayMutator) defaultingAWSName(vg *appmesh.VirtualGateway) error {
+func (m *virtualGatewayMutator) defaultingAWSName(vg *appmesh.VirtualGateway, ms *appmesh.Mesh) error {
 	if vg.Spec.AWSName == nil || len(*vg.Spec.AWSName) == 0 {
-		awsName := fmt.Sprintf("%s_%s", vg.Name, vg.Namespace)
+		awsName := m.awsNameGenerator.GenerateWithSuffix(vg.Name, vg.Namespace, "_", ms.Spec.AWSNameSuffix)
 		vg.Spec.AWSName = &awsName
 	}
 	return nil
 }
 
-func (m *virtualGatewayMutator) designateMeshMembership(ctx context.Context, vg *appmesh.VirtualGateway) error {
+func (m *virtualGatewayMutator) designateMeshMembership(ctx context.Context, vg *appmesh.VirtualGateway) (*appmesh.Mesh, error) {
 	if vg.Spec.MeshRef != nil {
-		return errors.Errorf("%s create may not specify read-only field: %s", "VirtualGateway", "spec.meshRef")
+		return nil, errors.Errorf("%s create may not specify read-only field: %s", "VirtualGateway", "spec.meshRef")
 	}
 	mesh, err := m.meshMembershipDesignator.Designate(ctx, vg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	vg.Spec.MeshRef = &appmesh.MeshReference{
 		Name: mesh.Name,
 		UID:  mesh.UID,
 	}
-	return nil
+	return mesh, nil
 }
 
 // +kubebuilder:webhook:path=/mutate-appmesh-k8s-aws-v1beta2-virtualgateway,mutating=true,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualgateways,verbs=create;update,versions=v1beta2,name=mvirtualgateway.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
@@ -2,10 +2,13 @@ package appmesh
 
 import (
 	"context"
+	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualgateway"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -34,6 +37,13 @@ func (v *virtualGatewayValidator) ValidateCreate(ctx context.Context, obj runtim
 	if err := v.checkForConnectionPoolProtocols(vg); err != nil {
 		return err
 	}
+	if err := v.checkListenerPolicyRanges(vg); err != nil {
+		return err
+	}
+	if err := v.checkProvisionLoadBalancer(vg); err != nil {
+		return err
+	}
+	v.warnRiskyConfigs(ctx, vg)
 	return nil
 }
 
@@ -48,6 +58,13 @@ func (v *virtualGatewayValidator) ValidateUpdate(ctx context.Context, obj runtim
 	if err := v.checkForConnectionPoolProtocols(vg); err != nil {
 		return err
 	}
+	if err := v.checkListenerPolicyRanges(vg); err != nil {
+		return err
+	}
+	if err := v.checkProvisionLoadBalancer(vg); err != nil {
+		return err
+	}
+	v.warnRiskyConfigs(ctx, vg)
 	return nil
 }
 
@@ -112,8 +129,91 @@ func (v *virtualGatewayValidator) checkListenerMultipleConnectionPools(ln appmes
 	return nil
 }
 
+// vgListenerPolicyRange is the range AppMesh accepts for a health check or connection pool setting of VirtualGateway listeners,
+// settings without max are unbounded.
+type vgListenerPolicyRange struct {
+	field string
+	value int64
+	min   int64
+	max   int64
+}
+
+// checkListenerPolicyRanges checks the health check policy and connection pool settings of each listener of vg are within
+// the ranges AppMesh accepts, so they're rejected upon admission instead of failing to sync.
+func (v *virtualGatewayValidator) checkListenerPolicyRanges(vg *appmesh.VirtualGateway) error {
+	for i, ln := range vg.Spec.Listeners {
+		var ranges []vgListenerPolicyRange
+		if hc := ln.HealthCheck; hc != nil {
+			ranges = append(ranges,
+				vgListenerPolicyRange{field: "healthCheck.healthyThreshold", value: hc.HealthyThreshold, min: 2, max: 10},
+				vgListenerPolicyRange{field: "healthCheck.unhealthyThreshold", value: hc.UnhealthyThreshold, min: 2, max: 10},
+				vgListenerPolicyRange{field: "healthCheck.intervalMillis", value: hc.IntervalMillis, min: 5000, max: 300000},
+				vgListenerPolicyRange{field: "healthCheck.timeoutMillis", value: hc.TimeoutMillis, min: 2000, max: 60000},
+			)
+			if hc.Port != nil {
+				ranges = append(ranges, vgListenerPolicyRange{field: "healthCheck.port", value: int64(*hc.Port), min: 1, max: 65535})
+			}
+		}
+		if pool := ln.ConnectionPool; pool != nil {
+			if pool.HTTP != nil {
+				ranges = append(ranges, vgListenerPolicyRange{field: "connectionPool.http.maxConnections", value: pool.HTTP.MaxConnections, min: 1})
+				if pool.HTTP.MaxPendingRequests != nil {
+					ranges = append(ranges, vgListenerPolicyRange{field: "connectionPool.http.maxPendingRequests", value: *pool.HTTP.MaxPendingRequests, min: 1})
+				}
+			}
+			if pool.HTTP2 != nil {
+				ranges = append(ranges, vgListenerPolicyRange{field: "connectionPool.http2.maxRequests", value: pool.HTTP2.MaxRequests, min: 1})
+			}
+			if pool.GRPC != nil {
+				ranges = append(ranges, vgListenerPolicyRange{field: "connectionPool.grpc.maxRequests", value: pool.GRPC.MaxRequests, min: 1})
+			}
+		}
+		for _, r := range ranges {
+			if r.max == 0 && r.value < r.min {
+				return errors.Errorf("spec.listeners[%d].%s must be at least %d: %d", i, r.field, r.min, r.value)
+			}
+			if r.max != 0 && (r.value < r.min || r.value > r.max) {
+				return errors.Errorf("spec.listeners[%d].%s must be between %d and %d: %d", i, r.field, r.min, r.max, r.value)
+			}
+		}
+	}
+	return nil
+}
+
+// checkProvisionLoadBalancer checks the load balancer of vg can be provisioned,
+// which requires a Service selecting pods of vg by labels, with a port per listener.
+func (v *virtualGatewayValidator) checkProvisionLoadBalancer(vg *appmesh.VirtualGateway) error {
+	if vg.Spec.ProvisionLoadBalancer == nil {
+		return nil
+	}
+	if len(vg.Spec.Listeners) == 0 {
+		return errors.New("provisionLoadBalancer requires at least one listener")
+	}
+	if vg.Spec.PodSelector == nil || len(vg.Spec.PodSelector.MatchLabels) == 0 || len(vg.Spec.PodSelector.MatchExpressions) != 0 {
+		return errors.New("provisionLoadBalancer requires podSelector with matchLabels only")
+	}
+	serviceName := virtualgateway.LoadBalancerServiceName(vg)
+	if errs := validation.IsDNS1035Label(serviceName); len(errs) != 0 {
+		return errors.Errorf("provisionLoadBalancer service name %s is invalid: %s", serviceName, strings.Join(errs, ","))
+	}
+	return nil
+}
+
 // +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualgateway,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualgateways,verbs=create;update,versions=v1beta2,name=vvirtualgateway.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
 
+// warnRiskyConfigs warns about TLS configurations of vg that are valid, but let connections fall back to plaintext or fail.
+func (v *virtualGatewayValidator) warnRiskyConfigs(ctx context.Context, vg *appmesh.VirtualGateway) {
+	for i, ln := range vg.Spec.Listeners {
+		if ln.TLS != nil && ln.TLS.Mode == appmesh.VirtualGatewayListenerTLSModeDisabled {
+			webhook.ContextAddWarning(ctx, tlsDisabledWarning(fmt.Sprintf("spec.listeners[%d].tls", i)))
+		}
+	}
+	if vg.Spec.BackendDefaults != nil && vg.Spec.BackendDefaults.ClientPolicy != nil && vg.Spec.BackendDefaults.ClientPolicy.TLS != nil &&
+		isTLSEnforceDisabled(vg.Spec.BackendDefaults.ClientPolicy.TLS.Enforce) {
+		webhook.ContextAddWarning(ctx, tlsNotEnforcedWarning("spec.backendDefaults.clientPolicy.tls"))
+	}
+}
+
 func (v *virtualGatewayValidator) SetupWithManager(mgr ctrl.Manager) {
 	mgr.GetWebhookServer().Register(apiPathValidateAppMeshVirtualGateway, webhook.ValidatingWebhookForValidator(v))
 }
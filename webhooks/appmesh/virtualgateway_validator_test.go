@@ -1,7 +1,9 @@
 package appmesh
 
 import (
+	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -319,3 +321,219 @@ func Test_virtualGatewayValidator_checkForConnectionPoolProtocols(t *testing.T)
 	}
 
 }
+
+func Test_virtualGatewayValidator_checkProvisionLoadBalancer(t *testing.T) {
+	listeners := []appmesh.VirtualGatewayListener{
+		{
+			PortMapping: appmesh.VirtualGatewayPortMapping{
+				Port:     8080,
+				Protocol: "http",
+			},
+		},
+	}
+	podSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "ingress-gw"},
+	}
+	tests := []struct {
+		name    string
+		vgName  string
+		spec    appmesh.VirtualGatewaySpec
+		wantErr error
+	}{
+		{
+			name:   "virtualGateway without provisionLoadBalancer",
+			vgName: "my-vg",
+			spec:   appmesh.VirtualGatewaySpec{},
+		},
+		{
+			name:   "virtualGateway with provisionLoadBalancer",
+			vgName: "my-vg",
+			spec: appmesh.VirtualGatewaySpec{
+				PodSelector:           podSelector,
+				Listeners:             listeners,
+				ProvisionLoadBalancer: &appmesh.VirtualGatewayLoadBalancer{},
+			},
+		},
+		{
+			name:   "virtualGateway with provisionLoadBalancer but no listener",
+			vgName: "my-vg",
+			spec: appmesh.VirtualGatewaySpec{
+				PodSelector:           podSelector,
+				ProvisionLoadBalancer: &appmesh.VirtualGatewayLoadBalancer{},
+			},
+			wantErr: errors.New("provisionLoadBalancer requires at least one listener"),
+		},
+		{
+			name:   "virtualGateway with provisionLoadBalancer and podSelector with matchExpressions",
+			vgName: "my-vg",
+			spec: appmesh.VirtualGatewaySpec{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpExists},
+					},
+				},
+				Listeners:             listeners,
+				ProvisionLoadBalancer: &appmesh.VirtualGatewayLoadBalancer{},
+			},
+			wantErr: errors.New("provisionLoadBalancer requires podSelector with matchLabels only"),
+		},
+		{
+			name:   "virtualGateway with provisionLoadBalancer and name that isn't a valid service name",
+			vgName: "my.vg",
+			spec: appmesh.VirtualGatewaySpec{
+				PodSelector:           podSelector,
+				Listeners:             listeners,
+				ProvisionLoadBalancer: &appmesh.VirtualGatewayLoadBalancer{},
+			},
+			wantErr: errors.New("provisionLoadBalancer service name my.vg is invalid: a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')"),
+		},
+		{
+			name:   "virtualGateway with provisionLoadBalancer and valid serviceName",
+			vgName: "my.vg",
+			spec: appmesh.VirtualGatewaySpec{
+				PodSelector: podSelector,
+				Listeners:   listeners,
+				ProvisionLoadBalancer: &appmesh.VirtualGatewayLoadBalancer{
+					ServiceName: aws.String("my-vg"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualGatewayValidator{}
+			vg := &appmesh.VirtualGateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "app-ns",
+					Name:      tt.vgName,
+				},
+				Spec: tt.spec,
+			}
+			err := v.checkProvisionLoadBalancer(vg)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualGatewayValidator_warnRiskyConfigs(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         appmesh.VirtualGatewaySpec
+		wantWarnings []string
+	}{
+		{
+			name: "TLS is enforced",
+			spec: appmesh.VirtualGatewaySpec{
+				Listeners: []appmesh.VirtualGatewayListener{
+					{TLS: &appmesh.VirtualGatewayListenerTLS{Mode: appmesh.VirtualGatewayListenerTLSModeStrict}},
+				},
+				BackendDefaults: &appmesh.VirtualGatewayBackendDefaults{
+					ClientPolicy: &appmesh.VirtualGatewayClientPolicy{TLS: &appmesh.VirtualGatewayClientPolicyTLS{Enforce: aws.Bool(true)}},
+				},
+			},
+		},
+		{
+			name: "listener TLS is disabled and backend TLS isn't enforced",
+			spec: appmesh.VirtualGatewaySpec{
+				Listeners: []appmesh.VirtualGatewayListener{
+					{TLS: &appmesh.VirtualGatewayListenerTLS{Mode: appmesh.VirtualGatewayListenerTLSModeDisabled}},
+				},
+				BackendDefaults: &appmesh.VirtualGatewayBackendDefaults{
+					ClientPolicy: &appmesh.VirtualGatewayClientPolicy{TLS: &appmesh.VirtualGatewayClientPolicyTLS{Enforce: aws.Bool(false)}},
+				},
+			},
+			wantWarnings: []string{
+				"spec.listeners[0].tls.mode is DISABLED, the listener doesn't accept TLS connections, so clients enforcing TLS fail to connect",
+				"spec.backendDefaults.clientPolicy.tls.enforce is false, connections to backends aren't required to use TLS",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := webhook.ContextWithWarnings(context.Background())
+			v := &virtualGatewayValidator{}
+			v.warnRiskyConfigs(ctx, &appmesh.VirtualGateway{Spec: tt.spec})
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
+
+func Test_virtualGatewayValidator_checkListenerPolicyRanges(t *testing.T) {
+	healthCheck := func(healthyThreshold, unhealthyThreshold, intervalMillis, timeoutMillis int64) *appmesh.VirtualGatewayHealthCheckPolicy {
+		return &appmesh.VirtualGatewayHealthCheckPolicy{
+			HealthyThreshold:   healthyThreshold,
+			UnhealthyThreshold: unhealthyThreshold,
+			IntervalMillis:     intervalMillis,
+			TimeoutMillis:      timeoutMillis,
+			Protocol:           appmesh.VirtualGatewayPortProtocolHTTP,
+		}
+	}
+	port0 := appmesh.PortNumber(0)
+	tests := []struct {
+		name      string
+		listeners []appmesh.VirtualGatewayListener
+		wantErr   error
+	}{
+		{
+			name: "health check and connection pool within ranges",
+			listeners: []appmesh.VirtualGatewayListener{
+				{
+					HealthCheck:    healthCheck(2, 10, 5000, 60000),
+					ConnectionPool: &appmesh.VirtualGatewayConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 1, MaxPendingRequests: aws.Int64(1)}},
+				},
+				{
+					HealthCheck:    healthCheck(10, 2, 300000, 2000),
+					ConnectionPool: &appmesh.VirtualGatewayConnectionPool{GRPC: &appmesh.GRPCConnectionPool{MaxRequests: 1024}},
+				},
+			},
+		},
+		{
+			name:      "listeners without health check and connection pool",
+			listeners: []appmesh.VirtualGatewayListener{{}},
+		},
+		{
+			name:      "health check interval below range",
+			listeners: []appmesh.VirtualGatewayListener{{}, {HealthCheck: healthCheck(2, 2, 1000, 2000)}},
+			wantErr:   errors.New("spec.listeners[1].healthCheck.intervalMillis must be between 5000 and 300000: 1000"),
+		},
+		{
+			name:      "health check healthy threshold above range",
+			listeners: []appmesh.VirtualGatewayListener{{HealthCheck: healthCheck(11, 2, 5000, 2000)}},
+			wantErr:   errors.New("spec.listeners[0].healthCheck.healthyThreshold must be between 2 and 10: 11"),
+		},
+		{
+			name: "health check port out of range",
+			listeners: []appmesh.VirtualGatewayListener{{HealthCheck: func() *appmesh.VirtualGatewayHealthCheckPolicy {
+				hc := healthCheck(2, 2, 5000, 2000)
+				hc.Port = &port0
+				return hc
+			}()}},
+			wantErr: errors.New("spec.listeners[0].healthCheck.port must be between 1 and 65535: 0"),
+		},
+		{
+			name:      "http2 connection pool max requests below range",
+			listeners: []appmesh.VirtualGatewayListener{{ConnectionPool: &appmesh.VirtualGatewayConnectionPool{HTTP2: &appmesh.HTTP2ConnectionPool{MaxRequests: 0}}}},
+			wantErr:   errors.New("spec.listeners[0].connectionPool.http2.maxRequests must be at least 1: 0"),
+		},
+		{
+			name:      "http connection pool max pending requests below range",
+			listeners: []appmesh.VirtualGatewayListener{{ConnectionPool: &appmesh.VirtualGatewayConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 10, MaxPendingRequests: aws.Int64(0)}}}},
+			wantErr:   errors.New("spec.listeners[0].connectionPool.http.maxPendingRequests must be at least 1: 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualGatewayValidator{}
+			err := v.checkListenerPolicyRanges(&appmesh.VirtualGateway{Spec: appmesh.VirtualGatewaySpec{Listeners: tt.listeners}})
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,8 @@ package appmesh
 
 import (
 	"context"
-	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
@@ -15,9 +15,10 @@ import (
 const apiPathMutateAppMeshVirtualNode = "/mutate-appmesh-k8s-aws-v1beta2-virtualnode"
 
 // NewVirtualNodeMutator returns a mutator for VirtualNode.
-func NewVirtualNodeMutator(meshMembershipDesignator mesh.MembershipDesignator) *virtualNodeMutator {
+func NewVirtualNodeMutator(meshMembershipDesignator mesh.MembershipDesignator, awsNameGenerator awsname.Generator) *virtualNodeMutator {
 	return &virtualNodeMutator{
 		meshMembershipDesignator: meshMembershipDesignator,
+		awsNameGenerator:         awsNameGenerator,
 	}
 }
 
@@ -25,6 +26,7 @@ var _ webhook.Mutator = &virtualNodeMutator{}
 
 type virtualNodeMutator struct {
 	meshMembershipDesignator mesh.MembershipDesignator
+	awsNameGenerator         awsname.Generator
 }
 
 func (m *virtualNodeMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -33,10 +35,11 @@ func (m *virtualNodeMutator) Prototype(req admission.Request) (runtime.Object, e
 
 func (m *virtualNodeMutator) MutateCreate(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
 	vn := obj.(*appmesh.VirtualNode)
-	if err := m.designateMeshMembership(ctx, vn); err != nil {
+	ms, err := m.designateMeshMembership(ctx, vn)
+	if err != nil {
 		return nil, err
 	}
-	if err := m.defaultingAWSName(vn); err != nil {
+	if err := m.defaultingAWSName(vn, ms); err != nil {
 		return nil, err
 	}
 
@@ -47,27 +50,27 @@ func (m *virtualNodeMutator) MutateUpdate(ctx context.Context, obj runtime.Objec
 	return obj, nil
 }
 
-func (m *virtualNodeMutator) defaultingAWSName(vn *appmesh.VirtualNode) error {
+func (m *virtualNodeMutator) defaultingAWSName(vn *appmesh.VirtualNode, ms *appmesh.Mesh) error {
 	if vn.Spec.AWSName == nil || len(*vn.Spec.AWSName) == 0 {
-		awsName := fmt.Sprintf("%s_%s", vn.Name, vn.Namespace)
+		awsName := m.awsNameGenerator.GenerateWithSuffix(vn.Name, vn.Namespace, "_", ms.Spec.AWSNameSuffix)
 		vn.Spec.AWSName = &awsName
 	}
 	return nil
 }
 
-func (m *virtualNodeMutator) designateMeshMembership(ctx context.Context, vn *appmesh.VirtualNode) error {
+func (m *virtualNodeMutator) designateMeshMembership(ctx context.Context, vn *appmesh.VirtualNode) (*appmesh.Mesh, error) {
 	if vn.Spec.MeshRef != nil {
-		return errors.Errorf("%s create may not specify read-only field: %s", "VirtualNode", "spec.meshRef")
+		return nil, errors.Errorf("%s create may not specify read-only field: %s", "VirtualNode", "spec.meshRef")
 	}
 	mesh, err := m.meshMembershipDesignator.Designate(ctx, vn)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	vn.Spec.MeshRef = &appmesh.MeshReference{
 		Name: mesh.Name,
 		UID:  mesh.UID,
 	}
-	return nil
+	return mesh, nil
 }
 
 // +kubebuilder:webhook:path=/mutate-appmesh-k8s-aws-v1beta2-virtualnode,mutating=true,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualnodes,verbs=create;update,versions=v1beta2,name=mvirtualnode.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
@@ -4,6 +4,7 @@ import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	mock_mesh "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
@@ -14,7 +15,8 @@ import (
 
 func Test_virtualNodeMutator_defaultingAWSName(t *testing.T) {
 	type args struct {
-		vn *appmesh.VirtualNode
+		vn                *appmesh.VirtualNode
+		meshAWSNameSuffix *string
 	}
 	tests := []struct {
 		name    string
@@ -89,11 +91,36 @@ func Test_virtualNodeMutator_defaultingAWSName(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "VirtualNode didn't specify awsName, mesh specified awsNameSuffix",
+			args: args{
+				vn: &appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "awesome-ns",
+						Name:      "my-vn",
+					},
+					Spec: appmesh.VirtualNodeSpec{},
+				},
+				meshAWSNameSuffix: aws.String("-dev"),
+			},
+			want: &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "awesome-ns",
+					Name:      "my-vn",
+				},
+				Spec: appmesh.VirtualNodeSpec{
+					AWSName: aws.String("my-vn_awesome-ns-dev"),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &virtualNodeMutator{}
-			err := m.defaultingAWSName(tt.args.vn)
+			m := &virtualNodeMutator{
+				awsNameGenerator: awsname.NewGenerator(awsname.Config{Strategy: awsname.StrategyNameNamespace}, ""),
+			}
+			ms := &appmesh.Mesh{Spec: appmesh.MeshSpec{AWSNameSuffix: tt.args.meshAWSNameSuffix}}
+			err := m.defaultingAWSName(tt.args.vn, ms)
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -203,7 +230,7 @@ func Test_virtualNodeMutator_designateMeshMembership(t *testing.T) {
 			m := &virtualNodeMutator{
 				meshMembershipDesignator: designator,
 			}
-			err := m.designateMeshMembership(ctx, tt.args.vn)
+			_, err := m.designateMeshMembership(ctx, tt.args.vn)
 
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
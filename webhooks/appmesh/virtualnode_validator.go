@@ -2,13 +2,18 @@ package appmesh
 
 import (
 	"context"
+	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"strings"
 )
@@ -16,13 +21,20 @@ import (
 const apiPathValidateAppMeshVirtualNode = "/validate-appmesh-k8s-aws-v1beta2-virtualnode"
 
 // NewVirtualNodeValidator returns a validator for VirtualNode.
-func NewVirtualNodeValidator() *virtualNodeValidator {
-	return &virtualNodeValidator{}
+func NewVirtualNodeValidator(k8sClient client.Reader, referencesIndexer references.ObjectReferenceIndexer, namespaceQuotaChecker quota.NamespaceChecker) *virtualNodeValidator {
+	return &virtualNodeValidator{
+		k8sClient:             k8sClient,
+		referencesIndexer:     referencesIndexer,
+		namespaceQuotaChecker: namespaceQuotaChecker,
+	}
 }
 
 var _ webhook.Validator = &virtualNodeValidator{}
 
 type virtualNodeValidator struct {
+	k8sClient             client.Reader
+	referencesIndexer     references.ObjectReferenceIndexer
+	namespaceQuotaChecker quota.NamespaceChecker
 }
 
 func (v *virtualNodeValidator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -37,9 +49,28 @@ func (v *virtualNodeValidator) ValidateCreate(ctx context.Context, obj runtime.O
 	if err := v.checkVirtualNodeBackendsForDuplicates(vn); err != nil {
 		return err
 	}
+	if err := v.checkListenersForDuplicatePorts(vn); err != nil {
+		return err
+	}
 	if err := v.checkForConnectionPoolProtocols(vn); err != nil {
 		return err
 	}
+	if err := v.checkListenerProtocolSettings(vn); err != nil {
+		return err
+	}
+	if err := v.checkListenerHealthCheckSources(vn); err != nil {
+		return err
+	}
+	if err := v.checkServiceDiscoveryFailover(vn); err != nil {
+		return err
+	}
+	if err := v.checkPodSelectorTermsForOverlaps(ctx, vn); err != nil {
+		return err
+	}
+	if err := v.namespaceQuotaChecker.CheckVirtualNode(ctx, vn); err != nil {
+		return err
+	}
+	v.warnRiskyConfigs(ctx, vn)
 	return nil
 }
 
@@ -55,13 +86,33 @@ func (v *virtualNodeValidator) ValidateUpdate(ctx context.Context, obj runtime.O
 	if err := v.checkVirtualNodeBackendsForDuplicates(vn); err != nil {
 		return err
 	}
+	if err := v.checkListenersForDuplicatePorts(vn); err != nil {
+		return err
+	}
 	if err := v.checkForConnectionPoolProtocols(vn); err != nil {
 		return err
 	}
+	if err := v.checkListenerProtocolSettings(vn); err != nil {
+		return err
+	}
+	if err := v.checkListenerHealthCheckSources(vn); err != nil {
+		return err
+	}
+	if err := v.checkServiceDiscoveryFailover(vn); err != nil {
+		return err
+	}
+	if err := v.checkPodSelectorTermsForOverlaps(ctx, vn); err != nil {
+		return err
+	}
+	v.warnRiskyConfigs(ctx, vn)
 	return nil
 }
 
 func (v *virtualNodeValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	vn := obj.(*appmesh.VirtualNode)
+	if err := v.checkForReferrers(ctx, vn); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -75,7 +126,7 @@ func (v *virtualNodeValidator) enforceFieldsImmutability(vn *appmesh.VirtualNode
 		changedImmutableFields = append(changedImmutableFields, "spec.meshRef")
 	}
 	if oldVN.Spec.ServiceDiscovery != nil && oldVN.Spec.ServiceDiscovery.AWSCloudMap != nil &&
-		!reflect.DeepEqual(vn.Spec.ServiceDiscovery.AWSCloudMap, oldVN.Spec.ServiceDiscovery.AWSCloudMap) {
+		!reflect.DeepEqual(cloudMapServiceIdentity(vn.Spec.ServiceDiscovery.AWSCloudMap), cloudMapServiceIdentity(oldVN.Spec.ServiceDiscovery.AWSCloudMap)) {
 		changedImmutableFields = append(changedImmutableFields, "spec.serviceDiscovery.awsCloudMap")
 	}
 	if len(changedImmutableFields) != 0 {
@@ -84,6 +135,16 @@ func (v *virtualNodeValidator) enforceFieldsImmutability(vn *appmesh.VirtualNode
 	return nil
 }
 
+// cloudMapServiceIdentity returns the immutable part of cloudMap, manageNamespace can be changed since it doesn't affect the CloudMap service.
+func cloudMapServiceIdentity(cloudMap *appmesh.AWSCloudMapServiceDiscovery) *appmesh.AWSCloudMapServiceDiscovery {
+	if cloudMap == nil {
+		return nil
+	}
+	identity := cloudMap.DeepCopy()
+	identity.ManageNamespace = nil
+	return identity
+}
+
 func (v *virtualNodeValidator) checkVirtualNodeBackendsForDuplicates(vn *appmesh.VirtualNode) error {
 	backends := vn.Spec.Backends
 	backendMap := make(map[string]bool, len(backends))
@@ -162,7 +223,161 @@ func (v *virtualNodeValidator) checkListenerMultipleConnectionPools(ln appmesh.L
 	return nil
 }
 
-// +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualnode,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualnodes,verbs=create;update,versions=v1beta2,name=vvirtualnode.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
+// checkListenersForDuplicatePorts checks each listener listens on a distinct port.
+func (v *virtualNodeValidator) checkListenersForDuplicatePorts(vn *appmesh.VirtualNode) error {
+	listenerPorts := make(map[appmesh.PortNumber]bool, len(vn.Spec.Listeners))
+	for _, listener := range vn.Spec.Listeners {
+		port := listener.PortMapping.Port
+		if listenerPorts[port] {
+			return errors.Errorf("%s-%s has duplicate listeners on port %d", "VirtualNode", vn.Name, port)
+		}
+		listenerPorts[port] = true
+	}
+	return nil
+}
+
+// checkListenerProtocolSettings checks the connection pool and timeout of each listener match the protocol of that listener.
+func (v *virtualNodeValidator) checkListenerProtocolSettings(vn *appmesh.VirtualNode) error {
+	for _, listener := range vn.Spec.Listeners {
+		protocol := listener.PortMapping.Protocol
+		if pool := listener.ConnectionPool; pool != nil {
+			if poolProtocol, ok := connectionPoolProtocol(pool); ok && poolProtocol != protocol {
+				return errors.Errorf("listener on port %d with protocol %s can't specify %s connection pool", listener.PortMapping.Port, protocol, poolProtocol)
+			}
+		}
+		if timeout := listener.Timeout; timeout != nil {
+			for _, timeoutProtocol := range listenerTimeoutProtocols(timeout) {
+				if timeoutProtocol != protocol {
+					return errors.Errorf("listener on port %d with protocol %s can't specify %s timeout", listener.PortMapping.Port, protocol, timeoutProtocol)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// connectionPoolProtocol returns the protocol of pool, checkListenerMultipleConnectionPools ensures there's at most one.
+func connectionPoolProtocol(pool *appmesh.VirtualNodeConnectionPool) (appmesh.PortProtocol, bool) {
+	switch {
+	case pool.TCP != nil:
+		return appmesh.PortProtocolTCP, true
+	case pool.HTTP != nil:
+		return appmesh.PortProtocolHTTP, true
+	case pool.HTTP2 != nil:
+		return appmesh.PortProtocolHTTP2, true
+	case pool.GRPC != nil:
+		return appmesh.PortProtocolGRPC, true
+	default:
+		return "", false
+	}
+}
+
+func listenerTimeoutProtocols(timeout *appmesh.ListenerTimeout) []appmesh.PortProtocol {
+	var protocols []appmesh.PortProtocol
+	if timeout.TCP != nil {
+		protocols = append(protocols, appmesh.PortProtocolTCP)
+	}
+	if timeout.HTTP != nil {
+		protocols = append(protocols, appmesh.PortProtocolHTTP)
+	}
+	if timeout.HTTP2 != nil {
+		protocols = append(protocols, appmesh.PortProtocolHTTP2)
+	}
+	if timeout.GRPC != nil {
+		protocols = append(protocols, appmesh.PortProtocolGRPC)
+	}
+	return protocols
+}
+
+func (v *virtualNodeValidator) checkListenerHealthCheckSources(vn *appmesh.VirtualNode) error {
+	for _, listener := range vn.Spec.Listeners {
+		if listener.HealthCheck != nil && aws.BoolValue(listener.HealthCheckFromProbe) {
+			return errors.Errorf("listener on port %d can't specify both healthCheck and healthCheckFromProbe", listener.PortMapping.Port)
+		}
+	}
+	return nil
+}
+
+// checkServiceDiscoveryFailover checks both service discoveries are specified for failover between them.
+func (v *virtualNodeValidator) checkServiceDiscoveryFailover(vn *appmesh.VirtualNode) error {
+	sd := vn.Spec.ServiceDiscovery
+	if sd == nil || sd.Failover == nil {
+		return nil
+	}
+	if sd.AWSCloudMap == nil || sd.DNS == nil {
+		return errors.Errorf("serviceDiscovery failover requires both awsCloudMap and dns to be specified")
+	}
+	return nil
+}
+
+// checkPodSelectorTermsForOverlaps checks that no pod can be selected by both vn and another VirtualNode of its namespace,
+// when either of them has podSelectorTerms, since pods can only be a member of a single VirtualNode.
+func (v *virtualNodeValidator) checkPodSelectorTermsForOverlaps(ctx context.Context, vn *appmesh.VirtualNode) error {
+	if !virtualnode.HasPodSelector(vn) {
+		return nil
+	}
+	vnList := &appmesh.VirtualNodeList{}
+	if err := v.k8sClient.List(ctx, vnList, client.InNamespace(vn.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list virtualNodes")
+	}
+	for i := range vnList.Items {
+		otherVN := &vnList.Items[i]
+		if otherVN.Name == vn.Name {
+			continue
+		}
+		if len(vn.Spec.PodSelectorTerms) == 0 && len(otherVN.Spec.PodSelectorTerms) == 0 {
+			continue
+		}
+		for _, term := range virtualnode.PodSelectorTerms(vn) {
+			for _, otherTerm := range virtualnode.PodSelectorTerms(otherVN) {
+				overlap, err := virtualnode.PodSelectorTermsOverlap(term, otherTerm)
+				if err != nil {
+					return err
+				}
+				if overlap {
+					return errors.Errorf("pod selectors overlap with virtualNode %s, pods can only be selected by a single virtualNode", otherVN.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkForReferrers denies deletion of vn while virtualServices or virtualRouters still reference it, unless vn allows it.
+func (v *virtualNodeValidator) checkForReferrers(ctx context.Context, vn *appmesh.VirtualNode) error {
+	if isReferencedDeletionAllowed(vn) {
+		return nil
+	}
+	referrers, err := findVirtualNodeReferrers(ctx, v.referencesIndexer, vn)
+	if err != nil {
+		return err
+	}
+	if len(referrers) != 0 {
+		return referencedDeletionError("VirtualNode", vn, referrers)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualnode,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualnodes,verbs=create;update;delete,versions=v1beta2,name=vvirtualnode.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
+
+// warnRiskyConfigs warns about TLS configurations of vn that are valid, but let connections fall back to plaintext or fail.
+func (v *virtualNodeValidator) warnRiskyConfigs(ctx context.Context, vn *appmesh.VirtualNode) {
+	for i, ln := range vn.Spec.Listeners {
+		if ln.TLS != nil && ln.TLS.Mode == appmesh.ListenerTLSModeDisabled {
+			webhook.ContextAddWarning(ctx, tlsDisabledWarning(fmt.Sprintf("spec.listeners[%d].tls", i)))
+		}
+	}
+	if vn.Spec.BackendDefaults != nil && vn.Spec.BackendDefaults.ClientPolicy != nil && vn.Spec.BackendDefaults.ClientPolicy.TLS != nil &&
+		isTLSEnforceDisabled(vn.Spec.BackendDefaults.ClientPolicy.TLS.Enforce) {
+		webhook.ContextAddWarning(ctx, tlsNotEnforcedWarning("spec.backendDefaults.clientPolicy.tls"))
+	}
+	for i, backend := range vn.Spec.Backends {
+		if backend.VirtualService.ClientPolicy != nil && backend.VirtualService.ClientPolicy.TLS != nil &&
+			isTLSEnforceDisabled(backend.VirtualService.ClientPolicy.TLS.Enforce) {
+			webhook.ContextAddWarning(ctx, tlsNotEnforcedWarning(fmt.Sprintf("spec.backends[%d].virtualService.clientPolicy.tls", i)))
+		}
+	}
+}
 
 func (v *virtualNodeValidator) SetupWithManager(mgr ctrl.Manager) {
 	mgr.GetWebhookServer().Register(apiPathValidateAppMeshVirtualNode, webhook.ValidatingWebhookForValidator(v))
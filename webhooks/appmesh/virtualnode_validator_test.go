@@ -1,11 +1,20 @@
 package appmesh
 
 import (
+	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualservice"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -306,6 +315,45 @@ func Test_virtualNodeValidator_enforceFieldsImmutability(t *testing.T) {
 			},
 			wantErr: errors.New("VirtualNode update may not change these fields: spec.serviceDiscovery.awsCloudMap"),
 		},
+		{
+			name: "VirtualNode field awsCloudMap.manageNamespace changed",
+			args: args{
+				vn: &appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "awesome-ns",
+						Name:      "my-vn",
+					},
+					Spec: appmesh.VirtualNodeSpec{
+						AWSName: aws.String("my-vn_awesome-ns"),
+						ServiceDiscovery: &appmesh.ServiceDiscovery{
+							AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+								NamespaceName: "cloudmap-ns",
+								ServiceName:   "cloudmap-svc",
+								ManageNamespace: &appmesh.AWSCloudMapNamespaceManagement{
+									DeleteWhenUnused: aws.Bool(true),
+								},
+							},
+						},
+					},
+				},
+				oldVN: &appmesh.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "awesome-ns",
+						Name:      "my-vn",
+					},
+					Spec: appmesh.VirtualNodeSpec{
+						AWSName: aws.String("my-vn_awesome-ns"),
+						ServiceDiscovery: &appmesh.ServiceDiscovery{
+							AWSCloudMap: &appmesh.AWSCloudMapServiceDiscovery{
+								NamespaceName: "cloudmap-ns",
+								ServiceName:   "cloudmap-svc",
+							},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
 		{
 			name: "VirtualNode fields awsName, meshRef and awsCloudMap changed",
 			args: args{
@@ -890,3 +938,455 @@ func Test_virtualNodeValidator_checkForConnectionPoolProtocols(t *testing.T) {
 		})
 	}
 }
+
+func Test_virtualNodeValidator_checkListenerHealthCheckSources(t *testing.T) {
+	healthCheck := &appmesh.HealthCheckPolicy{
+		HealthyThreshold:   2,
+		IntervalMillis:     5000,
+		Protocol:           "http",
+		TimeoutMillis:      2000,
+		UnhealthyThreshold: 2,
+	}
+	tests := []struct {
+		name      string
+		listeners []appmesh.Listener
+		wantErr   error
+	}{
+		{
+			name: "listeners with healthCheck or healthCheckFromProbe",
+			listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheck: healthCheck},
+				{PortMapping: appmesh.PortMapping{Port: 8081, Protocol: "http"}, HealthCheckFromProbe: aws.Bool(true)},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "listener with healthCheck and healthCheckFromProbe disabled",
+			listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheck: healthCheck, HealthCheckFromProbe: aws.Bool(false)},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "listener with both healthCheck and healthCheckFromProbe",
+			listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, HealthCheck: healthCheck, HealthCheckFromProbe: aws.Bool(true)},
+			},
+			wantErr: errors.New("listener on port 8080 can't specify both healthCheck and healthCheckFromProbe"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualNodeValidator{}
+			vn := &appmesh.VirtualNode{Spec: appmesh.VirtualNodeSpec{Listeners: tt.listeners}}
+			err := v.checkListenerHealthCheckSources(vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualNodeValidator_checkListenersForDuplicatePorts(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners []appmesh.Listener
+		wantErr   error
+	}{
+		{
+			name: "listeners on distinct ports with distinct TLS modes",
+			listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, TLS: &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeStrict}},
+				{PortMapping: appmesh.PortMapping{Port: 8443, Protocol: "http2"}, TLS: &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModePermissive}},
+				{PortMapping: appmesh.PortMapping{Port: 9090, Protocol: "tcp"}},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "listeners on the same port",
+			listeners: []appmesh.Listener{
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}},
+				{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "tcp"}},
+			},
+			wantErr: errors.New("VirtualNode-my-vn has duplicate listeners on port 8080"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualNodeValidator{}
+			vn := &appmesh.VirtualNode{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "awesome-ns", Name: "my-vn"},
+				Spec:       appmesh.VirtualNodeSpec{Listeners: tt.listeners},
+			}
+			err := v.checkListenersForDuplicatePorts(vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualNodeValidator_checkListenerProtocolSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners []appmesh.Listener
+		wantErr   error
+	}{
+		{
+			name: "listeners with connection pool and timeout matching their protocols",
+			listeners: []appmesh.Listener{
+				{
+					PortMapping:    appmesh.PortMapping{Port: 8080, Protocol: "http"},
+					ConnectionPool: &appmesh.VirtualNodeConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 100}},
+					Timeout:        &appmesh.ListenerTimeout{HTTP: &appmesh.HTTPTimeout{}},
+				},
+				{
+					PortMapping:    appmesh.PortMapping{Port: 9090, Protocol: "tcp"},
+					ConnectionPool: &appmesh.VirtualNodeConnectionPool{TCP: &appmesh.TCPConnectionPool{MaxConnections: 100}},
+					Timeout:        &appmesh.ListenerTimeout{TCP: &appmesh.TCPTimeout{}},
+				},
+				{
+					PortMapping: appmesh.PortMapping{Port: 50051, Protocol: "grpc"},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "listener with connection pool of another protocol",
+			listeners: []appmesh.Listener{
+				{
+					PortMapping:    appmesh.PortMapping{Port: 8080, Protocol: "http"},
+					ConnectionPool: &appmesh.VirtualNodeConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 100}},
+				},
+				{
+					PortMapping:    appmesh.PortMapping{Port: 9090, Protocol: "tcp"},
+					ConnectionPool: &appmesh.VirtualNodeConnectionPool{HTTP: &appmesh.HTTPConnectionPool{MaxConnections: 100}},
+				},
+			},
+			wantErr: errors.New("listener on port 9090 with protocol tcp can't specify http connection pool"),
+		},
+		{
+			name: "listener with timeout of another protocol",
+			listeners: []appmesh.Listener{
+				{
+					PortMapping: appmesh.PortMapping{Port: 50051, Protocol: "grpc"},
+					Timeout:     &appmesh.ListenerTimeout{HTTP2: &appmesh.HTTPTimeout{}},
+				},
+			},
+			wantErr: errors.New("listener on port 50051 with protocol grpc can't specify http2 timeout"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualNodeValidator{}
+			vn := &appmesh.VirtualNode{Spec: appmesh.VirtualNodeSpec{Listeners: tt.listeners}}
+			err := v.checkListenerProtocolSettings(vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualNodeValidator_checkServiceDiscoveryFailover(t *testing.T) {
+	cloudMap := &appmesh.AWSCloudMapServiceDiscovery{
+		NamespaceName: "cm-ns",
+		ServiceName:   "cm-svc",
+	}
+	dns := &appmesh.DNSServiceDiscovery{
+		Hostname: "www.example.com",
+	}
+	failover := &appmesh.ServiceDiscoveryFailover{
+		Primary: appmesh.ServiceDiscoveryTypeAWSCloudMap,
+	}
+	tests := []struct {
+		name             string
+		serviceDiscovery *appmesh.ServiceDiscovery
+		wantErr          error
+	}{
+		{
+			name:             "no serviceDiscovery",
+			serviceDiscovery: nil,
+			wantErr:          nil,
+		},
+		{
+			name:             "awsCloudMap without failover",
+			serviceDiscovery: &appmesh.ServiceDiscovery{AWSCloudMap: cloudMap},
+			wantErr:          nil,
+		},
+		{
+			name:             "failover with awsCloudMap and dns",
+			serviceDiscovery: &appmesh.ServiceDiscovery{AWSCloudMap: cloudMap, DNS: dns, Failover: failover},
+			wantErr:          nil,
+		},
+		{
+			name:             "failover without dns",
+			serviceDiscovery: &appmesh.ServiceDiscovery{AWSCloudMap: cloudMap, Failover: failover},
+			wantErr:          errors.New("serviceDiscovery failover requires both awsCloudMap and dns to be specified"),
+		},
+		{
+			name:             "failover without awsCloudMap",
+			serviceDiscovery: &appmesh.ServiceDiscovery{DNS: dns, Failover: failover},
+			wantErr:          errors.New("serviceDiscovery failover requires both awsCloudMap and dns to be specified"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualNodeValidator{}
+			vn := &appmesh.VirtualNode{Spec: appmesh.VirtualNodeSpec{ServiceDiscovery: tt.serviceDiscovery}}
+			err := v.checkServiceDiscoveryFailover(vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualNodeValidator_checkPodSelectorTermsForOverlaps(t *testing.T) {
+	newVN := func(name string, podSelector *metav1.LabelSelector, podSelectorTerms ...metav1.LabelSelector) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name},
+			Spec: appmesh.VirtualNodeSpec{
+				PodSelector:      podSelector,
+				PodSelectorTerms: podSelectorTerms,
+			},
+		}
+	}
+	blue := metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app", "version": "blue"}}
+	green := metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app", "version": "green"}}
+	canary := metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app", "version": "canary"}}
+	allVersions := metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}}
+	tests := []struct {
+		name        string
+		vn          *appmesh.VirtualNode
+		existingVNs []*appmesh.VirtualNode
+		wantErr     error
+	}{
+		{
+			name:        "podSelectorTerms don't overlap with other virtualNodes",
+			vn:          newVN("vn-blue-green", nil, blue, green),
+			existingVNs: []*appmesh.VirtualNode{newVN("vn-canary", &canary)},
+			wantErr:     nil,
+		},
+		{
+			name:        "podSelectorTerms overlap with podSelector of other virtualNode",
+			vn:          newVN("vn-blue-green", nil, blue, green),
+			existingVNs: []*appmesh.VirtualNode{newVN("vn-all", &allVersions)},
+			wantErr:     errors.New("pod selectors overlap with virtualNode vn-all, pods can only be selected by a single virtualNode"),
+		},
+		{
+			name:        "podSelector overlaps with podSelectorTerms of other virtualNode",
+			vn:          newVN("vn-green", &green),
+			existingVNs: []*appmesh.VirtualNode{newVN("vn-blue-green", nil, blue, green)},
+			wantErr:     errors.New("pod selectors overlap with virtualNode vn-blue-green, pods can only be selected by a single virtualNode"),
+		},
+		{
+			name:        "overlapping podSelectors without podSelectorTerms aren't checked",
+			vn:          newVN("vn-green", &green),
+			existingVNs: []*appmesh.VirtualNode{newVN("vn-all", &allVersions)},
+			wantErr:     nil,
+		},
+		{
+			name:        "virtualNode being updated doesn't overlap with itself",
+			vn:          newVN("vn-blue-green", nil, blue, green),
+			existingVNs: []*appmesh.VirtualNode{newVN("vn-blue-green", nil, blue)},
+			wantErr:     nil,
+		},
+		{
+			name: "virtualNodes of other namespaces aren't checked",
+			vn:   newVN("vn-blue-green", nil, blue, green),
+			existingVNs: []*appmesh.VirtualNode{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "other-ns", Name: "vn-all"},
+					Spec:       appmesh.VirtualNodeSpec{PodSelector: &allVersions},
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range tt.existingVNs {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+
+			v := NewVirtualNodeValidator(k8sClient, nil, quota.NewNoopNamespaceChecker())
+			err := v.checkPodSelectorTermsForOverlaps(ctx, tt.vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_virtualNodeValidator_warnRiskyConfigs(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         appmesh.VirtualNodeSpec
+		wantWarnings []string
+	}{
+		{
+			name: "TLS is enforced",
+			spec: appmesh.VirtualNodeSpec{
+				Listeners: []appmesh.Listener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}, TLS: &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeStrict}},
+				},
+				BackendDefaults: &appmesh.BackendDefaults{
+					ClientPolicy: &appmesh.ClientPolicy{TLS: &appmesh.ClientPolicyTLS{}},
+				},
+			},
+		},
+		{
+			name: "listener TLS is disabled and backend TLS isn't enforced",
+			spec: appmesh.VirtualNodeSpec{
+				Listeners: []appmesh.Listener{
+					{PortMapping: appmesh.PortMapping{Port: 8080, Protocol: "http"}},
+					{PortMapping: appmesh.PortMapping{Port: 8443, Protocol: "http"}, TLS: &appmesh.ListenerTLS{Mode: appmesh.ListenerTLSModeDisabled}},
+				},
+				BackendDefaults: &appmesh.BackendDefaults{
+					ClientPolicy: &appmesh.ClientPolicy{TLS: &appmesh.ClientPolicyTLS{Enforce: aws.Bool(false)}},
+				},
+				Backends: []appmesh.Backend{
+					{VirtualService: appmesh.VirtualServiceBackend{VirtualServiceRef: &appmesh.VirtualServiceReference{Name: "vs-1"}}},
+					{VirtualService: appmesh.VirtualServiceBackend{
+						VirtualServiceRef: &appmesh.VirtualServiceReference{Name: "vs-2"},
+						ClientPolicy:      &appmesh.ClientPolicy{TLS: &appmesh.ClientPolicyTLS{Enforce: aws.Bool(false)}},
+					}},
+				},
+			},
+			wantWarnings: []string{
+				"spec.listeners[1].tls.mode is DISABLED, the listener doesn't accept TLS connections, so clients enforcing TLS fail to connect",
+				"spec.backendDefaults.clientPolicy.tls.enforce is false, connections to backends aren't required to use TLS",
+				"spec.backends[1].virtualService.clientPolicy.tls.enforce is false, connections to backends aren't required to use TLS",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := webhook.ContextWithWarnings(context.Background())
+			v := &virtualNodeValidator{}
+			v.warnRiskyConfigs(ctx, &appmesh.VirtualNode{Spec: tt.spec})
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
+
+func Test_virtualNodeValidator_checkForReferrers(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	vn := &appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vn"}}
+	vsProvidedBy := func(objectMeta metav1.ObjectMeta, vnRef appmesh.VirtualNodeReference) *appmesh.VirtualService {
+		return &appmesh.VirtualService{
+			ObjectMeta: objectMeta,
+			Spec: appmesh.VirtualServiceSpec{
+				Provider: &appmesh.VirtualServiceProvider{
+					VirtualNode: &appmesh.VirtualNodeServiceProvider{VirtualNodeRef: &vnRef},
+				},
+			},
+		}
+	}
+	vrRoutingTo := func(objectMeta metav1.ObjectMeta, vnRef appmesh.VirtualNodeReference) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			ObjectMeta: objectMeta,
+			Spec: appmesh.VirtualRouterSpec{
+				Routes: []appmesh.Route{
+					{
+						Name: "route-1",
+						HTTPRoute: &appmesh.HTTPRoute{
+							Action: appmesh.HTTPRouteAction{
+								WeightedTargets: []appmesh.WeightedTarget{{VirtualNodeRef: &vnRef, Weight: 100}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		vn          *appmesh.VirtualNode
+		existingVSs []*appmesh.VirtualService
+		existingVRs []*appmesh.VirtualRouter
+		wantErr     error
+	}{
+		{
+			name: "virtualNode is referenced by virtualService and virtualRouter",
+			vn:   vn,
+			existingVSs: []*appmesh.VirtualService{
+				vsProvidedBy(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-1"}, appmesh.VirtualNodeReference{Name: "my-vn"}),
+				vsProvidedBy(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-2"}, appmesh.VirtualNodeReference{Name: "other-vn"}),
+			},
+			existingVRs: []*appmesh.VirtualRouter{
+				vrRoutingTo(metav1.ObjectMeta{Namespace: "other-ns", Name: "vr-1"}, appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "my-vn"}),
+			},
+			wantErr: errors.New(`VirtualNode-my-vn is referenced by virtualService/my-ns/vs-1, virtualRouter/other-ns/vr-1, deleting it breaks traffic through them: remove the references first, or set annotation appmesh.k8s.aws/allow-referenced-deletion: "true" to delete it anyway`),
+		},
+		{
+			name: "virtualNode is referenced by objects being deleted",
+			vn:   vn,
+			existingVSs: []*appmesh.VirtualService{
+				vsProvidedBy(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-1", DeletionTimestamp: &deletionTimestamp, Finalizers: []string{"finalizers.appmesh.k8s.aws/aws-resources"}}, appmesh.VirtualNodeReference{Name: "my-vn"}),
+			},
+		},
+		{
+			name: "virtualNode of the same name in other namespace is referenced",
+			vn:   vn,
+			existingVRs: []*appmesh.VirtualRouter{
+				vrRoutingTo(metav1.ObjectMeta{Namespace: "other-ns", Name: "vr-1"}, appmesh.VirtualNodeReference{Name: "my-vn"}),
+			},
+		},
+		{
+			name: "virtualNode allows referenced deletion",
+			vn: &appmesh.VirtualNode{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-ns",
+				Name:        "my-vn",
+				Annotations: map[string]string{"appmesh.k8s.aws/allow-referenced-deletion": "true"},
+			}},
+			existingVSs: []*appmesh.VirtualService{
+				vsProvidedBy(metav1.ObjectMeta{Namespace: "my-ns", Name: "vs-1"}, appmesh.VirtualNodeReference{Name: "my-vn"}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			vsField, vsIndexFunc := references.BuildIndexField(virtualservice.ReferenceKindVirtualNode, virtualservice.VirtualNodeReferenceIndexFunc)
+			vrField, vrIndexFunc := references.BuildIndexField(virtualrouter.ReferenceKindVirtualNode, virtualrouter.VirtualNodeReferenceIndexFunc)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).
+				WithIndex(&appmesh.VirtualService{}, vsField, vsIndexFunc).
+				WithIndex(&appmesh.VirtualRouter{}, vrField, vrIndexFunc).
+				Build()
+			for _, vs := range tt.existingVSs {
+				assert.NoError(t, k8sClient.Create(ctx, vs.DeepCopy()))
+			}
+			for _, vr := range tt.existingVRs {
+				assert.NoError(t, k8sClient.Create(ctx, vr.DeepCopy()))
+			}
+
+			v := NewVirtualNodeValidator(k8sClient, references.NewDefaultObjectReferenceIndexer(k8sClient, nil), quota.NewNoopNamespaceChecker())
+			err := v.ValidateDelete(ctx, tt.vn)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
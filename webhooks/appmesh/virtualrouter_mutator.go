@@ -2,8 +2,8 @@ package appmesh
 
 import (
 	"context"
-	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
@@ -15,9 +15,10 @@ import (
 const apiPathMutateAppMeshVirtualRouter = "/mutate-appmesh-k8s-aws-v1beta2-virtualrouter"
 
 // NewVirtualRouterMutator returns a mutator for VirtualRouter.
-func NewVirtualRouterMutator(meshMembershipDesignator mesh.MembershipDesignator) *virtualRouterMutator {
+func NewVirtualRouterMutator(meshMembershipDesignator mesh.MembershipDesignator, awsNameGenerator awsname.Generator) *virtualRouterMutator {
 	return &virtualRouterMutator{
 		meshMembershipDesignator: meshMembershipDesignator,
+		awsNameGenerator:         awsNameGenerator,
 	}
 }
 
@@ -25,6 +26,7 @@ var _ webhook.Mutator = &virtualRouterMutator{}
 
 type virtualRouterMutator struct {
 	meshMembershipDesignator mesh.MembershipDesignator
+	awsNameGenerator         awsname.Generator
 }
 
 func (m *virtualRouterMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -33,10 +35,11 @@ func (m *virtualRouterMutator) Prototype(req admission.Request) (runtime.Object,
 
 func (m *virtualRouterMutator) MutateCreate(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
 	vr := obj.(*appmesh.VirtualRouter)
-	if err := m.designateMeshMembership(ctx, vr); err != nil {
+	ms, err := m.designateMeshMembership(ctx, vr)
+	if err != nil {
 		return nil, err
 	}
-	if err := m.defaultingAWSName(vr); err != nil {
+	if err := m.defaultingAWSName(vr, ms); err != nil {
 		return nil, err
 	}
 
@@ -47,27 +50,27 @@ func (m *virtualRouterMutator) MutateUpdate(ctx context.Context, obj runtime.Obj
 	return obj, nil
 }
 
-func (m *virtualRouterMutator) defaultingAWSName(vr *appmesh.VirtualRouter) error {
+func (m *virtualRouterMutator) defaultingAWSName(vr *appmesh.VirtualRouter, ms *appmesh.Mesh) error {
 	if vr.Spec.AWSName == nil || len(*vr.Spec.AWSName) == 0 {
-		awsName := fmt.Sprintf("%s_%s", vr.Name, vr.Namespace)
+		awsName := m.awsNameGenerator.GenerateWithSuffix(vr.Name, vr.Namespace, "_", ms.Spec.AWSNameSuffix)
 		vr.Spec.AWSName = &awsName
 	}
 	return nil
 }
 
-func (m *virtualRouterMutator) designateMeshMembership(ctx context.Context, vr *appmesh.VirtualRouter) error {
+func (m *virtualRouterMutator) designateMeshMembership(ctx context.Context, vr *appmesh.VirtualRouter) (*appmesh.Mesh, error) {
 	if vr.Spec.MeshRef != nil {
-		return errors.Errorf("%s create may not specify read-only field: %s", "VirtualRouter", "spec.meshRef")
+		return nil, errors.Errorf("%s create may not specify read-only field: %s", "VirtualRouter", "spec.meshRef")
 	}
 	mesh, err := m.meshMembershipDesignator.Designate(ctx, vr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	vr.Spec.MeshRef = &appmesh.MeshReference{
 		Name: mesh.Name,
 		UID:  mesh.UID,
 	}
-	return nil
+	return mesh, nil
 }
 
 // +kubebuilder:webhook:path=/mutate-appmesh-k8s-aws-v1beta2-virtualrouter,mutating=true,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualrouters,verbs=create;update,versions=v1beta2,name=mvirtualrouter.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
@@ -4,6 +4,7 @@ import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
 	mock_mesh "github.com/aws/aws-app-mesh-controller-for-k8s/mocks/aws-app-mesh-controller-for-k8s/pkg/mesh"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
@@ -92,8 +93,10 @@ func Test_virtualRouterMutator_defaultingAWSName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &virtualRouterMutator{}
-			err := m.defaultingAWSName(tt.args.vr)
+			m := &virtualRouterMutator{
+				awsNameGenerator: awsname.NewGenerator(awsname.Config{Strategy: awsname.StrategyNameNamespace}, ""),
+			}
+			err := m.defaultingAWSName(tt.args.vr, &appmesh.Mesh{})
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -203,7 +206,7 @@ func Test_virtualRouterMutator_designateMeshMembership(t *testing.T) {
 			m := &virtualRouterMutator{
 				meshMembershipDesignator: designator,
 			}
-			err := m.designateMeshMembership(ctx, tt.args.vr)
+			_, err := m.designateMeshMembership(ctx, tt.args.vr)
 
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
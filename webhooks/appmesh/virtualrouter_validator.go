@@ -2,27 +2,47 @@ package appmesh
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualrouter"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 const apiPathValidateAppMeshVirtualRouter = "/validate-appmesh-k8s-aws-v1beta2-virtualrouter"
 
+// maxTimeoutSeconds is the maximum seconds of google.protobuf.Duration, Envoy rejects route timeouts beyond it.
+const maxTimeoutSeconds = 315576000000
+
+// maxTCPRouteMatchPorts is the maximum number of ports matched by a tcpRoute's portRange and ports, each port is created as an AppMesh route.
+const maxTCPRouteMatchPorts = 50
+
 // NewVirtualRouterValidator returns a validator for VirtualRouter.
-func NewVirtualRouterValidator() *virtualRouterValidator {
-	return &virtualRouterValidator{}
+func NewVirtualRouterValidator(k8sClient client.Client, namespaceQuotaChecker quota.NamespaceChecker) *virtualRouterValidator {
+	return &virtualRouterValidator{
+		k8sClient:             k8sClient,
+		namespaceQuotaChecker: namespaceQuotaChecker,
+	}
 }
 
 var _ webhook.Validator = &virtualRouterValidator{}
 
 type virtualRouterValidator struct {
+	k8sClient             client.Client
+	namespaceQuotaChecker quota.NamespaceChecker
 }
 
 func (v *virtualRouterValidator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -39,35 +59,196 @@ func (v *virtualRouterValidator) ValidateCreate(ctx context.Context, obj runtime
 			return err
 		}
 	}
-	return nil
+	if err := v.checkForDuplicateTCPRoutePortEntries(vr); err != nil {
+		return err
+	}
+	v.checkRouteMatchConflicts(ctx, vr)
+	if err := v.namespaceQuotaChecker.CheckVirtualRouter(ctx, vr, nil); err != nil {
+		return err
+	}
+	return v.validateVirtualNodeReferences(ctx, vr)
 }
 
 func validateRoute(route appmesh.Route) error {
 	if route.HTTPRoute != nil {
-		return validateRouteMatch(route.HTTPRoute.Match)
+		if err := validateRouteMatch("httpRoute", route.HTTPRoute.Match); err != nil {
+			return err
+		}
+		if err := validateHTTPTimeout("httpRoute", route.HTTPRoute.Timeout); err != nil {
+			return err
+		}
 	}
 
 	if route.HTTP2Route != nil {
-		return validateRouteMatch(route.HTTP2Route.Match)
+		if err := validateRouteMatch("http2Route", route.HTTP2Route.Match); err != nil {
+			return err
+		}
+		if err := validateHTTPTimeout("http2Route", route.HTTP2Route.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if route.GRPCRoute != nil && route.GRPCRoute.RetryPolicy != nil {
+		if err := validateGRPCRetryPolicy("grpcRoute.retryPolicy", route.GRPCRoute.RetryPolicy); err != nil {
+			return err
+		}
+	}
+
+	if route.GRPCRoute != nil && route.GRPCRoute.Timeout != nil {
+		if err := validateTimeoutDuration("grpcRoute.timeout.perRequest", route.GRPCRoute.Timeout.PerRequest); err != nil {
+			return err
+		}
+		if err := validateTimeoutDuration("grpcRoute.timeout.idle", route.GRPCRoute.Timeout.Idle); err != nil {
+			return err
+		}
+	}
+
+	if route.TCPRoute != nil && route.TCPRoute.Match != nil {
+		if err := validateTCPRouteMatch(route.TCPRoute.Match); err != nil {
+			return err
+		}
+	}
+
+	if route.TCPRoute != nil && route.TCPRoute.Timeout != nil {
+		if err := validateTimeoutDuration("tcpRoute.timeout.idle", route.TCPRoute.Timeout.Idle); err != nil {
+			return err
+		}
+	}
+	return validateRouteWeightedTargets(route)
+}
+
+func validateRouteWeightedTargets(route appmesh.Route) error {
+	if route.HTTPRoute != nil {
+		if err := validateWeightedTargets("httpRoute", route.HTTPRoute.Action.WeightedTargets); err != nil {
+			return err
+		}
+	}
+	if route.HTTP2Route != nil {
+		if err := validateWeightedTargets("http2Route", route.HTTP2Route.Action.WeightedTargets); err != nil {
+			return err
+		}
+	}
+	if route.GRPCRoute != nil {
+		if err := validateWeightedTargets("grpcRoute", route.GRPCRoute.Action.WeightedTargets); err != nil {
+			return err
+		}
+	}
+	if route.TCPRoute != nil {
+		if err := validateWeightedTargets("tcpRoute", route.TCPRoute.Action.WeightedTargets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWeightedTargets validates virtualNodeSelectors of weighted targets, which are mutually exclusive with virtualNodeRef and virtualNodeARN.
+func validateWeightedTargets(routeField string, targets []appmesh.WeightedTarget) error {
+	for i, target := range targets {
+		if target.VirtualNodeSelector == nil {
+			continue
+		}
+		field := fmt.Sprintf("%s.action.weightedTargets[%d]", routeField, i)
+		if target.VirtualNodeRef != nil || target.VirtualNodeARN != nil {
+			return errors.Errorf("%s.virtualNodeSelector is mutually exclusive with virtualNodeRef and virtualNodeARN", field)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(target.VirtualNodeSelector); err != nil {
+			return errors.Wrapf(err, "%s.virtualNodeSelector is invalid", field)
+		}
 	}
 	return nil
 }
 
-func validateRouteMatch(route appmesh.HTTPRouteMatch) error {
+// validateTCPRouteMatch validates the port, portRange and ports of a tcpRoute match.
+func validateTCPRouteMatch(match *appmesh.TCPRouteMatch) error {
+	var numOfPorts int64
+	if match.PortRange != nil {
+		if match.PortRange.Start > match.PortRange.End {
+			return errors.Errorf("tcpRoute.match.portRange.start must not exceed end: %d-%d", match.PortRange.Start, match.PortRange.End)
+		}
+		numOfPorts += match.PortRange.End - match.PortRange.Start + 1
+		if numOfPorts > maxTCPRouteMatchPorts {
+			return errors.Errorf("tcpRoute.match.portRange must not span more than %d ports: %d-%d", maxTCPRouteMatchPorts, match.PortRange.Start, match.PortRange.End)
+		}
+	}
+	if len(match.Ports) > maxTCPRouteMatchPorts {
+		return errors.Errorf("tcpRoute.match.ports must not list more than %d ports: %d", maxTCPRouteMatchPorts, len(match.Ports))
+	}
+	numOfPorts += int64(len(match.Ports))
+	if numOfPorts > maxTCPRouteMatchPorts {
+		return errors.Errorf("tcpRoute.match.portRange and tcpRoute.match.ports must not match more than %d ports combined: %d", maxTCPRouteMatchPorts, numOfPorts)
+	}
+
+	numOfPortMatches := 0
+	if match.Port != nil {
+		numOfPortMatches++
+	}
+	if match.PortRange != nil {
+		numOfPortMatches++
+	}
+	if len(match.Ports) != 0 {
+		numOfPortMatches++
+	}
+	if numOfPortMatches > 1 {
+		return errors.New("tcpRoute.match.port, tcpRoute.match.portRange and tcpRoute.match.ports are mutually exclusive")
+	}
+	return nil
+}
+
+func validateGRPCRetryPolicy(field string, retryPolicy *appmesh.GRPCRetryPolicy) error {
+	if retryPolicy.Profile == nil {
+		return nil
+	}
+	if len(retryPolicy.GRPCRetryEvents) != 0 || len(retryPolicy.HTTPRetryEvents) != 0 || len(retryPolicy.TCPRetryEvents) != 0 {
+		return errors.Errorf("%s.profile is mutually exclusive with grpcRetryEvents, httpRetryEvents and tcpRetryEvents", field)
+	}
+	return nil
+}
+
+func validateHTTPTimeout(routeField string, timeout *appmesh.HTTPTimeout) error {
+	if timeout == nil {
+		return nil
+	}
+	if err := validateTimeoutDuration(routeField+".timeout.perRequest", timeout.PerRequest); err != nil {
+		return err
+	}
+	return validateTimeoutDuration(routeField+".timeout.idle", timeout.Idle)
+}
+
+// validateTimeoutDuration validates the timeout of field doesn't exceed the maximum accepted by Envoy.
+func validateTimeoutDuration(field string, duration *appmesh.Duration) error {
+	if duration == nil {
+		return nil
+	}
+	seconds := duration.Value
+	if duration.Unit == appmesh.DurationUnitMS {
+		seconds = duration.Value / 1000
+	}
+	if seconds > maxTimeoutSeconds {
+		return errors.Errorf("%s must not exceed %d seconds: %d%s", field, maxTimeoutSeconds, duration.Value, duration.Unit)
+	}
+	return nil
+}
+
+func validateRouteMatch(routeField string, route appmesh.HTTPRouteMatch) error {
 	if route.Prefix == nil && route.Path == nil {
 		return errors.New("Either Prefix or Path must be specified")
 	}
 	if route.Prefix != nil && route.Path != nil {
 		return errors.New("Both Prefix and Path cannot be specified, only 1 allowed")
 	}
+	if route.Prefix != nil && !strings.HasPrefix(*route.Prefix, "/") {
+		return errors.Errorf("%s.match.prefix must start with /: %q", routeField, *route.Prefix)
+	}
 
 	if route.Path != nil {
-		return validatePathForVirtualRoute(route.Path)
+		if err := validatePathForVirtualRoute(routeField, route.Path); err != nil {
+			return err
+		}
 	}
-	return nil
+	return validateQueryParametersIfAny(route.QueryParameters)
 }
 
-func validatePathForVirtualRoute(path *appmesh.HTTPPathMatch) error {
+func validatePathForVirtualRoute(routeField string, path *appmesh.HTTPPathMatch) error {
 	exact := path.Exact
 	regex := path.Regex
 
@@ -78,6 +259,15 @@ func validatePathForVirtualRoute(path *appmesh.HTTPPathMatch) error {
 	if exact != nil && regex != nil {
 		return errors.New("Both exact and regex for path are not allowed. Only one must be specified")
 	}
+	if exact != nil && !strings.HasPrefix(*exact, "/") {
+		return errors.Errorf("%s.match.path.exact must start with /: %q", routeField, *exact)
+	}
+	// Envoy evaluates regexes with RE2, whose syntax is the one of Go regexps.
+	if regex != nil {
+		if _, err := regexp.Compile(*regex); err != nil {
+			return errors.Wrapf(err, "%s.match.path.regex is invalid", routeField)
+		}
+	}
 	return nil
 }
 
@@ -90,12 +280,22 @@ func (v *virtualRouterValidator) ValidateUpdate(ctx context.Context, obj runtime
 	if err := v.checkForDuplicateRouteEntries(vr); err != nil {
 		return err
 	}
+	if err := v.checkRouteRenames(ctx, vr, oldVR); err != nil {
+		return err
+	}
 	for _, route := range vr.Spec.Routes {
 		if err := validateRoute(route); err != nil {
 			return err
 		}
 	}
-	return nil
+	if err := v.checkForDuplicateTCPRoutePortEntries(vr); err != nil {
+		return err
+	}
+	v.checkRouteMatchConflicts(ctx, vr)
+	if err := v.namespaceQuotaChecker.CheckVirtualRouter(ctx, vr, oldVR); err != nil {
+		return err
+	}
+	return v.validateVirtualNodeReferences(ctx, vr)
 }
 
 func (v *virtualRouterValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
@@ -117,6 +317,67 @@ func (v *virtualRouterValidator) enforceFieldsImmutability(vr *appmesh.VirtualRo
 	return nil
 }
 
+// validateVirtualNodeReferences validates virtualNodes referenced by route targets of vr.
+// virtualNodes that belong to a different mesh are rejected, since the mesh of a virtualNode is immutable.
+// missing virtualNodes are only warned, since they may be created after vr, e.g. by the same kubectl apply,
+// or are never persisted, e.g. by a dry-run request.
+func (v *virtualRouterValidator) validateVirtualNodeReferences(ctx context.Context, vr *appmesh.VirtualRouter) error {
+	if vr.Spec.MeshRef == nil {
+		return nil
+	}
+	validatedVNKeys := make(map[types.NamespacedName]bool)
+	for _, vnRef := range virtualrouter.ExtractVirtualNodeReferences(vr) {
+		vnKey := references.ObjectKeyForVirtualNodeReference(vr, vnRef)
+		if validatedVNKeys[vnKey] {
+			continue
+		}
+		validatedVNKeys[vnKey] = true
+
+		vn := &appmesh.VirtualNode{}
+		if err := v.k8sClient.Get(ctx, vnKey, vn); err != nil {
+			if apierrors.IsNotFound(err) {
+				webhook.ContextAddWarning(ctx, fmt.Sprintf("virtualNode %v referenced by routes doesn't exist, routes won't be configured until it's created", vnKey))
+				continue
+			}
+			return errors.Wrapf(err, "failed to fetch virtualNode %v referenced by routes", vnKey)
+		}
+		if vn.Spec.MeshRef == nil || *vn.Spec.MeshRef != *vr.Spec.MeshRef {
+			return errors.Errorf("virtualNode %v referenced by routes doesn't belong to mesh %s", vnKey, vr.Spec.MeshRef.Name)
+		}
+	}
+	return nil
+}
+
+// checkRouteRenames rejects route renames of vr unless they're allowed by annotation, since AppMesh routes can't be renamed in place.
+func (v *virtualRouterValidator) checkRouteRenames(ctx context.Context, vr *appmesh.VirtualRouter, oldVR *appmesh.VirtualRouter) error {
+	renames := virtualrouter.DetectRouteRenames(vr.Spec.Routes, oldVR.Spec.Routes)
+	if len(renames) == 0 {
+		return nil
+	}
+	var renamed []string
+	for _, rename := range renames {
+		renamed = append(renamed, fmt.Sprintf("%s to %s", rename.OldName, rename.NewName))
+	}
+	if !virtualrouter.IsRouteRenameAllowed(vr) {
+		return errors.Errorf("%s-%s renames routes %s, AppMesh routes can't be renamed in place: set annotation %s: \"true\" to create the renamed routes before deleting the old ones",
+			"VirtualRouter", vr.Name, strings.Join(renamed, ","), virtualrouter.AllowRouteRenameAnnotation)
+	}
+	webhook.ContextAddWarning(ctx, fmt.Sprintf("routes renamed %s are created before the old routes are deleted, shadowing them until they're deleted", strings.Join(renamed, ",")))
+	return nil
+}
+
+// checkRouteMatchConflicts warns about routes of vr whose requests are matched by other routes.
+// they're not rejected, since routes may be shadowed on purpose while traffic is shifted between them.
+func (v *virtualRouterValidator) checkRouteMatchConflicts(ctx context.Context, vr *appmesh.VirtualRouter) {
+	for _, conflict := range virtualrouter.DetectRouteMatchConflicts(vr.Spec.Routes) {
+		if conflict.Shadowed {
+			webhook.ContextAddWarning(ctx, fmt.Sprintf("route %s is shadowed by route %s of higher priority, which matches all of its requests, so it never matches", conflict.Route, conflict.ConflictingRoute))
+		} else {
+			webhook.ContextAddWarning(ctx, fmt.Sprintf("routes %s and %s have the same match without distinct priorities, it's undefined which of them matches requests", conflict.ConflictingRoute, conflict.Route))
+		}
+	}
+}
+
 func (v *virtualRouterValidator) checkForDuplicateRouteEntries(vr *appmesh.VirtualRouter) error {
 	routes := vr.Spec.Routes
 	routeMap := make(map[string]bool, len(routes))
@@ -130,6 +391,19 @@ func (v *virtualRouterValidator) checkForDuplicateRouteEntries(vr *appmesh.Virtu
 	return nil
 }
 
+// checkForDuplicateTCPRoutePortEntries checks the routes derived from tcpRoutes matching a portRange or ports don't conflict with other routes.
+func (v *virtualRouterValidator) checkForDuplicateTCPRoutePortEntries(vr *appmesh.VirtualRouter) error {
+	routes := virtualrouter.ExpandTCPRoutePorts(vr.Spec.Routes)
+	routeMap := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if _, ok := routeMap[route.Name]; ok {
+			return errors.Errorf("%s-%s has duplicate route entries for %s", "VirtualRouter", vr.Name, route.Name)
+		}
+		routeMap[route.Name] = true
+	}
+	return nil
+}
+
 // +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualrouter,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualrouters,verbs=create;update,versions=v1beta2,name=vvirtualrouter.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
 
 func (v *virtualRouterValidator) SetupWithManager(mgr ctrl.Manager) {
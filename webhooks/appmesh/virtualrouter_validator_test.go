@@ -1,13 +1,22 @@
 package appmesh
 
 import (
+	"context"
 	"testing"
 
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/quota"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_virtualRouterValidator_enforceFieldsImmutability(t *testing.T) {
@@ -292,7 +301,181 @@ func Test_virtualRouterValidator_checkForDuplicateRouteEntries(t *testing.T) {
 	}
 }
 
+func Test_virtualRouterValidator_checkRouteRenames(t *testing.T) {
+	httpRoute := func(name string, prefix string) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{Prefix: aws.String(prefix)},
+			},
+		}
+	}
+	buildVR := func(annotations map[string]string, routes ...appmesh.Route) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vr", Annotations: annotations},
+			Spec:       appmesh.VirtualRouterSpec{Routes: routes},
+		}
+	}
+	tests := []struct {
+		name         string
+		vr           *appmesh.VirtualRouter
+		oldVR        *appmesh.VirtualRouter
+		wantErr      error
+		wantWarnings []string
+	}{
+		{
+			name:  "routes aren't renamed",
+			vr:    buildVR(nil, httpRoute("route-1", "/"), httpRoute("route-3", "/v3")),
+			oldVR: buildVR(nil, httpRoute("route-1", "/"), httpRoute("route-2", "/v2")),
+		},
+		{
+			name:    "route renamed without annotation",
+			vr:      buildVR(nil, httpRoute("route-1-new", "/")),
+			oldVR:   buildVR(nil, httpRoute("route-1", "/")),
+			wantErr: errors.New(`VirtualRouter-my-vr renames routes route-1 to route-1-new, AppMesh routes can't be renamed in place: set annotation appmesh.k8s.aws/allow-route-rename: "true" to create the renamed routes before deleting the old ones`),
+		},
+		{
+			name:    "route renamed with annotation disabled",
+			vr:      buildVR(map[string]string{"appmesh.k8s.aws/allow-route-rename": "false"}, httpRoute("route-1-new", "/")),
+			oldVR:   buildVR(nil, httpRoute("route-1", "/")),
+			wantErr: errors.New(`VirtualRouter-my-vr renames routes route-1 to route-1-new, AppMesh routes can't be renamed in place: set annotation appmesh.k8s.aws/allow-route-rename: "true" to create the renamed routes before deleting the old ones`),
+		},
+		{
+			name:         "route renamed with annotation",
+			vr:           buildVR(map[string]string{"appmesh.k8s.aws/allow-route-rename": "true"}, httpRoute("route-1-new", "/")),
+			oldVR:        buildVR(nil, httpRoute("route-1", "/")),
+			wantWarnings: []string{"routes renamed route-1 to route-1-new are created before the old routes are deleted, shadowing them until they're deleted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := webhook.ContextWithWarnings(context.Background())
+			v := &virtualRouterValidator{}
+			err := v.checkRouteRenames(ctx, tt.vr, tt.oldVR)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
+
+func Test_virtualRouterValidator_checkRouteMatchConflicts(t *testing.T) {
+	httpRoute := func(name string, priority *int64, path appmesh.HTTPPathMatch) appmesh.Route {
+		return appmesh.Route{
+			Name: name,
+			HTTPRoute: &appmesh.HTTPRoute{
+				Match: appmesh.HTTPRouteMatch{Path: &path},
+			},
+			Priority: priority,
+		}
+	}
+	tests := []struct {
+		name         string
+		routes       []appmesh.Route
+		wantWarnings []string
+	}{
+		{
+			name: "routes don't conflict",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), appmesh.HTTPPathMatch{Exact: aws.String("/users/me")}),
+				httpRoute("route-2", aws.Int64(2), appmesh.HTTPPathMatch{Regex: aws.String("/users/[a-z]+")}),
+			},
+		},
+		{
+			name: "routes shadowed and with identical match",
+			routes: []appmesh.Route{
+				httpRoute("route-1", aws.Int64(1), appmesh.HTTPPathMatch{Regex: aws.String("/users/[a-z]+")}),
+				httpRoute("route-2", aws.Int64(2), appmesh.HTTPPathMatch{Exact: aws.String("/users/me")}),
+				httpRoute("route-3", nil, appmesh.HTTPPathMatch{Exact: aws.String("/status")}),
+				httpRoute("route-4", nil, appmesh.HTTPPathMatch{Exact: aws.String("/status")}),
+			},
+			wantWarnings: []string{
+				"route route-2 is shadowed by route route-1 of higher priority, which matches all of its requests, so it never matches",
+				"routes route-3 and route-4 have the same match without distinct priorities, it's undefined which of them matches requests",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := webhook.ContextWithWarnings(context.Background())
+			v := &virtualRouterValidator{}
+			vr := &appmesh.VirtualRouter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vr"},
+				Spec:       appmesh.VirtualRouterSpec{Routes: tt.routes},
+			}
+			v.checkRouteMatchConflicts(ctx, vr)
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
+
+func Test_virtualRouterValidator_checkForDuplicateTCPRoutePortEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []appmesh.Route
+		wantErr error
+	}{
+		{
+			name: "derived routes conflict with another route",
+			routes: []appmesh.Route{
+				{
+					Name:     "tcp",
+					TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Ports: []int64{9000, 9001}}},
+				},
+				{
+					Name:     "tcp-9001",
+					TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9001)}},
+				},
+			},
+			wantErr: errors.New("VirtualRouter-my-vr has duplicate route entries for tcp-9001"),
+		},
+		{
+			name: "ports with duplicate port",
+			routes: []appmesh.Route{
+				{
+					Name:     "tcp",
+					TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Ports: []int64{9000, 9000}}},
+				},
+			},
+			wantErr: errors.New("VirtualRouter-my-vr has duplicate route entries for tcp-9000"),
+		},
+		{
+			name: "no conflicting routes",
+			routes: []appmesh.Route{
+				{
+					Name:     "tcp",
+					TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9002}}},
+				},
+				{
+					Name:     "tcp-9003",
+					TCPRoute: &appmesh.TCPRoute{Match: &appmesh.TCPRouteMatch{Port: aws.Int64(9003)}},
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &virtualRouterValidator{}
+			vr := &appmesh.VirtualRouter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "awesome-ns", Name: "my-vr"},
+				Spec:       appmesh.VirtualRouterSpec{Routes: tt.routes},
+			}
+			err := v.checkForDuplicateTCPRoutePortEntries(vr)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func Test_virtualRouterValidator_validateRoute(t *testing.T) {
+	grpcRetryProfileAll := appmesh.GRPCRetryProfileAll
 	tests := []struct {
 		name    string
 		vr      appmesh.Route
@@ -321,6 +504,56 @@ func Test_virtualRouterValidator_validateRoute(t *testing.T) {
 			},
 			wantErr: errors.New("Either Prefix or Path must be specified"),
 		},
+		{
+			name: "Prefix not starting with /",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("color"),
+					},
+				},
+			},
+			wantErr: errors.New(`httpRoute.match.prefix must start with /: "color"`),
+		},
+		{
+			name: "Exact path not starting with /",
+			vr: appmesh.Route{
+				HTTP2Route: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Path: &appmesh.HTTPPathMatch{
+							Exact: aws.String("color/blue"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New(`http2Route.match.path.exact must start with /: "color/blue"`),
+		},
+		{
+			name: "Invalid regex path",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Path: &appmesh.HTTPPathMatch{
+							Regex: aws.String("/color/(blue"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("httpRoute.match.path.regex is invalid: error parsing regexp: missing closing ): `/color/(blue`"),
+		},
+		{
+			name: "Valid regex path",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Path: &appmesh.HTTPPathMatch{
+							Regex: aws.String("/color/(blue|green)"),
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
 		{
 			name: "Valid Case",
 			vr: appmesh.Route{
@@ -348,6 +581,289 @@ func Test_virtualRouterValidator_validateRoute(t *testing.T) {
 			},
 			wantErr: errors.New("Both Prefix and Path cannot be specified, only 1 allowed"),
 		},
+		{
+			name: "HTTP2 Route with idle timeout only",
+			vr: appmesh.Route{
+				HTTP2Route: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("/"),
+					},
+					Timeout: &appmesh.HTTPTimeout{
+						Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 600},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "HTTP Route with perRequest timeout beyond maximum",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("/"),
+					},
+					Timeout: &appmesh.HTTPTimeout{
+						PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 315576000001},
+					},
+				},
+			},
+			wantErr: errors.New("httpRoute.timeout.perRequest must not exceed 315576000000 seconds: 315576000001s"),
+		},
+		{
+			name: "HTTP2 Route with idle timeout beyond maximum",
+			vr: appmesh.Route{
+				HTTP2Route: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("/"),
+					},
+					Timeout: &appmesh.HTTPTimeout{
+						PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 15000},
+						Idle:       &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 315576000001},
+					},
+				},
+			},
+			wantErr: errors.New("http2Route.timeout.idle must not exceed 315576000000 seconds: 315576000001s"),
+		},
+		{
+			name: "GRPC Route with perRequest timeout only",
+			vr: appmesh.Route{
+				GRPCRoute: &appmesh.GRPCRoute{
+					Timeout: &appmesh.GRPCTimeout{
+						PerRequest: &appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 315576000000999},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "GRPC Route with idle timeout beyond maximum",
+			vr: appmesh.Route{
+				GRPCRoute: &appmesh.GRPCRoute{
+					Timeout: &appmesh.GRPCTimeout{
+						Idle: &appmesh.Duration{Unit: appmesh.DurationUnitMS, Value: 315576000001000},
+					},
+				},
+			},
+			wantErr: errors.New("grpcRoute.timeout.idle must not exceed 315576000000 seconds: 315576000001000ms"),
+		},
+		{
+			name: "GRPC Route with retry profile",
+			vr: appmesh.Route{
+				GRPCRoute: &appmesh.GRPCRoute{
+					RetryPolicy: &appmesh.GRPCRetryPolicy{
+						Profile:    &grpcRetryProfileAll,
+						MaxRetries: 3,
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "GRPC Route with retry profile and retry events",
+			vr: appmesh.Route{
+				GRPCRoute: &appmesh.GRPCRoute{
+					RetryPolicy: &appmesh.GRPCRetryPolicy{
+						Profile:         &grpcRetryProfileAll,
+						GRPCRetryEvents: []appmesh.GRPCRetryPolicyEvent{"cancelled"},
+						MaxRetries:      3,
+					},
+				},
+			},
+			wantErr: errors.New("grpcRoute.retryPolicy.profile is mutually exclusive with grpcRetryEvents, httpRetryEvents and tcpRetryEvents"),
+		},
+		{
+			name: "TCP Route with idle timeout beyond maximum",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Timeout: &appmesh.TCPTimeout{
+						Idle: &appmesh.Duration{Unit: appmesh.DurationUnitS, Value: 315576000001},
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.timeout.idle must not exceed 315576000000 seconds: 315576000001s"),
+		},
+		{
+			name: "HTTP2 Route with query parameters",
+			vr: appmesh.Route{
+				HTTP2Route: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Path: &appmesh.HTTPPathMatch{
+							Exact: aws.String("/color"),
+						},
+						QueryParameters: []appmesh.HTTPQueryParameters{
+							{
+								Name:  aws.String("color"),
+								Match: &appmesh.QueryMatchMethod{Exact: aws.String("blue")},
+							},
+							{
+								Name: aws.String("debug"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTP Route with query parameter missing name",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("/"),
+						QueryParameters: []appmesh.HTTPQueryParameters{
+							{
+								Match: &appmesh.QueryMatchMethod{Exact: aws.String("blue")},
+							},
+						},
+					},
+				},
+			},
+			wantErr: errors.New("Missing name for one or more query parameter match blocks"),
+		},
+		{
+			name: "HTTP Route with query parameter match missing exact",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{
+						Prefix: aws.String("/"),
+						QueryParameters: []appmesh.HTTPQueryParameters{
+							{
+								Name:  aws.String("color"),
+								Match: &appmesh.QueryMatchMethod{},
+							},
+						},
+					},
+				},
+			},
+			wantErr: errors.New("Missing Match criteria for one or more exact query match block, don't specify match block if you dont need it"),
+		},
+		{
+			name: "TCP Route with port range",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9049},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "TCP Route with port and ports",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						Port:  aws.Int64(9000),
+						Ports: []int64{9001, 9002},
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.port, tcpRoute.match.portRange and tcpRoute.match.ports are mutually exclusive"),
+		},
+		{
+			name: "TCP Route with reversed port range",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						PortRange: &appmesh.TCPRoutePortRange{Start: 9010, End: 9000},
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.portRange.start must not exceed end: 9010-9000"),
+		},
+		{
+			name: "TCP Route with port range beyond maximum",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9050},
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.portRange must not span more than 50 ports: 9000-9050"),
+		},
+		{
+			name: "TCP Route with ports beyond maximum",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						Ports: tcpRoutePorts(9000, 51),
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.ports must not list more than 50 ports: 51"),
+		},
+		{
+			name: "TCP Route with port range and ports beyond maximum combined",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9039},
+						Ports:     tcpRoutePorts(9100, 11),
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.portRange and tcpRoute.match.ports must not match more than 50 ports combined: 51"),
+		},
+		{
+			name: "TCP Route with port range and ports within maximum combined",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Match: &appmesh.TCPRouteMatch{
+						PortRange: &appmesh.TCPRoutePortRange{Start: 9000, End: 9039},
+						Ports:     tcpRoutePorts(9100, 10),
+					},
+				},
+			},
+			wantErr: errors.New("tcpRoute.match.port, tcpRoute.match.portRange and tcpRoute.match.ports are mutually exclusive"),
+		},
+		{
+			name: "TCP Route with virtualNodeSelector target",
+			vr: appmesh.Route{
+				TCPRoute: &appmesh.TCPRoute{
+					Action: appmesh.TCPRouteAction{
+						WeightedTargets: []appmesh.WeightedTarget{
+							{VirtualNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}}, Weight: 100},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "HTTP Route with virtualNodeSelector and virtualNodeRef target",
+			vr: appmesh.Route{
+				HTTPRoute: &appmesh.HTTPRoute{
+					Match: appmesh.HTTPRouteMatch{Prefix: aws.String("/")},
+					Action: appmesh.HTTPRouteAction{
+						WeightedTargets: []appmesh.WeightedTarget{
+							{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "payments-blue"}, Weight: 50},
+							{
+								VirtualNodeRef:      &appmesh.VirtualNodeReference{Name: "payments-green"},
+								VirtualNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "payments"}},
+								Weight:              50,
+							},
+						},
+					},
+				},
+			},
+			wantErr: errors.New("httpRoute.action.weightedTargets[1].virtualNodeSelector is mutually exclusive with virtualNodeRef and virtualNodeARN"),
+		},
+		{
+			name: "GRPC Route with invalid virtualNodeSelector target",
+			vr: appmesh.Route{
+				GRPCRoute: &appmesh.GRPCRoute{
+					Action: appmesh.GRPCRouteAction{
+						WeightedTargets: []appmesh.WeightedTarget{
+							{
+								VirtualNodeSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "track", Operator: "Near"}}},
+								Weight:              100,
+							},
+						},
+					},
+				},
+			},
+			wantErr: errors.New(`grpcRoute.action.weightedTargets[0].virtualNodeSelector is invalid: "Near" is not a valid label selector operator`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -360,3 +876,175 @@ func Test_virtualRouterValidator_validateRoute(t *testing.T) {
 		})
 	}
 }
+
+func Test_virtualRouterValidator_validateVirtualNodeReferences(t *testing.T) {
+	meshRef := &appmesh.MeshReference{
+		Name: "my-mesh",
+		UID:  "uid-1",
+	}
+	vnInMesh := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-ns",
+			Name:      "vn-1",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: meshRef,
+		},
+	}
+	vnInAnotherMesh := &appmesh.VirtualNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other-ns",
+			Name:      "vn-2",
+		},
+		Spec: appmesh.VirtualNodeSpec{
+			MeshRef: &appmesh.MeshReference{
+				Name: "another-mesh",
+				UID:  "uid-2",
+			},
+		},
+	}
+	buildVR := func(targets ...appmesh.WeightedTarget) *appmesh.VirtualRouter {
+		return &appmesh.VirtualRouter{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "my-ns",
+				Name:      "my-vr",
+			},
+			Spec: appmesh.VirtualRouterSpec{
+				MeshRef: meshRef,
+				Routes: []appmesh.Route{
+					{
+						Name: "route-1",
+						HTTPRoute: &appmesh.HTTPRoute{
+							Action: appmesh.HTTPRouteAction{
+								WeightedTargets: targets,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		existingVNs  []*appmesh.VirtualNode
+		vr           *appmesh.VirtualRouter
+		wantErr      error
+		wantWarnings []string
+	}{
+		{
+			name:        "virtualNodes exist in same mesh",
+			existingVNs: []*appmesh.VirtualNode{vnInMesh},
+			vr: buildVR(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn-1"}, Weight: 50},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String("my-ns"), Name: "vn-1"}, Weight: 50},
+			),
+		},
+		{
+			name:        "virtualNode doesn't exist",
+			existingVNs: []*appmesh.VirtualNode{vnInMesh},
+			vr: buildVR(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn-1"}, Weight: 50},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn-3"}, Weight: 50},
+			),
+			wantWarnings: []string{"virtualNode my-ns/vn-3 referenced by routes doesn't exist, routes won't be configured until it's created"},
+		},
+		{
+			name:        "virtualNode belongs to another mesh",
+			existingVNs: []*appmesh.VirtualNode{vnInMesh, vnInAnotherMesh},
+			vr: buildVR(
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Name: "vn-1"}, Weight: 50},
+				appmesh.WeightedTarget{VirtualNodeRef: &appmesh.VirtualNodeReference{Namespace: aws.String("other-ns"), Name: "vn-2"}, Weight: 50},
+			),
+			wantErr: errors.New("virtualNode other-ns/vn-2 referenced by routes doesn't belong to mesh my-mesh"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := webhook.ContextWithWarnings(context.Background())
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewFakeClientWithScheme(k8sSchema)
+			for _, vn := range tt.existingVNs {
+				err := k8sClient.Create(ctx, vn.DeepCopy())
+				assert.NoError(t, err)
+			}
+
+			v := NewVirtualRouterValidator(k8sClient, quota.NewNoopNamespaceChecker())
+			err := v.validateVirtualNodeReferences(ctx, tt.vr)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantWarnings, webhook.ContextGetWarnings(ctx))
+		})
+	}
+}
+
+func Test_virtualRouterValidator_namespaceQuotas(t *testing.T) {
+	configMapKey := types.NamespacedName{Namespace: "appmesh-system", Name: "namespace-quotas"}
+	vrWithRoutes := func(routeNames ...string) *appmesh.VirtualRouter {
+		vr := &appmesh.VirtualRouter{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "router-a"}}
+		for _, routeName := range routeNames {
+			vr.Spec.Routes = append(vr.Spec.Routes, appmesh.Route{
+				Name:     routeName,
+				TCPRoute: &appmesh.TCPRoute{},
+			})
+		}
+		return vr
+	}
+	tests := []struct {
+		name     string
+		validate func(v *virtualRouterValidator) error
+		wantErr  error
+	}{
+		{
+			name: "create within quota",
+			validate: func(v *virtualRouterValidator) error {
+				return v.ValidateCreate(context.Background(), vrWithRoutes("route-a"))
+			},
+		},
+		{
+			name: "create exceeding quota",
+			validate: func(v *virtualRouterValidator) error {
+				return v.ValidateCreate(context.Background(), vrWithRoutes("route-a", "route-b", "route-c"))
+			},
+			wantErr: errors.New(`namespace "my-ns" quota of routes would be exceeded: 3 > 2, quotas are set by ConfigMap appmesh-system/namespace-quotas`),
+		},
+		{
+			name: "update removing routes of namespace exceeding quota",
+			validate: func(v *virtualRouterValidator) error {
+				return v.ValidateUpdate(context.Background(), vrWithRoutes("route-a", "route-b", "route-c"), vrWithRoutes("route-a", "route-b", "route-c", "route-d"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).WithRuntimeObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: configMapKey.Namespace, Name: configMapKey.Name},
+				Data:       map[string]string{quota.NamespaceQuotasDataKey: "default:\n  routes: 2\n"},
+			}).Build()
+
+			v := NewVirtualRouterValidator(k8sClient, quota.NewNamespaceChecker(configMapKey, k8sClient, k8sClient, logr.Discard()))
+			err := tt.validate(v)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// tcpRoutePorts returns count consecutive ports from start.
+func tcpRoutePorts(start int64, count int) []int64 {
+	ports := make([]int64, 0, count)
+	for i := 0; i < count; i++ {
+		ports = append(ports, start+int64(i))
+	}
+	return ports
+}
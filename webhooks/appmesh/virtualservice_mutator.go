@@ -2,8 +2,8 @@ package appmesh
 
 import (
 	"context"
-	"fmt"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/awsname"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/mesh"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
@@ -15,9 +15,10 @@ import (
 const apiPathMutateAppMeshVirtualService = "/mutate-appmesh-k8s-aws-v1beta2-virtualservice"
 
 // NewVirtualServiceMutator returns a mutator for VirtualService.
-func NewVirtualServiceMutator(meshMembershipDesignator mesh.MembershipDesignator) *virtualServiceMutator {
+func NewVirtualServiceMutator(meshMembershipDesignator mesh.MembershipDesignator, awsNameGenerator awsname.Generator) *virtualServiceMutator {
 	return &virtualServiceMutator{
 		meshMembershipDesignator: meshMembershipDesignator,
+		awsNameGenerator:         awsNameGenerator,
 	}
 }
 
@@ -25,6 +26,7 @@ var _ webhook.Mutator = &virtualServiceMutator{}
 
 type virtualServiceMutator struct {
 	meshMembershipDesignator mesh.MembershipDesignator
+	awsNameGenerator         awsname.Generator
 }
 
 func (m *virtualServiceMutator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -49,7 +51,7 @@ func (m *virtualServiceMutator) MutateUpdate(ctx context.Context, obj runtime.Ob
 
 func (m *virtualServiceMutator) defaultingAWSName(vs *appmesh.VirtualService) error {
 	if vs.Spec.AWSName == nil || len(*vs.Spec.AWSName) == 0 {
-		awsName := fmt.Sprintf("%s.%s", vs.Name, vs.Namespace)
+		awsName := m.awsNameGenerator.Generate(vs.Name, vs.Namespace, ".")
 		vs.Spec.AWSName = &awsName
 	}
 	return nil
@@ -3,11 +3,13 @@ package appmesh
 import (
 	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
 	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/webhook"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"strings"
 )
@@ -15,13 +17,18 @@ import (
 const apiPathValidateAppMeshVirtualService = "/validate-appmesh-k8s-aws-v1beta2-virtualservice"
 
 // NewVirtualServiceValidator returns a validator for VirtualService.
-func NewVirtualServiceValidator() *virtualServiceValidator {
-	return &virtualServiceValidator{}
+func NewVirtualServiceValidator(k8sClient client.Reader, referencesIndexer references.ObjectReferenceIndexer) *virtualServiceValidator {
+	return &virtualServiceValidator{
+		k8sClient:         k8sClient,
+		referencesIndexer: referencesIndexer,
+	}
 }
 
 var _ webhook.Validator = &virtualServiceValidator{}
 
 type virtualServiceValidator struct {
+	k8sClient         client.Reader
+	referencesIndexer references.ObjectReferenceIndexer
 }
 
 func (v *virtualServiceValidator) Prototype(req admission.Request) (runtime.Object, error) {
@@ -42,6 +49,10 @@ func (v *virtualServiceValidator) ValidateUpdate(ctx context.Context, obj runtim
 }
 
 func (v *virtualServiceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	vs := obj.(*appmesh.VirtualService)
+	if err := v.checkForReferrers(ctx, vs); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -60,7 +71,22 @@ func (v *virtualServiceValidator) enforceFieldsImmutability(vs *appmesh.VirtualS
 	return nil
 }
 
-// +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualservice,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualservices,verbs=create;update,versions=v1beta2,name=vvirtualservice.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
+// checkForReferrers denies deletion of vs while virtualNodes or gatewayRoutes still reference it, unless vs allows it.
+func (v *virtualServiceValidator) checkForReferrers(ctx context.Context, vs *appmesh.VirtualService) error {
+	if isReferencedDeletionAllowed(vs) {
+		return nil
+	}
+	referrers, err := findVirtualServiceReferrers(ctx, v.referencesIndexer, vs)
+	if err != nil {
+		return err
+	}
+	if len(referrers) != 0 {
+		return referencedDeletionError("VirtualService", vs, referrers)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-appmesh-k8s-aws-v1beta2-virtualservice,mutating=false,failurePolicy=fail,groups=appmesh.k8s.aws,resources=virtualservices,verbs=create;update;delete,versions=v1beta2,name=vvirtualservice.appmesh.k8s.aws,sideEffects=None,webhookVersions=v1beta1
 
 func (v *virtualServiceValidator) SetupWithManager(mgr ctrl.Manager) {
 	mgr.GetWebhookServer().Register(apiPathValidateAppMeshVirtualService, webhook.ValidatingWebhookForValidator(v))
@@ -1,11 +1,18 @@
 package appmesh
 
 import (
+	"context"
 	appmesh "github.com/aws/aws-app-mesh-controller-for-k8s/apis/appmesh/v1beta2"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/gatewayroute"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/references"
+	"github.com/aws/aws-app-mesh-controller-for-k8s/pkg/virtualnode"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -160,3 +167,95 @@ func Test_virtualServiceValidator_enforceFieldsImmutability(t *testing.T) {
 		})
 	}
 }
+
+func Test_virtualServiceValidator_checkForReferrers(t *testing.T) {
+	vs := &appmesh.VirtualService{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-vs"}}
+	vnWithBackend := func(objectMeta metav1.ObjectMeta, vsRef appmesh.VirtualServiceReference) *appmesh.VirtualNode {
+		return &appmesh.VirtualNode{
+			ObjectMeta: objectMeta,
+			Spec: appmesh.VirtualNodeSpec{
+				Backends: []appmesh.Backend{{VirtualService: appmesh.VirtualServiceBackend{VirtualServiceRef: &vsRef}}},
+			},
+		}
+	}
+	grTargeting := func(objectMeta metav1.ObjectMeta, vsRef appmesh.VirtualServiceReference) *appmesh.GatewayRoute {
+		return &appmesh.GatewayRoute{
+			ObjectMeta: objectMeta,
+			Spec: appmesh.GatewayRouteSpec{
+				HTTPRoute: &appmesh.HTTPGatewayRoute{
+					Action: appmesh.HTTPGatewayRouteAction{
+						Target: appmesh.GatewayRouteTarget{
+							VirtualService: appmesh.GatewayRouteVirtualService{VirtualServiceRef: &vsRef},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		vs          *appmesh.VirtualService
+		existingVNs []*appmesh.VirtualNode
+		existingGRs []*appmesh.GatewayRoute
+		wantErr     error
+	}{
+		{
+			name: "virtualService is referenced by virtualNode and gatewayRoute",
+			vs:   vs,
+			existingVNs: []*appmesh.VirtualNode{
+				vnWithBackend(metav1.ObjectMeta{Namespace: "my-ns", Name: "vn-1"}, appmesh.VirtualServiceReference{Name: "my-vs"}),
+				vnWithBackend(metav1.ObjectMeta{Namespace: "my-ns", Name: "vn-2"}, appmesh.VirtualServiceReference{Name: "other-vs"}),
+			},
+			existingGRs: []*appmesh.GatewayRoute{
+				grTargeting(metav1.ObjectMeta{Namespace: "ingress-ns", Name: "gr-1"}, appmesh.VirtualServiceReference{Namespace: aws.String("my-ns"), Name: "my-vs"}),
+			},
+			wantErr: errors.New(`VirtualService-my-vs is referenced by virtualNode/my-ns/vn-1, gatewayRoute/ingress-ns/gr-1, deleting it breaks traffic through them: remove the references first, or set annotation appmesh.k8s.aws/allow-referenced-deletion: "true" to delete it anyway`),
+		},
+		{
+			name: "virtualService isn't referenced",
+			vs:   vs,
+			existingVNs: []*appmesh.VirtualNode{
+				vnWithBackend(metav1.ObjectMeta{Namespace: "my-ns", Name: "vn-1"}, appmesh.VirtualServiceReference{Name: "other-vs"}),
+			},
+		},
+		{
+			name: "virtualService allows referenced deletion",
+			vs: &appmesh.VirtualService{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "my-ns",
+				Name:        "my-vs",
+				Annotations: map[string]string{"appmesh.k8s.aws/allow-referenced-deletion": "true"},
+			}},
+			existingVNs: []*appmesh.VirtualNode{
+				vnWithBackend(metav1.ObjectMeta{Namespace: "my-ns", Name: "vn-1"}, appmesh.VirtualServiceReference{Name: "my-vs"}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k8sSchema := runtime.NewScheme()
+			clientgoscheme.AddToScheme(k8sSchema)
+			appmesh.AddToScheme(k8sSchema)
+			vnField, vnIndexFunc := references.BuildIndexField(virtualnode.ReferenceKindVirtualService, virtualnode.VirtualServiceReferenceIndexFunc)
+			grField, grIndexFunc := references.BuildIndexField(gatewayroute.ReferenceKindVirtualService, gatewayroute.VirtualServiceReferenceIndexFunc)
+			k8sClient := testclient.NewClientBuilder().WithScheme(k8sSchema).
+				WithIndex(&appmesh.VirtualNode{}, vnField, vnIndexFunc).
+				WithIndex(&appmesh.GatewayRoute{}, grField, grIndexFunc).
+				Build()
+			for _, vn := range tt.existingVNs {
+				assert.NoError(t, k8sClient.Create(ctx, vn.DeepCopy()))
+			}
+			for _, gr := range tt.existingGRs {
+				assert.NoError(t, k8sClient.Create(ctx, gr.DeepCopy()))
+			}
+
+			v := NewVirtualServiceValidator(k8sClient, references.NewDefaultObjectReferenceIndexer(k8sClient, nil))
+			err := v.ValidateDelete(ctx, tt.vs)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
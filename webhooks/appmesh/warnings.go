@@ -0,0 +1,20 @@
+package appmesh
+
+import (
+	"fmt"
+)
+
+// isTLSEnforceDisabled tests whether client policy TLS is explicitly not enforced, AppMesh enforces it by default.
+func isTLSEnforceDisabled(enforce *bool) bool {
+	return enforce != nil && !*enforce
+}
+
+// tlsNotEnforcedWarning is the warning of client policy TLS at fieldPath that isn't enforced.
+func tlsNotEnforcedWarning(fieldPath string) string {
+	return fmt.Sprintf("%s.enforce is false, connections to backends aren't required to use TLS", fieldPath)
+}
+
+// tlsDisabledWarning is the warning of listener TLS at fieldPath whose mode is DISABLED.
+func tlsDisabledWarning(fieldPath string) string {
+	return fmt.Sprintf("%s.mode is DISABLED, the listener doesn't accept TLS connections, so clients enforcing TLS fail to connect", fieldPath)
+}